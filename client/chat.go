@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/google/uuid"
+)
+
+// ChatOption configures a chat request.
+type ChatOption func(*pb.GenerateReplyRequest)
+
+// WithInstructions sets the system prompt.
+func WithInstructions(instructions string) ChatOption {
+	return func(r *pb.GenerateReplyRequest) { r.Instructions = instructions }
+}
+
+// WithProvider selects the provider instead of the tenant's default.
+func WithProvider(p pb.Provider) ChatOption {
+	return func(r *pb.GenerateReplyRequest) { r.PreferredProvider = p }
+}
+
+// WithModel overrides the provider's configured model.
+func WithModel(model string) ChatOption {
+	return func(r *pb.GenerateReplyRequest) { r.ModelOverride = model }
+}
+
+// WithHistory sets the earlier turns of the conversation.
+func WithHistory(messages ...*pb.Message) ChatOption {
+	return func(r *pb.GenerateReplyRequest) { r.ConversationHistory = messages }
+}
+
+// WithPreviousResponseID continues a conversation from an earlier response.
+func WithPreviousResponseID(id string) ChatOption {
+	return func(r *pb.GenerateReplyRequest) { r.PreviousResponseId = id }
+}
+
+// WithFailover enables failover to the tenant's failover order, or to
+// fallback when it is not PROVIDER_UNSPECIFIED.
+func WithFailover(fallback pb.Provider) ChatOption {
+	return func(r *pb.GenerateReplyRequest) {
+		r.EnableFailover = true
+		r.FallbackProvider = fallback
+	}
+}
+
+// WithRequestID sets the request ID used for tracing and idempotency.
+func WithRequestID(id string) ChatOption {
+	return func(r *pb.GenerateReplyRequest) { r.RequestId = id }
+}
+
+// WithMetadata adds a request metadata entry.
+func WithMetadata(key, value string) ChatOption {
+	return func(r *pb.GenerateReplyRequest) {
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string)
+		}
+		r.Metadata[key] = value
+	}
+}
+
+// WithRequest edits the request directly, for fields without an option.
+func WithRequest(edit func(*pb.GenerateReplyRequest)) ChatOption {
+	return edit
+}
+
+// newChatRequest builds the request for input and opts.
+func newChatRequest(input string, opts []ChatOption) *pb.GenerateReplyRequest {
+	req := &pb.GenerateReplyRequest{UserInput: input}
+	for _, opt := range opts {
+		opt(req)
+	}
+	return req
+}
+
+// Chat generates a reply to input. When retries are enabled the request is
+// sent as idempotent, with a generated request ID if none was set, so a retry
+// after a lost response does not generate (or bill) the reply twice.
+func (c *Client) Chat(ctx context.Context, input string, opts ...ChatOption) (*pb.GenerateReplyResponse, error) {
+	req := newChatRequest(input, opts)
+	if c.cfg.maxRetries > 0 {
+		if req.RequestId == "" {
+			req.RequestId = uuid.NewString()
+		}
+		req.Idempotent = true
+	}
+	return c.airborne.GenerateReply(ctx, req)
+}
+
+// StreamError is a provider error reported inside a reply stream.
+type StreamError struct {
+	Code      string
+	Message   string
+	Retryable bool
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("stream error %s: %s", e.Code, e.Message)
+}
+
+// ChatStream streams a reply to input. Iteration yields each chunk until the
+// stream completes; a failed call or an error chunk is yielded once as the
+// error (a *StreamError for error chunks) and ends the iteration. Breaking
+// out of the loop cancels the stream.
+func (c *Client) ChatStream(ctx context.Context, input string, opts ...ChatOption) iter.Seq2[*pb.GenerateReplyChunk, error] {
+	req := newChatRequest(input, opts)
+	return func(yield func(*pb.GenerateReplyChunk, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := c.airborne.GenerateReplyStream(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if e := chunk.GetError(); e != nil {
+				yield(nil, &StreamError{Code: e.Code, Message: e.Message, Retryable: e.Retryable})
+				return
+			}
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+	}
+}
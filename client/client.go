@@ -0,0 +1,224 @@
+// Package client is a Go client for the Airborne gRPC API. It wraps the
+// generated stubs with authentication, timeouts and retries, and adds helpers
+// for chat, streaming and chunked file uploads:
+//
+//	c, err := client.NewClient("grpcs://airborne.internal:443", apiKey, client.WithTenant("ai8"))
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	resp, err := c.Chat(ctx, "Summarize this thread", client.WithProvider(pb.Provider_PROVIDER_GEMINI))
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultTimeout bounds unary calls whose context has no deadline.
+	DefaultTimeout = 2 * time.Minute
+
+	// DefaultMaxRetries is how many times a unary call is retried after the
+	// server was unavailable.
+	DefaultMaxRetries = 2
+
+	// retryBackoffBase is the first retry delay; it doubles on each retry.
+	retryBackoffBase = 250 * time.Millisecond
+)
+
+// Client calls an Airborne server. It is safe for concurrent use.
+type Client struct {
+	conn     *grpc.ClientConn
+	airborne pb.AirborneServiceClient
+	files    pb.FileServiceClient
+	memory   pb.MemoryServiceClient
+
+	apiKey string
+	cfg    config
+}
+
+type config struct {
+	tenantID    string
+	timeout     time.Duration
+	maxRetries  int
+	insecure    bool
+	tlsConfig   *tls.Config
+	dialOptions []grpc.DialOption
+}
+
+// Option configures a Client.
+type Option func(*config)
+
+// WithTenant sends tenantID as the x-tenant-id header on every call.
+func WithTenant(tenantID string) Option {
+	return func(c *config) { c.tenantID = tenantID }
+}
+
+// WithTimeout sets the timeout for unary calls without a deadline (default
+// DefaultTimeout). Zero disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) { c.timeout = d }
+}
+
+// WithMaxRetries sets how many times unary calls are retried when the server
+// is unavailable (default DefaultMaxRetries). Zero disables retries.
+func WithMaxRetries(n int) Option {
+	return func(c *config) { c.maxRetries = n }
+}
+
+// WithInsecure connects without TLS, whatever the URL scheme.
+func WithInsecure() Option {
+	return func(c *config) { c.insecure = true }
+}
+
+// WithTLSConfig sets the TLS configuration for secure connections.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *config) { c.tlsConfig = cfg }
+}
+
+// WithDialOptions adds gRPC dial options, e.g. extra interceptors.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *config) { c.dialOptions = append(c.dialOptions, opts...) }
+}
+
+// NewClient creates a client for the server at url, authenticating with
+// apiKey. The scheme picks the transport: "grpc://" and "http://" connect
+// without TLS, "grpcs://", "https://" and bare host:port addresses use TLS.
+// The connection is established lazily on the first call.
+func NewClient(url, apiKey string, opts ...Option) (*Client, error) {
+	cfg := config{timeout: DefaultTimeout, maxRetries: DefaultMaxRetries}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	target, secure, err := parseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{apiKey: apiKey, cfg: cfg}
+	dialOpts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(c.unaryInterceptor()),
+		grpc.WithChainStreamInterceptor(c.streamInterceptor()),
+	}
+	if secure && !cfg.insecure {
+		tlsCfg := cfg.tlsConfig
+		if tlsCfg == nil {
+			tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	dialOpts = append(dialOpts, cfg.dialOptions...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", target, err)
+	}
+	c.conn = conn
+	c.airborne = pb.NewAirborneServiceClient(conn)
+	c.files = pb.NewFileServiceClient(conn)
+	c.memory = pb.NewMemoryServiceClient(conn)
+	return c, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Airborne returns the generated AirborneService stub on the client's
+// connection, for calls without a helper.
+func (c *Client) Airborne() pb.AirborneServiceClient {
+	return c.airborne
+}
+
+// Files returns the generated FileService stub on the client's connection.
+func (c *Client) Files() pb.FileServiceClient {
+	return c.files
+}
+
+// Memory returns the generated MemoryService stub on the client's connection.
+func (c *Client) Memory() pb.MemoryServiceClient {
+	return c.memory
+}
+
+// parseURL returns the dial target for url and whether it uses TLS.
+func parseURL(url string) (string, bool, error) {
+	target, secure := strings.TrimSpace(url), true
+	for _, scheme := range []string{"grpc://", "http://"} {
+		if rest, ok := strings.CutPrefix(target, scheme); ok {
+			target, secure = rest, false
+		}
+	}
+	for _, scheme := range []string{"grpcs://", "https://"} {
+		if rest, ok := strings.CutPrefix(target, scheme); ok {
+			target = rest
+		}
+	}
+	target = strings.TrimSuffix(target, "/")
+	if target == "" || strings.Contains(target, "://") {
+		return "", false, fmt.Errorf("invalid Airborne URL %q", url)
+	}
+	return target, secure, nil
+}
+
+// withMetadata adds the API key and tenant headers to ctx.
+func (c *Client) withMetadata(ctx context.Context) context.Context {
+	pairs := []string{"authorization", "Bearer " + c.apiKey}
+	if c.cfg.tenantID != "" {
+		pairs = append(pairs, "x-tenant-id", c.cfg.tenantID)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// unaryInterceptor authenticates unary calls, applies the default timeout
+// and retries calls the server was unavailable for.
+func (c *Client) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = c.withMetadata(ctx)
+		if _, ok := ctx.Deadline(); !ok && c.cfg.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.cfg.timeout)
+			defer cancel()
+		}
+
+		backoff := retryBackoffBase
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || status.Code(err) != codes.Unavailable || attempt >= c.cfg.maxRetries {
+				return err
+			}
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			}
+			backoff *= 2
+		}
+	}
+}
+
+// streamInterceptor authenticates streaming calls. Streams are not retried
+// or given a default timeout; their lifetime is the caller's context.
+func (c *Client) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(c.withMetadata(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeServer struct {
+	pb.UnimplementedAirborneServiceServer
+	pb.UnimplementedFileServiceServer
+
+	unavailable atomic.Int32 // GenerateReply calls to fail before answering
+	calls       atomic.Int32
+	lastMD      metadata.MD
+	lastReq     *pb.GenerateReplyRequest
+	uploaded    bytes.Buffer
+	uploadMsgs  int
+	uploadMeta  *pb.UploadFileMetadata
+}
+
+func (f *fakeServer) GenerateReply(ctx context.Context, req *pb.GenerateReplyRequest) (*pb.GenerateReplyResponse, error) {
+	f.calls.Add(1)
+	f.lastMD, _ = metadata.FromIncomingContext(ctx)
+	f.lastReq = req
+	if f.unavailable.Add(-1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &pb.GenerateReplyResponse{Text: "reply to " + req.UserInput}, nil
+}
+
+func (f *fakeServer) GenerateReplyStream(req *pb.GenerateReplyRequest, stream pb.AirborneService_GenerateReplyStreamServer) error {
+	for _, text := range []string{"Hello", " world"} {
+		if err := stream.Send(&pb.GenerateReplyChunk{Chunk: &pb.GenerateReplyChunk_TextDelta{TextDelta: &pb.TextDelta{Text: text}}}); err != nil {
+			return err
+		}
+	}
+	if req.UserInput == "fail" {
+		return stream.Send(&pb.GenerateReplyChunk{Chunk: &pb.GenerateReplyChunk_Error{Error: &pb.StreamError{Code: "provider_error", Message: "boom"}}})
+	}
+	return stream.Send(&pb.GenerateReplyChunk{Chunk: &pb.GenerateReplyChunk_Complete{Complete: &pb.StreamComplete{Model: "mock"}}})
+}
+
+func (f *fakeServer) UploadFile(stream pb.FileService_UploadFileServer) error {
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&pb.UploadFileResponse{FileId: "file-1", Filename: f.uploadMeta.Filename})
+		}
+		if err != nil {
+			return err
+		}
+		if meta := msg.GetMetadata(); meta != nil {
+			f.uploadMeta = meta
+			continue
+		}
+		f.uploadMsgs++
+		f.uploaded.Write(msg.GetChunk())
+	}
+}
+
+func newTestClient(t *testing.T, opts ...Option) (*Client, *fakeServer) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	fake := &fakeServer{}
+	pb.RegisterAirborneServiceServer(srv, fake)
+	pb.RegisterFileServiceServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }
+	opts = append([]Option{WithTenant("ai8"), WithDialOptions(grpc.WithContextDialer(dialer))}, opts...)
+	c, err := NewClient("grpc://localhost:50051", "test-key", opts...)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, fake
+}
+
+func TestChat(t *testing.T) {
+	c, fake := newTestClient(t)
+
+	resp, err := c.Chat(context.Background(), "Hello", WithProvider(pb.Provider_PROVIDER_GEMINI), WithMetadata("team", "support"))
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if resp.Text != "reply to Hello" {
+		t.Errorf("unexpected reply %q", resp.Text)
+	}
+	if got := fake.lastMD.Get("authorization"); len(got) != 1 || got[0] != "Bearer test-key" {
+		t.Errorf("authorization = %v", got)
+	}
+	if got := fake.lastMD.Get("x-tenant-id"); len(got) != 1 || got[0] != "ai8" {
+		t.Errorf("x-tenant-id = %v", got)
+	}
+	if fake.lastReq.PreferredProvider != pb.Provider_PROVIDER_GEMINI || fake.lastReq.Metadata["team"] != "support" {
+		t.Errorf("options not applied: %+v", fake.lastReq)
+	}
+	if !fake.lastReq.Idempotent || fake.lastReq.RequestId == "" {
+		t.Errorf("expected retried chats to be idempotent, got idempotent=%v request_id=%q", fake.lastReq.Idempotent, fake.lastReq.RequestId)
+	}
+}
+
+func TestChat_RetriesUnavailable(t *testing.T) {
+	c, fake := newTestClient(t)
+	fake.unavailable.Store(2)
+
+	if _, err := c.Chat(context.Background(), "Hello"); err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if fake.calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", fake.calls.Load())
+	}
+
+	c, fake = newTestClient(t, WithMaxRetries(0))
+	fake.unavailable.Store(1)
+	if _, err := c.Chat(context.Background(), "Hello"); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected Unavailable without retries, got %v", err)
+	}
+	if fake.lastReq.Idempotent {
+		t.Error("expected no idempotency without retries")
+	}
+}
+
+func TestChatStream(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	var text string
+	var complete bool
+	for chunk, err := range c.ChatStream(context.Background(), "Hello") {
+		if err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+		text += chunk.GetTextDelta().GetText()
+		complete = complete || chunk.GetComplete() != nil
+	}
+	if text != "Hello world" || !complete {
+		t.Errorf("text = %q, complete = %v", text, complete)
+	}
+
+	var streamErr *StreamError
+	for _, err := range c.ChatStream(context.Background(), "fail") {
+		if err != nil && !errors.As(err, &streamErr) {
+			t.Fatalf("unexpected error type %T", err)
+		}
+	}
+	if streamErr == nil || streamErr.Code != "provider_error" {
+		t.Errorf("expected a StreamError, got %v", streamErr)
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	c, fake := newTestClient(t)
+	content := bytes.Repeat([]byte("a"), UploadChunkSize*2+10)
+
+	resp, err := c.UploadFile(context.Background(), "docs", "notes.txt", bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if resp.FileId != "file-1" || fake.uploadMsgs != 3 || !bytes.Equal(fake.uploaded.Bytes(), content) {
+		t.Errorf("unexpected upload: file_id=%q messages=%d bytes=%d", resp.FileId, fake.uploadMsgs, fake.uploaded.Len())
+	}
+	if fake.uploadMeta.StoreId != "docs" || fake.uploadMeta.Size != int64(len(content)) || fake.uploadMeta.MimeType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected metadata: %+v", fake.uploadMeta)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		wantTarget string
+		wantSecure bool
+		wantErr    bool
+	}{
+		{"grpc://localhost:50051", "localhost:50051", false, false},
+		{"http://localhost:50051/", "localhost:50051", false, false},
+		{"grpcs://airborne.internal:443", "airborne.internal:443", true, false},
+		{"airborne.internal:443", "airborne.internal:443", true, false},
+		{"", "", false, true},
+		{"ftp://host:21", "", false, true},
+	}
+	for _, tt := range tests {
+		target, secure, err := parseURL(tt.url)
+		if (err != nil) != tt.wantErr || target != tt.wantTarget || secure != tt.wantSecure {
+			t.Errorf("parseURL(%q) = %q, %v, %v; want %q, %v, err=%v", tt.url, target, secure, err, tt.wantTarget, tt.wantSecure, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+)
+
+// UploadChunkSize is the size of the data messages an upload is split into.
+const UploadChunkSize = 64 * 1024
+
+// UploadOption configures a file upload.
+type UploadOption func(*pb.UploadFileMetadata)
+
+// WithMimeType sets the file's MIME type.
+func WithMimeType(mimeType string) UploadOption {
+	return func(m *pb.UploadFileMetadata) { m.MimeType = mimeType }
+}
+
+// WithStoreProvider sets the provider that hosts the store.
+func WithStoreProvider(p pb.Provider) UploadOption {
+	return func(m *pb.UploadFileMetadata) { m.Provider = p }
+}
+
+// UploadFile uploads the contents of r to a store as filename, streaming it
+// in UploadChunkSize messages. size is the file size if known, else 0; the
+// server uses it to reject oversized files before they are sent.
+func (c *Client) UploadFile(ctx context.Context, storeID, filename string, r io.Reader, size int64, opts ...UploadOption) (*pb.UploadFileResponse, error) {
+	meta := &pb.UploadFileMetadata{
+		StoreId:  storeID,
+		Filename: filename,
+		MimeType: mime.TypeByExtension(filepath.Ext(filename)),
+		Size:     size,
+	}
+	for _, opt := range opts {
+		opt(meta)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.files.UploadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Metadata{Metadata: meta}}); err != nil {
+		return nil, uploadSendError(stream, err)
+	}
+
+	buf := make([]byte, UploadChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			if err := stream.Send(&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: chunk}}); err != nil {
+				return nil, uploadSendError(stream, err)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read %s: %w", filename, readErr)
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// UploadFilePath uploads the file at path to a store under its base name.
+func (c *Client) UploadFilePath(ctx context.Context, storeID, path string, opts ...UploadOption) (*pb.UploadFileResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return c.UploadFile(ctx, storeID, filepath.Base(path), f, info.Size(), opts...)
+}
+
+// uploadSendError returns the server's status when a send failed because the
+// server ended the stream, which gRPC reports on send as io.EOF.
+func uploadSendError(stream pb.FileService_UploadFileClient, err error) error {
+	if errors.Is(err, io.EOF) {
+		if _, recvErr := stream.CloseAndRecv(); recvErr != nil {
+			return recvErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,17 @@
+"""Python client for the Airborne gRPC API."""
+
+from airborne_client._version import __version__
+from airborne_client.client import (
+    DEFAULT_TIMEOUT,
+    UPLOAD_CHUNK_SIZE,
+    AirborneClient,
+    StreamError,
+)
+
+__all__ = [
+    "DEFAULT_TIMEOUT",
+    "UPLOAD_CHUNK_SIZE",
+    "AirborneClient",
+    "StreamError",
+    "__version__",
+]
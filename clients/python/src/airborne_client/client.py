@@ -0,0 +1,141 @@
+"""Client for the Airborne gRPC API.
+
+Wraps the generated stubs with authentication and adds helpers for chat,
+streaming and chunked file uploads.
+"""
+
+from __future__ import annotations
+
+import mimetypes
+import os
+from typing import BinaryIO, Iterator, Optional
+
+import grpc
+
+from airborne.v1 import airborne_pb2, airborne_pb2_grpc, files_pb2, files_pb2_grpc
+from airborne.v1 import memory_pb2_grpc
+
+#: Size of the data messages an upload is split into.
+UPLOAD_CHUNK_SIZE = 64 * 1024
+
+#: Default timeout for unary calls, in seconds.
+DEFAULT_TIMEOUT = 120.0
+
+
+class StreamError(Exception):
+    """A provider error reported inside a reply stream."""
+
+    def __init__(self, code: str, message: str, retryable: bool) -> None:
+        super().__init__(f"stream error {code}: {message}")
+        self.code = code
+        self.message = message
+        self.retryable = retryable
+
+
+class AirborneClient:
+    """Calls an Airborne server.
+
+    ``target`` is a host:port address. Connections use TLS unless ``secure``
+    is false. ``timeout`` bounds unary calls; streams run until they finish or
+    are closed.
+    """
+
+    def __init__(
+        self,
+        target: str,
+        api_key: str,
+        tenant_id: Optional[str] = None,
+        secure: bool = True,
+        timeout: float = DEFAULT_TIMEOUT,
+        credentials: Optional[grpc.ChannelCredentials] = None,
+    ) -> None:
+        if secure:
+            self._channel = grpc.secure_channel(target, credentials or grpc.ssl_channel_credentials())
+        else:
+            self._channel = grpc.insecure_channel(target)
+        self._metadata = [("authorization", f"Bearer {api_key}")]
+        if tenant_id:
+            self._metadata.append(("x-tenant-id", tenant_id))
+        self._timeout = timeout
+
+        #: Generated stubs, for calls without a helper. Pass ``metadata=client.metadata``.
+        self.airborne = airborne_pb2_grpc.AirborneServiceStub(self._channel)
+        self.files = files_pb2_grpc.FileServiceStub(self._channel)
+        self.memory = memory_pb2_grpc.MemoryServiceStub(self._channel)
+
+    @property
+    def metadata(self) -> list:
+        """The authentication headers sent on every call."""
+        return list(self._metadata)
+
+    def chat(self, user_input: str, **fields) -> airborne_pb2.GenerateReplyResponse:
+        """Generates a reply to ``user_input``.
+
+        Other GenerateReplyRequest fields are passed as keyword arguments,
+        e.g. ``instructions`` or ``preferred_provider``.
+        """
+        request = airborne_pb2.GenerateReplyRequest(user_input=user_input, **fields)
+        return self.airborne.GenerateReply(request, timeout=self._timeout, metadata=self._metadata)
+
+    def chat_stream(self, user_input: str, **fields) -> Iterator[airborne_pb2.GenerateReplyChunk]:
+        """Streams a reply to ``user_input``, yielding each chunk.
+
+        An error chunk is raised as StreamError. Closing the generator
+        cancels the stream.
+        """
+        request = airborne_pb2.GenerateReplyRequest(user_input=user_input, **fields)
+        call = self.airborne.GenerateReplyStream(request, metadata=self._metadata)
+        try:
+            for chunk in call:
+                if chunk.HasField("error"):
+                    raise StreamError(chunk.error.code, chunk.error.message, chunk.error.retryable)
+                yield chunk
+        finally:
+            call.cancel()
+
+    def upload_file(
+        self,
+        store_id: str,
+        filename: str,
+        data: BinaryIO,
+        size: int = 0,
+        mime_type: Optional[str] = None,
+        **metadata,
+    ) -> files_pb2.UploadFileResponse:
+        """Uploads ``data`` to a store as ``filename`` in UPLOAD_CHUNK_SIZE messages.
+
+        ``size`` is the file size if known; the server uses it to reject
+        oversized files before they are sent.
+        """
+        meta = files_pb2.UploadFileMetadata(
+            store_id=store_id,
+            filename=filename,
+            mime_type=mime_type or mimetypes.guess_type(filename)[0] or "",
+            size=size,
+            **metadata,
+        )
+
+        def requests() -> Iterator[files_pb2.UploadFileRequest]:
+            yield files_pb2.UploadFileRequest(metadata=meta)
+            while True:
+                chunk = data.read(UPLOAD_CHUNK_SIZE)
+                if not chunk:
+                    return
+                yield files_pb2.UploadFileRequest(chunk=chunk)
+
+        return self.files.UploadFile(requests(), timeout=self._timeout, metadata=self._metadata)
+
+    def upload_file_path(self, store_id: str, path: str, **kwargs) -> files_pb2.UploadFileResponse:
+        """Uploads the file at ``path`` to a store under its base name."""
+        with open(path, "rb") as f:
+            return self.upload_file(store_id, os.path.basename(path), f, os.fstat(f.fileno()).st_size, **kwargs)
+
+    def close(self) -> None:
+        """Closes the channel."""
+        self._channel.close()
+
+    def __enter__(self) -> "AirborneClient":
+        return self
+
+    def __exit__(self, *exc) -> None:
+        self.close()
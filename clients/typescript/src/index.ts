@@ -0,0 +1,149 @@
+// TypeScript client for the Airborne gRPC API.
+//
+// The generated messages and service descriptors live in ./gen (run
+// scripts/generate-clients.sh ts); this file adds authentication and typed
+// helpers for chat, streaming and file uploads.
+
+import { create, type MessageInitShape } from "@bufbuild/protobuf";
+import { createClient, type Client, type Interceptor } from "@connectrpc/connect";
+import { createGrpcTransport } from "@connectrpc/connect-node";
+import {
+  AirborneService,
+  GenerateReplyRequestSchema,
+  type GenerateReplyChunk,
+  type GenerateReplyResponse,
+} from "./gen/airborne/v1/airborne_pb.js";
+import {
+  FileService,
+  UploadFileRequestSchema,
+  type UploadFileMetadata,
+  type UploadFileRequest,
+  type UploadFileResponse,
+} from "./gen/airborne/v1/files_pb.js";
+import { MemoryService } from "./gen/airborne/v1/memory_pb.js";
+
+export * from "./gen/airborne/v1/airborne_pb.js";
+export * from "./gen/airborne/v1/common_pb.js";
+export * from "./gen/airborne/v1/files_pb.js";
+export * from "./gen/airborne/v1/memory_pb.js";
+
+/** Size of the data messages an upload is split into. */
+export const UPLOAD_CHUNK_SIZE = 64 * 1024;
+
+/** Default timeout for unary calls, in milliseconds. */
+export const DEFAULT_TIMEOUT_MS = 120_000;
+
+export interface AirborneClientOptions {
+  /** Server URL, e.g. "https://airborne.internal:443" or "http://localhost:50051". */
+  baseUrl: string;
+  apiKey: string;
+  /** Sent as x-tenant-id on every call. */
+  tenantId?: string;
+  /** Timeout for unary calls; streams run until their signal aborts. */
+  timeoutMs?: number;
+}
+
+/** Fields of a chat request besides user_input. */
+export type ChatInit = Omit<MessageInitShape<typeof GenerateReplyRequestSchema>, "userInput">;
+
+/** A streamed chunk other than an error, which is thrown as a StreamError. */
+export type ChatStreamEvent = Exclude<GenerateReplyChunk["chunk"], { case: "error" } | { case: undefined }>;
+
+/** A provider error reported inside a reply stream. */
+export class StreamError extends Error {
+  constructor(
+    readonly code: string,
+    message: string,
+    readonly retryable: boolean,
+  ) {
+    super(`stream error ${code}: ${message}`);
+    this.name = "StreamError";
+  }
+}
+
+export class AirborneClient {
+  /** Generated AirborneService client, for calls without a helper. */
+  readonly airborne: Client<typeof AirborneService>;
+  /** Generated FileService client. */
+  readonly files: Client<typeof FileService>;
+  /** Generated MemoryService client. */
+  readonly memory: Client<typeof MemoryService>;
+
+  private readonly timeoutMs: number;
+
+  constructor(options: AirborneClientOptions) {
+    const auth: Interceptor = (next) => (req) => {
+      req.header.set("authorization", `Bearer ${options.apiKey}`);
+      if (options.tenantId) {
+        req.header.set("x-tenant-id", options.tenantId);
+      }
+      return next(req);
+    };
+    const transport = createGrpcTransport({ baseUrl: options.baseUrl, interceptors: [auth] });
+    this.airborne = createClient(AirborneService, transport);
+    this.files = createClient(FileService, transport);
+    this.memory = createClient(MemoryService, transport);
+    this.timeoutMs = options.timeoutMs ?? DEFAULT_TIMEOUT_MS;
+  }
+
+  /** Generates a reply to input. */
+  chat(input: string, init: ChatInit = {}, signal?: AbortSignal): Promise<GenerateReplyResponse> {
+    const req = create(GenerateReplyRequestSchema, { ...init, userInput: input });
+    return this.airborne.generateReply(req, { timeoutMs: this.timeoutMs, signal });
+  }
+
+  /**
+   * Streams a reply to input, yielding each chunk until the stream completes.
+   * An error chunk is thrown as a StreamError. Breaking out of the loop
+   * cancels the stream.
+   */
+  async *chatStream(input: string, init: ChatInit = {}, signal?: AbortSignal): AsyncGenerator<ChatStreamEvent> {
+    const req = create(GenerateReplyRequestSchema, { ...init, userInput: input });
+    for await (const chunk of this.airborne.generateReplyStream(req, { signal })) {
+      const event = chunk.chunk;
+      if (event.case === "error") {
+        throw new StreamError(event.value.code, event.value.message, event.value.retryable);
+      }
+      if (event.case !== undefined) {
+        yield event;
+      }
+    }
+  }
+
+  /** Streams a reply and returns its full text with the completion chunk. */
+  async chatText(input: string, init: ChatInit = {}, signal?: AbortSignal) {
+    let text = "";
+    let complete;
+    for await (const event of this.chatStream(input, init, signal)) {
+      if (event.case === "textDelta") {
+        text += event.value.text;
+      } else if (event.case === "complete") {
+        complete = event.value;
+      }
+    }
+    return { text, complete };
+  }
+
+  /** Uploads data to a store as filename, streaming it in UPLOAD_CHUNK_SIZE messages. */
+  uploadFile(
+    storeId: string,
+    filename: string,
+    data: Uint8Array,
+    metadata: Partial<Omit<UploadFileMetadata, "$typeName">> = {},
+  ): Promise<UploadFileResponse> {
+    async function* messages(): AsyncGenerator<UploadFileRequest> {
+      yield create(UploadFileRequestSchema, {
+        data: {
+          case: "metadata",
+          value: { ...metadata, storeId, filename, size: BigInt(data.byteLength) },
+        },
+      });
+      for (let offset = 0; offset < data.byteLength; offset += UPLOAD_CHUNK_SIZE) {
+        yield create(UploadFileRequestSchema, {
+          data: { case: "chunk", value: data.subarray(offset, offset + UPLOAD_CHUNK_SIZE) },
+        });
+      }
+    }
+    return this.files.uploadFile(messages());
+  }
+}
@@ -35,9 +35,12 @@ func main() {
 	rootCmd.AddCommand(cli.HealthCmd(clientFactory))
 	rootCmd.AddCommand(cli.ActivityCmd(clientFactory))
 	rootCmd.AddCommand(cli.TestCmd(clientFactory))
+	rootCmd.AddCommand(cli.ProvidersCmd(clientFactory))
+	rootCmd.AddCommand(cli.ConfigCmd(clientFactory))
 	rootCmd.AddCommand(cli.DebugCmd(clientFactory))
 	rootCmd.AddCommand(cli.ThreadCmd(clientFactory))
 	rootCmd.AddCommand(cli.WatchCmd(clientFactory))
+	rootCmd.AddCommand(cli.LoadtestCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -138,6 +138,13 @@ func replaceGlobalSecretsWithReferences(cfg *config.Config) {
 		cfg.Auth.AdminToken = "ENV=AIRBORNE_ADMIN_TOKEN"
 	}
 
+	// Replace internal signing secrets, one variable per key
+	for i, k := range cfg.Auth.InternalSigning.Keys {
+		if k.Secret != "" && !hasReferencePattern(k.Secret) {
+			cfg.Auth.InternalSigning.Keys[i].Secret = "ENV=AIRBORNE_INTERNAL_SIGNING_SECRET_" + envSuffix(k.ID)
+		}
+	}
+
 	// Replace TLS certificate paths (keep FILE= patterns if present)
 	if cfg.TLS.CertFile != "" &&
 	   !hasReferencePattern(cfg.TLS.CertFile) {
@@ -161,6 +168,16 @@ func hasReferencePattern(value string) bool {
 	       strings.HasPrefix(value, "${")
 }
 
+// envSuffix turns a key ID into an environment variable name suffix.
+func envSuffix(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(id))
+}
+
 func validateTenantConfig(tc *tenant.TenantConfig) error {
 	// Check required fields
 	if tc.TenantID == "" {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -15,9 +16,16 @@ import (
 
 	airbornev1 "github.com/ai8future/airborne/gen/go/airborne/v1"
 	"github.com/ai8future/airborne/internal/admin"
+	"github.com/ai8future/airborne/internal/auth"
 	"github.com/ai8future/airborne/internal/config"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/logctx"
 	"github.com/ai8future/airborne/internal/markdownsvc"
+	"github.com/ai8future/airborne/internal/redact"
+	"github.com/ai8future/airborne/internal/selftest"
 	"github.com/ai8future/airborne/internal/server"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/validation"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -33,8 +41,26 @@ var (
 func main() {
 	// Parse command-line flags
 	healthCheck := flag.Bool("health-check", false, "Run gRPC health check and exit")
+	validateConfig := flag.Bool("validate-config", false, "Validate server and tenant configuration and exit")
+	selfTest := flag.Bool("selftest", false, "Run an end-to-end smoke test against an in-process server with mock dependencies and exit")
 	flag.Parse()
 
+	if *selfTest {
+		if err := runSelfTest(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *validateConfig {
+		if err := runValidateConfig(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// If health check mode, run the check and exit
 	if *healthCheck {
 		if err := runHealthCheck(); err != nil {
@@ -63,6 +89,20 @@ func main() {
 		"grpc_port", cfg.Server.GRPCPort,
 	)
 
+	// Let custom base URLs reach allowlisted internal gateways
+	allowlist, err := validation.ParseURLAllowlist(cfg.Egress.Allowlist)
+	if err != nil {
+		slog.Error("invalid egress allowlist", "error", err)
+		os.Exit(1)
+	}
+	validation.SetURLAllowlist(allowlist)
+
+	// Route provider traffic through the corporate proxy, if any
+	if err := egress.SetDefaultProxy(cfg.Egress.Proxy); err != nil {
+		slog.Error("invalid egress proxy", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize markdown_svc client (optional service)
 	if err := markdownsvc.Initialize(cfg.MarkdownSvcAddr); err != nil {
 		slog.Error("markdownsvc init failed", "error", err)
@@ -112,12 +152,24 @@ func main() {
 		}
 		grpcAddr := fmt.Sprintf("%s:%d", grpcHost, cfg.Server.GRPCPort)
 
+		// Sign dashboard calls instead of forwarding the admin token when enabled
+		var signer *auth.RequestSigner
+		if key, ok := cfg.Auth.InternalSigning.SigningKey(); cfg.Auth.InternalSigning.Enabled && ok {
+			signer = auth.NewRequestSigner(key)
+		}
+
 		adminServer = admin.NewServer(components.DBClient, admin.Config{
 			Port:        cfg.Admin.Port,
 			GRPCAddr:    grpcAddr,
 			AuthToken:   cfg.Auth.AdminToken,
+			Signer:      signer,
 			TenantMgr:   components.TenantMgr,
 			RedisClient: components.RedisClient,
+			Metrics:     components.Metrics,
+			Effective:   cfg,
+			Docs:        cfg.Admin.Docs,
+			Network:     cfg.Admin.Network,
+			GeoIP:       components.GeoIP,
 			Version: admin.VersionInfo{
 				Version:   Version,
 				GitCommit: GitCommit,
@@ -159,7 +211,8 @@ func configureLogger(cfg config.LoggingConfig) {
 		level = slog.LevelError
 	}
 
-	opts := &slog.HandlerOptions{Level: level}
+	// Credentials are scrubbed from every attribute before it is written
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact.ReplaceAttr}
 	var handler slog.Handler
 	if strings.ToLower(cfg.Format) == "text" {
 		handler = slog.NewTextHandler(os.Stdout, opts)
@@ -167,7 +220,51 @@ func configureLogger(cfg config.LoggingConfig) {
 		handler = slog.NewJSONHandler(os.Stdout, opts)
 	}
 
-	slog.SetDefault(slog.New(handler))
+	// Tag request logs with the request's trace ID, tenant, client and IDs
+	slog.SetDefault(slog.New(logctx.NewHandler(handler)))
+}
+
+// runValidateConfig loads the server and tenant configuration the way startup
+// does and reports every problem found, without starting any listeners.
+func runValidateConfig() error {
+	if _, err := config.Load(); err != nil {
+		return err
+	}
+
+	tenants, err := tenant.Load("")
+	switch {
+	case errors.Is(err, tenant.ErrNoTenantConfigs):
+		fmt.Println("configuration OK (no tenant configs: single-tenant legacy mode)")
+		return nil
+	case err != nil:
+		return err
+	}
+
+	fmt.Printf("configuration OK (%d tenants: %s)\n", tenants.TenantCount(), strings.Join(tenants.TenantCodes(), ", "))
+	return nil
+}
+
+// runSelfTest starts the server in-process with mock providers, SQLite and
+// in-memory RAG, runs the smoke-test suite against it and prints each
+// check's outcome. It ignores the deployed configuration and needs no
+// external services.
+func runSelfTest() error {
+	// Only warnings from the server; the report goes to stdout
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	report, err := selftest.Run(context.Background(), server.VersionInfo{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+	})
+	if err != nil {
+		return fmt.Errorf("selftest could not start: %w", err)
+	}
+	report.Print(os.Stdout)
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("selftest failed: %d checks", len(failed))
+	}
+	return nil
 }
 
 // runHealthCheck performs a gRPC health check against the AdminService/Health endpoint
@@ -215,8 +312,15 @@ func runHealthCheck() error {
 		return fmt.Errorf("health check RPC failed: %w", err)
 	}
 
-	// Check if status is healthy
-	if resp.Status != "healthy" {
+	// Degraded means Redis-dependent features are on their fallback policy;
+	// the process is alive, so restarting it would not help
+	switch resp.Status {
+	case "healthy":
+	case "degraded":
+		for _, d := range resp.Degraded {
+			fmt.Fprintf(os.Stderr, "degraded: %s (policy %s)\n", d.Feature, d.Policy)
+		}
+	default:
 		return fmt.Errorf("server unhealthy: status=%s", resp.Status)
 	}
 
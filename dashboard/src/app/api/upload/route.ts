@@ -6,6 +6,7 @@ interface UploadResponse {
   file_uri?: string;
   filename?: string;
   mime_type?: string;
+  expires_at?: string;
   error?: string;
 }
 
@@ -14,6 +15,7 @@ export async function POST(request: NextRequest) {
     const formData = await request.formData();
     const file = formData.get("file") as File | null;
     const tenantId = formData.get("tenant_id") as string | null;
+    const threadId = formData.get("thread_id") as string | null;
 
     if (!file) {
       return NextResponse.json(
@@ -28,6 +30,9 @@ export async function POST(request: NextRequest) {
     if (tenantId) {
       backendFormData.append("tenant_id", tenantId);
     }
+    if (threadId) {
+      backendFormData.append("thread_id", threadId);
+    }
 
     const uploadResponse = await fetch(`${AIRBORNE_ADMIN_URL}/admin/upload`, {
       method: "POST",
@@ -804,6 +804,7 @@ export default function ConversationPanel({ activity, selectedThreadId, onSelect
         const formData = new FormData();
         formData.append("file", selectedFile);
         formData.append("tenant_id", tenant);
+        formData.append("thread_id", threadId);
 
         const uploadRes = await fetch('/api/upload', {
           method: 'POST',
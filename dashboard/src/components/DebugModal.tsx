@@ -8,6 +8,7 @@ interface DebugData {
   tenant_id: string;
   user_id: string;
   timestamp: string;
+  trace_id?: string;
 
   // Request
   system_prompt: string;
@@ -15,6 +16,8 @@ interface DebugData {
   request_model: string;
   request_provider: string;
   request_timestamp: string;
+  final_system_prompt?: string;
+  final_user_prompt?: string;
 
   // Response
   response_text: string;
@@ -337,6 +340,14 @@ export default function DebugModal({ messageId, onClose }: DebugModalProps) {
                     {debugData.thread_id || "N/A"}
                   </div>
                 </div>
+                {debugData.trace_id && (
+                  <div className="mt-2">
+                    <span className="text-gray-500 text-sm">Trace ID:</span>
+                    <div className="font-medium text-gray-800 font-mono text-xs">
+                      {debugData.trace_id}
+                    </div>
+                  </div>
+                )}
               </div>
 
               {/* Content panels */}
@@ -384,6 +395,24 @@ export default function DebugModal({ messageId, onClose }: DebugModalProps) {
                             {debugData.user_input || "(No user input)"}
                           </pre>
                         </div>
+
+                        {/* Final prompts as assembled by the provider */}
+                        {debugData.final_system_prompt && debugData.final_system_prompt !== debugData.system_prompt && (
+                          <div>
+                            <h4 className="text-sm font-medium text-gray-600 mb-2">Final System Prompt (as sent)</h4>
+                            <pre className="bg-gray-100 rounded p-3 text-sm whitespace-pre-wrap font-mono overflow-x-auto max-h-48 overflow-y-auto">
+                              {debugData.final_system_prompt}
+                            </pre>
+                          </div>
+                        )}
+                        {debugData.final_user_prompt && debugData.final_user_prompt !== debugData.user_input && (
+                          <div>
+                            <h4 className="text-sm font-medium text-gray-600 mb-2">Final User Prompt (as sent)</h4>
+                            <pre className="bg-gray-100 rounded p-3 text-sm whitespace-pre-wrap font-mono overflow-x-auto max-h-48 overflow-y-auto">
+                              {debugData.final_user_prompt}
+                            </pre>
+                          </div>
+                        )}
                       </div>
                     </div>
                   </div>
@@ -61,9 +61,10 @@ func (*HealthRequest) Descriptor() ([]byte, []int) {
 // HealthResponse contains basic health info
 type HealthResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`   // "healthy" or "unhealthy"
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`   // "healthy", "degraded" or "unhealthy"
 	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // Server version
 	UptimeSeconds int64                  `protobuf:"varint,3,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	Degraded      []*DegradedFeature     `protobuf:"bytes,4,rep,name=degraded,proto3" json:"degraded,omitempty"` // Features running without Redis
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -119,6 +120,83 @@ func (x *HealthResponse) GetUptimeSeconds() int64 {
 	return 0
 }
 
+func (x *HealthResponse) GetDegraded() []*DegradedFeature {
+	if x != nil {
+		return x.Degraded
+	}
+	return nil
+}
+
+// DegradedFeature describes a Redis-dependent feature operating under its
+// fallback policy because Redis is unavailable
+type DegradedFeature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feature       string                 `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`                       // "rate_limit" or "idempotency"
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`                         // "memory" or "fail_closed"
+	SinceUnix     int64                  `protobuf:"varint,3,opt,name=since_unix,json=sinceUnix,proto3" json:"since_unix,omitempty"` // When the degradation started
+	Activations   int64                  `protobuf:"varint,4,opt,name=activations,proto3" json:"activations,omitempty"`              // Requests handled under the policy since startup
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DegradedFeature) Reset() {
+	*x = DegradedFeature{}
+	mi := &file_airborne_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DegradedFeature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DegradedFeature) ProtoMessage() {}
+
+func (x *DegradedFeature) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DegradedFeature.ProtoReflect.Descriptor instead.
+func (*DegradedFeature) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DegradedFeature) GetFeature() string {
+	if x != nil {
+		return x.Feature
+	}
+	return ""
+}
+
+func (x *DegradedFeature) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *DegradedFeature) GetSinceUnix() int64 {
+	if x != nil {
+		return x.SinceUnix
+	}
+	return 0
+}
+
+func (x *DegradedFeature) GetActivations() int64 {
+	if x != nil {
+		return x.Activations
+	}
+	return 0
+}
+
 // ReadyRequest is empty
 type ReadyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -128,7 +206,7 @@ type ReadyRequest struct {
 
 func (x *ReadyRequest) Reset() {
 	*x = ReadyRequest{}
-	mi := &file_airborne_v1_admin_proto_msgTypes[2]
+	mi := &file_airborne_v1_admin_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -140,7 +218,7 @@ func (x *ReadyRequest) String() string {
 func (*ReadyRequest) ProtoMessage() {}
 
 func (x *ReadyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_admin_proto_msgTypes[2]
+	mi := &file_airborne_v1_admin_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -153,7 +231,7 @@ func (x *ReadyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadyRequest.ProtoReflect.Descriptor instead.
 func (*ReadyRequest) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{2}
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{3}
 }
 
 // ReadyResponse contains dependency readiness
@@ -167,7 +245,7 @@ type ReadyResponse struct {
 
 func (x *ReadyResponse) Reset() {
 	*x = ReadyResponse{}
-	mi := &file_airborne_v1_admin_proto_msgTypes[3]
+	mi := &file_airborne_v1_admin_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -179,7 +257,7 @@ func (x *ReadyResponse) String() string {
 func (*ReadyResponse) ProtoMessage() {}
 
 func (x *ReadyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_admin_proto_msgTypes[3]
+	mi := &file_airborne_v1_admin_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -192,7 +270,7 @@ func (x *ReadyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadyResponse.ProtoReflect.Descriptor instead.
 func (*ReadyResponse) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{3}
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ReadyResponse) GetReady() bool {
@@ -221,7 +299,7 @@ type DependencyStatus struct {
 
 func (x *DependencyStatus) Reset() {
 	*x = DependencyStatus{}
-	mi := &file_airborne_v1_admin_proto_msgTypes[4]
+	mi := &file_airborne_v1_admin_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -233,7 +311,7 @@ func (x *DependencyStatus) String() string {
 func (*DependencyStatus) ProtoMessage() {}
 
 func (x *DependencyStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_admin_proto_msgTypes[4]
+	mi := &file_airborne_v1_admin_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,7 +324,7 @@ func (x *DependencyStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DependencyStatus.ProtoReflect.Descriptor instead.
 func (*DependencyStatus) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{4}
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *DependencyStatus) GetHealthy() bool {
@@ -279,7 +357,7 @@ type VersionRequest struct {
 
 func (x *VersionRequest) Reset() {
 	*x = VersionRequest{}
-	mi := &file_airborne_v1_admin_proto_msgTypes[5]
+	mi := &file_airborne_v1_admin_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -291,7 +369,7 @@ func (x *VersionRequest) String() string {
 func (*VersionRequest) ProtoMessage() {}
 
 func (x *VersionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_admin_proto_msgTypes[5]
+	mi := &file_airborne_v1_admin_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -304,7 +382,7 @@ func (x *VersionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VersionRequest.ProtoReflect.Descriptor instead.
 func (*VersionRequest) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{5}
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{6}
 }
 
 // VersionResponse contains detailed version info
@@ -320,7 +398,7 @@ type VersionResponse struct {
 
 func (x *VersionResponse) Reset() {
 	*x = VersionResponse{}
-	mi := &file_airborne_v1_admin_proto_msgTypes[6]
+	mi := &file_airborne_v1_admin_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -332,7 +410,7 @@ func (x *VersionResponse) String() string {
 func (*VersionResponse) ProtoMessage() {}
 
 func (x *VersionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_admin_proto_msgTypes[6]
+	mi := &file_airborne_v1_admin_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -345,7 +423,7 @@ func (x *VersionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
 func (*VersionResponse) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{6}
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *VersionResponse) GetVersion() string {
@@ -376,16 +454,342 @@ func (x *VersionResponse) GetGoVersion() string {
 	return ""
 }
 
+// GetProviderHealthRequest selects the tenants to report
+type GetProviderHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"` // Restrict to one tenant; empty for all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProviderHealthRequest) Reset() {
+	*x = GetProviderHealthRequest{}
+	mi := &file_airborne_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProviderHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProviderHealthRequest) ProtoMessage() {}
+
+func (x *GetProviderHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProviderHealthRequest.ProtoReflect.Descriptor instead.
+func (*GetProviderHealthRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetProviderHealthRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// GetProviderHealthResponse lists provider health per tenant, ordered by
+// tenant and provider
+type GetProviderHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Providers     []*ProviderHealth      `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	WindowSeconds int64                  `protobuf:"varint,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // Span error rates are computed over
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProviderHealthResponse) Reset() {
+	*x = GetProviderHealthResponse{}
+	mi := &file_airborne_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProviderHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProviderHealthResponse) ProtoMessage() {}
+
+func (x *GetProviderHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProviderHealthResponse.ProtoReflect.Descriptor instead.
+func (*GetProviderHealthResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetProviderHealthResponse) GetProviders() []*ProviderHealth {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *GetProviderHealthResponse) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// ProviderHealth is one provider's health for one tenant, since the server
+// started
+type ProviderHealth struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TenantId        string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Provider        string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Enabled         bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`                                          // Enabled in the tenant config
+	KeyStatus       string                 `protobuf:"bytes,4,opt,name=key_status,json=keyStatus,proto3" json:"key_status,omitempty"`                      // "missing", "unknown", "valid" or "invalid"
+	LastSuccessUnix int64                  `protobuf:"varint,5,opt,name=last_success_unix,json=lastSuccessUnix,proto3" json:"last_success_unix,omitempty"` // 0 if no call has succeeded
+	LastFailureUnix int64                  `protobuf:"varint,6,opt,name=last_failure_unix,json=lastFailureUnix,proto3" json:"last_failure_unix,omitempty"` // 0 if no call has failed
+	LastError       string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Calls           int32                  `protobuf:"varint,8,opt,name=calls,proto3" json:"calls,omitempty"`                            // Calls within the window
+	Errors          int32                  `protobuf:"varint,9,opt,name=errors,proto3" json:"errors,omitempty"`                          // Failed calls within the window
+	ErrorRate       float64                `protobuf:"fixed64,10,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"` // errors / calls; 0 without calls
+	Headroom        *RateLimitHeadroom     `protobuf:"bytes,11,opt,name=headroom,proto3" json:"headroom,omitempty"`                      // Unset until the provider reports rate limits
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProviderHealth) Reset() {
+	*x = ProviderHealth{}
+	mi := &file_airborne_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderHealth) ProtoMessage() {}
+
+func (x *ProviderHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderHealth.ProtoReflect.Descriptor instead.
+func (*ProviderHealth) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProviderHealth) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ProviderHealth) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderHealth) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ProviderHealth) GetKeyStatus() string {
+	if x != nil {
+		return x.KeyStatus
+	}
+	return ""
+}
+
+func (x *ProviderHealth) GetLastSuccessUnix() int64 {
+	if x != nil {
+		return x.LastSuccessUnix
+	}
+	return 0
+}
+
+func (x *ProviderHealth) GetLastFailureUnix() int64 {
+	if x != nil {
+		return x.LastFailureUnix
+	}
+	return 0
+}
+
+func (x *ProviderHealth) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ProviderHealth) GetCalls() int32 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *ProviderHealth) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ProviderHealth) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *ProviderHealth) GetHeadroom() *RateLimitHeadroom {
+	if x != nil {
+		return x.Headroom
+	}
+	return nil
+}
+
+// RateLimitHeadroom is the rate-limit state last reported in a provider's
+// response headers
+type RateLimitHeadroom struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RemainingRequests int32                  `protobuf:"varint,1,opt,name=remaining_requests,json=remainingRequests,proto3" json:"remaining_requests,omitempty"` // -1 when not reported
+	RemainingTokens   int32                  `protobuf:"varint,2,opt,name=remaining_tokens,json=remainingTokens,proto3" json:"remaining_tokens,omitempty"`       // -1 when not reported
+	RequestsResetUnix int64                  `protobuf:"varint,3,opt,name=requests_reset_unix,json=requestsResetUnix,proto3" json:"requests_reset_unix,omitempty"`
+	TokensResetUnix   int64                  `protobuf:"varint,4,opt,name=tokens_reset_unix,json=tokensResetUnix,proto3" json:"tokens_reset_unix,omitempty"`
+	RetryAfterUnix    int64                  `protobuf:"varint,5,opt,name=retry_after_unix,json=retryAfterUnix,proto3" json:"retry_after_unix,omitempty"` // Set after a 429
+	WaitMs            int64                  `protobuf:"varint,6,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`                           // Until the provider has headroom again; 0 if it has now
+	UpdatedUnix       int64                  `protobuf:"varint,7,opt,name=updated_unix,json=updatedUnix,proto3" json:"updated_unix,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RateLimitHeadroom) Reset() {
+	*x = RateLimitHeadroom{}
+	mi := &file_airborne_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitHeadroom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitHeadroom) ProtoMessage() {}
+
+func (x *RateLimitHeadroom) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitHeadroom.ProtoReflect.Descriptor instead.
+func (*RateLimitHeadroom) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RateLimitHeadroom) GetRemainingRequests() int32 {
+	if x != nil {
+		return x.RemainingRequests
+	}
+	return 0
+}
+
+func (x *RateLimitHeadroom) GetRemainingTokens() int32 {
+	if x != nil {
+		return x.RemainingTokens
+	}
+	return 0
+}
+
+func (x *RateLimitHeadroom) GetRequestsResetUnix() int64 {
+	if x != nil {
+		return x.RequestsResetUnix
+	}
+	return 0
+}
+
+func (x *RateLimitHeadroom) GetTokensResetUnix() int64 {
+	if x != nil {
+		return x.TokensResetUnix
+	}
+	return 0
+}
+
+func (x *RateLimitHeadroom) GetRetryAfterUnix() int64 {
+	if x != nil {
+		return x.RetryAfterUnix
+	}
+	return 0
+}
+
+func (x *RateLimitHeadroom) GetWaitMs() int64 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+func (x *RateLimitHeadroom) GetUpdatedUnix() int64 {
+	if x != nil {
+		return x.UpdatedUnix
+	}
+	return 0
+}
+
 var File_airborne_v1_admin_proto protoreflect.FileDescriptor
 
 const file_airborne_v1_admin_proto_rawDesc = "" +
 	"\n" +
 	"\x17airborne/v1/admin.proto\x12\vairborne.v1\"\x0f\n" +
-	"\rHealthRequest\"i\n" +
+	"\rHealthRequest\"\xa3\x01\n" +
 	"\x0eHealthResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12%\n" +
-	"\x0euptime_seconds\x18\x03 \x01(\x03R\ruptimeSeconds\"\x0e\n" +
+	"\x0euptime_seconds\x18\x03 \x01(\x03R\ruptimeSeconds\x128\n" +
+	"\bdegraded\x18\x04 \x03(\v2\x1c.airborne.v1.DegradedFeatureR\bdegraded\"\x84\x01\n" +
+	"\x0fDegradedFeature\x12\x18\n" +
+	"\afeature\x18\x01 \x01(\tR\afeature\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\x12\x1d\n" +
+	"\n" +
+	"since_unix\x18\x03 \x01(\x03R\tsinceUnix\x12 \n" +
+	"\vactivations\x18\x04 \x01(\x03R\vactivations\"\x0e\n" +
 	"\fReadyRequest\"\xd7\x01\n" +
 	"\rReadyResponse\x12\x14\n" +
 	"\x05ready\x18\x01 \x01(\bR\x05ready\x12P\n" +
@@ -406,11 +810,41 @@ const file_airborne_v1_admin_proto_rawDesc = "" +
 	"\n" +
 	"build_time\x18\x03 \x01(\tR\tbuildTime\x12\x1d\n" +
 	"\n" +
-	"go_version\x18\x04 \x01(\tR\tgoVersion2\xd7\x01\n" +
+	"go_version\x18\x04 \x01(\tR\tgoVersion\"7\n" +
+	"\x18GetProviderHealthRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"}\n" +
+	"\x19GetProviderHealthResponse\x129\n" +
+	"\tproviders\x18\x01 \x03(\v2\x1b.airborne.v1.ProviderHealthR\tproviders\x12%\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\x03R\rwindowSeconds\"\x82\x03\n" +
+	"\x0eProviderHealth\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\x12\x1d\n" +
+	"\n" +
+	"key_status\x18\x04 \x01(\tR\tkeyStatus\x12*\n" +
+	"\x11last_success_unix\x18\x05 \x01(\x03R\x0flastSuccessUnix\x12*\n" +
+	"\x11last_failure_unix\x18\x06 \x01(\x03R\x0flastFailureUnix\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x12\x14\n" +
+	"\x05calls\x18\b \x01(\x05R\x05calls\x12\x16\n" +
+	"\x06errors\x18\t \x01(\x05R\x06errors\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\n" +
+	" \x01(\x01R\terrorRate\x12:\n" +
+	"\bheadroom\x18\v \x01(\v2\x1e.airborne.v1.RateLimitHeadroomR\bheadroom\"\xaf\x02\n" +
+	"\x11RateLimitHeadroom\x12-\n" +
+	"\x12remaining_requests\x18\x01 \x01(\x05R\x11remainingRequests\x12)\n" +
+	"\x10remaining_tokens\x18\x02 \x01(\x05R\x0fremainingTokens\x12.\n" +
+	"\x13requests_reset_unix\x18\x03 \x01(\x03R\x11requestsResetUnix\x12*\n" +
+	"\x11tokens_reset_unix\x18\x04 \x01(\x03R\x0ftokensResetUnix\x12(\n" +
+	"\x10retry_after_unix\x18\x05 \x01(\x03R\x0eretryAfterUnix\x12\x17\n" +
+	"\await_ms\x18\x06 \x01(\x03R\x06waitMs\x12!\n" +
+	"\fupdated_unix\x18\a \x01(\x03R\vupdatedUnix2\xbb\x02\n" +
 	"\fAdminService\x12A\n" +
 	"\x06Health\x12\x1a.airborne.v1.HealthRequest\x1a\x1b.airborne.v1.HealthResponse\x12>\n" +
 	"\x05Ready\x12\x19.airborne.v1.ReadyRequest\x1a\x1a.airborne.v1.ReadyResponse\x12D\n" +
-	"\aVersion\x12\x1b.airborne.v1.VersionRequest\x1a\x1c.airborne.v1.VersionResponseB\xa7\x01\n" +
+	"\aVersion\x12\x1b.airborne.v1.VersionRequest\x1a\x1c.airborne.v1.VersionResponse\x12b\n" +
+	"\x11GetProviderHealth\x12%.airborne.v1.GetProviderHealthRequest\x1a&.airborne.v1.GetProviderHealthResponseB\xa7\x01\n" +
 	"\x0fcom.airborne.v1B\n" +
 	"AdminProtoP\x01Z;github.com/ai8future/airborne/gen/go/airborne/v1;airbornev1\xa2\x02\x03AXX\xaa\x02\vAirborne.V1\xca\x02\vAirborne\\V1\xe2\x02\x17Airborne\\V1\\GPBMetadata\xea\x02\fAirborne::V1b\x06proto3"
 
@@ -426,31 +860,41 @@ func file_airborne_v1_admin_proto_rawDescGZIP() []byte {
 	return file_airborne_v1_admin_proto_rawDescData
 }
 
-var file_airborne_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_airborne_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_airborne_v1_admin_proto_goTypes = []any{
-	(*HealthRequest)(nil),    // 0: airborne.v1.HealthRequest
-	(*HealthResponse)(nil),   // 1: airborne.v1.HealthResponse
-	(*ReadyRequest)(nil),     // 2: airborne.v1.ReadyRequest
-	(*ReadyResponse)(nil),    // 3: airborne.v1.ReadyResponse
-	(*DependencyStatus)(nil), // 4: airborne.v1.DependencyStatus
-	(*VersionRequest)(nil),   // 5: airborne.v1.VersionRequest
-	(*VersionResponse)(nil),  // 6: airborne.v1.VersionResponse
-	nil,                      // 7: airborne.v1.ReadyResponse.DependenciesEntry
+	(*HealthRequest)(nil),             // 0: airborne.v1.HealthRequest
+	(*HealthResponse)(nil),            // 1: airborne.v1.HealthResponse
+	(*DegradedFeature)(nil),           // 2: airborne.v1.DegradedFeature
+	(*ReadyRequest)(nil),              // 3: airborne.v1.ReadyRequest
+	(*ReadyResponse)(nil),             // 4: airborne.v1.ReadyResponse
+	(*DependencyStatus)(nil),          // 5: airborne.v1.DependencyStatus
+	(*VersionRequest)(nil),            // 6: airborne.v1.VersionRequest
+	(*VersionResponse)(nil),           // 7: airborne.v1.VersionResponse
+	(*GetProviderHealthRequest)(nil),  // 8: airborne.v1.GetProviderHealthRequest
+	(*GetProviderHealthResponse)(nil), // 9: airborne.v1.GetProviderHealthResponse
+	(*ProviderHealth)(nil),            // 10: airborne.v1.ProviderHealth
+	(*RateLimitHeadroom)(nil),         // 11: airborne.v1.RateLimitHeadroom
+	nil,                               // 12: airborne.v1.ReadyResponse.DependenciesEntry
 }
 var file_airborne_v1_admin_proto_depIdxs = []int32{
-	7, // 0: airborne.v1.ReadyResponse.dependencies:type_name -> airborne.v1.ReadyResponse.DependenciesEntry
-	4, // 1: airborne.v1.ReadyResponse.DependenciesEntry.value:type_name -> airborne.v1.DependencyStatus
-	0, // 2: airborne.v1.AdminService.Health:input_type -> airborne.v1.HealthRequest
-	2, // 3: airborne.v1.AdminService.Ready:input_type -> airborne.v1.ReadyRequest
-	5, // 4: airborne.v1.AdminService.Version:input_type -> airborne.v1.VersionRequest
-	1, // 5: airborne.v1.AdminService.Health:output_type -> airborne.v1.HealthResponse
-	3, // 6: airborne.v1.AdminService.Ready:output_type -> airborne.v1.ReadyResponse
-	6, // 7: airborne.v1.AdminService.Version:output_type -> airborne.v1.VersionResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: airborne.v1.HealthResponse.degraded:type_name -> airborne.v1.DegradedFeature
+	12, // 1: airborne.v1.ReadyResponse.dependencies:type_name -> airborne.v1.ReadyResponse.DependenciesEntry
+	10, // 2: airborne.v1.GetProviderHealthResponse.providers:type_name -> airborne.v1.ProviderHealth
+	11, // 3: airborne.v1.ProviderHealth.headroom:type_name -> airborne.v1.RateLimitHeadroom
+	5,  // 4: airborne.v1.ReadyResponse.DependenciesEntry.value:type_name -> airborne.v1.DependencyStatus
+	0,  // 5: airborne.v1.AdminService.Health:input_type -> airborne.v1.HealthRequest
+	3,  // 6: airborne.v1.AdminService.Ready:input_type -> airborne.v1.ReadyRequest
+	6,  // 7: airborne.v1.AdminService.Version:input_type -> airborne.v1.VersionRequest
+	8,  // 8: airborne.v1.AdminService.GetProviderHealth:input_type -> airborne.v1.GetProviderHealthRequest
+	1,  // 9: airborne.v1.AdminService.Health:output_type -> airborne.v1.HealthResponse
+	4,  // 10: airborne.v1.AdminService.Ready:output_type -> airborne.v1.ReadyResponse
+	7,  // 11: airborne.v1.AdminService.Version:output_type -> airborne.v1.VersionResponse
+	9,  // 12: airborne.v1.AdminService.GetProviderHealth:output_type -> airborne.v1.GetProviderHealthResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_airborne_v1_admin_proto_init() }
@@ -464,7 +908,7 @@ func file_airborne_v1_admin_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_airborne_v1_admin_proto_rawDesc), len(file_airborne_v1_admin_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
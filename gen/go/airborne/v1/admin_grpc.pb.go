@@ -19,9 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AdminService_Health_FullMethodName  = "/airborne.v1.AdminService/Health"
-	AdminService_Ready_FullMethodName   = "/airborne.v1.AdminService/Ready"
-	AdminService_Version_FullMethodName = "/airborne.v1.AdminService/Version"
+	AdminService_Health_FullMethodName            = "/airborne.v1.AdminService/Health"
+	AdminService_Ready_FullMethodName             = "/airborne.v1.AdminService/Ready"
+	AdminService_Version_FullMethodName           = "/airborne.v1.AdminService/Version"
+	AdminService_GetProviderHealth_FullMethodName = "/airborne.v1.AdminService/GetProviderHealth"
 )
 
 // AdminServiceClient is the client API for AdminService service.
@@ -36,6 +37,9 @@ type AdminServiceClient interface {
 	Ready(ctx context.Context, in *ReadyRequest, opts ...grpc.CallOption) (*ReadyResponse, error)
 	// Version returns version information
 	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	// GetProviderHealth reports each tenant's providers: recent call outcomes,
+	// rate-limit headroom and API key state
+	GetProviderHealth(ctx context.Context, in *GetProviderHealthRequest, opts ...grpc.CallOption) (*GetProviderHealthResponse, error)
 }
 
 type adminServiceClient struct {
@@ -76,6 +80,16 @@ func (c *adminServiceClient) Version(ctx context.Context, in *VersionRequest, op
 	return out, nil
 }
 
+func (c *adminServiceClient) GetProviderHealth(ctx context.Context, in *GetProviderHealthRequest, opts ...grpc.CallOption) (*GetProviderHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProviderHealthResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetProviderHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdminServiceServer is the server API for AdminService service.
 // All implementations must embed UnimplementedAdminServiceServer
 // for forward compatibility.
@@ -88,6 +102,9 @@ type AdminServiceServer interface {
 	Ready(context.Context, *ReadyRequest) (*ReadyResponse, error)
 	// Version returns version information
 	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+	// GetProviderHealth reports each tenant's providers: recent call outcomes,
+	// rate-limit headroom and API key state
+	GetProviderHealth(context.Context, *GetProviderHealthRequest) (*GetProviderHealthResponse, error)
 	mustEmbedUnimplementedAdminServiceServer()
 }
 
@@ -107,6 +124,9 @@ func (UnimplementedAdminServiceServer) Ready(context.Context, *ReadyRequest) (*R
 func (UnimplementedAdminServiceServer) Version(context.Context, *VersionRequest) (*VersionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Version not implemented")
 }
+func (UnimplementedAdminServiceServer) GetProviderHealth(context.Context, *GetProviderHealthRequest) (*GetProviderHealthResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProviderHealth not implemented")
+}
 func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
 func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
 
@@ -182,6 +202,24 @@ func _AdminService_Version_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminService_GetProviderHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProviderHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetProviderHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetProviderHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetProviderHealth(ctx, req.(*GetProviderHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -201,6 +239,10 @@ var AdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _AdminService_Version_Handler,
 		},
+		{
+			MethodName: "GetProviderHealth",
+			Handler:    _AdminService_GetProviderHealth_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "airborne/v1/admin.proto",
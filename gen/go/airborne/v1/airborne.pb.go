@@ -34,8 +34,8 @@ type GenerateReplyRequest struct {
 	// Optional: Conversation history for context
 	ConversationHistory []*Message `protobuf:"bytes,3,rep,name=conversation_history,json=conversationHistory,proto3" json:"conversation_history,omitempty"`
 	// Provider selection
-	PreferredProvider Provider `protobuf:"varint,4,opt,name=preferred_provider,json=preferredProvider,proto3,enum=airborne.v1.Provider" json:"preferred_provider,omitempty"` // Which provider to use
-	ModelOverride     string   `protobuf:"bytes,5,opt,name=model_override,json=modelOverride,proto3" json:"model_override,omitempty"`                                        // Override the default model
+	PreferredProvider Provider `protobuf:"varint,4,opt,name=preferred_provider,json=preferredProvider,proto3,enum=airborne.v1.Provider" json:"preferred_provider,omitempty"` // Which provider to use; unspecified continues the thread's pinned provider
+	ModelOverride     string   `protobuf:"bytes,5,opt,name=model_override,json=modelOverride,proto3" json:"model_override,omitempty"`                                        // Override the default model; "auto" tries the tenant's cheap model first (GenerateReply only)
 	// Feature flags
 	EnableFileSearch    bool `protobuf:"varint,6,opt,name=enable_file_search,json=enableFileSearch,proto3" json:"enable_file_search,omitempty"`           // Enable RAG with file search
 	EnableWebSearch     bool `protobuf:"varint,7,opt,name=enable_web_search,json=enableWebSearch,proto3" json:"enable_web_search,omitempty"`              // Enable web search grounding
@@ -49,20 +49,82 @@ type GenerateReplyRequest struct {
 	// Key is provider name: "openai", "gemini", "anthropic"
 	ProviderConfigs map[string]*ProviderConfig `protobuf:"bytes,11,rep,name=provider_configs,json=providerConfigs,proto3" json:"provider_configs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	// Failover settings
-	EnableFailover   bool     `protobuf:"varint,12,opt,name=enable_failover,json=enableFailover,proto3" json:"enable_failover,omitempty"`                                 // Enable automatic failover on error
-	FallbackProvider Provider `protobuf:"varint,13,opt,name=fallback_provider,json=fallbackProvider,proto3,enum=airborne.v1.Provider" json:"fallback_provider,omitempty"` // Specific fallback provider (or use server default order)
+	EnableFailover   bool     `protobuf:"varint,12,opt,name=enable_failover,json=enableFailover,proto3" json:"enable_failover,omitempty"`                                 // Enable automatic failover on error (GenerateReply only)
+	FallbackProvider Provider `protobuf:"varint,13,opt,name=fallback_provider,json=fallbackProvider,proto3,enum=airborne.v1.Provider" json:"fallback_provider,omitempty"` // Specific fallback provider (or use the tenant's failover order)
 	// Request metadata
 	ClientId  string            `protobuf:"bytes,14,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`                                                           // Identifies the calling client
 	RequestId string            `protobuf:"bytes,15,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                                                        // Client-provided request ID for tracing
 	Metadata  map[string]string `protobuf:"bytes,16,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (e.g., user tier, project code)
 	// Tool/Function calling
-	Tools       []*Tool       `protobuf:"bytes,19,rep,name=tools,proto3" json:"tools,omitempty"`                                // Available tools the model can call
-	ToolResults []*ToolResult `protobuf:"bytes,20,rep,name=tool_results,json=toolResults,proto3" json:"tool_results,omitempty"` // Results from previous tool calls (for multi-turn)
+	Tools []*Tool `protobuf:"bytes,19,rep,name=tools,proto3" json:"tools,omitempty"` // Available tools the model can call
+	// Results for the tool calls of the response named by previous_response_id;
+	// user_input may be empty. Parallel calls are answered in one batch with one
+	// result per call, in any order. Sent to the same provider, without failover or hedging.
+	ToolResults []*ToolResult `protobuf:"bytes,20,rep,name=tool_results,json=toolResults,proto3" json:"tool_results,omitempty"`
 	// Enable structured output mode (Gemini-only)
 	// When true, response includes structured_metadata with intent, entities, topics
 	EnableStructuredOutput bool `protobuf:"varint,21,opt,name=enable_structured_output,json=enableStructuredOutput,proto3" json:"enable_structured_output,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	// Enable conversation memory: inject stored facts about user_id into the
+	// prompt and store new durable facts extracted from this turn. Facts are
+	// only extracted by GenerateReply; GenerateReplyStream injects them only
+	EnableMemory bool `protobuf:"varint,22,opt,name=enable_memory,json=enableMemory,proto3" json:"enable_memory,omitempty"`
+	// End-user identifier that memories and the profile are scoped to
+	// (memories default to the authenticated client ID). Naming another
+	// client's user requires the users permission
+	UserId string `protobuf:"bytes,23,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Enable idempotency: an identical request (same request_id and payload)
+	// repeated by the same tenant within the idempotency window returns the
+	// stored response instead of calling the provider again. Later turns of
+	// the thread are generated as usual. Requires request_id. Unary
+	// GenerateReply only.
+	Idempotent bool `protobuf:"varint,24,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+	// Scheduling class when the server is at its concurrency limit:
+	// interactive requests are queued ahead of batch and background work,
+	// which is shed first under provider rate-limit pressure
+	Priority Priority `protobuf:"varint,25,opt,name=priority,proto3,enum=airborne.v1.Priority" json:"priority,omitempty"`
+	// Hedging: if the provider has not responded (unary) or sent its first
+	// token (streaming) within hedge_delay_ms, send the same request to the
+	// failover provider and use whichever answers first, cancelling the other.
+	// Trades extra provider spend for tail latency.
+	EnableHedging bool  `protobuf:"varint,26,opt,name=enable_hedging,json=enableHedging,proto3" json:"enable_hedging,omitempty"`
+	HedgeDelayMs  int32 `protobuf:"varint,27,opt,name=hedge_delay_ms,json=hedgeDelayMs,proto3" json:"hedge_delay_ms,omitempty"` // 0 uses the server default (2000)
+	// Per-category safety thresholds. They can only tighten the tenant's
+	// configured thresholds, never loosen them.
+	Safety *SafetySettings `protobuf:"bytes,28,opt,name=safety,proto3" json:"safety,omitempty"`
+	// Provider-native computer-use tool. Requires the computer_use permission.
+	// Actions are returned as computer_actions for an external executor, which
+	// continues the turn by sending screenshots back as tool_results.
+	ComputerUse *ComputerUse `protobuf:"bytes,29,opt,name=computer_use,json=computerUse,proto3" json:"computer_use,omitempty"`
+	// Total time budget for the request, failover included; 0 uses the
+	// caller's deadline, if any. With failover enabled the primary provider gets
+	// the tenant's primary share of the remaining time (default 70%) and the
+	// rest is split across fallback attempts.
+	TimeoutMs int32 `protobuf:"varint,30,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Per-request feature flag overrides, keyed by flag name
+	// (structured_output, image_generation, code_execution, rag). They replace
+	// the tenant's flags for this request only. Requires admin permission.
+	FeatureOverrides map[string]bool `protobuf:"bytes,31,rep,name=feature_overrides,json=featureOverrides,proto3" json:"feature_overrides,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Files sent with user_input, up to 10 and 20 MB in total. Types the
+	// selected provider cannot read are rejected; text files work everywhere.
+	// Not allowed with tool_results.
+	Attachments []*Attachment `protobuf:"bytes,32,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	// What internal RAG retrieval embeds as its query: "latest" (user_input),
+	// "recent" (the user's recent messages and user_input) or "condense" (a
+	// standalone question rewritten from the recent conversation). Empty uses
+	// the tenant's retrieval.query_mode.
+	RetrievalQueryMode string `protobuf:"bytes,33,opt,name=retrieval_query_mode,json=retrievalQueryMode,proto3" json:"retrieval_query_mode,omitempty"`
+	// Token budget for internal RAG context injected into the instructions.
+	// It can only lower the tenant's retrieval.context_tokens; 0 uses it.
+	RagContextTokens int32 `protobuf:"varint,34,opt,name=rag_context_tokens,json=ragContextTokens,proto3" json:"rag_context_tokens,omitempty"`
+	// Named generation preset from the tenant config (e.g. "precise"),
+	// supplying model, temperature, max output tokens and reasoning effort.
+	// provider_configs override the preset. Unknown presets are rejected.
+	Preset string `protobuf:"bytes,35,opt,name=preset,proto3" json:"preset,omitempty"`
+	// Images sent with user_input by URL or provider file ID, up to 10,
+	// supported by OpenAI and Gemini. Not allowed with tool_results.
+	Images        []*ImageInput `protobuf:"bytes,36,rep,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GenerateReplyRequest) Reset() {
@@ -242,6 +304,111 @@ func (x *GenerateReplyRequest) GetEnableStructuredOutput() bool {
 	return false
 }
 
+func (x *GenerateReplyRequest) GetEnableMemory() bool {
+	if x != nil {
+		return x.EnableMemory
+	}
+	return false
+}
+
+func (x *GenerateReplyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GenerateReplyRequest) GetIdempotent() bool {
+	if x != nil {
+		return x.Idempotent
+	}
+	return false
+}
+
+func (x *GenerateReplyRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *GenerateReplyRequest) GetEnableHedging() bool {
+	if x != nil {
+		return x.EnableHedging
+	}
+	return false
+}
+
+func (x *GenerateReplyRequest) GetHedgeDelayMs() int32 {
+	if x != nil {
+		return x.HedgeDelayMs
+	}
+	return 0
+}
+
+func (x *GenerateReplyRequest) GetSafety() *SafetySettings {
+	if x != nil {
+		return x.Safety
+	}
+	return nil
+}
+
+func (x *GenerateReplyRequest) GetComputerUse() *ComputerUse {
+	if x != nil {
+		return x.ComputerUse
+	}
+	return nil
+}
+
+func (x *GenerateReplyRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *GenerateReplyRequest) GetFeatureOverrides() map[string]bool {
+	if x != nil {
+		return x.FeatureOverrides
+	}
+	return nil
+}
+
+func (x *GenerateReplyRequest) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *GenerateReplyRequest) GetRetrievalQueryMode() string {
+	if x != nil {
+		return x.RetrievalQueryMode
+	}
+	return ""
+}
+
+func (x *GenerateReplyRequest) GetRagContextTokens() int32 {
+	if x != nil {
+		return x.RagContextTokens
+	}
+	return 0
+}
+
+func (x *GenerateReplyRequest) GetPreset() string {
+	if x != nil {
+		return x.Preset
+	}
+	return ""
+}
+
+func (x *GenerateReplyRequest) GetImages() []*ImageInput {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
 // GenerateReplyResponse contains the generated reply
 type GenerateReplyResponse struct {
 	state      protoimpl.MessageState `protogen:"open.v1"`
@@ -269,6 +436,45 @@ type GenerateReplyResponse struct {
 	// Grounding/web search cost tracking
 	GroundingQueries int32   `protobuf:"varint,16,opt,name=grounding_queries,json=groundingQueries,proto3" json:"grounding_queries,omitempty"`    // Number of web search queries executed
 	GroundingCostUsd float64 `protobuf:"fixed64,17,opt,name=grounding_cost_usd,json=groundingCostUsd,proto3" json:"grounding_cost_usd,omitempty"` // Cost of grounding queries in USD
+	// True if this is a stored response replayed for an idempotent retry
+	Cached bool `protobuf:"varint,18,opt,name=cached,proto3" json:"cached,omitempty"`
+	// Estimated total cost in USD (tokens incl. cached/thinking, plus grounding),
+	// from the server's pricing table; 0 if the model is not priced
+	EstimatedCostUsd float64 `protobuf:"fixed64,19,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`
+	// Set when the tenant's budget policy swapped in a cheaper model: the reason
+	// and the model that would otherwise have been used
+	DowngradeReason string `protobuf:"bytes,20,opt,name=downgrade_reason,json=downgradeReason,proto3" json:"downgrade_reason,omitempty"`
+	OriginalModel   string `protobuf:"bytes,21,opt,name=original_model,json=originalModel,proto3" json:"original_model,omitempty"`
+	// True if a hedge request was sent to a second provider; provider is the
+	// one that answered first
+	Hedged bool `protobuf:"varint,22,opt,name=hedged,proto3" json:"hedged,omitempty"`
+	// Set, with text empty, when safety filters blocked the prompt or response
+	SafetyBlock *SafetyBlock `protobuf:"bytes,23,opt,name=safety_block,json=safetyBlock,proto3" json:"safety_block,omitempty"`
+	// Computer-use actions to perform (requires_tool_output is set)
+	ComputerActions []*ComputerAction `protobuf:"bytes,24,rep,name=computer_actions,json=computerActions,proto3" json:"computer_actions,omitempty"`
+	// Providers that failed before the one that answered, in the order tried
+	// (set when failed_over)
+	FailoverAttempts []*FailoverAttempt `protobuf:"bytes,25,rep,name=failover_attempts,json=failoverAttempts,proto3" json:"failover_attempts,omitempty"`
+	// Set when the tenant's judge policy for the request's use_case metadata
+	// generated several candidate replies and returned the best-scoring one
+	Judge *JudgeResult `protobuf:"bytes,26,opt,name=judge,proto3" json:"judge,omitempty"`
+	// Set when the tenant's grounding policy checked a reply generated with
+	// internal RAG context against the retrieved chunks
+	Grounding *GroundingCheck `protobuf:"bytes,27,opt,name=grounding,proto3" json:"grounding,omitempty"`
+	// True if an identical request from the same client was already being
+	// generated and this is its reply, shared instead of a second provider call
+	Deduplicated bool `protobuf:"varint,28,opt,name=deduplicated,proto3" json:"deduplicated,omitempty"`
+	// Set when the tenant's attribution policy is enabled
+	Attribution *Attribution `protobuf:"bytes,29,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	// The text as markdown with citations as numbered footnotes, when
+	// citations have positions in it: a marker after each cited span (a link
+	// for URL citations) and a numbered list of the sources at the end.
+	// html_content is rendered from it.
+	FootnotedMarkdown string `protobuf:"bytes,30,opt,name=footnoted_markdown,json=footnotedMarkdown,proto3" json:"footnoted_markdown,omitempty"`
+	// Set for "auto" model tier requests: "cheap" or "premium", the tier that
+	// served the reply, and why the cheap model's reply was escalated
+	ModelTier        string `protobuf:"bytes,31,opt,name=model_tier,json=modelTier,proto3" json:"model_tier,omitempty"`
+	EscalationReason string `protobuf:"bytes,32,opt,name=escalation_reason,json=escalationReason,proto3" json:"escalation_reason,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -422,191 +628,206 @@ func (x *GenerateReplyResponse) GetGroundingCostUsd() float64 {
 	return 0
 }
 
-// GenerateReplyChunk is a streaming response chunk
-type GenerateReplyChunk struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Chunk:
-	//
-	//	*GenerateReplyChunk_TextDelta
-	//	*GenerateReplyChunk_UsageUpdate
-	//	*GenerateReplyChunk_CitationUpdate
-	//	*GenerateReplyChunk_Complete
-	//	*GenerateReplyChunk_Error
-	//	*GenerateReplyChunk_ToolCallUpdate
-	//	*GenerateReplyChunk_CodeExecutionUpdate
-	Chunk         isGenerateReplyChunk_Chunk `protobuf_oneof:"chunk"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GenerateReplyResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
 }
 
-func (x *GenerateReplyChunk) Reset() {
-	*x = GenerateReplyChunk{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GenerateReplyResponse) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
 }
 
-func (x *GenerateReplyChunk) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GenerateReplyResponse) GetDowngradeReason() string {
+	if x != nil {
+		return x.DowngradeReason
+	}
+	return ""
 }
 
-func (*GenerateReplyChunk) ProtoMessage() {}
-
-func (x *GenerateReplyChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[2]
+func (x *GenerateReplyResponse) GetOriginalModel() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.OriginalModel
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use GenerateReplyChunk.ProtoReflect.Descriptor instead.
-func (*GenerateReplyChunk) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{2}
+func (x *GenerateReplyResponse) GetHedged() bool {
+	if x != nil {
+		return x.Hedged
+	}
+	return false
 }
 
-func (x *GenerateReplyChunk) GetChunk() isGenerateReplyChunk_Chunk {
+func (x *GenerateReplyResponse) GetSafetyBlock() *SafetyBlock {
 	if x != nil {
-		return x.Chunk
+		return x.SafetyBlock
 	}
 	return nil
 }
 
-func (x *GenerateReplyChunk) GetTextDelta() *TextDelta {
+func (x *GenerateReplyResponse) GetComputerActions() []*ComputerAction {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_TextDelta); ok {
-			return x.TextDelta
-		}
+		return x.ComputerActions
 	}
 	return nil
 }
 
-func (x *GenerateReplyChunk) GetUsageUpdate() *UsageUpdate {
+func (x *GenerateReplyResponse) GetFailoverAttempts() []*FailoverAttempt {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_UsageUpdate); ok {
-			return x.UsageUpdate
-		}
+		return x.FailoverAttempts
 	}
 	return nil
 }
 
-func (x *GenerateReplyChunk) GetCitationUpdate() *CitationUpdate {
+func (x *GenerateReplyResponse) GetJudge() *JudgeResult {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_CitationUpdate); ok {
-			return x.CitationUpdate
-		}
+		return x.Judge
 	}
 	return nil
 }
 
-func (x *GenerateReplyChunk) GetComplete() *StreamComplete {
+func (x *GenerateReplyResponse) GetGrounding() *GroundingCheck {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_Complete); ok {
-			return x.Complete
-		}
+		return x.Grounding
 	}
 	return nil
 }
 
-func (x *GenerateReplyChunk) GetError() *StreamError {
+func (x *GenerateReplyResponse) GetDeduplicated() bool {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_Error); ok {
-			return x.Error
-		}
+		return x.Deduplicated
 	}
-	return nil
+	return false
 }
 
-func (x *GenerateReplyChunk) GetToolCallUpdate() *ToolCallUpdate {
+func (x *GenerateReplyResponse) GetAttribution() *Attribution {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_ToolCallUpdate); ok {
-			return x.ToolCallUpdate
-		}
+		return x.Attribution
 	}
 	return nil
 }
 
-func (x *GenerateReplyChunk) GetCodeExecutionUpdate() *CodeExecutionUpdate {
+func (x *GenerateReplyResponse) GetFootnotedMarkdown() string {
 	if x != nil {
-		if x, ok := x.Chunk.(*GenerateReplyChunk_CodeExecutionUpdate); ok {
-			return x.CodeExecutionUpdate
-		}
+		return x.FootnotedMarkdown
 	}
-	return nil
+	return ""
 }
 
-type isGenerateReplyChunk_Chunk interface {
-	isGenerateReplyChunk_Chunk()
+func (x *GenerateReplyResponse) GetModelTier() string {
+	if x != nil {
+		return x.ModelTier
+	}
+	return ""
 }
 
-type GenerateReplyChunk_TextDelta struct {
-	TextDelta *TextDelta `protobuf:"bytes,1,opt,name=text_delta,json=textDelta,proto3,oneof"`
+func (x *GenerateReplyResponse) GetEscalationReason() string {
+	if x != nil {
+		return x.EscalationReason
+	}
+	return ""
 }
 
-type GenerateReplyChunk_UsageUpdate struct {
-	UsageUpdate *UsageUpdate `protobuf:"bytes,2,opt,name=usage_update,json=usageUpdate,proto3,oneof"`
+// FailoverAttempt records one provider tried during failover
+type FailoverAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      Provider               `protobuf:"varint,1,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // Sanitized provider error
+	DurationMs    int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type GenerateReplyChunk_CitationUpdate struct {
-	CitationUpdate *CitationUpdate `protobuf:"bytes,3,opt,name=citation_update,json=citationUpdate,proto3,oneof"`
+func (x *FailoverAttempt) Reset() {
+	*x = FailoverAttempt{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type GenerateReplyChunk_Complete struct {
-	Complete *StreamComplete `protobuf:"bytes,4,opt,name=complete,proto3,oneof"`
+func (x *FailoverAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type GenerateReplyChunk_Error struct {
-	Error *StreamError `protobuf:"bytes,5,opt,name=error,proto3,oneof"`
-}
+func (*FailoverAttempt) ProtoMessage() {}
 
-type GenerateReplyChunk_ToolCallUpdate struct {
-	ToolCallUpdate *ToolCallUpdate `protobuf:"bytes,6,opt,name=tool_call_update,json=toolCallUpdate,proto3,oneof"`
+func (x *FailoverAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type GenerateReplyChunk_CodeExecutionUpdate struct {
-	CodeExecutionUpdate *CodeExecutionUpdate `protobuf:"bytes,7,opt,name=code_execution_update,json=codeExecutionUpdate,proto3,oneof"`
+// Deprecated: Use FailoverAttempt.ProtoReflect.Descriptor instead.
+func (*FailoverAttempt) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{2}
 }
 
-func (*GenerateReplyChunk_TextDelta) isGenerateReplyChunk_Chunk() {}
-
-func (*GenerateReplyChunk_UsageUpdate) isGenerateReplyChunk_Chunk() {}
-
-func (*GenerateReplyChunk_CitationUpdate) isGenerateReplyChunk_Chunk() {}
-
-func (*GenerateReplyChunk_Complete) isGenerateReplyChunk_Chunk() {}
+func (x *FailoverAttempt) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
 
-func (*GenerateReplyChunk_Error) isGenerateReplyChunk_Chunk() {}
+func (x *FailoverAttempt) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
 
-func (*GenerateReplyChunk_ToolCallUpdate) isGenerateReplyChunk_Chunk() {}
+func (x *FailoverAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
 
-func (*GenerateReplyChunk_CodeExecutionUpdate) isGenerateReplyChunk_Chunk() {}
+func (x *FailoverAttempt) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
 
-// ToolCallUpdate signals a tool call during streaming
-type ToolCallUpdate struct {
+// JudgeResult reports how a judge model scored the candidate replies
+type JudgeResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ToolCall      *ToolCall              `protobuf:"bytes,1,opt,name=tool_call,json=toolCall,proto3" json:"tool_call,omitempty"`
+	UseCase       string                 `protobuf:"bytes,1,opt,name=use_case,json=useCase,proto3" json:"use_case,omitempty"` // Judge policy applied ("*" for the tenant default)
+	Winner        int32                  `protobuf:"varint,2,opt,name=winner,proto3" json:"winner,omitempty"`                 // Index in candidates of the returned reply
+	Candidates    []*JudgeCandidate      `protobuf:"bytes,3,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	JudgeProvider Provider               `protobuf:"varint,4,opt,name=judge_provider,json=judgeProvider,proto3,enum=airborne.v1.Provider" json:"judge_provider,omitempty"`
+	JudgeModel    string                 `protobuf:"bytes,5,opt,name=judge_model,json=judgeModel,proto3" json:"judge_model,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ToolCallUpdate) Reset() {
-	*x = ToolCallUpdate{}
+func (x *JudgeResult) Reset() {
+	*x = JudgeResult{}
 	mi := &file_airborne_v1_airborne_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ToolCallUpdate) String() string {
+func (x *JudgeResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ToolCallUpdate) ProtoMessage() {}
+func (*JudgeResult) ProtoMessage() {}
 
-func (x *ToolCallUpdate) ProtoReflect() protoreflect.Message {
+func (x *JudgeResult) ProtoReflect() protoreflect.Message {
 	mi := &file_airborne_v1_airborne_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -618,41 +839,2164 @@ func (x *ToolCallUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ToolCallUpdate.ProtoReflect.Descriptor instead.
-func (*ToolCallUpdate) Descriptor() ([]byte, []int) {
+// Deprecated: Use JudgeResult.ProtoReflect.Descriptor instead.
+func (*JudgeResult) Descriptor() ([]byte, []int) {
 	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ToolCallUpdate) GetToolCall() *ToolCall {
+func (x *JudgeResult) GetUseCase() string {
 	if x != nil {
-		return x.ToolCall
+		return x.UseCase
 	}
-	return nil
+	return ""
 }
 
-// CodeExecutionUpdate signals code execution during streaming
-type CodeExecutionUpdate struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Execution     *CodeExecutionResult   `protobuf:"bytes,1,opt,name=execution,proto3" json:"execution,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *JudgeResult) GetWinner() int32 {
+	if x != nil {
+		return x.Winner
+	}
+	return 0
 }
 
-func (x *CodeExecutionUpdate) Reset() {
-	*x = CodeExecutionUpdate{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *JudgeResult) GetCandidates() []*JudgeCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *JudgeResult) GetJudgeProvider() Provider {
+	if x != nil {
+		return x.JudgeProvider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *JudgeResult) GetJudgeModel() string {
+	if x != nil {
+		return x.JudgeModel
+	}
+	return ""
+}
+
+// JudgeCandidate is one scored candidate reply
+type JudgeCandidate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"` // 0-10 against the policy's criteria
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // The judge's explanation
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`   // Model that generated the candidate
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JudgeCandidate) Reset() {
+	*x = JudgeCandidate{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JudgeCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JudgeCandidate) ProtoMessage() {}
+
+func (x *JudgeCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JudgeCandidate.ProtoReflect.Descriptor instead.
+func (*JudgeCandidate) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JudgeCandidate) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *JudgeCandidate) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *JudgeCandidate) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// GroundingCheck reports how well the retrieved chunks support a reply
+type GroundingCheck struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Score             float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`         // 0 (unsupported) to 1 (fully supported)
+	Threshold         float64                `protobuf:"fixed64,2,opt,name=threshold,proto3" json:"threshold,omitempty"` // The tenant's minimum score
+	Grounded          bool                   `protobuf:"varint,3,opt,name=grounded,proto3" json:"grounded,omitempty"`    // score >= threshold
+	Action            string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`         // "disclaimer" or "fallback" when not grounded
+	Reason            string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`         // The verifier's explanation
+	UnsupportedClaims []string               `protobuf:"bytes,6,rep,name=unsupported_claims,json=unsupportedClaims,proto3" json:"unsupported_claims,omitempty"`
+	VerifierProvider  Provider               `protobuf:"varint,7,opt,name=verifier_provider,json=verifierProvider,proto3,enum=airborne.v1.Provider" json:"verifier_provider,omitempty"`
+	VerifierModel     string                 `protobuf:"bytes,8,opt,name=verifier_model,json=verifierModel,proto3" json:"verifier_model,omitempty"`
+	// The fallback answer in streams, where the reply was already sent:
+	// clients should show it in place of the streamed text
+	FallbackText  string `protobuf:"bytes,9,opt,name=fallback_text,json=fallbackText,proto3" json:"fallback_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroundingCheck) Reset() {
+	*x = GroundingCheck{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroundingCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroundingCheck) ProtoMessage() {}
+
+func (x *GroundingCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroundingCheck.ProtoReflect.Descriptor instead.
+func (*GroundingCheck) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GroundingCheck) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *GroundingCheck) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *GroundingCheck) GetGrounded() bool {
+	if x != nil {
+		return x.Grounded
+	}
+	return false
+}
+
+func (x *GroundingCheck) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *GroundingCheck) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *GroundingCheck) GetUnsupportedClaims() []string {
+	if x != nil {
+		return x.UnsupportedClaims
+	}
+	return nil
+}
+
+func (x *GroundingCheck) GetVerifierProvider() Provider {
+	if x != nil {
+		return x.VerifierProvider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *GroundingCheck) GetVerifierModel() string {
+	if x != nil {
+		return x.VerifierModel
+	}
+	return ""
+}
+
+func (x *GroundingCheck) GetFallbackText() string {
+	if x != nil {
+		return x.FallbackText
+	}
+	return ""
+}
+
+// Attribution records which configuration generated a reply. The signature
+// is the hex HMAC-SHA256, with the tenant's attribution signing key, of
+// "airborne-attribution-v1\n" followed by model, provider name (e.g.
+// "openai"), generated_at and request_hash, each ending in "\n".
+type Attribution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Provider      Provider               `protobuf:"varint,2,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	GeneratedAt   string                 `protobuf:"bytes,3,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"` // RFC 3339
+	RequestHash   string                 `protobuf:"bytes,4,opt,name=request_hash,json=requestHash,proto3" json:"request_hash,omitempty"` // Hex SHA-256 of the deterministically serialized request, without request_id and idempotent
+	Signature     string                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`                        // Empty without a signing key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attribution) Reset() {
+	*x = Attribution{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attribution) ProtoMessage() {}
+
+func (x *Attribution) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attribution.ProtoReflect.Descriptor instead.
+func (*Attribution) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Attribution) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Attribution) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *Attribution) GetGeneratedAt() string {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return ""
+}
+
+func (x *Attribution) GetRequestHash() string {
+	if x != nil {
+		return x.RequestHash
+	}
+	return ""
+}
+
+func (x *Attribution) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+// GenerateReplyChunk is a streaming response chunk
+type GenerateReplyChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Chunk:
+	//
+	//	*GenerateReplyChunk_TextDelta
+	//	*GenerateReplyChunk_UsageUpdate
+	//	*GenerateReplyChunk_CitationUpdate
+	//	*GenerateReplyChunk_Complete
+	//	*GenerateReplyChunk_Error
+	//	*GenerateReplyChunk_ToolCallUpdate
+	//	*GenerateReplyChunk_CodeExecutionUpdate
+	//	*GenerateReplyChunk_ComputerActionUpdate
+	Chunk         isGenerateReplyChunk_Chunk `protobuf_oneof:"chunk"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateReplyChunk) Reset() {
+	*x = GenerateReplyChunk{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateReplyChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateReplyChunk) ProtoMessage() {}
+
+func (x *GenerateReplyChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateReplyChunk.ProtoReflect.Descriptor instead.
+func (*GenerateReplyChunk) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GenerateReplyChunk) GetChunk() isGenerateReplyChunk_Chunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetTextDelta() *TextDelta {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_TextDelta); ok {
+			return x.TextDelta
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetUsageUpdate() *UsageUpdate {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_UsageUpdate); ok {
+			return x.UsageUpdate
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetCitationUpdate() *CitationUpdate {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_CitationUpdate); ok {
+			return x.CitationUpdate
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetComplete() *StreamComplete {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_Complete); ok {
+			return x.Complete
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetError() *StreamError {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetToolCallUpdate() *ToolCallUpdate {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_ToolCallUpdate); ok {
+			return x.ToolCallUpdate
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetCodeExecutionUpdate() *CodeExecutionUpdate {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_CodeExecutionUpdate); ok {
+			return x.CodeExecutionUpdate
+		}
+	}
+	return nil
+}
+
+func (x *GenerateReplyChunk) GetComputerActionUpdate() *ComputerActionUpdate {
+	if x != nil {
+		if x, ok := x.Chunk.(*GenerateReplyChunk_ComputerActionUpdate); ok {
+			return x.ComputerActionUpdate
+		}
+	}
+	return nil
+}
+
+type isGenerateReplyChunk_Chunk interface {
+	isGenerateReplyChunk_Chunk()
+}
+
+type GenerateReplyChunk_TextDelta struct {
+	TextDelta *TextDelta `protobuf:"bytes,1,opt,name=text_delta,json=textDelta,proto3,oneof"`
+}
+
+type GenerateReplyChunk_UsageUpdate struct {
+	UsageUpdate *UsageUpdate `protobuf:"bytes,2,opt,name=usage_update,json=usageUpdate,proto3,oneof"`
+}
+
+type GenerateReplyChunk_CitationUpdate struct {
+	CitationUpdate *CitationUpdate `protobuf:"bytes,3,opt,name=citation_update,json=citationUpdate,proto3,oneof"`
+}
+
+type GenerateReplyChunk_Complete struct {
+	Complete *StreamComplete `protobuf:"bytes,4,opt,name=complete,proto3,oneof"`
+}
+
+type GenerateReplyChunk_Error struct {
+	Error *StreamError `protobuf:"bytes,5,opt,name=error,proto3,oneof"`
+}
+
+type GenerateReplyChunk_ToolCallUpdate struct {
+	ToolCallUpdate *ToolCallUpdate `protobuf:"bytes,6,opt,name=tool_call_update,json=toolCallUpdate,proto3,oneof"`
+}
+
+type GenerateReplyChunk_CodeExecutionUpdate struct {
+	CodeExecutionUpdate *CodeExecutionUpdate `protobuf:"bytes,7,opt,name=code_execution_update,json=codeExecutionUpdate,proto3,oneof"`
+}
+
+type GenerateReplyChunk_ComputerActionUpdate struct {
+	ComputerActionUpdate *ComputerActionUpdate `protobuf:"bytes,8,opt,name=computer_action_update,json=computerActionUpdate,proto3,oneof"`
+}
+
+func (*GenerateReplyChunk_TextDelta) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_UsageUpdate) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_CitationUpdate) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_Complete) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_Error) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_ToolCallUpdate) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_CodeExecutionUpdate) isGenerateReplyChunk_Chunk() {}
+
+func (*GenerateReplyChunk_ComputerActionUpdate) isGenerateReplyChunk_Chunk() {}
+
+// ToolCallUpdate signals a tool call during streaming
+type ToolCallUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ToolCall      *ToolCall              `protobuf:"bytes,1,opt,name=tool_call,json=toolCall,proto3" json:"tool_call,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolCallUpdate) Reset() {
+	*x = ToolCallUpdate{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolCallUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolCallUpdate) ProtoMessage() {}
+
+func (x *ToolCallUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolCallUpdate.ProtoReflect.Descriptor instead.
+func (*ToolCallUpdate) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ToolCallUpdate) GetToolCall() *ToolCall {
+	if x != nil {
+		return x.ToolCall
+	}
+	return nil
+}
+
+// ComputerActionUpdate signals a computer-use action during streaming
+type ComputerActionUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        *ComputerAction        `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputerActionUpdate) Reset() {
+	*x = ComputerActionUpdate{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputerActionUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputerActionUpdate) ProtoMessage() {}
+
+func (x *ComputerActionUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputerActionUpdate.ProtoReflect.Descriptor instead.
+func (*ComputerActionUpdate) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ComputerActionUpdate) GetAction() *ComputerAction {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+// CodeExecutionUpdate signals code execution during streaming
+type CodeExecutionUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Execution     *CodeExecutionResult   `protobuf:"bytes,1,opt,name=execution,proto3" json:"execution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CodeExecutionUpdate) Reset() {
+	*x = CodeExecutionUpdate{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeExecutionUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeExecutionUpdate) ProtoMessage() {}
+
+func (x *CodeExecutionUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeExecutionUpdate.ProtoReflect.Descriptor instead.
+func (*CodeExecutionUpdate) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CodeExecutionUpdate) GetExecution() *CodeExecutionResult {
+	if x != nil {
+		return x.Execution
+	}
+	return nil
+}
+
+// TextDelta contains incremental text
+type TextDelta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"` // Position in the full response
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextDelta) Reset() {
+	*x = TextDelta{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextDelta) ProtoMessage() {}
+
+func (x *TextDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextDelta.ProtoReflect.Descriptor instead.
+func (*TextDelta) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TextDelta) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TextDelta) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+// UsageUpdate provides intermediate token counts while a reply streams
+type UsageUpdate struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Usage            *Usage                 `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	EstimatedCostUsd float64                `protobuf:"fixed64,2,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"` // Cost of the usage so far
+	Estimated        bool                   `protobuf:"varint,3,opt,name=estimated,proto3" json:"estimated,omitempty"`                                          // Token counts are approximated from the streamed text
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UsageUpdate) Reset() {
+	*x = UsageUpdate{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageUpdate) ProtoMessage() {}
+
+func (x *UsageUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageUpdate.ProtoReflect.Descriptor instead.
+func (*UsageUpdate) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UsageUpdate) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *UsageUpdate) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
+}
+
+func (x *UsageUpdate) GetEstimated() bool {
+	if x != nil {
+		return x.Estimated
+	}
+	return false
+}
+
+// CitationUpdate adds a citation during streaming
+type CitationUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Citation      *Citation              `protobuf:"bytes,1,opt,name=citation,proto3" json:"citation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CitationUpdate) Reset() {
+	*x = CitationUpdate{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CitationUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CitationUpdate) ProtoMessage() {}
+
+func (x *CitationUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CitationUpdate.ProtoReflect.Descriptor instead.
+func (*CitationUpdate) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CitationUpdate) GetCitation() *Citation {
+	if x != nil {
+		return x.Citation
+	}
+	return nil
+}
+
+// StreamComplete signals successful stream completion
+type StreamComplete struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ResponseId         string                 `protobuf:"bytes,1,opt,name=response_id,json=responseId,proto3" json:"response_id,omitempty"`
+	Model              string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Provider           Provider               `protobuf:"varint,3,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	FinalUsage         *Usage                 `protobuf:"bytes,4,opt,name=final_usage,json=finalUsage,proto3" json:"final_usage,omitempty"`
+	Citations          []*Citation            `protobuf:"bytes,5,rep,name=citations,proto3" json:"citations,omitempty"`
+	ToolCalls          []*ToolCall            `protobuf:"bytes,6,rep,name=tool_calls,json=toolCalls,proto3" json:"tool_calls,omitempty"`
+	RequiresToolOutput bool                   `protobuf:"varint,7,opt,name=requires_tool_output,json=requiresToolOutput,proto3" json:"requires_tool_output,omitempty"`
+	CodeExecutions     []*CodeExecutionResult `protobuf:"bytes,8,rep,name=code_executions,json=codeExecutions,proto3" json:"code_executions,omitempty"`
+	Images             []*GeneratedImage      `protobuf:"bytes,9,rep,name=images,proto3" json:"images,omitempty"`
+	HtmlContent        string                 `protobuf:"bytes,10,opt,name=html_content,json=htmlContent,proto3" json:"html_content,omitempty"`                      // HTML-rendered content (if markdown_svc is enabled)
+	StructuredMetadata *StructuredMetadata    `protobuf:"bytes,11,opt,name=structured_metadata,json=structuredMetadata,proto3" json:"structured_metadata,omitempty"` // Structured metadata (when enable_structured_output is true)
+	EstimatedCostUsd   float64                `protobuf:"fixed64,12,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`   // Estimated total cost in USD (see GenerateReplyResponse)
+	DowngradeReason    string                 `protobuf:"bytes,13,opt,name=downgrade_reason,json=downgradeReason,proto3" json:"downgrade_reason,omitempty"`          // Budget downgrade reason (see GenerateReplyResponse)
+	OriginalModel      string                 `protobuf:"bytes,14,opt,name=original_model,json=originalModel,proto3" json:"original_model,omitempty"`                // Model replaced by the budget downgrade
+	Hedged             bool                   `protobuf:"varint,15,opt,name=hedged,proto3" json:"hedged,omitempty"`                                                  // A hedge request was sent (see GenerateReplyResponse)
+	SafetyBlock        *SafetyBlock           `protobuf:"bytes,16,opt,name=safety_block,json=safetyBlock,proto3" json:"safety_block,omitempty"`                      // Safety filters blocked the prompt or response
+	ComputerActions    []*ComputerAction      `protobuf:"bytes,17,rep,name=computer_actions,json=computerActions,proto3" json:"computer_actions,omitempty"`          // Computer-use actions to perform
+	Grounding          *GroundingCheck        `protobuf:"bytes,18,opt,name=grounding,proto3" json:"grounding,omitempty"`                                             // Grounding check of the streamed reply (see GenerateReplyResponse)
+	Attribution        *Attribution           `protobuf:"bytes,19,opt,name=attribution,proto3" json:"attribution,omitempty"`                                         // Attribution metadata (see GenerateReplyResponse)
+	FootnotedMarkdown  string                 `protobuf:"bytes,20,opt,name=footnoted_markdown,json=footnotedMarkdown,proto3" json:"footnoted_markdown,omitempty"`    // Text with citation footnotes (see GenerateReplyResponse)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *StreamComplete) Reset() {
+	*x = StreamComplete{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamComplete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamComplete) ProtoMessage() {}
+
+func (x *StreamComplete) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamComplete.ProtoReflect.Descriptor instead.
+func (*StreamComplete) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StreamComplete) GetResponseId() string {
+	if x != nil {
+		return x.ResponseId
+	}
+	return ""
+}
+
+func (x *StreamComplete) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *StreamComplete) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *StreamComplete) GetFinalUsage() *Usage {
+	if x != nil {
+		return x.FinalUsage
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetCitations() []*Citation {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetToolCalls() []*ToolCall {
+	if x != nil {
+		return x.ToolCalls
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetRequiresToolOutput() bool {
+	if x != nil {
+		return x.RequiresToolOutput
+	}
+	return false
+}
+
+func (x *StreamComplete) GetCodeExecutions() []*CodeExecutionResult {
+	if x != nil {
+		return x.CodeExecutions
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetImages() []*GeneratedImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetHtmlContent() string {
+	if x != nil {
+		return x.HtmlContent
+	}
+	return ""
+}
+
+func (x *StreamComplete) GetStructuredMetadata() *StructuredMetadata {
+	if x != nil {
+		return x.StructuredMetadata
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
+}
+
+func (x *StreamComplete) GetDowngradeReason() string {
+	if x != nil {
+		return x.DowngradeReason
+	}
+	return ""
+}
+
+func (x *StreamComplete) GetOriginalModel() string {
+	if x != nil {
+		return x.OriginalModel
+	}
+	return ""
+}
+
+func (x *StreamComplete) GetHedged() bool {
+	if x != nil {
+		return x.Hedged
+	}
+	return false
+}
+
+func (x *StreamComplete) GetSafetyBlock() *SafetyBlock {
+	if x != nil {
+		return x.SafetyBlock
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetComputerActions() []*ComputerAction {
+	if x != nil {
+		return x.ComputerActions
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetGrounding() *GroundingCheck {
+	if x != nil {
+		return x.Grounding
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+func (x *StreamComplete) GetFootnotedMarkdown() string {
+	if x != nil {
+		return x.FootnotedMarkdown
+	}
+	return ""
+}
+
+// StreamError signals an error during streaming
+type StreamError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Retryable     bool                   `protobuf:"varint,3,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamError) Reset() {
+	*x = StreamError{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamError) ProtoMessage() {}
+
+func (x *StreamError) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamError.ProtoReflect.Descriptor instead.
+func (*StreamError) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StreamError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *StreamError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *StreamError) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+// GeneratedImage represents an AI-generated image
+type GeneratedImage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`                            // Raw image bytes (JPEG/PNG)
+	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`    // MIME type (e.g., "image/jpeg")
+	Prompt        string                 `protobuf:"bytes,3,opt,name=prompt,proto3" json:"prompt,omitempty"`                        // The prompt used to generate the image
+	AltText       string                 `protobuf:"bytes,4,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`       // Accessibility description
+	Width         int32                  `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`                         // Width in pixels
+	Height        int32                  `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`                       // Height in pixels
+	ContentId     string                 `protobuf:"bytes,7,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"` // Content-ID for email embedding (cid:xxx)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratedImage) Reset() {
+	*x = GeneratedImage{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratedImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratedImage) ProtoMessage() {}
+
+func (x *GeneratedImage) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratedImage.ProtoReflect.Descriptor instead.
+func (*GeneratedImage) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GeneratedImage) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GeneratedImage) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *GeneratedImage) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *GeneratedImage) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+func (x *GeneratedImage) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *GeneratedImage) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *GeneratedImage) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+// SelectProviderRequest asks which provider should handle a request
+type SelectProviderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId string `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Content  string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"` // The input content (for trigger phrase detection)
+	// Deprecated: set thread_id instead. Used only when thread_id is empty.
+	ExistingProvider string             `protobuf:"bytes,2,opt,name=existing_provider,json=existingProvider,proto3" json:"existing_provider,omitempty"`
+	UserTier         string             `protobuf:"bytes,3,opt,name=user_tier,json=userTier,proto3" json:"user_tier,omitempty"` // User tier for tier-based routing
+	Triggers         []*ProviderTrigger `protobuf:"bytes,4,rep,name=triggers,proto3" json:"triggers,omitempty"`                 // Custom trigger phrases
+	// Thread the content continues, i.e. the request_id of its GenerateReply
+	// turns. Without a matching trigger, the thread's pinned provider and
+	// model are selected.
+	ThreadId string `protobuf:"bytes,6,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// Client the thread belongs to, as client_id in GenerateReply; ignored
+	// for authenticated clients
+	ClientId      string `protobuf:"bytes,7,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectProviderRequest) Reset() {
+	*x = SelectProviderRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectProviderRequest) ProtoMessage() {}
+
+func (x *SelectProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectProviderRequest.ProtoReflect.Descriptor instead.
+func (*SelectProviderRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SelectProviderRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SelectProviderRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SelectProviderRequest) GetExistingProvider() string {
+	if x != nil {
+		return x.ExistingProvider
+	}
+	return ""
+}
+
+func (x *SelectProviderRequest) GetUserTier() string {
+	if x != nil {
+		return x.UserTier
+	}
+	return ""
+}
+
+func (x *SelectProviderRequest) GetTriggers() []*ProviderTrigger {
+	if x != nil {
+		return x.Triggers
+	}
+	return nil
+}
+
+func (x *SelectProviderRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *SelectProviderRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+// ProviderTrigger defines a phrase that triggers a specific provider
+type ProviderTrigger struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phrase        string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`                                // Trigger phrase to match
+	Provider      Provider               `protobuf:"varint,2,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"` // Provider to use when matched
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`                                  // Optional model override
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderTrigger) Reset() {
+	*x = ProviderTrigger{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderTrigger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderTrigger) ProtoMessage() {}
+
+func (x *ProviderTrigger) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderTrigger.ProtoReflect.Descriptor instead.
+func (*ProviderTrigger) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ProviderTrigger) GetPhrase() string {
+	if x != nil {
+		return x.Phrase
+	}
+	return ""
+}
+
+func (x *ProviderTrigger) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *ProviderTrigger) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// SelectProviderResponse contains the provider selection result
+type SelectProviderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      Provider               `protobuf:"varint,1,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	ModelOverride string                 `protobuf:"bytes,2,opt,name=model_override,json=modelOverride,proto3" json:"model_override,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // "trigger", "tier", "continuity", "default"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectProviderResponse) Reset() {
+	*x = SelectProviderResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectProviderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectProviderResponse) ProtoMessage() {}
+
+func (x *SelectProviderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectProviderResponse.ProtoReflect.Descriptor instead.
+func (*SelectProviderResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SelectProviderResponse) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *SelectProviderResponse) GetModelOverride() string {
+	if x != nil {
+		return x.ModelOverride
+	}
+	return ""
+}
+
+func (x *SelectProviderResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// GetCapabilitiesRequest asks which providers and features are available
+type GetCapabilitiesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetCapabilitiesRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// GetCapabilitiesResponse lists enabled providers and their features
+type GetCapabilitiesResponse struct {
+	state           protoimpl.MessageState  `protogen:"open.v1"`
+	DefaultProvider Provider                `protobuf:"varint,1,opt,name=default_provider,json=defaultProvider,proto3,enum=airborne.v1.Provider" json:"default_provider,omitempty"` // Provider used when preferred_provider is unspecified
+	Providers       []*ProviderCapabilities `protobuf:"bytes,2,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetCapabilitiesResponse) GetDefaultProvider() Provider {
+	if x != nil {
+		return x.DefaultProvider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *GetCapabilitiesResponse) GetProviders() []*ProviderCapabilities {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+// ProviderCapabilities describes what a provider supports for the tenant
+type ProviderCapabilities struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Provider                 Provider               `protobuf:"varint,1,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	Model                    string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"` // Model used by default (tenant config or provider default)
+	SupportsFileSearch       bool                   `protobuf:"varint,3,opt,name=supports_file_search,json=supportsFileSearch,proto3" json:"supports_file_search,omitempty"`
+	SupportsWebSearch        bool                   `protobuf:"varint,4,opt,name=supports_web_search,json=supportsWebSearch,proto3" json:"supports_web_search,omitempty"`
+	SupportsCodeExecution    bool                   `protobuf:"varint,5,opt,name=supports_code_execution,json=supportsCodeExecution,proto3" json:"supports_code_execution,omitempty"`
+	SupportsStreaming        bool                   `protobuf:"varint,6,opt,name=supports_streaming,json=supportsStreaming,proto3" json:"supports_streaming,omitempty"`
+	SupportsNativeContinuity bool                   `protobuf:"varint,7,opt,name=supports_native_continuity,json=supportsNativeContinuity,proto3" json:"supports_native_continuity,omitempty"` // previous_response_id continuity
+	SupportsStructuredOutput bool                   `protobuf:"varint,8,opt,name=supports_structured_output,json=supportsStructuredOutput,proto3" json:"supports_structured_output,omitempty"` // enable_structured_output
+	MaxContextTokens         int32                  `protobuf:"varint,9,opt,name=max_context_tokens,json=maxContextTokens,proto3" json:"max_context_tokens,omitempty"`                         // Context window of model (0 if unknown)
+	SupportsComputerUse      bool                   `protobuf:"varint,10,opt,name=supports_computer_use,json=supportsComputerUse,proto3" json:"supports_computer_use,omitempty"`               // computer_use
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ProviderCapabilities) Reset() {
+	*x = ProviderCapabilities{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderCapabilities) ProtoMessage() {}
+
+func (x *ProviderCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderCapabilities.ProtoReflect.Descriptor instead.
+func (*ProviderCapabilities) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ProviderCapabilities) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *ProviderCapabilities) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ProviderCapabilities) GetSupportsFileSearch() bool {
+	if x != nil {
+		return x.SupportsFileSearch
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetSupportsWebSearch() bool {
+	if x != nil {
+		return x.SupportsWebSearch
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetSupportsCodeExecution() bool {
+	if x != nil {
+		return x.SupportsCodeExecution
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetSupportsStreaming() bool {
+	if x != nil {
+		return x.SupportsStreaming
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetSupportsNativeContinuity() bool {
+	if x != nil {
+		return x.SupportsNativeContinuity
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetSupportsStructuredOutput() bool {
+	if x != nil {
+		return x.SupportsStructuredOutput
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetMaxContextTokens() int32 {
+	if x != nil {
+		return x.MaxContextTokens
+	}
+	return 0
+}
+
+func (x *ProviderCapabilities) GetSupportsComputerUse() bool {
+	if x != nil {
+		return x.SupportsComputerUse
+	}
+	return false
+}
+
+// SummarizeDocumentRequest asks for a summary of an uploaded or stored document
+type SummarizeDocumentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Document to summarize: either its content or a file in an internal store
+	//
+	// Types that are valid to be assigned to Source:
+	//
+	//	*SummarizeDocumentRequest_Content
+	//	*SummarizeDocumentRequest_StoredFile
+	Source            isSummarizeDocumentRequest_Source `protobuf_oneof:"source"`
+	Filename          string                            `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`                                                                       // Filename of content (used for text extraction)
+	MimeType          string                            `protobuf:"bytes,5,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                                                       // MIME type of content (optional)
+	Instructions      string                            `protobuf:"bytes,6,opt,name=instructions,proto3" json:"instructions,omitempty"`                                                               // Optional focus for the summary, e.g. "for a legal reviewer"
+	PreferredProvider Provider                          `protobuf:"varint,7,opt,name=preferred_provider,json=preferredProvider,proto3,enum=airborne.v1.Provider" json:"preferred_provider,omitempty"` // Provider to use (default: tenant default)
+	ModelOverride     string                            `protobuf:"bytes,8,opt,name=model_override,json=modelOverride,proto3" json:"model_override,omitempty"`                                        // Override the tenant's model
+	MaxSections       int32                             `protobuf:"varint,9,opt,name=max_sections,json=maxSections,proto3" json:"max_sections,omitempty"`                                             // Maximum sections in the summary (default 8)
+	RequestId         string                            `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SummarizeDocumentRequest) Reset() {
+	*x = SummarizeDocumentRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummarizeDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeDocumentRequest) ProtoMessage() {}
+
+func (x *SummarizeDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeDocumentRequest.ProtoReflect.Descriptor instead.
+func (*SummarizeDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SummarizeDocumentRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentRequest) GetSource() isSummarizeDocumentRequest_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *SummarizeDocumentRequest) GetContent() []byte {
+	if x != nil {
+		if x, ok := x.Source.(*SummarizeDocumentRequest_Content); ok {
+			return x.Content
+		}
+	}
+	return nil
+}
+
+func (x *SummarizeDocumentRequest) GetStoredFile() *StoredFileRef {
+	if x != nil {
+		if x, ok := x.Source.(*SummarizeDocumentRequest_StoredFile); ok {
+			return x.StoredFile
+		}
+	}
+	return nil
+}
+
+func (x *SummarizeDocumentRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentRequest) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentRequest) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentRequest) GetPreferredProvider() Provider {
+	if x != nil {
+		return x.PreferredProvider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *SummarizeDocumentRequest) GetModelOverride() string {
+	if x != nil {
+		return x.ModelOverride
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentRequest) GetMaxSections() int32 {
+	if x != nil {
+		return x.MaxSections
+	}
+	return 0
+}
+
+func (x *SummarizeDocumentRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type isSummarizeDocumentRequest_Source interface {
+	isSummarizeDocumentRequest_Source()
+}
+
+type SummarizeDocumentRequest_Content struct {
+	Content []byte `protobuf:"bytes,2,opt,name=content,proto3,oneof"` // Raw file content
+}
+
+type SummarizeDocumentRequest_StoredFile struct {
+	StoredFile *StoredFileRef `protobuf:"bytes,3,opt,name=stored_file,json=storedFile,proto3,oneof"` // File previously uploaded to an internal (RAG) store
+}
+
+func (*SummarizeDocumentRequest_Content) isSummarizeDocumentRequest_Source() {}
+
+func (*SummarizeDocumentRequest_StoredFile) isSummarizeDocumentRequest_Source() {}
+
+// StoredFileRef identifies a file in an internal file store
+type StoredFileRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	FileId        string                 `protobuf:"bytes,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoredFileRef) Reset() {
+	*x = StoredFileRef{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoredFileRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredFileRef) ProtoMessage() {}
+
+func (x *StoredFileRef) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredFileRef.ProtoReflect.Descriptor instead.
+func (*StoredFileRef) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StoredFileRef) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *StoredFileRef) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+// SummarizeDocumentResponse contains the structured summary
+type SummarizeDocumentResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Title            string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`       // Document title as inferred by the model
+	Summary          string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`   // Overall summary
+	Sections         []*SummarySection      `protobuf:"bytes,3,rep,name=sections,proto3" json:"sections,omitempty"` // Section summaries with citations
+	Provider         Provider               `protobuf:"varint,4,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	Model            string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	Usage            *Usage                 `protobuf:"bytes,6,opt,name=usage,proto3" json:"usage,omitempty"` // Total usage across all map and reduce calls
+	EstimatedCostUsd float64                `protobuf:"fixed64,7,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`
+	PartCount        int32                  `protobuf:"varint,8,opt,name=part_count,json=partCount,proto3" json:"part_count,omitempty"` // Number of document parts summarized in the map step
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SummarizeDocumentResponse) Reset() {
+	*x = SummarizeDocumentResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummarizeDocumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeDocumentResponse) ProtoMessage() {}
+
+func (x *SummarizeDocumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeDocumentResponse.ProtoReflect.Descriptor instead.
+func (*SummarizeDocumentResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SummarizeDocumentResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentResponse) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentResponse) GetSections() []*SummarySection {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+func (x *SummarizeDocumentResponse) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *SummarizeDocumentResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *SummarizeDocumentResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *SummarizeDocumentResponse) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
+}
+
+func (x *SummarizeDocumentResponse) GetPartCount() int32 {
+	if x != nil {
+		return x.PartCount
+	}
+	return 0
+}
+
+// SummarySection summarizes one section of the document
+type SummarySection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Heading       string                 `protobuf:"bytes,1,opt,name=heading,proto3" json:"heading,omitempty"`
+	Summary       string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Citations     []*DocumentSpan        `protobuf:"bytes,3,rep,name=citations,proto3" json:"citations,omitempty"` // Parts of the document the section is drawn from
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SummarySection) Reset() {
+	*x = SummarySection{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummarySection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarySection) ProtoMessage() {}
+
+func (x *SummarySection) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarySection.ProtoReflect.Descriptor instead.
+func (*SummarySection) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SummarySection) GetHeading() string {
+	if x != nil {
+		return x.Heading
+	}
+	return ""
+}
+
+func (x *SummarySection) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *SummarySection) GetCitations() []*DocumentSpan {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+// DocumentSpan is a range of the source document
+type DocumentSpan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Part          int32                  `protobuf:"varint,1,opt,name=part,proto3" json:"part,omitempty"`                            // 1-based index of the document part
+	CharStart     int32                  `protobuf:"varint,2,opt,name=char_start,json=charStart,proto3" json:"char_start,omitempty"` // Character offset of the part in the extracted text
+	CharEnd       int32                  `protobuf:"varint,3,opt,name=char_end,json=charEnd,proto3" json:"char_end,omitempty"`
+	Snippet       string                 `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet,omitempty"` // Opening text of the part
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentSpan) Reset() {
+	*x = DocumentSpan{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentSpan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentSpan) ProtoMessage() {}
+
+func (x *DocumentSpan) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentSpan.ProtoReflect.Descriptor instead.
+func (*DocumentSpan) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DocumentSpan) GetPart() int32 {
+	if x != nil {
+		return x.Part
+	}
+	return 0
+}
+
+func (x *DocumentSpan) GetCharStart() int32 {
+	if x != nil {
+		return x.CharStart
+	}
+	return 0
+}
+
+func (x *DocumentSpan) GetCharEnd() int32 {
+	if x != nil {
+		return x.CharEnd
+	}
+	return 0
+}
+
+func (x *DocumentSpan) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+// EmbedRequest asks for embeddings of a batch of texts
+type EmbedRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId      string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Texts         []string `protobuf:"bytes,2,rep,name=texts,proto3" json:"texts,omitempty"` // Texts to embed (at most 512, 32KB each)
+	RequestId     string   `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedRequest) Reset() {
+	*x = EmbedRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedRequest) ProtoMessage() {}
+
+func (x *EmbedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedRequest.ProtoReflect.Descriptor instead.
+func (*EmbedRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EmbedRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *EmbedRequest) GetTexts() []string {
+	if x != nil {
+		return x.Texts
+	}
+	return nil
+}
+
+func (x *EmbedRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// EmbedResponse contains one embedding per input text, in input order
+type EmbedResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Embeddings       []*Embedding           `protobuf:"bytes,1,rep,name=embeddings,proto3" json:"embeddings,omitempty"`
+	Provider         string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"` // "openai", "gemini" or "ollama"
+	Model            string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Dimensions       int32                  `protobuf:"varint,4,opt,name=dimensions,proto3" json:"dimensions,omitempty"`
+	Usage            *Usage                 `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"` // Input tokens (estimated when the provider does not report them)
+	EstimatedCostUsd float64                `protobuf:"fixed64,6,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *EmbedResponse) Reset() {
+	*x = EmbedResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedResponse) ProtoMessage() {}
+
+func (x *EmbedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedResponse.ProtoReflect.Descriptor instead.
+func (*EmbedResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *EmbedResponse) GetEmbeddings() []*Embedding {
+	if x != nil {
+		return x.Embeddings
+	}
+	return nil
+}
+
+func (x *EmbedResponse) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *EmbedResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *EmbedResponse) GetDimensions() int32 {
+	if x != nil {
+		return x.Dimensions
+	}
+	return 0
 }
 
-func (x *CodeExecutionUpdate) String() string {
+func (x *EmbedResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *EmbedResponse) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
+}
+
+// Embedding is the vector for one input text
+type Embedding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float32              `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Embedding) Reset() {
+	*x = Embedding{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Embedding) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CodeExecutionUpdate) ProtoMessage() {}
+func (*Embedding) ProtoMessage() {}
 
-func (x *CodeExecutionUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[4]
+func (x *Embedding) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -663,42 +3007,123 @@ func (x *CodeExecutionUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CodeExecutionUpdate.ProtoReflect.Descriptor instead.
-func (*CodeExecutionUpdate) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Embedding.ProtoReflect.Descriptor instead.
+func (*Embedding) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *CodeExecutionUpdate) GetExecution() *CodeExecutionResult {
+func (x *Embedding) GetValues() []float32 {
 	if x != nil {
-		return x.Execution
+		return x.Values
 	}
 	return nil
 }
 
-// TextDelta contains incremental text
-type TextDelta struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
-	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"` // Position in the full response
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// AnalyzeTextRequest asks for structured metadata about a text
+type AnalyzeTextRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId          string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Text              string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`                                                                               // Text to analyze
+	PreferredProvider Provider `protobuf:"varint,3,opt,name=preferred_provider,json=preferredProvider,proto3,enum=airborne.v1.Provider" json:"preferred_provider,omitempty"` // Provider to use (default: tenant default)
+	ModelOverride     string   `protobuf:"bytes,4,opt,name=model_override,json=modelOverride,proto3" json:"model_override,omitempty"`                                        // Override the tenant's model
+	RequestId         string   `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AnalyzeTextRequest) Reset() {
+	*x = AnalyzeTextRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TextDelta) Reset() {
-	*x = TextDelta{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[5]
+func (x *AnalyzeTextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeTextRequest) ProtoMessage() {}
+
+func (x *AnalyzeTextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeTextRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeTextRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AnalyzeTextRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *AnalyzeTextRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *AnalyzeTextRequest) GetPreferredProvider() Provider {
+	if x != nil {
+		return x.PreferredProvider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *AnalyzeTextRequest) GetModelOverride() string {
+	if x != nil {
+		return x.ModelOverride
+	}
+	return ""
+}
+
+func (x *AnalyzeTextRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// AnalyzeTextResponse contains the extracted metadata
+type AnalyzeTextResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Metadata         *StructuredMetadata    `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Provider         Provider               `protobuf:"varint,2,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
+	Model            string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Usage            *Usage                 `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	EstimatedCostUsd float64                `protobuf:"fixed64,5,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AnalyzeTextResponse) Reset() {
+	*x = AnalyzeTextResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TextDelta) String() string {
+func (x *AnalyzeTextResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TextDelta) ProtoMessage() {}
+func (*AnalyzeTextResponse) ProtoMessage() {}
 
-func (x *TextDelta) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[5]
+func (x *AnalyzeTextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -709,48 +3134,84 @@ func (x *TextDelta) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TextDelta.ProtoReflect.Descriptor instead.
-func (*TextDelta) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use AnalyzeTextResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeTextResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *TextDelta) GetText() string {
+func (x *AnalyzeTextResponse) GetMetadata() *StructuredMetadata {
 	if x != nil {
-		return x.Text
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *AnalyzeTextResponse) GetProvider() Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return Provider_PROVIDER_UNSPECIFIED
+}
+
+func (x *AnalyzeTextResponse) GetModel() string {
+	if x != nil {
+		return x.Model
 	}
 	return ""
 }
 
-func (x *TextDelta) GetIndex() int32 {
+func (x *AnalyzeTextResponse) GetUsage() *Usage {
 	if x != nil {
-		return x.Index
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *AnalyzeTextResponse) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
 	}
 	return 0
 }
 
-// UsageUpdate provides intermediate token counts
-type UsageUpdate struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Usage         *Usage                 `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+// RegenerateMessageRequest edits a turn of a stored thread and regenerates the
+// reply. Threads are those recorded by GenerateReply, keyed by request_id.
+type RegenerateMessageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Message to regenerate from: a user message, or an assistant message to
+	// regenerate from the user message before it. Every later message on the
+	// thread's current branch is superseded, and the user message too when
+	// content replaces it. Without new content the user message is kept and
+	// the new reply is an alternative to its earlier replies.
+	MessageId string `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Replacement user input; empty keeps the original message's content
+	Content string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// Generation options, as for GenerateReply. user_input,
+	// conversation_history and request_id are taken from the thread;
+	// previous_response_id and tool_results are ignored. Empty instructions
+	// reuse those of the superseded reply.
+	Options       *GenerateReplyRequest `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UsageUpdate) Reset() {
-	*x = UsageUpdate{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[6]
+func (x *RegenerateMessageRequest) Reset() {
+	*x = RegenerateMessageRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UsageUpdate) String() string {
+func (x *RegenerateMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UsageUpdate) ProtoMessage() {}
+func (*RegenerateMessageRequest) ProtoMessage() {}
 
-func (x *UsageUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[6]
+func (x *RegenerateMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -761,41 +3222,65 @@ func (x *UsageUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UsageUpdate.ProtoReflect.Descriptor instead.
-func (*UsageUpdate) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RegenerateMessageRequest.ProtoReflect.Descriptor instead.
+func (*RegenerateMessageRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *UsageUpdate) GetUsage() *Usage {
+func (x *RegenerateMessageRequest) GetTenantId() string {
 	if x != nil {
-		return x.Usage
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *RegenerateMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *RegenerateMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *RegenerateMessageRequest) GetOptions() *GenerateReplyRequest {
+	if x != nil {
+		return x.Options
 	}
 	return nil
 }
 
-// CitationUpdate adds a citation during streaming
-type CitationUpdate struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Citation      *Citation              `protobuf:"bytes,1,opt,name=citation,proto3" json:"citation,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// RegenerateMessageResponse contains the new reply
+type RegenerateMessageResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Reply                *GenerateReplyResponse `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	ThreadId             string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	SupersededMessageIds []string               `protobuf:"bytes,3,rep,name=superseded_message_ids,json=supersededMessageIds,proto3" json:"superseded_message_ids,omitempty"` // Messages replaced by the new turn, in thread order
+	MessageId            string                 `protobuf:"bytes,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`                                    // ID the new reply is stored under; it is persisted asynchronously
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
-func (x *CitationUpdate) Reset() {
-	*x = CitationUpdate{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[7]
+func (x *RegenerateMessageResponse) Reset() {
+	*x = RegenerateMessageResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CitationUpdate) String() string {
+func (x *RegenerateMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CitationUpdate) ProtoMessage() {}
+func (*RegenerateMessageResponse) ProtoMessage() {}
 
-func (x *CitationUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[7]
+func (x *RegenerateMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -806,51 +3291,69 @@ func (x *CitationUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CitationUpdate.ProtoReflect.Descriptor instead.
-func (*CitationUpdate) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RegenerateMessageResponse.ProtoReflect.Descriptor instead.
+func (*RegenerateMessageResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *CitationUpdate) GetCitation() *Citation {
+func (x *RegenerateMessageResponse) GetReply() *GenerateReplyResponse {
 	if x != nil {
-		return x.Citation
+		return x.Reply
 	}
 	return nil
 }
 
-// StreamComplete signals successful stream completion
-type StreamComplete struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	ResponseId         string                 `protobuf:"bytes,1,opt,name=response_id,json=responseId,proto3" json:"response_id,omitempty"`
-	Model              string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
-	Provider           Provider               `protobuf:"varint,3,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
-	FinalUsage         *Usage                 `protobuf:"bytes,4,opt,name=final_usage,json=finalUsage,proto3" json:"final_usage,omitempty"`
-	Citations          []*Citation            `protobuf:"bytes,5,rep,name=citations,proto3" json:"citations,omitempty"`
-	ToolCalls          []*ToolCall            `protobuf:"bytes,6,rep,name=tool_calls,json=toolCalls,proto3" json:"tool_calls,omitempty"`
-	RequiresToolOutput bool                   `protobuf:"varint,7,opt,name=requires_tool_output,json=requiresToolOutput,proto3" json:"requires_tool_output,omitempty"`
-	CodeExecutions     []*CodeExecutionResult `protobuf:"bytes,8,rep,name=code_executions,json=codeExecutions,proto3" json:"code_executions,omitempty"`
-	Images             []*GeneratedImage      `protobuf:"bytes,9,rep,name=images,proto3" json:"images,omitempty"`
-	HtmlContent        string                 `protobuf:"bytes,10,opt,name=html_content,json=htmlContent,proto3" json:"html_content,omitempty"`                      // HTML-rendered content (if markdown_svc is enabled)
-	StructuredMetadata *StructuredMetadata    `protobuf:"bytes,11,opt,name=structured_metadata,json=structuredMetadata,proto3" json:"structured_metadata,omitempty"` // Structured metadata (when enable_structured_output is true)
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+func (x *RegenerateMessageResponse) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
 }
 
-func (x *StreamComplete) Reset() {
-	*x = StreamComplete{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[8]
+func (x *RegenerateMessageResponse) GetSupersededMessageIds() []string {
+	if x != nil {
+		return x.SupersededMessageIds
+	}
+	return nil
+}
+
+func (x *RegenerateMessageResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+// ListBranchesRequest selects a message of a stored thread
+type ListBranchesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// A user message lists its edits; an assistant message lists the replies
+	// regenerated for its user message
+	MessageId string `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Client the thread belongs to, as client_id in GenerateReply; ignored
+	// for authenticated clients
+	ClientId      string `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBranchesRequest) Reset() {
+	*x = ListBranchesRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamComplete) String() string {
+func (x *ListBranchesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamComplete) ProtoMessage() {}
+func (*ListBranchesRequest) ProtoMessage() {}
 
-func (x *StreamComplete) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[8]
+func (x *ListBranchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -861,113 +3364,150 @@ func (x *StreamComplete) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamComplete.ProtoReflect.Descriptor instead.
-func (*StreamComplete) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ListBranchesRequest.ProtoReflect.Descriptor instead.
+func (*ListBranchesRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *StreamComplete) GetResponseId() string {
+func (x *ListBranchesRequest) GetTenantId() string {
 	if x != nil {
-		return x.ResponseId
+		return x.TenantId
 	}
 	return ""
 }
 
-func (x *StreamComplete) GetModel() string {
+func (x *ListBranchesRequest) GetMessageId() string {
 	if x != nil {
-		return x.Model
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *StreamComplete) GetProvider() Provider {
+func (x *ListBranchesRequest) GetClientId() string {
 	if x != nil {
-		return x.Provider
+		return x.ClientId
 	}
-	return Provider_PROVIDER_UNSPECIFIED
+	return ""
 }
 
-func (x *StreamComplete) GetFinalUsage() *Usage {
+// Branch is one alternative at a point of a thread
+type Branch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Provider      string                 `protobuf:"bytes,4,opt,name=provider,proto3" json:"provider,omitempty"`
+	Model         string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC 3339
+	Active        bool                   `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`                       // On the thread's current branch
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Branch) Reset() {
+	*x = Branch{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Branch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Branch) ProtoMessage() {}
+
+func (x *Branch) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[36]
 	if x != nil {
-		return x.FinalUsage
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *StreamComplete) GetCitations() []*Citation {
+// Deprecated: Use Branch.ProtoReflect.Descriptor instead.
+func (*Branch) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *Branch) GetMessageId() string {
 	if x != nil {
-		return x.Citations
+		return x.MessageId
 	}
-	return nil
+	return ""
 }
 
-func (x *StreamComplete) GetToolCalls() []*ToolCall {
+func (x *Branch) GetRole() string {
 	if x != nil {
-		return x.ToolCalls
+		return x.Role
 	}
-	return nil
+	return ""
 }
 
-func (x *StreamComplete) GetRequiresToolOutput() bool {
+func (x *Branch) GetContent() string {
 	if x != nil {
-		return x.RequiresToolOutput
+		return x.Content
 	}
-	return false
+	return ""
 }
 
-func (x *StreamComplete) GetCodeExecutions() []*CodeExecutionResult {
+func (x *Branch) GetProvider() string {
 	if x != nil {
-		return x.CodeExecutions
+		return x.Provider
 	}
-	return nil
+	return ""
 }
 
-func (x *StreamComplete) GetImages() []*GeneratedImage {
+func (x *Branch) GetModel() string {
 	if x != nil {
-		return x.Images
+		return x.Model
 	}
-	return nil
+	return ""
 }
 
-func (x *StreamComplete) GetHtmlContent() string {
+func (x *Branch) GetCreatedAt() string {
 	if x != nil {
-		return x.HtmlContent
+		return x.CreatedAt
 	}
 	return ""
 }
 
-func (x *StreamComplete) GetStructuredMetadata() *StructuredMetadata {
+func (x *Branch) GetActive() bool {
 	if x != nil {
-		return x.StructuredMetadata
+		return x.Active
 	}
-	return nil
+	return false
 }
 
-// StreamError signals an error during streaming
-type StreamError struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Retryable     bool                   `protobuf:"varint,3,opt,name=retryable,proto3" json:"retryable,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ListBranchesResponse contains the alternatives, oldest first
+type ListBranchesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId        string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	ParentMessageId string                 `protobuf:"bytes,2,opt,name=parent_message_id,json=parentMessageId,proto3" json:"parent_message_id,omitempty"` // Message the alternatives follow; empty at the start of the thread
+	Branches        []*Branch              `protobuf:"bytes,3,rep,name=branches,proto3" json:"branches,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *StreamError) Reset() {
-	*x = StreamError{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[9]
+func (x *ListBranchesResponse) Reset() {
+	*x = ListBranchesResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamError) String() string {
+func (x *ListBranchesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamError) ProtoMessage() {}
+func (*ListBranchesResponse) ProtoMessage() {}
 
-func (x *StreamError) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[9]
+func (x *ListBranchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -978,61 +3518,62 @@ func (x *StreamError) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamError.ProtoReflect.Descriptor instead.
-func (*StreamError) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ListBranchesResponse.ProtoReflect.Descriptor instead.
+func (*ListBranchesResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *StreamError) GetCode() string {
+func (x *ListBranchesResponse) GetThreadId() string {
 	if x != nil {
-		return x.Code
+		return x.ThreadId
 	}
 	return ""
 }
 
-func (x *StreamError) GetMessage() string {
+func (x *ListBranchesResponse) GetParentMessageId() string {
 	if x != nil {
-		return x.Message
+		return x.ParentMessageId
 	}
 	return ""
 }
 
-func (x *StreamError) GetRetryable() bool {
+func (x *ListBranchesResponse) GetBranches() []*Branch {
 	if x != nil {
-		return x.Retryable
+		return x.Branches
 	}
-	return false
+	return nil
 }
 
-// GeneratedImage represents an AI-generated image
-type GeneratedImage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`                            // Raw image bytes (JPEG/PNG)
-	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`    // MIME type (e.g., "image/jpeg")
-	Prompt        string                 `protobuf:"bytes,3,opt,name=prompt,proto3" json:"prompt,omitempty"`                        // The prompt used to generate the image
-	AltText       string                 `protobuf:"bytes,4,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`       // Accessibility description
-	Width         int32                  `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`                         // Width in pixels
-	Height        int32                  `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`                       // Height in pixels
-	ContentId     string                 `protobuf:"bytes,7,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"` // Content-ID for email embedding (cid:xxx)
+// SelectBranchRequest selects the message whose branch becomes current
+type SelectBranchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Any message of the branch. Below it the branch follows the replies that
+	// were current most recently.
+	MessageId string `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Client the thread belongs to, as client_id in GenerateReply; ignored
+	// for authenticated clients
+	ClientId      string `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GeneratedImage) Reset() {
-	*x = GeneratedImage{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[10]
+func (x *SelectBranchRequest) Reset() {
+	*x = SelectBranchRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GeneratedImage) String() string {
+func (x *SelectBranchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GeneratedImage) ProtoMessage() {}
+func (*SelectBranchRequest) ProtoMessage() {}
 
-func (x *GeneratedImage) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[10]
+func (x *SelectBranchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1043,88 +3584,113 @@ func (x *GeneratedImage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GeneratedImage.ProtoReflect.Descriptor instead.
-func (*GeneratedImage) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use SelectBranchRequest.ProtoReflect.Descriptor instead.
+func (*SelectBranchRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *GeneratedImage) GetData() []byte {
+func (x *SelectBranchRequest) GetTenantId() string {
 	if x != nil {
-		return x.Data
+		return x.TenantId
 	}
-	return nil
+	return ""
 }
 
-func (x *GeneratedImage) GetMimeType() string {
+func (x *SelectBranchRequest) GetMessageId() string {
 	if x != nil {
-		return x.MimeType
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *GeneratedImage) GetPrompt() string {
+func (x *SelectBranchRequest) GetClientId() string {
 	if x != nil {
-		return x.Prompt
+		return x.ClientId
 	}
 	return ""
 }
 
-func (x *GeneratedImage) GetAltText() string {
-	if x != nil {
-		return x.AltText
-	}
-	return ""
+// SelectBranchResponse contains the thread's new current branch
+type SelectBranchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	MessageIds    []string               `protobuf:"bytes,2,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"` // Messages of the current branch, in thread order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GeneratedImage) GetWidth() int32 {
+func (x *SelectBranchResponse) Reset() {
+	*x = SelectBranchResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectBranchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectBranchResponse) ProtoMessage() {}
+
+func (x *SelectBranchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[39]
 	if x != nil {
-		return x.Width
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GeneratedImage) GetHeight() int32 {
+// Deprecated: Use SelectBranchResponse.ProtoReflect.Descriptor instead.
+func (*SelectBranchResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SelectBranchResponse) GetThreadId() string {
 	if x != nil {
-		return x.Height
+		return x.ThreadId
 	}
-	return 0
+	return ""
 }
 
-func (x *GeneratedImage) GetContentId() string {
+func (x *SelectBranchResponse) GetMessageIds() []string {
 	if x != nil {
-		return x.ContentId
+		return x.MessageIds
 	}
-	return ""
+	return nil
 }
 
-// SelectProviderRequest asks which provider should handle a request
-type SelectProviderRequest struct {
+// CreateSessionTokenRequest sets the lifetime and limits of a session token
+type CreateSessionTokenRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Tenant identification (required for multitenant mode, optional for single-tenant)
-	TenantId         string             `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	Content          string             `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`                                           // The input content (for trigger phrase detection)
-	ExistingProvider string             `protobuf:"bytes,2,opt,name=existing_provider,json=existingProvider,proto3" json:"existing_provider,omitempty"` // Provider from existing thread (for continuity)
-	UserTier         string             `protobuf:"bytes,3,opt,name=user_tier,json=userTier,proto3" json:"user_tier,omitempty"`                         // User tier for tier-based routing
-	Triggers         []*ProviderTrigger `protobuf:"bytes,4,rep,name=triggers,proto3" json:"triggers,omitempty"`                                         // Custom trigger phrases
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
-}
-
-func (x *SelectProviderRequest) Reset() {
-	*x = SelectProviderRequest{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[11]
+	TenantId   string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	TtlSeconds int32  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Default 900, at most 3600
+	// Limits of the token, within the API key's; 0 leaves only the key's
+	RequestsPerMinute int32 `protobuf:"varint,3,opt,name=requests_per_minute,json=requestsPerMinute,proto3" json:"requests_per_minute,omitempty"`
+	RequestsPerDay    int32 `protobuf:"varint,4,opt,name=requests_per_day,json=requestsPerDay,proto3" json:"requests_per_day,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateSessionTokenRequest) Reset() {
+	*x = CreateSessionTokenRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SelectProviderRequest) String() string {
+func (x *CreateSessionTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SelectProviderRequest) ProtoMessage() {}
+func (*CreateSessionTokenRequest) ProtoMessage() {}
 
-func (x *SelectProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[11]
+func (x *CreateSessionTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1135,71 +3701,64 @@ func (x *SelectProviderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SelectProviderRequest.ProtoReflect.Descriptor instead.
-func (*SelectProviderRequest) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CreateSessionTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionTokenRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *SelectProviderRequest) GetTenantId() string {
+func (x *CreateSessionTokenRequest) GetTenantId() string {
 	if x != nil {
 		return x.TenantId
 	}
 	return ""
 }
 
-func (x *SelectProviderRequest) GetContent() string {
-	if x != nil {
-		return x.Content
-	}
-	return ""
-}
-
-func (x *SelectProviderRequest) GetExistingProvider() string {
+func (x *CreateSessionTokenRequest) GetTtlSeconds() int32 {
 	if x != nil {
-		return x.ExistingProvider
+		return x.TtlSeconds
 	}
-	return ""
+	return 0
 }
 
-func (x *SelectProviderRequest) GetUserTier() string {
+func (x *CreateSessionTokenRequest) GetRequestsPerMinute() int32 {
 	if x != nil {
-		return x.UserTier
+		return x.RequestsPerMinute
 	}
-	return ""
+	return 0
 }
 
-func (x *SelectProviderRequest) GetTriggers() []*ProviderTrigger {
+func (x *CreateSessionTokenRequest) GetRequestsPerDay() int32 {
 	if x != nil {
-		return x.Triggers
+		return x.RequestsPerDay
 	}
-	return nil
+	return 0
 }
 
-// ProviderTrigger defines a phrase that triggers a specific provider
-type ProviderTrigger struct {
+// CreateSessionTokenResponse contains the token, which is shown only once
+type CreateSessionTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Phrase        string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`                                // Trigger phrase to match
-	Provider      Provider               `protobuf:"varint,2,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"` // Provider to use when matched
-	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`                                  // Optional model override
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`                          // Sent as the API key, e.g. "Authorization: Bearer <token>"
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // For RevokeSessionToken
+	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC 3339
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProviderTrigger) Reset() {
-	*x = ProviderTrigger{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[12]
+func (x *CreateSessionTokenResponse) Reset() {
+	*x = CreateSessionTokenResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProviderTrigger) String() string {
+func (x *CreateSessionTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProviderTrigger) ProtoMessage() {}
+func (*CreateSessionTokenResponse) ProtoMessage() {}
 
-func (x *ProviderTrigger) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[12]
+func (x *CreateSessionTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1210,57 +3769,57 @@ func (x *ProviderTrigger) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProviderTrigger.ProtoReflect.Descriptor instead.
-func (*ProviderTrigger) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use CreateSessionTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionTokenResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *ProviderTrigger) GetPhrase() string {
+func (x *CreateSessionTokenResponse) GetToken() string {
 	if x != nil {
-		return x.Phrase
+		return x.Token
 	}
 	return ""
 }
 
-func (x *ProviderTrigger) GetProvider() Provider {
+func (x *CreateSessionTokenResponse) GetSessionId() string {
 	if x != nil {
-		return x.Provider
+		return x.SessionId
 	}
-	return Provider_PROVIDER_UNSPECIFIED
+	return ""
 }
 
-func (x *ProviderTrigger) GetModel() string {
+func (x *CreateSessionTokenResponse) GetExpiresAt() string {
 	if x != nil {
-		return x.Model
+		return x.ExpiresAt
 	}
 	return ""
 }
 
-// SelectProviderResponse contains the provider selection result
-type SelectProviderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Provider      Provider               `protobuf:"varint,1,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`
-	ModelOverride string                 `protobuf:"bytes,2,opt,name=model_override,json=modelOverride,proto3" json:"model_override,omitempty"`
-	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // "trigger", "tier", "continuity", "default"
+// RevokeSessionTokenRequest identifies the session token to revoke
+type RevokeSessionTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant identification (required for multitenant mode, optional for single-tenant)
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	SessionId     string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SelectProviderResponse) Reset() {
-	*x = SelectProviderResponse{}
-	mi := &file_airborne_v1_airborne_proto_msgTypes[13]
+func (x *RevokeSessionTokenRequest) Reset() {
+	*x = RevokeSessionTokenRequest{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SelectProviderResponse) String() string {
+func (x *RevokeSessionTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SelectProviderResponse) ProtoMessage() {}
+func (*RevokeSessionTokenRequest) ProtoMessage() {}
 
-func (x *SelectProviderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_airborne_proto_msgTypes[13]
+func (x *RevokeSessionTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1271,38 +3830,75 @@ func (x *SelectProviderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SelectProviderResponse.ProtoReflect.Descriptor instead.
-func (*SelectProviderResponse) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use RevokeSessionTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionTokenRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *SelectProviderResponse) GetProvider() Provider {
+func (x *RevokeSessionTokenRequest) GetTenantId() string {
 	if x != nil {
-		return x.Provider
+		return x.TenantId
 	}
-	return Provider_PROVIDER_UNSPECIFIED
+	return ""
 }
 
-func (x *SelectProviderResponse) GetModelOverride() string {
+func (x *RevokeSessionTokenRequest) GetSessionId() string {
 	if x != nil {
-		return x.ModelOverride
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *SelectProviderResponse) GetReason() string {
+// RevokeSessionTokenResponse reports whether a live token was revoked
+type RevokeSessionTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revoked       bool                   `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"` // False if the token had already expired or been revoked
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionTokenResponse) Reset() {
+	*x = RevokeSessionTokenResponse{}
+	mi := &file_airborne_v1_airborne_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionTokenResponse) ProtoMessage() {}
+
+func (x *RevokeSessionTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_airborne_proto_msgTypes[43]
 	if x != nil {
-		return x.Reason
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionTokenResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_airborne_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RevokeSessionTokenResponse) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
 }
 
 var File_airborne_v1_airborne_proto protoreflect.FileDescriptor
 
 const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\n" +
-	"\x1aairborne/v1/airborne.proto\x12\vairborne.v1\x1a\x18airborne/v1/common.proto\"\xd3\n" +
-	"\n" +
+	"\x1aairborne/v1/airborne.proto\x12\vairborne.v1\x1a\x18airborne/v1/common.proto\"\xd1\x10\n" +
 	"\x14GenerateReplyRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x11 \x01(\tR\btenantId\x12\"\n" +
 	"\finstructions\x18\x01 \x01(\tR\finstructions\x12\x1d\n" +
@@ -1327,7 +3923,25 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\bmetadata\x18\x10 \x03(\v2/.airborne.v1.GenerateReplyRequest.MetadataEntryR\bmetadata\x12'\n" +
 	"\x05tools\x18\x13 \x03(\v2\x11.airborne.v1.ToolR\x05tools\x12:\n" +
 	"\ftool_results\x18\x14 \x03(\v2\x17.airborne.v1.ToolResultR\vtoolResults\x128\n" +
-	"\x18enable_structured_output\x18\x15 \x01(\bR\x16enableStructuredOutput\x1aC\n" +
+	"\x18enable_structured_output\x18\x15 \x01(\bR\x16enableStructuredOutput\x12#\n" +
+	"\renable_memory\x18\x16 \x01(\bR\fenableMemory\x12\x17\n" +
+	"\auser_id\x18\x17 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"idempotent\x18\x18 \x01(\bR\n" +
+	"idempotent\x121\n" +
+	"\bpriority\x18\x19 \x01(\x0e2\x15.airborne.v1.PriorityR\bpriority\x12%\n" +
+	"\x0eenable_hedging\x18\x1a \x01(\bR\renableHedging\x12$\n" +
+	"\x0ehedge_delay_ms\x18\x1b \x01(\x05R\fhedgeDelayMs\x123\n" +
+	"\x06safety\x18\x1c \x01(\v2\x1b.airborne.v1.SafetySettingsR\x06safety\x12;\n" +
+	"\fcomputer_use\x18\x1d \x01(\v2\x18.airborne.v1.ComputerUseR\vcomputerUse\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x1e \x01(\x05R\ttimeoutMs\x12d\n" +
+	"\x11feature_overrides\x18\x1f \x03(\v27.airborne.v1.GenerateReplyRequest.FeatureOverridesEntryR\x10featureOverrides\x129\n" +
+	"\vattachments\x18  \x03(\v2\x17.airborne.v1.AttachmentR\vattachments\x120\n" +
+	"\x14retrieval_query_mode\x18! \x01(\tR\x12retrievalQueryMode\x12,\n" +
+	"\x12rag_context_tokens\x18\" \x01(\x05R\x10ragContextTokens\x12\x16\n" +
+	"\x06preset\x18# \x01(\tR\x06preset\x12/\n" +
+	"\x06images\x18$ \x03(\v2\x17.airborne.v1.ImageInputR\x06images\x1aC\n" +
 	"\x15FileIdToFilenameEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a_\n" +
@@ -1336,7 +3950,10 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x05value:\x028\x01\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb8\x06\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15FeatureOverridesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\"\xfe\v\n" +
 	"\x15GenerateReplyResponse\x12\x12\n" +
 	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1f\n" +
 	"\vresponse_id\x18\x02 \x01(\tR\n" +
@@ -1358,7 +3975,58 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\fhtml_content\x18\x0e \x01(\tR\vhtmlContent\x12P\n" +
 	"\x13structured_metadata\x18\x0f \x01(\v2\x1f.airborne.v1.StructuredMetadataR\x12structuredMetadata\x12+\n" +
 	"\x11grounding_queries\x18\x10 \x01(\x05R\x10groundingQueries\x12,\n" +
-	"\x12grounding_cost_usd\x18\x11 \x01(\x01R\x10groundingCostUsd\"\xeb\x03\n" +
+	"\x12grounding_cost_usd\x18\x11 \x01(\x01R\x10groundingCostUsd\x12\x16\n" +
+	"\x06cached\x18\x12 \x01(\bR\x06cached\x12,\n" +
+	"\x12estimated_cost_usd\x18\x13 \x01(\x01R\x10estimatedCostUsd\x12)\n" +
+	"\x10downgrade_reason\x18\x14 \x01(\tR\x0fdowngradeReason\x12%\n" +
+	"\x0eoriginal_model\x18\x15 \x01(\tR\roriginalModel\x12\x16\n" +
+	"\x06hedged\x18\x16 \x01(\bR\x06hedged\x12;\n" +
+	"\fsafety_block\x18\x17 \x01(\v2\x18.airborne.v1.SafetyBlockR\vsafetyBlock\x12F\n" +
+	"\x10computer_actions\x18\x18 \x03(\v2\x1b.airborne.v1.ComputerActionR\x0fcomputerActions\x12I\n" +
+	"\x11failover_attempts\x18\x19 \x03(\v2\x1c.airborne.v1.FailoverAttemptR\x10failoverAttempts\x12.\n" +
+	"\x05judge\x18\x1a \x01(\v2\x18.airborne.v1.JudgeResultR\x05judge\x129\n" +
+	"\tgrounding\x18\x1b \x01(\v2\x1b.airborne.v1.GroundingCheckR\tgrounding\x12\"\n" +
+	"\fdeduplicated\x18\x1c \x01(\bR\fdeduplicated\x12:\n" +
+	"\vattribution\x18\x1d \x01(\v2\x18.airborne.v1.AttributionR\vattribution\x12-\n" +
+	"\x12footnoted_markdown\x18\x1e \x01(\tR\x11footnotedMarkdown\x12\x1d\n" +
+	"\n" +
+	"model_tier\x18\x1f \x01(\tR\tmodelTier\x12+\n" +
+	"\x11escalation_reason\x18  \x01(\tR\x10escalationReason\"\x91\x01\n" +
+	"\x0fFailoverAttempt\x121\n" +
+	"\bprovider\x18\x01 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\"\xdc\x01\n" +
+	"\vJudgeResult\x12\x19\n" +
+	"\buse_case\x18\x01 \x01(\tR\auseCase\x12\x16\n" +
+	"\x06winner\x18\x02 \x01(\x05R\x06winner\x12;\n" +
+	"\n" +
+	"candidates\x18\x03 \x03(\v2\x1b.airborne.v1.JudgeCandidateR\n" +
+	"candidates\x12<\n" +
+	"\x0ejudge_provider\x18\x04 \x01(\x0e2\x15.airborne.v1.ProviderR\rjudgeProvider\x12\x1f\n" +
+	"\vjudge_model\x18\x05 \x01(\tR\n" +
+	"judgeModel\"T\n" +
+	"\x0eJudgeCandidate\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\"\xcf\x02\n" +
+	"\x0eGroundingCheck\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\x12\x1c\n" +
+	"\tthreshold\x18\x02 \x01(\x01R\tthreshold\x12\x1a\n" +
+	"\bgrounded\x18\x03 \x01(\bR\bgrounded\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12-\n" +
+	"\x12unsupported_claims\x18\x06 \x03(\tR\x11unsupportedClaims\x12B\n" +
+	"\x11verifier_provider\x18\a \x01(\x0e2\x15.airborne.v1.ProviderR\x10verifierProvider\x12%\n" +
+	"\x0everifier_model\x18\b \x01(\tR\rverifierModel\x12#\n" +
+	"\rfallback_text\x18\t \x01(\tR\ffallbackText\"\xba\x01\n" +
+	"\vAttribution\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x121\n" +
+	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12!\n" +
+	"\fgenerated_at\x18\x03 \x01(\tR\vgeneratedAt\x12!\n" +
+	"\frequest_hash\x18\x04 \x01(\tR\vrequestHash\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\tR\tsignature\"\xc6\x04\n" +
 	"\x12GenerateReplyChunk\x127\n" +
 	"\n" +
 	"text_delta\x18\x01 \x01(\v2\x16.airborne.v1.TextDeltaH\x00R\ttextDelta\x12=\n" +
@@ -1367,19 +4035,24 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\bcomplete\x18\x04 \x01(\v2\x1b.airborne.v1.StreamCompleteH\x00R\bcomplete\x120\n" +
 	"\x05error\x18\x05 \x01(\v2\x18.airborne.v1.StreamErrorH\x00R\x05error\x12G\n" +
 	"\x10tool_call_update\x18\x06 \x01(\v2\x1b.airborne.v1.ToolCallUpdateH\x00R\x0etoolCallUpdate\x12V\n" +
-	"\x15code_execution_update\x18\a \x01(\v2 .airborne.v1.CodeExecutionUpdateH\x00R\x13codeExecutionUpdateB\a\n" +
+	"\x15code_execution_update\x18\a \x01(\v2 .airborne.v1.CodeExecutionUpdateH\x00R\x13codeExecutionUpdate\x12Y\n" +
+	"\x16computer_action_update\x18\b \x01(\v2!.airborne.v1.ComputerActionUpdateH\x00R\x14computerActionUpdateB\a\n" +
 	"\x05chunk\"D\n" +
 	"\x0eToolCallUpdate\x122\n" +
-	"\ttool_call\x18\x01 \x01(\v2\x15.airborne.v1.ToolCallR\btoolCall\"U\n" +
+	"\ttool_call\x18\x01 \x01(\v2\x15.airborne.v1.ToolCallR\btoolCall\"K\n" +
+	"\x14ComputerActionUpdate\x123\n" +
+	"\x06action\x18\x01 \x01(\v2\x1b.airborne.v1.ComputerActionR\x06action\"U\n" +
 	"\x13CodeExecutionUpdate\x12>\n" +
 	"\texecution\x18\x01 \x01(\v2 .airborne.v1.CodeExecutionResultR\texecution\"5\n" +
 	"\tTextDelta\x12\x12\n" +
 	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
-	"\x05index\x18\x02 \x01(\x05R\x05index\"7\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\"\x83\x01\n" +
 	"\vUsageUpdate\x12(\n" +
-	"\x05usage\x18\x01 \x01(\v2\x12.airborne.v1.UsageR\x05usage\"C\n" +
+	"\x05usage\x18\x01 \x01(\v2\x12.airborne.v1.UsageR\x05usage\x12,\n" +
+	"\x12estimated_cost_usd\x18\x02 \x01(\x01R\x10estimatedCostUsd\x12\x1c\n" +
+	"\testimated\x18\x03 \x01(\bR\testimated\"C\n" +
 	"\x0eCitationUpdate\x121\n" +
-	"\bcitation\x18\x01 \x01(\v2\x15.airborne.v1.CitationR\bcitation\"\xc1\x04\n" +
+	"\bcitation\x18\x01 \x01(\v2\x15.airborne.v1.CitationR\bcitation\"\x84\b\n" +
 	"\x0eStreamComplete\x12\x1f\n" +
 	"\vresponse_id\x18\x01 \x01(\tR\n" +
 	"responseId\x12\x14\n" +
@@ -1395,7 +4068,16 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\x06images\x18\t \x03(\v2\x1b.airborne.v1.GeneratedImageR\x06images\x12!\n" +
 	"\fhtml_content\x18\n" +
 	" \x01(\tR\vhtmlContent\x12P\n" +
-	"\x13structured_metadata\x18\v \x01(\v2\x1f.airborne.v1.StructuredMetadataR\x12structuredMetadata\"Y\n" +
+	"\x13structured_metadata\x18\v \x01(\v2\x1f.airborne.v1.StructuredMetadataR\x12structuredMetadata\x12,\n" +
+	"\x12estimated_cost_usd\x18\f \x01(\x01R\x10estimatedCostUsd\x12)\n" +
+	"\x10downgrade_reason\x18\r \x01(\tR\x0fdowngradeReason\x12%\n" +
+	"\x0eoriginal_model\x18\x0e \x01(\tR\roriginalModel\x12\x16\n" +
+	"\x06hedged\x18\x0f \x01(\bR\x06hedged\x12;\n" +
+	"\fsafety_block\x18\x10 \x01(\v2\x18.airborne.v1.SafetyBlockR\vsafetyBlock\x12F\n" +
+	"\x10computer_actions\x18\x11 \x03(\v2\x1b.airborne.v1.ComputerActionR\x0fcomputerActions\x129\n" +
+	"\tgrounding\x18\x12 \x01(\v2\x1b.airborne.v1.GroundingCheckR\tgrounding\x12:\n" +
+	"\vattribution\x18\x13 \x01(\v2\x18.airborne.v1.AttributionR\vattribution\x12-\n" +
+	"\x12footnoted_markdown\x18\x14 \x01(\tR\x11footnotedMarkdown\"Y\n" +
 	"\vStreamError\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
@@ -1408,13 +4090,15 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\x05width\x18\x05 \x01(\x05R\x05width\x12\x16\n" +
 	"\x06height\x18\x06 \x01(\x05R\x06height\x12\x1d\n" +
 	"\n" +
-	"content_id\x18\a \x01(\tR\tcontentId\"\xd2\x01\n" +
+	"content_id\x18\a \x01(\tR\tcontentId\"\x8c\x02\n" +
 	"\x15SelectProviderRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x05 \x01(\tR\btenantId\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12+\n" +
 	"\x11existing_provider\x18\x02 \x01(\tR\x10existingProvider\x12\x1b\n" +
 	"\tuser_tier\x18\x03 \x01(\tR\buserTier\x128\n" +
-	"\btriggers\x18\x04 \x03(\v2\x1c.airborne.v1.ProviderTriggerR\btriggers\"r\n" +
+	"\btriggers\x18\x04 \x03(\v2\x1c.airborne.v1.ProviderTriggerR\btriggers\x12\x1b\n" +
+	"\tthread_id\x18\x06 \x01(\tR\bthreadId\x12\x1b\n" +
+	"\tclient_id\x18\a \x01(\tR\bclientId\"r\n" +
 	"\x0fProviderTrigger\x12\x16\n" +
 	"\x06phrase\x18\x01 \x01(\tR\x06phrase\x121\n" +
 	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x14\n" +
@@ -1422,11 +4106,164 @@ const file_airborne_v1_airborne_proto_rawDesc = "" +
 	"\x16SelectProviderResponse\x121\n" +
 	"\bprovider\x18\x01 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12%\n" +
 	"\x0emodel_override\x18\x02 \x01(\tR\rmodelOverride\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason2\xa1\x02\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"5\n" +
+	"\x16GetCapabilitiesRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"\x9c\x01\n" +
+	"\x17GetCapabilitiesResponse\x12@\n" +
+	"\x10default_provider\x18\x01 \x01(\x0e2\x15.airborne.v1.ProviderR\x0fdefaultProvider\x12?\n" +
+	"\tproviders\x18\x02 \x03(\v2!.airborne.v1.ProviderCapabilitiesR\tproviders\"\x86\x04\n" +
+	"\x14ProviderCapabilities\x121\n" +
+	"\bprovider\x18\x01 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x120\n" +
+	"\x14supports_file_search\x18\x03 \x01(\bR\x12supportsFileSearch\x12.\n" +
+	"\x13supports_web_search\x18\x04 \x01(\bR\x11supportsWebSearch\x126\n" +
+	"\x17supports_code_execution\x18\x05 \x01(\bR\x15supportsCodeExecution\x12-\n" +
+	"\x12supports_streaming\x18\x06 \x01(\bR\x11supportsStreaming\x12<\n" +
+	"\x1asupports_native_continuity\x18\a \x01(\bR\x18supportsNativeContinuity\x12<\n" +
+	"\x1asupports_structured_output\x18\b \x01(\bR\x18supportsStructuredOutput\x12,\n" +
+	"\x12max_context_tokens\x18\t \x01(\x05R\x10maxContextTokens\x122\n" +
+	"\x15supports_computer_use\x18\n" +
+	" \x01(\bR\x13supportsComputerUse\"\xa8\x03\n" +
+	"\x18SummarizeDocumentRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1a\n" +
+	"\acontent\x18\x02 \x01(\fH\x00R\acontent\x12=\n" +
+	"\vstored_file\x18\x03 \x01(\v2\x1a.airborne.v1.StoredFileRefH\x00R\n" +
+	"storedFile\x12\x1a\n" +
+	"\bfilename\x18\x04 \x01(\tR\bfilename\x12\x1b\n" +
+	"\tmime_type\x18\x05 \x01(\tR\bmimeType\x12\"\n" +
+	"\finstructions\x18\x06 \x01(\tR\finstructions\x12D\n" +
+	"\x12preferred_provider\x18\a \x01(\x0e2\x15.airborne.v1.ProviderR\x11preferredProvider\x12%\n" +
+	"\x0emodel_override\x18\b \x01(\tR\rmodelOverride\x12!\n" +
+	"\fmax_sections\x18\t \x01(\x05R\vmaxSections\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\n" +
+	" \x01(\tR\trequestIdB\b\n" +
+	"\x06source\"C\n" +
+	"\rStoredFileRef\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\tR\x06fileId\"\xc4\x02\n" +
+	"\x19SummarizeDocumentResponse\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\x127\n" +
+	"\bsections\x18\x03 \x03(\v2\x1b.airborne.v1.SummarySectionR\bsections\x121\n" +
+	"\bprovider\x18\x04 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12(\n" +
+	"\x05usage\x18\x06 \x01(\v2\x12.airborne.v1.UsageR\x05usage\x12,\n" +
+	"\x12estimated_cost_usd\x18\a \x01(\x01R\x10estimatedCostUsd\x12\x1d\n" +
+	"\n" +
+	"part_count\x18\b \x01(\x05R\tpartCount\"}\n" +
+	"\x0eSummarySection\x12\x18\n" +
+	"\aheading\x18\x01 \x01(\tR\aheading\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\x127\n" +
+	"\tcitations\x18\x03 \x03(\v2\x19.airborne.v1.DocumentSpanR\tcitations\"v\n" +
+	"\fDocumentSpan\x12\x12\n" +
+	"\x04part\x18\x01 \x01(\x05R\x04part\x12\x1d\n" +
+	"\n" +
+	"char_start\x18\x02 \x01(\x05R\tcharStart\x12\x19\n" +
+	"\bchar_end\x18\x03 \x01(\x05R\acharEnd\x12\x18\n" +
+	"\asnippet\x18\x04 \x01(\tR\asnippet\"`\n" +
+	"\fEmbedRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x14\n" +
+	"\x05texts\x18\x02 \x03(\tR\x05texts\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"\xf1\x01\n" +
+	"\rEmbedResponse\x126\n" +
+	"\n" +
+	"embeddings\x18\x01 \x03(\v2\x16.airborne.v1.EmbeddingR\n" +
+	"embeddings\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x1e\n" +
+	"\n" +
+	"dimensions\x18\x04 \x01(\x05R\n" +
+	"dimensions\x12(\n" +
+	"\x05usage\x18\x05 \x01(\v2\x12.airborne.v1.UsageR\x05usage\x12,\n" +
+	"\x12estimated_cost_usd\x18\x06 \x01(\x01R\x10estimatedCostUsd\"#\n" +
+	"\tEmbedding\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x02R\x06values\"\xd1\x01\n" +
+	"\x12AnalyzeTextRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12D\n" +
+	"\x12preferred_provider\x18\x03 \x01(\x0e2\x15.airborne.v1.ProviderR\x11preferredProvider\x12%\n" +
+	"\x0emodel_override\x18\x04 \x01(\tR\rmodelOverride\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\"\xf3\x01\n" +
+	"\x13AnalyzeTextResponse\x12;\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1f.airborne.v1.StructuredMetadataR\bmetadata\x121\n" +
+	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12(\n" +
+	"\x05usage\x18\x04 \x01(\v2\x12.airborne.v1.UsageR\x05usage\x12,\n" +
+	"\x12estimated_cost_usd\x18\x05 \x01(\x01R\x10estimatedCostUsd\"\xad\x01\n" +
+	"\x18RegenerateMessageRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12;\n" +
+	"\aoptions\x18\x04 \x01(\v2!.airborne.v1.GenerateReplyRequestR\aoptions\"\xc7\x01\n" +
+	"\x19RegenerateMessageResponse\x128\n" +
+	"\x05reply\x18\x01 \x01(\v2\".airborne.v1.GenerateReplyResponseR\x05reply\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x124\n" +
+	"\x16superseded_message_ids\x18\x03 \x03(\tR\x14supersededMessageIds\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\tR\tmessageId\"n\n" +
+	"\x13ListBranchesRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\"\xbe\x01\n" +
+	"\x06Branch\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x1a\n" +
+	"\bprovider\x18\x04 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06active\x18\a \x01(\bR\x06active\"\x90\x01\n" +
+	"\x14ListBranchesResponse\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12*\n" +
+	"\x11parent_message_id\x18\x02 \x01(\tR\x0fparentMessageId\x12/\n" +
+	"\bbranches\x18\x03 \x03(\v2\x13.airborne.v1.BranchR\bbranches\"n\n" +
+	"\x13SelectBranchRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\"T\n" +
+	"\x14SelectBranchResponse\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12\x1f\n" +
+	"\vmessage_ids\x18\x02 \x03(\tR\n" +
+	"messageIds\"\xb3\x01\n" +
+	"\x19CreateSessionTokenRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x05R\n" +
+	"ttlSeconds\x12.\n" +
+	"\x13requests_per_minute\x18\x03 \x01(\x05R\x11requestsPerMinute\x12(\n" +
+	"\x10requests_per_day\x18\x04 \x01(\x05R\x0erequestsPerDay\"p\n" +
+	"\x1aCreateSessionTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\"W\n" +
+	"\x19RevokeSessionTokenRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"6\n" +
+	"\x1aRevokeSessionTokenResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\bR\arevoked2\xd1\b\n" +
 	"\x0fAirborneService\x12V\n" +
 	"\rGenerateReply\x12!.airborne.v1.GenerateReplyRequest\x1a\".airborne.v1.GenerateReplyResponse\x12[\n" +
 	"\x13GenerateReplyStream\x12!.airborne.v1.GenerateReplyRequest\x1a\x1f.airborne.v1.GenerateReplyChunk0\x01\x12Y\n" +
-	"\x0eSelectProvider\x12\".airborne.v1.SelectProviderRequest\x1a#.airborne.v1.SelectProviderResponseB\xaa\x01\n" +
+	"\x0eSelectProvider\x12\".airborne.v1.SelectProviderRequest\x1a#.airborne.v1.SelectProviderResponse\x12\\\n" +
+	"\x0fGetCapabilities\x12#.airborne.v1.GetCapabilitiesRequest\x1a$.airborne.v1.GetCapabilitiesResponse\x12b\n" +
+	"\x11SummarizeDocument\x12%.airborne.v1.SummarizeDocumentRequest\x1a&.airborne.v1.SummarizeDocumentResponse\x12>\n" +
+	"\x05Embed\x12\x19.airborne.v1.EmbedRequest\x1a\x1a.airborne.v1.EmbedResponse\x12P\n" +
+	"\vAnalyzeText\x12\x1f.airborne.v1.AnalyzeTextRequest\x1a .airborne.v1.AnalyzeTextResponse\x12b\n" +
+	"\x11RegenerateMessage\x12%.airborne.v1.RegenerateMessageRequest\x1a&.airborne.v1.RegenerateMessageResponse\x12S\n" +
+	"\fListBranches\x12 .airborne.v1.ListBranchesRequest\x1a!.airborne.v1.ListBranchesResponse\x12S\n" +
+	"\fSelectBranch\x12 .airborne.v1.SelectBranchRequest\x1a!.airborne.v1.SelectBranchResponse\x12e\n" +
+	"\x12CreateSessionToken\x12&.airborne.v1.CreateSessionTokenRequest\x1a'.airborne.v1.CreateSessionTokenResponse\x12e\n" +
+	"\x12RevokeSessionToken\x12&.airborne.v1.RevokeSessionTokenRequest\x1a'.airborne.v1.RevokeSessionTokenResponseB\xaa\x01\n" +
 	"\x0fcom.airborne.v1B\rAirborneProtoP\x01Z;github.com/ai8future/airborne/gen/go/airborne/v1;airbornev1\xa2\x02\x03AXX\xaa\x02\vAirborne.V1\xca\x02\vAirborne\\V1\xe2\x02\x17Airborne\\V1\\GPBMetadata\xea\x02\fAirborne::V1b\x06proto3"
 
 var (
@@ -1441,86 +4278,183 @@ func file_airborne_v1_airborne_proto_rawDescGZIP() []byte {
 	return file_airborne_v1_airborne_proto_rawDescData
 }
 
-var file_airborne_v1_airborne_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_airborne_v1_airborne_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_airborne_v1_airborne_proto_goTypes = []any{
-	(*GenerateReplyRequest)(nil),   // 0: airborne.v1.GenerateReplyRequest
-	(*GenerateReplyResponse)(nil),  // 1: airborne.v1.GenerateReplyResponse
-	(*GenerateReplyChunk)(nil),     // 2: airborne.v1.GenerateReplyChunk
-	(*ToolCallUpdate)(nil),         // 3: airborne.v1.ToolCallUpdate
-	(*CodeExecutionUpdate)(nil),    // 4: airborne.v1.CodeExecutionUpdate
-	(*TextDelta)(nil),              // 5: airborne.v1.TextDelta
-	(*UsageUpdate)(nil),            // 6: airborne.v1.UsageUpdate
-	(*CitationUpdate)(nil),         // 7: airborne.v1.CitationUpdate
-	(*StreamComplete)(nil),         // 8: airborne.v1.StreamComplete
-	(*StreamError)(nil),            // 9: airborne.v1.StreamError
-	(*GeneratedImage)(nil),         // 10: airborne.v1.GeneratedImage
-	(*SelectProviderRequest)(nil),  // 11: airborne.v1.SelectProviderRequest
-	(*ProviderTrigger)(nil),        // 12: airborne.v1.ProviderTrigger
-	(*SelectProviderResponse)(nil), // 13: airborne.v1.SelectProviderResponse
-	nil,                            // 14: airborne.v1.GenerateReplyRequest.FileIdToFilenameEntry
-	nil,                            // 15: airborne.v1.GenerateReplyRequest.ProviderConfigsEntry
-	nil,                            // 16: airborne.v1.GenerateReplyRequest.MetadataEntry
-	(*Message)(nil),                // 17: airborne.v1.Message
-	(Provider)(0),                  // 18: airborne.v1.Provider
-	(*Tool)(nil),                   // 19: airborne.v1.Tool
-	(*ToolResult)(nil),             // 20: airborne.v1.ToolResult
-	(*Usage)(nil),                  // 21: airborne.v1.Usage
-	(*Citation)(nil),               // 22: airborne.v1.Citation
-	(*ToolCall)(nil),               // 23: airborne.v1.ToolCall
-	(*CodeExecutionResult)(nil),    // 24: airborne.v1.CodeExecutionResult
-	(*StructuredMetadata)(nil),     // 25: airborne.v1.StructuredMetadata
-	(*ProviderConfig)(nil),         // 26: airborne.v1.ProviderConfig
+	(*GenerateReplyRequest)(nil),       // 0: airborne.v1.GenerateReplyRequest
+	(*GenerateReplyResponse)(nil),      // 1: airborne.v1.GenerateReplyResponse
+	(*FailoverAttempt)(nil),            // 2: airborne.v1.FailoverAttempt
+	(*JudgeResult)(nil),                // 3: airborne.v1.JudgeResult
+	(*JudgeCandidate)(nil),             // 4: airborne.v1.JudgeCandidate
+	(*GroundingCheck)(nil),             // 5: airborne.v1.GroundingCheck
+	(*Attribution)(nil),                // 6: airborne.v1.Attribution
+	(*GenerateReplyChunk)(nil),         // 7: airborne.v1.GenerateReplyChunk
+	(*ToolCallUpdate)(nil),             // 8: airborne.v1.ToolCallUpdate
+	(*ComputerActionUpdate)(nil),       // 9: airborne.v1.ComputerActionUpdate
+	(*CodeExecutionUpdate)(nil),        // 10: airborne.v1.CodeExecutionUpdate
+	(*TextDelta)(nil),                  // 11: airborne.v1.TextDelta
+	(*UsageUpdate)(nil),                // 12: airborne.v1.UsageUpdate
+	(*CitationUpdate)(nil),             // 13: airborne.v1.CitationUpdate
+	(*StreamComplete)(nil),             // 14: airborne.v1.StreamComplete
+	(*StreamError)(nil),                // 15: airborne.v1.StreamError
+	(*GeneratedImage)(nil),             // 16: airborne.v1.GeneratedImage
+	(*SelectProviderRequest)(nil),      // 17: airborne.v1.SelectProviderRequest
+	(*ProviderTrigger)(nil),            // 18: airborne.v1.ProviderTrigger
+	(*SelectProviderResponse)(nil),     // 19: airborne.v1.SelectProviderResponse
+	(*GetCapabilitiesRequest)(nil),     // 20: airborne.v1.GetCapabilitiesRequest
+	(*GetCapabilitiesResponse)(nil),    // 21: airborne.v1.GetCapabilitiesResponse
+	(*ProviderCapabilities)(nil),       // 22: airborne.v1.ProviderCapabilities
+	(*SummarizeDocumentRequest)(nil),   // 23: airborne.v1.SummarizeDocumentRequest
+	(*StoredFileRef)(nil),              // 24: airborne.v1.StoredFileRef
+	(*SummarizeDocumentResponse)(nil),  // 25: airborne.v1.SummarizeDocumentResponse
+	(*SummarySection)(nil),             // 26: airborne.v1.SummarySection
+	(*DocumentSpan)(nil),               // 27: airborne.v1.DocumentSpan
+	(*EmbedRequest)(nil),               // 28: airborne.v1.EmbedRequest
+	(*EmbedResponse)(nil),              // 29: airborne.v1.EmbedResponse
+	(*Embedding)(nil),                  // 30: airborne.v1.Embedding
+	(*AnalyzeTextRequest)(nil),         // 31: airborne.v1.AnalyzeTextRequest
+	(*AnalyzeTextResponse)(nil),        // 32: airborne.v1.AnalyzeTextResponse
+	(*RegenerateMessageRequest)(nil),   // 33: airborne.v1.RegenerateMessageRequest
+	(*RegenerateMessageResponse)(nil),  // 34: airborne.v1.RegenerateMessageResponse
+	(*ListBranchesRequest)(nil),        // 35: airborne.v1.ListBranchesRequest
+	(*Branch)(nil),                     // 36: airborne.v1.Branch
+	(*ListBranchesResponse)(nil),       // 37: airborne.v1.ListBranchesResponse
+	(*SelectBranchRequest)(nil),        // 38: airborne.v1.SelectBranchRequest
+	(*SelectBranchResponse)(nil),       // 39: airborne.v1.SelectBranchResponse
+	(*CreateSessionTokenRequest)(nil),  // 40: airborne.v1.CreateSessionTokenRequest
+	(*CreateSessionTokenResponse)(nil), // 41: airborne.v1.CreateSessionTokenResponse
+	(*RevokeSessionTokenRequest)(nil),  // 42: airborne.v1.RevokeSessionTokenRequest
+	(*RevokeSessionTokenResponse)(nil), // 43: airborne.v1.RevokeSessionTokenResponse
+	nil,                                // 44: airborne.v1.GenerateReplyRequest.FileIdToFilenameEntry
+	nil,                                // 45: airborne.v1.GenerateReplyRequest.ProviderConfigsEntry
+	nil,                                // 46: airborne.v1.GenerateReplyRequest.MetadataEntry
+	nil,                                // 47: airborne.v1.GenerateReplyRequest.FeatureOverridesEntry
+	(*Message)(nil),                    // 48: airborne.v1.Message
+	(Provider)(0),                      // 49: airborne.v1.Provider
+	(*Tool)(nil),                       // 50: airborne.v1.Tool
+	(*ToolResult)(nil),                 // 51: airborne.v1.ToolResult
+	(Priority)(0),                      // 52: airborne.v1.Priority
+	(*SafetySettings)(nil),             // 53: airborne.v1.SafetySettings
+	(*ComputerUse)(nil),                // 54: airborne.v1.ComputerUse
+	(*Attachment)(nil),                 // 55: airborne.v1.Attachment
+	(*ImageInput)(nil),                 // 56: airborne.v1.ImageInput
+	(*Usage)(nil),                      // 57: airborne.v1.Usage
+	(*Citation)(nil),                   // 58: airborne.v1.Citation
+	(*ToolCall)(nil),                   // 59: airborne.v1.ToolCall
+	(*CodeExecutionResult)(nil),        // 60: airborne.v1.CodeExecutionResult
+	(*StructuredMetadata)(nil),         // 61: airborne.v1.StructuredMetadata
+	(*SafetyBlock)(nil),                // 62: airborne.v1.SafetyBlock
+	(*ComputerAction)(nil),             // 63: airborne.v1.ComputerAction
+	(*ProviderConfig)(nil),             // 64: airborne.v1.ProviderConfig
 }
 var file_airborne_v1_airborne_proto_depIdxs = []int32{
-	17, // 0: airborne.v1.GenerateReplyRequest.conversation_history:type_name -> airborne.v1.Message
-	18, // 1: airborne.v1.GenerateReplyRequest.preferred_provider:type_name -> airborne.v1.Provider
-	14, // 2: airborne.v1.GenerateReplyRequest.file_id_to_filename:type_name -> airborne.v1.GenerateReplyRequest.FileIdToFilenameEntry
-	15, // 3: airborne.v1.GenerateReplyRequest.provider_configs:type_name -> airborne.v1.GenerateReplyRequest.ProviderConfigsEntry
-	18, // 4: airborne.v1.GenerateReplyRequest.fallback_provider:type_name -> airborne.v1.Provider
-	16, // 5: airborne.v1.GenerateReplyRequest.metadata:type_name -> airborne.v1.GenerateReplyRequest.MetadataEntry
-	19, // 6: airborne.v1.GenerateReplyRequest.tools:type_name -> airborne.v1.Tool
-	20, // 7: airborne.v1.GenerateReplyRequest.tool_results:type_name -> airborne.v1.ToolResult
-	21, // 8: airborne.v1.GenerateReplyResponse.usage:type_name -> airborne.v1.Usage
-	22, // 9: airborne.v1.GenerateReplyResponse.citations:type_name -> airborne.v1.Citation
-	18, // 10: airborne.v1.GenerateReplyResponse.provider:type_name -> airborne.v1.Provider
-	18, // 11: airborne.v1.GenerateReplyResponse.original_provider:type_name -> airborne.v1.Provider
-	23, // 12: airborne.v1.GenerateReplyResponse.tool_calls:type_name -> airborne.v1.ToolCall
-	24, // 13: airborne.v1.GenerateReplyResponse.code_executions:type_name -> airborne.v1.CodeExecutionResult
-	10, // 14: airborne.v1.GenerateReplyResponse.images:type_name -> airborne.v1.GeneratedImage
-	25, // 15: airborne.v1.GenerateReplyResponse.structured_metadata:type_name -> airborne.v1.StructuredMetadata
-	5,  // 16: airborne.v1.GenerateReplyChunk.text_delta:type_name -> airborne.v1.TextDelta
-	6,  // 17: airborne.v1.GenerateReplyChunk.usage_update:type_name -> airborne.v1.UsageUpdate
-	7,  // 18: airborne.v1.GenerateReplyChunk.citation_update:type_name -> airborne.v1.CitationUpdate
-	8,  // 19: airborne.v1.GenerateReplyChunk.complete:type_name -> airborne.v1.StreamComplete
-	9,  // 20: airborne.v1.GenerateReplyChunk.error:type_name -> airborne.v1.StreamError
-	3,  // 21: airborne.v1.GenerateReplyChunk.tool_call_update:type_name -> airborne.v1.ToolCallUpdate
-	4,  // 22: airborne.v1.GenerateReplyChunk.code_execution_update:type_name -> airborne.v1.CodeExecutionUpdate
-	23, // 23: airborne.v1.ToolCallUpdate.tool_call:type_name -> airborne.v1.ToolCall
-	24, // 24: airborne.v1.CodeExecutionUpdate.execution:type_name -> airborne.v1.CodeExecutionResult
-	21, // 25: airborne.v1.UsageUpdate.usage:type_name -> airborne.v1.Usage
-	22, // 26: airborne.v1.CitationUpdate.citation:type_name -> airborne.v1.Citation
-	18, // 27: airborne.v1.StreamComplete.provider:type_name -> airborne.v1.Provider
-	21, // 28: airborne.v1.StreamComplete.final_usage:type_name -> airborne.v1.Usage
-	22, // 29: airborne.v1.StreamComplete.citations:type_name -> airborne.v1.Citation
-	23, // 30: airborne.v1.StreamComplete.tool_calls:type_name -> airborne.v1.ToolCall
-	24, // 31: airborne.v1.StreamComplete.code_executions:type_name -> airborne.v1.CodeExecutionResult
-	10, // 32: airborne.v1.StreamComplete.images:type_name -> airborne.v1.GeneratedImage
-	25, // 33: airborne.v1.StreamComplete.structured_metadata:type_name -> airborne.v1.StructuredMetadata
-	12, // 34: airborne.v1.SelectProviderRequest.triggers:type_name -> airborne.v1.ProviderTrigger
-	18, // 35: airborne.v1.ProviderTrigger.provider:type_name -> airborne.v1.Provider
-	18, // 36: airborne.v1.SelectProviderResponse.provider:type_name -> airborne.v1.Provider
-	26, // 37: airborne.v1.GenerateReplyRequest.ProviderConfigsEntry.value:type_name -> airborne.v1.ProviderConfig
-	0,  // 38: airborne.v1.AirborneService.GenerateReply:input_type -> airborne.v1.GenerateReplyRequest
-	0,  // 39: airborne.v1.AirborneService.GenerateReplyStream:input_type -> airborne.v1.GenerateReplyRequest
-	11, // 40: airborne.v1.AirborneService.SelectProvider:input_type -> airborne.v1.SelectProviderRequest
-	1,  // 41: airborne.v1.AirborneService.GenerateReply:output_type -> airborne.v1.GenerateReplyResponse
-	2,  // 42: airborne.v1.AirborneService.GenerateReplyStream:output_type -> airborne.v1.GenerateReplyChunk
-	13, // 43: airborne.v1.AirborneService.SelectProvider:output_type -> airborne.v1.SelectProviderResponse
-	41, // [41:44] is the sub-list for method output_type
-	38, // [38:41] is the sub-list for method input_type
-	38, // [38:38] is the sub-list for extension type_name
-	38, // [38:38] is the sub-list for extension extendee
-	0,  // [0:38] is the sub-list for field type_name
+	48, // 0: airborne.v1.GenerateReplyRequest.conversation_history:type_name -> airborne.v1.Message
+	49, // 1: airborne.v1.GenerateReplyRequest.preferred_provider:type_name -> airborne.v1.Provider
+	44, // 2: airborne.v1.GenerateReplyRequest.file_id_to_filename:type_name -> airborne.v1.GenerateReplyRequest.FileIdToFilenameEntry
+	45, // 3: airborne.v1.GenerateReplyRequest.provider_configs:type_name -> airborne.v1.GenerateReplyRequest.ProviderConfigsEntry
+	49, // 4: airborne.v1.GenerateReplyRequest.fallback_provider:type_name -> airborne.v1.Provider
+	46, // 5: airborne.v1.GenerateReplyRequest.metadata:type_name -> airborne.v1.GenerateReplyRequest.MetadataEntry
+	50, // 6: airborne.v1.GenerateReplyRequest.tools:type_name -> airborne.v1.Tool
+	51, // 7: airborne.v1.GenerateReplyRequest.tool_results:type_name -> airborne.v1.ToolResult
+	52, // 8: airborne.v1.GenerateReplyRequest.priority:type_name -> airborne.v1.Priority
+	53, // 9: airborne.v1.GenerateReplyRequest.safety:type_name -> airborne.v1.SafetySettings
+	54, // 10: airborne.v1.GenerateReplyRequest.computer_use:type_name -> airborne.v1.ComputerUse
+	47, // 11: airborne.v1.GenerateReplyRequest.feature_overrides:type_name -> airborne.v1.GenerateReplyRequest.FeatureOverridesEntry
+	55, // 12: airborne.v1.GenerateReplyRequest.attachments:type_name -> airborne.v1.Attachment
+	56, // 13: airborne.v1.GenerateReplyRequest.images:type_name -> airborne.v1.ImageInput
+	57, // 14: airborne.v1.GenerateReplyResponse.usage:type_name -> airborne.v1.Usage
+	58, // 15: airborne.v1.GenerateReplyResponse.citations:type_name -> airborne.v1.Citation
+	49, // 16: airborne.v1.GenerateReplyResponse.provider:type_name -> airborne.v1.Provider
+	49, // 17: airborne.v1.GenerateReplyResponse.original_provider:type_name -> airborne.v1.Provider
+	59, // 18: airborne.v1.GenerateReplyResponse.tool_calls:type_name -> airborne.v1.ToolCall
+	60, // 19: airborne.v1.GenerateReplyResponse.code_executions:type_name -> airborne.v1.CodeExecutionResult
+	16, // 20: airborne.v1.GenerateReplyResponse.images:type_name -> airborne.v1.GeneratedImage
+	61, // 21: airborne.v1.GenerateReplyResponse.structured_metadata:type_name -> airborne.v1.StructuredMetadata
+	62, // 22: airborne.v1.GenerateReplyResponse.safety_block:type_name -> airborne.v1.SafetyBlock
+	63, // 23: airborne.v1.GenerateReplyResponse.computer_actions:type_name -> airborne.v1.ComputerAction
+	2,  // 24: airborne.v1.GenerateReplyResponse.failover_attempts:type_name -> airborne.v1.FailoverAttempt
+	3,  // 25: airborne.v1.GenerateReplyResponse.judge:type_name -> airborne.v1.JudgeResult
+	5,  // 26: airborne.v1.GenerateReplyResponse.grounding:type_name -> airborne.v1.GroundingCheck
+	6,  // 27: airborne.v1.GenerateReplyResponse.attribution:type_name -> airborne.v1.Attribution
+	49, // 28: airborne.v1.FailoverAttempt.provider:type_name -> airborne.v1.Provider
+	4,  // 29: airborne.v1.JudgeResult.candidates:type_name -> airborne.v1.JudgeCandidate
+	49, // 30: airborne.v1.JudgeResult.judge_provider:type_name -> airborne.v1.Provider
+	49, // 31: airborne.v1.GroundingCheck.verifier_provider:type_name -> airborne.v1.Provider
+	49, // 32: airborne.v1.Attribution.provider:type_name -> airborne.v1.Provider
+	11, // 33: airborne.v1.GenerateReplyChunk.text_delta:type_name -> airborne.v1.TextDelta
+	12, // 34: airborne.v1.GenerateReplyChunk.usage_update:type_name -> airborne.v1.UsageUpdate
+	13, // 35: airborne.v1.GenerateReplyChunk.citation_update:type_name -> airborne.v1.CitationUpdate
+	14, // 36: airborne.v1.GenerateReplyChunk.complete:type_name -> airborne.v1.StreamComplete
+	15, // 37: airborne.v1.GenerateReplyChunk.error:type_name -> airborne.v1.StreamError
+	8,  // 38: airborne.v1.GenerateReplyChunk.tool_call_update:type_name -> airborne.v1.ToolCallUpdate
+	10, // 39: airborne.v1.GenerateReplyChunk.code_execution_update:type_name -> airborne.v1.CodeExecutionUpdate
+	9,  // 40: airborne.v1.GenerateReplyChunk.computer_action_update:type_name -> airborne.v1.ComputerActionUpdate
+	59, // 41: airborne.v1.ToolCallUpdate.tool_call:type_name -> airborne.v1.ToolCall
+	63, // 42: airborne.v1.ComputerActionUpdate.action:type_name -> airborne.v1.ComputerAction
+	60, // 43: airborne.v1.CodeExecutionUpdate.execution:type_name -> airborne.v1.CodeExecutionResult
+	57, // 44: airborne.v1.UsageUpdate.usage:type_name -> airborne.v1.Usage
+	58, // 45: airborne.v1.CitationUpdate.citation:type_name -> airborne.v1.Citation
+	49, // 46: airborne.v1.StreamComplete.provider:type_name -> airborne.v1.Provider
+	57, // 47: airborne.v1.StreamComplete.final_usage:type_name -> airborne.v1.Usage
+	58, // 48: airborne.v1.StreamComplete.citations:type_name -> airborne.v1.Citation
+	59, // 49: airborne.v1.StreamComplete.tool_calls:type_name -> airborne.v1.ToolCall
+	60, // 50: airborne.v1.StreamComplete.code_executions:type_name -> airborne.v1.CodeExecutionResult
+	16, // 51: airborne.v1.StreamComplete.images:type_name -> airborne.v1.GeneratedImage
+	61, // 52: airborne.v1.StreamComplete.structured_metadata:type_name -> airborne.v1.StructuredMetadata
+	62, // 53: airborne.v1.StreamComplete.safety_block:type_name -> airborne.v1.SafetyBlock
+	63, // 54: airborne.v1.StreamComplete.computer_actions:type_name -> airborne.v1.ComputerAction
+	5,  // 55: airborne.v1.StreamComplete.grounding:type_name -> airborne.v1.GroundingCheck
+	6,  // 56: airborne.v1.StreamComplete.attribution:type_name -> airborne.v1.Attribution
+	18, // 57: airborne.v1.SelectProviderRequest.triggers:type_name -> airborne.v1.ProviderTrigger
+	49, // 58: airborne.v1.ProviderTrigger.provider:type_name -> airborne.v1.Provider
+	49, // 59: airborne.v1.SelectProviderResponse.provider:type_name -> airborne.v1.Provider
+	49, // 60: airborne.v1.GetCapabilitiesResponse.default_provider:type_name -> airborne.v1.Provider
+	22, // 61: airborne.v1.GetCapabilitiesResponse.providers:type_name -> airborne.v1.ProviderCapabilities
+	49, // 62: airborne.v1.ProviderCapabilities.provider:type_name -> airborne.v1.Provider
+	24, // 63: airborne.v1.SummarizeDocumentRequest.stored_file:type_name -> airborne.v1.StoredFileRef
+	49, // 64: airborne.v1.SummarizeDocumentRequest.preferred_provider:type_name -> airborne.v1.Provider
+	26, // 65: airborne.v1.SummarizeDocumentResponse.sections:type_name -> airborne.v1.SummarySection
+	49, // 66: airborne.v1.SummarizeDocumentResponse.provider:type_name -> airborne.v1.Provider
+	57, // 67: airborne.v1.SummarizeDocumentResponse.usage:type_name -> airborne.v1.Usage
+	27, // 68: airborne.v1.SummarySection.citations:type_name -> airborne.v1.DocumentSpan
+	30, // 69: airborne.v1.EmbedResponse.embeddings:type_name -> airborne.v1.Embedding
+	57, // 70: airborne.v1.EmbedResponse.usage:type_name -> airborne.v1.Usage
+	49, // 71: airborne.v1.AnalyzeTextRequest.preferred_provider:type_name -> airborne.v1.Provider
+	61, // 72: airborne.v1.AnalyzeTextResponse.metadata:type_name -> airborne.v1.StructuredMetadata
+	49, // 73: airborne.v1.AnalyzeTextResponse.provider:type_name -> airborne.v1.Provider
+	57, // 74: airborne.v1.AnalyzeTextResponse.usage:type_name -> airborne.v1.Usage
+	0,  // 75: airborne.v1.RegenerateMessageRequest.options:type_name -> airborne.v1.GenerateReplyRequest
+	1,  // 76: airborne.v1.RegenerateMessageResponse.reply:type_name -> airborne.v1.GenerateReplyResponse
+	36, // 77: airborne.v1.ListBranchesResponse.branches:type_name -> airborne.v1.Branch
+	64, // 78: airborne.v1.GenerateReplyRequest.ProviderConfigsEntry.value:type_name -> airborne.v1.ProviderConfig
+	0,  // 79: airborne.v1.AirborneService.GenerateReply:input_type -> airborne.v1.GenerateReplyRequest
+	0,  // 80: airborne.v1.AirborneService.GenerateReplyStream:input_type -> airborne.v1.GenerateReplyRequest
+	17, // 81: airborne.v1.AirborneService.SelectProvider:input_type -> airborne.v1.SelectProviderRequest
+	20, // 82: airborne.v1.AirborneService.GetCapabilities:input_type -> airborne.v1.GetCapabilitiesRequest
+	23, // 83: airborne.v1.AirborneService.SummarizeDocument:input_type -> airborne.v1.SummarizeDocumentRequest
+	28, // 84: airborne.v1.AirborneService.Embed:input_type -> airborne.v1.EmbedRequest
+	31, // 85: airborne.v1.AirborneService.AnalyzeText:input_type -> airborne.v1.AnalyzeTextRequest
+	33, // 86: airborne.v1.AirborneService.RegenerateMessage:input_type -> airborne.v1.RegenerateMessageRequest
+	35, // 87: airborne.v1.AirborneService.ListBranches:input_type -> airborne.v1.ListBranchesRequest
+	38, // 88: airborne.v1.AirborneService.SelectBranch:input_type -> airborne.v1.SelectBranchRequest
+	40, // 89: airborne.v1.AirborneService.CreateSessionToken:input_type -> airborne.v1.CreateSessionTokenRequest
+	42, // 90: airborne.v1.AirborneService.RevokeSessionToken:input_type -> airborne.v1.RevokeSessionTokenRequest
+	1,  // 91: airborne.v1.AirborneService.GenerateReply:output_type -> airborne.v1.GenerateReplyResponse
+	7,  // 92: airborne.v1.AirborneService.GenerateReplyStream:output_type -> airborne.v1.GenerateReplyChunk
+	19, // 93: airborne.v1.AirborneService.SelectProvider:output_type -> airborne.v1.SelectProviderResponse
+	21, // 94: airborne.v1.AirborneService.GetCapabilities:output_type -> airborne.v1.GetCapabilitiesResponse
+	25, // 95: airborne.v1.AirborneService.SummarizeDocument:output_type -> airborne.v1.SummarizeDocumentResponse
+	29, // 96: airborne.v1.AirborneService.Embed:output_type -> airborne.v1.EmbedResponse
+	32, // 97: airborne.v1.AirborneService.AnalyzeText:output_type -> airborne.v1.AnalyzeTextResponse
+	34, // 98: airborne.v1.AirborneService.RegenerateMessage:output_type -> airborne.v1.RegenerateMessageResponse
+	37, // 99: airborne.v1.AirborneService.ListBranches:output_type -> airborne.v1.ListBranchesResponse
+	39, // 100: airborne.v1.AirborneService.SelectBranch:output_type -> airborne.v1.SelectBranchResponse
+	41, // 101: airborne.v1.AirborneService.CreateSessionToken:output_type -> airborne.v1.CreateSessionTokenResponse
+	43, // 102: airborne.v1.AirborneService.RevokeSessionToken:output_type -> airborne.v1.RevokeSessionTokenResponse
+	91, // [91:103] is the sub-list for method output_type
+	79, // [79:91] is the sub-list for method input_type
+	79, // [79:79] is the sub-list for extension type_name
+	79, // [79:79] is the sub-list for extension extendee
+	0,  // [0:79] is the sub-list for field type_name
 }
 
 func init() { file_airborne_v1_airborne_proto_init() }
@@ -1529,7 +4463,7 @@ func file_airborne_v1_airborne_proto_init() {
 		return
 	}
 	file_airborne_v1_common_proto_init()
-	file_airborne_v1_airborne_proto_msgTypes[2].OneofWrappers = []any{
+	file_airborne_v1_airborne_proto_msgTypes[7].OneofWrappers = []any{
 		(*GenerateReplyChunk_TextDelta)(nil),
 		(*GenerateReplyChunk_UsageUpdate)(nil),
 		(*GenerateReplyChunk_CitationUpdate)(nil),
@@ -1537,6 +4471,11 @@ func file_airborne_v1_airborne_proto_init() {
 		(*GenerateReplyChunk_Error)(nil),
 		(*GenerateReplyChunk_ToolCallUpdate)(nil),
 		(*GenerateReplyChunk_CodeExecutionUpdate)(nil),
+		(*GenerateReplyChunk_ComputerActionUpdate)(nil),
+	}
+	file_airborne_v1_airborne_proto_msgTypes[23].OneofWrappers = []any{
+		(*SummarizeDocumentRequest_Content)(nil),
+		(*SummarizeDocumentRequest_StoredFile)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1544,7 +4483,7 @@ func file_airborne_v1_airborne_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_airborne_v1_airborne_proto_rawDesc), len(file_airborne_v1_airborne_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
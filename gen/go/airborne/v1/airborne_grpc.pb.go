@@ -22,6 +22,15 @@ const (
 	AirborneService_GenerateReply_FullMethodName       = "/airborne.v1.AirborneService/GenerateReply"
 	AirborneService_GenerateReplyStream_FullMethodName = "/airborne.v1.AirborneService/GenerateReplyStream"
 	AirborneService_SelectProvider_FullMethodName      = "/airborne.v1.AirborneService/SelectProvider"
+	AirborneService_GetCapabilities_FullMethodName     = "/airborne.v1.AirborneService/GetCapabilities"
+	AirborneService_SummarizeDocument_FullMethodName   = "/airborne.v1.AirborneService/SummarizeDocument"
+	AirborneService_Embed_FullMethodName               = "/airborne.v1.AirborneService/Embed"
+	AirborneService_AnalyzeText_FullMethodName         = "/airborne.v1.AirborneService/AnalyzeText"
+	AirborneService_RegenerateMessage_FullMethodName   = "/airborne.v1.AirborneService/RegenerateMessage"
+	AirborneService_ListBranches_FullMethodName        = "/airborne.v1.AirborneService/ListBranches"
+	AirborneService_SelectBranch_FullMethodName        = "/airborne.v1.AirborneService/SelectBranch"
+	AirborneService_CreateSessionToken_FullMethodName  = "/airborne.v1.AirborneService/CreateSessionToken"
+	AirborneService_RevokeSessionToken_FullMethodName  = "/airborne.v1.AirborneService/RevokeSessionToken"
 )
 
 // AirborneServiceClient is the client API for AirborneService service.
@@ -32,10 +41,38 @@ const (
 type AirborneServiceClient interface {
 	// GenerateReply generates a completion (unary request/response)
 	GenerateReply(ctx context.Context, in *GenerateReplyRequest, opts ...grpc.CallOption) (*GenerateReplyResponse, error)
-	// GenerateReplyStream generates a streaming completion
+	// GenerateReplyStream generates a streaming completion. Chunks are sent as
+	// the provider produces them, so features that need the whole reply first
+	// apply to GenerateReply only: reply validation rules, judge policies,
+	// server-side tools, failover and the "auto" model tier. model_override
+	// "auto" is rejected; tenants that tier by default stream from the
+	// premium model
 	GenerateReplyStream(ctx context.Context, in *GenerateReplyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateReplyChunk], error)
 	// SelectProvider determines which provider to use based on content and rules
 	SelectProvider(ctx context.Context, in *SelectProviderRequest, opts ...grpc.CallOption) (*SelectProviderResponse, error)
+	// GetCapabilities lists the tenant's enabled providers and the features each supports
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+	// SummarizeDocument summarizes a long document with map-reduce over its chunks
+	SummarizeDocument(ctx context.Context, in *SummarizeDocumentRequest, opts ...grpc.CallOption) (*SummarizeDocumentResponse, error)
+	// Embed returns vector embeddings from the tenant's embedding provider
+	Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error)
+	// AnalyzeText extracts intent, entities, topics and facts from arbitrary text
+	AnalyzeText(ctx context.Context, in *AnalyzeTextRequest, opts ...grpc.CallOption) (*AnalyzeTextResponse, error)
+	// RegenerateMessage truncates a stored thread at a message and generates a
+	// new assistant reply, optionally to edited user input. The new reply is a
+	// branch alongside the replaced messages, which are kept but marked
+	// superseded.
+	RegenerateMessage(ctx context.Context, in *RegenerateMessageRequest, opts ...grpc.CallOption) (*RegenerateMessageResponse, error)
+	// ListBranches lists the alternatives at a message of a stored thread
+	ListBranches(ctx context.Context, in *ListBranchesRequest, opts ...grpc.CallOption) (*ListBranchesResponse, error)
+	// SelectBranch makes the branch through a message the thread's current one
+	SelectBranch(ctx context.Context, in *SelectBranchRequest, opts ...grpc.CallOption) (*SelectBranchResponse, error)
+	// CreateSessionToken mints a short-lived token, scoped to
+	// GenerateReplyStream, for front-end apps to stream replies without the
+	// API key. Requires an API key with chat:stream and auth_mode redis.
+	CreateSessionToken(ctx context.Context, in *CreateSessionTokenRequest, opts ...grpc.CallOption) (*CreateSessionTokenResponse, error)
+	// RevokeSessionToken invalidates a session token before it expires
+	RevokeSessionToken(ctx context.Context, in *RevokeSessionTokenRequest, opts ...grpc.CallOption) (*RevokeSessionTokenResponse, error)
 }
 
 type airborneServiceClient struct {
@@ -85,6 +122,96 @@ func (c *airborneServiceClient) SelectProvider(ctx context.Context, in *SelectPr
 	return out, nil
 }
 
+func (c *airborneServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, AirborneService_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) SummarizeDocument(ctx context.Context, in *SummarizeDocumentRequest, opts ...grpc.CallOption) (*SummarizeDocumentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SummarizeDocumentResponse)
+	err := c.cc.Invoke(ctx, AirborneService_SummarizeDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmbedResponse)
+	err := c.cc.Invoke(ctx, AirborneService_Embed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) AnalyzeText(ctx context.Context, in *AnalyzeTextRequest, opts ...grpc.CallOption) (*AnalyzeTextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzeTextResponse)
+	err := c.cc.Invoke(ctx, AirborneService_AnalyzeText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) RegenerateMessage(ctx context.Context, in *RegenerateMessageRequest, opts ...grpc.CallOption) (*RegenerateMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegenerateMessageResponse)
+	err := c.cc.Invoke(ctx, AirborneService_RegenerateMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) ListBranches(ctx context.Context, in *ListBranchesRequest, opts ...grpc.CallOption) (*ListBranchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBranchesResponse)
+	err := c.cc.Invoke(ctx, AirborneService_ListBranches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) SelectBranch(ctx context.Context, in *SelectBranchRequest, opts ...grpc.CallOption) (*SelectBranchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectBranchResponse)
+	err := c.cc.Invoke(ctx, AirborneService_SelectBranch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) CreateSessionToken(ctx context.Context, in *CreateSessionTokenRequest, opts ...grpc.CallOption) (*CreateSessionTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSessionTokenResponse)
+	err := c.cc.Invoke(ctx, AirborneService_CreateSessionToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airborneServiceClient) RevokeSessionToken(ctx context.Context, in *RevokeSessionTokenRequest, opts ...grpc.CallOption) (*RevokeSessionTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionTokenResponse)
+	err := c.cc.Invoke(ctx, AirborneService_RevokeSessionToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AirborneServiceServer is the server API for AirborneService service.
 // All implementations must embed UnimplementedAirborneServiceServer
 // for forward compatibility.
@@ -93,10 +220,38 @@ func (c *airborneServiceClient) SelectProvider(ctx context.Context, in *SelectPr
 type AirborneServiceServer interface {
 	// GenerateReply generates a completion (unary request/response)
 	GenerateReply(context.Context, *GenerateReplyRequest) (*GenerateReplyResponse, error)
-	// GenerateReplyStream generates a streaming completion
+	// GenerateReplyStream generates a streaming completion. Chunks are sent as
+	// the provider produces them, so features that need the whole reply first
+	// apply to GenerateReply only: reply validation rules, judge policies,
+	// server-side tools, failover and the "auto" model tier. model_override
+	// "auto" is rejected; tenants that tier by default stream from the
+	// premium model
 	GenerateReplyStream(*GenerateReplyRequest, grpc.ServerStreamingServer[GenerateReplyChunk]) error
 	// SelectProvider determines which provider to use based on content and rules
 	SelectProvider(context.Context, *SelectProviderRequest) (*SelectProviderResponse, error)
+	// GetCapabilities lists the tenant's enabled providers and the features each supports
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	// SummarizeDocument summarizes a long document with map-reduce over its chunks
+	SummarizeDocument(context.Context, *SummarizeDocumentRequest) (*SummarizeDocumentResponse, error)
+	// Embed returns vector embeddings from the tenant's embedding provider
+	Embed(context.Context, *EmbedRequest) (*EmbedResponse, error)
+	// AnalyzeText extracts intent, entities, topics and facts from arbitrary text
+	AnalyzeText(context.Context, *AnalyzeTextRequest) (*AnalyzeTextResponse, error)
+	// RegenerateMessage truncates a stored thread at a message and generates a
+	// new assistant reply, optionally to edited user input. The new reply is a
+	// branch alongside the replaced messages, which are kept but marked
+	// superseded.
+	RegenerateMessage(context.Context, *RegenerateMessageRequest) (*RegenerateMessageResponse, error)
+	// ListBranches lists the alternatives at a message of a stored thread
+	ListBranches(context.Context, *ListBranchesRequest) (*ListBranchesResponse, error)
+	// SelectBranch makes the branch through a message the thread's current one
+	SelectBranch(context.Context, *SelectBranchRequest) (*SelectBranchResponse, error)
+	// CreateSessionToken mints a short-lived token, scoped to
+	// GenerateReplyStream, for front-end apps to stream replies without the
+	// API key. Requires an API key with chat:stream and auth_mode redis.
+	CreateSessionToken(context.Context, *CreateSessionTokenRequest) (*CreateSessionTokenResponse, error)
+	// RevokeSessionToken invalidates a session token before it expires
+	RevokeSessionToken(context.Context, *RevokeSessionTokenRequest) (*RevokeSessionTokenResponse, error)
 	mustEmbedUnimplementedAirborneServiceServer()
 }
 
@@ -116,6 +271,33 @@ func (UnimplementedAirborneServiceServer) GenerateReplyStream(*GenerateReplyRequ
 func (UnimplementedAirborneServiceServer) SelectProvider(context.Context, *SelectProviderRequest) (*SelectProviderResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SelectProvider not implemented")
 }
+func (UnimplementedAirborneServiceServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedAirborneServiceServer) SummarizeDocument(context.Context, *SummarizeDocumentRequest) (*SummarizeDocumentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SummarizeDocument not implemented")
+}
+func (UnimplementedAirborneServiceServer) Embed(context.Context, *EmbedRequest) (*EmbedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Embed not implemented")
+}
+func (UnimplementedAirborneServiceServer) AnalyzeText(context.Context, *AnalyzeTextRequest) (*AnalyzeTextResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AnalyzeText not implemented")
+}
+func (UnimplementedAirborneServiceServer) RegenerateMessage(context.Context, *RegenerateMessageRequest) (*RegenerateMessageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegenerateMessage not implemented")
+}
+func (UnimplementedAirborneServiceServer) ListBranches(context.Context, *ListBranchesRequest) (*ListBranchesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBranches not implemented")
+}
+func (UnimplementedAirborneServiceServer) SelectBranch(context.Context, *SelectBranchRequest) (*SelectBranchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SelectBranch not implemented")
+}
+func (UnimplementedAirborneServiceServer) CreateSessionToken(context.Context, *CreateSessionTokenRequest) (*CreateSessionTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateSessionToken not implemented")
+}
+func (UnimplementedAirborneServiceServer) RevokeSessionToken(context.Context, *RevokeSessionTokenRequest) (*RevokeSessionTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeSessionToken not implemented")
+}
 func (UnimplementedAirborneServiceServer) mustEmbedUnimplementedAirborneServiceServer() {}
 func (UnimplementedAirborneServiceServer) testEmbeddedByValue()                         {}
 
@@ -184,6 +366,168 @@ func _AirborneService_SelectProvider_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AirborneService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_SummarizeDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SummarizeDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).SummarizeDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_SummarizeDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).SummarizeDocument(ctx, req.(*SummarizeDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_Embed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmbedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).Embed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_Embed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).Embed(ctx, req.(*EmbedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_AnalyzeText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).AnalyzeText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_AnalyzeText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).AnalyzeText(ctx, req.(*AnalyzeTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_RegenerateMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).RegenerateMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_RegenerateMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).RegenerateMessage(ctx, req.(*RegenerateMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_ListBranches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBranchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).ListBranches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_ListBranches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).ListBranches(ctx, req.(*ListBranchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_SelectBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectBranchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).SelectBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_SelectBranch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).SelectBranch(ctx, req.(*SelectBranchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_CreateSessionToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).CreateSessionToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_CreateSessionToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).CreateSessionToken(ctx, req.(*CreateSessionTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AirborneService_RevokeSessionToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AirborneServiceServer).RevokeSessionToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AirborneService_RevokeSessionToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AirborneServiceServer).RevokeSessionToken(ctx, req.(*RevokeSessionTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AirborneService_ServiceDesc is the grpc.ServiceDesc for AirborneService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -199,6 +543,42 @@ var AirborneService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SelectProvider",
 			Handler:    _AirborneService_SelectProvider_Handler,
 		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _AirborneService_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "SummarizeDocument",
+			Handler:    _AirborneService_SummarizeDocument_Handler,
+		},
+		{
+			MethodName: "Embed",
+			Handler:    _AirborneService_Embed_Handler,
+		},
+		{
+			MethodName: "AnalyzeText",
+			Handler:    _AirborneService_AnalyzeText_Handler,
+		},
+		{
+			MethodName: "RegenerateMessage",
+			Handler:    _AirborneService_RegenerateMessage_Handler,
+		},
+		{
+			MethodName: "ListBranches",
+			Handler:    _AirborneService_ListBranches_Handler,
+		},
+		{
+			MethodName: "SelectBranch",
+			Handler:    _AirborneService_SelectBranch_Handler,
+		},
+		{
+			MethodName: "CreateSessionToken",
+			Handler:    _AirborneService_CreateSessionToken_Handler,
+		},
+		{
+			MethodName: "RevokeSessionToken",
+			Handler:    _AirborneService_RevokeSessionToken_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
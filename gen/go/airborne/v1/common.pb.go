@@ -21,6 +21,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Priority is a request's QoS class. Unspecified is treated as interactive.
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	Priority_PRIORITY_INTERACTIVE Priority = 1 // User-facing; never shed for provider pressure
+	Priority_PRIORITY_BATCH       Priority = 2 // Bulk jobs that can tolerate queueing
+	Priority_PRIORITY_BACKGROUND  Priority = 3 // Lowest priority; shed first
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_INTERACTIVE",
+		2: "PRIORITY_BATCH",
+		3: "PRIORITY_BACKGROUND",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_INTERACTIVE": 1,
+		"PRIORITY_BATCH":       2,
+		"PRIORITY_BACKGROUND":  3,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_airborne_v1_common_proto_enumTypes[0].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_airborne_v1_common_proto_enumTypes[0]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{0}
+}
+
 // Provider identifies the AI provider
 type Provider int32
 
@@ -125,11 +178,11 @@ func (x Provider) String() string {
 }
 
 func (Provider) Descriptor() protoreflect.EnumDescriptor {
-	return file_airborne_v1_common_proto_enumTypes[0].Descriptor()
+	return file_airborne_v1_common_proto_enumTypes[1].Descriptor()
 }
 
 func (Provider) Type() protoreflect.EnumType {
-	return &file_airborne_v1_common_proto_enumTypes[0]
+	return &file_airborne_v1_common_proto_enumTypes[1]
 }
 
 func (x Provider) Number() protoreflect.EnumNumber {
@@ -138,7 +191,63 @@ func (x Provider) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Provider.Descriptor instead.
 func (Provider) EnumDescriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{0}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{1}
+}
+
+// SafetyThreshold is how readily content in a harm category is blocked
+type SafetyThreshold int32
+
+const (
+	SafetyThreshold_SAFETY_THRESHOLD_UNSPECIFIED            SafetyThreshold = 0 // Tenant setting or provider default
+	SafetyThreshold_SAFETY_THRESHOLD_BLOCK_NONE             SafetyThreshold = 1
+	SafetyThreshold_SAFETY_THRESHOLD_BLOCK_ONLY_HIGH        SafetyThreshold = 2
+	SafetyThreshold_SAFETY_THRESHOLD_BLOCK_MEDIUM_AND_ABOVE SafetyThreshold = 3
+	SafetyThreshold_SAFETY_THRESHOLD_BLOCK_LOW_AND_ABOVE    SafetyThreshold = 4
+)
+
+// Enum value maps for SafetyThreshold.
+var (
+	SafetyThreshold_name = map[int32]string{
+		0: "SAFETY_THRESHOLD_UNSPECIFIED",
+		1: "SAFETY_THRESHOLD_BLOCK_NONE",
+		2: "SAFETY_THRESHOLD_BLOCK_ONLY_HIGH",
+		3: "SAFETY_THRESHOLD_BLOCK_MEDIUM_AND_ABOVE",
+		4: "SAFETY_THRESHOLD_BLOCK_LOW_AND_ABOVE",
+	}
+	SafetyThreshold_value = map[string]int32{
+		"SAFETY_THRESHOLD_UNSPECIFIED":            0,
+		"SAFETY_THRESHOLD_BLOCK_NONE":             1,
+		"SAFETY_THRESHOLD_BLOCK_ONLY_HIGH":        2,
+		"SAFETY_THRESHOLD_BLOCK_MEDIUM_AND_ABOVE": 3,
+		"SAFETY_THRESHOLD_BLOCK_LOW_AND_ABOVE":    4,
+	}
+)
+
+func (x SafetyThreshold) Enum() *SafetyThreshold {
+	p := new(SafetyThreshold)
+	*p = x
+	return p
+}
+
+func (x SafetyThreshold) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SafetyThreshold) Descriptor() protoreflect.EnumDescriptor {
+	return file_airborne_v1_common_proto_enumTypes[2].Descriptor()
+}
+
+func (SafetyThreshold) Type() protoreflect.EnumType {
+	return &file_airborne_v1_common_proto_enumTypes[2]
+}
+
+func (x SafetyThreshold) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SafetyThreshold.Descriptor instead.
+func (SafetyThreshold) EnumDescriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{2}
 }
 
 type Citation_Type int32
@@ -174,11 +283,11 @@ func (x Citation_Type) String() string {
 }
 
 func (Citation_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_airborne_v1_common_proto_enumTypes[1].Descriptor()
+	return file_airborne_v1_common_proto_enumTypes[3].Descriptor()
 }
 
 func (Citation_Type) Type() protoreflect.EnumType {
-	return &file_airborne_v1_common_proto_enumTypes[1]
+	return &file_airborne_v1_common_proto_enumTypes[3]
 }
 
 func (x Citation_Type) Number() protoreflect.EnumNumber {
@@ -187,7 +296,7 @@ func (x Citation_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Citation_Type.Descriptor instead.
 func (Citation_Type) EnumDescriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{2, 0}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{4, 0}
 }
 
 // Message represents a conversation turn
@@ -251,6 +360,140 @@ func (x *Message) GetTimestamp() int64 {
 	return 0
 }
 
+// Attachment is a file sent with the user's message. Each provider receives
+// it in its native form (Gemini file data or file URIs, OpenAI file inputs,
+// Anthropic document blocks); text files are sent as part of the message.
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"` // Detected from the content when empty
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_airborne_v1_common_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attachment) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Attachment) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *Attachment) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+// ImageInput is an image sent with the user's message by reference, from a
+// URL or a file uploaded to the provider, rather than as attachment content.
+// Set exactly one of url and file_id.
+type ImageInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`                           // https URL, or a data URL (OpenAI only); for Gemini also a Files API URI
+	FileId        string                 `protobuf:"bytes,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`       // ID of an image uploaded to the provider's Files API (OpenAI only)
+	MimeType      string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"` // Required by Gemini when the URL has no image file extension
+	Detail        string                 `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`                     // "auto" (default), "low" or "high"; read by OpenAI only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageInput) Reset() {
+	*x = ImageInput{}
+	mi := &file_airborne_v1_common_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageInput) ProtoMessage() {}
+
+func (x *ImageInput) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageInput.ProtoReflect.Descriptor instead.
+func (*ImageInput) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ImageInput) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImageInput) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *ImageInput) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *ImageInput) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
 // Usage contains token metrics
 type Usage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -263,7 +506,7 @@ type Usage struct {
 
 func (x *Usage) Reset() {
 	*x = Usage{}
-	mi := &file_airborne_v1_common_proto_msgTypes[1]
+	mi := &file_airborne_v1_common_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -275,7 +518,7 @@ func (x *Usage) String() string {
 func (*Usage) ProtoMessage() {}
 
 func (x *Usage) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[1]
+	mi := &file_airborne_v1_common_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +531,7 @@ func (x *Usage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Usage.ProtoReflect.Descriptor instead.
 func (*Usage) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{1}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Usage) GetInputTokens() int64 {
@@ -331,7 +574,7 @@ type Citation struct {
 
 func (x *Citation) Reset() {
 	*x = Citation{}
-	mi := &file_airborne_v1_common_proto_msgTypes[2]
+	mi := &file_airborne_v1_common_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -343,7 +586,7 @@ func (x *Citation) String() string {
 func (*Citation) ProtoMessage() {}
 
 func (x *Citation) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[2]
+	mi := &file_airborne_v1_common_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -356,7 +599,7 @@ func (x *Citation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Citation.ProtoReflect.Descriptor instead.
 func (*Citation) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{2}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Citation) GetType() Citation_Type {
@@ -451,7 +694,7 @@ type ProviderConfig struct {
 
 func (x *ProviderConfig) Reset() {
 	*x = ProviderConfig{}
-	mi := &file_airborne_v1_common_proto_msgTypes[3]
+	mi := &file_airborne_v1_common_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -463,7 +706,7 @@ func (x *ProviderConfig) String() string {
 func (*ProviderConfig) ProtoMessage() {}
 
 func (x *ProviderConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[3]
+	mi := &file_airborne_v1_common_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -476,7 +719,7 @@ func (x *ProviderConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderConfig.ProtoReflect.Descriptor instead.
 func (*ProviderConfig) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{3}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ProviderConfig) GetApiKey() string {
@@ -546,7 +789,7 @@ type Tool struct {
 
 func (x *Tool) Reset() {
 	*x = Tool{}
-	mi := &file_airborne_v1_common_proto_msgTypes[4]
+	mi := &file_airborne_v1_common_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +801,7 @@ func (x *Tool) String() string {
 func (*Tool) ProtoMessage() {}
 
 func (x *Tool) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[4]
+	mi := &file_airborne_v1_common_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +814,7 @@ func (x *Tool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Tool.ProtoReflect.Descriptor instead.
 func (*Tool) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{4}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Tool) GetName() string {
@@ -605,7 +848,9 @@ func (x *Tool) GetStrict() bool {
 // ToolCall represents the model's request to invoke a tool
 type ToolCall struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Unique ID for this tool call (used to match with ToolResult)
+	// Unique ID for this tool call (used to match with ToolResult). This is
+	// the provider's call ID (OpenAI call_id, Anthropic tool_use id), or
+	// "call_<index>" when the provider assigns none (Gemini).
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	// Name of the tool to invoke
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -617,7 +862,7 @@ type ToolCall struct {
 
 func (x *ToolCall) Reset() {
 	*x = ToolCall{}
-	mi := &file_airborne_v1_common_proto_msgTypes[5]
+	mi := &file_airborne_v1_common_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -629,7 +874,7 @@ func (x *ToolCall) String() string {
 func (*ToolCall) ProtoMessage() {}
 
 func (x *ToolCall) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[5]
+	mi := &file_airborne_v1_common_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -642,7 +887,7 @@ func (x *ToolCall) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ToolCall.ProtoReflect.Descriptor instead.
 func (*ToolCall) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{5}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ToolCall) GetId() string {
@@ -674,14 +919,19 @@ type ToolResult struct {
 	// Output from the tool as string (typically JSON)
 	Output string `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
 	// Whether the tool execution failed
-	IsError       bool `protobuf:"varint,3,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	IsError bool `protobuf:"varint,3,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
+	// Computer-use results: the screenshot taken after performing the action,
+	// and the pending safety checks the executor acknowledged (OpenAI)
+	Screenshot               []byte                 `protobuf:"bytes,4,opt,name=screenshot,proto3" json:"screenshot,omitempty"`
+	ScreenshotMimeType       string                 `protobuf:"bytes,5,opt,name=screenshot_mime_type,json=screenshotMimeType,proto3" json:"screenshot_mime_type,omitempty"` // Defaults to image/png
+	AcknowledgedSafetyChecks []*ComputerSafetyCheck `protobuf:"bytes,6,rep,name=acknowledged_safety_checks,json=acknowledgedSafetyChecks,proto3" json:"acknowledged_safety_checks,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
 func (x *ToolResult) Reset() {
 	*x = ToolResult{}
-	mi := &file_airborne_v1_common_proto_msgTypes[6]
+	mi := &file_airborne_v1_common_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -693,7 +943,7 @@ func (x *ToolResult) String() string {
 func (*ToolResult) ProtoMessage() {}
 
 func (x *ToolResult) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[6]
+	mi := &file_airborne_v1_common_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -706,7 +956,7 @@ func (x *ToolResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ToolResult.ProtoReflect.Descriptor instead.
 func (*ToolResult) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{6}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ToolResult) GetToolCallId() string {
@@ -730,6 +980,27 @@ func (x *ToolResult) GetIsError() bool {
 	return false
 }
 
+func (x *ToolResult) GetScreenshot() []byte {
+	if x != nil {
+		return x.Screenshot
+	}
+	return nil
+}
+
+func (x *ToolResult) GetScreenshotMimeType() string {
+	if x != nil {
+		return x.ScreenshotMimeType
+	}
+	return ""
+}
+
+func (x *ToolResult) GetAcknowledgedSafetyChecks() []*ComputerSafetyCheck {
+	if x != nil {
+		return x.AcknowledgedSafetyChecks
+	}
+	return nil
+}
+
 // CodeExecutionResult contains output from code execution
 type CodeExecutionResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -751,7 +1022,7 @@ type CodeExecutionResult struct {
 
 func (x *CodeExecutionResult) Reset() {
 	*x = CodeExecutionResult{}
-	mi := &file_airborne_v1_common_proto_msgTypes[7]
+	mi := &file_airborne_v1_common_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -763,7 +1034,7 @@ func (x *CodeExecutionResult) String() string {
 func (*CodeExecutionResult) ProtoMessage() {}
 
 func (x *CodeExecutionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[7]
+	mi := &file_airborne_v1_common_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -776,7 +1047,7 @@ func (x *CodeExecutionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CodeExecutionResult.ProtoReflect.Descriptor instead.
 func (*CodeExecutionResult) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{7}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CodeExecutionResult) GetCode() string {
@@ -829,14 +1100,17 @@ type GeneratedFile struct {
 	// MIME type
 	MimeType string `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
 	// File content (base64 encoded for binary files)
-	Content       []byte `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Content []byte `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// URL the file can be downloaded from, set when the provider links to the
+	// file instead of returning its content
+	Url           string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GeneratedFile) Reset() {
 	*x = GeneratedFile{}
-	mi := &file_airborne_v1_common_proto_msgTypes[8]
+	mi := &file_airborne_v1_common_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -848,7 +1122,7 @@ func (x *GeneratedFile) String() string {
 func (*GeneratedFile) ProtoMessage() {}
 
 func (x *GeneratedFile) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[8]
+	mi := &file_airborne_v1_common_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -861,7 +1135,7 @@ func (x *GeneratedFile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GeneratedFile.ProtoReflect.Descriptor instead.
 func (*GeneratedFile) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{8}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GeneratedFile) GetName() string {
@@ -885,6 +1159,13 @@ func (x *GeneratedFile) GetContent() []byte {
 	return nil
 }
 
+func (x *GeneratedFile) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
 // StructuredMetadata contains extracted metadata from structured output mode
 type StructuredMetadata struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -897,14 +1178,16 @@ type StructuredMetadata struct {
 	// 2-4 keyword tags
 	Topics []string `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"`
 	// Calendar/meeting signals
-	Scheduling    *SchedulingIntent `protobuf:"bytes,5,opt,name=scheduling,proto3" json:"scheduling,omitempty"`
+	Scheduling *SchedulingIntent `protobuf:"bytes,5,opt,name=scheduling,proto3" json:"scheduling,omitempty"`
+	// Durable facts about the user worth remembering across conversations
+	Facts         []*StructuredFact `protobuf:"bytes,6,rep,name=facts,proto3" json:"facts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StructuredMetadata) Reset() {
 	*x = StructuredMetadata{}
-	mi := &file_airborne_v1_common_proto_msgTypes[9]
+	mi := &file_airborne_v1_common_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -916,7 +1199,7 @@ func (x *StructuredMetadata) String() string {
 func (*StructuredMetadata) ProtoMessage() {}
 
 func (x *StructuredMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[9]
+	mi := &file_airborne_v1_common_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -929,7 +1212,7 @@ func (x *StructuredMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StructuredMetadata.ProtoReflect.Descriptor instead.
 func (*StructuredMetadata) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{9}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *StructuredMetadata) GetIntent() string {
@@ -967,6 +1250,13 @@ func (x *StructuredMetadata) GetScheduling() *SchedulingIntent {
 	return nil
 }
 
+func (x *StructuredMetadata) GetFacts() []*StructuredFact {
+	if x != nil {
+		return x.Facts
+	}
+	return nil
+}
+
 // StructuredEntity represents an extracted named entity
 type StructuredEntity struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -982,7 +1272,7 @@ type StructuredEntity struct {
 
 func (x *StructuredEntity) Reset() {
 	*x = StructuredEntity{}
-	mi := &file_airborne_v1_common_proto_msgTypes[10]
+	mi := &file_airborne_v1_common_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -994,7 +1284,7 @@ func (x *StructuredEntity) String() string {
 func (*StructuredEntity) ProtoMessage() {}
 
 func (x *StructuredEntity) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[10]
+	mi := &file_airborne_v1_common_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1007,7 +1297,7 @@ func (x *StructuredEntity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StructuredEntity.ProtoReflect.Descriptor instead.
 func (*StructuredEntity) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{10}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *StructuredEntity) GetName() string {
@@ -1037,7 +1327,7 @@ type SchedulingIntent struct {
 
 func (x *SchedulingIntent) Reset() {
 	*x = SchedulingIntent{}
-	mi := &file_airborne_v1_common_proto_msgTypes[11]
+	mi := &file_airborne_v1_common_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1049,7 +1339,7 @@ func (x *SchedulingIntent) String() string {
 func (*SchedulingIntent) ProtoMessage() {}
 
 func (x *SchedulingIntent) ProtoReflect() protoreflect.Message {
-	mi := &file_airborne_v1_common_proto_msgTypes[11]
+	mi := &file_airborne_v1_common_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1062,7 +1352,7 @@ func (x *SchedulingIntent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SchedulingIntent.ProtoReflect.Descriptor instead.
 func (*SchedulingIntent) Descriptor() ([]byte, []int) {
-	return file_airborne_v1_common_proto_rawDescGZIP(), []int{11}
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *SchedulingIntent) GetDetected() bool {
@@ -1079,6 +1369,386 @@ func (x *SchedulingIntent) GetDatetimeMentioned() string {
 	return ""
 }
 
+// StructuredFact is a durable fact about the user (name, preference, etc.)
+type StructuredFact struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Fact category: name, preference, profile, relationship, other
+	Category string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	// Short standalone statement, e.g. "Prefers replies in Spanish"
+	Content       string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StructuredFact) Reset() {
+	*x = StructuredFact{}
+	mi := &file_airborne_v1_common_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StructuredFact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructuredFact) ProtoMessage() {}
+
+func (x *StructuredFact) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructuredFact.ProtoReflect.Descriptor instead.
+func (*StructuredFact) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StructuredFact) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *StructuredFact) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// SafetySettings sets a threshold per harm category. Gemini applies them as
+// native safety settings, OpenAI as moderation cutoffs on the prompt.
+type SafetySettings struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Harassment       SafetyThreshold        `protobuf:"varint,1,opt,name=harassment,proto3,enum=airborne.v1.SafetyThreshold" json:"harassment,omitempty"`
+	HateSpeech       SafetyThreshold        `protobuf:"varint,2,opt,name=hate_speech,json=hateSpeech,proto3,enum=airborne.v1.SafetyThreshold" json:"hate_speech,omitempty"`
+	SexuallyExplicit SafetyThreshold        `protobuf:"varint,3,opt,name=sexually_explicit,json=sexuallyExplicit,proto3,enum=airborne.v1.SafetyThreshold" json:"sexually_explicit,omitempty"`
+	DangerousContent SafetyThreshold        `protobuf:"varint,4,opt,name=dangerous_content,json=dangerousContent,proto3,enum=airborne.v1.SafetyThreshold" json:"dangerous_content,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SafetySettings) Reset() {
+	*x = SafetySettings{}
+	mi := &file_airborne_v1_common_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SafetySettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SafetySettings) ProtoMessage() {}
+
+func (x *SafetySettings) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SafetySettings.ProtoReflect.Descriptor instead.
+func (*SafetySettings) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SafetySettings) GetHarassment() SafetyThreshold {
+	if x != nil {
+		return x.Harassment
+	}
+	return SafetyThreshold_SAFETY_THRESHOLD_UNSPECIFIED
+}
+
+func (x *SafetySettings) GetHateSpeech() SafetyThreshold {
+	if x != nil {
+		return x.HateSpeech
+	}
+	return SafetyThreshold_SAFETY_THRESHOLD_UNSPECIFIED
+}
+
+func (x *SafetySettings) GetSexuallyExplicit() SafetyThreshold {
+	if x != nil {
+		return x.SexuallyExplicit
+	}
+	return SafetyThreshold_SAFETY_THRESHOLD_UNSPECIFIED
+}
+
+func (x *SafetySettings) GetDangerousContent() SafetyThreshold {
+	if x != nil {
+		return x.DangerousContent
+	}
+	return SafetyThreshold_SAFETY_THRESHOLD_UNSPECIFIED
+}
+
+// SafetyBlock describes content a provider's safety filters blocked
+type SafetyBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`       // "prompt" or "response"
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"` // harassment, hate_speech, sexually_explicit, dangerous_content; empty if unknown
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SafetyBlock) Reset() {
+	*x = SafetyBlock{}
+	mi := &file_airborne_v1_common_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SafetyBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SafetyBlock) ProtoMessage() {}
+
+func (x *SafetyBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SafetyBlock.ProtoReflect.Descriptor instead.
+func (*SafetyBlock) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SafetyBlock) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *SafetyBlock) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *SafetyBlock) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ComputerUse enables the provider's native computer-use tool (OpenAI and
+// Anthropic). The model returns actions for an external executor to perform.
+type ComputerUse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DisplayWidth  int32                  `protobuf:"varint,1,opt,name=display_width,json=displayWidth,proto3" json:"display_width,omitempty"`    // Screen width in pixels
+	DisplayHeight int32                  `protobuf:"varint,2,opt,name=display_height,json=displayHeight,proto3" json:"display_height,omitempty"` // Screen height in pixels
+	Environment   string                 `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`                           // "browser" (default), "mac", "windows" or "ubuntu"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputerUse) Reset() {
+	*x = ComputerUse{}
+	mi := &file_airborne_v1_common_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputerUse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputerUse) ProtoMessage() {}
+
+func (x *ComputerUse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputerUse.ProtoReflect.Descriptor instead.
+func (*ComputerUse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ComputerUse) GetDisplayWidth() int32 {
+	if x != nil {
+		return x.DisplayWidth
+	}
+	return 0
+}
+
+func (x *ComputerUse) GetDisplayHeight() int32 {
+	if x != nil {
+		return x.DisplayHeight
+	}
+	return 0
+}
+
+func (x *ComputerUse) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+// ComputerAction is an action the model wants performed on the computer.
+// The executor performs it and answers with a ToolResult whose tool_call_id
+// is id and whose screenshot shows the result.
+type ComputerAction struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action              string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`                                                        // Action type, e.g. "click", "type", "screenshot"
+	Input               string                 `protobuf:"bytes,3,opt,name=input,proto3" json:"input,omitempty"`                                                          // Provider-specific action parameters as JSON
+	PendingSafetyChecks []*ComputerSafetyCheck `protobuf:"bytes,4,rep,name=pending_safety_checks,json=pendingSafetyChecks,proto3" json:"pending_safety_checks,omitempty"` // Must be acknowledged to continue (OpenAI)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ComputerAction) Reset() {
+	*x = ComputerAction{}
+	mi := &file_airborne_v1_common_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputerAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputerAction) ProtoMessage() {}
+
+func (x *ComputerAction) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputerAction.ProtoReflect.Descriptor instead.
+func (*ComputerAction) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ComputerAction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ComputerAction) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ComputerAction) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *ComputerAction) GetPendingSafetyChecks() []*ComputerSafetyCheck {
+	if x != nil {
+		return x.PendingSafetyChecks
+	}
+	return nil
+}
+
+// ComputerSafetyCheck is a provider safety check raised for a computer action
+type ComputerSafetyCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputerSafetyCheck) Reset() {
+	*x = ComputerSafetyCheck{}
+	mi := &file_airborne_v1_common_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputerSafetyCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputerSafetyCheck) ProtoMessage() {}
+
+func (x *ComputerSafetyCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_common_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputerSafetyCheck.ProtoReflect.Descriptor instead.
+func (*ComputerSafetyCheck) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_common_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ComputerSafetyCheck) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ComputerSafetyCheck) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ComputerSafetyCheck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_airborne_v1_common_proto protoreflect.FileDescriptor
 
 const file_airborne_v1_common_proto_rawDesc = "" +
@@ -1087,7 +1757,18 @@ const file_airborne_v1_common_proto_rawDesc = "" +
 	"\aMessage\x12\x12\n" +
 	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1c\n" +
-	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\"r\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\"_\n" +
+	"\n" +
+	"Attachment\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\"l\n" +
+	"\n" +
+	"ImageInput\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tmime_type\x18\x03 \x01(\tR\bmimeType\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\"r\n" +
 	"\x05Usage\x12!\n" +
 	"\finput_tokens\x18\x01 \x01(\x03R\vinputTokens\x12#\n" +
 	"\routput_tokens\x18\x02 \x01(\x03R\foutputTokens\x12!\n" +
@@ -1133,24 +1814,30 @@ const file_airborne_v1_common_proto_rawDesc = "" +
 	"\bToolCall\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
-	"\targuments\x18\x03 \x01(\tR\targuments\"a\n" +
+	"\targuments\x18\x03 \x01(\tR\targuments\"\x93\x02\n" +
 	"\n" +
 	"ToolResult\x12 \n" +
 	"\ftool_call_id\x18\x01 \x01(\tR\n" +
 	"toolCallId\x12\x16\n" +
 	"\x06output\x18\x02 \x01(\tR\x06output\x12\x19\n" +
-	"\bis_error\x18\x03 \x01(\bR\aisError\"\xc4\x01\n" +
+	"\bis_error\x18\x03 \x01(\bR\aisError\x12\x1e\n" +
+	"\n" +
+	"screenshot\x18\x04 \x01(\fR\n" +
+	"screenshot\x120\n" +
+	"\x14screenshot_mime_type\x18\x05 \x01(\tR\x12screenshotMimeType\x12^\n" +
+	"\x1aacknowledged_safety_checks\x18\x06 \x03(\v2 .airborne.v1.ComputerSafetyCheckR\x18acknowledgedSafetyChecks\"\xc4\x01\n" +
 	"\x13CodeExecutionResult\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1a\n" +
 	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x16\n" +
 	"\x06stdout\x18\x03 \x01(\tR\x06stdout\x12\x16\n" +
 	"\x06stderr\x18\x04 \x01(\tR\x06stderr\x12\x1b\n" +
 	"\texit_code\x18\x05 \x01(\x05R\bexitCode\x120\n" +
-	"\x05files\x18\x06 \x03(\v2\x1a.airborne.v1.GeneratedFileR\x05files\"Z\n" +
+	"\x05files\x18\x06 \x03(\v2\x1a.airborne.v1.GeneratedFileR\x05files\"l\n" +
 	"\rGeneratedFile\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
 	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\fR\acontent\"\xf0\x01\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"\xa3\x02\n" +
 	"\x12StructuredMetadata\x12\x16\n" +
 	"\x06intent\x18\x01 \x01(\tR\x06intent\x120\n" +
 	"\x14requires_user_action\x18\x02 \x01(\bR\x12requiresUserAction\x129\n" +
@@ -1158,13 +1845,47 @@ const file_airborne_v1_common_proto_rawDesc = "" +
 	"\x06topics\x18\x04 \x03(\tR\x06topics\x12=\n" +
 	"\n" +
 	"scheduling\x18\x05 \x01(\v2\x1d.airborne.v1.SchedulingIntentR\n" +
-	"scheduling\":\n" +
+	"scheduling\x121\n" +
+	"\x05facts\x18\x06 \x03(\v2\x1b.airborne.v1.StructuredFactR\x05facts\":\n" +
 	"\x10StructuredEntity\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\"]\n" +
 	"\x10SchedulingIntent\x12\x1a\n" +
 	"\bdetected\x18\x01 \x01(\bR\bdetected\x12-\n" +
-	"\x12datetime_mentioned\x18\x02 \x01(\tR\x11datetimeMentioned*\xc9\x04\n" +
+	"\x12datetime_mentioned\x18\x02 \x01(\tR\x11datetimeMentioned\"F\n" +
+	"\x0eStructuredFact\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\xa3\x02\n" +
+	"\x0eSafetySettings\x12<\n" +
+	"\n" +
+	"harassment\x18\x01 \x01(\x0e2\x1c.airborne.v1.SafetyThresholdR\n" +
+	"harassment\x12=\n" +
+	"\vhate_speech\x18\x02 \x01(\x0e2\x1c.airborne.v1.SafetyThresholdR\n" +
+	"hateSpeech\x12I\n" +
+	"\x11sexually_explicit\x18\x03 \x01(\x0e2\x1c.airborne.v1.SafetyThresholdR\x10sexuallyExplicit\x12I\n" +
+	"\x11dangerous_content\x18\x04 \x01(\x0e2\x1c.airborne.v1.SafetyThresholdR\x10dangerousContent\"W\n" +
+	"\vSafetyBlock\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"{\n" +
+	"\vComputerUse\x12#\n" +
+	"\rdisplay_width\x18\x01 \x01(\x05R\fdisplayWidth\x12%\n" +
+	"\x0edisplay_height\x18\x02 \x01(\x05R\rdisplayHeight\x12 \n" +
+	"\venvironment\x18\x03 \x01(\tR\venvironment\"\xa4\x01\n" +
+	"\x0eComputerAction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x14\n" +
+	"\x05input\x18\x03 \x01(\tR\x05input\x12T\n" +
+	"\x15pending_safety_checks\x18\x04 \x03(\v2 .airborne.v1.ComputerSafetyCheckR\x13pendingSafetyChecks\"S\n" +
+	"\x13ComputerSafetyCheck\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage*k\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14PRIORITY_INTERACTIVE\x10\x01\x12\x12\n" +
+	"\x0ePRIORITY_BATCH\x10\x02\x12\x17\n" +
+	"\x13PRIORITY_BACKGROUND\x10\x03*\xc9\x04\n" +
 	"\bProvider\x12\x18\n" +
 	"\x14PROVIDER_UNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fPROVIDER_OPENAI\x10\x01\x12\x13\n" +
@@ -1191,7 +1912,13 @@ const file_airborne_v1_common_proto_rawDesc = "" +
 	"\x10PROVIDER_UPSTAGE\x10+\x12\x13\n" +
 	"\x0fPROVIDER_NEBIUS\x10,\x12\x15\n" +
 	"\x11PROVIDER_CEREBRAS\x10-\x12\x14\n" +
-	"\x10PROVIDER_MINIMAX\x10.B\xa8\x01\n" +
+	"\x10PROVIDER_MINIMAX\x10.*\xd1\x01\n" +
+	"\x0fSafetyThreshold\x12 \n" +
+	"\x1cSAFETY_THRESHOLD_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bSAFETY_THRESHOLD_BLOCK_NONE\x10\x01\x12$\n" +
+	" SAFETY_THRESHOLD_BLOCK_ONLY_HIGH\x10\x02\x12+\n" +
+	"'SAFETY_THRESHOLD_BLOCK_MEDIUM_AND_ABOVE\x10\x03\x12(\n" +
+	"$SAFETY_THRESHOLD_BLOCK_LOW_AND_ABOVE\x10\x04B\xa8\x01\n" +
 	"\x0fcom.airborne.v1B\vCommonProtoP\x01Z;github.com/ai8future/airborne/gen/go/airborne/v1;airbornev1\xa2\x02\x03AXX\xaa\x02\vAirborne.V1\xca\x02\vAirborne\\V1\xe2\x02\x17Airborne\\V1\\GPBMetadata\xea\x02\fAirborne::V1b\x06proto3"
 
 var (
@@ -1206,36 +1933,53 @@ func file_airborne_v1_common_proto_rawDescGZIP() []byte {
 	return file_airborne_v1_common_proto_rawDescData
 }
 
-var file_airborne_v1_common_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_airborne_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_airborne_v1_common_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_airborne_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_airborne_v1_common_proto_goTypes = []any{
-	(Provider)(0),               // 0: airborne.v1.Provider
-	(Citation_Type)(0),          // 1: airborne.v1.Citation.Type
-	(*Message)(nil),             // 2: airborne.v1.Message
-	(*Usage)(nil),               // 3: airborne.v1.Usage
-	(*Citation)(nil),            // 4: airborne.v1.Citation
-	(*ProviderConfig)(nil),      // 5: airborne.v1.ProviderConfig
-	(*Tool)(nil),                // 6: airborne.v1.Tool
-	(*ToolCall)(nil),            // 7: airborne.v1.ToolCall
-	(*ToolResult)(nil),          // 8: airborne.v1.ToolResult
-	(*CodeExecutionResult)(nil), // 9: airborne.v1.CodeExecutionResult
-	(*GeneratedFile)(nil),       // 10: airborne.v1.GeneratedFile
-	(*StructuredMetadata)(nil),  // 11: airborne.v1.StructuredMetadata
-	(*StructuredEntity)(nil),    // 12: airborne.v1.StructuredEntity
-	(*SchedulingIntent)(nil),    // 13: airborne.v1.SchedulingIntent
-	nil,                         // 14: airborne.v1.ProviderConfig.ExtraOptionsEntry
+	(Priority)(0),               // 0: airborne.v1.Priority
+	(Provider)(0),               // 1: airborne.v1.Provider
+	(SafetyThreshold)(0),        // 2: airborne.v1.SafetyThreshold
+	(Citation_Type)(0),          // 3: airborne.v1.Citation.Type
+	(*Message)(nil),             // 4: airborne.v1.Message
+	(*Attachment)(nil),          // 5: airborne.v1.Attachment
+	(*ImageInput)(nil),          // 6: airborne.v1.ImageInput
+	(*Usage)(nil),               // 7: airborne.v1.Usage
+	(*Citation)(nil),            // 8: airborne.v1.Citation
+	(*ProviderConfig)(nil),      // 9: airborne.v1.ProviderConfig
+	(*Tool)(nil),                // 10: airborne.v1.Tool
+	(*ToolCall)(nil),            // 11: airborne.v1.ToolCall
+	(*ToolResult)(nil),          // 12: airborne.v1.ToolResult
+	(*CodeExecutionResult)(nil), // 13: airborne.v1.CodeExecutionResult
+	(*GeneratedFile)(nil),       // 14: airborne.v1.GeneratedFile
+	(*StructuredMetadata)(nil),  // 15: airborne.v1.StructuredMetadata
+	(*StructuredEntity)(nil),    // 16: airborne.v1.StructuredEntity
+	(*SchedulingIntent)(nil),    // 17: airborne.v1.SchedulingIntent
+	(*StructuredFact)(nil),      // 18: airborne.v1.StructuredFact
+	(*SafetySettings)(nil),      // 19: airborne.v1.SafetySettings
+	(*SafetyBlock)(nil),         // 20: airborne.v1.SafetyBlock
+	(*ComputerUse)(nil),         // 21: airborne.v1.ComputerUse
+	(*ComputerAction)(nil),      // 22: airborne.v1.ComputerAction
+	(*ComputerSafetyCheck)(nil), // 23: airborne.v1.ComputerSafetyCheck
+	nil,                         // 24: airborne.v1.ProviderConfig.ExtraOptionsEntry
 }
 var file_airborne_v1_common_proto_depIdxs = []int32{
-	1,  // 0: airborne.v1.Citation.type:type_name -> airborne.v1.Citation.Type
-	14, // 1: airborne.v1.ProviderConfig.extra_options:type_name -> airborne.v1.ProviderConfig.ExtraOptionsEntry
-	10, // 2: airborne.v1.CodeExecutionResult.files:type_name -> airborne.v1.GeneratedFile
-	12, // 3: airborne.v1.StructuredMetadata.entities:type_name -> airborne.v1.StructuredEntity
-	13, // 4: airborne.v1.StructuredMetadata.scheduling:type_name -> airborne.v1.SchedulingIntent
-	5,  // [5:5] is the sub-list for method output_type
-	5,  // [5:5] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	3,  // 0: airborne.v1.Citation.type:type_name -> airborne.v1.Citation.Type
+	24, // 1: airborne.v1.ProviderConfig.extra_options:type_name -> airborne.v1.ProviderConfig.ExtraOptionsEntry
+	23, // 2: airborne.v1.ToolResult.acknowledged_safety_checks:type_name -> airborne.v1.ComputerSafetyCheck
+	14, // 3: airborne.v1.CodeExecutionResult.files:type_name -> airborne.v1.GeneratedFile
+	16, // 4: airborne.v1.StructuredMetadata.entities:type_name -> airborne.v1.StructuredEntity
+	17, // 5: airborne.v1.StructuredMetadata.scheduling:type_name -> airborne.v1.SchedulingIntent
+	18, // 6: airborne.v1.StructuredMetadata.facts:type_name -> airborne.v1.StructuredFact
+	2,  // 7: airborne.v1.SafetySettings.harassment:type_name -> airborne.v1.SafetyThreshold
+	2,  // 8: airborne.v1.SafetySettings.hate_speech:type_name -> airborne.v1.SafetyThreshold
+	2,  // 9: airborne.v1.SafetySettings.sexually_explicit:type_name -> airborne.v1.SafetyThreshold
+	2,  // 10: airborne.v1.SafetySettings.dangerous_content:type_name -> airborne.v1.SafetyThreshold
+	23, // 11: airborne.v1.ComputerAction.pending_safety_checks:type_name -> airborne.v1.ComputerSafetyCheck
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_airborne_v1_common_proto_init() }
@@ -1243,14 +1987,14 @@ func file_airborne_v1_common_proto_init() {
 	if File_airborne_v1_common_proto != nil {
 		return
 	}
-	file_airborne_v1_common_proto_msgTypes[3].OneofWrappers = []any{}
+	file_airborne_v1_common_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_airborne_v1_common_proto_rawDesc), len(file_airborne_v1_common_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   13,
+			NumEnums:      4,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -253,13 +253,19 @@ func (*UploadFileRequest_Chunk) isUploadFileRequest_Data() {}
 
 // UploadFileMetadata describes the file being uploaded
 type UploadFileMetadata struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`               // Target store ID
-	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`                            // Original filename
-	MimeType      string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`            // MIME type (e.g., "application/pdf")
-	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`                                   // File size in bytes
-	Provider      Provider               `protobuf:"varint,5,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"` // Provider for this store
-	Config        *ProviderConfig        `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`                                // Provider configuration
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StoreId        string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`                       // Target store ID
+	Filename       string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`                                    // Original filename
+	MimeType       string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                    // MIME type (e.g., "application/pdf")
+	Size           int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`                                           // File size in bytes
+	Provider       Provider               `protobuf:"varint,5,opt,name=provider,proto3,enum=airborne.v1.Provider" json:"provider,omitempty"`         // Provider for this store
+	Config         *ProviderConfig        `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`                                        // Provider configuration
+	MirrorInternal bool                   `protobuf:"varint,7,opt,name=mirror_internal,json=mirrorInternal,proto3" json:"mirror_internal,omitempty"` // Also ingest an OpenAI/Gemini upload into the internal store with the same ID
+	// Limit retrieval of the file's chunks to these client IDs and members of
+	// these groups (see the client key's groups). Internal stores only; empty
+	// leaves the file visible to every client of the tenant.
+	AllowedUsers  []string `protobuf:"bytes,8,rep,name=allowed_users,json=allowedUsers,proto3" json:"allowed_users,omitempty"`
+	AllowedGroups []string `protobuf:"bytes,9,rep,name=allowed_groups,json=allowedGroups,proto3" json:"allowed_groups,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -336,13 +342,36 @@ func (x *UploadFileMetadata) GetConfig() *ProviderConfig {
 	return nil
 }
 
+func (x *UploadFileMetadata) GetMirrorInternal() bool {
+	if x != nil {
+		return x.MirrorInternal
+	}
+	return false
+}
+
+func (x *UploadFileMetadata) GetAllowedUsers() []string {
+	if x != nil {
+		return x.AllowedUsers
+	}
+	return nil
+}
+
+func (x *UploadFileMetadata) GetAllowedGroups() []string {
+	if x != nil {
+		return x.AllowedGroups
+	}
+	return nil
+}
+
 // UploadFileResponse contains the uploaded file info
 type UploadFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`    // Provider's file ID
-	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`              // Original filename
-	StoreId       string                 `protobuf:"bytes,3,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"` // Store it was added to
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                  // "processing", "ready", "failed"
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`                   // Provider's file ID
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`                             // Original filename
+	StoreId       string                 `protobuf:"bytes,3,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`                // Store it was added to
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                 // "processing", "ready", "failed"
+	Duplicate     bool                   `protobuf:"varint,5,opt,name=duplicate,proto3" json:"duplicate,omitempty"`                          // Identical content was already in the store; file_id is the existing file
+	MirrorStatus  string                 `protobuf:"bytes,6,opt,name=mirror_status,json=mirrorStatus,proto3" json:"mirror_status,omitempty"` // Internal mirror of a provider upload: "ready", "failed", or empty when not mirrored
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -405,6 +434,20 @@ func (x *UploadFileResponse) GetStatus() string {
 	return ""
 }
 
+func (x *UploadFileResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *UploadFileResponse) GetMirrorStatus() string {
+	if x != nil {
+		return x.MirrorStatus
+	}
+	return ""
+}
+
 // DeleteFileStoreRequest deletes a store
 type DeleteFileStoreRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -904,164 +947,1834 @@ func (x *FileStoreSummary) GetCreatedAt() string {
 	return ""
 }
 
-var File_airborne_v1_files_proto protoreflect.FileDescriptor
+// RetrieveRequest searches an internal store for chunks similar to a query
+type RetrieveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	TopK          int32                  `protobuf:"varint,3,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"` // Chunks to return (default: server retrieval_top_k, max 100)
+	Filter        *RetrieveFilter        `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`          // Optional restrictions on the chunks searched
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_airborne_v1_files_proto_rawDesc = "" +
-	"\n" +
-	"\x17airborne/v1/files.proto\x12\vairborne.v1\x1a\x18airborne/v1/common.proto\"\xda\x01\n" +
-	"\x16CreateFileStoreRequest\x121\n" +
-	"\bprovider\x18\x01 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
-	"\tclient_id\x18\x03 \x01(\tR\bclientId\x123\n" +
-	"\x06config\x18\x04 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12'\n" +
-	"\x0fexpiration_days\x18\x05 \x01(\x05R\x0eexpirationDays\"\x9a\x01\n" +
-	"\x17CreateFileStoreResponse\x12\x19\n" +
-	"\bstore_id\x18\x01 \x01(\tR\astoreId\x121\n" +
-	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\tR\tcreatedAt\"r\n" +
-	"\x11UploadFileRequest\x12=\n" +
-	"\bmetadata\x18\x01 \x01(\v2\x1f.airborne.v1.UploadFileMetadataH\x00R\bmetadata\x12\x16\n" +
-	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
-	"\x04data\"\xe4\x01\n" +
-	"\x12UploadFileMetadata\x12\x19\n" +
-	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x1a\n" +
-	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x1b\n" +
-	"\tmime_type\x18\x03 \x01(\tR\bmimeType\x12\x12\n" +
-	"\x04size\x18\x04 \x01(\x03R\x04size\x121\n" +
-	"\bprovider\x18\x05 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
-	"\x06config\x18\x06 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\"|\n" +
-	"\x12UploadFileResponse\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1a\n" +
-	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x19\n" +
-	"\bstore_id\x18\x03 \x01(\tR\astoreId\x12\x16\n" +
-	"\x06status\x18\x04 \x01(\tR\x06status\"\xb1\x01\n" +
-	"\x16DeleteFileStoreRequest\x12\x19\n" +
-	"\bstore_id\x18\x01 \x01(\tR\astoreId\x121\n" +
-	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
-	"\x06config\x18\x03 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12\x14\n" +
-	"\x05force\x18\x04 \x01(\bR\x05force\"M\n" +
-	"\x17DeleteFileStoreResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\x98\x01\n" +
-	"\x13GetFileStoreRequest\x12\x19\n" +
-	"\bstore_id\x18\x01 \x01(\tR\astoreId\x121\n" +
-	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
-	"\x06config\x18\x03 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\"\x8e\x02\n" +
-	"\x14GetFileStoreResponse\x12\x19\n" +
-	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x121\n" +
-	"\bprovider\x18\x03 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x1d\n" +
-	"\n" +
-	"file_count\x18\x04 \x01(\x05R\tfileCount\x12\x1f\n" +
-	"\vtotal_bytes\x18\x05 \x01(\x03R\n" +
-	"totalBytes\x12\x16\n" +
-	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"expires_at\x18\b \x01(\tR\texpiresAt\"\xd1\x01\n" +
-	"\x15ListFileStoresRequest\x12\x1b\n" +
-	"\tclient_id\x18\x01 \x01(\tR\bclientId\x121\n" +
-	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
-	"\x06config\x18\x03 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12\x14\n" +
-	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1d\n" +
-	"\n" +
-	"page_token\x18\x05 \x01(\tR\tpageToken\"w\n" +
-	"\x16ListFileStoresResponse\x125\n" +
-	"\x06stores\x18\x01 \x03(\v2\x1d.airborne.v1.FileStoreSummaryR\x06stores\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xca\x01\n" +
-	"\x10FileStoreSummary\x12\x19\n" +
-	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x121\n" +
-	"\bprovider\x18\x03 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x1d\n" +
-	"\n" +
-	"file_count\x18\x04 \x01(\x05R\tfileCount\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\tR\tcreatedAt2\xca\x03\n" +
-	"\vFileService\x12\\\n" +
-	"\x0fCreateFileStore\x12#.airborne.v1.CreateFileStoreRequest\x1a$.airborne.v1.CreateFileStoreResponse\x12O\n" +
-	"\n" +
-	"UploadFile\x12\x1e.airborne.v1.UploadFileRequest\x1a\x1f.airborne.v1.UploadFileResponse(\x01\x12\\\n" +
-	"\x0fDeleteFileStore\x12#.airborne.v1.DeleteFileStoreRequest\x1a$.airborne.v1.DeleteFileStoreResponse\x12S\n" +
-	"\fGetFileStore\x12 .airborne.v1.GetFileStoreRequest\x1a!.airborne.v1.GetFileStoreResponse\x12Y\n" +
-	"\x0eListFileStores\x12\".airborne.v1.ListFileStoresRequest\x1a#.airborne.v1.ListFileStoresResponseB\xa7\x01\n" +
-	"\x0fcom.airborne.v1B\n" +
-	"FilesProtoP\x01Z;github.com/ai8future/airborne/gen/go/airborne/v1;airbornev1\xa2\x02\x03AXX\xaa\x02\vAirborne.V1\xca\x02\vAirborne\\V1\xe2\x02\x17Airborne\\V1\\GPBMetadata\xea\x02\fAirborne::V1b\x06proto3"
+func (x *RetrieveRequest) Reset() {
+	*x = RetrieveRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-var (
-	file_airborne_v1_files_proto_rawDescOnce sync.Once
-	file_airborne_v1_files_proto_rawDescData []byte
-)
+func (x *RetrieveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_airborne_v1_files_proto_rawDescGZIP() []byte {
-	file_airborne_v1_files_proto_rawDescOnce.Do(func() {
-		file_airborne_v1_files_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_airborne_v1_files_proto_rawDesc), len(file_airborne_v1_files_proto_rawDesc)))
-	})
-	return file_airborne_v1_files_proto_rawDescData
+func (*RetrieveRequest) ProtoMessage() {}
+
+func (x *RetrieveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_airborne_v1_files_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
-var file_airborne_v1_files_proto_goTypes = []any{
-	(*CreateFileStoreRequest)(nil),  // 0: airborne.v1.CreateFileStoreRequest
-	(*CreateFileStoreResponse)(nil), // 1: airborne.v1.CreateFileStoreResponse
-	(*UploadFileRequest)(nil),       // 2: airborne.v1.UploadFileRequest
-	(*UploadFileMetadata)(nil),      // 3: airborne.v1.UploadFileMetadata
-	(*UploadFileResponse)(nil),      // 4: airborne.v1.UploadFileResponse
-	(*DeleteFileStoreRequest)(nil),  // 5: airborne.v1.DeleteFileStoreRequest
-	(*DeleteFileStoreResponse)(nil), // 6: airborne.v1.DeleteFileStoreResponse
-	(*GetFileStoreRequest)(nil),     // 7: airborne.v1.GetFileStoreRequest
-	(*GetFileStoreResponse)(nil),    // 8: airborne.v1.GetFileStoreResponse
-	(*ListFileStoresRequest)(nil),   // 9: airborne.v1.ListFileStoresRequest
-	(*ListFileStoresResponse)(nil),  // 10: airborne.v1.ListFileStoresResponse
-	(*FileStoreSummary)(nil),        // 11: airborne.v1.FileStoreSummary
-	(Provider)(0),                   // 12: airborne.v1.Provider
-	(*ProviderConfig)(nil),          // 13: airborne.v1.ProviderConfig
+// Deprecated: Use RetrieveRequest.ProtoReflect.Descriptor instead.
+func (*RetrieveRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{12}
 }
-var file_airborne_v1_files_proto_depIdxs = []int32{
-	12, // 0: airborne.v1.CreateFileStoreRequest.provider:type_name -> airborne.v1.Provider
-	13, // 1: airborne.v1.CreateFileStoreRequest.config:type_name -> airborne.v1.ProviderConfig
-	12, // 2: airborne.v1.CreateFileStoreResponse.provider:type_name -> airborne.v1.Provider
-	3,  // 3: airborne.v1.UploadFileRequest.metadata:type_name -> airborne.v1.UploadFileMetadata
-	12, // 4: airborne.v1.UploadFileMetadata.provider:type_name -> airborne.v1.Provider
-	13, // 5: airborne.v1.UploadFileMetadata.config:type_name -> airborne.v1.ProviderConfig
-	12, // 6: airborne.v1.DeleteFileStoreRequest.provider:type_name -> airborne.v1.Provider
-	13, // 7: airborne.v1.DeleteFileStoreRequest.config:type_name -> airborne.v1.ProviderConfig
-	12, // 8: airborne.v1.GetFileStoreRequest.provider:type_name -> airborne.v1.Provider
-	13, // 9: airborne.v1.GetFileStoreRequest.config:type_name -> airborne.v1.ProviderConfig
-	12, // 10: airborne.v1.GetFileStoreResponse.provider:type_name -> airborne.v1.Provider
-	12, // 11: airborne.v1.ListFileStoresRequest.provider:type_name -> airborne.v1.Provider
-	13, // 12: airborne.v1.ListFileStoresRequest.config:type_name -> airborne.v1.ProviderConfig
-	11, // 13: airborne.v1.ListFileStoresResponse.stores:type_name -> airborne.v1.FileStoreSummary
-	12, // 14: airborne.v1.FileStoreSummary.provider:type_name -> airborne.v1.Provider
-	0,  // 15: airborne.v1.FileService.CreateFileStore:input_type -> airborne.v1.CreateFileStoreRequest
-	2,  // 16: airborne.v1.FileService.UploadFile:input_type -> airborne.v1.UploadFileRequest
-	5,  // 17: airborne.v1.FileService.DeleteFileStore:input_type -> airborne.v1.DeleteFileStoreRequest
-	7,  // 18: airborne.v1.FileService.GetFileStore:input_type -> airborne.v1.GetFileStoreRequest
-	9,  // 19: airborne.v1.FileService.ListFileStores:input_type -> airborne.v1.ListFileStoresRequest
-	1,  // 20: airborne.v1.FileService.CreateFileStore:output_type -> airborne.v1.CreateFileStoreResponse
-	4,  // 21: airborne.v1.FileService.UploadFile:output_type -> airborne.v1.UploadFileResponse
-	6,  // 22: airborne.v1.FileService.DeleteFileStore:output_type -> airborne.v1.DeleteFileStoreResponse
-	8,  // 23: airborne.v1.FileService.GetFileStore:output_type -> airborne.v1.GetFileStoreResponse
-	10, // 24: airborne.v1.FileService.ListFileStores:output_type -> airborne.v1.ListFileStoresResponse
-	20, // [20:25] is the sub-list for method output_type
-	15, // [15:20] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+
+func (x *RetrieveRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
 }
 
-func init() { file_airborne_v1_files_proto_init() }
-func file_airborne_v1_files_proto_init() {
-	if File_airborne_v1_files_proto != nil {
-		return
+func (x *RetrieveRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
 	}
-	file_airborne_v1_common_proto_init()
-	file_airborne_v1_files_proto_msgTypes[2].OneofWrappers = []any{
-		(*UploadFileRequest_Metadata)(nil),
-		(*UploadFileRequest_Chunk)(nil),
+	return ""
+}
+
+func (x *RetrieveRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *RetrieveRequest) GetFilter() *RetrieveFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// RetrieveFilter restricts a similarity search. Set fields must all match.
+type RetrieveFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`   // Files uploaded in this thread
+	FileId        string                 `protobuf:"bytes,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`         // A single file
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`                   // Files with this name
+	MinScore      float32                `protobuf:"fixed32,4,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"` // Drop chunks scoring below this similarity
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetrieveFilter) Reset() {
+	*x = RetrieveFilter{}
+	mi := &file_airborne_v1_files_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrieveFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveFilter) ProtoMessage() {}
+
+func (x *RetrieveFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveFilter.ProtoReflect.Descriptor instead.
+func (*RetrieveFilter) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RetrieveFilter) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *RetrieveFilter) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *RetrieveFilter) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *RetrieveFilter) GetMinScore() float32 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+// RetrieveResponse contains matching chunks, most similar first
+type RetrieveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunks        []*RetrievedChunk      `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetrieveResponse) Reset() {
+	*x = RetrieveResponse{}
+	mi := &file_airborne_v1_files_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrieveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveResponse) ProtoMessage() {}
+
+func (x *RetrieveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveResponse.ProtoReflect.Descriptor instead.
+func (*RetrieveResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RetrieveResponse) GetChunks() []*RetrievedChunk {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+// RetrievedChunk is a stored chunk with its similarity score
+type RetrievedChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Score         float32                `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`
+	FileId        string                 `protobuf:"bytes,3,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,5,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	ChunkIndex    int32                  `protobuf:"varint,6,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"`
+	CharStart     int32                  `protobuf:"varint,7,opt,name=char_start,json=charStart,proto3" json:"char_start,omitempty"` // Offsets of the chunk in the file's extracted text
+	CharEnd       int32                  `protobuf:"varint,8,opt,name=char_end,json=charEnd,proto3" json:"char_end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetrievedChunk) Reset() {
+	*x = RetrievedChunk{}
+	mi := &file_airborne_v1_files_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrievedChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrievedChunk) ProtoMessage() {}
+
+func (x *RetrievedChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrievedChunk.ProtoReflect.Descriptor instead.
+func (*RetrievedChunk) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RetrievedChunk) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *RetrievedChunk) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *RetrievedChunk) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *RetrievedChunk) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *RetrievedChunk) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *RetrievedChunk) GetChunkIndex() int32 {
+	if x != nil {
+		return x.ChunkIndex
+	}
+	return 0
+}
+
+func (x *RetrievedChunk) GetCharStart() int32 {
+	if x != nil {
+		return x.CharStart
+	}
+	return 0
+}
+
+func (x *RetrievedChunk) GetCharEnd() int32 {
+	if x != nil {
+		return x.CharEnd
+	}
+	return 0
+}
+
+// ExtractTextRequest previews text extraction for a file
+type ExtractTextRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`   // File contents (max 100MB)
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"` // Used to detect the format
+	MimeType      string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	MaxTextChars  int32                  `protobuf:"varint,4,opt,name=max_text_chars,json=maxTextChars,proto3" json:"max_text_chars,omitempty"` // Truncate the returned text (0 = full text)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractTextRequest) Reset() {
+	*x = ExtractTextRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractTextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractTextRequest) ProtoMessage() {}
+
+func (x *ExtractTextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractTextRequest.ProtoReflect.Descriptor instead.
+func (*ExtractTextRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExtractTextRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ExtractTextRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExtractTextRequest) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *ExtractTextRequest) GetMaxTextChars() int32 {
+	if x != nil {
+		return x.MaxTextChars
+	}
+	return 0
+}
+
+// ExtractTextResponse is the extracted text and its detected structure
+type ExtractTextResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // text was cut at max_text_chars
+	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`        // Format reported by the extractor, if any
+	Fallback      bool                   `protobuf:"varint,4,opt,name=fallback,proto3" json:"fallback,omitempty"`   // The format is unsupported and was read as plain text
+	PageCount     int32                  `protobuf:"varint,5,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`
+	CharCount     int32                  `protobuf:"varint,6,opt,name=char_count,json=charCount,proto3" json:"char_count,omitempty"` // Counts cover the full text, not the truncated one
+	WordCount     int32                  `protobuf:"varint,7,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`
+	ChunkCount    int32                  `protobuf:"varint,8,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"` // Chunks the text would be split into on upload
+	Structure     *DocumentStructure     `protobuf:"bytes,9,opt,name=structure,proto3" json:"structure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractTextResponse) Reset() {
+	*x = ExtractTextResponse{}
+	mi := &file_airborne_v1_files_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractTextResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractTextResponse) ProtoMessage() {}
+
+func (x *ExtractTextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractTextResponse.ProtoReflect.Descriptor instead.
+func (*ExtractTextResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExtractTextResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ExtractTextResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ExtractTextResponse) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExtractTextResponse) GetFallback() bool {
+	if x != nil {
+		return x.Fallback
+	}
+	return false
+}
+
+func (x *ExtractTextResponse) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *ExtractTextResponse) GetCharCount() int32 {
+	if x != nil {
+		return x.CharCount
+	}
+	return 0
+}
+
+func (x *ExtractTextResponse) GetWordCount() int32 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *ExtractTextResponse) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+func (x *ExtractTextResponse) GetStructure() *DocumentStructure {
+	if x != nil {
+		return x.Structure
+	}
+	return nil
+}
+
+// DocumentStructure summarizes the layout detected in extracted text
+type DocumentStructure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Headings      []*DocumentHeading     `protobuf:"bytes,1,rep,name=headings,proto3" json:"headings,omitempty"`
+	Paragraphs    int32                  `protobuf:"varint,2,opt,name=paragraphs,proto3" json:"paragraphs,omitempty"`
+	ListItems     int32                  `protobuf:"varint,3,opt,name=list_items,json=listItems,proto3" json:"list_items,omitempty"`
+	Tables        int32                  `protobuf:"varint,4,opt,name=tables,proto3" json:"tables,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentStructure) Reset() {
+	*x = DocumentStructure{}
+	mi := &file_airborne_v1_files_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentStructure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentStructure) ProtoMessage() {}
+
+func (x *DocumentStructure) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentStructure.ProtoReflect.Descriptor instead.
+func (*DocumentStructure) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DocumentStructure) GetHeadings() []*DocumentHeading {
+	if x != nil {
+		return x.Headings
+	}
+	return nil
+}
+
+func (x *DocumentStructure) GetParagraphs() int32 {
+	if x != nil {
+		return x.Paragraphs
+	}
+	return 0
+}
+
+func (x *DocumentStructure) GetListItems() int32 {
+	if x != nil {
+		return x.ListItems
+	}
+	return 0
+}
+
+func (x *DocumentStructure) GetTables() int32 {
+	if x != nil {
+		return x.Tables
+	}
+	return 0
+}
+
+// DocumentHeading is a heading found in extracted text
+type DocumentHeading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         int32                  `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"` // 1 for top-level headings
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // Byte offset of the heading in the text
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentHeading) Reset() {
+	*x = DocumentHeading{}
+	mi := &file_airborne_v1_files_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentHeading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentHeading) ProtoMessage() {}
+
+func (x *DocumentHeading) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentHeading.ProtoReflect.Descriptor instead.
+func (*DocumentHeading) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DocumentHeading) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *DocumentHeading) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DocumentHeading) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// StartReindexRequest starts re-embedding an internal store
+type StartReindexRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartReindexRequest) Reset() {
+	*x = StartReindexRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartReindexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartReindexRequest) ProtoMessage() {}
+
+func (x *StartReindexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartReindexRequest.ProtoReflect.Descriptor instead.
+func (*StartReindexRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StartReindexRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+// GetReindexStatusRequest asks for a store's re-index status
+type GetReindexStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReindexStatusRequest) Reset() {
+	*x = GetReindexStatusRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReindexStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReindexStatusRequest) ProtoMessage() {}
+
+func (x *GetReindexStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReindexStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetReindexStatusRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetReindexStatusRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+// ReindexStatus is the progress of a store's latest re-index job
+type ReindexStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	StoreId         string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // "running", "completed", "failed"; empty if never re-indexed
+	Model           string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"` // Embedding model the job re-embeds with
+	TotalChunks     int32                  `protobuf:"varint,4,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"`
+	ReindexedChunks int32                  `protobuf:"varint,5,opt,name=reindexed_chunks,json=reindexedChunks,proto3" json:"reindexed_chunks,omitempty"`
+	Error           string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                                     // Why a failed job stopped
+	StartedAt       string                 `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`            // ISO 8601 timestamp
+	FinishedAt      string                 `protobuf:"bytes,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`         // Empty while running
+	StoredModel     string                 `protobuf:"bytes,9,opt,name=stored_model,json=storedModel,proto3" json:"stored_model,omitempty"`      // Embedding model recorded on the stored chunks, if known
+	NeedsReindex    bool                   `protobuf:"varint,10,opt,name=needs_reindex,json=needsReindex,proto3" json:"needs_reindex,omitempty"` // Stored vectors don't match the current embedding model
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReindexStatus) Reset() {
+	*x = ReindexStatus{}
+	mi := &file_airborne_v1_files_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReindexStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReindexStatus) ProtoMessage() {}
+
+func (x *ReindexStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReindexStatus.ProtoReflect.Descriptor instead.
+func (*ReindexStatus) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReindexStatus) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetTotalChunks() int32 {
+	if x != nil {
+		return x.TotalChunks
+	}
+	return 0
+}
+
+func (x *ReindexStatus) GetReindexedChunks() int32 {
+	if x != nil {
+		return x.ReindexedChunks
+	}
+	return 0
+}
+
+func (x *ReindexStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetStoredModel() string {
+	if x != nil {
+		return x.StoredModel
+	}
+	return ""
+}
+
+func (x *ReindexStatus) GetNeedsReindex() bool {
+	if x != nil {
+		return x.NeedsReindex
+	}
+	return false
+}
+
+// ExportStoreRequest exports an internal store
+type ExportStoreRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StoreId        string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	IncludeVectors bool                   `protobuf:"varint,2,opt,name=include_vectors,json=includeVectors,proto3" json:"include_vectors,omitempty"` // Include embeddings so imports with the same model skip re-embedding
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExportStoreRequest) Reset() {
+	*x = ExportStoreRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportStoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportStoreRequest) ProtoMessage() {}
+
+func (x *ExportStoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportStoreRequest.ProtoReflect.Descriptor instead.
+func (*ExportStoreRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExportStoreRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *ExportStoreRequest) GetIncludeVectors() bool {
+	if x != nil {
+		return x.IncludeVectors
+	}
+	return false
+}
+
+// ExportStoreChunk is a piece of a gzip-compressed snapshot archive
+type ExportStoreChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportStoreChunk) Reset() {
+	*x = ExportStoreChunk{}
+	mi := &file_airborne_v1_files_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportStoreChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportStoreChunk) ProtoMessage() {}
+
+func (x *ExportStoreChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportStoreChunk.ProtoReflect.Descriptor instead.
+func (*ExportStoreChunk) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportStoreChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ImportStoreRequest streams a snapshot archive into a store
+type ImportStoreRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*ImportStoreRequest_Metadata
+	//	*ImportStoreRequest_Chunk
+	Data          isImportStoreRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportStoreRequest) Reset() {
+	*x = ImportStoreRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportStoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportStoreRequest) ProtoMessage() {}
+
+func (x *ImportStoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportStoreRequest.ProtoReflect.Descriptor instead.
+func (*ImportStoreRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ImportStoreRequest) GetData() isImportStoreRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ImportStoreRequest) GetMetadata() *ImportStoreMetadata {
+	if x != nil {
+		if x, ok := x.Data.(*ImportStoreRequest_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *ImportStoreRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*ImportStoreRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isImportStoreRequest_Data interface {
+	isImportStoreRequest_Data()
+}
+
+type ImportStoreRequest_Metadata struct {
+	Metadata *ImportStoreMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"` // First message: import options
+}
+
+type ImportStoreRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // Subsequent messages: archive data
+}
+
+func (*ImportStoreRequest_Metadata) isImportStoreRequest_Data() {}
+
+func (*ImportStoreRequest_Chunk) isImportStoreRequest_Data() {}
+
+// ImportStoreMetadata configures an import
+type ImportStoreMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"` // Target store (default: the exported store's ID)
+	Overwrite     bool                   `protobuf:"varint,2,opt,name=overwrite,proto3" json:"overwrite,omitempty"`           // Replace the store if it already exists
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportStoreMetadata) Reset() {
+	*x = ImportStoreMetadata{}
+	mi := &file_airborne_v1_files_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportStoreMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportStoreMetadata) ProtoMessage() {}
+
+func (x *ImportStoreMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportStoreMetadata.ProtoReflect.Descriptor instead.
+func (*ImportStoreMetadata) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ImportStoreMetadata) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *ImportStoreMetadata) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+// ImportStoreResponse reports an import's outcome
+type ImportStoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	ChunkCount    int32                  `protobuf:"varint,2,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
+	Reembedded    bool                   `protobuf:"varint,3,opt,name=reembedded,proto3" json:"reembedded,omitempty"`                             // Vectors were regenerated with the current embedding model
+	SourceStoreId string                 `protobuf:"bytes,4,opt,name=source_store_id,json=sourceStoreId,proto3" json:"source_store_id,omitempty"` // Store the archive was exported from
+	SourceModel   string                 `protobuf:"bytes,5,opt,name=source_model,json=sourceModel,proto3" json:"source_model,omitempty"`         // Embedding model of the exported store
+	ExportedAt    string                 `protobuf:"bytes,6,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"`            // ISO 8601 timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportStoreResponse) Reset() {
+	*x = ImportStoreResponse{}
+	mi := &file_airborne_v1_files_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportStoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportStoreResponse) ProtoMessage() {}
+
+func (x *ImportStoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportStoreResponse.ProtoReflect.Descriptor instead.
+func (*ImportStoreResponse) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ImportStoreResponse) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *ImportStoreResponse) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+func (x *ImportStoreResponse) GetReembedded() bool {
+	if x != nil {
+		return x.Reembedded
+	}
+	return false
+}
+
+func (x *ImportStoreResponse) GetSourceStoreId() string {
+	if x != nil {
+		return x.SourceStoreId
+	}
+	return ""
+}
+
+func (x *ImportStoreResponse) GetSourceModel() string {
+	if x != nil {
+		return x.SourceModel
+	}
+	return ""
+}
+
+func (x *ImportStoreResponse) GetExportedAt() string {
+	if x != nil {
+		return x.ExportedAt
+	}
+	return ""
+}
+
+// InitUploadRequest starts a resumable upload
+type InitUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *UploadFileMetadata    `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"` // size is required
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitUploadRequest) Reset() {
+	*x = InitUploadRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitUploadRequest) ProtoMessage() {}
+
+func (x *InitUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitUploadRequest.ProtoReflect.Descriptor instead.
+func (*InitUploadRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *InitUploadRequest) GetMetadata() *UploadFileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// UploadChunkRequest writes a piece of a resumable upload
+type UploadChunkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // Offset of data in the file; at most received_bytes
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadChunkRequest) Reset() {
+	*x = UploadChunkRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadChunkRequest) ProtoMessage() {}
+
+func (x *UploadChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadChunkRequest.ProtoReflect.Descriptor instead.
+func (*UploadChunkRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UploadChunkRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadChunkRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *UploadChunkRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// GetUploadStatusRequest asks for a resumable upload's progress
+type GetUploadStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadStatusRequest) Reset() {
+	*x = GetUploadStatusRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadStatusRequest) ProtoMessage() {}
+
+func (x *GetUploadStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadStatusRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetUploadStatusRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+// CompleteUploadRequest finishes a resumable upload
+type CompleteUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"` // Optional hex SHA-256 of the file, checked before storing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteUploadRequest) Reset() {
+	*x = CompleteUploadRequest{}
+	mi := &file_airborne_v1_files_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteUploadRequest) ProtoMessage() {}
+
+func (x *CompleteUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteUploadRequest.ProtoReflect.Descriptor instead.
+func (*CompleteUploadRequest) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CompleteUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *CompleteUploadRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+// UploadSession is the state of a resumable upload
+type UploadSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`                                        // Declared file size
+	ReceivedBytes int64                  `protobuf:"varint,3,opt,name=received_bytes,json=receivedBytes,proto3" json:"received_bytes,omitempty"` // Bytes received so far; resume from this offset
+	ExpiresAt     string                 `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`              // ISO 8601 timestamp; each chunk extends it
+	Progress      *UploadProgress        `protobuf:"bytes,5,opt,name=progress,proto3" json:"progress,omitempty"`                                 // Set once CompleteUpload starts
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadSession) Reset() {
+	*x = UploadSession{}
+	mi := &file_airborne_v1_files_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadSession) ProtoMessage() {}
+
+func (x *UploadSession) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadSession.ProtoReflect.Descriptor instead.
+func (*UploadSession) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UploadSession) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadSession) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadSession) GetReceivedBytes() int64 {
+	if x != nil {
+		return x.ReceivedBytes
+	}
+	return 0
+}
+
+func (x *UploadSession) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *UploadSession) GetProgress() *UploadProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+// UploadProgress reports how far an upload's processing has got
+type UploadProgress struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Stage          string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`                                         // "received", "uploading", "processing", "ready", "failed"
+	ProviderStatus string                 `protobuf:"bytes,2,opt,name=provider_status,json=providerStatus,proto3" json:"provider_status,omitempty"` // Provider's own status while processing, e.g. OpenAI "in_progress"
+	BytesReceived  int64                  `protobuf:"varint,3,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	UpdatedAt      string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // ISO 8601 timestamp
+	Result         *UploadFileResponse    `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`                        // Set when stage is "ready" or "failed"
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UploadProgress) Reset() {
+	*x = UploadProgress{}
+	mi := &file_airborne_v1_files_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadProgress) ProtoMessage() {}
+
+func (x *UploadProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_airborne_v1_files_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadProgress.ProtoReflect.Descriptor instead.
+func (*UploadProgress) Descriptor() ([]byte, []int) {
+	return file_airborne_v1_files_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UploadProgress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *UploadProgress) GetProviderStatus() string {
+	if x != nil {
+		return x.ProviderStatus
+	}
+	return ""
+}
+
+func (x *UploadProgress) GetBytesReceived() int64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *UploadProgress) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *UploadProgress) GetResult() *UploadFileResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+var File_airborne_v1_files_proto protoreflect.FileDescriptor
+
+const file_airborne_v1_files_proto_rawDesc = "" +
+	"\n" +
+	"\x17airborne/v1/files.proto\x12\vairborne.v1\x1a\x18airborne/v1/common.proto\"\xda\x01\n" +
+	"\x16CreateFileStoreRequest\x121\n" +
+	"\bprovider\x18\x01 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\x123\n" +
+	"\x06config\x18\x04 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12'\n" +
+	"\x0fexpiration_days\x18\x05 \x01(\x05R\x0eexpirationDays\"\x9a\x01\n" +
+	"\x17CreateFileStoreResponse\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x121\n" +
+	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"r\n" +
+	"\x11UploadFileRequest\x12=\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1f.airborne.v1.UploadFileMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"\xd9\x02\n" +
+	"\x12UploadFileMetadata\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x1b\n" +
+	"\tmime_type\x18\x03 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x121\n" +
+	"\bprovider\x18\x05 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
+	"\x06config\x18\x06 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12'\n" +
+	"\x0fmirror_internal\x18\a \x01(\bR\x0emirrorInternal\x12#\n" +
+	"\rallowed_users\x18\b \x03(\tR\fallowedUsers\x12%\n" +
+	"\x0eallowed_groups\x18\t \x03(\tR\rallowedGroups\"\xbf\x01\n" +
+	"\x12UploadFileResponse\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x19\n" +
+	"\bstore_id\x18\x03 \x01(\tR\astoreId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1c\n" +
+	"\tduplicate\x18\x05 \x01(\bR\tduplicate\x12#\n" +
+	"\rmirror_status\x18\x06 \x01(\tR\fmirrorStatus\"\xb1\x01\n" +
+	"\x16DeleteFileStoreRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x121\n" +
+	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
+	"\x06config\x18\x03 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12\x14\n" +
+	"\x05force\x18\x04 \x01(\bR\x05force\"M\n" +
+	"\x17DeleteFileStoreResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x98\x01\n" +
+	"\x13GetFileStoreRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x121\n" +
+	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
+	"\x06config\x18\x03 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\"\x8e\x02\n" +
+	"\x14GetFileStoreResponse\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x121\n" +
+	"\bprovider\x18\x03 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x1d\n" +
+	"\n" +
+	"file_count\x18\x04 \x01(\x05R\tfileCount\x12\x1f\n" +
+	"\vtotal_bytes\x18\x05 \x01(\x03R\n" +
+	"totalBytes\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\tR\texpiresAt\"\xd1\x01\n" +
+	"\x15ListFileStoresRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x121\n" +
+	"\bprovider\x18\x02 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x123\n" +
+	"\x06config\x18\x03 \x01(\v2\x1b.airborne.v1.ProviderConfigR\x06config\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"w\n" +
+	"\x16ListFileStoresResponse\x125\n" +
+	"\x06stores\x18\x01 \x03(\v2\x1d.airborne.v1.FileStoreSummaryR\x06stores\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xca\x01\n" +
+	"\x10FileStoreSummary\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x121\n" +
+	"\bprovider\x18\x03 \x01(\x0e2\x15.airborne.v1.ProviderR\bprovider\x12\x1d\n" +
+	"\n" +
+	"file_count\x18\x04 \x01(\x05R\tfileCount\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\"\x8c\x01\n" +
+	"\x0fRetrieveRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x13\n" +
+	"\x05top_k\x18\x03 \x01(\x05R\x04topK\x123\n" +
+	"\x06filter\x18\x04 \x01(\v2\x1b.airborne.v1.RetrieveFilterR\x06filter\"\x7f\n" +
+	"\x0eRetrieveFilter\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\tR\x06fileId\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12\x1b\n" +
+	"\tmin_score\x18\x04 \x01(\x02R\bminScore\"G\n" +
+	"\x10RetrieveResponse\x123\n" +
+	"\x06chunks\x18\x01 \x03(\v2\x1b.airborne.v1.RetrievedChunkR\x06chunks\"\xe7\x01\n" +
+	"\x0eRetrievedChunk\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x02R\x05score\x12\x17\n" +
+	"\afile_id\x18\x03 \x01(\tR\x06fileId\x12\x1a\n" +
+	"\bfilename\x18\x04 \x01(\tR\bfilename\x12\x1b\n" +
+	"\tthread_id\x18\x05 \x01(\tR\bthreadId\x12\x1f\n" +
+	"\vchunk_index\x18\x06 \x01(\x05R\n" +
+	"chunkIndex\x12\x1d\n" +
+	"\n" +
+	"char_start\x18\a \x01(\x05R\tcharStart\x12\x19\n" +
+	"\bchar_end\x18\b \x01(\x05R\acharEnd\"\x8d\x01\n" +
+	"\x12ExtractTextRequest\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x1b\n" +
+	"\tmime_type\x18\x03 \x01(\tR\bmimeType\x12$\n" +
+	"\x0emax_text_chars\x18\x04 \x01(\x05R\fmaxTextChars\"\xb7\x02\n" +
+	"\x13ExtractTextResponse\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x1a\n" +
+	"\bfallback\x18\x04 \x01(\bR\bfallback\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\x05 \x01(\x05R\tpageCount\x12\x1d\n" +
+	"\n" +
+	"char_count\x18\x06 \x01(\x05R\tcharCount\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\a \x01(\x05R\twordCount\x12\x1f\n" +
+	"\vchunk_count\x18\b \x01(\x05R\n" +
+	"chunkCount\x12<\n" +
+	"\tstructure\x18\t \x01(\v2\x1e.airborne.v1.DocumentStructureR\tstructure\"\xa4\x01\n" +
+	"\x11DocumentStructure\x128\n" +
+	"\bheadings\x18\x01 \x03(\v2\x1c.airborne.v1.DocumentHeadingR\bheadings\x12\x1e\n" +
+	"\n" +
+	"paragraphs\x18\x02 \x01(\x05R\n" +
+	"paragraphs\x12\x1d\n" +
+	"\n" +
+	"list_items\x18\x03 \x01(\x05R\tlistItems\x12\x16\n" +
+	"\x06tables\x18\x04 \x01(\x05R\x06tables\"S\n" +
+	"\x0fDocumentHeading\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\x05R\x05level\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"0\n" +
+	"\x13StartReindexRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\"4\n" +
+	"\x17GetReindexStatusRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\"\xc2\x02\n" +
+	"\rReindexStatus\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12!\n" +
+	"\ftotal_chunks\x18\x04 \x01(\x05R\vtotalChunks\x12)\n" +
+	"\x10reindexed_chunks\x18\x05 \x01(\x05R\x0freindexedChunks\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\a \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\b \x01(\tR\n" +
+	"finishedAt\x12!\n" +
+	"\fstored_model\x18\t \x01(\tR\vstoredModel\x12#\n" +
+	"\rneeds_reindex\x18\n" +
+	" \x01(\bR\fneedsReindex\"X\n" +
+	"\x12ExportStoreRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12'\n" +
+	"\x0finclude_vectors\x18\x02 \x01(\bR\x0eincludeVectors\"&\n" +
+	"\x10ExportStoreChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"t\n" +
+	"\x12ImportStoreRequest\x12>\n" +
+	"\bmetadata\x18\x01 \x01(\v2 .airborne.v1.ImportStoreMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"N\n" +
+	"\x13ImportStoreMetadata\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x1c\n" +
+	"\toverwrite\x18\x02 \x01(\bR\toverwrite\"\xdd\x01\n" +
+	"\x13ImportStoreResponse\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x1f\n" +
+	"\vchunk_count\x18\x02 \x01(\x05R\n" +
+	"chunkCount\x12\x1e\n" +
+	"\n" +
+	"reembedded\x18\x03 \x01(\bR\n" +
+	"reembedded\x12&\n" +
+	"\x0fsource_store_id\x18\x04 \x01(\tR\rsourceStoreId\x12!\n" +
+	"\fsource_model\x18\x05 \x01(\tR\vsourceModel\x12\x1f\n" +
+	"\vexported_at\x18\x06 \x01(\tR\n" +
+	"exportedAt\"P\n" +
+	"\x11InitUploadRequest\x12;\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1f.airborne.v1.UploadFileMetadataR\bmetadata\"]\n" +
+	"\x12UploadChunkRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"5\n" +
+	"\x16GetUploadStatusRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\"L\n" +
+	"\x15CompleteUploadRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\"\xbf\x01\n" +
+	"\rUploadSession\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12%\n" +
+	"\x0ereceived_bytes\x18\x03 \x01(\x03R\rreceivedBytes\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x127\n" +
+	"\bprogress\x18\x05 \x01(\v2\x1b.airborne.v1.UploadProgressR\bprogress\"\xce\x01\n" +
+	"\x0eUploadProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12'\n" +
+	"\x0fprovider_status\x18\x02 \x01(\tR\x0eproviderStatus\x12%\n" +
+	"\x0ebytes_received\x18\x03 \x01(\x03R\rbytesReceived\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\x127\n" +
+	"\x06result\x18\x05 \x01(\v2\x1f.airborne.v1.UploadFileResponseR\x06result2\xca\n" +
+	"\n" +
+	"\vFileService\x12\\\n" +
+	"\x0fCreateFileStore\x12#.airborne.v1.CreateFileStoreRequest\x1a$.airborne.v1.CreateFileStoreResponse\x12O\n" +
+	"\n" +
+	"UploadFile\x12\x1e.airborne.v1.UploadFileRequest\x1a\x1f.airborne.v1.UploadFileResponse(\x01\x12\\\n" +
+	"\x0fDeleteFileStore\x12#.airborne.v1.DeleteFileStoreRequest\x1a$.airborne.v1.DeleteFileStoreResponse\x12S\n" +
+	"\fGetFileStore\x12 .airborne.v1.GetFileStoreRequest\x1a!.airborne.v1.GetFileStoreResponse\x12Y\n" +
+	"\x0eListFileStores\x12\".airborne.v1.ListFileStoresRequest\x1a#.airborne.v1.ListFileStoresResponse\x12G\n" +
+	"\bRetrieve\x12\x1c.airborne.v1.RetrieveRequest\x1a\x1d.airborne.v1.RetrieveResponse\x12P\n" +
+	"\vExtractText\x12\x1f.airborne.v1.ExtractTextRequest\x1a .airborne.v1.ExtractTextResponse\x12L\n" +
+	"\fStartReindex\x12 .airborne.v1.StartReindexRequest\x1a\x1a.airborne.v1.ReindexStatus\x12T\n" +
+	"\x10GetReindexStatus\x12$.airborne.v1.GetReindexStatusRequest\x1a\x1a.airborne.v1.ReindexStatus\x12O\n" +
+	"\vExportStore\x12\x1f.airborne.v1.ExportStoreRequest\x1a\x1d.airborne.v1.ExportStoreChunk0\x01\x12R\n" +
+	"\vImportStore\x12\x1f.airborne.v1.ImportStoreRequest\x1a .airborne.v1.ImportStoreResponse(\x01\x12H\n" +
+	"\n" +
+	"InitUpload\x12\x1e.airborne.v1.InitUploadRequest\x1a\x1a.airborne.v1.UploadSession\x12J\n" +
+	"\vUploadChunk\x12\x1f.airborne.v1.UploadChunkRequest\x1a\x1a.airborne.v1.UploadSession\x12R\n" +
+	"\x0fGetUploadStatus\x12#.airborne.v1.GetUploadStatusRequest\x1a\x1a.airborne.v1.UploadSession\x12U\n" +
+	"\x0eCompleteUpload\x12\".airborne.v1.CompleteUploadRequest\x1a\x1f.airborne.v1.UploadFileResponse\x12Y\n" +
+	"\x16UploadFileWithProgress\x12\x1e.airborne.v1.UploadFileRequest\x1a\x1b.airborne.v1.UploadProgress(\x010\x01B\xa7\x01\n" +
+	"\x0fcom.airborne.v1B\n" +
+	"FilesProtoP\x01Z;github.com/ai8future/airborne/gen/go/airborne/v1;airbornev1\xa2\x02\x03AXX\xaa\x02\vAirborne.V1\xca\x02\vAirborne\\V1\xe2\x02\x17Airborne\\V1\\GPBMetadata\xea\x02\fAirborne::V1b\x06proto3"
+
+var (
+	file_airborne_v1_files_proto_rawDescOnce sync.Once
+	file_airborne_v1_files_proto_rawDescData []byte
+)
+
+func file_airborne_v1_files_proto_rawDescGZIP() []byte {
+	file_airborne_v1_files_proto_rawDescOnce.Do(func() {
+		file_airborne_v1_files_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_airborne_v1_files_proto_rawDesc), len(file_airborne_v1_files_proto_rawDesc)))
+	})
+	return file_airborne_v1_files_proto_rawDescData
+}
+
+var file_airborne_v1_files_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_airborne_v1_files_proto_goTypes = []any{
+	(*CreateFileStoreRequest)(nil),  // 0: airborne.v1.CreateFileStoreRequest
+	(*CreateFileStoreResponse)(nil), // 1: airborne.v1.CreateFileStoreResponse
+	(*UploadFileRequest)(nil),       // 2: airborne.v1.UploadFileRequest
+	(*UploadFileMetadata)(nil),      // 3: airborne.v1.UploadFileMetadata
+	(*UploadFileResponse)(nil),      // 4: airborne.v1.UploadFileResponse
+	(*DeleteFileStoreRequest)(nil),  // 5: airborne.v1.DeleteFileStoreRequest
+	(*DeleteFileStoreResponse)(nil), // 6: airborne.v1.DeleteFileStoreResponse
+	(*GetFileStoreRequest)(nil),     // 7: airborne.v1.GetFileStoreRequest
+	(*GetFileStoreResponse)(nil),    // 8: airborne.v1.GetFileStoreResponse
+	(*ListFileStoresRequest)(nil),   // 9: airborne.v1.ListFileStoresRequest
+	(*ListFileStoresResponse)(nil),  // 10: airborne.v1.ListFileStoresResponse
+	(*FileStoreSummary)(nil),        // 11: airborne.v1.FileStoreSummary
+	(*RetrieveRequest)(nil),         // 12: airborne.v1.RetrieveRequest
+	(*RetrieveFilter)(nil),          // 13: airborne.v1.RetrieveFilter
+	(*RetrieveResponse)(nil),        // 14: airborne.v1.RetrieveResponse
+	(*RetrievedChunk)(nil),          // 15: airborne.v1.RetrievedChunk
+	(*ExtractTextRequest)(nil),      // 16: airborne.v1.ExtractTextRequest
+	(*ExtractTextResponse)(nil),     // 17: airborne.v1.ExtractTextResponse
+	(*DocumentStructure)(nil),       // 18: airborne.v1.DocumentStructure
+	(*DocumentHeading)(nil),         // 19: airborne.v1.DocumentHeading
+	(*StartReindexRequest)(nil),     // 20: airborne.v1.StartReindexRequest
+	(*GetReindexStatusRequest)(nil), // 21: airborne.v1.GetReindexStatusRequest
+	(*ReindexStatus)(nil),           // 22: airborne.v1.ReindexStatus
+	(*ExportStoreRequest)(nil),      // 23: airborne.v1.ExportStoreRequest
+	(*ExportStoreChunk)(nil),        // 24: airborne.v1.ExportStoreChunk
+	(*ImportStoreRequest)(nil),      // 25: airborne.v1.ImportStoreRequest
+	(*ImportStoreMetadata)(nil),     // 26: airborne.v1.ImportStoreMetadata
+	(*ImportStoreResponse)(nil),     // 27: airborne.v1.ImportStoreResponse
+	(*InitUploadRequest)(nil),       // 28: airborne.v1.InitUploadRequest
+	(*UploadChunkRequest)(nil),      // 29: airborne.v1.UploadChunkRequest
+	(*GetUploadStatusRequest)(nil),  // 30: airborne.v1.GetUploadStatusRequest
+	(*CompleteUploadRequest)(nil),   // 31: airborne.v1.CompleteUploadRequest
+	(*UploadSession)(nil),           // 32: airborne.v1.UploadSession
+	(*UploadProgress)(nil),          // 33: airborne.v1.UploadProgress
+	(Provider)(0),                   // 34: airborne.v1.Provider
+	(*ProviderConfig)(nil),          // 35: airborne.v1.ProviderConfig
+}
+var file_airborne_v1_files_proto_depIdxs = []int32{
+	34, // 0: airborne.v1.CreateFileStoreRequest.provider:type_name -> airborne.v1.Provider
+	35, // 1: airborne.v1.CreateFileStoreRequest.config:type_name -> airborne.v1.ProviderConfig
+	34, // 2: airborne.v1.CreateFileStoreResponse.provider:type_name -> airborne.v1.Provider
+	3,  // 3: airborne.v1.UploadFileRequest.metadata:type_name -> airborne.v1.UploadFileMetadata
+	34, // 4: airborne.v1.UploadFileMetadata.provider:type_name -> airborne.v1.Provider
+	35, // 5: airborne.v1.UploadFileMetadata.config:type_name -> airborne.v1.ProviderConfig
+	34, // 6: airborne.v1.DeleteFileStoreRequest.provider:type_name -> airborne.v1.Provider
+	35, // 7: airborne.v1.DeleteFileStoreRequest.config:type_name -> airborne.v1.ProviderConfig
+	34, // 8: airborne.v1.GetFileStoreRequest.provider:type_name -> airborne.v1.Provider
+	35, // 9: airborne.v1.GetFileStoreRequest.config:type_name -> airborne.v1.ProviderConfig
+	34, // 10: airborne.v1.GetFileStoreResponse.provider:type_name -> airborne.v1.Provider
+	34, // 11: airborne.v1.ListFileStoresRequest.provider:type_name -> airborne.v1.Provider
+	35, // 12: airborne.v1.ListFileStoresRequest.config:type_name -> airborne.v1.ProviderConfig
+	11, // 13: airborne.v1.ListFileStoresResponse.stores:type_name -> airborne.v1.FileStoreSummary
+	34, // 14: airborne.v1.FileStoreSummary.provider:type_name -> airborne.v1.Provider
+	13, // 15: airborne.v1.RetrieveRequest.filter:type_name -> airborne.v1.RetrieveFilter
+	15, // 16: airborne.v1.RetrieveResponse.chunks:type_name -> airborne.v1.RetrievedChunk
+	18, // 17: airborne.v1.ExtractTextResponse.structure:type_name -> airborne.v1.DocumentStructure
+	19, // 18: airborne.v1.DocumentStructure.headings:type_name -> airborne.v1.DocumentHeading
+	26, // 19: airborne.v1.ImportStoreRequest.metadata:type_name -> airborne.v1.ImportStoreMetadata
+	3,  // 20: airborne.v1.InitUploadRequest.metadata:type_name -> airborne.v1.UploadFileMetadata
+	33, // 21: airborne.v1.UploadSession.progress:type_name -> airborne.v1.UploadProgress
+	4,  // 22: airborne.v1.UploadProgress.result:type_name -> airborne.v1.UploadFileResponse
+	0,  // 23: airborne.v1.FileService.CreateFileStore:input_type -> airborne.v1.CreateFileStoreRequest
+	2,  // 24: airborne.v1.FileService.UploadFile:input_type -> airborne.v1.UploadFileRequest
+	5,  // 25: airborne.v1.FileService.DeleteFileStore:input_type -> airborne.v1.DeleteFileStoreRequest
+	7,  // 26: airborne.v1.FileService.GetFileStore:input_type -> airborne.v1.GetFileStoreRequest
+	9,  // 27: airborne.v1.FileService.ListFileStores:input_type -> airborne.v1.ListFileStoresRequest
+	12, // 28: airborne.v1.FileService.Retrieve:input_type -> airborne.v1.RetrieveRequest
+	16, // 29: airborne.v1.FileService.ExtractText:input_type -> airborne.v1.ExtractTextRequest
+	20, // 30: airborne.v1.FileService.StartReindex:input_type -> airborne.v1.StartReindexRequest
+	21, // 31: airborne.v1.FileService.GetReindexStatus:input_type -> airborne.v1.GetReindexStatusRequest
+	23, // 32: airborne.v1.FileService.ExportStore:input_type -> airborne.v1.ExportStoreRequest
+	25, // 33: airborne.v1.FileService.ImportStore:input_type -> airborne.v1.ImportStoreRequest
+	28, // 34: airborne.v1.FileService.InitUpload:input_type -> airborne.v1.InitUploadRequest
+	29, // 35: airborne.v1.FileService.UploadChunk:input_type -> airborne.v1.UploadChunkRequest
+	30, // 36: airborne.v1.FileService.GetUploadStatus:input_type -> airborne.v1.GetUploadStatusRequest
+	31, // 37: airborne.v1.FileService.CompleteUpload:input_type -> airborne.v1.CompleteUploadRequest
+	2,  // 38: airborne.v1.FileService.UploadFileWithProgress:input_type -> airborne.v1.UploadFileRequest
+	1,  // 39: airborne.v1.FileService.CreateFileStore:output_type -> airborne.v1.CreateFileStoreResponse
+	4,  // 40: airborne.v1.FileService.UploadFile:output_type -> airborne.v1.UploadFileResponse
+	6,  // 41: airborne.v1.FileService.DeleteFileStore:output_type -> airborne.v1.DeleteFileStoreResponse
+	8,  // 42: airborne.v1.FileService.GetFileStore:output_type -> airborne.v1.GetFileStoreResponse
+	10, // 43: airborne.v1.FileService.ListFileStores:output_type -> airborne.v1.ListFileStoresResponse
+	14, // 44: airborne.v1.FileService.Retrieve:output_type -> airborne.v1.RetrieveResponse
+	17, // 45: airborne.v1.FileService.ExtractText:output_type -> airborne.v1.ExtractTextResponse
+	22, // 46: airborne.v1.FileService.StartReindex:output_type -> airborne.v1.ReindexStatus
+	22, // 47: airborne.v1.FileService.GetReindexStatus:output_type -> airborne.v1.ReindexStatus
+	24, // 48: airborne.v1.FileService.ExportStore:output_type -> airborne.v1.ExportStoreChunk
+	27, // 49: airborne.v1.FileService.ImportStore:output_type -> airborne.v1.ImportStoreResponse
+	32, // 50: airborne.v1.FileService.InitUpload:output_type -> airborne.v1.UploadSession
+	32, // 51: airborne.v1.FileService.UploadChunk:output_type -> airborne.v1.UploadSession
+	32, // 52: airborne.v1.FileService.GetUploadStatus:output_type -> airborne.v1.UploadSession
+	4,  // 53: airborne.v1.FileService.CompleteUpload:output_type -> airborne.v1.UploadFileResponse
+	33, // 54: airborne.v1.FileService.UploadFileWithProgress:output_type -> airborne.v1.UploadProgress
+	39, // [39:55] is the sub-list for method output_type
+	23, // [23:39] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_airborne_v1_files_proto_init() }
+func file_airborne_v1_files_proto_init() {
+	if File_airborne_v1_files_proto != nil {
+		return
+	}
+	file_airborne_v1_common_proto_init()
+	file_airborne_v1_files_proto_msgTypes[2].OneofWrappers = []any{
+		(*UploadFileRequest_Metadata)(nil),
+		(*UploadFileRequest_Chunk)(nil),
+	}
+	file_airborne_v1_files_proto_msgTypes[25].OneofWrappers = []any{
+		(*ImportStoreRequest_Metadata)(nil),
+		(*ImportStoreRequest_Chunk)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1069,7 +2782,7 @@ func file_airborne_v1_files_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_airborne_v1_files_proto_rawDesc), len(file_airborne_v1_files_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileService_CreateFileStore_FullMethodName = "/airborne.v1.FileService/CreateFileStore"
-	FileService_UploadFile_FullMethodName      = "/airborne.v1.FileService/UploadFile"
-	FileService_DeleteFileStore_FullMethodName = "/airborne.v1.FileService/DeleteFileStore"
-	FileService_GetFileStore_FullMethodName    = "/airborne.v1.FileService/GetFileStore"
-	FileService_ListFileStores_FullMethodName  = "/airborne.v1.FileService/ListFileStores"
+	FileService_CreateFileStore_FullMethodName        = "/airborne.v1.FileService/CreateFileStore"
+	FileService_UploadFile_FullMethodName             = "/airborne.v1.FileService/UploadFile"
+	FileService_DeleteFileStore_FullMethodName        = "/airborne.v1.FileService/DeleteFileStore"
+	FileService_GetFileStore_FullMethodName           = "/airborne.v1.FileService/GetFileStore"
+	FileService_ListFileStores_FullMethodName         = "/airborne.v1.FileService/ListFileStores"
+	FileService_Retrieve_FullMethodName               = "/airborne.v1.FileService/Retrieve"
+	FileService_ExtractText_FullMethodName            = "/airborne.v1.FileService/ExtractText"
+	FileService_StartReindex_FullMethodName           = "/airborne.v1.FileService/StartReindex"
+	FileService_GetReindexStatus_FullMethodName       = "/airborne.v1.FileService/GetReindexStatus"
+	FileService_ExportStore_FullMethodName            = "/airborne.v1.FileService/ExportStore"
+	FileService_ImportStore_FullMethodName            = "/airborne.v1.FileService/ImportStore"
+	FileService_InitUpload_FullMethodName             = "/airborne.v1.FileService/InitUpload"
+	FileService_UploadChunk_FullMethodName            = "/airborne.v1.FileService/UploadChunk"
+	FileService_GetUploadStatus_FullMethodName        = "/airborne.v1.FileService/GetUploadStatus"
+	FileService_CompleteUpload_FullMethodName         = "/airborne.v1.FileService/CompleteUpload"
+	FileService_UploadFileWithProgress_FullMethodName = "/airborne.v1.FileService/UploadFileWithProgress"
 )
 
 // FileServiceClient is the client API for FileService service.
@@ -42,6 +53,42 @@ type FileServiceClient interface {
 	GetFileStore(ctx context.Context, in *GetFileStoreRequest, opts ...grpc.CallOption) (*GetFileStoreResponse, error)
 	// ListFileStores lists all stores for a client
 	ListFileStores(ctx context.Context, in *ListFileStoresRequest, opts ...grpc.CallOption) (*ListFileStoresResponse, error)
+	// Retrieve runs a similarity search against an internal (RAG) store
+	Retrieve(ctx context.Context, in *RetrieveRequest, opts ...grpc.CallOption) (*RetrieveResponse, error)
+	// ExtractText runs the RAG extractor over a file without ingesting it, so
+	// extraction quality can be checked before uploading to a store
+	ExtractText(ctx context.Context, in *ExtractTextRequest, opts ...grpc.CallOption) (*ExtractTextResponse, error)
+	// StartReindex re-embeds every chunk of an internal store with the current
+	// embedding model in the background (requires admin permission)
+	StartReindex(ctx context.Context, in *StartReindexRequest, opts ...grpc.CallOption) (*ReindexStatus, error)
+	// GetReindexStatus reports re-index progress and whether a store's vectors
+	// match the current embedding model
+	GetReindexStatus(ctx context.Context, in *GetReindexStatusRequest, opts ...grpc.CallOption) (*ReindexStatus, error)
+	// ExportStore streams a portable snapshot archive of an internal store
+	// (requires admin permission)
+	ExportStore(ctx context.Context, in *ExportStoreRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportStoreChunk], error)
+	// ImportStore loads a snapshot archive into an internal store of the
+	// caller's tenant (client streaming; requires admin permission)
+	ImportStore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportStoreRequest, ImportStoreResponse], error)
+	// InitUpload starts a resumable upload. Send the file with UploadChunk and
+	// add it to the store with CompleteUpload; uploads left idle longer than
+	// the server's upload TTL are discarded
+	InitUpload(ctx context.Context, in *InitUploadRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	// UploadChunk writes file data at an offset of a resumable upload
+	UploadChunk(ctx context.Context, in *UploadChunkRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	// GetUploadStatus reports how much of a resumable upload the server has,
+	// so a client can resume after a dropped connection, and its processing
+	// progress once CompleteUpload has started
+	GetUploadStatus(ctx context.Context, in *GetUploadStatusRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	// CompleteUpload adds a fully received resumable upload to its store.
+	// Repeating it after success returns the same result until the upload
+	// expires
+	CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*UploadFileResponse, error)
+	// UploadFileWithProgress is UploadFile reporting progress: send metadata
+	// and chunks as for UploadFile, then close the send side. The server sends
+	// an UploadProgress for each processing stage and provider status change;
+	// the last one carries the result
+	UploadFileWithProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UploadFileRequest, UploadProgress], error)
 }
 
 type fileServiceClient struct {
@@ -105,6 +152,131 @@ func (c *fileServiceClient) ListFileStores(ctx context.Context, in *ListFileStor
 	return out, nil
 }
 
+func (c *fileServiceClient) Retrieve(ctx context.Context, in *RetrieveRequest, opts ...grpc.CallOption) (*RetrieveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetrieveResponse)
+	err := c.cc.Invoke(ctx, FileService_Retrieve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) ExtractText(ctx context.Context, in *ExtractTextRequest, opts ...grpc.CallOption) (*ExtractTextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtractTextResponse)
+	err := c.cc.Invoke(ctx, FileService_ExtractText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) StartReindex(ctx context.Context, in *StartReindexRequest, opts ...grpc.CallOption) (*ReindexStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReindexStatus)
+	err := c.cc.Invoke(ctx, FileService_StartReindex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) GetReindexStatus(ctx context.Context, in *GetReindexStatusRequest, opts ...grpc.CallOption) (*ReindexStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReindexStatus)
+	err := c.cc.Invoke(ctx, FileService_GetReindexStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) ExportStore(ctx context.Context, in *ExportStoreRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportStoreChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileService_ServiceDesc.Streams[1], FileService_ExportStore_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportStoreRequest, ExportStoreChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileService_ExportStoreClient = grpc.ServerStreamingClient[ExportStoreChunk]
+
+func (c *fileServiceClient) ImportStore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportStoreRequest, ImportStoreResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileService_ServiceDesc.Streams[2], FileService_ImportStore_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportStoreRequest, ImportStoreResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileService_ImportStoreClient = grpc.ClientStreamingClient[ImportStoreRequest, ImportStoreResponse]
+
+func (c *fileServiceClient) InitUpload(ctx context.Context, in *InitUploadRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, FileService_InitUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) UploadChunk(ctx context.Context, in *UploadChunkRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, FileService_UploadChunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) GetUploadStatus(ctx context.Context, in *GetUploadStatusRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, FileService_GetUploadStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*UploadFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadFileResponse)
+	err := c.cc.Invoke(ctx, FileService_CompleteUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) UploadFileWithProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UploadFileRequest, UploadProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileService_ServiceDesc.Streams[3], FileService_UploadFileWithProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadFileRequest, UploadProgress]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileService_UploadFileWithProgressClient = grpc.BidiStreamingClient[UploadFileRequest, UploadProgress]
+
 // FileServiceServer is the server API for FileService service.
 // All implementations must embed UnimplementedFileServiceServer
 // for forward compatibility.
@@ -121,6 +293,42 @@ type FileServiceServer interface {
 	GetFileStore(context.Context, *GetFileStoreRequest) (*GetFileStoreResponse, error)
 	// ListFileStores lists all stores for a client
 	ListFileStores(context.Context, *ListFileStoresRequest) (*ListFileStoresResponse, error)
+	// Retrieve runs a similarity search against an internal (RAG) store
+	Retrieve(context.Context, *RetrieveRequest) (*RetrieveResponse, error)
+	// ExtractText runs the RAG extractor over a file without ingesting it, so
+	// extraction quality can be checked before uploading to a store
+	ExtractText(context.Context, *ExtractTextRequest) (*ExtractTextResponse, error)
+	// StartReindex re-embeds every chunk of an internal store with the current
+	// embedding model in the background (requires admin permission)
+	StartReindex(context.Context, *StartReindexRequest) (*ReindexStatus, error)
+	// GetReindexStatus reports re-index progress and whether a store's vectors
+	// match the current embedding model
+	GetReindexStatus(context.Context, *GetReindexStatusRequest) (*ReindexStatus, error)
+	// ExportStore streams a portable snapshot archive of an internal store
+	// (requires admin permission)
+	ExportStore(*ExportStoreRequest, grpc.ServerStreamingServer[ExportStoreChunk]) error
+	// ImportStore loads a snapshot archive into an internal store of the
+	// caller's tenant (client streaming; requires admin permission)
+	ImportStore(grpc.ClientStreamingServer[ImportStoreRequest, ImportStoreResponse]) error
+	// InitUpload starts a resumable upload. Send the file with UploadChunk and
+	// add it to the store with CompleteUpload; uploads left idle longer than
+	// the server's upload TTL are discarded
+	InitUpload(context.Context, *InitUploadRequest) (*UploadSession, error)
+	// UploadChunk writes file data at an offset of a resumable upload
+	UploadChunk(context.Context, *UploadChunkRequest) (*UploadSession, error)
+	// GetUploadStatus reports how much of a resumable upload the server has,
+	// so a client can resume after a dropped connection, and its processing
+	// progress once CompleteUpload has started
+	GetUploadStatus(context.Context, *GetUploadStatusRequest) (*UploadSession, error)
+	// CompleteUpload adds a fully received resumable upload to its store.
+	// Repeating it after success returns the same result until the upload
+	// expires
+	CompleteUpload(context.Context, *CompleteUploadRequest) (*UploadFileResponse, error)
+	// UploadFileWithProgress is UploadFile reporting progress: send metadata
+	// and chunks as for UploadFile, then close the send side. The server sends
+	// an UploadProgress for each processing stage and provider status change;
+	// the last one carries the result
+	UploadFileWithProgress(grpc.BidiStreamingServer[UploadFileRequest, UploadProgress]) error
 	mustEmbedUnimplementedFileServiceServer()
 }
 
@@ -146,6 +354,39 @@ func (UnimplementedFileServiceServer) GetFileStore(context.Context, *GetFileStor
 func (UnimplementedFileServiceServer) ListFileStores(context.Context, *ListFileStoresRequest) (*ListFileStoresResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListFileStores not implemented")
 }
+func (UnimplementedFileServiceServer) Retrieve(context.Context, *RetrieveRequest) (*RetrieveResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Retrieve not implemented")
+}
+func (UnimplementedFileServiceServer) ExtractText(context.Context, *ExtractTextRequest) (*ExtractTextResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExtractText not implemented")
+}
+func (UnimplementedFileServiceServer) StartReindex(context.Context, *StartReindexRequest) (*ReindexStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartReindex not implemented")
+}
+func (UnimplementedFileServiceServer) GetReindexStatus(context.Context, *GetReindexStatusRequest) (*ReindexStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetReindexStatus not implemented")
+}
+func (UnimplementedFileServiceServer) ExportStore(*ExportStoreRequest, grpc.ServerStreamingServer[ExportStoreChunk]) error {
+	return status.Error(codes.Unimplemented, "method ExportStore not implemented")
+}
+func (UnimplementedFileServiceServer) ImportStore(grpc.ClientStreamingServer[ImportStoreRequest, ImportStoreResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportStore not implemented")
+}
+func (UnimplementedFileServiceServer) InitUpload(context.Context, *InitUploadRequest) (*UploadSession, error) {
+	return nil, status.Error(codes.Unimplemented, "method InitUpload not implemented")
+}
+func (UnimplementedFileServiceServer) UploadChunk(context.Context, *UploadChunkRequest) (*UploadSession, error) {
+	return nil, status.Error(codes.Unimplemented, "method UploadChunk not implemented")
+}
+func (UnimplementedFileServiceServer) GetUploadStatus(context.Context, *GetUploadStatusRequest) (*UploadSession, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUploadStatus not implemented")
+}
+func (UnimplementedFileServiceServer) CompleteUpload(context.Context, *CompleteUploadRequest) (*UploadFileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompleteUpload not implemented")
+}
+func (UnimplementedFileServiceServer) UploadFileWithProgress(grpc.BidiStreamingServer[UploadFileRequest, UploadProgress]) error {
+	return status.Error(codes.Unimplemented, "method UploadFileWithProgress not implemented")
+}
 func (UnimplementedFileServiceServer) mustEmbedUnimplementedFileServiceServer() {}
 func (UnimplementedFileServiceServer) testEmbeddedByValue()                     {}
 
@@ -246,6 +487,175 @@ func _FileService_ListFileStores_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileService_Retrieve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Retrieve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_Retrieve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Retrieve(ctx, req.(*RetrieveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_ExtractText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtractTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).ExtractText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_ExtractText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).ExtractText(ctx, req.(*ExtractTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_StartReindex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartReindexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).StartReindex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_StartReindex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).StartReindex(ctx, req.(*StartReindexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_GetReindexStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReindexStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).GetReindexStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_GetReindexStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).GetReindexStatus(ctx, req.(*GetReindexStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_ExportStore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportStoreRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServiceServer).ExportStore(m, &grpc.GenericServerStream[ExportStoreRequest, ExportStoreChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileService_ExportStoreServer = grpc.ServerStreamingServer[ExportStoreChunk]
+
+func _FileService_ImportStore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileServiceServer).ImportStore(&grpc.GenericServerStream[ImportStoreRequest, ImportStoreResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileService_ImportStoreServer = grpc.ClientStreamingServer[ImportStoreRequest, ImportStoreResponse]
+
+func _FileService_InitUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).InitUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_InitUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).InitUpload(ctx, req.(*InitUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_UploadChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).UploadChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_UploadChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).UploadChunk(ctx, req.(*UploadChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_GetUploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).GetUploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_GetUploadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).GetUploadStatus(ctx, req.(*GetUploadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_CompleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).CompleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_CompleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).CompleteUpload(ctx, req.(*CompleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_UploadFileWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileServiceServer).UploadFileWithProgress(&grpc.GenericServerStream[UploadFileRequest, UploadProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileService_UploadFileWithProgressServer = grpc.BidiStreamingServer[UploadFileRequest, UploadProgress]
+
 // FileService_ServiceDesc is the grpc.ServiceDesc for FileService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -269,6 +679,38 @@ var FileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListFileStores",
 			Handler:    _FileService_ListFileStores_Handler,
 		},
+		{
+			MethodName: "Retrieve",
+			Handler:    _FileService_Retrieve_Handler,
+		},
+		{
+			MethodName: "ExtractText",
+			Handler:    _FileService_ExtractText_Handler,
+		},
+		{
+			MethodName: "StartReindex",
+			Handler:    _FileService_StartReindex_Handler,
+		},
+		{
+			MethodName: "GetReindexStatus",
+			Handler:    _FileService_GetReindexStatus_Handler,
+		},
+		{
+			MethodName: "InitUpload",
+			Handler:    _FileService_InitUpload_Handler,
+		},
+		{
+			MethodName: "UploadChunk",
+			Handler:    _FileService_UploadChunk_Handler,
+		},
+		{
+			MethodName: "GetUploadStatus",
+			Handler:    _FileService_GetUploadStatus_Handler,
+		},
+		{
+			MethodName: "CompleteUpload",
+			Handler:    _FileService_CompleteUpload_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -276,6 +718,22 @@ var FileService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FileService_UploadFile_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ExportStore",
+			Handler:       _FileService_ExportStore_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportStore",
+			Handler:       _FileService_ImportStore_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadFileWithProgress",
+			Handler:       _FileService_UploadFileWithProgress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "airborne/v1/files.proto",
 }
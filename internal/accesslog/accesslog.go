@@ -0,0 +1,325 @@
+// Package accesslog provides gRPC interceptors that emit one structured access
+// log line per RPC and feed the same data to the metrics registry.
+package accesslog
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/logctx"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// healthMethod is only logged when it fails or is slow.
+const healthMethod = "/airborne.v1.AdminService/Health"
+
+// Config controls access log sampling.
+type Config struct {
+	// SampleRate is the fraction (0-1) of successful, fast RPCs that are logged.
+	// Failed and slow RPCs are always logged.
+	SampleRate float64
+
+	// SlowThreshold marks RPCs at or above this latency as slow (0 disables).
+	SlowThreshold time.Duration
+}
+
+// Logger emits access logs and records RPC metrics.
+type Logger struct {
+	cfg     Config
+	metrics *metrics.Registry
+	sample  func() float64
+}
+
+// New creates an access logger. The metrics registry is optional.
+func New(cfg Config, reg *metrics.Registry) *Logger {
+	if cfg.SampleRate < 0 {
+		cfg.SampleRate = 0
+	}
+	if cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+	return &Logger{
+		cfg:     cfg,
+		metrics: reg,
+		sample:  rand.Float64,
+	}
+}
+
+type contextKey struct{}
+
+// entry collects per-RPC fields that are only known deeper in the chain.
+type entry struct {
+	mu       sync.Mutex
+	traceID  string
+	tenantID string
+	clientID string
+	attrs    []any
+}
+
+func fromContext(ctx context.Context) *entry {
+	e, _ := ctx.Value(contextKey{}).(*entry)
+	return e
+}
+
+// Annotate adds key/value pairs to the current RPC's access log line.
+// Handlers use this instead of logging their own per-request summary.
+// It is a no-op when the context did not pass through the access logger.
+func Annotate(ctx context.Context, args ...any) {
+	e := fromContext(ctx)
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.attrs = append(e.attrs, args...)
+	e.mu.Unlock()
+}
+
+// capture records the authenticated tenant and client from ctx.
+func (e *entry) capture(ctx context.Context) {
+	tenantID := ""
+	if cfg := auth.TenantFromContext(ctx); cfg != nil {
+		tenantID = cfg.TenantID
+	}
+	clientID := ""
+	if client := auth.ClientFromContext(ctx); client != nil {
+		clientID = client.ClientID
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if tenantID != "" {
+		e.tenantID = tenantID
+	}
+	if clientID != "" {
+		e.clientID = clientID
+	}
+}
+
+// UnaryInterceptor logs unary RPCs. It should be the outermost logging interceptor
+// so that rejected requests are logged too.
+func (l *Logger) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		e := &entry{traceID: tracing.IDFromContext(ctx)}
+		ctx = context.WithValue(ctx, contextKey{}, e)
+
+		resp, err := handler(ctx, req)
+
+		l.finish(info.FullMethod, "gRPC request", e, req, start, err, messageSize(req), messageSize(resp))
+		return resp, err
+	}
+}
+
+// StreamInterceptor logs streaming RPCs with total bytes and message counts.
+func (l *Logger) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		e := &entry{traceID: tracing.IDFromContext(ss.Context())}
+		wrapped := &countingStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ss.Context(), contextKey{}, e),
+		}
+
+		err := handler(srv, wrapped)
+
+		wrapped.mu.Lock()
+		e.attrs = append(e.attrs,
+			"messages_received", wrapped.recvCount,
+			"messages_sent", wrapped.sendCount,
+		)
+		reqBytes, respBytes := wrapped.recvBytes, wrapped.sendBytes
+		first := wrapped.first
+		wrapped.mu.Unlock()
+
+		l.finish(info.FullMethod, "gRPC stream", e, first, start, err, reqBytes, respBytes)
+		return err
+	}
+}
+
+// UnaryAnnotator captures tenant and client identity for the access log and
+// attaches it to the handler's log lines. It must run after the tenant and
+// auth interceptors.
+func UnaryAnnotator() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if e := fromContext(ctx); e != nil {
+			e.capture(ctx)
+		}
+		return handler(withIdentity(ctx), req)
+	}
+}
+
+// StreamAnnotator captures tenant and client identity for the access log and
+// attaches it to the handler's log lines. Tenant resolution for streams can
+// happen on the first message, so identity is captured after the handler
+// returns and attached whenever the handler reads the stream's context.
+func StreamAnnotator() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, &identityStream{ServerStream: ss})
+		ctx := ss.Context()
+		if e := fromContext(ctx); e != nil {
+			e.capture(ctx)
+		}
+		return err
+	}
+}
+
+// withIdentity attaches the authenticated tenant and client to ctx's log
+// lines, and applies the tenant's privacy settings to them.
+func withIdentity(ctx context.Context) context.Context {
+	var args []any
+	if cfg := auth.TenantFromContext(ctx); cfg != nil {
+		args = append(args, logctx.KeyTenantID, cfg.TenantID)
+		ctx = logctx.WithContentMode(ctx, cfg.Privacy.LogContent)
+	}
+	if client := auth.ClientFromContext(ctx); client != nil {
+		args = append(args, logctx.KeyClientID, client.ClientID)
+	}
+	if len(args) == 0 {
+		return ctx
+	}
+	return logctx.With(ctx, args...)
+}
+
+// identityStream attaches the authenticated tenant and client to the log
+// lines of a stream's context.
+type identityStream struct {
+	grpc.ServerStream
+}
+
+func (s *identityStream) Context() context.Context {
+	return withIdentity(s.ServerStream.Context())
+}
+
+// finish emits the access log line and records metrics.
+func (l *Logger) finish(method, msg string, e *entry, req interface{}, start time.Time, err error, reqBytes, respBytes int) {
+	latency := time.Since(start)
+	code := status.Code(err)
+
+	e.mu.Lock()
+	tenantID, clientID := e.tenantID, e.clientID
+	extra := append([]any(nil), e.attrs...)
+	e.mu.Unlock()
+
+	// Metrics only use the authenticated tenant to keep label cardinality bounded
+	l.metrics.ObserveRPC(metrics.RPCObservation{
+		Method:        method,
+		TenantID:      tenantID,
+		Code:          code.String(),
+		Latency:       latency,
+		RequestBytes:  reqBytes,
+		ResponseBytes: respBytes,
+	})
+
+	// Fall back to the requested tenant for requests rejected before auth
+	if tenantID == "" {
+		if r, ok := req.(interface{ GetTenantId() string }); ok {
+			tenantID = r.GetTenantId()
+		}
+	}
+
+	slow := l.cfg.SlowThreshold > 0 && latency >= l.cfg.SlowThreshold
+	level, ok := l.level(method, code, slow)
+	if !ok {
+		return
+	}
+
+	args := []any{
+		"method", method,
+		"tenant_id", tenantID,
+		"client_id", clientID,
+		"code", code.String(),
+		"duration_ms", latency.Milliseconds(),
+		"request_bytes", reqBytes,
+		"response_bytes", respBytes,
+	}
+	if slow {
+		args = append(args, "slow", true)
+	}
+	if level == slog.LevelInfo && l.cfg.SampleRate < 1 {
+		args = append(args, "sample_rate", l.cfg.SampleRate)
+	}
+	if e.traceID != "" {
+		args = append(args, tracing.LogKey, e.traceID)
+	}
+	if err != nil && code != codes.OK {
+		args = append(args, "error", status.Convert(err).Message())
+	}
+	args = append(args, extra...)
+
+	slog.Log(context.Background(), level, msg, args...)
+}
+
+// level decides whether and at which level an RPC is logged.
+func (l *Logger) level(method string, code codes.Code, slow bool) (slog.Level, bool) {
+	switch {
+	case code != codes.OK || slow:
+		return slog.LevelWarn, true
+	case method == healthMethod:
+		return 0, false
+	case l.cfg.SampleRate >= 1:
+		return slog.LevelInfo, true
+	case l.cfg.SampleRate <= 0:
+		return 0, false
+	default:
+		return slog.LevelInfo, l.sample() < l.cfg.SampleRate
+	}
+}
+
+// messageSize returns the wire size of a protobuf message, or 0 for non-proto values.
+func messageSize(m interface{}) int {
+	if msg, ok := m.(proto.Message); ok && msg != nil {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+// countingStream wraps a ServerStream to count messages and bytes.
+type countingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	mu        sync.Mutex
+	first     interface{} // first received message, for tenant fallback
+	recvCount int
+	sendCount int
+	recvBytes int
+	sendBytes int
+}
+
+func (s *countingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.mu.Lock()
+		if s.first == nil {
+			s.first = m
+		}
+		s.recvCount++
+		s.recvBytes += messageSize(m)
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.mu.Lock()
+		s.sendCount++
+		s.sendBytes += messageSize(m)
+		s.mu.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,279 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/logctx"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// captureLogs redirects the default slog logger to a buffer for the duration of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+// runUnary runs the outer logger, an identity injector and the annotator around handler.
+func runUnary(t *testing.T, l *Logger, method string, req interface{}, handler grpc.UnaryHandler) error {
+	t.Helper()
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	identity := func(ctx context.Context, req interface{}) (interface{}, error) {
+		ctx = context.WithValue(ctx, auth.TenantContextKey, &tenant.TenantConfig{TenantID: "ai8"})
+		ctx = context.WithValue(ctx, auth.ClientContextKey, &auth.ClientKey{ClientID: "client-1"})
+		return UnaryAnnotator()(ctx, req, info, handler)
+	}
+	_, err := l.UnaryInterceptor()(tracing.WithID(context.Background(), "trace-1"), req, info, identity)
+	return err
+}
+
+func TestUnaryInterceptor_LogsAndRecordsMetrics(t *testing.T) {
+	buf := captureLogs(t)
+	reg := metrics.NewRegistry()
+	l := New(Config{SampleRate: 1}, reg)
+
+	req := &pb.GenerateReplyRequest{UserInput: "hello"}
+	err := runUnary(t, l, "/airborne.v1.AirborneService/GenerateReply", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		Annotate(ctx, "provider", "openai")
+		return &pb.GenerateReplyResponse{Text: "hi there"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := logLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+	line := lines[0]
+	if line["msg"] != "gRPC request" || line["level"] != "INFO" {
+		t.Errorf("unexpected msg/level: %v %v", line["msg"], line["level"])
+	}
+	if line["tenant_id"] != "ai8" || line["client_id"] != "client-1" {
+		t.Errorf("expected tenant/client from annotator, got %v/%v", line["tenant_id"], line["client_id"])
+	}
+	if line["provider"] != "openai" {
+		t.Errorf("expected handler annotation, got %v", line["provider"])
+	}
+	if line["trace_id"] != "trace-1" {
+		t.Errorf("expected trace ID, got %v", line["trace_id"])
+	}
+	if line["request_bytes"].(float64) <= 0 || line["response_bytes"].(float64) <= 0 {
+		t.Errorf("expected message sizes, got %v/%v", line["request_bytes"], line["response_bytes"])
+	}
+
+	snap := reg.Snapshot()
+	if len(snap.RPCs) != 1 || snap.RPCs[0].TenantID != "ai8" || snap.RPCs[0].Code != "OK" {
+		t.Errorf("unexpected metrics: %+v", snap.RPCs)
+	}
+}
+
+func TestAnnotators_AttachIdentityToLogs(t *testing.T) {
+	l := New(Config{}, nil)
+
+	var attrs []slog.Attr
+	err := runUnary(t, l, "/svc/Method", nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		attrs = logctx.Attrs(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attrs) != 2 || attrs[0].Value.String() != "ai8" || attrs[1].Value.String() != "client-1" {
+		t.Errorf("expected tenant and client log fields, got %v", attrs)
+	}
+
+	ctx := context.WithValue(context.Background(), auth.TenantContextKey, &tenant.TenantConfig{TenantID: "ai8"})
+	ss := &fakeStream{ctx: ctx}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		attrs = logctx.Attrs(stream.Context())
+		return nil
+	}
+	if err := StreamAnnotator()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attrs) != 1 || attrs[0].Key != logctx.KeyTenantID || attrs[0].Value.String() != "ai8" {
+		t.Errorf("expected tenant log field on stream context, got %v", attrs)
+	}
+}
+
+func TestUnaryInterceptor_SamplingSkipsSuccess(t *testing.T) {
+	buf := captureLogs(t)
+	reg := metrics.NewRegistry()
+	l := New(Config{SampleRate: 0.5}, reg)
+	l.sample = func() float64 { return 0.9 }
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	_ = runUnary(t, l, "/svc/Method", nil, ok)
+
+	if buf.Len() != 0 {
+		t.Errorf("expected sampled-out request not to be logged, got %s", buf.String())
+	}
+	if got := reg.Snapshot().RPCs[0].Count; got != 1 {
+		t.Errorf("expected metrics to record sampled-out request, got count %d", got)
+	}
+
+	l.sample = func() float64 { return 0.1 }
+	_ = runUnary(t, l, "/svc/Method", nil, ok)
+
+	lines := logLines(t, buf)
+	if len(lines) != 1 || lines[0]["sample_rate"] != 0.5 {
+		t.Errorf("expected sampled-in request logged with sample_rate, got %s", buf.String())
+	}
+}
+
+func TestUnaryInterceptor_ErrorsAndSlowAlwaysLogged(t *testing.T) {
+	buf := captureLogs(t)
+	l := New(Config{SampleRate: 0, SlowThreshold: time.Nanosecond}, nil)
+
+	_ = runUnary(t, l, "/svc/Fail", nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad input")
+	})
+	_ = runUnary(t, l, "/svc/Slow", nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		return nil, nil
+	})
+
+	lines := logLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if lines[0]["level"] != "WARN" || lines[0]["code"] != "InvalidArgument" || lines[0]["error"] != "bad input" {
+		t.Errorf("unexpected failure line: %v", lines[0])
+	}
+	if lines[1]["level"] != "WARN" || lines[1]["slow"] != true {
+		t.Errorf("unexpected slow line: %v", lines[1])
+	}
+}
+
+func TestUnaryInterceptor_HealthNotLogged(t *testing.T) {
+	buf := captureLogs(t)
+	l := New(Config{SampleRate: 1}, nil)
+
+	_ = runUnary(t, l, healthMethod, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	if buf.Len() != 0 {
+		t.Errorf("expected health check not to be logged, got %s", buf.String())
+	}
+}
+
+func TestUnaryInterceptor_RejectedRequestUsesRequestedTenant(t *testing.T) {
+	buf := captureLogs(t)
+	reg := metrics.NewRegistry()
+	l := New(Config{SampleRate: 1}, reg)
+
+	// Auth rejects before the annotator runs
+	reject := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unauthenticated, "missing key")
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	_, _ = l.UnaryInterceptor()(context.Background(), &pb.GenerateReplyRequest{TenantId: "email4ai"}, info, reject)
+
+	lines := logLines(t, buf)
+	if len(lines) != 1 || lines[0]["tenant_id"] != "email4ai" {
+		t.Errorf("expected requested tenant in log, got %s", buf.String())
+	}
+	if got := reg.Snapshot().RPCs[0].TenantID; got != "" {
+		t.Errorf("expected unauthenticated tenant to be excluded from metrics, got %q", got)
+	}
+}
+
+func TestAnnotate_NoEntry(t *testing.T) {
+	// Must not panic outside the interceptor
+	Annotate(context.Background(), "key", "value")
+}
+
+// fakeStream is a minimal grpc.ServerStream for stream interceptor tests.
+type fakeStream struct {
+	ctx  context.Context
+	recv []interface{}
+}
+
+func (f *fakeStream) SetHeader(metadata.MD) error  { return nil }
+func (f *fakeStream) SendHeader(metadata.MD) error { return nil }
+func (f *fakeStream) SetTrailer(metadata.MD)       {}
+func (f *fakeStream) Context() context.Context     { return f.ctx }
+func (f *fakeStream) SendMsg(m interface{}) error  { return nil }
+func (f *fakeStream) RecvMsg(m interface{}) error {
+	if len(f.recv) == 0 {
+		return errors.New("EOF")
+	}
+	f.recv = f.recv[1:]
+	return nil
+}
+
+func TestStreamInterceptor_CountsMessages(t *testing.T) {
+	buf := captureLogs(t)
+	reg := metrics.NewRegistry()
+	l := New(Config{SampleRate: 1}, reg)
+
+	ss := &fakeStream{ctx: context.Background(), recv: []interface{}{1}}
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&pb.GenerateReplyRequest{UserInput: "hi"}); err != nil {
+			return err
+		}
+		Annotate(stream.Context(), "provider", "gemini")
+		for i := 0; i < 3; i++ {
+			if err := stream.SendMsg(&pb.GenerateReplyChunk{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := l.StreamInterceptor()(nil, ss, info, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := logLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(lines))
+	}
+	line := lines[0]
+	if line["msg"] != "gRPC stream" || line["messages_received"] != 1.0 || line["messages_sent"] != 3.0 {
+		t.Errorf("unexpected stream log: %v", line)
+	}
+	if line["provider"] != "gemini" {
+		t.Errorf("expected stream annotation, got %v", line["provider"])
+	}
+	if len(reg.Snapshot().RPCs) != 1 {
+		t.Errorf("expected stream to be recorded in metrics")
+	}
+}
@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+)
+
+// AnalyticsResponse is the response from the conversation analytics endpoint.
+type AnalyticsResponse struct {
+	TenantID string `json:"tenant_id"`
+	Since    string `json:"since"`
+	Until    string `json:"until"`
+	db.AnalyticsReport
+	Error string `json:"error,omitempty"`
+}
+
+// handleAnalytics returns a tenant's most common intents and topics among
+// the user messages sent in a window, as extracted by the conversation
+// analytics job.
+// GET /admin/analytics?tenant_id=ai8&since=...&until=...&limit=20
+func (s *Server) handleAnalytics(w http.ResponseWriter, r *http.Request) {
+	until := time.Now().UTC()
+	if v := r.URL.Query().Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "until must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		until = t
+	}
+	since := until.Add(-7 * 24 * time.Hour)
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "since must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = t
+	}
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	tenantID := r.URL.Query().Get("tenant_id")
+
+	resp := AnalyticsResponse{
+		TenantID:        tenantID,
+		Since:           since.Format(time.RFC3339),
+		Until:           until.Format(time.RFC3339),
+		AnalyticsReport: db.AnalyticsReport{Intents: []db.AnalyticsCount{}, Topics: []db.AnalyticsCount{}},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if s.dbClient == nil {
+		resp.Error = "database not configured"
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+	repo, err := s.dbClient.TenantRepository(tenantID)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp.Error = err.Error()
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := repo.GetAnalyticsReport(ctx, since, until, limit)
+	if err != nil {
+		slog.Error("failed to fetch conversation analytics", "tenant_id", tenantID, "error", err)
+		resp.Error = err.Error()
+	} else {
+		resp.AnalyticsReport = *report
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EffectiveConfigResponse is the server's resolved configuration.
+type EffectiveConfigResponse struct {
+	Environment string         `json:"environment,omitempty"` // AIRBORNE_ENV
+	Sources     []string       `json:"sources"`               // Config files merged, lowest precedence first
+	Config      map[string]any `json:"config,omitempty"`      // Secrets masked
+	Error       string         `json:"error,omitempty"`
+}
+
+// handleEffectiveConfig returns the fully resolved configuration the server
+// is running with, after file layering and environment variable overrides.
+// GET /admin/config/effective
+func (s *Server) handleEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.cfg == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(EffectiveConfigResponse{Error: "effective config not available"})
+		return
+	}
+
+	// Round-trip through YAML so keys match the config file names
+	data, err := yaml.Marshal(s.cfg.Redacted())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(EffectiveConfigResponse{Error: "failed to encode config: " + err.Error()})
+		return
+	}
+	var cfg map[string]any
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(EffectiveConfigResponse{Error: "failed to encode config: " + err.Error()})
+		return
+	}
+
+	sources := s.cfg.Sources
+	if sources == nil {
+		sources = []string{}
+	}
+	json.NewEncoder(w).Encode(EffectiveConfigResponse{
+		Environment: s.cfg.Environment,
+		Sources:     sources,
+		Config:      cfg,
+	})
+}
@@ -0,0 +1,193 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/email"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+// emailThreadNamespace derives thread IDs from a tenant and the Message-ID
+// a conversation started with, so every reply continues the same thread.
+var emailThreadNamespace = uuid.MustParse("5b8f0f5e-4c6a-4d1e-9a53-8f2d1c7e6b40")
+
+// defaultEmailPrompt is used when the request has no system prompt.
+const defaultEmailPrompt = "You are a helpful assistant answering email. Reply with the body of the reply email only, in plain text, without a subject line."
+
+// EmailResponse is the response from the email ingestion endpoint: the
+// reply to send, with the headers that thread it in the sender's client.
+type EmailResponse struct {
+	ThreadID  string      `json:"thread_id,omitempty"`
+	MessageID string      `json:"message_id,omitempty"` // Of the inbound email
+	Reply     *EmailReply `json:"reply,omitempty"`
+	Provider  string      `json:"provider,omitempty"`
+	Model     string      `json:"model,omitempty"`
+	TokensIn  int         `json:"tokens_in,omitempty"`
+	TokensOut int         `json:"tokens_out,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// EmailReply is a generated reply email.
+type EmailReply struct {
+	email.Reply
+	Body string `json:"body"`
+}
+
+// emailThreadID returns the thread an email continues.
+func emailThreadID(tenantID string, m *email.Message) uuid.UUID {
+	key := m.ThreadKey()
+	if key == "" {
+		return uuid.New()
+	}
+	return uuid.NewSHA1(emailThreadNamespace, []byte(tenantID+"\x00"+key))
+}
+
+// emailUserInput returns the user message for an email: its subject and
+// reply text.
+func emailUserInput(m *email.Message) string {
+	text := m.Text
+	if text == "" {
+		text = "(No message text; see the attached files.)"
+	}
+	if m.Subject == "" {
+		return text
+	}
+	return "Subject: " + m.Subject + "\n\n" + text
+}
+
+// handleEmail generates the reply to an inbound email.
+// POST /admin/email (multipart/form-data)
+// Fields: file (required, raw RFC 822 message), tenant_id (required),
+// provider, system_prompt.
+// The email continues the thread of the conversation it belongs to, found
+// from its References and In-Reply-To headers; the sender's address is the
+// request's user_id, so their profile and memories apply.
+func (s *Server) handleEmail(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(EmailResponse{Error: msg})
+	}
+
+	if err := r.ParseMultipartForm(email.MaxMessageBytes + 1<<20); err != nil {
+		writeError(http.StatusBadRequest, "failed to parse multipart form: "+err.Error())
+		return
+	}
+	tenantID := strings.TrimSpace(r.FormValue("tenant_id"))
+	if tenantID == "" {
+		writeError(http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	providerName := r.FormValue("provider")
+	if err := validateProvider(providerName); err != nil {
+		writeError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+	raw, err := io.ReadAll(io.LimitReader(file, email.MaxMessageBytes+1))
+	if err != nil {
+		writeError(http.StatusBadRequest, "failed to read file: "+err.Error())
+		return
+	}
+	msg, err := email.Parse(raw)
+	if err != nil {
+		writeError(http.StatusBadRequest, err.Error())
+		return
+	}
+	threadID := emailThreadID(tenantID, msg)
+
+	client, err := s.getGRPCClient()
+	if err != nil {
+		writeError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	// Continue the stored conversation, as the dashboard chat does
+	var history []*pb.Message
+	var previousResponseID string
+	if s.dbClient != nil {
+		if repo, err := s.dbClient.TenantRepository(tenantID); err == nil {
+			messages, err := repo.GetMessages(r.Context(), threadID, 50)
+			if err != nil {
+				slog.Warn("failed to load email thread history", "error", err, "thread_id", threadID)
+			} else if len(messages) > 0 {
+				history = buildCompressedHistory(messages, &previousResponseID)
+			}
+		}
+	}
+
+	systemPrompt := r.FormValue("system_prompt")
+	if strings.TrimSpace(systemPrompt) == "" {
+		systemPrompt = defaultEmailPrompt
+	}
+
+	grpcReq := &pb.GenerateReplyRequest{
+		Instructions:        systemPrompt,
+		UserInput:           emailUserInput(msg),
+		TenantId:            tenantID,
+		ClientId:            "email-ingest",
+		RequestId:           threadID.String(), // Thread continuity, as in handleChat
+		UserId:              strings.ToLower(msg.From.Address),
+		ConversationHistory: history,
+		PreviousResponseId:  previousResponseID,
+		Metadata:            map[string]string{"email_message_id": msg.MessageID},
+	}
+	for _, a := range msg.Attachments {
+		grpcReq.Attachments = append(grpcReq.Attachments, &pb.Attachment{Filename: a.Filename, MimeType: a.MIMEType, Content: a.Content})
+	}
+	switch strings.ToLower(providerName) {
+	case "gemini":
+		grpcReq.PreferredProvider = pb.Provider_PROVIDER_GEMINI
+	case "openai":
+		grpcReq.PreferredProvider = pb.Provider_PROVIDER_OPENAI
+	case "anthropic":
+		grpcReq.PreferredProvider = pb.Provider_PROVIDER_ANTHROPIC
+	}
+
+	ctx := r.Context()
+	if s.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.authToken)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Minute)
+	defer cancel()
+
+	resp, err := client.GenerateReply(ctx, grpcReq)
+	if err != nil {
+		slog.Error("email gRPC call failed", "error", err, "thread_id", threadID)
+		writeError(http.StatusOK, err.Error()) // Return 200 with error in body
+		return
+	}
+	if strings.TrimSpace(resp.Text) == "" {
+		writeError(http.StatusOK, "empty reply")
+		return
+	}
+
+	emailResp := EmailResponse{
+		ThreadID:  threadID.String(),
+		MessageID: msg.MessageID,
+		Reply:     &EmailReply{Reply: msg.ReplyHeaders(), Body: resp.Text},
+		Provider:  strings.ToLower(strings.TrimPrefix(resp.Provider.String(), "PROVIDER_")),
+		Model:     resp.Model,
+	}
+	if resp.Usage != nil {
+		emailResp.TokensIn = int(resp.Usage.InputTokens)
+		emailResp.TokensOut = int(resp.Usage.OutputTokens)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emailResp)
+}
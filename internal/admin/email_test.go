@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeChatClient records GenerateReply requests and answers them.
+type fakeChatClient struct {
+	pb.AirborneServiceClient
+	requests []*pb.GenerateReplyRequest
+}
+
+func (f *fakeChatClient) GenerateReply(ctx context.Context, req *pb.GenerateReplyRequest, opts ...grpc.CallOption) (*pb.GenerateReplyResponse, error) {
+	f.requests = append(f.requests, req)
+	return &pb.GenerateReplyResponse{Text: "Sure, two more.", Provider: pb.Provider_PROVIDER_OPENAI, Model: "gpt-4o"}, nil
+}
+
+// postEmail posts raw as the email form's file.
+func postEmail(t *testing.T, s *Server, raw string, fields map[string]string) (*httptest.ResponseRecorder, EmailResponse) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		mw.WriteField(name, value)
+	}
+	fw, _ := mw.CreateFormFile("file", "message.eml")
+	fw.Write([]byte(raw))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/email", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.handleEmail(rec, req)
+
+	var resp EmailResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	return rec, resp
+}
+
+func TestHandleEmail(t *testing.T) {
+	chat := &fakeChatClient{}
+	s := &Server{grpcClient: chat}
+
+	first := "From: Ana <Ana@Example.com>\r\nSubject: Order\r\nMessage-ID: <m1@example.com>\r\n\r\nOne coffee, please.\r\n"
+	reply := "From: Ana <ana@example.com>\r\nSubject: Re: Order\r\nMessage-ID: <m3@example.com>\r\n" +
+		"In-Reply-To: <m2@example.org>\r\nReferences: <m1@example.com> <m2@example.org>\r\n\r\n" +
+		"Make it two.\r\n\r\nOn Mon, 5 Oct 2026, Assistant wrote:\r\n> Coming up.\r\n"
+
+	_, resp1 := postEmail(t, s, first, map[string]string{"tenant_id": "email4ai", "provider": "openai"})
+	rec, resp2 := postEmail(t, s, reply, map[string]string{"tenant_id": "email4ai"})
+	if rec.Code != http.StatusOK || resp2.Error != "" {
+		t.Fatalf("status %d, error %q", rec.Code, resp2.Error)
+	}
+
+	if resp1.ThreadID == "" || resp1.ThreadID != resp2.ThreadID {
+		t.Errorf("thread IDs %q and %q, want the reply to continue the first email's thread", resp1.ThreadID, resp2.ThreadID)
+	}
+	if resp2.Reply.Body != "Sure, two more." || resp2.Reply.Subject != "Re: Order" || resp2.Reply.InReplyTo != "<m3@example.com>" {
+		t.Errorf("unexpected reply: %+v", resp2.Reply)
+	}
+
+	req := chat.requests[1]
+	if req.UserInput != "Subject: Re: Order\n\nMake it two." {
+		t.Errorf("UserInput = %q, want the reply without the quoted message", req.UserInput)
+	}
+	if req.UserId != "ana@example.com" || req.TenantId != "email4ai" || req.RequestId != resp2.ThreadID {
+		t.Errorf("unexpected request: user=%q tenant=%q request_id=%q", req.UserId, req.TenantId, req.RequestId)
+	}
+	if chat.requests[0].PreferredProvider != pb.Provider_PROVIDER_OPENAI {
+		t.Errorf("PreferredProvider = %v, want openai", chat.requests[0].PreferredProvider)
+	}
+
+	// Another tenant's conversation with the same headers is a separate thread
+	_, other := postEmail(t, s, first, map[string]string{"tenant_id": "ai8"})
+	if other.ThreadID == resp1.ThreadID {
+		t.Error("expected threads to be separate per tenant")
+	}
+}
+
+func TestHandleEmail_Invalid(t *testing.T) {
+	s := &Server{grpcClient: &fakeChatClient{}}
+	valid := "From: ana@example.com\r\n\r\nHello\r\n"
+
+	tests := []struct {
+		name   string
+		raw    string
+		fields map[string]string
+	}{
+		{"no tenant", valid, nil},
+		{"unknown provider", valid, map[string]string{"tenant_id": "ai8", "provider": "mistral"}},
+		{"no sender", "Subject: hi\r\n\r\nHello\r\n", map[string]string{"tenant_id": "ai8"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, resp := postEmail(t, s, tt.raw, tt.fields)
+			if rec.Code != http.StatusBadRequest || resp.Error == "" {
+				t.Errorf("status %d, error %q, want 400 with an error", rec.Code, resp.Error)
+			}
+		})
+	}
+}
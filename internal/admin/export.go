@@ -0,0 +1,220 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+)
+
+// Thread export formats.
+const (
+	ExportFormatMarkdown = "markdown"
+	ExportFormatJSON     = "json"
+)
+
+// ThreadExport is a conversation prepared for sharing or archival, with the
+// tenant's redaction policy applied.
+type ThreadExport struct {
+	ThreadID   uuid.UUID                `json:"thread_id"`
+	TenantID   string                   `json:"tenant_id"`
+	UserID     string                   `json:"user_id"`
+	Models     []string                 `json:"models"` // provider/model of each assistant reply, in first-use order
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+	ExportedAt time.Time                `json:"exported_at"`
+	Redacted   bool                     `json:"redacted"` // The tenant's redaction policy was applied
+	Messages   []db.ConversationMessage `json:"messages"`
+}
+
+// newThreadExport builds the export of conv, redacting message text and
+// citations with redactor. Rendered HTML is dropped since it would carry
+// the unredacted text.
+func newThreadExport(conv *db.ThreadConversation, redactor *tenant.Redactor, now time.Time) *ThreadExport {
+	export := &ThreadExport{
+		ThreadID:   conv.ThreadID,
+		TenantID:   conv.TenantID,
+		UserID:     redactor.UserID(conv.UserID),
+		Models:     []string{},
+		CreatedAt:  conv.CreatedAt,
+		UpdatedAt:  conv.UpdatedAt,
+		ExportedAt: now.UTC(),
+		Redacted:   redactor != nil,
+		Messages:   make([]db.ConversationMessage, 0, len(conv.Messages)),
+	}
+	seen := make(map[string]bool)
+	for _, msg := range conv.Messages {
+		msg.Content = redactor.Redact(msg.Content)
+		msg.RenderedHTML = ""
+		if len(msg.Citations) > 0 {
+			citations := make([]db.Citation, len(msg.Citations))
+			for i, c := range msg.Citations {
+				c.Title = redactor.Redact(c.Title)
+				c.Snippet = redactor.Redact(c.Snippet)
+				citations[i] = c
+			}
+			msg.Citations = citations
+		}
+		if msg.Role == db.RoleAssistant && msg.Model != "" {
+			model := msg.Model
+			if msg.Provider != "" {
+				model = msg.Provider + "/" + msg.Model
+			}
+			if !seen[model] {
+				seen[model] = true
+				export.Models = append(export.Models, model)
+			}
+		}
+		export.Messages = append(export.Messages, msg)
+	}
+	return export
+}
+
+// Markdown renders the export as a Markdown document.
+func (e *ThreadExport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Conversation %s\n\n", e.ThreadID)
+	fmt.Fprintf(&b, "- **Tenant:** %s\n", e.TenantID)
+	if e.UserID != "" {
+		fmt.Fprintf(&b, "- **User:** %s\n", e.UserID)
+	}
+	if len(e.Models) > 0 {
+		fmt.Fprintf(&b, "- **Models:** %s\n", strings.Join(e.Models, ", "))
+	}
+	fmt.Fprintf(&b, "- **Started:** %s\n", e.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Updated:** %s\n", e.UpdatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Exported:** %s\n", e.ExportedAt.Format(time.RFC3339))
+	if e.Redacted {
+		b.WriteString("- **Redacted:** yes\n")
+	}
+
+	for _, msg := range e.Messages {
+		fmt.Fprintf(&b, "\n---\n\n### %s", roleHeading(msg.Role))
+		if msg.Role == db.RoleAssistant && msg.Model != "" {
+			fmt.Fprintf(&b, " (%s)", msg.Model)
+		}
+		fmt.Fprintf(&b, "\n\n_%s_\n\n", msg.Timestamp.UTC().Format(time.RFC3339))
+		b.WriteString(strings.TrimSpace(msg.Content))
+		b.WriteString("\n")
+
+		if len(msg.Citations) > 0 {
+			b.WriteString("\n**Sources**\n\n")
+			for i, c := range msg.Citations {
+				fmt.Fprintf(&b, "%d. %s\n", i+1, citationMarkdown(c))
+			}
+		}
+	}
+	return b.String()
+}
+
+func roleHeading(role string) string {
+	switch role {
+	case db.RoleUser:
+		return "User"
+	case db.RoleAssistant:
+		return "Assistant"
+	case db.RoleSystem:
+		return "System"
+	}
+	return role
+}
+
+// citationMarkdown renders a citation as a link to its URL or the name of
+// its file, followed by its snippet.
+func citationMarkdown(c db.Citation) string {
+	var s string
+	switch {
+	case c.URL != "":
+		title := c.Title
+		if title == "" {
+			title = c.URL
+		}
+		s = fmt.Sprintf("[%s](%s)", title, c.URL)
+	case c.Filename != "":
+		s = c.Filename
+	case c.Title != "":
+		s = c.Title
+	default:
+		s = c.FileID
+	}
+	if c.Snippet != "" {
+		s += fmt.Sprintf(" — %q", c.Snippet)
+	}
+	return s
+}
+
+// handleThreadExport renders a thread's full conversation, with citations,
+// models and timestamps, as Markdown or JSON. The thread's tenant redaction
+// policy is applied to the export.
+// GET /admin/thread/{thread_id}/export?format=markdown
+func (s *Server) handleThreadExport(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: msg})
+	}
+
+	threadID, err := uuid.Parse(r.PathValue("thread_id"))
+	if err != nil {
+		writeError(http.StatusBadRequest, "invalid thread_id format")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportFormatMarkdown
+	}
+	if s.dbClient == nil {
+		writeError(http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	conv, err := db.NewRepository(s.dbClient).GetThreadConversationAllTenants(ctx, threadID)
+	if err != nil {
+		slog.Warn("failed to fetch thread conversation", "thread_id", threadID, "error", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(http.StatusNotFound, "thread not found")
+		} else {
+			writeError(http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	var policy tenant.RedactionPolicy
+	if s.tenantMgr != nil {
+		if cfg, ok := s.tenantMgr.Tenant(conv.TenantID); ok {
+			policy = cfg.Redaction
+		}
+	}
+	redactor, err := policy.Compile()
+	if err != nil {
+		// The loader rejects invalid policies, so this is a server fault;
+		// refuse rather than export unredacted text
+		slog.Error("invalid tenant redaction policy", "tenant_id", conv.TenantID, "error", err)
+		writeError(http.StatusInternalServerError, "invalid tenant redaction policy")
+		return
+	}
+
+	export := newThreadExport(conv, redactor, time.Now())
+	filename := "thread-" + threadID.String()
+	if format == ExportFormatJSON {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(export)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".md"))
+	fmt.Fprint(w, export.Markdown())
+}
@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+)
+
+func TestThreadExport(t *testing.T) {
+	started := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	conv := &db.ThreadConversation{
+		ThreadID:  uuid.New(),
+		TenantID:  "ai8",
+		UserID:    "user-1",
+		CreatedAt: started,
+		UpdatedAt: started.Add(time.Minute),
+		Messages: []db.ConversationMessage{
+			{Role: db.RoleUser, Content: "Email me at jane@example.com", Timestamp: started},
+			{Role: db.RoleAssistant, Content: "Sent to jane@example.com", RenderedHTML: "<p>Sent to jane@example.com</p>",
+				Provider: "openai", Model: "gpt-4o", Timestamp: started.Add(time.Second),
+				Citations: []db.Citation{{Type: "url", URL: "https://example.com/a", Title: "Contact jane@example.com"}}},
+			{Role: db.RoleAssistant, Content: "Done", Provider: "gemini", Model: "gemini-2.5-flash", Timestamp: started.Add(time.Minute)},
+			{Role: db.RoleAssistant, Content: "Again", Provider: "openai", Model: "gpt-4o", Timestamp: started.Add(time.Minute)},
+		},
+	}
+	redactor, err := tenant.RedactionPolicy{PII: []string{tenant.RedactEmail}, HideUserID: true}.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	export := newThreadExport(conv, redactor, started.Add(time.Hour))
+	if strings.Join(export.Models, ",") != "openai/gpt-4o,gemini/gemini-2.5-flash" {
+		t.Errorf("Models = %v", export.Models)
+	}
+	if !export.Redacted || export.UserID != "[REDACTED]" {
+		t.Errorf("expected redacted export, got redacted=%v user=%q", export.Redacted, export.UserID)
+	}
+	data, _ := json.Marshal(export)
+	if strings.Contains(string(data), "jane@example.com") {
+		t.Errorf("export leaks redacted text: %s", data)
+	}
+	if conv.Messages[1].Citations[0].Title != "Contact jane@example.com" {
+		t.Error("export modified the conversation")
+	}
+
+	md := export.Markdown()
+	for _, want := range []string{
+		"# Conversation " + conv.ThreadID.String(),
+		"- **Models:** openai/gpt-4o, gemini/gemini-2.5-flash",
+		"### Assistant (gpt-4o)",
+		"_2026-10-01T09:00:01Z_",
+		"Email me at [REDACTED]",
+		"1. [Contact [REDACTED]](https://example.com/a)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "jane@example.com") {
+		t.Errorf("markdown leaks redacted text:\n%s", md)
+	}
+}
+
+func TestHandleThreadExport(t *testing.T) {
+	ctx := context.Background()
+	client, err := db.NewClient(ctx, db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	repo, _ := client.TenantRepository("ai8")
+	threadID := uuid.New()
+	citations := []db.Citation{{Type: "file", Filename: "handbook.pdf", Snippet: "30 days"}}
+	if err := repo.PersistConversationTurnWithDebug(ctx, threadID, "user-1", "How much leave?", "30 days.", "openai", "gpt-4o", "",
+		1, 2, 3, 0.01, 0, 0, nil, citations, nil); err != nil {
+		t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+	}
+
+	s := &Server{dbClient: client}
+	mux := http.NewServeMux()
+	for _, rt := range s.routes() {
+		mux.HandleFunc(rt.pattern, rt.serve(""))
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/admin/thread/" + threadID.String() + "/export")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/markdown") {
+		t.Fatalf("markdown export: %d %s", rec.Code, rec.Body)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "30 days.") || !strings.Contains(body, `1. handbook.pdf — "30 days"`) {
+		t.Errorf("unexpected markdown:\n%s", body)
+	}
+
+	rec = get("/admin/thread/" + threadID.String() + "/export?format=json")
+	var export ThreadExport
+	if err := json.Unmarshal(rec.Body.Bytes(), &export); err != nil {
+		t.Fatalf("json export: %v: %s", err, rec.Body)
+	}
+	if export.TenantID != "ai8" || len(export.Messages) != 2 || export.Redacted || len(export.Messages[1].Citations) != 1 {
+		t.Errorf("unexpected export: %+v", export)
+	}
+
+	if rec := get("/admin/thread/" + threadID.String() + "/export?format=pdf"); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: expected 400, got %d", rec.Code)
+	}
+	if rec := get("/admin/thread/" + uuid.NewString() + "/export"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown thread: expected 404, got %d", rec.Code)
+	}
+	if rec := get("/admin/thread/" + threadID.String()); rec.Code != http.StatusOK {
+		t.Errorf("thread view no longer routed: %d", rec.Code)
+	}
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+)
+
+// LedgerResponse is the response from the ledger endpoint.
+type LedgerResponse struct {
+	Since  string                  `json:"since"`
+	Until  string                  `json:"until"`
+	Totals []db.ProviderCallTotals `json:"totals"`
+	Calls  []db.ProviderCall       `json:"calls"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+// handleLedger returns provider calls from the usage ledger, with totals per
+// provider and model over the whole window for reconciling invoices.
+func (s *Server) handleLedger(w http.ResponseWriter, r *http.Request) {
+	until := time.Now().UTC()
+	if v := r.URL.Query().Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "until must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		until = t
+	}
+	since := until.Add(-24 * time.Hour)
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "since must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = t
+	}
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+	tenantID := r.URL.Query().Get("tenant_id")
+	providerName := r.URL.Query().Get("provider")
+
+	resp := LedgerResponse{
+		Since:  since.Format(time.RFC3339),
+		Until:  until.Format(time.RFC3339),
+		Totals: []db.ProviderCallTotals{},
+		Calls:  []db.ProviderCall{},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if s.dbClient == nil {
+		resp.Error = "database not configured"
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	repo := db.NewRepository(s.dbClient)
+	totals, err := repo.GetProviderCallTotals(ctx, tenantID, providerName, since, until)
+	if err == nil {
+		resp.Totals = totals
+		resp.Calls, err = repo.GetProviderCalls(ctx, tenantID, providerName, since, until, limit)
+	}
+	if err != nil {
+		slog.Error("failed to fetch usage ledger", "error", err)
+		resp.Totals, resp.Calls, resp.Error = []db.ProviderCallTotals{}, []db.ProviderCall{}, err.Error()
+	}
+	json.NewEncoder(w).Encode(resp)
+}
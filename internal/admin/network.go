@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+)
+
+// NetworkViolationsResponse is the response from the network violations endpoint.
+type NetworkViolationsResponse struct {
+	Violations []db.NetworkViolation `json:"violations"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// restrictNetwork rejects requests from outside the configured network
+// restrictions, recording them in the audit log. Health checks are always
+// served, so load balancers outside the allowed networks can probe the
+// server.
+func (s *Server) restrictNetwork(next http.Handler) http.Handler {
+	if s.network.Empty() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		addr := auth.RemoteAddr(r.RemoteAddr)
+		country, err := s.network.Check(addr, s.geo)
+		if err != nil {
+			auth.ReportNetworkViolation(auth.NetworkViolation{
+				Surface: "admin_http",
+				Method:  r.Method + " " + r.URL.Path,
+				Addr:    addr,
+				Country: country,
+				Reason:  err,
+			})
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "not allowed from this network"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleNetworkViolations returns rejected requests from outside the
+// configured network restrictions, newest first.
+// GET /admin/network/violations?limit=50
+func (s *Server) handleNetworkViolations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.dbClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(NetworkViolationsResponse{Violations: []db.NetworkViolation{}, Error: "database not configured"})
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	violations, err := db.NewRepository(s.dbClient).GetNetworkViolations(ctx, limit)
+	if err != nil {
+		slog.Error("failed to fetch network violations", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(NetworkViolationsResponse{Violations: []db.NetworkViolation{}, Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(NetworkViolationsResponse{Violations: violations})
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/auth"
+)
+
+func TestRestrictNetwork(t *testing.T) {
+	s := &Server{network: auth.NetworkRestrictions{AllowedCIDRs: []string{"192.0.2.0/24"}}}
+	handler := s.restrictNetwork(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tt := range []struct {
+		path, remote string
+		want         int
+	}{
+		{"/admin/activity", "192.0.2.10:51000", http.StatusOK},
+		{"/admin/activity", "198.51.100.1:51000", http.StatusForbidden},
+		{"/admin/health", "198.51.100.1:51000", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = tt.remote
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("GET %s from %s = %d, want %d", tt.path, tt.remote, rec.Code, tt.want)
+		}
+	}
+}
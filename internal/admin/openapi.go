@@ -0,0 +1,271 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// openAPISpec builds an OpenAPI 3 document for routes. Request and response
+// schemas are derived from the typed structs' JSON tags; fields without
+// omitempty are listed as required.
+func openAPISpec(version string, routes []route) map[string]any {
+	schemas := make(map[string]any)
+	paths := make(map[string]map[string]any)
+
+	for _, rt := range routes {
+		for _, op := range rt.operations {
+			item := paths[op.path]
+			if item == nil {
+				item = make(map[string]any)
+				paths[op.path] = item
+			}
+			item[strings.ToLower(op.method)] = operationSpec(op, schemas)
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Airborne Admin API",
+			"description": "Operational endpoints served by the admin HTTP server.",
+			"version":     version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+func operationSpec(op operation, schemas map[string]any) map[string]any {
+	spec := map[string]any{
+		"summary":     op.summary,
+		"operationId": operationID(op),
+	}
+
+	var params []map[string]any
+	for _, p := range op.params {
+		params = append(params, map[string]any{
+			"name":        p.name,
+			"in":          p.in,
+			"description": p.description,
+			"required":    p.required,
+			"schema":      paramSchema(p),
+		})
+	}
+	if params != nil {
+		spec["parameters"] = params
+	}
+
+	if op.body != nil {
+		spec["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": schemaFor(reflect.TypeOf(op.body), schemas)},
+			},
+		}
+	} else if len(op.form) > 0 {
+		props := make(map[string]any, len(op.form))
+		var required []string
+		for _, f := range op.form {
+			schema := paramSchema(f)
+			schema["description"] = f.description
+			props[f.name] = schema
+			if f.required {
+				required = append(required, f.name)
+			}
+		}
+		form := map[string]any{"type": "object", "properties": props}
+		if required != nil {
+			form["required"] = required
+		}
+		spec["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"multipart/form-data": map[string]any{"schema": form}},
+		}
+	}
+
+	ok := map[string]any{"description": "OK"}
+	switch {
+	case op.contentType != "":
+		ok["content"] = map[string]any{op.contentType: map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}}
+	case op.response != nil:
+		ok["content"] = map[string]any{"application/json": map[string]any{"schema": schemaFor(reflect.TypeOf(op.response), schemas)}}
+	}
+	errResp := map[string]any{
+		"description": "Invalid request",
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaFor(reflect.TypeOf(ErrorResponse{}), schemas)},
+		},
+	}
+	responses := map[string]any{"200": ok, "400": errResp}
+	if op.auth {
+		spec["security"] = []map[string]any{{"bearerAuth": []string{}}}
+		responses["401"] = map[string]any{"description": "Missing or invalid bearer token"}
+		responses["403"] = map[string]any{"description": "Admin auth token not configured"}
+	}
+	spec["responses"] = responses
+	return spec
+}
+
+// operationID derives a unique ID from the method and path, e.g.
+// "getAdminDebugMessageId".
+func operationID(op operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.method))
+	for _, part := range strings.FieldsFunc(op.path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '_' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func paramSchema(p param) map[string]any {
+	schema := map[string]any{"type": p.typ}
+	if p.typ == "file" {
+		schema = map[string]any{"type": "string", "format": "binary"}
+	}
+	if len(p.enum) > 0 {
+		schema["enum"] = p.enum
+	}
+	return schema
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the schema for t. Named structs are added to schemas
+// once and referenced by name.
+func schemaFor(t reflect.Type, schemas map[string]any) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		schema := schemaFor(t.Elem(), schemas)
+		if _, ok := schema["$ref"]; ok {
+			return schema
+		}
+		schema["nullable"] = true
+		return schema
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	case rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		if _, ok := schemas[t.Name()]; !ok {
+			schemas[t.Name()] = map[string]any{} // Placeholder for recursive types
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema describes t's JSON-encoded fields, flattening embedded structs.
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	props := make(map[string]any)
+	var required []string
+
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				addFields(f.Type)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = schemaFor(f.Type, schemas)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	schema := map[string]any{"type": "object", "properties": props}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// handleOpenAPI returns the OpenAPI spec for the admin API.
+// GET /admin/openapi.json
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(openAPISpec(s.version.Version, s.routes()))
+}
+
+// docsPage loads Swagger UI from a CDN and points it at the spec.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Airborne Admin API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/admin/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// handleDocs serves Swagger UI for the admin API.
+// GET /admin/docs
+func handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
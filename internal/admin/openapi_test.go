@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	s := &Server{version: VersionInfo{Version: "1.2.3"}, docs: true}
+	rec := httptest.NewRecorder()
+	s.handleOpenAPI(rec, httptest.NewRequest(http.MethodGet, "/admin/openapi.json", nil))
+
+	var spec struct {
+		OpenAPI string                               `json:"openapi"`
+		Info    struct{ Version string }             `json:"info"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+		Comps   struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	if spec.OpenAPI != "3.0.3" || spec.Info.Version != "1.2.3" {
+		t.Errorf("unexpected header: openapi=%q version=%q", spec.OpenAPI, spec.Info.Version)
+	}
+
+	for _, rt := range s.routes() {
+		for _, op := range rt.operations {
+			if _, ok := spec.Paths[op.path][strings.ToLower(op.method)]; !ok {
+				t.Errorf("%s %s missing from spec", op.method, op.path)
+			}
+		}
+	}
+
+	// Every reference resolves to a component schema
+	for _, ref := range strings.Split(rec.Body.String(), `"$ref":"#/components/schemas/`)[1:] {
+		name, _, _ := strings.Cut(ref, `"`)
+		if _, ok := spec.Comps.Schemas[name]; !ok {
+			t.Errorf("unresolved schema reference %q", name)
+		}
+	}
+
+	chat := spec.Comps.Schemas["ChatRequest"]
+	required, _ := chat["required"].([]any)
+	if len(required) != 2 || required[0] != "thread_id" || required[1] != "message" {
+		t.Errorf("ChatRequest required = %v, want [thread_id message]", required)
+	}
+	if _, ok := spec.Comps.Schemas["ActivityRollup"]; !ok {
+		t.Error("expected nested db types to be described")
+	}
+}
+
+func TestRouteValidation(t *testing.T) {
+	called := false
+	rt := route{"/admin/stats", func(w http.ResponseWriter, r *http.Request) { called = true }, []operation{{
+		method: http.MethodGet,
+		params: []param{
+			queryParam("granularity", "string", "").oneOf("hour", "day"),
+			queryParam("periods", "integer", ""),
+			queryParam("store_id", "string", "").must(),
+		},
+	}}}
+
+	tests := []struct {
+		method   string
+		query    string
+		wantCode int
+	}{
+		{http.MethodGet, "store_id=docs&granularity=hour&periods=5", http.StatusOK},
+		{http.MethodGet, "granularity=hour", http.StatusBadRequest},
+		{http.MethodGet, "store_id=docs&granularity=week", http.StatusBadRequest},
+		{http.MethodGet, "store_id=docs&periods=many", http.StatusBadRequest},
+		{http.MethodPost, "store_id=docs", http.StatusMethodNotAllowed},
+		{http.MethodOptions, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		called = false
+		rec := httptest.NewRecorder()
+		rt.serve("")(rec, httptest.NewRequest(tt.method, "/admin/stats?"+tt.query, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s ?%s: code = %d, want %d", tt.method, tt.query, rec.Code, tt.wantCode)
+		}
+		if wantCalled := tt.wantCode == http.StatusOK && tt.method != http.MethodOptions; called != wantCalled {
+			t.Errorf("%s ?%s: handler called = %v", tt.method, tt.query, called)
+		}
+	}
+}
+
+func TestRouteAuth(t *testing.T) {
+	rt := route{"/admin/tenants/{tenant_id}/settings", func(w http.ResponseWriter, r *http.Request) {}, []operation{{
+		method: http.MethodGet, auth: true,
+	}}}
+
+	tests := []struct {
+		token    string
+		header   string
+		wantCode int
+	}{
+		{"secret", "Bearer secret", http.StatusOK},
+		{"secret", "Bearer wrong", http.StatusUnauthorized},
+		{"secret", "", http.StatusUnauthorized},
+		{"", "Bearer ", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/admin/tenants/acme/settings", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		rt.serve(tt.token)(rec, r)
+		if rec.Code != tt.wantCode {
+			t.Errorf("token %q, header %q: code = %d, want %d", tt.token, tt.header, rec.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestRoutesRequireAuth(t *testing.T) {
+	// Operations acting on a caller-chosen tenant with the server's credential
+	want := map[string]bool{
+		"GET /admin/store/export":  true,
+		"POST /admin/store/import": true,
+		"POST /admin/reindex":      true,
+		"GET /admin/reindex":       false,
+	}
+	for _, rt := range (&Server{}).routes() {
+		for _, op := range rt.operations {
+			key := op.method + " " + op.path
+			if auth, ok := want[key]; ok && op.auth != auth {
+				t.Errorf("%s: auth = %v, want %v", key, op.auth, auth)
+			}
+			delete(want, key)
+		}
+	}
+	for key := range want {
+		t.Errorf("%s: route not found", key)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		req     validator
+		wantErr string
+	}{
+		{"chat ok", `{"thread_id":"6f1c1e2a-8a55-4a3e-9a6e-1b2c3d4e5f60","message":"hi"}`, &ChatRequest{}, ""},
+		{"chat bad uuid", `{"thread_id":"abc","message":"hi"}`, &ChatRequest{}, "invalid thread_id"},
+		{"test no prompt", `{"provider":"gemini"}`, &TestRequest{}, "prompt is required"},
+		{"test bad provider", `{"prompt":"hi","provider":"mistral"}`, &TestRequest{}, "unknown provider"},
+		{"reindex no store", `{"tenant_id":"ai8"}`, &ReindexRequest{}, "store_id is required"},
+		{"malformed", `{`, &ReindexRequest{}, "invalid request body"},
+		{"settings no actor", `{"failover":{"enabled":true}}`, &TenantSettingsRequest{}, "actor is required"},
+		{"settings no change", `{"actor":"ops"}`, &TenantSettingsRequest{}, "no settings to change"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		err := decodeJSON(r, tt.req)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
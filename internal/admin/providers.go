@@ -0,0 +1,161 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/provider/anthropic"
+	"github.com/ai8future/airborne/internal/provider/gemini"
+	"github.com/ai8future/airborne/internal/provider/openai"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// providerCheckTimeout bounds a single provider smoke-test request.
+const providerCheckTimeout = 30 * time.Second
+
+// providerCheckMaxTokens keeps smoke-test replies as cheap as possible.
+const providerCheckMaxTokens = 16
+
+// ProviderCheck is the smoke-test result for one tenant provider.
+type ProviderCheck struct {
+	TenantID  string `json:"tenant_id"`
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	Status    string `json:"status"` // "ok", "error", or "skipped"
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ProviderCheckResponse is the response from the provider check endpoint.
+type ProviderCheckResponse struct {
+	Checks []ProviderCheck `json:"checks"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// providerClients returns the clients used for smoke tests, keyed by name.
+// Only providers the chat service can route to are checked.
+func providerClients() map[string]provider.Provider {
+	return map[string]provider.Provider{
+		provider.NameOpenAI:    openai.NewClient(),
+		provider.NameGemini:    gemini.NewClient(),
+		provider.NameAnthropic: anthropic.NewClient(),
+	}
+}
+
+// handleProvidersCheck verifies each enabled provider's API key with a
+// minimal request.
+// GET /admin/providers/check?tenant_id=optional (all tenants when omitted)
+func (s *Server) handleProvidersCheck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.tenantMgr == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ProviderCheckResponse{Error: "tenant manager not configured"})
+		return
+	}
+
+	tenantIDs := s.tenantMgr.TenantCodes()
+	if tenantID := r.URL.Query().Get("tenant_id"); tenantID != "" {
+		if _, ok := s.tenantMgr.Tenant(tenantID); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ProviderCheckResponse{Error: "tenant not found: " + tenantID})
+			return
+		}
+		tenantIDs = []string{tenantID}
+	}
+
+	clients := providerClients()
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		checks []ProviderCheck
+	)
+	for _, tenantID := range tenantIDs {
+		tenantCfg, ok := s.tenantMgr.Tenant(tenantID)
+		if !ok {
+			continue
+		}
+		for name, pCfg := range tenantCfg.Providers {
+			if !pCfg.Enabled {
+				continue
+			}
+			wg.Add(1)
+			go func(tenantID, name string, pCfg tenant.ProviderConfig) {
+				defer wg.Done()
+				check := checkProvider(r.Context(), clients[name], tenantID, name, pCfg, tenantCfg.Proxy)
+				mu.Lock()
+				checks = append(checks, check)
+				mu.Unlock()
+			}(tenantID, name, pCfg)
+		}
+	}
+	wg.Wait()
+
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].TenantID != checks[j].TenantID {
+			return checks[i].TenantID < checks[j].TenantID
+		}
+		return checks[i].Provider < checks[j].Provider
+	})
+
+	json.NewEncoder(w).Encode(ProviderCheckResponse{Checks: checks})
+}
+
+// checkProvider issues a minimal generation request against one provider.
+func checkProvider(ctx context.Context, client provider.Provider, tenantID, name string, pCfg tenant.ProviderConfig, proxy *egress.ProxyConfig) ProviderCheck {
+	check := ProviderCheck{
+		TenantID: tenantID,
+		Provider: name,
+		Model:    pCfg.Model,
+	}
+
+	if client == nil {
+		check.Status = "skipped"
+		check.Error = "provider is not routable by the chat service"
+		return check
+	}
+	if pCfg.APIKey == "" {
+		check.Status = "error"
+		check.Error = "API key not configured"
+		return check
+	}
+
+	maxTokens := providerCheckMaxTokens
+	params := provider.GenerateParams{
+		Instructions: "Reply with the single word OK.",
+		UserInput:    "ping",
+		ClientID:     "providers-check",
+		Config: provider.ProviderConfig{
+			APIKey:          pCfg.APIKey,
+			Model:           pCfg.Model,
+			BaseURL:         pCfg.BaseURL,
+			MaxOutputTokens: &maxTokens,
+			Proxy:           proxy,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, providerCheckTimeout)
+	defer cancel()
+	ctx = egress.WithAttribution(ctx, tenantID, name)
+
+	start := time.Now()
+	result, err := client.GenerateReply(ctx, params)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Status = "error"
+		check.Error = err.Error()
+		return check
+	}
+
+	check.Status = "ok"
+	if result.Model != "" {
+		check.Model = result.Model
+	}
+	return check
+}
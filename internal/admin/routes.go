@@ -0,0 +1,416 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/metrics"
+)
+
+// route is a ServeMux pattern with the operations served under it. The same
+// definitions register the handlers, enforce methods and query parameters,
+// and generate the OpenAPI spec.
+type route struct {
+	pattern    string // ServeMux pattern
+	handler    http.HandlerFunc
+	operations []operation
+}
+
+// operation documents one method on a route.
+type operation struct {
+	method      string
+	path        string // OpenAPI path, with {name} for path parameters
+	summary     string
+	params      []param // Query and path parameters
+	form        []param // multipart/form-data fields
+	body        any     // JSON request body, nil for none
+	response    any     // JSON response body
+	contentType string  // Response media type when it is not JSON
+	auth        bool    // Requires the admin bearer token
+}
+
+// param is a query, path or form parameter.
+type param struct {
+	name        string
+	in          string // "query", "path" or "form"
+	typ         string // "string", "integer", "boolean" or "file"
+	description string
+	required    bool
+	enum        []string
+}
+
+func queryParam(name, typ, description string) param {
+	return param{name: name, in: "query", typ: typ, description: description}
+}
+
+func pathParam(name, description string) param {
+	return param{name: name, in: "path", typ: "string", description: description, required: true}
+}
+
+func formField(name, typ, description string) param {
+	return param{name: name, in: "form", typ: typ, description: description}
+}
+
+// must marks p as required.
+func (p param) must() param {
+	p.required = true
+	return p
+}
+
+// oneOf restricts p to values.
+func (p param) oneOf(values ...string) param {
+	p.enum = values
+	return p
+}
+
+// ErrorResponse is the body of errors raised before a handler runs.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// routes returns the admin API's routes.
+func (s *Server) routes() []route {
+	tenantParam := queryParam("tenant_id", "string", "Restrict to one tenant")
+	storeParam := queryParam("store_id", "string", "RAG store ID").must()
+
+	routes := []route{
+		{"/admin/activity", s.handleActivity, []operation{{
+			method: http.MethodGet, path: "/admin/activity",
+			summary: "Recent activity across tenants",
+			params: []param{
+				queryParam("limit", "integer", "Entries to return, 1-200 (default 50)"),
+				tenantParam,
+			},
+			response: ActivityResponse{},
+		}}},
+		{"/admin/stats", s.handleStats, []operation{{
+			method: http.MethodGet, path: "/admin/stats",
+			summary: "Usage totals per tenant, provider, model and client key",
+			params: []param{
+				queryParam("granularity", "string", "Rollup bucket size (default day)").oneOf(db.RollupHourly, db.RollupDaily),
+				queryParam("periods", "integer", "Buckets to return, including the current one"),
+				tenantParam,
+				queryParam("key_id", "string", "Restrict to one client key"),
+			},
+			response: StatsResponse{},
+		}}},
+		{"/admin/ledger", s.handleLedger, []operation{{
+			method: http.MethodGet, path: "/admin/ledger",
+			summary: "Provider calls from the usage ledger, with totals per provider and model",
+			params: []param{
+				queryParam("since", "string", "Start of the window, RFC 3339 (default 24 hours before until)"),
+				queryParam("until", "string", "End of the window, RFC 3339 (default now)"),
+				queryParam("limit", "integer", "Calls to return, newest first, 1-1000 (default 100)"),
+				tenantParam,
+				queryParam("provider", "string", "Restrict to one provider"),
+			},
+			response: LedgerResponse{},
+		}}},
+		{"/admin/analytics", s.handleAnalytics, []operation{{
+			method: http.MethodGet, path: "/admin/analytics",
+			summary: "Most common intents and topics of a tenant's user messages, from the conversation analytics job",
+			params: []param{
+				queryParam("tenant_id", "string", "Tenant to report on").must(),
+				queryParam("since", "string", "Start of the window, RFC 3339 (default 7 days before until)"),
+				queryParam("until", "string", "End of the window, RFC 3339 (default now)"),
+				queryParam("limit", "integer", "Intents and topics to return, 1-100 (default 20)"),
+			},
+			response: AnalyticsResponse{},
+		}}},
+		{"/admin/debug/", s.handleDebug, []operation{{
+			method: http.MethodGet, path: "/admin/debug/{message_id}",
+			summary:  "Full request and response debug data for a message",
+			params:   []param{pathParam("message_id", "Message UUID")},
+			response: db.DebugData{},
+		}}},
+		{"/admin/thread/", s.handleThread, []operation{{
+			method: http.MethodGet, path: "/admin/thread/{thread_id}",
+			summary:  "Full conversation for a thread",
+			params:   []param{pathParam("thread_id", "Thread UUID")},
+			response: db.ThreadConversation{},
+		}}},
+		{"/admin/thread/{thread_id}/export", s.handleThreadExport, []operation{{
+			method: http.MethodGet, path: "/admin/thread/{thread_id}/export",
+			summary: "Download a thread's conversation as Markdown or JSON, with the tenant's redaction policy applied",
+			params: []param{
+				pathParam("thread_id", "Thread UUID"),
+				queryParam("format", "string", "Export format (default markdown)").oneOf(ExportFormatMarkdown, ExportFormatJSON),
+			},
+			response: ThreadExport{},
+		}}},
+		{"/admin/health", s.handleHealth, []operation{{
+			method: http.MethodGet, path: "/admin/health",
+			summary:  "Service and database health",
+			response: HealthResponse{},
+		}}},
+		{"/admin/version", s.handleVersion, []operation{{
+			method: http.MethodGet, path: "/admin/version",
+			summary:  "Build version information",
+			response: VersionInfo{},
+		}}},
+		{"/admin/metrics", s.handleMetrics, []operation{{
+			method: http.MethodGet, path: "/admin/metrics",
+			summary:  "Aggregated gRPC request metrics",
+			response: metrics.Snapshot{},
+		}}},
+		{"/admin/test", s.handleTest, []operation{{
+			method: http.MethodPost, path: "/admin/test",
+			summary:  "Send a test message through the gRPC service",
+			body:     TestRequest{},
+			response: TestResponse{},
+		}}},
+		{"/admin/providers/check", s.handleProvidersCheck, []operation{
+			{
+				method: http.MethodGet, path: "/admin/providers/check",
+				summary:  "Smoke-test each enabled provider's API key",
+				params:   []param{queryParam("tenant_id", "string", "Check one tenant (all tenants when omitted)")},
+				response: ProviderCheckResponse{},
+			},
+			{
+				method: http.MethodPost, path: "/admin/providers/check",
+				summary:  "Smoke-test each enabled provider's API key",
+				params:   []param{queryParam("tenant_id", "string", "Check one tenant (all tenants when omitted)")},
+				response: ProviderCheckResponse{},
+			},
+		}},
+		{"/admin/config/effective", s.handleEffectiveConfig, []operation{{
+			method: http.MethodGet, path: "/admin/config/effective",
+			summary:  "Resolved server configuration with secrets masked",
+			response: EffectiveConfigResponse{},
+		}}},
+		{"/admin/chat", s.handleChat, []operation{{
+			method: http.MethodPost, path: "/admin/chat",
+			summary:  "Send a message to an existing thread",
+			body:     ChatRequest{},
+			response: ChatResponse{},
+		}}},
+		{"/admin/email", s.handleEmail, []operation{{
+			method: http.MethodPost, path: "/admin/email",
+			summary: "Generate the reply to an inbound email, continuing the thread its References and In-Reply-To headers belong to",
+			form: []param{
+				formField("file", "file", "Raw RFC 822 message").must(),
+				formField("tenant_id", "string", "Tenant to reply as").must(),
+				formField("provider", "string", "Provider to use").oneOf("gemini", "openai", "anthropic"),
+				formField("system_prompt", "string", "Instructions for the reply (default: a plain-text email assistant)"),
+			},
+			response: EmailResponse{},
+			auth:     true,
+		}}},
+		{"/admin/upload", s.handleUpload, []operation{{
+			method: http.MethodPost, path: "/admin/upload",
+			summary: "Upload a file to the Gemini Files API for use in chat",
+			form: []param{
+				formField("file", "file", "File to upload").must(),
+				formField("tenant_id", "string", "Tenant whose Gemini key is used"),
+				formField("thread_id", "string", "Thread to register the file with, so it is re-uploaded once expired"),
+			},
+			response: UploadResponse{},
+		}}},
+		{"/admin/extract", s.handleExtract, []operation{{
+			method: http.MethodPost, path: "/admin/extract",
+			summary: "Preview RAG text extraction for a file without ingesting it",
+			form: []param{
+				formField("file", "file", "File to extract").must(),
+				formField("tenant_id", "string", "Tenant to extract as"),
+				formField("max_text_chars", "integer", "Truncate the returned text"),
+			},
+			response: ExtractResponse{},
+		}}},
+		{"/admin/reindex", s.handleReindex, []operation{
+			{
+				method: http.MethodGet, path: "/admin/reindex",
+				summary:  "RAG store re-index progress",
+				params:   []param{tenantParam, storeParam},
+				response: ReindexResponse{},
+			},
+			{
+				method: http.MethodPost, path: "/admin/reindex",
+				summary:  "Start re-embedding a RAG store",
+				body:     ReindexRequest{},
+				response: ReindexResponse{},
+				auth:     true,
+			},
+		}},
+		{"/admin/store/export", s.handleStoreExport, []operation{{
+			method: http.MethodGet, path: "/admin/store/export",
+			summary: "Download a RAG store snapshot archive",
+			params: []param{
+				tenantParam,
+				storeParam,
+				queryParam("include_vectors", "boolean", "Include embeddings so import can skip re-embedding"),
+			},
+			contentType: "application/gzip",
+			auth:        true,
+		}}},
+		{"/admin/store/import", s.handleStoreImport, []operation{{
+			method: http.MethodPost, path: "/admin/store/import",
+			summary: "Restore a RAG store from a snapshot archive",
+			form: []param{
+				formField("file", "file", "Snapshot archive from /admin/store/export").must(),
+				formField("tenant_id", "string", "Tenant to import into"),
+				formField("store_id", "string", "Target store (default: the snapshot's store)"),
+				formField("overwrite", "boolean", "Replace an existing store"),
+			},
+			response: StoreImportResponse{},
+			auth:     true,
+		}}},
+		{"/admin/tenants/{tenant_id}/settings", s.handleTenantSettings, []operation{
+			{
+				method: http.MethodGet, path: "/admin/tenants/{tenant_id}/settings",
+				summary:  "A tenant's provider, failover and feature settings, with secrets masked",
+				params:   []param{pathParam("tenant_id", "Tenant ID")},
+				response: TenantSettingsResponse{},
+				auth:     true,
+			},
+			{
+				method: http.MethodPost, path: "/admin/tenants/{tenant_id}/settings",
+				summary:  "Change a tenant's provider, failover and feature settings without a redeploy",
+				params:   []param{pathParam("tenant_id", "Tenant ID")},
+				body:     TenantSettingsRequest{},
+				response: TenantSettingsResponse{},
+				auth:     true,
+			},
+		}},
+		{"/admin/tenants/{tenant_id}/audit", s.handleTenantAudit, []operation{{
+			method: http.MethodGet, path: "/admin/tenants/{tenant_id}/audit",
+			summary: "Recent settings changes for a tenant",
+			params: []param{
+				pathParam("tenant_id", "Tenant ID"),
+				queryParam("limit", "integer", "Entries to return, 1-200 (default 50)"),
+			},
+			response: TenantAuditResponse{},
+			auth:     true,
+		}}},
+		{"/admin/network/violations", s.handleNetworkViolations, []operation{{
+			method: http.MethodGet, path: "/admin/network/violations",
+			summary:  "Recent requests rejected by the network restrictions",
+			params:   []param{queryParam("limit", "integer", "Violations to return, 1-200 (default 50)")},
+			response: NetworkViolationsResponse{},
+			auth:     true,
+		}}},
+		{"/admin/openapi.json", s.handleOpenAPI, []operation{{
+			method: http.MethodGet, path: "/admin/openapi.json",
+			summary:  "This OpenAPI document",
+			response: map[string]any{},
+		}}},
+	}
+	if s.docs {
+		routes = append(routes, route{"/admin/docs", handleDocs, []operation{{
+			method: http.MethodGet, path: "/admin/docs",
+			summary:     "Swagger UI for this API",
+			contentType: "text/html",
+		}}})
+	}
+	return routes
+}
+
+// serve wraps rt's handler with CORS headers, method enforcement, query
+// parameter validation and, for operations that require it, a check for
+// token as a bearer token. Those operations are refused when token is empty.
+func (rt route) serve(token string) http.HandlerFunc {
+	methods := make([]string, 0, len(rt.operations))
+	for _, op := range rt.operations {
+		methods = append(methods, op.method)
+	}
+	allow := strings.Join(append(slices.Clone(methods), http.MethodOptions), ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		i := slices.Index(methods, r.Method)
+		if i < 0 {
+			w.Header().Set("Allow", allow)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if rt.operations[i].auth {
+			if code, err := checkToken(r, token); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(code)
+				json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+				return
+			}
+		}
+		if err := validateQuery(r, rt.operations[i].params); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		rt.handler(w, r)
+	}
+}
+
+// checkToken checks r's bearer token against token in constant time and
+// returns the status to respond with when it does not match.
+func checkToken(r *http.Request, token string) (int, error) {
+	if token == "" {
+		return http.StatusForbidden, errors.New("admin auth token not configured")
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		return http.StatusUnauthorized, errors.New("invalid or missing bearer token")
+	}
+	return 0, nil
+}
+
+// validateQuery checks r's query string against the documented parameters:
+// required parameters are present, and values parse as their type and are
+// one of their allowed values. Range limits are left to the handlers, which
+// clamp rather than reject.
+func validateQuery(r *http.Request, params []param) error {
+	query := r.URL.Query()
+	for _, p := range params {
+		if p.in != "query" {
+			continue
+		}
+		value := query.Get(p.name)
+		if value == "" {
+			if p.required {
+				return fmt.Errorf("%s is required", p.name)
+			}
+			continue
+		}
+		switch p.typ {
+		case "integer":
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s must be an integer", p.name)
+			}
+		case "boolean":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("%s must be true or false", p.name)
+			}
+		}
+		if len(p.enum) > 0 && !slices.Contains(p.enum, value) {
+			return fmt.Errorf("%s must be one of %s", p.name, strings.Join(p.enum, ", "))
+		}
+	}
+	return nil
+}
+
+// validator is a request body that checks its own fields.
+type validator interface {
+	validate() error
+}
+
+// decodeJSON decodes r's JSON body into v and validates it.
+func decodeJSON(r *http.Request, v validator) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return v.validate()
+}
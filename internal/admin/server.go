@@ -5,27 +5,36 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"mime/multipart"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/config"
 	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/metrics"
 	"github.com/ai8future/airborne/internal/provider"
 	"github.com/ai8future/airborne/internal/provider/gemini"
 	"github.com/ai8future/airborne/internal/redis"
 	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/tracing"
+	"github.com/ai8future/airborne/internal/validation"
 	pricing_db "github.com/ai8future/pricing_db"
 	"github.com/google/uuid"
 	"google.golang.org/genai"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // Server is the HTTP admin server for operational endpoints.
@@ -38,9 +47,17 @@ type Server struct {
 	port        int
 	grpcAddr    string
 	authToken   string
+	adminToken  string // Required by endpoints that change settings
+	settingsMu  sync.Mutex
+	signer      *auth.RequestSigner
 	grpcConn    *grpc.ClientConn
 	grpcClient  pb.AirborneServiceClient
 	version     VersionInfo
+	metrics     *metrics.Registry
+	cfg         *config.Config
+	docs        bool
+	network     auth.NetworkRestrictions
+	geo         auth.GeoIP
 }
 
 // VersionInfo holds version information for the service.
@@ -53,11 +70,17 @@ type VersionInfo struct {
 // Config holds admin server configuration.
 type Config struct {
 	Port        int
-	GRPCAddr    string          // Address of the gRPC server (e.g., "localhost:50051")
-	AuthToken   string          // Auth token for gRPC calls
-	TenantMgr   *tenant.Manager // Tenant manager for accessing API keys
-	RedisClient *redis.Client   // Redis client for idempotency
-	Version     VersionInfo     // Version information
+	GRPCAddr    string                   // Address of the gRPC server (e.g., "localhost:50051")
+	AuthToken   string                   // Auth token for gRPC calls
+	Signer      *auth.RequestSigner      // Signs gRPC calls in place of AuthToken (optional)
+	TenantMgr   *tenant.Manager          // Tenant manager for accessing API keys
+	RedisClient *redis.Client            // Redis client for idempotency
+	Version     VersionInfo              // Version information
+	Metrics     *metrics.Registry        // gRPC metrics registry (optional)
+	Effective   *config.Config           // Resolved server config, served with secrets masked (optional)
+	Docs        bool                     // Serve Swagger UI at /admin/docs
+	Network     auth.NetworkRestrictions // Addresses and countries requests are accepted from (optional)
+	GeoIP       auth.GeoIP               // Resolves countries for Network (optional)
 }
 
 // NewServer creates a new admin HTTP server.
@@ -76,40 +99,29 @@ func NewServer(dbClient *db.Client, cfg Config) *Server {
 		port:        cfg.Port,
 		grpcAddr:    cfg.GRPCAddr,
 		authToken:   cfg.AuthToken,
+		adminToken:  cfg.AuthToken,
+		signer:      cfg.Signer,
 		version:     cfg.Version,
+		metrics:     cfg.Metrics,
+		cfg:         cfg.Effective,
+		docs:        cfg.Docs,
+		network:     cfg.Network,
+		geo:         cfg.GeoIP,
+	}
+	if s.signer != nil {
+		// Signed calls are authenticated without the bearer token, so it
+		// never crosses the internal hop
+		s.authToken = ""
 	}
 
 	mux := http.NewServeMux()
-
-	// CORS middleware wrapper
-	corsHandler := func(h http.HandlerFunc) http.HandlerFunc {
-		return func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-
-			h(w, r)
-		}
+	for _, rt := range s.routes() {
+		mux.HandleFunc(rt.pattern, rt.serve(s.adminToken))
 	}
 
-	// Register endpoints
-	mux.HandleFunc("/admin/activity", corsHandler(s.handleActivity))
-	mux.HandleFunc("/admin/debug/", corsHandler(s.handleDebug))
-	mux.HandleFunc("/admin/thread/", corsHandler(s.handleThread))
-	mux.HandleFunc("/admin/health", corsHandler(s.handleHealth))
-	mux.HandleFunc("/admin/version", corsHandler(s.handleVersion))
-	mux.HandleFunc("/admin/test", corsHandler(s.handleTest))
-	mux.HandleFunc("/admin/chat", corsHandler(s.handleChat))
-	mux.HandleFunc("/admin/upload", corsHandler(s.handleUpload))
-
 	s.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      mux,
+		Handler:      tracing.Middleware(s.restrictNetwork(mux)),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 5 * time.Minute, // Must exceed context timeout for LLM requests
 		IdleTimeout:  60 * time.Second,
@@ -124,6 +136,12 @@ func (s *Server) Start() error {
 	return s.server.ListenAndServe()
 }
 
+// Serve serves the admin HTTP server on l instead of the configured port.
+func (s *Server) Serve(l net.Listener) error {
+	slog.Info("starting admin HTTP server", "address", l.Addr().String())
+	return s.server.Serve(l)
+}
+
 // Shutdown gracefully shuts down the server.
 func (s *Server) Shutdown(ctx context.Context) error {
 	if s.grpcConn != nil {
@@ -132,14 +150,64 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
 }
 
+// ActivityItem is one message in the activity feed.
+type ActivityItem struct {
+	ID               string  `json:"id"`
+	ThreadID         string  `json:"thread_id"`
+	Tenant           string  `json:"tenant"`
+	UserID           string  `json:"user_id"`
+	Content          string  `json:"content"`
+	FullContent      string  `json:"full_content"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	TokensUsed       int     `json:"tokens_used"`
+	CostUSD          float64 `json:"cost_usd"`
+	GroundingQueries int     `json:"grounding_queries"`
+	GroundingCostUSD float64 `json:"grounding_cost_usd"`
+	ThreadCostUSD    float64 `json:"thread_cost_usd"`
+	ProcessingTimeMs int     `json:"processing_time_ms"`
+	Status           string  `json:"status"` // success, failed
+	Timestamp        string  `json:"timestamp"`
+}
+
+// ActivityResponse is the response from the activity endpoint.
+type ActivityResponse struct {
+	Activity []ActivityItem `json:"activity"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// StatsTotals sums the rollups returned by the stats endpoint.
+type StatsTotals struct {
+	RequestCount     int64   `json:"request_count"`
+	FailedCount      int64   `json:"failed_count"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	GroundingQueries int64   `json:"grounding_queries"`
+	GroundingCostUSD float64 `json:"grounding_cost_usd"`
+}
+
+// StatsResponse is the response from the stats endpoint.
+type StatsResponse struct {
+	Granularity string              `json:"granularity,omitempty"`
+	Since       string              `json:"since,omitempty"`
+	Rollups     []db.ActivityRollup `json:"rollups"`
+	Keys        []db.KeyUsage       `json:"keys"`
+	Totals      *StatsTotals        `json:"totals,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// HealthResponse is the response from the health endpoint.
+type HealthResponse struct {
+	Status   string `json:"status"`   // healthy or degraded
+	Database string `json:"database"` // healthy, unhealthy or not_configured
+}
+
 // handleActivity returns recent activity for the dashboard.
 // GET /admin/activity?limit=50&tenant_id=optional
 func (s *Server) handleActivity(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// Parse query parameters
 	limitStr := r.URL.Query().Get("limit")
 	limit := 50 // default
@@ -155,9 +223,9 @@ func (s *Server) handleActivity(w http.ResponseWriter, r *http.Request) {
 	if s.dbClient == nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"activity": []interface{}{},
-			"error":    "database not configured",
+		json.NewEncoder(w).Encode(ActivityResponse{
+			Activity: []ActivityItem{},
+			Error:    "database not configured",
 		})
 		return
 	}
@@ -183,52 +251,132 @@ func (s *Server) handleActivity(w http.ResponseWriter, r *http.Request) {
 		slog.Error("failed to fetch activity", "error", err)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK) // Return 200 with error in body (matches Bizops pattern)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"activity": []interface{}{},
-			"error":    err.Error(),
+		json.NewEncoder(w).Encode(ActivityResponse{
+			Activity: []ActivityItem{},
+			Error:    err.Error(),
 		})
 		return
 	}
 
 	// Convert to response format matching Bizops expectations
-	activity := make([]map[string]interface{}, len(entries))
+	activity := make([]ActivityItem, len(entries))
 	for i, e := range entries {
-		activity[i] = map[string]interface{}{
-			"id":                 e.ID.String(),
-			"thread_id":          e.ThreadID.String(),
-			"tenant":             e.TenantID,
-			"user_id":            e.UserID,
-			"content":            e.Content,
-			"full_content":       e.FullContent,
-			"provider":           e.Provider,
-			"model":              e.Model,
-			"input_tokens":       e.InputTokens,
-			"output_tokens":      e.OutputTokens,
-			"tokens_used":        e.TotalTokens,
-			"cost_usd":           e.CostUSD,
-			"grounding_queries":  e.GroundingQueries,
-			"grounding_cost_usd": e.GroundingCostUSD,
-			"thread_cost_usd":    e.ThreadCostUSD,
-			"processing_time_ms": e.ProcessingTimeMs,
-			"status":             e.Status,
-			"timestamp":          e.Timestamp.Format(time.RFC3339),
+		activity[i] = ActivityItem{
+			ID:               e.ID.String(),
+			ThreadID:         e.ThreadID.String(),
+			Tenant:           e.TenantID,
+			UserID:           e.UserID,
+			Content:          e.Content,
+			FullContent:      e.FullContent,
+			Provider:         e.Provider,
+			Model:            e.Model,
+			InputTokens:      e.InputTokens,
+			OutputTokens:     e.OutputTokens,
+			TokensUsed:       e.TotalTokens,
+			CostUSD:          e.CostUSD,
+			GroundingQueries: e.GroundingQueries,
+			GroundingCostUSD: e.GroundingCostUSD,
+			ThreadCostUSD:    e.ThreadCostUSD,
+			ProcessingTimeMs: e.ProcessingTimeMs,
+			Status:           e.Status,
+			Timestamp:        e.Timestamp.Format(time.RFC3339),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ActivityResponse{Activity: activity})
+}
+
+// handleStats returns usage totals per tenant/provider/model from the activity rollups,
+// with a per-client-key breakdown under "keys".
+// GET /admin/stats?granularity=day&periods=30&tenant_id=optional&key_id=optional
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	granularity := r.URL.Query().Get("granularity")
+	if granularity == "" {
+		granularity = db.RollupDaily
+	}
+
+	var step time.Duration
+	var periods, maxPeriods int
+	switch granularity {
+	case db.RollupHourly:
+		step, periods, maxPeriods = time.Hour, 24, 24*31
+	case db.RollupDaily:
+		step, periods, maxPeriods = 24*time.Hour, 30, 366
+	default:
+		http.Error(w, "granularity must be 'hour' or 'day'", http.StatusBadRequest)
+		return
+	}
+	if p, err := strconv.Atoi(r.URL.Query().Get("periods")); err == nil && p > 0 && p <= maxPeriods {
+		periods = p
+	}
+
+	tenantID := r.URL.Query().Get("tenant_id")
+	keyID := r.URL.Query().Get("key_id")
+
+	if s.dbClient == nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(StatsResponse{
+			Rollups: []db.ActivityRollup{},
+			Keys:    []db.KeyUsage{},
+			Error:   "database not configured",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	// Include the current (partial) bucket
+	since := db.RollupBucket(granularity, time.Now()).Add(-time.Duration(periods-1) * step)
+	rollups, err := db.NewRepository(s.dbClient).GetActivityRollups(ctx, granularity, since, tenantID)
+	if err != nil {
+		slog.Error("failed to fetch activity rollups", "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(StatsResponse{
+			Rollups: []db.ActivityRollup{},
+			Keys:    []db.KeyUsage{},
+			Error:   err.Error(),
+		})
+		return
+	}
+	if keyID != "" {
+		filtered := rollups[:0]
+		for _, ru := range rollups {
+			if ru.KeyID == keyID {
+				filtered = append(filtered, ru)
+			}
 		}
+		rollups = filtered
+	}
+	if rollups == nil {
+		rollups = []db.ActivityRollup{}
+	}
+
+	var totals StatsTotals
+	for _, ru := range rollups {
+		totals.RequestCount += ru.RequestCount
+		totals.FailedCount += ru.FailedCount
+		totals.InputTokens += ru.InputTokens
+		totals.OutputTokens += ru.OutputTokens
+		totals.CostUSD += ru.CostUSD
+		totals.GroundingQueries += ru.GroundingQueries
+		totals.GroundingCostUSD += ru.GroundingCostUSD
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"activity": activity,
+	json.NewEncoder(w).Encode(StatsResponse{
+		Granularity: granularity,
+		Since:       since.Format(time.RFC3339),
+		Rollups:     rollups,
+		Keys:        db.UsageByKey(rollups),
+		Totals:      &totals,
 	})
 }
 
 // handleHealth returns health status.
 // GET /admin/health
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	status := "healthy"
 	dbStatus := "not_configured"
 
@@ -247,40 +395,35 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":   status,
-		"database": dbStatus,
+	json.NewEncoder(w).Encode(HealthResponse{
+		Status:   status,
+		Database: dbStatus,
 	})
 }
 
 // handleVersion returns version information.
 // GET /admin/version
 func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(s.version)
 }
 
+// handleMetrics returns aggregated gRPC request metrics.
+// GET /admin/metrics
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.metrics.Snapshot())
+}
+
 // handleDebug returns full request/response debug data for a message.
 // GET /admin/debug/{message_id}
 func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// Extract message ID from path: /admin/debug/{message_id}
 	path := strings.TrimPrefix(r.URL.Path, "/admin/debug/")
 	if path == "" {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "message_id required",
-		})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "message_id required"})
 		return
 	}
 
@@ -288,9 +431,7 @@ func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "invalid message_id format",
-		})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid message_id format"})
 		return
 	}
 
@@ -298,9 +439,7 @@ func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
 	if s.dbClient == nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusServiceUnavailable)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "database not configured",
-		})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "database not configured"})
 		return
 	}
 
@@ -315,14 +454,10 @@ func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		if strings.Contains(err.Error(), "not found") {
 			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "debug data not found",
-			})
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "debug data not found"})
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": err.Error(),
-			})
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		}
 		return
 	}
@@ -335,19 +470,12 @@ func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
 // handleThread returns the full conversation for a thread.
 // GET /admin/thread/{thread_id}
 func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// Extract thread ID from path: /admin/thread/{thread_id}
 	path := strings.TrimPrefix(r.URL.Path, "/admin/thread/")
 	if path == "" {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "thread_id required",
-		})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "thread_id required"})
 		return
 	}
 
@@ -355,9 +483,7 @@ func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "invalid thread_id format",
-		})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid thread_id format"})
 		return
 	}
 
@@ -365,9 +491,7 @@ func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
 	if s.dbClient == nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusServiceUnavailable)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "database not configured",
-		})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "database not configured"})
 		return
 	}
 
@@ -382,14 +506,10 @@ func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		if strings.Contains(err.Error(), "not found") {
 			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "thread not found",
-			})
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "thread not found"})
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": err.Error(),
-			})
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		}
 		return
 	}
@@ -400,21 +520,66 @@ func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
 }
 
 // TestRequest is the request body for the test endpoint.
+// The optional fields mirror GenerateReplyRequest so operators can reproduce
+// a real client configuration when debugging.
 type TestRequest struct {
-	Prompt   string `json:"prompt"`
-	TenantID string `json:"tenant_id,omitempty"`
-	Provider string `json:"provider,omitempty"` // "gemini", "openai", "anthropic"
+	Prompt           string   `json:"prompt"`
+	TenantID         string   `json:"tenant_id,omitempty"`
+	Provider         string   `json:"provider,omitempty"` // "gemini", "openai", "anthropic"
+	Instructions     string   `json:"instructions,omitempty"`
+	Model            string   `json:"model,omitempty"`
+	Preset           string   `json:"preset,omitempty"` // Tenant generation preset, e.g. "precise"
+	Temperature      *float64 `json:"temperature,omitempty"`
+	EnableWebSearch  bool     `json:"enable_web_search,omitempty"`
+	EnableFileSearch bool     `json:"enable_file_search,omitempty"`
+	FileStoreID      string   `json:"file_store_id,omitempty"`
+	StructuredOutput bool     `json:"structured_output,omitempty"`
+	Stream           bool     `json:"stream,omitempty"` // Use GenerateReplyStream and report stream timings
+}
+
+func (r *TestRequest) validate() error {
+	if strings.TrimSpace(r.Prompt) == "" {
+		return errors.New("prompt is required")
+	}
+	if r.EnableFileSearch && strings.TrimSpace(r.FileStoreID) == "" {
+		return errors.New("file_store_id is required when enable_file_search is set")
+	}
+	return validateProvider(r.Provider)
+}
+
+// validateProvider checks a dashboard provider name; empty means Gemini.
+func validateProvider(name string) error {
+	switch strings.ToLower(name) {
+	case "", "gemini", "openai", "anthropic":
+		return nil
+	}
+	return fmt.Errorf("unknown provider %q (expected gemini, openai or anthropic)", name)
+}
+
+// TestCitation is a condensed citation returned by the test endpoint.
+type TestCitation struct {
+	Type     string `json:"type"`
+	URL      string `json:"url,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Filename string `json:"filename,omitempty"`
 }
 
 // TestResponse is the response from the test endpoint.
 type TestResponse struct {
-	Reply         string `json:"reply"`
-	Provider      string `json:"provider"`
-	Model         string `json:"model"`
-	InputTokens   int    `json:"input_tokens"`
-	OutputTokens  int    `json:"output_tokens"`
-	ProcessingMs  int64  `json:"processing_ms"`
-	Error         string `json:"error,omitempty"`
+	Reply              string          `json:"reply"`
+	Provider           string          `json:"provider"`
+	Model              string          `json:"model"`
+	InputTokens        int             `json:"input_tokens"`
+	OutputTokens       int             `json:"output_tokens"`
+	ProcessingMs       int64           `json:"processing_ms"`
+	Citations          []TestCitation  `json:"citations,omitempty"`
+	ToolCalls          []string        `json:"tool_calls,omitempty"`
+	StructuredMetadata json.RawMessage `json:"structured_metadata,omitempty"`
+	SafetyBlock        string          `json:"safety_block,omitempty"`
+	Streamed           bool            `json:"streamed,omitempty"`
+	FirstTokenMs       int64           `json:"first_token_ms,omitempty"`
+	Chunks             int             `json:"chunks,omitempty"`
+	Error              string          `json:"error,omitempty"`
 }
 
 // getGRPCClient lazily initializes the gRPC client.
@@ -427,9 +592,14 @@ func (s *Server) getGRPCClient() (pb.AirborneServiceClient, error) {
 		return nil, fmt.Errorf("gRPC address not configured")
 	}
 
-	conn, err := grpc.NewClient(s.grpcAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if s.signer != nil {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(s.signer.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(s.signer.StreamClientInterceptor()),
+		)
+	}
+	conn, err := grpc.NewClient(s.grpcAddr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
 	}
@@ -439,31 +609,26 @@ func (s *Server) getGRPCClient() (pb.AirborneServiceClient, error) {
 	return s.grpcClient, nil
 }
 
+// getFileClient returns a FileService client on the shared gRPC connection.
+func (s *Server) getFileClient() (pb.FileServiceClient, error) {
+	if _, err := s.getGRPCClient(); err != nil {
+		return nil, err
+	}
+	return pb.NewFileServiceClient(s.grpcConn), nil
+}
+
 // handleTest sends a test message to the AI service.
 // POST /admin/test
 // Body: {"prompt": "Hello", "tenant_id": "optional", "provider": "gemini"}
+// Optional: model, temperature, instructions, enable_web_search,
+// enable_file_search, file_store_id, structured_output, stream.
 func (s *Server) handleTest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Parse request body
 	var req TestRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSON(r, &req); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(TestResponse{
-			Error: "invalid request body: " + err.Error(),
-		})
-		return
-	}
-
-	if strings.TrimSpace(req.Prompt) == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(TestResponse{
-			Error: "prompt is required",
+			Error: err.Error(),
 		})
 		return
 	}
@@ -479,18 +644,33 @@ func (s *Server) handleTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build gRPC request
-	grpcReq := &pb.GenerateReplyRequest{
-		Instructions: "You are a helpful assistant. Respond concisely.",
-		UserInput:    req.Prompt,
-		TenantId:     req.TenantID,
-		ClientId:     "dashboard-test",
-		RequestId:    uuid.New().String(),
+	instructions := req.Instructions
+	if instructions == "" {
+		instructions = "You are a helpful assistant. Respond concisely."
 	}
 
-	// Set provider if specified
-	switch strings.ToLower(req.Provider) {
-	case "gemini", "":
+	// Build gRPC request
+	grpcReq := &pb.GenerateReplyRequest{
+		Instructions:           instructions,
+		UserInput:              req.Prompt,
+		TenantId:               req.TenantID,
+		ClientId:               "dashboard-test",
+		RequestId:              uuid.New().String(),
+		ModelOverride:          req.Model,
+		Preset:                 req.Preset,
+		EnableWebSearch:        req.EnableWebSearch,
+		EnableFileSearch:       req.EnableFileSearch,
+		FileStoreId:            req.FileStoreID,
+		EnableStructuredOutput: req.StructuredOutput,
+	}
+
+	// Set provider if specified; without one a preset picks its own
+	providerKey := strings.ToLower(req.Provider)
+	if providerKey == "" && req.Preset == "" {
+		providerKey = "gemini"
+	}
+	switch providerKey {
+	case "gemini":
 		grpcReq.PreferredProvider = pb.Provider_PROVIDER_GEMINI
 	case "openai":
 		grpcReq.PreferredProvider = pb.Provider_PROVIDER_OPENAI
@@ -498,6 +678,12 @@ func (s *Server) handleTest(w http.ResponseWriter, r *http.Request) {
 		grpcReq.PreferredProvider = pb.Provider_PROVIDER_ANTHROPIC
 	}
 
+	if req.Temperature != nil {
+		grpcReq.ProviderConfigs = map[string]*pb.ProviderConfig{
+			providerKey: {Temperature: req.Temperature},
+		}
+	}
+
 	// Add auth token to context
 	ctx := r.Context()
 	if s.authToken != "" {
@@ -508,12 +694,15 @@ func (s *Server) handleTest(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(ctx, 4*time.Minute)
 	defer cancel()
 
-	start := time.Now()
-
 	// Make gRPC call
-	resp, err := client.GenerateReply(ctx, grpcReq)
+	var result TestResponse
+	if req.Stream {
+		result, err = runTestStream(ctx, client, grpcReq)
+	} else {
+		result, err = runTest(ctx, client, grpcReq)
+	}
 	if err != nil {
-		slog.Error("test gRPC call failed", "error", err)
+		slog.Error("test gRPC call failed", "error", err, "stream", req.Stream)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK) // Return 200 with error in body
 		json.NewEncoder(w).Encode(TestResponse{
@@ -522,27 +711,132 @@ func (s *Server) handleTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	processingMs := time.Since(start).Milliseconds()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
 
-	// Extract token usage
-	var inputTokens, outputTokens int
+// runTest performs a unary GenerateReply call for the test endpoint.
+func runTest(ctx context.Context, client pb.AirborneServiceClient, grpcReq *pb.GenerateReplyRequest) (TestResponse, error) {
+	start := time.Now()
+	resp, err := client.GenerateReply(ctx, grpcReq)
+	if err != nil {
+		return TestResponse{}, err
+	}
+
+	result := TestResponse{
+		Reply:              resp.Text,
+		Provider:           providerLabel(resp.Provider),
+		Model:              resp.Model,
+		ProcessingMs:       time.Since(start).Milliseconds(),
+		Citations:          testCitations(resp.Citations),
+		ToolCalls:          testToolCalls(resp.ToolCalls),
+		StructuredMetadata: testStructuredMetadata(resp.StructuredMetadata),
+		SafetyBlock:        testSafetyBlock(resp.SafetyBlock),
+	}
 	if resp.Usage != nil {
-		inputTokens = int(resp.Usage.InputTokens)
-		outputTokens = int(resp.Usage.OutputTokens)
+		result.InputTokens = int(resp.Usage.InputTokens)
+		result.OutputTokens = int(resp.Usage.OutputTokens)
 	}
+	return result, nil
+}
 
-	// Convert provider enum to friendly string
-	providerName := strings.ToLower(strings.TrimPrefix(resp.Provider.String(), "PROVIDER_"))
+// runTestStream performs a GenerateReplyStream call for the test endpoint,
+// accumulating text deltas and recording time to first token.
+func runTestStream(ctx context.Context, client pb.AirborneServiceClient, grpcReq *pb.GenerateReplyRequest) (TestResponse, error) {
+	start := time.Now()
+	stream, err := client.GenerateReplyStream(ctx, grpcReq)
+	if err != nil {
+		return TestResponse{}, err
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(TestResponse{
-		Reply:        resp.Text,
-		Provider:     providerName,
-		Model:        resp.Model,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		ProcessingMs: processingMs,
-	})
+	result := TestResponse{Streamed: true}
+	var text strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return TestResponse{}, err
+		}
+		result.Chunks++
+
+		switch c := chunk.Chunk.(type) {
+		case *pb.GenerateReplyChunk_TextDelta:
+			if result.FirstTokenMs == 0 && c.TextDelta.GetText() != "" {
+				result.FirstTokenMs = time.Since(start).Milliseconds()
+			}
+			text.WriteString(c.TextDelta.GetText())
+		case *pb.GenerateReplyChunk_Error:
+			return TestResponse{}, fmt.Errorf("stream error %s: %s", c.Error.GetCode(), c.Error.GetMessage())
+		case *pb.GenerateReplyChunk_Complete:
+			done := c.Complete
+			result.Provider = providerLabel(done.Provider)
+			result.Model = done.Model
+			result.Citations = testCitations(done.Citations)
+			result.ToolCalls = testToolCalls(done.ToolCalls)
+			result.StructuredMetadata = testStructuredMetadata(done.StructuredMetadata)
+			result.SafetyBlock = testSafetyBlock(done.SafetyBlock)
+			if done.FinalUsage != nil {
+				result.InputTokens = int(done.FinalUsage.InputTokens)
+				result.OutputTokens = int(done.FinalUsage.OutputTokens)
+			}
+		}
+	}
+
+	result.Reply = text.String()
+	result.ProcessingMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// providerLabel converts a provider enum to its friendly lowercase name.
+func providerLabel(p pb.Provider) string {
+	return strings.ToLower(strings.TrimPrefix(p.String(), "PROVIDER_"))
+}
+
+func testCitations(citations []*pb.Citation) []TestCitation {
+	if len(citations) == 0 {
+		return nil
+	}
+	out := make([]TestCitation, 0, len(citations))
+	for _, c := range citations {
+		out = append(out, TestCitation{
+			Type:     strings.ToLower(strings.TrimPrefix(c.Type.String(), "TYPE_")),
+			URL:      c.Url,
+			Title:    c.Title,
+			Filename: c.Filename,
+		})
+	}
+	return out
+}
+
+func testToolCalls(calls []*pb.ToolCall) []string {
+	if len(calls) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(calls))
+	for _, c := range calls {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func testStructuredMetadata(md *pb.StructuredMetadata) json.RawMessage {
+	if md == nil {
+		return nil
+	}
+	raw, err := protojson.Marshal(md)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+func testSafetyBlock(b *pb.SafetyBlock) string {
+	if b == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s (%s)", b.Stage, b.Category, b.Reason)
 }
 
 // ChatRequest is the request body for the chat endpoint.
@@ -558,6 +852,19 @@ type ChatRequest struct {
 	RequestID    string `json:"request_id,omitempty"`     // Idempotency key for retry support
 }
 
+func (r *ChatRequest) validate() error {
+	if strings.TrimSpace(r.Message) == "" {
+		return errors.New("message is required")
+	}
+	if strings.TrimSpace(r.ThreadID) == "" {
+		return errors.New("thread_id is required")
+	}
+	if _, err := uuid.Parse(r.ThreadID); err != nil {
+		return errors.New("invalid thread_id format (must be UUID)")
+	}
+	return validateProvider(r.Provider)
+}
+
 // ChatResponse is the response from the chat endpoint.
 type ChatResponse struct {
 	ID               string  `json:"id,omitempty"`
@@ -577,50 +884,16 @@ type ChatResponse struct {
 // POST /admin/chat
 // Body: {"thread_id": "uuid", "message": "Hello", "tenant_id": "optional", "provider": "gemini"}
 func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Parse request body
 	var req ChatRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ChatResponse{
-			Error: "invalid request body: " + err.Error(),
-		})
-		return
-	}
-
-	if strings.TrimSpace(req.Message) == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ChatResponse{
-			Error: "message is required",
-		})
-		return
-	}
-
-	if strings.TrimSpace(req.ThreadID) == "" {
+	if err := decodeJSON(r, &req); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(ChatResponse{
-			Error: "thread_id is required",
-		})
-		return
-	}
-
-	// Validate thread_id is a valid UUID
-	threadUUID, err := uuid.Parse(req.ThreadID)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ChatResponse{
-			Error: "invalid thread_id format (must be UUID)",
+			Error: err.Error(),
 		})
 		return
 	}
+	threadUUID := uuid.MustParse(req.ThreadID) // Checked by validate
 
 	// Idempotency check: if request_id provided, check Redis for duplicate request
 	var idempKey string
@@ -868,21 +1141,19 @@ func buildCompressedHistory(dbMessages []db.Message, previousResponseID *string)
 
 // UploadResponse is the response from the upload endpoint.
 type UploadResponse struct {
-	FileURI  string `json:"file_uri,omitempty"`
-	Filename string `json:"filename,omitempty"`
-	MIMEType string `json:"mime_type,omitempty"`
-	Error    string `json:"error,omitempty"`
+	FileURI   string `json:"file_uri,omitempty"`
+	Filename  string `json:"filename,omitempty"`
+	MIMEType  string `json:"mime_type,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"` // When Gemini deletes the file (RFC 3339)
+	Error     string `json:"error,omitempty"`
 }
 
 // handleUpload uploads a file to Gemini Files API.
 // POST /admin/upload (multipart/form-data)
-// Returns the file URI for use in chat.
+// Returns the file URI for use in chat. With a thread_id the file is
+// registered with the thread and re-uploaded when a chat uses it after it
+// expires.
 func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// Parse multipart form (max 100MB)
 	if err := r.ParseMultipartForm(100 << 20); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -911,6 +1182,18 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 		tenantID = "email4ai" // Default tenant
 	}
 
+	var threadID uuid.UUID
+	if v := r.FormValue("thread_id"); v != "" {
+		if threadID, err = uuid.Parse(v); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(UploadResponse{
+				Error: "invalid thread_id format (must be UUID)",
+			})
+			return
+		}
+	}
+
 	// Get Gemini API key from tenant config
 	apiKey, err := s.getGeminiAPIKey(tenantID)
 	if err != nil {
@@ -922,17 +1205,49 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Detect MIME type
-	mimeType := header.Header.Get("Content-Type")
-	if mimeType == "" || mimeType == "application/octet-stream" {
-		mimeType = detectMIMEType(header.Filename)
+	// Check the content against the declared type and the tenant's allowlist
+	head := make([]byte, validation.SniffLen)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(UploadResponse{
+			Error: "failed to read file: " + err.Error(),
+		})
+		return
+	}
+	var allowed []string
+	if s.tenantMgr != nil {
+		if cfg, ok := s.tenantMgr.Tenant(tenantID); ok {
+			allowed = cfg.Uploads.AllowedTypes
+		}
+	}
+	mimeType, err := validation.CheckUpload(head[:n], header.Filename, header.Header.Get("Content-Type"), allowed)
+	if err != nil {
+		slog.Warn("rejected upload", "error", err, "filename", header.Filename, "tenant_id", tenantID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(UploadResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(UploadResponse{
+			Error: "failed to read file: " + err.Error(),
+		})
+		return
 	}
 
 	// Upload to Gemini Files API
 	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
 	defer cancel()
 
-	fileURI, err := s.uploadFileToGemini(ctx, apiKey, file, header.Filename, mimeType)
+	fileURI, expiresAt, err := s.uploadFileToGemini(ctx, apiKey, s.tenantProxy(tenantID), content, header.Filename, mimeType)
 	if err != nil {
 		slog.Error("failed to upload file to Gemini", "error", err, "filename", header.Filename)
 		w.Header().Set("Content-Type", "application/json")
@@ -949,14 +1264,429 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 		"file_uri", fileURI,
 	)
 
+	// Register the file with its thread so chats can use it after it expires
+	if threadID != uuid.Nil && s.dbClient != nil {
+		if repo, repoErr := s.dbClient.TenantRepository(tenantID); repoErr == nil {
+			if err := registerThreadFile(ctx, repo, threadID, fileURI, header.Filename, mimeType, content, expiresAt); err != nil {
+				slog.Warn("failed to register thread file", "error", err, "thread_id", threadID)
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(UploadResponse{
-		FileURI:  fileURI,
-		Filename: header.Filename,
-		MIMEType: mimeType,
+		FileURI:   fileURI,
+		Filename:  header.Filename,
+		MIMEType:  mimeType,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// ExtractResponse is the response from the extract endpoint.
+type ExtractResponse struct {
+	Filename   string            `json:"filename,omitempty"`
+	MIMEType   string            `json:"mime_type,omitempty"`
+	Text       string            `json:"text"`
+	Truncated  bool              `json:"truncated,omitempty"`
+	Format     string            `json:"format,omitempty"`
+	Fallback   bool              `json:"fallback,omitempty"`
+	PageCount  int32             `json:"page_count,omitempty"`
+	CharCount  int32             `json:"char_count"`
+	WordCount  int32             `json:"word_count"`
+	ChunkCount int32             `json:"chunk_count"`
+	Structure  *ExtractStructure `json:"structure,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// ExtractStructure is the layout detected in extracted text.
+type ExtractStructure struct {
+	Headings   []ExtractHeading `json:"headings"`
+	Paragraphs int32            `json:"paragraphs"`
+	ListItems  int32            `json:"list_items"`
+	Tables     int32            `json:"tables"`
+}
+
+// ExtractHeading is a heading found in extracted text.
+type ExtractHeading struct {
+	Level  int32  `json:"level"`
+	Text   string `json:"text"`
+	Offset int32  `json:"offset"`
+}
+
+// handleExtract previews RAG text extraction for a file without ingesting it.
+// POST /admin/extract (multipart/form-data)
+// Fields: file (required), tenant_id, max_text_chars.
+func (s *Server) handleExtract(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(ExtractResponse{Error: msg})
+	}
+
+	// Parse multipart form (max 100MB)
+	if err := r.ParseMultipartForm(100 << 20); err != nil {
+		writeError(http.StatusBadRequest, "failed to parse multipart form: "+err.Error())
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(http.StatusBadRequest, "failed to read file: "+err.Error())
+		return
+	}
+
+	var maxChars int64
+	if v := r.FormValue("max_text_chars"); v != "" {
+		maxChars, err = strconv.ParseInt(v, 10, 32)
+		if err != nil || maxChars < 0 {
+			writeError(http.StatusBadRequest, "max_text_chars must be a non-negative integer")
+			return
+		}
+	}
+
+	mimeType := header.Header.Get("Content-Type")
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = validation.MIMETypeForFilename(header.Filename)
+	}
+
+	client, err := s.getFileClient()
+	if err != nil {
+		writeError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if s.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.authToken)
+	}
+	if tenantID := r.FormValue("tenant_id"); tenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", tenantID)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	resp, err := client.ExtractText(ctx, &pb.ExtractTextRequest{
+		Content:      content,
+		Filename:     header.Filename,
+		MimeType:     mimeType,
+		MaxTextChars: int32(maxChars),
+	})
+	if err != nil {
+		slog.Error("extract gRPC call failed", "error", err, "filename", header.Filename)
+		writeError(http.StatusOK, err.Error()) // Return 200 with error in body
+		return
+	}
+
+	result := ExtractResponse{
+		Filename:   header.Filename,
+		MIMEType:   mimeType,
+		Text:       resp.Text,
+		Truncated:  resp.Truncated,
+		Format:     resp.Format,
+		Fallback:   resp.Fallback,
+		PageCount:  resp.PageCount,
+		CharCount:  resp.CharCount,
+		WordCount:  resp.WordCount,
+		ChunkCount: resp.ChunkCount,
+	}
+	if st := resp.Structure; st != nil {
+		result.Structure = &ExtractStructure{
+			Headings:   make([]ExtractHeading, 0, len(st.Headings)),
+			Paragraphs: st.Paragraphs,
+			ListItems:  st.ListItems,
+			Tables:     st.Tables,
+		}
+		for _, h := range st.Headings {
+			result.Structure.Headings = append(result.Structure.Headings, ExtractHeading{
+				Level:  h.Level,
+				Text:   h.Text,
+				Offset: h.Offset,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ReindexRequest starts re-embedding a RAG store.
+type ReindexRequest struct {
+	TenantID string `json:"tenant_id,omitempty"`
+	StoreID  string `json:"store_id"`
+}
+
+func (r *ReindexRequest) validate() error {
+	if r.StoreID == "" {
+		return errors.New("store_id is required")
+	}
+	return nil
+}
+
+// ReindexResponse is a RAG store's re-index progress.
+type ReindexResponse struct {
+	StoreID         string `json:"store_id,omitempty"`
+	State           string `json:"state,omitempty"`
+	Model           string `json:"model,omitempty"`
+	TotalChunks     int32  `json:"total_chunks"`
+	ReindexedChunks int32  `json:"reindexed_chunks"`
+	StartedAt       string `json:"started_at,omitempty"`
+	FinishedAt      string `json:"finished_at,omitempty"`
+	StoredModel     string `json:"stored_model,omitempty"`
+	NeedsReindex    bool   `json:"needs_reindex"`
+	Error           string `json:"error,omitempty"`
+}
+
+// handleReindex starts a RAG store re-index or reports its progress.
+// POST /admin/reindex
+// Body: {"tenant_id": "optional", "store_id": "docs"}
+// GET /admin/reindex?tenant_id=optional&store_id=docs
+func (s *Server) handleReindex(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(ReindexResponse{Error: msg})
+	}
+
+	var req ReindexRequest
+	var err error
+	if r.Method == http.MethodPost {
+		err = decodeJSON(r, &req)
+	} else {
+		req = ReindexRequest{
+			TenantID: r.URL.Query().Get("tenant_id"),
+			StoreID:  r.URL.Query().Get("store_id"),
+		}
+		err = req.validate()
+	}
+	if err != nil {
+		writeError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client, err := s.getFileClient()
+	if err != nil {
+		writeError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if s.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.authToken)
+	}
+	if req.TenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", req.TenantID)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var resp *pb.ReindexStatus
+	if r.Method == http.MethodPost {
+		resp, err = client.StartReindex(ctx, &pb.StartReindexRequest{StoreId: req.StoreID})
+	} else {
+		resp, err = client.GetReindexStatus(ctx, &pb.GetReindexStatusRequest{StoreId: req.StoreID})
+	}
+	if err != nil {
+		slog.Error("reindex gRPC call failed", "error", err, "store_id", req.StoreID)
+		writeError(http.StatusOK, err.Error()) // Return 200 with error in body
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReindexResponse{
+		StoreID:         resp.StoreId,
+		State:           resp.State,
+		Model:           resp.Model,
+		TotalChunks:     resp.TotalChunks,
+		ReindexedChunks: resp.ReindexedChunks,
+		StartedAt:       resp.StartedAt,
+		FinishedAt:      resp.FinishedAt,
+		StoredModel:     resp.StoredModel,
+		NeedsReindex:    resp.NeedsReindex,
+		Error:           resp.Error,
+	})
+}
+
+// StoreImportResponse is the result of importing a RAG store snapshot.
+type StoreImportResponse struct {
+	StoreID       string `json:"store_id,omitempty"`
+	ChunkCount    int32  `json:"chunk_count"`
+	Reembedded    bool   `json:"reembedded"`
+	SourceStoreID string `json:"source_store_id,omitempty"`
+	SourceModel   string `json:"source_model,omitempty"`
+	ExportedAt    string `json:"exported_at,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// handleStoreExport downloads a RAG store snapshot archive.
+// GET /admin/store/export?tenant_id=optional&store_id=docs&include_vectors=true
+func (s *Server) handleStoreExport(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: msg})
+	}
+
+	storeID := r.URL.Query().Get("store_id")
+	if storeID == "" {
+		writeError(http.StatusBadRequest, "store_id is required")
+		return
+	}
+	includeVectors, _ := strconv.ParseBool(r.URL.Query().Get("include_vectors"))
+
+	client, err := s.getFileClient()
+	if err != nil {
+		writeError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if s.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.authToken)
+	}
+	if tenantID := r.URL.Query().Get("tenant_id"); tenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", tenantID)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	stream, err := client.ExportStore(ctx, &pb.ExportStoreRequest{StoreId: storeID, IncludeVectors: includeVectors})
+	if err != nil {
+		slog.Error("store export gRPC call failed", "error", err, "store_id", storeID)
+		writeError(http.StatusOK, err.Error()) // Return 200 with error in body
+		return
+	}
+
+	// Wait for the first chunk so errors can still be reported as JSON
+	chunk, err := stream.Recv()
+	if err != nil {
+		slog.Error("store export gRPC call failed", "error", err, "store_id", storeID)
+		writeError(http.StatusOK, err.Error()) // Return 200 with error in body
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", storeID+".snapshot.jsonl.gz"))
+	for {
+		if _, err := w.Write(chunk.Data); err != nil {
+			return
+		}
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// Headers are already sent; the truncated archive fails import validation
+			slog.Error("store export stream failed", "error", err, "store_id", storeID)
+			return
+		}
+	}
+}
+
+// handleStoreImport uploads a RAG store snapshot archive.
+// POST /admin/store/import (multipart/form-data)
+// Fields: file (required), tenant_id, store_id, overwrite.
+func (s *Server) handleStoreImport(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(StoreImportResponse{Error: msg})
+	}
+
+	// Parse multipart form (max 100MB)
+	if err := r.ParseMultipartForm(100 << 20); err != nil {
+		writeError(http.StatusBadRequest, "failed to parse multipart form: "+err.Error())
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	overwrite, _ := strconv.ParseBool(r.FormValue("overwrite"))
+	storeID := r.FormValue("store_id")
+
+	client, err := s.getFileClient()
+	if err != nil {
+		writeError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if s.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.authToken)
+	}
+	if tenantID := r.FormValue("tenant_id"); tenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", tenantID)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	resp, err := importStoreSnapshot(ctx, client, file, storeID, overwrite)
+	if err != nil {
+		slog.Error("store import gRPC call failed", "error", err, "store_id", storeID)
+		writeError(http.StatusOK, err.Error()) // Return 200 with error in body
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(StoreImportResponse{
+		StoreID:       resp.StoreId,
+		ChunkCount:    resp.ChunkCount,
+		Reembedded:    resp.Reembedded,
+		SourceStoreID: resp.SourceStoreId,
+		SourceModel:   resp.SourceModel,
+		ExportedAt:    resp.ExportedAt,
 	})
 }
 
+// importStoreSnapshot streams a snapshot archive to the ImportStore RPC.
+func importStoreSnapshot(ctx context.Context, client pb.FileServiceClient, file io.Reader, storeID string, overwrite bool) (*pb.ImportStoreResponse, error) {
+	stream, err := client.ImportStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&pb.ImportStoreRequest{
+		Data: &pb.ImportStoreRequest_Metadata{
+			Metadata: &pb.ImportStoreMetadata{StoreId: storeID, Overwrite: overwrite},
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, readErr := file.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.ImportStoreRequest{
+				Data: &pb.ImportStoreRequest_Chunk{Chunk: buf[:n]},
+			}); err != nil {
+				// The server's status is surfaced by CloseAndRecv
+				if err == io.EOF {
+					break
+				}
+				return nil, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read file: %w", readErr)
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
 // getGeminiAPIKey retrieves the Gemini API key for a tenant.
 func (s *Server) getGeminiAPIKey(tenantID string) (string, error) {
 	if s.tenantMgr == nil {
@@ -980,23 +1710,31 @@ func (s *Server) getGeminiAPIKey(tenantID string) (string, error) {
 	return providerCfg.APIKey, nil
 }
 
-// uploadFileToGemini uploads a file to Gemini Files API and returns the URI.
-func (s *Server) uploadFileToGemini(ctx context.Context, apiKey string, file multipart.File, filename, mimeType string) (string, error) {
+// tenantProxy returns the tenant's outbound proxy, or nil to use the
+// server-wide proxy.
+func (s *Server) tenantProxy(tenantID string) *egress.ProxyConfig {
+	if s.tenantMgr == nil {
+		return nil
+	}
+	if tenantCfg, ok := s.tenantMgr.Tenant(tenantID); ok {
+		return tenantCfg.Proxy
+	}
+	return nil
+}
+
+// uploadFileToGemini uploads a file to Gemini Files API and returns its URI
+// and expiry.
+func (s *Server) uploadFileToGemini(ctx context.Context, apiKey string, proxy *egress.ProxyConfig, content []byte, filename, mimeType string) (string, time.Time, error) {
 	// Create Gemini client
 	clientConfig := &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: egress.Client(proxy, 0),
 	}
 
 	client, err := genai.NewClient(ctx, clientConfig)
 	if err != nil {
-		return "", fmt.Errorf("create Gemini client: %w", err)
-	}
-
-	// Read file content
-	content, err := io.ReadAll(file)
-	if err != nil {
-		return "", fmt.Errorf("read file: %w", err)
+		return "", time.Time{}, fmt.Errorf("create Gemini client: %w", err)
 	}
 
 	// Upload file
@@ -1007,7 +1745,7 @@ func (s *Server) uploadFileToGemini(ctx context.Context, apiKey string, file mul
 
 	uploadedFile, err := client.Files.Upload(ctx, bytes.NewReader(content), uploadConfig)
 	if err != nil {
-		return "", fmt.Errorf("upload file: %w", err)
+		return "", time.Time{}, fmt.Errorf("upload file: %w", err)
 	}
 
 	// Wait for file to be processed
@@ -1016,57 +1754,22 @@ func (s *Server) uploadFileToGemini(ctx context.Context, apiKey string, file mul
 			time.Sleep(2 * time.Second)
 			uploadedFile, err = client.Files.Get(ctx, uploadedFile.Name, nil)
 			if err != nil {
-				return "", fmt.Errorf("get file status: %w", err)
+				return "", time.Time{}, fmt.Errorf("get file status: %w", err)
 			}
 			if uploadedFile.State == genai.FileStateActive {
 				break
 			}
 			if uploadedFile.State == genai.FileStateFailed {
-				return "", fmt.Errorf("file processing failed")
+				return "", time.Time{}, fmt.Errorf("file processing failed")
 			}
 		}
 	}
 
-	return uploadedFile.URI, nil
-}
-
-// detectMIMEType guesses MIME type from filename extension.
-func detectMIMEType(filename string) string {
-	ext := strings.ToLower(filename)
-	if idx := strings.LastIndex(ext, "."); idx != -1 {
-		ext = ext[idx:]
-	}
-
-	mimeTypes := map[string]string{
-		".pdf":  "application/pdf",
-		".txt":  "text/plain",
-		".md":   "text/markdown",
-		".csv":  "text/csv",
-		".json": "application/json",
-		".xml":  "application/xml",
-		".html": "text/html",
-		".png":  "image/png",
-		".jpg":  "image/jpeg",
-		".jpeg": "image/jpeg",
-		".gif":  "image/gif",
-		".webp": "image/webp",
-		".svg":  "image/svg+xml",
-		".mp3":  "audio/mpeg",
-		".wav":  "audio/wav",
-		".mp4":  "video/mp4",
-		".webm": "video/webm",
-		".doc":  "application/msword",
-		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
-		".xls":  "application/vnd.ms-excel",
-		".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
-		".ppt":  "application/vnd.ms-powerpoint",
-		".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
-	}
-
-	if mt, ok := mimeTypes[ext]; ok {
-		return mt
-	}
-	return "application/octet-stream"
+	expiresAt := uploadedFile.ExpirationTime
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(geminiFileTTL)
+	}
+	return uploadedFile.URI, expiresAt, nil
 }
 
 // ChatWithFileRequest extends ChatRequest with file support.
@@ -1132,6 +1835,30 @@ func (s *Server) handleChatWithFile(w http.ResponseWriter, r *http.Request, req
 		systemPrompt = systemPrompt + "\n\n[Note: Previous conversation messages are provided for context. Focus on the most recent user message.]"
 	}
 
+	// Swap a registered file that Gemini has expired for a new upload
+	if req.FileURI != "" && s.dbClient != nil && req.TenantID != "" {
+		if repo, repoErr := s.dbClient.TenantRepository(req.TenantID); repoErr == nil {
+			upload := func(ctx context.Context, content []byte, filename, mimeType string) (string, time.Time, error) {
+				return s.uploadFileToGemini(ctx, apiKey, s.tenantProxy(req.TenantID), content, filename, mimeType)
+			}
+			file, err := resolveThreadFile(r.Context(), repo, threadUUID, req.FileURI, upload, time.Now())
+			if err != nil {
+				slog.Error("failed to resolve thread file", "error", err, "thread_id", req.ThreadID)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK) // Return 200 with error in body
+				json.NewEncoder(w).Encode(ChatResponse{
+					Error: err.Error(),
+				})
+				return
+			}
+			if file != nil {
+				req.FileURI = file.FileURI
+				req.FileMIMEType = file.MIMEType
+				req.Filename = file.Filename
+			}
+		}
+	}
+
 	// Build inline images (files)
 	var inlineImages []provider.InlineImage
 	if req.FileURI != "" {
@@ -1254,6 +1981,7 @@ func (s *Server) handleChatWithFile(w http.ResponseWriter, r *http.Request, req
 					SystemPrompt:     &systemPrompt,
 					RawRequestJSON:   rawRequestJSON,
 					RawResponseJSON:  rawResponseJSON,
+					Metadata:         db.FinalPromptMetadata(result.SystemPrompt, result.UserPrompt),
 				}
 				if err := repo.CreateMessage(r.Context(), assistantMsg); err != nil {
 					slog.Warn("failed to save assistant message", "error", err)
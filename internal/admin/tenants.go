@@ -0,0 +1,232 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// TenantProviderSettings is a provider's editable settings. The API key is
+// never returned, only whether one is configured.
+type TenantProviderSettings struct {
+	Enabled          bool     `json:"enabled"`
+	Model            string   `json:"model"`
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	MaxOutputTokens  *int     `json:"max_output_tokens,omitempty"`
+	AllowedModels    []string `json:"allowed_models,omitempty"`
+	APIKeyConfigured bool     `json:"api_key_configured"`
+}
+
+// TenantFeatureSettings are a tenant's optional features. Flags holds every
+// known feature flag with its effective value.
+type TenantFeatureSettings struct {
+	ImageGeneration bool            `json:"image_generation"`
+	EventStream     bool            `json:"event_stream"`
+	Flags           map[string]bool `json:"flags"`
+}
+
+// TenantSettings is the runtime-editable part of a tenant's config.
+type TenantSettings struct {
+	TenantID  string                            `json:"tenant_id"`
+	Providers map[string]TenantProviderSettings `json:"providers"`
+	Failover  tenant.FailoverConfig             `json:"failover"`
+	Features  TenantFeatureSettings             `json:"features"`
+}
+
+// TenantSettingsResponse is the response from the tenant settings endpoint.
+type TenantSettingsResponse struct {
+	Settings *TenantSettings        `json:"settings,omitempty"`
+	Override *tenant.SettingsUpdate `json:"override,omitempty"` // Runtime changes on top of the config file
+	Error    string                 `json:"error,omitempty"`
+}
+
+// TenantSettingsRequest changes a tenant's settings. Only fields present in
+// the body are changed.
+type TenantSettingsRequest struct {
+	Actor  string `json:"actor"`            // Who is making the change, for the audit trail
+	Reason string `json:"reason,omitempty"` // Why, for the audit trail
+	tenant.SettingsUpdate
+}
+
+func (r *TenantSettingsRequest) validate() error {
+	if strings.TrimSpace(r.Actor) == "" {
+		return errors.New("actor is required")
+	}
+	if r.IsEmpty() {
+		return errors.New("no settings to change")
+	}
+	return nil
+}
+
+// TenantAuditResponse is the response from the tenant audit endpoint.
+type TenantAuditResponse struct {
+	Entries []db.SettingsAuditEntry `json:"entries"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// tenantSettings extracts the editable settings from cfg.
+func tenantSettings(cfg tenant.TenantConfig) TenantSettings {
+	settings := TenantSettings{
+		TenantID:  cfg.TenantID,
+		Providers: make(map[string]TenantProviderSettings, len(cfg.Providers)),
+		Failover:  cfg.Failover,
+		Features: TenantFeatureSettings{
+			ImageGeneration: cfg.ImageGeneration.Enabled,
+			EventStream:     cfg.EventStream.Enabled,
+			Flags:           make(map[string]bool, len(tenant.Features)),
+		},
+	}
+	for _, name := range tenant.Features {
+		settings.Features.Flags[name] = cfg.FeatureEnabled(name)
+	}
+	for name, p := range cfg.Providers {
+		settings.Providers[name] = TenantProviderSettings{
+			Enabled:          p.Enabled,
+			Model:            p.Model,
+			Temperature:      p.Temperature,
+			TopP:             p.TopP,
+			MaxOutputTokens:  p.MaxOutputTokens,
+			AllowedModels:    p.AllowedModels,
+			APIKeyConfigured: p.APIKey != "",
+		}
+	}
+	return settings
+}
+
+// handleTenantSettings shows or changes a tenant's provider, failover and
+// feature settings. Changes apply immediately, are persisted as overrides on
+// top of the config file and are recorded in the audit trail.
+// GET /admin/tenants/{tenant_id}/settings
+// POST /admin/tenants/{tenant_id}/settings
+// Body: {"actor": "ops@example.com", "reason": "...", "providers": {"openai": {"model": "gpt-4o"}}}
+func (s *Server) handleTenantSettings(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(TenantSettingsResponse{Error: msg})
+	}
+
+	if s.tenantMgr == nil {
+		writeError(http.StatusServiceUnavailable, "tenant manager not configured")
+		return
+	}
+	tenantID := r.PathValue("tenant_id")
+	cfg, ok := s.tenantMgr.Tenant(tenantID)
+	if !ok {
+		writeError(http.StatusNotFound, "tenant not found: "+tenantID)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		settings := tenantSettings(cfg)
+		resp := TenantSettingsResponse{Settings: &settings}
+		if override, ok := s.tenantMgr.Override(tenantID); ok {
+			resp.Override = &override
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	var req TenantSettingsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(http.StatusBadRequest, err.Error())
+		return
+	}
+	// Every change must be audited, so changes need the database
+	if s.dbClient == nil {
+		writeError(http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+
+	// Serialize changes so the persisted override matches the live one
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+
+	cfg, ok = s.tenantMgr.Tenant(tenantID)
+	if !ok {
+		writeError(http.StatusNotFound, "tenant not found: "+tenantID)
+		return
+	}
+	if _, _, err := s.tenantMgr.PreviewSettings(tenantID, req.SettingsUpdate); err != nil {
+		writeError(http.StatusBadRequest, err.Error())
+		return
+	}
+	previous, hadOverride := s.tenantMgr.Override(tenantID)
+	updated, merged, err := s.tenantMgr.UpdateSettings(tenantID, req.SettingsUpdate)
+	if err != nil {
+		// Only possible if a reload changed the tenant since the preview
+		writeError(http.StatusConflict, err.Error())
+		return
+	}
+
+	change, _ := json.Marshal(req.SettingsUpdate)
+	beforeJSON, _ := json.Marshal(tenantSettings(cfg))
+	afterJSON, _ := json.Marshal(tenantSettings(updated))
+	overrideJSON, _ := json.Marshal(merged)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := db.NewRepository(s.dbClient).SaveSettingsChange(ctx, db.SettingsChange{
+		TenantID:   tenantID,
+		Actor:      req.Actor,
+		RemoteAddr: r.RemoteAddr,
+		Reason:     req.Reason,
+		Change:     string(change),
+		Before:     string(beforeJSON),
+		After:      string(afterJSON),
+		Override:   string(overrideJSON),
+	}); err != nil {
+		// Unaudited changes must not stay live
+		s.tenantMgr.RevertSettings(tenantID, cfg, previous, hadOverride)
+		slog.Error("failed to persist tenant settings change", "error", err, "tenant_id", tenantID)
+		writeError(http.StatusInternalServerError, "failed to persist change: "+err.Error())
+		return
+	}
+	slog.Info("tenant settings changed",
+		"tenant_id", tenantID,
+		"actor", req.Actor,
+		"change", string(change),
+	)
+
+	settings := tenantSettings(updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TenantSettingsResponse{Settings: &settings, Override: &merged})
+}
+
+// handleTenantAudit returns a tenant's settings changes, newest first.
+// GET /admin/tenants/{tenant_id}/audit?limit=50
+func (s *Server) handleTenantAudit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.dbClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(TenantAuditResponse{Entries: []db.SettingsAuditEntry{}, Error: "database not configured"})
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	entries, err := db.NewRepository(s.dbClient).GetSettingsAudit(ctx, r.PathValue("tenant_id"), limit)
+	if err != nil {
+		slog.Error("failed to fetch settings audit", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(TenantAuditResponse{Entries: []db.SettingsAuditEntry{}, Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(TenantAuditResponse{Entries: entries})
+}
@@ -0,0 +1,82 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/google/uuid"
+)
+
+// geminiFileTTL is how long the Gemini Files API keeps an upload, assumed
+// when an upload reports no expiration time.
+const geminiFileTTL = 48 * time.Hour
+
+// threadFileRenewMargin re-uploads a thread file this long before it
+// expires, so it cannot expire while a chat is using it.
+const threadFileRenewMargin = 10 * time.Minute
+
+// maxThreadFileContent is the largest file whose content the thread file
+// registry keeps for re-upload.
+const maxThreadFileContent = 20 << 20
+
+// errThreadFileExpired is returned for an expired file whose content was
+// too large to keep.
+var errThreadFileExpired = errors.New("the attached file has expired, upload it again")
+
+// fileUploader uploads content to the Gemini Files API and returns the
+// file's URI and expiry.
+type fileUploader func(ctx context.Context, content []byte, filename, mimeType string) (string, time.Time, error)
+
+// registerThreadFile records a file uploaded for a thread, creating the
+// thread if it does not exist yet.
+func registerThreadFile(ctx context.Context, repo *db.Repository, threadID uuid.UUID, uri, filename, mimeType string, content []byte, expiresAt time.Time) error {
+	if _, err := repo.GetOrCreateThread(ctx, threadID, "dashboard-user"); err != nil {
+		return err
+	}
+	f := &db.ThreadFile{
+		ID:          uuid.New(),
+		ThreadID:    threadID,
+		OriginalURI: uri,
+		Filename:    filename,
+		MIMEType:    mimeType,
+		SizeBytes:   int64(len(content)),
+		ExpiresAt:   expiresAt,
+	}
+	if len(content) <= maxThreadFileContent {
+		f.Content = content
+	}
+	return repo.AddThreadFile(ctx, f)
+}
+
+// resolveThreadFile looks up a file URI referenced by a chat in the
+// thread's registry. A file that has expired, or is about to, is uploaded
+// again with upload and returned with its new URI. It returns nil for URIs
+// the thread has not registered.
+func resolveThreadFile(ctx context.Context, repo *db.Repository, threadID uuid.UUID, uri string, upload fileUploader, now time.Time) (*db.ThreadFile, error) {
+	f, err := repo.GetThreadFile(ctx, threadID, uri)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	if now.Add(threadFileRenewMargin).Before(f.ExpiresAt) {
+		return f, nil
+	}
+	if f.Content == nil {
+		return nil, errThreadFileExpired
+	}
+
+	newURI, expiresAt, err := upload(ctx, f.Content, f.Filename, f.MIMEType)
+	if err != nil {
+		return nil, fmt.Errorf("re-upload expired file: %w", err)
+	}
+	if err := repo.UpdateThreadFileURI(ctx, f.ID, newURI, expiresAt); err != nil {
+		// The new upload still serves this chat; the next one uploads again
+		slog.WarnContext(ctx, "failed to record re-uploaded thread file", "error", err, "thread_id", threadID)
+	}
+	slog.InfoContext(ctx, "re-uploaded expired thread file", "thread_id", threadID, "filename", f.Filename, "file_uri", newURI)
+	f.FileURI, f.ExpiresAt = newURI, expiresAt
+	return f, nil
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestResolveThreadFile(t *testing.T) {
+	ctx := context.Background()
+	client, err := db.NewClient(ctx, db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	repo, _ := client.TenantRepository("ai8")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	threadID := uuid.New()
+	if err := registerThreadFile(ctx, repo, threadID, "files/a", "a.pdf", "application/pdf", []byte("pdf"), now.Add(time.Hour)); err != nil {
+		t.Fatalf("registerThreadFile failed: %v", err)
+	}
+	large := make([]byte, maxThreadFileContent+1)
+	if err := registerThreadFile(ctx, repo, threadID, "files/big", "big.pdf", "application/pdf", large, now.Add(time.Hour)); err != nil {
+		t.Fatalf("registerThreadFile failed: %v", err)
+	}
+
+	uploads := 0
+	upload := func(ctx context.Context, content []byte, filename, mimeType string) (string, time.Time, error) {
+		uploads++
+		if string(content) != "pdf" || filename != "a.pdf" || mimeType != "application/pdf" {
+			t.Errorf("unexpected re-upload: %q %s %s", content, filename, mimeType)
+		}
+		return "files/a2", now.Add(3 * time.Hour), nil
+	}
+
+	// Unexpired files and unknown URIs are used as they are
+	if f, err := resolveThreadFile(ctx, repo, threadID, "files/a", upload, now); err != nil || f.FileURI != "files/a" {
+		t.Fatalf("fresh file = %+v, %v", f, err)
+	}
+	if f, err := resolveThreadFile(ctx, repo, threadID, "files/other", upload, now); err != nil || f != nil {
+		t.Fatalf("unknown file = %+v, %v", f, err)
+	}
+
+	// Within the renewal margin the file is uploaded again, once
+	later := now.Add(time.Hour - threadFileRenewMargin/2)
+	for range 2 {
+		f, err := resolveThreadFile(ctx, repo, threadID, "files/a", upload, later)
+		if err != nil || f.FileURI != "files/a2" {
+			t.Fatalf("expired file = %+v, %v", f, err)
+		}
+	}
+	if uploads != 1 {
+		t.Errorf("expected one re-upload, got %d", uploads)
+	}
+
+	if _, err := resolveThreadFile(ctx, repo, threadID, "files/big", upload, later); !errors.Is(err, errThreadFileExpired) {
+		t.Errorf("expired file without content: expected errThreadFileExpired, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+// Package anomaly flags unusual usage by client keys: token usage far above
+// the key's recent average, a high share of failed calls, and calls at an
+// hour of the day the key has not been used at before. Each key is compared
+// with its own history, which is kept in memory and relearned after a
+// restart.
+package anomaly
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Kinds of anomaly.
+const (
+	KindTokenSpike = "token_spike"
+	KindErrorRate  = "error_rate"
+	KindOddHour    = "odd_hour"
+)
+
+// Defaults for Thresholds fields left at zero.
+const (
+	DefaultSpikeFactor  = 10
+	DefaultMaxErrorRate = 0.5
+)
+
+const (
+	// HistoryHours is how many hours of usage are kept per key. A key is
+	// only checked for odd hours once it has been seen for this long.
+	HistoryHours = 7 * 24
+
+	// BaselineHours is the span a key's average hourly token usage is taken
+	// over. A key is only checked for spikes once it has been seen for this
+	// long.
+	BaselineHours = 24
+
+	// MinSpikeTokens is the token usage an hour needs before it can be a
+	// spike, so keys with little traffic do not alert on small jumps.
+	MinSpikeTokens = 10000
+
+	// MinErrorCalls is how many calls an hour needs before its error rate
+	// can be abnormal.
+	MinErrorCalls = 20
+
+	// Cooldown is the minimum time between two alerts of the same kind for
+	// a key.
+	Cooldown = time.Hour
+)
+
+// Thresholds configure the checks for one observation. Zero values use the
+// defaults.
+type Thresholds struct {
+	SpikeFactor  float64 // Hourly tokens over the baseline average that count as a spike
+	MaxErrorRate float64 // Share of failed calls in the hour that is abnormal
+	SkipOddHours bool    // Do not check the hour of day
+}
+
+// Observation is one provider call made with a client key.
+type Observation struct {
+	TenantID string
+	ClientID string
+	Tokens   int64 // Input plus output tokens
+	Failed   bool
+}
+
+// Anomaly is unusual usage detected for a client key.
+type Anomaly struct {
+	Kind     string
+	TenantID string
+	ClientID string
+	Detail   string  // Human-readable description
+	Current  float64 // Tokens this hour, error rate this hour, or calls at this hour of day
+	Baseline float64 // Average hourly tokens, the maximum error rate, or 0
+	At       time.Time
+}
+
+// bucket is one hour of a key's usage.
+type bucket struct {
+	hour   int64 // Unix hour the counts belong to
+	calls  int64
+	errors int64
+	tokens int64
+}
+
+// keyState is the tracked usage of one client key.
+type keyState struct {
+	firstSeen time.Time
+	buckets   [HistoryHours]bucket
+	lastAlert map[string]time.Time
+}
+
+// count returns the bucket for hour if it still holds that hour's usage.
+func (k *keyState) count(hour int64) bucket {
+	b := k.buckets[hour%HistoryHours]
+	if b.hour != hour {
+		return bucket{hour: hour}
+	}
+	return b
+}
+
+type key struct {
+	tenantID string
+	clientID string
+}
+
+// Detector tracks usage per (tenant, client key). It is safe for concurrent
+// use.
+type Detector struct {
+	now func() time.Time
+
+	mu   sync.Mutex
+	keys map[key]*keyState
+}
+
+// NewDetector creates an empty detector.
+func NewDetector() *Detector {
+	return &Detector{now: time.Now, keys: make(map[key]*keyState)}
+}
+
+// Observe records a call and returns the anomalies it raises. An anomaly is
+// raised at most once per Cooldown for each key and kind.
+func (d *Detector) Observe(o Observation, t Thresholds) []Anomaly {
+	if t.SpikeFactor <= 0 {
+		t.SpikeFactor = DefaultSpikeFactor
+	}
+	if t.MaxErrorRate <= 0 {
+		t.MaxErrorRate = DefaultMaxErrorRate
+	}
+	now := d.now()
+	hour := now.Unix() / int64(time.Hour/time.Second)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	k := key{o.TenantID, o.ClientID}
+	st, ok := d.keys[k]
+	if !ok {
+		st = &keyState{firstSeen: now, lastAlert: make(map[string]time.Time)}
+		d.keys[k] = st
+	}
+
+	b := &st.buckets[hour%HistoryHours]
+	if b.hour != hour {
+		*b = bucket{hour: hour}
+	}
+	b.calls++
+	b.tokens += o.Tokens
+	if o.Failed {
+		b.errors++
+	}
+	current := *b
+	seen := now.Sub(st.firstSeen)
+
+	var found []Anomaly
+	raise := func(kind, detail string, currentValue, baseline float64) {
+		if last, ok := st.lastAlert[kind]; ok && now.Sub(last) < Cooldown {
+			return
+		}
+		st.lastAlert[kind] = now
+		found = append(found, Anomaly{
+			Kind:     kind,
+			TenantID: o.TenantID,
+			ClientID: o.ClientID,
+			Detail:   detail,
+			Current:  currentValue,
+			Baseline: baseline,
+			At:       now,
+		})
+	}
+
+	if seen >= BaselineHours*time.Hour && current.tokens >= MinSpikeTokens {
+		var total int64
+		for h := hour - BaselineHours; h < hour; h++ {
+			total += st.count(h).tokens
+		}
+		baseline := float64(total) / BaselineHours
+		if baseline > 0 && float64(current.tokens) >= t.SpikeFactor*baseline {
+			raise(KindTokenSpike, fmt.Sprintf("%d tokens this hour, %.0fx the %.0f tokens per hour of the last %d hours",
+				current.tokens, float64(current.tokens)/baseline, baseline, BaselineHours), float64(current.tokens), baseline)
+		}
+	}
+
+	if current.calls >= MinErrorCalls {
+		rate := float64(current.errors) / float64(current.calls)
+		if rate >= t.MaxErrorRate {
+			raise(KindErrorRate, fmt.Sprintf("%d of %d calls failed this hour", current.errors, current.calls), rate, t.MaxErrorRate)
+		}
+	}
+
+	if !t.SkipOddHours && seen >= HistoryHours*time.Hour {
+		// The same hour on each earlier day still in the history
+		var calls int64
+		days := 0
+		for h := hour - 24; h > hour-HistoryHours; h -= 24 {
+			calls += st.count(h).calls
+			days++
+		}
+		if calls == 0 {
+			raise(KindOddHour, fmt.Sprintf("used at %02d:00 UTC, an hour it was not used at on the previous %d days", now.UTC().Hour(), days),
+				float64(current.calls), 0)
+		}
+	}
+	return found
+}
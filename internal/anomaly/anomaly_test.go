@@ -0,0 +1,101 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestDetector(now *time.Time) *Detector {
+	d := NewDetector()
+	d.now = func() time.Time { return *now }
+	return d
+}
+
+func kinds(found []Anomaly) []string {
+	var out []string
+	for _, a := range found {
+		out = append(out, a.Kind)
+	}
+	return out
+}
+
+func TestDetector_TokenSpike(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 30, 0, 0, time.UTC)
+	d := newTestDetector(&now)
+	obs := Observation{TenantID: "acme", ClientID: "billing", Tokens: 2000}
+
+	// A day of steady usage
+	for range BaselineHours {
+		if found := d.Observe(obs, Thresholds{SkipOddHours: true}); len(found) != 0 {
+			t.Fatalf("steady usage raised %v", kinds(found))
+		}
+		now = now.Add(time.Hour)
+	}
+
+	// 9x the average is not a spike
+	obs.Tokens = 18000
+	if found := d.Observe(obs, Thresholds{SkipOddHours: true}); len(found) != 0 {
+		t.Fatalf("9x usage raised %v", kinds(found))
+	}
+	found := d.Observe(obs, Thresholds{SkipOddHours: true})
+	if len(found) != 1 || found[0].Kind != KindTokenSpike || found[0].Current != 36000 || found[0].Baseline != 2000 || found[0].ClientID != "billing" {
+		t.Fatalf("18x usage raised %+v", found)
+	}
+
+	// Alerts are not repeated within the cooldown
+	if found := d.Observe(obs, Thresholds{SkipOddHours: true}); len(found) != 0 {
+		t.Errorf("repeated alert %v", kinds(found))
+	}
+
+	// Other keys have their own baseline
+	other := Observation{TenantID: "acme", ClientID: "new-key", Tokens: 50000}
+	if found := d.Observe(other, Thresholds{}); len(found) != 0 {
+		t.Errorf("key without history raised %v", kinds(found))
+	}
+}
+
+func TestDetector_ErrorRate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	d := newTestDetector(&now)
+	obs := Observation{TenantID: "acme", ClientID: "billing", Tokens: 10}
+
+	var raised []Anomaly
+	for i := range MinErrorCalls {
+		obs.Failed = i%4 == 0
+		raised = append(raised, d.Observe(obs, Thresholds{})...)
+	}
+	if len(raised) != 0 {
+		t.Fatalf("25%% errors raised %v", kinds(raised))
+	}
+	obs.Failed = true
+	if found := d.Observe(obs, Thresholds{MaxErrorRate: 0.25}); len(found) != 1 || found[0].Kind != KindErrorRate {
+		t.Fatalf("errors over a 0.25 maximum raised %+v", found)
+	}
+}
+
+func TestDetector_OddHour(t *testing.T) {
+	now := time.Date(2026, 10, 10, 9, 0, 0, 0, time.UTC)
+	d := newTestDetector(&now)
+	obs := Observation{TenantID: "acme", ClientID: "billing", Tokens: 100}
+
+	// A week of use during office hours
+	for range 7 {
+		for hour := 9; hour < 18; hour++ {
+			d.Observe(obs, Thresholds{})
+			now = now.Add(time.Hour)
+		}
+		now = now.Add(15 * time.Hour)
+	}
+
+	if found := d.Observe(obs, Thresholds{}); len(found) != 0 {
+		t.Fatalf("use at 09:00 raised %v", kinds(found))
+	}
+	now = now.Add(18 * time.Hour)
+	if found := d.Observe(obs, Thresholds{SkipOddHours: true}); len(found) != 0 {
+		t.Fatalf("skipped check raised %v", kinds(found))
+	}
+	found := d.Observe(obs, Thresholds{})
+	if len(found) != 1 || found[0].Kind != KindOddHour || found[0].Detail != "used at 03:00 UTC, an hour it was not used at on the previous 6 days" {
+		t.Fatalf("use at 03:00 raised %+v", found)
+	}
+}
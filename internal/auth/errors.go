@@ -18,6 +18,10 @@ var (
 	// ErrRateLimitExceeded indicates rate limit was exceeded
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 
+	// ErrRateLimiterUnavailable indicates limits could not be checked and
+	// the configured policy is to fail closed
+	ErrRateLimiterUnavailable = errors.New("rate limiter unavailable")
+
 	// ErrMissingAPIKey indicates no API key was provided
 	ErrMissingAPIKey = errors.New("missing API key")
 )
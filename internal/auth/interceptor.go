@@ -2,8 +2,10 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -23,6 +25,8 @@ const (
 type Authenticator struct {
 	keyStore    *KeyStore
 	rateLimiter *RateLimiter
+	sessions    *SessionStore // Validates session tokens; nil rejects them
+	geo         GeoIP         // Resolves countries for keys with country restrictions
 	skipMethods map[string]bool
 }
 
@@ -33,11 +37,26 @@ func NewAuthenticator(keyStore *KeyStore, rateLimiter *RateLimiter) *Authenticat
 		rateLimiter: rateLimiter,
 		skipMethods: map[string]bool{
 			"/airborne.v1.AdminService/Health": true,
+			"/grpc.health.v1.Health/Check":     true,
+			"/grpc.health.v1.Health/List":      true,
+			"/grpc.health.v1.Health/Watch":     true,
 			// Version removed - requires authentication with PermissionAdmin
 		},
 	}
 }
 
+// SetGeoIP sets the provider that resolves the country of requests made
+// with keys restricted to countries. Without one such requests are rejected.
+func (a *Authenticator) SetGeoIP(geo GeoIP) {
+	a.geo = geo
+}
+
+// SetSessionStore enables session tokens, which authenticate as the key they
+// were minted with for the methods in SessionMethods.
+func (a *Authenticator) SetSessionStore(sessions *SessionStore) {
+	a.sessions = sessions
+}
+
 // UnaryInterceptor returns a unary server interceptor for authentication
 func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -51,11 +70,17 @@ func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
 		if err != nil {
 			return nil, err
 		}
+		if err := checkSessionScope(info.FullMethod, client); err != nil {
+			return nil, err
+		}
+		if err := a.checkNetwork(ctx, info.FullMethod, client); err != nil {
+			return nil, err
+		}
 
 		// Check rate limits
 		if a.rateLimiter != nil {
 			if err := a.rateLimiter.Allow(ctx, client); err != nil {
-				return nil, status.Error(codes.ResourceExhausted, err.Error())
+				return nil, rateLimitStatus(err)
 			}
 		}
 
@@ -66,6 +91,15 @@ func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// rateLimitStatus converts a rate limiter error to a gRPC status. Callers
+// can retry Unavailable elsewhere, while ResourceExhausted means back off.
+func rateLimitStatus(err error) error {
+	if errors.Is(err, ErrRateLimiterUnavailable) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.ResourceExhausted, err.Error())
+}
+
 // StreamInterceptor returns a stream server interceptor for authentication
 func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
@@ -79,11 +113,17 @@ func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
 		if err != nil {
 			return err
 		}
+		if err := checkSessionScope(info.FullMethod, client); err != nil {
+			return err
+		}
+		if err := a.checkNetwork(ss.Context(), info.FullMethod, client); err != nil {
+			return err
+		}
 
 		// Check rate limits
 		if a.rateLimiter != nil {
 			if err := a.rateLimiter.Allow(ss.Context(), client); err != nil {
-				return status.Error(codes.ResourceExhausted, err.Error())
+				return rateLimitStatus(err)
 			}
 		}
 
@@ -99,6 +139,11 @@ func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
 
 // authenticate extracts and validates the API key from metadata
 func (a *Authenticator) authenticate(ctx context.Context) (*ClientKey, error) {
+	// Already authenticated by an internal request signature
+	if client := ClientFromContext(ctx); client != nil {
+		return client, nil
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Unauthenticated, "missing metadata")
@@ -110,6 +155,10 @@ func (a *Authenticator) authenticate(ctx context.Context) (*ClientKey, error) {
 		return nil, status.Error(codes.Unauthenticated, "missing API key")
 	}
 
+	if IsSessionToken(apiKey) {
+		return a.authenticateSession(ctx, apiKey)
+	}
+
 	// Validate key
 	client, err := a.keyStore.ValidateKey(ctx, apiKey)
 	if err != nil {
@@ -127,6 +176,80 @@ func (a *Authenticator) authenticate(ctx context.Context) (*ClientKey, error) {
 	return client, nil
 }
 
+// authenticateSession validates a session token and returns the client of
+// the key it was minted with, limited to the session's permissions. The key
+// must still be valid, so deleting it revokes its sessions too.
+func (a *Authenticator) authenticateSession(ctx context.Context, token string) (*ClientKey, error) {
+	if a.sessions == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	session, err := a.sessions.Validate(ctx, token)
+	if err != nil {
+		slog.Debug("session authentication failed", "error", err)
+		switch err {
+		case ErrSessionNotFound, ErrSessionInvalid:
+			return nil, status.Error(codes.Unauthenticated, "invalid session token")
+		case ErrSessionExpired:
+			return nil, status.Error(codes.Unauthenticated, "session token expired")
+		default:
+			return nil, status.Error(codes.Internal, "authentication error")
+		}
+	}
+	key, err := a.keyStore.GetKey(ctx, session.KeyID)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid session token")
+		}
+		return nil, status.Error(codes.Internal, "authentication error")
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "session token expired")
+	}
+
+	client := *key
+	client.Permissions = session.Permissions
+	client.Session = session
+	return &client, nil
+}
+
+// checkSessionScope rejects session token requests for methods outside
+// SessionMethods.
+func checkSessionScope(method string, client *ClientKey) error {
+	if client.Session == nil || client.Session.Allows(method) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "session tokens cannot call this method")
+}
+
+// checkNetwork rejects requests from outside the client key's network
+// restrictions, recording them in the audit log.
+func (a *Authenticator) checkNetwork(ctx context.Context, method string, client *ClientKey) error {
+	return checkClientNetwork(ctx, method, client, a.geo)
+}
+
+// checkClientNetwork rejects requests from outside client's network
+// restrictions, resolving countries with geo, and reports them.
+func checkClientNetwork(ctx context.Context, method string, client *ClientKey, geo GeoIP) error {
+	if client.Network == nil || client.Network.Empty() {
+		return nil
+	}
+	addr := PeerAddr(ctx)
+	country, err := client.Network.Check(addr, geo)
+	if err == nil {
+		return nil
+	}
+	ReportNetworkViolation(NetworkViolation{
+		Surface:  "grpc",
+		Method:   method,
+		ClientID: client.ClientID,
+		KeyID:    client.KeyHash(),
+		Addr:     addr,
+		Country:  country,
+		Reason:   err,
+	})
+	return status.Error(codes.PermissionDenied, "client not allowed from this network")
+}
+
 // extractAPIKey extracts the API key from gRPC metadata
 func extractAPIKey(md metadata.MD) string {
 	// Try authorization header first
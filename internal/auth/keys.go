@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -30,6 +31,15 @@ const (
 	PermissionChatStream Permission = "chat:stream"
 	PermissionFiles      Permission = "files"
 	PermissionAdmin      Permission = "admin"
+
+	// PermissionComputerUse allows requests to enable provider-native
+	// computer-use tools, whose actions drive a real machine
+	PermissionComputerUse Permission = "computer_use"
+
+	// PermissionUsers lets a backend acting for its end users read and
+	// delete any user's memories and profile by user_id; other keys only
+	// reach their own client's
+	PermissionUsers Permission = "users"
 )
 
 // RateLimits defines rate limits for a client
@@ -51,6 +61,15 @@ type ClientKey struct {
 	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
 	LastUsed    *time.Time        `json:"last_used,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	Groups      []string          `json:"groups,omitempty"` // Groups named by document ACLs in RAG stores
+
+	// Network restricts the addresses and countries the key may be used
+	// from; nil allows any
+	Network *NetworkRestrictions `json:"network,omitempty"`
+
+	// Session is set when the request was made with a session token minted
+	// with this key, rather than with the key itself
+	Session *Session `json:"-"`
 }
 
 // KeyStore manages API keys in Redis
@@ -188,6 +207,8 @@ type CreateKeyParams struct {
 	ClientName  string
 	Permissions []Permission
 	RateLimits  RateLimits
+	Groups      []string
+	Network     *NetworkRestrictions
 }
 
 // CreateKey creates a new API key with auto-generated client ID
@@ -203,6 +224,18 @@ func (s *KeyStore) CreateKey(ctx context.Context, params CreateKeyParams) (*Clie
 	if err != nil {
 		return nil, "", err
 	}
+	if len(params.Groups) > 0 || params.Network != nil {
+		if params.Network != nil {
+			if err := params.Network.Validate(); err != nil {
+				return nil, "", fmt.Errorf("invalid network restrictions: %w", err)
+			}
+		}
+		key.Groups = params.Groups
+		key.Network = params.Network
+		if err := s.saveKey(ctx, key); err != nil {
+			return nil, "", err
+		}
+	}
 
 	return key, fullKey, nil
 }
@@ -217,6 +250,17 @@ func (k *ClientKey) HasPermission(perm Permission) bool {
 	return false
 }
 
+// KeyHash returns a short, non-reversible identifier for the key, used to
+// attribute usage. The key ID itself is part of the API key, so it is never
+// stored alongside usage. Returns "" for keys without an ID (static auth).
+func (k *ClientKey) KeyHash() string {
+	if k == nil || k.KeyID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(k.KeyID))
+	return hex.EncodeToString(sum[:8])
+}
+
 // saveKey saves a key to Redis
 func (s *KeyStore) saveKey(ctx context.Context, key *ClientKey) error {
 	data, err := json.Marshal(key)
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -180,3 +181,28 @@ func TestClientKeyHasPermission(t *testing.T) {
 		})
 	}
 }
+
+func TestClientKeyKeyHash(t *testing.T) {
+	a := &ClientKey{KeyID: "abcd1234"}
+	b := &ClientKey{KeyID: "efgh5678"}
+
+	if got := a.KeyHash(); len(got) != 16 {
+		t.Errorf("KeyHash() = %q, want 16 hex chars", got)
+	}
+	if a.KeyHash() != (&ClientKey{KeyID: "abcd1234"}).KeyHash() {
+		t.Error("KeyHash() should be stable for the same key ID")
+	}
+	if a.KeyHash() == b.KeyHash() {
+		t.Error("KeyHash() should differ between key IDs")
+	}
+	if strings.Contains(a.KeyHash(), a.KeyID) {
+		t.Error("KeyHash() must not expose the key ID")
+	}
+	if got := (&ClientKey{}).KeyHash(); got != "" {
+		t.Errorf("KeyHash() without key ID = %q, want empty", got)
+	}
+	var nilKey *ClientKey
+	if got := nilKey.KeyHash(); got != "" {
+		t.Errorf("nil KeyHash() = %q, want empty", got)
+	}
+}
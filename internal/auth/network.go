@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/peer"
+)
+
+// ErrAddressNotAllowed is returned for requests from an address outside a
+// key's network restrictions.
+var ErrAddressNotAllowed = errors.New("request address not allowed")
+
+// NetworkRestrictions limit where a key may be used from. A request must
+// come from one of AllowedCIDRs, when set, and from one of AllowedCountries,
+// when set. Countries are ISO 3166-1 alpha-2 codes resolved with a GeoIP
+// provider; requests whose country cannot be resolved are rejected.
+type NetworkRestrictions struct {
+	AllowedCIDRs     []string `json:"allowed_cidrs,omitempty" yaml:"allowed_cidrs"`         // CIDRs or single IPs, e.g. 203.0.113.0/24
+	AllowedCountries []string `json:"allowed_countries,omitempty" yaml:"allowed_countries"` // e.g. [US, CA]
+}
+
+// Empty reports whether the restrictions allow every address.
+func (n NetworkRestrictions) Empty() bool {
+	return len(n.AllowedCIDRs) == 0 && len(n.AllowedCountries) == 0
+}
+
+// Validate checks the CIDRs and country codes.
+func (n NetworkRestrictions) Validate() error {
+	for _, c := range n.AllowedCIDRs {
+		if _, err := parsePrefix(c); err != nil {
+			return fmt.Errorf("allowed_cidrs: %w", err)
+		}
+	}
+	for _, c := range n.AllowedCountries {
+		if !isCountryCode(c) {
+			return fmt.Errorf("allowed_countries: %q is not a two-letter country code", c)
+		}
+	}
+	return nil
+}
+
+// isCountryCode reports whether s looks like an ISO 3166-1 alpha-2 code.
+func isCountryCode(s string) bool {
+	return len(s) == 2 && strings.Trim(strings.ToUpper(s), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+}
+
+// Check returns nil if addr satisfies the restrictions, resolving its
+// country with geo. It returns ErrAddressNotAllowed, wrapped with the
+// reason, otherwise. The country is returned when it was resolved.
+func (n NetworkRestrictions) Check(addr netip.Addr, geo GeoIP) (country string, err error) {
+	if n.Empty() {
+		return "", nil
+	}
+	addr = addr.Unmap()
+	if !addr.IsValid() {
+		return "", fmt.Errorf("%w: unknown address", ErrAddressNotAllowed)
+	}
+	if len(n.AllowedCIDRs) > 0 && !slices.ContainsFunc(n.AllowedCIDRs, func(c string) bool {
+		p, err := parsePrefix(c)
+		return err == nil && p.Contains(addr)
+	}) {
+		return "", fmt.Errorf("%w: %s is outside the allowed CIDRs", ErrAddressNotAllowed, addr)
+	}
+	if len(n.AllowedCountries) == 0 {
+		return "", nil
+	}
+	if geo == nil {
+		return "", fmt.Errorf("%w: country restrictions need a GeoIP provider", ErrAddressNotAllowed)
+	}
+	country, err = geo.Country(addr)
+	if err != nil {
+		return "", fmt.Errorf("%w: resolve country of %s: %v", ErrAddressNotAllowed, addr, err)
+	}
+	if country == "" || !slices.ContainsFunc(n.AllowedCountries, func(c string) bool { return strings.EqualFold(c, country) }) {
+		return country, fmt.Errorf("%w: %s is in country %q", ErrAddressNotAllowed, addr, country)
+	}
+	return country, nil
+}
+
+// parsePrefix parses a CIDR or a single IP, as a one-address prefix.
+func parsePrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return p.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+// PeerAddr returns the address of the gRPC client that sent ctx's request.
+// Forwarding headers are not trusted, since any client can set them.
+func PeerAddr(ctx context.Context) netip.Addr {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}
+	}
+	return RemoteAddr(p.Addr.String())
+}
+
+// RemoteAddr parses an "ip:port" or bare IP remote address, as found in
+// http.Request.RemoteAddr.
+func RemoteAddr(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// NetworkViolation describes a request rejected by network restrictions.
+type NetworkViolation struct {
+	Surface  string // "grpc" or "admin_http"
+	Method   string // RPC method or HTTP path
+	ClientID string
+	KeyID    string // Hashed, see ClientKey.KeyHash
+	Addr     netip.Addr
+	Country  string // Empty when not resolved
+	Reason   error
+}
+
+// NetworkViolationSink stores network violations for the audit trail.
+type NetworkViolationSink func(ctx context.Context, v NetworkViolation) error
+
+// networkViolationSink is the sink installed by SetNetworkViolationSink.
+var networkViolationSink atomic.Pointer[NetworkViolationSink]
+
+// SetNetworkViolationSink installs the sink ReportNetworkViolation stores
+// violations in. A nil sink leaves them in the log only.
+func SetNetworkViolationSink(sink NetworkViolationSink) {
+	if sink == nil {
+		networkViolationSink.Store(nil)
+		return
+	}
+	networkViolationSink.Store(&sink)
+}
+
+// ReportNetworkViolation writes a violation to the log and, in the
+// background, to the network violation sink.
+func ReportNetworkViolation(v NetworkViolation) {
+	slog.Warn("network restriction violation",
+		"audit", true,
+		"surface", v.Surface,
+		"method", v.Method,
+		"client_id", v.ClientID,
+		"key_id", v.KeyID,
+		"remote_addr", v.Addr.String(),
+		"country", v.Country,
+		"reason", v.Reason.Error(),
+	)
+	sink := networkViolationSink.Load()
+	if sink == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := (*sink)(ctx, v); err != nil {
+			slog.Error("failed to record network violation", "error", err, "surface", v.Surface, "method", v.Method)
+		}
+	}()
+}
+
+// GeoIP resolves the country of an address.
+type GeoIP interface {
+	// Country returns the ISO 3166-1 alpha-2 code of addr's country, or ""
+	// when it is not known.
+	Country(addr netip.Addr) (string, error)
+}
+
+// geoRange is one network of a CSVGeoIP database.
+type geoRange struct {
+	first, last netip.Addr
+	country     string
+}
+
+// CSVGeoIP is a GeoIP provider backed by a CSV file of "network,country"
+// lines, where network is a CIDR or single IP and country an ISO 3166-1
+// alpha-2 code. Empty lines and lines starting with # are skipped, as is a
+// "network,..." header. Networks must not overlap.
+type CSVGeoIP struct {
+	ranges []geoRange // Sorted by first address
+}
+
+// LoadGeoIPCSV loads a CSVGeoIP database from path.
+func LoadGeoIPCSV(path string) (*CSVGeoIP, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db := &CSVGeoIP{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || (line == 1 && strings.HasPrefix(text, "network,")) {
+			continue
+		}
+		network, country, ok := strings.Cut(text, ",")
+		country, _, _ = strings.Cut(country, ",")
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if !ok || !isCountryCode(country) {
+			return nil, fmt.Errorf("%s:%d: want network,country", path, line)
+		}
+		p, err := parsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		db.ranges = append(db.ranges, geoRange{first: p.Addr(), last: lastAddr(p), country: country})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(db.ranges, func(a, b geoRange) int { return a.first.Compare(b.first) })
+	return db, nil
+}
+
+// Country implements GeoIP.
+func (db *CSVGeoIP) Country(addr netip.Addr) (string, error) {
+	addr = addr.Unmap()
+	// The last range starting at or before addr is the only one that can
+	// contain it
+	i, found := slices.BinarySearchFunc(db.ranges, addr, func(r geoRange, a netip.Addr) int { return r.first.Compare(a) })
+	if !found {
+		i--
+	}
+	if i < 0 || db.ranges[i].last.Compare(addr) < 0 {
+		return "", nil
+	}
+	return db.ranges[i].country, nil
+}
+
+// lastAddr returns the last address of p.
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Addr().AsSlice()
+	for bit := p.Bits(); bit < len(b)*8; bit++ {
+		b[bit/8] |= 1 << (7 - bit%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
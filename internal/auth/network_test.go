@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func loadTestGeoIP(t *testing.T) *CSVGeoIP {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	data := "network,country\n# test ranges\n203.0.113.0/24,us\n198.51.100.7,DE\n2001:db8::/32,FR\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	geo, err := LoadGeoIPCSV(path)
+	if err != nil {
+		t.Fatalf("LoadGeoIPCSV failed: %v", err)
+	}
+	return geo
+}
+
+func TestCSVGeoIP_Country(t *testing.T) {
+	geo := loadTestGeoIP(t)
+	for addr, want := range map[string]string{
+		"203.0.113.0":        "US",
+		"203.0.113.255":      "US",
+		"203.0.114.0":        "",
+		"198.51.100.7":       "DE",
+		"198.51.100.8":       "",
+		"::ffff:203.0.113.9": "US",
+		"2001:db8::1":        "FR",
+		"2001:db9::1":        "",
+		"10.0.0.1":           "",
+	} {
+		if got, err := geo.Country(netip.MustParseAddr(addr)); err != nil || got != want {
+			t.Errorf("Country(%s) = %q, %v, want %q", addr, got, err, want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.csv")
+	os.WriteFile(path, []byte("203.0.113.0/24,USA\n"), 0o600)
+	if _, err := LoadGeoIPCSV(path); err == nil {
+		t.Error("expected an error for a three-letter country code")
+	}
+}
+
+func TestNetworkRestrictions_Check(t *testing.T) {
+	geo := loadTestGeoIP(t)
+	tests := []struct {
+		name    string
+		n       NetworkRestrictions
+		addr    string
+		geo     GeoIP
+		allowed bool
+	}{
+		{"no restrictions", NetworkRestrictions{}, "192.0.2.1", nil, true},
+		{"inside cidr", NetworkRestrictions{AllowedCIDRs: []string{"192.0.2.0/24"}}, "192.0.2.1", nil, true},
+		{"single ip", NetworkRestrictions{AllowedCIDRs: []string{"10.0.0.0/8", "192.0.2.9"}}, "192.0.2.9", nil, true},
+		{"outside cidr", NetworkRestrictions{AllowedCIDRs: []string{"192.0.2.0/24"}}, "192.0.3.1", nil, false},
+		{"unknown address", NetworkRestrictions{AllowedCIDRs: []string{"0.0.0.0/0"}}, "", nil, false},
+		{"allowed country", NetworkRestrictions{AllowedCountries: []string{"us"}}, "203.0.113.5", geo, true},
+		{"other country", NetworkRestrictions{AllowedCountries: []string{"US"}}, "198.51.100.7", geo, false},
+		{"unresolved country", NetworkRestrictions{AllowedCountries: []string{"US"}}, "10.0.0.1", geo, false},
+		{"no geoip provider", NetworkRestrictions{AllowedCountries: []string{"US"}}, "203.0.113.5", nil, false},
+		{"cidr and country", NetworkRestrictions{AllowedCIDRs: []string{"203.0.113.0/25"}, AllowedCountries: []string{"US"}}, "203.0.113.200", geo, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var addr netip.Addr
+			if tt.addr != "" {
+				addr = netip.MustParseAddr(tt.addr)
+			}
+			_, err := tt.n.Check(addr, tt.geo)
+			if tt.allowed && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrAddressNotAllowed) {
+				t.Errorf("err = %v, want ErrAddressNotAllowed", err)
+			}
+		})
+	}
+
+	if err := (NetworkRestrictions{AllowedCIDRs: []string{"192.0.2.0/33"}}).Validate(); err == nil {
+		t.Error("expected an error for an invalid CIDR")
+	}
+	if err := (NetworkRestrictions{AllowedCountries: []string{"USA"}}).Validate(); err == nil {
+		t.Error("expected an error for an invalid country code")
+	}
+}
+
+func TestAuthenticator_CheckNetwork(t *testing.T) {
+	a := &Authenticator{}
+	client := &ClientKey{ClientID: "billing", Network: &NetworkRestrictions{AllowedCIDRs: []string{"192.0.2.0/24"}}}
+	ctxFrom := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 443}})
+	}
+
+	if err := a.checkNetwork(ctxFrom("192.0.2.10"), "/airborne.v1.AirborneService/GenerateReply", client); err != nil {
+		t.Errorf("allowed address rejected: %v", err)
+	}
+	err := a.checkNetwork(ctxFrom("198.51.100.1"), "/airborne.v1.AirborneService/GenerateReply", client)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("disallowed address: got %v, want PermissionDenied", err)
+	}
+	if err := a.checkNetwork(ctxFrom("198.51.100.1"), "/airborne.v1.AirborneService/GenerateReply", &ClientKey{ClientID: "open"}); err != nil {
+		t.Errorf("key without restrictions rejected: %v", err)
+	}
+}
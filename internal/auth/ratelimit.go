@@ -14,6 +14,15 @@ const (
 	rateLimitPrefix = "airborne:ratelimit:"
 )
 
+// rateLimitTypes are the counters kept per client.
+var rateLimitTypes = []string{"rpm", "rpd", "tpm"}
+
+// rateLimitKey returns the counter key for a client. The client ID is a
+// Redis Cluster hash tag, so all of a client's counters share one slot.
+func rateLimitKey(clientID, limitType string) string {
+	return rateLimitPrefix + redis.HashTag(clientID) + ":" + limitType
+}
+
 // rateLimitScript is a Lua script for atomic rate limiting
 // It increments the counter and sets TTL atomically, returning the new count
 const rateLimitScript = `
@@ -50,15 +59,59 @@ type RateLimiter struct {
 	redis          *redis.Client
 	defaultLimits  RateLimits
 	enabled        bool
+	fallback       *redis.MemoryStore // Non-nil when the policy is to count in memory
+	tracker        *redis.FallbackTracker
+}
+
+// RateLimiterOption configures a RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithRateLimitFallback sets what happens when Redis is unavailable: with
+// redis.PolicyMemory counters are kept per instance until Redis recovers,
+// with redis.PolicyFailClosed requests are rejected with
+// ErrRateLimiterUnavailable. Without this option the limiter fails closed.
+func WithRateLimitFallback(policy string, tracker *redis.FallbackTracker) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.fallback = nil
+		if policy == redis.PolicyMemory {
+			r.fallback = redis.NewMemoryStore()
+		}
+		r.tracker = tracker
+		tracker.Register(redis.FeatureRateLimit, policy)
+	}
 }
 
 // NewRateLimiter creates a new rate limiter
-func NewRateLimiter(redis *redis.Client, defaultLimits RateLimits, enabled bool) *RateLimiter {
-	return &RateLimiter{
+func NewRateLimiter(redis *redis.Client, defaultLimits RateLimits, enabled bool, opts ...RateLimiterOption) *RateLimiter {
+	r := &RateLimiter{
 		redis:         redis,
 		defaultLimits: defaultLimits,
 		enabled:       enabled,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// incr runs a counter script against key, falling back according to the
+// configured policy when Redis cannot be reached. by and window describe the
+// script's effect so the in-memory fallback can mirror it.
+func (r *RateLimiter) incr(ctx context.Context, script, key string, by int64, window time.Duration, args ...interface{}) (interface{}, error) {
+	result, err := r.redis.Eval(ctx, script, []string{key}, args...)
+	if err == nil {
+		r.tracker.Recovered(redis.FeatureRateLimit)
+		return result, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err // The caller gave up; Redis is not at fault
+	}
+
+	r.tracker.Degraded(redis.FeatureRateLimit, err)
+	if r.fallback == nil {
+		return nil, fmt.Errorf("%w: %v", ErrRateLimiterUnavailable, err)
+	}
+	return r.fallback.IncrWindow(key, by, window), nil
 }
 
 // Allow checks if a request is allowed under rate limits
@@ -89,6 +142,21 @@ func (r *RateLimiter) Allow(ctx context.Context, client *ClientKey) error {
 		}
 	}
 
+	// Session tokens also have their own limits, within the key's
+	if session := client.Session; session != nil {
+		sessionID := "session:" + session.ID
+		if session.RateLimits.RequestsPerMinute > 0 {
+			if err := r.checkLimit(ctx, sessionID, "rpm", session.RateLimits.RequestsPerMinute, time.Minute); err != nil {
+				return err
+			}
+		}
+		if session.RateLimits.RequestsPerDay > 0 {
+			if err := r.checkLimit(ctx, sessionID, "rpd", session.RateLimits.RequestsPerDay, 24*time.Hour); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -112,10 +180,10 @@ func (r *RateLimiter) RecordTokens(ctx context.Context, clientID string, tokens
 		return nil
 	}
 
-	key := fmt.Sprintf("%s%s:tpm", rateLimitPrefix, clientID)
+	key := rateLimitKey(clientID, "tpm")
 
 	// Use Lua script for atomic increment + TTL setting
-	result, err := r.redis.Eval(ctx, tokenRecordScript, []string{key}, tokens, 60)
+	result, err := r.incr(ctx, tokenRecordScript, key, tokens, time.Minute, tokens, 60)
 	if err != nil {
 		return fmt.Errorf("failed to record tokens: %w", err)
 	}
@@ -143,10 +211,10 @@ func (r *RateLimiter) RecordTokens(ctx context.Context, clientID string, tokens
 
 // checkLimit checks and increments a rate limit counter atomically
 func (r *RateLimiter) checkLimit(ctx context.Context, clientID, limitType string, limit int, window time.Duration) error {
-	key := fmt.Sprintf("%s%s:%s", rateLimitPrefix, clientID, limitType)
+	key := rateLimitKey(clientID, limitType)
 	windowSeconds := int(window.Seconds())
 
-	result, err := r.redis.Eval(ctx, rateLimitScript, []string{key}, limit, windowSeconds)
+	result, err := r.incr(ctx, rateLimitScript, key, 1, window, limit, windowSeconds)
 	if err != nil {
 		return fmt.Errorf("failed to check rate limit: %w", err)
 	}
@@ -192,8 +260,8 @@ func (r *RateLimiter) checkLimit(ctx context.Context, clientID, limitType string
 func (r *RateLimiter) GetUsage(ctx context.Context, clientID string) (map[string]int64, error) {
 	usage := make(map[string]int64)
 
-	for _, limitType := range []string{"rpm", "rpd", "tpm"} {
-		key := fmt.Sprintf("%s%s:%s", rateLimitPrefix, clientID, limitType)
+	for _, limitType := range rateLimitTypes {
+		key := rateLimitKey(clientID, limitType)
 		val, err := r.redis.Get(ctx, key)
 		if err != nil && !redis.IsNil(err) {
 			return nil, err
@@ -222,11 +290,10 @@ func (r *RateLimiter) GetUsage(ctx context.Context, clientID string) (map[string
 
 // Reset resets rate limit counters for a client
 func (r *RateLimiter) Reset(ctx context.Context, clientID string) error {
-	for _, limitType := range []string{"rpm", "rpd", "tpm"} {
-		key := fmt.Sprintf("%s%s:%s", rateLimitPrefix, clientID, limitType)
-		if err := r.redis.Del(ctx, key); err != nil {
-			return err
-		}
+	keys := make([]string, 0, len(rateLimitTypes))
+	for _, limitType := range rateLimitTypes {
+		keys = append(keys, rateLimitKey(clientID, limitType))
 	}
-	return nil
+	// A single multi-key DEL is valid in cluster mode because the keys share a hash tag
+	return r.redis.Del(ctx, keys...)
 }
@@ -2,11 +2,15 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/ai8future/airborne/internal/redis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestRateLimiter_AtomicIncrement(t *testing.T) {
@@ -176,9 +180,9 @@ func TestGetUsage_MalformedValue(t *testing.T) {
 	clientID := "test-client"
 
 	// Inject malformed (non-numeric) values directly into Redis
-	s.Set("airborne:ratelimit:"+clientID+":rpm", "not-a-number")
-	s.Set("airborne:ratelimit:"+clientID+":rpd", "garbage")
-	s.Set("airborne:ratelimit:"+clientID+":tpm", "xyz123")
+	s.Set(rateLimitKey(clientID, "rpm"), "not-a-number")
+	s.Set(rateLimitKey(clientID, "rpd"), "garbage")
+	s.Set(rateLimitKey(clientID, "tpm"), "xyz123")
 
 	usage, err := rl.GetUsage(ctx, clientID)
 	if err != nil {
@@ -217,9 +221,9 @@ func TestGetUsage_ValidValues(t *testing.T) {
 	clientID := "test-client"
 
 	// Inject valid numeric values directly into Redis
-	s.Set("airborne:ratelimit:"+clientID+":rpm", "42")
-	s.Set("airborne:ratelimit:"+clientID+":rpd", "123")
-	s.Set("airborne:ratelimit:"+clientID+":tpm", "9999")
+	s.Set(rateLimitKey(clientID, "rpm"), "42")
+	s.Set(rateLimitKey(clientID, "rpd"), "123")
+	s.Set(rateLimitKey(clientID, "tpm"), "9999")
 
 	usage, err := rl.GetUsage(ctx, clientID)
 	if err != nil {
@@ -321,3 +325,72 @@ func TestRecordTokens_WithMiniredis(t *testing.T) {
 
 // Unused import guard for time package
 var _ = time.Second
+
+func TestRateLimitKey_SharesClusterSlot(t *testing.T) {
+	// Every counter for a client must carry the same hash tag so multi-key
+	// commands (Reset) work in Redis Cluster.
+	for _, limitType := range rateLimitTypes {
+		key := rateLimitKey("client-1", limitType)
+		if !strings.Contains(key, "{client-1}") {
+			t.Errorf("rateLimitKey(%q) = %q, want hash tag {client-1}", limitType, key)
+		}
+	}
+}
+
+func TestRateLimiter_RedisDown(t *testing.T) {
+	s := miniredis.RunT(t)
+
+	client, err := redis.NewClient(redis.Config{Addr: s.Addr()})
+	if err != nil {
+		t.Fatalf("Failed to create redis client: %v", err)
+	}
+	defer client.Close()
+
+	s.SetError("LOADING Redis is loading the dataset in memory")
+
+	key := &ClientKey{ClientID: "test-client"}
+	limits := RateLimits{RequestsPerMinute: 2}
+	ctx := context.Background()
+
+	t.Run("fail closed", func(t *testing.T) {
+		tracker := redis.NewFallbackTracker()
+		rl := NewRateLimiter(client, limits, true, WithRateLimitFallback(redis.PolicyFailClosed, tracker))
+
+		err := rl.Allow(ctx, key)
+		if !errors.Is(err, ErrRateLimiterUnavailable) {
+			t.Fatalf("Expected ErrRateLimiterUnavailable, got: %v", err)
+		}
+		if got := status.Code(rateLimitStatus(err)); got != codes.Unavailable {
+			t.Errorf("status code = %v, want Unavailable", got)
+		}
+		if d := tracker.Degradations(); len(d) != 1 || d[0].Feature != redis.FeatureRateLimit {
+			t.Errorf("expected rate_limit degradation, got %+v", d)
+		}
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		tracker := redis.NewFallbackTracker()
+		rl := NewRateLimiter(client, limits, true, WithRateLimitFallback(redis.PolicyMemory, tracker))
+
+		for i := 0; i < 2; i++ {
+			if err := rl.Allow(ctx, key); err != nil {
+				t.Fatalf("request %d should be allowed from memory: %v", i+1, err)
+			}
+		}
+		if err := rl.Allow(ctx, key); err != ErrRateLimitExceeded {
+			t.Errorf("Expected ErrRateLimitExceeded from memory counter, got: %v", err)
+		}
+
+		// Redis coming back clears the degradation
+		s.SetError("")
+		if err := rl.Allow(ctx, key); err != nil {
+			t.Fatalf("Allow after recovery: %v", err)
+		}
+		if d := tracker.Degradations(); len(d) != 0 {
+			t.Errorf("expected recovery, got %+v", d)
+		}
+		if st := tracker.Status(); len(st) != 1 || st[0].Activations != 3 {
+			t.Errorf("expected 3 activations, got %+v", st)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ai8future/airborne/internal/redis"
+)
+
+const (
+	defaultSessionPrefix = "airborne:session:"
+
+	// Session token format: airborne_st_<session ID>_<secret>
+	sessionTokenPrefix = "airborne_st_"
+	sessionIDLength    = 16
+
+	// DefaultSessionTTL is how long a session token is valid when the
+	// request sets no lifetime.
+	DefaultSessionTTL = 15 * time.Minute
+
+	// MaxSessionTTL caps a session token's lifetime.
+	MaxSessionTTL = time.Hour
+)
+
+// Session token errors
+var (
+	ErrSessionNotFound = errors.New("session token not found")
+	ErrSessionInvalid  = errors.New("invalid session token")
+	ErrSessionExpired  = errors.New("session token expired")
+)
+
+// SessionMethods are the RPCs a session token can call. Session tokens are
+// meant for browsers streaming replies, so everything else needs the key.
+var SessionMethods = map[string]bool{
+	"/airborne.v1.AirborneService/GenerateReplyStream": true,
+}
+
+// Session is a short-lived token minted with an API key, so front-end apps
+// can stream replies without holding the key. Requests made with it act as
+// the key's client, limited to SessionMethods and the session's rate limits
+// on top of the key's.
+type Session struct {
+	ID          string       `json:"id"`
+	SecretHash  string       `json:"secret_hash"` // SHA-256; the secret is random, so a slow hash adds nothing
+	KeyID       string       `json:"key_id"`      // Key the session was minted with
+	ClientID    string       `json:"client_id"`
+	Permissions []Permission `json:"permissions"`
+	RateLimits  RateLimits   `json:"rate_limits"` // Requests only; zero leaves just the key's limits
+	CreatedAt   time.Time    `json:"created_at"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
+
+// Allows reports whether the session can call method.
+func (s *Session) Allows(method string) bool {
+	return SessionMethods[method]
+}
+
+// SessionStore manages session tokens in Redis. Sessions expire with their
+// Redis keys.
+type SessionStore struct {
+	redis     *redis.Client
+	keyPrefix string
+}
+
+// NewSessionStore creates a session store.
+func NewSessionStore(redis *redis.Client) *SessionStore {
+	return &SessionStore{redis: redis, keyPrefix: defaultSessionPrefix}
+}
+
+// CreateSessionParams holds parameters for minting a session token.
+type CreateSessionParams struct {
+	TTL               time.Duration // Zero uses DefaultSessionTTL; capped at MaxSessionTTL
+	RequestsPerMinute int           // Zero leaves only the key's limit
+	RequestsPerDay    int           // Zero leaves only the key's limit
+}
+
+// Create mints a session token for parent, the key of the request. The
+// session gets the streaming permission only, which parent must have.
+// Returns the token (shown once) and the session record.
+func (s *SessionStore) Create(ctx context.Context, parent *ClientKey, params CreateSessionParams) (string, *Session, error) {
+	if parent == nil || parent.KeyID == "" {
+		return "", nil, fmt.Errorf("session tokens must be minted with an API key")
+	}
+	if parent.Session != nil {
+		return "", nil, fmt.Errorf("session tokens cannot mint session tokens")
+	}
+	if !parent.HasPermission(PermissionChatStream) {
+		return "", nil, fmt.Errorf("key lacks the %s permission", PermissionChatStream)
+	}
+	if params.RequestsPerMinute < 0 || params.RequestsPerDay < 0 {
+		return "", nil, fmt.Errorf("rate limits must not be negative")
+	}
+	ttl := params.TTL
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	ttl = min(ttl, MaxSessionTTL)
+	if parent.ExpiresAt != nil {
+		ttl = min(ttl, time.Until(*parent.ExpiresAt))
+		if ttl <= 0 {
+			return "", nil, ErrKeyExpired
+		}
+	}
+
+	id, err := generateRandomString(sessionIDLength)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	secret, err := generateRandomString(32)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	now := time.Now().UTC()
+	session := &Session{
+		ID:          id,
+		SecretHash:  hashSessionSecret(secret),
+		KeyID:       parent.KeyID,
+		ClientID:    parent.ClientID,
+		Permissions: []Permission{PermissionChatStream},
+		RateLimits:  RateLimits{RequestsPerMinute: params.RequestsPerMinute, RequestsPerDay: params.RequestsPerDay},
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := s.redis.Set(ctx, s.keyPrefix+id, string(data), ttl); err != nil {
+		return "", nil, fmt.Errorf("failed to store session: %w", err)
+	}
+	return sessionTokenPrefix + id + "_" + secret, session, nil
+}
+
+// Validate returns the session of a token.
+func (s *SessionStore) Validate(ctx context.Context, token string) (*Session, error) {
+	id, secret, ok := parseSessionToken(token)
+	if !ok {
+		return nil, ErrSessionInvalid
+	}
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrSessionExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSessionSecret(secret)), []byte(session.SecretHash)) != 1 {
+		return nil, ErrSessionInvalid
+	}
+	return session, nil
+}
+
+// Get returns a session by ID.
+func (s *SessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := s.redis.Get(ctx, s.keyPrefix+id)
+	if err != nil {
+		if redis.IsNil(err) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("data corruption in session store for %q: %w", id, err)
+	}
+	return &session, nil
+}
+
+// Revoke deletes a session, so its token stops working immediately.
+func (s *SessionStore) Revoke(ctx context.Context, id string) error {
+	return s.redis.Del(ctx, s.keyPrefix+id)
+}
+
+// IsSessionToken reports whether token has the session token format, as
+// opposed to an API key.
+func IsSessionToken(token string) bool {
+	return strings.HasPrefix(token, sessionTokenPrefix)
+}
+
+// parseSessionToken splits a session token into its ID and secret.
+func parseSessionToken(token string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(token, sessionTokenPrefix)
+	if !ok || len(rest) < sessionIDLength+2 || rest[sessionIDLength] != '_' {
+		return "", "", false
+	}
+	return rest[:sessionIDLength], rest[sessionIDLength+1:], true
+}
+
+func hashSessionSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/redis"
+	"github.com/alicebob/miniredis/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	s := miniredis.RunT(t)
+	client, err := redis.NewClient(redis.Config{Addr: s.Addr()})
+	if err != nil {
+		t.Fatalf("Failed to create redis client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return s, client
+}
+
+func TestSessionStore_CreateValidateRevoke(t *testing.T) {
+	s, client := newTestRedis(t)
+	store := NewSessionStore(client)
+	ctx := context.Background()
+	parent := &ClientKey{KeyID: "key1", ClientID: "web", Permissions: []Permission{PermissionChat, PermissionChatStream}}
+
+	token, session, err := store.Create(ctx, parent, CreateSessionParams{RequestsPerMinute: 5})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !IsSessionToken(token) || session.KeyID != "key1" || session.ClientID != "web" || session.RateLimits.RequestsPerMinute != 5 {
+		t.Errorf("unexpected token %q or session %+v", token, session)
+	}
+	if got := session.ExpiresAt.Sub(session.CreatedAt); got != DefaultSessionTTL {
+		t.Errorf("ttl = %v, want %v", got, DefaultSessionTTL)
+	}
+	if len(session.Permissions) != 1 || session.Permissions[0] != PermissionChatStream {
+		t.Errorf("permissions = %v, want only %s", session.Permissions, PermissionChatStream)
+	}
+
+	got, err := store.Validate(ctx, token)
+	if err != nil || got.ID != session.ID {
+		t.Fatalf("Validate = %+v, %v", got, err)
+	}
+	if _, err := store.Validate(ctx, token[:len(token)-1]+"x"); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("wrong secret: err = %v, want ErrSessionInvalid", err)
+	}
+	if _, err := store.Validate(ctx, "airborne_st_short"); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("malformed token: err = %v, want ErrSessionInvalid", err)
+	}
+
+	if err := store.Revoke(ctx, session.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := store.Validate(ctx, token); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoked token: err = %v, want ErrSessionNotFound", err)
+	}
+
+	// Sessions expire with their Redis keys
+	token, _, err = store.Create(ctx, parent, CreateSessionParams{TTL: 2 * MaxSessionTTL})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	s.FastForward(MaxSessionTTL + time.Second)
+	if _, err := store.Validate(ctx, token); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expired token: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionStore_CreateRequiresStreamingKey(t *testing.T) {
+	_, client := newTestRedis(t)
+	store := NewSessionStore(client)
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute)
+
+	for name, parent := range map[string]*ClientKey{
+		"static auth":    {ClientID: "static", Permissions: []Permission{PermissionAdmin}},
+		"no chat:stream": {KeyID: "key1", ClientID: "web", Permissions: []Permission{PermissionChat}},
+		"session":        {KeyID: "key1", ClientID: "web", Permissions: []Permission{PermissionChatStream}, Session: &Session{ID: "s"}},
+		"expired key":    {KeyID: "key1", ClientID: "web", Permissions: []Permission{PermissionChatStream}, ExpiresAt: &past},
+	} {
+		if _, _, err := store.Create(ctx, parent, CreateSessionParams{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAuthenticator_SessionToken(t *testing.T) {
+	_, client := newTestRedis(t)
+	keyStore := NewKeyStore(client)
+	sessions := NewSessionStore(client)
+	ctx := context.Background()
+
+	key, _, err := keyStore.CreateKey(ctx, CreateKeyParams{ClientName: "web", Permissions: []Permission{PermissionChat, PermissionChatStream}})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	token, session, err := sessions.Create(ctx, key, CreateSessionParams{RequestsPerMinute: 1})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	a := NewAuthenticator(keyStore, NewRateLimiter(client, RateLimits{}, true))
+	reqCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+
+	// Without a session store, session tokens are just invalid keys
+	if _, err := a.authenticate(reqCtx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("without store: got %v, want Unauthenticated", err)
+	}
+	a.SetSessionStore(sessions)
+
+	got, err := a.authenticate(reqCtx)
+	if err != nil {
+		t.Fatalf("authenticate failed: %v", err)
+	}
+	if got.ClientID != key.ClientID || got.Session == nil || got.Session.ID != session.ID || got.HasPermission(PermissionChat) {
+		t.Errorf("unexpected client %+v", got)
+	}
+	if err := checkSessionScope("/airborne.v1.AirborneService/GenerateReplyStream", got); err != nil {
+		t.Errorf("streaming rejected: %v", err)
+	}
+	if err := checkSessionScope("/airborne.v1.AirborneService/GenerateReply", got); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("unary call: got %v, want PermissionDenied", err)
+	}
+
+	// The session's limit applies on top of the key's
+	if err := a.rateLimiter.Allow(ctx, got); err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	if err := a.rateLimiter.Allow(ctx, got); err == nil {
+		t.Error("second request allowed past the session's limit")
+	}
+	if err := a.rateLimiter.Allow(ctx, key); err != nil {
+		t.Errorf("key itself limited by the session: %v", err)
+	}
+
+	// Deleting the key revokes its sessions
+	if err := keyStore.DeleteKey(ctx, key.KeyID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.authenticate(reqCtx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("after key deletion: got %v, want Unauthenticated", err)
+	}
+}
@@ -0,0 +1,310 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata keys carrying an internal request signature.
+const (
+	SignatureKeyIDHeader     = "x-airborne-key-id"
+	SignatureTimestampHeader = "x-airborne-timestamp"
+	SignatureNonceHeader     = "x-airborne-nonce"
+	SignatureHeader          = "x-airborne-signature"
+)
+
+// SignedClientID is the client ID of requests authenticated by signature.
+const SignedClientID = "admin-dashboard"
+
+// signatureVersion prefixes the signed string so the format can change.
+const signatureVersion = "airborne-sig-v1"
+
+// DefaultSignatureMaxSkew is how far a signature's timestamp may be from the
+// server clock; it is also how long nonces are remembered.
+const DefaultSignatureMaxSkew = 5 * time.Minute
+
+// minSigningSecretLen is the shortest accepted HMAC secret.
+const minSigningSecretLen = 32
+
+var (
+	// ErrSignatureInvalid is returned for a signature that does not verify.
+	ErrSignatureInvalid = errors.New("invalid request signature")
+	// ErrSignatureExpired is returned when a signature's timestamp is outside the allowed skew.
+	ErrSignatureExpired = errors.New("request signature expired")
+	// ErrSignatureReplayed is returned when a signature's nonce was already used.
+	ErrSignatureReplayed = errors.New("request signature replayed")
+)
+
+// SigningKey is an HMAC key shared by the admin dashboard and the gRPC server.
+type SigningKey struct {
+	ID     string
+	Secret string
+}
+
+// ValidateSigningKeys checks that keys have unique IDs and long enough
+// secrets, and that activeKey is one of them.
+func ValidateSigningKeys(keys []SigningKey, activeKey string) error {
+	if len(keys) == 0 {
+		return errors.New("at least one key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" {
+			return errors.New("key id is required")
+		}
+		if seen[k.ID] {
+			return fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		seen[k.ID] = true
+		if len(k.Secret) < minSigningSecretLen {
+			return fmt.Errorf("key %q secret must be at least %d characters", k.ID, minSigningSecretLen)
+		}
+	}
+	if !seen[activeKey] {
+		return fmt.Errorf("active key %q is not in keys", activeKey)
+	}
+	return nil
+}
+
+// RequestSigner signs outgoing gRPC requests with an HMAC key, binding each
+// request to its method, tenant, time and body.
+type RequestSigner struct {
+	key SigningKey
+	now func() time.Time
+}
+
+// NewRequestSigner creates a signer using key.
+func NewRequestSigner(key SigningKey) *RequestSigner {
+	return &RequestSigner{key: key, now: time.Now}
+}
+
+// UnaryClientInterceptor signs unary calls, covering the request body.
+func (s *RequestSigner) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		bodyHash, err := hashBody(req)
+		if err != nil {
+			return err
+		}
+		return invoker(s.sign(ctx, method, bodyHash), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor signs streaming calls. Headers are sent before any
+// message, so stream signatures cover the method, tenant and time only.
+func (s *RequestSigner) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(s.sign(ctx, method, ""), desc, cc, method, opts...)
+	}
+}
+
+// sign appends signature metadata to the outgoing context.
+func (s *RequestSigner) sign(ctx context.Context, method, bodyHash string) context.Context {
+	tenantID := ""
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if vals := md.Get("x-tenant-id"); len(vals) > 0 {
+			tenantID = vals[0]
+		}
+	}
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	nonce := newNonce()
+	sig := computeSignature(s.key.Secret, method, tenantID, ts, nonce, bodyHash)
+	return metadata.AppendToOutgoingContext(ctx,
+		SignatureKeyIDHeader, s.key.ID,
+		SignatureTimestampHeader, ts,
+		SignatureNonceHeader, nonce,
+		SignatureHeader, sig,
+	)
+}
+
+// SignatureVerifier authenticates internal requests signed by a
+// RequestSigner. Every listed key is accepted, so keys can be rotated by
+// adding the new key, switching the signer to it, then removing the old one.
+// Requests without signature metadata pass through to token authentication.
+type SignatureVerifier struct {
+	keys    map[string]string
+	maxSkew time.Duration
+	now     func() time.Time
+	network NetworkRestrictions // Where signed requests are accepted from; empty allows any
+	geo     GeoIP
+
+	mu        sync.Mutex
+	nonces    map[string]time.Time // Nonce -> expiry
+	nextPrune time.Time
+}
+
+// NewSignatureVerifier creates a verifier accepting keys. A zero maxSkew uses
+// DefaultSignatureMaxSkew.
+func NewSignatureVerifier(keys []SigningKey, maxSkew time.Duration) *SignatureVerifier {
+	if maxSkew <= 0 {
+		maxSkew = DefaultSignatureMaxSkew
+	}
+	v := &SignatureVerifier{
+		keys:    make(map[string]string, len(keys)),
+		maxSkew: maxSkew,
+		now:     time.Now,
+		nonces:  make(map[string]time.Time),
+	}
+	for _, k := range keys {
+		v.keys[k.ID] = k.Secret
+	}
+	return v
+}
+
+// SetNetwork restricts the addresses and countries signed requests are
+// accepted from, resolving countries with geo. Violations are reported like
+// those of API keys.
+func (v *SignatureVerifier) SetNetwork(network NetworkRestrictions, geo GeoIP) {
+	v.network = network
+	v.geo = geo
+}
+
+// UnaryInterceptor returns a unary server interceptor verifying signatures.
+func (v *SignatureVerifier) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get(SignatureHeader)) == 0 {
+			return handler(ctx, req)
+		}
+		bodyHash, err := hashBody(req)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to hash request")
+		}
+		ctx, err = v.authenticate(ctx, md, info.FullMethod, bodyHash)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a stream server interceptor verifying signatures.
+func (v *SignatureVerifier) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		if len(md.Get(SignatureHeader)) == 0 {
+			return handler(srv, ss)
+		}
+		ctx, err := v.authenticate(ss.Context(), md, info.FullMethod, "")
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate verifies the signature in md and returns ctx carrying the
+// signed client.
+func (v *SignatureVerifier) authenticate(ctx context.Context, md metadata.MD, method, bodyHash string) (context.Context, error) {
+	keyID := firstValue(md, SignatureKeyIDHeader)
+	if err := v.verify(md, method, bodyHash); err != nil {
+		slog.Warn("rejected signed request", "method", method, "key_id", keyID, "error", err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	client := &ClientKey{
+		KeyID:       keyID,
+		ClientID:    SignedClientID,
+		ClientName:  "signed-admin",
+		Permissions: []Permission{PermissionChat, PermissionChatStream, PermissionFiles, PermissionAdmin},
+	}
+	if !v.network.Empty() {
+		client.Network = &v.network
+	}
+	if err := checkClientNetwork(ctx, method, client, v.geo); err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, ClientContextKey, client), nil
+}
+
+// verify checks the signature, its age and its nonce.
+func (v *SignatureVerifier) verify(md metadata.MD, method, bodyHash string) error {
+	secret, ok := v.keys[firstValue(md, SignatureKeyIDHeader)]
+	if !ok {
+		return fmt.Errorf("%w: unknown key", ErrSignatureInvalid)
+	}
+	ts := firstValue(md, SignatureTimestampHeader)
+	nonce := firstValue(md, SignatureNonceHeader)
+	if nonce == "" {
+		return fmt.Errorf("%w: missing nonce", ErrSignatureInvalid)
+	}
+	want := computeSignature(secret, method, firstValue(md, "x-tenant-id"), ts, nonce, bodyHash)
+	if !hmac.Equal([]byte(want), []byte(firstValue(md, SignatureHeader))) {
+		return ErrSignatureInvalid
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrSignatureInvalid)
+	}
+	now := v.now()
+	signedAt := time.Unix(unix, 0)
+	if signedAt.Before(now.Add(-v.maxSkew)) || signedAt.After(now.Add(v.maxSkew)) {
+		return ErrSignatureExpired
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if now.After(v.nextPrune) {
+		for n, expiry := range v.nonces {
+			if now.After(expiry) {
+				delete(v.nonces, n)
+			}
+		}
+		v.nextPrune = now.Add(v.maxSkew)
+	}
+	if _, seen := v.nonces[nonce]; seen {
+		return ErrSignatureReplayed
+	}
+	v.nonces[nonce] = signedAt.Add(v.maxSkew)
+	return nil
+}
+
+// computeSignature returns the hex HMAC-SHA256 of a request's signed fields.
+func computeSignature(secret, method, tenantID, ts, nonce, bodyHash string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{signatureVersion, method, tenantID, ts, nonce, bodyHash}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashBody returns the hex SHA-256 of a request's deterministic encoding.
+func hashBody(req interface{}) (string, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", nil
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("hash request: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func newNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/airborne.v1.FileService/Retrieve"
+
+var (
+	testKeyOld = SigningKey{ID: "k1", Secret: strings.Repeat("a", 32)}
+	testKeyNew = SigningKey{ID: "k2", Secret: strings.Repeat("b", 32)}
+)
+
+// signedIncoming runs req through the signer and returns the incoming
+// context the server would see.
+func signedIncoming(t *testing.T, signer *RequestSigner, tenantID string, req interface{}) context.Context {
+	t.Helper()
+	ctx := context.Background()
+	if tenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", tenantID)
+	}
+	var sent metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := signer.UnaryClientInterceptor()(ctx, testMethod, req, nil, nil, invoker); err != nil {
+		t.Fatalf("signing failed: %v", err)
+	}
+	return metadata.NewIncomingContext(context.Background(), sent)
+}
+
+// verifyUnary runs the verifier and returns the client the handler saw.
+func verifyUnary(v *SignatureVerifier, ctx context.Context, req interface{}) (*ClientKey, error) {
+	var client *ClientKey
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		client = ClientFromContext(ctx)
+		return nil, nil
+	}
+	_, err := v.UnaryInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+	return client, err
+}
+
+func TestSignatureVerifier_Unary(t *testing.T) {
+	signer := NewRequestSigner(testKeyNew)
+	verifier := NewSignatureVerifier([]SigningKey{testKeyOld, testKeyNew}, 0)
+	req := &pb.RetrieveRequest{StoreId: "docs", Query: "hello"}
+
+	ctx := signedIncoming(t, signer, "ai8", req)
+	client, err := verifyUnary(verifier, ctx, req)
+	if err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if client == nil || client.ClientID != SignedClientID || client.KeyID != "k2" || !client.HasPermission(PermissionAdmin) {
+		t.Errorf("unexpected signed client: %+v", client)
+	}
+
+	// The same signature cannot be used twice
+	if _, err := verifyUnary(verifier, ctx, req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected replay to be rejected, got %v", err)
+	}
+}
+
+func TestSignatureVerifier_Network(t *testing.T) {
+	signer := NewRequestSigner(testKeyNew)
+	verifier := NewSignatureVerifier([]SigningKey{testKeyNew}, 0)
+	verifier.SetNetwork(NetworkRestrictions{AllowedCIDRs: []string{"192.0.2.0/24"}}, nil)
+	req := &pb.RetrieveRequest{StoreId: "docs", Query: "hello"}
+
+	reported := make(chan NetworkViolation, 1)
+	SetNetworkViolationSink(func(ctx context.Context, v NetworkViolation) error {
+		reported <- v
+		return nil
+	})
+	t.Cleanup(func() { SetNetworkViolationSink(nil) })
+
+	signedFrom := func(ip string) context.Context {
+		return peer.NewContext(signedIncoming(t, signer, "ai8", req), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 443}})
+	}
+	if _, err := verifyUnary(verifier, signedFrom("192.0.2.10"), req); err != nil {
+		t.Errorf("allowed address rejected: %v", err)
+	}
+	if _, err := verifyUnary(verifier, signedFrom("198.51.100.1"), req); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("disallowed address: got %v, want PermissionDenied", err)
+	}
+	select {
+	case v := <-reported:
+		if v.ClientID != SignedClientID || v.Method != testMethod || v.Addr.String() != "198.51.100.1" {
+			t.Errorf("reported violation = %+v", v)
+		}
+	case <-time.After(time.Second):
+		t.Error("violation not sent to the sink")
+	}
+}
+
+func TestSignatureVerifier_Rejects(t *testing.T) {
+	req := &pb.RetrieveRequest{StoreId: "docs", Query: "hello"}
+
+	tests := []struct {
+		name   string
+		signer *RequestSigner
+		mutate func(ctx context.Context) (context.Context, interface{})
+	}{
+		{"tampered body", NewRequestSigner(testKeyNew), func(ctx context.Context) (context.Context, interface{}) {
+			return ctx, &pb.RetrieveRequest{StoreId: "other", Query: "hello"}
+		}},
+		{"swapped tenant", NewRequestSigner(testKeyNew), func(ctx context.Context) (context.Context, interface{}) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			md = md.Copy()
+			md.Set("x-tenant-id", "email4ai")
+			return metadata.NewIncomingContext(ctx, md), req
+		}},
+		{"unknown key", NewRequestSigner(SigningKey{ID: "k9", Secret: testKeyNew.Secret}), nil},
+		{"wrong secret", NewRequestSigner(SigningKey{ID: "k2", Secret: strings.Repeat("c", 32)}), nil},
+		{"expired", &RequestSigner{key: testKeyNew, now: func() time.Time { return time.Now().Add(-time.Hour) }}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewSignatureVerifier([]SigningKey{testKeyNew}, time.Minute)
+			ctx := signedIncoming(t, tt.signer, "ai8", req)
+			var r interface{} = req
+			if tt.mutate != nil {
+				ctx, r = tt.mutate(ctx)
+			}
+			if _, err := verifyUnary(verifier, ctx, r); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("expected Unauthenticated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSignatureVerifier_UnsignedPassesThrough(t *testing.T) {
+	verifier := NewSignatureVerifier([]SigningKey{testKeyNew}, 0)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	client, err := verifyUnary(verifier, ctx, &pb.RetrieveRequest{})
+	if err != nil || client != nil {
+		t.Errorf("expected unsigned request to pass unauthenticated, got %+v, %v", client, err)
+	}
+
+	// Token authentication still runs for unsigned requests
+	static := NewStaticAuthenticator("token")
+	if _, err := static.authenticate(metadata.NewIncomingContext(context.Background(), metadata.MD{})); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without token, got %v", err)
+	}
+}
+
+func TestSignatureVerifier_Stream(t *testing.T) {
+	signer := NewRequestSigner(testKeyOld)
+	verifier := NewSignatureVerifier([]SigningKey{testKeyOld, testKeyNew}, 0)
+
+	var sent metadata.MD
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "ai8")
+	if _, err := signer.StreamClientInterceptor()(ctx, &grpc.StreamDesc{}, nil, testMethod, streamer); err != nil {
+		t.Fatalf("signing failed: %v", err)
+	}
+
+	ss := &mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), sent)}
+	var client *ClientKey
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		client = ClientFromContext(stream.Context())
+		// Token authentication accepts the signed client as is
+		_, err := NewStaticAuthenticator("token").authenticate(stream.Context())
+		return err
+	}
+	if err := verifier.StreamInterceptor()(nil, ss, &grpc.StreamServerInfo{FullMethod: testMethod}, handler); err != nil {
+		t.Fatalf("expected valid stream signature, got %v", err)
+	}
+	if client == nil || client.KeyID != "k1" {
+		t.Errorf("unexpected signed client: %+v", client)
+	}
+}
+
+func TestValidateSigningKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []SigningKey
+		active  string
+		wantErr bool
+	}{
+		{"valid rotation", []SigningKey{testKeyOld, testKeyNew}, "k2", false},
+		{"no keys", nil, "k1", true},
+		{"short secret", []SigningKey{{ID: "k1", Secret: "short"}}, "k1", true},
+		{"duplicate id", []SigningKey{testKeyOld, testKeyOld}, "k1", true},
+		{"unknown active key", []SigningKey{testKeyOld}, "k2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSigningKeys(tt.keys, tt.active); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSigningKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
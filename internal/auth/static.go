@@ -25,6 +25,9 @@ func NewStaticAuthenticator(adminToken string) *StaticAuthenticator {
 		adminToken: adminToken,
 		skipMethods: map[string]bool{
 			"/airborne.v1.AdminService/Health": true,
+			"/grpc.health.v1.Health/Check":     true,
+			"/grpc.health.v1.Health/List":      true,
+			"/grpc.health.v1.Health/Watch":     true,
 		},
 	}
 }
@@ -59,6 +62,11 @@ func (a *StaticAuthenticator) StreamInterceptor() grpc.StreamServerInterceptor {
 }
 
 func (a *StaticAuthenticator) authenticate(ctx context.Context) (context.Context, error) {
+	// Already authenticated by an internal request signature
+	if ClientFromContext(ctx) != nil {
+		return ctx, nil
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Unauthenticated, "missing metadata")
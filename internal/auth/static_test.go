@@ -137,6 +137,19 @@ func TestStaticUnaryInterceptorSkipMethod(t *testing.T) {
 	}
 }
 
+func TestStaticUnaryInterceptorSkipsGRPCHealth(t *testing.T) {
+	auth := NewStaticAuthenticator("secret")
+	interceptor := auth.UnaryInterceptor()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler); err != nil {
+		t.Fatalf("expected unauthenticated grpc.health.v1 check to pass, got %v", err)
+	}
+}
+
 func TestStaticUnaryInterceptorRequiresAuth(t *testing.T) {
 	auth := NewStaticAuthenticator("secret")
 	interceptor := auth.UnaryInterceptor()
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/isolation"
 	"github.com/ai8future/airborne/internal/tenant"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -29,14 +30,22 @@ func NewTenantInterceptor(mgr *tenant.Manager) *TenantInterceptor {
 	return &TenantInterceptor{
 		manager: mgr,
 		skipMethods: map[string]bool{
-			"/airborne.v1.AdminService/Health":         true,
-			"/airborne.v1.AdminService/Ready":          true,
-			"/airborne.v1.AdminService/Version":        true,
-			"/airborne.v1.FileService/CreateFileStore": true,
-			"/airborne.v1.FileService/UploadFile":      true,
-			"/airborne.v1.FileService/DeleteFileStore": true,
-			"/airborne.v1.FileService/GetFileStore":    true,
-			"/airborne.v1.FileService/ListFileStores":  true,
+			"/airborne.v1.AdminService/Health":            true,
+			"/airborne.v1.AdminService/Ready":             true,
+			"/airborne.v1.AdminService/Version":           true,
+			"/airborne.v1.AdminService/GetProviderHealth": true,
+			"/airborne.v1.FileService/CreateFileStore":    true,
+			"/airborne.v1.FileService/UploadFile":         true,
+			"/airborne.v1.FileService/DeleteFileStore":    true,
+			"/airborne.v1.FileService/GetFileStore":       true,
+			"/airborne.v1.FileService/ListFileStores":     true,
+			"/airborne.v1.FileService/Retrieve":           true,
+			"/grpc.health.v1.Health/Check":                true,
+			"/grpc.health.v1.Health/List":                 true,
+			"/grpc.health.v1.Health/Watch":                true,
+			// Reflection still requires authentication, but no tenant
+			"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      true,
+			"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": true,
 		},
 	}
 }
@@ -60,6 +69,7 @@ func (t *TenantInterceptor) UnaryInterceptor() grpc.UnaryServerInterceptor {
 
 		// Add tenant config to context
 		ctx = context.WithValue(ctx, TenantContextKey, tenantCfg)
+		ctx = isolation.WithTenant(ctx, tenantCfg.TenantID)
 
 		return handler(ctx, req)
 	}
@@ -144,6 +154,24 @@ func extractTenantID(req interface{}) string {
 		return r.TenantId
 	case *pb.SelectProviderRequest:
 		return r.TenantId
+	case *pb.GetCapabilitiesRequest:
+		return r.TenantId
+	case *pb.SummarizeDocumentRequest:
+		return r.TenantId
+	case *pb.EmbedRequest:
+		return r.TenantId
+	case *pb.AnalyzeTextRequest:
+		return r.TenantId
+	case *pb.RegenerateMessageRequest:
+		return r.TenantId
+	case *pb.ListBranchesRequest:
+		return r.TenantId
+	case *pb.SelectBranchRequest:
+		return r.TenantId
+	case *pb.ListMemoriesRequest:
+		return r.TenantId
+	case *pb.DeleteMemoryRequest:
+		return r.TenantId
 	default:
 		return ""
 	}
@@ -182,7 +210,8 @@ func (s *tenantStream) Context() context.Context {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.tenantCfg != nil {
-		return context.WithValue(s.ServerStream.Context(), TenantContextKey, s.tenantCfg)
+		ctx := context.WithValue(s.ServerStream.Context(), TenantContextKey, s.tenantCfg)
+		return isolation.WithTenant(ctx, s.tenantCfg.TenantID)
 	}
 	return s.ServerStream.Context()
 }
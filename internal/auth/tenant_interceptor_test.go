@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/isolation"
 	"github.com/ai8future/airborne/internal/tenant"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -86,6 +87,7 @@ func TestNewTenantInterceptor(t *testing.T) {
 		"/airborne.v1.AdminService/Health",
 		"/airborne.v1.AdminService/Ready",
 		"/airborne.v1.AdminService/Version",
+		"/airborne.v1.AdminService/GetProviderHealth",
 		"/airborne.v1.FileService/CreateFileStore",
 		"/airborne.v1.FileService/UploadFile",
 		"/airborne.v1.FileService/DeleteFileStore",
@@ -126,6 +128,21 @@ func TestExtractTenantID(t *testing.T) {
 			req:      &pb.SelectProviderRequest{},
 			expected: "",
 		},
+		{
+			name:     "GetCapabilitiesRequest with tenant_id",
+			req:      &pb.GetCapabilitiesRequest{TenantId: "tenant-321"},
+			expected: "tenant-321",
+		},
+		{
+			name:     "ListMemoriesRequest with tenant_id",
+			req:      &pb.ListMemoriesRequest{TenantId: "tenant-789"},
+			expected: "tenant-789",
+		},
+		{
+			name:     "DeleteMemoryRequest with tenant_id",
+			req:      &pb.DeleteMemoryRequest{TenantId: "tenant-789"},
+			expected: "tenant-789",
+		},
 		{
 			name:     "Unknown request type",
 			req:      struct{}{},
@@ -497,6 +514,9 @@ func TestTenantStream_Context(t *testing.T) {
 		if tenantCfg.TenantID != "ctx-tenant" {
 			t.Errorf("TenantID = %v, want ctx-tenant", tenantCfg.TenantID)
 		}
+		if tenantID, _ := isolation.TenantFromContext(ctx); tenantID != "ctx-tenant" {
+			t.Errorf("isolation tenant = %q, want ctx-tenant", tenantID)
+		}
 	})
 }
 
@@ -0,0 +1,180 @@
+// Package chaos injects faults at the server's dependency boundaries
+// (providers, Redis, the database and RAG) so failover, circuit breaking and
+// graceful degradation can be validated under controlled failures. Each
+// boundary has its own probabilities of added latency, errors and malformed
+// responses.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Boundaries faults are injected at.
+const (
+	BoundaryProvider = "provider"
+	BoundaryRedis    = "redis"
+	BoundaryDatabase = "database"
+	BoundaryRAG      = "rag"
+)
+
+// ErrInjected is wrapped by every injected error.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// injectedErrors mimic a real failure at each boundary, so callers classify
+// injected errors the way they would real ones (e.g. provider errors are
+// retryable and trigger failover).
+var injectedErrors = map[string]string{
+	BoundaryProvider: "503 Service Unavailable: overloaded",
+	BoundaryRedis:    "connection reset by peer",
+	BoundaryDatabase: "connection refused",
+	BoundaryRAG:      "503 Service Unavailable",
+}
+
+// FaultConfig sets the probability of each fault at one boundary, 0-1.
+type FaultConfig struct {
+	LatencyProbability   float64 `yaml:"latency_probability"`
+	LatencyMs            int     `yaml:"latency_ms"`            // Added latency
+	ErrorProbability     float64 `yaml:"error_probability"`     // Fail the call
+	MalformedProbability float64 `yaml:"malformed_probability"` // Corrupt the response (providers and RAG only)
+}
+
+// IsZero reports whether no fault is configured.
+func (c FaultConfig) IsZero() bool {
+	return c.LatencyProbability == 0 && c.ErrorProbability == 0 && c.MalformedProbability == 0
+}
+
+// Validate checks the probabilities and latency.
+func (c FaultConfig) Validate() error {
+	for name, p := range map[string]float64{
+		"latency_probability":   c.LatencyProbability,
+		"error_probability":     c.ErrorProbability,
+		"malformed_probability": c.MalformedProbability,
+	} {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", name, p)
+		}
+	}
+	if c.LatencyMs < 0 {
+		return fmt.Errorf("latency_ms must not be negative")
+	}
+	return nil
+}
+
+// Config enables fault injection and sets the faults of each boundary.
+type Config struct {
+	Enabled  bool        `yaml:"enabled"`
+	Seed     uint64      `yaml:"seed"` // Makes fault sequences repeatable; 0 seeds randomly
+	Provider FaultConfig `yaml:"provider"`
+	Redis    FaultConfig `yaml:"redis"`
+	Database FaultConfig `yaml:"database"`
+	RAG      FaultConfig `yaml:"rag"`
+}
+
+// Validate checks the faults of each boundary.
+func (c Config) Validate() error {
+	for name, f := range map[string]FaultConfig{
+		BoundaryProvider: c.Provider,
+		BoundaryRedis:    c.Redis,
+		BoundaryDatabase: c.Database,
+		BoundaryRAG:      c.RAG,
+	} {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Injector decides the faults of calls across one boundary. A nil Injector
+// injects nothing. It is safe for concurrent use.
+type Injector struct {
+	boundary string
+	cfg      FaultConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewInjector creates the injector of boundary, or nil when fault injection
+// is disabled or the boundary has no faults.
+func NewInjector(cfg Config, boundary string) *Injector {
+	if !cfg.Enabled {
+		return nil
+	}
+	faults := map[string]FaultConfig{
+		BoundaryProvider: cfg.Provider,
+		BoundaryRedis:    cfg.Redis,
+		BoundaryDatabase: cfg.Database,
+		BoundaryRAG:      cfg.RAG,
+	}[boundary]
+	if faults.IsZero() {
+		return nil
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &Injector{
+		boundary: boundary,
+		cfg:      faults,
+		rng:      rand.New(rand.NewPCG(seed, stream(boundary))),
+	}
+}
+
+// stream separates the random sequences of boundaries sharing a seed.
+func stream(boundary string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(boundary))
+	return h.Sum64()
+}
+
+// roll reports whether an event of probability p happens.
+func (i *Injector) roll(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64() < p
+}
+
+// Inject delays the call and returns the error to fail it with, or nil. It
+// returns ctx's error if ctx is done while delaying.
+func (i *Injector) Inject(ctx context.Context) error {
+	_, err := i.Fault(ctx)
+	return err
+}
+
+// Fault is Inject for boundaries that can also return malformed responses:
+// malformed reports whether the call's response should be corrupted.
+func (i *Injector) Fault(ctx context.Context) (malformed bool, err error) {
+	if i == nil {
+		return false, nil
+	}
+	if i.roll(i.cfg.LatencyProbability) && i.cfg.LatencyMs > 0 {
+		slog.Debug("chaos: injecting latency", "boundary", i.boundary, "latency_ms", i.cfg.LatencyMs)
+		timer := time.NewTimer(time.Duration(i.cfg.LatencyMs) * time.Millisecond)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		}
+	}
+	if i.roll(i.cfg.ErrorProbability) {
+		slog.Debug("chaos: injecting error", "boundary", i.boundary)
+		return false, fmt.Errorf("%w: %s: %s", ErrInjected, i.boundary, injectedErrors[i.boundary])
+	}
+	if i.roll(i.cfg.MalformedProbability) {
+		slog.Debug("chaos: injecting malformed response", "boundary", i.boundary)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,133 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/provider/mock"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+	"github.com/ai8future/airborne/internal/retry"
+)
+
+func TestNewInjector_Disabled(t *testing.T) {
+	faults := FaultConfig{ErrorProbability: 1}
+	if inj := NewInjector(Config{Provider: faults}, BoundaryProvider); inj != nil {
+		t.Error("expected no injector when chaos is disabled")
+	}
+	if inj := NewInjector(Config{Enabled: true, Provider: faults}, BoundaryRedis); inj != nil {
+		t.Error("expected no injector for a boundary without faults")
+	}
+
+	var inj *Injector
+	if malformed, err := inj.Fault(context.Background()); malformed || err != nil {
+		t.Errorf("nil injector injected malformed=%v err=%v", malformed, err)
+	}
+}
+
+func TestInjector_Fault(t *testing.T) {
+	ctx := context.Background()
+	inj := NewInjector(Config{Enabled: true, Redis: FaultConfig{ErrorProbability: 1}}, BoundaryRedis)
+	err := inj.Inject(ctx)
+	if !errors.Is(err, ErrInjected) {
+		t.Fatalf("err = %v, want ErrInjected", err)
+	}
+
+	inj = NewInjector(Config{Enabled: true, RAG: FaultConfig{MalformedProbability: 1}}, BoundaryRAG)
+	if malformed, err := inj.Fault(ctx); !malformed || err != nil {
+		t.Errorf("malformed = %v, err = %v, want a malformed response", malformed, err)
+	}
+
+	inj = NewInjector(Config{Enabled: true, Database: FaultConfig{LatencyProbability: 1, LatencyMs: 20}}, BoundaryDatabase)
+	start := time.Now()
+	if err := inj.Inject(ctx); err != nil || time.Since(start) < 20*time.Millisecond {
+		t.Errorf("err = %v after %v, want 20ms of latency", err, time.Since(start))
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := inj.Inject(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled while delayed", err)
+	}
+}
+
+func TestInjector_SeedRepeatable(t *testing.T) {
+	cfg := Config{Enabled: true, Seed: 42, Provider: FaultConfig{ErrorProbability: 0.3}}
+	sequence := func() []bool {
+		inj := NewInjector(cfg, BoundaryProvider)
+		var failed []bool
+		for range 50 {
+			failed = append(failed, inj.Inject(context.Background()) != nil)
+		}
+		return failed
+	}
+
+	first, second := sequence(), sequence()
+	var failures int
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("call %d differs between runs with the same seed", i)
+		}
+		if first[i] {
+			failures++
+		}
+	}
+	if failures == 0 || failures == 50 {
+		t.Errorf("failures = %d of 50 at probability 0.3", failures)
+	}
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	base := mock.NewClient(provider.NameOpenAI, mock.Config{})
+	if p := Provider(base, nil); p != provider.Provider(base) {
+		t.Error("expected the provider unwrapped without an injector")
+	}
+
+	p := Provider(base, NewInjector(Config{Enabled: true, Provider: FaultConfig{ErrorProbability: 1}}, BoundaryProvider))
+	_, err := p.GenerateReply(ctx, provider.GenerateParams{UserInput: "hi"})
+	if !retry.IsRetryable(err) {
+		t.Errorf("err = %v, want a retryable provider error", err)
+	}
+	if base.Calls() != 0 {
+		t.Errorf("provider called %d times, want the call failed before reaching it", base.Calls())
+	}
+
+	p = Provider(base, NewInjector(Config{Enabled: true, Provider: FaultConfig{MalformedProbability: 1}}, BoundaryProvider))
+	result, err := p.GenerateReply(ctx, provider.GenerateParams{UserInput: "hi"})
+	if err != nil || result.Text != "" || result.Usage != nil {
+		t.Errorf("result = %+v, err = %v, want a malformed reply", result, err)
+	}
+
+	ch, err := p.GenerateReplyStream(ctx, provider.GenerateParams{UserInput: "stream a few words"})
+	if err != nil {
+		t.Fatalf("GenerateReplyStream failed: %v", err)
+	}
+	var chunks []provider.StreamChunk
+	for chunk := range ch {
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) != 1 || chunks[0].Type != provider.ChunkTypeText {
+		t.Errorf("chunks = %+v, want a stream cut off after its first chunk", chunks)
+	}
+}
+
+func TestStore_Malformed(t *testing.T) {
+	base := testutil.NewMockStore()
+	ctx := context.Background()
+	if err := base.CreateCollection(ctx, "c", 2); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err)
+	}
+	if err := base.Upsert(ctx, "c", []vectorstore.Point{{ID: "p1", Vector: []float32{1, 0}, Payload: map[string]any{"text": "hello"}}}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	store := Store(base, NewInjector(Config{Enabled: true, RAG: FaultConfig{MalformedProbability: 1}}, BoundaryRAG))
+	results, err := store.Search(ctx, vectorstore.SearchParams{Collection: "c", Vector: []float32{1, 0}, Limit: 1})
+	if err != nil || len(results) != 1 || results[0].Payload != nil {
+		t.Errorf("results = %+v, err = %v, want one result without payload", results, err)
+	}
+}
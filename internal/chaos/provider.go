@@ -0,0 +1,63 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+// faultyProvider injects faults into a provider's calls.
+type faultyProvider struct {
+	provider.Provider
+	inj *Injector
+}
+
+// Provider wraps p so its calls get the injector's faults. A malformed reply
+// has no text, usage or model, as if the response failed to parse; a
+// malformed stream ends after its first chunk without completing. p is
+// returned unwrapped when inj is nil.
+func Provider(p provider.Provider, inj *Injector) provider.Provider {
+	if inj == nil || p == nil {
+		return p
+	}
+	return &faultyProvider{Provider: p, inj: inj}
+}
+
+func (p *faultyProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	malformed, err := p.inj.Fault(ctx)
+	if err != nil {
+		return provider.GenerateResult{}, err
+	}
+	result, err := p.Provider.GenerateReply(ctx, params)
+	if err != nil || !malformed {
+		return result, err
+	}
+	return provider.GenerateResult{ResponseJSON: []byte(`{"choices":[{"message":{"conte`)}, nil
+}
+
+func (p *faultyProvider) GenerateReplyStream(ctx context.Context, params provider.GenerateParams) (<-chan provider.StreamChunk, error) {
+	malformed, err := p.inj.Fault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := p.Provider.GenerateReplyStream(ctx, params)
+	if err != nil || !malformed {
+		return ch, err
+	}
+
+	out := make(chan provider.StreamChunk)
+	go func() {
+		defer close(out)
+		chunk, ok := <-ch
+		if ok {
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+			}
+		}
+		// Drain the rest so the provider's goroutine can finish
+		for range ch {
+		}
+	}()
+	return out, nil
+}
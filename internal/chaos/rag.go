@@ -0,0 +1,161 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/ai8future/airborne/internal/rag/embedder"
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+)
+
+// faultyEmbedder injects faults into an embedder's calls.
+type faultyEmbedder struct {
+	embedder.Embedder
+	inj *Injector
+}
+
+// Embedder wraps e so its calls get the injector's faults. Malformed
+// embeddings have half the expected dimensions. e is returned unwrapped when
+// inj is nil.
+func Embedder(e embedder.Embedder, inj *Injector) embedder.Embedder {
+	if inj == nil || e == nil {
+		return e
+	}
+	return &faultyEmbedder{Embedder: e, inj: inj}
+}
+
+func (e *faultyEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	malformed, err := e.inj.Fault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vec, err := e.Embedder.Embed(ctx, text)
+	if err != nil || !malformed {
+		return vec, err
+	}
+	return vec[:len(vec)/2], nil
+}
+
+func (e *faultyEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	malformed, err := e.inj.Fault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vecs, err := e.Embedder.EmbedBatch(ctx, texts)
+	if err != nil || !malformed {
+		return vecs, err
+	}
+	for i, vec := range vecs {
+		vecs[i] = vec[:len(vec)/2]
+	}
+	return vecs, nil
+}
+
+// faultyStore injects faults into a vector store's calls.
+type faultyStore struct {
+	vectorstore.Store
+	inj *Injector
+}
+
+// Store wraps s so its calls get the injector's faults. Malformed search
+// and scroll results have no payload. s is returned unwrapped when inj is
+// nil.
+func Store(s vectorstore.Store, inj *Injector) vectorstore.Store {
+	if inj == nil || s == nil {
+		return s
+	}
+	return &faultyStore{Store: s, inj: inj}
+}
+
+// stripPayloads returns results without their payloads.
+func stripPayloads(results []vectorstore.SearchResult) []vectorstore.SearchResult {
+	stripped := make([]vectorstore.SearchResult, len(results))
+	for i, r := range results {
+		stripped[i] = vectorstore.SearchResult{ID: r.ID, Score: r.Score, Vector: r.Vector}
+	}
+	return stripped
+}
+
+func (s *faultyStore) CreateCollection(ctx context.Context, name string, dimensions int) error {
+	if err := s.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.CreateCollection(ctx, name, dimensions)
+}
+
+func (s *faultyStore) DeleteCollection(ctx context.Context, name string) error {
+	if err := s.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.DeleteCollection(ctx, name)
+}
+
+func (s *faultyStore) CollectionExists(ctx context.Context, name string) (bool, error) {
+	if err := s.inj.Inject(ctx); err != nil {
+		return false, err
+	}
+	return s.Store.CollectionExists(ctx, name)
+}
+
+func (s *faultyStore) CollectionInfo(ctx context.Context, name string) (*vectorstore.CollectionInfo, error) {
+	if err := s.inj.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.CollectionInfo(ctx, name)
+}
+
+func (s *faultyStore) Upsert(ctx context.Context, collection string, points []vectorstore.Point) error {
+	if err := s.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.Upsert(ctx, collection, points)
+}
+
+func (s *faultyStore) Search(ctx context.Context, params vectorstore.SearchParams) ([]vectorstore.SearchResult, error) {
+	malformed, err := s.inj.Fault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.Store.Search(ctx, params)
+	if err != nil || !malformed {
+		return results, err
+	}
+	return stripPayloads(results), nil
+}
+
+func (s *faultyStore) Scroll(ctx context.Context, params vectorstore.ScrollParams) ([]vectorstore.SearchResult, error) {
+	malformed, err := s.inj.Fault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.Store.Scroll(ctx, params)
+	if err != nil || !malformed {
+		return results, err
+	}
+	return stripPayloads(results), nil
+}
+
+func (s *faultyStore) ScrollPage(ctx context.Context, params vectorstore.ScrollParams) ([]vectorstore.SearchResult, string, error) {
+	malformed, err := s.inj.Fault(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	results, next, err := s.Store.ScrollPage(ctx, params)
+	if err != nil || !malformed {
+		return results, next, err
+	}
+	return stripPayloads(results), next, nil
+}
+
+func (s *faultyStore) SwapCollection(ctx context.Context, name, target string) error {
+	if err := s.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.SwapCollection(ctx, name, target)
+}
+
+func (s *faultyStore) Delete(ctx context.Context, collection string, ids []string) error {
+	if err := s.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.Delete(ctx, collection, ids)
+}
@@ -55,18 +55,61 @@ type ActivityResponse struct {
 }
 
 type TestRequest struct {
-	Prompt   string `json:"prompt"`
-	TenantID string `json:"tenant_id"`
-	Provider string `json:"provider,omitempty"`
+	Prompt           string   `json:"prompt"`
+	TenantID         string   `json:"tenant_id"`
+	Provider         string   `json:"provider,omitempty"`
+	Instructions     string   `json:"instructions,omitempty"`
+	Model            string   `json:"model,omitempty"`
+	Preset           string   `json:"preset,omitempty"`
+	Temperature      *float64 `json:"temperature,omitempty"`
+	EnableWebSearch  bool     `json:"enable_web_search,omitempty"`
+	EnableFileSearch bool     `json:"enable_file_search,omitempty"`
+	FileStoreID      string   `json:"file_store_id,omitempty"`
+	StructuredOutput bool     `json:"structured_output,omitempty"`
+	Stream           bool     `json:"stream,omitempty"`
+}
+
+type TestCitation struct {
+	Type     string `json:"type"`
+	URL      string `json:"url,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Filename string `json:"filename,omitempty"`
 }
 
 type TestResponse struct {
-	Reply        string `json:"reply"`
-	Provider     string `json:"provider"`
-	Model        string `json:"model"`
-	InputTokens  int    `json:"input_tokens"`
-	OutputTokens int    `json:"output_tokens"`
-	ProcessingMs int    `json:"processing_ms"`
+	Reply              string          `json:"reply"`
+	Provider           string          `json:"provider"`
+	Model              string          `json:"model"`
+	InputTokens        int             `json:"input_tokens"`
+	OutputTokens       int             `json:"output_tokens"`
+	ProcessingMs       int             `json:"processing_ms"`
+	Citations          []TestCitation  `json:"citations,omitempty"`
+	ToolCalls          []string        `json:"tool_calls,omitempty"`
+	StructuredMetadata json.RawMessage `json:"structured_metadata,omitempty"`
+	SafetyBlock        string          `json:"safety_block,omitempty"`
+	Streamed           bool            `json:"streamed,omitempty"`
+	FirstTokenMs       int             `json:"first_token_ms,omitempty"`
+	Chunks             int             `json:"chunks,omitempty"`
+	Error              string          `json:"error,omitempty"`
+}
+
+type ProviderCheck struct {
+	TenantID  string `json:"tenant_id"`
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	Status    string `json:"status"`
+	LatencyMs int    `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ProviderCheckResponse struct {
+	Checks []ProviderCheck `json:"checks"`
+}
+
+type EffectiveConfigResponse struct {
+	Environment string         `json:"environment,omitempty"`
+	Sources     []string       `json:"sources"`
+	Config      map[string]any `json:"config"`
 }
 
 type DebugResponse struct {
@@ -77,6 +120,8 @@ type DebugResponse struct {
 	Timestamp        string  `json:"timestamp"`
 	SystemPrompt     string  `json:"system_prompt"`
 	UserInput        string  `json:"user_input"`
+	FinalSystem      string  `json:"final_system_prompt"`
+	FinalUser        string  `json:"final_user_prompt"`
 	RequestModel     string  `json:"request_model"`
 	RequestProvider  string  `json:"request_provider"`
 	ResponseText     string  `json:"response_text"`
@@ -171,9 +216,55 @@ func (c *Client) Test(req TestRequest) (*TestResponse, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&testResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	if testResp.Error != "" {
+		return nil, fmt.Errorf("test failed: %s", testResp.Error)
+	}
 	return &testResp, nil
 }
 
+func (c *Client) ProvidersCheck(tenantID string) (*ProviderCheckResponse, error) {
+	url := c.BaseURL + "/admin/providers/check"
+	if tenantID != "" {
+		url += "?tenant_id=" + tenantID
+	}
+
+	resp, err := c.HTTPClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var checkResp ProviderCheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&checkResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &checkResp, nil
+}
+
+func (c *Client) EffectiveConfig() (*EffectiveConfigResponse, error) {
+	resp, err := c.HTTPClient.Get(c.BaseURL + "/admin/config/effective")
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var cfgResp EffectiveConfigResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cfgResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &cfgResp, nil
+}
+
 func (c *Client) Debug(messageID string) (*DebugResponse, error) {
 	resp, err := c.HTTPClient.Get(c.BaseURL + "/admin/debug/" + messageID)
 	if err != nil {
@@ -9,7 +9,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ai8future/airborne/internal/config"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 type ClientFactory func(cmd *cobra.Command) *Client
@@ -80,11 +82,31 @@ func TestCmd(cf ClientFactory) *cobra.Command {
 			tenant, _ := cmd.Flags().GetString("tenant")
 			provider, _ := cmd.Flags().GetString("provider")
 			asJSON, _ := cmd.Flags().GetBool("json")
+			model, _ := cmd.Flags().GetString("model")
+			preset, _ := cmd.Flags().GetString("preset")
+			instructions, _ := cmd.Flags().GetString("instructions")
+			webSearch, _ := cmd.Flags().GetBool("web-search")
+			storeID, _ := cmd.Flags().GetString("store-id")
+			fileSearch, _ := cmd.Flags().GetBool("file-search")
+			structured, _ := cmd.Flags().GetBool("structured")
+			stream, _ := cmd.Flags().GetBool("stream")
 
 			req := TestRequest{
-				Prompt:   args[0],
-				TenantID: tenant,
-				Provider: provider,
+				Prompt:           args[0],
+				TenantID:         tenant,
+				Provider:         provider,
+				Instructions:     instructions,
+				Model:            model,
+				Preset:           preset,
+				EnableWebSearch:  webSearch,
+				EnableFileSearch: fileSearch || storeID != "",
+				FileStoreID:      storeID,
+				StructuredOutput: structured,
+				Stream:           stream,
+			}
+			if cmd.Flags().Changed("temperature") {
+				temp, _ := cmd.Flags().GetFloat64("temperature")
+				req.Temperature = &temp
 			}
 
 			fmt.Printf("Sending test prompt to %s...\n", tenant)
@@ -106,9 +128,147 @@ func TestCmd(cf ClientFactory) *cobra.Command {
 	}
 
 	cmd.Flags().StringP("provider", "p", "", "Provider to use (gemini, openai, anthropic)")
+	cmd.Flags().StringP("model", "m", "", "Model override")
+	cmd.Flags().String("preset", "", "Tenant generation preset (e.g. precise)")
+	cmd.Flags().String("instructions", "", "System instructions (default: concise assistant)")
+	cmd.Flags().Float64("temperature", 0, "Sampling temperature")
+	cmd.Flags().Bool("web-search", false, "Enable web search")
+	cmd.Flags().Bool("file-search", false, "Enable file search (requires --store-id)")
+	cmd.Flags().String("store-id", "", "File store ID for file search (implies --file-search)")
+	cmd.Flags().Bool("structured", false, "Enable structured output")
+	cmd.Flags().Bool("stream", false, "Use the streaming RPC and report time to first token")
 	return cmd
 }
 
+func ProvidersCmd(cf ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "Inspect tenant providers",
+	}
+	cmd.AddCommand(providersCheckCmd(cf))
+	return cmd
+}
+
+func providersCheckCmd(cf ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verify each enabled provider's API key with a minimal request",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := cf(cmd)
+			tenant, _ := cmd.Flags().GetString("tenant")
+			all, _ := cmd.Flags().GetBool("all")
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			if all {
+				tenant = ""
+			}
+
+			resp, err := client.ProvidersCheck(tenant)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(resp)
+			}
+
+			if len(resp.Checks) == 0 {
+				fmt.Println("No enabled providers")
+				return nil
+			}
+
+			PrintProviderChecks(resp.Checks)
+			for _, c := range resp.Checks {
+				if c.Status == "error" {
+					return fmt.Errorf("one or more provider checks failed")
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Check every tenant instead of --tenant")
+	return cmd
+}
+
+func ConfigCmd(cf ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect server configuration",
+	}
+	cmd.AddCommand(configEffectiveCmd(cf))
+	return cmd
+}
+
+func configEffectiveCmd(cf ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "effective",
+		Short: "Print the fully resolved config with secrets masked",
+		Long: `Print the configuration after merging the base file, the environment
+overlay, the local override and environment variables, with secrets masked.
+
+By default the running server is asked for its config. With --local the
+config is resolved from files and environment on this machine instead,
+which is useful for comparing environments before deploying.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			local, _ := cmd.Flags().GetBool("local")
+			env, _ := cmd.Flags().GetString("env")
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			var resp *EffectiveConfigResponse
+			if local {
+				if env != "" {
+					os.Setenv("AIRBORNE_ENV", env)
+				}
+				var err error
+				resp, err = localEffectiveConfig()
+				if err != nil {
+					return err
+				}
+			} else {
+				if env != "" {
+					return fmt.Errorf("--env requires --local; the server reports the environment it was started with")
+				}
+				var err error
+				resp, err = cf(cmd).EffectiveConfig()
+				if err != nil {
+					return err
+				}
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(resp)
+			}
+			return PrintEffectiveConfig(resp)
+		},
+	}
+
+	cmd.Flags().Bool("local", false, "Resolve config from local files and environment instead of the server")
+	cmd.Flags().String("env", "", "Environment overlay to apply with --local (sets AIRBORNE_ENV)")
+	return cmd
+}
+
+// localEffectiveConfig resolves config the way the server does at startup.
+func localEffectiveConfig() (*EffectiveConfigResponse, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		return nil, err
+	}
+	resp := &EffectiveConfigResponse{Environment: cfg.Environment, Sources: cfg.Sources}
+	if err := yaml.Unmarshal(data, &resp.Config); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func DebugCmd(cf ClientFactory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "debug [message-id]",
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/loadtest"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func LoadtestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "Drive concurrent GenerateReply traffic and report latency",
+		Long: "Sends concurrent GenerateReply and GenerateReplyStream requests to a server's gRPC API\n" +
+			"and reports throughput, latency percentiles and errors. With --mock the requests go to\n" +
+			"an in-process server whose providers answer after a fixed latency, measuring Airborne's\n" +
+			"own overhead without provider cost.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, _ := cmd.Flags().GetString("target")
+			apiKey, _ := cmd.Flags().GetString("api-key")
+			useTLS, _ := cmd.Flags().GetBool("tls")
+			caFile, _ := cmd.Flags().GetString("ca-file")
+			tenants, _ := cmd.Flags().GetStringSlice("tenants")
+			promptsFile, _ := cmd.Flags().GetString("prompts-file")
+			providerName, _ := cmd.Flags().GetString("provider")
+			mock, _ := cmd.Flags().GetBool("mock")
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			cfg := loadtest.Config{APIKey: apiKey, Tenants: tenants}
+			cfg.Concurrency, _ = cmd.Flags().GetInt("concurrency")
+			cfg.Requests, _ = cmd.Flags().GetInt("requests")
+			cfg.Duration, _ = cmd.Flags().GetDuration("duration")
+			cfg.StreamRatio, _ = cmd.Flags().GetFloat64("stream-ratio")
+			cfg.Timeout, _ = cmd.Flags().GetDuration("timeout")
+			if cfg.Duration > 0 && !cmd.Flags().Changed("requests") {
+				cfg.Requests = 0
+			}
+			if len(cfg.Tenants) == 0 {
+				tenant, _ := cmd.Flags().GetString("tenant")
+				cfg.Tenants = []string{tenant}
+			}
+			switch providerName {
+			case "":
+			case "openai":
+				cfg.Provider = pb.Provider_PROVIDER_OPENAI
+			case "gemini":
+				cfg.Provider = pb.Provider_PROVIDER_GEMINI
+			case "anthropic":
+				cfg.Provider = pb.Provider_PROVIDER_ANTHROPIC
+			default:
+				return fmt.Errorf("unknown provider %q", providerName)
+			}
+			if promptsFile != "" {
+				data, err := os.ReadFile(promptsFile)
+				if err != nil {
+					return fmt.Errorf("failed to read prompts: %w", err)
+				}
+				for _, line := range strings.Split(string(data), "\n") {
+					if line = strings.TrimSpace(line); line != "" {
+						cfg.Prompts = append(cfg.Prompts, line)
+					}
+				}
+			}
+
+			creds := insecure.NewCredentials()
+			if mock {
+				mockCfg := loadtest.MockConfig{Tenants: cfg.Tenants}
+				mockCfg.Latency, _ = cmd.Flags().GetDuration("mock-latency")
+				mockCfg.Chunks, _ = cmd.Flags().GetInt("mock-chunks")
+				mockCfg.ChunkInterval, _ = cmd.Flags().GetDuration("mock-chunk-interval")
+				mockCfg.ErrorRate, _ = cmd.Flags().GetFloat64("mock-error-rate")
+				srv, err := loadtest.StartMockServer(mockCfg)
+				if err != nil {
+					return err
+				}
+				defer srv.Stop()
+				target = srv.Addr
+			} else if useTLS || caFile != "" {
+				creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+				if caFile != "" {
+					var err error
+					if creds, err = credentials.NewClientTLSFromFile(caFile, ""); err != nil {
+						return fmt.Errorf("failed to load CA certificate: %w", err)
+					}
+				}
+			}
+
+			conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+			if err != nil {
+				return fmt.Errorf("failed to connect to %s: %w", target, err)
+			}
+			defer conn.Close()
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if !asJSON {
+				fmt.Printf("Load testing %s with %d workers...\n\n", target, max(cfg.Concurrency, 1))
+			}
+			report, err := loadtest.Run(ctx, pb.NewAirborneServiceClient(conn), cfg)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			report.Print(os.Stdout)
+			return nil
+		},
+	}
+
+	target := os.Getenv("AIRBORNE_GRPC_ADDR")
+	if target == "" {
+		target = "localhost:50051"
+	}
+	cmd.Flags().String("target", target, "gRPC address of the server (or AIRBORNE_GRPC_ADDR)")
+	cmd.Flags().String("api-key", os.Getenv("AIRBORNE_API_KEY"), "Client API key (or AIRBORNE_API_KEY)")
+	cmd.Flags().Bool("tls", false, "Connect with TLS")
+	cmd.Flags().String("ca-file", "", "CA certificate to verify the server with (implies --tls)")
+	cmd.Flags().IntP("concurrency", "c", 10, "Concurrent workers")
+	cmd.Flags().IntP("requests", "n", 100, "Total requests")
+	cmd.Flags().DurationP("duration", "d", 0, "Run for this long instead of a number of requests")
+	cmd.Flags().Float64("stream-ratio", 0, "Fraction of requests sent as streams, 0-1")
+	cmd.Flags().StringSlice("tenants", nil, "Tenants to spread requests across (default: --tenant)")
+	cmd.Flags().String("prompts-file", "", "File of prompts, one per line (default: built-in prompts)")
+	cmd.Flags().StringP("provider", "p", "", "Provider to request (gemini, openai, anthropic; default: tenant's)")
+	cmd.Flags().Duration("timeout", 60*time.Second, "Per-request timeout")
+	cmd.Flags().Bool("mock", false, "Run against an in-process server with mock providers")
+	cmd.Flags().Duration("mock-latency", 200*time.Millisecond, "Mock provider latency before replying or streaming")
+	cmd.Flags().Int("mock-chunks", 10, "Text chunks per mock streamed reply")
+	cmd.Flags().Duration("mock-chunk-interval", 20*time.Millisecond, "Delay between mock streamed chunks")
+	cmd.Flags().Float64("mock-error-rate", 0, "Fraction of mock provider calls that fail, 0-1")
+	return cmd
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -82,6 +83,55 @@ func PrintActivityTable(activities []Activity) {
 	}
 }
 
+func PrintEffectiveConfig(resp *EffectiveConfigResponse) error {
+	env := resp.Environment
+	if env == "" {
+		env = "(none)"
+	}
+	fmt.Println(cyan("# environment: " + env))
+	if len(resp.Sources) == 0 {
+		fmt.Println(cyan("# sources: none (defaults and environment variables only)"))
+	} else {
+		fmt.Println(cyan("# sources, lowest precedence first, then environment variables:"))
+		for _, src := range resp.Sources {
+			fmt.Println(cyan("#   " + src))
+		}
+	}
+
+	data, err := yaml.Marshal(resp.Config)
+	if err != nil {
+		return fmt.Errorf("failed to format config: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+func PrintProviderChecks(checks []ProviderCheck) {
+	fmt.Printf("%-10s  %-10s  %-28s  %-7s  %-6s  %s\n",
+		"TENANT", "PROVIDER", "MODEL", "STATUS", "LAT", "ERROR")
+	fmt.Println(strings.Repeat("-", 85))
+
+	for _, c := range checks {
+		// Pad before coloring so color codes do not break alignment
+		status := fmt.Sprintf("%-7s", c.Status)
+		switch c.Status {
+		case "ok":
+			status = green(status)
+		case "error":
+			status = red(status)
+		default:
+			status = yellow(status)
+		}
+		fmt.Printf("%-10s  %-10s  %-28s  %s  %-6s  %s\n",
+			TruncateString(c.TenantID, 10),
+			c.Provider,
+			TruncateString(c.Model, 28),
+			status,
+			FormatDuration(c.LatencyMs),
+			c.Error)
+	}
+}
+
 func PrintActivityDetail(a Activity) {
 	fmt.Printf("%s %s\n", bold("ID:"), a.ID)
 	fmt.Printf("%s %s\n", bold("Thread:"), a.ThreadID)
@@ -118,6 +168,17 @@ func PrintDebugInfo(d *DebugResponse) {
 	fmt.Printf("%s\n", bold("User Input:"))
 	fmt.Printf("%s\n\n", d.UserInput)
 
+	// What the provider was actually sent, after RAG context and file
+	// mappings were folded in
+	if d.FinalSystem != "" && d.FinalSystem != d.SystemPrompt {
+		fmt.Printf("%s\n", bold("Final System Prompt (as sent):"))
+		fmt.Printf("%s\n\n", cyan(d.FinalSystem))
+	}
+	if d.FinalUser != "" && d.FinalUser != d.UserInput {
+		fmt.Printf("%s\n", bold("Final User Prompt (as sent):"))
+		fmt.Printf("%s\n\n", d.FinalUser)
+	}
+
 	fmt.Printf("%s\n", bold("Response:"))
 	fmt.Printf("%s\n", d.ResponseText)
 }
@@ -138,7 +199,35 @@ func PrintTestResult(r *TestResponse) {
 	fmt.Printf("%s %s (%s)\n", bold("Model:"), r.Model, r.Provider)
 	fmt.Printf("%s %d in / %d out\n", bold("Tokens:"), r.InputTokens, r.OutputTokens)
 	fmt.Printf("%s %s\n", bold("Duration:"), FormatDuration(r.ProcessingMs))
+	if r.Streamed {
+		fmt.Printf("%s %s to first token, %d chunks\n", bold("Stream:"), FormatDuration(r.FirstTokenMs), r.Chunks)
+	}
+	if r.SafetyBlock != "" {
+		fmt.Printf("%s %s\n", bold("Safety block:"), r.SafetyBlock)
+	}
+	if len(r.ToolCalls) > 0 {
+		fmt.Printf("%s %s\n", bold("Tool calls:"), strings.Join(r.ToolCalls, ", "))
+	}
 	fmt.Println()
 	fmt.Printf("%s\n", bold("Response:"))
 	fmt.Println(r.Reply)
+	if len(r.Citations) > 0 {
+		fmt.Println()
+		fmt.Printf("%s\n", bold("Citations:"))
+		for i, c := range r.Citations {
+			source := c.URL
+			if source == "" {
+				source = c.Filename
+			}
+			if c.Title != "" {
+				source = c.Title + " - " + source
+			}
+			fmt.Printf("  [%d] %s\n", i+1, source)
+		}
+	}
+	if len(r.StructuredMetadata) > 0 {
+		fmt.Println()
+		fmt.Printf("%s\n", bold("Structured metadata:"))
+		fmt.Println(string(r.StructuredMetadata))
+	}
 }
@@ -7,12 +7,20 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/chaos"
 	"github.com/ai8future/airborne/internal/config/envutil"
+	"github.com/ai8future/airborne/internal/config/schema"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/httpcapture"
+	"github.com/ai8future/airborne/internal/provider/mock"
+	"github.com/ai8future/airborne/internal/redis"
+	"github.com/ai8future/airborne/internal/validation"
 )
 
 // Config holds all server configuration
@@ -21,30 +29,156 @@ type Config struct {
 	TLS             TLSConfig                 `yaml:"tls"`
 	Redis           RedisConfig               `yaml:"redis"`
 	Database        DatabaseConfig            `yaml:"database"`
+	Events          EventsConfig              `yaml:"events"`
+	Analytics       AnalyticsConfig           `yaml:"analytics"`
 	Admin           AdminConfig               `yaml:"admin"`
 	Auth            AuthConfig                `yaml:"auth"`
 	RateLimits      RateLimitConfig           `yaml:"rate_limits"`
+	QoS             QoSConfig                 `yaml:"qos"`
 	Providers       map[string]ProviderConfig `yaml:"providers"`
 	Failover        FailoverConfig            `yaml:"failover"`
 	Logging         LoggingConfig             `yaml:"logging"`
 	StartupMode     StartupMode               `yaml:"startup_mode"`
 	RAG             RAGConfig                 `yaml:"rag"`
+	Models          ModelsConfig              `yaml:"models"`
+	Egress          EgressConfig              `yaml:"egress"`
+	MockProviders   MockProvidersConfig       `yaml:"mock_providers"`
+	Chaos           chaos.Config              `yaml:"chaos"`
 	MarkdownSvcAddr string                    `yaml:"markdown_svc_addr"`
+
+	// Environment (AIRBORNE_ENV) and Sources, the config files merged in
+	// precedence order, record how the config was assembled.
+	Environment string   `yaml:"-" json:"-"`
+	Sources     []string `yaml:"-" json:"-"`
+}
+
+// ModelsConfig holds server-wide model catalog policy
+type ModelsConfig struct {
+	// Deny lists models no tenant may select (e.g., deprecated or expensive models).
+	// Matching is case-insensitive; a trailing "*" matches any suffix.
+	Deny []string `yaml:"deny"`
+}
+
+// EgressConfig holds policy for outbound provider requests
+type EgressConfig struct {
+	// Allowlist exempts internal gateways from the SSRF private IP checks
+	// applied to custom provider and filestore base URLs. Entries are
+	// hostnames, "*.suffix" wildcards, IP addresses or CIDRs.
+	Allowlist []string `yaml:"allowlist"`
+
+	// Proxy routes provider traffic (SDK clients, filestore and embedding
+	// requests) through an HTTP, HTTPS or SOCKS5 proxy. Tenants may
+	// override it with their own proxy.
+	Proxy egress.ProxyConfig `yaml:"proxy"`
+
+	// Audit logs every outbound host contacted and can block hosts
+	// outside its allowlist.
+	Audit egress.AuditConfig `yaml:"audit"`
+
+	// Fixtures records provider responses, scrubbed of credentials, or
+	// replays them without network access. Refused in production startup
+	// mode.
+	Fixtures httpcapture.FixtureConfig `yaml:"fixtures"`
+}
+
+// MockProvidersConfig replaces the OpenAI, Gemini and Anthropic clients
+// with mock providers (see provider/mock) for integration tests. It is
+// refused in production startup mode.
+type MockProvidersConfig struct {
+	Enabled         bool                `yaml:"enabled"`
+	LatencyMs       int                 `yaml:"latency_ms"`        // Before a reply or the first streamed chunk
+	ChunkIntervalMs int                 `yaml:"chunk_interval_ms"` // Between streamed chunks
+	ErrorRate       float64             `yaml:"error_rate"`        // Fraction of calls failing with a 503
+	Script          []string            `yaml:"script"`            // Outcomes cycled through per call (ok, 429, 500, 503, 401, timeout, safety_block, empty)
+	Scripts         map[string][]string `yaml:"scripts"`           // Per-provider scripts, replacing script for that provider
 }
 
-// DatabaseConfig holds PostgreSQL connection settings
+// ProviderConfig returns the mock configuration of the named provider.
+func (c MockProvidersConfig) ProviderConfig(name string) mock.Config {
+	script := c.Script
+	if s, ok := c.Scripts[name]; ok {
+		script = s
+	}
+	return mock.Config{
+		Latency:       time.Duration(c.LatencyMs) * time.Millisecond,
+		ChunkInterval: time.Duration(c.ChunkIntervalMs) * time.Millisecond,
+		ErrorRate:     c.ErrorRate,
+		Script:        script,
+	}
+}
+
+// DatabaseConfig holds database connection settings
 type DatabaseConfig struct {
-	Enabled        bool   `yaml:"enabled"`
-	URL            string `yaml:"url"`
-	MaxConnections int    `yaml:"max_connections"`
-	LogQueries     bool   `yaml:"log_queries"`
-	CACert         string `yaml:"ca_cert"` // PEM-encoded CA certificate for SSL verification
+	Enabled           bool   `yaml:"enabled"`
+	Driver            string `yaml:"driver"`      // "postgres" (default) or "sqlite"
+	URL               string `yaml:"url"`         // Connection URL, or database file path for SQLite
+	ReplicaURL        string `yaml:"replica_url"` // Optional read replica for dashboard queries (PostgreSQL only)
+	MaxConnections    int    `yaml:"max_connections"`
+	LogQueries        bool   `yaml:"log_queries"`
+	RollupIntervalSec int    `yaml:"rollup_interval_sec"` // Activity rollup refresh interval (default 300)
+	CACert            string `yaml:"ca_cert"`             // PEM-encoded CA certificate for SSL verification
+}
+
+// EventsConfig holds transactional outbox and event delivery settings
+type EventsConfig struct {
+	Enabled            bool            `yaml:"enabled"`              // Write events to the outbox and run the dispatcher (requires database)
+	DispatchIntervalMs int             `yaml:"dispatch_interval_ms"` // Outbox poll interval (default 2000)
+	BatchSize          int             `yaml:"batch_size"`           // Events claimed per poll (default 100)
+	MaxAttempts        int             `yaml:"max_attempts"`         // Delivery attempts before dead-lettering (default 10)
+	RetentionHours     int             `yaml:"retention_hours"`      // How long delivered events are kept (default 168)
+	Webhooks           []WebhookConfig `yaml:"webhooks"`
+	Kafka              KafkaConfig     `yaml:"kafka"` // Generation event stream (tenants opt in via event_stream)
+	NATS               NATSConfig      `yaml:"nats"`
+}
+
+// AnalyticsConfig holds conversation analytics job settings
+type AnalyticsConfig struct {
+	Enabled     bool   `yaml:"enabled"`      // Analyze stored user messages in the background (requires database)
+	IntervalSec int    `yaml:"interval_sec"` // Time between runs (default 300)
+	BatchSize   int    `yaml:"batch_size"`   // Messages analyzed per tenant per run (default 50)
+	Provider    string `yaml:"provider"`     // Provider to extract with (default: each tenant's default provider)
+	Model       string `yaml:"model"`        // Model to extract with (default: the provider's model)
+}
+
+// KafkaConfig holds Kafka event stream settings
+type KafkaConfig struct {
+	Brokers []string `yaml:"brokers"` // Empty disables Kafka publishing
+	Topic   string   `yaml:"topic"`
+}
+
+// NATSConfig holds NATS event stream settings
+type NATSConfig struct {
+	URL       string `yaml:"url"`       // Empty disables NATS publishing
+	Subject   string `yaml:"subject"`   // Events go to "<subject>.<tenant_id>"
+	JetStream bool   `yaml:"jetstream"` // Wait for JetStream acknowledgement
+}
+
+// WebhookConfig describes one webhook event sink
+type WebhookConfig struct {
+	URL    string   `yaml:"url"`
+	Secret string   `yaml:"secret"` // Optional HMAC-SHA256 signing secret
+	Events []string `yaml:"events"` // Event types to deliver (empty = all)
+}
+
+// QoSConfig holds request prioritization settings
+type QoSConfig struct {
+	MaxConcurrent     int  `yaml:"max_concurrent"`       // Concurrent provider calls per server (0 disables QoS scheduling)
+	MaxQueue          int  `yaml:"max_queue"`            // Requests waiting for a slot (default 100)
+	PressureWindowSec int  `yaml:"pressure_window_sec"`  // How long batch traffic is shed after a provider rate limit (default 30)
+	ProviderHeadroom  bool `yaml:"provider_headroom"`    // Track provider rate-limit headers and avoid requests they would reject
+	HeadroomMaxWaitMs int  `yaml:"headroom_max_wait_ms"` // Longest wait for headroom before failing over or rejecting (default 2000)
+	DedupWindowMs     int  `yaml:"dedup_window_ms"`      // Share replies among identical requests in flight or this recent (0 disables)
 }
 
 // AdminConfig holds HTTP admin server settings
 type AdminConfig struct {
 	Enabled bool `yaml:"enabled"`
 	Port    int  `yaml:"port"`
+	Docs    bool `yaml:"docs"` // Serve Swagger UI at /admin/docs; the spec is always at /admin/openapi.json
+
+	// Network restricts the addresses and countries the admin HTTP server
+	// accepts requests from, except for /admin/health
+	Network auth.NetworkRestrictions `yaml:"network"`
 }
 
 // RAGConfig holds RAG (Retrieval-Augmented Generation) settings
@@ -57,12 +191,18 @@ type RAGConfig struct {
 	ChunkSize      int    `yaml:"chunk_size"`
 	ChunkOverlap   int    `yaml:"chunk_overlap"`
 	RetrievalTopK  int    `yaml:"retrieval_top_k"`
+
+	// Resumable uploads are staged in UploadDir (default: a directory under
+	// the system temp dir) and discarded after UploadTTLMinutes without a chunk
+	UploadDir        string `yaml:"upload_dir"`
+	UploadTTLMinutes int    `yaml:"upload_ttl_minutes"`
 }
 
 // ServerConfig holds server settings
 type ServerConfig struct {
-	GRPCPort int    `yaml:"grpc_port"`
-	Host     string `yaml:"host"`
+	GRPCPort   int    `yaml:"grpc_port"`
+	Host       string `yaml:"host"`
+	Reflection bool   `yaml:"reflection"` // Register gRPC server reflection (for grpcurl)
 }
 
 // TLSConfig holds TLS settings
@@ -74,15 +214,121 @@ type TLSConfig struct {
 
 // RedisConfig holds Redis connection settings
 type RedisConfig struct {
-	Addr     string `yaml:"addr"`
-	Password string `yaml:"password"`
-	DB       int    `yaml:"db"`
+	Mode             string              `yaml:"mode"` // standalone (default), sentinel or cluster
+	Addr             string              `yaml:"addr"`
+	Addrs            []string            `yaml:"addrs"`       // Sentinel or cluster seed addresses
+	MasterName       string              `yaml:"master_name"` // Sentinel master name
+	Username         string              `yaml:"username"`
+	Password         string              `yaml:"password"`
+	SentinelUsername string              `yaml:"sentinel_username"`
+	SentinelPassword string              `yaml:"sentinel_password"`
+	DB               int                 `yaml:"db"`
+	PoolSize         int                 `yaml:"pool_size"` // Per node (default 10)
+	TLS              RedisTLSConfig      `yaml:"tls"`
+	Fallback         RedisFallbackConfig `yaml:"fallback"`
+}
+
+// RedisFallbackConfig sets, per feature, what happens while Redis is
+// unavailable: "memory" keeps per-instance state, "fail_closed" rejects
+// requests that need the feature.
+type RedisFallbackConfig struct {
+	RateLimit   string `yaml:"rate_limit"`
+	Idempotency string `yaml:"idempotency"`
+}
+
+// RedisTLSConfig holds TLS settings for Redis connections
+type RedisTLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// ClientConfig converts the settings to a Redis client configuration.
+func (r RedisConfig) ClientConfig() redis.Config {
+	return redis.Config{
+		Mode:             r.Mode,
+		Addr:             r.Addr,
+		Addrs:            r.Addrs,
+		MasterName:       r.MasterName,
+		Username:         r.Username,
+		Password:         r.Password,
+		SentinelUsername: r.SentinelUsername,
+		SentinelPassword: r.SentinelPassword,
+		DB:               r.DB,
+		PoolSize:         r.PoolSize,
+		TLS: redis.TLSConfig{
+			Enabled:            r.TLS.Enabled,
+			CAFile:             r.TLS.CAFile,
+			CertFile:           r.TLS.CertFile,
+			KeyFile:            r.TLS.KeyFile,
+			ServerName:         r.TLS.ServerName,
+			InsecureSkipVerify: r.TLS.InsecureSkipVerify,
+		},
+	}
 }
 
 // AuthConfig holds authentication settings
 type AuthConfig struct {
-	AdminToken string `yaml:"admin_token"`
-	AuthMode   string `yaml:"auth_mode"` // "static" (default) or "redis"
+	AdminToken      string                `yaml:"admin_token"`
+	AuthMode        string                `yaml:"auth_mode"` // "static" (default) or "redis"
+	InternalSigning InternalSigningConfig `yaml:"internal_signing"`
+	GeoIPFile       string                `yaml:"geoip_file"` // CSV of network,country lines; needed by country restrictions
+}
+
+// InternalSigningConfig holds the HMAC keys that sign admin dashboard
+// requests to the gRPC server in place of the static admin token.
+// To rotate, add the new key, switch active_key to it, then remove the old key.
+type InternalSigningConfig struct {
+	Enabled    bool               `yaml:"enabled"`
+	ActiveKey  string             `yaml:"active_key"`   // Key ID the admin dashboard signs with
+	Keys       []SigningKeyConfig `yaml:"keys"`         // Keys the gRPC server accepts
+	MaxSkewSec int                `yaml:"max_skew_sec"` // Accepted clock skew and replay window (default 300)
+
+	// Network restricts the addresses and countries signed requests are
+	// accepted from, such as the admin dashboard's host
+	Network auth.NetworkRestrictions `yaml:"network"`
+}
+
+// SigningKeyConfig is one internal signing key.
+type SigningKeyConfig struct {
+	ID     string `yaml:"id"`
+	Secret string `yaml:"secret"`
+}
+
+// SigningKeys returns the configured keys.
+func (c InternalSigningConfig) SigningKeys() []auth.SigningKey {
+	keys := make([]auth.SigningKey, len(c.Keys))
+	for i, k := range c.Keys {
+		keys[i] = auth.SigningKey{ID: k.ID, Secret: k.Secret}
+	}
+	return keys
+}
+
+// SigningKey returns the active key, and false if it is not configured.
+func (c InternalSigningConfig) SigningKey() (auth.SigningKey, bool) {
+	for _, k := range c.Keys {
+		if k.ID == c.ActiveKey {
+			return auth.SigningKey{ID: k.ID, Secret: k.Secret}, true
+		}
+	}
+	return auth.SigningKey{}, false
+}
+
+// Validate checks the signing keys when signing is enabled.
+func (c InternalSigningConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxSkewSec < 0 {
+		return fmt.Errorf("max_skew_sec must not be negative")
+	}
+	if err := c.Network.Validate(); err != nil {
+		return fmt.Errorf("network: %w", err)
+	}
+	return auth.ValidateSigningKeys(c.SigningKeys(), c.ActiveKey)
 }
 
 // RateLimitConfig holds default rate limits
@@ -107,12 +353,24 @@ type FailoverConfig struct {
 
 // LoggingConfig holds logging settings
 type LoggingConfig struct {
-	Level  string `yaml:"level"`
-	Format string `yaml:"format"`
+	Level     string          `yaml:"level"`
+	Format    string          `yaml:"format"`
+	AccessLog AccessLogConfig `yaml:"access_log"`
+}
+
+// AccessLogConfig holds gRPC access log settings
+type AccessLogConfig struct {
+	SampleRate      float64 `yaml:"sample_rate"`       // Fraction (0-1) of successful requests logged; failures and slow requests are always logged
+	SlowThresholdMs int     `yaml:"slow_threshold_ms"` // Requests at or above this latency are logged as slow (0 disables)
 }
 
 // Load loads configuration from file and environment variables.
 // If AIRBORNE_USE_FROZEN is set to "true", loads from frozen config instead.
+//
+// Settings are applied in increasing precedence: defaults, the base config
+// file (AIRBORNE_CONFIG, default configs/airborne.yaml), the overlay for
+// AIRBORNE_ENV (e.g. configs/airborne.staging.yaml), the untracked local
+// override (configs/airborne.local.yaml), then environment variables.
 func Load() (*Config, error) {
 	// Check if we should use frozen config
 	if os.Getenv("AIRBORNE_USE_FROZEN") == "true" {
@@ -126,23 +384,17 @@ func Load() (*Config, error) {
 
 	cfg := defaultConfig()
 
-	// Try to load from file
+	// Layer base config, environment overlay and local override files
 	configPath := os.Getenv("AIRBORNE_CONFIG")
 	if configPath == "" {
 		configPath = "configs/airborne.yaml"
 	}
-
-	data, err := os.ReadFile(configPath)
+	cfg.Environment = strings.TrimSpace(os.Getenv("AIRBORNE_ENV"))
+	sources, err := loadLayers(cfg, configPath, cfg.Environment)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
-		}
-		// File doesn't exist - continue with defaults
-	} else {
-		if err := yaml.Unmarshal(data, cfg); err != nil {
-			return nil, fmt.Errorf("failed to parse config file: %w", err)
-		}
+		return nil, err
 	}
+	cfg.Sources = sources
 
 	// Override with environment variables
 	cfg.applyEnvOverrides()
@@ -152,7 +404,7 @@ func Load() (*Config, error) {
 
 	// Validate
 	if err := cfg.validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
 	}
 
 	return cfg, nil
@@ -186,6 +438,11 @@ func LoadFrozen(path string) (*Config, error) {
 		return nil, fmt.Errorf("frozen config missing global_config")
 	}
 
+	// Snapshots frozen before access log settings existed keep logging every request
+	if cfg.Logging.AccessLog == (AccessLogConfig{}) {
+		cfg.Logging.AccessLog = defaultConfig().Logging.AccessLog
+	}
+
 	// Resolve ENV=/FILE= references in config
 	cfg.expandEnvVars()
 
@@ -193,6 +450,12 @@ func LoadFrozen(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// Default returns the built-in defaults, without config files or
+// environment variables applied.
+func Default() *Config {
+	return defaultConfig()
+}
+
 // defaultConfig returns configuration with sensible defaults
 func defaultConfig() *Config {
 	return &Config{
@@ -206,11 +469,29 @@ func defaultConfig() *Config {
 		Redis: RedisConfig{
 			Addr: "localhost:6379",
 			DB:   0,
+			Fallback: RedisFallbackConfig{
+				RateLimit:   redis.PolicyMemory,
+				Idempotency: redis.PolicyMemory,
+			},
 		},
 		Database: DatabaseConfig{
-			Enabled:        false,
-			MaxConnections: 10,
-			LogQueries:     false,
+			Enabled:           false,
+			MaxConnections:    10,
+			LogQueries:        false,
+			RollupIntervalSec: 300,
+		},
+		Events: EventsConfig{
+			Enabled:            false,
+			DispatchIntervalMs: 2000,
+			BatchSize:          100,
+			MaxAttempts:        10,
+			RetentionHours:     168,
+			Kafka:              KafkaConfig{Topic: "airborne.generations"},
+			NATS:               NATSConfig{Subject: "airborne.generations"},
+		},
+		Analytics: AnalyticsConfig{
+			IntervalSec: 300,
+			BatchSize:   50,
 		},
 		Admin: AdminConfig{
 			Enabled: false,
@@ -224,6 +505,11 @@ func defaultConfig() *Config {
 			DefaultRPD: 10000,
 			DefaultTPM: 100000,
 		},
+		QoS: QoSConfig{
+			MaxQueue:          100,
+			PressureWindowSec: 30,
+			HeadroomMaxWaitMs: 2000,
+		},
 		Providers: map[string]ProviderConfig{
 			"openai": {
 				Enabled:      true,
@@ -245,6 +531,10 @@ func defaultConfig() *Config {
 		Logging: LoggingConfig{
 			Level:  "info",
 			Format: "json",
+			AccessLog: AccessLogConfig{
+				SampleRate:      1.0,
+				SlowThresholdMs: 5000,
+			},
 		},
 		StartupMode: StartupModeProduction,
 		RAG: RAGConfig{
@@ -256,6 +546,8 @@ func defaultConfig() *Config {
 			ChunkSize:      2000,
 			ChunkOverlap:   200,
 			RetrievalTopK:  5,
+
+			UploadTTLMinutes: 1440,
 		},
 	}
 }
@@ -265,6 +557,7 @@ func (c *Config) applyEnvOverrides() {
 	// Server configuration
 	c.Server.GRPCPort = envutil.GetIntEnv("AIRBORNE_GRPC_PORT", c.Server.GRPCPort)
 	c.Server.Host = envutil.GetStringEnv("AIRBORNE_HOST", c.Server.Host)
+	c.Server.Reflection = envutil.GetBoolEnv("AIRBORNE_GRPC_REFLECTION", c.Server.Reflection)
 
 	// TLS configuration
 	c.TLS.Enabled = envutil.GetBoolEnv("AIRBORNE_TLS_ENABLED", c.TLS.Enabled)
@@ -275,14 +568,31 @@ func (c *Config) applyEnvOverrides() {
 	c.Redis.Addr = envutil.GetStringEnv("REDIS_ADDR", c.Redis.Addr)
 	c.Redis.Password = envutil.GetStringEnv("REDIS_PASSWORD", c.Redis.Password)
 	c.Redis.DB = envutil.GetIntEnv("REDIS_DB", c.Redis.DB)
+	c.Redis.Mode = envutil.GetStringEnv("REDIS_MODE", c.Redis.Mode)
+	if addrs := os.Getenv("REDIS_ADDRS"); addrs != "" {
+		c.Redis.Addrs = nil
+		for _, a := range strings.Split(addrs, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				c.Redis.Addrs = append(c.Redis.Addrs, a)
+			}
+		}
+	}
+	c.Redis.MasterName = envutil.GetStringEnv("REDIS_MASTER_NAME", c.Redis.MasterName)
+	c.Redis.Username = envutil.GetStringEnv("REDIS_USERNAME", c.Redis.Username)
+	c.Redis.SentinelPassword = envutil.GetStringEnv("REDIS_SENTINEL_PASSWORD", c.Redis.SentinelPassword)
+	c.Redis.TLS.Enabled = envutil.GetBoolEnv("REDIS_TLS_ENABLED", c.Redis.TLS.Enabled)
+	c.Redis.TLS.CAFile = envutil.GetStringEnv("REDIS_TLS_CA_FILE", c.Redis.TLS.CAFile)
+	c.Redis.Fallback.RateLimit = envutil.GetStringEnv("REDIS_FALLBACK_RATE_LIMIT", c.Redis.Fallback.RateLimit)
+	c.Redis.Fallback.Idempotency = envutil.GetStringEnv("REDIS_FALLBACK_IDEMPOTENCY", c.Redis.Fallback.Idempotency)
 
 	// Database configuration
 	c.Database.Enabled = envutil.GetBoolEnv("DATABASE_ENABLED", c.Database.Enabled)
+	c.Database.Driver = envutil.GetStringEnv("DATABASE_DRIVER", c.Database.Driver)
 
-	// Database URL - try environment first, then Doppler
+	// Database URL - try environment first, then Doppler (PostgreSQL only)
 	if url := os.Getenv("DATABASE_URL"); url != "" {
 		c.Database.URL = url
-	} else {
+	} else if c.Database.Driver != "sqlite" {
 		// Try to fetch DATABASE_URL from Doppler supabase project
 		if dopplerURL := fetchDopplerSecret("supabase", "DATABASE_URL"); dopplerURL != "" {
 			c.Database.URL = dopplerURL
@@ -307,20 +617,78 @@ func (c *Config) applyEnvOverrides() {
 		fmt.Fprintf(os.Stderr, "WARNING: DATABASE_URL is set but database is not enabled. Set DATABASE_ENABLED=true to use database persistence.\n")
 	}
 
+	c.Database.ReplicaURL = envutil.GetStringEnv("DATABASE_REPLICA_URL", c.Database.ReplicaURL)
 	c.Database.MaxConnections = envutil.GetIntEnv("DATABASE_MAX_CONNECTIONS", c.Database.MaxConnections)
 	c.Database.LogQueries = envutil.GetBoolEnv("DATABASE_LOG_QUERIES", c.Database.LogQueries)
+	c.Database.RollupIntervalSec = envutil.GetIntEnv("DATABASE_ROLLUP_INTERVAL_SEC", c.Database.RollupIntervalSec)
+
+	// Event outbox configuration (EVENTS_WEBHOOK_URL adds a webhook sink)
+	c.Events.Enabled = envutil.GetBoolEnv("EVENTS_ENABLED", c.Events.Enabled)
+	c.Events.DispatchIntervalMs = envutil.GetIntEnv("EVENTS_DISPATCH_INTERVAL_MS", c.Events.DispatchIntervalMs)
+	c.Events.MaxAttempts = envutil.GetIntEnv("EVENTS_MAX_ATTEMPTS", c.Events.MaxAttempts)
+	if url := os.Getenv("EVENTS_WEBHOOK_URL"); url != "" {
+		c.Events.Webhooks = append(c.Events.Webhooks, WebhookConfig{
+			URL:    url,
+			Secret: os.Getenv("EVENTS_WEBHOOK_SECRET"),
+		})
+	}
+
+	if brokers := os.Getenv("EVENTS_KAFKA_BROKERS"); brokers != "" {
+		c.Events.Kafka.Brokers = nil
+		for _, b := range strings.Split(brokers, ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				c.Events.Kafka.Brokers = append(c.Events.Kafka.Brokers, b)
+			}
+		}
+	}
+	c.Events.Kafka.Topic = envutil.GetStringEnv("EVENTS_KAFKA_TOPIC", c.Events.Kafka.Topic)
+	c.Events.NATS.URL = envutil.GetStringEnv("EVENTS_NATS_URL", c.Events.NATS.URL)
+	c.Events.NATS.Subject = envutil.GetStringEnv("EVENTS_NATS_SUBJECT", c.Events.NATS.Subject)
+	c.Analytics.Enabled = envutil.GetBoolEnv("ANALYTICS_ENABLED", c.Analytics.Enabled)
+	c.Analytics.IntervalSec = envutil.GetIntEnv("ANALYTICS_INTERVAL_SEC", c.Analytics.IntervalSec)
+	c.Analytics.BatchSize = envutil.GetIntEnv("ANALYTICS_BATCH_SIZE", c.Analytics.BatchSize)
+	c.Analytics.Provider = envutil.GetStringEnv("ANALYTICS_PROVIDER", c.Analytics.Provider)
+	c.Analytics.Model = envutil.GetStringEnv("ANALYTICS_MODEL", c.Analytics.Model)
+
+	// Request prioritization
+	c.QoS.MaxConcurrent = envutil.GetIntEnv("QOS_MAX_CONCURRENT", c.QoS.MaxConcurrent)
+	c.QoS.MaxQueue = envutil.GetIntEnv("QOS_MAX_QUEUE", c.QoS.MaxQueue)
+	c.QoS.PressureWindowSec = envutil.GetIntEnv("QOS_PRESSURE_WINDOW_SEC", c.QoS.PressureWindowSec)
+	c.QoS.ProviderHeadroom = envutil.GetBoolEnv("QOS_PROVIDER_HEADROOM", c.QoS.ProviderHeadroom)
+	c.QoS.HeadroomMaxWaitMs = envutil.GetIntEnv("QOS_HEADROOM_MAX_WAIT_MS", c.QoS.HeadroomMaxWaitMs)
+	c.QoS.DedupWindowMs = envutil.GetIntEnv("QOS_DEDUP_WINDOW_MS", c.QoS.DedupWindowMs)
 
 	// Admin HTTP server configuration
 	c.Admin.Enabled = envutil.GetBoolEnv("ADMIN_ENABLED", c.Admin.Enabled)
 	c.Admin.Port = envutil.GetIntEnv("ADMIN_PORT", c.Admin.Port)
+	c.Admin.Docs = envutil.GetBoolEnv("ADMIN_DOCS", c.Admin.Docs)
 
 	// Auth configuration
 	c.Auth.AdminToken = envutil.GetStringEnv("AIRBORNE_ADMIN_TOKEN", c.Auth.AdminToken)
 	c.Auth.AuthMode = envutil.GetStringEnv("AIRBORNE_AUTH_MODE", c.Auth.AuthMode)
+	c.Auth.GeoIPFile = envutil.GetStringEnv("AIRBORNE_GEOIP_FILE", c.Auth.GeoIPFile)
+	c.Auth.InternalSigning.Enabled = envutil.GetBoolEnv("AIRBORNE_INTERNAL_SIGNING_ENABLED", c.Auth.InternalSigning.Enabled)
+	c.Auth.InternalSigning.ActiveKey = envutil.GetStringEnv("AIRBORNE_INTERNAL_SIGNING_ACTIVE_KEY", c.Auth.InternalSigning.ActiveKey)
+	if keys := os.Getenv("AIRBORNE_INTERNAL_SIGNING_KEYS"); keys != "" {
+		// Comma-separated id:secret pairs
+		c.Auth.InternalSigning.Keys = nil
+		for _, pair := range strings.Split(keys, ",") {
+			id, secret, _ := strings.Cut(strings.TrimSpace(pair), ":")
+			c.Auth.InternalSigning.Keys = append(c.Auth.InternalSigning.Keys, SigningKeyConfig{ID: id, Secret: secret})
+		}
+	}
 
 	// Logging configuration
 	c.Logging.Level = envutil.GetStringEnv("AIRBORNE_LOG_LEVEL", c.Logging.Level)
 	c.Logging.Format = envutil.GetStringEnv("AIRBORNE_LOG_FORMAT", c.Logging.Format)
+	if rate := os.Getenv("AIRBORNE_ACCESS_LOG_SAMPLE_RATE"); rate != "" {
+		if parsed, err := strconv.ParseFloat(rate, 64); err == nil {
+			c.Logging.AccessLog.SampleRate = parsed
+		} else {
+			slog.Warn("invalid AIRBORNE_ACCESS_LOG_SAMPLE_RATE, using default", "value", rate)
+		}
+	}
+	c.Logging.AccessLog.SlowThresholdMs = envutil.GetIntEnv("AIRBORNE_ACCESS_LOG_SLOW_MS", c.Logging.AccessLog.SlowThresholdMs)
 
 	// Startup mode
 	if mode := os.Getenv("AIRBORNE_STARTUP_MODE"); mode != "" {
@@ -336,17 +704,61 @@ func (c *Config) applyEnvOverrides() {
 	c.RAG.ChunkSize = envutil.GetIntEnv("RAG_CHUNK_SIZE", c.RAG.ChunkSize)
 	c.RAG.ChunkOverlap = envutil.GetIntEnv("RAG_CHUNK_OVERLAP", c.RAG.ChunkOverlap)
 	c.RAG.RetrievalTopK = envutil.GetIntEnv("RAG_RETRIEVAL_TOP_K", c.RAG.RetrievalTopK)
+	c.RAG.UploadDir = envutil.GetStringEnv("RAG_UPLOAD_DIR", c.RAG.UploadDir)
+	c.RAG.UploadTTLMinutes = envutil.GetIntEnv("RAG_UPLOAD_TTL_MINUTES", c.RAG.UploadTTLMinutes)
 
 	// Markdown service configuration
 	c.MarkdownSvcAddr = envutil.GetStringEnv("MARKDOWN_SVC_ADDR", c.MarkdownSvcAddr)
+
+	// Model catalog configuration (comma-separated deny list replaces the YAML list)
+	if deny := os.Getenv("AIRBORNE_MODEL_DENYLIST"); deny != "" {
+		c.Models.Deny = nil
+		for _, m := range strings.Split(deny, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				c.Models.Deny = append(c.Models.Deny, m)
+			}
+		}
+	}
+
+	// Egress allowlist (comma-separated, replaces the YAML list)
+	if allow := os.Getenv("AIRBORNE_EGRESS_ALLOWLIST"); allow != "" {
+		c.Egress.Allowlist = nil
+		for _, entry := range strings.Split(allow, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				c.Egress.Allowlist = append(c.Egress.Allowlist, entry)
+			}
+		}
+	}
+	c.Egress.Audit.Enabled = envutil.GetBoolEnv("AIRBORNE_EGRESS_AUDIT", c.Egress.Audit.Enabled)
+	c.Egress.Audit.Enforce = envutil.GetBoolEnv("AIRBORNE_EGRESS_ENFORCE", c.Egress.Audit.Enforce)
+	c.Egress.Proxy.URL = envutil.GetStringEnv("AIRBORNE_EGRESS_PROXY_URL", c.Egress.Proxy.URL)
+	c.Egress.Fixtures.Mode = envutil.GetStringEnv("AIRBORNE_HTTP_FIXTURES", c.Egress.Fixtures.Mode)
+	c.Egress.Fixtures.Dir = envutil.GetStringEnv("AIRBORNE_HTTP_FIXTURES_DIR", c.Egress.Fixtures.Dir)
+	if noProxy := os.Getenv("AIRBORNE_EGRESS_NO_PROXY"); noProxy != "" {
+		c.Egress.Proxy.NoProxy = nil
+		for _, entry := range strings.Split(noProxy, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				c.Egress.Proxy.NoProxy = append(c.Egress.Proxy.NoProxy, entry)
+			}
+		}
+	}
 }
 
 // expandEnvVars expands ${VAR} patterns in string fields
 func (c *Config) expandEnvVars() {
 	c.Redis.Password = expandEnv(c.Redis.Password)
+	c.Redis.SentinelPassword = expandEnv(c.Redis.SentinelPassword)
 	c.Database.URL = expandEnv(c.Database.URL)
+	c.Database.ReplicaURL = expandEnv(c.Database.ReplicaURL)
 	c.Database.CACert = expandEnv(c.Database.CACert)
+	c.Events.NATS.URL = expandEnv(c.Events.NATS.URL)
+	for i := range c.Events.Webhooks {
+		c.Events.Webhooks[i].Secret = expandEnv(c.Events.Webhooks[i].Secret)
+	}
 	c.Auth.AdminToken = expandEnv(c.Auth.AdminToken)
+	for i := range c.Auth.InternalSigning.Keys {
+		c.Auth.InternalSigning.Keys[i].Secret = expandEnv(c.Auth.InternalSigning.Keys[i].Secret)
+	}
 	c.TLS.CertFile = expandEnv(c.TLS.CertFile)
 	c.TLS.KeyFile = expandEnv(c.TLS.KeyFile)
 }
@@ -372,16 +784,121 @@ func expandEnv(s string) string {
 
 // validate checks configuration validity
 func (c *Config) validate() error {
+	var errs schema.Errors
+
 	if c.Server.GRPCPort <= 0 || c.Server.GRPCPort > 65535 {
-		return fmt.Errorf("invalid grpc_port: %d", c.Server.GRPCPort)
+		errs.Add("server.grpc_port", "must be between 1 and 65535, got %d", c.Server.GRPCPort)
+	}
+	if c.Admin.Enabled && (c.Admin.Port <= 0 || c.Admin.Port > 65535) {
+		errs.Add("admin.port", "must be between 1 and 65535, got %d", c.Admin.Port)
 	}
 
 	if c.TLS.Enabled {
 		if c.TLS.CertFile == "" {
-			return fmt.Errorf("tls.cert_file required when TLS is enabled")
+			errs.Add("tls.cert_file", "is required when TLS is enabled")
 		}
 		if c.TLS.KeyFile == "" {
-			return fmt.Errorf("tls.key_file required when TLS is enabled")
+			errs.Add("tls.key_file", "is required when TLS is enabled")
+		}
+	}
+
+	if c.Logging.AccessLog.SampleRate < 0 || c.Logging.AccessLog.SampleRate > 1 {
+		errs.Add("logging.access_log.sample_rate", "must be between 0 and 1, got %v", c.Logging.AccessLog.SampleRate)
+	}
+	if c.Logging.AccessLog.SlowThresholdMs < 0 {
+		errs.Add("logging.access_log.slow_threshold_ms", "must not be negative")
+	}
+
+	switch c.Database.Driver {
+	case "", "postgres", "sqlite":
+	default:
+		errs.Add("database.driver", "must be 'postgres' or 'sqlite', got %q", c.Database.Driver)
+	}
+	if c.Database.RollupIntervalSec < 0 {
+		errs.Add("database.rollup_interval_sec", "must not be negative")
+	}
+	if c.Database.Driver == "sqlite" && c.Database.ReplicaURL != "" {
+		errs.Add("database.replica_url", "is not supported with the sqlite driver")
+	}
+
+	if c.Events.Enabled && !c.Database.Enabled {
+		errs.Add("events.enabled", "requires database.enabled (events are written to the database outbox)")
+	}
+	if (len(c.Events.Kafka.Brokers) > 0 || c.Events.NATS.URL != "") && !c.Events.Enabled {
+		errs.Add("events", "kafka and nats require events.enabled")
+	}
+	if len(c.Events.Kafka.Brokers) > 0 && c.Events.Kafka.Topic == "" {
+		errs.Add("events.kafka.topic", "is required when brokers are set")
+	}
+	if c.Events.NATS.URL != "" && c.Events.NATS.Subject == "" {
+		errs.Add("events.nats.subject", "is required when url is set")
+	}
+	for i, wh := range c.Events.Webhooks {
+		if !strings.HasPrefix(wh.URL, "https://") && !strings.HasPrefix(wh.URL, "http://") {
+			errs.Add(fmt.Sprintf("events.webhooks[%d].url", i), "must be an http(s) URL")
+		}
+	}
+
+	if c.Analytics.Enabled && !c.Database.Enabled {
+		errs.Add("analytics.enabled", "requires database.enabled (stored conversations are analyzed)")
+	}
+	if c.Analytics.IntervalSec < 0 {
+		errs.Add("analytics.interval_sec", "must not be negative")
+	}
+	if c.Analytics.BatchSize < 0 {
+		errs.Add("analytics.batch_size", "must not be negative")
+	}
+	switch c.Analytics.Provider {
+	case "", "openai", "gemini", "anthropic":
+	default:
+		errs.Add("analytics.provider", "must be 'openai', 'gemini' or 'anthropic', got %q", c.Analytics.Provider)
+	}
+
+	errs.Wrap("redis", c.Redis.ClientConfig().Validate())
+	errs.Wrap("redis.fallback.rate_limit", redis.ValidatePolicy(c.Redis.Fallback.RateLimit))
+	errs.Wrap("redis.fallback.idempotency", redis.ValidatePolicy(c.Redis.Fallback.Idempotency))
+
+	if _, err := validation.ParseURLAllowlist(c.Egress.Allowlist); err != nil {
+		errs.Wrap("egress.allowlist", err)
+	}
+	errs.Wrap("egress.proxy", c.Egress.Proxy.Validate())
+	errs.Wrap("egress.audit", c.Egress.Audit.Validate())
+	errs.Wrap("egress.fixtures", c.Egress.Fixtures.Validate())
+	if c.Egress.Fixtures.Mode != httpcapture.FixtureModeOff && c.StartupMode.IsProduction() {
+		errs.Add("egress.fixtures.mode", "fixtures are not allowed in production startup mode")
+	}
+	errs.Wrap("auth.internal_signing", c.Auth.InternalSigning.Validate())
+	errs.Wrap("admin.network", c.Admin.Network.Validate())
+	if len(c.Admin.Network.AllowedCountries) > 0 && c.Auth.GeoIPFile == "" {
+		errs.Add("auth.geoip_file", "is required when admin.network.allowed_countries is set")
+	}
+	if len(c.Auth.InternalSigning.Network.AllowedCountries) > 0 && c.Auth.GeoIPFile == "" {
+		errs.Add("auth.geoip_file", "is required when auth.internal_signing.network.allowed_countries is set")
+	}
+
+	for name, v := range map[string]int{
+		"qos.max_concurrent":               c.QoS.MaxConcurrent,
+		"qos.max_queue":                    c.QoS.MaxQueue,
+		"qos.pressure_window_sec":          c.QoS.PressureWindowSec,
+		"qos.headroom_max_wait_ms":         c.QoS.HeadroomMaxWaitMs,
+		"qos.dedup_window_ms":              c.QoS.DedupWindowMs,
+		"rag.upload_ttl_minutes":           c.RAG.UploadTTLMinutes,
+		"mock_providers.latency_ms":        c.MockProviders.LatencyMs,
+		"mock_providers.chunk_interval_ms": c.MockProviders.ChunkIntervalMs,
+	} {
+		if v < 0 {
+			errs.Add(name, "must not be negative")
+		}
+	}
+
+	errs.Wrap("chaos", c.Chaos.Validate())
+	if c.MockProviders.Enabled {
+		if c.StartupMode.IsProduction() {
+			errs.Add("mock_providers.enabled", "mock providers are not allowed in production startup mode")
+		}
+		errs.Wrap("mock_providers", c.MockProviders.ProviderConfig("").Validate())
+		for name, script := range c.MockProviders.Scripts {
+			errs.Wrap("mock_providers.scripts."+name, mock.Config{Script: script}.Validate())
 		}
 	}
 
@@ -391,10 +908,11 @@ func (c *Config) validate() error {
 		// Valid modes
 	default:
 		// Fatal error - do not allow invalid startup modes
-		return fmt.Errorf("invalid startup_mode %q, must be 'production' or 'development'", c.StartupMode)
+		errs.Add("startup_mode", "must be 'production' or 'development', got %q", c.StartupMode)
 	}
 
-	return nil
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs.Err()
 }
 
 // fetchDopplerSecret fetches a single secret from Doppler.
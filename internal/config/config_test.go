@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad_DefaultValues(t *testing.T) {
@@ -172,6 +174,380 @@ func TestLoad_LogLevelEnvOverride(t *testing.T) {
 	}
 }
 
+func TestLoad_ModelDenylistEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_MODEL_DENYLIST", " gpt-4-32k, o1-pro* ,")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if len(cfg.Models.Deny) != 2 || cfg.Models.Deny[0] != "gpt-4-32k" || cfg.Models.Deny[1] != "o1-pro*" {
+		t.Errorf("expected Models.Deny [gpt-4-32k o1-pro*] from env, got %v", cfg.Models.Deny)
+	}
+}
+
+func TestLoad_RedisClusterEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("REDIS_MODE", "cluster")
+	t.Setenv("REDIS_ADDRS", "redis-1:6379, redis-2:6379")
+	t.Setenv("REDIS_TLS_ENABLED", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	rc := cfg.Redis.ClientConfig()
+	if rc.Mode != "cluster" || len(rc.Addrs) != 2 || rc.Addrs[1] != "redis-2:6379" {
+		t.Errorf("expected cluster mode with 2 seed addrs from env, got mode %q addrs %v", rc.Mode, rc.Addrs)
+	}
+	if !rc.TLS.Enabled {
+		t.Error("expected Redis TLS enabled from env")
+	}
+}
+
+func TestLoad_RedisSentinelWithoutMaster_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("REDIS_MODE", "sentinel")
+	t.Setenv("REDIS_ADDRS", "sentinel-1:26379")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for sentinel mode without master_name")
+	}
+}
+
+func TestLoad_RedisFallbackPolicies(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("REDIS_FALLBACK_IDEMPOTENCY", "fail_closed")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Redis.Fallback.RateLimit != "memory" {
+		t.Errorf("expected rate_limit fallback to default to memory, got %q", cfg.Redis.Fallback.RateLimit)
+	}
+	if cfg.Redis.Fallback.Idempotency != "fail_closed" {
+		t.Errorf("expected idempotency fallback fail_closed from env, got %q", cfg.Redis.Fallback.Idempotency)
+	}
+}
+
+func TestLoad_RedisFallbackInvalidPolicy_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("REDIS_FALLBACK_RATE_LIMIT", "fail_open")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown redis fallback policy")
+	}
+}
+
+func TestLoad_UnknownKeyReportsPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "airborne.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  grpc_prot: 50051\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("AIRBORNE_CONFIG", path)
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+	if !strings.Contains(err.Error(), `server.grpc_prot: unknown field (did you mean "grpc_port"?) (line 2)`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoad_ReportsAllValidationErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "airborne.yaml")
+	doc := "server:\n  grpc_port: 70000\nlogging:\n  access_log:\n    sample_rate: 2\n"
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("AIRBORNE_CONFIG", path)
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{
+		"logging.access_log.sample_rate: must be between 0 and 1, got 2",
+		"server.grpc_port: must be between 1 and 65535, got 70000",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestLoad_EgressAllowlistEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_EGRESS_ALLOWLIST", "*.corp.internal, 10.20.0.0/16")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if len(cfg.Egress.Allowlist) != 2 || cfg.Egress.Allowlist[0] != "*.corp.internal" || cfg.Egress.Allowlist[1] != "10.20.0.0/16" {
+		t.Errorf("expected Egress.Allowlist [*.corp.internal 10.20.0.0/16] from env, got %v", cfg.Egress.Allowlist)
+	}
+}
+
+func TestLoad_EgressProxyEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_EGRESS_PROXY_URL", "socks5://proxy.corp.internal:1080")
+	t.Setenv("AIRBORNE_EGRESS_NO_PROXY", ".corp.internal, 10.0.0.0/8")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Egress.Proxy.URL != "socks5://proxy.corp.internal:1080" {
+		t.Errorf("expected Egress.Proxy.URL from env, got %q", cfg.Egress.Proxy.URL)
+	}
+	if len(cfg.Egress.Proxy.NoProxy) != 2 || cfg.Egress.Proxy.NoProxy[1] != "10.0.0.0/8" {
+		t.Errorf("expected Egress.Proxy.NoProxy [.corp.internal 10.0.0.0/8] from env, got %v", cfg.Egress.Proxy.NoProxy)
+	}
+}
+
+func TestLoad_EgressEnforceWithoutAllowlist_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_EGRESS_AUDIT", "true")
+	t.Setenv("AIRBORNE_EGRESS_ENFORCE", "true")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for egress enforcement without allowed_hosts")
+	}
+}
+
+func TestLoad_InvalidEgressProxy_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_EGRESS_PROXY_URL", "ftp://proxy.corp.internal")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unsupported egress proxy scheme")
+	}
+}
+
+func TestLoad_InvalidEgressAllowlist_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_EGRESS_ALLOWLIST", "10.0.0.0/40")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid egress allowlist CIDR")
+	}
+}
+
+func TestLoad_AccessLogEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_ACCESS_LOG_SAMPLE_RATE", "0.25")
+	t.Setenv("AIRBORNE_ACCESS_LOG_SLOW_MS", "1500")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Logging.AccessLog.SampleRate != 0.25 {
+		t.Errorf("expected AccessLog.SampleRate 0.25 from env, got %v", cfg.Logging.AccessLog.SampleRate)
+	}
+	if cfg.Logging.AccessLog.SlowThresholdMs != 1500 {
+		t.Errorf("expected AccessLog.SlowThresholdMs 1500 from env, got %d", cfg.Logging.AccessLog.SlowThresholdMs)
+	}
+}
+
+func TestLoad_AccessLogSampleRateOutOfRange_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_ACCESS_LOG_SAMPLE_RATE", "1.5")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for sample_rate above 1")
+	}
+}
+
+func TestLoad_DatabaseDriverEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_URL", filepath.Join(dir, "airborne.db"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Database.Driver != "sqlite" {
+		t.Errorf("expected Database.Driver sqlite from env, got %q", cfg.Database.Driver)
+	}
+}
+
+func TestLoad_InvalidDatabaseDriver_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_DRIVER", "mysql")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unsupported database driver")
+	}
+}
+
+func TestLoad_DatabaseReplicaURLEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_REPLICA_URL", "postgres://replica:5432/airborne")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Database.ReplicaURL != "postgres://replica:5432/airborne" {
+		t.Errorf("expected Database.ReplicaURL from env, got %q", cfg.Database.ReplicaURL)
+	}
+}
+
+func TestLoad_SQLiteWithReplica_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_REPLICA_URL", "postgres://replica:5432/airborne")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for sqlite with a read replica")
+	}
+}
+
+func TestLoad_RollupIntervalEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_ROLLUP_INTERVAL_SEC", "60")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Database.RollupIntervalSec != 60 {
+		t.Errorf("expected Database.RollupIntervalSec 60 from env, got %d", cfg.Database.RollupIntervalSec)
+	}
+}
+
+func TestLoad_EventsEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_ENABLED", "true")
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_URL", filepath.Join(dir, "airborne.db"))
+	t.Setenv("EVENTS_ENABLED", "true")
+	t.Setenv("EVENTS_WEBHOOK_URL", "https://hooks.example.com/airborne")
+	t.Setenv("EVENTS_WEBHOOK_SECRET", "s3cret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if !cfg.Events.Enabled {
+		t.Error("expected Events.Enabled from env")
+	}
+	if len(cfg.Events.Webhooks) != 1 || cfg.Events.Webhooks[0].URL != "https://hooks.example.com/airborne" || cfg.Events.Webhooks[0].Secret != "s3cret" {
+		t.Errorf("expected webhook from env, got %+v", cfg.Events.Webhooks)
+	}
+	if cfg.Events.MaxAttempts != 10 {
+		t.Errorf("expected default Events.MaxAttempts 10, got %d", cfg.Events.MaxAttempts)
+	}
+}
+
+func TestLoad_EventStreamEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("DATABASE_ENABLED", "true")
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_URL", filepath.Join(dir, "airborne.db"))
+	t.Setenv("EVENTS_ENABLED", "true")
+	t.Setenv("EVENTS_KAFKA_BROKERS", "kafka-1:9092, kafka-2:9092")
+	t.Setenv("EVENTS_NATS_URL", "nats://nats:4222")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if len(cfg.Events.Kafka.Brokers) != 2 || cfg.Events.Kafka.Brokers[1] != "kafka-2:9092" {
+		t.Errorf("expected two Kafka brokers from env, got %v", cfg.Events.Kafka.Brokers)
+	}
+	if cfg.Events.Kafka.Topic != "airborne.generations" {
+		t.Errorf("expected default Kafka topic, got %q", cfg.Events.Kafka.Topic)
+	}
+	if cfg.Events.NATS.URL != "nats://nats:4222" || cfg.Events.NATS.Subject != "airborne.generations" {
+		t.Errorf("unexpected NATS config: %+v", cfg.Events.NATS)
+	}
+}
+
+func TestLoad_EventStreamWithoutEvents_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("EVENTS_KAFKA_BROKERS", "kafka-1:9092")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for Kafka without events enabled")
+	}
+}
+
+func TestLoad_EventsWithoutDatabase_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("EVENTS_ENABLED", "true")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for events without a database")
+	}
+}
+
+func TestLoad_QoSEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("QOS_MAX_CONCURRENT", "32")
+	t.Setenv("QOS_DEDUP_WINDOW_MS", "1500")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.QoS.MaxConcurrent != 32 {
+		t.Errorf("expected QoS.MaxConcurrent 32 from env, got %d", cfg.QoS.MaxConcurrent)
+	}
+	if cfg.QoS.DedupWindowMs != 1500 {
+		t.Errorf("expected QoS.DedupWindowMs 1500 from env, got %d", cfg.QoS.DedupWindowMs)
+	}
+	if cfg.QoS.MaxQueue != 100 || cfg.QoS.PressureWindowSec != 30 || cfg.QoS.HeadroomMaxWaitMs != 2000 {
+		t.Errorf("expected QoS defaults, got %+v", cfg.QoS)
+	}
+
+	t.Setenv("QOS_MAX_QUEUE", "-1")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for negative qos.max_queue")
+	}
+}
+
 func TestLoad_MissingConfigFile_UsesDefaults(t *testing.T) {
 	dir := t.TempDir()
 	nonexistentPath := filepath.Join(dir, "does_not_exist.yaml")
@@ -300,6 +676,31 @@ func TestLoad_TLSValidation_EnabledWithoutKey(t *testing.T) {
 	}
 }
 
+func TestLoad_InternalSigning(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_ADMIN_TOKEN", "admin-token")
+	t.Setenv("AIRBORNE_INTERNAL_SIGNING_ENABLED", "true")
+	t.Setenv("AIRBORNE_INTERNAL_SIGNING_ACTIVE_KEY", "k2")
+	t.Setenv("AIRBORNE_INTERNAL_SIGNING_KEYS", "k1:"+strings.Repeat("a", 32)+", k2:"+strings.Repeat("b", 32))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Auth.InternalSigning.Keys) != 2 || cfg.Auth.InternalSigning.Keys[1].ID != "k2" {
+		t.Fatalf("unexpected signing keys: %+v", cfg.Auth.InternalSigning.Keys)
+	}
+	if key, ok := cfg.Auth.InternalSigning.SigningKey(); !ok || key.Secret != strings.Repeat("b", 32) {
+		t.Errorf("unexpected active key: %+v", key)
+	}
+
+	t.Setenv("AIRBORNE_INTERNAL_SIGNING_ACTIVE_KEY", "k3")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "auth.internal_signing") {
+		t.Errorf("expected validation error for unknown active key, got %v", err)
+	}
+}
+
 func TestLoad_InvalidPort(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
@@ -499,3 +900,61 @@ func TestLoad_GRPCPortEnvOverride_InvalidValue(t *testing.T) {
 		t.Errorf("expected default port 50051 for invalid env, got %d", cfg.Server.GRPCPort)
 	}
 }
+
+func TestLoad_MockProviders(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "airborne.yaml")
+	doc := "mock_providers:\n  enabled: true\n  latency_ms: 5\n  script: [\"429\", \"ok\"]\n  scripts:\n    gemini: [\"bogus\"]\n"
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("AIRBORNE_CONFIG", path)
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{
+		"mock_providers.enabled: mock providers are not allowed in production startup mode",
+		`mock_providers.scripts.gemini: unknown outcome "bogus"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	doc = "startup_mode: development\nmock_providers:\n  enabled: true\n  latency_ms: 5\n  script: [\"429\", \"ok\"]\n  scripts:\n    gemini: [\"timeout\"]\n"
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := cfg.MockProviders.ProviderConfig("openai"); got.Latency != 5*time.Millisecond || len(got.Script) != 2 {
+		t.Errorf("openai mock config = %+v", got)
+	}
+	if got := cfg.MockProviders.ProviderConfig("gemini"); len(got.Script) != 1 || got.Script[0] != "timeout" {
+		t.Errorf("gemini mock config = %+v", got)
+	}
+}
+
+func TestLoad_FixturesEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+	t.Setenv("AIRBORNE_HTTP_FIXTURES", "replay")
+	t.Setenv("AIRBORNE_HTTP_FIXTURES_DIR", dir)
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "egress.fixtures.mode: fixtures are not allowed in production startup mode") {
+		t.Fatalf("expected production refusal, got %v", err)
+	}
+
+	t.Setenv("AIRBORNE_STARTUP_MODE", "development")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Egress.Fixtures.Mode != "replay" || cfg.Egress.Fixtures.Dir != dir {
+		t.Errorf("fixtures = %+v, want replay from %s", cfg.Egress.Fixtures, dir)
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ai8future/airborne/internal/config/schema"
+)
+
+// localOverlay names the untracked per-machine override layer.
+const localOverlay = "local"
+
+// envNamePattern restricts environment names to safe file name components.
+var envNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// overlayPath returns the overlay file for name next to the base config,
+// e.g. configs/airborne.yaml -> configs/airborne.staging.yaml.
+func overlayPath(base, name string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + name + ext
+}
+
+// loadLayers merges the base config, the environment overlay and the local
+// override into cfg, in increasing precedence. Mappings merge key by key;
+// scalars and lists in a later layer replace earlier ones. It returns the
+// files that were applied.
+//
+// A missing base or local file is skipped. A missing overlay for a named
+// environment is an error, so a typo in AIRBORNE_ENV cannot silently run
+// production with base settings.
+func loadLayers(cfg *Config, base, env string) ([]string, error) {
+	type layer struct {
+		path     string
+		required bool
+	}
+	layers := []layer{{path: base}}
+	if env != "" {
+		if !envNamePattern.MatchString(env) || env == localOverlay {
+			return nil, fmt.Errorf("invalid AIRBORNE_ENV %q: must be lowercase letters, digits, '-' or '_' and not %q", env, localOverlay)
+		}
+		layers = append(layers, layer{path: overlayPath(base, env), required: true})
+	}
+	layers = append(layers, layer{path: overlayPath(base, localOverlay)})
+
+	var merged *yaml.Node
+	var sources []string
+	for _, l := range layers {
+		data, err := os.ReadFile(l.path)
+		if err != nil {
+			if os.IsNotExist(err) && !l.required {
+				continue
+			}
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("config overlay for environment %q not found: %s", env, l.path)
+			}
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		// Check each layer on its own so errors point at the right file and line
+		if err := schema.CheckYAML(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration in %s:\n%w", l.path, err)
+		}
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", l.path, err)
+		}
+		sources = append(sources, l.path)
+		if len(doc.Content) == 0 {
+			continue // Empty file
+		}
+		if merged == nil {
+			merged = doc.Content[0]
+		} else {
+			merged = mergeNodes(merged, doc.Content[0])
+		}
+	}
+
+	if merged != nil {
+		if err := merged.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+	return sources, nil
+}
+
+// mergeNodes overlays src onto dst. Mappings are merged recursively; any
+// other node in src replaces its counterpart in dst.
+func mergeNodes(dst, src *yaml.Node) *yaml.Node {
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return src
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		replaced := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == key.Value {
+				dst.Content[j+1] = mergeNodes(dst.Content[j+1], val)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			dst.Content = append(dst.Content, key, val)
+		}
+	}
+	return dst
+}
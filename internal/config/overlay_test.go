@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoad_EnvironmentOverlay(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "airborne.yaml")
+	writeConfigFile(t, base, `
+server:
+  grpc_port: 50051
+  host: "0.0.0.0"
+logging:
+  level: info
+failover:
+  default_order: [openai, gemini]
+providers:
+  openai:
+    enabled: true
+    default_model: gpt-4o
+`)
+	writeConfigFile(t, filepath.Join(dir, "airborne.staging.yaml"), `
+server:
+  grpc_port: 6000
+failover:
+  default_order: [gemini]
+providers:
+  openai:
+    default_model: gpt-4o-mini
+`)
+	writeConfigFile(t, filepath.Join(dir, "airborne.local.yaml"), `
+logging:
+  level: debug
+`)
+	t.Setenv("AIRBORNE_CONFIG", base)
+	t.Setenv("AIRBORNE_ENV", "staging")
+	t.Setenv("AIRBORNE_LOG_FORMAT", "text")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Server.GRPCPort != 6000 || cfg.Server.Host != "0.0.0.0" {
+		t.Errorf("expected overlay port with base host, got %d %q", cfg.Server.GRPCPort, cfg.Server.Host)
+	}
+	if got := cfg.Failover.DefaultOrder; len(got) != 1 || got[0] != "gemini" {
+		t.Errorf("expected overlay to replace lists, got %v", got)
+	}
+	if p := cfg.Providers["openai"]; !p.Enabled || p.DefaultModel != "gpt-4o-mini" {
+		t.Errorf("expected nested merge of provider settings, got %+v", p)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("expected local override to win over base, got %q", cfg.Logging.Level)
+	}
+	if cfg.Logging.Format != "text" {
+		t.Errorf("expected environment variable to win over files, got %q", cfg.Logging.Format)
+	}
+
+	want := []string{base, filepath.Join(dir, "airborne.staging.yaml"), filepath.Join(dir, "airborne.local.yaml")}
+	if cfg.Environment != "staging" || strings.Join(cfg.Sources, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected provenance: env %q sources %v", cfg.Environment, cfg.Sources)
+	}
+}
+
+func TestLoad_MissingEnvironmentOverlay_Fails(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "airborne.yaml")
+	writeConfigFile(t, base, "server:\n  grpc_port: 50051\n")
+	t.Setenv("AIRBORNE_CONFIG", base)
+	t.Setenv("AIRBORNE_ENV", "prod")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "airborne.prod.yaml") {
+		t.Fatalf("expected missing overlay error, got %v", err)
+	}
+}
+
+func TestLoad_InvalidEnvironmentName_Fails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIRBORNE_CONFIG", filepath.Join(dir, "nonexistent.yaml"))
+
+	for _, env := range []string{"../prod", "Staging", "local"} {
+		t.Setenv("AIRBORNE_ENV", env)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for AIRBORNE_ENV=%q", env)
+		}
+	}
+}
+
+func TestLoad_OverlayErrorNamesFile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "airborne.yaml")
+	writeConfigFile(t, base, "server:\n  grpc_port: 50051\n")
+	writeConfigFile(t, filepath.Join(dir, "airborne.local.yaml"), "server:\n  grpc_port: high\n")
+	t.Setenv("AIRBORNE_CONFIG", base)
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for invalid local override")
+	}
+	if !strings.Contains(err.Error(), "airborne.local.yaml") || !strings.Contains(err.Error(), "server.grpc_port: expected an integer") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces secrets in displayed configuration.
+const redactedValue = "REDACTED"
+
+// Redacted returns a copy of the config that is safe to display: passwords,
+// tokens and webhook secrets are masked, and credentials embedded in URLs
+// are masked while the rest of the URL is kept. ENV= and FILE= references
+// and ${VAR} placeholders are left as they are since they are not secrets.
+func (c *Config) Redacted() *Config {
+	cp := *c
+
+	cp.Redis.Password = redactSecret(c.Redis.Password)
+	cp.Redis.SentinelPassword = redactSecret(c.Redis.SentinelPassword)
+	cp.Auth.AdminToken = redactSecret(c.Auth.AdminToken)
+	cp.Auth.InternalSigning.Keys = make([]SigningKeyConfig, len(c.Auth.InternalSigning.Keys))
+	for i, k := range c.Auth.InternalSigning.Keys {
+		k.Secret = redactSecret(k.Secret)
+		cp.Auth.InternalSigning.Keys[i] = k
+	}
+	cp.Database.URL = redactURL(c.Database.URL)
+	cp.Database.ReplicaURL = redactURL(c.Database.ReplicaURL)
+	cp.Events.NATS.URL = redactURL(c.Events.NATS.URL)
+	cp.Egress.Proxy.URL = redactURL(c.Egress.Proxy.URL)
+
+	cp.Events.Webhooks = make([]WebhookConfig, len(c.Events.Webhooks))
+	for i, wh := range c.Events.Webhooks {
+		wh.URL = redactURL(wh.URL)
+		wh.Secret = redactSecret(wh.Secret)
+		cp.Events.Webhooks[i] = wh
+	}
+	return &cp
+}
+
+// isReference reports whether v points at a secret rather than holding one.
+func isReference(v string) bool {
+	return strings.HasPrefix(v, "ENV=") || strings.HasPrefix(v, "FILE=") || strings.HasPrefix(v, "${")
+}
+
+func redactSecret(v string) string {
+	if v == "" || isReference(v) {
+		return v
+	}
+	return redactedValue
+}
+
+// redactURL masks the password in a URL's userinfo and any query parameter
+// that looks like a credential. Unparseable values are masked entirely.
+func redactURL(v string) string {
+	if v == "" || isReference(v) {
+		return v
+	}
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme == "" && u.Host == "") {
+		if strings.Contains(v, "://") || strings.Contains(v, "@") {
+			return redactedValue
+		}
+		return v // A plain path, such as a SQLite database file
+	}
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), redactedValue)
+		}
+	}
+	if q := u.Query(); len(q) > 0 {
+		changed := false
+		for key := range q {
+			lk := strings.ToLower(key)
+			if strings.Contains(lk, "password") || strings.Contains(lk, "secret") || strings.Contains(lk, "token") || lk == "key" {
+				q.Set(key, redactedValue)
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = q.Encode()
+		}
+	}
+	return u.String()
+}
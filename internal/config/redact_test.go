@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestRedacted(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Redis.Password = "hunter2"
+	cfg.Redis.SentinelPassword = "ENV=REDIS_SENTINEL_PASSWORD"
+	cfg.Auth.AdminToken = "admin-token"
+	cfg.Database.URL = "postgres://airborne:s3cret@db:5432/airborne?sslmode=require"
+	cfg.Database.ReplicaURL = "/var/lib/airborne/airborne.db"
+	cfg.Egress.Proxy.URL = "http://proxy.corp:3128"
+	cfg.Events.Webhooks = []WebhookConfig{{URL: "https://hooks.example.com/x?token=abc", Secret: "whsec"}}
+	cfg.Auth.InternalSigning.Keys = []SigningKeyConfig{{ID: "k1", Secret: "signing-secret"}}
+
+	red := cfg.Redacted()
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"redis password", red.Redis.Password, "REDACTED"},
+		{"secret reference kept", red.Redis.SentinelPassword, "ENV=REDIS_SENTINEL_PASSWORD"},
+		{"admin token", red.Auth.AdminToken, "REDACTED"},
+		{"database url", red.Database.URL, "postgres://airborne:REDACTED@db:5432/airborne?sslmode=require"},
+		{"file path kept", red.Database.ReplicaURL, "/var/lib/airborne/airborne.db"},
+		{"url without credentials kept", red.Egress.Proxy.URL, "http://proxy.corp:3128"},
+		{"webhook url", red.Events.Webhooks[0].URL, "https://hooks.example.com/x?token=REDACTED"},
+		{"webhook secret", red.Events.Webhooks[0].Secret, "REDACTED"},
+		{"signing key id kept", red.Auth.InternalSigning.Keys[0].ID, "k1"},
+		{"signing secret", red.Auth.InternalSigning.Keys[0].Secret, "REDACTED"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if cfg.Redis.Password != "hunter2" || cfg.Events.Webhooks[0].Secret != "whsec" || cfg.Auth.InternalSigning.Keys[0].Secret != "signing-secret" {
+		t.Error("Redacted must not modify the original config")
+	}
+}
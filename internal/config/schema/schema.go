@@ -0,0 +1,362 @@
+// Package schema checks raw configuration documents against the Go types
+// they decode into, reporting problems by dotted path (e.g.
+// "redis.fallback.rate_limit") instead of by decoder internals.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError is a problem with the value at Path.
+type FieldError struct {
+	Path string
+	Line int // Source line, 0 when unknown
+	Msg  string
+}
+
+func (e *FieldError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s: %s (line %d)", path, e.Msg, e.Line)
+	}
+	return fmt.Sprintf("%s: %s", path, e.Msg)
+}
+
+// Errors collects every problem found in a document.
+type Errors []*FieldError
+
+// Add records a problem at path.
+func (e *Errors) Add(path, format string, args ...any) {
+	*e = append(*e, &FieldError{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// Wrap records err at path. A FieldError or Errors from a nested check is
+// re-rooted under path; any other error becomes the message.
+func (e *Errors) Wrap(path string, err error) {
+	if err == nil {
+		return
+	}
+	switch v := Prefix(path, err).(type) {
+	case Errors:
+		*e = append(*e, v...)
+	case *FieldError:
+		*e = append(*e, v)
+	default:
+		e.Add(path, "%v", err)
+	}
+}
+
+// Err returns the collected errors, or nil when there are none.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, fe := range e {
+		lines[i] = fe.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Prefix roots the paths of a FieldError or Errors under prefix. Other
+// errors are returned unchanged.
+func Prefix(prefix string, err error) error {
+	switch v := err.(type) {
+	case *FieldError:
+		cp := *v
+		cp.Path = join(prefix, v.Path)
+		return &cp
+	case Errors:
+		out := make(Errors, len(v))
+		for i, fe := range v {
+			cp := *fe
+			cp.Path = join(prefix, fe.Path)
+			out[i] = &cp
+		}
+		return out
+	default:
+		return err
+	}
+}
+
+// CheckYAML reports unknown keys and type mismatches in a YAML document
+// destined for v, using yaml struct tags. Syntax errors are returned as-is.
+func CheckYAML(data []byte, v any) error {
+	return check(data, reflect.TypeOf(v), "yaml")
+}
+
+// CheckJSON is CheckYAML for JSON documents, using json struct tags. Keys
+// match case-insensitively, as they do in encoding/json.
+func CheckJSON(data []byte, v any) error {
+	return check(data, reflect.TypeOf(v), "json")
+}
+
+func check(data []byte, t reflect.Type, tagKey string) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return nil // Empty document
+	}
+	w := walker{tagKey: tagKey}
+	w.walk(doc.Content[0], t, "")
+	return w.errs.Err()
+}
+
+type walker struct {
+	tagKey string
+	errs   Errors
+}
+
+func (w *walker) fail(n *yaml.Node, path, format string, args ...any) {
+	w.errs = append(w.errs, &FieldError{Path: path, Line: n.Line, Msg: fmt.Sprintf(format, args...)})
+}
+
+var (
+	yamlUnmarshaler = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// customDecoded reports whether t decodes itself, in which case its shape
+// is not checked.
+func customDecoded(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return pt.Implements(yamlUnmarshaler) || pt.Implements(jsonUnmarshaler) || pt.Implements(textUnmarshaler)
+}
+
+func (w *walker) walk(n *yaml.Node, t reflect.Type, path string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+	if customDecoded(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			w.fail(n, path, "expected an object, got %s", describe(n))
+			return
+		}
+		fields := structFields(t, w.tagKey)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				w.walk(val, t, path) // YAML merge key
+				continue
+			}
+			f, ok := fields.lookup(key.Value, w.tagKey == "json")
+			if !ok {
+				w.fail(key, join(path, key.Value), "unknown field%s", suggest(key.Value, fields))
+				continue
+			}
+			w.walk(val, f.typ, join(path, key.Value))
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			w.fail(n, path, "expected an object, got %s", describe(n))
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			w.walk(n.Content[i+1], t.Elem(), join(path, n.Content[i].Value))
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && n.Kind == yaml.ScalarNode {
+			return // []byte accepts a base64 string
+		}
+		if n.Kind != yaml.SequenceNode {
+			w.fail(n, path, "expected a list, got %s", describe(n))
+			return
+		}
+		for i, item := range n.Content {
+			w.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.String:
+		// YAML turns any scalar into a string; JSON only accepts strings
+		if n.Kind != yaml.ScalarNode || (w.tagKey == "json" && n.Tag != "!!str") {
+			w.fail(n, path, "expected a string, got %s", describe(n))
+		}
+	case reflect.Bool:
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!bool" {
+			w.fail(n, path, "expected true or false, got %s", describe(n))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			w.fail(n, path, "expected an integer, got %s", describe(n))
+			return
+		}
+		v, err := strconv.ParseInt(strings.ReplaceAll(n.Value, "_", ""), 0, 64)
+		if err != nil || reflect.Zero(t).OverflowInt(v) {
+			w.fail(n, path, "integer %s is out of range", n.Value)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			w.fail(n, path, "expected a non-negative integer, got %s", describe(n))
+			return
+		}
+		v, err := strconv.ParseUint(strings.ReplaceAll(n.Value, "_", ""), 0, 64)
+		if err != nil || reflect.Zero(t).OverflowUint(v) {
+			w.fail(n, path, "must be a non-negative integer in range, got %s", n.Value)
+		}
+	case reflect.Float32, reflect.Float64:
+		if n.Kind != yaml.ScalarNode || (n.Tag != "!!float" && n.Tag != "!!int") {
+			w.fail(n, path, "expected a number, got %s", describe(n))
+			return
+		}
+		if v, err := strconv.ParseFloat(n.Value, 64); err == nil && (math.IsNaN(v) || math.IsInf(v, 0)) {
+			w.fail(n, path, "must be a finite number")
+		}
+	}
+}
+
+// describe names a node's kind for error messages.
+func describe(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch n.Tag {
+	case "!!str":
+		return strconv.Quote(n.Value)
+	case "!!int":
+		return "integer " + n.Value
+	case "!!float":
+		return "number " + n.Value
+	case "!!bool":
+		return "boolean " + n.Value
+	}
+	return n.Value
+}
+
+type field struct {
+	name string
+	typ  reflect.Type
+}
+
+type fieldSet []field
+
+func (fs fieldSet) lookup(name string, foldCase bool) (field, bool) {
+	for _, f := range fs {
+		if f.name == name {
+			return f, true
+		}
+	}
+	if foldCase {
+		for _, f := range fs {
+			if strings.EqualFold(f.name, name) {
+				return f, true
+			}
+		}
+	}
+	return field{}, false
+}
+
+// structFields lists the keys a struct accepts under tagKey, flattening
+// inlined (yaml) and embedded (json) structs the way the decoders do.
+func structFields(t reflect.Type, tagKey string) fieldSet {
+	var out fieldSet
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(tagKey)
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		inline := strings.Contains(","+opts+",", ",inline,") ||
+			(tagKey == "json" && sf.Anonymous && name == "" && ft.Kind() == reflect.Struct)
+		if inline && ft.Kind() == reflect.Struct {
+			out = append(out, structFields(ft, tagKey)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+			if tagKey == "yaml" {
+				name = strings.ToLower(name)
+			}
+		}
+		out = append(out, field{name: name, typ: sf.Type})
+	}
+	return out
+}
+
+// suggest offers the closest known key for a likely typo.
+func suggest(key string, fields fieldSet) string {
+	best, bestDist := "", 3 // Only suggest within two edits
+	for _, f := range fields {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(f.name)); d < bestDist {
+			best, bestDist = f.name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func join(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case strings.HasPrefix(path, "["):
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
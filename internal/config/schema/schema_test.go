@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	Server struct {
+		Port    int     `yaml:"port" json:"port"`
+		Enabled bool    `yaml:"enabled" json:"enabled"`
+		Ratio   float64 `yaml:"ratio" json:"ratio"`
+	} `yaml:"server" json:"server"`
+	Tags      []string `yaml:"tags" json:"tags"`
+	Providers map[string]struct {
+		Model string `yaml:"model" json:"model"`
+	} `yaml:"providers" json:"providers"`
+	Extra map[string]any `yaml:"extra" json:"extra"`
+	Small int8           `yaml:"small" json:"small"`
+}
+
+func TestCheckYAML_Valid(t *testing.T) {
+	doc := `
+server:
+  port: 8080
+  enabled: true
+  ratio: 1
+tags: [a, b]
+providers:
+  openai:
+    model: gpt-4o
+extra:
+  anything: [1, {nested: true}]
+`
+	if err := CheckYAML([]byte(doc), &testConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckYAML(nil, &testConfig{}); err != nil {
+		t.Fatalf("empty document: %v", err)
+	}
+}
+
+func TestCheckYAML_Errors(t *testing.T) {
+	doc := `
+server:
+  prot: 8080
+  enabled: "yes"
+  ratio: high
+tags: a
+providers:
+  openai:
+    model: gpt-4o
+    temperature: 1
+small: 300
+`
+	err := CheckYAML([]byte(doc), &testConfig{})
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %T: %v", err, err)
+	}
+
+	want := []string{
+		`server.prot: unknown field (did you mean "port"?) (line 3)`,
+		`server.enabled: expected true or false, got "yes" (line 4)`,
+		`server.ratio: expected a number, got "high" (line 5)`,
+		`tags: expected a list, got "a" (line 6)`,
+		`providers.openai.temperature: unknown field (line 10)`,
+		`small: integer 300 is out of range (line 11)`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(errs), len(want), err)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Errorf("error %d = %q, want %q", i, errs[i].Error(), w)
+		}
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	if err := CheckJSON([]byte(`{"Server":{"port":1},"tags":["a"]}`), &testConfig{}); err != nil {
+		t.Fatalf("keys should match case-insensitively: %v", err)
+	}
+
+	err := CheckJSON([]byte(`{"providers":{"openai":{"model":4}}}`), &testConfig{})
+	if err == nil || !strings.Contains(err.Error(), "providers.openai.model: expected a string, got integer 4") {
+		t.Errorf("expected string type mismatch, got %v", err)
+	}
+
+	if err := CheckJSON([]byte(`{invalid`), &testConfig{}); err == nil {
+		t.Error("expected syntax error")
+	}
+}
+
+func TestPrefixAndWrap(t *testing.T) {
+	var inner Errors
+	inner.Add("temperature", "must be between 0 and 2")
+	inner.Add("[0]", "must not be empty")
+
+	err := Prefix("tenants.ai8.providers.gemini", inner.Err())
+	want := "tenants.ai8.providers.gemini.temperature: must be between 0 and 2\ntenants.ai8.providers.gemini[0]: must not be empty"
+	if err.Error() != want {
+		t.Errorf("Prefix() = %q, want %q", err, want)
+	}
+
+	var errs Errors
+	errs.Wrap("proxy", errors.New("invalid url"))
+	errs.Wrap("redis", nil)
+	if len(errs) != 1 || errs[0].Error() != "proxy: invalid url" {
+		t.Errorf("unexpected wrapped errors: %v", errs)
+	}
+	if (Errors{}).Err() != nil {
+		t.Error("expected nil error for no problems")
+	}
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// DefaultRollupInterval is how often the aggregator refreshes rollups.
+	DefaultRollupInterval = 5 * time.Minute
+
+	// rollupBackfill is how far back the first run aggregates when no rollups exist.
+	rollupBackfill = 30 * 24 * time.Hour
+)
+
+// RollupAggregator maintains the activity rollup tables in the background.
+type RollupAggregator struct {
+	repo     *Repository
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewRollupAggregator creates an aggregator that refreshes rollups every
+// interval. A non-positive interval uses DefaultRollupInterval.
+func NewRollupAggregator(client *Client, interval time.Duration) *RollupAggregator {
+	if interval <= 0 {
+		interval = DefaultRollupInterval
+	}
+	return &RollupAggregator{
+		repo:     NewRepository(client),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run refreshes rollups until ctx is cancelled. The first refresh resumes
+// from the latest stored hour (or backfills), so downtime leaves no gaps.
+func (a *RollupAggregator) Run(ctx context.Context) {
+	since, err := a.repo.LatestRollupHour(ctx)
+	if err != nil {
+		slog.Warn("failed to read latest activity rollup, backfilling", "error", err)
+	}
+	if since.IsZero() {
+		since = a.now().Add(-rollupBackfill)
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		since = a.refresh(ctx, since)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh rebuilds rollups from since and returns the start for the next run.
+// On failure the same window is retried next time.
+func (a *RollupAggregator) refresh(ctx context.Context, since time.Time) time.Time {
+	start := a.now()
+	if err := a.repo.RefreshActivityRollups(ctx, since); err != nil {
+		if ctx.Err() == nil {
+			slog.Error("failed to refresh activity rollups", "error", err, "since", since)
+		}
+		return since
+	}
+	slog.Debug("refreshed activity rollups", "since", since, "duration_ms", a.now().Sub(start).Milliseconds())
+
+	// Re-aggregate the previous hour too, to pick up late-committed turns
+	return RollupBucket(RollupHourly, start).Add(-time.Hour)
+}
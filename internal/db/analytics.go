@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageAnalysis is the structured metadata the conversation analytics job
+// extracted from a user message.
+type MessageAnalysis struct {
+	MessageID          uuid.UUID
+	ThreadID           uuid.UUID
+	Intent             string
+	RequiresUserAction bool
+	Entities           []AnalysisEntity
+	Topics             []string
+	Provider           string
+	Model              string
+	Error              string // Set when extraction failed; the message is not retried
+	MessageCreatedAt   time.Time
+}
+
+// AnalysisEntity is a named entity found in a message.
+type AnalysisEntity struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// AnalyticsCount is how many analyzed messages have a value.
+type AnalyticsCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// AnalyticsReport aggregates the analyses of the messages sent in a window.
+type AnalyticsReport struct {
+	Analyzed int64            `json:"analyzed"` // Messages analyzed successfully
+	Failed   int64            `json:"failed"`   // Messages extraction failed for
+	Intents  []AnalyticsCount `json:"intents"`  // Most common first
+	Topics   []AnalyticsCount `json:"topics"`   // Most common first
+}
+
+// messageAnalyticsTable returns the tenant-specific message analytics table name.
+func (r *Repository) messageAnalyticsTable() string {
+	if r.tablePrefix == "" {
+		return "airborne_message_analytics" // Legacy table
+	}
+	return r.tablePrefix + "_message_analytics"
+}
+
+// messageTopicsTable returns the tenant-specific message topics table name.
+func (r *Repository) messageTopicsTable() string {
+	if r.tablePrefix == "" {
+		return "airborne_message_topics" // Legacy table
+	}
+	return r.tablePrefix + "_message_topics"
+}
+
+// ListUnanalyzedMessages returns up to limit user messages on their thread's
+// current branch that have not been analyzed, newest first, so reports cover
+// recent traffic while older messages are worked through. Only the ID,
+// thread ID, content and creation time are set.
+func (r *Repository) ListUnanalyzedMessages(ctx context.Context, limit int) ([]Message, error) {
+	if err := r.checkTenant(ctx, "ListUnanalyzedMessages"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT m.id, m.thread_id, m.content, m.created_at
+		FROM %s m
+		LEFT JOIN %s a ON a.message_id = m.id
+		WHERE m.role = 'user' AND m.superseded_at IS NULL AND m.content <> '' AND a.message_id IS NULL
+		ORDER BY m.created_at DESC
+		LIMIT $1
+	`, r.messagesTable(), r.messageAnalyticsTable())
+	r.client.logQuery(query, limit)
+
+	rows, err := r.client.backend.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unanalyzed messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		m := Message{Role: RoleUser}
+		if err := rows.Scan(&m.ID, &m.ThreadID, &m.Content, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list unanalyzed messages: %w", err)
+	}
+	return messages, nil
+}
+
+// SaveMessageAnalysis stores a message's analysis with its topics, which are
+// lowercased so reports count spelling variants together. A message that is
+// already analyzed keeps its first analysis.
+func (r *Repository) SaveMessageAnalysis(ctx context.Context, a *MessageAnalysis) error {
+	if err := r.checkTenant(ctx, "SaveMessageAnalysis"); err != nil {
+		return err
+	}
+	entities, err := json.Marshal(a.Entities)
+	if err != nil || a.Entities == nil {
+		entities = []byte("[]")
+	}
+
+	tx, err := r.client.backend.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (message_id, thread_id, intent, requires_user_action, entities, provider, model, error, message_created_at, analyzed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT (message_id) DO NOTHING
+	`, r.messageAnalyticsTable())
+	r.client.logQuery(query, a.MessageID, a.Intent)
+
+	inserted, err := tx.Exec(ctx, query,
+		a.MessageID, a.ThreadID, a.Intent, a.RequiresUserAction, string(entities), a.Provider, a.Model, a.Error, a.MessageCreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save message analysis: %w", err)
+	}
+	if inserted == 0 {
+		return nil
+	}
+
+	topicQuery := fmt.Sprintf(`
+		INSERT INTO %s (message_id, topic)
+		VALUES ($1, $2)
+		ON CONFLICT (message_id, topic) DO NOTHING
+	`, r.messageTopicsTable())
+	for _, topic := range a.Topics {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if topic == "" {
+			continue
+		}
+		if _, err := tx.Exec(ctx, topicQuery, a.MessageID, topic); err != nil {
+			return fmt.Errorf("failed to save message topic: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit message analysis: %w", err)
+	}
+	return nil
+}
+
+// GetAnalyticsReport aggregates the analyses of messages sent in
+// [since, until), returning at most limit intents and topics.
+func (r *Repository) GetAnalyticsReport(ctx context.Context, since, until time.Time, limit int) (*AnalyticsReport, error) {
+	if err := r.checkTenant(ctx, "GetAnalyticsReport"); err != nil {
+		return nil, err
+	}
+	report := &AnalyticsReport{Intents: []AnalyticsCount{}, Topics: []AnalyticsCount{}}
+
+	countQuery := fmt.Sprintf(`
+		SELECT COALESCE(SUM(CASE WHEN error = '' THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN error <> '' THEN 1 ELSE 0 END), 0)
+		FROM %s
+		WHERE message_created_at >= $1 AND message_created_at < $2
+	`, r.messageAnalyticsTable())
+	r.client.logQuery(countQuery, since, until)
+	if err := r.client.reader().QueryRow(ctx, countQuery, since, until).Scan(&report.Analyzed, &report.Failed); err != nil {
+		return nil, fmt.Errorf("failed to count message analyses: %w", err)
+	}
+
+	intentQuery := fmt.Sprintf(`
+		SELECT intent, COUNT(*) AS n
+		FROM %s
+		WHERE message_created_at >= $1 AND message_created_at < $2 AND error = ''
+		GROUP BY intent
+		ORDER BY n DESC, intent
+		LIMIT $3
+	`, r.messageAnalyticsTable())
+	intents, err := r.analyticsCounts(ctx, intentQuery, since, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query intent counts: %w", err)
+	}
+	report.Intents = intents
+
+	topicQuery := fmt.Sprintf(`
+		SELECT t.topic, COUNT(*) AS n
+		FROM %s t
+		JOIN %s a ON a.message_id = t.message_id
+		WHERE a.message_created_at >= $1 AND a.message_created_at < $2
+		GROUP BY t.topic
+		ORDER BY n DESC, t.topic
+		LIMIT $3
+	`, r.messageTopicsTable(), r.messageAnalyticsTable())
+	topics, err := r.analyticsCounts(ctx, topicQuery, since, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query topic counts: %w", err)
+	}
+	report.Topics = topics
+	return report, nil
+}
+
+// analyticsCounts runs a (value, count) query.
+func (r *Repository) analyticsCounts(ctx context.Context, query string, args ...any) ([]AnalyticsCount, error) {
+	r.client.logQuery(query, args...)
+	rows, err := r.client.reader().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []AnalyticsCount{}
+	for rows.Next() {
+		var c AnalyticsCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMessageAnalytics(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+
+	threadID := uuid.New()
+	for _, input := range []string{"Where is my order?", "Cancel it please", "Thanks"} {
+		if err := repo.PersistConversationTurn(ctx, threadID, "user-1", input, "OK", "openai", "gpt-4o", "", 10, 5, 100, 0.001); err != nil {
+			t.Fatalf("PersistConversationTurn failed: %v", err)
+		}
+	}
+
+	pending, err := repo.ListUnanalyzedMessages(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListUnanalyzedMessages failed: %v", err)
+	}
+	if len(pending) != 3 {
+		t.Fatalf("got %d unanalyzed messages, want the 3 user messages", len(pending))
+	}
+
+	analyses := []*MessageAnalysis{
+		{Intent: "question", Topics: []string{"Orders", "shipping"}, Entities: []AnalysisEntity{{Name: "Acme", Type: "organization"}}},
+		{Intent: "request", Topics: []string{"orders", " "}},
+		{Error: "reply did not match the schema"},
+	}
+	for i, a := range analyses {
+		a.MessageID, a.ThreadID, a.MessageCreatedAt = pending[i].ID, pending[i].ThreadID, pending[i].CreatedAt
+		a.Provider, a.Model = "openai", "gpt-4o-mini"
+		if err := repo.SaveMessageAnalysis(ctx, a); err != nil {
+			t.Fatalf("SaveMessageAnalysis failed: %v", err)
+		}
+	}
+	// A second analysis of the same message is ignored
+	if err := repo.SaveMessageAnalysis(ctx, &MessageAnalysis{MessageID: pending[0].ID, ThreadID: threadID, Intent: "complaint", Topics: []string{"refunds"}, MessageCreatedAt: pending[0].CreatedAt}); err != nil {
+		t.Fatalf("SaveMessageAnalysis (duplicate) failed: %v", err)
+	}
+
+	if pending, err := repo.ListUnanalyzedMessages(ctx, 10); err != nil || len(pending) != 0 {
+		t.Errorf("ListUnanalyzedMessages = %d messages, %v, want none left", len(pending), err)
+	}
+
+	now := time.Now()
+	report, err := repo.GetAnalyticsReport(ctx, now.Add(-time.Hour), now.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetAnalyticsReport failed: %v", err)
+	}
+	if report.Analyzed != 2 || report.Failed != 1 {
+		t.Errorf("Analyzed = %d, Failed = %d, want 2 and 1", report.Analyzed, report.Failed)
+	}
+	if len(report.Intents) != 2 || report.Intents[0].Count != 1 {
+		t.Errorf("Intents = %+v, want question and request once each", report.Intents)
+	}
+	if len(report.Topics) != 2 || report.Topics[0] != (AnalyticsCount{Value: "orders", Count: 2}) {
+		t.Errorf("Topics = %+v, want orders counted across spellings", report.Topics)
+	}
+
+	empty, err := repo.GetAnalyticsReport(ctx, now.Add(-48*time.Hour), now.Add(-24*time.Hour), 10)
+	if err != nil || empty.Analyzed != 0 || len(empty.Topics) != 0 {
+		t.Errorf("report for an earlier window = %+v, %v, want it empty", empty, err)
+	}
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Supported database drivers.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// errNoRows is returned by backends when a single-row query matches nothing,
+// regardless of the underlying driver's sentinel error.
+var errNoRows = errors.New("db: no rows in result set")
+
+// backend is the storage interface the Repository runs its SQL against.
+// Queries use PostgreSQL-style $N placeholders; backends translate any
+// dialect differences themselves.
+type backend interface {
+	querier
+	Begin(ctx context.Context) (tx, error)
+	Ping(ctx context.Context) error
+	Close()
+}
+
+// querier executes statements on a backend or inside a transaction.
+type querier interface {
+	// Exec runs a statement and returns the number of rows affected.
+	Exec(ctx context.Context, query string, args ...any) (int64, error)
+	Query(ctx context.Context, query string, args ...any) (rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) row
+}
+
+// tx is a backend transaction.
+type tx interface {
+	querier
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// rows iterates over a query result.
+type rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+}
+
+// row is the result of QueryRow. Scan returns errNoRows when nothing matched.
+type row interface {
+	Scan(dest ...any) error
+}
@@ -0,0 +1,60 @@
+package db
+
+import "context"
+
+// WithFaults runs inject before every statement, transaction and ping, so
+// chaos testing can delay or fail database access. inject returns the error
+// to fail the call with, or nil. Call it before the client is used.
+func (c *Client) WithFaults(inject func(ctx context.Context) error) {
+	c.backend = faultBackend{backend: c.backend, inject: inject}
+	if c.replica != nil {
+		c.replica.backend = faultBackend{backend: c.replica.backend, inject: inject}
+		c.replica.primary = c.backend
+	}
+}
+
+// faultBackend is a backend whose calls first go through inject.
+type faultBackend struct {
+	backend
+	inject func(ctx context.Context) error
+}
+
+// errRow is a row whose Scan fails with err.
+type errRow struct{ err error }
+
+func (r errRow) Scan(dest ...any) error { return r.err }
+
+func (b faultBackend) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	if err := b.inject(ctx); err != nil {
+		return 0, err
+	}
+	return b.backend.Exec(ctx, query, args...)
+}
+
+func (b faultBackend) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	if err := b.inject(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.Query(ctx, query, args...)
+}
+
+func (b faultBackend) QueryRow(ctx context.Context, query string, args ...any) row {
+	if err := b.inject(ctx); err != nil {
+		return errRow{err}
+	}
+	return b.backend.QueryRow(ctx, query, args...)
+}
+
+func (b faultBackend) Begin(ctx context.Context) (tx, error) {
+	if err := b.inject(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.Begin(ctx)
+}
+
+func (b faultBackend) Ping(ctx context.Context) error {
+	if err := b.inject(ctx); err != nil {
+		return err
+	}
+	return b.backend.Ping(ctx)
+}
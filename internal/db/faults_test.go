@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestClient_WithFaults(t *testing.T) {
+	ctx := context.Background()
+	client := newSQLiteClient(t)
+	injected := errors.New("injected")
+	var fail bool
+	client.WithFaults(func(ctx context.Context) error {
+		if fail {
+			return injected
+		}
+		return nil
+	})
+	repo, err := client.TenantRepository("zztest")
+	if err != nil {
+		t.Fatalf("TenantRepository failed: %v", err)
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed without a fault: %v", err)
+	}
+	if _, err := repo.GetProviderCalls(ctx, "zztest", "", time.Now().Add(-time.Hour), time.Now(), 10); err != nil {
+		t.Fatalf("query failed without a fault: %v", err)
+	}
+
+	fail = true
+	if err := client.Ping(ctx); !errors.Is(err, injected) {
+		t.Errorf("Ping err = %v, want the injected fault", err)
+	}
+	if _, err := repo.GetProviderCalls(ctx, "zztest", "", time.Now().Add(-time.Hour), time.Now(), 10); !errors.Is(err, injected) {
+		t.Errorf("query err = %v, want the injected fault", err)
+	}
+	if _, err := repo.GetThreadConversation(ctx, uuid.New()); !errors.Is(err, injected) {
+		t.Errorf("single-row query err = %v, want the injected fault", err)
+	}
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// providerCallsTable is the usage ledger: one row per provider API call,
+// shared across tenants.
+const providerCallsTable = "airborne_provider_calls"
+
+// ProviderCall is a provider API call in the usage ledger. Failed calls are
+// recorded too, with the error and, when the provider answered, its HTTP
+// status.
+type ProviderCall struct {
+	ID           uuid.UUID `json:"id"`
+	TenantID     string    `json:"tenant_id"`
+	RequestID    string    `json:"request_id,omitempty"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model,omitempty"`
+	KeyID        string    `json:"key_id,omitempty"` // Hashed client API key ID
+	InputTokens  int64     `json:"input_tokens"`
+	OutputTokens int64     `json:"output_tokens"`
+	CostUSD      float64   `json:"cost_usd"`
+	HTTPStatus   int       `json:"http_status"` // 0 when the call failed without a response
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ProviderCallTotals sums the ledger for one provider and model.
+type ProviderCallTotals struct {
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	CallCount    int64   `json:"call_count"`
+	FailedCount  int64   `json:"failed_count"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// RecordProviderCall appends a call to the usage ledger. A zero ID or
+// CreatedAt is filled in.
+func (r *Repository) RecordProviderCall(ctx context.Context, c ProviderCall) error {
+	if err := r.checkTenantArg(ctx, "RecordProviderCall", c.TenantID); err != nil {
+		return err
+	}
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			id, tenant_id, request_id, provider, model, key_id,
+			input_tokens, output_tokens, cost_usd, http_status, error, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, providerCallsTable)
+	r.client.logQuery(query, c.TenantID, c.RequestID, c.Provider, c.Model)
+
+	if _, err := r.client.backend.Exec(ctx, query,
+		c.ID, c.TenantID, c.RequestID, c.Provider, c.Model, c.KeyID,
+		c.InputTokens, c.OutputTokens, c.CostUSD, c.HTTPStatus, c.Error, c.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to record provider call: %w", err)
+	}
+	return nil
+}
+
+// GetProviderCalls returns up to limit ledger entries created in [since,
+// until), newest first. Empty tenantID and providerName match all tenants
+// and providers.
+func (r *Repository) GetProviderCalls(ctx context.Context, tenantID, providerName string, since, until time.Time, limit int) ([]ProviderCall, error) {
+	if err := r.checkTenantArg(ctx, "GetProviderCalls", tenantID); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, request_id, provider, model, key_id,
+		       input_tokens, output_tokens, cost_usd, http_status, error, created_at
+		FROM %s
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3 = '' OR tenant_id = $3) AND ($4 = '' OR provider = $4)
+		ORDER BY created_at DESC
+		LIMIT $5
+	`, providerCallsTable)
+	r.client.logQuery(query, since, until, tenantID, providerName, limit)
+
+	rows, err := r.client.reader().Query(ctx, query, since, until, tenantID, providerName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider calls: %w", err)
+	}
+	defer rows.Close()
+
+	calls := []ProviderCall{}
+	for rows.Next() {
+		var c ProviderCall
+		if err := rows.Scan(
+			&c.ID, &c.TenantID, &c.RequestID, &c.Provider, &c.Model, &c.KeyID,
+			&c.InputTokens, &c.OutputTokens, &c.CostUSD, &c.HTTPStatus, &c.Error, &c.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan provider call: %w", err)
+		}
+		calls = append(calls, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query provider calls: %w", err)
+	}
+	return calls, nil
+}
+
+// GetProviderCallTotals sums the ledger entries created in [since, until)
+// per provider and model, ordered by provider and model. Empty tenantID and
+// providerName match all tenants and providers.
+func (r *Repository) GetProviderCallTotals(ctx context.Context, tenantID, providerName string, since, until time.Time) ([]ProviderCallTotals, error) {
+	if err := r.checkTenantArg(ctx, "GetProviderCallTotals", tenantID); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT provider, model, COUNT(*),
+		       COALESCE(SUM(CASE WHEN error <> '' THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(input_tokens), 0), COALESCE(SUM(output_tokens), 0),
+		       COALESCE(SUM(cost_usd), 0)
+		FROM %s
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3 = '' OR tenant_id = $3) AND ($4 = '' OR provider = $4)
+		GROUP BY provider, model
+		ORDER BY provider, model
+	`, providerCallsTable)
+	r.client.logQuery(query, since, until, tenantID, providerName)
+
+	rows, err := r.client.reader().Query(ctx, query, since, until, tenantID, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider call totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []ProviderCallTotals{}
+	for rows.Next() {
+		var t ProviderCallTotals
+		if err := rows.Scan(&t.Provider, &t.Model, &t.CallCount, &t.FailedCount, &t.InputTokens, &t.OutputTokens, &t.CostUSD); err != nil {
+			return nil, fmt.Errorf("failed to scan provider call totals: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query provider call totals: %w", err)
+	}
+	return totals, nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProviderCallLedger(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(newOutboxRepo(t).client)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, c := range []ProviderCall{
+		{TenantID: "ai8", RequestID: "r1", Provider: "openai", Model: "gpt-4o", InputTokens: 100, OutputTokens: 20, CostUSD: 0.01, HTTPStatus: 200, CreatedAt: now.Add(-3 * time.Minute)},
+		{TenantID: "ai8", RequestID: "r2", Provider: "openai", Model: "gpt-4o", HTTPStatus: 429, Error: "rate limited", CreatedAt: now.Add(-2 * time.Minute)},
+		{TenantID: "ai8", RequestID: "r3", Provider: "gemini", Model: "gemini-2.5-flash", InputTokens: 50, OutputTokens: 5, CostUSD: 0.002, HTTPStatus: 200, CreatedAt: now.Add(-time.Minute)},
+		{TenantID: "email4ai", RequestID: "r4", Provider: "openai", Model: "gpt-4o", InputTokens: 10, HTTPStatus: 200, CreatedAt: now.Add(-time.Minute)},
+		{TenantID: "ai8", RequestID: "old", Provider: "openai", Model: "gpt-4o", CreatedAt: now.Add(-48 * time.Hour)},
+	} {
+		if err := repo.RecordProviderCall(ctx, c); err != nil {
+			t.Fatalf("RecordProviderCall failed: %v", err)
+		}
+	}
+
+	since, until := now.Add(-time.Hour), now
+	calls, err := repo.GetProviderCalls(ctx, "ai8", "", since, until, 10)
+	if err != nil {
+		t.Fatalf("GetProviderCalls failed: %v", err)
+	}
+	if len(calls) != 3 || calls[0].RequestID != "r3" || calls[2].RequestID != "r1" {
+		t.Fatalf("calls = %+v, want r3, r2, r1", calls)
+	}
+	if calls[1].HTTPStatus != 429 || calls[1].Error != "rate limited" || calls[1].ID.String() == "" {
+		t.Errorf("failed call = %+v", calls[1])
+	}
+	if calls, err := repo.GetProviderCalls(ctx, "", "openai", since, until, 2); err != nil || len(calls) != 2 {
+		t.Errorf("limited openai calls = %d, %v, want 2", len(calls), err)
+	}
+
+	totals, err := repo.GetProviderCallTotals(ctx, "", "", since, until)
+	if err != nil {
+		t.Fatalf("GetProviderCallTotals failed: %v", err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("totals = %+v, want gemini and openai", totals)
+	}
+	openai := totals[1]
+	if openai.Provider != "openai" || openai.CallCount != 3 || openai.FailedCount != 1 || openai.InputTokens != 110 || openai.OutputTokens != 20 {
+		t.Errorf("openai totals = %+v", openai)
+	}
+	if totals, err := repo.GetProviderCallTotals(ctx, "ai8", "gemini", since, until); err != nil || len(totals) != 1 || totals[0].CostUSD != 0.002 {
+		t.Errorf("ai8 gemini totals = %+v, %v", totals, err)
+	}
+}
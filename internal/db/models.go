@@ -45,7 +45,10 @@ type Message struct {
 	ProcessingTimeMs *int       `json:"processing_time_ms,omitempty"`
 	Citations        *string    `json:"citations,omitempty"` // JSONB stored as string
 	CreatedAt        time.Time  `json:"created_at"`
-	Metadata         *string    `json:"metadata,omitempty"` // JSONB stored as string
+	Metadata         *string    `json:"metadata,omitempty"`          // JSONB stored as string
+	KeyID            *string    `json:"key_id,omitempty"`            // Hashed client API key ID (assistant messages)
+	ParentMessageID  *uuid.UUID `json:"parent_message_id,omitempty"` // Previous message on this branch; nil for a thread's first message
+	SupersededAt     *time.Time `json:"superseded_at,omitempty"`     // Set when the message is off the thread's current branch
 
 	// Debug fields (for request/response inspection)
 	SystemPrompt    *string `json:"system_prompt,omitempty"`
@@ -93,6 +96,7 @@ type DebugData struct {
 	TenantID  string    `json:"tenant_id"`
 	UserID    string    `json:"user_id"`
 	Timestamp time.Time `json:"timestamp"`
+	TraceID   string    `json:"trace_id,omitempty"` // Server-side trace ID of the request; empty for older messages
 
 	// Request (what was sent to AI)
 	SystemPrompt     string `json:"system_prompt"`
@@ -101,6 +105,12 @@ type DebugData struct {
 	RequestProvider  string `json:"request_provider"`
 	RequestTimestamp string `json:"request_timestamp"`
 
+	// Prompts exactly as the provider assembled them: the system prompt with
+	// RAG context, memory and file-ID mappings, and the new user message.
+	// Empty for messages stored before these were recorded.
+	FinalSystemPrompt string `json:"final_system_prompt,omitempty"`
+	FinalUserPrompt   string `json:"final_user_prompt,omitempty"`
+
 	// Response (what came back from AI)
 	ResponseText     string  `json:"response_text"`
 	ResponseModel    string  `json:"response_model"`
@@ -120,11 +130,24 @@ type DebugData struct {
 	// Rendered HTML (from markdown_svc)
 	RenderedHTML string `json:"rendered_html,omitempty"`
 
+	// Replies rejected by the tenant's validation rules and regenerated
+	ValidationAttempts []ValidationAttempt `json:"validation_attempts,omitempty"`
+
 	// Status
 	Status string `json:"status"` // success, failed
 	Error  string `json:"error,omitempty"`
 }
 
+// ValidationAttempt records a reply that failed the tenant's validation rules
+// and was regenerated.
+type ValidationAttempt struct {
+	Attempt  int      `json:"attempt"`
+	Provider string   `json:"provider"`
+	Model    string   `json:"model,omitempty"`
+	Problems []string `json:"problems"`
+	Response string   `json:"response,omitempty"` // Rejected reply, truncated
+}
+
 // Citation represents a web or file search citation.
 type Citation struct {
 	Type     string `json:"type"` // url, file
@@ -211,13 +234,14 @@ func (m *Message) TruncateContent(maxLen int) string {
 // ConversationMessage represents a message in the conversation view.
 // This is a simplified view for the chat display.
 type ConversationMessage struct {
-	ID           uuid.UUID `json:"id"`
-	Role         string    `json:"role"`
-	Content      string    `json:"content"`
-	RenderedHTML string    `json:"rendered_html,omitempty"`
-	Model        string    `json:"model,omitempty"`
-	Provider     string    `json:"provider,omitempty"`
-	Timestamp    time.Time `json:"timestamp"`
+	ID           uuid.UUID  `json:"id"`
+	Role         string     `json:"role"`
+	Content      string     `json:"content"`
+	RenderedHTML string     `json:"rendered_html,omitempty"`
+	Model        string     `json:"model,omitempty"`
+	Provider     string     `json:"provider,omitempty"`
+	Citations    []Citation `json:"citations,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
 }
 
 // ThreadConversation contains full thread data with all messages.
@@ -285,3 +309,41 @@ type ThreadVectorStore struct {
 	Enabled   bool      `json:"enabled"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// Memory is a durable fact remembered about a user across conversations.
+type Memory struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    string    `json:"user_id"`
+	Category  string    `json:"category"` // name, preference, profile, relationship, other
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"` // Last time the fact was observed
+}
+
+// MemoryCategory constants
+const (
+	MemoryCategoryName         = "name"
+	MemoryCategoryPreference   = "preference"
+	MemoryCategoryProfile      = "profile"
+	MemoryCategoryRelationship = "relationship"
+	MemoryCategoryOther        = "other"
+)
+
+// NewMemory creates a new memory fact for a user.
+// Unknown categories are normalized to "other".
+func NewMemory(userID, category, content string) *Memory {
+	switch category {
+	case MemoryCategoryName, MemoryCategoryPreference, MemoryCategoryProfile, MemoryCategoryRelationship:
+	default:
+		category = MemoryCategoryOther
+	}
+	now := time.Now()
+	return &Memory{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Category:  category,
+		Content:   content,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -226,3 +226,27 @@ func TestCitationsRoundTrip(t *testing.T) {
 }
 
 func strPtr(s string) *string { return &s }
+
+func TestNewMemory(t *testing.T) {
+	mem := NewMemory("user-1", MemoryCategoryPreference, "Prefers metric units")
+
+	if mem.ID == uuid.Nil {
+		t.Error("expected non-nil UUID")
+	}
+	if mem.UserID != "user-1" {
+		t.Errorf("UserID = %q, want %q", mem.UserID, "user-1")
+	}
+	if mem.Category != MemoryCategoryPreference {
+		t.Errorf("Category = %q, want %q", mem.Category, MemoryCategoryPreference)
+	}
+	if !mem.CreatedAt.Equal(mem.UpdatedAt) {
+		t.Errorf("CreatedAt (%v) != UpdatedAt (%v)", mem.CreatedAt, mem.UpdatedAt)
+	}
+}
+
+func TestNewMemory_UnknownCategory(t *testing.T) {
+	mem := NewMemory("user-1", "favorite-color", "Likes green")
+	if mem.Category != MemoryCategoryOther {
+		t.Errorf("Category = %q, want %q", mem.Category, MemoryCategoryOther)
+	}
+}
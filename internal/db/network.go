@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// networkViolationsTable is shared across tenants; violations are recorded
+// before a request's tenant is trusted.
+const networkViolationsTable = "airborne_network_violations"
+
+// NetworkViolation is a request rejected by network restrictions.
+type NetworkViolation struct {
+	ID         uuid.UUID `json:"id"`
+	Surface    string    `json:"surface"` // "grpc" or "admin_http"
+	Method     string    `json:"method"`  // RPC method or HTTP method and path
+	ClientID   string    `json:"client_id,omitempty"`
+	KeyID      string    `json:"key_id,omitempty"` // Hashed key ID
+	RemoteAddr string    `json:"remote_addr"`
+	Country    string    `json:"country,omitempty"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RecordNetworkViolation stores a network violation.
+func (r *Repository) RecordNetworkViolation(ctx context.Context, v NetworkViolation) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, surface, method, client_id, key_id, remote_addr, country, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	`, networkViolationsTable)
+	r.client.logQuery(query, v.Surface, v.Method, v.ClientID)
+
+	if _, err := r.client.backend.Exec(ctx, query, uuid.New(), v.Surface, v.Method, v.ClientID, v.KeyID, v.RemoteAddr, v.Country, v.Reason); err != nil {
+		return fmt.Errorf("failed to record network violation: %w", err)
+	}
+	return nil
+}
+
+// GetNetworkViolations returns the most recent network violations, newest
+// first.
+func (r *Repository) GetNetworkViolations(ctx context.Context, limit int) ([]NetworkViolation, error) {
+	query := fmt.Sprintf(`
+		SELECT id, surface, method, client_id, key_id, remote_addr, country, reason, created_at
+		FROM %s
+		ORDER BY created_at DESC
+		LIMIT $1
+	`, networkViolationsTable)
+	r.client.logQuery(query, limit)
+
+	rows, err := r.client.reader().Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query network violations: %w", err)
+	}
+	defer rows.Close()
+
+	violations := []NetworkViolation{}
+	for rows.Next() {
+		var v NetworkViolation
+		if err := rows.Scan(&v.ID, &v.Surface, &v.Method, &v.ClientID, &v.KeyID, &v.RemoteAddr, &v.Country, &v.Reason, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan network violation: %w", err)
+		}
+		violations = append(violations, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query network violations: %w", err)
+	}
+	return violations, nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNetworkViolations(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+
+	for _, method := range []string{"/airborne.v1.AirborneService/GenerateReply", "GET /admin/activity"} {
+		if err := repo.RecordNetworkViolation(ctx, NetworkViolation{
+			Surface:    "grpc",
+			Method:     method,
+			ClientID:   "client-1",
+			RemoteAddr: "198.51.100.1",
+			Country:    "FR",
+			Reason:     "request address not allowed: 198.51.100.1 is outside the allowed CIDRs",
+		}); err != nil {
+			t.Fatalf("RecordNetworkViolation failed: %v", err)
+		}
+	}
+
+	violations, err := repo.GetNetworkViolations(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetNetworkViolations failed: %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %d", len(violations))
+	}
+	v := violations[0]
+	if v.ClientID != "client-1" || v.RemoteAddr != "198.51.100.1" || v.Country != "FR" || v.CreatedAt.IsZero() {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+
+	if violations, err := repo.GetNetworkViolations(ctx, 1); err != nil || len(violations) != 1 {
+		t.Errorf("expected the limit to apply, got %d, %v", len(violations), err)
+	}
+}
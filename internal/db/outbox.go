@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// outboxTable is shared across tenants; rows are keyed by tenant_id.
+const outboxTable = "airborne_event_outbox"
+
+// Outbox event statuses
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// EventGenerationCompleted is emitted for every persisted conversation turn.
+const EventGenerationCompleted = "generation.completed"
+
+// OutboxEvent is an event awaiting delivery.
+type OutboxEvent struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	EventType string    `json:"event_type"`
+	Payload   string    `json:"payload"` // JSON document
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GenerationEvent is the payload of a generation.completed event.
+// It carries usage and routing metadata only, never message content.
+type GenerationEvent struct {
+	TenantID         string    `json:"tenant_id"`
+	ThreadID         uuid.UUID `json:"thread_id"`
+	MessageID        uuid.UUID `json:"message_id"`
+	UserID           string    `json:"user_id"`
+	KeyID            string    `json:"key_id,omitempty"` // Hashed client API key ID
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	Status           string    `json:"status"` // success, failed
+	InputTokens      int       `json:"input_tokens"`
+	OutputTokens     int       `json:"output_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	GroundingQueries int       `json:"grounding_queries"`
+	GroundingCostUSD float64   `json:"grounding_cost_usd"`
+	ProcessingTimeMs int       `json:"processing_time_ms"`
+	CompletedAt      time.Time `json:"completed_at"`
+}
+
+// enqueueEvent writes an event to the outbox using q, normally the
+// transaction that persists the change the event describes.
+func (r *Repository) enqueueEvent(ctx context.Context, q querier, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, tenant_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, 'pending', 0, NOW(), NOW())
+	`, outboxTable)
+	r.client.logQuery(query, eventType, r.tenantID)
+
+	if _, err := q.Exec(ctx, query, uuid.New(), r.tenantID, eventType, string(data)); err != nil {
+		return fmt.Errorf("failed to enqueue %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// ClaimOutboxEvents claims up to limit pending events that are due at now.
+// A claimed event is hidden from other dispatchers until now+lease; if it is
+// neither delivered nor failed by then (e.g. the process crashed) it becomes
+// due again, which is what makes delivery at-least-once.
+func (r *Repository) ClaimOutboxEvents(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]OutboxEvent, error) {
+	selectQuery := fmt.Sprintf(`
+		SELECT id, tenant_id, event_type, payload, attempts, created_at
+		FROM %s
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at ASC, created_at ASC
+		LIMIT $2
+	`, outboxTable)
+	r.client.logQuery(selectQuery, now, limit)
+
+	rows, err := r.client.backend.Query(ctx, selectQuery, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	var candidates []OutboxEvent
+	for rows.Next() {
+		var ev OutboxEvent
+		if err := rows.Scan(&ev.ID, &ev.TenantID, &ev.EventType, &ev.Payload, &ev.Attempts, &ev.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		candidates = append(candidates, ev)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+
+	// Claim each row conditionally so concurrent dispatchers never share one
+	claimQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE id = $1 AND status = 'pending' AND next_attempt_at <= $3
+	`, outboxTable)
+
+	var claimed []OutboxEvent
+	for _, ev := range candidates {
+		n, err := r.client.backend.Exec(ctx, claimQuery, ev.ID, now.Add(lease), now)
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim outbox event: %w", err)
+		}
+		if n == 1 {
+			ev.Attempts++
+			claimed = append(claimed, ev)
+		}
+	}
+	return claimed, nil
+}
+
+// MarkOutboxDelivered marks a claimed event as delivered.
+func (r *Repository) MarkOutboxDelivered(ctx context.Context, id uuid.UUID) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET status = 'delivered', delivered_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`, outboxTable)
+	r.client.logQuery(query, id)
+
+	if _, err := r.client.backend.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox event delivered: %w", err)
+	}
+	return nil
+}
+
+// RetryOutboxEvent records a failed delivery and schedules the next attempt.
+func (r *Repository) RetryOutboxEvent(ctx context.Context, id uuid.UUID, cause error, retryAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET next_attempt_at = $2, last_error = $3
+		WHERE id = $1
+	`, outboxTable)
+	r.client.logQuery(query, id, retryAt)
+
+	if _, err := r.client.backend.Exec(ctx, query, id, retryAt, outboxError(cause)); err != nil {
+		return fmt.Errorf("failed to reschedule outbox event: %w", err)
+	}
+	return nil
+}
+
+// DeadLetterOutboxEvent gives up on an event after its final failed attempt.
+// Dead events stay in the table for inspection and manual replay.
+func (r *Repository) DeadLetterOutboxEvent(ctx context.Context, id uuid.UUID, cause error) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET status = 'dead', last_error = $2
+		WHERE id = $1
+	`, outboxTable)
+	r.client.logQuery(query, id)
+
+	if _, err := r.client.backend.Exec(ctx, query, id, outboxError(cause)); err != nil {
+		return fmt.Errorf("failed to dead-letter outbox event: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeliveredOutbox deletes events delivered before the given time.
+func (r *Repository) PurgeDeliveredOutbox(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE status = 'delivered' AND delivered_at < $1`, outboxTable)
+	r.client.logQuery(query, before)
+
+	n, err := r.client.backend.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", err)
+	}
+	return n, nil
+}
+
+// outboxError truncates a delivery error for storage.
+func outboxError(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	if len(msg) > 1000 {
+		msg = msg[:1000]
+	}
+	return strings.ToValidUTF8(msg, "")
+}
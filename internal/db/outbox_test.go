@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newOutboxRepo(t *testing.T) *Repository {
+	t.Helper()
+	client, err := NewClient(context.Background(), Config{
+		Driver: DriverSQLite,
+		URL:    filepath.Join(t.TempDir(), "airborne.db"),
+		Outbox: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	repo, err := client.TenantRepository("ai8")
+	if err != nil {
+		t.Fatalf("TenantRepository failed: %v", err)
+	}
+	return repo
+}
+
+func TestPersistConversationTurn_WritesOutboxEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+	threadID := uuid.New()
+
+	if err := repo.PersistConversationTurn(ctx, threadID, "user-1", "secret question", "secret answer", "openai", "gpt-4o", "resp-1", 10, 20, 150, 0.002); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+
+	events, err := repo.ClaimOutboxEvents(ctx, time.Now().Add(time.Second), 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimOutboxEvents failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.EventType != EventGenerationCompleted || ev.TenantID != "ai8" || ev.Attempts != 1 {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	var payload GenerationEvent
+	if err := json.Unmarshal([]byte(ev.Payload), &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.ThreadID != threadID || payload.Status != "success" || payload.TotalTokens != 30 || payload.CostUSD != 0.002 {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if strings.Contains(ev.Payload, "secret question") || strings.Contains(ev.Payload, "secret answer") {
+		t.Error("payload must not include message content")
+	}
+}
+
+func TestPersistConversationTurn_OutboxDisabled(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	if err := repo.PersistConversationTurn(ctx, uuid.New(), "user-1", "q", "a", "openai", "gpt-4o", "", 1, 1, 1, 0); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+	events, err := repo.ClaimOutboxEvents(ctx, time.Now().Add(time.Second), 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimOutboxEvents failed: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events with the outbox disabled, got %d", len(events))
+	}
+}
+
+func TestOutboxLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+
+	if err := repo.PersistConversationTurn(ctx, uuid.New(), "user-1", "q", "[FAILED] boom", "gemini", "gemini-2.5-flash", "", 0, 0, 5, 0); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+
+	now := time.Now().Add(time.Second)
+	claimed, err := repo.ClaimOutboxEvents(ctx, now, 10, time.Minute)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("expected to claim 1 event, got %d, %v", len(claimed), err)
+	}
+	id := claimed[0].ID
+
+	// A claimed event is leased to its dispatcher
+	again, _ := repo.ClaimOutboxEvents(ctx, now, 10, time.Minute)
+	if len(again) != 0 {
+		t.Fatalf("leased event was claimed twice")
+	}
+
+	// An expired lease makes the event due again
+	again, _ = repo.ClaimOutboxEvents(ctx, now.Add(2*time.Minute), 10, time.Minute)
+	if len(again) != 1 || again[0].Attempts != 2 {
+		t.Fatalf("expected the event to be reclaimed after its lease, got %+v", again)
+	}
+
+	if err := repo.RetryOutboxEvent(ctx, id, errors.New("503"), now.Add(time.Hour)); err != nil {
+		t.Fatalf("RetryOutboxEvent failed: %v", err)
+	}
+	if due, _ := repo.ClaimOutboxEvents(ctx, now.Add(30*time.Minute), 10, time.Minute); len(due) != 0 {
+		t.Error("event was claimed before its retry time")
+	}
+
+	if err := repo.MarkOutboxDelivered(ctx, id); err != nil {
+		t.Fatalf("MarkOutboxDelivered failed: %v", err)
+	}
+	if due, _ := repo.ClaimOutboxEvents(ctx, now.Add(2*time.Hour), 10, time.Minute); len(due) != 0 {
+		t.Error("delivered event was claimed")
+	}
+
+	n, err := repo.PurgeDeliveredOutbox(ctx, time.Now().Add(time.Minute))
+	if err != nil || n != 1 {
+		t.Errorf("PurgeDeliveredOutbox = %d, %v; want 1", n, err)
+	}
+}
+
+func TestDeadLetterOutboxEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+
+	if err := repo.PersistConversationTurn(ctx, uuid.New(), "user-1", "q", "a", "openai", "gpt-4o", "", 1, 1, 1, 0); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+	now := time.Now().Add(time.Second)
+	claimed, _ := repo.ClaimOutboxEvents(ctx, now, 10, time.Minute)
+	if len(claimed) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(claimed))
+	}
+
+	if err := repo.DeadLetterOutboxEvent(ctx, claimed[0].ID, errors.New("gone")); err != nil {
+		t.Fatalf("DeadLetterOutboxEvent failed: %v", err)
+	}
+	if due, _ := repo.ClaimOutboxEvents(ctx, now.Add(time.Hour), 10, time.Minute); len(due) != 0 {
+		t.Error("dead event was claimed")
+	}
+}
@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPageSize is used when a page query does not set a limit.
+	DefaultPageSize = 50
+
+	// MaxPageSize caps a single page.
+	MaxPageSize = 500
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// MessageQuery selects a page of a thread's messages. Messages are ordered by
+// (created_at, id), so pages are stable even when timestamps collide.
+type MessageQuery struct {
+	Limit      int    // Page size (default DefaultPageSize, max MaxPageSize)
+	After      string // Only messages after this cursor
+	Before     string // Only messages before this cursor
+	Descending bool   // Newest first
+}
+
+// MessagePage is a page of messages in the requested order.
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+
+	// NextCursor continues in the same direction: pass it as After for
+	// ascending pages or Before for descending pages. Empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ThreadQuery filters and pages a user's threads, most recently updated first.
+type ThreadQuery struct {
+	Limit        int       // Page size (default DefaultPageSize, max MaxPageSize)
+	Cursor       string    // NextCursor from the previous page
+	Status       string    // Optional status filter (active, archived, deleted)
+	Provider     string    // Optional last-used provider filter
+	UpdatedAfter time.Time // Optional lower bound on updated_at
+}
+
+// ThreadPage is a page of threads.
+type ThreadPage struct {
+	Threads    []Thread `json:"threads"`
+	NextCursor string   `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// pageCursor is a keyset position: a timestamp and the row ID as tie-breaker.
+type pageCursor struct {
+	at time.Time
+	id uuid.UUID
+}
+
+// encodeCursor returns an opaque cursor for a keyset position.
+func encodeCursor(at time.Time, id uuid.UUID) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor.
+func decodeCursor(cursor string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	ts, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	at, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	return pageCursor{at: at, id: id}, nil
+}
+
+// pageSize applies the default and maximum page size.
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
+
+// ListMessages returns a page of a thread's messages using keyset pagination.
+func (r *Repository) ListMessages(ctx context.Context, threadID uuid.UUID, q MessageQuery) (*MessagePage, error) {
+	if err := r.checkTenant(ctx, "ListMessages"); err != nil {
+		return nil, err
+	}
+	limit := pageSize(q.Limit)
+	args := []any{threadID}
+	where := []string{"thread_id = $1", "superseded_at IS NULL"}
+
+	if q.After != "" {
+		c, err := decodeCursor(q.After)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, c.at, c.id)
+		where = append(where, fmt.Sprintf("(created_at > $%d OR (created_at = $%d AND id > $%d))", len(args)-1, len(args)-1, len(args)))
+	}
+	if q.Before != "" {
+		c, err := decodeCursor(q.Before)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, c.at, c.id)
+		where = append(where, fmt.Sprintf("(created_at < $%d OR (created_at = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args)))
+	}
+
+	order := "ASC"
+	if q.Descending {
+		order = "DESC"
+	}
+
+	// Fetch one extra row to know whether another page exists
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT id, thread_id, role, content, provider, model, response_id,
+		       input_tokens, output_tokens, total_tokens, cost_usd,
+		       processing_time_ms, citations, created_at, metadata, system_prompt,
+		       parent_message_id, superseded_at
+		FROM %s
+		WHERE %s
+		ORDER BY created_at %s, id %s
+		LIMIT $%d
+	`, r.messagesTable(), strings.Join(where, " AND "), order, order, len(args))
+	r.client.logQuery(query, args...)
+
+	rows, err := r.client.backend.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages, err := scanMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{Messages: messages}
+	if len(page.Messages) > limit {
+		page.Messages = page.Messages[:limit]
+		last := page.Messages[limit-1]
+		page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// ListThreadsByUser returns a page of a user's threads, most recently updated first.
+func (r *Repository) ListThreadsByUser(ctx context.Context, userID string, q ThreadQuery) (*ThreadPage, error) {
+	if err := r.checkTenant(ctx, "ListThreadsByUser"); err != nil {
+		return nil, err
+	}
+	limit := pageSize(q.Limit)
+	args := []any{userID}
+	where := []string{"user_id = $1"}
+
+	if q.Status != "" {
+		args = append(args, q.Status)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if q.Provider != "" {
+		args = append(args, q.Provider)
+		where = append(where, fmt.Sprintf("provider = $%d", len(args)))
+	}
+	if !q.UpdatedAfter.IsZero() {
+		args = append(args, q.UpdatedAfter)
+		where = append(where, fmt.Sprintf("updated_at > $%d", len(args)))
+	}
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, c.at, c.id)
+		where = append(where, fmt.Sprintf("(updated_at < $%d OR (updated_at = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args)))
+	}
+
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT id, user_id, provider, model, status, message_count, created_at, updated_at, metadata
+		FROM %s
+		WHERE %s
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $%d
+	`, r.threadsTable(), strings.Join(where, " AND "), len(args))
+	r.client.logQuery(query, args...)
+
+	rows, err := r.client.backend.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list threads: %w", err)
+	}
+	defer rows.Close()
+
+	page := &ThreadPage{}
+	for rows.Next() {
+		var thread Thread
+		err := rows.Scan(
+			&thread.ID,
+			&thread.UserID,
+			&thread.Provider,
+			&thread.Model,
+			&thread.Status,
+			&thread.MessageCount,
+			&thread.CreatedAt,
+			&thread.UpdatedAt,
+			&thread.Metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan thread: %w", err)
+		}
+		page.Threads = append(page.Threads, thread)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list threads: %w", err)
+	}
+
+	if len(page.Threads) > limit {
+		page.Threads = page.Threads[:limit]
+		last := page.Threads[limit-1]
+		page.NextCursor = encodeCursor(last.UpdatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// scanMessages reads message rows in the column order used by GetMessages.
+func scanMessages(rs rows) ([]Message, error) {
+	var messages []Message
+	for rs.Next() {
+		var msg Message
+		err := rs.Scan(
+			&msg.ID,
+			&msg.ThreadID,
+			&msg.Role,
+			&msg.Content,
+			&msg.Provider,
+			&msg.Model,
+			&msg.ResponseID,
+			&msg.InputTokens,
+			&msg.OutputTokens,
+			&msg.TotalTokens,
+			&msg.CostUSD,
+			&msg.ProcessingTimeMs,
+			&msg.Citations,
+			&msg.CreatedAt,
+			&msg.Metadata,
+			&msg.SystemPrompt,
+			&msg.ParentMessageID,
+			&msg.SupersededAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+	return messages, nil
+}
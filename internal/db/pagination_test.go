@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 30, 0, 123456789, time.UTC)
+	id := uuid.New()
+
+	c, err := decodeCursor(encodeCursor(at, id))
+	if err != nil {
+		t.Fatalf("decodeCursor failed: %v", err)
+	}
+	if !c.at.Equal(at) || c.id != id {
+		t.Errorf("round trip = %v/%v, want %v/%v", c.at, c.id, at, id)
+	}
+
+	for _, bad := range []string{"not base64!", "bm8tc2VwYXJhdG9y", encodeCursor(at, id)[:10]} {
+		if _, err := decodeCursor(bad); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", bad, err)
+		}
+	}
+}
+
+func TestListMessages_Pagination(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	thread := NewThread("user-1")
+	if err := repo.CreateThread(ctx, thread); err != nil {
+		t.Fatalf("CreateThread failed: %v", err)
+	}
+
+	// Pairs of messages share a timestamp so the id tie-breaker is exercised
+	base := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		msg := NewMessage(thread.ID, RoleUser, "m")
+		msg.CreatedAt = base.Add(time.Duration(i/2) * time.Second)
+		if err := repo.CreateMessage(ctx, msg); err != nil {
+			t.Fatalf("CreateMessage failed: %v", err)
+		}
+	}
+
+	collect := func(q MessageQuery, next func(*MessageQuery, string)) []Message {
+		var all []Message
+		for pages := 0; ; pages++ {
+			if pages > 10 {
+				t.Fatal("pagination did not terminate")
+			}
+			page, err := repo.ListMessages(ctx, thread.ID, q)
+			if err != nil {
+				t.Fatalf("ListMessages failed: %v", err)
+			}
+			if len(page.Messages) > q.Limit {
+				t.Fatalf("page has %d messages, limit %d", len(page.Messages), q.Limit)
+			}
+			all = append(all, page.Messages...)
+			if page.NextCursor == "" {
+				return all
+			}
+			next(&q, page.NextCursor)
+		}
+	}
+
+	asc := collect(MessageQuery{Limit: 3}, func(q *MessageQuery, c string) { q.After = c })
+	desc := collect(MessageQuery{Limit: 3, Descending: true}, func(q *MessageQuery, c string) { q.Before = c })
+
+	if len(asc) != 7 || len(desc) != 7 {
+		t.Fatalf("expected 7 messages each way, got %d ascending and %d descending", len(asc), len(desc))
+	}
+	seen := make(map[uuid.UUID]bool)
+	for i := range asc {
+		if seen[asc[i].ID] {
+			t.Errorf("message %s returned twice", asc[i].ID)
+		}
+		seen[asc[i].ID] = true
+		if asc[i].ID != desc[len(desc)-1-i].ID {
+			t.Errorf("descending order is not the reverse of ascending at %d", i)
+		}
+	}
+
+	// A window between two cursors
+	page, err := repo.ListMessages(ctx, thread.ID, MessageQuery{
+		After:  encodeCursor(asc[1].CreatedAt, asc[1].ID),
+		Before: encodeCursor(asc[5].CreatedAt, asc[5].ID),
+	})
+	if err != nil {
+		t.Fatalf("ListMessages failed: %v", err)
+	}
+	if len(page.Messages) != 3 || page.Messages[0].ID != asc[2].ID || page.NextCursor != "" {
+		t.Errorf("unexpected window page: %d messages, next %q", len(page.Messages), page.NextCursor)
+	}
+
+	if _, err := repo.ListMessages(ctx, thread.ID, MessageQuery{After: "garbage"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestListThreadsByUser(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	base := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	var ids []uuid.UUID
+	for i := 0; i < 5; i++ {
+		thread := NewThread("user-1")
+		thread.UpdatedAt = base.Add(time.Duration(i) * time.Minute)
+		if i == 4 {
+			thread.Status = ThreadStatusArchived
+		}
+		if i%2 == 0 {
+			provider := "gemini"
+			thread.Provider = &provider
+		}
+		if err := repo.CreateThread(ctx, thread); err != nil {
+			t.Fatalf("CreateThread failed: %v", err)
+		}
+		ids = append(ids, thread.ID)
+	}
+	if err := repo.CreateThread(ctx, NewThread("user-2")); err != nil {
+		t.Fatalf("CreateThread failed: %v", err)
+	}
+
+	first, err := repo.ListThreadsByUser(ctx, "user-1", ThreadQuery{Limit: 3})
+	if err != nil {
+		t.Fatalf("ListThreadsByUser failed: %v", err)
+	}
+	if len(first.Threads) != 3 || first.Threads[0].ID != ids[4] || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	second, err := repo.ListThreadsByUser(ctx, "user-1", ThreadQuery{Limit: 3, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("ListThreadsByUser failed: %v", err)
+	}
+	if len(second.Threads) != 2 || second.Threads[0].ID != ids[1] || second.NextCursor != "" {
+		t.Errorf("unexpected second page: %+v", second)
+	}
+
+	filtered, err := repo.ListThreadsByUser(ctx, "user-1", ThreadQuery{
+		Status:       ThreadStatusActive,
+		Provider:     "gemini",
+		UpdatedAfter: base,
+	})
+	if err != nil {
+		t.Fatalf("ListThreadsByUser failed: %v", err)
+	}
+	if len(filtered.Threads) != 1 || filtered.Threads[0].ID != ids[2] {
+		t.Errorf("expected only thread 2 to match filters, got %+v", filtered.Threads)
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ThreadPin is the provider and model a thread's turns default to when they
+// do not choose one. Unlike the thread's last-used provider, the pin does
+// not move when a turn fails over.
+type ThreadPin struct {
+	Provider string
+	Model    string // Empty for the provider's configured model
+}
+
+// GetThreadPin returns the pin of the user's thread, or nil if the thread
+// does not exist, belongs to another user or is not pinned.
+func (r *Repository) GetThreadPin(ctx context.Context, threadID uuid.UUID, userID string) (*ThreadPin, error) {
+	if err := r.checkTenant(ctx, "GetThreadPin"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT pinned_provider, pinned_model
+		FROM %s
+		WHERE id = $1 AND user_id = $2
+	`, r.threadsTable())
+	r.client.logQuery(query, threadID, userID)
+
+	var provider, model *string
+	err := r.client.backend.QueryRow(ctx, query, threadID, userID).Scan(&provider, &model)
+	if errors.Is(err, errNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread pin: %w", err)
+	}
+	if provider == nil || *provider == "" {
+		return nil, nil
+	}
+	pin := &ThreadPin{Provider: *provider}
+	if model != nil {
+		pin.Model = *model
+	}
+	return pin, nil
+}
+
+// PinThread sets the provider and model of a thread's pin. It does nothing
+// if the thread does not exist.
+func (r *Repository) PinThread(ctx context.Context, threadID uuid.UUID, pin ThreadPin) error {
+	if err := r.checkTenant(ctx, "PinThread"); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET pinned_provider = $2, pinned_model = $3
+		WHERE id = $1
+	`, r.threadsTable())
+	r.client.logQuery(query, threadID, pin.Provider, pin.Model)
+
+	if _, err := r.client.backend.Exec(ctx, query, threadID, pin.Provider, pin.Model); err != nil {
+		return fmt.Errorf("failed to pin thread: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestThreadPins(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+	threadID := uuid.New()
+
+	if err := repo.PinThread(ctx, threadID, ThreadPin{Provider: "gemini"}); err != nil {
+		t.Fatalf("PinThread on a missing thread failed: %v", err)
+	}
+	if pin, err := repo.GetThreadPin(ctx, threadID, "user-1"); err != nil || pin != nil {
+		t.Fatalf("GetThreadPin = %v, %v, want no pin for a missing thread", pin, err)
+	}
+
+	if err := repo.PersistConversationTurnWithDebug(ctx, threadID, "user-1", "hello", "hi", "openai", "gpt-4o", "",
+		10, 5, 100, 0.001, 0, 0, nil, nil, nil); err != nil {
+		t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+	}
+	if pin, err := repo.GetThreadPin(ctx, threadID, "user-1"); err != nil || pin != nil {
+		t.Fatalf("GetThreadPin = %v, %v, want no pin before pinning", pin, err)
+	}
+
+	if err := repo.PinThread(ctx, threadID, ThreadPin{Provider: "anthropic", Model: "claude-sonnet-4"}); err != nil {
+		t.Fatalf("PinThread failed: %v", err)
+	}
+	pin, err := repo.GetThreadPin(ctx, threadID, "user-1")
+	if err != nil || pin == nil || *pin != (ThreadPin{Provider: "anthropic", Model: "claude-sonnet-4"}) {
+		t.Errorf("GetThreadPin = %v, %v, want the anthropic pin", pin, err)
+	}
+	if pin, err := repo.GetThreadPin(ctx, threadID, "user-2"); err != nil || pin != nil {
+		t.Errorf("GetThreadPin = %v, %v, want no pin for another user's thread", pin, err)
+	}
+
+	// A turn on another provider, as after failover, leaves the pin
+	if err := repo.PersistConversationTurnWithDebug(ctx, threadID, "user-1", "again", "hi again", "gemini", "gemini-2.5-flash", "",
+		10, 5, 100, 0.001, 0, 0, nil, nil, nil); err != nil {
+		t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+	}
+	if pin, err := repo.GetThreadPin(ctx, threadID, "user-1"); err != nil || pin == nil || pin.Provider != "anthropic" {
+		t.Errorf("GetThreadPin = %v, %v, want the pin unchanged", pin, err)
+	}
+}
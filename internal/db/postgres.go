@@ -1,8 +1,11 @@
-// Package db provides PostgreSQL database connectivity for message persistence.
+// Package db provides database connectivity for message persistence.
+// PostgreSQL is the default backend; SQLite is available for single-node
+// deployments that do not run a database server.
 package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,33 +14,99 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Client wraps a PostgreSQL connection pool.
+// Client wraps a database backend.
 type Client struct {
-	pool        *pgxpool.Pool
+	backend     backend
+	pool        *pgxpool.Pool // nil unless the driver is PostgreSQL
+	replica     *replica      // nil when no read replica is configured
 	logQueries  bool
+	outbox      bool // Write events to the outbox with each persisted turn
 	tenantRepos map[string]*Repository
 	mu          sync.RWMutex
 }
 
 // Config holds database connection configuration.
 type Config struct {
-	URL            string
+	Driver         string // "postgres" (default) or "sqlite"
+	URL            string // Connection URL, or database file path for SQLite
+	ReplicaURL     string // Optional PostgreSQL read replica for dashboard queries
 	MaxConnections int
 	LogQueries     bool
+	Outbox         bool   // Write a generation.completed outbox event with each persisted turn
 	CACert         string // PEM-encoded CA certificate for SSL verification
 }
 
-// NewClient creates a new PostgreSQL client with connection pool.
+// NewClient creates a new database client for the configured driver.
 func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	if cfg.URL == "" {
 		return nil, fmt.Errorf("database URL is required")
 	}
 
-	dbURL := cfg.URL
+	client := &Client{
+		logQueries:  cfg.LogQueries,
+		outbox:      cfg.Outbox,
+		tenantRepos: make(map[string]*Repository),
+	}
+
+	switch cfg.Driver {
+	case "", DriverPostgres:
+		pool, err := newPostgresPool(ctx, cfg.URL, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		// Verify connectivity
+		if err := pool.Ping(ctx); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to ping database: %w", err)
+		}
 
+		slog.Info("database connection established",
+			"max_connections", pool.Config().MaxConns,
+		)
+
+		client.pool = pool
+		client.backend = &pgBackend{pgQuerier: pgQuerier{q: pool}, pool: pool}
+
+		if cfg.ReplicaURL != "" {
+			replicaPool, err := newPostgresPool(ctx, cfg.ReplicaURL, cfg)
+			if err != nil {
+				pool.Close()
+				return nil, fmt.Errorf("read replica: %w", err)
+			}
+			client.replica = newReplica(&pgBackend{pgQuerier: pgQuerier{q: replicaPool}, pool: replicaPool}, client.backend)
+
+			// An unreachable replica is not fatal; reads use the primary until it recovers
+			if err := replicaPool.Ping(ctx); err != nil {
+				client.replica.markDown(err)
+			} else {
+				slog.Info("read replica connection established")
+			}
+		}
+	case DriverSQLite:
+		if cfg.ReplicaURL != "" {
+			return nil, fmt.Errorf("read replicas are not supported by the %s driver", DriverSQLite)
+		}
+		backend, err := newSQLiteBackend(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		client.backend = backend
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	return client, nil
+}
+
+// newPostgresPool creates a PostgreSQL connection pool for dbURL. Connections
+// are established lazily, so callers ping to verify connectivity.
+func newPostgresPool(ctx context.Context, dbURL string, cfg Config) (*pgxpool.Pool, error) {
 	// If CA certificate provided, write to temp file and add to connection string
 	if cfg.CACert != "" {
 		certPath, err := writeCACertToFile(cfg.CACert)
@@ -81,39 +150,29 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
-	// Verify connectivity
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	slog.Info("database connection established",
-		"max_connections", poolConfig.MaxConns,
-	)
-
-	return &Client{
-		pool:        pool,
-		logQueries:  cfg.LogQueries,
-		tenantRepos: make(map[string]*Repository),
-	}, nil
+	return pool, nil
 }
 
-// Pool returns the underlying connection pool for direct access.
+// Pool returns the underlying PostgreSQL connection pool for direct access.
+// It returns nil when the client uses another driver.
 func (c *Client) Pool() *pgxpool.Pool {
 	return c.pool
 }
 
 // Close closes the database connection pool.
 func (c *Client) Close() {
-	if c.pool != nil {
-		c.pool.Close()
+	if c.replica != nil {
+		c.replica.backend.Close()
+	}
+	if c.backend != nil {
+		c.backend.Close()
 		slog.Info("database connection closed")
 	}
 }
 
 // Ping verifies the database connection is alive.
 func (c *Client) Ping(ctx context.Context) error {
-	return c.pool.Ping(ctx)
+	return c.backend.Ping(ctx)
 }
 
 // TenantRepository returns a repository scoped to a specific tenant's tables.
@@ -172,3 +231,80 @@ func writeCACertToFile(certPEM string) (string, error) {
 
 	return certPath, nil
 }
+
+// pgExecutor is the subset of pgxpool.Pool and pgx.Tx used by the repository.
+type pgExecutor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// pgQuerier adapts a pgx pool or transaction to the querier interface.
+type pgQuerier struct {
+	q pgExecutor
+}
+
+func (p pgQuerier) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	tag, err := p.q.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (p pgQuerier) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	return p.q.Query(ctx, query, args...)
+}
+
+func (p pgQuerier) QueryRow(ctx context.Context, query string, args ...any) row {
+	return pgRow{p.q.QueryRow(ctx, query, args...)}
+}
+
+// pgRow maps pgx.ErrNoRows to errNoRows.
+type pgRow struct {
+	row pgx.Row
+}
+
+func (r pgRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errNoRows
+	}
+	return err
+}
+
+// pgBackend is the PostgreSQL backend.
+type pgBackend struct {
+	pgQuerier
+	pool *pgxpool.Pool
+}
+
+func (b *pgBackend) Begin(ctx context.Context) (tx, error) {
+	t, err := b.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgTx{pgQuerier: pgQuerier{q: t}, tx: t}, nil
+}
+
+func (b *pgBackend) Ping(ctx context.Context) error {
+	return b.pool.Ping(ctx)
+}
+
+func (b *pgBackend) Close() {
+	b.pool.Close()
+}
+
+// pgTx is a PostgreSQL transaction.
+type pgTx struct {
+	pgQuerier
+	tx pgx.Tx
+}
+
+func (t *pgTx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *pgTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
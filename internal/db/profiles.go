@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UserProfile holds a user's personalization preferences, merged into the
+// instructions of requests carrying the user's ID. Empty fields are unset.
+type UserProfile struct {
+	UserID             string
+	DisplayName        string
+	Language           string // Preferred reply language
+	Tone               string
+	CustomInstructions string
+	Timezone           string // IANA name, e.g. "Europe/Berlin"
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// IsEmpty reports whether the profile sets no preference.
+func (p *UserProfile) IsEmpty() bool {
+	return p.DisplayName == "" && p.Language == "" && p.Tone == "" && p.CustomInstructions == "" && p.Timezone == ""
+}
+
+// userProfilesTable returns the tenant-specific user profiles table name.
+func (r *Repository) userProfilesTable() string {
+	if r.tablePrefix == "" {
+		return "airborne_user_profiles" // Legacy table
+	}
+	return r.tablePrefix + "_user_profiles"
+}
+
+// GetUserProfile returns a user's profile, or nil if the user has none.
+func (r *Repository) GetUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	if err := r.checkTenant(ctx, "GetUserProfile"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT user_id, display_name, language, tone, custom_instructions, timezone, created_at, updated_at
+		FROM %s
+		WHERE user_id = $1
+	`, r.userProfilesTable())
+	r.client.logQuery(query, userID)
+
+	var p UserProfile
+	err := r.client.backend.QueryRow(ctx, query, userID).Scan(
+		&p.UserID, &p.DisplayName, &p.Language, &p.Tone, &p.CustomInstructions, &p.Timezone, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if errors.Is(err, errNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profile: %w", err)
+	}
+	return &p, nil
+}
+
+// UpsertUserProfile creates or replaces a user's profile.
+func (r *Repository) UpsertUserProfile(ctx context.Context, p *UserProfile) error {
+	if err := r.checkTenant(ctx, "UpsertUserProfile"); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, display_name, language, tone, custom_instructions, timezone, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET display_name = excluded.display_name,
+		    language = excluded.language,
+		    tone = excluded.tone,
+		    custom_instructions = excluded.custom_instructions,
+		    timezone = excluded.timezone,
+		    updated_at = excluded.updated_at
+	`, r.userProfilesTable())
+	r.client.logQuery(query, p.UserID)
+
+	if _, err := r.client.backend.Exec(ctx, query,
+		p.UserID, p.DisplayName, p.Language, p.Tone, p.CustomInstructions, p.Timezone,
+	); err != nil {
+		return fmt.Errorf("failed to upsert user profile: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserProfile deletes a user's profile. Returns the number of rows
+// deleted (0 if the user has no profile).
+func (r *Repository) DeleteUserProfile(ctx context.Context, userID string) (int64, error) {
+	if err := r.checkTenant(ctx, "DeleteUserProfile"); err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, r.userProfilesTable())
+	r.client.logQuery(query, userID)
+
+	deleted, err := r.client.backend.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user profile: %w", err)
+	}
+	return deleted, nil
+}
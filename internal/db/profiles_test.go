@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserProfiles(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+
+	if p, err := repo.GetUserProfile(ctx, "user-1"); err != nil || p != nil {
+		t.Fatalf("GetUserProfile = %v, %v, want no profile", p, err)
+	}
+
+	for _, p := range []*UserProfile{
+		{UserID: "user-1", DisplayName: "Ada", Language: "German", Tone: "formal"},
+		{UserID: "user-1", DisplayName: "Ada", Tone: "friendly", CustomInstructions: "Sign off with my name.", Timezone: "Europe/Berlin"},
+	} {
+		if err := repo.UpsertUserProfile(ctx, p); err != nil {
+			t.Fatalf("UpsertUserProfile failed: %v", err)
+		}
+	}
+
+	got, err := repo.GetUserProfile(ctx, "user-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetUserProfile = %v, %v", got, err)
+	}
+	if got.Language != "" || got.Tone != "friendly" || got.CustomInstructions != "Sign off with my name." || got.Timezone != "Europe/Berlin" || got.CreatedAt.IsZero() {
+		t.Errorf("expected the second profile to replace the first, got %+v", got)
+	}
+
+	if deleted, err := repo.DeleteUserProfile(ctx, "user-1"); err != nil || deleted != 1 {
+		t.Errorf("DeleteUserProfile = %d, %v, want 1 row deleted", deleted, err)
+	}
+	if p, err := repo.GetUserProfile(ctx, "user-1"); err != nil || p != nil {
+		t.Errorf("GetUserProfile after delete = %v, %v, want no profile", p, err)
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// replicaRetryInterval is how long reads stay on the primary after a replica failure.
+const replicaRetryInterval = 30 * time.Second
+
+// replica routes read-heavy dashboard queries to a read replica, falling back
+// to the primary while the replica is failing. Chat-path reads stay on the
+// primary so they always see the latest writes.
+type replica struct {
+	backend backend
+	primary querier
+
+	mu        sync.Mutex
+	downUntil time.Time
+	now       func() time.Time
+}
+
+func newReplica(b backend, primary querier) *replica {
+	return &replica{backend: b, primary: primary, now: time.Now}
+}
+
+// available reports whether reads should be attempted on the replica.
+func (r *replica) available() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.now().Before(r.downUntil)
+}
+
+// markDown sends reads to the primary for replicaRetryInterval.
+func (r *replica) markDown(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downUntil = r.now().Add(replicaRetryInterval)
+	slog.Warn("read replica unavailable, using primary", "error", err, "retry_in", replicaRetryInterval)
+}
+
+// failed reports whether err indicates the replica itself is unhealthy, as
+// opposed to an empty result or a cancelled request.
+func (r *replica) failed(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, errNoRows) || ctx.Err() != nil {
+		return false
+	}
+	r.markDown(err)
+	return true
+}
+
+// reader returns the querier for read-heavy dashboard queries.
+func (c *Client) reader() querier {
+	if c.replica == nil || !c.replica.available() {
+		return c.backend
+	}
+	return replicaQuerier{c.replica}
+}
+
+// replicaQuerier runs reads on the replica and retries them on the primary
+// when the replica fails.
+type replicaQuerier struct {
+	r *replica
+}
+
+// Exec always runs on the primary; replicas are read-only.
+func (q replicaQuerier) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	return q.r.primary.Exec(ctx, query, args...)
+}
+
+func (q replicaQuerier) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	rows, err := q.r.backend.Query(ctx, query, args...)
+	if q.r.failed(ctx, err) {
+		return q.r.primary.Query(ctx, query, args...)
+	}
+	return rows, err
+}
+
+func (q replicaQuerier) QueryRow(ctx context.Context, query string, args ...any) row {
+	return replicaRow{
+		ctx:     ctx,
+		r:       q.r,
+		row:     q.r.backend.QueryRow(ctx, query, args...),
+		primary: func() row { return q.r.primary.QueryRow(ctx, query, args...) },
+	}
+}
+
+// replicaRow defers the fallback decision until Scan, where QueryRow errors surface.
+type replicaRow struct {
+	ctx     context.Context
+	r       *replica
+	row     row
+	primary func() row
+}
+
+func (rr replicaRow) Scan(dest ...any) error {
+	err := rr.row.Scan(dest...)
+	if rr.r.failed(rr.ctx, err) {
+		return rr.primary().Scan(dest...)
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newReplicatedClient builds a client whose primary and replica are separate
+// SQLite files, so tests can tell which one served a read.
+func newReplicatedClient(t *testing.T) (*Client, *sqliteBackend) {
+	t.Helper()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	primary, err := newSQLiteBackend(ctx, Config{URL: filepath.Join(dir, "primary.db")})
+	if err != nil {
+		t.Fatalf("failed to open primary: %v", err)
+	}
+	replicaBackend, err := newSQLiteBackend(ctx, Config{URL: filepath.Join(dir, "replica.db")})
+	if err != nil {
+		t.Fatalf("failed to open replica: %v", err)
+	}
+
+	client := &Client{
+		backend:     primary,
+		replica:     newReplica(replicaBackend, primary),
+		tenantRepos: make(map[string]*Repository),
+	}
+	t.Cleanup(client.Close)
+	return client, replicaBackend
+}
+
+func TestReplica_RoutesDashboardReads(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newReplicatedClient(t)
+	repo, _ := client.TenantRepository("ai8")
+
+	threadID := uuid.New()
+	if err := repo.PersistConversationTurn(ctx, threadID, "user-1", "hello", "hi", "openai", "gpt-4o", "", 1, 2, 3, 0.01); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+
+	// Writes and chat-path reads use the primary
+	thread, err := repo.GetThread(ctx, threadID)
+	if err != nil || thread == nil {
+		t.Fatalf("expected thread on primary, got %v, %v", thread, err)
+	}
+
+	// Dashboard reads use the (empty) replica
+	feed, err := repo.GetActivityFeed(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetActivityFeed failed: %v", err)
+	}
+	if len(feed) != 0 {
+		t.Errorf("expected activity feed to be served by replica, got %d entries", len(feed))
+	}
+	if _, err := repo.GetThreadConversation(ctx, threadID); err == nil {
+		t.Error("expected thread conversation to be served by replica")
+	}
+}
+
+func TestReplica_FallsBackToPrimary(t *testing.T) {
+	ctx := context.Background()
+	client, replicaBackend := newReplicatedClient(t)
+	repo, _ := client.TenantRepository("ai8")
+
+	threadID := uuid.New()
+	if err := repo.PersistConversationTurn(ctx, threadID, "user-1", "hello", "hi", "openai", "gpt-4o", "", 1, 2, 3, 0.01); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+
+	// Simulate the replica going away
+	replicaBackend.Close()
+
+	feed, err := repo.GetActivityFeed(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetActivityFeed failed: %v", err)
+	}
+	if len(feed) != 1 {
+		t.Errorf("expected fallback to primary, got %d entries", len(feed))
+	}
+	if client.replica.available() {
+		t.Error("expected replica to be marked down after failure")
+	}
+
+	// QueryRow failures fall back too
+	client.replica.downUntil = time.Time{}
+	conv, err := repo.GetThreadConversation(ctx, threadID)
+	if err != nil || len(conv.Messages) != 2 {
+		t.Fatalf("expected thread conversation from primary, got %v, %v", conv, err)
+	}
+
+	// The replica is retried once the interval has passed
+	now := time.Now()
+	client.replica.now = func() time.Time { return now.Add(replicaRetryInterval) }
+	if !client.replica.available() {
+		t.Error("expected replica to be retried after the retry interval")
+	}
+}
+
+func TestReplica_NotFoundDoesNotMarkDown(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newReplicatedClient(t)
+	repo, _ := client.TenantRepository("ai8")
+
+	if _, err := repo.GetDebugData(ctx, uuid.New()); err == nil {
+		t.Fatal("expected not found error")
+	}
+	if !client.replica.available() {
+		t.Error("an empty result must not mark the replica down")
+	}
+}
+
+func TestNewClient_SQLiteRejectsReplica(t *testing.T) {
+	_, err := NewClient(context.Background(), Config{
+		Driver:     DriverSQLite,
+		URL:        filepath.Join(t.TempDir(), "airborne.db"),
+		ReplicaURL: "postgres://replica/airborne",
+	})
+	if err == nil {
+		t.Fatal("expected error for sqlite with a read replica")
+	}
+}
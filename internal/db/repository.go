@@ -2,13 +2,16 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/ai8future/airborne/internal/isolation"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 )
 
 // ValidTenantIDs contains the list of valid tenant IDs.
@@ -23,6 +26,9 @@ var ErrInvalidTenant = errors.New("invalid tenant ID: must be 'ai8', 'email4ai',
 
 // Repository provides data access operations for threads and messages.
 // Each repository instance is scoped to a specific tenant's tables.
+// Dashboard reads, such as activity feeds, conversations, the usage ledger
+// and audit trails, are served from the read replica when one is
+// configured; reads on the chat path use the primary.
 type Repository struct {
 	client      *Client
 	tablePrefix string // "ai8_airborne" or "email4ai_airborne"
@@ -53,6 +59,31 @@ func (r *Repository) TenantID() string {
 	return r.tenantID
 }
 
+// checkTenant verifies that a tenant-scoped repository is used on behalf of
+// its own tenant. Legacy repositories have no tenant and are not checked.
+func (r *Repository) checkTenant(ctx context.Context, operation string) error {
+	if r.tenantID == "" {
+		return nil
+	}
+	return isolation.Check(ctx, isolation.LayerDB, operation, r.tenantID)
+}
+
+// checkTenantArg verifies an access to shared tables on behalf of tenantID,
+// where an empty tenantID means all tenants. A tenant-scoped repository only
+// serves its own tenant, and a request only its authenticated one.
+func (r *Repository) checkTenantArg(ctx context.Context, operation, tenantID string) error {
+	if r.tenantID != "" && tenantID != r.tenantID {
+		isolation.Report(isolation.Violation{
+			Layer:        isolation.LayerDB,
+			Operation:    operation,
+			TenantID:     r.tenantID,
+			TargetTenant: tenantID,
+		})
+		return fmt.Errorf("%w: %s %s", isolation.ErrCrossTenant, isolation.LayerDB, operation)
+	}
+	return isolation.Check(ctx, isolation.LayerDB, operation, tenantID)
+}
+
 // threadsTable returns the tenant-specific threads table name.
 func (r *Repository) threadsTable() string {
 	if r.tablePrefix == "" {
@@ -93,15 +124,26 @@ func (r *Repository) vectorStoresTable() string {
 	return r.tablePrefix + "_thread_vector_stores"
 }
 
+// memoriesTable returns the tenant-specific memories table name.
+func (r *Repository) memoriesTable() string {
+	if r.tablePrefix == "" {
+		return "airborne_memories" // Legacy table
+	}
+	return r.tablePrefix + "_memories"
+}
+
 // CreateThread inserts a new thread into the database.
 func (r *Repository) CreateThread(ctx context.Context, thread *Thread) error {
+	if err := r.checkTenant(ctx, "CreateThread"); err != nil {
+		return err
+	}
 	query := fmt.Sprintf(`
 		INSERT INTO %s (id, user_id, provider, model, status, message_count, created_at, updated_at, metadata)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`, r.threadsTable())
 	r.client.logQuery(query, thread.ID, thread.UserID)
 
-	_, err := r.client.pool.Exec(ctx, query,
+	_, err := r.client.backend.Exec(ctx, query,
 		thread.ID,
 		thread.UserID,
 		thread.Provider,
@@ -120,6 +162,9 @@ func (r *Repository) CreateThread(ctx context.Context, thread *Thread) error {
 
 // GetThread retrieves a thread by ID.
 func (r *Repository) GetThread(ctx context.Context, id uuid.UUID) (*Thread, error) {
+	if err := r.checkTenant(ctx, "GetThread"); err != nil {
+		return nil, err
+	}
 	query := fmt.Sprintf(`
 		SELECT id, user_id, provider, model, status, message_count, created_at, updated_at, metadata
 		FROM %s
@@ -128,7 +173,7 @@ func (r *Repository) GetThread(ctx context.Context, id uuid.UUID) (*Thread, erro
 	r.client.logQuery(query, id)
 
 	var thread Thread
-	err := r.client.pool.QueryRow(ctx, query, id).Scan(
+	err := r.client.backend.QueryRow(ctx, query, id).Scan(
 		&thread.ID,
 		&thread.UserID,
 		&thread.Provider,
@@ -140,7 +185,7 @@ func (r *Repository) GetThread(ctx context.Context, id uuid.UUID) (*Thread, erro
 		&thread.Metadata,
 	)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, errNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get thread: %w", err)
@@ -150,6 +195,9 @@ func (r *Repository) GetThread(ctx context.Context, id uuid.UUID) (*Thread, erro
 
 // UpdateThreadProvider updates the last-used provider and model for a thread.
 func (r *Repository) UpdateThreadProvider(ctx context.Context, threadID uuid.UUID, provider, model string) error {
+	if err := r.checkTenant(ctx, "UpdateThreadProvider"); err != nil {
+		return err
+	}
 	query := fmt.Sprintf(`
 		UPDATE %s
 		SET provider = $2, model = $3, updated_at = NOW()
@@ -157,7 +205,7 @@ func (r *Repository) UpdateThreadProvider(ctx context.Context, threadID uuid.UUI
 	`, r.threadsTable())
 	r.client.logQuery(query, threadID, provider, model)
 
-	_, err := r.client.pool.Exec(ctx, query, threadID, provider, model)
+	_, err := r.client.backend.Exec(ctx, query, threadID, provider, model)
 	if err != nil {
 		return fmt.Errorf("failed to update thread provider: %w", err)
 	}
@@ -166,17 +214,20 @@ func (r *Repository) UpdateThreadProvider(ctx context.Context, threadID uuid.UUI
 
 // CreateMessage inserts a new message into the database.
 func (r *Repository) CreateMessage(ctx context.Context, msg *Message) error {
+	if err := r.checkTenant(ctx, "CreateMessage"); err != nil {
+		return err
+	}
 	query := fmt.Sprintf(`
 		INSERT INTO %s (
 			id, thread_id, role, content, provider, model, response_id,
 			input_tokens, output_tokens, total_tokens, cost_usd,
 			processing_time_ms, citations, created_at, metadata,
-			system_prompt, raw_request_json, raw_response_json
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			system_prompt, raw_request_json, raw_response_json, key_id, parent_message_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 	`, r.messagesTable())
 	r.client.logQuery(query, msg.ID, msg.ThreadID, msg.Role)
 
-	_, err := r.client.pool.Exec(ctx, query,
+	_, err := r.client.backend.Exec(ctx, query,
 		msg.ID,
 		msg.ThreadID,
 		msg.Role,
@@ -195,6 +246,8 @@ func (r *Repository) CreateMessage(ctx context.Context, msg *Message) error {
 		msg.SystemPrompt,
 		msg.RawRequestJSON,
 		msg.RawResponseJSON,
+		msg.KeyID,
+		msg.ParentMessageID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
@@ -204,54 +257,318 @@ func (r *Repository) CreateMessage(ctx context.Context, msg *Message) error {
 
 // GetMessages retrieves messages for a thread, ordered chronologically.
 func (r *Repository) GetMessages(ctx context.Context, threadID uuid.UUID, limit int) ([]Message, error) {
+	if err := r.checkTenant(ctx, "GetMessages"); err != nil {
+		return nil, err
+	}
 	query := fmt.Sprintf(`
 		SELECT id, thread_id, role, content, provider, model, response_id,
 		       input_tokens, output_tokens, total_tokens, cost_usd,
-		       processing_time_ms, citations, created_at, metadata
+		       processing_time_ms, citations, created_at, metadata, system_prompt,
+		       parent_message_id, superseded_at
 		FROM %s
-		WHERE thread_id = $1
+		WHERE thread_id = $1 AND superseded_at IS NULL
 		ORDER BY created_at ASC
 		LIMIT $2
 	`, r.messagesTable())
 	r.client.logQuery(query, threadID, limit)
 
-	rows, err := r.client.pool.Query(ctx, query, threadID, limit)
+	rows, err := r.client.backend.Query(ctx, query, threadID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
 	defer rows.Close()
 
-	var messages []Message
+	return scanMessages(rows)
+}
+
+// GetMessage retrieves a message by ID, or nil if it does not exist.
+func (r *Repository) GetMessage(ctx context.Context, id uuid.UUID) (*Message, error) {
+	if err := r.checkTenant(ctx, "GetMessage"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT id, thread_id, role, content, provider, model, response_id,
+		       input_tokens, output_tokens, total_tokens, cost_usd,
+		       processing_time_ms, citations, created_at, metadata, system_prompt,
+		       parent_message_id, superseded_at
+		FROM %s
+		WHERE id = $1
+	`, r.messagesTable())
+	r.client.logQuery(query, id)
+
+	rows, err := r.client.backend.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	defer rows.Close()
+
+	messages, err := scanMessages(rows)
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// GetActiveMessages retrieves every message on a thread's current branch,
+// skipping superseded messages. Messages are in conversation order: a turn's
+// user and assistant messages share a timestamp, so ties put the user first.
+func (r *Repository) GetActiveMessages(ctx context.Context, threadID uuid.UUID) ([]Message, error) {
+	if err := r.checkTenant(ctx, "GetActiveMessages"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT id, thread_id, role, content, provider, model, response_id,
+		       input_tokens, output_tokens, total_tokens, cost_usd,
+		       processing_time_ms, citations, created_at, metadata, system_prompt,
+		       parent_message_id, superseded_at
+		FROM %s
+		WHERE thread_id = $1 AND superseded_at IS NULL
+		ORDER BY created_at ASC, CASE WHEN role = 'user' THEN 0 ELSE 1 END, id ASC
+	`, r.messagesTable())
+	r.client.logQuery(query, threadID)
+
+	rows, err := r.client.backend.Query(ctx, query, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMessages(rows)
+}
+
+// SupersedeMessages marks messages of a thread as replaced by a regenerated
+// turn. They are kept for debugging and usage accounting, but conversation
+// reads skip them and the thread's message count no longer includes them.
+// Messages already superseded are left alone. It returns how many messages
+// were marked.
+func (r *Repository) SupersedeMessages(ctx context.Context, threadID uuid.UUID, messageIDs []uuid.UUID) (int64, error) {
+	if err := r.checkTenant(ctx, "SupersedeMessages"); err != nil {
+		return 0, err
+	}
+	if len(messageIDs) == 0 {
+		return 0, nil
+	}
+	args := []any{threadID}
+	placeholders := make([]string, len(messageIDs))
+	for i, id := range messageIDs {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+
+	tx, err := r.client.backend.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET superseded_at = NOW()
+		WHERE thread_id = $1 AND superseded_at IS NULL AND id IN (%s)
+	`, r.messagesTable(), strings.Join(placeholders, ", "))
+	r.client.logQuery(query, args...)
+
+	superseded, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to supersede messages: %w", err)
+	}
+
+	countQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET message_count = message_count - $2, updated_at = NOW()
+		WHERE id = $1
+	`, r.threadsTable())
+	if _, err := tx.Exec(ctx, countQuery, threadID, superseded); err != nil {
+		return 0, fmt.Errorf("failed to update thread message count: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return superseded, nil
+}
+
+// GetBranches retrieves the alternatives at a message: the messages of its
+// thread with the same role and parent, oldest first, including the message
+// itself. Replies regenerated from one user message are alternatives to each
+// other, as are edits of one user message. It returns nil if the message
+// does not exist.
+func (r *Repository) GetBranches(ctx context.Context, messageID uuid.UUID) ([]Message, error) {
+	msg, err := r.GetMessage(ctx, messageID)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	args := []any{msg.ThreadID, msg.Role}
+	parentCond := "parent_message_id IS NULL"
+	if msg.ParentMessageID != nil {
+		args = append(args, *msg.ParentMessageID)
+		parentCond = "parent_message_id = $3"
+	}
+	query := fmt.Sprintf(`
+		SELECT id, thread_id, role, content, provider, model, response_id,
+		       input_tokens, output_tokens, total_tokens, cost_usd,
+		       processing_time_ms, citations, created_at, metadata, system_prompt,
+		       parent_message_id, superseded_at
+		FROM %s
+		WHERE thread_id = $1 AND role = $2 AND %s
+		ORDER BY created_at ASC, id ASC
+	`, r.messagesTable(), parentCond)
+	r.client.logQuery(query, args...)
+
+	rows, err := r.client.backend.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMessages(rows)
+}
+
+// branchNode is a message's place in a thread's message tree.
+type branchNode struct {
+	id           uuid.UUID
+	parentID     *uuid.UUID
+	supersededAt *time.Time
+}
+
+// SelectBranch makes the branch through messageID the thread's current one.
+// The branch runs from the thread's first message down to messageID and on
+// through its replies, following at each step the reply that was current
+// most recently, and the newest on a tie. Messages on the branch are
+// restored and every other message is superseded. It returns the branch's
+// message IDs in conversation order, or nil if messageID is not in the
+// thread.
+func (r *Repository) SelectBranch(ctx context.Context, threadID, messageID uuid.UUID) ([]uuid.UUID, error) {
+	if err := r.checkTenant(ctx, "SelectBranch"); err != nil {
+		return nil, err
+	}
+	tx, err := r.client.backend.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		SELECT id, parent_message_id, superseded_at
+		FROM %s
+		WHERE thread_id = $1
+		ORDER BY created_at ASC, CASE WHEN role = 'user' THEN 0 ELSE 1 END, id ASC
+	`, r.messagesTable())
+	r.client.logQuery(query, threadID)
+
+	rows, err := tx.Query(ctx, query, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread messages: %w", err)
+	}
+	nodes := make(map[uuid.UUID]*branchNode)
+	children := make(map[uuid.UUID][]*branchNode) // Keyed by parent; uuid.Nil holds the roots
 	for rows.Next() {
-		var msg Message
-		err := rows.Scan(
-			&msg.ID,
-			&msg.ThreadID,
-			&msg.Role,
-			&msg.Content,
-			&msg.Provider,
-			&msg.Model,
-			&msg.ResponseID,
-			&msg.InputTokens,
-			&msg.OutputTokens,
-			&msg.TotalTokens,
-			&msg.CostUSD,
-			&msg.ProcessingTimeMs,
-			&msg.Citations,
-			&msg.CreatedAt,
-			&msg.Metadata,
-		)
-		if err != nil {
+		n := &branchNode{}
+		if err := rows.Scan(&n.id, &n.parentID, &n.supersededAt); err != nil {
+			rows.Close()
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
-		messages = append(messages, msg)
+		nodes[n.id] = n
+		var parent uuid.UUID
+		if n.parentID != nil {
+			parent = *n.parentID
+		}
+		children[parent] = append(children[parent], n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read thread messages: %w", err)
+	}
+
+	selected, ok := nodes[messageID]
+	if !ok {
+		return nil, nil
+	}
+	// Walk up to the root; onPath also guards against cycles
+	onPath := map[uuid.UUID]bool{selected.id: true}
+	path := []uuid.UUID{selected.id}
+	for n := selected; n.parentID != nil; {
+		parent, ok := nodes[*n.parentID]
+		if !ok || onPath[parent.id] {
+			break
+		}
+		onPath[parent.id] = true
+		path = append(path, parent.id)
+		n = parent
+	}
+	slices.Reverse(path)
+	// Walk down through the most recently current replies
+	for n := selected; ; {
+		var next *branchNode
+		for _, c := range children[n.id] {
+			if !onPath[c.id] && (next == nil || branchPreferred(c, next)) {
+				next = c
+			}
+		}
+		if next == nil {
+			break
+		}
+		onPath[next.id] = true
+		path = append(path, next.id)
+		n = next
+	}
+
+	args := []any{threadID}
+	placeholders := make([]string, len(path))
+	for i, id := range path {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+	restoreQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET superseded_at = NULL
+		WHERE thread_id = $1 AND superseded_at IS NOT NULL AND id IN (%s)
+	`, r.messagesTable(), strings.Join(placeholders, ", "))
+	if _, err := tx.Exec(ctx, restoreQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to restore branch: %w", err)
+	}
+	supersedeQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET superseded_at = NOW()
+		WHERE thread_id = $1 AND superseded_at IS NULL AND id NOT IN (%s)
+	`, r.messagesTable(), strings.Join(placeholders, ", "))
+	if _, err := tx.Exec(ctx, supersedeQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to supersede other branches: %w", err)
+	}
+	countQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET message_count = $2, updated_at = NOW()
+		WHERE id = $1
+	`, r.threadsTable())
+	if _, err := tx.Exec(ctx, countQuery, threadID, len(path)); err != nil {
+		return nil, fmt.Errorf("failed to update thread message count: %w", err)
 	}
-	return messages, nil
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return path, nil
+}
+
+// branchPreferred reports whether a reply should be followed over another
+// when extending a selected branch: a current reply first, then the one
+// superseded last. a is the newer reply, so ties go to it.
+func branchPreferred(a, b *branchNode) bool {
+	switch {
+	case a.supersededAt == nil:
+		return true
+	case b.supersededAt == nil:
+		return false
+	}
+	return !a.supersededAt.Before(*b.supersededAt)
 }
 
 // GetActivityFeed retrieves the latest assistant messages for the activity dashboard.
 // This queries the tenant-specific tables.
 func (r *Repository) GetActivityFeed(ctx context.Context, limit int) ([]ActivityEntry, error) {
+	if err := r.checkTenant(ctx, "GetActivityFeed"); err != nil {
+		return nil, err
+	}
 	query := fmt.Sprintf(`
 		SELECT
 			m.id,
@@ -281,7 +598,7 @@ func (r *Repository) GetActivityFeed(ctx context.Context, limit int) ([]Activity
 	`, r.messagesTable(), r.messagesTable(), r.threadsTable())
 	r.client.logQuery(query, limit)
 
-	rows, err := r.client.pool.Query(ctx, query, limit)
+	rows, err := r.client.reader().Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get activity feed: %w", err)
 	}
@@ -350,7 +667,7 @@ func (r *Repository) GetActivityFeedAllTenants(ctx context.Context, limit int) (
 			COALESCE(m.grounding_queries, 0) as grounding_queries,
 			COALESCE(m.grounding_cost_usd, 0) as grounding_cost_usd,
 			COALESCE(m.processing_time_ms, 0) as processing_time_ms,
-			m.created_at,
+			m.created_at AS created_at,
 			(
 				SELECT COALESCE(SUM(cost_usd), 0)
 				FROM ai8_airborne_messages
@@ -377,7 +694,7 @@ func (r *Repository) GetActivityFeedAllTenants(ctx context.Context, limit int) (
 			COALESCE(m.grounding_queries, 0) as grounding_queries,
 			COALESCE(m.grounding_cost_usd, 0) as grounding_cost_usd,
 			COALESCE(m.processing_time_ms, 0) as processing_time_ms,
-			m.created_at,
+			m.created_at AS created_at,
 			(
 				SELECT COALESCE(SUM(cost_usd), 0)
 				FROM email4ai_airborne_messages
@@ -404,7 +721,7 @@ func (r *Repository) GetActivityFeedAllTenants(ctx context.Context, limit int) (
 			COALESCE(m.grounding_queries, 0) as grounding_queries,
 			COALESCE(m.grounding_cost_usd, 0) as grounding_cost_usd,
 			COALESCE(m.processing_time_ms, 0) as processing_time_ms,
-			m.created_at,
+			m.created_at AS created_at,
 			(
 				SELECT COALESCE(SUM(cost_usd), 0)
 				FROM zztest_airborne_messages
@@ -419,7 +736,7 @@ func (r *Repository) GetActivityFeedAllTenants(ctx context.Context, limit int) (
 	`
 	r.client.logQuery(query, limit)
 
-	rows, err := r.client.pool.Query(ctx, query, limit)
+	rows, err := r.client.reader().Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get activity feed (all tenants): %w", err)
 	}
@@ -489,18 +806,77 @@ type DebugInfo struct {
 	RawRequestJSON  string
 	RawResponseJSON string
 	RenderedHTML    string
+
+	// FinalSystemPrompt and FinalUserPrompt are the prompts exactly as the
+	// provider assembled them. Stored in the message metadata.
+	FinalSystemPrompt string
+	FinalUserPrompt   string
+
+	// ValidationAttempts lists replies rejected by the tenant's validation
+	// rules before this one. Stored in the message metadata.
+	ValidationAttempts []ValidationAttempt
+
+	// KeyID is the hashed ID of the client API key that made the request,
+	// used for per-key usage breakdowns. Empty for static-token auth.
+	KeyID string
+
+	// TraceID is the server-side trace ID of the request, so support can
+	// find its logs. Stored in the message metadata.
+	TraceID string
+}
+
+// messageMetadata is the JSON stored in an assistant message's metadata column.
+type messageMetadata struct {
+	ValidationAttempts []ValidationAttempt `json:"validation_attempts,omitempty"`
+	FinalSystemPrompt  string              `json:"final_system_prompt,omitempty"`
+	FinalUserPrompt    string              `json:"final_user_prompt,omitempty"`
+	TraceID            string              `json:"trace_id,omitempty"`
+}
+
+// FinalPromptMetadata returns the metadata JSON recording the prompts as the
+// provider assembled them, for messages saved with CreateMessage. It returns
+// nil when both are empty.
+func FinalPromptMetadata(systemPrompt, userPrompt string) *string {
+	if systemPrompt == "" && userPrompt == "" {
+		return nil
+	}
+	data, err := json.Marshal(messageMetadata{FinalSystemPrompt: systemPrompt, FinalUserPrompt: userPrompt})
+	if err != nil {
+		slog.Warn("failed to serialize message metadata", "error", err)
+		return nil
+	}
+	str := string(data)
+	return &str
 }
 
 // PersistConversationTurn saves both user and assistant messages in a transaction.
 // This is the main entry point for chat service persistence.
 // Note: tenantID parameter is no longer needed - the repository is already scoped to a tenant.
 func (r *Repository) PersistConversationTurn(ctx context.Context, threadID uuid.UUID, userID string, userContent, assistantContent, provider, model, responseID string, inputTokens, outputTokens, processingTimeMs int, costUSD float64) error {
-	return r.PersistConversationTurnWithDebug(ctx, threadID, userID, userContent, assistantContent, provider, model, responseID, inputTokens, outputTokens, processingTimeMs, costUSD, 0, 0, nil, nil)
+	return r.PersistConversationTurnWithDebug(ctx, threadID, userID, userContent, assistantContent, provider, model, responseID, inputTokens, outputTokens, processingTimeMs, costUSD, 0, 0, nil, nil, nil)
+}
+
+// TurnPlacement places a conversation turn in a thread's message tree other
+// than at the end of the current branch. It is used to branch a thread.
+type TurnPlacement struct {
+	// ParentID is the parent of the new user message; uuid.Nil starts a new
+	// root, as when the thread's first message is edited.
+	ParentID uuid.UUID
+	// UserMessageID, when set, is an existing user message the reply answers
+	// instead of inserting a new one. The reply becomes an alternative to the
+	// message's other replies. ParentID is then ignored.
+	UserMessageID uuid.UUID
+	// ReplyID is the ID of the assistant message; generated when uuid.Nil.
+	ReplyID uuid.UUID
 }
 
 // PersistConversationTurnWithDebug saves both user and assistant messages with optional debug data and citations.
-func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threadID uuid.UUID, userID string, userContent, assistantContent, provider, model, responseID string, inputTokens, outputTokens, processingTimeMs int, costUSD float64, groundingQueries int, groundingCostUSD float64, debug *DebugInfo, citations []Citation) error {
-	tx, err := r.client.pool.Begin(ctx)
+// A nil placement continues the thread's current branch.
+func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threadID uuid.UUID, userID string, userContent, assistantContent, provider, model, responseID string, inputTokens, outputTokens, processingTimeMs int, costUSD float64, groundingQueries int, groundingCostUSD float64, debug *DebugInfo, citations []Citation, placement *TurnPlacement) error {
+	if err := r.checkTenant(ctx, "PersistConversationTurnWithDebug"); err != nil {
+		return err
+	}
+	tx, err := r.client.backend.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -527,23 +903,53 @@ func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threa
 		slog.Debug("created new thread", "thread_id", threadID, "tenant", r.tenantID)
 	}
 
-	// Insert user message
+	// Insert user message, by default after the last message of the current branch
 	userMsgID := uuid.New()
-	userInsertQuery := fmt.Sprintf(`
-		INSERT INTO %s (id, thread_id, role, content, created_at)
-		VALUES ($1, $2, 'user', $3, NOW())
-	`, r.messagesTable())
-	_, err = tx.Exec(ctx, userInsertQuery, userMsgID, threadID, userContent)
-	if err != nil {
-		return fmt.Errorf("failed to insert user message: %w", err)
+	var userParentID *uuid.UUID
+	switch {
+	case placement == nil:
+		var tail uuid.UUID
+		tailQuery := fmt.Sprintf(`
+			SELECT id FROM %s
+			WHERE thread_id = $1 AND superseded_at IS NULL
+			ORDER BY created_at DESC, CASE WHEN role = 'user' THEN 0 ELSE 1 END DESC, id DESC
+			LIMIT 1
+		`, r.messagesTable())
+		err = tx.QueryRow(ctx, tailQuery, threadID).Scan(&tail)
+		if err != nil && !errors.Is(err, errNoRows) {
+			return fmt.Errorf("failed to find last thread message: %w", err)
+		}
+		if err == nil {
+			userParentID = &tail
+		}
+	case placement.UserMessageID != uuid.Nil:
+		userMsgID = placement.UserMessageID
+	case placement.ParentID != uuid.Nil:
+		userParentID = &placement.ParentID
+	}
+	if placement == nil || placement.UserMessageID == uuid.Nil {
+		userInsertQuery := fmt.Sprintf(`
+			INSERT INTO %s (id, thread_id, role, content, created_at, parent_message_id)
+			VALUES ($1, $2, 'user', $3, NOW(), $4)
+		`, r.messagesTable())
+		_, err = tx.Exec(ctx, userInsertQuery, userMsgID, threadID, userContent, userParentID)
+		if err != nil {
+			return fmt.Errorf("failed to insert user message: %w", err)
+		}
 	}
 
 	// Insert assistant message with full metrics and optional debug data
 	assistantMsgID := uuid.New()
+	if placement != nil && placement.ReplyID != uuid.Nil {
+		assistantMsgID = placement.ReplyID
+	}
 	totalTokens := inputTokens + outputTokens
 
-	var systemPrompt, rawReqJSON, rawRespJSON, renderedHTML *string
+	var systemPrompt, rawReqJSON, rawRespJSON, renderedHTML, metadata, keyID *string
 	if debug != nil {
+		if debug.KeyID != "" {
+			keyID = &debug.KeyID
+		}
 		if debug.SystemPrompt != "" {
 			systemPrompt = &debug.SystemPrompt
 		}
@@ -556,6 +962,20 @@ func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threa
 		if debug.RenderedHTML != "" {
 			renderedHTML = &debug.RenderedHTML
 		}
+		if len(debug.ValidationAttempts) > 0 || debug.FinalSystemPrompt != "" || debug.FinalUserPrompt != "" || debug.TraceID != "" {
+			data, err := json.Marshal(messageMetadata{
+				ValidationAttempts: debug.ValidationAttempts,
+				FinalSystemPrompt:  debug.FinalSystemPrompt,
+				FinalUserPrompt:    debug.FinalUserPrompt,
+				TraceID:            debug.TraceID,
+			})
+			if err != nil {
+				slog.Warn("failed to serialize message metadata", "error", err)
+			} else {
+				str := string(data)
+				metadata = &str
+			}
+		}
 	}
 
 	// Serialize citations to JSON
@@ -570,13 +990,13 @@ func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threa
 			id, thread_id, role, content, provider, model, response_id,
 			input_tokens, output_tokens, total_tokens, cost_usd, processing_time_ms, created_at,
 			system_prompt, raw_request_json, raw_response_json, rendered_html, citations,
-			grounding_queries, grounding_cost_usd
-		) VALUES ($1, $2, 'assistant', $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), $12, $13, $14, $15, $16, $17, $18)
+			grounding_queries, grounding_cost_usd, metadata, key_id, parent_message_id
+		) VALUES ($1, $2, 'assistant', $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 	`, r.messagesTable())
 	_, err = tx.Exec(ctx, assistantInsertQuery, assistantMsgID, threadID, assistantContent, provider, model, responseID,
 		inputTokens, outputTokens, totalTokens, costUSD, processingTimeMs,
 		systemPrompt, rawReqJSON, rawRespJSON, renderedHTML, citationsJSON,
-		groundingQueries, groundingCostUSD)
+		groundingQueries, groundingCostUSD, metadata, keyID, userMsgID)
 	if err != nil {
 		return fmt.Errorf("failed to insert assistant message: %w", err)
 	}
@@ -592,6 +1012,39 @@ func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threa
 		return fmt.Errorf("failed to update thread provider: %w", err)
 	}
 
+	// Record the completion in the outbox atomically with the turn itself
+	if r.client.outbox {
+		status := "success"
+		if strings.HasPrefix(assistantContent, "[FAILED] ") {
+			status = "failed"
+		}
+		var eventKeyID string
+		if keyID != nil {
+			eventKeyID = *keyID
+		}
+		err = r.enqueueEvent(ctx, tx, EventGenerationCompleted, GenerationEvent{
+			TenantID:         r.tenantID,
+			ThreadID:         threadID,
+			MessageID:        assistantMsgID,
+			UserID:           userID,
+			KeyID:            eventKeyID,
+			Provider:         provider,
+			Model:            model,
+			Status:           status,
+			InputTokens:      inputTokens,
+			OutputTokens:     outputTokens,
+			TotalTokens:      totalTokens,
+			CostUSD:          costUSD,
+			GroundingQueries: groundingQueries,
+			GroundingCostUSD: groundingCostUSD,
+			ProcessingTimeMs: processingTimeMs,
+			CompletedAt:      time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -609,6 +1062,9 @@ func (r *Repository) PersistConversationTurnWithDebug(ctx context.Context, threa
 
 // GetDebugData retrieves the full request/response debug data for a message.
 func (r *Repository) GetDebugData(ctx context.Context, messageID uuid.UUID) (*DebugData, error) {
+	if err := r.checkTenant(ctx, "GetDebugData"); err != nil {
+		return nil, err
+	}
 	query := fmt.Sprintf(`
 		SELECT
 			m.id,
@@ -626,10 +1082,11 @@ func (r *Repository) GetDebugData(ctx context.Context, messageID uuid.UUID) (*De
 			COALESCE(m.grounding_cost_usd, 0) as grounding_cost_usd,
 			COALESCE(m.processing_time_ms, 0) as duration_ms,
 			COALESCE(m.response_id, '') as response_id,
-			COALESCE(m.citations::text, '') as citations,
-			COALESCE(m.raw_request_json::text, '') as raw_request_json,
-			COALESCE(m.raw_response_json::text, '') as raw_response_json,
+			COALESCE(CAST(m.citations AS TEXT), '') as citations,
+			COALESCE(CAST(m.raw_request_json AS TEXT), '') as raw_request_json,
+			COALESCE(CAST(m.raw_response_json AS TEXT), '') as raw_response_json,
 			COALESCE(m.rendered_html, '') as rendered_html,
+			COALESCE(CAST(m.metadata AS TEXT), '') as metadata,
 			(
 				SELECT COALESCE(content, '')
 				FROM %s
@@ -647,7 +1104,8 @@ func (r *Repository) GetDebugData(ctx context.Context, messageID uuid.UUID) (*De
 
 	var data DebugData
 	var userInput *string
-	err := r.client.pool.QueryRow(ctx, query, messageID).Scan(
+	var metadata string
+	err := r.client.reader().QueryRow(ctx, query, messageID).Scan(
 		&data.MessageID,
 		&data.ThreadID,
 		&data.UserID,
@@ -667,10 +1125,11 @@ func (r *Repository) GetDebugData(ctx context.Context, messageID uuid.UUID) (*De
 		&data.RawRequestJSON,
 		&data.RawResponseJSON,
 		&data.RenderedHTML,
+		&metadata,
 		&userInput,
 	)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, errNoRows) {
 			return nil, fmt.Errorf("message not found")
 		}
 		return nil, fmt.Errorf("failed to get debug data: %w", err)
@@ -686,6 +1145,16 @@ func (r *Repository) GetDebugData(ctx context.Context, messageID uuid.UUID) (*De
 	if userInput != nil {
 		data.UserInput = *userInput
 	}
+	if metadata != "" {
+		var meta messageMetadata
+		if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+			slog.Warn("failed to parse message metadata", "message_id", messageID, "error", err)
+		}
+		data.ValidationAttempts = meta.ValidationAttempts
+		data.FinalSystemPrompt = meta.FinalSystemPrompt
+		data.FinalUserPrompt = meta.FinalUserPrompt
+		data.TraceID = meta.TraceID
+	}
 
 	return &data, nil
 }
@@ -729,6 +1198,9 @@ func (r *Repository) GetOrCreateThread(ctx context.Context, threadID uuid.UUID,
 
 // GetThreadConversation retrieves complete thread data with all messages for conversation view.
 func (r *Repository) GetThreadConversation(ctx context.Context, threadID uuid.UUID) (*ThreadConversation, error) {
+	if err := r.checkTenant(ctx, "GetThreadConversation"); err != nil {
+		return nil, err
+	}
 	// First get thread info
 	threadQuery := fmt.Sprintf(`
 		SELECT id, user_id, COALESCE(provider, '') as provider, COALESCE(model, '') as model,
@@ -739,7 +1211,7 @@ func (r *Repository) GetThreadConversation(ctx context.Context, threadID uuid.UU
 	r.client.logQuery(threadQuery, threadID)
 
 	var conv ThreadConversation
-	err := r.client.pool.QueryRow(ctx, threadQuery, threadID).Scan(
+	err := r.client.reader().QueryRow(ctx, threadQuery, threadID).Scan(
 		&conv.ThreadID,
 		&conv.UserID,
 		&conv.Provider,
@@ -749,7 +1221,7 @@ func (r *Repository) GetThreadConversation(ctx context.Context, threadID uuid.UU
 		&conv.UpdatedAt,
 	)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, errNoRows) {
 			return nil, fmt.Errorf("thread not found")
 		}
 		return nil, fmt.Errorf("failed to get thread: %w", err)
@@ -761,14 +1233,15 @@ func (r *Repository) GetThreadConversation(ctx context.Context, threadID uuid.UU
 	// Get all messages in chronological order
 	messagesQuery := fmt.Sprintf(`
 		SELECT id, role, content, COALESCE(rendered_html, '') as rendered_html,
-		       COALESCE(model, '') as model, COALESCE(provider, '') as provider, created_at
+		       COALESCE(model, '') as model, COALESCE(provider, '') as provider,
+		       COALESCE(CAST(citations AS TEXT), '') as citations, created_at
 		FROM %s
-		WHERE thread_id = $1
-		ORDER BY created_at ASC
+		WHERE thread_id = $1 AND superseded_at IS NULL
+		ORDER BY created_at ASC, CASE WHEN role = 'user' THEN 0 ELSE 1 END
 	`, r.messagesTable())
 	r.client.logQuery(messagesQuery, threadID)
 
-	rows, err := r.client.pool.Query(ctx, messagesQuery, threadID)
+	rows, err := r.client.reader().Query(ctx, messagesQuery, threadID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
@@ -776,6 +1249,7 @@ func (r *Repository) GetThreadConversation(ctx context.Context, threadID uuid.UU
 
 	for rows.Next() {
 		var msg ConversationMessage
+		var citations string
 		err := rows.Scan(
 			&msg.ID,
 			&msg.Role,
@@ -783,11 +1257,15 @@ func (r *Repository) GetThreadConversation(ctx context.Context, threadID uuid.UU
 			&msg.RenderedHTML,
 			&msg.Model,
 			&msg.Provider,
+			&citations,
 			&msg.Timestamp,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
+		if msg.Citations, err = ParseCitations(&citations); err != nil {
+			slog.Warn("failed to parse message citations", "message_id", msg.ID, "error", err)
+		}
 		conv.Messages = append(conv.Messages, msg)
 	}
 
@@ -810,3 +1288,100 @@ func (r *Repository) GetThreadConversationAllTenants(ctx context.Context, thread
 	}
 	return nil, fmt.Errorf("thread not found in any tenant")
 }
+
+// UpsertMemory stores a memory fact for a user. If the same fact already exists
+// for the user, only its updated_at timestamp is refreshed.
+func (r *Repository) UpsertMemory(ctx context.Context, mem *Memory) error {
+	if err := r.checkTenant(ctx, "UpsertMemory"); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, user_id, category, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, content) DO UPDATE
+		SET category = EXCLUDED.category, updated_at = EXCLUDED.updated_at
+	`, r.memoriesTable())
+	r.client.logQuery(query, mem.ID, mem.UserID, mem.Category)
+
+	_, err := r.client.backend.Exec(ctx, query,
+		mem.ID,
+		mem.UserID,
+		mem.Category,
+		mem.Content,
+		mem.CreatedAt,
+		mem.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert memory: %w", err)
+	}
+	return nil
+}
+
+// ListMemories retrieves memory facts for a user, most recently observed first.
+func (r *Repository) ListMemories(ctx context.Context, userID string, limit int) ([]Memory, error) {
+	if err := r.checkTenant(ctx, "ListMemories"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT id, user_id, category, content, created_at, updated_at
+		FROM %s
+		WHERE user_id = $1
+		ORDER BY updated_at DESC
+		LIMIT $2
+	`, r.memoriesTable())
+	r.client.logQuery(query, userID, limit)
+
+	rows, err := r.client.backend.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []Memory
+	for rows.Next() {
+		var mem Memory
+		if err := rows.Scan(
+			&mem.ID,
+			&mem.UserID,
+			&mem.Category,
+			&mem.Content,
+			&mem.CreatedAt,
+			&mem.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan memory: %w", err)
+		}
+		memories = append(memories, mem)
+	}
+	return memories, nil
+}
+
+// DeleteMemory deletes a single memory fact belonging to a user.
+// Returns the number of rows deleted (0 if the fact does not exist).
+func (r *Repository) DeleteMemory(ctx context.Context, userID string, id uuid.UUID) (int64, error) {
+	if err := r.checkTenant(ctx, "DeleteMemory"); err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND user_id = $2`, r.memoriesTable())
+	r.client.logQuery(query, id, userID)
+
+	deleted, err := r.client.backend.Exec(ctx, query, id, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete memory: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteUserMemories deletes all memory facts for a user.
+func (r *Repository) DeleteUserMemories(ctx context.Context, userID string) (int64, error) {
+	if err := r.checkTenant(ctx, "DeleteUserMemories"); err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, r.memoriesTable())
+	r.client.logQuery(query, userID)
+
+	deleted, err := r.client.backend.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user memories: %w", err)
+	}
+	return deleted, nil
+}
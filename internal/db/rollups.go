@@ -0,0 +1,368 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rollup granularities.
+const (
+	RollupHourly = "hour"
+	RollupDaily  = "day"
+)
+
+// rollupsTable is shared across tenants; rows are keyed by tenant_id.
+const rollupsTable = "airborne_activity_rollups"
+
+// ActivityRollup is aggregated usage for one tenant/provider/model/key in a time bucket.
+type ActivityRollup struct {
+	Granularity      string    `json:"granularity"`
+	BucketStart      time.Time `json:"bucket_start"`
+	TenantID         string    `json:"tenant"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	KeyID            string    `json:"key_id,omitempty"` // Hashed client API key ID; empty for unattributed usage
+	RequestCount     int64     `json:"request_count"`
+	FailedCount      int64     `json:"failed_count"`
+	InputTokens      int64     `json:"input_tokens"`
+	OutputTokens     int64     `json:"output_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	GroundingQueries int64     `json:"grounding_queries"`
+	GroundingCostUSD float64   `json:"grounding_cost_usd"`
+	ProcessingTimeMs int64     `json:"processing_time_ms"` // Sum across requests
+}
+
+// rollupKey identifies a rollup row.
+type rollupKey struct {
+	bucket   time.Time
+	tenantID string
+	provider string
+	model    string
+	keyID    string
+}
+
+// add accumulates another rollup's totals.
+func (a *ActivityRollup) add(b ActivityRollup) {
+	a.RequestCount += b.RequestCount
+	a.FailedCount += b.FailedCount
+	a.InputTokens += b.InputTokens
+	a.OutputTokens += b.OutputTokens
+	a.CostUSD += b.CostUSD
+	a.GroundingQueries += b.GroundingQueries
+	a.GroundingCostUSD += b.GroundingCostUSD
+	a.ProcessingTimeMs += b.ProcessingTimeMs
+}
+
+// RollupBucket returns the UTC start of the bucket containing t.
+func RollupBucket(granularity string, t time.Time) time.Time {
+	t = t.UTC()
+	if granularity == RollupDaily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// RefreshActivityRollups recomputes hourly rollups for every tenant from the
+// hour containing since, then the daily rollups for the affected days.
+// Buckets are rebuilt rather than incremented, so refreshing is idempotent.
+func (r *Repository) RefreshActivityRollups(ctx context.Context, since time.Time) error {
+	hourStart := RollupBucket(RollupHourly, since)
+	dayStart := RollupBucket(RollupDaily, since)
+
+	hourly := make(map[rollupKey]*ActivityRollup)
+	for _, tenantID := range sortedTenantIDs() {
+		if err := r.aggregateTenantMessages(ctx, tenantID, hourStart, hourly); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.client.backend.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceRollups(ctx, tx, RollupHourly, hourStart, hourly); err != nil {
+		return err
+	}
+
+	// Daily buckets are summed from the hourly rollups, including hours before since
+	daily, err := dailyFromHourly(ctx, tx, dayStart)
+	if err != nil {
+		return err
+	}
+	if err := replaceRollups(ctx, tx, RollupDaily, dayStart, daily); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit rollups: %w", err)
+	}
+	return nil
+}
+
+// aggregateTenantMessages adds one tenant's assistant messages since start to hourly.
+func (r *Repository) aggregateTenantMessages(ctx context.Context, tenantID string, start time.Time, hourly map[rollupKey]*ActivityRollup) error {
+	query := fmt.Sprintf(`
+		SELECT
+			m.created_at,
+			m.content,
+			COALESCE(m.provider, '') as provider,
+			COALESCE(m.model, '') as model,
+			COALESCE(m.input_tokens, 0) as input_tokens,
+			COALESCE(m.output_tokens, 0) as output_tokens,
+			COALESCE(m.cost_usd, 0) as cost_usd,
+			COALESCE(m.grounding_queries, 0) as grounding_queries,
+			COALESCE(m.grounding_cost_usd, 0) as grounding_cost_usd,
+			COALESCE(m.processing_time_ms, 0) as processing_time_ms,
+			COALESCE(m.key_id, '') as key_id
+		FROM %s_airborne_messages m
+		WHERE m.role = 'assistant' AND m.created_at >= $1
+	`, tenantID)
+	r.client.logQuery(query, start)
+
+	rows, err := r.client.backend.Query(ctx, query, start)
+	if err != nil {
+		return fmt.Errorf("failed to read messages for rollup (%s): %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			createdAt time.Time
+			content   string
+			m         ActivityRollup
+		)
+		if err := rows.Scan(
+			&createdAt,
+			&content,
+			&m.Provider,
+			&m.Model,
+			&m.InputTokens,
+			&m.OutputTokens,
+			&m.CostUSD,
+			&m.GroundingQueries,
+			&m.GroundingCostUSD,
+			&m.ProcessingTimeMs,
+			&m.KeyID,
+		); err != nil {
+			return fmt.Errorf("failed to scan message for rollup: %w", err)
+		}
+		m.RequestCount = 1
+		if strings.HasPrefix(content, "[FAILED] ") {
+			m.FailedCount = 1
+		}
+
+		key := rollupKey{bucket: RollupBucket(RollupHourly, createdAt), tenantID: tenantID, provider: m.Provider, model: m.Model, keyID: m.KeyID}
+		agg, ok := hourly[key]
+		if !ok {
+			agg = &ActivityRollup{Granularity: RollupHourly, BucketStart: key.bucket, TenantID: tenantID, Provider: m.Provider, Model: m.Model, KeyID: m.KeyID}
+			hourly[key] = agg
+		}
+		agg.add(m)
+	}
+	return rows.Err()
+}
+
+// dailyFromHourly sums hourly rollups from dayStart into daily buckets.
+func dailyFromHourly(ctx context.Context, q querier, dayStart time.Time) (map[rollupKey]*ActivityRollup, error) {
+	hours, err := queryRollups(ctx, q, RollupHourly, dayStart, "")
+	if err != nil {
+		return nil, err
+	}
+
+	daily := make(map[rollupKey]*ActivityRollup)
+	for _, h := range hours {
+		key := rollupKey{bucket: RollupBucket(RollupDaily, h.BucketStart), tenantID: h.TenantID, provider: h.Provider, model: h.Model, keyID: h.KeyID}
+		agg, ok := daily[key]
+		if !ok {
+			agg = &ActivityRollup{Granularity: RollupDaily, BucketStart: key.bucket, TenantID: h.TenantID, Provider: h.Provider, Model: h.Model, KeyID: h.KeyID}
+			daily[key] = agg
+		}
+		agg.add(h)
+	}
+	return daily, nil
+}
+
+// replaceRollups deletes a granularity's buckets from start onward and inserts rollups.
+// The upsert keeps concurrent aggregators (one per server instance) from conflicting.
+func replaceRollups(ctx context.Context, q querier, granularity string, start time.Time, rollups map[rollupKey]*ActivityRollup) error {
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE granularity = $1 AND bucket_start >= $2`, rollupsTable)
+	if _, err := q.Exec(ctx, deleteQuery, granularity, start); err != nil {
+		return fmt.Errorf("failed to clear %s rollups: %w", granularity, err)
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (
+			granularity, bucket_start, tenant_id, provider, model, key_id,
+			request_count, failed_count, input_tokens, output_tokens, cost_usd,
+			grounding_queries, grounding_cost_usd, processing_time_ms, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+		ON CONFLICT (granularity, bucket_start, tenant_id, provider, model, key_id) DO UPDATE
+		SET request_count = EXCLUDED.request_count,
+		    failed_count = EXCLUDED.failed_count,
+		    input_tokens = EXCLUDED.input_tokens,
+		    output_tokens = EXCLUDED.output_tokens,
+		    cost_usd = EXCLUDED.cost_usd,
+		    grounding_queries = EXCLUDED.grounding_queries,
+		    grounding_cost_usd = EXCLUDED.grounding_cost_usd,
+		    processing_time_ms = EXCLUDED.processing_time_ms,
+		    updated_at = EXCLUDED.updated_at
+	`, rollupsTable)
+	for _, ru := range rollups {
+		if _, err := q.Exec(ctx, insertQuery,
+			granularity, ru.BucketStart, ru.TenantID, ru.Provider, ru.Model, ru.KeyID,
+			ru.RequestCount, ru.FailedCount, ru.InputTokens, ru.OutputTokens, ru.CostUSD,
+			ru.GroundingQueries, ru.GroundingCostUSD, ru.ProcessingTimeMs,
+		); err != nil {
+			return fmt.Errorf("failed to insert %s rollup: %w", granularity, err)
+		}
+	}
+	return nil
+}
+
+// queryRollups returns rollups of a granularity from since onward, oldest first.
+// An empty tenantID matches all tenants.
+func queryRollups(ctx context.Context, q querier, granularity string, since time.Time, tenantID string) ([]ActivityRollup, error) {
+	query := fmt.Sprintf(`
+		SELECT granularity, bucket_start, tenant_id, provider, model, key_id,
+		       request_count, failed_count, input_tokens, output_tokens, cost_usd,
+		       grounding_queries, grounding_cost_usd, processing_time_ms
+		FROM %s
+		WHERE granularity = $1 AND bucket_start >= $2 AND ($3 = '' OR tenant_id = $3)
+		ORDER BY bucket_start ASC, tenant_id, provider, model, key_id
+	`, rollupsTable)
+
+	rows, err := q.Query(ctx, query, granularity, since, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rollups: %w", err)
+	}
+	defer rows.Close()
+
+	var rollups []ActivityRollup
+	for rows.Next() {
+		var ru ActivityRollup
+		if err := rows.Scan(
+			&ru.Granularity,
+			&ru.BucketStart,
+			&ru.TenantID,
+			&ru.Provider,
+			&ru.Model,
+			&ru.KeyID,
+			&ru.RequestCount,
+			&ru.FailedCount,
+			&ru.InputTokens,
+			&ru.OutputTokens,
+			&ru.CostUSD,
+			&ru.GroundingQueries,
+			&ru.GroundingCostUSD,
+			&ru.ProcessingTimeMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rollup: %w", err)
+		}
+		rollups = append(rollups, ru)
+	}
+	return rollups, rows.Err()
+}
+
+// GetActivityRollups returns rollups of a granularity from since onward,
+// oldest first. An empty tenantID returns all tenants.
+func (r *Repository) GetActivityRollups(ctx context.Context, granularity string, since time.Time, tenantID string) ([]ActivityRollup, error) {
+	if granularity != RollupHourly && granularity != RollupDaily {
+		return nil, fmt.Errorf("invalid rollup granularity %q", granularity)
+	}
+	if tenantID != "" && !ValidTenantIDs[tenantID] {
+		return nil, fmt.Errorf("%w: got %q", ErrInvalidTenant, tenantID)
+	}
+	if err := r.checkTenantArg(ctx, "GetActivityRollups", tenantID); err != nil {
+		return nil, err
+	}
+	return queryRollups(ctx, r.client.reader(), granularity, RollupBucket(granularity, since), tenantID)
+}
+
+// KeyUsage is total usage for one client API key of a tenant.
+type KeyUsage struct {
+	TenantID         string  `json:"tenant"`
+	KeyID            string  `json:"key_id"` // Hashed client API key ID; empty for unattributed usage
+	RequestCount     int64   `json:"request_count"`
+	FailedCount      int64   `json:"failed_count"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	GroundingQueries int64   `json:"grounding_queries"`
+	GroundingCostUSD float64 `json:"grounding_cost_usd"`
+}
+
+// UsageByKey sums rollups per tenant and client key, ordered by total cost
+// (token plus grounding), highest first.
+func UsageByKey(rollups []ActivityRollup) []KeyUsage {
+	type tenantKey struct{ tenantID, keyID string }
+	byKey := make(map[tenantKey]*KeyUsage)
+	for _, ru := range rollups {
+		k := tenantKey{ru.TenantID, ru.KeyID}
+		u, ok := byKey[k]
+		if !ok {
+			u = &KeyUsage{TenantID: ru.TenantID, KeyID: ru.KeyID}
+			byKey[k] = u
+		}
+		u.RequestCount += ru.RequestCount
+		u.FailedCount += ru.FailedCount
+		u.InputTokens += ru.InputTokens
+		u.OutputTokens += ru.OutputTokens
+		u.CostUSD += ru.CostUSD
+		u.GroundingQueries += ru.GroundingQueries
+		u.GroundingCostUSD += ru.GroundingCostUSD
+	}
+
+	usage := make([]KeyUsage, 0, len(byKey))
+	for _, u := range byKey {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		ci, cj := usage[i].CostUSD+usage[i].GroundingCostUSD, usage[j].CostUSD+usage[j].GroundingCostUSD
+		if ci != cj {
+			return ci > cj
+		}
+		if usage[i].TenantID != usage[j].TenantID {
+			return usage[i].TenantID < usage[j].TenantID
+		}
+		return usage[i].KeyID < usage[j].KeyID
+	})
+	return usage
+}
+
+// LatestRollupHour returns the most recent hourly bucket, or the zero time
+// when no rollups exist yet.
+func (r *Repository) LatestRollupHour(ctx context.Context) (time.Time, error) {
+	query := fmt.Sprintf(`
+		SELECT bucket_start FROM %s
+		WHERE granularity = $1
+		ORDER BY bucket_start DESC
+		LIMIT 1
+	`, rollupsTable)
+	r.client.logQuery(query, RollupHourly)
+
+	var latest time.Time
+	err := r.client.backend.QueryRow(ctx, query, RollupHourly).Scan(&latest)
+	if errors.Is(err, errNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest rollup: %w", err)
+	}
+	return latest, nil
+}
+
+// sortedTenantIDs returns ValidTenantIDs in a stable order.
+func sortedTenantIDs() []string {
+	ids := make([]string, 0, len(ValidTenantIDs))
+	for id := range ValidTenantIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
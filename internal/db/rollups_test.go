@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// seedAssistantMessage inserts an assistant message at a fixed time.
+func seedAssistantMessage(t *testing.T, repo *Repository, thread *Thread, at time.Time, content, model string, cost float64) {
+	t.Helper()
+	msg := NewMessage(thread.ID, RoleAssistant, content)
+	msg.CreatedAt = at
+	msg.SetAssistantMetrics("openai", model, 10, 20, 100, cost, "")
+	if err := repo.CreateMessage(context.Background(), msg); err != nil {
+		t.Fatalf("CreateMessage failed: %v", err)
+	}
+}
+
+func TestRollupBucket(t *testing.T) {
+	at := time.Date(2026, 10, 16, 14, 35, 12, 0, time.FixedZone("EST", -5*3600))
+
+	if got, want := RollupBucket(RollupHourly, at), time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("hourly bucket = %v, want %v", got, want)
+	}
+	if got, want := RollupBucket(RollupDaily, at), time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("daily bucket = %v, want %v", got, want)
+	}
+}
+
+func TestRefreshActivityRollups(t *testing.T) {
+	ctx := context.Background()
+	client := newSQLiteClient(t)
+	ai8, _ := client.TenantRepository("ai8")
+	email, _ := client.TenantRepository("email4ai")
+	base := NewRepository(client)
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	thread := NewThread("user-1")
+	if err := ai8.CreateThread(ctx, thread); err != nil {
+		t.Fatalf("CreateThread failed: %v", err)
+	}
+	other := NewThread("user-2")
+	if err := email.CreateThread(ctx, other); err != nil {
+		t.Fatalf("CreateThread failed: %v", err)
+	}
+
+	seedAssistantMessage(t, ai8, thread, day.Add(9*time.Hour+5*time.Minute), "a", "gpt-4o", 0.01)
+	seedAssistantMessage(t, ai8, thread, day.Add(9*time.Hour+50*time.Minute), "[FAILED] b", "gpt-4o", 0)
+	seedAssistantMessage(t, ai8, thread, day.Add(10*time.Hour), "c", "gpt-4o", 0.02)
+	seedAssistantMessage(t, email, other, day.Add(26*time.Hour), "d", "gpt-4o-mini", 0.005)
+
+	latest, err := base.LatestRollupHour(ctx)
+	if err != nil || !latest.IsZero() {
+		t.Fatalf("expected no rollups yet, got %v, %v", latest, err)
+	}
+
+	for i := 0; i < 2; i++ { // Refreshing twice must not double count
+		if err := base.RefreshActivityRollups(ctx, day); err != nil {
+			t.Fatalf("RefreshActivityRollups failed: %v", err)
+		}
+	}
+
+	hourly, err := base.GetActivityRollups(ctx, RollupHourly, day, "ai8")
+	if err != nil {
+		t.Fatalf("GetActivityRollups failed: %v", err)
+	}
+	if len(hourly) != 2 {
+		t.Fatalf("expected 2 hourly ai8 buckets, got %+v", hourly)
+	}
+	if h := hourly[0]; h.RequestCount != 2 || h.FailedCount != 1 || h.InputTokens != 20 || h.CostUSD != 0.01 {
+		t.Errorf("unexpected 09:00 bucket: %+v", h)
+	}
+
+	daily, err := base.GetActivityRollups(ctx, RollupDaily, day, "")
+	if err != nil {
+		t.Fatalf("GetActivityRollups failed: %v", err)
+	}
+	if len(daily) != 2 {
+		t.Fatalf("expected 2 daily buckets, got %+v", daily)
+	}
+	if d := daily[0]; d.TenantID != "ai8" || d.RequestCount != 3 || d.ProcessingTimeMs != 300 || d.CostUSD != 0.03 {
+		t.Errorf("unexpected ai8 daily bucket: %+v", d)
+	}
+	if d := daily[1]; d.TenantID != "email4ai" || d.Model != "gpt-4o-mini" || !d.BucketStart.Equal(day.Add(24*time.Hour)) {
+		t.Errorf("unexpected email4ai daily bucket: %+v", d)
+	}
+
+	// A partial refresh keeps earlier hours in the daily total
+	if err := base.RefreshActivityRollups(ctx, day.Add(10*time.Hour)); err != nil {
+		t.Fatalf("RefreshActivityRollups failed: %v", err)
+	}
+	daily, _ = base.GetActivityRollups(ctx, RollupDaily, day, "ai8")
+	if len(daily) != 1 || daily[0].RequestCount != 3 {
+		t.Errorf("expected daily total to include earlier hours, got %+v", daily)
+	}
+
+	latest, err = base.LatestRollupHour(ctx)
+	if err != nil || !latest.Equal(day.Add(26*time.Hour)) {
+		t.Errorf("LatestRollupHour = %v, %v; want %v", latest, err, day.Add(26*time.Hour))
+	}
+}
+
+func TestGetActivityRollups_Validation(t *testing.T) {
+	base := NewRepository(newSQLiteClient(t))
+
+	if _, err := base.GetActivityRollups(context.Background(), "week", time.Now(), ""); err == nil {
+		t.Error("expected error for invalid granularity")
+	}
+	if _, err := base.GetActivityRollups(context.Background(), RollupDaily, time.Now(), "bogus"); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected ErrInvalidTenant, got %v", err)
+	}
+}
+
+func TestRollupAggregator_Refresh(t *testing.T) {
+	agg := NewRollupAggregator(newSQLiteClient(t), 0)
+	if agg.interval != DefaultRollupInterval {
+		t.Errorf("interval = %v, want default %v", agg.interval, DefaultRollupInterval)
+	}
+
+	now := time.Date(2026, 10, 16, 14, 35, 0, 0, time.UTC)
+	agg.now = func() time.Time { return now }
+
+	next := agg.refresh(context.Background(), now.Add(-48*time.Hour))
+	if want := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("next since = %v, want %v (previous hour)", next, want)
+	}
+}
+
+func TestRefreshActivityRollups_ByKey(t *testing.T) {
+	ctx := context.Background()
+	client := newSQLiteClient(t)
+	ai8, _ := client.TenantRepository("ai8")
+	base := NewRepository(client)
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	thread := NewThread("user-1")
+	if err := ai8.CreateThread(ctx, thread); err != nil {
+		t.Fatalf("CreateThread failed: %v", err)
+	}
+
+	seed := func(keyID string, cost float64) {
+		t.Helper()
+		msg := NewMessage(thread.ID, RoleAssistant, "reply")
+		msg.CreatedAt = day.Add(9 * time.Hour)
+		msg.SetAssistantMetrics("openai", "gpt-4o", 10, 20, 100, cost, "")
+		if keyID != "" {
+			msg.KeyID = &keyID
+		}
+		if err := ai8.CreateMessage(ctx, msg); err != nil {
+			t.Fatalf("CreateMessage failed: %v", err)
+		}
+	}
+	seed("team-a", 0.01)
+	seed("team-a", 0.02)
+	seed("team-b", 0.05)
+	seed("", 0.001)
+
+	if err := base.RefreshActivityRollups(ctx, day); err != nil {
+		t.Fatalf("RefreshActivityRollups failed: %v", err)
+	}
+	daily, err := base.GetActivityRollups(ctx, RollupDaily, day, "ai8")
+	if err != nil {
+		t.Fatalf("GetActivityRollups failed: %v", err)
+	}
+	if len(daily) != 3 {
+		t.Fatalf("expected one daily rollup per key, got %+v", daily)
+	}
+
+	usage := UsageByKey(daily)
+	if len(usage) != 3 {
+		t.Fatalf("expected 3 key usages, got %+v", usage)
+	}
+	if u := usage[0]; u.KeyID != "team-b" || u.RequestCount != 1 {
+		t.Errorf("expected team-b to be the top spender, got %+v", u)
+	}
+	if u := usage[1]; u.KeyID != "team-a" || u.RequestCount != 2 || u.InputTokens != 20 {
+		t.Errorf("unexpected team-a usage: %+v", u)
+	}
+	if u := usage[2]; u.KeyID != "" || u.RequestCount != 1 {
+		t.Errorf("expected unattributed usage last, got %+v", u)
+	}
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant settings tables are shared across tenants; rows are keyed by
+// tenant_id. Tenant IDs come from the tenant configs, not ValidTenantIDs.
+const (
+	tenantOverridesTable = "airborne_tenant_overrides"
+	settingsAuditTable   = "airborne_tenant_settings_audit"
+)
+
+// SettingsChange is one runtime change to a tenant's settings. The JSON
+// documents are stored as given; callers mask any secrets first.
+type SettingsChange struct {
+	TenantID   string
+	Actor      string
+	RemoteAddr string
+	Reason     string
+	Change     string // The update as submitted
+	Before     string // Settings before the change
+	After      string // Settings after the change
+	Override   string // Merged override to persist for the tenant
+}
+
+// SettingsAuditEntry is a recorded settings change.
+type SettingsAuditEntry struct {
+	ID         uuid.UUID `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	Actor      string    `json:"actor"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Change     string    `json:"change"` // JSON document
+	Before     string    `json:"before"` // JSON document
+	After      string    `json:"after"`  // JSON document
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SaveSettingsChange stores a tenant's merged override and its audit entry
+// in one transaction, so every persisted change is audited.
+func (r *Repository) SaveSettingsChange(ctx context.Context, c SettingsChange) error {
+	if err := r.checkTenantArg(ctx, "SaveSettingsChange", c.TenantID); err != nil {
+		return err
+	}
+	t, err := r.client.backend.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer t.Rollback(ctx)
+
+	upsert := fmt.Sprintf(`
+		INSERT INTO %s (tenant_id, settings, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET settings = excluded.settings, updated_by = excluded.updated_by, updated_at = excluded.updated_at
+	`, tenantOverridesTable)
+	r.client.logQuery(upsert, c.TenantID, c.Actor)
+	if _, err := t.Exec(ctx, upsert, c.TenantID, c.Override, c.Actor); err != nil {
+		return fmt.Errorf("failed to save tenant override: %w", err)
+	}
+
+	audit := fmt.Sprintf(`
+		INSERT INTO %s (id, tenant_id, actor, remote_addr, reason, change, before, after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	`, settingsAuditTable)
+	r.client.logQuery(audit, c.TenantID, c.Actor)
+	if _, err := t.Exec(ctx, audit, uuid.New(), c.TenantID, c.Actor, c.RemoteAddr, c.Reason, c.Change, c.Before, c.After); err != nil {
+		return fmt.Errorf("failed to write settings audit entry: %w", err)
+	}
+
+	if err := t.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit settings change: %w", err)
+	}
+	return nil
+}
+
+// GetTenantOverrides returns every tenant's persisted override as a JSON
+// document, keyed by tenant ID.
+func (r *Repository) GetTenantOverrides(ctx context.Context) (map[string]string, error) {
+	query := fmt.Sprintf(`SELECT tenant_id, settings FROM %s`, tenantOverridesTable)
+	r.client.logQuery(query)
+
+	rows, err := r.client.backend.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenant overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make(map[string]string)
+	for rows.Next() {
+		var tenantID, settings string
+		if err := rows.Scan(&tenantID, &settings); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant override: %w", err)
+		}
+		overrides[tenantID] = settings
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query tenant overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// GetSettingsAudit returns a tenant's most recent settings changes, newest
+// first.
+func (r *Repository) GetSettingsAudit(ctx context.Context, tenantID string, limit int) ([]SettingsAuditEntry, error) {
+	if err := r.checkTenantArg(ctx, "GetSettingsAudit", tenantID); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, actor, remote_addr, reason, change, before, after, created_at
+		FROM %s
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, settingsAuditTable)
+	r.client.logQuery(query, tenantID, limit)
+
+	rows, err := r.client.reader().Query(ctx, query, tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query settings audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []SettingsAuditEntry{}
+	for rows.Next() {
+		var e SettingsAuditEntry
+		if err := rows.Scan(&e.ID, &e.TenantID, &e.Actor, &e.RemoteAddr, &e.Reason, &e.Change, &e.Before, &e.After, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan settings audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query settings audit: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSettingsChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(newOutboxRepo(t).client)
+
+	for i, override := range []string{`{"failover":{"enabled":true}}`, `{"failover":{"enabled":false}}`} {
+		if err := repo.SaveSettingsChange(ctx, SettingsChange{
+			TenantID:   "acme",
+			Actor:      "ops@example.com",
+			RemoteAddr: "10.0.0.1",
+			Reason:     "tuning",
+			Change:     override,
+			Before:     `{}`,
+			After:      override,
+			Override:   override,
+		}); err != nil {
+			t.Fatalf("SaveSettingsChange %d failed: %v", i, err)
+		}
+	}
+
+	overrides, err := repo.GetTenantOverrides(ctx)
+	if err != nil {
+		t.Fatalf("GetTenantOverrides failed: %v", err)
+	}
+	if len(overrides) != 1 || overrides["acme"] != `{"failover":{"enabled":false}}` {
+		t.Errorf("expected the latest override to replace the first, got %v", overrides)
+	}
+
+	entries, err := repo.GetSettingsAudit(ctx, "acme", 10)
+	if err != nil {
+		t.Fatalf("GetSettingsAudit failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	if entries[0].Actor != "ops@example.com" || entries[0].Reason != "tuning" || entries[0].CreatedAt.IsZero() {
+		t.Errorf("unexpected audit entry: %+v", entries[0])
+	}
+
+	if entries, err := repo.GetSettingsAudit(ctx, "other", 10); err != nil || len(entries) != 0 {
+		t.Errorf("expected no entries for another tenant, got %v, %v", entries, err)
+	}
+}
@@ -0,0 +1,477 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// sqliteTimeFormat stores timestamps as fixed-width UTC text, so string
+// comparison matches chronological order and equal times compare equal.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000000-07:00"
+
+// sqliteNow replaces NOW() in shared queries, producing sqliteTimeFormat.
+const sqliteNow = "(strftime('%Y-%m-%d %H:%M:%f', 'now') || '000000+00:00')"
+
+// sqliteDSNParams configures every pooled connection. WAL lets readers
+// proceed during a write.
+const sqliteDSNParams = "_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)"
+
+// sqliteSchema creates the tables for the tenant substituted for {tenant}.
+// It mirrors the PostgreSQL tenant migrations (004-007, 010, 013, 015, 017,
+// 018), with a trigger maintaining message_count and updated_at.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_threads (
+    id              TEXT PRIMARY KEY,
+    user_id         TEXT NOT NULL,
+    provider        TEXT,
+    model           TEXT,
+    status          TEXT NOT NULL DEFAULT 'active',
+    message_count   INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    metadata        TEXT,
+    pinned_provider TEXT,
+    pinned_model    TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_{tenant}_threads_user ON {tenant}_airborne_threads(user_id);
+CREATE INDEX IF NOT EXISTS idx_{tenant}_threads_updated ON {tenant}_airborne_threads(updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_messages (
+    id                  TEXT PRIMARY KEY,
+    thread_id           TEXT NOT NULL REFERENCES {tenant}_airborne_threads(id) ON DELETE CASCADE,
+    role                TEXT NOT NULL CHECK (role IN ('user', 'assistant', 'system')),
+    content             TEXT NOT NULL,
+    provider            TEXT,
+    model               TEXT,
+    response_id         TEXT,
+    input_tokens        INTEGER,
+    output_tokens       INTEGER,
+    total_tokens        INTEGER,
+    cost_usd            REAL,
+    grounding_queries   INTEGER,
+    grounding_cost_usd  REAL,
+    processing_time_ms  INTEGER,
+    citations           TEXT,
+    created_at          TIMESTAMP NOT NULL,
+    metadata            TEXT,
+    system_prompt       TEXT,
+    raw_request_json    TEXT,
+    raw_response_json   TEXT,
+    rendered_html       TEXT,
+    key_id              TEXT,
+    superseded_at       TIMESTAMP,
+    parent_message_id   TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_{tenant}_messages_thread ON {tenant}_airborne_messages(thread_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_{tenant}_messages_created ON {tenant}_airborne_messages(created_at DESC);
+
+CREATE TRIGGER IF NOT EXISTS trigger_{tenant}_message_inserted
+    AFTER INSERT ON {tenant}_airborne_messages
+    FOR EACH ROW
+BEGIN
+    UPDATE {tenant}_airborne_threads
+    SET message_count = message_count + 1,
+        updated_at = ` + sqliteNow + `
+    WHERE id = NEW.thread_id;
+END;
+
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_memories (
+    id              TEXT PRIMARY KEY,
+    user_id         TEXT NOT NULL,
+    category        TEXT NOT NULL DEFAULT 'other',
+    content         TEXT NOT NULL,
+    created_at      TIMESTAMP,
+    updated_at      TIMESTAMP,
+    UNIQUE (user_id, content)
+);
+
+CREATE INDEX IF NOT EXISTS idx_{tenant}_memories_user_updated ON {tenant}_airborne_memories(user_id, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_thread_files (
+    id              TEXT PRIMARY KEY,
+    thread_id       TEXT NOT NULL REFERENCES {tenant}_airborne_threads(id) ON DELETE CASCADE,
+    original_uri    TEXT NOT NULL,
+    file_uri        TEXT NOT NULL,
+    filename        TEXT NOT NULL,
+    mime_type       TEXT NOT NULL,
+    size_bytes      INTEGER NOT NULL,
+    content         BLOB,
+    expires_at      TIMESTAMP NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (thread_id, original_uri)
+);
+
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_user_profiles (
+    user_id             TEXT PRIMARY KEY,
+    display_name        TEXT NOT NULL DEFAULT '',
+    language            TEXT NOT NULL DEFAULT '',
+    tone                TEXT NOT NULL DEFAULT '',
+    custom_instructions TEXT NOT NULL DEFAULT '',
+    timezone            TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_message_analytics (
+    message_id           TEXT PRIMARY KEY REFERENCES {tenant}_airborne_messages(id) ON DELETE CASCADE,
+    thread_id            TEXT NOT NULL,
+    intent               TEXT NOT NULL DEFAULT '',
+    requires_user_action INTEGER NOT NULL DEFAULT 0,
+    entities             TEXT NOT NULL DEFAULT '[]',
+    provider             TEXT NOT NULL DEFAULT '',
+    model                TEXT NOT NULL DEFAULT '',
+    error                TEXT NOT NULL DEFAULT '',
+    message_created_at   TIMESTAMP NOT NULL,
+    analyzed_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_{tenant}_message_analytics_created ON {tenant}_airborne_message_analytics(message_created_at);
+
+CREATE TABLE IF NOT EXISTS {tenant}_airborne_message_topics (
+    message_id          TEXT NOT NULL REFERENCES {tenant}_airborne_message_analytics(message_id) ON DELETE CASCADE,
+    topic               TEXT NOT NULL,
+    PRIMARY KEY (message_id, topic)
+);
+`
+
+// sqliteSharedSchema creates tables shared by all tenants (migrations 008-012
+// and 016).
+const sqliteSharedSchema = `
+CREATE TABLE IF NOT EXISTS airborne_activity_rollups (
+    granularity         TEXT NOT NULL CHECK (granularity IN ('hour', 'day')),
+    bucket_start        TIMESTAMP NOT NULL,
+    tenant_id           TEXT NOT NULL,
+    provider            TEXT NOT NULL,
+    model               TEXT NOT NULL,
+    key_id              TEXT NOT NULL DEFAULT '',
+    request_count       INTEGER NOT NULL DEFAULT 0,
+    failed_count        INTEGER NOT NULL DEFAULT 0,
+    input_tokens        INTEGER NOT NULL DEFAULT 0,
+    output_tokens       INTEGER NOT NULL DEFAULT 0,
+    cost_usd            REAL NOT NULL DEFAULT 0,
+    grounding_queries   INTEGER NOT NULL DEFAULT 0,
+    grounding_cost_usd  REAL NOT NULL DEFAULT 0,
+    processing_time_ms  INTEGER NOT NULL DEFAULT 0,
+    updated_at          TIMESTAMP NOT NULL,
+    PRIMARY KEY (granularity, bucket_start, tenant_id, provider, model, key_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_rollups_bucket ON airborne_activity_rollups(granularity, bucket_start DESC);
+
+CREATE TABLE IF NOT EXISTS airborne_event_outbox (
+    id               TEXT PRIMARY KEY,
+    tenant_id        TEXT NOT NULL,
+    event_type       TEXT NOT NULL,
+    payload          TEXT NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMP NOT NULL,
+    last_error       TEXT,
+    created_at       TIMESTAMP NOT NULL,
+    delivered_at     TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_due ON airborne_event_outbox(status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS airborne_tenant_overrides (
+    tenant_id   TEXT PRIMARY KEY,
+    settings    TEXT NOT NULL,
+    updated_by  TEXT NOT NULL,
+    updated_at  TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS airborne_tenant_settings_audit (
+    id          TEXT PRIMARY KEY,
+    tenant_id   TEXT NOT NULL,
+    actor       TEXT NOT NULL,
+    remote_addr TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL DEFAULT '',
+    change      TEXT NOT NULL,
+    before      TEXT NOT NULL,
+    after       TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_settings_audit_tenant ON airborne_tenant_settings_audit(tenant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS airborne_file_store_usage (
+    tenant_id   TEXT NOT NULL,
+    store_id    TEXT NOT NULL,
+    provider    TEXT NOT NULL,
+    file_count  INTEGER NOT NULL DEFAULT 0,
+    total_bytes INTEGER NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, store_id)
+);
+
+CREATE TABLE IF NOT EXISTS airborne_provider_calls (
+    id            TEXT PRIMARY KEY,
+    tenant_id     TEXT NOT NULL,
+    request_id    TEXT NOT NULL DEFAULT '',
+    provider      TEXT NOT NULL,
+    model         TEXT NOT NULL DEFAULT '',
+    key_id        TEXT NOT NULL DEFAULT '',
+    input_tokens  INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    cost_usd      REAL NOT NULL DEFAULT 0,
+    http_status   INTEGER NOT NULL DEFAULT 0,
+    error         TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_provider_calls_created ON airborne_provider_calls(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_provider_calls_tenant ON airborne_provider_calls(tenant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS airborne_network_violations (
+    id          TEXT PRIMARY KEY,
+    surface     TEXT NOT NULL,
+    method      TEXT NOT NULL,
+    client_id   TEXT NOT NULL DEFAULT '',
+    key_id      TEXT NOT NULL DEFAULT '',
+    remote_addr TEXT NOT NULL,
+    country     TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_network_violations_created ON airborne_network_violations(created_at DESC);
+`
+
+// sqliteDSN converts a configured URL or file path into a driver DSN.
+// Accepted forms are "sqlite:///path/to/file.db", "file:..." and a plain path.
+func sqliteDSN(url string) string {
+	dsn := strings.TrimPrefix(url, "sqlite://")
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + sqliteDSNParams
+	}
+	return dsn + "?" + sqliteDSNParams
+}
+
+// newSQLiteBackend opens a SQLite database and creates the schema.
+func newSQLiteBackend(ctx context.Context, cfg Config) (*sqliteBackend, error) {
+	sqlDB, err := sql.Open("sqlite", sqliteDSN(cfg.URL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY under load
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	for tenantID := range ValidTenantIDs {
+		if _, err := sqlDB.ExecContext(ctx, strings.ReplaceAll(sqliteSchema, "{tenant}", tenantID)); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("failed to create sqlite schema for tenant %s: %w", tenantID, err)
+		}
+	}
+	if err := upgradeSQLiteSchema(ctx, sqlDB); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	if _, err := sqlDB.ExecContext(ctx, sqliteSharedSchema); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+
+	slog.Info("database connection established", "driver", DriverSQLite)
+
+	return &sqliteBackend{sqliteQuerier: sqliteQuerier{q: sqlDB}, db: sqlDB}, nil
+}
+
+// sqliteParentBackfill links the messages of threads stored before branching
+// (migration 014): each message's parent is the one before it.
+const sqliteParentBackfill = `
+UPDATE {table} AS m SET parent_message_id = prev.parent_id
+FROM (
+    SELECT id, LAG(id) OVER (PARTITION BY thread_id ORDER BY created_at, CASE WHEN role = 'user' THEN 0 ELSE 1 END, id) AS parent_id
+    FROM {table}
+) AS prev
+WHERE m.id = prev.id AND prev.parent_id IS NOT NULL`
+
+// sqlitePinBackfill pins threads stored before pinning (migration 020) to
+// their last-used provider and model.
+const sqlitePinBackfill = `
+UPDATE {table} SET pinned_provider = provider, pinned_model = model WHERE pinned_provider IS NULL`
+
+// upgradeSQLiteSchema brings databases created by older versions up to date,
+// since CREATE TABLE IF NOT EXISTS leaves existing tables untouched.
+func upgradeSQLiteSchema(ctx context.Context, sqlDB *sql.DB) error {
+	for tenantID := range ValidTenantIDs {
+		for _, col := range []struct{ table, name, typ, backfill string }{
+			{"_airborne_messages", "key_id", "TEXT", ""},
+			{"_airborne_messages", "superseded_at", "TIMESTAMP", ""},
+			{"_airborne_messages", "parent_message_id", "TEXT", sqliteParentBackfill},
+			{"_airborne_user_profiles", "timezone", "TEXT NOT NULL DEFAULT ''", ""},
+			{"_airborne_threads", "pinned_provider", "TEXT", ""},
+			{"_airborne_threads", "pinned_model", "TEXT", sqlitePinBackfill},
+		} {
+			table := tenantID + col.table
+			ok, err := sqliteHasColumn(ctx, sqlDB, table, col.name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				if _, err := sqlDB.ExecContext(ctx, "ALTER TABLE "+table+" ADD COLUMN "+col.name+" "+col.typ); err != nil {
+					return fmt.Errorf("failed to add %s to %s: %w", col.name, table, err)
+				}
+				if col.backfill != "" {
+					if _, err := sqlDB.ExecContext(ctx, strings.ReplaceAll(col.backfill, "{table}", table)); err != nil {
+						return fmt.Errorf("failed to backfill %s in %s: %w", col.name, table, err)
+					}
+				}
+			}
+		}
+	}
+
+	// key_id is part of the rollup primary key, which SQLite cannot alter.
+	// Rollups are derived data, so drop the table and let the aggregator rebuild it.
+	exists, err := sqliteHasColumn(ctx, sqlDB, rollupsTable, "granularity")
+	if err != nil {
+		return err
+	}
+	if exists {
+		ok, err := sqliteHasColumn(ctx, sqlDB, rollupsTable, "key_id")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if _, err := sqlDB.ExecContext(ctx, "DROP TABLE "+rollupsTable); err != nil {
+				return fmt.Errorf("failed to drop outdated rollups table: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// sqliteHasColumn reports whether table has the named column. It returns
+// false when the table does not exist.
+func sqliteHasColumn(ctx context.Context, sqlDB *sql.DB, table, column string) (bool, error) {
+	var n int
+	err := sqlDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect sqlite table %s: %w", table, err)
+	}
+	return n > 0, nil
+}
+
+// sqlExecutor is the subset of sql.DB and sql.Tx used by the repository.
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteQuerier adapts a database/sql handle to the querier interface,
+// rewriting PostgreSQL-only syntax in the shared queries.
+type sqliteQuerier struct {
+	q sqlExecutor
+}
+
+func (s sqliteQuerier) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	res, err := s.q.ExecContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s sqliteQuerier) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	r, err := s.q.QueryContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+	if err != nil {
+		return nil, err
+	}
+	return sqliteRows{r}, nil
+}
+
+func (s sqliteQuerier) QueryRow(ctx context.Context, query string, args ...any) row {
+	return sqliteRow{s.q.QueryRowContext(ctx, sqliteQuery(query), sqliteArgs(args)...)}
+}
+
+// sqliteQuery translates PostgreSQL functions used by the repository.
+func sqliteQuery(query string) string {
+	return strings.ReplaceAll(query, "NOW()", sqliteNow)
+}
+
+// sqliteArgs formats timestamps with sqliteTimeFormat in UTC.
+func sqliteArgs(args []any) []any {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		if t, ok := arg.(time.Time); ok {
+			arg = t.UTC().Format(sqliteTimeFormat)
+		}
+		out[i] = arg
+	}
+	return out
+}
+
+// sqliteRows adapts sql.Rows, whose Close returns an error.
+type sqliteRows struct {
+	*sql.Rows
+}
+
+func (r sqliteRows) Close() {
+	r.Rows.Close()
+}
+
+// sqliteRow maps sql.ErrNoRows to errNoRows.
+type sqliteRow struct {
+	row *sql.Row
+}
+
+func (r sqliteRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errNoRows
+	}
+	return err
+}
+
+// sqliteBackend is the SQLite backend.
+type sqliteBackend struct {
+	sqliteQuerier
+	db *sql.DB
+}
+
+func (b *sqliteBackend) Begin(ctx context.Context) (tx, error) {
+	t, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteTx{sqliteQuerier: sqliteQuerier{q: t}, tx: t}, nil
+}
+
+func (b *sqliteBackend) Ping(ctx context.Context) error {
+	return b.db.PingContext(ctx)
+}
+
+func (b *sqliteBackend) Close() {
+	b.db.Close()
+}
+
+// sqliteTx is a SQLite transaction.
+type sqliteTx struct {
+	sqliteQuerier
+	tx *sql.Tx
+}
+
+func (t *sqliteTx) Commit(ctx context.Context) error {
+	return t.tx.Commit()
+}
+
+func (t *sqliteTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback()
+}
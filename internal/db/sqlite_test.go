@@ -0,0 +1,464 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/isolation"
+	"github.com/google/uuid"
+)
+
+// newSQLiteClient opens a fresh SQLite database in a temp directory.
+func newSQLiteClient(t *testing.T) *Client {
+	t.Helper()
+	client, err := NewClient(context.Background(), Config{
+		Driver: DriverSQLite,
+		URL:    filepath.Join(t.TempDir(), "airborne.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func newSQLiteRepo(t *testing.T, tenantID string) *Repository {
+	t.Helper()
+	repo, err := newSQLiteClient(t).TenantRepository(tenantID)
+	if err != nil {
+		t.Fatalf("TenantRepository failed: %v", err)
+	}
+	return repo
+}
+
+func TestNewClient_UnsupportedDriver(t *testing.T) {
+	_, err := NewClient(context.Background(), Config{Driver: "mysql", URL: "x"})
+	if err == nil {
+		t.Fatal("expected error for unsupported driver")
+	}
+}
+
+func TestSQLiteDSN(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"/var/lib/airborne.db", "/var/lib/airborne.db?" + sqliteDSNParams},
+		{"sqlite:///var/lib/airborne.db", "/var/lib/airborne.db?" + sqliteDSNParams},
+		{"file:airborne.db?mode=rwc", "file:airborne.db?mode=rwc&" + sqliteDSNParams},
+	}
+	for _, tt := range tests {
+		if got := sqliteDSN(tt.url); got != tt.want {
+			t.Errorf("sqliteDSN(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSQLite_ThreadLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client := newSQLiteClient(t)
+	repo, _ := client.TenantRepository("ai8")
+
+	if client.Pool() != nil {
+		t.Error("expected nil pgx pool for sqlite")
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	missing, err := repo.GetThread(ctx, uuid.New())
+	if err != nil || missing != nil {
+		t.Fatalf("expected nil thread for unknown ID, got %v, %v", missing, err)
+	}
+
+	thread, err := repo.GetOrCreateThread(ctx, uuid.New(), "user-1")
+	if err != nil {
+		t.Fatalf("GetOrCreateThread failed: %v", err)
+	}
+	if err := repo.UpdateThreadProvider(ctx, thread.ID, "openai", "gpt-4o"); err != nil {
+		t.Fatalf("UpdateThreadProvider failed: %v", err)
+	}
+
+	got, err := repo.GetThread(ctx, thread.ID)
+	if err != nil {
+		t.Fatalf("GetThread failed: %v", err)
+	}
+	if got.UserID != "user-1" || got.Provider == nil || *got.Provider != "openai" {
+		t.Errorf("unexpected thread: %+v", got)
+	}
+	if !got.CreatedAt.Equal(thread.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, thread.CreatedAt)
+	}
+}
+
+func TestSQLite_CrossTenantAccessBlocked(t *testing.T) {
+	repo := newSQLiteRepo(t, "ai8")
+
+	ctx := isolation.WithTenant(context.Background(), "ai8")
+	thread, err := repo.GetOrCreateThread(ctx, uuid.New(), "user-1")
+	if err != nil {
+		t.Fatalf("GetOrCreateThread failed: %v", err)
+	}
+
+	other := isolation.WithTenant(context.Background(), "email4ai")
+	if _, err := repo.GetThread(other, thread.ID); !errors.Is(err, isolation.ErrCrossTenant) {
+		t.Errorf("expected ErrCrossTenant, got %v", err)
+	}
+	if _, err := repo.ListMemories(other, "user-1", 10); !errors.Is(err, isolation.ErrCrossTenant) {
+		t.Errorf("expected ErrCrossTenant, got %v", err)
+	}
+}
+
+func TestSQLite_MessagesAndTrigger(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	thread := NewThread("user-1")
+	if err := repo.CreateThread(ctx, thread); err != nil {
+		t.Fatalf("CreateThread failed: %v", err)
+	}
+
+	first := NewMessage(thread.ID, RoleUser, "hello")
+	second := NewMessage(thread.ID, RoleAssistant, "hi there")
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+	second.SetAssistantMetrics("openai", "gpt-4o", 10, 20, 150, 0.0025, "resp-1")
+	for _, msg := range []*Message{second, first} {
+		if err := repo.CreateMessage(ctx, msg); err != nil {
+			t.Fatalf("CreateMessage failed: %v", err)
+		}
+	}
+
+	messages, err := repo.GetMessages(ctx, thread.ID, 10)
+	if err != nil {
+		t.Fatalf("GetMessages failed: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Content != "hello" || messages[1].Content != "hi there" {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+	if messages[1].CostUSD == nil || *messages[1].CostUSD != 0.0025 {
+		t.Errorf("unexpected cost: %v", messages[1].CostUSD)
+	}
+
+	got, _ := repo.GetThread(ctx, thread.ID)
+	if got.MessageCount != 2 {
+		t.Errorf("MessageCount = %d, want 2 (maintained by trigger)", got.MessageCount)
+	}
+}
+
+func TestSQLite_PersistConversationTurnAndActivity(t *testing.T) {
+	ctx := context.Background()
+	client := newSQLiteClient(t)
+	repo, _ := client.TenantRepository("email4ai")
+
+	threadID := uuid.New()
+	debug := &DebugInfo{
+		SystemPrompt:      "be brief",
+		RawRequestJSON:    `{"input":"hello"}`,
+		RawResponseJSON:   `{"output":"hi"}`,
+		RenderedHTML:      "<p>hi</p>",
+		FinalSystemPrompt: "be brief\n\nThe following files are attached...",
+		FinalUserPrompt:   "hello",
+		TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+		ValidationAttempts: []ValidationAttempt{
+			{Attempt: 1, Provider: "gemini", Problems: []string{"the response is empty"}},
+		},
+	}
+	citations := []Citation{{Type: "url", URL: "https://example.com"}}
+	err := repo.PersistConversationTurnWithDebug(ctx, threadID, "user-1", "hello", "hi", "gemini", "gemini-2.5-flash", "resp-1",
+		10, 20, 150, 0.01, 1, 0.014, debug, citations, nil)
+	if err != nil {
+		t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond) // NOW() has millisecond resolution in SQLite
+	if err := repo.PersistConversationTurn(ctx, threadID, "user-1", "again", "[FAILED] boom", "gemini", "gemini-2.5-flash", "", 0, 0, 5, 0); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+
+	feed, err := repo.GetActivityFeed(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetActivityFeed failed: %v", err)
+	}
+	if len(feed) != 2 {
+		t.Fatalf("expected 2 activity entries, got %d", len(feed))
+	}
+	if feed[0].Status != "failed" || feed[0].Content != "boom" {
+		t.Errorf("expected newest entry to be the failed turn, got %+v", feed[0])
+	}
+	if feed[1].TenantID != "email4ai" || feed[1].GroundingQueries != 1 || feed[1].ThreadCostUSD != 0.01 {
+		t.Errorf("unexpected activity entry: %+v", feed[1])
+	}
+
+	all, err := NewRepository(client).GetActivityFeedAllTenants(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetActivityFeedAllTenants failed: %v", err)
+	}
+	if len(all) != 2 || all[0].TenantID != "email4ai" {
+		t.Errorf("unexpected all-tenant feed: %+v", all)
+	}
+
+	data, err := NewRepository(client).GetDebugDataAllTenants(ctx, feed[1].ID)
+	if err != nil {
+		t.Fatalf("GetDebugDataAllTenants failed: %v", err)
+	}
+	if data.UserInput != "hello" || data.SystemPrompt != "be brief" || data.RenderedHTML != "<p>hi</p>" {
+		t.Errorf("unexpected debug data: %+v", data)
+	}
+	if data.RawRequestJSON != debug.RawRequestJSON || data.Citations == "" {
+		t.Errorf("expected raw JSON and citations, got %q / %q", data.RawRequestJSON, data.Citations)
+	}
+	if len(data.ValidationAttempts) != 1 || data.ValidationAttempts[0].Problems[0] != "the response is empty" {
+		t.Errorf("unexpected validation attempts: %+v", data.ValidationAttempts)
+	}
+	if data.FinalSystemPrompt != debug.FinalSystemPrompt || data.FinalUserPrompt != "hello" {
+		t.Errorf("unexpected final prompts: %q / %q", data.FinalSystemPrompt, data.FinalUserPrompt)
+	}
+	if data.TraceID != debug.TraceID {
+		t.Errorf("expected trace ID %q, got %q", debug.TraceID, data.TraceID)
+	}
+
+	conv, err := NewRepository(client).GetThreadConversationAllTenants(ctx, threadID)
+	if err != nil {
+		t.Fatalf("GetThreadConversationAllTenants failed: %v", err)
+	}
+	if conv.TenantID != "email4ai" || conv.MessageCount != 4 || len(conv.Messages) != 4 {
+		t.Errorf("unexpected conversation: tenant=%s count=%d messages=%d", conv.TenantID, conv.MessageCount, len(conv.Messages))
+	}
+	if conv.Messages[1].RenderedHTML != "<p>hi</p>" {
+		t.Errorf("expected rendered HTML on assistant message, got %q", conv.Messages[1].RenderedHTML)
+	}
+	if len(conv.Messages[1].Citations) != 1 || conv.Messages[1].Citations[0].URL != "https://example.com" {
+		t.Errorf("expected citations on assistant message, got %+v", conv.Messages[1].Citations)
+	}
+}
+
+func TestSQLite_SupersedeMessages(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	threadID := uuid.New()
+	for _, turn := range []string{"one", "two", "three"} {
+		if err := repo.PersistConversationTurn(ctx, threadID, "user-1", turn, "re: "+turn, "openai", "gpt-4o", "", 1, 2, 3, 0.01); err != nil {
+			t.Fatalf("PersistConversationTurn failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	messages, err := repo.GetActiveMessages(ctx, threadID)
+	if err != nil || len(messages) != 6 {
+		t.Fatalf("GetActiveMessages = %d messages, %v; want 6", len(messages), err)
+	}
+	for i, want := range []string{"one", "re: one", "two", "re: two", "three", "re: three"} {
+		if messages[i].Content != want {
+			t.Fatalf("message %d = %q, want %q", i, messages[i].Content, want)
+		}
+	}
+
+	// Replace the last two turns
+	var ids []uuid.UUID
+	for _, m := range messages[2:] {
+		ids = append(ids, m.ID)
+	}
+	n, err := repo.SupersedeMessages(ctx, threadID, ids)
+	if err != nil || n != 4 {
+		t.Fatalf("SupersedeMessages = %d, %v; want 4", n, err)
+	}
+	if n, _ := repo.SupersedeMessages(ctx, threadID, ids); n != 0 {
+		t.Errorf("superseding again marked %d messages", n)
+	}
+
+	active, _ := repo.GetActiveMessages(ctx, threadID)
+	recent, _ := repo.GetMessages(ctx, threadID, 10)
+	conv, _ := repo.GetThreadConversation(ctx, threadID)
+	if len(active) != 2 || len(recent) != 2 || len(conv.Messages) != 2 || conv.MessageCount != 2 {
+		t.Errorf("expected superseded messages to be hidden: active=%d recent=%d conversation=%d count=%d",
+			len(active), len(recent), len(conv.Messages), conv.MessageCount)
+	}
+	if _, err := repo.GetDebugData(ctx, messages[3].ID); err != nil {
+		t.Errorf("superseded message no longer available for debugging: %v", err)
+	}
+}
+
+func TestSQLite_Branches(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	threadID := uuid.New()
+	for _, turn := range []string{"one", "two"} {
+		if err := repo.PersistConversationTurn(ctx, threadID, "user-1", turn, "re: "+turn, "openai", "gpt-4o", "", 1, 2, 3, 0.01); err != nil {
+			t.Fatalf("PersistConversationTurn failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	messages, _ := repo.GetActiveMessages(ctx, threadID)
+	if messages[0].ParentMessageID != nil || messages[3].ParentMessageID == nil || *messages[3].ParentMessageID != messages[2].ID {
+		t.Fatalf("turns not chained: %+v", messages)
+	}
+
+	// An alternative reply to "two", then an edit of "two"
+	altID := uuid.New()
+	err := repo.PersistConversationTurnWithDebug(ctx, threadID, "user-1", "", "re: two, again", "openai", "gpt-4o", "",
+		1, 2, 3, 0.01, 0, 0, nil, nil, &TurnPlacement{UserMessageID: messages[2].ID, ReplyID: altID})
+	if err != nil {
+		t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+	}
+	repo.SupersedeMessages(ctx, threadID, []uuid.UUID{messages[3].ID})
+	time.Sleep(5 * time.Millisecond)
+	err = repo.PersistConversationTurnWithDebug(ctx, threadID, "user-1", "two, edited", "re: two, edited", "openai", "gpt-4o", "",
+		1, 2, 3, 0.01, 0, 0, nil, nil, &TurnPlacement{ParentID: messages[1].ID})
+	if err != nil {
+		t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+	}
+	repo.SupersedeMessages(ctx, threadID, []uuid.UUID{messages[2].ID, altID})
+
+	replies, err := repo.GetBranches(ctx, altID)
+	if err != nil || len(replies) != 2 || replies[0].ID != messages[3].ID || replies[0].SupersededAt == nil {
+		t.Fatalf("GetBranches(reply) = %+v, %v", replies, err)
+	}
+	edits, _ := repo.GetBranches(ctx, messages[2].ID)
+	if len(edits) != 2 || edits[1].Content != "two, edited" || edits[1].SupersededAt != nil {
+		t.Fatalf("GetBranches(user) = %+v", edits)
+	}
+	if roots, _ := repo.GetBranches(ctx, messages[0].ID); len(roots) != 1 {
+		t.Errorf("expected a single root, got %d", len(roots))
+	}
+	if missing, err := repo.GetBranches(ctx, uuid.New()); err != nil || missing != nil {
+		t.Errorf("GetBranches(unknown) = %v, %v", missing, err)
+	}
+
+	// Selecting the original "two" follows the reply that was current last
+	path, err := repo.SelectBranch(ctx, threadID, messages[2].ID)
+	if err != nil {
+		t.Fatalf("SelectBranch failed: %v", err)
+	}
+	want := []uuid.UUID{messages[0].ID, messages[1].ID, messages[2].ID, altID}
+	if !slices.Equal(path, want) {
+		t.Fatalf("SelectBranch = %v, want %v", path, want)
+	}
+	active, _ := repo.GetActiveMessages(ctx, threadID)
+	thread, _ := repo.GetThread(ctx, threadID)
+	if len(active) != 4 || active[3].Content != "re: two, again" || thread.MessageCount != 4 {
+		t.Errorf("unexpected current branch: %d messages, count %d", len(active), thread.MessageCount)
+	}
+
+	// A new turn continues the selected branch
+	if err := repo.PersistConversationTurn(ctx, threadID, "user-1", "three", "re: three", "openai", "gpt-4o", "", 1, 2, 3, 0.01); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+	active, _ = repo.GetActiveMessages(ctx, threadID)
+	if len(active) != 6 || *active[4].ParentMessageID != altID {
+		t.Errorf("new turn not appended to the selected branch: %+v", active)
+	}
+
+	if path, err := repo.SelectBranch(ctx, threadID, uuid.New()); err != nil || path != nil {
+		t.Errorf("SelectBranch(unknown) = %v, %v", path, err)
+	}
+}
+
+func TestSQLite_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "zztest")
+
+	if _, err := repo.GetDebugData(ctx, uuid.New()); err == nil {
+		t.Error("expected error for unknown message")
+	}
+	if _, err := repo.GetThreadConversation(ctx, uuid.New()); err == nil {
+		t.Error("expected error for unknown thread")
+	}
+}
+
+func TestSQLite_Memories(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t, "ai8")
+
+	name := NewMemory("user-1", MemoryCategoryName, "Name is Sam")
+	pref := NewMemory("user-1", MemoryCategoryPreference, "Prefers short answers")
+	pref.UpdatedAt = name.UpdatedAt.Add(time.Minute)
+	for _, mem := range []*Memory{name, pref} {
+		if err := repo.UpsertMemory(ctx, mem); err != nil {
+			t.Fatalf("UpsertMemory failed: %v", err)
+		}
+	}
+
+	// Re-observing a fact refreshes it instead of duplicating it
+	again := NewMemory("user-1", MemoryCategoryName, "Name is Sam")
+	again.UpdatedAt = name.UpdatedAt.Add(time.Hour)
+	if err := repo.UpsertMemory(ctx, again); err != nil {
+		t.Fatalf("UpsertMemory failed: %v", err)
+	}
+
+	memories, err := repo.ListMemories(ctx, "user-1", 10)
+	if err != nil {
+		t.Fatalf("ListMemories failed: %v", err)
+	}
+	if len(memories) != 2 || memories[0].Content != "Name is Sam" {
+		t.Fatalf("unexpected memories: %+v", memories)
+	}
+
+	deleted, err := repo.DeleteMemory(ctx, "user-1", memories[1].ID)
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteMemory = %d, %v; want 1", deleted, err)
+	}
+	deleted, err = repo.DeleteUserMemories(ctx, "user-1")
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteUserMemories = %d, %v; want 1", deleted, err)
+	}
+}
+
+func TestSQLite_UpgradeAddsKeyID(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "airborne.db")
+
+	// Simulate a database created before key attribution existed
+	legacy, err := sql.Open("sqlite", sqliteDSN(path))
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE ai8_airborne_messages (id TEXT PRIMARY KEY, thread_id TEXT, role TEXT, content TEXT, created_at TIMESTAMP)`,
+		`CREATE TABLE airborne_activity_rollups (granularity TEXT, bucket_start TIMESTAMP, tenant_id TEXT, provider TEXT, model TEXT)`,
+		`CREATE TABLE ai8_airborne_threads (id TEXT PRIMARY KEY, user_id TEXT NOT NULL, provider TEXT, model TEXT, status TEXT NOT NULL DEFAULT 'active', message_count INTEGER NOT NULL DEFAULT 0, created_at TIMESTAMP NOT NULL, updated_at TIMESTAMP NOT NULL, metadata TEXT)`,
+		`INSERT INTO ai8_airborne_threads (id, user_id, provider, model, created_at, updated_at) VALUES ('t-1', 'user-1', 'gemini', 'gemini-2.5-flash', '2026-01-01 10:00:00', '2026-01-01 10:00:00')`,
+		`CREATE TABLE ai8_airborne_user_profiles (user_id TEXT PRIMARY KEY, display_name TEXT NOT NULL DEFAULT '', language TEXT NOT NULL DEFAULT '', tone TEXT NOT NULL DEFAULT '', custom_instructions TEXT NOT NULL DEFAULT '', created_at TIMESTAMP NOT NULL, updated_at TIMESTAMP NOT NULL)`,
+		`INSERT INTO ai8_airborne_messages VALUES ('m-2', 't-1', 'assistant', 'hi', '2026-01-01 10:00:00'), ('m-1', 't-1', 'user', 'hello', '2026-01-01 10:00:00')`,
+	} {
+		if _, err := legacy.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("legacy schema: %v", err)
+		}
+	}
+	legacy.Close()
+
+	client, err := NewClient(ctx, Config{Driver: DriverSQLite, URL: path})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	sqlDB := client.backend.(*sqliteBackend).db
+	for _, table := range []string{"ai8_airborne_messages", rollupsTable} {
+		ok, err := sqliteHasColumn(ctx, sqlDB, table, "key_id")
+		if err != nil || !ok {
+			t.Errorf("%s missing key_id after upgrade (err=%v)", table, err)
+		}
+	}
+	if ok, err := sqliteHasColumn(ctx, sqlDB, "ai8_airborne_messages", "superseded_at"); err != nil || !ok {
+		t.Errorf("messages missing superseded_at after upgrade (err=%v)", err)
+	}
+	if ok, err := sqliteHasColumn(ctx, sqlDB, "ai8_airborne_user_profiles", "timezone"); err != nil || !ok {
+		t.Errorf("user profiles missing timezone after upgrade (err=%v)", err)
+	}
+	var pinned sql.NullString
+	if err := sqlDB.QueryRowContext(ctx, "SELECT pinned_provider FROM ai8_airborne_threads WHERE id = 't-1'").Scan(&pinned); err != nil || pinned.String != "gemini" {
+		t.Errorf("thread pin after upgrade = %v, %v; want the last-used provider", pinned, err)
+	}
+
+	// Existing threads become a single branch
+	var parent sql.NullString
+	if err := sqlDB.QueryRowContext(ctx, "SELECT parent_message_id FROM ai8_airborne_messages WHERE id = 'm-2'").Scan(&parent); err != nil || parent.String != "m-1" {
+		t.Errorf("assistant parent after upgrade = %v, %v; want m-1", parent, err)
+	}
+}
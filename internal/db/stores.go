@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// fileStoreUsageTable is the store registry: files and bytes uploaded to
+// each file store, shared across tenants and keyed by tenant_id and store_id.
+const fileStoreUsageTable = "airborne_file_store_usage"
+
+// StoreUsage is a file store's entry in the store registry.
+type StoreUsage struct {
+	FileCount  int
+	TotalBytes int64
+}
+
+// GetStoreUsage returns a store's recorded usage, or zero usage for a store
+// with no uploads.
+func (r *Repository) GetStoreUsage(ctx context.Context, tenantID, storeID string) (StoreUsage, error) {
+	if err := r.checkTenantArg(ctx, "GetStoreUsage", tenantID); err != nil {
+		return StoreUsage{}, err
+	}
+	query := fmt.Sprintf(`SELECT file_count, total_bytes FROM %s WHERE tenant_id = $1 AND store_id = $2`, fileStoreUsageTable)
+	r.client.logQuery(query, tenantID, storeID)
+
+	var u StoreUsage
+	err := r.client.backend.QueryRow(ctx, query, tenantID, storeID).Scan(&u.FileCount, &u.TotalBytes)
+	if errors.Is(err, errNoRows) {
+		return StoreUsage{}, nil
+	}
+	if err != nil {
+		return StoreUsage{}, fmt.Errorf("failed to query store usage: %w", err)
+	}
+	return u, nil
+}
+
+// GetTenantStorageBytes returns the bytes recorded across all of a tenant's
+// stores.
+func (r *Repository) GetTenantStorageBytes(ctx context.Context, tenantID string) (int64, error) {
+	if err := r.checkTenantArg(ctx, "GetTenantStorageBytes", tenantID); err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf(`SELECT COALESCE(SUM(total_bytes), 0) FROM %s WHERE tenant_id = $1`, fileStoreUsageTable)
+	r.client.logQuery(query, tenantID)
+
+	var total int64
+	if err := r.client.backend.QueryRow(ctx, query, tenantID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to query tenant storage: %w", err)
+	}
+	return total, nil
+}
+
+// RecordStoreUpload adds an uploaded file to a store's usage.
+func (r *Repository) RecordStoreUpload(ctx context.Context, tenantID, storeID, provider string, bytes int64) error {
+	if err := r.checkTenantArg(ctx, "RecordStoreUpload", tenantID); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (tenant_id, store_id, provider, file_count, total_bytes, updated_at)
+		VALUES ($1, $2, $3, 1, $4, NOW())
+		ON CONFLICT (tenant_id, store_id) DO UPDATE
+		SET file_count = %s.file_count + 1,
+		    total_bytes = %s.total_bytes + excluded.total_bytes,
+		    updated_at = excluded.updated_at
+	`, fileStoreUsageTable, fileStoreUsageTable, fileStoreUsageTable)
+	r.client.logQuery(query, tenantID, storeID, provider, bytes)
+
+	if _, err := r.client.backend.Exec(ctx, query, tenantID, storeID, provider, bytes); err != nil {
+		return fmt.Errorf("failed to record store upload: %w", err)
+	}
+	return nil
+}
+
+// DeleteStoreUsage removes a deleted store from the registry.
+func (r *Repository) DeleteStoreUsage(ctx context.Context, tenantID, storeID string) error {
+	if err := r.checkTenantArg(ctx, "DeleteStoreUsage", tenantID); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE tenant_id = $1 AND store_id = $2`, fileStoreUsageTable)
+	r.client.logQuery(query, tenantID, storeID)
+
+	if _, err := r.client.backend.Exec(ctx, query, tenantID, storeID); err != nil {
+		return fmt.Errorf("failed to delete store usage: %w", err)
+	}
+	return nil
+}
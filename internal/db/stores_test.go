@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/isolation"
+)
+
+func TestStoreUsage(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(newOutboxRepo(t).client)
+
+	if u, err := repo.GetStoreUsage(ctx, "acme", "docs"); err != nil || u != (StoreUsage{}) {
+		t.Fatalf("expected zero usage for an unknown store, got %+v, %v", u, err)
+	}
+
+	for _, rec := range []struct {
+		store string
+		bytes int64
+	}{{"docs", 100}, {"docs", 50}, {"notes", 25}} {
+		if err := repo.RecordStoreUpload(ctx, "acme", rec.store, "internal", rec.bytes); err != nil {
+			t.Fatalf("RecordStoreUpload failed: %v", err)
+		}
+	}
+	if err := repo.RecordStoreUpload(ctx, "other", "docs", "openai", 1000); err != nil {
+		t.Fatalf("RecordStoreUpload failed: %v", err)
+	}
+
+	u, err := repo.GetStoreUsage(ctx, "acme", "docs")
+	if err != nil {
+		t.Fatalf("GetStoreUsage failed: %v", err)
+	}
+	if u.FileCount != 2 || u.TotalBytes != 150 {
+		t.Errorf("usage = %+v, want 2 files and 150 bytes", u)
+	}
+	if total, err := repo.GetTenantStorageBytes(ctx, "acme"); err != nil || total != 175 {
+		t.Errorf("tenant storage = %d, %v, want 175", total, err)
+	}
+
+	if err := repo.DeleteStoreUsage(ctx, "acme", "docs"); err != nil {
+		t.Fatalf("DeleteStoreUsage failed: %v", err)
+	}
+	if total, err := repo.GetTenantStorageBytes(ctx, "acme"); err != nil || total != 25 {
+		t.Errorf("tenant storage after delete = %d, %v, want 25", total, err)
+	}
+}
+
+func TestStoreUsage_TenantChecked(t *testing.T) {
+	scoped := newOutboxRepo(t) // ai8
+	if _, err := scoped.GetStoreUsage(context.Background(), "email4ai", "docs"); !errors.Is(err, isolation.ErrCrossTenant) {
+		t.Errorf("tenant repository for another tenant: err = %v, want ErrCrossTenant", err)
+	}
+	if _, err := scoped.GetStoreUsage(context.Background(), "ai8", "docs"); err != nil {
+		t.Errorf("tenant repository for its own tenant: %v", err)
+	}
+
+	shared := NewRepository(scoped.client)
+	ctx := isolation.WithTenant(context.Background(), "ai8")
+	if _, err := shared.GetTenantStorageBytes(ctx, "email4ai"); !errors.Is(err, isolation.ErrCrossTenant) {
+		t.Errorf("request for another tenant: err = %v, want ErrCrossTenant", err)
+	}
+	if err := shared.RecordStoreUpload(ctx, "ai8", "docs", "internal", 10); err != nil {
+		t.Errorf("request for its own tenant: %v", err)
+	}
+}
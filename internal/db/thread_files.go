@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThreadFile is a file attached to a thread through the Gemini Files API.
+// Gemini deletes uploads after 48 hours; the registry keeps the file's
+// content so it can be uploaded again under a new URI.
+type ThreadFile struct {
+	ID          uuid.UUID
+	ThreadID    uuid.UUID
+	OriginalURI string // URI of the first upload, which clients keep referencing
+	FileURI     string // Current URI
+	Filename    string
+	MIMEType    string
+	SizeBytes   int64
+	Content     []byte // nil when the file was too large to keep
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// threadFilesTable returns the tenant-specific thread files table name.
+func (r *Repository) threadFilesTable() string {
+	if r.tablePrefix == "" {
+		return "airborne_thread_files" // Legacy table
+	}
+	return r.tablePrefix + "_thread_files"
+}
+
+// AddThreadFile registers a file uploaded for a thread. The thread must
+// exist. Registering the same URI again replaces the earlier entry.
+func (r *Repository) AddThreadFile(ctx context.Context, f *ThreadFile) error {
+	if err := r.checkTenant(ctx, "AddThreadFile"); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, thread_id, original_uri, file_uri, filename, mime_type, size_bytes, content, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		ON CONFLICT (thread_id, original_uri) DO UPDATE
+		SET file_uri = excluded.file_uri,
+		    filename = excluded.filename,
+		    mime_type = excluded.mime_type,
+		    size_bytes = excluded.size_bytes,
+		    content = excluded.content,
+		    expires_at = excluded.expires_at,
+		    updated_at = excluded.updated_at
+	`, r.threadFilesTable())
+	r.client.logQuery(query, f.ID, f.ThreadID, f.OriginalURI, f.Filename, f.MIMEType, f.SizeBytes)
+
+	if _, err := r.client.backend.Exec(ctx, query,
+		f.ID, f.ThreadID, f.OriginalURI, f.Filename, f.MIMEType, f.SizeBytes, f.Content, f.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("failed to add thread file: %w", err)
+	}
+	return nil
+}
+
+// GetThreadFile returns a thread's file by its original or current URI, or
+// nil if the thread has no such file.
+func (r *Repository) GetThreadFile(ctx context.Context, threadID uuid.UUID, uri string) (*ThreadFile, error) {
+	if err := r.checkTenant(ctx, "GetThreadFile"); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT id, thread_id, original_uri, file_uri, filename, mime_type, size_bytes, content, expires_at, created_at, updated_at
+		FROM %s
+		WHERE thread_id = $1 AND (original_uri = $2 OR file_uri = $2)
+	`, r.threadFilesTable())
+	r.client.logQuery(query, threadID, uri)
+
+	var f ThreadFile
+	err := r.client.backend.QueryRow(ctx, query, threadID, uri).Scan(
+		&f.ID, &f.ThreadID, &f.OriginalURI, &f.FileURI, &f.Filename, &f.MIMEType,
+		&f.SizeBytes, &f.Content, &f.ExpiresAt, &f.CreatedAt, &f.UpdatedAt,
+	)
+	if errors.Is(err, errNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread file: %w", err)
+	}
+	return &f, nil
+}
+
+// UpdateThreadFileURI records a new upload of a thread file.
+func (r *Repository) UpdateThreadFileURI(ctx context.Context, id uuid.UUID, fileURI string, expiresAt time.Time) error {
+	if err := r.checkTenant(ctx, "UpdateThreadFileURI"); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`UPDATE %s SET file_uri = $2, expires_at = $3, updated_at = NOW() WHERE id = $1`, r.threadFilesTable())
+	r.client.logQuery(query, id, fileURI, expiresAt)
+
+	if _, err := r.client.backend.Exec(ctx, query, id, fileURI, expiresAt); err != nil {
+		return fmt.Errorf("failed to update thread file: %w", err)
+	}
+	return nil
+}
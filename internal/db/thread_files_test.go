@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestThreadFiles(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutboxRepo(t)
+	threadID := uuid.New()
+	if _, err := repo.GetOrCreateThread(ctx, threadID, "user-1"); err != nil {
+		t.Fatalf("GetOrCreateThread failed: %v", err)
+	}
+
+	expires := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	file := &ThreadFile{
+		ID:          uuid.New(),
+		ThreadID:    threadID,
+		OriginalURI: "https://example.com/files/a",
+		Filename:    "report.pdf",
+		MIMEType:    "application/pdf",
+		SizeBytes:   5,
+		Content:     []byte("hello"),
+		ExpiresAt:   expires,
+	}
+	if err := repo.AddThreadFile(ctx, file); err != nil {
+		t.Fatalf("AddThreadFile failed: %v", err)
+	}
+
+	got, err := repo.GetThreadFile(ctx, threadID, file.OriginalURI)
+	if err != nil || got == nil {
+		t.Fatalf("GetThreadFile = %v, %v", got, err)
+	}
+	if got.FileURI != file.OriginalURI || got.Filename != "report.pdf" || string(got.Content) != "hello" || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("unexpected file: %+v", got)
+	}
+
+	renewed := expires.Add(time.Hour)
+	if err := repo.UpdateThreadFileURI(ctx, got.ID, "https://example.com/files/b", renewed); err != nil {
+		t.Fatalf("UpdateThreadFileURI failed: %v", err)
+	}
+	for _, uri := range []string{file.OriginalURI, "https://example.com/files/b"} {
+		got, err := repo.GetThreadFile(ctx, threadID, uri)
+		if err != nil || got == nil || got.FileURI != "https://example.com/files/b" || !got.ExpiresAt.Equal(renewed) {
+			t.Errorf("GetThreadFile(%s) = %+v, %v", uri, got, err)
+		}
+	}
+
+	if got, err := repo.GetThreadFile(ctx, uuid.New(), file.OriginalURI); err != nil || got != nil {
+		t.Errorf("expected no file for another thread, got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,186 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+// ErrHostBlocked is returned for requests to hosts outside the audit
+// allowlist while enforcement is on.
+var ErrHostBlocked = errors.New("egress host not allowed")
+
+// AuditConfig controls egress auditing. When enabled, every outbound request
+// is logged and counted per host, provider and tenant. Enforce additionally
+// rejects requests to hosts not matched by AllowedHosts.
+type AuditConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Enforce      bool     `yaml:"enforce"`
+	AllowedHosts []string `yaml:"allowed_hosts"` // Hostnames, "*.suffix" wildcards, IPs or CIDRs
+}
+
+// Validate checks the allowlist entries and that enforcement has an allowlist.
+func (c AuditConfig) Validate() error {
+	if _, err := validation.ParseURLAllowlist(c.AllowedHosts); err != nil {
+		return err
+	}
+	if c.Enforce && !c.Enabled {
+		return fmt.Errorf("enforce requires enabled")
+	}
+	if c.Enforce && len(c.AllowedHosts) == 0 {
+		return fmt.Errorf("enforce requires allowed_hosts")
+	}
+	return nil
+}
+
+// Auditor records outbound requests and blocks disallowed hosts.
+type Auditor struct {
+	allow   *validation.URLAllowlist
+	enforce bool
+	metrics *metrics.Registry
+}
+
+// NewAuditor creates an auditor that reports to reg (which may be nil).
+// It returns nil when auditing is disabled.
+func NewAuditor(cfg AuditConfig, reg *metrics.Registry) (*Auditor, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	allow, _ := validation.ParseURLAllowlist(cfg.AllowedHosts)
+	return &Auditor{allow: allow, enforce: cfg.Enforce, metrics: reg}, nil
+}
+
+var auditor atomic.Pointer[Auditor]
+
+// SetAuditor installs the auditor used by all egress transports. Nil
+// disables auditing.
+func SetAuditor(a *Auditor) {
+	auditor.Store(a)
+}
+
+type attributionKey struct{}
+
+type attribution struct {
+	tenantID string
+	provider string
+}
+
+// WithAttribution tags outbound requests made with ctx with the tenant and
+// provider they are made for, so audit records can name them.
+func WithAttribution(ctx context.Context, tenantID, provider string) context.Context {
+	return context.WithValue(ctx, attributionKey{}, attribution{tenantID: tenantID, provider: provider})
+}
+
+// Audited wraps a round tripper so its requests go through the installed
+// auditor. Use it for outbound clients that do not need the provider proxy.
+func Audited(base http.RoundTripper) http.RoundTripper {
+	return &auditTransport{base: base}
+}
+
+type auditTransport struct {
+	base http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	a := auditor.Load()
+	if a == nil {
+		return t.base.RoundTrip(req)
+	}
+	return a.roundTrip(t.base, req)
+}
+
+func (a *Auditor) roundTrip(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	attr, _ := req.Context().Value(attributionKey{}).(attribution)
+	obs := metrics.EgressObservation{
+		Host:     req.URL.Hostname(),
+		Provider: attr.provider,
+		TenantID: attr.tenantID,
+	}
+
+	if a.enforce && !a.allow.Allows(obs.Host) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		obs.Blocked = true
+		a.record(obs, 0, ErrHostBlocked)
+		return nil, fmt.Errorf("%w: %s", ErrHostBlocked, obs.Host)
+	}
+
+	if req.ContentLength > 0 {
+		obs.BytesSent = req.ContentLength
+	}
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		obs.Latency = time.Since(start)
+		a.record(obs, 0, err)
+		return nil, err
+	}
+
+	// Streaming responses are recorded once the body is drained or closed
+	resp.Body = &auditBody{ReadCloser: resp.Body, done: func(n int64) {
+		obs.BytesReceived = n
+		obs.Latency = time.Since(start)
+		a.record(obs, resp.StatusCode, nil)
+	}}
+	return resp, nil
+}
+
+// record logs the request and adds it to the egress metrics.
+func (a *Auditor) record(obs metrics.EgressObservation, statusCode int, err error) {
+	a.metrics.ObserveEgress(obs)
+
+	args := []any{
+		"host", obs.Host,
+		"provider", obs.Provider,
+		"tenant_id", obs.TenantID,
+		"bytes_sent", obs.BytesSent,
+		"bytes_received", obs.BytesReceived,
+		"duration_ms", obs.Latency.Milliseconds(),
+	}
+	if statusCode != 0 {
+		args = append(args, "status", statusCode)
+	}
+	switch {
+	case obs.Blocked:
+		slog.Warn("egress blocked", args...)
+	case err != nil:
+		slog.Info("egress", append(args, "error", err.Error())...)
+	default:
+		slog.Info("egress", args...)
+	}
+}
+
+// auditBody counts response bytes and reports them once, at EOF or Close.
+type auditBody struct {
+	io.ReadCloser
+	n    int64
+	once sync.Once
+	done func(n int64)
+}
+
+func (b *auditBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if err == io.EOF {
+		b.once.Do(func() { b.done(b.n) })
+	}
+	return n, err
+}
+
+func (b *auditBody) Close() error {
+	b.once.Do(func() { b.done(b.n) })
+	return b.ReadCloser.Close()
+}
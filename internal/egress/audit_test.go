@@ -0,0 +1,105 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/metrics"
+)
+
+func TestAuditor_RecordsRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("hello world"))
+	}))
+	defer srv.Close()
+
+	reg := metrics.NewRegistry()
+	a, err := NewAuditor(AuditConfig{Enabled: true}, reg)
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	SetAuditor(a)
+	t.Cleanup(func() { SetAuditor(nil) })
+
+	ctx := WithAttribution(context.Background(), "tenant-a", "openai")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader("ping"))
+	resp, err := Client(nil, 0).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	snap := reg.Snapshot()
+	if len(snap.Egress) != 1 {
+		t.Fatalf("expected one egress series, got %+v", snap.Egress)
+	}
+	got := snap.Egress[0]
+	if got.Host != "127.0.0.1" || got.Provider != "openai" || got.TenantID != "tenant-a" {
+		t.Errorf("unexpected attribution: %+v", got)
+	}
+	if got.Requests != 1 || got.BytesSent != 4 || got.BytesReceived != 11 {
+		t.Errorf("unexpected counts: %+v", got)
+	}
+}
+
+func TestAuditor_EnforceBlocksUnlistedHosts(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+
+	reg := metrics.NewRegistry()
+	a, err := NewAuditor(AuditConfig{Enabled: true, Enforce: true, AllowedHosts: []string{"*.openai.com"}}, reg)
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	SetAuditor(a)
+	t.Cleanup(func() { SetAuditor(nil) })
+
+	_, err = Client(nil, 0).Get(srv.URL)
+	if !errors.Is(err, ErrHostBlocked) {
+		t.Fatalf("err = %v, want ErrHostBlocked", err)
+	}
+	if hits != 0 {
+		t.Error("blocked request reached the server")
+	}
+	if snap := reg.Snapshot(); len(snap.Egress) != 1 || snap.Egress[0].Blocked != 1 {
+		t.Errorf("expected a blocked egress record, got %+v", snap.Egress)
+	}
+
+	// Allowlisted hosts pass through
+	allowed, _ := NewAuditor(AuditConfig{Enabled: true, Enforce: true, AllowedHosts: []string{"127.0.0.1"}}, reg)
+	SetAuditor(allowed)
+	resp, err := Client(nil, 0).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("allowlisted request failed: %v", err)
+	}
+	resp.Body.Close()
+	if hits != 1 {
+		t.Errorf("hits = %d, want 1", hits)
+	}
+}
+
+func TestAuditConfig_Validate(t *testing.T) {
+	invalid := []AuditConfig{
+		{Enabled: true, Enforce: true},
+		{Enforce: true, AllowedHosts: []string{"api.openai.com"}},
+		{Enabled: true, AllowedHosts: []string{"10.0.0.0/99"}},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", c)
+		}
+	}
+	if a, err := NewAuditor(AuditConfig{}, nil); a != nil || err != nil {
+		t.Errorf("NewAuditor(disabled) = %v, %v; want nil, nil", a, err)
+	}
+}
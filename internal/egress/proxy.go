@@ -0,0 +1,194 @@
+// Package egress controls how outbound provider traffic leaves the server.
+package egress
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ai8future/airborne/internal/validation"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyConfig routes outbound requests through an HTTP, HTTPS or SOCKS5 proxy.
+type ProxyConfig struct {
+	URL     string   `json:"url,omitempty" yaml:"url,omitempty"`           // http://, https://, socks5:// or socks5h://
+	NoProxy []string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"` // Hosts, ".domain" suffixes or CIDRs sent directly
+}
+
+// IsZero reports whether no proxy is configured.
+func (p ProxyConfig) IsZero() bool {
+	return strings.TrimSpace(p.URL) == ""
+}
+
+// Validate checks the proxy URL and NoProxy entries.
+func (p ProxyConfig) Validate() error {
+	if p.IsZero() {
+		if len(p.NoProxy) > 0 {
+			return fmt.Errorf("no_proxy requires url")
+		}
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSpace(p.URL))
+	if err != nil {
+		return fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("proxy url scheme must be http, https, socks5 or socks5h, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("proxy url is missing a host")
+	}
+	for _, entry := range p.NoProxy {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.ContainsAny(entry, ", ") {
+			return fmt.Errorf("invalid no_proxy entry %q", entry)
+		}
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("invalid no_proxy CIDR %q: %w", entry, err)
+			}
+		}
+	}
+	return nil
+}
+
+// key identifies transports that can be shared.
+func (p ProxyConfig) key() string {
+	return strings.TrimSpace(p.URL) + "|" + strings.Join(p.NoProxy, ",")
+}
+
+var (
+	defaultProxy atomic.Pointer[ProxyConfig]
+	transports   sync.Map // ProxyConfig.key() -> *http.Transport
+
+	publicTransports sync.Map // ProxyConfig.key() -> *http.Transport for PublicTransport
+)
+
+// SetDefaultProxy sets the server-wide proxy used when a tenant configures
+// none. A zero config sends traffic directly (honoring the standard proxy
+// environment variables).
+func SetDefaultProxy(p ProxyConfig) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.IsZero() {
+		defaultProxy.Store(nil)
+		return nil
+	}
+	defaultProxy.Store(&p)
+	return nil
+}
+
+// Transport returns the round tripper for outbound provider requests. A nil
+// or zero p falls back to the server-wide proxy. Requests pass through the
+// installed Auditor, if any.
+func Transport(p *ProxyConfig) http.RoundTripper {
+	return Audited(proxyTransport(p))
+}
+
+// proxyTransport returns the transport for p. Transports are shared per
+// proxy configuration so connections are pooled across requests.
+func proxyTransport(p *ProxyConfig) http.RoundTripper {
+	if p == nil || p.IsZero() {
+		p = defaultProxy.Load()
+	}
+	if p == nil {
+		return http.DefaultTransport
+	}
+	key := p.key()
+	if t, ok := transports.Load(key); ok {
+		return t.(*http.Transport)
+	}
+
+	proxyURL := strings.TrimSpace(p.URL)
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    strings.Join(p.NoProxy, ","),
+	}).ProxyFunc()
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	actual, _ := transports.LoadOrStore(key, t)
+	return actual.(*http.Transport)
+}
+
+// PublicTransport is Transport(p) for requests to URLs a model chose: direct
+// connections may only reach public addresses. Each address a connection
+// dials, after DNS resolution, is checked with validation.DialControl, so a
+// hostname that passed validation cannot rebind to an internal address, and
+// redirects are checked the same way. Connections to the proxy itself are
+// exempt, as the proxy resolves the hostnames of the requests it carries.
+func PublicTransport(p *ProxyConfig) http.RoundTripper {
+	if p == nil || p.IsZero() {
+		p = defaultProxy.Load()
+	}
+	key := ""
+	if p != nil {
+		key = p.key()
+	}
+	if t, ok := publicTransports.Load(key); ok {
+		return Audited(t.(*http.Transport))
+	}
+
+	t := proxyTransport(p).(*http.Transport).Clone()
+	proxies := proxyAddrs(p)
+	direct := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	public := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: validation.DialControl}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if proxies[addr] {
+			return direct.DialContext(ctx, network, addr)
+		}
+		return public.DialContext(ctx, network, addr)
+	}
+	actual, _ := publicTransports.LoadOrStore(key, t)
+	return Audited(actual.(*http.Transport))
+}
+
+// proxyAddrs returns the host:port addresses of the proxies requests sent
+// with p go through: p's proxy, or without one those of the standard proxy
+// environment variables.
+func proxyAddrs(p *ProxyConfig) map[string]bool {
+	var urls []string
+	if p != nil {
+		urls = append(urls, p.URL)
+	} else {
+		env := httpproxy.FromEnvironment()
+		urls = append(urls, env.HTTPProxy, env.HTTPSProxy)
+	}
+	defaultPorts := map[string]string{"http": "80", "https": "443", "socks5": "1080", "socks5h": "1080"}
+	addrs := make(map[string]bool, len(urls))
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw // As the environment variables allow
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		port := cmp.Or(u.Port(), defaultPorts[u.Scheme])
+		addrs[net.JoinHostPort(u.Hostname(), port)] = true
+	}
+	return addrs
+}
+
+// Client returns an HTTP client that sends requests through Transport(p).
+func Client(p *ProxyConfig, timeout time.Duration) *http.Client {
+	return &http.Client{Transport: Transport(p), Timeout: timeout}
+}
@@ -0,0 +1,100 @@
+package egress
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+func proxyFor(t *testing.T, rt http.RoundTripper, rawURL string) string {
+	t.Helper()
+	tr, ok := rt.(*http.Transport)
+	if !ok {
+		t.Fatalf("Transport returned %T, want *http.Transport", rt)
+	}
+	req, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+	u, err := tr.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy(%s) failed: %v", rawURL, err)
+	}
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func TestTransport_ProxyAndNoProxy(t *testing.T) {
+	p := &ProxyConfig{URL: "socks5://proxy.corp.internal:1080", NoProxy: []string{".corp.internal", "10.0.0.0/8"}}
+	rt := proxyTransport(p)
+
+	if got := proxyFor(t, rt, "https://api.openai.com/v1/responses"); got != "socks5://proxy.corp.internal:1080" {
+		t.Errorf("provider request proxied via %q, want the socks5 proxy", got)
+	}
+	if got := proxyFor(t, rt, "https://gateway.corp.internal/v1"); got != "" {
+		t.Errorf("no_proxy domain proxied via %q, want direct", got)
+	}
+	if got := proxyFor(t, rt, "https://10.1.2.3/v1"); got != "" {
+		t.Errorf("no_proxy CIDR proxied via %q, want direct", got)
+	}
+	if proxyTransport(&ProxyConfig{URL: p.URL, NoProxy: p.NoProxy}) != rt {
+		t.Error("expected transports to be shared for the same proxy config")
+	}
+}
+
+func TestTransport_DefaultProxy(t *testing.T) {
+	t.Cleanup(func() { _ = SetDefaultProxy(ProxyConfig{}) })
+
+	if proxyTransport(nil) != http.DefaultTransport {
+		t.Error("expected http.DefaultTransport without a proxy")
+	}
+	if err := SetDefaultProxy(ProxyConfig{URL: "http://proxy.example.test:3128"}); err != nil {
+		t.Fatalf("SetDefaultProxy failed: %v", err)
+	}
+	if got := proxyFor(t, proxyTransport(nil), "https://generativelanguage.googleapis.com"); got != "http://proxy.example.test:3128" {
+		t.Errorf("default proxy = %q", got)
+	}
+	// A tenant proxy takes precedence over the default
+	if got := proxyFor(t, proxyTransport(&ProxyConfig{URL: "https://tenant-proxy.example.test"}), "https://api.anthropic.com"); got != "https://tenant-proxy.example.test" {
+		t.Errorf("tenant proxy = %q", got)
+	}
+}
+
+func TestProxyConfig_Validate(t *testing.T) {
+	invalid := []ProxyConfig{
+		{URL: "ftp://proxy.example.test"},
+		{URL: "http://"},
+		{NoProxy: []string{"localhost"}},
+		{URL: "http://proxy.example.test", NoProxy: []string{"10.0.0.0/99"}},
+		{URL: "http://proxy.example.test", NoProxy: []string{"a.com,b.com"}},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", p)
+		}
+	}
+	if err := (ProxyConfig{URL: "socks5h://proxy:1080", NoProxy: []string{".internal"}}).Validate(); err != nil {
+		t.Errorf("Validate failed for valid config: %v", err)
+	}
+}
+
+func TestPublicTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// The test server listens on loopback, as a rebound hostname would resolve
+	client := &http.Client{Transport: PublicTransport(nil)}
+	if _, err := client.Get(srv.URL); !errors.Is(err, validation.ErrPrivateIP) {
+		t.Errorf("direct request to loopback: err = %v, want ErrPrivateIP", err)
+	}
+
+	// The proxy itself may be internal; it resolves the requested host
+	client = &http.Client{Transport: PublicTransport(&ProxyConfig{URL: srv.URL})}
+	resp, err := client.Get("http://pages.example.com/")
+	if err != nil {
+		t.Fatalf("request through an internal proxy failed: %v", err)
+	}
+	resp.Body.Close()
+}
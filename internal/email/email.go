@@ -0,0 +1,375 @@
+// Package email parses inbound RFC 822 messages for the email ingestion
+// endpoint: it extracts the reply text and attachments, identifies the
+// conversation a message belongs to from its threading headers, and builds
+// the headers of the reply.
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Message limits.
+const (
+	MaxMessageBytes = 25 << 20 // Raw message size, as accepted by common mail providers
+	maxParts        = 100      // MIME parts walked, against nesting bombs
+)
+
+// ErrNoContent is returned for messages with neither text nor attachments.
+var ErrNoContent = errors.New("email has no text or attachments")
+
+// Attachment is a file attached to an email.
+type Attachment struct {
+	Filename string
+	MIMEType string
+	Content  []byte
+}
+
+// Message is a parsed inbound email.
+type Message struct {
+	From        *mail.Address
+	To          []*mail.Address
+	Cc          []*mail.Address
+	Subject     string
+	MessageID   string   // Without angle brackets
+	InReplyTo   []string // Without angle brackets
+	References  []string // Without angle brackets, oldest first
+	Text        string   // Body with quoted earlier messages removed
+	Attachments []Attachment
+}
+
+// Parse parses a raw RFC 822 message.
+func Parse(raw []byte) (*Message, error) {
+	if len(raw) > MaxMessageBytes {
+		return nil, fmt.Errorf("email exceeds %d MB", MaxMessageBytes>>20)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return nil, errors.New("email has no valid From address")
+	}
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	m := &Message{
+		From:       from[0],
+		To:         addressList(msg.Header, "To"),
+		Cc:         addressList(msg.Header, "Cc"),
+		Subject:    strings.TrimSpace(subject),
+		MessageID:  firstID(msg.Header.Get("Message-ID")),
+		InReplyTo:  messageIDs(msg.Header.Get("In-Reply-To")),
+		References: messageIDs(msg.Header.Get("References")),
+	}
+
+	b := &bodyParts{}
+	if err := b.walk(msg.Header, msg.Body); err != nil {
+		return nil, err
+	}
+	text := b.plain
+	if text == "" {
+		text = HTMLToText(b.html)
+	}
+	m.Text = StripQuoted(text)
+	m.Attachments = b.attachments
+	if m.Text == "" && len(m.Attachments) == 0 {
+		return nil, ErrNoContent
+	}
+	return m, nil
+}
+
+// ThreadKey returns the Message-ID of the first message of the conversation:
+// the oldest reference, else the message replied to, else the message's own
+// ID. Every reply in a conversation that keeps its References header shares
+// the key. It is empty when the message has none of these headers.
+func (m *Message) ThreadKey() string {
+	if len(m.References) > 0 {
+		return m.References[0]
+	}
+	if len(m.InReplyTo) > 0 {
+		return m.InReplyTo[0]
+	}
+	return m.MessageID
+}
+
+// Reply holds the headers of a reply to a message.
+type Reply struct {
+	To         string `json:"to"`
+	Subject    string `json:"subject"`
+	InReplyTo  string `json:"in_reply_to,omitempty"`
+	References string `json:"references,omitempty"`
+}
+
+// ReplyHeaders returns the headers that thread a reply to m in the sender's
+// mail client.
+func (m *Message) ReplyHeaders() Reply {
+	r := Reply{To: m.From.String(), Subject: replySubject(m.Subject)}
+	if m.MessageID != "" {
+		r.InReplyTo = "<" + m.MessageID + ">"
+		refs := append(append([]string{}, m.References...), m.MessageID)
+		for i, ref := range refs {
+			refs[i] = "<" + ref + ">"
+		}
+		r.References = strings.Join(refs, " ")
+	}
+	return r
+}
+
+// replyPrefix matches a reply prefix already on a subject.
+var replyPrefix = regexp.MustCompile(`(?i)^re\s*:`)
+
+// replySubject prefixes subject with "Re:" unless it already has it.
+func replySubject(subject string) string {
+	if replyPrefix.MatchString(subject) {
+		return subject
+	}
+	return strings.TrimSpace("Re: " + subject)
+}
+
+// quoteHeader matches the line mail clients put above a quoted message,
+// e.g. "On Mon, 1 Jan 2026 at 10:00, Ana <ana@example.com> wrote:".
+var quoteHeader = regexp.MustCompile(`^(On\s.+\swrote|Am\s.+\sschrieb|Le\s.+\sa écrit)\s*:\s*$`)
+
+// StripQuoted removes the earlier messages a reply quotes: everything from
+// an attribution line ("On ... wrote:") or an Outlook "-----Original
+// Message-----" separator, and any remaining "> " lines. Threads are
+// continued from stored history, so the quoted text would only repeat it.
+func StripQuoted(text string) string {
+	var kept []string
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(text, "\r\n", "\n")))
+	scanner.Buffer(make([]byte, 64*1024), MaxMessageBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if quoteHeader.MatchString(trimmed) || strings.HasPrefix(trimmed, "-----Original Message-----") {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// bodyParts collects the first text/plain and text/html bodies and every
+// attachment of a message.
+type bodyParts struct {
+	plain       string
+	html        string
+	attachments []Attachment
+	parts       int
+}
+
+// walk collects the MIME entity with header h and body r.
+func (b *bodyParts) walk(h header, r io.Reader) error {
+	b.parts++
+	if b.parts > maxParts {
+		return fmt.Errorf("email has more than %d MIME parts", maxParts)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("invalid multipart body: %w", err)
+			}
+			if err := b.walk(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransfer(h.Get("Content-Transfer-Encoding"), r))
+	if err != nil {
+		return fmt.Errorf("invalid %s body: %w", mediaType, err)
+	}
+
+	disposition, dparams, _ := mime.ParseMediaType(h.Get("Content-Disposition"))
+	filename := dparams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if disposition == "attachment" || filename != "" || !strings.HasPrefix(mediaType, "text/") {
+		if len(content) > 0 {
+			b.attachments = append(b.attachments, Attachment{Filename: filename, MIMEType: mediaType, Content: content})
+		}
+		return nil
+	}
+
+	switch mediaType {
+	case "text/html":
+		if b.html == "" {
+			b.html = string(content)
+		}
+	default:
+		if b.plain == "" {
+			b.plain = string(content)
+		}
+	}
+	return nil
+}
+
+// header is the Get method shared by mail.Header and textproto.MIMEHeader.
+type header interface {
+	Get(key string) string
+}
+
+// decodeTransfer undoes a Content-Transfer-Encoding.
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineStripper{r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// newlineStripper drops the line breaks base64 bodies are wrapped with.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	for {
+		read, err := n.r.Read(p)
+		kept := 0
+		for _, c := range p[:read] {
+			if c != '\r' && c != '\n' && c != ' ' && c != '\t' {
+				p[kept] = c
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+// HTMLToText returns the visible text of an HTML body, one line per block.
+func HTMLToText(body string) string {
+	if body == "" {
+		return ""
+	}
+	var sb strings.Builder
+	z := html.NewTokenizer(strings.NewReader(body))
+	skip := 0 // Depth inside <script>, <style> or <head>
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(collapseBlankLines(sb.String()))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				skip++
+			case "br", "p", "div", "li", "tr", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote":
+				newline(&sb)
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				skip = max(skip-1, 0)
+			case "p", "div", "li", "tr", "h1", "h2", "h3", "h4", "h5", "h6":
+				newline(&sb)
+			}
+		case html.TextToken:
+			if skip == 0 {
+				sb.WriteString(whitespace.ReplaceAllString(string(z.Text()), " "))
+			}
+		}
+	}
+}
+
+// whitespace matches the runs of whitespace HTML renders as one space,
+// including non-breaking spaces.
+var whitespace = regexp.MustCompile(`[\s\x{00a0}]+`)
+
+// newline ends the current line of sb unless it is already ended.
+func newline(sb *strings.Builder) {
+	if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+}
+
+// collapseBlankLines trims lines and keeps at most one blank line in a row.
+func collapseBlankLines(s string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addressList returns the parsed addresses of a header, or nil when it is
+// missing or malformed.
+func addressList(h mail.Header, key string) []*mail.Address {
+	list, err := h.AddressList(key)
+	if err != nil {
+		return nil
+	}
+	return list
+}
+
+// messageID matches one <id> of a Message-ID, In-Reply-To or References header.
+var messageID = regexp.MustCompile(`<([^<>\s]+)>`)
+
+// messageIDs returns the IDs of a threading header, without angle brackets.
+// Headers without brackets are taken as whitespace-separated IDs.
+func messageIDs(value string) []string {
+	var ids []string
+	for _, match := range messageID.FindAllStringSubmatch(value, -1) {
+		ids = append(ids, match[1])
+	}
+	if ids == nil {
+		ids = strings.Fields(value)
+	}
+	return ids
+}
+
+func firstID(value string) string {
+	if ids := messageIDs(value); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
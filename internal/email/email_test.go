@@ -0,0 +1,125 @@
+package email
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const multipartReply = "From: Ana Silva <ana@example.com>\r\n" +
+	"To: assistant@example.org\r\n" +
+	"Subject: =?UTF-8?Q?Re:_Caf=C3=A9_order?=\r\n" +
+	"Message-ID: <m3@example.com>\r\n" +
+	"In-Reply-To: <m2@example.org>\r\n" +
+	"References: <m1@example.com> <m2@example.org>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Two more, please =E2=80=93 thanks!\r\n" +
+	"\r\n" +
+	"On Mon, 5 Oct 2026 at 10:00, Assistant <assistant@example.org> wrote:\r\n" +
+	"> Your order is confirmed.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Two more, please</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=\"order.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"order.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0x\r\n" +
+	"LjQK\r\n" +
+	"--outer--\r\n"
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(multipartReply))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if m.From.Address != "ana@example.com" || m.From.Name != "Ana Silva" {
+		t.Errorf("From = %v", m.From)
+	}
+	if m.Subject != "Re: Café order" {
+		t.Errorf("Subject = %q, want the decoded subject", m.Subject)
+	}
+	if m.Text != "Two more, please – thanks!" {
+		t.Errorf("Text = %q, want the plain body without the quoted message", m.Text)
+	}
+	if len(m.Attachments) != 1 || m.Attachments[0].Filename != "order.pdf" || string(m.Attachments[0].Content) != "%PDF-1.4\n" {
+		t.Errorf("Attachments = %+v, want the decoded PDF", m.Attachments)
+	}
+	if m.ThreadKey() != "m1@example.com" {
+		t.Errorf("ThreadKey = %q, want the oldest reference", m.ThreadKey())
+	}
+
+	reply := m.ReplyHeaders()
+	if reply.Subject != "Re: Café order" || reply.InReplyTo != "<m3@example.com>" {
+		t.Errorf("unexpected reply headers: %+v", reply)
+	}
+	if reply.References != "<m1@example.com> <m2@example.org> <m3@example.com>" {
+		t.Errorf("References = %q", reply.References)
+	}
+}
+
+func TestParse_HTMLOnly(t *testing.T) {
+	raw := "From: ana@example.com\r\nSubject: Hello\r\nMessage-ID: <first@example.com>\r\n" +
+		"Content-Type: text/html\r\n\r\n" +
+		"<html><head><style>p{}</style></head><body><p>Hi&nbsp;there,</p><div>Where is my <b>order</b>?</div></body></html>"
+	m, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if m.Text != "Hi there,\nWhere is my order?" {
+		t.Errorf("Text = %q", m.Text)
+	}
+	if m.ThreadKey() != "first@example.com" {
+		t.Errorf("ThreadKey = %q, want the message's own ID", m.ThreadKey())
+	}
+	if got := m.ReplyHeaders().Subject; got != "Re: Hello" {
+		t.Errorf("reply subject = %q", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"no from", "Subject: hi\r\n\r\nbody"},
+		{"not an email", "just some text"},
+		{"only quoted text", "From: ana@example.com\r\n\r\n> earlier message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.raw)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+	if _, err := Parse([]byte("From: ana@example.com\r\n\r\n\r\n")); !errors.Is(err, ErrNoContent) {
+		t.Errorf("err = %v, want ErrNoContent", err)
+	}
+}
+
+func TestStripQuoted(t *testing.T) {
+	text := strings.Join([]string{
+		"Sounds good.",
+		"> quoted line",
+		"See you then.",
+		"",
+		"-----Original Message-----",
+		"From: someone",
+	}, "\r\n")
+	if got := StripQuoted(text); got != "Sounds good.\nSee you then." {
+		t.Errorf("StripQuoted = %q", got)
+	}
+}
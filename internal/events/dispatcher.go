@@ -0,0 +1,186 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+)
+
+// Dispatcher defaults
+const (
+	DefaultDispatchInterval = 2 * time.Second
+	DefaultBatchSize        = 100
+	DefaultMaxAttempts      = 10
+	DefaultRetention        = 7 * 24 * time.Hour
+
+	// claimLease hides claimed events from other dispatchers while a batch is
+	// delivered; events still undelivered after it expires are retried.
+	claimLease = 5 * time.Minute
+
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = 15 * time.Minute
+	purgeInterval  = time.Hour
+)
+
+// DispatcherConfig tunes outbox delivery. Zero values use the defaults.
+type DispatcherConfig struct {
+	Interval    time.Duration // How often to poll the outbox
+	BatchSize   int           // Events claimed per poll
+	MaxAttempts int           // Attempts before an event is dead-lettered
+	Retention   time.Duration // How long delivered events are kept
+}
+
+// Dispatcher delivers outbox events to sinks at-least-once. Several instances
+// may run against the same database; each event is claimed by one at a time.
+type Dispatcher struct {
+	repo  *db.Repository
+	sinks []Sink
+	cfg   DispatcherConfig
+	now   func() time.Time
+}
+
+// NewDispatcher creates a dispatcher for the client's outbox.
+func NewDispatcher(client *db.Client, sinks []Sink, cfg DispatcherConfig) *Dispatcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultDispatchInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	return &Dispatcher{
+		repo:  db.NewRepository(client),
+		sinks: sinks,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Run delivers events until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	slog.Info("event dispatcher started", "sinks", len(d.sinks), "interval", d.cfg.Interval)
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	var lastPurge time.Time
+	for {
+		d.drain(ctx)
+		if d.now().Sub(lastPurge) >= purgeInterval {
+			d.purge(ctx)
+			lastPurge = d.now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain delivers full batches until the outbox has no more due events.
+func (d *Dispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		if d.dispatchBatch(ctx) < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// dispatchBatch claims and delivers one batch, returning the number claimed.
+func (d *Dispatcher) dispatchBatch(ctx context.Context) int {
+	events, err := d.repo.ClaimOutboxEvents(ctx, d.now(), d.cfg.BatchSize, claimLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("failed to claim outbox events", "error", err)
+		}
+		return 0
+	}
+
+	for _, ev := range events {
+		if ctx.Err() != nil {
+			// Unprocessed events become due again when their lease expires
+			break
+		}
+		d.deliver(ctx, ev)
+	}
+	return len(events)
+}
+
+// deliver sends an event to every sink and records the outcome. A failure on
+// any sink retries the event on all of them.
+func (d *Dispatcher) deliver(ctx context.Context, ev db.OutboxEvent) {
+	var deliverErr error
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, ev); err != nil {
+			deliverErr = fmt.Errorf("%s: %w", sink.Name(), err)
+			break
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	var err error
+	switch {
+	case deliverErr == nil:
+		err = d.repo.MarkOutboxDelivered(ctx, ev.ID)
+	case ev.Attempts >= d.cfg.MaxAttempts:
+		slog.Error("event delivery failed permanently",
+			"event_id", ev.ID,
+			"event_type", ev.EventType,
+			"tenant_id", ev.TenantID,
+			"attempts", ev.Attempts,
+			"error", deliverErr,
+		)
+		err = d.repo.DeadLetterOutboxEvent(ctx, ev.ID, deliverErr)
+	default:
+		retryAt := d.now().Add(retryDelay(ev.Attempts))
+		slog.Warn("event delivery failed, will retry",
+			"event_id", ev.ID,
+			"event_type", ev.EventType,
+			"attempts", ev.Attempts,
+			"retry_at", retryAt,
+			"error", deliverErr,
+		)
+		err = d.repo.RetryOutboxEvent(ctx, ev.ID, deliverErr, retryAt)
+	}
+	if err != nil {
+		slog.Error("failed to record event delivery", "event_id", ev.ID, "error", err)
+	}
+}
+
+// purge deletes delivered events older than the retention period.
+func (d *Dispatcher) purge(ctx context.Context) {
+	n, err := d.repo.PurgeDeliveredOutbox(ctx, d.now().Add(-d.cfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("failed to purge delivered events", "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		slog.Debug("purged delivered events", "count", n)
+	}
+}
+
+// retryDelay is the exponential backoff after the given attempt.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/google/uuid"
+)
+
+// fakeSink records deliveries and fails the first failures attempts.
+type fakeSink struct {
+	failures  int
+	delivered []uuid.UUID
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Deliver(_ context.Context, ev db.OutboxEvent) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.delivered = append(s.delivered, ev.ID)
+	return nil
+}
+
+// newOutboxClient opens a SQLite database with the outbox enabled and
+// persists one conversation turn into it.
+func newOutboxClient(t *testing.T) *db.Client {
+	t.Helper()
+	ctx := context.Background()
+	client, err := db.NewClient(ctx, db.Config{
+		Driver: db.DriverSQLite,
+		URL:    filepath.Join(t.TempDir(), "airborne.db"),
+		Outbox: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	repo, _ := client.TenantRepository("ai8")
+	if err := repo.PersistConversationTurn(ctx, uuid.New(), "user-1", "q", "a", "openai", "gpt-4o", "", 1, 2, 3, 0.01); err != nil {
+		t.Fatalf("PersistConversationTurn failed: %v", err)
+	}
+	return client
+}
+
+func TestDispatcher_RetriesUntilDelivered(t *testing.T) {
+	ctx := context.Background()
+	sink := &fakeSink{failures: 1}
+	d := NewDispatcher(newOutboxClient(t), []Sink{sink}, DispatcherConfig{})
+
+	now := time.Now().Add(time.Second)
+	d.now = func() time.Time { return now }
+
+	if n := d.dispatchBatch(ctx); n != 1 {
+		t.Fatalf("first batch claimed %d events, want 1", n)
+	}
+	if len(sink.delivered) != 0 {
+		t.Fatal("event delivered despite sink failure")
+	}
+
+	// Not due again until the backoff has passed
+	if n := d.dispatchBatch(ctx); n != 0 {
+		t.Fatalf("event retried before its backoff, claimed %d", n)
+	}
+
+	now = now.Add(retryDelay(1))
+	if n := d.dispatchBatch(ctx); n != 1 || len(sink.delivered) != 1 {
+		t.Fatalf("expected delivery on retry, claimed %d, delivered %d", n, len(sink.delivered))
+	}
+
+	now = now.Add(time.Hour)
+	if n := d.dispatchBatch(ctx); n != 0 {
+		t.Errorf("delivered event was claimed again")
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	sink := &fakeSink{failures: 100}
+	d := NewDispatcher(newOutboxClient(t), []Sink{sink}, DispatcherConfig{MaxAttempts: 2})
+
+	now := time.Now().Add(time.Second)
+	d.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if n := d.dispatchBatch(ctx); n != 1 {
+			t.Fatalf("attempt %d claimed %d events, want 1", i+1, n)
+		}
+		now = now.Add(retryMaxDelay)
+	}
+	if n := d.dispatchBatch(ctx); n != 0 {
+		t.Errorf("dead-lettered event was claimed again")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{20, retryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
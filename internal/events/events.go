@@ -0,0 +1,42 @@
+// Package events delivers outbox events (see db.OutboxEvent) to external sinks
+// such as webhooks. Delivery is at-least-once: receivers should deduplicate on
+// the event ID.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+)
+
+// Sink delivers events to one destination.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+
+	// Deliver sends one event. A nil error means the sink accepted it;
+	// any error schedules a retry of the whole event.
+	Deliver(ctx context.Context, event db.OutboxEvent) error
+}
+
+// Envelope is the wire format shared by all sinks.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	TenantID  string          `json:"tenant_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps an outbox event for delivery.
+func NewEnvelope(event db.OutboxEvent) Envelope {
+	return Envelope{
+		ID:        event.ID.String(),
+		Type:      event.EventType,
+		TenantID:  event.TenantID,
+		CreatedAt: event.CreatedAt.UTC(),
+		Data:      json.RawMessage(event.Payload),
+	}
+}
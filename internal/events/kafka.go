@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher writes events to a Kafka topic, keyed by tenant so each
+// tenant's events stay ordered within a partition.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher creates a publisher for topic on the given brokers.
+// Connections are made lazily on first publish.
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond, // Publishes are synchronous; don't wait to fill batches
+		},
+	}
+}
+
+// Publish implements Publisher.
+func (p *KafkaPublisher) Publish(ctx context.Context, key string, value []byte) error {
+	if err := p.writer.WriteMessages(ctx, kafka.Message{Key: []byte(key), Value: value}); err != nil {
+		return fmt.Errorf("kafka publish: %w", err)
+	}
+	return nil
+}
+
+// Close implements Publisher.
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes events to "<subject>.<tenant_id>" on NATS.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext // nil for core NATS
+	subject string
+}
+
+// NewNATSPublisher connects to NATS. With jetStream set, publishes wait for a
+// stream acknowledgement (a stream must capture the subject); otherwise they
+// wait for the server to flush. An unreachable server at startup is retried
+// in the background rather than failing.
+func NewNATSPublisher(url, subject string, jetStream bool) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("airborne"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("nats connect: %w", err)
+	}
+
+	p := &NATSPublisher{conn: conn, subject: subject}
+	if jetStream {
+		js, err := conn.JetStream()
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("nats jetstream: %w", err)
+		}
+		p.js = js
+	}
+	return p, nil
+}
+
+// Publish implements Publisher.
+func (p *NATSPublisher) Publish(ctx context.Context, key string, value []byte) error {
+	subject := p.subject + "." + key
+
+	if p.js != nil {
+		if _, err := p.js.Publish(subject, value, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("nats jetstream publish: %w", err)
+		}
+		return nil
+	}
+
+	if err := p.conn.Publish(subject, value); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("nats flush: %w", err)
+	}
+	return nil
+}
+
+// Close implements Publisher, flushing pending messages first.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+)
+
+// Publisher sends messages to a broker topic or subject.
+type Publisher interface {
+	// Publish sends value, keyed by key (the tenant ID). It returns once the
+	// broker has accepted the message.
+	Publish(ctx context.Context, key string, value []byte) error
+	Close() error
+}
+
+// StreamPolicy reports whether a tenant's generations are streamed and
+// whether events include conversation content.
+type StreamPolicy func(tenantID string) (enabled, includeContent bool)
+
+// ContentLoader returns the user input and response text of a generation.
+type ContentLoader func(ctx context.Context, tenantID string, messageID uuid.UUID) (userInput, response string, err error)
+
+// StreamSink publishes generation.completed events to Kafka or NATS.
+type StreamSink struct {
+	name    string
+	pub     Publisher
+	policy  StreamPolicy
+	content ContentLoader
+}
+
+// NewStreamSink creates a sink that publishes completed generations for
+// tenants the policy enables. content may be nil when no tenant needs it.
+func NewStreamSink(name string, pub Publisher, policy StreamPolicy, content ContentLoader) *StreamSink {
+	return &StreamSink{name: name, pub: pub, policy: policy, content: content}
+}
+
+// Name implements Sink.
+func (s *StreamSink) Name() string {
+	return s.name
+}
+
+// Deliver implements Sink. Other event types and disabled tenants are skipped.
+func (s *StreamSink) Deliver(ctx context.Context, event db.OutboxEvent) error {
+	if event.EventType != db.EventGenerationCompleted {
+		return nil
+	}
+	enabled, includeContent := s.policy(event.TenantID)
+	if !enabled {
+		return nil
+	}
+
+	envelope := NewEnvelope(event)
+	if includeContent && s.content != nil {
+		data, err := s.withContent(ctx, event)
+		if err != nil {
+			return err
+		}
+		envelope.Data = data
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	return s.pub.Publish(ctx, event.TenantID, body)
+}
+
+// withContent adds user_input and response_text to a generation payload.
+func (s *StreamSink) withContent(ctx context.Context, event db.OutboxEvent) (json.RawMessage, error) {
+	var gen db.GenerationEvent
+	if err := json.Unmarshal([]byte(event.Payload), &gen); err != nil {
+		return nil, fmt.Errorf("decode generation event: %w", err)
+	}
+
+	userInput, response, err := s.content(ctx, event.TenantID, gen.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("load content: %w", err)
+	}
+
+	data, err := json.Marshal(struct {
+		db.GenerationEvent
+		UserInput    string `json:"user_input"`
+		ResponseText string `json:"response_text"`
+	}{gen, userInput, response})
+	if err != nil {
+		return nil, fmt.Errorf("encode event content: %w", err)
+	}
+	return data, nil
+}
+
+// TenantStreamPolicy reads each tenant's event_stream settings at delivery
+// time, so config reloads apply immediately. Unknown tenants are not streamed.
+// Without a tenant manager (single-tenant legacy mode) every generation is
+// streamed without content.
+func TenantStreamPolicy(mgr *tenant.Manager) StreamPolicy {
+	return func(tenantID string) (bool, bool) {
+		if mgr == nil {
+			return true, false
+		}
+		cfg, ok := mgr.Tenant(tenantID)
+		if !ok {
+			return false, false
+		}
+		return cfg.EventStream.Enabled, cfg.EventStream.IncludeContent
+	}
+}
+
+// RepositoryContent loads generation content from the tenant's message tables.
+func RepositoryContent(client *db.Client) ContentLoader {
+	return func(ctx context.Context, tenantID string, messageID uuid.UUID) (string, string, error) {
+		repo, err := client.TenantRepository(tenantID)
+		if err != nil {
+			return "", "", err
+		}
+		data, err := repo.GetDebugData(ctx, messageID)
+		if err != nil {
+			return "", "", err
+		}
+		return data.UserInput, data.ResponseText, nil
+	}
+}
@@ -0,0 +1,159 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+)
+
+// fakePublisher records published messages.
+type fakePublisher struct {
+	keys   []string
+	values [][]byte
+	err    error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, key string, value []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.keys = append(p.keys, key)
+	p.values = append(p.values, value)
+	return nil
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func generationEvent(t *testing.T, tenantID string) db.OutboxEvent {
+	t.Helper()
+	payload, err := json.Marshal(db.GenerationEvent{
+		TenantID:  tenantID,
+		MessageID: uuid.New(),
+		Provider:  "openai",
+		Model:     "gpt-4o",
+		Status:    "success",
+		CostUSD:   0.01,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := testEvent()
+	ev.TenantID = tenantID
+	ev.Payload = string(payload)
+	return ev
+}
+
+func TestStreamSink_PublishesEnabledTenants(t *testing.T) {
+	pub := &fakePublisher{}
+	policy := func(tenantID string) (bool, bool) { return tenantID == "ai8", false }
+	sink := NewStreamSink("test", pub, policy, nil)
+
+	if err := sink.Deliver(context.Background(), generationEvent(t, "ai8")); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	if err := sink.Deliver(context.Background(), generationEvent(t, "email4ai")); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	other := testEvent()
+	other.EventType = "thread.deleted"
+	if err := sink.Deliver(context.Background(), other); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	if len(pub.values) != 1 || pub.keys[0] != "ai8" {
+		t.Fatalf("expected one ai8 message, got keys %v", pub.keys)
+	}
+	var env Envelope
+	if err := json.Unmarshal(pub.values[0], &env); err != nil {
+		t.Fatalf("message is not an envelope: %v", err)
+	}
+	if env.Type != db.EventGenerationCompleted || strings.Contains(string(env.Data), "response_text") {
+		t.Errorf("unexpected envelope: %s", pub.values[0])
+	}
+}
+
+func TestStreamSink_IncludeContent(t *testing.T) {
+	pub := &fakePublisher{}
+	policy := func(string) (bool, bool) { return true, true }
+	content := func(context.Context, string, uuid.UUID) (string, string, error) {
+		return "hello", "hi there", nil
+	}
+	sink := NewStreamSink("test", pub, policy, content)
+
+	if err := sink.Deliver(context.Background(), generationEvent(t, "ai8")); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	var env struct {
+		Data struct {
+			Provider     string `json:"provider"`
+			UserInput    string `json:"user_input"`
+			ResponseText string `json:"response_text"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(pub.values[0], &env); err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if env.Data.Provider != "openai" || env.Data.UserInput != "hello" || env.Data.ResponseText != "hi there" {
+		t.Errorf("unexpected data: %+v", env.Data)
+	}
+}
+
+func TestStreamSink_PublishError(t *testing.T) {
+	pub := &fakePublisher{err: errors.New("broker down")}
+	sink := NewStreamSink("test", pub, func(string) (bool, bool) { return true, false }, nil)
+
+	if err := sink.Deliver(context.Background(), generationEvent(t, "ai8")); err == nil {
+		t.Error("expected publish error to be returned for retry")
+	}
+}
+
+func TestTenantStreamPolicy(t *testing.T) {
+	mgr := &tenant.Manager{Tenants: map[string]tenant.TenantConfig{
+		"ai8":      {TenantID: "ai8", EventStream: tenant.EventStreamConfig{Enabled: true, IncludeContent: true}},
+		"email4ai": {TenantID: "email4ai"},
+	}}
+	policy := TenantStreamPolicy(mgr)
+
+	if enabled, content := policy("ai8"); !enabled || !content {
+		t.Errorf("ai8 = %v, %v; want true, true", enabled, content)
+	}
+	if enabled, _ := policy("email4ai"); enabled {
+		t.Error("email4ai should not be streamed without opting in")
+	}
+	if enabled, _ := policy("unknown"); enabled {
+		t.Error("unknown tenants should not be streamed")
+	}
+	if enabled, content := TenantStreamPolicy(nil)("any"); !enabled || content {
+		t.Errorf("legacy mode = %v, %v; want true, false", enabled, content)
+	}
+}
+
+func TestRepositoryContent(t *testing.T) {
+	ctx := context.Background()
+	client := newOutboxClient(t)
+
+	events, err := db.NewRepository(client).ClaimOutboxEvents(ctx, time.Now().Add(time.Second), 10, time.Minute)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected one outbox event, got %d, %v", len(events), err)
+	}
+	var gen db.GenerationEvent
+	if err := json.Unmarshal([]byte(events[0].Payload), &gen); err != nil {
+		t.Fatal(err)
+	}
+
+	userInput, response, err := RepositoryContent(client)(ctx, "ai8", gen.MessageID)
+	if err != nil {
+		t.Fatalf("RepositoryContent failed: %v", err)
+	}
+	if userInput != "q" || response != "a" {
+		t.Errorf("content = %q, %q; want q, a", userInput, response)
+	}
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/egress"
+)
+
+// DefaultWebhookTimeout bounds a single webhook delivery.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Webhook headers
+const (
+	HeaderEvent     = "X-Airborne-Event"
+	HeaderDelivery  = "X-Airborne-Delivery" // Event ID; stable across retries
+	HeaderSignature = "X-Airborne-Signature"
+)
+
+// WebhookSink POSTs events as JSON to an HTTP endpoint.
+type WebhookSink struct {
+	url        string
+	secret     string
+	eventTypes map[string]bool // nil delivers every event type
+	client     *http.Client
+}
+
+// NewWebhookSink creates a webhook sink. When secret is set, each request
+// carries an HMAC-SHA256 signature of the body in X-Airborne-Signature.
+// An empty eventTypes list subscribes to all events.
+func NewWebhookSink(url, secret string, eventTypes []string) *WebhookSink {
+	s := &WebhookSink{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: DefaultWebhookTimeout, Transport: egress.Audited(http.DefaultTransport)},
+	}
+	if len(eventTypes) > 0 {
+		s.eventTypes = make(map[string]bool, len(eventTypes))
+		for _, t := range eventTypes {
+			s.eventTypes[t] = true
+		}
+	}
+	return s
+}
+
+// Name implements Sink.
+func (s *WebhookSink) Name() string {
+	return "webhook:" + s.url
+}
+
+// Deliver implements Sink. Events the sink is not subscribed to are skipped.
+func (s *WebhookSink) Deliver(ctx context.Context, event db.OutboxEvent) error {
+	if s.eventTypes != nil && !s.eventTypes[event.EventType] {
+		return nil
+	}
+
+	body, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.EventType)
+	req.Header.Set(HeaderDelivery, event.ID.String())
+	if s.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the X-Airborne-Signature value for a request body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
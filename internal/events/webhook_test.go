@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/google/uuid"
+)
+
+func testEvent() db.OutboxEvent {
+	return db.OutboxEvent{
+		ID:        uuid.New(),
+		TenantID:  "ai8",
+		EventType: db.EventGenerationCompleted,
+		Payload:   `{"provider":"openai","cost_usd":0.01}`,
+		Attempts:  1,
+		CreatedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookSink_Deliver(t *testing.T) {
+	ev := testEvent()
+	var got Envelope
+	var headers http.Header
+	var body []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, "s3cret", nil)
+	if err := sink.Deliver(context.Background(), ev); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	if got.ID != ev.ID.String() || got.Type != ev.EventType || got.TenantID != "ai8" {
+		t.Errorf("unexpected envelope: %+v", got)
+	}
+	if string(got.Data) != ev.Payload {
+		t.Errorf("data = %s, want %s", got.Data, ev.Payload)
+	}
+	if headers.Get(HeaderDelivery) != ev.ID.String() || headers.Get(HeaderEvent) != ev.EventType {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if sig := headers.Get(HeaderSignature); sig != Sign("s3cret", body) {
+		t.Errorf("signature = %q, want %q", sig, Sign("s3cret", body))
+	}
+}
+
+func TestWebhookSink_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookSink(srv.URL, "", nil).Deliver(context.Background(), testEvent()); err == nil {
+		t.Error("expected error for 503 response")
+	}
+}
+
+func TestWebhookSink_EventTypeFilter(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, "", []string{"thread.deleted"})
+	if err := sink.Deliver(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	if called {
+		t.Error("unsubscribed event type was delivered")
+	}
+}
@@ -0,0 +1,162 @@
+// Package headroom tracks provider rate-limit headroom reported in response
+// headers, per (tenant, provider), so callers can wait or fail over before a
+// request is rejected with 429 instead of after.
+package headroom
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is assumed when a provider returns 429 without saying
+// when to retry.
+const defaultRetryAfter = 5 * time.Second
+
+// State is the last rate-limit state reported by a provider. Remaining
+// counts are -1 when the provider did not report them.
+type State struct {
+	RemainingRequests int
+	RemainingTokens   int
+	RequestsReset     time.Time // When the request allowance refills
+	TokensReset       time.Time // When the token allowance refills
+	RetryAfter        time.Time // Set after a 429
+	Updated           time.Time
+}
+
+// Wait returns how long until the provider has headroom again, or 0 if it
+// has headroom now.
+func (s State) Wait(now time.Time) time.Duration {
+	until := s.RetryAfter
+	if s.RemainingRequests == 0 && s.RequestsReset.After(until) {
+		until = s.RequestsReset
+	}
+	if s.RemainingTokens == 0 && s.TokensReset.After(until) {
+		until = s.TokensReset
+	}
+	if until.After(now) {
+		return until.Sub(now)
+	}
+	return 0
+}
+
+// Parse extracts rate-limit state from a provider response. It understands
+// OpenAI (x-ratelimit-*), Anthropic (anthropic-ratelimit-*) and the standard
+// Retry-After header, which Gemini sends on 429. ok is false when the
+// response carries no rate-limit information.
+func Parse(statusCode int, h http.Header, now time.Time) (State, bool) {
+	s := State{RemainingRequests: -1, RemainingTokens: -1, Updated: now}
+	found := false
+
+	if v, ok := headerInt(h, "x-ratelimit-remaining-requests", "anthropic-ratelimit-requests-remaining"); ok {
+		s.RemainingRequests = v
+		found = true
+	}
+	if v, ok := headerInt(h, "x-ratelimit-remaining-tokens", "anthropic-ratelimit-tokens-remaining"); ok {
+		s.RemainingTokens = v
+		found = true
+	}
+	s.RequestsReset = headerReset(h, now, "x-ratelimit-reset-requests", "anthropic-ratelimit-requests-reset")
+	s.TokensReset = headerReset(h, now, "x-ratelimit-reset-tokens", "anthropic-ratelimit-tokens-reset")
+
+	if statusCode == http.StatusTooManyRequests {
+		found = true
+		s.RetryAfter = headerReset(h, now, "retry-after")
+		if s.RetryAfter.IsZero() {
+			s.RetryAfter = now.Add(defaultRetryAfter)
+		}
+	}
+	return s, found
+}
+
+// headerInt returns the first of names present as an integer.
+func headerInt(h http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if v := strings.TrimSpace(h.Get(name)); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// headerReset returns the first of names present as an absolute time. Values
+// may be seconds ("30"), Go-style durations as sent by OpenAI ("6m0s",
+// "20ms"), RFC 3339 timestamps as sent by Anthropic, or HTTP dates.
+func headerReset(h http.Header, now time.Time, names ...string) time.Time {
+	for _, name := range names {
+		v := strings.TrimSpace(h.Get(name))
+		if v == "" {
+			continue
+		}
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			return now.Add(time.Duration(secs * float64(time.Second)))
+		}
+		if d, err := time.ParseDuration(v); err == nil {
+			return now.Add(d)
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+type key struct {
+	tenantID string
+	provider string
+}
+
+// Tracker holds the latest State per (tenant, provider). Tenants are tracked
+// separately because each uses its own provider API keys. It is safe for
+// concurrent use.
+type Tracker struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	states map[key]State
+}
+
+// NewTracker creates an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		now:    time.Now,
+		states: make(map[key]State),
+	}
+}
+
+// Observe records a provider response. Responses without rate-limit
+// information leave the current state unchanged.
+func (t *Tracker) Observe(tenantID, providerName string, statusCode int, h http.Header) {
+	s, ok := Parse(statusCode, h, t.now())
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	t.states[key{tenantID, providerName}] = s
+	t.mu.Unlock()
+}
+
+// State returns the last recorded state for the tenant and provider.
+func (t *Tracker) State(tenantID, providerName string) (State, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.states[key{tenantID, providerName}]
+	return s, ok
+}
+
+// Wait returns how long until the provider has headroom for the tenant, or
+// 0 if it has headroom now (or nothing is known).
+func (t *Tracker) Wait(tenantID, providerName string) time.Duration {
+	s, ok := t.State(tenantID, providerName)
+	if !ok {
+		return 0
+	}
+	return s.Wait(t.now())
+}
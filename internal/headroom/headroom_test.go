@@ -0,0 +1,98 @@
+package headroom
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func header(kv ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+	return h
+}
+
+func TestParse_OpenAI(t *testing.T) {
+	now := time.Now()
+	s, ok := Parse(http.StatusOK, header(
+		"x-ratelimit-remaining-requests", "0",
+		"x-ratelimit-remaining-tokens", "149984",
+		"x-ratelimit-reset-requests", "6m0s",
+		"x-ratelimit-reset-tokens", "6ms",
+	), now)
+	if !ok {
+		t.Fatal("expected rate-limit state")
+	}
+	if s.RemainingRequests != 0 || s.RemainingTokens != 149984 {
+		t.Errorf("remaining = %d requests, %d tokens", s.RemainingRequests, s.RemainingTokens)
+	}
+	if got := s.Wait(now); got != 6*time.Minute {
+		t.Errorf("Wait = %v, want 6m", got)
+	}
+}
+
+func TestParse_Anthropic(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s, ok := Parse(http.StatusOK, header(
+		"anthropic-ratelimit-requests-remaining", "49",
+		"anthropic-ratelimit-tokens-remaining", "0",
+		"anthropic-ratelimit-tokens-reset", "2026-10-16T12:00:20Z",
+	), now)
+	if !ok {
+		t.Fatal("expected rate-limit state")
+	}
+	if s.RemainingRequests != 49 {
+		t.Errorf("RemainingRequests = %d, want 49", s.RemainingRequests)
+	}
+	if got := s.Wait(now); got != 20*time.Second {
+		t.Errorf("Wait = %v, want 20s", got)
+	}
+}
+
+func TestParse_TooManyRequests(t *testing.T) {
+	now := time.Now()
+
+	s, _ := Parse(http.StatusTooManyRequests, header("retry-after", "12"), now)
+	if got := s.Wait(now); got != 12*time.Second {
+		t.Errorf("Wait with Retry-After = %v, want 12s", got)
+	}
+
+	s, ok := Parse(http.StatusTooManyRequests, http.Header{}, now)
+	if !ok || s.Wait(now) != defaultRetryAfter {
+		t.Errorf("Wait without Retry-After = %v, want %v", s.Wait(now), defaultRetryAfter)
+	}
+}
+
+func TestParse_NoRateLimitHeaders(t *testing.T) {
+	if _, ok := Parse(http.StatusOK, header("content-type", "application/json"), time.Now()); ok {
+		t.Error("expected no state for a response without rate-limit headers")
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+	tr.now = func() time.Time { return now }
+
+	tr.Observe("ai8", "openai", http.StatusTooManyRequests, header("retry-after", "30"))
+	if got := tr.Wait("ai8", "openai"); got != 30*time.Second {
+		t.Errorf("Wait = %v, want 30s", got)
+	}
+	if got := tr.Wait("email4ai", "openai"); got != 0 {
+		t.Errorf("other tenant Wait = %v, want 0", got)
+	}
+
+	// Responses without rate-limit headers keep the last state
+	tr.Observe("ai8", "openai", http.StatusOK, http.Header{})
+	if got := tr.Wait("ai8", "openai"); got != 30*time.Second {
+		t.Errorf("Wait after plain response = %v, want 30s", got)
+	}
+
+	// A later response with headroom clears it
+	tr.Observe("ai8", "openai", http.StatusOK, header("x-ratelimit-remaining-requests", "10"))
+	if got := tr.Wait("ai8", "openai"); got != 0 {
+		t.Errorf("Wait after headroom restored = %v, want 0", got)
+	}
+}
@@ -0,0 +1,206 @@
+package httpcapture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ai8future/airborne/internal/redact"
+)
+
+// Fixture modes.
+const (
+	FixtureModeOff    = ""       // Requests go to the provider
+	FixtureModeRecord = "record" // Requests go to the provider and responses are saved
+	FixtureModeReplay = "replay" // Saved responses are served without network access
+)
+
+// ErrNoFixture is returned in replay mode for requests nothing was recorded for.
+var ErrNoFixture = errors.New("no recorded fixture for request")
+
+// FixtureConfig controls recording and replaying provider HTTP traffic, so
+// provider client changes can be tested without spending tokens or
+// depending on the network.
+type FixtureConfig struct {
+	Mode string `yaml:"mode"` // "record", "replay", or empty for off
+	Dir  string `yaml:"dir"`  // Directory fixtures are written to and read from
+}
+
+// Validate checks the mode and that a mode has a directory.
+func (c FixtureConfig) Validate() error {
+	switch c.Mode {
+	case FixtureModeOff:
+		return nil
+	case FixtureModeRecord, FixtureModeReplay:
+	default:
+		return fmt.Errorf("mode must be %q or %q, got %q", FixtureModeRecord, FixtureModeReplay, c.Mode)
+	}
+	if c.Dir == "" {
+		return errors.New("dir is required when fixtures are enabled")
+	}
+	return nil
+}
+
+// fixture is the saved traffic of one request. Requests are matched by
+// method, URL and body with credentials scrubbed, so a request repeated
+// during recording, such as a retry, saves a response for each attempt and
+// replays them in order.
+type fixture struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Body      string            `json:"body,omitempty"`
+	Responses []fixtureResponse `json:"responses"`
+}
+
+type fixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Fixtures records or replays the traffic of every capturing transport.
+// It is safe for concurrent use.
+type Fixtures struct {
+	mode string
+	dir  string
+
+	mu       sync.Mutex
+	recorded map[string]*fixture // Fixtures written by this process
+	replayed map[string]int      // Responses served per fixture
+}
+
+// NewFixtures creates fixtures as configured, creating the directory in
+// record mode. It returns nil when fixtures are off.
+func NewFixtures(cfg FixtureConfig) (*Fixtures, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Mode == FixtureModeOff {
+		return nil, nil
+	}
+	if cfg.Mode == FixtureModeRecord {
+		if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create fixture directory: %w", err)
+		}
+	}
+	return &Fixtures{
+		mode:     cfg.Mode,
+		dir:      cfg.Dir,
+		recorded: make(map[string]*fixture),
+		replayed: make(map[string]int),
+	}, nil
+}
+
+var fixtures atomic.Pointer[Fixtures]
+
+// SetFixtures installs the fixtures used by all capturing transports. Nil
+// sends requests to the providers.
+func SetFixtures(f *Fixtures) {
+	fixtures.Store(f)
+}
+
+// key identifies a request by its scrubbed method, URL and body.
+func key(method, url string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, url)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:24]
+}
+
+func (f *Fixtures) path(key string) string {
+	return filepath.Join(f.dir, key+".json")
+}
+
+// roundTrip records or replays req, whose body has been read into body.
+func (f *Fixtures) roundTrip(base http.RoundTripper, req *http.Request, body []byte) (*http.Response, error) {
+	url := redact.Secrets(req.URL.String())
+	scrubbed := redact.JSON(body)
+	k := key(req.Method, url, scrubbed)
+	if f.mode == FixtureModeReplay {
+		return f.replay(req, k, url)
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fx, ok := f.recorded[k]
+	if !ok {
+		fx = &fixture{Method: req.Method, URL: url, Body: string(scrubbed)}
+		f.recorded[k] = fx
+	}
+	fx.Responses = append(fx.Responses, fixtureResponse{
+		StatusCode: resp.StatusCode,
+		Header:     redact.Header(resp.Header),
+		Body:       string(redact.JSON(respBody)),
+	})
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err == nil {
+		err = os.WriteFile(f.path(k), data, 0o644)
+	}
+	if err != nil {
+		slog.Warn("httpcapture: failed to record fixture", "url", url, "error", err)
+	}
+	return resp, nil
+}
+
+// replay serves the next recorded response for key k, repeating the last
+// once all have been served.
+func (f *Fixtures) replay(req *http.Request, k, url string) (*http.Response, error) {
+	data, err := os.ReadFile(f.path(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoFixture, req.Method, url)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fx fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", k, err)
+	}
+	if len(fx.Responses) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoFixture, req.Method, url)
+	}
+
+	f.mu.Lock()
+	n := f.replayed[k]
+	f.replayed[k]++
+	f.mu.Unlock()
+	r := fx.Responses[min(n, len(fx.Responses)-1)]
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Content-Length") // Scrubbing may have changed the length
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(r.Body))),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
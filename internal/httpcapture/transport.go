@@ -1,12 +1,16 @@
 // Package httpcapture provides HTTP transport wrappers for capturing
-// raw request and response bodies for debugging purposes.
+// raw request and response bodies for debugging purposes, and for recording
+// and replaying provider traffic as test fixtures.
 package httpcapture
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log/slog"
 	"net/http"
+
+	"github.com/ai8future/airborne/internal/redact"
 )
 
 // Transport wraps an http.RoundTripper to capture request and response bodies.
@@ -15,11 +19,30 @@ type Transport struct {
 	// Base is the underlying transport. If nil, http.DefaultTransport is used.
 	Base http.RoundTripper
 
-	// RequestBody contains the captured request body after RoundTrip completes.
+	// RequestBody contains the captured request body after RoundTrip
+	// completes, with credentials redacted since it is persisted.
 	RequestBody []byte
 
-	// ResponseBody contains the captured response body after RoundTrip completes.
+	// ResponseBody contains the captured response body after RoundTrip
+	// completes, with credentials redacted.
 	ResponseBody []byte
+
+	// ResponseHeader contains the captured response headers after RoundTrip
+	// completes, with credential headers such as Set-Cookie redacted.
+	ResponseHeader http.Header
+}
+
+// ResponseObserver receives the status and headers of every provider
+// response, including retried attempts and error responses.
+type ResponseObserver func(statusCode int, header http.Header)
+
+type observerKey struct{}
+
+// WithResponseObserver returns a context whose provider HTTP responses are
+// reported to fn. Provider SDKs pass the request context through to the
+// transport, so this works without changes to individual providers.
+func WithResponseObserver(ctx context.Context, fn ResponseObserver) context.Context {
+	return context.WithValue(ctx, observerKey{}, fn)
 }
 
 // New creates a new capturing transport with the default base transport.
@@ -34,18 +57,20 @@ func New() *Transport {
 func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	slog.Debug("httpcapture: RoundTrip called",
 		"method", req.Method,
-		"url", req.URL.String(),
+		"url", redact.Secrets(req.URL.String()),
 		"has_body", req.Body != nil,
 	)
 
 	// Capture request body if present
+	var reqBody []byte
 	if req.Body != nil {
 		body, err := io.ReadAll(req.Body)
 		if err != nil {
 			slog.Warn("httpcapture: failed to read request body", "error", err)
 			return nil, err
 		}
-		t.RequestBody = body
+		reqBody = body
+		t.RequestBody = redact.JSON(body)
 		// Restore the body so the SDK can read it
 		req.Body = io.NopCloser(bytes.NewReader(body))
 
@@ -54,12 +79,18 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		)
 	}
 
-	// Make the actual request
+	// Make the actual request, or record or replay it when fixtures are installed
 	base := t.Base
 	if base == nil {
 		base = http.DefaultTransport
 	}
-	resp, err := base.RoundTrip(req)
+	var resp *http.Response
+	var err error
+	if fx := fixtures.Load(); fx != nil {
+		resp, err = fx.roundTrip(base, req, reqBody)
+	} else {
+		resp, err = base.RoundTrip(req)
+	}
 	if err != nil {
 		slog.Warn("httpcapture: request failed", "error", err)
 		return nil, err
@@ -70,6 +101,11 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		"has_body", resp.Body != nil,
 	)
 
+	t.ResponseHeader = redact.Header(resp.Header)
+	if observe, ok := req.Context().Value(observerKey{}).(ResponseObserver); ok && observe != nil {
+		observe(resp.StatusCode, resp.Header)
+	}
+
 	// Capture response body if present
 	if resp.Body != nil {
 		body, err := io.ReadAll(resp.Body)
@@ -78,7 +114,7 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 			resp.Body.Close()
 			return nil, err
 		}
-		t.ResponseBody = body
+		t.ResponseBody = redact.JSON(body)
 		// Restore the body so the SDK can read it
 		resp.Body = io.NopCloser(bytes.NewReader(body))
 
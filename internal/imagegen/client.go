@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/provider"
 )
 
@@ -29,6 +30,10 @@ type ImageRequest struct {
 
 	// OpenAIAPIKey is the API key for OpenAI/DALL-E image generation
 	OpenAIAPIKey string
+
+	// Proxy routes provider requests through an outbound proxy; nil uses
+	// the server-wide proxy
+	Proxy *egress.ProxyConfig
 }
 
 // DetectImageRequest checks text against configured trigger phrases.
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/provider"
 )
 
@@ -103,7 +104,7 @@ func (c *Client) generateGemini(ctx context.Context, req *ImageRequest) (provide
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-goog-api-key", req.GeminiAPIKey)
 
-	client := &http.Client{Timeout: geminiTimeout}
+	client := egress.Client(req.Proxy, geminiTimeout)
 	resp, err := client.Do(httpReq)
 	if err != nil {
 		return provider.GeneratedImage{}, fmt.Errorf("gemini request failed: %w", err)
@@ -11,6 +11,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/provider"
 )
 
@@ -23,7 +24,10 @@ func (c *Client) generateOpenAI(ctx context.Context, req *ImageRequest) (provide
 		return provider.GeneratedImage{}, fmt.Errorf("openai API key not configured for image generation")
 	}
 
-	client := openai.NewClient(option.WithAPIKey(req.OpenAIAPIKey))
+	client := openai.NewClient(
+		option.WithAPIKey(req.OpenAIAPIKey),
+		option.WithHTTPClient(egress.Client(req.Proxy, 0)),
+	)
 
 	model := req.Config.GetModel()
 	if model == "" {
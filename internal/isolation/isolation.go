@@ -0,0 +1,111 @@
+// Package isolation guards tenant-scoped data access against cross-tenant use.
+//
+// The tenant interceptor records the authenticated tenant on the request
+// context. Repositories and the RAG service then check that the tenant they
+// were asked to act for matches it before touching tenant-scoped tables or
+// collections. A mismatch means a bug assembled a table or collection name
+// from the wrong tenant; it is blocked, logged and counted rather than served.
+package isolation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/ai8future/airborne/internal/logctx"
+	"github.com/ai8future/airborne/internal/tracing"
+)
+
+// Layers that enforce tenant isolation.
+const (
+	LayerDB  = "db"
+	LayerRAG = "rag"
+)
+
+// ErrCrossTenant is returned when a data access targets a tenant other than
+// the authenticated one.
+var ErrCrossTenant = errors.New("cross-tenant access denied")
+
+// Violation describes a blocked cross-tenant access.
+type Violation struct {
+	Layer         string // LayerDB or LayerRAG
+	Operation     string // Repository method or RAG operation
+	TenantID      string // Authenticated tenant
+	TargetTenant  string // Tenant the access was scoped to
+	TargetStoreID string // RAG store, when Layer is LayerRAG
+}
+
+type contextKey struct{}
+
+// WithTenant records the authenticated tenant on ctx.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// TenantFromContext returns the authenticated tenant recorded on ctx.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(contextKey{}).(string)
+	return tenantID, ok
+}
+
+// Detach returns a background context carrying ctx's authenticated tenant,
+// trace ID and log fields, for work that outlives the request but must stay
+// scoped to its tenant.
+func Detach(ctx context.Context) context.Context {
+	detached := tracing.WithID(context.Background(), tracing.IDFromContext(ctx))
+	detached = logctx.Inherit(detached, ctx)
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		return WithTenant(detached, tenantID)
+	}
+	return detached
+}
+
+var observer atomic.Pointer[func(Violation)]
+
+// SetObserver registers a function called for every violation, typically to
+// record it in metrics. Passing nil removes the observer.
+func SetObserver(fn func(Violation)) {
+	if fn == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&fn)
+}
+
+// Check verifies that an access scoped to tenantID is made on behalf of that
+// tenant. Contexts without an authenticated tenant (background jobs, the
+// admin dashboard) are not checked.
+func Check(ctx context.Context, layer, operation, tenantID string) error {
+	authenticated, ok := TenantFromContext(ctx)
+	if !ok || authenticated == tenantID {
+		return nil
+	}
+	Report(Violation{
+		Layer:        layer,
+		Operation:    operation,
+		TenantID:     authenticated,
+		TargetTenant: tenantID,
+	})
+	return fmt.Errorf("%w: %s %s", ErrCrossTenant, layer, operation)
+}
+
+// Report logs a violation and notifies the observer. It is used directly when
+// foreign data is detected after an access, such as a retrieved chunk owned by
+// another tenant.
+func Report(v Violation) {
+	slog.Error("tenant isolation violation",
+		"layer", v.Layer,
+		"operation", v.Operation,
+		"tenant_id", v.TenantID,
+		"target_tenant_id", v.TargetTenant,
+		"target_store_id", v.TargetStoreID,
+	)
+	if fn := observer.Load(); fn != nil {
+		(*fn)(v)
+	}
+}
@@ -0,0 +1,59 @@
+package isolation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/logctx"
+	"github.com/ai8future/airborne/internal/tracing"
+)
+
+func TestCheck(t *testing.T) {
+	var seen []Violation
+	SetObserver(func(v Violation) { seen = append(seen, v) })
+	defer SetObserver(nil)
+
+	ctx := WithTenant(context.Background(), "ai8")
+
+	if err := Check(ctx, LayerDB, "GetThread", "ai8"); err != nil {
+		t.Errorf("expected matching tenant to pass, got %v", err)
+	}
+	if err := Check(context.Background(), LayerDB, "GetThread", "email4ai"); err != nil {
+		t.Errorf("expected unauthenticated context to pass, got %v", err)
+	}
+
+	err := Check(ctx, LayerRAG, "Retrieve", "email4ai")
+	if !errors.Is(err, ErrCrossTenant) {
+		t.Fatalf("expected ErrCrossTenant, got %v", err)
+	}
+	if len(seen) != 1 {
+		t.Fatalf("expected 1 observed violation, got %d", len(seen))
+	}
+	want := Violation{Layer: LayerRAG, Operation: "Retrieve", TenantID: "ai8", TargetTenant: "email4ai"}
+	if seen[0] != want {
+		t.Errorf("unexpected violation: %+v", seen[0])
+	}
+}
+
+func TestDetach(t *testing.T) {
+	parent, cancel := context.WithCancel(WithTenant(tracing.WithID(logctx.With(context.Background(), "request_id", "req-1"), "trace-1"), "ai8"))
+	cancel()
+
+	ctx := Detach(parent)
+	if ctx.Err() != nil {
+		t.Error("expected detached context to outlive its parent")
+	}
+	if tenantID, ok := TenantFromContext(ctx); !ok || tenantID != "ai8" {
+		t.Errorf("expected tenant to carry over, got %q", tenantID)
+	}
+	if id := tracing.IDFromContext(ctx); id != "trace-1" {
+		t.Errorf("expected trace ID to carry over, got %q", id)
+	}
+	if attrs := logctx.Attrs(ctx); len(attrs) != 1 || attrs[0].Value.String() != "req-1" {
+		t.Errorf("expected log fields to carry over, got %v", attrs)
+	}
+	if _, ok := TenantFromContext(Detach(context.Background())); ok {
+		t.Error("expected no tenant on detached background context")
+	}
+}
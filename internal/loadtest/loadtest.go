@@ -0,0 +1,179 @@
+// Package loadtest drives concurrent GenerateReply and GenerateReplyStream
+// traffic against an Airborne server and reports throughput, latency
+// percentiles and errors. Requests are assigned to tenants, prompts and RPCs
+// deterministically, so runs with the same configuration are comparable.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPrompts are cycled through when Config.Prompts is empty.
+var defaultPrompts = []string{
+	"Summarize the benefits of unit testing in two sentences.",
+	"Write a haiku about load balancers.",
+	"Explain the difference between latency and throughput.",
+	"List three ways to reduce the cost of a cloud deployment.",
+	"What is a good name for a cat that likes to sleep on keyboards?",
+}
+
+// Config describes a load test run.
+type Config struct {
+	Concurrency int           // Concurrent workers (default 1)
+	Requests    int           // Total requests; 0 runs until Duration
+	Duration    time.Duration // Run time when Requests is 0
+	StreamRatio float64       // Fraction of requests sent as streams, 0-1
+	Tenants     []string      // Tenants requests are spread across; empty for single-tenant servers
+	Prompts     []string      // Prompts cycled through; synthetic prompts if empty
+	Provider    pb.Provider   // Provider requested; unspecified uses the tenant's default
+	APIKey      string        // Sent as a bearer token
+	Timeout     time.Duration // Per-request timeout (default 60s)
+}
+
+// result is the outcome of one request.
+type result struct {
+	stream     bool
+	latency    time.Duration
+	firstToken time.Duration // Streams only; 0 without text
+	err        string        // Error category; empty on success
+}
+
+// Run sends requests to client as configured and reports the results. It
+// stops early, reporting what completed, when ctx is cancelled.
+func Run(ctx context.Context, client pb.AirborneServiceClient, cfg Config) (*Report, error) {
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, errors.New("either requests or duration is required")
+	}
+	if cfg.StreamRatio < 0 || cfg.StreamRatio > 1 {
+		return nil, fmt.Errorf("stream ratio must be between 0 and 1, got %v", cfg.StreamRatio)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	if len(cfg.Prompts) == 0 {
+		cfg.Prompts = defaultPrompts
+	}
+
+	runCtx := ctx
+	if cfg.Requests <= 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		next    atomic.Int64
+		mu      sync.Mutex
+		results []result
+		wg      sync.WaitGroup
+	)
+	start := time.Now()
+	for range cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for runCtx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if cfg.Requests > 0 && i >= cfg.Requests {
+					return
+				}
+				r := send(runCtx, client, cfg, i)
+				if runCtx.Err() != nil && r.err != "" {
+					return // Cut off by the end of the run, not a failure
+				}
+				mu.Lock()
+				results = append(results, r)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return newReport(results, time.Since(start)), nil
+}
+
+// every reports whether request i is among the fraction ratio of requests,
+// spreading them evenly: with a ratio of 0.25 every fourth request is.
+func every(i int, ratio float64) bool {
+	return math.Floor(float64(i+1)*ratio) > math.Floor(float64(i)*ratio)
+}
+
+// request builds request i. The request number is added to the prompt so
+// servers that deduplicate identical requests still generate every reply.
+func request(cfg Config, i int) (*pb.GenerateReplyRequest, string) {
+	var tenantID string
+	if len(cfg.Tenants) > 0 {
+		tenantID = cfg.Tenants[i%len(cfg.Tenants)]
+	}
+	return &pb.GenerateReplyRequest{
+		RequestId:         uuid.NewString(),
+		TenantId:          tenantID,
+		UserInput:         fmt.Sprintf("%s (load test request %d)", cfg.Prompts[i%len(cfg.Prompts)], i),
+		PreferredProvider: cfg.Provider,
+	}, tenantID
+}
+
+// send sends request i and measures it.
+func send(ctx context.Context, client pb.AirborneServiceClient, cfg Config, i int) result {
+	req, tenantID := request(cfg, i)
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	if cfg.APIKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+cfg.APIKey)
+	}
+	if tenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", tenantID)
+	}
+
+	r := result{stream: every(i, cfg.StreamRatio)}
+	start := time.Now()
+	if !r.stream {
+		_, err := client.GenerateReply(ctx, req)
+		r.latency = time.Since(start)
+		if err != nil {
+			r.err = status.Code(err).String()
+		}
+		return r
+	}
+
+	stream, err := client.GenerateReplyStream(ctx, req)
+	if err != nil {
+		r.latency = time.Since(start)
+		r.err = status.Code(err).String()
+		return r
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			r.err = status.Code(err).String()
+			break
+		}
+		switch c := chunk.Chunk.(type) {
+		case *pb.GenerateReplyChunk_TextDelta:
+			if r.firstToken == 0 {
+				r.firstToken = time.Since(start)
+			}
+		case *pb.GenerateReplyChunk_Error:
+			r.err = "stream " + c.Error.Code
+		}
+	}
+	r.latency = time.Since(start)
+	return r
+}
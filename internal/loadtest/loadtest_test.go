@@ -0,0 +1,124 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestEvery(t *testing.T) {
+	for _, tt := range []struct {
+		ratio float64
+		n     int
+		want  int
+	}{{0, 100, 0}, {1, 100, 100}, {0.25, 100, 25}, {0.3, 10, 3}} {
+		got := 0
+		for i := range tt.n {
+			if every(i, tt.ratio) {
+				got++
+			}
+		}
+		if got != tt.want {
+			t.Errorf("every(_, %v) over %d = %d, want %d", tt.ratio, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	l := summarize(durations)
+	if l.Count != 100 || l.P50 != 50*time.Millisecond || l.P99 != 99*time.Millisecond || l.Max != 100*time.Millisecond {
+		t.Errorf("summary = %+v", l)
+	}
+	if l.Mean != 50500*time.Microsecond {
+		t.Errorf("mean = %v, want 50.5ms", l.Mean)
+	}
+	if l := summarize([]time.Duration{time.Second}); l.P50 != time.Second || l.P99 != time.Second {
+		t.Errorf("single-value summary = %+v", l)
+	}
+}
+
+func TestRun_MockServer(t *testing.T) {
+	srv, err := StartMockServer(MockConfig{
+		Tenants:   []string{"alpha", "beta"},
+		Latency:   time.Millisecond,
+		Chunks:    3,
+		ErrorRate: 0.1,
+	})
+	if err != nil {
+		t.Fatalf("StartMockServer failed: %v", err)
+	}
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	report, err := Run(context.Background(), pb.NewAirborneServiceClient(conn), Config{
+		Concurrency: 4,
+		Requests:    40,
+		StreamRatio: 0.5,
+		Tenants:     []string{"alpha", "beta"},
+		Provider:    pb.Provider_PROVIDER_OPENAI,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Requests != 40 || report.Failed != 4 || report.Succeeded != 36 {
+		t.Fatalf("report = %+v, want 40 requests with 4 failures", report)
+	}
+	if report.Unary.Count+report.Stream.Count != 36 || report.Stream.Count == 0 || report.FirstToken.Count != report.Stream.Count {
+		t.Errorf("unexpected latency counts: unary %d, stream %d, first token %d", report.Unary.Count, report.Stream.Count, report.FirstToken.Count)
+	}
+	// Which requests fail depends on the order workers reach the providers
+	if report.Errors["Internal"]+report.Errors["stream PROVIDER_ERROR"] != 4 {
+		t.Errorf("errors = %v, want 4 provider failures", report.Errors)
+	}
+	if report.Unary.P50 < time.Millisecond || report.Throughput <= 0 {
+		t.Errorf("unexpected timings: %+v", report)
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	for _, want := range []string{"Requests:   40", "first token", "ERROR"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printed report missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRun_Duration(t *testing.T) {
+	srv, err := StartMockServer(MockConfig{Latency: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("StartMockServer failed: %v", err)
+	}
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	report, err := Run(context.Background(), pb.NewAirborneServiceClient(conn), Config{Concurrency: 2, Duration: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Requests == 0 || report.Failed != 0 {
+		t.Errorf("report = %+v, want successful requests only", report)
+	}
+
+	if _, err := Run(context.Background(), pb.NewAirborneServiceClient(conn), Config{}); err == nil {
+		t.Error("expected an error without requests or duration")
+	}
+}
@@ -0,0 +1,108 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/provider/mock"
+	"github.com/ai8future/airborne/internal/service"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc"
+)
+
+// MockConfig describes the providers of a mock server.
+type MockConfig struct {
+	Tenants       []string      // Tenants the server accepts (default "loadtest")
+	Latency       time.Duration // Before a reply, or before the first streamed chunk
+	Chunks        int           // Text chunks per streamed reply (default 10)
+	ChunkInterval time.Duration // Between streamed chunks
+	ErrorRate     float64       // Fraction of calls failing with 503, 0-1
+}
+
+// MockServer is an in-process Airborne server whose providers are mocks.
+// Requests pass through tenant resolution and the chat service as on a real
+// server, but are not authenticated, rate limited or persisted.
+type MockServer struct {
+	Addr   string // Listen address
+	server *grpc.Server
+}
+
+// StartMockServer starts a mock server on a loopback port.
+func StartMockServer(cfg MockConfig) (*MockServer, error) {
+	if cfg.ErrorRate < 0 || cfg.ErrorRate > 1 {
+		return nil, fmt.Errorf("mock error rate must be between 0 and 1, got %v", cfg.ErrorRate)
+	}
+	if cfg.Chunks <= 0 {
+		cfg.Chunks = 10
+	}
+	tenantIDs := cfg.Tenants
+	if len(tenantIDs) == 0 {
+		tenantIDs = []string{"loadtest"}
+	}
+	mgr := &tenant.Manager{Tenants: make(map[string]tenant.TenantConfig)}
+	for _, id := range tenantIDs {
+		providers := make(map[string]tenant.ProviderConfig)
+		for _, name := range []string{provider.NameOpenAI, provider.NameGemini, provider.NameAnthropic} {
+			providers[name] = tenant.ProviderConfig{Enabled: true, APIKey: "mock", Model: "mock-model"}
+		}
+		mgr.Tenants[id] = tenant.TenantConfig{TenantID: id, DisplayName: id, Providers: providers}
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	providerCfg := mock.Config{
+		Latency:       cfg.Latency,
+		Chunks:        cfg.Chunks,
+		ChunkInterval: cfg.ChunkInterval,
+		ErrorRate:     cfg.ErrorRate,
+	}
+	chat := service.NewChatService(nil, nil, nil, nil, service.WithProviders(
+		mock.NewClient(provider.NameOpenAI, providerCfg),
+		mock.NewClient(provider.NameGemini, providerCfg),
+		mock.NewClient(provider.NameAnthropic, providerCfg),
+	))
+	tenants := auth.NewTenantInterceptor(mgr)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(mockClientUnary, tenants.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(mockClientStream, tenants.StreamInterceptor()),
+	)
+	pb.RegisterAirborneServiceServer(srv, chat)
+	go srv.Serve(lis)
+	return &MockServer{Addr: lis.Addr().String(), server: srv}, nil
+}
+
+// Stop stops the server, ending open requests.
+func (m *MockServer) Stop() {
+	m.server.Stop()
+}
+
+// mockClient is the client every mock server request is made as.
+var mockClient = &auth.ClientKey{
+	ClientID:    "loadtest",
+	Permissions: []auth.Permission{auth.PermissionChat, auth.PermissionChatStream},
+}
+
+func mockClientUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(context.WithValue(ctx, auth.ClientContextKey, mockClient), req)
+}
+
+func mockClientStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &clientStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), auth.ClientContextKey, mockClient)})
+}
+
+// clientStream is a server stream with the mock client in its context.
+type clientStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientStream) Context() context.Context {
+	return s.ctx
+}
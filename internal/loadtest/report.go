@@ -0,0 +1,120 @@
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Latency summarizes the durations of successful requests.
+type Latency struct {
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P90   time.Duration `json:"p90_ns"`
+	P95   time.Duration `json:"p95_ns"`
+	P99   time.Duration `json:"p99_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// Report is the outcome of a load test run.
+type Report struct {
+	Requests   int            `json:"requests"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Elapsed    time.Duration  `json:"elapsed_ns"`
+	Throughput float64        `json:"throughput_rps"` // Completed requests per second
+	Unary      Latency        `json:"unary"`
+	Stream     Latency        `json:"stream"`
+	FirstToken Latency        `json:"first_token"`      // Time to the first text of successful streams
+	Errors     map[string]int `json:"errors,omitempty"` // Failures by gRPC code or stream error code
+}
+
+// newReport summarizes results collected over elapsed.
+func newReport(results []result, elapsed time.Duration) *Report {
+	r := &Report{Requests: len(results), Elapsed: elapsed, Errors: make(map[string]int)}
+	var unary, stream, firstToken []time.Duration
+	for _, res := range results {
+		if res.err != "" {
+			r.Failed++
+			r.Errors[res.err]++
+			continue
+		}
+		r.Succeeded++
+		if !res.stream {
+			unary = append(unary, res.latency)
+			continue
+		}
+		stream = append(stream, res.latency)
+		if res.firstToken > 0 {
+			firstToken = append(firstToken, res.firstToken)
+		}
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(len(results)) / elapsed.Seconds()
+	}
+	r.Unary = summarize(unary)
+	r.Stream = summarize(stream)
+	r.FirstToken = summarize(firstToken)
+	return r
+}
+
+// summarize computes the latency summary of durations.
+func summarize(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return Latency{
+		Count: len(sorted),
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P95:   percentile(sorted, 95),
+		P99:   percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// Print writes the report as a table.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Requests:   %d (%d succeeded, %d failed) in %s\n", r.Requests, r.Succeeded, r.Failed, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Throughput: %.2f requests/s\n\n", r.Throughput)
+
+	fmt.Fprintf(w, "%-12s %7s %9s %9s %9s %9s %9s %9s\n", "LATENCY", "COUNT", "MEAN", "P50", "P90", "P95", "P99", "MAX")
+	for _, row := range []struct {
+		name string
+		l    Latency
+	}{{"unary", r.Unary}, {"stream", r.Stream}, {"first token", r.FirstToken}} {
+		if row.l.Count == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%-12s %7d %9s %9s %9s %9s %9s %9s\n", row.name, row.l.Count,
+			ms(row.l.Mean), ms(row.l.P50), ms(row.l.P90), ms(row.l.P95), ms(row.l.P99), ms(row.l.Max))
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(w, "\n%-24s %7s\n", "ERROR", "COUNT")
+		for _, code := range slices.Sorted(maps.Keys(r.Errors)) {
+			fmt.Fprintf(w, "%-24s %7d\n", code, r.Errors[code])
+		}
+	}
+}
+
+// ms formats d in milliseconds.
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
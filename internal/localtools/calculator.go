@@ -0,0 +1,421 @@
+package localtools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// Calculator limits.
+const (
+	maxExpressionLen = 1000
+	maxResultBits    = 4096 // Of the numerator or denominator of any value
+	resultDecimals   = 12   // For results without an exact decimal form
+)
+
+func runCalculator(args json.RawMessage) (any, error) {
+	var a struct {
+		Expression string `json:"expression"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	result, err := Evaluate(a.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"expression": a.Expression, "result": result}, nil
+}
+
+// Evaluate returns the value of an arithmetic expression as a decimal
+// string. Arithmetic is exact on rationals, so 0.1 + 0.2 is 0.3; results
+// without an exact decimal form, and those of sqrt, ln, log10, exp and
+// non-integer powers, are rounded to 12 decimal places.
+func Evaluate(expression string) (string, error) {
+	if strings.TrimSpace(expression) == "" {
+		return "", errors.New("expression is empty")
+	}
+	if len(expression) > maxExpressionLen {
+		return "", fmt.Errorf("expression is longer than %d characters", maxExpressionLen)
+	}
+	p := &parser{input: expression}
+	p.next()
+	v, err := p.expr()
+	if err != nil {
+		return "", err
+	}
+	if p.tok.kind != tokEOF {
+		return "", fmt.Errorf("unexpected %q at position %d", p.tok.text, p.tok.pos+1)
+	}
+	return formatRat(v), nil
+}
+
+// formatRat formats v as an integer or a decimal without trailing zeros.
+func formatRat(v *big.Rat) string {
+	if v.IsInt() {
+		return v.Num().String()
+	}
+	s := strings.TrimRight(v.FloatString(resultDecimals), "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// parser is a recursive descent parser that evaluates as it parses:
+//
+//	expr  = term { ("+" | "-") term }
+//	term  = unary { ("*" | "/" | "%") unary }
+//	unary = ("-" | "+") unary | power
+//	power = primary [ "^" unary ]
+//	primary = number | name [ "(" expr { "," expr } ")" ] | "(" expr ")"
+type parser struct {
+	input string
+	pos   int
+	tok   token
+	depth int
+}
+
+// maxDepth bounds nesting, against stack exhaustion.
+const maxDepth = 100
+
+func (p *parser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokEOF, text: "end of expression", pos: start}
+		return
+	}
+	c := p.input[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		// Exponent, as in 1.5e3; a lone e is the constant
+		if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+			i := p.pos + 1
+			if i < len(p.input) && (p.input[i] == '+' || p.input[i] == '-') {
+				i++
+			}
+			if i < len(p.input) && isDigit(p.input[i]) {
+				for i < len(p.input) && isDigit(p.input[i]) {
+					i++
+				}
+				p.pos = i
+			}
+		}
+		p.tok = token{kind: tokNumber, text: p.input[start:p.pos], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || isDigit(p.input[p.pos]) || unicode.IsLetter(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: strings.ToLower(p.input[start:p.pos]), pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	}
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// accept consumes the current token if it is the operator op.
+func (p *parser) accept(op string) bool {
+	if p.tok.kind == tokOp && p.tok.text == op {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expr() (*big.Rat, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, errors.New("expression is nested too deeply")
+	}
+	v, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("+"):
+			w, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			v = new(big.Rat).Add(v, w)
+		case p.accept("-"):
+			w, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			v = new(big.Rat).Sub(v, w)
+		default:
+			return v, checkSize(v)
+		}
+	}
+}
+
+func (p *parser) term() (*big.Rat, error) {
+	v, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.tok
+		if !p.accept("*") && !p.accept("/") && !p.accept("%") {
+			return v, nil
+		}
+		w, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		switch op.text {
+		case "*":
+			v = new(big.Rat).Mul(v, w)
+		case "/":
+			if w.Sign() == 0 {
+				return nil, errors.New("division by zero")
+			}
+			v = new(big.Rat).Quo(v, w)
+		case "%":
+			if w.Sign() == 0 {
+				return nil, errors.New("modulo by zero")
+			}
+			// a - b*floor(a/b), so the result has the sign of b
+			q := floorRat(new(big.Rat).Quo(v, w))
+			v = new(big.Rat).Sub(v, new(big.Rat).Mul(w, q))
+		}
+		if err := checkSize(v); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) unary() (*big.Rat, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, errors.New("expression is nested too deeply")
+	}
+	switch {
+	case p.accept("-"):
+		v, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).Neg(v), nil
+	case p.accept("+"):
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (*big.Rat, error) {
+	base, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("^") {
+		return base, nil
+	}
+	exp, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return pow(base, exp)
+}
+
+func (p *parser) primary() (*big.Rat, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		p.next()
+		v, ok := new(big.Rat).SetString(tok.text)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return v, nil
+	case tok.kind == tokIdent:
+		p.next()
+		if !p.accept("(") {
+			return constant(tok.text)
+		}
+		var args []*big.Rat
+		for {
+			v, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+			if p.accept(")") {
+				break
+			}
+			if !p.accept(",") {
+				return nil, fmt.Errorf("expected , or ) at position %d", p.tok.pos+1)
+			}
+		}
+		return call(tok.text, args)
+	case p.accept("("):
+		v, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ) at position %d", p.tok.pos+1)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+}
+
+// constant returns the value of a named constant.
+func constant(name string) (*big.Rat, error) {
+	switch name {
+	case "pi":
+		return fromFloat(math.Pi)
+	case "e":
+		return fromFloat(math.E)
+	}
+	return nil, fmt.Errorf("unknown constant %q", name)
+}
+
+// call returns the value of a function applied to args.
+func call(name string, args []*big.Rat) (*big.Rat, error) {
+	arity := map[string]int{"sqrt": 1, "abs": 1, "floor": 1, "ceil": 1, "ln": 1, "log10": 1, "exp": 1}
+	if n, ok := arity[name]; ok && len(args) != n {
+		return nil, fmt.Errorf("%s takes %d argument", name, n)
+	}
+	switch name {
+	case "abs":
+		return new(big.Rat).Abs(args[0]), nil
+	case "floor":
+		return floorRat(args[0]), nil
+	case "ceil":
+		return new(big.Rat).Neg(floorRat(new(big.Rat).Neg(args[0]))), nil
+	case "round":
+		if len(args) > 2 {
+			return nil, errors.New("round takes 1 or 2 arguments")
+		}
+		digits := 0
+		if len(args) == 2 {
+			if !args[1].IsInt() || args[1].Num().Cmp(big.NewInt(20)) > 0 || args[1].Sign() < 0 {
+				return nil, errors.New("round digits must be an integer from 0 to 20")
+			}
+			digits = int(args[1].Num().Int64())
+		}
+		return roundRat(args[0], digits), nil
+	case "min", "max":
+		v := args[0]
+		for _, w := range args[1:] {
+			if (name == "min") == (w.Cmp(v) < 0) {
+				v = w
+			}
+		}
+		return v, nil
+	case "sqrt":
+		if args[0].Sign() < 0 {
+			return nil, errors.New("sqrt of a negative number")
+		}
+		f, _ := args[0].Float64()
+		return fromFloat(math.Sqrt(f))
+	case "ln", "log10":
+		if args[0].Sign() <= 0 {
+			return nil, fmt.Errorf("%s of a number that is not positive", name)
+		}
+		f, _ := args[0].Float64()
+		if name == "ln" {
+			return fromFloat(math.Log(f))
+		}
+		return fromFloat(math.Log10(f))
+	case "exp":
+		f, _ := args[0].Float64()
+		return fromFloat(math.Exp(f))
+	}
+	return nil, fmt.Errorf("unknown function %q", name)
+}
+
+// pow returns base^exp, exactly for integer exponents.
+func pow(base, exp *big.Rat) (*big.Rat, error) {
+	if exp.IsInt() && exp.Num().IsInt64() && exp.Num().Int64() >= -1000 && exp.Num().Int64() <= 1000 {
+		n := exp.Num().Int64()
+		if n < 0 && base.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		abs := n
+		if abs < 0 {
+			abs = -abs
+		}
+		num := new(big.Int).Exp(base.Num(), big.NewInt(abs), nil)
+		den := new(big.Int).Exp(base.Denom(), big.NewInt(abs), nil)
+		if num.BitLen() > maxResultBits || den.BitLen() > maxResultBits {
+			return nil, errors.New("result is too large")
+		}
+		if n < 0 {
+			num, den = den, num
+		}
+		return new(big.Rat).SetFrac(num, den), nil
+	}
+	b, _ := base.Float64()
+	e, _ := exp.Float64()
+	return fromFloat(math.Pow(b, e))
+}
+
+// fromFloat converts the result of a float function, rejecting NaN and
+// infinities.
+func fromFloat(f float64) (*big.Rat, error) {
+	v := new(big.Rat).SetFloat64(f)
+	if v == nil {
+		return nil, errors.New("result is not a finite number")
+	}
+	return v, nil
+}
+
+// floorRat returns the largest integer not above v.
+func floorRat(v *big.Rat) *big.Rat {
+	q := new(big.Int).Div(v.Num(), v.Denom()) // Euclidean; the denominator is positive
+	return new(big.Rat).SetInt(q)
+}
+
+// roundRat rounds v to digits decimal places, halves away from zero.
+func roundRat(v *big.Rat, digits int) *big.Rat {
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil))
+	scaled := new(big.Rat).Mul(new(big.Rat).Abs(v), scale)
+	rounded := floorRat(new(big.Rat).Add(scaled, big.NewRat(1, 2)))
+	if v.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+	return rounded.Quo(rounded, scale)
+}
+
+// checkSize rejects values too large to keep computing with exactly.
+func checkSize(v *big.Rat) error {
+	if v.Num().BitLen() > maxResultBits || v.Denom().BitLen() > maxResultBits {
+		return errors.New("result is too large")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package localtools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// dateLayout is the date format date_math reads and writes.
+const dateLayout = "2006-01-02"
+
+// maxDateSpan bounds the days date_math counts, so counting business days
+// stays cheap.
+const maxDateSpan = 100 * 366
+
+func runDateMath(args json.RawMessage) (any, error) {
+	var a struct {
+		Date   string `json:"date"`
+		Years  int    `json:"years"`
+		Months int    `json:"months"`
+		Days   int    `json:"days"`
+		Until  string `json:"until"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(dateLayout, a.Date)
+	if err != nil {
+		return nil, fmt.Errorf("date must be YYYY-MM-DD, got %q", a.Date)
+	}
+
+	if a.Until == "" {
+		if a.Years == 0 && a.Months == 0 && a.Days == 0 {
+			return dateResult(start), nil
+		}
+		if abs(a.Years) > 1000 || abs(a.Months) > 12000 || abs(a.Days) > maxDateSpan*10 {
+			return nil, errors.New("offset is too large")
+		}
+		return dateResult(start.AddDate(a.Years, a.Months, a.Days)), nil
+	}
+	if a.Years != 0 || a.Months != 0 || a.Days != 0 {
+		return nil, errors.New("until cannot be combined with years, months or days")
+	}
+	end, err := time.Parse(dateLayout, a.Until)
+	if err != nil {
+		return nil, fmt.Errorf("until must be YYYY-MM-DD, got %q", a.Until)
+	}
+	days := int(end.Sub(start).Hours() / 24)
+	if abs(days) > maxDateSpan {
+		return nil, fmt.Errorf("dates are more than %d days apart", maxDateSpan)
+	}
+	return map[string]any{
+		"date":          a.Date,
+		"until":         a.Until,
+		"days":          days,
+		"business_days": businessDays(start, end),
+		"weekday":       start.Weekday().String(),
+		"until_weekday": end.Weekday().String(),
+	}, nil
+}
+
+// dateResult describes a date.
+func dateResult(d time.Time) map[string]any {
+	return map[string]any{"result": d.Format(dateLayout), "weekday": d.Weekday().String()}
+}
+
+// businessDays counts the Mondays to Fridays from start, inclusive, to end,
+// exclusive, negated when end is before start.
+func businessDays(start, end time.Time) int {
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	n := 0
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if wd := d.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			n++
+		}
+	}
+	return sign * n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
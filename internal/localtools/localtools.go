@@ -0,0 +1,157 @@
+// Package localtools provides deterministic tools the server runs in-process
+// when a model calls them: arithmetic, date math, unit conversion and UUID
+// generation. Models get these wrong when they answer from memory, which
+// shows most in financial summaries; the tools answer exactly and without a
+// network round trip.
+package localtools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Tool names.
+const (
+	Calculator   = "calculator"
+	DateMath     = "date_math"
+	ConvertUnits = "convert_units"
+	GenerateUUID = "generate_uuid"
+)
+
+// maxUUIDs bounds how many UUIDs one generate_uuid call returns.
+const maxUUIDs = 20
+
+// Tool is a local tool as offered to the model.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any // JSON Schema of the arguments
+	run         func(args json.RawMessage) (any, error)
+}
+
+// Run answers a call with the JSON arguments args, returning the result as
+// JSON. Errors describe what was wrong with the arguments.
+func (t Tool) Run(args string) (string, error) {
+	if args == "" {
+		args = "{}"
+	}
+	result, err := t.run(json.RawMessage(args))
+	if err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+var tools = map[string]Tool{
+	Calculator: {
+		Name: Calculator,
+		Description: "Evaluates an arithmetic expression exactly and returns the result. Supports + - * / % ^, parentheses, " +
+			"the constants pi and e, and sqrt, abs, round(x, digits), floor, ceil, min, max, ln, log10 and exp. " +
+			"Use it for any arithmetic instead of calculating yourself.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"expression": map[string]any{"type": "string", "description": "e.g. (1250.40 - 980) * 1.2 / 3"},
+			},
+			"required": []string{"expression"},
+		},
+		run: runCalculator,
+	},
+	DateMath: {
+		Name: DateMath,
+		Description: "Adds years, months and days to a date, or counts the days and business days (Monday to Friday) " +
+			"from a date until another. Dates are YYYY-MM-DD. Returns the result with its weekday.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"date":   map[string]any{"type": "string", "description": "Start date, YYYY-MM-DD"},
+				"years":  map[string]any{"type": "integer"},
+				"months": map[string]any{"type": "integer"},
+				"days":   map[string]any{"type": "integer"},
+				"until":  map[string]any{"type": "string", "description": "End date to count the days until, YYYY-MM-DD"},
+			},
+			"required": []string{"date"},
+		},
+		run: runDateMath,
+	},
+	ConvertUnits: {
+		Name:        ConvertUnits,
+		Description: "Converts a value between units of length, area, volume, mass, time, speed, data size or temperature, e.g. km to mi, lb to kg, c to f, gib to gb.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"value": map[string]any{"type": "number"},
+				"from":  map[string]any{"type": "string", "description": "Unit to convert from"},
+				"to":    map[string]any{"type": "string", "description": "Unit to convert to"},
+			},
+			"required": []string{"value", "from", "to"},
+		},
+		run: runConvertUnits,
+	},
+	GenerateUUID: {
+		Name:        GenerateUUID,
+		Description: fmt.Sprintf("Generates random (version 4) UUIDs, 1 by default and at most %d.", maxUUIDs),
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"count": map[string]any{"type": "integer", "minimum": 1, "maximum": maxUUIDs},
+			},
+		},
+		run: runGenerateUUID,
+	},
+}
+
+// Lookup returns the tool named name.
+func Lookup(name string) (Tool, bool) {
+	t, ok := tools[name]
+	return t, ok
+}
+
+// Names returns the names of all local tools, sorted.
+func Names() []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// decodeArgs unmarshals a call's arguments into v, rejecting unknown fields
+// so misspelled arguments are not silently ignored.
+func decodeArgs(args json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func runGenerateUUID(args json.RawMessage) (any, error) {
+	var a struct {
+		Count int `json:"count"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.Count == 0 {
+		a.Count = 1
+	}
+	if a.Count < 0 || a.Count > maxUUIDs {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxUUIDs)
+	}
+	ids := make([]string, a.Count)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	return map[string]any{"uuids": ids}, nil
+}
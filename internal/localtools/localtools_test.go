@@ -0,0 +1,135 @@
+package localtools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"0.1 + 0.2", "0.3"},
+		{"(1250.40 - 980) * 1.2 / 3", "108.16"},
+		{"2 ^ 10", "1024"},
+		{"-2 ^ 2", "-4"},
+		{"2 ^ -2", "0.25"},
+		{"2 ^ 3 ^ 2", "512"},
+		{"10 / 3", "3.333333333333"},
+		{"-7 % 3", "2"},
+		{"1.5e3 + 1", "1501"},
+		{"round(2.675, 2)", "2.68"},
+		{"round(-2.5)", "-3"},
+		{"floor(-1.5) + ceil(1.2)", "0"},
+		{"max(1, 7, 3) - min(4, -2)", "9"},
+		{"sqrt(2)", "1.414213562373"},
+		{"abs(-3) * pi", "9.424777960769"},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr)
+		if err != nil || got != tt.want {
+			t.Errorf("Evaluate(%q) = %q, %v, want %q", tt.expr, got, err, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "1 / 0", "5 % 0", "sqrt(-1)", "ln(0)", "2 ^ 100000", "1 +", "(1", "foo(1)", "bar", "1.2.3", "2e", strings.Repeat("(", 200) + "1" + strings.Repeat(")", 200)} {
+		if got, err := Evaluate(expr); err == nil {
+			t.Errorf("Evaluate(%q) = %q, want an error", expr, got)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{10, "km", "mi", 6.213711922},
+		{1, "lb", "kg", 0.45359237},
+		{100, "C", "F", 212},
+		{32, "fahrenheit", "kelvin", 273.15},
+		{1, "GiB", "MB", 1073.741824},
+		{60, "mph", "km/h", 96.56064},
+		{2, "hours", "min", 120},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to)
+		if err != nil || got != tt.want {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v, want %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+	for _, pair := range [][2]string{{"km", "kg"}, {"c", "m"}, {"furlong", "m"}} {
+		if _, err := Convert(1, pair[0], pair[1]); err == nil {
+			t.Errorf("Convert(1, %s, %s) succeeded, want an error", pair[0], pair[1])
+		}
+	}
+}
+
+func TestDateMath(t *testing.T) {
+	tool, _ := Lookup(DateMath)
+	tests := []struct {
+		args string
+		want map[string]any
+	}{
+		{`{"date":"2026-01-31","months":1}`, map[string]any{"result": "2026-03-03", "weekday": "Tuesday"}},
+		{`{"date":"2026-10-17","days":-17}`, map[string]any{"result": "2026-09-30", "weekday": "Wednesday"}},
+		{`{"date":"2026-10-16","until":"2026-10-26"}`, map[string]any{"days": float64(10), "business_days": float64(6)}},
+		{`{"date":"2026-10-26","until":"2026-10-16"}`, map[string]any{"days": float64(-10), "business_days": float64(-6)}},
+	}
+	for _, tt := range tests {
+		out, err := tool.Run(tt.args)
+		if err != nil {
+			t.Fatalf("Run(%s) failed: %v", tt.args, err)
+		}
+		var got map[string]any
+		json.Unmarshal([]byte(out), &got)
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("Run(%s)[%s] = %v, want %v", tt.args, k, got[k], v)
+			}
+		}
+	}
+	for _, args := range []string{`{"date":"17/10/2026"}`, `{"date":"2026-10-17","until":"2026-11-01","days":1}`, `{"date":"2026-10-17","weeks":1}`} {
+		if _, err := tool.Run(args); err == nil {
+			t.Errorf("Run(%s) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestGenerateUUID(t *testing.T) {
+	tool, _ := Lookup(GenerateUUID)
+	out, err := tool.Run(`{"count":3}`)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	var got struct{ UUIDs []string }
+	json.Unmarshal([]byte(out), &got)
+	if len(got.UUIDs) != 3 || got.UUIDs[0] == got.UUIDs[1] || len(got.UUIDs[0]) != 36 {
+		t.Errorf("unexpected UUIDs %v", got.UUIDs)
+	}
+	if out, err := tool.Run(""); err != nil || !strings.Contains(out, `"uuids":["`) {
+		t.Errorf("Run without arguments = %s, %v, want one UUID", out, err)
+	}
+	if _, err := tool.Run(`{"count":500}`); err == nil {
+		t.Error("expected an error for too many UUIDs")
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if len(names) != 4 || names[0] != Calculator {
+		t.Errorf("Names() = %v", names)
+	}
+	for _, name := range names {
+		tool, ok := Lookup(name)
+		if !ok || tool.Name != name || tool.Description == "" {
+			t.Errorf("Lookup(%q) = %+v, %v", name, tool, ok)
+		}
+		if _, err := json.Marshal(tool.Parameters); err != nil {
+			t.Errorf("%s parameters are not JSON: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package localtools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// unit is a unit of measure: a value in it times factor is the value in the
+// base unit of its dimension.
+type unit struct {
+	dimension string
+	factor    float64
+}
+
+// units are the units convert_units knows, by lowercase name. Temperatures
+// are converted separately, as their scales do not share a zero.
+var units = map[string]unit{
+	// Length, in meters
+	"m": {"length", 1}, "km": {"length", 1000}, "cm": {"length", 0.01}, "mm": {"length", 0.001},
+	"mi": {"length", 1609.344}, "yd": {"length", 0.9144}, "ft": {"length", 0.3048}, "in": {"length", 0.0254},
+	"nmi": {"length", 1852},
+	// Area, in square meters
+	"m2": {"area", 1}, "km2": {"area", 1e6}, "cm2": {"area", 1e-4}, "ha": {"area", 1e4},
+	"acre": {"area", 4046.8564224}, "ft2": {"area", 0.09290304}, "mi2": {"area", 2589988.110336},
+	// Volume, in liters
+	"l": {"volume", 1}, "ml": {"volume", 0.001}, "m3": {"volume", 1000},
+	"gal": {"volume", 3.785411784}, "qt": {"volume", 0.946352946}, "pt": {"volume", 0.473176473},
+	"cup": {"volume", 0.2365882365}, "floz": {"volume", 0.0295735295625},
+	// Mass, in kilograms
+	"kg": {"mass", 1}, "g": {"mass", 0.001}, "mg": {"mass", 1e-6}, "t": {"mass", 1000},
+	"lb": {"mass", 0.45359237}, "oz": {"mass", 0.028349523125}, "st": {"mass", 6.35029318},
+	// Time, in seconds
+	"ms": {"time", 0.001}, "s": {"time", 1}, "min": {"time", 60}, "h": {"time", 3600},
+	"d": {"time", 86400}, "wk": {"time", 604800},
+	// Speed, in meters per second
+	"m/s": {"speed", 1}, "km/h": {"speed", 1000.0 / 3600}, "mph": {"speed", 0.44704}, "kn": {"speed", 1852.0 / 3600},
+	// Data size, in bytes
+	"b": {"data", 1}, "kb": {"data", 1e3}, "mb": {"data", 1e6}, "gb": {"data", 1e9}, "tb": {"data", 1e12},
+	"kib": {"data", 1 << 10}, "mib": {"data", 1 << 20}, "gib": {"data", 1 << 30}, "tib": {"data", 1 << 40},
+}
+
+// unitAliases map common spellings to unit names.
+var unitAliases = map[string]string{
+	"meter": "m", "meters": "m", "kilometer": "km", "kilometers": "km", "mile": "mi", "miles": "mi",
+	"foot": "ft", "feet": "ft", "inch": "in", "inches": "in", "yard": "yd", "yards": "yd",
+	"liter": "l", "liters": "l", "litre": "l", "litres": "l", "gallon": "gal", "gallons": "gal",
+	"kilogram": "kg", "kilograms": "kg", "gram": "g", "grams": "g", "pound": "lb", "pounds": "lb", "lbs": "lb",
+	"ounce": "oz", "ounces": "oz", "tonne": "t", "tonnes": "t",
+	"sec": "s", "second": "s", "seconds": "s", "minute": "min", "minutes": "min", "hr": "h", "hour": "h", "hours": "h",
+	"day": "d", "days": "d", "week": "wk", "weeks": "wk", "kph": "km/h", "knots": "kn",
+	"celsius": "c", "fahrenheit": "f", "kelvin": "k", "°c": "c", "°f": "f",
+}
+
+func runConvertUnits(args json.RawMessage) (any, error) {
+	var a struct {
+		Value *float64 `json:"value"`
+		From  string   `json:"from"`
+		To    string   `json:"to"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.Value == nil {
+		return nil, errors.New("value is required")
+	}
+	result, err := Convert(*a.Value, a.From, a.To)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(result, 0) {
+		return nil, errors.New("result is out of range")
+	}
+	return map[string]any{"value": *a.Value, "from": a.From, "to": a.To, "result": result}, nil
+}
+
+// Convert returns value in from units as to units, rounded to 10
+// significant digits to hide floating point noise.
+func Convert(value float64, from, to string) (float64, error) {
+	from, to = unitName(from), unitName(to)
+	if isTemperature(from) || isTemperature(to) {
+		if !isTemperature(from) || !isTemperature(to) {
+			return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+		}
+		return roundSignificant(fromKelvin(toKelvin(value, from), to)), nil
+	}
+	f, ok := units[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	t, ok := units[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if f.dimension != t.dimension {
+		return 0, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from, f.dimension, to, t.dimension)
+	}
+	return roundSignificant(value * f.factor / t.factor), nil
+}
+
+// unitName returns the name of the unit s spells.
+func unitName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if alias, ok := unitAliases[s]; ok {
+		return alias
+	}
+	return s
+}
+
+func isTemperature(u string) bool {
+	return u == "c" || u == "f" || u == "k"
+}
+
+func toKelvin(v float64, u string) float64 {
+	switch u {
+	case "c":
+		return v + 273.15
+	case "f":
+		return (v-32)*5/9 + 273.15
+	}
+	return v
+}
+
+func fromKelvin(v float64, u string) float64 {
+	switch u {
+	case "c":
+		return v - 273.15
+	case "f":
+		return (v-273.15)*9/5 + 32
+	}
+	return v
+}
+
+// roundSignificant rounds v to 10 significant digits.
+func roundSignificant(v float64) float64 {
+	if v == 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return v
+	}
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 10, 64), 64)
+	return r
+}
@@ -0,0 +1,198 @@
+// Package logctx attaches request fields to log lines through the context.
+//
+// Request handling records fields such as tenant_id, client_id, request_id
+// and thread_id on the context once they are known, with With. Every line
+// logged through the slog *Context functions with that context, by the
+// service or by the provider packages it calls, then carries them, along
+// with the request's trace ID, without each call site repeating them. User
+// content logged with Content is truncated or hashed per the tenant's
+// privacy settings.
+package logctx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/ai8future/airborne/internal/tracing"
+)
+
+// Request fields attached by the server.
+const (
+	KeyTenantID  = "tenant_id"
+	KeyClientID  = "client_id"
+	KeyRequestID = "request_id"
+	KeyThreadID  = "thread_id"
+)
+
+// Modes for user content in log lines, set per tenant.
+const (
+	ContentFull     = "full"     // Logged as is
+	ContentTruncate = "truncate" // First truncatedContentLen characters
+	ContentHash     = "hash"     // SHA-256 prefix, so equal content can still be matched
+)
+
+// truncatedContentLen is how many characters ContentTruncate keeps.
+const truncatedContentLen = 32
+
+type contextKey struct{}
+
+// fields is the context value holding a request's log fields.
+type fields struct {
+	attrs       []slog.Attr
+	contentMode string
+}
+
+func fromContext(ctx context.Context) fields {
+	if ctx == nil {
+		return fields{}
+	}
+	f, _ := ctx.Value(contextKey{}).(fields)
+	return f
+}
+
+// With returns a copy of ctx whose log lines carry args, given as
+// alternating keys and values or slog.Attrs like slog.Logger.With. Empty
+// string values are skipped, and a key already on ctx is replaced.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithAttrs(ctx, slog.Group("", args...).Value.Group())
+}
+
+// WithAttrs is With for attributes.
+func WithAttrs(ctx context.Context, attrs []slog.Attr) context.Context {
+	f := fromContext(ctx)
+	merged := make([]slog.Attr, 0, len(f.attrs)+len(attrs))
+	for _, a := range f.attrs {
+		if !hasKey(attrs, a.Key) {
+			merged = append(merged, a)
+		}
+	}
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindString && a.Value.String() == "" {
+			continue
+		}
+		merged = append(merged, a)
+	}
+	if len(merged) == 0 {
+		return ctx
+	}
+	f.attrs = merged
+	return context.WithValue(ctx, contextKey{}, f)
+}
+
+// Attrs returns the fields attached to ctx. The slice must not be modified.
+func Attrs(ctx context.Context) []slog.Attr {
+	return fromContext(ctx).attrs
+}
+
+// WithContentMode returns a copy of ctx whose log lines render Content
+// attributes in mode. An empty mode leaves ctx unchanged.
+func WithContentMode(ctx context.Context, mode string) context.Context {
+	if mode == "" {
+		return ctx
+	}
+	f := fromContext(ctx)
+	f.contentMode = mode
+	return context.WithValue(ctx, contextKey{}, f)
+}
+
+// ContentMode returns the content mode of ctx, ContentFull by default.
+func ContentMode(ctx context.Context) string {
+	if mode := fromContext(ctx).contentMode; mode != "" {
+		return mode
+	}
+	return ContentFull
+}
+
+// Inherit returns a copy of ctx carrying the log fields and content mode of
+// from, for work detached from a request.
+func Inherit(ctx, from context.Context) context.Context {
+	f := fromContext(from)
+	if f.attrs == nil && f.contentMode == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, f)
+}
+
+func hasKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// content is user content in a log attribute.
+type content string
+
+// Content returns a log attribute for user content, such as a prompt, which
+// the Handler truncates or hashes according to the content mode of the
+// record's context.
+func Content(key, value string) slog.Attr {
+	return slog.Any(key, content(value))
+}
+
+func (c content) render(mode string) string {
+	switch mode {
+	case ContentTruncate:
+		if r := []rune(c); len(r) > truncatedContentLen {
+			return string(r[:truncatedContentLen]) + "..."
+		}
+	case ContentHash:
+		sum := sha256.Sum256([]byte(c))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+	return string(c)
+}
+
+// Handler adds the trace ID and fields of a record's context to every log
+// record, and renders Content attributes in the context's content mode.
+// Fields the call site logs itself are not repeated.
+type Handler struct {
+	slog.Handler
+}
+
+// NewHandler wraps h so that records logged with a request context carry
+// its fields.
+func NewHandler(h slog.Handler) *Handler {
+	return &Handler{Handler: h}
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	f := fromContext(ctx)
+	mode := ContentMode(ctx)
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	logged := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		logged[a.Key] = true
+		if a.Value.Kind() == slog.KindAny {
+			if c, ok := a.Value.Any().(content); ok {
+				a = slog.String(a.Key, c.render(mode))
+			}
+		}
+		out.AddAttrs(a)
+		return true
+	})
+	if traceID := tracing.IDFromContext(ctx); traceID != "" && !logged[tracing.LogKey] {
+		out.AddAttrs(slog.String(tracing.LogKey, traceID))
+	}
+	for _, a := range f.attrs {
+		if !logged[a.Key] {
+			out.AddAttrs(a)
+		}
+	}
+	return h.Handler.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,105 @@
+package logctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/tracing"
+)
+
+func TestWith(t *testing.T) {
+	parent := With(context.Background(), KeyTenantID, "ai8", KeyRequestID, "")
+	ctx := With(parent, KeyTenantID, "email4ai", slog.Int("attempt", 2))
+
+	attrs := Attrs(ctx)
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attrs, got %v", attrs)
+	}
+	if attrs[0].Key != KeyTenantID || attrs[0].Value.String() != "email4ai" {
+		t.Errorf("expected tenant to be replaced, got %v", attrs[0])
+	}
+	if attrs[1].Key != "attempt" || attrs[1].Value.Int64() != 2 {
+		t.Errorf("unexpected attr: %v", attrs[1])
+	}
+
+	// The parent context is unchanged
+	if attrs := Attrs(parent); len(attrs) != 1 || attrs[0].Value.String() != "ai8" {
+		t.Errorf("unexpected parent attrs: %v", attrs)
+	}
+	if bg := context.Background(); With(bg) != bg {
+		t.Error("expected no new context without attrs")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	ctx := With(tracing.WithID(context.Background(), "trace-1"), KeyTenantID, "ai8", KeyRequestID, "req-1")
+	logger.InfoContext(ctx, "traced", KeyRequestID, "explicit")
+	logger.Info("untraced")
+
+	dec := json.NewDecoder(&buf)
+	var line map[string]any
+	if err := dec.Decode(&line); err != nil {
+		t.Fatalf("decode log line: %v", err)
+	}
+	if line[tracing.LogKey] != "trace-1" || line[KeyTenantID] != "ai8" || line["component"] != "test" {
+		t.Errorf("expected context fields, got %v", line)
+	}
+	if line[KeyRequestID] != "explicit" {
+		t.Errorf("expected the call site's field to win, got %v", line[KeyRequestID])
+	}
+
+	line = nil
+	if err := dec.Decode(&line); err != nil {
+		t.Fatalf("decode log line: %v", err)
+	}
+	if _, ok := line[tracing.LogKey]; ok {
+		t.Errorf("expected no trace ID without a context, got %v", line)
+	}
+	if _, ok := line[KeyTenantID]; ok {
+		t.Errorf("expected no tenant without a context, got %v", line)
+	}
+}
+
+func TestHandler_Content(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil)))
+	prompt := "draw a lighthouse on a rocky coast at dusk, oil painting"
+
+	for _, mode := range []string{"", ContentTruncate, ContentHash} {
+		logger.InfoContext(WithContentMode(context.Background(), mode), "image", Content("prompt", prompt))
+	}
+	// Background work keeps the request's mode
+	logger.InfoContext(Inherit(context.Background(), WithContentMode(context.Background(), ContentHash)), "image", Content("prompt", prompt))
+
+	dec := json.NewDecoder(&buf)
+	var got []string
+	for dec.More() {
+		var line map[string]any
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("decode log line: %v", err)
+		}
+		got = append(got, line["prompt"].(string))
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 lines, got %v", got)
+	}
+	if got[0] != prompt {
+		t.Errorf("full: got %q", got[0])
+	}
+	if got[1] != prompt[:truncatedContentLen]+"..." {
+		t.Errorf("truncate: got %q", got[1])
+	}
+	if !strings.HasPrefix(got[2], "sha256:") || len(got[2]) != len("sha256:")+16 || strings.Contains(got[2], "lighthouse") {
+		t.Errorf("hash: got %q", got[2])
+	}
+	if got[3] != got[2] {
+		t.Errorf("inherited: expected %q, got %q", got[2], got[3])
+	}
+}
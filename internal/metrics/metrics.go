@@ -0,0 +1,482 @@
+// Package metrics provides lightweight in-process RPC metrics.
+package metrics
+
+import (
+	"maps"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the RPC latency histogram.
+// Observations above the last bucket are counted in an overflow bucket.
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	60 * time.Second,
+}
+
+// RPCObservation describes a single completed RPC.
+type RPCObservation struct {
+	Method        string
+	TenantID      string
+	Code          string
+	Latency       time.Duration
+	RequestBytes  int
+	ResponseBytes int
+}
+
+// RPCStats is the aggregated view of RPCs sharing method, tenant and status code.
+type RPCStats struct {
+	Method        string  `json:"method"`
+	TenantID      string  `json:"tenant_id,omitempty"`
+	Code          string  `json:"code"`
+	Count         int64   `json:"count"`
+	TotalMs       int64   `json:"total_ms"`
+	MaxMs         int64   `json:"max_ms"`
+	RequestBytes  int64   `json:"request_bytes"`
+	ResponseBytes int64   `json:"response_bytes"`
+	Buckets       []int64 `json:"latency_buckets"` // Counts per LatencyBuckets entry, plus overflow
+}
+
+// HedgeObservation describes one hedging-enabled generation.
+type HedgeObservation struct {
+	Primary   string  // Provider the request was sent to first
+	Hedge     string  // Provider the hedge request goes to
+	Fired     bool    // The hedge request was sent
+	HedgeWon  bool    // The hedge provider answered first
+	LoserCost float64 // Estimated USD spent on the losing request, when it completed
+}
+
+// HedgeStats is the aggregated view of hedging for a primary/hedge provider pair.
+type HedgeStats struct {
+	Primary       string  `json:"primary"`
+	Hedge         string  `json:"hedge"`
+	Requests      int64   `json:"requests"`        // Hedging-enabled requests
+	Fired         int64   `json:"fired"`           // Requests that sent a hedge
+	HedgeWins     int64   `json:"hedge_wins"`      // Requests answered by the hedge provider
+	WastedCostUSD float64 `json:"wasted_cost_usd"` // Estimated cost of completed losing requests
+}
+
+// TierObservation describes one "auto" model tier generation.
+type TierObservation struct {
+	TenantID  string
+	Provider  string
+	Escalated bool    // The cheap reply failed a check and the premium model answered
+	CheapCost float64 // Estimated USD spent on the escalated cheap reply
+}
+
+// TierStats is the aggregated view of model tiering for a tenant's provider.
+type TierStats struct {
+	TenantID      string  `json:"tenant_id"`
+	Provider      string  `json:"provider"`
+	Requests      int64   `json:"requests"`        // "auto" tier requests
+	Escalations   int64   `json:"escalations"`     // Requests the premium model answered
+	WastedCostUSD float64 `json:"wasted_cost_usd"` // Estimated cost of escalated cheap replies
+}
+
+// EgressObservation describes one outbound HTTP request.
+type EgressObservation struct {
+	Host          string
+	Provider      string // Empty when the request was not made for a provider call
+	TenantID      string
+	Blocked       bool // Rejected by egress enforcement before being sent
+	BytesSent     int64
+	BytesReceived int64
+	Latency       time.Duration
+}
+
+// EgressStats is the aggregated view of outbound requests to a host.
+type EgressStats struct {
+	Host          string `json:"host"`
+	Provider      string `json:"provider,omitempty"`
+	TenantID      string `json:"tenant_id,omitempty"`
+	Requests      int64  `json:"requests"`
+	Blocked       int64  `json:"blocked"`
+	BytesSent     int64  `json:"bytes_sent"`
+	BytesReceived int64  `json:"bytes_received"`
+	TotalMs       int64  `json:"total_ms"`
+}
+
+// IsolationObservation describes one blocked cross-tenant data access.
+type IsolationObservation struct {
+	Layer        string // "db" or "rag"
+	Operation    string
+	TenantID     string // Authenticated tenant
+	TargetTenant string // Tenant the access was scoped to
+}
+
+// IsolationStats is the aggregated view of cross-tenant accesses sharing
+// layer, operation and tenants.
+type IsolationStats struct {
+	Layer          string `json:"layer"`
+	Operation      string `json:"operation"`
+	TenantID       string `json:"tenant_id"`
+	TargetTenantID string `json:"target_tenant_id"`
+	Count          int64  `json:"count"`
+}
+
+// RedisStats is the Redis connection pool state, read when a snapshot is taken.
+type RedisStats struct {
+	Mode       string `json:"mode"`
+	Hits       uint32 `json:"hits"`     // Free connection found in the pool
+	Misses     uint32 `json:"misses"`   // New connection had to be dialled
+	Timeouts   uint32 `json:"timeouts"` // Waits for a connection that timed out
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+
+	Fallbacks []RedisFallbackStats `json:"fallbacks,omitempty"`
+}
+
+// RedisFallbackStats reports a Redis-dependent feature's degradation policy
+// and how often it has been applied.
+type RedisFallbackStats struct {
+	Feature     string `json:"feature"`
+	Policy      string `json:"policy"`
+	Active      bool   `json:"active"`      // Currently running without Redis
+	Activations int64  `json:"activations"` // Requests handled under the policy
+}
+
+type egressKey struct {
+	host     string
+	provider string
+	tenantID string
+}
+
+type isolationKey struct {
+	layer        string
+	operation    string
+	tenantID     string
+	targetTenant string
+}
+
+type hedgeKey struct {
+	primary string
+	hedge   string
+}
+
+type tierKey struct {
+	tenantID string
+	provider string
+}
+
+type rpcKey struct {
+	method   string
+	tenantID string
+	code     string
+}
+
+// Registry aggregates RPC observations. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.Mutex
+	rpcs      map[rpcKey]*RPCStats
+	hedges    map[hedgeKey]*HedgeStats
+	tiers     map[tierKey]*TierStats
+	egress    map[egressKey]*EgressStats
+	isolation map[isolationKey]*IsolationStats
+	slos      map[sloKey]*sloState
+	redis     func() RedisStats
+	since     time.Time
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		rpcs:      make(map[rpcKey]*RPCStats),
+		hedges:    make(map[hedgeKey]*HedgeStats),
+		tiers:     make(map[tierKey]*TierStats),
+		egress:    make(map[egressKey]*EgressStats),
+		isolation: make(map[isolationKey]*IsolationStats),
+		slos:      make(map[sloKey]*sloState),
+		since:     time.Now(),
+	}
+}
+
+// ObserveRPC records a completed RPC. A nil registry ignores observations.
+func (r *Registry) ObserveRPC(o RPCObservation) {
+	if r == nil {
+		return
+	}
+
+	key := rpcKey{method: o.Method, tenantID: o.TenantID, code: o.Code}
+	ms := o.Latency.Milliseconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.rpcs[key]
+	if !ok {
+		stats = &RPCStats{
+			Method:   o.Method,
+			TenantID: o.TenantID,
+			Code:     o.Code,
+			Buckets:  make([]int64, len(LatencyBuckets)+1),
+		}
+		r.rpcs[key] = stats
+	}
+
+	stats.Count++
+	stats.TotalMs += ms
+	if ms > stats.MaxMs {
+		stats.MaxMs = ms
+	}
+	stats.RequestBytes += int64(o.RequestBytes)
+	stats.ResponseBytes += int64(o.ResponseBytes)
+	stats.Buckets[bucketIndex(o.Latency)]++
+}
+
+// ObserveHedge records a hedging-enabled generation. A nil registry ignores observations.
+func (r *Registry) ObserveHedge(o HedgeObservation) {
+	if r == nil {
+		return
+	}
+
+	key := hedgeKey{primary: o.Primary, hedge: o.Hedge}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.hedges[key]
+	if !ok {
+		stats = &HedgeStats{Primary: o.Primary, Hedge: o.Hedge}
+		r.hedges[key] = stats
+	}
+
+	stats.Requests++
+	if o.Fired {
+		stats.Fired++
+	}
+	if o.HedgeWon {
+		stats.HedgeWins++
+	}
+	stats.WastedCostUSD += o.LoserCost
+}
+
+// ObserveTier records an "auto" model tier generation. A nil registry ignores observations.
+func (r *Registry) ObserveTier(o TierObservation) {
+	if r == nil {
+		return
+	}
+
+	key := tierKey{tenantID: o.TenantID, provider: o.Provider}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.tiers[key]
+	if !ok {
+		stats = &TierStats{TenantID: o.TenantID, Provider: o.Provider}
+		r.tiers[key] = stats
+	}
+
+	stats.Requests++
+	if o.Escalated {
+		stats.Escalations++
+	}
+	stats.WastedCostUSD += o.CheapCost
+}
+
+// ObserveEgress records an outbound HTTP request. A nil registry ignores observations.
+func (r *Registry) ObserveEgress(o EgressObservation) {
+	if r == nil {
+		return
+	}
+
+	key := egressKey{host: o.Host, provider: o.Provider, tenantID: o.TenantID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.egress[key]
+	if !ok {
+		stats = &EgressStats{Host: o.Host, Provider: o.Provider, TenantID: o.TenantID}
+		r.egress[key] = stats
+	}
+
+	stats.Requests++
+	if o.Blocked {
+		stats.Blocked++
+	}
+	stats.BytesSent += o.BytesSent
+	stats.BytesReceived += o.BytesReceived
+	stats.TotalMs += o.Latency.Milliseconds()
+}
+
+// ObserveIsolationViolation records a blocked cross-tenant access. A nil
+// registry ignores observations.
+func (r *Registry) ObserveIsolationViolation(o IsolationObservation) {
+	if r == nil {
+		return
+	}
+
+	key := isolationKey{layer: o.Layer, operation: o.Operation, tenantID: o.TenantID, targetTenant: o.TargetTenant}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.isolation[key]
+	if !ok {
+		stats = &IsolationStats{
+			Layer:          o.Layer,
+			Operation:      o.Operation,
+			TenantID:       o.TenantID,
+			TargetTenantID: o.TargetTenant,
+		}
+		r.isolation[key] = stats
+	}
+	stats.Count++
+}
+
+// SetRedisSource registers a function reporting Redis pool stats, included
+// in every snapshot. A nil registry ignores it.
+func (r *Registry) SetRedisSource(fn func() RedisStats) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redis = fn
+}
+
+// Snapshot is a point-in-time copy of the registry.
+type Snapshot struct {
+	Since          time.Time        `json:"since"`
+	LatencyBuckets []int64          `json:"latency_bucket_bounds_ms"`
+	RPCs           []RPCStats       `json:"rpcs"`
+	Hedges         []HedgeStats     `json:"hedges"`
+	Tiers          []TierStats      `json:"tiers"`
+	Egress         []EgressStats    `json:"egress"`
+	Isolation      []IsolationStats `json:"isolation_violations"`
+	SLOs           []SLOStats       `json:"slos"`
+	Redis          *RedisStats      `json:"redis,omitempty"` // Nil when Redis is not in use
+}
+
+// Snapshot returns a copy of all aggregated stats, sorted by method, tenant and code.
+func (r *Registry) Snapshot() Snapshot {
+	bounds := make([]int64, len(LatencyBuckets))
+	for i, b := range LatencyBuckets {
+		bounds[i] = b.Milliseconds()
+	}
+	if r == nil {
+		return Snapshot{LatencyBuckets: bounds, RPCs: []RPCStats{}, Hedges: []HedgeStats{}, Tiers: []TierStats{}, Egress: []EgressStats{}, Isolation: []IsolationStats{}, SLOs: []SLOStats{}}
+	}
+
+	r.mu.Lock()
+	snap := Snapshot{
+		Since:          r.since,
+		LatencyBuckets: bounds,
+		RPCs:           make([]RPCStats, 0, len(r.rpcs)),
+		Hedges:         make([]HedgeStats, 0, len(r.hedges)),
+		Tiers:          make([]TierStats, 0, len(r.tiers)),
+		Egress:         make([]EgressStats, 0, len(r.egress)),
+		Isolation:      make([]IsolationStats, 0, len(r.isolation)),
+		SLOs:           make([]SLOStats, 0, len(r.slos)),
+	}
+	for _, stats := range r.rpcs {
+		cp := *stats
+		cp.Buckets = append([]int64(nil), stats.Buckets...)
+		snap.RPCs = append(snap.RPCs, cp)
+	}
+	for _, stats := range r.hedges {
+		snap.Hedges = append(snap.Hedges, *stats)
+	}
+	for _, stats := range r.tiers {
+		snap.Tiers = append(snap.Tiers, *stats)
+	}
+	for _, stats := range r.egress {
+		snap.Egress = append(snap.Egress, *stats)
+	}
+	for _, stats := range r.isolation {
+		snap.Isolation = append(snap.Isolation, *stats)
+	}
+	for _, st := range r.slos {
+		cp := st.stats
+		cp.SlowByProvider = maps.Clone(st.stats.SlowByProvider)
+		if st.stats.LastBreachAt != nil {
+			at := *st.stats.LastBreachAt
+			cp.LastBreachAt = &at
+		}
+		snap.SLOs = append(snap.SLOs, cp)
+	}
+	redisSource := r.redis
+	r.mu.Unlock()
+
+	if redisSource != nil {
+		stats := redisSource()
+		snap.Redis = &stats
+	}
+
+	sort.Slice(snap.RPCs, func(i, j int) bool {
+		a, b := snap.RPCs[i], snap.RPCs[j]
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.TenantID != b.TenantID {
+			return a.TenantID < b.TenantID
+		}
+		return a.Code < b.Code
+	})
+	sort.Slice(snap.Hedges, func(i, j int) bool {
+		a, b := snap.Hedges[i], snap.Hedges[j]
+		if a.Primary != b.Primary {
+			return a.Primary < b.Primary
+		}
+		return a.Hedge < b.Hedge
+	})
+	sort.Slice(snap.Tiers, func(i, j int) bool {
+		a, b := snap.Tiers[i], snap.Tiers[j]
+		if a.TenantID != b.TenantID {
+			return a.TenantID < b.TenantID
+		}
+		return a.Provider < b.Provider
+	})
+	sort.Slice(snap.Egress, func(i, j int) bool {
+		a, b := snap.Egress[i], snap.Egress[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.TenantID < b.TenantID
+	})
+	sort.Slice(snap.Isolation, func(i, j int) bool {
+		a, b := snap.Isolation[i], snap.Isolation[j]
+		if a.Layer != b.Layer {
+			return a.Layer < b.Layer
+		}
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		if a.TenantID != b.TenantID {
+			return a.TenantID < b.TenantID
+		}
+		return a.TargetTenantID < b.TargetTenantID
+	})
+	sort.Slice(snap.SLOs, func(i, j int) bool {
+		a, b := snap.SLOs[i], snap.SLOs[j]
+		if a.TenantID != b.TenantID {
+			return a.TenantID < b.TenantID
+		}
+		return a.Objective < b.Objective
+	})
+	return snap
+}
+
+// bucketIndex returns the histogram bucket for a latency.
+func bucketIndex(d time.Duration) int {
+	for i, b := range LatencyBuckets {
+		if d <= b {
+			return i
+		}
+	}
+	return len(LatencyBuckets)
+}
@@ -0,0 +1,207 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistry_ObserveRPC(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveRPC(RPCObservation{Method: "/a", TenantID: "t1", Code: "OK", Latency: 10 * time.Millisecond, RequestBytes: 5, ResponseBytes: 7})
+	r.ObserveRPC(RPCObservation{Method: "/a", TenantID: "t1", Code: "OK", Latency: 2 * time.Minute, RequestBytes: 1, ResponseBytes: 2})
+	r.ObserveRPC(RPCObservation{Method: "/a", TenantID: "t1", Code: "Internal", Latency: 300 * time.Millisecond})
+
+	snap := r.Snapshot()
+	if len(snap.RPCs) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(snap.RPCs))
+	}
+
+	// Sorted by code: Internal before OK
+	if snap.RPCs[0].Code != "Internal" || snap.RPCs[1].Code != "OK" {
+		t.Fatalf("unexpected order: %+v", snap.RPCs)
+	}
+
+	ok := snap.RPCs[1]
+	if ok.Count != 2 {
+		t.Errorf("expected count 2, got %d", ok.Count)
+	}
+	if ok.MaxMs != (2 * time.Minute).Milliseconds() {
+		t.Errorf("expected max 120000ms, got %d", ok.MaxMs)
+	}
+	if ok.RequestBytes != 6 || ok.ResponseBytes != 9 {
+		t.Errorf("unexpected byte totals: req=%d resp=%d", ok.RequestBytes, ok.ResponseBytes)
+	}
+	if ok.Buckets[0] != 1 {
+		t.Errorf("expected 10ms in first bucket, got %v", ok.Buckets)
+	}
+	if ok.Buckets[len(LatencyBuckets)] != 1 {
+		t.Errorf("expected 2m in overflow bucket, got %v", ok.Buckets)
+	}
+}
+
+func TestRegistry_SnapshotIsCopy(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveRPC(RPCObservation{Method: "/a", Code: "OK", Latency: time.Millisecond})
+
+	snap := r.Snapshot()
+	snap.RPCs[0].Buckets[0] = 99
+
+	if got := r.Snapshot().RPCs[0].Buckets[0]; got != 1 {
+		t.Errorf("snapshot mutation leaked into registry: %d", got)
+	}
+}
+
+func TestRegistry_NilSafe(t *testing.T) {
+	var r *Registry
+	r.ObserveRPC(RPCObservation{Method: "/a", Code: "OK"})
+
+	snap := r.Snapshot()
+	if len(snap.RPCs) != 0 {
+		t.Errorf("expected empty snapshot, got %d", len(snap.RPCs))
+	}
+}
+
+func TestRegistry_ObserveHedge(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveHedge(HedgeObservation{Primary: "openai", Hedge: "gemini"})
+	r.ObserveHedge(HedgeObservation{Primary: "openai", Hedge: "gemini", Fired: true, HedgeWon: true, LoserCost: 0.25})
+	r.ObserveHedge(HedgeObservation{Primary: "anthropic", Hedge: "openai", Fired: true})
+
+	snap := r.Snapshot()
+	if len(snap.Hedges) != 2 || snap.Hedges[0].Primary != "anthropic" {
+		t.Fatalf("unexpected hedge series: %+v", snap.Hedges)
+	}
+	got := snap.Hedges[1]
+	if got.Requests != 2 || got.Fired != 1 || got.HedgeWins != 1 || got.WastedCostUSD != 0.25 {
+		t.Errorf("unexpected openai/gemini stats: %+v", got)
+	}
+}
+
+func TestRegistry_ObserveTier(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveTier(TierObservation{TenantID: "t1", Provider: "openai"})
+	r.ObserveTier(TierObservation{TenantID: "t1", Provider: "openai", Escalated: true, CheapCost: 0.01})
+	r.ObserveTier(TierObservation{TenantID: "t0", Provider: "gemini"})
+
+	snap := r.Snapshot()
+	if len(snap.Tiers) != 2 || snap.Tiers[0].TenantID != "t0" {
+		t.Fatalf("unexpected tier series: %+v", snap.Tiers)
+	}
+	got := snap.Tiers[1]
+	if got.Requests != 2 || got.Escalations != 1 || got.WastedCostUSD != 0.01 {
+		t.Errorf("unexpected t1/openai stats: %+v", got)
+	}
+}
+
+func TestRegistry_ObserveEgress(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveEgress(EgressObservation{Host: "api.openai.com", Provider: "openai", TenantID: "t1", BytesSent: 100, BytesReceived: 900, Latency: 200 * time.Millisecond})
+	r.ObserveEgress(EgressObservation{Host: "api.openai.com", Provider: "openai", TenantID: "t1", BytesSent: 50, BytesReceived: 100, Latency: 100 * time.Millisecond})
+	r.ObserveEgress(EgressObservation{Host: "evil.example.com", TenantID: "t1", Blocked: true})
+
+	snap := r.Snapshot()
+	if len(snap.Egress) != 2 || snap.Egress[0].Host != "api.openai.com" {
+		t.Fatalf("unexpected egress series: %+v", snap.Egress)
+	}
+	got := snap.Egress[0]
+	if got.Requests != 2 || got.BytesSent != 150 || got.BytesReceived != 1000 || got.TotalMs != 300 {
+		t.Errorf("unexpected api.openai.com stats: %+v", got)
+	}
+	if snap.Egress[1].Blocked != 1 {
+		t.Errorf("expected blocked request to be counted: %+v", snap.Egress[1])
+	}
+}
+
+func TestRegistry_ObserveIsolationViolation(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveIsolationViolation(IsolationObservation{Layer: "rag", Operation: "Retrieve", TenantID: "t1", TargetTenant: "t2"})
+	r.ObserveIsolationViolation(IsolationObservation{Layer: "rag", Operation: "Retrieve", TenantID: "t1", TargetTenant: "t2"})
+	r.ObserveIsolationViolation(IsolationObservation{Layer: "db", Operation: "GetThread", TenantID: "t1", TargetTenant: "t2"})
+
+	snap := r.Snapshot()
+	if len(snap.Isolation) != 2 || snap.Isolation[0].Layer != "db" {
+		t.Fatalf("unexpected isolation series: %+v", snap.Isolation)
+	}
+	if got := snap.Isolation[1]; got.Count != 2 || got.TargetTenantID != "t2" {
+		t.Errorf("unexpected rag violations: %+v", got)
+	}
+}
+
+func TestRegistry_RedisSource(t *testing.T) {
+	r := NewRegistry()
+	if snap := r.Snapshot(); snap.Redis != nil {
+		t.Fatalf("expected no redis stats without a source, got %+v", snap.Redis)
+	}
+
+	r.SetRedisSource(func() RedisStats {
+		return RedisStats{Mode: "cluster", TotalConns: 6, IdleConns: 4, Timeouts: 1}
+	})
+	snap := r.Snapshot()
+	if snap.Redis == nil || snap.Redis.Mode != "cluster" || snap.Redis.TotalConns != 6 || snap.Redis.Timeouts != 1 {
+		t.Errorf("unexpected redis stats: %+v", snap.Redis)
+	}
+}
+
+func TestRegistry_ObserveSLO(t *testing.T) {
+	r := NewRegistry()
+	observe := func(provider string, latency time.Duration) *SLOTransition {
+		return r.ObserveSLO(SLOObservation{TenantID: "t1", Objective: SLOCompletion, Provider: provider, Latency: latency, Percentile: 95, Threshold: time.Second})
+	}
+
+	// Slow requests alone do not breach before SLOMinSamples
+	for range SLOMinSamples - 1 {
+		if got := observe("openai", 3*time.Second); got != nil {
+			t.Fatalf("breached before min samples: %+v", got)
+		}
+	}
+	got := observe("gemini", 3*time.Second)
+	if got == nil || !got.Breached || got.Provider != "gemini" || got.CurrentMs != 3000 || got.ThresholdMs != 1000 {
+		t.Fatalf("expected breach, got %+v", got)
+	}
+	if got := observe("openai", 3*time.Second); got != nil {
+		t.Fatalf("expected no repeated transition, got %+v", got)
+	}
+
+	// Recovers once fast requests push the slow ones out of the top 5%
+	var recovered *SLOTransition
+	for range SLOWindow {
+		if tr := observe("openai", 100*time.Millisecond); tr != nil {
+			recovered = tr
+			break
+		}
+	}
+	if recovered == nil || recovered.Breached {
+		t.Fatalf("expected recovery, got %+v", recovered)
+	}
+
+	snap := r.Snapshot()
+	if len(snap.SLOs) != 1 {
+		t.Fatalf("expected 1 objective, got %+v", snap.SLOs)
+	}
+	st := snap.SLOs[0]
+	if st.Breached || st.Breaches != 1 || st.LastBreachAt == nil || st.Slow != 21 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+	if st.SlowByProvider["openai"] != 20 || st.SlowByProvider["gemini"] != 1 {
+		t.Errorf("unexpected slow by provider: %v", st.SlowByProvider)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{50: 50 * time.Millisecond, 95: 95 * time.Millisecond, 99.9: 100 * time.Millisecond} {
+		if got := percentile(latencies, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(nil, 95); got != 0 {
+		t.Errorf("empty percentile = %v", got)
+	}
+}
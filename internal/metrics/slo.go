@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Latency objectives a tenant can set.
+const (
+	SLOFirstToken = "first_token" // Time to the first text token of a stream
+	SLOCompletion = "completion"  // Time to the complete reply
+)
+
+const (
+	// SLOWindow is how many recent latencies an objective's percentile is
+	// computed over.
+	SLOWindow = 200
+
+	// SLOMinSamples is how many latencies an objective needs before it can
+	// be breached, so a few slow requests after startup do not alert.
+	SLOMinSamples = 20
+)
+
+// SLOObservation describes one latency measured against a tenant objective.
+type SLOObservation struct {
+	TenantID   string
+	Objective  string // SLOFirstToken or SLOCompletion
+	Provider   string
+	Latency    time.Duration
+	Percentile float64       // e.g. 95
+	Threshold  time.Duration // Latency the percentile must stay within
+}
+
+// SLOStats is the state of one tenant objective.
+type SLOStats struct {
+	TenantID       string           `json:"tenant_id"`
+	Objective      string           `json:"objective"`
+	Percentile     float64          `json:"percentile"`
+	ThresholdMs    int64            `json:"threshold_ms"`
+	Count          int64            `json:"count"`
+	Slow           int64            `json:"slow"` // Requests over the threshold
+	SlowByProvider map[string]int64 `json:"slow_by_provider,omitempty"`
+	CurrentMs      int64            `json:"current_ms"` // The percentile over the last SLOWindow requests
+	Breached       bool             `json:"breached"`
+	Breaches       int64            `json:"breaches"` // Times the objective went into breach
+	LastBreachAt   *time.Time       `json:"last_breach_at,omitempty"`
+}
+
+// SLOTransition reports an objective going into or out of breach.
+type SLOTransition struct {
+	TenantID    string
+	Objective   string
+	Provider    string // Provider of the request that changed the state
+	Breached    bool   // False when the objective recovered
+	Percentile  float64
+	ThresholdMs int64
+	CurrentMs   int64
+	At          time.Time
+}
+
+type sloKey struct {
+	tenantID  string
+	objective string
+}
+
+type sloState struct {
+	stats  SLOStats
+	window []time.Duration // Ring buffer of the last SLOWindow latencies
+	next   int
+}
+
+// ObserveSLO records a latency against its objective. The objective is
+// breached while the percentile of its last SLOWindow latencies exceeds the
+// threshold, once SLOMinSamples have been seen. It returns the transition
+// when the objective goes into or out of breach, else nil. A nil registry
+// ignores observations.
+func (r *Registry) ObserveSLO(o SLOObservation) *SLOTransition {
+	if r == nil {
+		return nil
+	}
+
+	key := sloKey{tenantID: o.TenantID, objective: o.Objective}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st, ok := r.slos[key]
+	if !ok {
+		st = &sloState{stats: SLOStats{TenantID: o.TenantID, Objective: o.Objective}}
+		r.slos[key] = st
+	}
+	// Objectives change when tenant config is reloaded
+	st.stats.Percentile = o.Percentile
+	st.stats.ThresholdMs = o.Threshold.Milliseconds()
+
+	if len(st.window) < SLOWindow {
+		st.window = append(st.window, o.Latency)
+	} else {
+		st.window[st.next] = o.Latency
+		st.next = (st.next + 1) % SLOWindow
+	}
+	st.stats.Count++
+	if o.Latency > o.Threshold {
+		st.stats.Slow++
+		if st.stats.SlowByProvider == nil {
+			st.stats.SlowByProvider = make(map[string]int64)
+		}
+		st.stats.SlowByProvider[o.Provider]++
+	}
+
+	current := percentile(st.window, o.Percentile)
+	st.stats.CurrentMs = current.Milliseconds()
+	breached := len(st.window) >= SLOMinSamples && current > o.Threshold
+	if breached == st.stats.Breached {
+		return nil
+	}
+
+	now := time.Now()
+	st.stats.Breached = breached
+	if breached {
+		st.stats.Breaches++
+		st.stats.LastBreachAt = &now
+	}
+	return &SLOTransition{
+		TenantID:    o.TenantID,
+		Objective:   o.Objective,
+		Provider:    o.Provider,
+		Breached:    breached,
+		Percentile:  o.Percentile,
+		ThresholdMs: st.stats.ThresholdMs,
+		CurrentMs:   st.stats.CurrentMs,
+		At:          now,
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of latencies.
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
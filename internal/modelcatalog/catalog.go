@@ -0,0 +1,135 @@
+// Package modelcatalog enforces which models clients may select per provider,
+// combining per-tenant allow lists with a server-wide deny list.
+package modelcatalog
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Catalog holds the server-wide model deny list.
+// A nil Catalog denies nothing but still enforces tenant allow lists.
+type Catalog struct {
+	deny []string
+}
+
+// New creates a catalog from server-wide deny patterns.
+// Patterns are matched case-insensitively; a trailing "*" matches any suffix.
+func New(deny []string) *Catalog {
+	return &Catalog{deny: normalize(deny)}
+}
+
+// Check verifies that model may be used with the provider.
+// allowed is the tenant's allow list for the provider (empty means any model).
+// Returns a *NotAllowedError when the model is denied or not allow-listed.
+func (c *Catalog) Check(providerName, model string, allowed []string) error {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return nil
+	}
+	allowed = normalize(allowed)
+
+	if c != nil && matchAny(c.deny, model) {
+		return &NotAllowedError{
+			Provider:  providerName,
+			Model:     model,
+			Reason:    "denied by server policy",
+			Permitted: c.permitted(allowed),
+		}
+	}
+
+	if len(allowed) > 0 && !matchAny(allowed, model) {
+		return &NotAllowedError{
+			Provider:  providerName,
+			Model:     model,
+			Reason:    "not in tenant allow list",
+			Permitted: c.permitted(allowed),
+		}
+	}
+
+	return nil
+}
+
+// permitted returns the allow-list entries that are not themselves denied.
+func (c *Catalog) permitted(allowed []string) []string {
+	var out []string
+	for _, a := range allowed {
+		if c != nil && !strings.HasSuffix(a, "*") && matchAny(c.deny, a) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// Match reports whether model matches pattern (case-insensitive, trailing "*" wildcard).
+func Match(pattern, model string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	model = strings.ToLower(strings.TrimSpace(model))
+	if pattern == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(model, prefix)
+	}
+	return pattern == model
+}
+
+func matchAny(patterns []string, model string) bool {
+	for _, p := range patterns {
+		if Match(p, model) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(patterns []string) []string {
+	var out []string
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// NotAllowedError is returned when a requested model is not permitted.
+type NotAllowedError struct {
+	Provider  string
+	Model     string
+	Reason    string
+	Permitted []string // Empty means any model that is not denied
+}
+
+func (e *NotAllowedError) Error() string {
+	permitted := "any model not denied by server policy"
+	if len(e.Permitted) > 0 {
+		permitted = strings.Join(e.Permitted, ", ")
+	}
+	return fmt.Sprintf("model %q is not allowed for provider %s (%s); permitted models: %s",
+		e.Model, e.Provider, e.Reason, permitted)
+}
+
+// GRPCStatus converts the error to a PermissionDenied status carrying an
+// ErrorInfo detail, so clients can read the permitted models programmatically.
+func (e *NotAllowedError) GRPCStatus() *status.Status {
+	st := status.New(codes.PermissionDenied, e.Error())
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "MODEL_NOT_ALLOWED",
+		Domain: "airborne",
+		Metadata: map[string]string{
+			"provider":         e.Provider,
+			"model":            e.Model,
+			"permitted_models": strings.Join(e.Permitted, ","),
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
@@ -0,0 +1,110 @@
+package modelcatalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, model string
+		want           bool
+	}{
+		{"gpt-4o", "gpt-4o", true},
+		{"GPT-4o", "gpt-4o", true},
+		{"gpt-4o", "gpt-4o-mini", false},
+		{"gpt-4o*", "gpt-4o-mini", true},
+		{"o1*", "gpt-4o", false},
+		{"", "gpt-4o", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.model); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestCheck_EmptyModelAlwaysAllowed(t *testing.T) {
+	c := New([]string{"*"})
+	if err := c.Check("openai", "  ", []string{"gpt-4o"}); err != nil {
+		t.Errorf("expected nil for empty model, got %v", err)
+	}
+}
+
+func TestCheck_DenyList(t *testing.T) {
+	c := New([]string{"gpt-4-32k", "o1-pro*"})
+
+	err := c.Check("openai", "o1-pro-2025", nil)
+	var notAllowed *NotAllowedError
+	if !errors.As(err, &notAllowed) {
+		t.Fatalf("expected NotAllowedError, got %v", err)
+	}
+	if notAllowed.Reason != "denied by server policy" {
+		t.Errorf("unexpected reason %q", notAllowed.Reason)
+	}
+
+	if err := c.Check("openai", "gpt-4o", nil); err != nil {
+		t.Errorf("expected gpt-4o to be allowed, got %v", err)
+	}
+}
+
+func TestCheck_TenantAllowList(t *testing.T) {
+	c := New([]string{"gpt-4-32k"})
+	allowed := []string{"gpt-4o", "gpt-4o-mini", "gpt-4-32k"}
+
+	if err := c.Check("openai", "gpt-4o-mini", allowed); err != nil {
+		t.Errorf("expected allow-listed model to pass, got %v", err)
+	}
+
+	err := c.Check("openai", "gpt-5", allowed)
+	var notAllowed *NotAllowedError
+	if !errors.As(err, &notAllowed) {
+		t.Fatalf("expected NotAllowedError, got %v", err)
+	}
+	if got := strings.Join(notAllowed.Permitted, ","); got != "gpt-4o,gpt-4o-mini" {
+		t.Errorf("Permitted = %q, want denied entries removed", got)
+	}
+	if !strings.Contains(err.Error(), "permitted models: gpt-4o, gpt-4o-mini") {
+		t.Errorf("error message should list permitted models: %v", err)
+	}
+}
+
+func TestCheck_NilCatalogEnforcesAllowList(t *testing.T) {
+	var c *Catalog
+	if err := c.Check("gemini", "gemini-2.5-pro", []string{"gemini-2.5-flash"}); err == nil {
+		t.Error("expected error for model outside allow list")
+	}
+	if err := c.Check("gemini", "gemini-2.5-pro", nil); err != nil {
+		t.Errorf("expected nil with no allow list, got %v", err)
+	}
+}
+
+func TestNotAllowedError_GRPCStatus(t *testing.T) {
+	err := New(nil).Check("openai", "gpt-5", []string{"gpt-4o"})
+
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("expected error to convert to gRPC status")
+	}
+	if st.Code() != codes.PermissionDenied {
+		t.Errorf("code = %v, want PermissionDenied", st.Code())
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	if info == nil {
+		t.Fatal("expected ErrorInfo detail")
+	}
+	if info.Reason != "MODEL_NOT_ALLOWED" || info.Metadata["permitted_models"] != "gpt-4o" {
+		t.Errorf("unexpected ErrorInfo: %+v", info)
+	}
+}
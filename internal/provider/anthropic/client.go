@@ -20,8 +20,6 @@ import (
 const (
 	thinkingTimeout = 15 * time.Minute // Extended timeout for thinking operations
 	defaultModel    = "claude-sonnet-4-20250514"
-	// maxHistoryChars limits conversation history to prevent context overflow
-	maxHistoryChars = 50000
 )
 
 // Client implements the provider.Provider interface using Anthropic's Messages API.
@@ -75,6 +73,11 @@ func (c *Client) SupportsStreaming() bool {
 	return true
 }
 
+// DefaultModel returns the model used when none is configured.
+func (c *Client) DefaultModel() string {
+	return defaultModel
+}
+
 // GenerateReply implements provider.Provider using Anthropic's Messages API.
 func (c *Client) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
 	cfg := params.Config
@@ -104,7 +107,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	defer cancel()
 
 	// Create captured client config with validation
-	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL)
+	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL, cfg.Proxy)
 	if err != nil {
 		return provider.GenerateResult{}, fmt.Errorf("client setup: %w", err)
 	}
@@ -117,12 +120,16 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	if httpCfg.BaseURL != "" {
 		opts = append(opts, option.WithBaseURL(httpCfg.BaseURL))
 	}
+	opts = append(opts, computerUseOptions(params.ComputerUse)...)
 
 	client := anthropic.NewClient(opts...)
 	capture := httpCfg.Capture
 
 	// Build messages from history and current input
-	messages := buildMessages(params.UserInput, params.ConversationHistory)
+	messages, replay, err := requestMessages(params)
+	if err != nil {
+		return provider.GenerateResult{}, err
+	}
 
 	// Build request parameters
 	maxTokens := int64(4096)
@@ -143,6 +150,14 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		}
 	}
 
+	// Add custom function tools
+	for _, tool := range params.Tools {
+		reqParams.Tools = append(reqParams.Tools, buildTool(tool))
+	}
+	if params.ComputerUse != nil {
+		reqParams.Tools = append(reqParams.Tools, buildComputerTool(params.ComputerUse))
+	}
+
 	// Apply optional parameters
 	if cfg.Temperature != nil {
 		reqParams.Temperature = anthropic.Float(*cfg.Temperature)
@@ -161,7 +176,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	}
 
 	if c.debug {
-		slog.Debug("anthropic request",
+		slog.DebugContext(ctx, "anthropic request",
 			"model", model,
 			"thinking_enabled", thinkingEnabled,
 			"thinking_budget", thinkingBudget,
@@ -172,7 +187,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	// Execute with retry
 	var lastErr error
 	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
-		slog.Info("anthropic request",
+		slog.InfoContext(ctx, "anthropic request",
 			"attempt", attempt,
 			"model", model,
 			"thinking_enabled", thinkingEnabled,
@@ -210,7 +225,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			// Check if parent context is still valid
 			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
 				lastErr = fmt.Errorf("anthropic request timeout: %w", err)
-				slog.Warn("anthropic timeout, retrying", "attempt", attempt)
+				slog.WarnContext(ctx, "anthropic timeout, retrying", "attempt", attempt)
 				if attempt < retry.MaxAttempts {
 					retry.SleepWithBackoff(ctx, attempt)
 					continue
@@ -223,7 +238,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 				return provider.GenerateResult{}, lastErr
 			}
 
-			slog.Warn("anthropic retryable error", "attempt", attempt, "error", err)
+			slog.WarnContext(ctx, "anthropic retryable error", "attempt", attempt, "error", err)
 			if attempt < retry.MaxAttempts {
 				retry.SleepWithBackoff(ctx, attempt)
 				continue
@@ -231,9 +246,26 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			return provider.GenerateResult{}, lastErr
 		}
 
+		// Report a refusal in the result rather than as an error
+		if block := refusalBlock(resp.StopReason); block != nil {
+			slog.WarnContext(ctx, "anthropic response refused", "model", model)
+			return provider.GenerateResult{
+				ResponseID: resp.ID,
+				Model:      model,
+				Usage: &provider.Usage{
+					InputTokens:  int64(resp.Usage.InputTokens),
+					OutputTokens: int64(resp.Usage.OutputTokens),
+					TotalTokens:  int64(resp.Usage.InputTokens + resp.Usage.OutputTokens),
+				},
+				SafetyBlock: block,
+			}, nil
+		}
+
 		// Extract text and thinking from response
 		text, thinkingText := extractContent(resp, includeThoughts)
-		if text == "" {
+		toolCalls := extractToolCalls(resp, params.ComputerUse != nil)
+		computerActions := extractComputerActions(resp, params.ComputerUse != nil)
+		if text == "" && len(toolCalls) == 0 && len(computerActions) == 0 {
 			lastErr = errors.New("anthropic returned empty response")
 			if attempt < retry.MaxAttempts {
 				retry.SleepWithBackoff(ctx, attempt)
@@ -253,7 +285,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			TotalTokens:  int64(resp.Usage.InputTokens + resp.Usage.OutputTokens),
 		}
 
-		slog.Info("anthropic request completed",
+		slog.InfoContext(ctx, "anthropic request completed",
 			"model", model,
 			"tokens_in", usage.InputTokens,
 			"tokens_out", usage.OutputTokens,
@@ -266,18 +298,34 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		}
 
 		return provider.GenerateResult{
-			Text:         finalText,
-			ResponseID:   resp.ID,
-			Usage:        usage,
-			Model:        model,
-			RequestJSON:  reqJSON,
-			ResponseJSON: respJSON,
+			Text:               finalText,
+			ResponseID:         resp.ID,
+			Usage:              usage,
+			Model:              model,
+			ToolCalls:          toolCalls,
+			RequiresToolOutput: len(toolCalls) > 0 || len(computerActions) > 0,
+			ToolTurnState:      toolTurnState(replay, resp, provider.PendingCalls(toolCalls, computerActions)),
+			ComputerActions:    computerActions,
+			SystemPrompt:       params.Instructions,
+			UserPrompt:         strings.TrimSpace(params.UserInput),
+			RequestJSON:        reqJSON,
+			ResponseJSON:       respJSON,
 		}, nil
 	}
 
 	return provider.GenerateResult{}, lastErr
 }
 
+// refusalBlock returns a safety block if the model stopped because it
+// refused the request. Anthropic has no adjustable safety thresholds, so
+// refusals are the only blocks it reports.
+func refusalBlock(reason anthropic.StopReason) *provider.SafetyBlock {
+	if reason != anthropic.StopReasonRefusal {
+		return nil
+	}
+	return &provider.SafetyBlock{Stage: "response", Reason: "model refused the request"}
+}
+
 // GenerateReplyStream implements streaming responses.
 func (c *Client) GenerateReplyStream(ctx context.Context, params provider.GenerateParams) (<-chan provider.StreamChunk, error) {
 	cfg := params.Config
@@ -300,7 +348,7 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 	model := provider.SelectModel(cfg.Model, defaultModel, params.OverrideModel)
 
 	// Create captured client config with validation
-	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL)
+	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL, cfg.Proxy)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("client setup: %w", err)
@@ -314,11 +362,16 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 	if httpCfg.BaseURL != "" {
 		opts = append(opts, option.WithBaseURL(httpCfg.BaseURL))
 	}
+	opts = append(opts, computerUseOptions(params.ComputerUse)...)
 
 	client := anthropic.NewClient(opts...)
 
 	// Build messages
-	messages := buildMessages(params.UserInput, params.ConversationHistory)
+	messages, replay, err := requestMessages(params)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 
 	maxTokens := int64(4096)
 	if cfg.MaxOutputTokens != nil {
@@ -337,6 +390,13 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		}
 	}
 
+	for _, tool := range params.Tools {
+		reqParams.Tools = append(reqParams.Tools, buildTool(tool))
+	}
+	if params.ComputerUse != nil {
+		reqParams.Tools = append(reqParams.Tools, buildComputerTool(params.ComputerUse))
+	}
+
 	if cfg.Temperature != nil {
 		reqParams.Temperature = anthropic.Float(*cfg.Temperature)
 	}
@@ -371,7 +431,7 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		for stream.Next() {
 			event := stream.Current()
 			if err := message.Accumulate(event); err != nil {
-			slog.Warn("failed to accumulate stream event", "error", err)
+			slog.WarnContext(ctx, "failed to accumulate stream event", "error", err)
 		}
 
 			switch eventVariant := event.AsAny().(type) {
@@ -407,75 +467,52 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 			TotalTokens:  int64(message.Usage.InputTokens + message.Usage.OutputTokens),
 		}
 
+		// Tool inputs arrive as partial JSON, so calls are sent once complete
+		toolCalls := extractToolCalls(&message, params.ComputerUse != nil)
+		for i := range toolCalls {
+			ch <- provider.StreamChunk{
+				Type:     provider.ChunkTypeToolCall,
+				ToolCall: &toolCalls[i],
+			}
+		}
+		computerActions := extractComputerActions(&message, params.ComputerUse != nil)
+		for i := range computerActions {
+			ch <- provider.StreamChunk{
+				Type:           provider.ChunkTypeComputerAction,
+				ComputerAction: &computerActions[i],
+			}
+		}
+
 		ch <- provider.StreamChunk{
-			Type:       provider.ChunkTypeComplete,
-			ResponseID: message.ID,
-			Model:      model,
-			Usage:      usage,
+			Type:               provider.ChunkTypeComplete,
+			ResponseID:         message.ID,
+			Model:              model,
+			Usage:              usage,
+			ToolCalls:          toolCalls,
+			RequiresToolOutput: len(toolCalls) > 0 || len(computerActions) > 0,
+			ToolTurnState:      toolTurnState(replay, &message, provider.PendingCalls(toolCalls, computerActions)),
+			ComputerActions:    computerActions,
+			SafetyBlock:        refusalBlock(message.StopReason),
+			SystemPrompt:       params.Instructions,
+			UserPrompt:         strings.TrimSpace(params.UserInput),
 		}
 	}()
 
 	return ch, nil
 }
 
-// buildMessages builds conversation messages from history and current input.
+// buildMessages adapts the conversation to Anthropic messages.
 func buildMessages(userInput string, history []provider.Message) []anthropic.MessageParam {
-	var messages []anthropic.MessageParam
-
-	// Add conversation history with size limit (keeping newest messages)
-	// First, collect valid messages and calculate what to keep
-	type validMsg struct {
-		role    string
-		content string
-		length  int
-	}
-	var validHistory []validMsg
-
-	for _, msg := range history {
-		trimmed := strings.TrimSpace(msg.Content)
-		if trimmed == "" {
-			continue
-		}
-		validHistory = append(validHistory, validMsg{
-			role:    msg.Role,
-			content: trimmed,
-			length:  len(trimmed),
-		})
-	}
-
-	// Calculate which messages to keep (iterate backwards to prioritize newest)
-	var startIndex int
-	currentChars := 0
-	for i := len(validHistory) - 1; i >= 0; i-- {
-		if currentChars+validHistory[i].length > maxHistoryChars {
-			startIndex = i + 1
-			slog.Debug("truncating conversation history",
-				"kept_messages", len(validHistory)-startIndex,
-				"dropped_messages", startIndex)
-			break
-		}
-		currentChars += validHistory[i].length
-	}
-
-	// Build final message list from startIndex onwards
-	for i := startIndex; i < len(validHistory); i++ {
-		msg := validHistory[i]
-		if msg.role == "assistant" {
-			messages = append(messages, anthropic.NewAssistantMessage(
-				anthropic.NewTextBlock(msg.content),
-			))
+	turns := provider.BuildConversation(userInput, history)
+	messages := make([]anthropic.MessageParam, 0, len(turns)+1)
+	for _, turn := range turns {
+		if turn.Role == provider.RoleAssistant {
+			messages = append(messages, anthropic.NewAssistantMessage(anthropic.NewTextBlock(turn.Text)))
 		} else {
-			messages = append(messages, anthropic.NewUserMessage(
-				anthropic.NewTextBlock(msg.content),
-			))
+			messages = append(messages, anthropic.NewUserMessage(anthropic.NewTextBlock(turn.Text)))
 		}
 	}
 
-	// Add current user input
-	messages = append(messages, anthropic.NewUserMessage(
-		anthropic.NewTextBlock(strings.TrimSpace(userInput)),
-	))
-
 	// Ensure messages start with user (Claude requirement)
 	if len(messages) > 0 && messages[0].Role != anthropic.MessageParamRoleUser {
 		messages = append([]anthropic.MessageParam{
@@ -486,6 +523,49 @@ func buildMessages(userInput string, history []provider.Message) []anthropic.Mes
 	return messages
 }
 
+// attachmentImageTypes are the image types Anthropic reads.
+var attachmentImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// SupportsAttachment reports whether Anthropic reads mimeType: images, and
+// PDFs and text as document blocks.
+func (c *Client) SupportsAttachment(mimeType string) bool {
+	return attachmentImageTypes[mimeType] || mimeType == "application/pdf"
+}
+
+// attachMessages adds attachments to the final user message, ahead of its
+// text as Anthropic recommends: images as image blocks, and PDFs and text
+// files as document blocks titled with the filename.
+func attachMessages(messages []anthropic.MessageParam, attachments []provider.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	var blocks []anthropic.ContentBlockParamUnion
+	for _, a := range attachments {
+		var block anthropic.ContentBlockParamUnion
+		switch {
+		case attachmentImageTypes[a.MIMEType]:
+			block = anthropic.NewImageBlockBase64(a.MIMEType, a.Base64())
+		case a.IsPDF():
+			block = anthropic.NewDocumentBlock(anthropic.Base64PDFSourceParam{Data: a.Base64()})
+			block.OfDocument.Title = anthropic.String(a.Filename)
+		case a.IsText():
+			block = anthropic.NewDocumentBlock(anthropic.PlainTextSourceParam{Data: string(a.Content)})
+			block.OfDocument.Title = anthropic.String(a.Filename)
+		default:
+			return &provider.UnsupportedAttachmentError{Provider: "anthropic", Filename: a.Filename, MIMEType: a.MIMEType}
+		}
+		blocks = append(blocks, block)
+	}
+	last := &messages[len(messages)-1]
+	last.Content = append(blocks, last.Content...)
+	return nil
+}
+
 // extractContent extracts text and thinking from the response content blocks.
 func extractContent(resp *anthropic.Message, includeThinking bool) (text, thinking string) {
 	var textParts []string
@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -165,3 +166,180 @@ func TestGenerateReplyStream_MissingAPIKey(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestRefusalBlock(t *testing.T) {
+	if refusalBlock(anthropic.StopReasonEndTurn) != nil {
+		t.Error("expected no block for end_turn")
+	}
+	block := refusalBlock(anthropic.StopReasonRefusal)
+	if block == nil || block.Stage != "response" || block.Reason == "" {
+		t.Errorf("unexpected refusal block: %+v", block)
+	}
+}
+
+func TestBuildToolContinuation(t *testing.T) {
+	state := []byte(`[{"role":"assistant","blocks":[{"type":"text","text":"Checking"},{"type":"tool_use","id":"toolu_1","name":"lookup","input":{"city":"Paris"}}]}]`)
+
+	messages, replay, err := requestMessages(provider.GenerateParams{
+		UserInput:   "Also tomorrow?",
+		ToolTurn:    &provider.ToolTurn{UserInput: "Weather?", State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "toolu_1", Output: "sunny", IsError: true}},
+	})
+	if err != nil {
+		t.Fatalf("requestMessages failed: %v", err)
+	}
+	if len(messages) != 3 || len(replay) != 2 {
+		t.Fatalf("expected user, assistant and tool result messages, got %d (replay %d)", len(messages), len(replay))
+	}
+	if messages[1].Role != anthropic.MessageParamRoleAssistant || messages[1].Content[1].OfToolUse == nil {
+		t.Errorf("expected replayed tool_use, got %+v", messages[1])
+	}
+	answer := messages[2].Content
+	if len(answer) != 2 || answer[0].OfToolResult == nil || answer[0].OfToolResult.ToolUseID != "toolu_1" {
+		t.Fatalf("expected tool_result then text, got %+v", answer)
+	}
+	if !answer[0].OfToolResult.IsError.Value {
+		t.Error("expected tool_result to be marked as an error")
+	}
+	if answer[1].OfText == nil || answer[1].OfText.Text != "Also tomorrow?" {
+		t.Errorf("expected new input after results, got %+v", answer[1])
+	}
+
+	if _, _, err := requestMessages(provider.GenerateParams{
+		ToolTurn:    &provider.ToolTurn{State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "toolu_9"}},
+	}); err == nil {
+		t.Error("expected error for unknown tool call")
+	}
+}
+
+func TestBuildTool(t *testing.T) {
+	tool := buildTool(provider.Tool{
+		Name:             "lookup",
+		Description:      "Look up a city",
+		ParametersSchema: `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"],"additionalProperties":false}`,
+	})
+	if tool.OfTool == nil || tool.OfTool.Name != "lookup" {
+		t.Fatalf("expected custom tool, got %+v", tool)
+	}
+	schema := tool.OfTool.InputSchema
+	if len(schema.Required) != 1 || schema.Required[0] != "city" {
+		t.Errorf("expected required city, got %v", schema.Required)
+	}
+	if schema.ExtraFields["additionalProperties"] != false {
+		t.Errorf("expected extra schema fields to be kept, got %v", schema.ExtraFields)
+	}
+}
+
+func TestBuildToolContinuation_ParallelCallsInCallOrder(t *testing.T) {
+	state := []byte(`[{"role":"assistant","blocks":[{"type":"tool_use","id":"toolu_a","name":"weather","input":{}},{"type":"tool_use","id":"toolu_b","name":"time","input":{}}]}]`)
+
+	messages, _, err := requestMessages(provider.GenerateParams{
+		ToolTurn: &provider.ToolTurn{UserInput: "Paris weather and Rome time?", State: state},
+		ToolResults: []provider.ToolResult{
+			{ToolCallID: "toolu_b", Output: "noon"},
+			{ToolCallID: "toolu_a", Output: "sunny"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("requestMessages failed: %v", err)
+	}
+	answer := messages[len(messages)-1].Content
+	if len(answer) != 2 || answer[0].OfToolResult.ToolUseID != "toolu_a" || answer[1].OfToolResult.ToolUseID != "toolu_b" {
+		t.Errorf("expected tool_result blocks in call order, got %+v", answer)
+	}
+
+	if _, _, err := requestMessages(provider.GenerateParams{
+		ToolTurn:    &provider.ToolTurn{State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "toolu_a"}},
+	}); err == nil {
+		t.Error("expected error when a parallel call is left unanswered")
+	}
+}
+
+func TestBuildComputerTool(t *testing.T) {
+	tool := buildComputerTool(&provider.ComputerUse{DisplayWidth: 1280, DisplayHeight: 800})
+	data, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got["type"] != computerToolType || got["name"] != "computer" || got["display_width_px"] != float64(1280) {
+		t.Errorf("unexpected computer tool definition: %s", data)
+	}
+}
+
+func TestComputerActions_SplitFromToolCalls(t *testing.T) {
+	var msg anthropic.Message
+	raw := `{"id":"msg_1","type":"message","role":"assistant","content":[
+		{"type":"tool_use","id":"toolu_fn","name":"lookup","input":{"q":"x"}},
+		{"type":"tool_use","id":"toolu_cu","name":"computer","input":{"action":"left_click","coordinate":[10,20]}}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	calls := extractToolCalls(&msg, true)
+	actions := extractComputerActions(&msg, true)
+	if len(calls) != 1 || calls[0].ID != "toolu_fn" {
+		t.Errorf("expected only the function call, got %+v", calls)
+	}
+	if len(actions) != 1 || actions[0].ID != "toolu_cu" || actions[0].Action != "left_click" {
+		t.Errorf("expected the computer action, got %+v", actions)
+	}
+	if calls := extractToolCalls(&msg, false); len(calls) != 2 {
+		t.Errorf("expected both calls as functions without computer use, got %d", len(calls))
+	}
+}
+
+func TestBuildToolContinuation_Screenshot(t *testing.T) {
+	state := []byte(`[{"role":"assistant","blocks":[{"type":"tool_use","id":"toolu_cu","name":"computer","input":{"action":"screenshot"}}]}]`)
+	messages, _, err := requestMessages(provider.GenerateParams{
+		ToolTurn:    &provider.ToolTurn{UserInput: "Open the page", State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "toolu_cu", Screenshot: []byte("png")}},
+	})
+	if err != nil {
+		t.Fatalf("requestMessages failed: %v", err)
+	}
+	result := messages[len(messages)-1].Content[0].OfToolResult
+	if result == nil || len(result.Content) != 1 || result.Content[0].OfImage == nil {
+		t.Fatalf("expected image tool_result, got %+v", result)
+	}
+	source := result.Content[0].OfImage.Source.OfBase64
+	if source.Data != "cG5n" || source.MediaType != "image/png" {
+		t.Errorf("unexpected screenshot source: %+v", source)
+	}
+}
+
+func TestAttachMessages(t *testing.T) {
+	messages, _, err := requestMessages(provider.GenerateParams{
+		UserInput: "Review these",
+		Attachments: []provider.Attachment{
+			{Filename: "diagram.png", MIMEType: "image/png", Content: []byte("png")},
+			{Filename: "spec.pdf", MIMEType: "application/pdf", Content: []byte("pdf")},
+			{Filename: "notes.txt", MIMEType: "text/plain", Content: []byte("notes")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("requestMessages failed: %v", err)
+	}
+	content := messages[len(messages)-1].Content
+	if len(content) != 4 {
+		t.Fatalf("expected 3 attachments and the text, got %d blocks", len(content))
+	}
+	if content[0].OfImage == nil {
+		t.Errorf("expected image block, got %+v", content[0])
+	}
+	if doc := content[1].OfDocument; doc == nil || doc.Source.OfBase64 == nil || doc.Title.Value != "spec.pdf" {
+		t.Errorf("expected PDF document block, got %+v", content[1])
+	}
+	if doc := content[2].OfDocument; doc == nil || doc.Source.OfText == nil || doc.Source.OfText.Data != "notes" {
+		t.Errorf("expected text document block, got %+v", content[2])
+	}
+	if content[3].OfText == nil || content[3].OfText.Text != "Review these" {
+		t.Errorf("expected the user text last, got %+v", content[3])
+	}
+}
@@ -0,0 +1,101 @@
+package anthropic
+
+import (
+	"encoding/json"
+
+	anthropic "github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/anthropics/anthropic-sdk-go/packages/param"
+
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+// Computer use is a beta tool, enabled per request by header.
+const (
+	computerToolType   = "computer_20250124"
+	computerBetaHeader = "computer-use-2025-01-24"
+)
+
+// defaultScreenshotMediaType is assumed when a screenshot has no MIME type.
+const defaultScreenshotMediaType = "image/png"
+
+// SupportsComputerUse returns true as Anthropic supports the computer tool.
+func (c *Client) SupportsComputerUse() bool {
+	return true
+}
+
+// computerUseOptions returns the request options that enable the beta tool.
+func computerUseOptions(cu *provider.ComputerUse) []option.RequestOption {
+	if cu == nil {
+		return nil
+	}
+	return []option.RequestOption{option.WithHeaderAdd("anthropic-beta", computerBetaHeader)}
+}
+
+// buildComputerTool defines the computer tool. The SDK only types it in the
+// beta API, so its definition overrides a custom tool's JSON.
+func buildComputerTool(cu *provider.ComputerUse) anthropic.ToolUnionParam {
+	tool := param.Override[anthropic.ToolParam](map[string]any{
+		"type":              computerToolType,
+		"name":              provider.ComputerToolName,
+		"display_width_px":  cu.DisplayWidth,
+		"display_height_px": cu.DisplayHeight,
+	})
+	return anthropic.ToolUnionParam{OfTool: &tool}
+}
+
+// isComputerCall reports whether a tool_use block is a computer action.
+func isComputerCall(block anthropic.ContentBlockUnion, computerUse bool) bool {
+	return computerUse && block.Type == "tool_use" && block.Name == provider.ComputerToolName
+}
+
+// extractComputerActions extracts computer tool_use blocks from the response.
+func extractComputerActions(resp *anthropic.Message, computerUse bool) []provider.ComputerAction {
+	if resp == nil {
+		return nil
+	}
+	var actions []provider.ComputerAction
+	for _, block := range resp.Content {
+		if !isComputerCall(block, computerUse) {
+			continue
+		}
+		var input struct {
+			Action string `json:"action"`
+		}
+		_ = json.Unmarshal(block.Input, &input)
+		actions = append(actions, provider.ComputerAction{
+			ID:     block.ID,
+			Action: input.Action,
+			Input:  string(block.Input),
+		})
+	}
+	return actions
+}
+
+// screenshotResult builds a tool_result block carrying a screenshot.
+func screenshotResult(block replayBlock) anthropic.ContentBlockParamUnion {
+	mediaType := block.MediaType
+	if mediaType == "" {
+		mediaType = defaultScreenshotMediaType
+	}
+	result := anthropic.ToolResultBlockParam{
+		ToolUseID: block.ToolUseID,
+		IsError:   anthropic.Bool(block.IsError),
+		Content: []anthropic.ToolResultBlockParamContentUnion{{
+			OfImage: &anthropic.ImageBlockParam{
+				Source: anthropic.ImageBlockParamSourceUnion{
+					OfBase64: &anthropic.Base64ImageSourceParam{
+						Data:      block.Image,
+						MediaType: anthropic.Base64ImageSourceMediaType(mediaType),
+					},
+				},
+			},
+		}},
+	}
+	if block.Text != "" {
+		result.Content = append(result.Content, anthropic.ToolResultBlockParamContentUnion{
+			OfText: &anthropic.TextBlockParam{Text: block.Text},
+		})
+	}
+	return anthropic.ContentBlockParamUnion{OfToolResult: &result}
+}
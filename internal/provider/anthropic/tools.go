@@ -0,0 +1,231 @@
+package anthropic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	anthropic "github.com/anthropics/anthropic-sdk-go"
+
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+// buildTool converts a function tool to an Anthropic custom tool.
+func buildTool(tool provider.Tool) anthropic.ToolUnionParam {
+	var schema map[string]any
+	if tool.ParametersSchema != "" {
+		if err := json.Unmarshal([]byte(tool.ParametersSchema), &schema); err != nil {
+			slog.Warn("invalid tool parameters schema", "tool", tool.Name, "error", err)
+			schema = nil
+		}
+	}
+
+	inputSchema := anthropic.ToolInputSchemaParam{Properties: map[string]any{}}
+	for key, value := range schema {
+		switch key {
+		case "type":
+			// Always "object"
+		case "properties":
+			inputSchema.Properties = value
+		case "required":
+			if list, ok := value.([]any); ok {
+				for _, name := range list {
+					if s, ok := name.(string); ok {
+						inputSchema.Required = append(inputSchema.Required, s)
+					}
+				}
+			}
+		default:
+			if inputSchema.ExtraFields == nil {
+				inputSchema.ExtraFields = make(map[string]any)
+			}
+			inputSchema.ExtraFields[key] = value
+		}
+	}
+
+	param := anthropic.ToolUnionParamOfTool(inputSchema, tool.Name)
+	if tool.Description != "" {
+		param.OfTool.Description = anthropic.String(tool.Description)
+	}
+	return param
+}
+
+// extractToolCalls extracts function tool_use blocks from the response,
+// leaving out computer actions when computer use is enabled.
+func extractToolCalls(resp *anthropic.Message, computerUse bool) []provider.ToolCall {
+	if resp == nil {
+		return nil
+	}
+	var toolCalls []provider.ToolCall
+	for _, block := range resp.Content {
+		if block.Type != "tool_use" || isComputerCall(block, computerUse) {
+			continue
+		}
+		args := string(block.Input)
+		if args == "" {
+			args = "{}"
+		}
+		toolCalls = append(toolCalls, provider.ToolCall{
+			ID:        block.ID,
+			Name:      block.Name,
+			Arguments: args,
+		})
+	}
+	return toolCalls
+}
+
+// replayMessage is a stored message from a tool exchange: an assistant turn
+// that requested tools or the user turn that answered it. Continuations
+// replay every exchange since the user's message, so chained tool calls keep
+// their context; thinking blocks are kept with their signatures as extended
+// thinking requires.
+type replayMessage struct {
+	Role   string        `json:"role"`
+	Blocks []replayBlock `json:"blocks"`
+}
+
+type replayBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	Thinking  string          `json:"thinking,omitempty"`
+	Signature string          `json:"signature,omitempty"`
+	Data      string          `json:"data,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+	Image     string          `json:"image,omitempty"` // Base64 screenshot answering a computer action
+	MediaType string          `json:"media_type,omitempty"`
+}
+
+// toParam converts a stored message back to a request message.
+func (m replayMessage) toParam() anthropic.MessageParam {
+	var blocks []anthropic.ContentBlockParamUnion
+	for _, block := range m.Blocks {
+		switch block.Type {
+		case "text":
+			if strings.TrimSpace(block.Text) != "" {
+				blocks = append(blocks, anthropic.NewTextBlock(block.Text))
+			}
+		case "thinking":
+			blocks = append(blocks, anthropic.NewThinkingBlock(block.Signature, block.Thinking))
+		case "redacted_thinking":
+			blocks = append(blocks, anthropic.NewRedactedThinkingBlock(block.Data))
+		case "tool_use":
+			input := block.Input
+			if len(input) == 0 {
+				input = json.RawMessage("{}")
+			}
+			blocks = append(blocks, anthropic.NewToolUseBlock(block.ID, input, block.Name))
+		case "tool_result":
+			if block.Image != "" {
+				blocks = append(blocks, screenshotResult(block))
+				continue
+			}
+			blocks = append(blocks, anthropic.NewToolResultBlock(block.ToolUseID, block.Text, block.IsError))
+		}
+	}
+	if m.Role == "assistant" {
+		return anthropic.NewAssistantMessage(blocks...)
+	}
+	return anthropic.NewUserMessage(blocks...)
+}
+
+// toolTurnState encodes the exchanges so far plus the assistant turn that
+// requested tools, for replay when the results are sent back. Nil when
+// there were no calls.
+func toolTurnState(replay []replayMessage, resp *anthropic.Message, toolCalls []provider.ToolCall) []byte {
+	if len(toolCalls) == 0 || resp == nil {
+		return nil
+	}
+	turn := replayMessage{Role: "assistant"}
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text", "tool_use", "thinking", "redacted_thinking":
+			turn.Blocks = append(turn.Blocks, replayBlock{
+				Type:      block.Type,
+				Text:      block.Text,
+				ID:        block.ID,
+				Name:      block.Name,
+				Input:     block.Input,
+				Thinking:  block.Thinking,
+				Signature: block.Signature,
+				Data:      block.Data,
+			})
+		}
+	}
+	state, err := json.Marshal(append(replay[:len(replay):len(replay)], turn))
+	if err != nil {
+		slog.Warn("failed to encode anthropic tool turn", "error", err)
+		return nil
+	}
+	return state
+}
+
+// requestMessages builds the conversation for params. When params carry
+// tool results it also returns the replayed tool exchanges.
+func requestMessages(params provider.GenerateParams) ([]anthropic.MessageParam, []replayMessage, error) {
+	if params.ToolTurn != nil {
+		return buildToolContinuation(params)
+	}
+	messages := buildMessages(params.UserInput, params.ConversationHistory)
+	if err := attachMessages(messages, params.Attachments); err != nil {
+		return nil, nil, err
+	}
+	return messages, nil, nil
+}
+
+// buildToolContinuation replays the tool exchanges since the user's message
+// and answers the last turn's tool_use blocks with the results, followed by
+// any new input.
+func buildToolContinuation(params provider.GenerateParams) ([]anthropic.MessageParam, []replayMessage, error) {
+	turn := params.ToolTurn
+	var replay []replayMessage
+	if err := json.Unmarshal(turn.State, &replay); err != nil {
+		return nil, nil, fmt.Errorf("invalid anthropic tool turn state: %w", err)
+	}
+	if len(replay) == 0 {
+		return nil, nil, errors.New("invalid anthropic tool turn state: no turns")
+	}
+
+	// Every tool_use must be answered, in the order the calls were made
+	var calls []provider.ToolCall
+	for _, block := range replay[len(replay)-1].Blocks {
+		if block.Type == "tool_use" {
+			calls = append(calls, provider.ToolCall{ID: block.ID})
+		}
+	}
+	results, err := provider.OrderToolResults(calls, params.ToolResults)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	answer := replayMessage{Role: "user"}
+	for _, result := range results {
+		block := replayBlock{
+			Type:      "tool_result",
+			ToolUseID: result.ToolCallID,
+			Text:      result.Output,
+			IsError:   result.IsError,
+		}
+		if len(result.Screenshot) > 0 {
+			block.Image = base64.StdEncoding.EncodeToString(result.Screenshot)
+			block.MediaType = result.ScreenshotMIMEType
+		}
+		answer.Blocks = append(answer.Blocks, block)
+	}
+	if text := strings.TrimSpace(params.UserInput); text != "" {
+		answer.Blocks = append(answer.Blocks, replayBlock{Type: "text", Text: text})
+	}
+	replay = append(replay, answer)
+
+	messages := buildMessages(turn.UserInput, params.ConversationHistory)
+	for _, msg := range replay {
+		messages = append(messages, msg.toParam())
+	}
+	return messages, replay, nil
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+// Attachment is a file sent with the user's message. Providers send it in
+// their native form; text files are sent as part of the message instead
+// (see InlineTextAttachments).
+type Attachment struct {
+	Filename string
+	MIMEType string
+	Content  []byte
+}
+
+// IsText reports whether the attachment is a text file.
+func (a Attachment) IsText() bool {
+	return validation.IsTextType(a.MIMEType)
+}
+
+// IsImage reports whether the attachment is an image.
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.MIMEType, "image/")
+}
+
+// IsPDF reports whether the attachment is a PDF document.
+func (a Attachment) IsPDF() bool {
+	return a.MIMEType == "application/pdf"
+}
+
+// Base64 returns the attachment's content base64 encoded.
+func (a Attachment) Base64() string {
+	return base64.StdEncoding.EncodeToString(a.Content)
+}
+
+// DataURL returns the attachment's content as a data: URL.
+func (a Attachment) DataURL() string {
+	return "data:" + a.MIMEType + ";base64," + a.Base64()
+}
+
+// AttachmentSupporter is implemented by providers that accept attachments.
+// Providers that do not implement it accept text attachments only.
+type AttachmentSupporter interface {
+	SupportsAttachment(mimeType string) bool
+}
+
+// SupportsAttachment reports whether p can be sent an attachment of
+// mimeType. Every provider can be sent text.
+func SupportsAttachment(p Provider, mimeType string) bool {
+	if validation.IsTextType(mimeType) {
+		return true
+	}
+	if as, ok := p.(AttachmentSupporter); ok {
+		return as.SupportsAttachment(mimeType)
+	}
+	return false
+}
+
+// ImageInputSupporter is implemented by providers that accept images by
+// URL or file ID. Providers that do not implement it accept none.
+type ImageInputSupporter interface {
+	SupportsImageInput(img InlineImage) bool
+}
+
+// SupportsImageInput reports whether p can be sent img.
+func SupportsImageInput(p Provider, img InlineImage) bool {
+	if is, ok := p.(ImageInputSupporter); ok {
+		return is.SupportsImageInput(img)
+	}
+	return false
+}
+
+// UnsupportedAttachmentError reports an attachment a provider cannot read.
+type UnsupportedAttachmentError struct {
+	Provider string
+	Filename string
+	MIMEType string
+}
+
+func (e *UnsupportedAttachmentError) Error() string {
+	return fmt.Sprintf("%s does not support %s attachments (%s)", e.Provider, e.MIMEType, e.Filename)
+}
+
+// InlineTextAttachments appends the text attachments to userInput, each
+// wrapped in an <attachment> element naming the file, and returns the
+// attachments left for the provider's native file inputs.
+func InlineTextAttachments(userInput string, attachments []Attachment) (string, []Attachment) {
+	var b strings.Builder
+	b.WriteString(userInput)
+	var rest []Attachment
+	for _, a := range attachments {
+		if !a.IsText() {
+			rest = append(rest, a)
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n<attachment filename=%q>\n%s\n</attachment>", a.Filename, strings.TrimRight(string(a.Content), "\n"))
+	}
+	return b.String(), rest
+}
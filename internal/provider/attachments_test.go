@@ -0,0 +1,47 @@
+package provider
+
+import "testing"
+
+func TestInlineTextAttachments(t *testing.T) {
+	attachments := []Attachment{
+		{Filename: "notes.md", MIMEType: "text/markdown", Content: []byte("# Notes\n")},
+		{Filename: "chart.png", MIMEType: "image/png", Content: []byte("png")},
+		{Filename: "data.json", MIMEType: "application/json", Content: []byte(`{"a":1}`)},
+	}
+
+	input, rest := InlineTextAttachments("Summarize these", attachments)
+	want := "Summarize these\n\n<attachment filename=\"notes.md\">\n# Notes\n</attachment>" +
+		"\n\n<attachment filename=\"data.json\">\n{\"a\":1}\n</attachment>"
+	if input != want {
+		t.Errorf("unexpected input:\n%s", input)
+	}
+	if len(rest) != 1 || rest[0].Filename != "chart.png" {
+		t.Errorf("expected the image left over, got %+v", rest)
+	}
+}
+
+// attachmentProvider accepts images.
+type attachmentProvider struct{ Provider }
+
+func (attachmentProvider) SupportsAttachment(mimeType string) bool { return mimeType == "image/png" }
+
+func TestSupportsAttachment(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        Provider
+		mimeType string
+		want     bool
+	}{
+		{"text everywhere", nil, "text/csv", true},
+		{"no supporter", nil, "image/png", false},
+		{"supported", attachmentProvider{}, "image/png", true},
+		{"unsupported", attachmentProvider{}, "application/pdf", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SupportsAttachment(tt.p, tt.mimeType); got != tt.want {
+				t.Errorf("SupportsAttachment(%q) = %v, want %v", tt.mimeType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package provider
+
+import "strings"
+
+// CodeExecutionSupporter is implemented by providers that can run code
+// (OpenAI code interpreter, Gemini code execution).
+type CodeExecutionSupporter interface {
+	SupportsCodeExecution() bool
+}
+
+// StructuredOutputSupporter is implemented by providers that support
+// structured output mode (intent, entities, topics, facts).
+type StructuredOutputSupporter interface {
+	SupportsStructuredOutput() bool
+}
+
+// ComputerUseSupporter is implemented by providers with a native
+// computer-use tool (OpenAI, Anthropic).
+type ComputerUseSupporter interface {
+	SupportsComputerUse() bool
+}
+
+// DefaultModeler is implemented by providers that expose the model used
+// when neither tenant config nor request overrides set one.
+type DefaultModeler interface {
+	DefaultModel() string
+}
+
+// Capabilities summarizes what a provider/model combination supports.
+// Used by capability discovery so clients don't hardcode feature assumptions.
+type Capabilities struct {
+	FileSearch       bool
+	WebSearch        bool
+	CodeExecution    bool
+	Streaming        bool
+	NativeContinuity bool
+	StructuredOutput bool
+	ComputerUse      bool
+
+	// Model is the model requests will use by default
+	Model string
+
+	// MaxContextTokens is the model's context window (0 if unknown)
+	MaxContextTokens int
+}
+
+// CapabilitiesOf describes the capabilities of p for the given model.
+// If model is empty, the provider's default model is used when known.
+func CapabilitiesOf(p Provider, model string) Capabilities {
+	caps := Capabilities{
+		FileSearch:       p.SupportsFileSearch(),
+		WebSearch:        p.SupportsWebSearch(),
+		Streaming:        p.SupportsStreaming(),
+		NativeContinuity: p.SupportsNativeContinuity(),
+	}
+	if ce, ok := p.(CodeExecutionSupporter); ok {
+		caps.CodeExecution = ce.SupportsCodeExecution()
+	}
+	if so, ok := p.(StructuredOutputSupporter); ok {
+		caps.StructuredOutput = so.SupportsStructuredOutput()
+	}
+	if cu, ok := p.(ComputerUseSupporter); ok {
+		caps.ComputerUse = cu.SupportsComputerUse()
+	}
+	if model == "" {
+		if dm, ok := p.(DefaultModeler); ok {
+			model = dm.DefaultModel()
+		}
+	}
+	caps.Model = model
+	caps.MaxContextTokens = ContextWindow(model)
+	return caps
+}
+
+// contextWindows maps model name prefixes to context window sizes in tokens.
+// More specific prefixes must come before shorter ones.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4.1", 1047576},
+	{"gpt-5", 400000},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"o1", 200000},
+	{"o3", 200000},
+	{"o4", 200000},
+	{"gemini-1.5-pro", 2097152},
+	{"gemini-", 1048576},
+	{"claude-", 200000},
+}
+
+// ContextWindow returns the context window size in tokens for a model,
+// or 0 if the model is unknown.
+func ContextWindow(model string) int {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if model == "" {
+		return 0
+	}
+	for _, cw := range contextWindows {
+		if strings.HasPrefix(model, cw.prefix) {
+			return cw.tokens
+		}
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+type stubProvider struct {
+	structured bool
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+func (s *stubProvider) GenerateReply(ctx context.Context, params GenerateParams) (GenerateResult, error) {
+	return GenerateResult{}, nil
+}
+func (s *stubProvider) GenerateReplyStream(ctx context.Context, params GenerateParams) (<-chan StreamChunk, error) {
+	return nil, nil
+}
+func (s *stubProvider) SupportsFileSearch() bool       { return true }
+func (s *stubProvider) SupportsWebSearch() bool        { return false }
+func (s *stubProvider) SupportsNativeContinuity() bool { return false }
+func (s *stubProvider) SupportsStreaming() bool        { return true }
+func (s *stubProvider) SupportsStructuredOutput() bool { return s.structured }
+func (s *stubProvider) DefaultModel() string           { return "gemini-3-pro-preview" }
+
+func TestCapabilitiesOf(t *testing.T) {
+	caps := CapabilitiesOf(&stubProvider{structured: true}, "")
+
+	if !caps.FileSearch || caps.WebSearch || !caps.Streaming || caps.NativeContinuity {
+		t.Errorf("core capabilities not taken from Provider interface: %+v", caps)
+	}
+	if !caps.StructuredOutput {
+		t.Error("expected StructuredOutput from optional interface")
+	}
+	if caps.CodeExecution {
+		t.Error("expected CodeExecution false when interface not implemented")
+	}
+	if caps.ComputerUse {
+		t.Error("expected ComputerUse false when interface not implemented")
+	}
+	if caps.Model != "gemini-3-pro-preview" {
+		t.Errorf("Model = %q, want provider default", caps.Model)
+	}
+	if caps.MaxContextTokens != 1048576 {
+		t.Errorf("MaxContextTokens = %d, want 1048576", caps.MaxContextTokens)
+	}
+}
+
+func TestCapabilitiesOf_ExplicitModel(t *testing.T) {
+	caps := CapabilitiesOf(&stubProvider{}, "gemini-1.5-pro-002")
+	if caps.Model != "gemini-1.5-pro-002" {
+		t.Errorf("Model = %q, want explicit model", caps.Model)
+	}
+	if caps.MaxContextTokens != 2097152 {
+		t.Errorf("MaxContextTokens = %d, want 2097152", caps.MaxContextTokens)
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{"gpt-4o", 128000},
+		{"gpt-4o-mini", 128000},
+		{"gpt-4.1-mini", 1047576},
+		{"gpt-5.2", 400000},
+		{"o3-mini", 200000},
+		{"claude-sonnet-4-20250514", 200000},
+		{"Gemini-2.5-Flash", 1048576},
+		{"unknown-model", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := ContextWindow(tt.model); got != tt.want {
+			t.Errorf("ContextWindow(%q) = %d, want %d", tt.model, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/httpcapture"
 	"github.com/ai8future/airborne/internal/provider"
 	"github.com/ai8future/airborne/internal/retry"
@@ -121,6 +122,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 
 	// Create capturing transport for debug JSON (always enabled for admin dashboard)
 	capture := httpcapture.New()
+	capture.Base = egress.Transport(cfg.Proxy)
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
 		option.WithBaseURL(baseURL),
@@ -130,7 +132,11 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	client := openai.NewClient(opts...)
 
 	// Build messages
-	messages := buildMessages(params.Instructions, params.UserInput, params.ConversationHistory)
+	userInput, err := c.inlineAttachments(params)
+	if err != nil {
+		return provider.GenerateResult{}, err
+	}
+	messages := buildMessages(params.Instructions, userInput, params.ConversationHistory)
 
 	// Build request
 	reqParams := openai.ChatCompletionNewParams{
@@ -150,7 +156,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	}
 
 	if c.debug {
-		slog.Debug(fmt.Sprintf("%s request", c.config.Name),
+		slog.DebugContext(ctx, fmt.Sprintf("%s request", c.config.Name),
 			"model", model,
 			"base_url", baseURL,
 			"request_id", params.RequestID,
@@ -160,7 +166,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	// Execute with retry
 	var lastErr error
 	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
-		slog.Info(fmt.Sprintf("%s request", c.config.Name),
+		slog.InfoContext(ctx, fmt.Sprintf("%s request", c.config.Name),
 			"attempt", attempt,
 			"model", model,
 			"request_id", params.RequestID,
@@ -174,7 +180,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			// Check if parent context is still valid
 			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
 				lastErr = fmt.Errorf("%s request timeout: %w", c.config.Name, err)
-				slog.Warn(fmt.Sprintf("%s timeout, retrying", c.config.Name), "attempt", attempt)
+				slog.WarnContext(ctx, fmt.Sprintf("%s timeout, retrying", c.config.Name), "attempt", attempt)
 				if attempt < retry.MaxAttempts {
 					retry.SleepWithBackoff(ctx, attempt)
 					continue
@@ -187,7 +193,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 				return provider.GenerateResult{}, lastErr
 			}
 
-			slog.Warn(fmt.Sprintf("%s retryable error", c.config.Name), "attempt", attempt, "error", err)
+			slog.WarnContext(ctx, fmt.Sprintf("%s retryable error", c.config.Name), "attempt", attempt, "error", err)
 			if attempt < retry.MaxAttempts {
 				retry.SleepWithBackoff(ctx, attempt)
 				continue
@@ -207,7 +213,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 
 		usage := extractUsage(resp)
 
-		slog.Info(fmt.Sprintf("%s request completed", c.config.Name),
+		slog.InfoContext(ctx, fmt.Sprintf("%s request completed", c.config.Name),
 			"model", model,
 			"tokens_in", usage.InputTokens,
 			"tokens_out", usage.OutputTokens,
@@ -223,6 +229,8 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			Text:         text,
 			Usage:        usage,
 			Model:        resp.Model,
+			SystemPrompt: params.Instructions,
+			UserPrompt:   strings.TrimSpace(params.UserInput),
 			RequestJSON:  reqJSON,
 			ResponseJSON: respJSON,
 		}, nil
@@ -259,11 +267,17 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
 		option.WithBaseURL(baseURL),
+		option.WithHTTPClient(egress.Client(cfg.Proxy, 0)),
 	}
 	client := openai.NewClient(opts...)
 
 	// Build messages
-	messages := buildMessages(params.Instructions, params.UserInput, params.ConversationHistory)
+	userInput, err := c.inlineAttachments(params)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	messages := buildMessages(params.Instructions, userInput, params.ConversationHistory)
 
 	// Build request
 	reqParams := openai.ChatCompletionNewParams{
@@ -282,7 +296,7 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 	}
 
 	if c.debug {
-		slog.Debug(fmt.Sprintf("%s streaming request", c.config.Name),
+		slog.DebugContext(ctx, fmt.Sprintf("%s streaming request", c.config.Name),
 			"model", model,
 			"base_url", baseURL,
 			"request_id", params.RequestID,
@@ -333,40 +347,45 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		}
 
 		ch <- provider.StreamChunk{
-			Type:  provider.ChunkTypeComplete,
-			Model: model,
-			Usage: usage,
+			Type:         provider.ChunkTypeComplete,
+			Model:        model,
+			Usage:        usage,
+			SystemPrompt: params.Instructions,
+			UserPrompt:   strings.TrimSpace(params.UserInput),
 		}
 	}()
 
 	return ch, nil
 }
 
-// buildMessages constructs the message array for chat completion.
+// inlineAttachments returns the user input with the text attachments
+// appended. Compatible APIs only take text, so other attachments are
+// rejected.
+func (c *Client) inlineAttachments(params provider.GenerateParams) (string, error) {
+	userInput, rest := provider.InlineTextAttachments(params.UserInput, params.Attachments)
+	if len(rest) > 0 {
+		return "", &provider.UnsupportedAttachmentError{Provider: c.config.Name, Filename: rest[0].Filename, MIMEType: rest[0].MIMEType}
+	}
+	return userInput, nil
+}
+
+// buildMessages adapts the conversation to a chat completion message array.
 func buildMessages(instructions, userInput string, history []provider.Message) []openai.ChatCompletionMessageParamUnion {
-	var messages []openai.ChatCompletionMessageParamUnion
+	turns := provider.BuildConversation(userInput, history)
+	messages := make([]openai.ChatCompletionMessageParamUnion, 0, len(turns)+1)
 
 	// Add system instruction
 	if instructions != "" {
 		messages = append(messages, openai.SystemMessage(instructions))
 	}
 
-	// Add conversation history
-	for _, msg := range history {
-		content := strings.TrimSpace(msg.Content)
-		if content == "" {
-			continue
-		}
-		if msg.Role == "assistant" {
-			messages = append(messages, openai.AssistantMessage(content))
+	for _, turn := range turns {
+		if turn.Role == provider.RoleAssistant {
+			messages = append(messages, openai.AssistantMessage(turn.Text))
 		} else {
-			messages = append(messages, openai.UserMessage(content))
+			messages = append(messages, openai.UserMessage(turn.Text))
 		}
 	}
-
-	// Add current user input
-	messages = append(messages, openai.UserMessage(strings.TrimSpace(userInput)))
-
 	return messages
 }
 
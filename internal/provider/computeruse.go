@@ -0,0 +1,59 @@
+package provider
+
+// ComputerToolName is the tool name computer-use actions are requested under.
+// Function tools cannot use it while computer use is enabled.
+const ComputerToolName = "computer"
+
+// Computer-use environments (OpenAI); Anthropic ignores the environment.
+const (
+	ComputerEnvironmentBrowser = "browser"
+	ComputerEnvironmentMac     = "mac"
+	ComputerEnvironmentWindows = "windows"
+	ComputerEnvironmentUbuntu  = "ubuntu"
+)
+
+// ComputerUse configures the provider's native computer-use tool.
+type ComputerUse struct {
+	DisplayWidth  int
+	DisplayHeight int
+
+	// Environment is one of the ComputerEnvironment constants
+	Environment string
+}
+
+// ComputerAction is an action the model wants performed on the computer.
+// The executor answers it with a ToolResult carrying a screenshot.
+type ComputerAction struct {
+	// ID is the call ID the result must reference
+	ID string
+
+	// Action is the action type, such as "click", "type" or "screenshot"
+	Action string
+
+	// Input holds the provider's action parameters as JSON
+	Input string
+
+	// PendingSafetyChecks must be acknowledged to continue (OpenAI)
+	PendingSafetyChecks []ComputerSafetyCheck
+}
+
+// ComputerSafetyCheck is a provider safety check raised for a computer action.
+type ComputerSafetyCheck struct {
+	ID      string
+	Code    string
+	Message string
+}
+
+// PendingCalls returns every call the next tool results must answer:
+// function tool calls followed by computer actions.
+func PendingCalls(toolCalls []ToolCall, actions []ComputerAction) []ToolCall {
+	if len(actions) == 0 {
+		return toolCalls
+	}
+	calls := make([]ToolCall, 0, len(toolCalls)+len(actions))
+	calls = append(calls, toolCalls...)
+	for _, action := range actions {
+		calls = append(calls, ToolCall{ID: action.ID, Name: ComputerToolName, Arguments: action.Input})
+	}
+	return calls
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// MaxHistoryChars limits the conversation history sent to a provider to
+// prevent context overflow. The newest messages are kept.
+const MaxHistoryChars = 50000
+
+// Conversation roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Turn is one message in a provider-neutral conversation.
+type Turn struct {
+	Role string // RoleUser or RoleAssistant
+	Text string
+}
+
+// BuildConversation converts the request history and new user input into
+// the turns every provider sends as its native multi-turn message array.
+// Empty messages are dropped, any role other than assistant is sent as
+// user, the oldest history is dropped beyond MaxHistoryChars, and the
+// trimmed user input is always the final turn.
+func BuildConversation(userInput string, history []Message) []Turn {
+	var turns []Turn
+	for _, msg := range history {
+		text := strings.TrimSpace(msg.Content)
+		if text == "" {
+			continue
+		}
+		role := RoleUser
+		if msg.Role == RoleAssistant {
+			role = RoleAssistant
+		}
+		turns = append(turns, Turn{Role: role, Text: text})
+	}
+
+	// Keep the newest history that fits the budget
+	start, total := 0, 0
+	for i := len(turns) - 1; i >= 0; i-- {
+		if total+len(turns[i].Text) > MaxHistoryChars {
+			start = i + 1
+			slog.Debug("truncating conversation history",
+				"kept_messages", len(turns)-start,
+				"dropped_messages", start,
+			)
+			break
+		}
+		total += len(turns[i].Text)
+	}
+	turns = turns[start:]
+
+	return append(turns, Turn{Role: RoleUser, Text: strings.TrimSpace(userInput)})
+}
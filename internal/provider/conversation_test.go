@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildConversation(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: "  Hello  "},
+		{Role: "assistant", Content: "Hi"},
+		{Role: "assistant", Content: "   "},
+		{Role: "system", Content: "note"},
+	}
+
+	turns := BuildConversation("  Next  ", history)
+	want := []Turn{
+		{Role: RoleUser, Text: "Hello"},
+		{Role: RoleAssistant, Text: "Hi"},
+		{Role: RoleUser, Text: "note"},
+		{Role: RoleUser, Text: "Next"},
+	}
+	if len(turns) != len(want) {
+		t.Fatalf("expected %d turns, got %d: %+v", len(want), len(turns), turns)
+	}
+	for i := range want {
+		if turns[i] != want[i] {
+			t.Errorf("turn %d = %+v, want %+v", i, turns[i], want[i])
+		}
+	}
+}
+
+func TestBuildConversation_TruncatesOldestHistory(t *testing.T) {
+	long := strings.Repeat("x", MaxHistoryChars/2+1)
+	history := []Message{
+		{Role: "user", Content: "oldest " + long},
+		{Role: "assistant", Content: "older " + long},
+		{Role: "user", Content: "newest"},
+	}
+
+	turns := BuildConversation("now", history)
+	if len(turns) != 3 {
+		t.Fatalf("expected 3 turns, got %d", len(turns))
+	}
+	if !strings.HasPrefix(turns[0].Text, "older") || turns[1].Text != "newest" || turns[2].Text != "now" {
+		t.Errorf("expected the oldest message dropped, got %q, %q, %q", turns[0].Text[:5], turns[1].Text, turns[2].Text)
+	}
+}
@@ -2,13 +2,16 @@
 package gemini
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"mime"
 	"sort"
 	"strings"
+	"time"
 
 	"google.golang.org/genai"
 
@@ -17,10 +20,7 @@ import (
 	"github.com/ai8future/airborne/internal/retry"
 )
 
-const (
-	// maxHistoryChars limits conversation history to prevent context overflow
-	maxHistoryChars = 50000
-)
+const defaultModel = "gemini-3-pro-preview"
 
 // Client implements the provider.Provider interface using Google's Gemini API.
 type Client struct {
@@ -73,6 +73,21 @@ func (c *Client) SupportsStreaming() bool {
 	return true
 }
 
+// SupportsCodeExecution returns true as Gemini supports the code execution tool.
+func (c *Client) SupportsCodeExecution() bool {
+	return true
+}
+
+// SupportsStructuredOutput returns true as Gemini supports JSON schema output.
+func (c *Client) SupportsStructuredOutput() bool {
+	return true
+}
+
+// DefaultModel returns the model used when none is configured.
+func (c *Client) DefaultModel() string {
+	return defaultModel
+}
+
 // GenerateReply implements provider.Provider using Google's Gemini API.
 func (c *Client) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
 	// Ensure request has a timeout
@@ -85,10 +100,10 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		return provider.GenerateResult{}, errors.New("Gemini API key is required")
 	}
 
-	model := provider.SelectModel(cfg.Model, "gemini-3-pro-preview", params.OverrideModel)
+	model := provider.SelectModel(cfg.Model, defaultModel, params.OverrideModel)
 
 	// Create captured client config with validation
-	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL)
+	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL, cfg.Proxy)
 	if err != nil {
 		return provider.GenerateResult{}, fmt.Errorf("client setup: %w", err)
 	}
@@ -112,18 +127,16 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	capture := httpCfg.Capture
 
 	// Build conversation content with inline images
-	contents := buildContents(params.UserInput, params.ConversationHistory, params.InlineImages)
+	contents, replay, err := requestContents(params)
+	if err != nil {
+		return provider.GenerateResult{}, err
+	}
+	if err := attachContents(ctx, client, contents, params.Attachments); err != nil {
+		return provider.GenerateResult{}, err
+	}
 
 	// Build system instruction with file ID mappings
-	systemInstruction := params.Instructions
-	if len(params.FileIDToFilename) > 0 {
-		var mappings []string
-		for id, name := range params.FileIDToFilename {
-			mappings = append(mappings, fmt.Sprintf("- %s: %s", id, name))
-		}
-		sort.Strings(mappings)
-		systemInstruction += "\n\nThe following files are attached. When referencing them, use the original filename:\n" + strings.Join(mappings, "\n")
-	}
+	systemInstruction := buildSystemInstruction(params.Instructions, params.FileIDToFilename)
 
 	// Build generation config
 	generateConfig := &genai.GenerateContentConfig{
@@ -153,8 +166,11 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		generateConfig.MaxOutputTokens = 32000
 	}
 
-	// Configure safety settings
-	if threshold := cfg.ExtraOptions["safety_threshold"]; threshold != "" {
+	// Configure safety settings (typed settings take precedence over the
+	// legacy safety_threshold option)
+	if cfg.Safety != nil && !cfg.Safety.IsZero() {
+		generateConfig.SafetySettings = typedSafetySettings(*cfg.Safety)
+	} else if threshold := cfg.ExtraOptions["safety_threshold"]; threshold != "" {
 		generateConfig.SafetySettings = buildSafetySettings(threshold)
 	}
 
@@ -183,7 +199,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			if thinkingBudgetStr != "" {
 				var budget int
 				if _, err := fmt.Sscanf(thinkingBudgetStr, "%d", &budget); err != nil {
-					slog.Warn("invalid thinking_budget value", "value", thinkingBudgetStr, "error", err)
+					slog.WarnContext(ctx, "invalid thinking_budget value", "value", thinkingBudgetStr, "error", err)
 					budget = 0
 				}
 				if budget > 0 {
@@ -237,7 +253,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	}
 
 	if c.debug {
-		slog.Debug("gemini request",
+		slog.DebugContext(ctx, "gemini request",
 			"model", model,
 			"file_store_id", params.FileStoreID,
 			"web_search", params.EnableWebSearch && !hasFileSearch,
@@ -249,7 +265,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	// Execute with retry
 	var lastErr error
 	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
-		slog.Info("gemini request",
+		slog.InfoContext(ctx, "gemini request",
 			"attempt", attempt,
 			"model", model,
 			"request_id", params.RequestID,
@@ -263,7 +279,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			// Check if parent context is still valid
 			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
 				lastErr = fmt.Errorf("gemini request timeout: %w", err)
-				slog.Warn("gemini timeout, retrying", "attempt", attempt)
+				slog.WarnContext(ctx, "gemini timeout, retrying", "attempt", attempt)
 				if attempt < retry.MaxAttempts {
 					retry.SleepWithBackoff(ctx, attempt)
 					continue
@@ -276,7 +292,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 				return provider.GenerateResult{}, lastErr
 			}
 
-			slog.Warn("gemini retryable error", "attempt", attempt, "error", err)
+			slog.WarnContext(ctx, "gemini retryable error", "attempt", attempt, "error", err)
 			if attempt < retry.MaxAttempts {
 				retry.SleepWithBackoff(ctx, attempt)
 				continue
@@ -294,9 +310,19 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		}
 
 		if text == "" {
-			// Check if blocked by safety filters
-			if reason := getBlockReason(resp); reason != "" {
-				return provider.GenerateResult{}, fmt.Errorf("gemini response blocked: %s", reason)
+			// Report a safety block in the result rather than as an error
+			if block := safetyBlock(resp); block != nil {
+				slog.WarnContext(ctx, "gemini response blocked",
+					"model", model,
+					"stage", block.Stage,
+					"category", block.Category,
+					"reason", block.Reason,
+				)
+				return provider.GenerateResult{
+					Model:       model,
+					Usage:       extractUsage(resp),
+					SafetyBlock: block,
+				}, nil
 			}
 			lastErr = errors.New("gemini returned empty response")
 			if attempt < retry.MaxAttempts {
@@ -311,7 +337,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		codeExecutions := extractCodeExecutionResults(resp)
 		groundingQueries := extractGroundingQueryCount(resp, model)
 
-		slog.Info("gemini request completed",
+		slog.InfoContext(ctx, "gemini request completed",
 			"model", model,
 			"tokens_in", usage.InputTokens,
 			"tokens_out", usage.OutputTokens,
@@ -324,18 +350,18 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		if capture != nil {
 			reqJSON = capture.RequestBody
 			respJSON = capture.ResponseBody
-			slog.Info("gemini: captured HTTP payloads",
+			slog.InfoContext(ctx, "gemini: captured HTTP payloads",
 				"request_json_len", len(reqJSON),
 				"response_json_len", len(respJSON),
 				"request_id", params.RequestID,
 			)
 			if len(reqJSON) == 0 {
-				slog.Warn("gemini: no request body captured - SDK may not be using custom HTTPClient",
+				slog.WarnContext(ctx, "gemini: no request body captured - SDK may not be using custom HTTPClient",
 					"request_id", params.RequestID,
 				)
 			}
 		} else {
-			slog.Warn("gemini: capture transport is nil",
+			slog.WarnContext(ctx, "gemini: capture transport is nil",
 				"request_id", params.RequestID,
 			)
 		}
@@ -347,9 +373,12 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			Model:              model,
 			ToolCalls:          toolCalls,
 			RequiresToolOutput: len(toolCalls) > 0,
+			ToolTurnState:      toolTurnState(replay, firstContent(resp), toolCalls),
 			CodeExecutions:     codeExecutions,
 			StructuredMetadata: structuredMetadata,
 			GroundingQueries:   groundingQueries,
+			SystemPrompt:       systemInstruction,
+			UserPrompt:         strings.TrimSpace(params.UserInput),
 			RequestJSON:        reqJSON,
 			ResponseJSON:       respJSON,
 		}, nil
@@ -370,10 +399,10 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		return nil, errors.New("Gemini API key is required")
 	}
 
-	model := provider.SelectModel(cfg.Model, "gemini-3-pro-preview", params.OverrideModel)
+	model := provider.SelectModel(cfg.Model, defaultModel, params.OverrideModel)
 
 	// Create captured client config with validation
-	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL)
+	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL, cfg.Proxy)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("client setup: %w", err)
@@ -399,18 +428,18 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 	capture := httpCfg.Capture
 
 	// Build conversation content with inline images
-	contents := buildContents(params.UserInput, params.ConversationHistory, params.InlineImages)
+	contents, replay, err := requestContents(params)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := attachContents(ctx, client, contents, params.Attachments); err != nil {
+		cancel()
+		return nil, err
+	}
 
 	// Build system instruction with file ID mappings
-	systemInstruction := params.Instructions
-	if len(params.FileIDToFilename) > 0 {
-		var mappings []string
-		for id, name := range params.FileIDToFilename {
-			mappings = append(mappings, fmt.Sprintf("- %s: %s", id, name))
-		}
-		sort.Strings(mappings)
-		systemInstruction += "\n\nThe following files are attached. When referencing them, use the original filename:\n" + strings.Join(mappings, "\n")
-	}
+	systemInstruction := buildSystemInstruction(params.Instructions, params.FileIDToFilename)
 
 	// Build generation config
 	generateConfig := &genai.GenerateContentConfig{
@@ -440,8 +469,11 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		generateConfig.MaxOutputTokens = 32000
 	}
 
-	// Configure safety settings
-	if threshold := cfg.ExtraOptions["safety_threshold"]; threshold != "" {
+	// Configure safety settings (typed settings take precedence over the
+	// legacy safety_threshold option)
+	if cfg.Safety != nil && !cfg.Safety.IsZero() {
+		generateConfig.SafetySettings = typedSafetySettings(*cfg.Safety)
+	} else if threshold := cfg.ExtraOptions["safety_threshold"]; threshold != "" {
 		generateConfig.SafetySettings = buildSafetySettings(threshold)
 	}
 
@@ -470,7 +502,7 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 			if thinkingBudgetStr != "" {
 				var budget int
 				if _, err := fmt.Sscanf(thinkingBudgetStr, "%d", &budget); err != nil {
-					slog.Warn("invalid thinking_budget value", "value", thinkingBudgetStr, "error", err)
+					slog.WarnContext(ctx, "invalid thinking_budget value", "value", thinkingBudgetStr, "error", err)
 					budget = 0
 				}
 				if budget > 0 {
@@ -532,9 +564,11 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 
 		var totalText strings.Builder
 		var toolCalls []provider.ToolCall
+		var callParts []*genai.Part // Function call parts, kept whole for their thought signatures
 		var codeExecutions []provider.CodeExecutionResult
 		var lastUsage *provider.Usage
 		var lastResp *genai.GenerateContentResponse // Track for grounding extraction
+		usageTracker := provider.NewUsageTracker()
 
 		// Use GenerateContentStream for streaming
 		for resp, err := range client.Models.GenerateContentStream(ctx, model, contents, generateConfig) {
@@ -574,11 +608,12 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 					if part.FunctionCall != nil {
 						argsJSON, _ := json.Marshal(part.FunctionCall.Args)
 						toolCall := provider.ToolCall{
-							ID:        part.FunctionCall.ID,
+							ID:        functionCallID(part.FunctionCall, len(toolCalls)),
 							Name:      part.FunctionCall.Name,
 							Arguments: string(argsJSON),
 						}
 						toolCalls = append(toolCalls, toolCall)
+						callParts = append(callParts, part)
 						ch <- provider.StreamChunk{
 							Type:     provider.ChunkTypeToolCall,
 							ToolCall: &toolCall,
@@ -606,6 +641,14 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 							CodeExecution: last,
 						}
 					}
+					if part.InlineData != nil && len(codeExecutions) > 0 {
+						last := &codeExecutions[len(codeExecutions)-1]
+						last.Files = append(last.Files, codeExecutionFile(part.InlineData, len(last.Files)+1))
+						ch <- provider.StreamChunk{
+							Type:          provider.ChunkTypeCodeExecution,
+							CodeExecution: last,
+						}
+					}
 				}
 			}
 
@@ -620,6 +663,10 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 					ThinkingTokens: int64(meta.ThoughtsTokenCount),
 					ToolUseTokens:  int64(meta.ToolUsePromptTokenCount),
 				}
+				// Report running usage for live token meters
+				if chunk, ok := usageTracker.Update(lastUsage, model, false); ok {
+					ch <- chunk
+				}
 			}
 		}
 
@@ -641,9 +688,9 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		// Log captured data for debugging
 		streamReqJSON := capture.RequestBody
 		if len(streamReqJSON) == 0 {
-			slog.Warn("gemini stream: no request body captured - SDK may not be using custom HTTPClient")
+			slog.WarnContext(ctx, "gemini stream: no request body captured - SDK may not be using custom HTTPClient")
 		} else {
-			slog.Info("gemini stream: captured request body",
+			slog.InfoContext(ctx, "gemini stream: captured request body",
 				"size", len(streamReqJSON),
 			)
 		}
@@ -658,8 +705,12 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 			Usage:              lastUsage,
 			ToolCalls:          toolCalls,
 			RequiresToolOutput: len(toolCalls) > 0,
+			ToolTurnState:      toolTurnState(replay, streamedContent(totalText.String(), callParts), toolCalls),
 			CodeExecutions:     codeExecutions,
 			GroundingQueries:   groundingQueries,
+			SafetyBlock:        safetyBlock(lastResp),
+			SystemPrompt:       systemInstruction,
+			UserPrompt:         strings.TrimSpace(params.UserInput),
 			RequestJSON:        streamReqJSON,
 			ResponseJSON:       respJSON,
 		}
@@ -675,50 +726,239 @@ type InlineImage struct {
 	Filename string
 }
 
-// buildContents builds conversation content from input, history, and images.
-func buildContents(userInput string, history []provider.Message, inlineImages []provider.InlineImage) []*genai.Content {
-	var contents []*genai.Content
+// requestContents builds the conversation for params. When params carry
+// tool results it also returns the replayed tool exchanges.
+func requestContents(params provider.GenerateParams) ([]*genai.Content, []*genai.Content, error) {
+	if params.ToolTurn != nil {
+		return buildToolContinuation(params)
+	}
+	userInput, _ := provider.InlineTextAttachments(params.UserInput, params.Attachments)
+	return buildContents(userInput, params.ConversationHistory, params.InlineImages), nil, nil
+}
 
-	// Add conversation history with size limit
-	totalChars := 0
-	for _, msg := range history {
-		trimmed := strings.TrimSpace(msg.Content)
-		if trimmed == "" {
-			continue
+// buildToolContinuation replays the tool exchanges since the user's message,
+// thought signatures included, and answers the last turn's function calls
+// with the tool results followed by any new user input. Replaying every
+// exchange keeps the context of chained tool calls.
+func buildToolContinuation(params provider.GenerateParams) ([]*genai.Content, []*genai.Content, error) {
+	turn := params.ToolTurn
+	var replay []*genai.Content
+	if err := json.Unmarshal(turn.State, &replay); err != nil {
+		return nil, nil, fmt.Errorf("invalid gemini tool turn state: %w", err)
+	}
+	if len(replay) == 0 {
+		return nil, nil, errors.New("invalid gemini tool turn state: no turns")
+	}
+
+	// Responses must carry the name of the call they answer and follow the
+	// order of the parallel calls
+	var calls []*genai.FunctionCall
+	var callIDs []provider.ToolCall
+	for _, part := range replay[len(replay)-1].Parts {
+		if part.FunctionCall != nil {
+			callIDs = append(callIDs, provider.ToolCall{ID: functionCallID(part.FunctionCall, len(calls))})
+			calls = append(calls, part.FunctionCall)
 		}
-		msgLen := len(trimmed)
-		if totalChars+msgLen > maxHistoryChars {
-			slog.Debug("truncating conversation history",
-				"total_chars", totalChars,
-				"max_chars", maxHistoryChars)
-			break
+	}
+	results, err := provider.OrderToolResults(callIDs, params.ToolResults)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var parts []*genai.Part
+	for i, result := range results {
+		response := map[string]any{"output": result.Output}
+		if result.IsError {
+			response = map[string]any{"error": result.Output}
 		}
-		totalChars += msgLen
+		part := genai.NewPartFromFunctionResponse(calls[i].Name, response)
+		part.FunctionResponse.ID = calls[i].ID
+		parts = append(parts, part)
+	}
+	if text := strings.TrimSpace(params.UserInput); text != "" {
+		parts = append(parts, genai.NewPartFromText(text))
+	}
+	replay = append(replay, genai.NewContentFromParts(parts, genai.RoleUser))
+
+	contents := buildContents(turn.UserInput, params.ConversationHistory, params.InlineImages)
+	return append(contents, replay...), replay, nil
+}
 
-		var role genai.Role
-		if msg.Role == "assistant" {
+// functionCallID returns the call's ID, or a positional one for models that
+// do not assign IDs. n is the call's index among the turn's function calls.
+func functionCallID(call *genai.FunctionCall, n int) string {
+	if call.ID != "" {
+		return call.ID
+	}
+	return fmt.Sprintf("call_%d", n)
+}
+
+// toolTurnState encodes the exchanges so far plus the model turn that
+// requested tools, for replay when the results are sent back. Nil when
+// there were no calls.
+func toolTurnState(replay []*genai.Content, turn *genai.Content, toolCalls []provider.ToolCall) []byte {
+	if len(toolCalls) == 0 || turn == nil {
+		return nil
+	}
+	state, err := json.Marshal(append(replay[:len(replay):len(replay)], turn))
+	if err != nil {
+		slog.Warn("failed to encode gemini tool turn", "error", err)
+		return nil
+	}
+	return state
+}
+
+// firstContent returns the first candidate's content, or nil.
+func firstContent(resp *genai.GenerateContentResponse) *genai.Content {
+	if resp == nil || len(resp.Candidates) == 0 {
+		return nil
+	}
+	return resp.Candidates[0].Content
+}
+
+// streamedContent rebuilds a streamed model turn from the accumulated text
+// and function call parts.
+func streamedContent(text string, callParts []*genai.Part) *genai.Content {
+	var parts []*genai.Part
+	if text != "" {
+		parts = append(parts, genai.NewPartFromText(text))
+	}
+	return genai.NewContentFromParts(append(parts, callParts...), genai.RoleModel)
+}
+
+// buildSystemInstruction appends the file ID to filename mappings to the
+// instructions so the model cites uploaded files by their original names.
+func buildSystemInstruction(instructions string, fileIDToFilename map[string]string) string {
+	if len(fileIDToFilename) == 0 {
+		return instructions
+	}
+	var mappings []string
+	for id, name := range fileIDToFilename {
+		mappings = append(mappings, fmt.Sprintf("- %s: %s", id, name))
+	}
+	sort.Strings(mappings)
+	return instructions + "\n\nThe following files are attached. When referencing them, use the original filename:\n" + strings.Join(mappings, "\n")
+}
+
+// buildContents adapts the conversation to Gemini contents, attaching
+// inline images to the final user turn.
+func buildContents(userInput string, history []provider.Message, inlineImages []provider.InlineImage) []*genai.Content {
+	turns := provider.BuildConversation(userInput, history)
+	contents := make([]*genai.Content, 0, len(turns))
+	for _, turn := range turns[:len(turns)-1] {
+		role := genai.Role(genai.RoleUser)
+		if turn.Role == provider.RoleAssistant {
 			role = genai.RoleModel
-		} else {
-			role = genai.RoleUser
 		}
-		contents = append(contents, genai.NewContentFromText(trimmed, role))
+		contents = append(contents, genai.NewContentFromText(turn.Text, role))
 	}
 
 	// Build user content with text and optional images
-	var parts []*genai.Part
-	parts = append(parts, genai.NewPartFromText(strings.TrimSpace(userInput)))
-
-	// Add inline images
+	parts := []*genai.Part{genai.NewPartFromText(turns[len(turns)-1].Text)}
 	for _, img := range inlineImages {
 		parts = append(parts, genai.NewPartFromURI(img.URI, img.MIMEType))
 	}
 
-	contents = append(contents, &genai.Content{
+	return append(contents, &genai.Content{
 		Role:  genai.RoleUser,
 		Parts: parts,
 	})
+}
+
+// SupportsImageInput reports whether img can be sent as file data: by
+// http(s) or Files API URI, with a type Gemini reads. Gemini has no file IDs
+// apart from the URI, or data URLs.
+func (c *Client) SupportsImageInput(img provider.InlineImage) bool {
+	return img.FileID == "" && (strings.HasPrefix(img.URI, "https://") || strings.HasPrefix(img.URI, "http://")) &&
+		attachmentImageTypes[img.MIMEType]
+}
+
+// attachmentInlineLimit is the most attachment content sent inline. Gemini
+// rejects requests over 20 MB, so larger attachments are uploaded to the
+// Files API and referenced by URI.
+const attachmentInlineLimit = 15 << 20
+
+// attachmentImageTypes are the image types Gemini reads.
+var attachmentImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+	"image/heic": true,
+	"image/heif": true,
+}
+
+// SupportsAttachment reports whether Gemini reads mimeType: images, PDFs,
+// audio and video.
+func (c *Client) SupportsAttachment(mimeType string) bool {
+	return supportsAttachment(mimeType)
+}
+
+func supportsAttachment(mimeType string) bool {
+	return attachmentImageTypes[mimeType] || mimeType == "application/pdf" ||
+		strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/")
+}
+
+// attachContents adds attachments to the final user turn of contents, as
+// inline data or, when together they exceed attachmentInlineLimit, as Files
+// API uploads. Text attachments are already part of the message text (see
+// requestContents).
+func attachContents(ctx context.Context, client *genai.Client, contents []*genai.Content, attachments []provider.Attachment) error {
+	var files []provider.Attachment
+	total := 0
+	for _, a := range attachments {
+		if a.IsText() {
+			continue
+		}
+		if !supportsAttachment(a.MIMEType) {
+			return &provider.UnsupportedAttachmentError{Provider: "gemini", Filename: a.Filename, MIMEType: a.MIMEType}
+		}
+		files = append(files, a)
+		total += len(a.Content)
+	}
+	if len(files) == 0 {
+		return nil
+	}
 
-	return contents
+	last := contents[len(contents)-1]
+	for _, a := range files {
+		if total <= attachmentInlineLimit {
+			last.Parts = append(last.Parts, genai.NewPartFromBytes(a.Content, a.MIMEType))
+			continue
+		}
+		uri, err := uploadAttachment(ctx, client, a)
+		if err != nil {
+			return fmt.Errorf("upload attachment %s: %w", a.Filename, err)
+		}
+		last.Parts = append(last.Parts, genai.NewPartFromURI(uri, a.MIMEType))
+	}
+	return nil
+}
+
+// uploadAttachment uploads an attachment to the Files API and waits for
+// Gemini to process it.
+func uploadAttachment(ctx context.Context, client *genai.Client, a provider.Attachment) (string, error) {
+	file, err := client.Files.Upload(ctx, bytes.NewReader(a.Content), &genai.UploadFileConfig{
+		MIMEType:    a.MIMEType,
+		DisplayName: a.Filename,
+	})
+	if err != nil {
+		return "", err
+	}
+	for file.State == genai.FileStateProcessing {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+		if file, err = client.Files.Get(ctx, file.Name, nil); err != nil {
+			return "", fmt.Errorf("get file status: %w", err)
+		}
+	}
+	if file.State == genai.FileStateFailed {
+		return "", errors.New("file processing failed")
+	}
+	slog.InfoContext(ctx, "attachment uploaded to Files API", "name", file.Name, "mime_type", a.MIMEType, "size", len(a.Content))
+	return file.URI, nil
 }
 
 // extractText extracts text from the response.
@@ -749,48 +989,12 @@ func extractStructuredResponse(resp *genai.GenerateContentResponse) (string, *pr
 		return "", nil
 	}
 
-	var parsed struct {
-		Reply              string `json:"reply"`
-		Intent             string `json:"intent"`
-		RequiresUserAction bool   `json:"requires_user_action"`
-		Entities           []struct {
-			Name string `json:"name"`
-			Type string `json:"type"`
-		} `json:"entities"`
-		Topics           []string `json:"topics"`
-		SchedulingIntent *struct {
-			Detected          bool   `json:"detected"`
-			DatetimeMentioned string `json:"datetime_mentioned"`
-		} `json:"scheduling_intent"`
-	}
-
-	if err := json.Unmarshal([]byte(rawJSON), &parsed); err != nil {
+	reply, metadata, err := provider.ParseStructuredJSON(rawJSON)
+	if err != nil {
 		slog.Warn("failed to parse structured response, falling back to raw text", "error", err)
 		return rawJSON, nil
 	}
-
-	// Convert to provider types
-	metadata := &provider.StructuredMetadata{
-		Intent:             parsed.Intent,
-		RequiresUserAction: parsed.RequiresUserAction,
-		Topics:             parsed.Topics,
-	}
-
-	for _, e := range parsed.Entities {
-		metadata.Entities = append(metadata.Entities, provider.StructuredEntity{
-			Name: e.Name,
-			Type: e.Type,
-		})
-	}
-
-	if parsed.SchedulingIntent != nil {
-		metadata.Scheduling = &provider.SchedulingIntent{
-			Detected:          parsed.SchedulingIntent.Detected,
-			DatetimeMentioned: parsed.SchedulingIntent.DatetimeMentioned,
-		}
-	}
-
-	return parsed.Reply, metadata
+	return reply, metadata
 }
 
 // getBlockReason checks if the response was blocked and returns the reason.
@@ -815,6 +1019,47 @@ func getBlockReason(resp *genai.GenerateContentResponse) string {
 	return ""
 }
 
+// harmCategoryNames maps Gemini harm categories to provider category names.
+var harmCategoryNames = map[genai.HarmCategory]string{
+	genai.HarmCategoryHarassment:       provider.HarmHarassment,
+	genai.HarmCategoryHateSpeech:       provider.HarmHateSpeech,
+	genai.HarmCategorySexuallyExplicit: provider.HarmSexuallyExplicit,
+	genai.HarmCategoryDangerousContent: provider.HarmDangerousContent,
+}
+
+// safetyBlock returns the block on the prompt or the first candidate, or nil
+// if the response was not blocked.
+func safetyBlock(resp *genai.GenerateContentResponse) *provider.SafetyBlock {
+	if resp == nil {
+		return nil
+	}
+	if fb := resp.PromptFeedback; fb != nil && fb.BlockReason != "" {
+		reason := fb.BlockReasonMessage
+		if reason == "" {
+			reason = "prompt blocked: " + strings.ToLower(string(fb.BlockReason))
+		}
+		return &provider.SafetyBlock{Stage: "prompt", Category: blockedCategory(fb.SafetyRatings), Reason: reason}
+	}
+	reason := getBlockReason(resp)
+	if reason == "" {
+		return nil
+	}
+	return &provider.SafetyBlock{Stage: "response", Category: blockedCategory(resp.Candidates[0].SafetyRatings), Reason: reason}
+}
+
+// blockedCategory returns the category of the first blocked rating.
+func blockedCategory(ratings []*genai.SafetyRating) string {
+	for _, r := range ratings {
+		if r != nil && r.Blocked {
+			if name, ok := harmCategoryNames[r.Category]; ok {
+				return name
+			}
+			return strings.ToLower(strings.TrimPrefix(string(r.Category), "HARM_CATEGORY_"))
+		}
+	}
+	return ""
+}
+
 // extractUsage extracts token usage from the response.
 // Captures all 5 Gemini token types for accurate pricing:
 // - PromptTokenCount (standard input)
@@ -985,6 +1230,34 @@ func buildSafetySettings(threshold string) []*genai.SafetySetting {
 	return settings
 }
 
+// typedSafetySettings converts typed thresholds to Gemini safety settings.
+// Categories without a threshold keep Gemini's default.
+func typedSafetySettings(safety provider.SafetySettings) []*genai.SafetySetting {
+	levels := map[provider.SafetyThreshold]genai.HarmBlockThreshold{
+		provider.SafetyBlockNone:           genai.HarmBlockThresholdBlockNone,
+		provider.SafetyBlockOnlyHigh:       genai.HarmBlockThresholdBlockOnlyHigh,
+		provider.SafetyBlockMediumAndAbove: genai.HarmBlockThresholdBlockMediumAndAbove,
+		provider.SafetyBlockLowAndAbove:    genai.HarmBlockThresholdBlockLowAndAbove,
+	}
+	categories := []struct {
+		category  genai.HarmCategory
+		threshold provider.SafetyThreshold
+	}{
+		{genai.HarmCategoryHarassment, safety.Harassment},
+		{genai.HarmCategoryHateSpeech, safety.HateSpeech},
+		{genai.HarmCategorySexuallyExplicit, safety.SexuallyExplicit},
+		{genai.HarmCategoryDangerousContent, safety.DangerousContent},
+	}
+
+	var settings []*genai.SafetySetting
+	for _, c := range categories {
+		if level, ok := levels[c.threshold]; ok {
+			settings = append(settings, &genai.SafetySetting{Category: c.category, Threshold: level})
+		}
+	}
+	return settings
+}
+
 // parseThinkingLevel converts config string to genai.ThinkingLevel.
 func parseThinkingLevel(s string) genai.ThinkingLevel {
 	switch strings.ToUpper(s) {
@@ -1002,7 +1275,8 @@ func parseThinkingLevel(s string) genai.ThinkingLevel {
 }
 
 // structuredOutputSchema returns the JSON schema for structured output mode.
-// This extracts intent, entities, topics, and scheduling signals alongside the response.
+// This extracts intent, entities, topics, scheduling signals, and durable user facts
+// alongside the response.
 func structuredOutputSchema() *genai.Schema {
 	return &genai.Schema{
 		Type: "object",
@@ -1014,10 +1288,7 @@ func structuredOutputSchema() *genai.Schema {
 			"intent": {
 				Type:        "string",
 				Description: "Primary intent classification",
-				Enum: []string{
-					"question", "request", "task_delegation",
-					"feedback", "complaint", "follow_up", "attachment_analysis",
-				},
+				Enum:        provider.StructuredIntents,
 			},
 			"requires_user_action": {
 				Type:        "boolean",
@@ -1033,19 +1304,7 @@ func structuredOutputSchema() *genai.Schema {
 						"type": {
 							Type:        "string",
 							Description: "Entity type",
-							Enum: []string{
-								// Core (9)
-								"person", "organization", "location", "product",
-								"project", "document", "event", "money", "date",
-								// Business (3)
-								"investor", "advisor", "metric",
-								// Technology (3)
-								"technology", "tool", "service",
-								// Operations (3)
-								"methodology", "credential", "timeframe",
-								// Content (3)
-								"feature", "url", "email_address",
-							},
+							Enum:        provider.StructuredEntityTypes,
 						},
 					},
 					Required: []string{"name", "type"},
@@ -1064,6 +1323,22 @@ func structuredOutputSchema() *genai.Schema {
 					"datetime_mentioned": {Type: "string", Description: "Raw text like 'next Tuesday at 2pm'"},
 				},
 			},
+			"facts": {
+				Type:        "array",
+				Description: "Durable facts the user stated about themselves (name, preferences, role). Empty if none.",
+				Items: &genai.Schema{
+					Type: "object",
+					Properties: map[string]*genai.Schema{
+						"category": {
+							Type:        "string",
+							Description: "Fact category",
+							Enum:        provider.StructuredFactCategories,
+						},
+						"content": {Type: "string", Description: "Short standalone statement, e.g. 'Prefers replies in Spanish'"},
+					},
+					Required: []string{"category", "content"},
+				},
+			},
 		},
 		Required: []string{"reply", "intent"},
 	}
@@ -1148,7 +1423,7 @@ func extractFunctionCalls(resp *genai.GenerateContentResponse) []provider.ToolCa
 					argsJSON = []byte("{}")
 				}
 				toolCalls = append(toolCalls, provider.ToolCall{
-					ID:        part.FunctionCall.ID,
+					ID:        functionCallID(part.FunctionCall, len(toolCalls)),
 					Name:      part.FunctionCall.Name,
 					Arguments: string(argsJSON),
 				})
@@ -1190,8 +1465,41 @@ func extractCodeExecutionResults(resp *genai.GenerateContentResponse) []provider
 					}
 				}
 			}
+			// Files the code generated, such as plots, follow its result
+			if part.InlineData != nil && len(executions) > 0 {
+				last := &executions[len(executions)-1]
+				last.Files = append(last.Files, codeExecutionFile(part.InlineData, len(last.Files)+1))
+			}
 		}
 	}
 
 	return executions
 }
+
+// codeExecutionFileExts are the extensions of the file types code execution
+// commonly generates, where mime.ExtensionsByType has several to choose from.
+var codeExecutionFileExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"text/csv":   ".csv",
+	"text/plain": ".txt",
+}
+
+// codeExecutionFile converts the n-th file a code execution generated. Files
+// Gemini does not name are named by their position and type.
+func codeExecutionFile(blob *genai.Blob, n int) provider.GeneratedFile {
+	name := blob.DisplayName
+	if name == "" {
+		ext, ok := codeExecutionFileExts[blob.MIMEType]
+		if !ok {
+			if exts, _ := mime.ExtensionsByType(blob.MIMEType); len(exts) > 0 {
+				ext = exts[0]
+			}
+		}
+		name = fmt.Sprintf("output_%d%s", n, ext)
+	}
+	return provider.GeneratedFile{
+		Name:     name,
+		MIMEType: blob.MIMEType,
+		Content:  blob.Data,
+	}
+}
@@ -46,6 +46,22 @@ func TestBuildContents_EmptyHistory(t *testing.T) {
 	}
 }
 
+func TestBuildSystemInstruction(t *testing.T) {
+	if got := buildSystemInstruction("Be brief.", nil); got != "Be brief." {
+		t.Errorf("without files = %q, want instructions unchanged", got)
+	}
+
+	got := buildSystemInstruction("Be brief.", map[string]string{
+		"files/b": "report.pdf",
+		"files/a": "notes.txt",
+	})
+	want := "Be brief.\n\nThe following files are attached. When referencing them, use the original filename:\n" +
+		"- files/a: notes.txt\n- files/b: report.pdf"
+	if got != want {
+		t.Errorf("buildSystemInstruction() = %q, want %q", got, want)
+	}
+}
+
 func TestExtractText(t *testing.T) {
 	resp := &genai.GenerateContentResponse{
 		Candidates: []*genai.Candidate{
@@ -74,6 +90,31 @@ func TestExtractText_Nil(t *testing.T) {
 	}
 }
 
+func TestExtractStructuredResponse_Facts(t *testing.T) {
+	raw := `{"reply":"Nice to meet you, Ana!","intent":"feedback","facts":[` +
+		`{"category":"name","content":"User's name is Ana"},` +
+		`{"category":"preference","content":"  "}]}`
+	resp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{
+			{Content: &genai.Content{Parts: []*genai.Part{{Text: raw}}}},
+		},
+	}
+
+	text, metadata := extractStructuredResponse(resp)
+	if text != "Nice to meet you, Ana!" {
+		t.Fatalf("text = %q, want reply field", text)
+	}
+	if metadata == nil {
+		t.Fatal("expected metadata")
+	}
+	if len(metadata.Facts) != 1 {
+		t.Fatalf("expected 1 fact (blank content skipped), got %d", len(metadata.Facts))
+	}
+	if metadata.Facts[0].Category != "name" || metadata.Facts[0].Content != "User's name is Ana" {
+		t.Fatalf("unexpected fact: %+v", metadata.Facts[0])
+	}
+}
+
 func TestExtractUsage(t *testing.T) {
 	resp := &genai.GenerateContentResponse{
 		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
@@ -239,6 +280,35 @@ func TestExtractCitations_NoMetadata(t *testing.T) {
 	}
 }
 
+func TestExtractCodeExecutionResults_Files(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content: &genai.Content{Parts: []*genai.Part{
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte("ignored")}}, // Before any code
+				{ExecutableCode: &genai.ExecutableCode{Code: "plot()", Language: genai.LanguagePython}},
+				{CodeExecutionResult: &genai.CodeExecutionResult{Outcome: genai.OutcomeOK, Output: "done"}},
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte("png")}},
+				{InlineData: &genai.Blob{MIMEType: "text/csv", Data: []byte("a,b"), DisplayName: "table.csv"}},
+			}},
+		}},
+	}
+
+	executions := extractCodeExecutionResults(resp)
+	if len(executions) != 1 {
+		t.Fatalf("expected 1 execution, got %d", len(executions))
+	}
+	files := executions[0].Files
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", files)
+	}
+	if files[0].Name != "output_1.png" || files[0].MIMEType != "image/png" || string(files[0].Content) != "png" {
+		t.Errorf("unexpected plot %+v", files[0])
+	}
+	if files[1].Name != "table.csv" || string(files[1].Content) != "a,b" {
+		t.Errorf("unexpected table %+v", files[1])
+	}
+}
+
 func TestBuildSafetySettings(t *testing.T) {
 	tests := []struct {
 		threshold string
@@ -267,6 +337,46 @@ func TestBuildSafetySettings(t *testing.T) {
 	}
 }
 
+func TestTypedSafetySettings(t *testing.T) {
+	settings := typedSafetySettings(provider.SafetySettings{
+		Harassment:       provider.SafetyBlockLowAndAbove,
+		DangerousContent: provider.SafetyBlockNone,
+	})
+	if len(settings) != 2 {
+		t.Fatalf("expected settings for the 2 configured categories, got %d", len(settings))
+	}
+	if settings[0].Category != genai.HarmCategoryHarassment || settings[0].Threshold != genai.HarmBlockThresholdBlockLowAndAbove {
+		t.Errorf("unexpected harassment setting: %+v", settings[0])
+	}
+	if settings[1].Category != genai.HarmCategoryDangerousContent || settings[1].Threshold != genai.HarmBlockThresholdBlockNone {
+		t.Errorf("unexpected dangerous content setting: %+v", settings[1])
+	}
+}
+
+func TestSafetyBlock(t *testing.T) {
+	if safetyBlock(&genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonStop}}}) != nil {
+		t.Error("expected no block for a normal response")
+	}
+
+	block := safetyBlock(&genai.GenerateContentResponse{
+		PromptFeedback: &genai.GenerateContentResponsePromptFeedback{
+			BlockReason:   genai.BlockedReasonSafety,
+			SafetyRatings: []*genai.SafetyRating{{Category: genai.HarmCategoryHateSpeech, Blocked: true}},
+		},
+	})
+	if block == nil || block.Stage != "prompt" || block.Category != provider.HarmHateSpeech || block.Reason == "" {
+		t.Errorf("unexpected prompt block: %+v", block)
+	}
+
+	block = safetyBlock(&genai.GenerateContentResponse{Candidates: []*genai.Candidate{{
+		FinishReason:  genai.FinishReasonSafety,
+		SafetyRatings: []*genai.SafetyRating{{Category: genai.HarmCategoryHarassment}, {Category: genai.HarmCategorySexuallyExplicit, Blocked: true}},
+	}}})
+	if block == nil || block.Stage != "response" || block.Category != provider.HarmSexuallyExplicit {
+		t.Errorf("unexpected response block: %+v", block)
+	}
+}
+
 func TestIsRetryableError(t *testing.T) {
 	tests := []struct {
 		name string
@@ -327,6 +437,22 @@ func TestClientCapabilities(t *testing.T) {
 	}
 }
 
+func TestSupportsImageInput(t *testing.T) {
+	client := NewClient()
+	if !client.SupportsImageInput(provider.InlineImage{URI: "https://generativelanguage.googleapis.com/v1beta/files/abc", MIMEType: "image/png"}) {
+		t.Error("Files API URI with a type should be supported")
+	}
+	for _, img := range []provider.InlineImage{
+		{URI: "https://example.com/chart"},
+		{URI: "data:image/png;base64,iVBORw0KGgo=", MIMEType: "image/png"},
+		{FileID: "file-1", MIMEType: "image/png"},
+	} {
+		if client.SupportsImageInput(img) {
+			t.Errorf("%+v should not be supported", img)
+		}
+	}
+}
+
 func TestGenerateReply_MissingAPIKey(t *testing.T) {
 	client := NewClient()
 	_, err := client.GenerateReply(context.Background(), provider.GenerateParams{
@@ -339,3 +465,109 @@ func TestGenerateReply_MissingAPIKey(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestBuildToolContinuation(t *testing.T) {
+	modelTurn := genai.NewContentFromParts([]*genai.Part{
+		{FunctionCall: &genai.FunctionCall{Name: "lookup", Args: map[string]any{"city": "Paris"}}, ThoughtSignature: []byte("sig")},
+	}, genai.RoleModel)
+	state := toolTurnState(nil, modelTurn, []provider.ToolCall{{ID: "call_0", Name: "lookup"}})
+	if state == nil {
+		t.Fatal("expected tool turn state")
+	}
+
+	contents, replay, err := requestContents(provider.GenerateParams{
+		ToolTurn:    &provider.ToolTurn{UserInput: "Weather?", State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "call_0", Output: "sunny"}},
+	})
+	if err != nil {
+		t.Fatalf("requestContents failed: %v", err)
+	}
+	if len(contents) != 3 || len(replay) != 2 {
+		t.Fatalf("expected user, model and tool result contents, got %d (replay %d)", len(contents), len(replay))
+	}
+	if contents[0].Parts[0].Text != "Weather?" {
+		t.Errorf("expected original user input first, got %q", contents[0].Parts[0].Text)
+	}
+	if string(contents[1].Parts[0].ThoughtSignature) != "sig" {
+		t.Error("expected thought signature to be replayed")
+	}
+	resp := contents[2].Parts[0].FunctionResponse
+	if resp == nil || resp.Name != "lookup" || resp.Response["output"] != "sunny" {
+		t.Errorf("unexpected function response: %+v", resp)
+	}
+
+	_, _, err = requestContents(provider.GenerateParams{
+		ToolTurn:    &provider.ToolTurn{State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "call_9"}},
+	})
+	if err == nil {
+		t.Error("expected error for unknown tool call")
+	}
+}
+
+func TestBuildToolContinuation_ParallelCallsInCallOrder(t *testing.T) {
+	modelTurn := genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromFunctionCall("weather", map[string]any{"city": "Paris"}),
+		genai.NewPartFromFunctionCall("time", map[string]any{"city": "Rome"}),
+	}, genai.RoleModel)
+	calls := extractFunctionCalls(&genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: modelTurn}},
+	})
+	if len(calls) != 2 || calls[0].ID != "call_0" || calls[1].ID != "call_1" {
+		t.Fatalf("expected stable positional call IDs, got %+v", calls)
+	}
+	state := toolTurnState(nil, modelTurn, calls)
+
+	contents, _, err := requestContents(provider.GenerateParams{
+		ToolTurn: &provider.ToolTurn{UserInput: "Paris weather and Rome time?", State: state},
+		ToolResults: []provider.ToolResult{
+			{ToolCallID: "call_1", Output: "noon"},
+			{ToolCallID: "call_0", Output: "sunny"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("requestContents failed: %v", err)
+	}
+	parts := contents[len(contents)-1].Parts
+	if len(parts) != 2 || parts[0].FunctionResponse.Name != "weather" || parts[1].FunctionResponse.Name != "time" {
+		t.Errorf("expected function responses in call order, got %+v, %+v", parts[0].FunctionResponse, parts[1].FunctionResponse)
+	}
+
+	_, _, err = requestContents(provider.GenerateParams{
+		ToolTurn:    &provider.ToolTurn{State: state},
+		ToolResults: []provider.ToolResult{{ToolCallID: "call_0", Output: "sunny"}},
+	})
+	if err == nil {
+		t.Error("expected error when a parallel call is left unanswered")
+	}
+}
+
+func TestAttachContents_Inline(t *testing.T) {
+	attachments := []provider.Attachment{
+		{Filename: "notes.txt", MIMEType: "text/plain", Content: []byte("notes")},
+		{Filename: "clip.mp3", MIMEType: "audio/mpeg", Content: []byte("mp3")},
+	}
+	contents, _, err := requestContents(provider.GenerateParams{UserInput: "Transcribe", Attachments: attachments})
+	if err != nil {
+		t.Fatalf("requestContents failed: %v", err)
+	}
+	if err := attachContents(context.Background(), nil, contents, attachments); err != nil {
+		t.Fatalf("attachContents failed: %v", err)
+	}
+	parts := contents[len(contents)-1].Parts
+	if len(parts) != 2 {
+		t.Fatalf("expected text and audio parts, got %d", len(parts))
+	}
+	if want := "Transcribe\n\n<attachment filename=\"notes.txt\">\nnotes\n</attachment>"; parts[0].Text != want {
+		t.Errorf("expected inlined text attachment, got %q", parts[0].Text)
+	}
+	if parts[1].InlineData == nil || parts[1].InlineData.MIMEType != "audio/mpeg" {
+		t.Errorf("expected inline audio, got %+v", parts[1])
+	}
+
+	err = attachContents(context.Background(), nil, contents, []provider.Attachment{{Filename: "a.zip", MIMEType: "application/zip"}})
+	var unsupported *provider.UnsupportedAttachmentError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("expected UnsupportedAttachmentError, got %v", err)
+	}
+}
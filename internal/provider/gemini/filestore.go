@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/validation"
 )
 
@@ -46,7 +47,20 @@ func isOfficeFile(mimeType string) bool {
 // FileStoreConfig contains configuration for Gemini file store operations.
 type FileStoreConfig struct {
 	APIKey  string
-	BaseURL string // Optional override for testing
+	BaseURL string              // Optional override for testing
+	Proxy   *egress.ProxyConfig // Outbound proxy; nil uses the server-wide proxy
+
+	// OnStatus, if set, is called with an upload's processing status
+	// ("in_progress", then "completed" or "failed") after each poll of its
+	// operation.
+	OnStatus func(status string)
+}
+
+// reportStatus passes status to the OnStatus callback, if any.
+func (cfg FileStoreConfig) reportStatus(status string) {
+	if cfg.OnStatus != nil {
+		cfg.OnStatus(status)
+	}
 }
 
 // FileStoreResult contains the result of a file store operation.
@@ -94,6 +108,20 @@ type operationError struct {
 	Message string `json:"message"`
 }
 
+// apiKeyHeader carries the API key on raw HTTP calls. Keys in the URL
+// (?key=) end up in proxy and access logs.
+const apiKeyHeader = "x-goog-api-key"
+
+// newAPIRequest creates a Gemini API request authenticated with apiKey.
+func newAPIRequest(ctx context.Context, method, url, apiKey string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(apiKeyHeader, apiKey)
+	return req, nil
+}
+
 // getBaseURL returns the base URL for the FileSearch API.
 func (cfg FileStoreConfig) getBaseURL() string {
 	if cfg.BaseURL != "" {
@@ -117,9 +145,9 @@ type filesAPIResponse struct {
 
 // uploadToFilesAPI uploads a file to the Gemini Files API.
 // This is the first step of the Office file workaround.
-func uploadToFilesAPI(ctx context.Context, apiKey string, filename string, mimeType string, content []byte) (string, error) {
+func uploadToFilesAPI(ctx context.Context, apiKey string, proxy *egress.ProxyConfig, filename string, mimeType string, content []byte) (string, error) {
 	// Create the upload URL
-	uploadURL := fmt.Sprintf("https://generativelanguage.googleapis.com/upload/v1beta/files?key=%s", apiKey)
+	uploadURL := "https://generativelanguage.googleapis.com/upload/v1beta/files"
 
 	// Create metadata JSON
 	metadata := map[string]interface{}{
@@ -134,7 +162,7 @@ func uploadToFilesAPI(ctx context.Context, apiKey string, filename string, mimeT
 
 	// Use resumable upload protocol for reliability
 	// Step 1: Initiate the upload
-	initReq, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(metadataJSON))
+	initReq, err := newAPIRequest(ctx, http.MethodPost, uploadURL, apiKey, bytes.NewReader(metadataJSON))
 	if err != nil {
 		return "", fmt.Errorf("create init request: %w", err)
 	}
@@ -144,7 +172,7 @@ func uploadToFilesAPI(ctx context.Context, apiKey string, filename string, mimeT
 	initReq.Header.Set("X-Goog-Upload-Header-Content-Length", fmt.Sprintf("%d", len(content)))
 	initReq.Header.Set("X-Goog-Upload-Header-Content-Type", mimeType)
 
-	initResp, err := http.DefaultClient.Do(initReq)
+	initResp, err := egress.Client(proxy, 0).Do(initReq)
 	if err != nil {
 		return "", fmt.Errorf("execute init request: %w", err)
 	}
@@ -170,7 +198,7 @@ func uploadToFilesAPI(ctx context.Context, apiKey string, filename string, mimeT
 	uploadReq.Header.Set("X-Goog-Upload-Command", "upload, finalize")
 	uploadReq.Header.Set("X-Goog-Upload-Offset", "0")
 
-	uploadResp, err := http.DefaultClient.Do(uploadReq)
+	uploadResp, err := egress.Client(proxy, 0).Do(uploadReq)
 	if err != nil {
 		return "", fmt.Errorf("execute upload request: %w", err)
 	}
@@ -186,7 +214,7 @@ func uploadToFilesAPI(ctx context.Context, apiKey string, filename string, mimeT
 		return "", fmt.Errorf("decode upload response: %w", err)
 	}
 
-	slog.Info("file uploaded to Files API",
+	slog.InfoContext(ctx, "file uploaded to Files API",
 		"name", fileResp.File.Name,
 		"display_name", fileResp.File.DisplayName,
 		"mime_type", fileResp.File.MIMEType,
@@ -199,7 +227,7 @@ func uploadToFilesAPI(ctx context.Context, apiKey string, filename string, mimeT
 // This is the second step of the Office file workaround.
 // Based on the genai SDK, the endpoint is :importFile and body is {"fileName": "files/xxx"}
 func importFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, storeID string, fileName string, displayName string) (*UploadedFile, error) {
-	url := fmt.Sprintf("%s/fileSearchStores/%s:importFile?key=%s", cfg.getBaseURL(), storeID, cfg.APIKey)
+	url := fmt.Sprintf("%s/fileSearchStores/%s:importFile", cfg.getBaseURL(), storeID)
 
 	// The genai SDK sends fileName directly in the body
 	reqBody := map[string]interface{}{
@@ -211,19 +239,19 @@ func importFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, store
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	slog.Info("importing file to FileSearchStore",
+	slog.InfoContext(ctx, "importing file to FileSearchStore",
 		"store_id", storeID,
 		"file_name", fileName,
 		"display_name", displayName,
 	)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	req, err := newAPIRequest(ctx, http.MethodPost, url, cfg.APIKey, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
@@ -239,7 +267,7 @@ func importFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, store
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	slog.Info("file import initiated",
+	slog.InfoContext(ctx, "file import initiated",
 		"store_id", storeID,
 		"file_name", fileName,
 		"operation", opResp.Name,
@@ -248,7 +276,7 @@ func importFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, store
 	// Poll for completion
 	status, err := waitForOperation(ctx, cfg, opResp.Name)
 	if err != nil {
-		slog.Warn("file import incomplete",
+		slog.WarnContext(ctx, "file import incomplete",
 			"store_id", storeID,
 			"file_name", fileName,
 			"error", err,
@@ -278,15 +306,15 @@ func importFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, store
 
 // deleteFromFilesAPI deletes a file from the Gemini Files API.
 // This is used for cleanup after the Office file workaround.
-func deleteFromFilesAPI(ctx context.Context, apiKey string, fileName string) error {
-	url := fmt.Sprintf("%s/%s?key=%s", filesAPIBaseURL, fileName, apiKey)
+func deleteFromFilesAPI(ctx context.Context, apiKey string, proxy *egress.ProxyConfig, fileName string) error {
+	url := fmt.Sprintf("%s/%s", filesAPIBaseURL, fileName)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req, err := newAPIRequest(ctx, http.MethodDelete, url, apiKey, nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(proxy, 0).Do(req)
 	if err != nil {
 		return fmt.Errorf("execute request: %w", err)
 	}
@@ -298,7 +326,7 @@ func deleteFromFilesAPI(ctx context.Context, apiKey string, fileName string) err
 		return fmt.Errorf("delete from Files API failed: %s - %s", resp.Status, string(body))
 	}
 
-	slog.Info("file deleted from Files API", "file_name", fileName)
+	slog.InfoContext(ctx, "file deleted from Files API", "file_name", fileName)
 	return nil
 }
 
@@ -314,7 +342,7 @@ func CreateFileSearchStore(ctx context.Context, cfg FileStoreConfig, name string
 		}
 	}
 
-	url := fmt.Sprintf("%s/fileSearchStores?key=%s", cfg.getBaseURL(), cfg.APIKey)
+	url := fmt.Sprintf("%s/fileSearchStores", cfg.getBaseURL())
 
 	reqBody := map[string]string{}
 	if name != "" {
@@ -326,15 +354,15 @@ func CreateFileSearchStore(ctx context.Context, cfg FileStoreConfig, name string
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	slog.Info("creating gemini file search store", "name", name)
+	slog.InfoContext(ctx, "creating gemini file search store", "name", name)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	req, err := newAPIRequest(ctx, http.MethodPost, url, cfg.APIKey, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
@@ -356,7 +384,7 @@ func CreateFileSearchStore(ctx context.Context, cfg FileStoreConfig, name string
 		storeID = storeResp.Name[idx+1:]
 	}
 
-	slog.Info("gemini file search store created",
+	slog.InfoContext(ctx, "gemini file search store created",
 		"store_id", storeID,
 		"name", storeResp.DisplayName,
 	)
@@ -399,7 +427,7 @@ func UploadFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, store
 
 	// Check if this is an Office file that requires the workaround
 	if isOfficeFile(mimeType) {
-		slog.Info("using Files API workaround for Office file",
+		slog.InfoContext(ctx, "using Files API workaround for Office file",
 			"store_id", storeID,
 			"filename", filename,
 			"mime_type", mimeType,
@@ -414,7 +442,7 @@ func UploadFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, store
 // uploadOfficeFileToFileSearchStore implements the two-step workaround for Office files.
 func uploadOfficeFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig, storeID string, filename string, mimeType string, fileContent []byte) (*UploadedFile, error) {
 	// Step 1: Upload to Files API
-	filesAPIName, err := uploadToFilesAPI(ctx, cfg.APIKey, filename, mimeType, fileContent)
+	filesAPIName, err := uploadToFilesAPI(ctx, cfg.APIKey, cfg.Proxy, filename, mimeType, fileContent)
 	if err != nil {
 		return nil, fmt.Errorf("upload to Files API: %w", err)
 	}
@@ -423,8 +451,8 @@ func uploadOfficeFileToFileSearchStore(ctx context.Context, cfg FileStoreConfig,
 	defer func() {
 		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		if cleanupErr := deleteFromFilesAPI(cleanupCtx, cfg.APIKey, filesAPIName); cleanupErr != nil {
-			slog.Warn("failed to cleanup file from Files API",
+		if cleanupErr := deleteFromFilesAPI(cleanupCtx, cfg.APIKey, cfg.Proxy, filesAPIName); cleanupErr != nil {
+			slog.WarnContext(ctx, "failed to cleanup file from Files API",
 				"file_name", filesAPIName,
 				"error", cleanupErr,
 			)
@@ -451,9 +479,9 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 		baseURL = strings.Replace(baseURL, fileSearchBaseURL, fileSearchBaseURL+"/upload", 1)
 	}
 
-	url := fmt.Sprintf("%s/fileSearchStores/%s:uploadToFileSearchStore?key=%s", baseURL, storeID, cfg.APIKey)
+	url := fmt.Sprintf("%s/fileSearchStores/%s:uploadToFileSearchStore", baseURL, storeID)
 
-	slog.Info("uploading file to gemini file search store (direct)",
+	slog.InfoContext(ctx, "uploading file to gemini file search store (direct)",
 		"store_id", storeID,
 		"filename", filename,
 		"mime_type", mimeType,
@@ -462,7 +490,7 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 	// Create multipart request
 	// For Gemini upload, we need to send metadata as JSON and file as binary
 	// Using simple JSON metadata with file in body
-	metadataURL := fmt.Sprintf("%s/fileSearchStores/%s:uploadToFileSearchStore?key=%s", cfg.getBaseURL(), storeID, cfg.APIKey)
+	metadataURL := fmt.Sprintf("%s/fileSearchStores/%s:uploadToFileSearchStore", cfg.getBaseURL(), storeID)
 
 	reqBody := map[string]interface{}{
 		"displayName": filename,
@@ -475,7 +503,7 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 
 	// First, try the simple upload approach with metadata
 	// Create a combined request body for the upload
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(fileContent))
+	req, err := newAPIRequest(ctx, http.MethodPost, url, cfg.APIKey, bytes.NewReader(fileContent))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -485,7 +513,7 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 	}
 	req.Header.Set("X-Goog-Upload-Protocol", "raw")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute upload request: %w", err)
 	}
@@ -494,13 +522,13 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 	// If raw upload fails, try JSON metadata approach
 	if resp.StatusCode != http.StatusOK {
 		// Try JSON metadata approach
-		req2, err := http.NewRequestWithContext(ctx, http.MethodPost, metadataURL, bytes.NewReader(jsonBody))
+		req2, err := newAPIRequest(ctx, http.MethodPost, metadataURL, cfg.APIKey, bytes.NewReader(jsonBody))
 		if err != nil {
 			return nil, fmt.Errorf("create metadata request: %w", err)
 		}
 		req2.Header.Set("Content-Type", "application/json")
 
-		resp2, err := http.DefaultClient.Do(req2)
+		resp2, err := egress.Client(cfg.Proxy, 0).Do(req2)
 		if err != nil {
 			return nil, fmt.Errorf("execute metadata request: %w", err)
 		}
@@ -518,7 +546,7 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 		return nil, fmt.Errorf("decode operation response: %w", err)
 	}
 
-	slog.Info("file upload initiated",
+	slog.InfoContext(ctx, "file upload initiated",
 		"store_id", storeID,
 		"filename", filename,
 		"operation", opResp.Name,
@@ -527,7 +555,7 @@ func uploadDirectToFileSearchStore(ctx context.Context, cfg FileStoreConfig, sto
 	// Poll for completion
 	status, err := waitForOperation(ctx, cfg, opResp.Name)
 	if err != nil {
-		slog.Warn("file processing incomplete",
+		slog.WarnContext(ctx, "file processing incomplete",
 			"store_id", storeID,
 			"filename", filename,
 			"error", err,
@@ -567,19 +595,19 @@ func waitForOperation(ctx context.Context, cfg FileStoreConfig, operationName st
 	ticker := time.NewTicker(fileSearchPollingInterval)
 	defer ticker.Stop()
 
-	url := fmt.Sprintf("%s/%s?key=%s", cfg.getBaseURL(), operationName, cfg.APIKey)
+	url := fmt.Sprintf("%s/%s", cfg.getBaseURL(), operationName)
 
 	for {
 		select {
 		case <-timeoutCtx.Done():
 			return "in_progress", fmt.Errorf("operation timeout")
 		case <-ticker.C:
-			req, err := http.NewRequestWithContext(timeoutCtx, http.MethodGet, url, nil)
+			req, err := newAPIRequest(timeoutCtx, http.MethodGet, url, cfg.APIKey, nil)
 			if err != nil {
 				return "unknown", fmt.Errorf("create request: %w", err)
 			}
 
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 			if err != nil {
 				return "unknown", fmt.Errorf("execute request: %w", err)
 			}
@@ -593,10 +621,13 @@ func waitForOperation(ctx context.Context, cfg FileStoreConfig, operationName st
 
 			if opResp.Done {
 				if opResp.Error != nil {
+					cfg.reportStatus("failed")
 					return "failed", fmt.Errorf("operation failed: %s", opResp.Error.Message)
 				}
+				cfg.reportStatus("completed")
 				return "completed", nil
 			}
+			cfg.reportStatus("in_progress")
 		}
 	}
 }
@@ -616,19 +647,19 @@ func DeleteFileSearchStore(ctx context.Context, cfg FileStoreConfig, storeID str
 		}
 	}
 
-	url := fmt.Sprintf("%s/fileSearchStores/%s?key=%s", cfg.getBaseURL(), storeID, cfg.APIKey)
+	url := fmt.Sprintf("%s/fileSearchStores/%s", cfg.getBaseURL(), storeID)
 	if force {
-		url += "&force=true"
+		url += "?force=true"
 	}
 
-	slog.Info("deleting gemini file search store", "store_id", storeID)
+	slog.InfoContext(ctx, "deleting gemini file search store", "store_id", storeID)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req, err := newAPIRequest(ctx, http.MethodDelete, url, cfg.APIKey, nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 	if err != nil {
 		return fmt.Errorf("execute request: %w", err)
 	}
@@ -639,7 +670,7 @@ func DeleteFileSearchStore(ctx context.Context, cfg FileStoreConfig, storeID str
 		return fmt.Errorf("delete file search store failed: %s - %s", resp.Status, string(body))
 	}
 
-	slog.Info("gemini file search store deleted", "store_id", storeID)
+	slog.InfoContext(ctx, "gemini file search store deleted", "store_id", storeID)
 	return nil
 }
 
@@ -658,14 +689,14 @@ func GetFileSearchStore(ctx context.Context, cfg FileStoreConfig, storeID string
 		}
 	}
 
-	url := fmt.Sprintf("%s/fileSearchStores/%s?key=%s", cfg.getBaseURL(), storeID, cfg.APIKey)
+	url := fmt.Sprintf("%s/fileSearchStores/%s", cfg.getBaseURL(), storeID)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := newAPIRequest(ctx, http.MethodGet, url, cfg.APIKey, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
@@ -713,17 +744,17 @@ func ListFileSearchStores(ctx context.Context, cfg FileStoreConfig, limit int) (
 		}
 	}
 
-	url := fmt.Sprintf("%s/fileSearchStores?key=%s", cfg.getBaseURL(), cfg.APIKey)
+	url := fmt.Sprintf("%s/fileSearchStores", cfg.getBaseURL())
 	if limit > 0 && limit <= 20 {
-		url += fmt.Sprintf("&pageSize=%d", limit)
+		url += fmt.Sprintf("?pageSize=%d", limit)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := newAPIRequest(ctx, http.MethodGet, url, cfg.APIKey, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := egress.Client(cfg.Proxy, 0).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
@@ -0,0 +1,46 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewAPIRequest(t *testing.T) {
+	req, err := newAPIRequest(context.Background(), http.MethodGet, fileSearchBaseURL+"/fileSearchStores/abc", "test-key", nil)
+	if err != nil {
+		t.Fatalf("newAPIRequest failed: %v", err)
+	}
+	if got := req.Header.Get(apiKeyHeader); got != "test-key" {
+		t.Errorf("expected key in %s header, got %q", apiKeyHeader, got)
+	}
+	if req.URL.RawQuery != "" {
+		t.Errorf("expected no query parameters, got %q", req.URL.RawQuery)
+	}
+}
+
+func TestDeleteFileSearchStore_RequestURL(t *testing.T) {
+	for _, tt := range []struct {
+		force     bool
+		wantQuery string
+	}{
+		{false, ""},
+		{true, "force=true"},
+	} {
+		var got *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+		}))
+
+		cfg := FileStoreConfig{APIKey: "test-key", BaseURL: srv.URL}
+		if err := DeleteFileSearchStore(context.Background(), cfg, "abc", tt.force); err != nil {
+			t.Fatalf("DeleteFileSearchStore(force=%v) failed: %v", tt.force, err)
+		}
+		srv.Close()
+
+		if got.Method != http.MethodDelete || got.URL.Path != "/fileSearchStores/abc" || got.URL.RawQuery != tt.wantQuery {
+			t.Errorf("force=%v: request = %s %s, want DELETE /fileSearchStores/abc?%s", tt.force, got.Method, got.URL, tt.wantQuery)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/httpcapture"
 	"github.com/ai8future/airborne/internal/validation"
 )
@@ -17,8 +18,9 @@ type CapturedClientConfig struct {
 }
 
 // NewCapturedClientConfig validates and creates a client configuration with HTTP capture.
+// Requests go through the given proxy, or the server-wide proxy when nil.
 // Callers convert this to provider-specific SDK options.
-func NewCapturedClientConfig(apiKey, baseURL string) (*CapturedClientConfig, error) {
+func NewCapturedClientConfig(apiKey, baseURL string, proxy *egress.ProxyConfig) (*CapturedClientConfig, error) {
 	// Validate API key
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
@@ -33,6 +35,7 @@ func NewCapturedClientConfig(apiKey, baseURL string) (*CapturedClientConfig, err
 
 	// Create HTTP capture
 	capture := httpcapture.New()
+	capture.Base = egress.Transport(proxy)
 
 	return &CapturedClientConfig{
 		APIKey:     apiKey,
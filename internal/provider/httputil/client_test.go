@@ -50,7 +50,7 @@ func TestNewCapturedClientConfig(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg, err := NewCapturedClientConfig(tt.apiKey, tt.baseURL)
+			cfg, err := NewCapturedClientConfig(tt.apiKey, tt.baseURL, nil)
 
 			if tt.wantErr {
 				if err == nil {
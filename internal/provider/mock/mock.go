@@ -0,0 +1,265 @@
+// Package mock provides a provider that answers without calling an API, for
+// integration tests and load tests. Replies, usage and latency are
+// deterministic, and calls can be scripted to fail the way real providers
+// do (rate limits, server errors, timeouts, safety blocks), so failover,
+// rate limiting and persistence can be tested end to end without API keys.
+//
+// A call's outcome is taken from the first of:
+//   - a directive in the user input: "[mock:429]" applies to every provider,
+//     "[mock:openai=429]" only to the named one
+//   - Config.Script, cycled through one outcome per call
+//   - Config.ErrorRate, failing that fraction of calls with a 503
+package mock
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/retry"
+)
+
+// Outcomes a call can be scripted to have.
+const (
+	OutcomeOK           = "ok"           // Reply normally
+	OutcomeRateLimit    = "429"          // Fail with a rate limit
+	OutcomeServerError  = "500"          // Fail with an internal server error
+	OutcomeUnavailable  = "503"          // Fail with an overloaded provider
+	OutcomeUnauthorized = "401"          // Fail with a rejected API key
+	OutcomeTimeout      = "timeout"      // Hang until the request is cancelled or Config.Timeout
+	OutcomeSafetyBlock  = "safety_block" // Reply with the response blocked by safety filters
+	OutcomeEmpty        = "empty"        // Reply with no text
+)
+
+var outcomes = map[string]bool{
+	OutcomeOK: true, OutcomeRateLimit: true, OutcomeServerError: true, OutcomeUnavailable: true,
+	OutcomeUnauthorized: true, OutcomeTimeout: true, OutcomeSafetyBlock: true, OutcomeEmpty: true,
+}
+
+// DefaultModel is reported when neither the tenant nor the request sets a model.
+const DefaultModel = "mock-model"
+
+// defaultTimeout is how long a timeout outcome hangs without a deadline.
+const defaultTimeout = 30 * time.Second
+
+// directivePattern matches "[mock:<outcome>]" and "[mock:<provider>=<outcome>]".
+var directivePattern = regexp.MustCompile(`\[mock:(?:([a-z0-9_-]+)=)?([a-z0-9_]+)\]`)
+
+// Config describes how a mock provider answers.
+type Config struct {
+	Latency       time.Duration // Before a reply, or before the first streamed chunk
+	Chunks        int           // Text chunks per streamed reply; 0 streams one word per chunk
+	ChunkInterval time.Duration // Between streamed chunks
+	ErrorRate     float64       // Fraction of calls failing with a 503, spread evenly, 0-1
+	Script        []string      // Outcomes cycled through, one per call
+	Timeout       time.Duration // How long a timeout outcome hangs without a deadline (default 30s)
+}
+
+// Validate checks the error rate and scripted outcomes.
+func (c Config) Validate() error {
+	if c.ErrorRate < 0 || c.ErrorRate > 1 {
+		return fmt.Errorf("error rate must be between 0 and 1, got %v", c.ErrorRate)
+	}
+	for _, o := range c.Script {
+		if !outcomes[o] {
+			return fmt.Errorf("unknown outcome %q", o)
+		}
+	}
+	return nil
+}
+
+// Client implements the provider.Provider interface with scripted replies.
+// It is safe for concurrent use.
+type Client struct {
+	name  string
+	cfg   Config
+	calls atomic.Int64
+}
+
+// NewClient creates a mock provider reporting name, typically one of the
+// provider.Name constants so tenant configuration selects it as that
+// provider.
+func NewClient(name string, cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Client{name: name, cfg: cfg}
+}
+
+func (c *Client) Name() string                   { return c.name }
+func (c *Client) SupportsFileSearch() bool       { return false }
+func (c *Client) SupportsWebSearch() bool        { return false }
+func (c *Client) SupportsNativeContinuity() bool { return false }
+func (c *Client) SupportsStreaming() bool        { return true }
+
+// Calls returns the number of calls made so far.
+func (c *Client) Calls() int {
+	return int(c.calls.Load())
+}
+
+// outcome returns the outcome of call n with params.
+func (c *Client) outcome(n int, params provider.GenerateParams) string {
+	for _, m := range directivePattern.FindAllStringSubmatch(params.UserInput, -1) {
+		if (m[1] == "" || m[1] == c.name) && outcomes[m[2]] {
+			return m[2]
+		}
+	}
+	if len(c.cfg.Script) > 0 {
+		return c.cfg.Script[n%len(c.cfg.Script)]
+	}
+	if math.Floor(float64(n+1)*c.cfg.ErrorRate) > math.Floor(float64(n)*c.cfg.ErrorRate) {
+		return OutcomeUnavailable
+	}
+	return OutcomeOK
+}
+
+// fail returns the error of a failing outcome, waiting out a timeout, or nil
+// if the outcome is not a failure.
+func (c *Client) fail(ctx context.Context, outcome string) error {
+	switch outcome {
+	case OutcomeRateLimit:
+		return fmt.Errorf("mock %s: 429 Too Many Requests: rate limit exceeded", c.name)
+	case OutcomeServerError:
+		return fmt.Errorf("mock %s: 500 Internal Server Error: server_error", c.name)
+	case OutcomeUnavailable:
+		return fmt.Errorf("mock %s: 503 Service Unavailable: overloaded", c.name)
+	case OutcomeUnauthorized:
+		return fmt.Errorf("mock %s: 401 Unauthorized: invalid_api_key", c.name)
+	case OutcomeTimeout:
+		if err := sleep(ctx, c.cfg.Timeout); err != nil {
+			return err
+		}
+		return fmt.Errorf("mock %s: request timeout after %s", c.name, c.cfg.Timeout)
+	}
+	return nil
+}
+
+// reply returns the reply to params: the provider and model, and the start
+// of the user input, so tests can tell which provider answered what.
+func (c *Client) reply(params provider.GenerateParams, model string) string {
+	input := strings.Join(strings.Fields(params.UserInput), " ")
+	if len(input) > 100 {
+		input = input[:100]
+	}
+	return fmt.Sprintf("Mock reply from %s (%s) to: %s", c.name, model, input)
+}
+
+// usage counts about four characters per token, like the estimates of
+// providers that report usage late.
+func usage(params provider.GenerateParams, text string) *provider.Usage {
+	chars := len(params.Instructions) + len(params.UserInput)
+	for _, m := range params.ConversationHistory {
+		chars += len(m.Content)
+	}
+	in := int64((chars + 3) / 4)
+	out := int64((len(text) + 3) / 4)
+	return &provider.Usage{InputTokens: in, OutputTokens: out, TotalTokens: in + out}
+}
+
+// result returns the result of a successful outcome.
+func (c *Client) result(params provider.GenerateParams, outcome string) provider.GenerateResult {
+	model := provider.SelectModel(params.Config.Model, DefaultModel, params.OverrideModel)
+	result := provider.GenerateResult{Model: model, SystemPrompt: params.Instructions, UserPrompt: params.UserInput}
+	switch outcome {
+	case OutcomeSafetyBlock:
+		result.SafetyBlock = &provider.SafetyBlock{Stage: "response", Category: provider.HarmDangerousContent, Reason: "mock safety block"}
+	case OutcomeOK:
+		result.Text = c.reply(params, model)
+	}
+	result.Usage = usage(params, result.Text)
+	return result
+}
+
+// GenerateReply answers after the configured latency.
+func (c *Client) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	outcome := c.outcome(int(c.calls.Add(1)-1), params)
+	if err := sleep(ctx, c.cfg.Latency); err != nil {
+		return provider.GenerateResult{}, err
+	}
+	if err := c.fail(ctx, outcome); err != nil {
+		return provider.GenerateResult{}, err
+	}
+	return c.result(params, outcome), nil
+}
+
+// GenerateReplyStream streams the reply in chunks after the configured
+// latency. Failures are sent as an error chunk.
+func (c *Client) GenerateReplyStream(ctx context.Context, params provider.GenerateParams) (<-chan provider.StreamChunk, error) {
+	outcome := c.outcome(int(c.calls.Add(1)-1), params)
+	ch := make(chan provider.StreamChunk)
+	go func() {
+		defer close(ch)
+		send := func(chunk provider.StreamChunk) bool {
+			select {
+			case ch <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if sleep(ctx, c.cfg.Latency) != nil {
+			return
+		}
+		if err := c.fail(ctx, outcome); err != nil {
+			if ctx.Err() == nil {
+				send(provider.StreamChunk{Type: provider.ChunkTypeError, Error: err, Retryable: retry.IsRetryable(err)})
+			}
+			return
+		}
+		result := c.result(params, outcome)
+		for i, text := range split(result.Text, c.cfg.Chunks) {
+			if i > 0 && sleep(ctx, c.cfg.ChunkInterval) != nil {
+				return
+			}
+			if !send(provider.StreamChunk{Type: provider.ChunkTypeText, Text: text, Index: i}) {
+				return
+			}
+		}
+		send(provider.StreamChunk{
+			Type:         provider.ChunkTypeComplete,
+			Model:        result.Model,
+			Usage:        result.Usage,
+			SafetyBlock:  result.SafetyBlock,
+			SystemPrompt: result.SystemPrompt,
+			UserPrompt:   result.UserPrompt,
+		})
+	}()
+	return ch, nil
+}
+
+// split splits text into n chunks of whole words, or one chunk per word if
+// n is 0.
+func split(text string, n int) []string {
+	if text == "" {
+		return nil
+	}
+	words := strings.SplitAfter(text, " ")
+	if n <= 0 || n >= len(words) {
+		return words
+	}
+	chunks := make([]string, n)
+	for i := range chunks {
+		chunks[i] = strings.Join(words[i*len(words)/n:(i+1)*len(words)/n], "")
+	}
+	return chunks
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,164 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/retry"
+)
+
+func TestGenerateReply_Deterministic(t *testing.T) {
+	c := NewClient(provider.NameOpenAI, Config{})
+	params := provider.GenerateParams{
+		Instructions: "Be brief.",
+		UserInput:    "What is   the capital of France?",
+		Config:       provider.ProviderConfig{Model: "gpt-test"},
+	}
+
+	first, err := c.GenerateReply(context.Background(), params)
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	second, _ := c.GenerateReply(context.Background(), params)
+	if first.Text != second.Text || *first.Usage != *second.Usage {
+		t.Errorf("replies differ: %+v vs %+v", first, second)
+	}
+	if first.Text != "Mock reply from openai (gpt-test) to: What is the capital of France?" {
+		t.Errorf("Text = %q", first.Text)
+	}
+	if first.Model != "gpt-test" {
+		t.Errorf("Model = %q, want gpt-test", first.Model)
+	}
+	if first.Usage.InputTokens != 11 || first.Usage.TotalTokens != first.Usage.InputTokens+first.Usage.OutputTokens {
+		t.Errorf("Usage = %+v", first.Usage)
+	}
+	if c.Calls() != 2 {
+		t.Errorf("Calls() = %d, want 2", c.Calls())
+	}
+}
+
+func TestGenerateReply_Script(t *testing.T) {
+	c := NewClient(provider.NameGemini, Config{Script: []string{OutcomeRateLimit, OutcomeOK, OutcomeSafetyBlock, OutcomeEmpty}})
+	ctx := context.Background()
+	params := provider.GenerateParams{UserInput: "hello"}
+
+	_, err := c.GenerateReply(ctx, params)
+	if !retry.IsRateLimited(err) || !retry.IsRetryable(err) {
+		t.Errorf("call 1: err = %v, want a retryable rate limit", err)
+	}
+	if result, err := c.GenerateReply(ctx, params); err != nil || result.Text == "" {
+		t.Errorf("call 2: result = %+v, err = %v, want a reply", result, err)
+	}
+	if result, err := c.GenerateReply(ctx, params); err != nil || result.SafetyBlock == nil || result.Text != "" {
+		t.Errorf("call 3: result = %+v, err = %v, want a safety block", result, err)
+	}
+	if result, err := c.GenerateReply(ctx, params); err != nil || result.Text != "" || result.SafetyBlock != nil {
+		t.Errorf("call 4: result = %+v, err = %v, want an empty reply", result, err)
+	}
+	if _, err := c.GenerateReply(ctx, params); !retry.IsRateLimited(err) {
+		t.Errorf("call 5: err = %v, want the script to repeat", err)
+	}
+}
+
+func TestGenerateReply_Directive(t *testing.T) {
+	openai := NewClient(provider.NameOpenAI, Config{})
+	gemini := NewClient(provider.NameGemini, Config{})
+	ctx := context.Background()
+
+	params := provider.GenerateParams{UserInput: "hi [mock:openai=500]"}
+	if _, err := openai.GenerateReply(ctx, params); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("openai: err = %v, want a 500", err)
+	}
+	if _, err := gemini.GenerateReply(ctx, params); err != nil {
+		t.Errorf("gemini: err = %v, want the directive to apply only to openai", err)
+	}
+
+	params.UserInput = "hi [mock:401]"
+	_, err := gemini.GenerateReply(ctx, params)
+	if err == nil || retry.IsRetryable(err) {
+		t.Errorf("gemini: err = %v, want a non-retryable auth failure", err)
+	}
+}
+
+func TestGenerateReply_Timeout(t *testing.T) {
+	c := NewClient(provider.NameAnthropic, Config{Script: []string{OutcomeTimeout}, Timeout: 10 * time.Millisecond})
+
+	_, err := c.GenerateReply(context.Background(), provider.GenerateParams{})
+	if err == nil || !retry.IsRetryable(err) {
+		t.Errorf("err = %v, want a retryable timeout", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	c = NewClient(provider.NameAnthropic, Config{Script: []string{OutcomeTimeout}})
+	if _, err := c.GenerateReply(ctx, provider.GenerateParams{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the request deadline", err)
+	}
+}
+
+func TestGenerateReply_ErrorRate(t *testing.T) {
+	c := NewClient(provider.NameOpenAI, Config{ErrorRate: 0.25})
+	var failed int
+	for range 20 {
+		if _, err := c.GenerateReply(context.Background(), provider.GenerateParams{}); err != nil {
+			failed++
+		}
+	}
+	if failed != 5 {
+		t.Errorf("failed = %d, want 5", failed)
+	}
+}
+
+func TestGenerateReplyStream(t *testing.T) {
+	c := NewClient(provider.NameOpenAI, Config{Chunks: 3, Script: []string{OutcomeOK, OutcomeServerError}})
+	params := provider.GenerateParams{UserInput: "stream this please"}
+
+	ch, err := c.GenerateReplyStream(context.Background(), params)
+	if err != nil {
+		t.Fatalf("GenerateReplyStream failed: %v", err)
+	}
+	var text strings.Builder
+	var texts int
+	var complete *provider.StreamChunk
+	for chunk := range ch {
+		switch chunk.Type {
+		case provider.ChunkTypeText:
+			texts++
+			text.WriteString(chunk.Text)
+		case provider.ChunkTypeComplete:
+			complete = &chunk
+		}
+	}
+	want, _ := NewClient(provider.NameOpenAI, Config{}).GenerateReply(context.Background(), params)
+	if texts != 3 || text.String() != want.Text {
+		t.Errorf("streamed %d chunks %q, want 3 chunks of %q", texts, text.String(), want.Text)
+	}
+	if complete == nil || complete.Usage == nil || complete.Model != DefaultModel {
+		t.Errorf("complete chunk = %+v", complete)
+	}
+
+	ch, _ = c.GenerateReplyStream(context.Background(), params)
+	var chunks []provider.StreamChunk
+	for chunk := range ch {
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) != 1 || chunks[0].Type != provider.ChunkTypeError || !chunks[0].Retryable {
+		t.Errorf("chunks = %+v, want one retryable error", chunks)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := (Config{Script: []string{OutcomeOK, "418"}}).Validate(); err == nil {
+		t.Error("expected error for unknown outcome")
+	}
+	if err := (Config{ErrorRate: 1.5}).Validate(); err == nil {
+		t.Error("expected error for error rate above 1")
+	}
+	if err := (Config{ErrorRate: 0.5, Script: []string{OutcomeTimeout}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
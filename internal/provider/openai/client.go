@@ -3,6 +3,7 @@ package openai
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -23,8 +24,9 @@ import (
 )
 
 const (
-	pollInitial = 500 * time.Millisecond
-	pollMax     = 5 * time.Second
+	pollInitial  = 500 * time.Millisecond
+	pollMax      = 5 * time.Second
+	defaultModel = "gpt-4o"
 )
 
 // citationMarkerPattern matches OpenAI's inline file citation markers like "fileciteturn2file0"
@@ -81,6 +83,21 @@ func (c *Client) SupportsStreaming() bool {
 	return true
 }
 
+// SupportsCodeExecution returns true as OpenAI supports the code interpreter tool.
+func (c *Client) SupportsCodeExecution() bool {
+	return true
+}
+
+// SupportsComputerUse returns true as OpenAI supports the computer_use_preview tool.
+func (c *Client) SupportsComputerUse() bool {
+	return true
+}
+
+// DefaultModel returns the model used when none is configured.
+func (c *Client) DefaultModel() string {
+	return defaultModel
+}
+
 // GenerateReply implements provider.Provider using OpenAI's Responses API.
 func (c *Client) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
 	// Ensure request has a timeout
@@ -93,10 +110,10 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		return provider.GenerateResult{}, errors.New("OpenAI API key is required")
 	}
 
-	model := provider.SelectModel(cfg.Model, "gpt-4o", params.OverrideModel)
+	model := provider.SelectModel(cfg.Model, defaultModel, params.OverrideModel)
 
 	// Create captured client config with validation
-	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL)
+	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL, cfg.Proxy)
 	if err != nil {
 		return provider.GenerateResult{}, fmt.Errorf("client setup: %w", err)
 	}
@@ -113,15 +130,30 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	client := openai.NewClient(clientOpts...)
 	capture := httpCfg.Capture
 
-	// Build user prompt from input and history
-	userPrompt := buildUserPrompt(params.UserInput, params.ConversationHistory)
+	// Build multi-turn input from history and current input
+	userInput, attachments := provider.InlineTextAttachments(params.UserInput, params.Attachments)
+	input, err := attachInput(buildInput(userInput, params.ConversationHistory, params.PreviousResponseID, params.ToolResults), attachments, params.InlineImages)
+	if err != nil {
+		return provider.GenerateResult{}, err
+	}
+	userPrompt := strings.TrimSpace(params.UserInput)
+
+	// Apply safety thresholds as a moderation check on the prompt
+	block, err := moderatePrompt(ctx, client, cfg.Safety, userPrompt)
+	if err != nil {
+		return provider.GenerateResult{}, err
+	}
+	if block != nil {
+		slog.WarnContext(ctx, "openai prompt blocked by moderation", "model", model, "category", block.Category)
+		return provider.GenerateResult{Model: model, SafetyBlock: block}, nil
+	}
 
 	// Build request
 	req := responses.ResponseNewParams{
 		Model:        shared.ResponsesModel(model),
 		Instructions: openai.String(params.Instructions),
 		Input: responses.ResponseNewParamsInputUnion{
-			OfString: openai.String(userPrompt),
+			OfInputItemList: input,
 		},
 		Background: openai.Bool(true),
 	}
@@ -198,11 +230,17 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 				},
 			},
 		})
+		// Outputs, such as the URLs of generated images, are only returned on request
+		req.Include = append(req.Include, responses.ResponseIncludableCodeInterpreterCallOutputs)
 	}
 	// Add custom function tools
 	for _, tool := range params.Tools {
 		tools = append(tools, buildFunctionTool(tool))
 	}
+	if params.ComputerUse != nil {
+		tools = append(tools, buildComputerTool(params.ComputerUse))
+		req.Truncation = responses.ResponseNewParamsTruncationAuto // Required by computer use
+	}
 	if len(tools) > 0 {
 		req.Tools = tools
 	}
@@ -213,7 +251,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	}
 
 	if c.debug {
-		slog.Debug("openai request",
+		slog.DebugContext(ctx, "openai request",
 			"model", model,
 			"override_model", params.OverrideModel,
 			"file_store_id", params.FileStoreID,
@@ -224,7 +262,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 	// Execute with retry
 	var lastErr error
 	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
-		slog.Info("openai request",
+		slog.InfoContext(ctx, "openai request",
 			"attempt", attempt,
 			"model", model,
 			"request_id", params.RequestID,
@@ -238,7 +276,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			// Check if parent context is still valid
 			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
 				lastErr = fmt.Errorf("openai request timeout: %w", err)
-				slog.Warn("openai timeout, retrying", "attempt", attempt)
+				slog.WarnContext(ctx, "openai timeout, retrying", "attempt", attempt)
 				if attempt < retry.MaxAttempts {
 					retry.SleepWithBackoff(ctx, attempt)
 					continue
@@ -251,7 +289,7 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 				return provider.GenerateResult{}, lastErr
 			}
 
-			slog.Warn("openai retryable error", "attempt", attempt, "error", err)
+			slog.WarnContext(ctx, "openai retryable error", "attempt", attempt, "error", err)
 			if attempt < retry.MaxAttempts {
 				retry.SleepWithBackoff(ctx, attempt)
 				continue
@@ -263,12 +301,14 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		resp, err = waitForCompletion(ctx, client, resp)
 		if err != nil {
 			lastErr = err
-			slog.Warn("openai wait error", "attempt", attempt, "error", err)
+			slog.WarnContext(ctx, "openai wait error", "attempt", attempt, "error", err)
 			continue
 		}
 
+		toolCalls := extractToolCalls(resp)
+		computerActions := extractComputerActions(resp)
 		text := strings.TrimSpace(resp.OutputText())
-		if text == "" {
+		if text == "" && len(toolCalls) == 0 && len(computerActions) == 0 {
 			lastErr = errors.New("openai returned empty response")
 			continue
 		}
@@ -277,15 +317,15 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 		text = stripCitationMarkers(text)
 
 		citations := extractCitations(resp, params.FileIDToFilename)
-		toolCalls := extractToolCalls(resp)
 		codeExecutions := extractCodeExecutions(resp)
 
-		slog.Info("openai request completed",
+		slog.InfoContext(ctx, "openai request completed",
 			"response_id", resp.ID,
 			"model", model,
 			"tokens_in", resp.Usage.InputTokens,
 			"tokens_out", resp.Usage.OutputTokens,
 			"tool_calls", len(toolCalls),
+			"computer_actions", len(computerActions),
 			"code_executions", len(codeExecutions),
 		)
 
@@ -306,8 +346,11 @@ func (c *Client) GenerateReply(ctx context.Context, params provider.GeneratePara
 			Citations:          citations,
 			Model:              model,
 			ToolCalls:          toolCalls,
-			RequiresToolOutput: len(toolCalls) > 0,
+			RequiresToolOutput: len(toolCalls) > 0 || len(computerActions) > 0,
+			ComputerActions:    computerActions,
 			CodeExecutions:     codeExecutions,
+			SystemPrompt:       params.Instructions,
+			UserPrompt:         userPrompt,
 			RequestJSON:        reqJSON,
 			ResponseJSON:       respJSON,
 		}, nil
@@ -328,10 +371,10 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		return nil, errors.New("OpenAI API key is required")
 	}
 
-	model := provider.SelectModel(cfg.Model, "gpt-4o", params.OverrideModel)
+	model := provider.SelectModel(cfg.Model, defaultModel, params.OverrideModel)
 
 	// Create captured client config with validation
-	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL)
+	httpCfg, err := httputil.NewCapturedClientConfig(cfg.APIKey, cfg.BaseURL, cfg.Proxy)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("client setup: %w", err)
@@ -348,15 +391,36 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 
 	client := openai.NewClient(clientOpts...)
 
-	// Build user prompt from input and history
-	userPrompt := buildUserPrompt(params.UserInput, params.ConversationHistory)
+	// Build multi-turn input from history and current input
+	userInput, attachments := provider.InlineTextAttachments(params.UserInput, params.Attachments)
+	input, err := attachInput(buildInput(userInput, params.ConversationHistory, params.PreviousResponseID, params.ToolResults), attachments, params.InlineImages)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	userPrompt := strings.TrimSpace(params.UserInput)
+
+	// Apply safety thresholds as a moderation check on the prompt
+	block, err := moderatePrompt(ctx, client, cfg.Safety, userPrompt)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if block != nil {
+		cancel()
+		slog.WarnContext(ctx, "openai prompt blocked by moderation", "model", model, "category", block.Category)
+		ch := make(chan provider.StreamChunk, 1)
+		ch <- provider.StreamChunk{Type: provider.ChunkTypeComplete, Model: model, SafetyBlock: block}
+		close(ch)
+		return ch, nil
+	}
 
 	// Build request (same as non-streaming)
 	req := responses.ResponseNewParams{
 		Model:        shared.ResponsesModel(model),
 		Instructions: openai.String(params.Instructions),
 		Input: responses.ResponseNewParamsInputUnion{
-			OfString: openai.String(userPrompt),
+			OfInputItemList: input,
 		},
 		Background: openai.Bool(true),
 	}
@@ -433,10 +497,16 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 				},
 			},
 		})
+		// Outputs, such as the URLs of generated images, are only returned on request
+		req.Include = append(req.Include, responses.ResponseIncludableCodeInterpreterCallOutputs)
 	}
 	for _, tool := range params.Tools {
 		tools = append(tools, buildFunctionTool(tool))
 	}
+	if params.ComputerUse != nil {
+		tools = append(tools, buildComputerTool(params.ComputerUse))
+		req.Truncation = responses.ResponseNewParamsTruncationAuto
+	}
 	if len(tools) > 0 {
 		req.Tools = tools
 	}
@@ -460,9 +530,13 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 		var responseID string
 		var totalText strings.Builder
 		var toolCalls []provider.ToolCall
+		var computerActions []provider.ComputerAction
 		var codeExecutions []provider.CodeExecutionResult
-		// Track function names by item ID (needed because done event doesn't include name)
-		functionNames := make(map[string]string)
+		// Track function calls by item ID (needed because done event doesn't include name or call_id)
+		functionCalls := make(map[string]responses.ResponseFunctionToolCall)
+		// The Responses API reports usage only on completion, so running
+		// usage is estimated from the streamed text
+		usageTracker := provider.NewUsageTracker()
 
 		for stream.Next() {
 			event := stream.Current()
@@ -480,7 +554,18 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 				added := event.AsResponseOutputItemAdded()
 				if added.Item.Type == "function_call" {
 					fc := added.Item.AsFunctionCall()
-					functionNames[fc.ID] = fc.Name
+					functionCalls[fc.ID] = fc
+				}
+
+			case "response.output_item.done":
+				done := event.AsResponseOutputItemDone()
+				if done.Item.Type == "computer_call" {
+					action := computerAction(done.Item.AsComputerCall())
+					computerActions = append(computerActions, action)
+					ch <- provider.StreamChunk{
+						Type:           provider.ChunkTypeComputerAction,
+						ComputerAction: &action,
+					}
 				}
 
 			case "response.output_text.delta":
@@ -491,14 +576,17 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 						Text: delta.Delta,
 					}
 					totalText.WriteString(delta.Delta)
+					if chunk, ok := usageTracker.Update(provider.EstimateStreamUsage(totalText.Len()), model, true); ok {
+						ch <- chunk
+					}
 				}
 
 			case "response.function_call_arguments.done":
 				fc := event.AsResponseFunctionCallArgumentsDone()
-				name := functionNames[fc.ItemID] // Look up name from when item was added
+				added := functionCalls[fc.ItemID] // Look up name and call_id from when item was added
 				toolCall := provider.ToolCall{
-					ID:        fc.ItemID,
-					Name:      name,
+					ID:        added.CallID,
+					Name:      added.Name,
 					Arguments: fc.Arguments,
 				}
 				toolCalls = append(toolCalls, toolCall)
@@ -529,6 +617,13 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 				if completed.Response.ID != "" {
 					responseID = completed.Response.ID
 				}
+				// Parallel calls can finish out of order; report them in output order
+				if calls := extractToolCalls(&completed.Response); len(calls) > 0 {
+					toolCalls = calls
+				}
+				if actions := extractComputerActions(&completed.Response); len(actions) > 0 {
+					computerActions = actions
+				}
 
 				var usage *provider.Usage
 				if completed.Response.Usage.TotalTokens > 0 {
@@ -545,8 +640,11 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 					Model:              model,
 					Usage:              usage,
 					ToolCalls:          toolCalls,
-					RequiresToolOutput: len(toolCalls) > 0,
+					RequiresToolOutput: len(toolCalls) > 0 || len(computerActions) > 0,
+					ComputerActions:    computerActions,
 					CodeExecutions:     codeExecutions,
+					SystemPrompt:       params.Instructions,
+					UserPrompt:         userPrompt,
 				}
 			}
 		}
@@ -563,24 +661,115 @@ func (c *Client) GenerateReplyStream(ctx context.Context, params provider.Genera
 	return ch, nil
 }
 
-// buildUserPrompt constructs the user prompt from input and history.
-func buildUserPrompt(userInput string, history []provider.Message) string {
-	var sb strings.Builder
+// buildInput adapts the conversation to Responses API input items. A request
+// that continues a stored response already has its history on the server,
+// so only the tool outputs and the new message are sent. Results carrying a
+// screenshot answer computer actions.
+func buildInput(userInput string, history []provider.Message, previousResponseID string, toolResults []provider.ToolResult) responses.ResponseInputParam {
+	if strings.TrimSpace(previousResponseID) != "" {
+		history = nil
+	}
 
-	if len(history) > 0 {
-		sb.WriteString("Previous conversation:\n\n")
-		for _, msg := range history {
-			role := "User"
-			if msg.Role == "assistant" {
-				role = "Assistant"
-			}
-			sb.WriteString(fmt.Sprintf("%s: %s\n\n", role, msg.Content))
+	var input responses.ResponseInputParam
+	for _, result := range toolResults {
+		if len(result.Screenshot) > 0 {
+			input = append(input, computerCallOutput(result))
+			continue
+		}
+		output := result.Output
+		if result.IsError {
+			output = "Error: " + output
 		}
-		sb.WriteString("---\n\nNew message:\n\n")
+		input = append(input, responses.ResponseInputItemParamOfFunctionCallOutput(result.ToolCallID, output))
+	}
+	if len(toolResults) > 0 && strings.TrimSpace(userInput) == "" {
+		return input // Tool outputs alone continue the turn
 	}
 
-	sb.WriteString(strings.TrimSpace(userInput))
-	return strings.TrimSpace(sb.String())
+	for _, turn := range provider.BuildConversation(userInput, history) {
+		role := responses.EasyInputMessageRoleUser
+		if turn.Role == provider.RoleAssistant {
+			role = responses.EasyInputMessageRoleAssistant
+		}
+		input = append(input, responses.ResponseInputItemParamOfMessage(turn.Text, role))
+	}
+	return input
+}
+
+// attachmentImageTypes are the image types OpenAI reads.
+var attachmentImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// SupportsAttachment reports whether OpenAI file inputs read mimeType:
+// images and PDFs.
+func (c *Client) SupportsAttachment(mimeType string) bool {
+	return attachmentImageTypes[mimeType] || mimeType == "application/pdf"
+}
+
+// SupportsImageInput reports whether img can be sent as input_image
+// content: by URL, including data URLs, or by file ID.
+func (c *Client) SupportsImageInput(img provider.InlineImage) bool {
+	return img.URI != "" || img.FileID != ""
+}
+
+// imageDetail maps an image's detail level to OpenAI's.
+func imageDetail(detail string) responses.ResponseInputImageDetail {
+	switch detail {
+	case "low":
+		return responses.ResponseInputImageDetailLow
+	case "high":
+		return responses.ResponseInputImageDetailHigh
+	default:
+		return responses.ResponseInputImageDetailAuto
+	}
+}
+
+// attachInput adds attachments and images to the final user message of
+// input: image attachments and images as input_image content, by URL or
+// file ID for images, and PDFs as input_file content. Attachments are sent
+// inline.
+func attachInput(input responses.ResponseInputParam, attachments []provider.Attachment, images []provider.InlineImage) (responses.ResponseInputParam, error) {
+	if len(attachments) == 0 && len(images) == 0 {
+		return input, nil
+	}
+	if len(input) == 0 || input[len(input)-1].OfMessage == nil {
+		return nil, errors.New("attachments require user input")
+	}
+	msg := input[len(input)-1].OfMessage
+	content := responses.ResponseInputMessageContentListParam{
+		responses.ResponseInputContentParamOfInputText(msg.Content.OfString.Value),
+	}
+	for _, a := range attachments {
+		switch {
+		case attachmentImageTypes[a.MIMEType]:
+			content = append(content, responses.ResponseInputContentUnionParam{OfInputImage: &responses.ResponseInputImageParam{
+				Detail:   responses.ResponseInputImageDetailAuto,
+				ImageURL: openai.String(a.DataURL()),
+			}})
+		case a.IsPDF():
+			content = append(content, responses.ResponseInputContentUnionParam{OfInputFile: &responses.ResponseInputFileParam{
+				Filename: openai.String(a.Filename),
+				FileData: openai.String(a.DataURL()),
+			}})
+		default:
+			return nil, &provider.UnsupportedAttachmentError{Provider: "openai", Filename: a.Filename, MIMEType: a.MIMEType}
+		}
+	}
+	for _, img := range images {
+		part := &responses.ResponseInputImageParam{Detail: imageDetail(img.Detail)}
+		if img.FileID != "" {
+			part.FileID = openai.String(img.FileID)
+		} else {
+			part.ImageURL = openai.String(img.URI)
+		}
+		content = append(content, responses.ResponseInputContentUnionParam{OfInputImage: part})
+	}
+	input[len(input)-1] = responses.ResponseInputItemParamOfMessage(content, msg.Role)
+	return input, nil
 }
 
 // waitForCompletion polls until the response is complete.
@@ -602,7 +791,7 @@ func waitForCompletion(ctx context.Context, client openai.Client, resp *response
 
 		updated, err := client.Responses.Get(ctx, resp.ID, responses.ResponseGetParams{})
 		if err != nil {
-			slog.Warn("response poll error", "error", err)
+			slog.WarnContext(ctx, "response poll error", "error", err)
 			continue
 		}
 
@@ -754,11 +943,12 @@ func extractToolCalls(resp *responses.Response) []provider.ToolCall {
 	for _, item := range resp.Output {
 		if item.Type == "function_call" {
 			fc := item.AsFunctionCall()
-			if fc.ID == "" {
+			if fc.CallID == "" {
 				continue
 			}
+			// Outputs are matched by call_id, not the item ID
 			toolCalls = append(toolCalls, provider.ToolCall{
-				ID:        fc.ID,
+				ID:        fc.CallID,
 				Name:      fc.Name,
 				Arguments: fc.Arguments,
 			})
@@ -793,11 +983,7 @@ func extractCodeExecutions(resp *responses.Response) []provider.CodeExecutionRes
 				case "logs":
 					exec.Stdout = output.Logs
 				case "image":
-					// Image outputs have a URL
-					exec.Files = append(exec.Files, provider.GeneratedFile{
-						Name:     "output.png",
-						MIMEType: "image/png",
-					})
+					exec.Files = append(exec.Files, generatedImageFile(output.URL, len(exec.Files)+1))
 				}
 			}
 
@@ -807,3 +993,27 @@ func extractCodeExecutions(resp *responses.Response) []provider.CodeExecutionRes
 
 	return executions
 }
+
+// generatedImageFile returns the n-th image a code interpreter call
+// generated. Images in data URLs are decoded; others are returned as links.
+func generatedImageFile(url string, n int) provider.GeneratedFile {
+	if mediaType, data, ok := strings.Cut(url, ";base64,"); ok && strings.HasPrefix(mediaType, "data:image/") {
+		if content, err := base64.StdEncoding.DecodeString(data); err == nil {
+			mediaType = strings.TrimPrefix(mediaType, "data:")
+			ext := strings.TrimPrefix(mediaType, "image/")
+			if ext == "jpeg" {
+				ext = "jpg"
+			}
+			return provider.GeneratedFile{
+				Name:     fmt.Sprintf("output_%d.%s", n, ext),
+				MIMEType: mediaType,
+				Content:  content,
+			}
+		}
+	}
+	return provider.GeneratedFile{
+		Name:     fmt.Sprintf("output_%d.png", n),
+		MIMEType: "image/png",
+		URL:      url,
+	}
+}
@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -13,23 +14,69 @@ import (
 	"github.com/ai8future/airborne/internal/retry"
 )
 
-func TestBuildUserPrompt_NoHistory(t *testing.T) {
-	got := buildUserPrompt("  hello  ", nil)
-	if got != "hello" {
-		t.Fatalf("buildUserPrompt() = %q, want %q", got, "hello")
+func TestBuildInput_NoHistory(t *testing.T) {
+	input := buildInput("  hello  ", nil, "", nil)
+	if len(input) != 1 {
+		t.Fatalf("expected 1 input item, got %d", len(input))
+	}
+	msg := input[0].OfMessage
+	if msg == nil || msg.Role != responses.EasyInputMessageRoleUser || msg.Content.OfString.Value != "hello" {
+		t.Fatalf("unexpected input item: %+v", input[0])
 	}
 }
 
-func TestBuildUserPrompt_WithHistory(t *testing.T) {
+func TestBuildInput_WithHistory(t *testing.T) {
 	history := []provider.Message{
 		{Role: "user", Content: "Hello"},
 		{Role: "assistant", Content: "Hi there"},
 	}
 
-	got := buildUserPrompt("  How are you?  ", history)
-	want := "Previous conversation:\n\nUser: Hello\n\nAssistant: Hi there\n\n---\n\nNew message:\n\nHow are you?"
-	if got != want {
-		t.Fatalf("buildUserPrompt() = %q, want %q", got, want)
+	input := buildInput("  How are you?  ", history, "", nil)
+	wantRoles := []responses.EasyInputMessageRole{
+		responses.EasyInputMessageRoleUser,
+		responses.EasyInputMessageRoleAssistant,
+		responses.EasyInputMessageRoleUser,
+	}
+	wantText := []string{"Hello", "Hi there", "How are you?"}
+	if len(input) != len(wantRoles) {
+		t.Fatalf("expected %d input items, got %d", len(wantRoles), len(input))
+	}
+	for i, item := range input {
+		if item.OfMessage == nil || item.OfMessage.Role != wantRoles[i] || item.OfMessage.Content.OfString.Value != wantText[i] {
+			t.Errorf("item %d = %+v, want %s %q", i, item.OfMessage, wantRoles[i], wantText[i])
+		}
+	}
+}
+
+func TestBuildInput_PreviousResponseSkipsHistory(t *testing.T) {
+	history := []provider.Message{{Role: "user", Content: "Hello"}}
+
+	input := buildInput("Next", history, "resp_123", nil)
+	if len(input) != 1 || input[0].OfMessage.Content.OfString.Value != "Next" {
+		t.Fatalf("expected only the new message, got %+v", input)
+	}
+}
+
+func TestBuildInput_ToolResults(t *testing.T) {
+	results := []provider.ToolResult{
+		{ToolCallID: "call_1", Output: `{"temp":21}`},
+		{ToolCallID: "call_2", Output: "timeout", IsError: true},
+	}
+
+	input := buildInput("", nil, "resp_123", results)
+	if len(input) != 2 {
+		t.Fatalf("expected only the 2 tool outputs, got %d items", len(input))
+	}
+	if out := input[0].OfFunctionCallOutput; out == nil || out.CallID != "call_1" || out.Output != `{"temp":21}` {
+		t.Errorf("unexpected first output: %+v", input[0])
+	}
+	if out := input[1].OfFunctionCallOutput; out == nil || out.Output != "Error: timeout" {
+		t.Errorf("expected error output to be marked, got %+v", input[1])
+	}
+
+	input = buildInput("Thanks, and tomorrow?", nil, "resp_123", results)
+	if len(input) != 3 || input[2].OfMessage == nil {
+		t.Fatalf("expected tool outputs followed by the new message, got %+v", input)
 	}
 }
 
@@ -260,3 +307,143 @@ func TestNewClientWithDebugLogging(t *testing.T) {
 		t.Error("expected debug to be false")
 	}
 }
+
+func TestExtractToolCalls_ParallelCallsUseCallID(t *testing.T) {
+	var resp responses.Response
+	raw := `{"id":"resp_1","output":[
+		{"type":"function_call","id":"fc_1","call_id":"call_a","name":"weather","arguments":"{\"city\":\"Paris\"}"},
+		{"type":"function_call","id":"fc_2","call_id":"call_b","name":"weather","arguments":"{\"city\":\"Rome\"}"}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	calls := extractToolCalls(&resp)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(calls))
+	}
+	if calls[0].ID != "call_a" || calls[1].ID != "call_b" {
+		t.Errorf("expected call_ids in output order, got %q, %q", calls[0].ID, calls[1].ID)
+	}
+	if calls[1].Arguments != `{"city":"Rome"}` {
+		t.Errorf("unexpected arguments: %s", calls[1].Arguments)
+	}
+}
+
+func TestExtractCodeExecutions_Images(t *testing.T) {
+	var resp responses.Response
+	raw := `{"id":"resp_1","output":[
+		{"type":"code_interpreter_call","id":"ci_1","code":"plot()","container_id":"cntr_1","status":"completed","outputs":[
+			{"type":"logs","logs":"done"},
+			{"type":"image","url":"data:image/jpeg;base64,anBn"},
+			{"type":"image","url":"https://files.example.com/plot.png"}
+		]}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	executions := extractCodeExecutions(&resp)
+	if len(executions) != 1 || executions[0].Stdout != "done" {
+		t.Fatalf("unexpected executions %+v", executions)
+	}
+	files := executions[0].Files
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", files)
+	}
+	if files[0].Name != "output_1.jpg" || files[0].MIMEType != "image/jpeg" || string(files[0].Content) != "jpg" || files[0].URL != "" {
+		t.Errorf("unexpected data URL image %+v", files[0])
+	}
+	if files[1].Name != "output_2.png" || files[1].URL != "https://files.example.com/plot.png" || files[1].Content != nil {
+		t.Errorf("unexpected linked image %+v", files[1])
+	}
+}
+
+func TestExtractComputerActions(t *testing.T) {
+	var resp responses.Response
+	raw := `{"id":"resp_1","output":[
+		{"type":"computer_call","id":"cu_1","call_id":"call_c","status":"completed",
+		 "action":{"type":"click","button":"left","x":10,"y":20},
+		 "pending_safety_checks":[{"id":"sc_1","code":"malicious_instructions","message":"check"}]}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	actions := extractComputerActions(&resp)
+	if len(actions) != 1 {
+		t.Fatalf("expected 1 computer action, got %d", len(actions))
+	}
+	action := actions[0]
+	if action.ID != "call_c" || action.Action != "click" {
+		t.Errorf("unexpected action: %+v", action)
+	}
+	if action.Input != `{"type":"click","button":"left","x":10,"y":20}` {
+		t.Errorf("expected raw action input, got %s", action.Input)
+	}
+	if len(action.PendingSafetyChecks) != 1 || action.PendingSafetyChecks[0].Code != "malicious_instructions" {
+		t.Errorf("unexpected safety checks: %+v", action.PendingSafetyChecks)
+	}
+}
+
+func TestBuildInput_ComputerScreenshot(t *testing.T) {
+	results := []provider.ToolResult{{
+		ToolCallID:               "call_c",
+		Screenshot:               []byte("png"),
+		AcknowledgedSafetyChecks: []provider.ComputerSafetyCheck{{ID: "sc_1", Code: "malicious_instructions"}},
+	}}
+	input := buildInput("", nil, "resp_1", results)
+	if len(input) != 1 {
+		t.Fatalf("expected 1 input item, got %d", len(input))
+	}
+	out := input[0].OfComputerCallOutput
+	if out == nil || out.CallID != "call_c" {
+		t.Fatalf("expected computer_call_output, got %+v", input[0])
+	}
+	if out.Output.ImageURL.Value != "data:image/png;base64,cG5n" {
+		t.Errorf("unexpected screenshot URL: %s", out.Output.ImageURL.Value)
+	}
+	if len(out.AcknowledgedSafetyChecks) != 1 || out.AcknowledgedSafetyChecks[0].ID != "sc_1" {
+		t.Errorf("expected acknowledged safety check, got %+v", out.AcknowledgedSafetyChecks)
+	}
+}
+
+func TestAttachInput(t *testing.T) {
+	attachments := []provider.Attachment{
+		{Filename: "chart.png", MIMEType: "image/png", Content: []byte("png")},
+		{Filename: "report.pdf", MIMEType: "application/pdf", Content: []byte("pdf")},
+	}
+	images := []provider.InlineImage{
+		{URI: "https://example.com/photo.jpg", Detail: "high"},
+		{FileID: "file-abc"},
+	}
+	input, err := attachInput(buildInput("Compare these", nil, "", nil), attachments, images)
+	if err != nil {
+		t.Fatalf("attachInput failed: %v", err)
+	}
+	content := input[len(input)-1].OfMessage.Content.OfInputItemContentList
+	if len(content) != 5 {
+		t.Fatalf("expected text, 2 files and 2 images, got %d parts", len(content))
+	}
+	if content[0].OfInputText == nil || content[0].OfInputText.Text != "Compare these" {
+		t.Errorf("expected the user text first, got %+v", content[0])
+	}
+	if img := content[1].OfInputImage; img == nil || img.ImageURL.Value != "data:image/png;base64,cG5n" {
+		t.Errorf("expected input_image, got %+v", content[1])
+	}
+	if file := content[2].OfInputFile; file == nil || file.Filename.Value != "report.pdf" {
+		t.Errorf("expected input_file, got %+v", content[2])
+	}
+	if img := content[3].OfInputImage; img == nil || img.ImageURL.Value != "https://example.com/photo.jpg" || img.Detail != responses.ResponseInputImageDetailHigh {
+		t.Errorf("expected input_image by URL, got %+v", content[3])
+	}
+	if img := content[4].OfInputImage; img == nil || img.FileID.Value != "file-abc" || img.Detail != responses.ResponseInputImageDetailAuto {
+		t.Errorf("expected input_image by file ID, got %+v", content[4])
+	}
+
+	_, err = attachInput(buildInput("Play this", nil, "", nil), []provider.Attachment{{Filename: "a.mp3", MIMEType: "audio/mpeg"}}, nil)
+	var unsupported *provider.UnsupportedAttachmentError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("expected UnsupportedAttachmentError, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package openai
+
+import (
+	"encoding/base64"
+
+	openai "github.com/openai/openai-go"
+	"github.com/openai/openai-go/responses"
+
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+// defaultScreenshotMIMEType is assumed when a screenshot has no MIME type.
+const defaultScreenshotMIMEType = "image/png"
+
+// buildComputerTool converts the computer-use settings to the
+// computer_use_preview tool.
+func buildComputerTool(cu *provider.ComputerUse) responses.ToolUnionParam {
+	env := responses.ComputerToolEnvironment(cu.Environment)
+	if env == "" {
+		env = responses.ComputerToolEnvironmentBrowser
+	}
+	return responses.ToolParamOfComputerUsePreview(int64(cu.DisplayHeight), int64(cu.DisplayWidth), env)
+}
+
+// computerAction converts a computer_call output item.
+func computerAction(call responses.ResponseComputerToolCall) provider.ComputerAction {
+	action := provider.ComputerAction{
+		ID:     call.CallID,
+		Action: call.Action.Type,
+		Input:  call.Action.RawJSON(),
+	}
+	for _, check := range call.PendingSafetyChecks {
+		action.PendingSafetyChecks = append(action.PendingSafetyChecks, provider.ComputerSafetyCheck{
+			ID:      check.ID,
+			Code:    check.Code,
+			Message: check.Message,
+		})
+	}
+	return action
+}
+
+// extractComputerActions extracts computer_call items from the response.
+func extractComputerActions(resp *responses.Response) []provider.ComputerAction {
+	if resp == nil {
+		return nil
+	}
+	var actions []provider.ComputerAction
+	for _, item := range resp.Output {
+		if item.Type == "computer_call" {
+			actions = append(actions, computerAction(item.AsComputerCall()))
+		}
+	}
+	return actions
+}
+
+// computerCallOutput answers a computer_call with the executor's screenshot.
+func computerCallOutput(result provider.ToolResult) responses.ResponseInputItemUnionParam {
+	mimeType := result.ScreenshotMIMEType
+	if mimeType == "" {
+		mimeType = defaultScreenshotMIMEType
+	}
+	item := responses.ResponseInputItemParamOfComputerCallOutput(result.ToolCallID, responses.ResponseComputerToolCallOutputScreenshotParam{
+		ImageURL: openai.String("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(result.Screenshot)),
+	})
+	for _, check := range result.AcknowledgedSafetyChecks {
+		item.OfComputerCallOutput.AcknowledgedSafetyChecks = append(item.OfComputerCallOutput.AcknowledgedSafetyChecks,
+			responses.ResponseInputItemComputerCallOutputAcknowledgedSafetyCheckParam{
+				ID:      check.ID,
+				Code:    openai.String(check.Code),
+				Message: openai.String(check.Message),
+			})
+	}
+	return item
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/validation"
 )
 
@@ -28,6 +29,21 @@ type FileStoreConfig struct {
 	// ExpirationDays is the number of days until the vector store expires.
 	// 0 means no automatic expiration.
 	ExpirationDays int
+
+	// Proxy routes requests through an outbound proxy; nil uses the
+	// server-wide proxy.
+	Proxy *egress.ProxyConfig
+
+	// OnStatus, if set, is called with a vector store file's processing
+	// status (e.g. "in_progress") after each status check during an upload.
+	OnStatus func(status string)
+}
+
+// reportStatus passes status to the OnStatus callback, if any.
+func (cfg FileStoreConfig) reportStatus(status string) {
+	if cfg.OnStatus != nil {
+		cfg.OnStatus(status)
+	}
 }
 
 // FileStoreResult contains the result of a file store operation.
@@ -55,6 +71,7 @@ func CreateVectorStore(ctx context.Context, cfg FileStoreConfig, name string) (*
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
+		option.WithHTTPClient(egress.Client(cfg.Proxy, 0)),
 	}
 	if cfg.BaseURL != "" {
 		if err := validation.ValidateProviderURL(cfg.BaseURL); err != nil {
@@ -75,14 +92,14 @@ func CreateVectorStore(ctx context.Context, cfg FileStoreConfig, name string) (*
 		}
 	}
 
-	slog.Info("creating openai vector store", "name", name)
+	slog.InfoContext(ctx, "creating openai vector store", "name", name)
 
 	store, err := client.VectorStores.New(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("create vector store: %w", err)
 	}
 
-	slog.Info("openai vector store created",
+	slog.InfoContext(ctx, "openai vector store created",
 		"store_id", store.ID,
 		"name", store.Name,
 	)
@@ -108,6 +125,7 @@ func UploadFileToVectorStore(ctx context.Context, cfg FileStoreConfig, storeID s
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
+		option.WithHTTPClient(egress.Client(cfg.Proxy, 0)),
 	}
 	if cfg.BaseURL != "" {
 		if err := validation.ValidateProviderURL(cfg.BaseURL); err != nil {
@@ -119,7 +137,7 @@ func UploadFileToVectorStore(ctx context.Context, cfg FileStoreConfig, storeID s
 	client := openai.NewClient(opts...)
 
 	// Step 1: Upload file to OpenAI Files API
-	slog.Info("uploading file to openai", "filename", filename, "store_id", storeID)
+	slog.InfoContext(ctx, "uploading file to openai", "filename", filename, "store_id", storeID)
 
 	uploadedFile, err := client.Files.New(ctx, openai.FileNewParams{
 		File:    content,
@@ -129,7 +147,7 @@ func UploadFileToVectorStore(ctx context.Context, cfg FileStoreConfig, storeID s
 		return nil, fmt.Errorf("upload file: %w", err)
 	}
 
-	slog.Info("file uploaded to openai",
+	slog.InfoContext(ctx, "file uploaded to openai",
 		"file_id", uploadedFile.ID,
 		"filename", uploadedFile.Filename,
 	)
@@ -142,16 +160,18 @@ func UploadFileToVectorStore(ctx context.Context, cfg FileStoreConfig, storeID s
 		return nil, fmt.Errorf("add file to vector store: %w", err)
 	}
 
-	slog.Info("file added to vector store",
+	slog.InfoContext(ctx, "file added to vector store",
 		"file_id", uploadedFile.ID,
 		"store_id", storeID,
 		"status", vsFile.Status,
 	)
 
+	cfg.reportStatus(string(vsFile.Status))
+
 	// Step 3: Poll until file is processed
-	finalStatus, err := waitForFileProcessing(ctx, client, storeID, vsFile.ID)
+	finalStatus, err := waitForFileProcessing(ctx, cfg, client, storeID, vsFile.ID)
 	if err != nil {
-		slog.Warn("file processing incomplete",
+		slog.WarnContext(ctx, "file processing incomplete",
 			"file_id", uploadedFile.ID,
 			"store_id", storeID,
 			"error", err,
@@ -174,7 +194,7 @@ func UploadFileToVectorStore(ctx context.Context, cfg FileStoreConfig, storeID s
 }
 
 // waitForFileProcessing polls until the file is processed or timeout.
-func waitForFileProcessing(ctx context.Context, client openai.Client, storeID, vsFileID string) (string, error) {
+func waitForFileProcessing(ctx context.Context, cfg FileStoreConfig, client openai.Client, storeID, vsFileID string) (string, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, vectorStorePollingTimeout)
 	defer cancel()
 
@@ -190,6 +210,7 @@ func waitForFileProcessing(ctx context.Context, client openai.Client, storeID, v
 			if err != nil {
 				return "unknown", fmt.Errorf("get file status: %w", err)
 			}
+			cfg.reportStatus(string(vsFile.Status))
 
 			switch vsFile.Status {
 			case openai.VectorStoreFileStatusCompleted:
@@ -224,6 +245,7 @@ func DeleteVectorStore(ctx context.Context, cfg FileStoreConfig, storeID string)
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
+		option.WithHTTPClient(egress.Client(cfg.Proxy, 0)),
 	}
 	if cfg.BaseURL != "" {
 		if err := validation.ValidateProviderURL(cfg.BaseURL); err != nil {
@@ -234,14 +256,14 @@ func DeleteVectorStore(ctx context.Context, cfg FileStoreConfig, storeID string)
 
 	client := openai.NewClient(opts...)
 
-	slog.Info("deleting openai vector store", "store_id", storeID)
+	slog.InfoContext(ctx, "deleting openai vector store", "store_id", storeID)
 
 	_, err := client.VectorStores.Delete(ctx, storeID)
 	if err != nil {
 		return fmt.Errorf("delete vector store: %w", err)
 	}
 
-	slog.Info("openai vector store deleted", "store_id", storeID)
+	slog.InfoContext(ctx, "openai vector store deleted", "store_id", storeID)
 	return nil
 }
 
@@ -256,6 +278,7 @@ func GetVectorStore(ctx context.Context, cfg FileStoreConfig, storeID string) (*
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
+		option.WithHTTPClient(egress.Client(cfg.Proxy, 0)),
 	}
 	if cfg.BaseURL != "" {
 		if err := validation.ValidateProviderURL(cfg.BaseURL); err != nil {
@@ -288,6 +311,7 @@ func ListVectorStores(ctx context.Context, cfg FileStoreConfig, limit int) ([]Fi
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
+		option.WithHTTPClient(egress.Client(cfg.Proxy, 0)),
 	}
 	if cfg.BaseURL != "" {
 		if err := validation.ValidateProviderURL(cfg.BaseURL); err != nil {
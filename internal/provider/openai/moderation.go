@@ -0,0 +1,76 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	openai "github.com/openai/openai-go"
+
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+// moderationCutoffs maps safety thresholds to the moderation score at or
+// above which a prompt is blocked.
+var moderationCutoffs = map[provider.SafetyThreshold]float64{
+	provider.SafetyBlockOnlyHigh:       0.9,
+	provider.SafetyBlockMediumAndAbove: 0.5,
+	provider.SafetyBlockLowAndAbove:    0.2,
+}
+
+// needsModeration reports whether any category has a blocking threshold.
+func needsModeration(safety *provider.SafetySettings) bool {
+	if safety == nil {
+		return false
+	}
+	for _, t := range safety.Categories() {
+		if _, ok := moderationCutoffs[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryScores returns the highest moderation score in each harm category.
+func categoryScores(s openai.ModerationCategoryScores) map[string]float64 {
+	return map[string]float64{
+		provider.HarmHarassment:       max(s.Harassment, s.HarassmentThreatening),
+		provider.HarmHateSpeech:       max(s.Hate, s.HateThreatening),
+		provider.HarmSexuallyExplicit: max(s.Sexual, s.SexualMinors),
+		provider.HarmDangerousContent: max(s.Violence, s.ViolenceGraphic, s.SelfHarm, s.SelfHarmInstructions,
+			s.SelfHarmIntent, s.Illicit, s.IllicitViolent),
+	}
+}
+
+// moderatePrompt runs the prompt through the moderation API and returns a
+// block if any category scores at or above its threshold's cutoff. OpenAI
+// has no per-request safety settings, so thresholds apply to the prompt.
+func moderatePrompt(ctx context.Context, client openai.Client, safety *provider.SafetySettings, prompt string) (*provider.SafetyBlock, error) {
+	if !needsModeration(safety) || prompt == "" {
+		return nil, nil // Nothing to check, e.g. a turn that only sends tool results
+	}
+
+	resp, err := client.Moderations.New(ctx, openai.ModerationNewParams{
+		Input: openai.ModerationNewParamsInputUnion{OfString: openai.String(prompt)},
+		Model: openai.ModerationModelOmniModerationLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("moderation check: %w", err)
+	}
+
+	thresholds := safety.Categories()
+	for _, result := range resp.Results {
+		scores := categoryScores(result.CategoryScores)
+		for _, category := range []string{provider.HarmHarassment, provider.HarmHateSpeech, provider.HarmSexuallyExplicit, provider.HarmDangerousContent} {
+			score := scores[category]
+			cutoff, ok := moderationCutoffs[thresholds[category]]
+			if ok && score >= cutoff {
+				return &provider.SafetyBlock{
+					Stage:    "prompt",
+					Category: category,
+					Reason:   fmt.Sprintf("prompt flagged by moderation (%s score %.2f, threshold %s)", category, score, thresholds[category]),
+				}, nil
+			}
+		}
+	}
+	return nil, nil
+}
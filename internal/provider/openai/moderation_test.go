@@ -0,0 +1,63 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openai "github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+func TestModeratePrompt(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/moderations" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "modr-1", "model": "omni-moderation-latest", "results": [{"flagged": false,
+			"categories": {}, "category_applied_input_types": {},
+			"category_scores": {"harassment": 0.3, "hate": 0.01, "sexual": 0.0, "violence": 0.6}}]}`))
+	}))
+	defer server.Close()
+	client := openai.NewClient(option.WithAPIKey("sk-test"), option.WithBaseURL(server.URL), option.WithMaxRetries(0))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		safety   *provider.SafetySettings
+		category string
+	}{
+		{"no settings", nil, ""},
+		{"all block_none skips moderation", &provider.SafetySettings{Harassment: provider.SafetyBlockNone}, ""},
+		{"below cutoff", &provider.SafetySettings{Harassment: provider.SafetyBlockMediumAndAbove}, ""},
+		{"harassment low", &provider.SafetySettings{Harassment: provider.SafetyBlockLowAndAbove}, provider.HarmHarassment},
+		{"dangerous medium", &provider.SafetySettings{DangerousContent: provider.SafetyBlockMediumAndAbove}, provider.HarmDangerousContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := moderatePrompt(ctx, client, tt.safety, "some prompt")
+			if err != nil {
+				t.Fatalf("moderatePrompt failed: %v", err)
+			}
+			got := ""
+			if block != nil {
+				got = block.Category
+				if block.Stage != "prompt" {
+					t.Errorf("Stage = %q, want prompt", block.Stage)
+				}
+			}
+			if got != tt.category {
+				t.Errorf("blocked category = %q, want %q", got, tt.category)
+			}
+		})
+	}
+	if calls != 3 {
+		t.Errorf("moderation API called %d times, want 3", calls)
+	}
+}
@@ -3,6 +3,8 @@ package provider
 import (
 	"context"
 	"time"
+
+	"github.com/ai8future/airborne/internal/egress"
 )
 
 // Provider defines the interface for AI providers
@@ -65,12 +67,20 @@ type GenerateParams struct {
 	// InlineImages contains images to include directly in the prompt
 	InlineImages []InlineImage
 
+	// Attachments are files sent with UserInput
+	Attachments []Attachment
+
 	// Tools contains available tools/functions the model can call
 	Tools []Tool
 
 	// ToolResults contains results from previous tool calls (for multi-turn)
 	ToolResults []ToolResult
 
+	// ToolTurn is the earlier model turn that requested the tools answered
+	// by ToolResults. Providers without server-side conversation state replay
+	// it; providers with native continuity use PreviousResponseID instead.
+	ToolTurn *ToolTurn
+
 	// Config contains provider-specific configuration
 	Config ProviderConfig
 
@@ -82,6 +92,9 @@ type GenerateParams struct {
 
 	// EnableStructuredOutput enables JSON mode with entity extraction (Gemini-only)
 	EnableStructuredOutput bool
+
+	// ComputerUse enables the provider's native computer-use tool (nil disables it)
+	ComputerUse *ComputerUse
 }
 
 // Tool defines a function that the model can call
@@ -121,6 +134,27 @@ type ToolResult struct {
 
 	// IsError indicates if the tool execution failed
 	IsError bool
+
+	// Screenshot answers a computer action with the screen after performing it
+	Screenshot         []byte
+	ScreenshotMIMEType string
+
+	// AcknowledgedSafetyChecks are the action's pending safety checks the
+	// executor accepted (OpenAI)
+	AcknowledgedSafetyChecks []ComputerSafetyCheck
+}
+
+// ToolTurn is a model turn that ended by requesting tools.
+type ToolTurn struct {
+	// UserInput is the user message the model was answering
+	UserInput string
+
+	// ToolCalls are the calls the model made
+	ToolCalls []ToolCall
+
+	// State is opaque provider data for replaying the turn verbatim (such as
+	// Gemini thought signatures), taken from GenerateResult.ToolTurnState
+	State []byte
 }
 
 // CodeExecutionResult contains output from code execution
@@ -154,6 +188,10 @@ type GeneratedFile struct {
 
 	// Content of the file
 	Content []byte
+
+	// URL the file can be downloaded from, when the provider links to it
+	// instead of returning its content
+	URL string
 }
 
 // Message represents a conversation message
@@ -163,11 +201,14 @@ type Message struct {
 	Timestamp time.Time
 }
 
-// InlineImage represents an image to include directly in the prompt
+// InlineImage represents an image to include directly in the prompt, by
+// URI or by the ID of a file uploaded to the provider
 type InlineImage struct {
 	URI      string
+	FileID   string // Provider file ID, in place of URI
 	MIMEType string
 	Filename string
+	Detail   string // "auto", "low" or "high"; empty is auto
 }
 
 // GeneratedImage represents an image produced by an image generation service
@@ -210,6 +251,9 @@ type StructuredMetadata struct {
 
 	// Scheduling contains calendar/meeting signals
 	Scheduling *SchedulingIntent
+
+	// Facts are durable facts about the user (name, preferences) for conversation memory
+	Facts []StructuredFact
 }
 
 // StructuredEntity represents an extracted named entity
@@ -221,6 +265,15 @@ type StructuredEntity struct {
 	Type string
 }
 
+// StructuredFact is a durable fact about the user extracted from a conversation turn
+type StructuredFact struct {
+	// Category is the fact category (name, preference, profile, relationship, other)
+	Category string
+
+	// Content is a short standalone statement of the fact
+	Content string
+}
+
 // SchedulingIntent contains calendar/meeting signals
 type SchedulingIntent struct {
 	// Detected is true if scheduling intent was detected
@@ -239,6 +292,8 @@ type ProviderConfig struct {
 	MaxOutputTokens *int
 	BaseURL         string
 	ExtraOptions    map[string]string
+	Safety          *SafetySettings     // Typed safety thresholds; nil uses the provider default
+	Proxy           *egress.ProxyConfig // Outbound proxy; nil uses the server-wide proxy
 }
 
 // GenerateResult contains the generated reply
@@ -264,6 +319,13 @@ type GenerateResult struct {
 	// RequiresToolOutput is true if client must provide tool results
 	RequiresToolOutput bool
 
+	// ToolTurnState is opaque provider data for replaying this turn when the
+	// tool results are sent back (see ToolTurn.State)
+	ToolTurnState []byte
+
+	// ComputerActions contains computer-use actions for the client to perform
+	ComputerActions []ComputerAction
+
 	// CodeExecutions contains results from code execution
 	CodeExecutions []CodeExecutionResult
 
@@ -273,10 +335,20 @@ type GenerateResult struct {
 	// StructuredMetadata contains extracted intent, entities, topics (when structured output enabled)
 	StructuredMetadata *StructuredMetadata
 
+	// SafetyBlock is set, with Text empty, when safety filters blocked the
+	// prompt or the response
+	SafetyBlock *SafetyBlock
+
 	// GroundingQueries is the count of web search queries executed (for cost tracking)
 	// For Gemini 3: actual query count. For Gemini 2.5 and older: 1 if grounding used, 0 otherwise.
 	GroundingQueries int
 
+	// SystemPrompt and UserPrompt are the final prompt strings as this
+	// provider assembled them (instructions with RAG context and file-ID
+	// mappings; the new user message), for debugging
+	SystemPrompt string
+	UserPrompt   string
+
 	// RequestJSON contains the raw API request for debugging
 	RequestJSON []byte
 
@@ -342,9 +414,29 @@ type StreamChunk struct {
 	RequiresToolOutput bool
 	CodeExecutions     []CodeExecutionResult
 
+	// ComputerAction is set on ChunkTypeComputerAction; ComputerActions
+	// lists them all on ChunkTypeComplete
+	ComputerAction  *ComputerAction
+	ComputerActions []ComputerAction
+
+	// UsageEstimated marks Usage approximated from streamed text because the
+	// provider reports usage only at completion (set on ChunkTypeUsage)
+	UsageEstimated bool
+
 	// GroundingQueries is the count of web search queries (set on ChunkTypeComplete)
 	GroundingQueries int
 
+	// SafetyBlock is set when safety filters blocked the prompt or the response (set on ChunkTypeComplete)
+	SafetyBlock *SafetyBlock
+
+	// ToolTurnState is opaque provider data for replaying a turn that
+	// requested tools (set on ChunkTypeComplete)
+	ToolTurnState []byte
+
+	// SystemPrompt and UserPrompt are the final assembled prompts (set on ChunkTypeComplete)
+	SystemPrompt string
+	UserPrompt   string
+
 	// RequestJSON contains the raw API request for debugging (set on ChunkTypeComplete)
 	RequestJSON []byte
 	// ResponseJSON contains the raw API response for debugging (set on ChunkTypeComplete)
@@ -362,4 +454,5 @@ const (
 	ChunkTypeError
 	ChunkTypeToolCall
 	ChunkTypeCodeExecution
+	ChunkTypeComputerAction
 )
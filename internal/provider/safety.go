@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SafetyThreshold is how readily content in a harm category is blocked.
+// Higher values are stricter.
+type SafetyThreshold int
+
+const (
+	SafetyUnspecified         SafetyThreshold = iota // Provider default
+	SafetyBlockNone                                  // Never block
+	SafetyBlockOnlyHigh                              // Block high-probability harm
+	SafetyBlockMediumAndAbove                        // Block medium and high
+	SafetyBlockLowAndAbove                           // Block low, medium and high
+)
+
+var safetyThresholdNames = map[SafetyThreshold]string{
+	SafetyBlockNone:           "block_none",
+	SafetyBlockOnlyHigh:       "block_only_high",
+	SafetyBlockMediumAndAbove: "block_medium_and_above",
+	SafetyBlockLowAndAbove:    "block_low_and_above",
+}
+
+// String returns the threshold's config name, or "" if unspecified.
+func (t SafetyThreshold) String() string {
+	return safetyThresholdNames[t]
+}
+
+// ParseSafetyThreshold parses a config name such as "block_only_high".
+// The "block_" prefix is optional and case is ignored; "" is unspecified.
+func ParseSafetyThreshold(s string) (SafetyThreshold, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return SafetyUnspecified, nil
+	}
+	for t, name := range safetyThresholdNames {
+		if s == name || "block_"+s == name {
+			return t, nil
+		}
+	}
+	return SafetyUnspecified, fmt.Errorf("unknown safety threshold %q", s)
+}
+
+// Harm categories, as reported in SafetyBlock.Category.
+const (
+	HarmHarassment       = "harassment"
+	HarmHateSpeech       = "hate_speech"
+	HarmSexuallyExplicit = "sexually_explicit"
+	HarmDangerousContent = "dangerous_content"
+)
+
+// SafetySettings sets a blocking threshold per harm category. Gemini applies
+// them as native safety settings and OpenAI as moderation score cutoffs on
+// the prompt; Anthropic has no adjustable filters.
+type SafetySettings struct {
+	Harassment       SafetyThreshold
+	HateSpeech       SafetyThreshold
+	SexuallyExplicit SafetyThreshold
+	DangerousContent SafetyThreshold
+}
+
+// IsZero reports whether no category has a threshold.
+func (s SafetySettings) IsZero() bool {
+	return s == SafetySettings{}
+}
+
+// Categories returns each harm category with its threshold.
+func (s SafetySettings) Categories() map[string]SafetyThreshold {
+	return map[string]SafetyThreshold{
+		HarmHarassment:       s.Harassment,
+		HarmHateSpeech:       s.HateSpeech,
+		HarmSexuallyExplicit: s.SexuallyExplicit,
+		HarmDangerousContent: s.DangerousContent,
+	}
+}
+
+// Tighten returns s with each category raised to other's threshold where
+// other is stricter, so a request can tighten but never loosen a tenant's
+// settings.
+func (s SafetySettings) Tighten(other SafetySettings) SafetySettings {
+	return SafetySettings{
+		Harassment:       max(s.Harassment, other.Harassment),
+		HateSpeech:       max(s.HateSpeech, other.HateSpeech),
+		SexuallyExplicit: max(s.SexuallyExplicit, other.SexuallyExplicit),
+		DangerousContent: max(s.DangerousContent, other.DangerousContent),
+	}
+}
+
+// SafetyBlock describes content a provider refused to process or return.
+type SafetyBlock struct {
+	// Stage is "prompt" if the input was blocked, "response" if the output was.
+	Stage string
+
+	// Category is the harm category that triggered the block, if known.
+	Category string
+
+	// Reason is the provider's explanation.
+	Reason string
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// StructuredIntents are the intent classes structured output can report.
+var StructuredIntents = []string{
+	"question", "request", "task_delegation",
+	"feedback", "complaint", "follow_up", "attachment_analysis",
+}
+
+// StructuredEntityTypes are the entity types structured output can report.
+var StructuredEntityTypes = []string{
+	// Core (9)
+	"person", "organization", "location", "product",
+	"project", "document", "event", "money", "date",
+	// Business (3)
+	"investor", "advisor", "metric",
+	// Technology (3)
+	"technology", "tool", "service",
+	// Operations (3)
+	"methodology", "credential", "timeframe",
+	// Content (3)
+	"feature", "url", "email_address",
+}
+
+// StructuredFactCategories are the categories of extracted user facts.
+var StructuredFactCategories = []string{"name", "preference", "profile", "relationship", "other"}
+
+// StructuredMetadataSchema returns the JSON schema of the metadata fields of
+// structured output, for providers that are asked for it in the prompt
+// rather than through a native response schema.
+func StructuredMetadataSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"intent":               map[string]any{"type": "string", "enum": toAny(StructuredIntents)},
+			"requires_user_action": map[string]any{"type": "boolean"},
+			"entities": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+						"type": map[string]any{"type": "string", "enum": toAny(StructuredEntityTypes)},
+					},
+					"required": []any{"name", "type"},
+				},
+			},
+			"topics": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"scheduling_intent": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"detected":           map[string]any{"type": "boolean"},
+					"datetime_mentioned": map[string]any{"type": "string"},
+				},
+			},
+			"facts": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"category": map[string]any{"type": "string", "enum": toAny(StructuredFactCategories)},
+						"content":  map[string]any{"type": "string"},
+					},
+					"required": []any{"category", "content"},
+				},
+			},
+		},
+		"required": []any{"intent"},
+	}
+}
+
+func toAny(values []string) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+// ParseStructuredJSON parses a structured output document, returning its
+// "reply" field (empty if absent) and the extracted metadata.
+func ParseStructuredJSON(raw string) (string, *StructuredMetadata, error) {
+	var parsed struct {
+		Reply              string `json:"reply"`
+		Intent             string `json:"intent"`
+		RequiresUserAction bool   `json:"requires_user_action"`
+		Entities           []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"entities"`
+		Topics           []string `json:"topics"`
+		SchedulingIntent *struct {
+			Detected          bool   `json:"detected"`
+			DatetimeMentioned string `json:"datetime_mentioned"`
+		} `json:"scheduling_intent"`
+		Facts []struct {
+			Category string `json:"category"`
+			Content  string `json:"content"`
+		} `json:"facts"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return "", nil, err
+	}
+
+	metadata := &StructuredMetadata{
+		Intent:             parsed.Intent,
+		RequiresUserAction: parsed.RequiresUserAction,
+		Topics:             parsed.Topics,
+	}
+
+	for _, e := range parsed.Entities {
+		metadata.Entities = append(metadata.Entities, StructuredEntity{
+			Name: e.Name,
+			Type: e.Type,
+		})
+	}
+
+	if parsed.SchedulingIntent != nil {
+		metadata.Scheduling = &SchedulingIntent{
+			Detected:          parsed.SchedulingIntent.Detected,
+			DatetimeMentioned: parsed.SchedulingIntent.DatetimeMentioned,
+		}
+	}
+
+	for _, f := range parsed.Facts {
+		if strings.TrimSpace(f.Content) == "" {
+			continue
+		}
+		metadata.Facts = append(metadata.Facts, StructuredFact{
+			Category: f.Category,
+			Content:  strings.TrimSpace(f.Content),
+		})
+	}
+
+	return parsed.Reply, metadata, nil
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+func TestParseStructuredJSON(t *testing.T) {
+	reply, m, err := ParseStructuredJSON(`{"reply": "Hi", "intent": "question", "topics": ["a"],
+		"facts": [{"category": "name", "content": " Sam "}, {"category": "other", "content": "  "}]}`)
+	if err != nil {
+		t.Fatalf("ParseStructuredJSON failed: %v", err)
+	}
+	if reply != "Hi" || m.Intent != "question" || len(m.Topics) != 1 || m.Scheduling != nil {
+		t.Errorf("unexpected result: %q %+v", reply, m)
+	}
+	if len(m.Facts) != 1 || m.Facts[0].Content != "Sam" {
+		t.Errorf("expected blank facts dropped and content trimmed, got %+v", m.Facts)
+	}
+
+	if _, _, err := ParseStructuredJSON("not json"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestStructuredMetadataSchema_Supported(t *testing.T) {
+	if err := validation.CheckSchema(StructuredMetadataSchema()); err != nil {
+		t.Errorf("schema uses keywords reply validation does not support: %v", err)
+	}
+}
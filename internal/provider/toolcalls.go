@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidateToolResultIDs checks that every tool result names a tool call and
+// that no call is answered twice.
+func ValidateToolResultIDs(results []ToolResult) error {
+	seen := make(map[string]bool, len(results))
+	for _, result := range results {
+		if strings.TrimSpace(result.ToolCallID) == "" {
+			return errors.New("tool result is missing tool_call_id")
+		}
+		if seen[result.ToolCallID] {
+			return fmt.Errorf("duplicate tool result for tool call %q", result.ToolCallID)
+		}
+		seen[result.ToolCallID] = true
+	}
+	return nil
+}
+
+// OrderToolResults matches a batch of tool results to the parallel calls
+// that requested them and returns the results in call order, whatever order
+// the client sent them in. Every call must be answered exactly once.
+func OrderToolResults(calls []ToolCall, results []ToolResult) ([]ToolResult, error) {
+	if err := ValidateToolResultIDs(results); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]ToolResult, len(results))
+	for _, result := range results {
+		byID[result.ToolCallID] = result
+	}
+
+	ordered := make([]ToolResult, 0, len(calls))
+	var missing []string
+	for _, call := range calls {
+		result, ok := byID[call.ID]
+		if !ok {
+			missing = append(missing, call.ID)
+			continue
+		}
+		ordered = append(ordered, result)
+		delete(byID, call.ID)
+	}
+	for _, result := range results {
+		if _, unknown := byID[result.ToolCallID]; unknown {
+			return nil, fmt.Errorf("tool result for unknown tool call %q", result.ToolCallID)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing tool results for tool calls: %s", strings.Join(missing, ", "))
+	}
+	return ordered, nil
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderToolResults(t *testing.T) {
+	calls := []ToolCall{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	results := []ToolResult{{ToolCallID: "c", Output: "3"}, {ToolCallID: "a", Output: "1"}, {ToolCallID: "b", Output: "2"}}
+
+	ordered, err := OrderToolResults(calls, results)
+	if err != nil {
+		t.Fatalf("OrderToolResults failed: %v", err)
+	}
+	var got []string
+	for _, r := range ordered {
+		got = append(got, r.Output)
+	}
+	if strings.Join(got, ",") != "1,2,3" {
+		t.Errorf("expected results in call order, got %v", got)
+	}
+}
+
+func TestOrderToolResults_Errors(t *testing.T) {
+	calls := []ToolCall{{ID: "a"}, {ID: "b"}}
+	tests := []struct {
+		name    string
+		results []ToolResult
+		want    string
+	}{
+		{"missing", []ToolResult{{ToolCallID: "a"}}, "missing tool results for tool calls: b"},
+		{"unknown", []ToolResult{{ToolCallID: "a"}, {ToolCallID: "b"}, {ToolCallID: "z"}}, `unknown tool call "z"`},
+		{"duplicate", []ToolResult{{ToolCallID: "a"}, {ToolCallID: "a"}}, `duplicate tool result for tool call "a"`},
+		{"empty id", []ToolResult{{ToolCallID: " "}}, "missing tool_call_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OrderToolResults(calls, tt.results)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import "time"
+
+// UsageUpdateInterval is the minimum time between the intermediate
+// ChunkTypeUsage chunks a stream sends.
+const UsageUpdateInterval = 500 * time.Millisecond
+
+// streamCharsPerToken approximates tokens in streamed text for providers
+// that only report usage at completion.
+const streamCharsPerToken = 4
+
+// UsageTracker paces the intermediate usage chunks of one stream so long
+// generations can show a live token meter without a chunk per token.
+type UsageTracker struct {
+	sent   Usage
+	sentAt time.Time
+	now    func() time.Time
+}
+
+// NewUsageTracker creates a tracker for a stream.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{now: time.Now}
+}
+
+// Update returns a ChunkTypeUsage chunk for usage when it changed since the
+// last chunk and UsageUpdateInterval has passed. estimated marks counts
+// approximated from streamed text rather than reported by the provider.
+func (t *UsageTracker) Update(usage *Usage, model string, estimated bool) (StreamChunk, bool) {
+	if usage == nil || *usage == t.sent {
+		return StreamChunk{}, false
+	}
+	now := t.now()
+	if !t.sentAt.IsZero() && now.Sub(t.sentAt) < UsageUpdateInterval {
+		return StreamChunk{}, false
+	}
+	t.sent = *usage
+	t.sentAt = now
+	snapshot := *usage
+	return StreamChunk{
+		Type:           ChunkTypeUsage,
+		Usage:          &snapshot,
+		Model:          model,
+		UsageEstimated: estimated,
+	}, true
+}
+
+// EstimateStreamUsage approximates the output usage of chars of streamed text.
+func EstimateStreamUsage(chars int) *Usage {
+	tokens := int64((chars + streamCharsPerToken - 1) / streamCharsPerToken)
+	return &Usage{OutputTokens: tokens, TotalTokens: tokens}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageTracker_Update(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := &UsageTracker{now: func() time.Time { return now }}
+
+	chunk, ok := tracker.Update(&Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}, "gemini-2.5-pro", false)
+	if !ok || chunk.Type != ChunkTypeUsage || chunk.Usage.OutputTokens != 5 || chunk.Model != "gemini-2.5-pro" {
+		t.Fatalf("expected first usage chunk, got %+v, %v", chunk, ok)
+	}
+
+	// Too soon after the last chunk
+	now = now.Add(UsageUpdateInterval / 2)
+	if _, ok := tracker.Update(&Usage{InputTokens: 10, OutputTokens: 9, TotalTokens: 19}, "gemini-2.5-pro", false); ok {
+		t.Error("expected update within the interval to be skipped")
+	}
+
+	// Unchanged usage is not repeated
+	now = now.Add(UsageUpdateInterval)
+	if _, ok := tracker.Update(&Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}, "gemini-2.5-pro", false); ok {
+		t.Error("expected unchanged usage to be skipped")
+	}
+
+	chunk, ok = tracker.Update(&Usage{InputTokens: 10, OutputTokens: 20, TotalTokens: 30}, "gemini-2.5-pro", false)
+	if !ok || chunk.Usage.TotalTokens != 30 {
+		t.Errorf("expected changed usage after the interval, got %+v, %v", chunk, ok)
+	}
+
+	if _, ok := tracker.Update(nil, "gemini-2.5-pro", false); ok {
+		t.Error("expected nil usage to be skipped")
+	}
+}
+
+func TestEstimateStreamUsage(t *testing.T) {
+	usage := EstimateStreamUsage(10)
+	if usage.OutputTokens != 3 || usage.TotalTokens != 3 || usage.InputTokens != 0 {
+		t.Errorf("EstimateStreamUsage(10) = %+v, want 3 output tokens", usage)
+	}
+	if usage := EstimateStreamUsage(0); usage.OutputTokens != 0 {
+		t.Errorf("EstimateStreamUsage(0) = %+v, want 0", usage)
+	}
+}
@@ -0,0 +1,156 @@
+// Package providerhealth tracks the outcome of provider calls per (tenant,
+// provider): when the provider last answered, its error rate over a rolling
+// window, and whether the tenant's API key was last rejected.
+package providerhealth
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWindow is the span the error rate is computed over.
+const DefaultWindow = 15 * time.Minute
+
+// Key states reported in Status.
+const (
+	KeyUnknown = "unknown" // No call has reached the provider yet
+	KeyValid   = "valid"   // The provider last accepted the key
+	KeyInvalid = "invalid" // The provider last rejected the key (401 or 403)
+	KeyMissing = "missing" // The tenant has no key for the provider; set by callers
+)
+
+// bucketWidth is the granularity of the rolling window.
+const bucketWidth = time.Minute
+
+// bucket counts the calls in one bucketWidth.
+type bucket struct {
+	start  int64 // Unix minute the counts belong to
+	calls  int
+	errors int
+}
+
+// entry is the tracked state of one tenant's provider.
+type entry struct {
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+	keyState    string
+	buckets     []bucket
+}
+
+// Status is a provider's health for one tenant.
+type Status struct {
+	LastSuccess time.Time // Zero if no call has succeeded
+	LastFailure time.Time // Zero if no call has failed
+	LastError   string    // Error of the last failed call
+	Calls       int       // Calls within the window
+	Errors      int       // Failed calls within the window
+	KeyState    string    // KeyUnknown, KeyValid or KeyInvalid
+}
+
+// ErrorRate returns the fraction of calls within the window that failed,
+// or 0 without calls.
+func (s Status) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+type key struct {
+	tenantID string
+	provider string
+}
+
+// Tracker holds the health of each (tenant, provider). Tenants are tracked
+// separately because each uses its own provider API keys. It is safe for
+// concurrent use.
+type Tracker struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[key]*entry
+}
+
+// NewTracker creates an empty tracker computing error rates over window. A
+// window of zero uses DefaultWindow.
+func NewTracker(window time.Duration) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Tracker{
+		window:  window,
+		now:     time.Now,
+		entries: make(map[key]*entry),
+	}
+}
+
+// Window returns the span error rates are computed over.
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// Observe records a provider call. statusCode is the HTTP status the
+// provider answered with, or 0 if the call failed without a response; err
+// is the call's failure, if any.
+func (t *Tracker) Observe(tenantID, providerName string, statusCode int, err error) {
+	now := t.now()
+	minute := now.Unix() / int64(bucketWidth/time.Second)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	k := key{tenantID, providerName}
+	e, ok := t.entries[k]
+	if !ok {
+		e = &entry{keyState: KeyUnknown, buckets: make([]bucket, max(1, int(t.window/bucketWidth)))}
+		t.entries[k] = e
+	}
+
+	b := &e.buckets[minute%int64(len(e.buckets))]
+	if b.start != minute {
+		*b = bucket{start: minute}
+	}
+	b.calls++
+	if err != nil {
+		b.errors++
+		e.lastFailure = now
+		e.lastError = err.Error()
+	} else {
+		e.lastSuccess = now
+	}
+
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		e.keyState = KeyInvalid
+	case statusCode != 0:
+		e.keyState = KeyValid
+	}
+}
+
+// Status returns the health recorded for the tenant and provider. ok is
+// false when no call has been observed.
+func (t *Tracker) Status(tenantID, providerName string) (s Status, ok bool) {
+	minute := t.now().Unix() / int64(bucketWidth/time.Second)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key{tenantID, providerName}]
+	if !ok {
+		return Status{KeyState: KeyUnknown}, false
+	}
+	s = Status{
+		LastSuccess: e.lastSuccess,
+		LastFailure: e.lastFailure,
+		LastError:   e.lastError,
+		KeyState:    e.keyState,
+	}
+	for _, b := range e.buckets {
+		if b.start > minute-int64(len(e.buckets)) {
+			s.Calls += b.calls
+			s.Errors += b.errors
+		}
+	}
+	return s, true
+}
@@ -0,0 +1,59 @@
+package providerhealth
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTracker_ErrorRate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tr := NewTracker(5 * time.Minute)
+	tr.now = func() time.Time { return now }
+
+	if s, ok := tr.Status("acme", "openai"); ok || s.KeyState != KeyUnknown {
+		t.Fatalf("unobserved status = %+v, %v", s, ok)
+	}
+
+	tr.Observe("acme", "openai", http.StatusOK, nil)
+	tr.Observe("acme", "openai", http.StatusOK, nil)
+	tr.Observe("acme", "openai", http.StatusServiceUnavailable, errors.New("503 Service Unavailable"))
+	tr.Observe("other", "openai", 0, errors.New("timeout"))
+
+	s, ok := tr.Status("acme", "openai")
+	if !ok || s.Calls != 3 || s.Errors != 1 || s.KeyState != KeyValid {
+		t.Fatalf("status = %+v, %v", s, ok)
+	}
+	if s.LastSuccess != now || s.LastFailure != now || s.LastError != "503 Service Unavailable" {
+		t.Errorf("last call times = %+v", s)
+	}
+	if got := s.ErrorRate(); got < 0.33 || got > 0.34 {
+		t.Errorf("ErrorRate = %v, want 1/3", got)
+	}
+
+	// Calls older than the window no longer count
+	now = now.Add(3 * time.Minute)
+	tr.Observe("acme", "openai", http.StatusOK, nil)
+	now = now.Add(3 * time.Minute)
+	if s, _ := tr.Status("acme", "openai"); s.Calls != 1 || s.Errors != 0 {
+		t.Errorf("status after window = %+v, want the last call only", s)
+	}
+}
+
+func TestTracker_KeyState(t *testing.T) {
+	tr := NewTracker(0)
+
+	tr.Observe("acme", "anthropic", 0, errors.New("connection refused"))
+	if s, _ := tr.Status("acme", "anthropic"); s.KeyState != KeyUnknown {
+		t.Errorf("key state without a response = %q, want unknown", s.KeyState)
+	}
+	tr.Observe("acme", "anthropic", http.StatusUnauthorized, errors.New("401 invalid x-api-key"))
+	if s, _ := tr.Status("acme", "anthropic"); s.KeyState != KeyInvalid {
+		t.Errorf("key state after 401 = %q, want invalid", s.KeyState)
+	}
+	tr.Observe("acme", "anthropic", http.StatusTooManyRequests, errors.New("429"))
+	if s, _ := tr.Status("acme", "anthropic"); s.KeyState != KeyValid {
+		t.Errorf("key state after 429 = %q, want valid", s.KeyState)
+	}
+}
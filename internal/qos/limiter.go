@@ -0,0 +1,266 @@
+// Package qos schedules generation requests by priority class. A Limiter
+// bounds concurrent provider calls and queues the overflow with interactive
+// requests ahead of batch and background work. Under provider rate-limit
+// pressure, lower classes are shed first so user-facing traffic keeps its
+// share of the provider's headroom.
+package qos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Class is a request's scheduling class. Lower values are served first.
+type Class int
+
+const (
+	Interactive Class = iota
+	Batch
+	Background
+
+	numClasses
+)
+
+// String returns the class name used in logs.
+func (c Class) String() string {
+	switch c {
+	case Interactive:
+		return "interactive"
+	case Batch:
+		return "batch"
+	case Background:
+		return "background"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// ErrQueueFull is returned when every slot is busy and the queue holds no
+	// lower-priority request that could be displaced.
+	ErrQueueFull = errors.New("qos: request queue is full")
+
+	// ErrShed is returned to batch and background requests that are dropped
+	// for provider rate-limit pressure or displaced by interactive requests.
+	ErrShed = errors.New("qos: request shed")
+)
+
+const (
+	defaultMaxQueue       = 100
+	defaultPressureWindow = 30 * time.Second
+)
+
+// Config configures a Limiter.
+type Config struct {
+	MaxConcurrent  int           // Concurrent provider calls; must be > 0
+	MaxQueue       int           // Requests waiting for a slot (default 100)
+	PressureWindow time.Duration // How long a rate-limited provider sheds batch work (default 30s)
+}
+
+// Limiter is a priority-aware concurrency limiter. It is safe for concurrent use.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	active   int
+	queues   [numClasses][]*waiter
+	pressure map[string]time.Time // provider -> end of pressure window
+}
+
+// waiter is a queued request. ready receives nil when a slot is granted or
+// ErrShed when the request is dropped; it is buffered so senders never block.
+type waiter struct {
+	provider string
+	class    Class
+	ready    chan error
+}
+
+// NewLimiter creates a limiter. Zero MaxQueue and PressureWindow use defaults.
+func NewLimiter(cfg Config) *Limiter {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 1
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = defaultMaxQueue
+	}
+	if cfg.PressureWindow <= 0 {
+		cfg.PressureWindow = defaultPressureWindow
+	}
+	return &Limiter{
+		cfg:      cfg,
+		now:      time.Now,
+		pressure: make(map[string]time.Time),
+	}
+}
+
+// Acquire waits for a slot to call providerName. The returned release func
+// must be called once the provider call finishes; calling it more than once
+// is safe. Batch and background requests fail fast with ErrShed while the
+// provider is under rate-limit pressure.
+func (l *Limiter) Acquire(ctx context.Context, providerName string, class Class) (func(), error) {
+	if class < Interactive || class >= numClasses {
+		class = Interactive
+	}
+
+	l.mu.Lock()
+	if class != Interactive && l.underPressure(providerName) {
+		l.mu.Unlock()
+		return nil, ErrShed
+	}
+	if l.active < l.cfg.MaxConcurrent && l.queued() == 0 {
+		l.active++
+		l.mu.Unlock()
+		return l.releaseFunc(), nil
+	}
+	if l.queued() >= l.cfg.MaxQueue && !l.displaceBelow(class) {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	w := &waiter{provider: providerName, class: class, ready: make(chan error, 1)}
+	l.queues[class] = append(l.queues[class], w)
+	l.mu.Unlock()
+
+	select {
+	case err := <-w.ready:
+		if err != nil {
+			return nil, err
+		}
+		return l.releaseFunc(), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		removed := l.remove(w)
+		l.mu.Unlock()
+		if !removed {
+			// Granted or shed while we were cancelling; hand back a granted slot
+			if err := <-w.ready; err == nil {
+				l.release()
+			}
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// TryAcquire takes a slot to call providerName only if one is free now,
+// without queueing. It reports false when every slot is busy, requests are
+// queued, or a batch or background request's provider is under rate-limit
+// pressure.
+func (l *Limiter) TryAcquire(providerName string, class Class) (func(), bool) {
+	if class < Interactive || class >= numClasses {
+		class = Interactive
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if class != Interactive && l.underPressure(providerName) {
+		return nil, false
+	}
+	if l.active >= l.cfg.MaxConcurrent || l.queued() > 0 {
+		return nil, false
+	}
+	l.active++
+	return l.releaseFunc(), true
+}
+
+// ReportRateLimited puts providerName under rate-limit pressure for the
+// configured window and sheds its queued batch and background requests.
+func (l *Limiter) ReportRateLimited(providerName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pressure[providerName] = l.now().Add(l.cfg.PressureWindow)
+	for class := Batch; class < numClasses; class++ {
+		kept := l.queues[class][:0]
+		for _, w := range l.queues[class] {
+			if w.provider == providerName {
+				w.ready <- ErrShed
+				continue
+			}
+			kept = append(kept, w)
+		}
+		l.queues[class] = kept
+	}
+}
+
+// Stats returns the number of running and queued requests.
+func (l *Limiter) Stats() (active, queued int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active, l.queued()
+}
+
+func (l *Limiter) releaseFunc() func() {
+	var once sync.Once
+	return func() { once.Do(l.release) }
+}
+
+// release frees a slot and hands it to the highest-priority waiter.
+func (l *Limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	for class := Interactive; class < numClasses; class++ {
+		if len(l.queues[class]) == 0 {
+			continue
+		}
+		w := l.queues[class][0]
+		l.queues[class] = l.queues[class][1:]
+		l.active++
+		w.ready <- nil
+		return
+	}
+}
+
+// underPressure reports whether providerName is inside a pressure window.
+// Caller must hold l.mu.
+func (l *Limiter) underPressure(providerName string) bool {
+	until, ok := l.pressure[providerName]
+	if !ok {
+		return false
+	}
+	if l.now().After(until) {
+		delete(l.pressure, providerName)
+		return false
+	}
+	return true
+}
+
+// queued returns the number of waiting requests. Caller must hold l.mu.
+func (l *Limiter) queued() int {
+	n := 0
+	for _, q := range l.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// displaceBelow sheds the most recently queued request of the lowest class
+// below class, making room in a full queue. Caller must hold l.mu.
+func (l *Limiter) displaceBelow(class Class) bool {
+	for c := numClasses - 1; c > class; c-- {
+		q := l.queues[c]
+		if len(q) == 0 {
+			continue
+		}
+		q[len(q)-1].ready <- ErrShed
+		l.queues[c] = q[:len(q)-1]
+		return true
+	}
+	return false
+}
+
+// remove drops w from its queue, reporting whether it was still queued.
+// Caller must hold l.mu.
+func (l *Limiter) remove(w *waiter) bool {
+	q := l.queues[w.class]
+	for i, other := range q {
+		if other == w {
+			l.queues[w.class] = append(q[:i], q[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package qos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// acquireAsync starts an Acquire in the background and waits until it is
+// queued. Granted requests report their class on served (if non-nil) and
+// release immediately.
+func acquireAsync(t *testing.T, l *Limiter, ctx context.Context, providerName string, class Class, served chan<- Class) <-chan error {
+	t.Helper()
+	_, before := l.Stats()
+	done := make(chan error, 1)
+	go func() {
+		release, err := l.Acquire(ctx, providerName, class)
+		if err == nil {
+			if served != nil {
+				served <- class
+			}
+			release()
+		}
+		done <- err
+	}()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, queued := l.Stats(); queued > before {
+			return done
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("request was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiter_InteractiveServedBeforeQueuedBatch(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrent: 1})
+	release, err := l.Acquire(context.Background(), "openai", Interactive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan Class, 2)
+	acquireAsync(t, l, context.Background(), "openai", Batch, order)
+	acquireAsync(t, l, context.Background(), "openai", Interactive, order)
+
+	release()
+	if first := <-order; first != Interactive {
+		t.Errorf("first served = %v, want interactive", first)
+	}
+	if second := <-order; second != Batch {
+		t.Errorf("second served = %v, want batch", second)
+	}
+}
+
+func TestLimiter_InteractiveDisplacesBatchWhenQueueFull(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrent: 1, MaxQueue: 1})
+	release, _ := l.Acquire(context.Background(), "openai", Interactive)
+	defer release()
+
+	batch := acquireAsync(t, l, context.Background(), "openai", Batch, nil)
+
+	// A second batch request finds the queue full
+	if _, err := l.Acquire(context.Background(), "openai", Batch); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("err = %v, want ErrQueueFull", err)
+	}
+
+	// An interactive request takes the batch request's place in the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.Acquire(ctx, "openai", Interactive)
+	if err := <-batch; !errors.Is(err, ErrShed) {
+		t.Errorf("displaced batch err = %v, want ErrShed", err)
+	}
+}
+
+func TestLimiter_RateLimitPressureShedsBatch(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrent: 1, PressureWindow: time.Minute})
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	release, _ := l.Acquire(context.Background(), "openai", Interactive)
+	queuedBatch := acquireAsync(t, l, context.Background(), "openai", Batch, nil)
+	otherProvider := acquireAsync(t, l, context.Background(), "gemini", Background, nil)
+
+	l.ReportRateLimited("openai")
+	if err := <-queuedBatch; !errors.Is(err, ErrShed) {
+		t.Errorf("queued batch err = %v, want ErrShed", err)
+	}
+	if _, err := l.Acquire(context.Background(), "openai", Background); !errors.Is(err, ErrShed) {
+		t.Errorf("new background err = %v, want ErrShed", err)
+	}
+
+	// Other providers' work and interactive requests are unaffected
+	release()
+	if err := <-otherProvider; err != nil {
+		t.Errorf("gemini background err = %v, want nil", err)
+	}
+	r, err := l.Acquire(context.Background(), "openai", Interactive)
+	if err != nil {
+		t.Fatalf("interactive under pressure: %v", err)
+	}
+	r()
+
+	// Pressure ends after the window
+	now = now.Add(2 * time.Minute)
+	r, err = l.Acquire(context.Background(), "openai", Batch)
+	if err != nil {
+		t.Fatalf("batch after pressure window: %v", err)
+	}
+	r()
+}
+
+func TestLimiter_CancelledWaiterLeavesQueue(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrent: 1})
+	release, _ := l.Acquire(context.Background(), "openai", Interactive)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := acquireAsync(t, l, ctx, "openai", Batch, nil)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+
+	release()
+	release() // Idempotent
+	if active, queued := l.Stats(); active != 0 || queued != 0 {
+		t.Errorf("stats = %d active, %d queued; want 0, 0", active, queued)
+	}
+}
+
+func TestLimiter_TryAcquire(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrent: 1})
+	release, ok := l.TryAcquire("openai", Interactive)
+	if !ok {
+		t.Fatal("TryAcquire failed with a free slot")
+	}
+	if _, ok := l.TryAcquire("gemini", Interactive); ok {
+		t.Error("TryAcquire succeeded with every slot busy")
+	}
+	release()
+	if active, _ := l.Stats(); active != 0 {
+		t.Fatalf("active = %d after release, want 0", active)
+	}
+
+	l.ReportRateLimited("openai")
+	if _, ok := l.TryAcquire("openai", Batch); ok {
+		t.Error("TryAcquire granted a batch slot under rate-limit pressure")
+	}
+	if release, ok := l.TryAcquire("openai", Interactive); !ok {
+		t.Error("TryAcquire refused an interactive slot under rate-limit pressure")
+	} else {
+		release()
+	}
+}
@@ -0,0 +1,88 @@
+package rag
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+)
+
+// payloadAllowedPrincipals lists the principals (see ACL) that may retrieve
+// a chunk. Chunks without it are visible to everyone.
+const payloadAllowedPrincipals = "allowed_principals"
+
+// ACL limits who can retrieve a document's chunks to the listed users and
+// members of the listed groups. An empty ACL leaves the document visible to
+// everyone with access to its store.
+type ACL struct {
+	Users  []string
+	Groups []string
+}
+
+// IsEmpty reports whether the ACL restricts nothing.
+func (a ACL) IsEmpty() bool {
+	return len(a.principals()) == 0
+}
+
+// principals returns the ACL's entries as sorted, distinct "user:<id>" and
+// "group:<name>" principals, the form stored in chunk payloads.
+func (a ACL) principals() []string {
+	return principals(a.Users, a.Groups)
+}
+
+// Viewer is the identity chunks are retrieved for.
+type Viewer struct {
+	UserID string
+	Groups []string
+}
+
+// filter returns the condition a chunk must meet to be visible to v: it has
+// no ACL, or its ACL names v or one of v's groups.
+func (v Viewer) filter() []vectorstore.Condition {
+	conds := []vectorstore.Condition{{Field: payloadAllowedPrincipals, IsEmpty: true}}
+	var users []string
+	if v.UserID != "" {
+		users = []string{v.UserID}
+	}
+	if ps := principals(users, v.Groups); len(ps) > 0 {
+		match := make([]any, len(ps))
+		for i, p := range ps {
+			match[i] = p
+		}
+		conds = append(conds, vectorstore.Condition{Field: payloadAllowedPrincipals, MatchAny: match})
+	}
+	return conds
+}
+
+func principals(users, groups []string) []string {
+	var out []string
+	for _, u := range users {
+		if u = strings.TrimSpace(u); u != "" {
+			out = append(out, "user:"+u)
+		}
+	}
+	for _, g := range groups {
+		if g = strings.TrimSpace(g); g != "" {
+			out = append(out, "group:"+g)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// chunkPrincipals returns the principals stored in a chunk payload.
+func chunkPrincipals(payload map[string]any) []string {
+	var out []string
+	switch v := payload[payloadAllowedPrincipals].(type) {
+	case []string:
+		out = append(out, v...)
+	case []any:
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				out = append(out, s)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,97 @@
+package rag
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestACL_Principals(t *testing.T) {
+	acl := ACL{Users: []string{"bob", " alice ", "bob", ""}, Groups: []string{"eng"}}
+	want := []string{"group:eng", "user:alice", "user:bob"}
+	if got := acl.principals(); !slices.Equal(got, want) {
+		t.Errorf("principals() = %v, want %v", got, want)
+	}
+	if !(ACL{Users: []string{" "}}).IsEmpty() {
+		t.Error("expected blank entries to leave the ACL empty")
+	}
+}
+
+func TestService_RetrieveEnforcesACL(t *testing.T) {
+	svc, _, _, mockExt := newTestService(t)
+	ctx := context.Background()
+	mockExt.DefaultText = strings.Repeat("Quarterly figures for the board. ", 10)
+
+	ingest := func(fileID string, acl ACL) {
+		t.Helper()
+		if _, err := svc.Ingest(ctx, IngestParams{
+			StoreID:  "shared",
+			TenantID: "tenant1",
+			File:     strings.NewReader(fileID),
+			Filename: fileID + ".txt",
+			FileID:   fileID,
+			ACL:      acl,
+		}); err != nil {
+			t.Fatalf("Ingest %s failed: %v", fileID, err)
+		}
+	}
+	ingest("public", ACL{})
+	ingest("alice_only", ACL{Users: []string{"alice"}})
+	ingest("finance", ACL{Groups: []string{"finance"}})
+
+	tests := []struct {
+		name   string
+		viewer *Viewer
+		want   []string
+	}{
+		{"no ACL checks", nil, []string{"alice_only", "finance", "public"}},
+		{"named user", &Viewer{UserID: "alice"}, []string{"alice_only", "public"}},
+		{"group member", &Viewer{UserID: "bob", Groups: []string{"finance"}}, []string{"finance", "public"}},
+		{"anonymous", &Viewer{}, []string{"public"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := svc.Retrieve(ctx, RetrieveParams{StoreID: "shared", TenantID: "tenant1", Query: "figures", TopK: 10, Viewer: tt.viewer})
+			if err != nil {
+				t.Fatalf("Retrieve failed: %v", err)
+			}
+			var files []string
+			for _, r := range results {
+				if !slices.Contains(files, r.FileID) {
+					files = append(files, r.FileID)
+				}
+			}
+			slices.Sort(files)
+			if !slices.Equal(files, tt.want) {
+				t.Errorf("retrieved %v, want %v", files, tt.want)
+			}
+		})
+	}
+
+	if chunks, err := svc.FileChunks(ctx, "tenant1", "shared", "finance", &Viewer{UserID: "alice"}); err != nil || len(chunks) != 0 {
+		t.Errorf("expected finance chunks hidden from alice, got %d (%v)", len(chunks), err)
+	}
+}
+
+func TestService_IngestDuplicateRequiresSameACL(t *testing.T) {
+	svc, _, _, mockExt := newTestService(t)
+	ctx := context.Background()
+	mockExt.DefaultText = strings.Repeat("Shared handbook text. ", 10)
+
+	ingest := func(fileID string, acl ACL) *IngestResult {
+		t.Helper()
+		result, err := svc.Ingest(ctx, IngestParams{StoreID: "s", TenantID: "tenant1", File: strings.NewReader("same"), Filename: "h.txt", FileID: fileID, ACL: acl})
+		if err != nil {
+			t.Fatalf("Ingest failed: %v", err)
+		}
+		return result
+	}
+	ingest("open", ACL{})
+	if ingest("restricted", ACL{Groups: []string{"hr"}}).Duplicate {
+		t.Error("expected a copy with a different ACL to be stored separately")
+	}
+	if !ingest("restricted_again", ACL{Groups: []string{"hr"}}).Duplicate {
+		t.Error("expected a copy with the same ACL to be a duplicate")
+	}
+}
@@ -19,3 +19,11 @@ type Embedder interface {
 	// Model returns the name of the embedding model being used.
 	Model() string
 }
+
+// UsageEmbedder is implemented by embedders whose API reports how many
+// tokens a batch consumed.
+type UsageEmbedder interface {
+	// EmbedBatchWithUsage generates embeddings for texts and returns the
+	// number of input tokens billed.
+	EmbedBatchWithUsage(ctx context.Context, texts []string) ([][]float32, int64, error)
+}
@@ -0,0 +1,186 @@
+package embedder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ai8future/airborne/internal/egress"
+)
+
+// geminiMaxBatch is the most requests batchEmbedContents accepts per call.
+const geminiMaxBatch = 100
+
+// GeminiEmbedder generates embeddings using the Gemini API.
+type GeminiEmbedder struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int
+	client     *http.Client
+}
+
+// GeminiConfig configures the Gemini embedder.
+type GeminiConfig struct {
+	// APIKey is the Gemini API key.
+	APIKey string
+
+	// BaseURL is the API base URL (default: https://generativelanguage.googleapis.com/v1beta).
+	BaseURL string
+
+	// Model is the embedding model to use (default: gemini-embedding-001).
+	Model string
+
+	// Dimensions shortens the returned vectors. Zero uses the model's native size.
+	Dimensions int
+
+	// Timeout is the HTTP request timeout (default: 30s).
+	Timeout time.Duration
+
+	// Proxy routes requests through an outbound proxy; nil uses the
+	// server-wide proxy.
+	Proxy *egress.ProxyConfig
+}
+
+// geminiModelDimensions maps known models to their native embedding dimensions.
+var geminiModelDimensions = map[string]int{
+	"gemini-embedding-001": 3072,
+	"text-embedding-004":   768,
+}
+
+// NewGeminiEmbedder creates a new Gemini embedder.
+func NewGeminiEmbedder(cfg GeminiConfig) *GeminiEmbedder {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	cfg.Model = strings.TrimPrefix(cfg.Model, "models/")
+	if cfg.Model == "" {
+		cfg.Model = "gemini-embedding-001"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	dimensions := cfg.Dimensions
+	if dimensions == 0 {
+		dimensions = geminiModelDimensions[cfg.Model]
+	}
+
+	return &GeminiEmbedder{
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      cfg.Model,
+		dimensions: dimensions,
+		client:     egress.Client(cfg.Proxy, cfg.Timeout),
+	}
+}
+
+// geminiEmbedRequest is one entry of a batchEmbedContents request.
+type geminiEmbedRequest struct {
+	Model   string `json:"model"`
+	Content struct {
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"content"`
+	OutputDimensionality int `json:"outputDimensionality,omitempty"`
+}
+
+// geminiBatchResponse is the response from batchEmbedContents.
+type geminiBatchResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
+
+// Embed generates an embedding for a single text.
+func (e *GeminiEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts, sending up to
+// geminiMaxBatch texts per request.
+func (e *GeminiEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiMaxBatch {
+		batch := texts[start:min(start+geminiMaxBatch, len(texts))]
+		vectors, err := e.embed(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("embed texts %d-%d: %w", start, start+len(batch)-1, err)
+		}
+		embeddings = append(embeddings, vectors...)
+	}
+	return embeddings, nil
+}
+
+// embed sends one batchEmbedContents request.
+func (e *GeminiEmbedder) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	requests := make([]geminiEmbedRequest, len(texts))
+	for i, text := range texts {
+		requests[i].Model = "models/" + e.model
+		requests[i].Content.Parts = []struct {
+			Text string `json:"text"`
+		}{{Text: text}}
+		if e.dimensions != geminiModelDimensions[e.model] {
+			requests[i].OutputDimensionality = e.dimensions
+		}
+	}
+
+	body, err := json.Marshal(map[string]any{"requests": requests})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/models/%s:batchEmbedContents", e.baseURL, e.model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", e.apiKey) // Keep the key out of the URL and any logs of it
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("gemini error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var batchResp geminiBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(batchResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(batchResp.Embeddings), len(texts))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for i, emb := range batchResp.Embeddings {
+		embeddings[i] = emb.Values
+	}
+	return embeddings, nil
+}
+
+// Dimensions returns the embedding dimensionality, or 0 for an unknown model
+// without configured dimensions.
+func (e *GeminiEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Model returns the model name.
+func (e *GeminiEmbedder) Model() string {
+	return e.model
+}
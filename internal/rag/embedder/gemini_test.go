@@ -0,0 +1,54 @@
+package embedder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGeminiEmbedder_EmbedBatch(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/models/text-embedding-004:batchEmbedContents" || r.Header.Get("x-goog-api-key") != "key" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("API key must not be sent in the query string: %s", r.URL.RawQuery)
+		}
+		var req struct {
+			Requests []geminiEmbedRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		var resp geminiBatchResponse
+		for _, item := range req.Requests {
+			if item.Model != "models/text-embedding-004" || item.OutputDimensionality != 0 {
+				t.Errorf("unexpected request entry: %+v", item)
+			}
+			resp.Embeddings = append(resp.Embeddings, struct {
+				Values []float32 `json:"values"`
+			}{Values: []float32{float32(len(item.Content.Parts[0].Text))}})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	emb := NewGeminiEmbedder(GeminiConfig{APIKey: "key", BaseURL: server.URL, Model: "models/text-embedding-004"})
+	texts := make([]string, geminiMaxBatch+1)
+	texts[geminiMaxBatch] = "last"
+
+	vectors, err := emb.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if calls != 2 || len(vectors) != len(texts) || vectors[geminiMaxBatch][0] != 4 {
+		t.Errorf("calls = %d, vectors = %d, last = %v", calls, len(vectors), vectors[len(vectors)-1])
+	}
+	if emb.Dimensions() != 768 || emb.Model() != "text-embedding-004" {
+		t.Errorf("Dimensions() = %d, Model() = %q", emb.Dimensions(), emb.Model())
+	}
+}
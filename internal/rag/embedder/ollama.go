@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/ai8future/airborne/internal/egress"
 )
 
 // OllamaEmbedder generates embeddings using Ollama's API.
@@ -62,7 +64,8 @@ func NewOllamaEmbedder(cfg OllamaConfig) *OllamaEmbedder {
 		model:      cfg.Model,
 		dimensions: dimensions,
 		client: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: egress.Audited(http.DefaultTransport),
 		},
 	}
 }
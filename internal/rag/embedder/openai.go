@@ -0,0 +1,196 @@
+package embedder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ai8future/airborne/internal/egress"
+)
+
+// openaiMaxBatch is the number of inputs sent per embeddings request.
+const openaiMaxBatch = 256
+
+// OpenAIEmbedder generates embeddings using OpenAI's embeddings API.
+type OpenAIEmbedder struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int
+	client     *http.Client
+}
+
+// OpenAIConfig configures the OpenAI embedder.
+type OpenAIConfig struct {
+	// APIKey is the OpenAI API key.
+	APIKey string
+
+	// BaseURL is the API base URL (default: https://api.openai.com/v1).
+	BaseURL string
+
+	// Model is the embedding model to use (default: text-embedding-3-small).
+	Model string
+
+	// Dimensions shortens the returned vectors (text-embedding-3 models only).
+	// Zero uses the model's native size.
+	Dimensions int
+
+	// Timeout is the HTTP request timeout (default: 30s).
+	Timeout time.Duration
+
+	// Proxy routes requests through an outbound proxy; nil uses the
+	// server-wide proxy.
+	Proxy *egress.ProxyConfig
+}
+
+// openaiModelDimensions maps known models to their native embedding dimensions.
+var openaiModelDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+// NewOpenAIEmbedder creates a new OpenAI embedder.
+func NewOpenAIEmbedder(cfg OpenAIConfig) *OpenAIEmbedder {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.openai.com/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "text-embedding-3-small"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	dimensions := cfg.Dimensions
+	if dimensions == 0 {
+		dimensions = openaiModelDimensions[cfg.Model]
+	}
+
+	return &OpenAIEmbedder{
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      cfg.Model,
+		dimensions: dimensions,
+		client:     egress.Client(cfg.Proxy, cfg.Timeout),
+	}
+}
+
+// openaiEmbedRequest is the request body for OpenAI's embeddings API.
+type openaiEmbedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+// openaiEmbedResponse is the response from OpenAI's embeddings API.
+type openaiEmbedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int64 `json:"prompt_tokens"`
+	} `json:"usage"`
+}
+
+// Embed generates an embedding for a single text.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts.
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, _, err := e.EmbedBatchWithUsage(ctx, texts)
+	return embeddings, err
+}
+
+// EmbedBatchWithUsage generates embeddings for multiple texts, sending up to
+// openaiMaxBatch texts per request, and returns the prompt tokens billed.
+func (e *OpenAIEmbedder) EmbedBatchWithUsage(ctx context.Context, texts []string) ([][]float32, int64, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	var tokens int64
+	for start := 0; start < len(texts); start += openaiMaxBatch {
+		batch := texts[start:min(start+openaiMaxBatch, len(texts))]
+		vectors, used, err := e.embed(ctx, batch)
+		if err != nil {
+			return nil, tokens, fmt.Errorf("embed texts %d-%d: %w", start, start+len(batch)-1, err)
+		}
+		embeddings = append(embeddings, vectors...)
+		tokens += used
+	}
+	return embeddings, tokens, nil
+}
+
+// embed sends one embeddings request.
+func (e *OpenAIEmbedder) embed(ctx context.Context, texts []string) ([][]float32, int64, error) {
+	reqBody := openaiEmbedRequest{
+		Model: e.model,
+		Input: texts,
+	}
+	if e.dimensions != openaiModelDimensions[e.model] {
+		reqBody.Dimensions = e.dimensions
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, 0, fmt.Errorf("openai error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var embedResp openaiEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, 0, fmt.Errorf("decode response: %w", err)
+	}
+	if len(embedResp.Data) != len(texts) {
+		return nil, 0, fmt.Errorf("got %d embeddings for %d texts", len(embedResp.Data), len(texts))
+	}
+
+	// Results carry their input index and are not guaranteed to be in order
+	embeddings := make([][]float32, len(texts))
+	for _, d := range embedResp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, 0, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+
+	return embeddings, embedResp.Usage.PromptTokens, nil
+}
+
+// Dimensions returns the embedding dimensionality, or 0 for an unknown model
+// without configured dimensions.
+func (e *OpenAIEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Model returns the model name.
+func (e *OpenAIEmbedder) Model() string {
+	return e.model
+}
@@ -0,0 +1,72 @@
+package embedder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAIEmbedder_EmbedBatchWithUsage(t *testing.T) {
+	var requests []openaiEmbedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("unexpected request %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req openaiEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		requests = append(requests, req)
+
+		// Answer in reverse order to check results are reordered by index
+		var resp openaiEmbedResponse
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			}{Index: i, Embedding: []float32{float32(len(req.Input[i]))}})
+		}
+		resp.Usage.PromptTokens = int64(len(req.Input))
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	emb := NewOpenAIEmbedder(OpenAIConfig{APIKey: "sk-test", BaseURL: server.URL + "/", Dimensions: 256})
+	texts := make([]string, openaiMaxBatch+2)
+	for i := range texts {
+		texts[i] = string(make([]byte, i%7))
+	}
+
+	vectors, tokens, err := emb.EmbedBatchWithUsage(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatchWithUsage failed: %v", err)
+	}
+	if len(requests) != 2 || len(requests[0].Input) != openaiMaxBatch || requests[0].Dimensions != 256 {
+		t.Fatalf("unexpected batching: %d requests", len(requests))
+	}
+	if tokens != int64(len(texts)) || len(vectors) != len(texts) {
+		t.Fatalf("tokens = %d, vectors = %d; want %d", tokens, len(vectors), len(texts))
+	}
+	for i, v := range vectors {
+		if v[0] != float32(i%7) {
+			t.Fatalf("vector %d = %v, want %d", i, v, i%7)
+		}
+	}
+	if emb.Dimensions() != 256 || emb.Model() != "text-embedding-3-small" {
+		t.Errorf("Dimensions() = %d, Model() = %q", emb.Dimensions(), emb.Model())
+	}
+}
+
+func TestOpenAIEmbedder_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid key"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	emb := NewOpenAIEmbedder(OpenAIConfig{BaseURL: server.URL})
+	if _, err := emb.Embed(context.Background(), "hello"); err == nil {
+		t.Error("expected error for 401 response")
+	}
+}
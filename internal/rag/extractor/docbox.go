@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/validation"
 )
 
@@ -48,7 +49,8 @@ func NewDocboxExtractor(cfg DocboxConfig) *DocboxExtractor {
 	return &DocboxExtractor{
 		baseURL: cfg.BaseURL,
 		client: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: egress.Audited(http.DefaultTransport),
 		},
 	}
 }
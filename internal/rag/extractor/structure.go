@@ -0,0 +1,90 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Heading is a heading found in extracted text.
+type Heading struct {
+	Level  int // 1 for top-level headings
+	Text   string
+	Offset int // Byte offset of the heading line in the text
+}
+
+// Structure summarizes the layout of extracted text.
+type Structure struct {
+	Headings   []Heading
+	Paragraphs int
+	ListItems  int
+	Tables     int
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	listMarker      = regexp.MustCompile(`^\s*(?:[-*+•]|\d{1,3}[.)])\s+\S`)
+	setextUnderline = regexp.MustCompile(`^(=+|-+)\s*$`)
+)
+
+// DetectStructure finds headings, paragraphs, list items and tables in
+// extracted text. Extractors emit Markdown-like text, so Markdown headings
+// (ATX and setext), list markers and pipe tables are recognized; any other
+// block of lines separated by a blank line counts as a paragraph.
+func DetectStructure(text string) Structure {
+	var s Structure
+	lines := strings.Split(text, "\n")
+
+	offset := 0
+	inParagraph, inTable := false, false
+	for i, raw := range lines {
+		lineOffset := offset
+		offset += len(raw) + 1
+		line := strings.TrimSpace(raw)
+
+		if line == "" {
+			inParagraph, inTable = false, false
+			continue
+		}
+
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			s.Headings = append(s.Headings, Heading{Level: len(m[1]), Text: m[2], Offset: lineOffset})
+			inParagraph, inTable = false, false
+			continue
+		}
+		// A setext underline turns the single line above it into a heading
+		if inParagraph && i > 0 && setextUnderline.MatchString(line) && !strings.Contains(strings.TrimSpace(lines[i-1]), "|") {
+			if prev := strings.TrimSpace(lines[i-1]); prev != "" && (i < 2 || strings.TrimSpace(lines[i-2]) == "") {
+				level := 1
+				if line[0] == '-' {
+					level = 2
+				}
+				s.Headings = append(s.Headings, Heading{Level: level, Text: prev, Offset: lineOffset - len(lines[i-1]) - 1})
+				s.Paragraphs--
+				inParagraph = false
+				continue
+			}
+		}
+
+		if strings.HasPrefix(line, "|") && strings.Count(line, "|") >= 2 {
+			if !inTable {
+				s.Tables++
+				inTable = true
+			}
+			inParagraph = false
+			continue
+		}
+		inTable = false
+
+		if listMarker.MatchString(raw) {
+			s.ListItems++
+			inParagraph = false
+			continue
+		}
+
+		if !inParagraph {
+			s.Paragraphs++
+			inParagraph = true
+		}
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package extractor
+
+import "testing"
+
+func TestDetectStructure(t *testing.T) {
+	text := "# Annual Report\n" +
+		"\n" +
+		"Revenue grew this year.\n" +
+		"Costs were flat.\n" +
+		"\n" +
+		"Summary\n" +
+		"-------\n" +
+		"\n" +
+		"- first point\n" +
+		"- second point\n" +
+		"1. numbered point\n" +
+		"\n" +
+		"| Quarter | Revenue |\n" +
+		"| --- | --- |\n" +
+		"| Q1 | 10 |\n" +
+		"\n" +
+		"## Outlook ##\n" +
+		"Next year looks good.\n"
+
+	s := DetectStructure(text)
+
+	want := []Heading{
+		{Level: 1, Text: "Annual Report", Offset: 0},
+		{Level: 2, Text: "Summary", Offset: 59},
+		{Level: 2, Text: "Outlook", Offset: 173},
+	}
+	if len(s.Headings) != len(want) {
+		t.Fatalf("expected %d headings, got %+v", len(want), s.Headings)
+	}
+	for i, h := range want {
+		if s.Headings[i] != h {
+			t.Errorf("heading %d: expected %+v, got %+v", i, h, s.Headings[i])
+		}
+		if text[h.Offset:h.Offset+1] == "\n" {
+			t.Errorf("heading %d offset points at a blank line", i)
+		}
+	}
+	if s.Paragraphs != 2 {
+		t.Errorf("expected 2 paragraphs, got %d", s.Paragraphs)
+	}
+	if s.ListItems != 3 {
+		t.Errorf("expected 3 list items, got %d", s.ListItems)
+	}
+	if s.Tables != 1 {
+		t.Errorf("expected 1 table, got %d", s.Tables)
+	}
+}
+
+func TestDetectStructure_PlainText(t *testing.T) {
+	s := DetectStructure("One paragraph\nspanning lines.\n\nAnother one.")
+	if len(s.Headings) != 0 || s.ListItems != 0 || s.Tables != 0 {
+		t.Errorf("expected plain paragraphs only, got %+v", s)
+	}
+	if s.Paragraphs != 2 {
+		t.Errorf("expected 2 paragraphs, got %d", s.Paragraphs)
+	}
+
+	if s := DetectStructure(""); s.Paragraphs != 0 || len(s.Headings) != 0 {
+		t.Errorf("expected empty structure, got %+v", s)
+	}
+}
@@ -0,0 +1,99 @@
+package rag
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// charsPerToken approximates tokens in retrieved text.
+const charsPerToken = 4
+
+// EstimateTokens approximates the tokens in text.
+func EstimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// PackOptions bounds the retrieved context injected into a prompt.
+type PackOptions struct {
+	// MaxTokens is the budget for all chunks' text; 0 means unlimited.
+	MaxTokens int
+
+	// MaxSourceTokens caps the text taken from one file, so a single long
+	// document cannot crowd out the others; 0 means no cap.
+	MaxSourceTokens int
+}
+
+// Pack selects results to fit the budget, greedily by descending score. A
+// result that does not fit whole is truncated at the last sentence boundary
+// that does, and left out if none does. Packed results keep score order.
+func Pack(results []RetrieveResult, opts PackOptions) []RetrieveResult {
+	if opts.MaxTokens <= 0 && opts.MaxSourceTokens <= 0 {
+		return results
+	}
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b RetrieveResult) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	var packed []RetrieveResult
+	used := 0
+	sourceUsed := make(map[string]int)
+	for _, r := range sorted {
+		source := r.FileID
+		if source == "" {
+			source = r.Filename
+		}
+		remaining := math.MaxInt
+		if opts.MaxTokens > 0 {
+			remaining = opts.MaxTokens - used
+		}
+		if opts.MaxSourceTokens > 0 {
+			remaining = min(remaining, opts.MaxSourceTokens-sourceUsed[source])
+		}
+		if remaining <= 0 {
+			continue
+		}
+
+		tokens := EstimateTokens(r.Text)
+		if tokens > remaining {
+			r.Text = truncateToSentence(r.Text, remaining*charsPerToken)
+			if r.Text == "" {
+				continue
+			}
+			tokens = EstimateTokens(r.Text)
+		}
+		used += tokens
+		sourceUsed[source] += tokens
+		packed = append(packed, r)
+	}
+	return packed
+}
+
+// truncateToSentence returns the longest prefix of text of at most maxBytes
+// ending at a sentence boundary: terminal punctuation followed by a space,
+// or a line break. It returns "" when no sentence fits.
+func truncateToSentence(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	for maxBytes > 0 && !utf8.RuneStart(text[maxBytes]) {
+		maxBytes--
+	}
+	cut := text[:maxBytes]
+	for i := len(cut) - 1; i >= 0; i-- {
+		switch cut[i] {
+		case '\n':
+			if s := strings.TrimRight(cut[:i], " \t\r\n"); s != "" {
+				return s
+			}
+		case '.', '!', '?':
+			if i+1 < len(text) && (text[i+1] == ' ' || text[i+1] == '\n' || text[i+1] == '\t' || text[i+1] == '\r') {
+				return cut[:i+1]
+			}
+		}
+	}
+	return ""
+}
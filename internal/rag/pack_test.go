@@ -0,0 +1,70 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPack_Unlimited(t *testing.T) {
+	results := []RetrieveResult{{Text: "b", Score: 0.1}, {Text: "a", Score: 0.9}}
+	if got := Pack(results, PackOptions{}); len(got) != 2 || got[0].Text != "b" {
+		t.Errorf("expected results unchanged without a budget, got %+v", got)
+	}
+}
+
+func TestPack_GreedyByScore(t *testing.T) {
+	results := []RetrieveResult{
+		{Text: strings.Repeat("x", 40), Score: 0.5, FileID: "f1"}, // 10 tokens
+		{Text: strings.Repeat("y", 40), Score: 0.9, FileID: "f2"}, // 10 tokens
+		{Text: strings.Repeat("z", 20), Score: 0.7, FileID: "f3"}, // 5 tokens
+	}
+	got := Pack(results, PackOptions{MaxTokens: 16})
+	if len(got) != 2 || got[0].FileID != "f2" || got[1].FileID != "f3" {
+		t.Errorf("expected the two best-scoring chunks that fit, got %+v", got)
+	}
+}
+
+func TestPack_TruncatesAtSentence(t *testing.T) {
+	text := "Refunds take 14 days. Store credit is instant. Exchanges need a receipt."
+	got := Pack([]RetrieveResult{{Text: text, Score: 1}}, PackOptions{MaxTokens: 12})
+	if len(got) != 1 || got[0].Text != "Refunds take 14 days. Store credit is instant." {
+		t.Errorf("expected truncation at a sentence boundary, got %+v", got)
+	}
+
+	// No sentence fits
+	if got := Pack([]RetrieveResult{{Text: text, Score: 1}}, PackOptions{MaxTokens: 3}); len(got) != 0 {
+		t.Errorf("expected the chunk left out, got %+v", got)
+	}
+}
+
+func TestPack_SourceCap(t *testing.T) {
+	results := []RetrieveResult{
+		{Text: "First point. ", Filename: "long.pdf", Score: 0.9},
+		{Text: "Second point.", Filename: "long.pdf", Score: 0.8},
+		{Text: "Other file.", Filename: "short.pdf", Score: 0.7},
+	}
+	got := Pack(results, PackOptions{MaxSourceTokens: 4})
+	if len(got) != 2 || got[0].Filename != "long.pdf" || got[1].Filename != "short.pdf" {
+		t.Errorf("expected one chunk per capped source, got %+v", got)
+	}
+}
+
+func TestTruncateToSentence(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"Short.", 100, "Short."},
+		{"One. Two. Three.", 10, "One. Two."},
+		{"Version 1.5 is out. More text", 15, ""},
+		{"Heading\nBody text continues", 12, "Heading"},
+		{"Prix: 10 €. Suite du texte", 14, "Prix: 10 €."},
+		{"Prix: 10 €. Suite du texte", 11, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateToSentence(tt.text, tt.max); got != tt.want {
+			t.Errorf("truncateToSentence(%q, %d) = %q, want %q", tt.text, tt.max, got, tt.want)
+		}
+	}
+}
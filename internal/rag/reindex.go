@@ -0,0 +1,268 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+)
+
+// Re-index job states.
+const (
+	ReindexRunning   = "running"
+	ReindexCompleted = "completed"
+	ReindexFailed    = "failed"
+)
+
+// reindexBatchSize is how many chunks are re-embedded per embedder request.
+const reindexBatchSize = 128
+
+var (
+	// ErrStoreNotFound is returned when a store has no collection.
+	ErrStoreNotFound = errors.New("store not found")
+
+	// ErrReindexInProgress is returned for writes to a store while it is
+	// being re-indexed, since they would be lost when the shadow collection
+	// replaces it.
+	ErrReindexInProgress = errors.New("store is being re-indexed")
+)
+
+// ReindexStatus reports the progress of a store's re-index job, and whether
+// the store needs one.
+type ReindexStatus struct {
+	// State is ReindexRunning, ReindexCompleted or ReindexFailed, or empty
+	// if the store has not been re-indexed since the server started.
+	State string
+
+	// Model is the embedding model the job re-embeds with.
+	Model string
+
+	// TotalChunks is the number of chunks in the store when the job started.
+	TotalChunks int
+
+	// ReindexedChunks is the number of chunks re-embedded so far.
+	ReindexedChunks int
+
+	// Error describes why a failed job stopped.
+	Error string
+
+	// StartedAt and FinishedAt bound the job. FinishedAt is zero while running.
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	// StoredModel is the embedding model recorded on the store's chunks,
+	// empty if unknown.
+	StoredModel string
+
+	// NeedsReindex reports that the store's vectors were made with a
+	// different model or dimensionality than the current embedder.
+	NeedsReindex bool
+}
+
+// reindexKey identifies a store's job.
+func reindexKey(tenantID, storeID string) string {
+	return tenantID + "/" + storeID
+}
+
+// reindexing reports whether a re-index job is running for the store.
+func (s *Service) reindexing(tenantID, storeID string) bool {
+	s.reindexMu.Lock()
+	defer s.reindexMu.Unlock()
+	job := s.reindexJobs[reindexKey(tenantID, storeID)]
+	return job != nil && job.State == ReindexRunning
+}
+
+// StartReindex re-embeds every chunk in a store with the current embedder.
+// Chunks are streamed from the stored payloads in batches into a shadow
+// collection, which atomically replaces the store when all are done, so
+// retrieval keeps working on the old vectors until then. The job runs in
+// the background; poll ReindexStatus for progress. Uploads to the store are
+// rejected with ErrReindexInProgress while it runs.
+func (s *Service) StartReindex(ctx context.Context, tenantID, storeID string) (*ReindexStatus, error) {
+	if err := validateCollectionParts(tenantID, storeID); err != nil {
+		return nil, err
+	}
+	if err := checkTenant(ctx, "StartReindex", tenantID); err != nil {
+		return nil, err
+	}
+	collectionName := s.collectionName(tenantID, storeID)
+	info, err := s.store.CollectionInfo(ctx, collectionName)
+	if err != nil {
+		exists, existsErr := s.store.CollectionExists(ctx, collectionName)
+		if existsErr == nil && !exists {
+			return nil, ErrStoreNotFound
+		}
+		return nil, fmt.Errorf("collection info: %w", err)
+	}
+
+	key := reindexKey(tenantID, storeID)
+	s.reindexMu.Lock()
+	if job := s.reindexJobs[key]; job != nil && job.State == ReindexRunning {
+		s.reindexMu.Unlock()
+		return nil, ErrReindexInProgress
+	}
+	job := &ReindexStatus{
+		State:       ReindexRunning,
+		Model:       s.embedder.Model(),
+		TotalChunks: int(info.PointCount),
+		StartedAt:   time.Now(),
+	}
+	if s.reindexJobs == nil {
+		s.reindexJobs = make(map[string]*ReindexStatus)
+	}
+	s.reindexJobs[key] = job
+	snapshot := *job
+	s.reindexMu.Unlock()
+
+	slog.Info("starting store re-index",
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"model", job.Model,
+		"chunks", job.TotalChunks,
+	)
+
+	// The job outlives the request that started it
+	go s.runReindex(context.WithoutCancel(ctx), tenantID, storeID, job)
+	return &snapshot, nil
+}
+
+// ReindexStatus returns the store's latest re-index job, if any, and
+// whether its vectors match the current embedder.
+func (s *Service) ReindexStatus(ctx context.Context, tenantID, storeID string) (*ReindexStatus, error) {
+	if err := validateCollectionParts(tenantID, storeID); err != nil {
+		return nil, err
+	}
+	if err := checkTenant(ctx, "ReindexStatus", tenantID); err != nil {
+		return nil, err
+	}
+
+	var status ReindexStatus
+	s.reindexMu.Lock()
+	if job := s.reindexJobs[reindexKey(tenantID, storeID)]; job != nil {
+		status = *job
+	}
+	s.reindexMu.Unlock()
+
+	collectionName := s.collectionName(tenantID, storeID)
+	exists, err := s.store.CollectionExists(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("check collection: %w", err)
+	}
+	if !exists {
+		if status.State == "" {
+			return nil, ErrStoreNotFound
+		}
+		return &status, nil
+	}
+
+	info, err := s.store.CollectionInfo(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("collection info: %w", err)
+	}
+	sample, _, err := s.store.ScrollPage(ctx, vectorstore.ScrollParams{Collection: collectionName, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("scroll: %w", err)
+	}
+	if len(sample) > 0 {
+		status.StoredModel = getString(sample[0].Payload, payloadEmbeddingModel)
+	}
+	status.NeedsReindex = info.PointCount > 0 &&
+		(info.Dimensions != s.embedder.Dimensions() ||
+			(status.StoredModel != "" && status.StoredModel != s.embedder.Model()))
+	return &status, nil
+}
+
+// runReindex performs a re-index job and records its outcome.
+func (s *Service) runReindex(ctx context.Context, tenantID, storeID string, job *ReindexStatus) {
+	collectionName := s.collectionName(tenantID, storeID)
+	shadow := fmt.Sprintf("%s_reindex_%d", collectionName, job.StartedAt.UnixNano())
+
+	err := s.reindexInto(ctx, collectionName, shadow, job)
+	if err == nil {
+		if err = s.store.SwapCollection(ctx, collectionName, shadow); err != nil {
+			err = fmt.Errorf("swap collection: %w", err)
+		}
+	}
+	if err != nil {
+		if delErr := s.store.DeleteCollection(ctx, shadow); delErr != nil {
+			slog.Warn("failed to delete re-index shadow collection", "collection", shadow, "error", delErr)
+		}
+	}
+
+	s.reindexMu.Lock()
+	defer s.reindexMu.Unlock()
+	job.FinishedAt = time.Now()
+	if err != nil {
+		job.State = ReindexFailed
+		job.Error = err.Error()
+		slog.Error("store re-index failed", "tenant_id", tenantID, "store_id", storeID, "error", err)
+		return
+	}
+	job.State = ReindexCompleted
+	slog.Info("store re-index completed",
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"chunks", job.ReindexedChunks,
+		"duration", job.FinishedAt.Sub(job.StartedAt),
+	)
+}
+
+// reindexInto re-embeds every chunk of collection into a new shadow
+// collection, updating job's progress after each batch.
+func (s *Service) reindexInto(ctx context.Context, collection, shadow string, job *ReindexStatus) error {
+	if err := s.store.CreateCollection(ctx, shadow, s.embedder.Dimensions()); err != nil {
+		return fmt.Errorf("create shadow collection: %w", err)
+	}
+
+	offset := ""
+	for {
+		page, next, err := s.store.ScrollPage(ctx, vectorstore.ScrollParams{
+			Collection: collection,
+			Limit:      reindexBatchSize,
+			Offset:     offset,
+		})
+		if err != nil {
+			return fmt.Errorf("scroll: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		texts := make([]string, len(page))
+		for i, p := range page {
+			texts[i] = getString(p.Payload, payloadText)
+		}
+		embeddings, err := s.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("generate embeddings: %w", err)
+		}
+		if len(embeddings) != len(page) {
+			return fmt.Errorf("embedding count mismatch: got %d for %d chunks", len(embeddings), len(page))
+		}
+
+		points := make([]vectorstore.Point, len(page))
+		for i, p := range page {
+			payload := make(map[string]any, len(p.Payload)+1)
+			for k, v := range p.Payload {
+				payload[k] = v
+			}
+			payload[payloadEmbeddingModel] = job.Model
+			points[i] = vectorstore.Point{ID: p.ID, Vector: embeddings[i], Payload: payload}
+		}
+		if err := s.store.Upsert(ctx, shadow, points); err != nil {
+			return fmt.Errorf("store embeddings: %w", err)
+		}
+
+		s.reindexMu.Lock()
+		job.ReindexedChunks += len(page)
+		s.reindexMu.Unlock()
+
+		if next == "" {
+			return nil
+		}
+		offset = next
+	}
+}
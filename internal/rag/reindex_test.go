@@ -0,0 +1,172 @@
+package rag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForReindex polls until the store's re-index job finishes.
+func waitForReindex(t *testing.T, svc *Service, tenantID, storeID string) *ReindexStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := svc.ReindexStatus(context.Background(), tenantID, storeID)
+		if err != nil {
+			t.Fatalf("ReindexStatus failed: %v", err)
+		}
+		if status.State != ReindexRunning {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("re-index did not finish")
+	return nil
+}
+
+func TestService_Reindex(t *testing.T) {
+	svc, mockEmb, mockStore, mockExt := newTestService(t)
+	ctx := context.Background()
+
+	mockExt.DefaultText = strings.Repeat("This is test content. ", 300)
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Ingest(ctx, IngestParams{
+			StoreID:  "store1",
+			TenantID: "tenant1",
+			File:     bytes.NewReader([]byte(fmt.Sprintf("file %d", i))),
+			Filename: "doc.txt",
+			FileID:   fmt.Sprintf("file_%d", i),
+		}); err != nil {
+			t.Fatalf("Ingest failed: %v", err)
+		}
+	}
+	info, _ := mockStore.CollectionInfo(ctx, "tenant1_store1")
+
+	// Switch to a new model with different dimensions
+	mockEmb.ModelName = "new-embed"
+	mockEmb.Dims = 1024
+	before, err := svc.ReindexStatus(ctx, "tenant1", "store1")
+	if err != nil {
+		t.Fatalf("ReindexStatus failed: %v", err)
+	}
+	if !before.NeedsReindex || before.StoredModel != "mock-embed" || before.State != "" {
+		t.Errorf("expected store to need re-index, got %+v", before)
+	}
+
+	started, err := svc.StartReindex(ctx, "tenant1", "store1")
+	if err != nil {
+		t.Fatalf("StartReindex failed: %v", err)
+	}
+	if started.Model != "new-embed" || started.TotalChunks != int(info.PointCount) {
+		t.Errorf("unexpected job: %+v", started)
+	}
+
+	done := waitForReindex(t, svc, "tenant1", "store1")
+	if done.State != ReindexCompleted || done.ReindexedChunks != int(info.PointCount) || done.FinishedAt.IsZero() {
+		t.Fatalf("unexpected finished job: %+v", done)
+	}
+	if done.NeedsReindex || done.StoredModel != "new-embed" {
+		t.Errorf("expected store to match the new model, got %+v", done)
+	}
+
+	after, _ := mockStore.CollectionInfo(ctx, "tenant1_store1")
+	if after.Dimensions != 1024 || after.PointCount != info.PointCount {
+		t.Errorf("expected swapped collection with all chunks, got %+v", after)
+	}
+	chunks, err := svc.FileChunks(ctx, "tenant1", "store1", "file_1", nil)
+	if err != nil || len(chunks) == 0 || chunks[0].Text == "" {
+		t.Errorf("expected chunk payloads to survive re-index, got %v, %v", chunks, err)
+	}
+}
+
+func TestService_Reindex_FailureKeepsStore(t *testing.T) {
+	svc, mockEmb, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Ingest(ctx, IngestParams{
+		StoreID:  "store1",
+		TenantID: "tenant1",
+		File:     bytes.NewReader([]byte("content")),
+		Filename: "doc.txt",
+	}); err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	mockEmb.EmbedBatchFunc = func(ctx context.Context, texts []string) ([][]float32, error) {
+		return nil, errors.New("rate limited")
+	}
+	if _, err := svc.StartReindex(ctx, "tenant1", "store1"); err != nil {
+		t.Fatalf("StartReindex failed: %v", err)
+	}
+
+	done := waitForReindex(t, svc, "tenant1", "store1")
+	if done.State != ReindexFailed || !strings.Contains(done.Error, "rate limited") {
+		t.Errorf("expected failed job, got %+v", done)
+	}
+	if info, err := mockStore.CollectionInfo(ctx, "tenant1_store1"); err != nil || info.PointCount != 1 {
+		t.Errorf("expected original store to be kept, got %+v, %v", info, err)
+	}
+	for _, c := range mockStore.CreateCollectionCalls {
+		if exists, _ := mockStore.CollectionExists(ctx, c.Name); exists && c.Name != "tenant1_store1" {
+			t.Errorf("expected shadow collection %s to be deleted", c.Name)
+		}
+	}
+}
+
+func TestService_Reindex_BlocksWrites(t *testing.T) {
+	svc, mockEmb, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Ingest(ctx, IngestParams{
+		StoreID:  "store1",
+		TenantID: "tenant1",
+		File:     bytes.NewReader([]byte("content")),
+		Filename: "doc.txt",
+	}); err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	mockEmb.EmbedBatchFunc = func(ctx context.Context, texts []string) ([][]float32, error) {
+		<-release
+		return make([][]float32, len(texts)), nil
+	}
+	if _, err := svc.StartReindex(ctx, "tenant1", "store1"); err != nil {
+		t.Fatalf("StartReindex failed: %v", err)
+	}
+
+	if _, err := svc.StartReindex(ctx, "tenant1", "store1"); !errors.Is(err, ErrReindexInProgress) {
+		t.Errorf("expected second job to be rejected, got %v", err)
+	}
+	_, err := svc.Ingest(ctx, IngestParams{
+		StoreID:  "store1",
+		TenantID: "tenant1",
+		File:     bytes.NewReader([]byte("other")),
+		Filename: "other.txt",
+	})
+	if !errors.Is(err, ErrReindexInProgress) {
+		t.Errorf("expected ingest to be rejected during re-index, got %v", err)
+	}
+	if err := svc.DeleteStore(ctx, "tenant1", "store1"); !errors.Is(err, ErrReindexInProgress) {
+		t.Errorf("expected delete to be rejected during re-index, got %v", err)
+	}
+
+	close(release)
+	if done := waitForReindex(t, svc, "tenant1", "store1"); done.State != ReindexCompleted {
+		t.Errorf("expected job to complete, got %+v", done)
+	}
+}
+
+func TestService_Reindex_MissingStore(t *testing.T) {
+	svc, _, _, _ := newTestService(t)
+	if _, err := svc.StartReindex(context.Background(), "tenant1", "missing"); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("expected ErrStoreNotFound, got %v", err)
+	}
+	if _, err := svc.ReindexStatus(context.Background(), "tenant1", "missing"); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("expected ErrStoreNotFound, got %v", err)
+	}
+}
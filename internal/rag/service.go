@@ -3,11 +3,18 @@ package rag
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/ai8future/airborne/internal/isolation"
 	"github.com/ai8future/airborne/internal/rag/chunker"
 	"github.com/ai8future/airborne/internal/rag/embedder"
 	"github.com/ai8future/airborne/internal/rag/extractor"
@@ -18,17 +25,23 @@ const maxCollectionPartLen = 128
 
 // Payload field keys for vector store points.
 const (
-	payloadTenantID   = "tenant_id"
-	payloadThreadID   = "thread_id"
-	payloadStoreID    = "store_id"
-	payloadFilename   = "filename"
-	payloadFileID     = "file_id"
-	payloadChunkIndex = "chunk_index"
-	payloadText       = "text"
-	payloadCharStart  = "char_start"
-	payloadCharEnd    = "char_end"
+	payloadTenantID       = "tenant_id"
+	payloadThreadID       = "thread_id"
+	payloadStoreID        = "store_id"
+	payloadFilename       = "filename"
+	payloadFileID         = "file_id"
+	payloadChunkIndex     = "chunk_index"
+	payloadText           = "text"
+	payloadCharStart      = "char_start"
+	payloadCharEnd        = "char_end"
+	payloadContentHash    = "content_hash"
+	payloadEmbeddingModel = "embedding_model"
 )
 
+// ErrInvalidCollectionName is returned when a tenant or store ID cannot be
+// used in a collection name.
+var ErrInvalidCollectionName = errors.New("invalid store")
+
 var collectionPartPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
 
 func validateCollectionParts(tenantID, storeID string) error {
@@ -36,22 +49,22 @@ func validateCollectionParts(tenantID, storeID string) error {
 	storeID = strings.TrimSpace(storeID)
 
 	if tenantID == "" {
-		return fmt.Errorf("tenant_id is required")
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidCollectionName)
 	}
 	if storeID == "" {
-		return fmt.Errorf("store_id is required")
+		return fmt.Errorf("%w: store_id is required", ErrInvalidCollectionName)
 	}
 	if len(tenantID) > maxCollectionPartLen {
-		return fmt.Errorf("tenant_id exceeds %d characters", maxCollectionPartLen)
+		return fmt.Errorf("%w: tenant_id exceeds %d characters", ErrInvalidCollectionName, maxCollectionPartLen)
 	}
 	if len(storeID) > maxCollectionPartLen {
-		return fmt.Errorf("store_id exceeds %d characters", maxCollectionPartLen)
+		return fmt.Errorf("%w: store_id exceeds %d characters", ErrInvalidCollectionName, maxCollectionPartLen)
 	}
 	if !collectionPartPattern.MatchString(tenantID) {
-		return fmt.Errorf("tenant_id contains invalid characters")
+		return fmt.Errorf("%w: tenant_id contains invalid characters", ErrInvalidCollectionName)
 	}
 	if !collectionPartPattern.MatchString(storeID) {
-		return fmt.Errorf("store_id contains invalid characters")
+		return fmt.Errorf("%w: store_id contains invalid characters", ErrInvalidCollectionName)
 	}
 	return nil
 }
@@ -62,6 +75,9 @@ type Service struct {
 	store     vectorstore.Store
 	extractor extractor.Extractor
 	opts      ServiceOptions
+
+	reindexMu   sync.Mutex
+	reindexJobs map[string]*ReindexStatus // Latest job per tenant/store
 }
 
 // ServiceOptions configures the RAG service.
@@ -103,10 +119,11 @@ func NewService(
 	}
 
 	return &Service{
-		embedder:  emb,
-		store:     store,
-		extractor: ext,
-		opts:      opts,
+		embedder:    emb,
+		store:       store,
+		extractor:   ext,
+		opts:        opts,
+		reindexJobs: make(map[string]*ReindexStatus),
 	}
 }
 
@@ -133,6 +150,9 @@ type IngestParams struct {
 	// FileID is an optional unique identifier for the file.
 	// If empty, defaults to filename_storeID for backwards compatibility.
 	FileID string
+
+	// ACL optionally limits who can retrieve the file's chunks.
+	ACL ACL
 }
 
 // IngestResult contains the result of file ingestion.
@@ -142,13 +162,30 @@ type IngestResult struct {
 
 	// CollectionName is the Qdrant collection name.
 	CollectionName string
+
+	// FileID identifies the stored file. For a duplicate it is the file
+	// already in the store.
+	FileID string
+
+	// Duplicate reports that an identical file was already stored in the
+	// same store and thread, so nothing was embedded.
+	Duplicate bool
 }
 
-// Ingest extracts text from a file, chunks it, embeds the chunks, and stores them.
+// Ingest extracts text from a file, chunks it, embeds the chunks, and stores
+// them. Files are identified by a SHA-256 hash of their content: ingesting a
+// file already stored in the same store and thread returns the existing file
+// instead of embedding it again, so retried uploads don't double the chunks.
 func (s *Service) Ingest(ctx context.Context, params IngestParams) (*IngestResult, error) {
 	if err := validateCollectionParts(params.TenantID, params.StoreID); err != nil {
 		return nil, err
 	}
+	if err := checkTenant(ctx, "Ingest", params.TenantID); err != nil {
+		return nil, err
+	}
+	if s.reindexing(params.TenantID, params.StoreID) {
+		return nil, ErrReindexInProgress
+	}
 
 	// Generate collection name
 	collectionName := s.collectionName(params.TenantID, params.StoreID)
@@ -164,11 +201,27 @@ func (s *Service) Ingest(ctx context.Context, params IngestParams) (*IngestResul
 		}
 	}
 
-	// Extract text from file
-	result, err := s.extractor.Extract(ctx, params.File, params.Filename, params.MIMEType)
+	// Extract text from file, hashing the content as it is read
+	hasher := sha256.New()
+	result, err := s.extractor.Extract(ctx, io.TeeReader(params.File, hasher), params.Filename, params.MIMEType)
 	if err != nil {
 		return nil, fmt.Errorf("extract text: %w", err)
 	}
+	if _, err := io.Copy(hasher, params.File); err != nil {
+		return nil, fmt.Errorf("hash file: %w", err)
+	}
+	contentHash := hex.EncodeToString(hasher.Sum(nil))
+
+	if exists {
+		existing, err := s.findDuplicate(ctx, collectionName, contentHash, params)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			existing.CollectionName = collectionName
+			return existing, nil
+		}
+	}
 
 	if len(result.Text) == 0 {
 		return &IngestResult{
@@ -220,17 +273,22 @@ func (s *Service) Ingest(ctx context.Context, params IngestParams) (*IngestResul
 			ID:     fmt.Sprintf("%s_%d", fileID, chunk.Index),
 			Vector: embeddings[i],
 			Payload: map[string]any{
-				payloadTenantID:   params.TenantID,
-				payloadThreadID:   params.ThreadID,
-				payloadStoreID:    params.StoreID,
-				payloadFilename:   params.Filename,
-				payloadFileID:     fileID,
-				payloadChunkIndex: chunk.Index,
-				payloadText:       chunk.Text,
-				payloadCharStart:  chunk.Start,
-				payloadCharEnd:    chunk.End,
+				payloadTenantID:       params.TenantID,
+				payloadThreadID:       params.ThreadID,
+				payloadStoreID:        params.StoreID,
+				payloadFilename:       params.Filename,
+				payloadFileID:         fileID,
+				payloadChunkIndex:     chunk.Index,
+				payloadText:           chunk.Text,
+				payloadCharStart:      chunk.Start,
+				payloadCharEnd:        chunk.End,
+				payloadContentHash:    contentHash,
+				payloadEmbeddingModel: s.embedder.Model(),
 			},
 		}
+		if acl := params.ACL.principals(); len(acl) > 0 {
+			points[i].Payload[payloadAllowedPrincipals] = acl
+		}
 	}
 
 	// Store in vector database
@@ -241,9 +299,48 @@ func (s *Service) Ingest(ctx context.Context, params IngestParams) (*IngestResul
 	return &IngestResult{
 		ChunkCount:     len(chunks),
 		CollectionName: collectionName,
+		FileID:         fileID,
 	}, nil
 }
 
+// findDuplicate returns the file in collection with the given content hash
+// and the upload's thread, or nil if there is none.
+func (s *Service) findDuplicate(ctx context.Context, collection, contentHash string, params IngestParams) (*IngestResult, error) {
+	points, err := s.store.Scroll(ctx, vectorstore.ScrollParams{
+		Collection: collection,
+		Filter: &vectorstore.Filter{
+			Must: []vectorstore.Condition{
+				{Field: payloadContentHash, Match: contentHash},
+				{Field: payloadThreadID, Match: params.ThreadID},
+			},
+		},
+		Limit: maxFileChunks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("check duplicate: %w", err)
+	}
+	points = ownedResults("Ingest", points, params.TenantID, params.StoreID)
+
+	// A copy with a different ACL is a different document
+	acl := params.ACL.principals()
+	points = slices.DeleteFunc(points, func(p vectorstore.SearchResult) bool {
+		return !slices.Equal(chunkPrincipals(p.Payload), acl)
+	})
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	// Identical copies stored concurrently share a hash; report the first
+	fileID := getString(points[0].Payload, payloadFileID)
+	count := 0
+	for _, p := range points {
+		if getString(p.Payload, payloadFileID) == fileID {
+			count++
+		}
+	}
+	return &IngestResult{ChunkCount: count, FileID: fileID, Duplicate: true}, nil
+}
+
 // RetrieveParams contains parameters for chunk retrieval.
 type RetrieveParams struct {
 	// StoreID is the file store identifier.
@@ -260,6 +357,19 @@ type RetrieveParams struct {
 
 	// ThreadID optionally filters to a specific thread.
 	ThreadID string
+
+	// FileID optionally filters to a specific file.
+	FileID string
+
+	// Filename optionally filters to files with this name.
+	Filename string
+
+	// MinScore optionally drops chunks below this similarity score.
+	MinScore float32
+
+	// Viewer, when set, limits results to chunks whose ACL allows it. Nil
+	// skips ACL checks, for callers that see every document.
+	Viewer *Viewer
 }
 
 // RetrieveResult is a single retrieved chunk.
@@ -275,6 +385,16 @@ type RetrieveResult struct {
 
 	// Score is the similarity score.
 	Score float32
+
+	// FileID identifies the source file.
+	FileID string
+
+	// ThreadID is the thread the file was uploaded in, if any.
+	ThreadID string
+
+	// Start and End are the chunk's character offsets in the extracted text.
+	Start int
+	End   int
 }
 
 // Retrieve finds chunks similar to the query text.
@@ -282,6 +402,9 @@ func (s *Service) Retrieve(ctx context.Context, params RetrieveParams) ([]Retrie
 	if err := validateCollectionParts(params.TenantID, params.StoreID); err != nil {
 		return nil, err
 	}
+	if err := checkTenant(ctx, "Retrieve", params.TenantID); err != nil {
+		return nil, err
+	}
 
 	collectionName := s.collectionName(params.TenantID, params.StoreID)
 
@@ -307,27 +430,41 @@ func (s *Service) Retrieve(ctx context.Context, params RetrieveParams) ([]Retrie
 	}
 
 	// Build filter
+	var conditions []vectorstore.Condition
+	for _, c := range []vectorstore.Condition{
+		{Field: payloadThreadID, Match: params.ThreadID},
+		{Field: payloadFileID, Match: params.FileID},
+		{Field: payloadFilename, Match: params.Filename},
+	} {
+		if c.Match != "" {
+			conditions = append(conditions, c)
+		}
+	}
 	var filter *vectorstore.Filter
-	if params.ThreadID != "" {
-		filter = &vectorstore.Filter{
-			Must: []vectorstore.Condition{
-				{Field: payloadThreadID, Match: params.ThreadID},
-			},
+	if len(conditions) > 0 {
+		filter = &vectorstore.Filter{Must: conditions}
+	}
+	if params.Viewer != nil {
+		if filter == nil {
+			filter = &vectorstore.Filter{}
 		}
+		filter.Should = params.Viewer.filter()
 	}
 
 	// Search
 	results, err := s.store.Search(ctx, vectorstore.SearchParams{
-		Collection: collectionName,
-		Vector:     queryVector,
-		Limit:      topK,
-		Filter:     filter,
+		Collection:     collectionName,
+		Vector:         queryVector,
+		Limit:          topK,
+		Filter:         filter,
+		ScoreThreshold: params.MinScore,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("search: %w", err)
 	}
 
 	// Convert to RetrieveResult
+	results = ownedResults("Retrieve", results, params.TenantID, params.StoreID)
 	retrieved := make([]RetrieveResult, len(results))
 	for i, r := range results {
 		retrieved[i] = RetrieveResult{
@@ -335,17 +472,132 @@ func (s *Service) Retrieve(ctx context.Context, params RetrieveParams) ([]Retrie
 			Filename:   getString(r.Payload, payloadFilename),
 			ChunkIndex: getInt(r.Payload, payloadChunkIndex),
 			Score:      r.Score,
+			FileID:     getString(r.Payload, payloadFileID),
+			ThreadID:   getString(r.Payload, payloadThreadID),
+			Start:      getInt(r.Payload, payloadCharStart),
+			End:        getInt(r.Payload, payloadCharEnd),
 		}
 	}
 
 	return retrieved, nil
 }
 
+// maxFileChunks bounds how many chunks FileChunks reads for one file.
+const maxFileChunks = 10000
+
+// FileChunk is a stored chunk of an ingested file.
+type FileChunk struct {
+	// Index is the chunk's position in the file.
+	Index int
+
+	// Text is the chunk content.
+	Text string
+
+	// Start and End are the chunk's character offsets in the extracted text.
+	Start int
+	End   int
+
+	// Filename is the source filename.
+	Filename string
+}
+
+// FileChunks returns the stored chunks of a file in document order. It
+// returns an empty slice if the store or file does not exist, or if viewer
+// is set and the file's ACL does not allow it.
+func (s *Service) FileChunks(ctx context.Context, tenantID, storeID, fileID string, viewer *Viewer) ([]FileChunk, error) {
+	if err := validateCollectionParts(tenantID, storeID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(fileID) == "" {
+		return nil, fmt.Errorf("file_id is required")
+	}
+	if err := checkTenant(ctx, "FileChunks", tenantID); err != nil {
+		return nil, err
+	}
+
+	collectionName := s.collectionName(tenantID, storeID)
+	exists, err := s.store.CollectionExists(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("check collection: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	filter := &vectorstore.Filter{
+		Must: []vectorstore.Condition{{Field: payloadFileID, Match: fileID}},
+	}
+	if viewer != nil {
+		filter.Should = viewer.filter()
+	}
+	points, err := s.store.Scroll(ctx, vectorstore.ScrollParams{
+		Collection: collectionName,
+		Filter:     filter,
+		Limit:      maxFileChunks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scroll: %w", err)
+	}
+	points = ownedResults("FileChunks", points, tenantID, storeID)
+
+	chunks := make([]FileChunk, len(points))
+	for i, p := range points {
+		chunks[i] = FileChunk{
+			Index:    getInt(p.Payload, payloadChunkIndex),
+			Text:     getString(p.Payload, payloadText),
+			Start:    getInt(p.Payload, payloadCharStart),
+			End:      getInt(p.Payload, payloadCharEnd),
+			Filename: getString(p.Payload, payloadFilename),
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].Index < chunks[j].Index })
+	return chunks, nil
+}
+
+// ExtractText extracts plain text from a document with the service's extractor.
+func (s *Service) ExtractText(ctx context.Context, file io.Reader, filename, mimeType string) (string, error) {
+	result, err := s.extractor.Extract(ctx, file, filename, mimeType)
+	if err != nil {
+		return "", fmt.Errorf("extract text: %w", err)
+	}
+	return result.Text, nil
+}
+
+// ExtractionPreview is the result of extracting a document without ingesting it.
+type ExtractionPreview struct {
+	*extractor.ExtractionResult
+	Structure  extractor.Structure
+	ChunkCount int // Chunks Ingest would store for the text
+}
+
+// PreviewExtraction extracts text from a document the way Ingest would and
+// reports its detected structure and chunk count, without embedding or
+// storing anything.
+func (s *Service) PreviewExtraction(ctx context.Context, file io.Reader, filename, mimeType string) (*ExtractionPreview, error) {
+	result, err := s.extractor.Extract(ctx, file, filename, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("extract text: %w", err)
+	}
+	chunks := chunker.ChunkText(result.Text, chunker.Options{
+		ChunkSize:    s.opts.ChunkSize,
+		Overlap:      s.opts.ChunkOverlap,
+		MinChunkSize: 100,
+	})
+	return &ExtractionPreview{
+		ExtractionResult: result,
+		Structure:        extractor.DetectStructure(result.Text),
+		ChunkCount:       len(chunks),
+	}, nil
+}
+
 // CreateStore creates a new file store (Qdrant collection).
 func (s *Service) CreateStore(ctx context.Context, tenantID, storeID string) error {
 	if err := validateCollectionParts(tenantID, storeID); err != nil {
 		return err
 	}
+	if err := checkTenant(ctx, "CreateStore", tenantID); err != nil {
+		return err
+	}
 	collectionName := s.collectionName(tenantID, storeID)
 	return s.store.CreateCollection(ctx, collectionName, s.embedder.Dimensions())
 }
@@ -355,6 +607,12 @@ func (s *Service) DeleteStore(ctx context.Context, tenantID, storeID string) err
 	if err := validateCollectionParts(tenantID, storeID); err != nil {
 		return err
 	}
+	if err := checkTenant(ctx, "DeleteStore", tenantID); err != nil {
+		return err
+	}
+	if s.reindexing(tenantID, storeID) {
+		return ErrReindexInProgress
+	}
 	collectionName := s.collectionName(tenantID, storeID)
 	return s.store.DeleteCollection(ctx, collectionName)
 }
@@ -364,10 +622,41 @@ func (s *Service) StoreInfo(ctx context.Context, tenantID, storeID string) (*vec
 	if err := validateCollectionParts(tenantID, storeID); err != nil {
 		return nil, err
 	}
+	if err := checkTenant(ctx, "StoreInfo", tenantID); err != nil {
+		return nil, err
+	}
 	collectionName := s.collectionName(tenantID, storeID)
 	return s.store.CollectionInfo(ctx, collectionName)
 }
 
+// checkTenant verifies that a store access is made on behalf of its tenant.
+func checkTenant(ctx context.Context, operation, tenantID string) error {
+	return isolation.Check(ctx, isolation.LayerRAG, operation, tenantID)
+}
+
+// ownedResults drops points whose payload belongs to another tenant or store.
+// Collection names join tenant and store IDs with an underscore, so tenant
+// "a" with store "b_c" and tenant "a_b" with store "c" share a collection;
+// the payload is the authoritative owner.
+func ownedResults(operation string, results []vectorstore.SearchResult, tenantID, storeID string) []vectorstore.SearchResult {
+	owned := results[:0]
+	for _, r := range results {
+		t, st := getString(r.Payload, payloadTenantID), getString(r.Payload, payloadStoreID)
+		if (t != "" && t != tenantID) || (st != "" && st != storeID) {
+			isolation.Report(isolation.Violation{
+				Layer:         isolation.LayerRAG,
+				Operation:     operation,
+				TenantID:      tenantID,
+				TargetTenant:  t,
+				TargetStoreID: st,
+			})
+			continue
+		}
+		owned = append(owned, r)
+	}
+	return owned
+}
+
 // collectionName generates a Qdrant collection name from tenant and store IDs.
 func (s *Service) collectionName(tenantID, storeID string) string {
 	return fmt.Sprintf("%s_%s", tenantID, storeID)
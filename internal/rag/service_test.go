@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ai8future/airborne/internal/isolation"
 	"github.com/ai8future/airborne/internal/rag/extractor"
 	"github.com/ai8future/airborne/internal/rag/testutil"
 	"github.com/ai8future/airborne/internal/rag/vectorstore"
@@ -439,6 +440,36 @@ func TestService_Retrieve_WithThreadFilter(t *testing.T) {
 	}
 }
 
+func TestService_Retrieve_FileFiltersAndMinScore(t *testing.T) {
+	svc, _, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+
+	mockStore.CreateCollection(ctx, "tenant1_store1", 768)
+
+	_, err := svc.Retrieve(ctx, RetrieveParams{
+		StoreID:  "store1",
+		TenantID: "tenant1",
+		Query:    "query",
+		FileID:   "file_1",
+		Filename: "report.pdf",
+		MinScore: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+
+	params := mockStore.SearchCalls[0]
+	if params.Filter == nil || len(params.Filter.Must) != 2 {
+		t.Fatalf("expected 2 filter conditions, got %+v", params.Filter)
+	}
+	if params.Filter.Must[0].Field != "file_id" || params.Filter.Must[1].Field != "filename" {
+		t.Errorf("unexpected filter fields: %+v", params.Filter.Must)
+	}
+	if params.ScoreThreshold != 0.5 {
+		t.Errorf("expected ScoreThreshold=0.5, got %v", params.ScoreThreshold)
+	}
+}
+
 func TestService_Retrieve_TopK(t *testing.T) {
 	svc, _, mockStore, _ := newTestService(t)
 	ctx := context.Background()
@@ -517,6 +548,109 @@ func TestService_Retrieve_EmbedderError(t *testing.T) {
 	}
 }
 
+func TestService_FileChunks_InOrder(t *testing.T) {
+	svc, _, _, mockExt := newTestService(t)
+	ctx := context.Background()
+
+	mockExt.DefaultText = strings.Repeat("This is test content. ", 300)
+	for _, fileID := range []string{"file_a", "file_b"} {
+		if _, err := svc.Ingest(ctx, IngestParams{
+			StoreID:  "store1",
+			TenantID: "tenant1",
+			File:     bytes.NewReader([]byte("content")),
+			Filename: fileID + ".txt",
+			FileID:   fileID,
+		}); err != nil {
+			t.Fatalf("Ingest failed: %v", err)
+		}
+	}
+
+	chunks, err := svc.FileChunks(ctx, "tenant1", "store1", "file_a", nil)
+	if err != nil {
+		t.Fatalf("FileChunks failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Index != i || c.Filename != "file_a.txt" || c.Text == "" {
+			t.Errorf("chunk %d = %+v", i, c)
+		}
+	}
+
+	if chunks, err := svc.FileChunks(ctx, "tenant1", "missing", "file_a", nil); err != nil || len(chunks) != 0 {
+		t.Errorf("FileChunks for missing store = %v, %v; want none", chunks, err)
+	}
+}
+
+func TestService_Ingest_DedupesIdenticalContent(t *testing.T) {
+	svc, mockEmb, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+
+	ingest := func(content, fileID, threadID string) *IngestResult {
+		t.Helper()
+		result, err := svc.Ingest(ctx, IngestParams{
+			StoreID:  "store1",
+			TenantID: "tenant1",
+			ThreadID: threadID,
+			File:     bytes.NewReader([]byte(content)),
+			Filename: "doc.txt",
+			FileID:   fileID,
+		})
+		if err != nil {
+			t.Fatalf("Ingest failed: %v", err)
+		}
+		return result
+	}
+
+	first := ingest("report v1", "file_1", "")
+	if first.Duplicate || first.FileID != "file_1" || first.ChunkCount != 1 {
+		t.Fatalf("unexpected first ingest: %+v", first)
+	}
+
+	retry := ingest("report v1", "file_2", "")
+	if !retry.Duplicate || retry.FileID != "file_1" || retry.ChunkCount != 1 {
+		t.Errorf("expected retry to return the existing file, got %+v", retry)
+	}
+	if len(mockEmb.EmbedBatchCalls) != 1 || len(mockStore.UpsertCalls) != 1 {
+		t.Errorf("duplicate should not be embedded or stored: %d embeds, %d upserts",
+			len(mockEmb.EmbedBatchCalls), len(mockStore.UpsertCalls))
+	}
+
+	// Different content, or the same content in another thread, is new
+	if r := ingest("report v2", "file_3", ""); r.Duplicate || r.FileID != "file_3" {
+		t.Errorf("expected changed content to be ingested, got %+v", r)
+	}
+	if r := ingest("report v1", "file_4", "thread_9"); r.Duplicate || r.FileID != "file_4" {
+		t.Errorf("expected other thread to be ingested, got %+v", r)
+	}
+}
+
+func TestService_PreviewExtraction(t *testing.T) {
+	svc, mockEmb, mockStore, mockExt := newTestService(t)
+	ctx := context.Background()
+
+	mockExt.DefaultText = "# Report\n\n" + strings.Repeat("This is test content. ", 300)
+	preview, err := svc.PreviewExtraction(ctx, bytes.NewReader([]byte("content")), "report.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("PreviewExtraction failed: %v", err)
+	}
+	if preview.Text != mockExt.DefaultText || preview.PageCount != 1 {
+		t.Errorf("unexpected extraction result: %+v", preview.ExtractionResult)
+	}
+	if len(preview.Structure.Headings) != 1 || preview.Structure.Headings[0].Text != "Report" {
+		t.Errorf("expected detected heading, got %+v", preview.Structure.Headings)
+	}
+	if preview.ChunkCount < 2 {
+		t.Errorf("expected multiple chunks, got %d", preview.ChunkCount)
+	}
+
+	// Nothing is embedded or stored
+	if len(mockEmb.EmbedBatchCalls) > 0 || len(mockStore.UpsertCalls) > 0 {
+		t.Error("preview should not embed or store chunks")
+	}
+}
+
 func TestService_CreateStore(t *testing.T) {
 	svc, _, mockStore, _ := newTestService(t)
 	ctx := context.Background()
@@ -642,3 +776,43 @@ func TestDefaultServiceOptions(t *testing.T) {
 		t.Errorf("expected RetrievalTopK=5, got %d", opts.RetrievalTopK)
 	}
 }
+
+func TestService_TenantIsolation(t *testing.T) {
+	svc, _, _, _ := newTestService(t)
+
+	var violations []isolation.Violation
+	isolation.SetObserver(func(v isolation.Violation) { violations = append(violations, v) })
+	defer isolation.SetObserver(nil)
+
+	// Tenant "a" with store "b_c" shares collection "a_b_c" with tenant "a_b" and store "c"
+	ctx := isolation.WithTenant(context.Background(), "a")
+	if _, err := svc.Ingest(ctx, IngestParams{
+		StoreID:  "b_c",
+		TenantID: "a",
+		File:     strings.NewReader("tenant a private notes"),
+		Filename: "notes.txt",
+		FileID:   "file_a",
+	}); err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	otherCtx := isolation.WithTenant(context.Background(), "a_b")
+	results, err := svc.Retrieve(otherCtx, RetrieveParams{StoreID: "c", TenantID: "a_b", Query: "notes"})
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected another tenant's chunks to be dropped, got %d", len(results))
+	}
+	if len(violations) == 0 || violations[0].TargetTenant != "a" || violations[0].TenantID != "a_b" {
+		t.Errorf("expected a recorded violation, got %+v", violations)
+	}
+
+	// Accessing a store for a tenant other than the authenticated one is refused
+	if _, err := svc.Retrieve(ctx, RetrieveParams{StoreID: "c", TenantID: "a_b", Query: "notes"}); !errors.Is(err, isolation.ErrCrossTenant) {
+		t.Errorf("expected ErrCrossTenant, got %v", err)
+	}
+	if err := svc.DeleteStore(ctx, "a_b", "c"); !errors.Is(err, isolation.ErrCrossTenant) {
+		t.Errorf("expected ErrCrossTenant, got %v", err)
+	}
+}
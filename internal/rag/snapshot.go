@@ -0,0 +1,374 @@
+package rag
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+)
+
+// SnapshotFormat identifies store snapshot archives.
+const SnapshotFormat = "airborne-rag-snapshot"
+
+// snapshotVersion is the archive layout version written by ExportStore.
+const snapshotVersion = 1
+
+// snapshotBatchSize is how many chunks are read or written per store call.
+const snapshotBatchSize = 128
+
+// maxSnapshotLineBytes bounds a single archive record (one chunk).
+const maxSnapshotLineBytes = 16 << 20
+
+var (
+	// ErrStoreExists is returned when importing into an existing store
+	// without overwrite.
+	ErrStoreExists = errors.New("store already exists")
+
+	// ErrInvalidSnapshot is returned for archives that are not store
+	// snapshots, use an unknown version, or are truncated.
+	ErrInvalidSnapshot = errors.New("invalid store snapshot")
+)
+
+// SnapshotManifest describes a store snapshot. It is the first record of
+// the archive.
+type SnapshotManifest struct {
+	Format         string    `json:"format"`
+	Version        int       `json:"version"`
+	StoreID        string    `json:"store_id"`
+	EmbeddingModel string    `json:"embedding_model"`
+	Dimensions     int       `json:"dimensions"`
+	HasVectors     bool      `json:"has_vectors"`
+	ExportedAt     time.Time `json:"exported_at"`
+
+	// ChunkCount is the number of chunks written. It is only known once
+	// the export finishes, so it is recorded in the archive's end record.
+	ChunkCount int `json:"-"`
+}
+
+// snapshotRecord is one line of a snapshot archive after the manifest:
+// a chunk, or the end record that marks a complete archive.
+type snapshotRecord struct {
+	Chunk *snapshotChunk `json:"chunk,omitempty"`
+	End   *snapshotEnd   `json:"end,omitempty"`
+}
+
+type snapshotChunk struct {
+	ID      string         `json:"id"`
+	Payload map[string]any `json:"payload"`
+	Vector  []float32      `json:"vector,omitempty"`
+}
+
+type snapshotEnd struct {
+	ChunkCount int `json:"chunk_count"`
+}
+
+// ExportParams contains parameters for exporting a store.
+type ExportParams struct {
+	TenantID string
+	StoreID  string
+
+	// IncludeVectors stores each chunk's embedding in the archive, so an
+	// import into an environment with the same embedding model skips
+	// re-embedding. Without vectors the archive is much smaller and is
+	// re-embedded on import.
+	IncludeVectors bool
+}
+
+// ImportParams contains parameters for importing a store snapshot.
+type ImportParams struct {
+	// TenantID owns the imported store.
+	TenantID string
+
+	// StoreID names the imported store. Empty uses the exported store's ID.
+	StoreID string
+
+	// Overwrite replaces an existing store. The existing chunks stay
+	// searchable until the import completes.
+	Overwrite bool
+}
+
+// ImportResult contains the outcome of an import.
+type ImportResult struct {
+	StoreID    string
+	ChunkCount int
+
+	// Reembedded reports that chunk vectors were regenerated with the
+	// current embedder, because the archive has none or they were made by
+	// a different model.
+	Reembedded bool
+
+	// Source describes the exported store.
+	Source SnapshotManifest
+}
+
+// ExportStore writes a portable snapshot of a store to w: a gzip-compressed
+// JSON Lines archive holding a manifest, each chunk's text and payload, and
+// optionally its vector. Tenant and store fields are left out of the
+// payloads so the archive can be imported under any tenant or store ID.
+func (s *Service) ExportStore(ctx context.Context, w io.Writer, params ExportParams) (*SnapshotManifest, error) {
+	if err := validateCollectionParts(params.TenantID, params.StoreID); err != nil {
+		return nil, err
+	}
+	if err := checkTenant(ctx, "ExportStore", params.TenantID); err != nil {
+		return nil, err
+	}
+	collectionName := s.collectionName(params.TenantID, params.StoreID)
+	exists, err := s.store.CollectionExists(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("check collection: %w", err)
+	}
+	if !exists {
+		return nil, ErrStoreNotFound
+	}
+	info, err := s.store.CollectionInfo(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("collection info: %w", err)
+	}
+
+	manifest := &SnapshotManifest{
+		Format:     SnapshotFormat,
+		Version:    snapshotVersion,
+		StoreID:    params.StoreID,
+		Dimensions: info.Dimensions,
+		HasVectors: params.IncludeVectors,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+
+	offset := ""
+	for wroteManifest := false; ; {
+		page, next, err := s.store.ScrollPage(ctx, vectorstore.ScrollParams{
+			Collection:  collectionName,
+			Limit:       snapshotBatchSize,
+			Offset:      offset,
+			WithVectors: params.IncludeVectors,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scroll: %w", err)
+		}
+
+		// The manifest records the model of the stored vectors, so it is
+		// written once the first page has been read
+		if !wroteManifest {
+			if len(page) > 0 {
+				manifest.EmbeddingModel = getString(page[0].Payload, payloadEmbeddingModel)
+			}
+			if err := enc.Encode(manifest); err != nil {
+				return nil, fmt.Errorf("write manifest: %w", err)
+			}
+			wroteManifest = true
+		}
+
+		for _, p := range page {
+			payload := make(map[string]any, len(p.Payload))
+			for k, v := range p.Payload {
+				if k != payloadTenantID && k != payloadStoreID {
+					payload[k] = v
+				}
+			}
+			rec := snapshotRecord{Chunk: &snapshotChunk{ID: p.ID, Payload: payload}}
+			if params.IncludeVectors {
+				rec.Chunk.Vector = p.Vector
+			}
+			if err := enc.Encode(rec); err != nil {
+				return nil, fmt.Errorf("write chunk: %w", err)
+			}
+			manifest.ChunkCount++
+		}
+
+		if next == "" || len(page) == 0 {
+			break
+		}
+		offset = next
+	}
+
+	if err := enc.Encode(snapshotRecord{End: &snapshotEnd{ChunkCount: manifest.ChunkCount}}); err != nil {
+		return nil, fmt.Errorf("write end record: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("finish archive: %w", err)
+	}
+
+	slog.Info("exported store snapshot",
+		"tenant_id", params.TenantID,
+		"store_id", params.StoreID,
+		"chunks", manifest.ChunkCount,
+		"vectors", params.IncludeVectors,
+	)
+	return manifest, nil
+}
+
+// ImportStore loads a snapshot written by ExportStore into a store. Vectors
+// in the archive are reused when they were made by the current embedding
+// model; otherwise each chunk's text is re-embedded. A new store is created
+// directly; an existing one is only replaced with Overwrite, by importing
+// into a shadow collection that is swapped in when the import completes.
+func (s *Service) ImportStore(ctx context.Context, r io.Reader, params ImportParams) (*ImportResult, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	defer zr.Close()
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSnapshotLineBytes)
+
+	var manifest SnapshotManifest
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("%w: missing manifest", ErrInvalidSnapshot)
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &manifest); err != nil || manifest.Format != SnapshotFormat {
+		return nil, fmt.Errorf("%w: not a store snapshot", ErrInvalidSnapshot)
+	}
+	if manifest.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, manifest.Version)
+	}
+
+	storeID := params.StoreID
+	if storeID == "" {
+		storeID = manifest.StoreID
+	}
+	if err := validateCollectionParts(params.TenantID, storeID); err != nil {
+		return nil, err
+	}
+	if err := checkTenant(ctx, "ImportStore", params.TenantID); err != nil {
+		return nil, err
+	}
+	if s.reindexing(params.TenantID, storeID) {
+		return nil, ErrReindexInProgress
+	}
+
+	collectionName := s.collectionName(params.TenantID, storeID)
+	exists, err := s.store.CollectionExists(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("check collection: %w", err)
+	}
+	if exists && !params.Overwrite {
+		return nil, ErrStoreExists
+	}
+	target := collectionName
+	if exists {
+		target = fmt.Sprintf("%s_import_%d", collectionName, time.Now().UnixNano())
+	}
+
+	result := &ImportResult{
+		StoreID: storeID,
+		Reembedded: !manifest.HasVectors ||
+			manifest.EmbeddingModel != s.embedder.Model() ||
+			manifest.Dimensions != s.embedder.Dimensions(),
+		Source: manifest,
+	}
+
+	if err := s.store.CreateCollection(ctx, target, s.embedder.Dimensions()); err != nil {
+		return nil, fmt.Errorf("create collection: %w", err)
+	}
+	err = s.importChunks(ctx, scanner, target, params.TenantID, storeID, result)
+	if err == nil && exists {
+		if err = s.store.SwapCollection(ctx, collectionName, target); err != nil {
+			err = fmt.Errorf("swap collection: %w", err)
+		}
+	}
+	if err != nil {
+		if delErr := s.store.DeleteCollection(ctx, target); delErr != nil {
+			slog.Warn("failed to delete partial import collection", "collection", target, "error", delErr)
+		}
+		return nil, err
+	}
+	result.Source.ChunkCount = result.ChunkCount
+
+	slog.Info("imported store snapshot",
+		"tenant_id", params.TenantID,
+		"store_id", storeID,
+		"source_store_id", manifest.StoreID,
+		"chunks", result.ChunkCount,
+		"reembedded", result.Reembedded,
+	)
+	return result, nil
+}
+
+// importChunks writes the archive's chunks into collection in batches,
+// re-embedding them if result.Reembedded is set.
+func (s *Service) importChunks(ctx context.Context, scanner *bufio.Scanner, collection, tenantID, storeID string, result *ImportResult) error {
+	batch := make([]*snapshotChunk, 0, snapshotBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		var embeddings [][]float32
+		if result.Reembedded {
+			texts := make([]string, len(batch))
+			for i, c := range batch {
+				texts[i] = getString(c.Payload, payloadText)
+			}
+			var err error
+			embeddings, err = s.embedder.EmbedBatch(ctx, texts)
+			if err != nil {
+				return fmt.Errorf("generate embeddings: %w", err)
+			}
+			if len(embeddings) != len(batch) {
+				return fmt.Errorf("embedding count mismatch: got %d for %d chunks", len(embeddings), len(batch))
+			}
+		}
+
+		points := make([]vectorstore.Point, len(batch))
+		for i, c := range batch {
+			vector := c.Vector
+			if result.Reembedded {
+				vector = embeddings[i]
+			} else if len(vector) != s.embedder.Dimensions() {
+				return fmt.Errorf("%w: chunk %s has %d dimensions, expected %d", ErrInvalidSnapshot, c.ID, len(vector), s.embedder.Dimensions())
+			}
+			c.Payload[payloadTenantID] = tenantID
+			c.Payload[payloadStoreID] = storeID
+			c.Payload[payloadEmbeddingModel] = s.embedder.Model()
+			points[i] = vectorstore.Point{ID: c.ID, Vector: vector, Payload: c.Payload}
+		}
+		if err := s.store.Upsert(ctx, collection, points); err != nil {
+			return fmt.Errorf("store chunks: %w", err)
+		}
+		result.ChunkCount += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for scanner.Scan() {
+		var rec snapshotRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		switch {
+		case rec.End != nil:
+			if err := flush(); err != nil {
+				return err
+			}
+			if rec.End.ChunkCount != result.ChunkCount {
+				return fmt.Errorf("%w: archive lists %d chunks, found %d", ErrInvalidSnapshot, rec.End.ChunkCount, result.ChunkCount)
+			}
+			return nil
+		case rec.Chunk != nil && rec.Chunk.ID != "":
+			if rec.Chunk.Payload == nil {
+				rec.Chunk.Payload = make(map[string]any)
+			}
+			batch = append(batch, rec.Chunk)
+			if len(batch) == snapshotBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("%w: unexpected record", ErrInvalidSnapshot)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	return fmt.Errorf("%w: archive is truncated", ErrInvalidSnapshot)
+}
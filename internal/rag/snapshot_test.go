@@ -0,0 +1,175 @@
+package rag
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+)
+
+// ingestTestFiles ingests n files into tenant1/store1.
+func ingestTestFiles(t *testing.T, svc *Service, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := svc.Ingest(context.Background(), IngestParams{
+			StoreID:  "store1",
+			TenantID: "tenant1",
+			File:     bytes.NewReader([]byte(fmt.Sprintf("file %d", i))),
+			Filename: fmt.Sprintf("doc%d.txt", i),
+			FileID:   fmt.Sprintf("file_%d", i),
+		}); err != nil {
+			t.Fatalf("Ingest failed: %v", err)
+		}
+	}
+}
+
+func TestService_ExportImportStore_WithVectors(t *testing.T) {
+	svc, mockEmb, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+	ingestTestFiles(t, svc, 3)
+
+	var archive bytes.Buffer
+	manifest, err := svc.ExportStore(ctx, &archive, ExportParams{TenantID: "tenant1", StoreID: "store1", IncludeVectors: true})
+	if err != nil {
+		t.Fatalf("ExportStore failed: %v", err)
+	}
+	if manifest.ChunkCount != 3 || manifest.EmbeddingModel != "mock-embed" || manifest.Dimensions != 768 || !manifest.HasVectors {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	embedCalls := len(mockEmb.EmbedBatchCalls)
+	result, err := svc.ImportStore(ctx, &archive, ImportParams{TenantID: "prod", StoreID: "kb"})
+	if err != nil {
+		t.Fatalf("ImportStore failed: %v", err)
+	}
+	if result.StoreID != "kb" || result.ChunkCount != 3 || result.Reembedded || result.Source.StoreID != "store1" {
+		t.Errorf("unexpected import result: %+v", result)
+	}
+	if len(mockEmb.EmbedBatchCalls) != embedCalls {
+		t.Error("expected stored vectors to be reused without embedding")
+	}
+
+	points, _, _ := mockStore.ScrollPage(ctx, vectorstore.ScrollParams{Collection: "prod_kb", Limit: 10, WithVectors: true})
+	if len(points) != 3 {
+		t.Fatalf("expected 3 imported chunks, got %d", len(points))
+	}
+	original, _, _ := mockStore.ScrollPage(ctx, vectorstore.ScrollParams{Collection: "tenant1_store1", Limit: 10, WithVectors: true})
+	for i, p := range points {
+		if p.Payload[payloadTenantID] != "prod" || p.Payload[payloadStoreID] != "kb" {
+			t.Errorf("expected payload to be re-owned, got %v", p.Payload)
+		}
+		if getString(p.Payload, payloadText) != getString(original[i].Payload, payloadText) || p.Payload[payloadContentHash] == nil {
+			t.Errorf("expected chunk payload to survive import, got %v", p.Payload)
+		}
+		if p.Vector[0] != original[i].Vector[0] {
+			t.Errorf("expected imported vector to match the original")
+		}
+	}
+}
+
+func TestService_ImportStore_ReembedsOnModelChange(t *testing.T) {
+	svc, mockEmb, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+	ingestTestFiles(t, svc, 2)
+
+	var archive bytes.Buffer
+	if _, err := svc.ExportStore(ctx, &archive, ExportParams{TenantID: "tenant1", StoreID: "store1", IncludeVectors: true}); err != nil {
+		t.Fatalf("ExportStore failed: %v", err)
+	}
+
+	mockEmb.ModelName = "other-embed"
+	mockEmb.Dims = 256
+	result, err := svc.ImportStore(ctx, &archive, ImportParams{TenantID: "tenant2"})
+	if err != nil {
+		t.Fatalf("ImportStore failed: %v", err)
+	}
+	if !result.Reembedded || result.StoreID != "store1" || result.ChunkCount != 2 {
+		t.Errorf("unexpected import result: %+v", result)
+	}
+	info, _ := mockStore.CollectionInfo(ctx, "tenant2_store1")
+	if info.Dimensions != 256 || info.PointCount != 2 {
+		t.Errorf("expected re-embedded collection, got %+v", info)
+	}
+}
+
+func TestService_ImportStore_ExistingStore(t *testing.T) {
+	svc, _, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+	ingestTestFiles(t, svc, 2)
+
+	var archive bytes.Buffer
+	if _, err := svc.ExportStore(ctx, &archive, ExportParams{TenantID: "tenant1", StoreID: "store1"}); err != nil {
+		t.Fatalf("ExportStore failed: %v", err)
+	}
+	data := archive.Bytes()
+
+	if _, err := svc.ImportStore(ctx, bytes.NewReader(data), ImportParams{TenantID: "tenant1"}); !errors.Is(err, ErrStoreExists) {
+		t.Errorf("expected ErrStoreExists, got %v", err)
+	}
+
+	// Overwrite replaces the store's chunks with the archive's
+	ingestTestFiles(t, svc, 4)
+	result, err := svc.ImportStore(ctx, bytes.NewReader(data), ImportParams{TenantID: "tenant1", Overwrite: true})
+	if err != nil {
+		t.Fatalf("ImportStore failed: %v", err)
+	}
+	if !result.Reembedded || result.ChunkCount != 2 {
+		t.Errorf("unexpected import result: %+v", result)
+	}
+	if info, _ := mockStore.CollectionInfo(ctx, "tenant1_store1"); info.PointCount != 2 {
+		t.Errorf("expected 2 chunks after overwrite, got %d", info.PointCount)
+	}
+}
+
+func TestService_ImportStore_InvalidArchive(t *testing.T) {
+	svc, _, mockStore, _ := newTestService(t)
+	ctx := context.Background()
+	ingestTestFiles(t, svc, 2)
+
+	var archive bytes.Buffer
+	if _, err := svc.ExportStore(ctx, &archive, ExportParams{TenantID: "tenant1", StoreID: "store1"}); err != nil {
+		t.Fatalf("ExportStore failed: %v", err)
+	}
+
+	// Drop the end record to simulate a truncated transfer
+	zr, _ := gzip.NewReader(&archive)
+	var plain bytes.Buffer
+	plain.ReadFrom(zr)
+	lines := strings.SplitAfter(strings.TrimSuffix(plain.String(), "\n"), "\n")
+	var truncated bytes.Buffer
+	zw := gzip.NewWriter(&truncated)
+	zw.Write([]byte(strings.Join(lines[:len(lines)-1], "")))
+	zw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not gzip", []byte("hello")},
+		{"truncated", truncated.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ImportStore(ctx, bytes.NewReader(tt.data), ImportParams{TenantID: "tenant2"})
+			if !errors.Is(err, ErrInvalidSnapshot) {
+				t.Errorf("expected ErrInvalidSnapshot, got %v", err)
+			}
+		})
+	}
+	if exists, _ := mockStore.CollectionExists(ctx, "tenant2_store1"); exists {
+		t.Error("expected partial import to be removed")
+	}
+}
+
+func TestService_ExportStore_MissingStore(t *testing.T) {
+	svc, _, _, _ := newTestService(t)
+	var archive bytes.Buffer
+	if _, err := svc.ExportStore(context.Background(), &archive, ExportParams{TenantID: "tenant1", StoreID: "missing"}); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("expected ErrStoreNotFound, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
 	"sync"
 
 	"github.com/ai8future/airborne/internal/rag/extractor"
@@ -234,12 +235,15 @@ func (m *MockStore) Search(ctx context.Context, params vectorstore.SearchParams)
 		return nil, nil
 	}
 
-	// Return up to Limit points
+	// Return up to Limit points matching the filter
 	var results []vectorstore.SearchResult
 	for id, p := range coll.points {
 		if len(results) >= params.Limit {
 			break
 		}
+		if params.Filter != nil && !matchesFilter(p.Payload, *params.Filter) {
+			continue
+		}
 		results = append(results, vectorstore.SearchResult{
 			ID:      id,
 			Score:   0.9,
@@ -249,6 +253,135 @@ func (m *MockStore) Search(ctx context.Context, params vectorstore.SearchParams)
 	return results, nil
 }
 
+// Scroll returns stored points matching the filter.
+func (m *MockStore) Scroll(ctx context.Context, params vectorstore.ScrollParams) ([]vectorstore.SearchResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	coll, exists := m.collections[params.Collection]
+	if !exists {
+		return nil, nil
+	}
+
+	var results []vectorstore.SearchResult
+	for id, p := range coll.points {
+		if len(results) >= params.Limit {
+			break
+		}
+		if params.Filter != nil && !matchesFilter(p.Payload, *params.Filter) {
+			continue
+		}
+		results = append(results, vectorstore.SearchResult{
+			ID:      id,
+			Payload: p.Payload,
+		})
+	}
+	return results, nil
+}
+
+// ScrollPage returns stored points matching the filter in ID order, one
+// page at a time.
+func (m *MockStore) ScrollPage(ctx context.Context, params vectorstore.ScrollParams) ([]vectorstore.SearchResult, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	coll, exists := m.collections[params.Collection]
+	if !exists {
+		return nil, "", fmt.Errorf("collection not found: %s", params.Collection)
+	}
+
+	ids := make([]string, 0, len(coll.points))
+	for id, p := range coll.points {
+		if id >= params.Offset && (params.Filter == nil || matchesFilter(p.Payload, *params.Filter)) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var next string
+	if len(ids) > params.Limit {
+		next = ids[params.Limit]
+		ids = ids[:params.Limit]
+	}
+	results := make([]vectorstore.SearchResult, len(ids))
+	for i, id := range ids {
+		results[i] = vectorstore.SearchResult{ID: id, Payload: coll.points[id].Payload}
+		if params.WithVectors {
+			results[i].Vector = coll.points[id].Vector
+		}
+	}
+	return results, next, nil
+}
+
+// SwapCollection replaces collection name with target.
+func (m *MockStore) SwapCollection(ctx context.Context, name, target string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	coll, exists := m.collections[target]
+	if !exists {
+		return fmt.Errorf("collection not found: %s", target)
+	}
+	m.collections[name] = coll
+	delete(m.collections, target)
+	return nil
+}
+
+// matchesFilter reports whether payload satisfies every Must condition and,
+// if there are any, one of the Should conditions.
+func matchesFilter(payload map[string]any, f vectorstore.Filter) bool {
+	for _, cond := range f.Must {
+		if !matchesCondition(payload, cond) {
+			return false
+		}
+	}
+	if len(f.Should) == 0 {
+		return true
+	}
+	for _, cond := range f.Should {
+		if matchesCondition(payload, cond) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCondition applies cond to payload the way Qdrant does: a condition
+// on an array field matches when one of its elements does.
+func matchesCondition(payload map[string]any, cond vectorstore.Condition) bool {
+	values := payloadValues(payload[cond.Field])
+	if cond.IsEmpty {
+		return len(values) == 0
+	}
+	wanted := cond.MatchAny
+	if wanted == nil {
+		wanted = []any{cond.Match}
+	}
+	for _, v := range values {
+		for _, w := range wanted {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// payloadValues returns the elements of an array payload value, or the
+// value itself.
+func payloadValues(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	case []string:
+		values := make([]any, len(v))
+		for i, s := range v {
+			values[i] = s
+		}
+		return values
+	}
+	return []any{v}
+}
+
 // Delete removes points by ID.
 func (m *MockStore) Delete(ctx context.Context, collection string, ids []string) error {
 	if m.DeleteFunc != nil {
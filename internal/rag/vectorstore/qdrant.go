@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
+
+	"github.com/ai8future/airborne/internal/egress"
 )
 
 // QdrantStore implements the Store interface using Qdrant's REST API.
@@ -37,7 +40,8 @@ func NewQdrantStore(cfg QdrantConfig) *QdrantStore {
 	return &QdrantStore{
 		baseURL: cfg.BaseURL,
 		client: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: egress.Audited(http.DefaultTransport),
 		},
 	}
 }
@@ -55,12 +59,75 @@ func (s *QdrantStore) CreateCollection(ctx context.Context, name string, dimensi
 	return err
 }
 
-// DeleteCollection removes a collection.
+// DeleteCollection removes a collection. If name is an alias left by
+// SwapCollection, the alias and the collection behind it are removed.
 func (s *QdrantStore) DeleteCollection(ctx context.Context, name string) error {
-	_, err := s.doRequest(ctx, http.MethodDelete, "/collections/"+name, nil)
+	target, err := s.aliasTarget(ctx, name)
+	if err != nil {
+		return err
+	}
+	if target != "" {
+		if err := s.updateAliases(ctx, map[string]any{"delete_alias": map[string]any{"alias_name": name}}); err != nil {
+			return err
+		}
+		name = target
+	}
+	_, err = s.doRequest(ctx, http.MethodDelete, "/collections/"+name, nil)
 	return err
 }
 
+// SwapCollection points the alias name at target. The first swap replaces
+// a real collection called name, which Qdrant requires to be deleted before
+// an alias can take its name; later swaps only move the alias.
+func (s *QdrantStore) SwapCollection(ctx context.Context, name, target string) error {
+	previous, err := s.aliasTarget(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	createAlias := map[string]any{"create_alias": map[string]any{"collection_name": target, "alias_name": name}}
+	if previous == "" {
+		if _, err := s.doRequest(ctx, http.MethodDelete, "/collections/"+name, nil); err != nil {
+			return fmt.Errorf("delete collection: %w", err)
+		}
+		return s.updateAliases(ctx, createAlias)
+	}
+
+	if err := s.updateAliases(ctx, map[string]any{"delete_alias": map[string]any{"alias_name": name}}, createAlias); err != nil {
+		return err
+	}
+	_, err = s.doRequest(ctx, http.MethodDelete, "/collections/"+previous, nil)
+	return err
+}
+
+// aliasTarget returns the collection the alias name refers to, or "" if
+// name is not an alias.
+func (s *QdrantStore) aliasTarget(ctx context.Context, name string) (string, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, "/aliases", nil)
+	if err != nil {
+		return "", fmt.Errorf("list aliases: %w", err)
+	}
+	result, _ := resp["result"].(map[string]any)
+	aliases, _ := result["aliases"].([]any)
+	for _, a := range aliases {
+		am, _ := a.(map[string]any)
+		if am["alias_name"] == name {
+			target, _ := am["collection_name"].(string)
+			return target, nil
+		}
+	}
+	return "", nil
+}
+
+// updateAliases applies alias actions in one atomic request.
+func (s *QdrantStore) updateAliases(ctx context.Context, actions ...map[string]any) error {
+	_, err := s.doRequest(ctx, http.MethodPost, "/collections/aliases", map[string]any{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("update aliases: %w", err)
+	}
+	return nil
+}
+
 // CollectionExists checks if a collection exists.
 func (s *QdrantStore) CollectionExists(ctx context.Context, name string) (bool, error) {
 	resp, err := s.doRequestRaw(ctx, http.MethodGet, "/collections/"+name, nil)
@@ -142,17 +209,8 @@ func (s *QdrantStore) Search(ctx context.Context, params SearchParams) ([]Search
 		"with_payload": true,
 	}
 
-	if params.Filter != nil && len(params.Filter.Must) > 0 {
-		mustConditions := make([]map[string]any, len(params.Filter.Must))
-		for i, cond := range params.Filter.Must {
-			mustConditions[i] = map[string]any{
-				"key":   cond.Field,
-				"match": map[string]any{"value": cond.Match},
-			}
-		}
-		body["filter"] = map[string]any{
-			"must": mustConditions,
-		}
+	if filter := qdrantFilter(params.Filter); filter != nil {
+		body["filter"] = filter
 	}
 
 	if params.ScoreThreshold > 0 {
@@ -169,8 +227,101 @@ func (s *QdrantStore) Search(ctx context.Context, params SearchParams) ([]Search
 		return nil, nil
 	}
 
-	results := make([]SearchResult, 0, len(resultsRaw))
-	for _, r := range resultsRaw {
+	return parsePoints(resultsRaw), nil
+}
+
+// Scroll pages through the points matching the filter.
+func (s *QdrantStore) Scroll(ctx context.Context, params ScrollParams) ([]SearchResult, error) {
+	var results []SearchResult
+	page := params
+	page.Offset = ""
+	for {
+		page.Limit = params.Limit - len(results)
+		points, next, err := s.ScrollPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, points...)
+
+		if next == "" || len(points) == 0 || len(results) >= params.Limit {
+			return results, nil
+		}
+		page.Offset = next
+	}
+}
+
+// ScrollPage returns one page of points matching the filter.
+func (s *QdrantStore) ScrollPage(ctx context.Context, params ScrollParams) ([]SearchResult, string, error) {
+	body := map[string]any{
+		"limit":        params.Limit,
+		"with_payload": true,
+	}
+	if params.WithVectors {
+		body["with_vector"] = true
+	}
+	if filter := qdrantFilter(params.Filter); filter != nil {
+		body["filter"] = filter
+	}
+	if params.Offset != "" {
+		// Numeric point IDs must be sent back as numbers
+		if n, err := strconv.ParseUint(params.Offset, 10, 64); err == nil {
+			body["offset"] = n
+		} else {
+			body["offset"] = params.Offset
+		}
+	}
+
+	resp, err := s.doRequest(ctx, http.MethodPost, "/collections/"+params.Collection+"/points/scroll", body)
+	if err != nil {
+		return nil, "", err
+	}
+	result, _ := resp["result"].(map[string]any)
+	pointsRaw, _ := result["points"].([]any)
+
+	var next string
+	switch offset := result["next_page_offset"].(type) {
+	case string:
+		next = offset
+	case float64:
+		next = fmt.Sprintf("%d", int64(offset))
+	}
+	return parsePoints(pointsRaw), next, nil
+}
+
+// qdrantFilter converts a Filter to Qdrant's JSON form, or nil if empty.
+func qdrantFilter(f *Filter) map[string]any {
+	if f == nil || (len(f.Must) == 0 && len(f.Should) == 0) {
+		return nil
+	}
+	filter := make(map[string]any, 2)
+	if len(f.Must) > 0 {
+		filter["must"] = qdrantConditions(f.Must)
+	}
+	if len(f.Should) > 0 {
+		filter["should"] = qdrantConditions(f.Should)
+	}
+	return filter
+}
+
+func qdrantConditions(conds []Condition) []map[string]any {
+	out := make([]map[string]any, len(conds))
+	for i, cond := range conds {
+		switch {
+		case cond.IsEmpty:
+			out[i] = map[string]any{"is_empty": map[string]any{"key": cond.Field}}
+		case cond.MatchAny != nil:
+			out[i] = map[string]any{"key": cond.Field, "match": map[string]any{"any": cond.MatchAny}}
+		default:
+			out[i] = map[string]any{"key": cond.Field, "match": map[string]any{"value": cond.Match}}
+		}
+	}
+	return out
+}
+
+// parsePoints converts points from a search or scroll response.
+func parsePoints(raw []any) []SearchResult {
+	results := make([]SearchResult, 0, len(raw))
+	for _, r := range raw {
 		rm, ok := r.(map[string]any)
 		if !ok {
 			continue
@@ -194,10 +345,17 @@ func (s *QdrantStore) Search(ctx context.Context, params SearchParams) ([]Search
 			result.Payload = payload
 		}
 
+		if vector, ok := rm["vector"].([]any); ok {
+			result.Vector = make([]float32, 0, len(vector))
+			for _, v := range vector {
+				f, _ := v.(float64)
+				result.Vector = append(result.Vector, float32(f))
+			}
+		}
+
 		results = append(results, result)
 	}
-
-	return results, nil
+	return results
 }
 
 // Delete removes points by ID.
@@ -210,6 +368,21 @@ func (s *QdrantStore) Delete(ctx context.Context, collection string, ids []strin
 	return err
 }
 
+// Ping checks that Qdrant is up and ready to serve requests.
+func (s *QdrantStore) Ping(ctx context.Context) error {
+	resp, err := s.doRequestRaw(ctx, http.MethodGet, "/readyz", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("qdrant not ready (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
 // doRequest sends an HTTP request and decodes the JSON response.
 func (s *QdrantStore) doRequest(ctx context.Context, method, path string, body any) (map[string]any, error) {
 	resp, err := s.doRequestRaw(ctx, method, path, body)
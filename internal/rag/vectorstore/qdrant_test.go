@@ -85,6 +85,10 @@ func TestQdrantStore_CreateCollection_ServerError(t *testing.T) {
 
 func TestQdrantStore_DeleteCollection_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/aliases" {
+			json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"aliases": []any{}}})
+			return
+		}
 		if r.Method != http.MethodDelete {
 			t.Errorf("expected DELETE, got %s", r.Method)
 		}
@@ -101,6 +105,40 @@ func TestQdrantStore_DeleteCollection_Success(t *testing.T) {
 	}
 }
 
+func TestQdrantStore_SwapCollection_MovesAlias(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/aliases" {
+			json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"aliases": []any{
+				map[string]any{"alias_name": "tenant_store", "collection_name": "tenant_store_reindex_1"},
+			}}})
+			return
+		}
+		if r.URL.Path == "/collections/aliases" {
+			var body struct {
+				Actions []map[string]any `json:"actions"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Actions) != 2 || body.Actions[0]["delete_alias"] == nil || body.Actions[1]["create_alias"] == nil {
+				t.Errorf("expected delete and create alias in one request, got %v", body.Actions)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"result": true})
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(QdrantConfig{BaseURL: server.URL})
+	if err := store.SwapCollection(context.Background(), "tenant_store", "tenant_store_reindex_2"); err != nil {
+		t.Fatalf("SwapCollection failed: %v", err)
+	}
+
+	want := []string{"GET /aliases", "POST /collections/aliases", "DELETE /collections/tenant_store_reindex_1"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+}
+
 func TestQdrantStore_CollectionExists_True(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -310,6 +348,45 @@ func TestQdrantStore_Search_WithFilter(t *testing.T) {
 	}
 }
 
+func TestQdrantStore_Search_WithShouldFilter(t *testing.T) {
+	var receivedBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&receivedBody)
+		json.NewEncoder(w).Encode(map[string]any{"result": []any{}})
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(QdrantConfig{BaseURL: server.URL})
+	store.Search(context.Background(), SearchParams{
+		Collection: "test_collection",
+		Vector:     []float32{0.1, 0.2, 0.3},
+		Limit:      5,
+		Filter: &Filter{
+			Should: []Condition{
+				{Field: "allowed_principals", IsEmpty: true},
+				{Field: "allowed_principals", MatchAny: []any{"user:alice", "group:eng"}},
+			},
+		},
+	})
+
+	filter, _ := receivedBody["filter"].(map[string]any)
+	if _, ok := filter["must"]; ok {
+		t.Error("expected no must conditions")
+	}
+	should, ok := filter["should"].([]any)
+	if !ok || len(should) != 2 {
+		t.Fatalf("expected 2 should conditions, got %v", filter["should"])
+	}
+	isEmpty, _ := should[0].(map[string]any)["is_empty"].(map[string]any)
+	if isEmpty["key"] != "allowed_principals" {
+		t.Errorf("expected is_empty condition, got %v", should[0])
+	}
+	match, _ := should[1].(map[string]any)["match"].(map[string]any)
+	if values, _ := match["any"].([]any); len(values) != 2 || values[0] != "user:alice" {
+		t.Errorf("expected match any condition, got %v", should[1])
+	}
+}
+
 func TestQdrantStore_Search_WithScoreThreshold(t *testing.T) {
 	var receivedBody map[string]any
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -382,6 +459,82 @@ func TestQdrantStore_Search_NumericID(t *testing.T) {
 	}
 }
 
+func TestQdrantStore_Scroll_Paginates(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/points/scroll") {
+			t.Errorf("expected /points/scroll, got %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+
+		if body["offset"] == nil {
+			json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{
+				"points":           []map[string]any{{"id": "1", "payload": map[string]any{"text": "a"}}},
+				"next_page_offset": "2",
+			}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{
+			"points":           []map[string]any{{"id": "2", "payload": map[string]any{"text": "b"}}},
+			"next_page_offset": nil,
+		}})
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(QdrantConfig{BaseURL: server.URL})
+	results, err := store.Scroll(context.Background(), ScrollParams{
+		Collection: "test_collection",
+		Filter:     &Filter{Must: []Condition{{Field: "file_id", Match: "file_1"}}},
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("Scroll failed: %v", err)
+	}
+	if len(results) != 2 || results[1].Payload["text"] != "b" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if len(requests) != 2 || requests[0]["filter"] == nil || requests[1]["limit"] != float64(9) {
+		t.Errorf("unexpected requests: %+v", requests)
+	}
+}
+
+func TestQdrantStore_ScrollPage_WithVectors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["with_vector"] != true {
+			t.Errorf("expected with_vector=true, got %v", body["with_vector"])
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"result": map[string]any{
+				"points": []map[string]any{
+					{"id": "a", "payload": map[string]any{"text": "x"}, "vector": []float64{0.5, -1}},
+				},
+				"next_page_offset": nil,
+			},
+		})
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(QdrantConfig{BaseURL: server.URL})
+	points, next, err := store.ScrollPage(context.Background(), ScrollParams{
+		Collection:  "test_collection",
+		Limit:       10,
+		WithVectors: true,
+	})
+	if err != nil {
+		t.Fatalf("ScrollPage failed: %v", err)
+	}
+	if next != "" || len(points) != 1 {
+		t.Fatalf("expected one point and no next page, got %d, %q", len(points), next)
+	}
+	if v := points[0].Vector; len(v) != 2 || v[0] != 0.5 || v[1] != -1 {
+		t.Errorf("unexpected vector: %v", v)
+	}
+}
+
 func TestQdrantStore_Delete_Success(t *testing.T) {
 	var receivedBody map[string]any
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -466,3 +619,28 @@ func BenchmarkQdrantStore_Search(b *testing.B) {
 		store.Search(ctx, params)
 	}
 }
+
+func TestQdrantStore_Ping(t *testing.T) {
+	ready := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			t.Errorf("expected /readyz path, got %s", r.URL.Path)
+		}
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("all shards are ready"))
+	}))
+	defer server.Close()
+
+	store := NewQdrantStore(QdrantConfig{BaseURL: server.URL})
+	if err := store.Ping(context.Background()); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+
+	ready = false
+	if err := store.Ping(context.Background()); err == nil {
+		t.Error("expected error when Qdrant is not ready")
+	}
+}
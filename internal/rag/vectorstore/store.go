@@ -24,6 +24,19 @@ type Store interface {
 	// Search finds the most similar points to a query vector.
 	Search(ctx context.Context, params SearchParams) ([]SearchResult, error)
 
+	// Scroll returns points matching a filter, in no particular order.
+	Scroll(ctx context.Context, params ScrollParams) ([]SearchResult, error)
+
+	// ScrollPage returns one page of up to Limit points starting at
+	// params.Offset, in ID order, and the offset of the next page. The next
+	// offset is empty after the last page.
+	ScrollPage(ctx context.Context, params ScrollParams) ([]SearchResult, string, error)
+
+	// SwapCollection atomically makes name refer to the target collection,
+	// then deletes the collection name referred to before. Reads and writes
+	// through name never see a missing collection once it has been swapped.
+	SwapCollection(ctx context.Context, name, target string) error
+
 	// Delete removes specific points from a collection by ID.
 	Delete(ctx context.Context, collection string, ids []string) error
 }
@@ -59,19 +72,51 @@ type SearchParams struct {
 	ScoreThreshold float32
 }
 
+// ScrollParams contains parameters for listing points without a query vector.
+type ScrollParams struct {
+	// Collection is the name of the collection to list.
+	Collection string
+
+	// Filter optionally restricts results to points matching conditions.
+	Filter *Filter
+
+	// Limit is the maximum number of points to return.
+	Limit int
+
+	// Offset is the point ID a ScrollPage call starts from (empty: the first
+	// point). Scroll ignores it.
+	Offset string
+
+	// WithVectors returns each point's vector along with its payload.
+	WithVectors bool
+}
+
 // Filter restricts search results based on payload fields.
 type Filter struct {
 	// Must contains conditions that must all be true.
 	Must []Condition
+
+	// Should contains conditions at least one of which must be true, when
+	// non-empty.
+	Should []Condition
 }
 
-// Condition is a single filter condition.
+// Condition is a single filter condition. Exactly one of Match, MatchAny
+// and IsEmpty is set. A condition on an array field matches when one of its
+// elements does.
 type Condition struct {
 	// Field is the payload field to filter on.
 	Field string
 
 	// Match is the value to match (exact match).
 	Match any
+
+	// MatchAny matches any of these values.
+	MatchAny []any
+
+	// IsEmpty matches points where the field is missing, null or an empty
+	// array.
+	IsEmpty bool
 }
 
 // SearchResult is a single search result.
@@ -84,6 +129,9 @@ type SearchResult struct {
 
 	// Payload contains the point's metadata.
 	Payload map[string]any
+
+	// Vector is the point's embedding, set only by scrolls that request it.
+	Vector []float32
 }
 
 // CollectionInfo contains metadata about a collection.
@@ -0,0 +1,129 @@
+// Package redact removes credentials from text before it is logged or
+// persisted.
+//
+// API keys reach logs and captured payloads in several ways: provider URLs
+// carry them as query parameters (Gemini's ?key=), SDK errors quote those
+// URLs, and request dumps include Authorization headers. Secrets scrubs the
+// known shapes; ReplaceAttr applies it to every log attribute.
+package redact
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Replacement replaces redacted credentials.
+const Replacement = "[REDACTED]"
+
+// secretPatterns match credentials. The first group, when present, is kept
+// so the redacted text still shows what was removed.
+var secretPatterns = []*regexp.Regexp{
+	// Query parameters: ?key=..., &api_key=..., &access_token=...
+	regexp.MustCompile(`(?i)([?&](?:key|api_?key|access_token|token)=)[^&\s"'#]+`),
+	// Headers and JSON fields: "Authorization": "Bearer ...", x-api-key: ...
+	regexp.MustCompile(`(?i)("?(?:authorization|proxy-authorization|x-api-key|x-goog-api-key|api-key|api_key|apikey)"?\s*[:=]\s*"?)(?:(?:bearer|basic)\s+)?[^"\s,;}]+`),
+	regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/-]+=*`),
+	// Airborne client keys keep their key ID: airborne_sk_<id>_<secret>
+	regexp.MustCompile(`(\bairborne_sk_[A-Za-z0-9]+_)[A-Za-z0-9_-]+`),
+	// Provider key shapes: OpenAI and Anthropic (sk-...), Google (AIza...)
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),
+}
+
+// sensitiveKeys are log attribute keys whose values are always redacted.
+var sensitiveKeys = map[string]bool{
+	"api_key":       true,
+	"apikey":        true,
+	"authorization": true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"admin_token":   true,
+	"signing_key":   true,
+}
+
+// Secrets returns s with credentials replaced by Replacement.
+func Secrets(s string) string {
+	for _, re := range secretPatterns {
+		if re.NumSubexp() > 0 {
+			s = re.ReplaceAllString(s, "${1}"+Replacement)
+		} else {
+			s = re.ReplaceAllLiteralString(s, Replacement)
+		}
+	}
+	return s
+}
+
+// JSON returns a captured HTTP payload with credentials replaced by
+// Replacement. The payload is returned as is when it contains none.
+func JSON(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	scrubbed := Secrets(string(body))
+	if scrubbed == string(body) {
+		return body
+	}
+	return []byte(scrubbed)
+}
+
+// credentialHeaders are HTTP headers that carry credentials.
+var credentialHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+	"Api-Key",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Header returns a copy of h with the values of credential headers replaced
+// by Replacement, for headers that are persisted.
+func Header(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	h = h.Clone()
+	for _, name := range credentialHeaders {
+		if _, ok := h[name]; ok {
+			h[name] = []string{Replacement}
+		}
+	}
+	return h
+}
+
+// ReplaceAttr is a slog.HandlerOptions.ReplaceAttr that redacts credentials
+// from string and error values, and the whole value of attributes named like
+// a credential.
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Replacement)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); mayContainSecret(s) {
+			return slog.String(a.Key, Secrets(s))
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok && err != nil {
+			return slog.String(a.Key, Secrets(err.Error()))
+		}
+	}
+	return a
+}
+
+// secretMarkers are substrings every secret pattern needs, so most log
+// values skip the regular expressions.
+var secretMarkers = []string{"=", ":", "earer", "sk", "AIza"}
+
+func mayContainSecret(s string) bool {
+	for _, m := range secretMarkers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,46 +2,229 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// Deployment modes.
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
 // Client wraps the Redis client with Airborne-specific operations
 type Client struct {
-	rdb *redis.Client
+	rdb  redis.UniversalClient
+	mode string
 }
 
 // Config holds Redis connection configuration
 type Config struct {
-	Addr     string
-	Password string
-	DB       int
+	Mode             string   // standalone (default), sentinel or cluster
+	Addr             string   // Standalone server address
+	Addrs            []string // Sentinel or cluster seed addresses
+	MasterName       string   // Sentinel master name
+	Username         string   // ACL username (Redis 6+)
+	Password         string
+	SentinelUsername string
+	SentinelPassword string
+	DB               int // Not supported in cluster mode
+	PoolSize         int // Per node; 0 uses the default of 10
+	TLS              TLSConfig
+}
+
+// TLSConfig holds TLS settings for Redis connections.
+type TLSConfig struct {
+	Enabled            bool
+	CAFile             string // PEM CA bundle; empty uses the system roots
+	CertFile           string // Client certificate for mutual TLS
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// Validate checks that the configuration is complete for its mode.
+func (c Config) Validate() error {
+	switch c.Mode {
+	case "", ModeStandalone:
+		if c.Addr == "" {
+			return errors.New("redis addr is required")
+		}
+	case ModeSentinel:
+		if len(c.Addrs) == 0 {
+			return errors.New("redis addrs (sentinel addresses) are required in sentinel mode")
+		}
+		if c.MasterName == "" {
+			return errors.New("redis master_name is required in sentinel mode")
+		}
+	case ModeCluster:
+		if len(c.Addrs) == 0 {
+			return errors.New("redis addrs (cluster seed nodes) are required in cluster mode")
+		}
+		if c.DB != 0 {
+			return errors.New("redis db must be 0 in cluster mode")
+		}
+	default:
+		return fmt.Errorf("invalid redis mode %q, must be 'standalone', 'sentinel' or 'cluster'", c.Mode)
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return errors.New("redis tls cert_file and key_file must be set together")
+	}
+	return nil
 }
 
-// NewClient creates a new Redis client
+// tlsConfig builds the client TLS configuration, or nil when TLS is disabled.
+func (c TLSConfig) tlsConfig() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in redis CA file %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load redis client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// NewClient creates a new Redis client for a standalone server, a Sentinel
+// managed master or a Redis Cluster, depending on cfg.Mode.
 func NewClient(cfg Config) (*Client, error) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:         cfg.Addr,
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 2,
-	})
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	tlsCfg, err := cfg.TLS.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	poolSize := cfg.PoolSize
+	if poolSize <= 0 {
+		poolSize = 10
+	}
+
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ModeStandalone
+	}
+
+	var rdb redis.UniversalClient
+	switch mode {
+	case ModeSentinel:
+		rdb = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelUsername: cfg.SentinelUsername,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        tlsCfg,
+			DialTimeout:      5 * time.Second,
+			ReadTimeout:      3 * time.Second,
+			WriteTimeout:     3 * time.Second,
+			PoolSize:         poolSize,
+			MinIdleConns:     2,
+		})
+	case ModeCluster:
+		rdb = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			TLSConfig:    tlsCfg,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolSize:     poolSize,
+			MinIdleConns: 2,
+		})
+	default:
+		rdb = redis.NewClient(&redis.Options{
+			Addr:         cfg.Addr,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			TLSConfig:    tlsCfg,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolSize:     poolSize,
+			MinIdleConns: 2,
+		})
+	}
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := rdb.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		rdb.Close()
+		return nil, fmt.Errorf("failed to connect to Redis (%s): %w", mode, err)
 	}
 
-	return &Client{rdb: rdb}, nil
+	return &Client{rdb: rdb, mode: mode}, nil
+}
+
+// HashTag wraps s in braces so that every key containing the tag hashes to
+// the same Redis Cluster slot. Use it for the part of a key shared by keys
+// that are read or written together, e.g. a client's rate-limit counters.
+func HashTag(s string) string {
+	return "{" + s + "}"
+}
+
+// Mode returns the deployment mode the client was created for.
+func (c *Client) Mode() string {
+	return c.mode
+}
+
+// PoolStats describes the connection pool, summed across nodes in cluster mode.
+type PoolStats struct {
+	Mode       string `json:"mode"`
+	Hits       uint32 `json:"hits"`     // Free connection found in the pool
+	Misses     uint32 `json:"misses"`   // New connection had to be dialled
+	Timeouts   uint32 `json:"timeouts"` // Waits for a connection that timed out
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"` // Connections removed as stale
+}
+
+// PoolStats returns the current connection pool statistics.
+func (c *Client) PoolStats() PoolStats {
+	s := c.rdb.PoolStats()
+	return PoolStats{
+		Mode:       c.mode,
+		Hits:       s.Hits,
+		Misses:     s.Misses,
+		Timeouts:   s.Timeouts,
+		TotalConns: s.TotalConns,
+		IdleConns:  s.IdleConns,
+		StaleConns: s.StaleConns,
+	}
 }
 
 // Close closes the Redis connection
@@ -125,14 +308,42 @@ func (c *Client) HDel(ctx context.Context, key string, fields ...string) error {
 	return c.rdb.HDel(ctx, key, fields...).Err()
 }
 
-// Scan iterates over keys matching a pattern
+// Scan iterates over keys matching a pattern. In cluster mode every master
+// is scanned, since each node only holds the keys of its own slots.
 func (c *Client) Scan(ctx context.Context, pattern string) ([]string, error) {
+	cluster, ok := c.rdb.(*redis.ClusterClient)
+	if !ok {
+		return scanNode(ctx, c.rdb, pattern)
+	}
+
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		batch, err := scanNode(ctx, node, pattern)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		keys = append(keys, batch...)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// scanNode runs a full SCAN against a single node.
+func scanNode(ctx context.Context, rdb redis.Cmdable, pattern string) ([]string, error) {
 	var keys []string
 	var cursor uint64
 	for {
 		var batch []string
 		var err error
-		batch, cursor, err = c.rdb.Scan(ctx, cursor, pattern, 100).Result()
+		batch, cursor, err = rdb.Scan(ctx, cursor, pattern, 100).Result()
 		if err != nil {
 			return nil, err
 		}
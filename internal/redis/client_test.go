@@ -60,3 +60,97 @@ func TestClient(t *testing.T) {
 		t.Errorf("expected nil error after Del, got %v", err)
 	}
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "standalone", cfg: Config{Addr: "localhost:6379"}},
+		{name: "standalone without addr", cfg: Config{Mode: ModeStandalone}, wantErr: true},
+		{name: "sentinel", cfg: Config{Mode: ModeSentinel, Addrs: []string{"s1:26379"}, MasterName: "mymaster"}},
+		{name: "sentinel without master", cfg: Config{Mode: ModeSentinel, Addrs: []string{"s1:26379"}}, wantErr: true},
+		{name: "sentinel without addrs", cfg: Config{Mode: ModeSentinel, MasterName: "mymaster"}, wantErr: true},
+		{name: "cluster", cfg: Config{Mode: ModeCluster, Addrs: []string{"n1:6379", "n2:6379"}}},
+		{name: "cluster without addrs", cfg: Config{Mode: ModeCluster}, wantErr: true},
+		{name: "cluster with db", cfg: Config{Mode: ModeCluster, Addrs: []string{"n1:6379"}, DB: 2}, wantErr: true},
+		{name: "unknown mode", cfg: Config{Mode: "ring", Addr: "localhost:6379"}, wantErr: true},
+		{name: "cert without key", cfg: Config{Addr: "localhost:6379", TLS: TLSConfig{Enabled: true, CertFile: "c.pem"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashTag(t *testing.T) {
+	if got := HashTag("client-1"); got != "{client-1}" {
+		t.Errorf("HashTag() = %q, want {client-1}", got)
+	}
+}
+
+func TestClient_ScanAndPoolStats(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	client, err := NewClient(Config{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	mr.Set("airborne:key:a", "1")
+	mr.Set("airborne:key:b", "2")
+	mr.Set("other", "3")
+
+	keys, err := client.Scan(context.Background(), "airborne:key:*")
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("Scan returned %v, want 2 keys", keys)
+	}
+
+	stats := client.PoolStats()
+	if stats.Mode != ModeStandalone || client.Mode() != ModeStandalone {
+		t.Errorf("mode = %q, want %q", stats.Mode, ModeStandalone)
+	}
+	if stats.TotalConns == 0 {
+		t.Errorf("expected open connections, got %+v", stats)
+	}
+}
+
+func TestNewClient_Cluster(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	client, err := NewClient(Config{Mode: ModeCluster, Addrs: []string{mr.Addr()}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Set(ctx, "airborne:key:a", "1", 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	keys, err := client.Scan(ctx, "airborne:key:*")
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(keys) != 1 || client.Mode() != ModeCluster {
+		t.Errorf("Scan returned %v in mode %q", keys, client.Mode())
+	}
+}
@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Degradation policies for Redis-dependent features.
+const (
+	// PolicyMemory falls back to process-local state while Redis is
+	// unavailable. Guarantees only hold per server instance.
+	PolicyMemory = "memory"
+	// PolicyFailClosed rejects requests that need the feature while Redis
+	// is unavailable.
+	PolicyFailClosed = "fail_closed"
+)
+
+// Features tracked by a FallbackTracker.
+const (
+	FeatureRateLimit   = "rate_limit"
+	FeatureIdempotency = "idempotency"
+)
+
+// ValidatePolicy checks that policy is a known degradation policy.
+func ValidatePolicy(policy string) error {
+	switch policy {
+	case PolicyMemory, PolicyFailClosed:
+		return nil
+	default:
+		return fmt.Errorf("unknown redis fallback policy %q (want %s or %s)", policy, PolicyMemory, PolicyFailClosed)
+	}
+}
+
+// FallbackStatus reports the degradation state of one feature.
+type FallbackStatus struct {
+	Feature     string    `json:"feature"`
+	Policy      string    `json:"policy"`
+	Active      bool      `json:"active"`          // Redis is currently failing for this feature
+	Since       time.Time `json:"since,omitempty"` // When the current degradation started
+	Activations int64     `json:"activations"`     // Requests served under the policy
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// FallbackTracker records when Redis-dependent features degrade and recover.
+// A nil tracker ignores all calls. It is safe for concurrent use.
+type FallbackTracker struct {
+	mu       sync.Mutex
+	features map[string]*FallbackStatus
+	now      func() time.Time
+}
+
+// NewFallbackTracker creates an empty tracker.
+func NewFallbackTracker() *FallbackTracker {
+	return &FallbackTracker{
+		features: make(map[string]*FallbackStatus),
+		now:      time.Now,
+	}
+}
+
+// Register declares a feature and its policy so it is reported even before
+// it first degrades.
+func (t *FallbackTracker) Register(feature, policy string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.feature(feature).Policy = policy
+}
+
+// feature returns the status for a feature, creating it. Callers hold mu.
+func (t *FallbackTracker) feature(name string) *FallbackStatus {
+	st, ok := t.features[name]
+	if !ok {
+		st = &FallbackStatus{Feature: name}
+		t.features[name] = st
+	}
+	return st
+}
+
+// Degraded records that a request for feature hit a Redis error and was
+// handled under the feature's policy.
+func (t *FallbackTracker) Degraded(feature string, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := t.feature(feature)
+	st.Activations++
+	if err != nil {
+		st.LastError = err.Error()
+	}
+	if !st.Active {
+		st.Active = true
+		st.Since = t.now()
+		slog.Warn("redis unavailable, feature degraded",
+			"feature", feature,
+			"policy", st.Policy,
+			"error", err,
+		)
+	}
+}
+
+// Recovered records that Redis served a request for feature again.
+func (t *FallbackTracker) Recovered(feature string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.features[feature]
+	if !ok || !st.Active {
+		return
+	}
+	slog.Info("redis recovered, feature restored",
+		"feature", feature,
+		"degraded_for", t.now().Sub(st.Since).Round(time.Second).String(),
+	)
+	st.Active = false
+	st.Since = time.Time{}
+}
+
+// Status returns the state of every known feature, sorted by name.
+func (t *FallbackTracker) Status() []FallbackStatus {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]FallbackStatus, 0, len(t.features))
+	for _, st := range t.features {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Feature < out[j].Feature })
+	return out
+}
+
+// Degradations returns only the features currently degraded.
+func (t *FallbackTracker) Degradations() []FallbackStatus {
+	var out []FallbackStatus
+	for _, st := range t.Status() {
+		if st.Active {
+			out = append(out, st)
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	for _, p := range []string{PolicyMemory, PolicyFailClosed} {
+		if err := ValidatePolicy(p); err != nil {
+			t.Errorf("ValidatePolicy(%q) = %v", p, err)
+		}
+	}
+	if err := ValidatePolicy("fail_open"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestFallbackTracker(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tr := NewFallbackTracker()
+	tr.now = func() time.Time { return now }
+
+	tr.Register(FeatureRateLimit, PolicyMemory)
+	tr.Register(FeatureIdempotency, PolicyFailClosed)
+	if got := tr.Degradations(); len(got) != 0 {
+		t.Fatalf("expected no degradations, got %+v", got)
+	}
+
+	tr.Degraded(FeatureRateLimit, errors.New("connection refused"))
+	now = now.Add(time.Second)
+	tr.Degraded(FeatureRateLimit, errors.New("i/o timeout"))
+
+	got := tr.Degradations()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 degradation, got %+v", got)
+	}
+	st := got[0]
+	if st.Feature != FeatureRateLimit || st.Policy != PolicyMemory || st.Activations != 2 {
+		t.Errorf("unexpected status: %+v", st)
+	}
+	if !st.Since.Equal(time.Unix(1_700_000_000, 0)) {
+		t.Errorf("expected Since to mark the first failure, got %v", st.Since)
+	}
+	if st.LastError != "i/o timeout" {
+		t.Errorf("expected last error, got %q", st.LastError)
+	}
+
+	tr.Recovered(FeatureRateLimit)
+	if got := tr.Degradations(); len(got) != 0 {
+		t.Errorf("expected recovery, got %+v", got)
+	}
+	all := tr.Status()
+	if len(all) != 2 || all[0].Feature != FeatureIdempotency || all[1].Activations != 2 {
+		t.Errorf("unexpected status after recovery: %+v", all)
+	}
+}
+
+func TestFallbackTracker_Nil(t *testing.T) {
+	var tr *FallbackTracker
+	tr.Register(FeatureRateLimit, PolicyMemory)
+	tr.Degraded(FeatureRateLimit, errors.New("down"))
+	tr.Recovered(FeatureRateLimit)
+	if tr.Status() != nil || tr.Degradations() != nil {
+		t.Error("expected nil tracker to report nothing")
+	}
+}
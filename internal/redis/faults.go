@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WithFaults runs inject before every command and pipeline, so chaos
+// testing can delay or fail Redis access. inject returns the error to fail
+// the command with, or nil.
+func (c *Client) WithFaults(inject func(ctx context.Context) error) {
+	c.rdb.AddHook(faultHook{inject: inject})
+}
+
+// faultHook is a go-redis hook that runs inject before sending commands.
+type faultHook struct {
+	inject func(ctx context.Context) error
+}
+
+func (h faultHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h faultHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.inject(ctx); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h faultHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.inject(ctx); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestClient_WithFaults(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	client, err := NewClient(Config{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	injected := errors.New("injected")
+	var fail bool
+	client.WithFaults(func(ctx context.Context) error {
+		if fail {
+			return injected
+		}
+		return nil
+	})
+
+	if err := client.Set(ctx, "key", "value", 0); err != nil {
+		t.Fatalf("Set failed without a fault: %v", err)
+	}
+
+	fail = true
+	if _, err := client.Get(ctx, "key"); !errors.Is(err, injected) {
+		t.Errorf("Get err = %v, want the injected fault", err)
+	}
+	if v, _ := mr.Get("key"); v != "value" {
+		t.Errorf("stored value = %q, want it untouched", v)
+	}
+}
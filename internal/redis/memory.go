@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// memorySweepEvery is how many writes pass between sweeps of expired keys.
+const memorySweepEvery = 1024
+
+// MemoryStore is a process-local stand-in for the subset of Redis used by
+// degraded features. State is not shared between server instances, so it
+// only gives single-node guarantees. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	items  map[string]memoryItem
+	writes int
+	now    func() time.Time
+}
+
+type memoryItem struct {
+	value     string
+	expiresAt time.Time // Zero means no expiry
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items: make(map[string]memoryItem),
+		now:   time.Now,
+	}
+}
+
+// get returns a live item, dropping it if expired. Callers hold mu.
+func (m *MemoryStore) get(key string) (memoryItem, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return memoryItem{}, false
+	}
+	if !item.expiresAt.IsZero() && !m.now().Before(item.expiresAt) {
+		delete(m.items, key)
+		return memoryItem{}, false
+	}
+	return item, true
+}
+
+// put stores an item and periodically sweeps expired keys. Callers hold mu.
+func (m *MemoryStore) put(key, value string, expiration time.Duration) {
+	item := memoryItem{value: value}
+	if expiration > 0 {
+		item.expiresAt = m.now().Add(expiration)
+	}
+	m.items[key] = item
+
+	m.writes++
+	if m.writes%memorySweepEvery == 0 {
+		now := m.now()
+		for k, it := range m.items {
+			if !it.expiresAt.IsZero() && !now.Before(it.expiresAt) {
+				delete(m.items, k)
+			}
+		}
+	}
+}
+
+// Get retrieves a value by key, returning redis.Nil when it does not exist.
+func (m *MemoryStore) Get(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.get(key)
+	if !ok {
+		return "", redis.Nil
+	}
+	return item.value, nil
+}
+
+// Set stores a value with optional expiration.
+func (m *MemoryStore) Set(_ context.Context, key string, value interface{}, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(key, toString(value), expiration)
+	return nil
+}
+
+// SetNX sets a value only if the key does not exist.
+func (m *MemoryStore) SetNX(_ context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.get(key); ok {
+		return false, nil
+	}
+	m.put(key, toString(value), expiration)
+	return true, nil
+}
+
+// Del deletes keys.
+func (m *MemoryStore) Del(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+	return nil
+}
+
+// IncrWindow adds by to a counter and returns the new value. A new counter
+// expires after window, matching the INCR + EXPIRE rate-limit scripts.
+func (m *MemoryStore) IncrWindow(key string, by int64, window time.Duration) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.get(key)
+	if !ok {
+		m.put(key, strconv.FormatInt(by, 10), window)
+		return by
+	}
+	n, _ := strconv.ParseInt(item.value, 10, 64)
+	n += by
+	item.value = strconv.FormatInt(n, 10)
+	m.items[key] = item // Keep the original expiry
+	return n
+}
+
+// toString formats a value the way go-redis encodes command arguments.
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	m := NewMemoryStore()
+	m.now = func() time.Time { return now }
+
+	if _, err := m.Get(ctx, "missing"); !IsNil(err) {
+		t.Fatalf("expected redis.Nil for missing key, got %v", err)
+	}
+
+	ok, _ := m.SetNX(ctx, "k", "v1", time.Minute)
+	if !ok {
+		t.Fatal("expected first SetNX to succeed")
+	}
+	ok, _ = m.SetNX(ctx, "k", "v2", time.Minute)
+	if ok {
+		t.Fatal("expected second SetNX to fail")
+	}
+	if v, _ := m.Get(ctx, "k"); v != "v1" {
+		t.Errorf("expected v1, got %q", v)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := m.Get(ctx, "k"); !IsNil(err) {
+		t.Errorf("expected key to expire, got %v", err)
+	}
+
+	_ = m.Set(ctx, "k", []byte("v3"), 0)
+	_ = m.Del(ctx, "k")
+	if _, err := m.Get(ctx, "k"); !IsNil(err) {
+		t.Errorf("expected key to be deleted, got %v", err)
+	}
+}
+
+func TestMemoryStore_IncrWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	m := NewMemoryStore()
+	m.now = func() time.Time { return now }
+
+	if n := m.IncrWindow("c", 1, time.Minute); n != 1 {
+		t.Errorf("expected 1, got %d", n)
+	}
+	now = now.Add(30 * time.Second)
+	if n := m.IncrWindow("c", 5, time.Minute); n != 6 {
+		t.Errorf("expected 6, got %d", n)
+	}
+
+	// The window is anchored at the first increment, not extended by later ones
+	now = now.Add(31 * time.Second)
+	if n := m.IncrWindow("c", 1, time.Minute); n != 1 {
+		t.Errorf("expected counter to reset after window, got %d", n)
+	}
+}
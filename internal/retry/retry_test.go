@@ -75,6 +75,29 @@ func TestIsRetryable(t *testing.T) {
 	}
 }
 
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("POST /v1/responses: 429 Too Many Requests"), true},
+		{errors.New("rate_limit_error: Number of request tokens has exceeded your rate limit"), true},
+		{errors.New("Error 429, Message: Resource has been exhausted (e.g. check quota)."), true},
+		{errors.New("503 service unavailable"), false},
+		{errors.New("Error 400, Message: Quota exceeded for store size, Status: INVALID_ARGUMENT"), false},
+		{errors.New("storage quota exceeded: 14290 bytes over"), false},
+		{errors.New("rpc error: code = Unknown desc = Status: RESOURCE_EXHAUSTED"), true},
+		{context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRateLimited(tt.err); got != tt.want {
+			t.Errorf("IsRateLimited(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
 func TestSleepWithBackoff_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
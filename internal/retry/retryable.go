@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"regexp"
 	"strings"
 )
 
@@ -63,3 +64,23 @@ func IsRetryable(err error) bool {
 
 	return false
 }
+
+// rateLimitStatus matches an HTTP 429 status in an error message, but not
+// 429 as part of a longer number such as a token count.
+var rateLimitStatus = regexp.MustCompile(`(?:^|[^\d.])429(?:[^\d.]|$)`)
+
+// IsRateLimited reports whether an error is a provider rate-limit rejection:
+// an HTTP 429, a RESOURCE_EXHAUSTED status or a rate-limit error type such
+// as rate_limit_error. Other errors that mention quotas are not matched.
+func IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errStr := err.Error()
+	if rateLimitStatus.MatchString(errStr) {
+		return true
+	}
+	errStr = strings.ToLower(errStr)
+	return strings.Contains(errStr, "resource_exhausted") || strings.Contains(errStr, "rate_limit")
+}
@@ -0,0 +1,257 @@
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/admin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+// checkTimeout bounds each check.
+const checkTimeout = 30 * time.Second
+
+// uploadText is the file the upload and retrieval checks store.
+const uploadText = "Airborne self-test document.\n\nThe self-test uploads this file and retrieves it again.\n"
+
+// suite runs the checks against one environment, sharing the state later
+// checks depend on (the chat request, the uploaded store).
+type suite struct {
+	env    *environment
+	token  string
+	chat   pb.AirborneServiceClient
+	files  pb.FileServiceClient
+	admin  pb.AdminServiceClient
+	report *Report
+
+	requestID string // Of the chat check, found again in the admin activity
+	storeID   string // Created by the upload check
+}
+
+// run runs every check, skipping none: a failed check is reported and the
+// next one still runs.
+func (s *suite) run(ctx context.Context) {
+	s.step(ctx, "chat", s.checkChat)
+	s.step(ctx, "chat stream", s.checkStream)
+	s.step(ctx, "file upload", s.checkUpload)
+	s.step(ctx, "rag retrieval", s.checkRetrieve)
+	s.step(ctx, "admin grpc health", s.checkAdminHealth)
+	s.step(ctx, "admin grpc providers", s.checkProviderHealth)
+	s.step(ctx, "admin http health", s.checkHTTPHealth)
+	s.step(ctx, "admin http version", s.checkHTTPVersion)
+	s.step(ctx, "admin http activity", s.checkHTTPActivity)
+}
+
+// step runs one check with the admin token attached and records its outcome.
+func (s *suite) step(ctx context.Context, name string, check func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.token)
+
+	start := time.Now()
+	err := check(ctx)
+	s.report.Steps = append(s.report.Steps, Step{Name: name, Duration: time.Since(start), Err: err})
+}
+
+func (s *suite) checkChat(ctx context.Context) error {
+	s.requestID = uuid.NewString()
+	resp, err := s.chat.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		RequestId: s.requestID,
+		UserInput: "Airborne self-test: reply to this message.",
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Text == "" {
+		return errors.New("empty reply")
+	}
+	if resp.Usage == nil {
+		return errors.New("reply without usage")
+	}
+	return nil
+}
+
+func (s *suite) checkStream(ctx context.Context) error {
+	stream, err := s.chat.GenerateReplyStream(ctx, &pb.GenerateReplyRequest{
+		RequestId: uuid.NewString(),
+		UserInput: "Airborne self-test: stream a reply to this message.",
+	})
+	if err != nil {
+		return err
+	}
+	var text strings.Builder
+	var complete bool
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch c := chunk.Chunk.(type) {
+		case *pb.GenerateReplyChunk_TextDelta:
+			text.WriteString(c.TextDelta.GetText())
+		case *pb.GenerateReplyChunk_Complete:
+			complete = true
+		case *pb.GenerateReplyChunk_Error:
+			return fmt.Errorf("stream error %s: %s", c.Error.Code, c.Error.Message)
+		}
+	}
+	if text.Len() == 0 {
+		return errors.New("no text streamed")
+	}
+	if !complete {
+		return errors.New("stream ended without completing")
+	}
+	return nil
+}
+
+func (s *suite) checkUpload(ctx context.Context) error {
+	store, err := s.files.CreateFileStore(ctx, &pb.CreateFileStoreRequest{Name: "selftest"})
+	if err != nil {
+		return fmt.Errorf("create store: %w", err)
+	}
+	s.storeID = store.StoreId
+
+	stream, err := s.files.UploadFile(ctx)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Metadata{Metadata: &pb.UploadFileMetadata{
+		StoreId:  s.storeID,
+		Filename: "selftest.txt",
+		MimeType: "text/plain",
+		Size:     int64(len(uploadText)),
+	}}})
+	if err == nil {
+		err = stream.Send(&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: []byte(uploadText)}})
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if resp.Status != "ready" {
+		return fmt.Errorf("upload status %q, want ready", resp.Status)
+	}
+	return nil
+}
+
+func (s *suite) checkRetrieve(ctx context.Context) error {
+	if s.storeID == "" {
+		return errors.New("no store: file upload failed")
+	}
+	resp, err := s.files.Retrieve(ctx, &pb.RetrieveRequest{StoreId: s.storeID, Query: "self-test document"})
+	if err != nil {
+		return err
+	}
+	for _, chunk := range resp.Chunks {
+		if chunk.Filename == "selftest.txt" && chunk.Text != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("retrieved %d chunks, none from the uploaded file", len(resp.Chunks))
+}
+
+func (s *suite) checkAdminHealth(ctx context.Context) error {
+	resp, err := s.admin.Health(ctx, &pb.HealthRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Status != "healthy" {
+		return fmt.Errorf("status %q, want healthy", resp.Status)
+	}
+	return nil
+}
+
+func (s *suite) checkProviderHealth(ctx context.Context) error {
+	resp, err := s.admin.GetProviderHealth(ctx, &pb.GetProviderHealthRequest{TenantId: TenantID})
+	if err != nil {
+		return err
+	}
+	for _, p := range resp.Providers {
+		if p.LastSuccessUnix > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("no successful call recorded across %d providers", len(resp.Providers))
+}
+
+func (s *suite) checkHTTPHealth(ctx context.Context) error {
+	var resp admin.HealthResponse
+	if err := s.getJSON(ctx, "/admin/health", &resp); err != nil {
+		return err
+	}
+	if resp.Status != "healthy" || resp.Database != "healthy" {
+		return fmt.Errorf("status %q, database %q, want both healthy", resp.Status, resp.Database)
+	}
+	return nil
+}
+
+func (s *suite) checkHTTPVersion(ctx context.Context) error {
+	var resp admin.VersionInfo
+	if err := s.getJSON(ctx, "/admin/version", &resp); err != nil {
+		return err
+	}
+	if resp.Version != s.env.version {
+		return fmt.Errorf("version %q, want %q", resp.Version, s.env.version)
+	}
+	return nil
+}
+
+// checkHTTPActivity waits for the chat check's conversation, which is
+// persisted asynchronously, to show in the activity feed.
+func (s *suite) checkHTTPActivity(ctx context.Context) error {
+	if s.requestID == "" {
+		return errors.New("no conversation: chat failed")
+	}
+	for {
+		var resp admin.ActivityResponse
+		if err := s.getJSON(ctx, "/admin/activity?tenant_id="+TenantID, &resp); err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		for _, item := range resp.Activity {
+			if item.ThreadID == s.requestID {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("chat conversation not in activity: %w", ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// getJSON gets path from the admin HTTP server and decodes its JSON body.
+func (s *suite) getJSON(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.env.adminURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
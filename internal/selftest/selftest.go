@@ -0,0 +1,228 @@
+// Package selftest runs an end-to-end smoke test of the server: it starts
+// the gRPC and admin servers in-process with mock providers, a temporary
+// SQLite database and in-memory RAG backends, then exercises chat,
+// streaming, file upload, RAG retrieval and the admin endpoints through
+// real clients. It needs no external services, so it can gate deployments
+// where the full test suite cannot run.
+package selftest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/admin"
+	"github.com/ai8future/airborne/internal/config"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/provider/mock"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/server"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TenantID is the tenant the self-test runs as. It is one of the tenants
+// whose conversations are persisted, so admin activity can be checked.
+const TenantID = "zztest"
+
+// embeddingDims is the dimension of the in-memory embeddings.
+const embeddingDims = 64
+
+// Step is the outcome of one check.
+type Step struct {
+	Name     string
+	Duration time.Duration
+	Err      error // nil when the check passed
+}
+
+// Report holds the outcome of every check, in the order they ran.
+type Report struct {
+	Steps []Step
+}
+
+// Failed returns the checks that failed.
+func (r *Report) Failed() []Step {
+	var failed []Step
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			failed = append(failed, s)
+		}
+	}
+	return failed
+}
+
+// Print writes one line per check to w.
+func (r *Report) Print(w io.Writer) {
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			fmt.Fprintf(w, "FAIL  %-20s %v\n", s.Name, s.Err)
+			continue
+		}
+		fmt.Fprintf(w, "ok    %-20s %v\n", s.Name, s.Duration.Round(time.Millisecond))
+	}
+	if failed := len(r.Failed()); failed > 0 {
+		fmt.Fprintf(w, "selftest failed: %d of %d checks\n", failed, len(r.Steps))
+	} else {
+		fmt.Fprintf(w, "selftest passed: %d checks\n", len(r.Steps))
+	}
+}
+
+// Run starts an in-process server and runs every check against it. It
+// returns an error only if the server cannot be started; failed checks are
+// recorded in the report.
+func Run(ctx context.Context, version server.VersionInfo) (*Report, error) {
+	dir, err := os.MkdirTemp("", "airborne-selftest-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := start(dir, token, version)
+	if err != nil {
+		return nil, err
+	}
+	defer env.stop()
+
+	s := &suite{
+		env:    env,
+		token:  token,
+		chat:   pb.NewAirborneServiceClient(env.conn),
+		files:  pb.NewFileServiceClient(env.conn),
+		admin:  pb.NewAdminServiceClient(env.conn),
+		report: &Report{},
+	}
+	s.run(ctx)
+	return s.report, nil
+}
+
+// Config returns the server configuration the self-test runs with: mock
+// providers, static auth with token, SQLite under dir and RAG enabled.
+func Config(dir, token string) *config.Config {
+	cfg := config.Default()
+	cfg.StartupMode = config.StartupModeDevelopment
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Auth.AuthMode = "static"
+	cfg.Auth.AdminToken = token
+	cfg.Database.Enabled = true
+	cfg.Database.Driver = db.DriverSQLite
+	cfg.Database.URL = filepath.Join(dir, "airborne.db")
+	cfg.RAG.Enabled = true
+	cfg.RAG.UploadDir = filepath.Join(dir, "uploads")
+	cfg.MockProviders = config.MockProvidersConfig{Enabled: true}
+	cfg.Admin.Enabled = true
+	return cfg
+}
+
+// Tenants returns a tenant manager serving only TenantID, with every
+// provider enabled.
+func Tenants() *tenant.Manager {
+	providers := make(map[string]tenant.ProviderConfig)
+	for _, name := range []string{provider.NameOpenAI, provider.NameGemini, provider.NameAnthropic} {
+		providers[name] = tenant.ProviderConfig{Enabled: true, APIKey: "selftest", Model: mock.DefaultModel}
+	}
+	return &tenant.Manager{Tenants: map[string]tenant.TenantConfig{
+		TenantID: {TenantID: TenantID, DisplayName: "Self-test", Providers: providers},
+	}}
+}
+
+// environment is a running in-process server and a client connection to it.
+type environment struct {
+	grpcServer *grpc.Server
+	components *server.ServerComponents
+	admin      *admin.Server
+	adminURL   string
+	conn       *grpc.ClientConn
+	version    string
+}
+
+// start starts the gRPC and admin servers on loopback ports.
+func start(dir, token string, version server.VersionInfo) (*environment, error) {
+	cfg := Config(dir, token)
+	grpcServer, components, err := server.NewGRPCServer(cfg, version,
+		server.WithTenants(Tenants()),
+		server.WithRAGBackends(testutil.NewMockEmbedder(embeddingDims), testutil.NewMockStore(), testutil.NewMockExtractor()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC server: %w", err)
+	}
+	if components.DBClient == nil {
+		components.Close()
+		return nil, errors.New("failed to open the self-test database")
+	}
+	env := &environment{grpcServer: grpcServer, components: components, version: version.Version}
+
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		env.stop()
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	go grpcServer.Serve(grpcLis)
+
+	adminLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		env.stop()
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	env.admin = admin.NewServer(components.DBClient, admin.Config{
+		GRPCAddr:    grpcLis.Addr().String(),
+		AuthToken:   token,
+		TenantMgr:   components.TenantMgr,
+		RedisClient: components.RedisClient,
+		Metrics:     components.Metrics,
+		Version: admin.VersionInfo{
+			Version:   version.Version,
+			GitCommit: version.GitCommit,
+			BuildTime: version.BuildTime,
+		},
+	})
+	env.adminURL = "http://" + adminLis.Addr().String()
+	go env.admin.Serve(adminLis)
+
+	env.conn, err = grpc.NewClient(grpcLis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		env.stop()
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return env, nil
+}
+
+// stop closes the client connection and stops the servers.
+func (e *environment) stop() {
+	if e.conn != nil {
+		e.conn.Close()
+	}
+	if e.admin != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := e.admin.Shutdown(ctx); err != nil {
+			slog.Warn("selftest: admin server shutdown failed", "error", err)
+		}
+		cancel()
+	}
+	e.grpcServer.Stop()
+	e.components.Close()
+}
+
+// randomToken returns a random admin token for one run.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate admin token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,28 @@
+package selftest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/server"
+)
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), server.VersionInfo{Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, step := range report.Failed() {
+		t.Errorf("%s: %v", step.Name, step.Err)
+	}
+	if len(report.Steps) != 9 {
+		t.Errorf("ran %d checks, want 9", len(report.Steps))
+	}
+
+	var out strings.Builder
+	report.Print(&out)
+	if !strings.Contains(out.String(), "selftest passed: 9 checks") {
+		t.Errorf("output = %q, want a passing summary", out.String())
+	}
+}
@@ -2,16 +2,31 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"runtime"
 	"time"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/anomaly"
 	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/chaos"
 	"github.com/ai8future/airborne/internal/config"
 	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/events"
+	"github.com/ai8future/airborne/internal/headroom"
+	"github.com/ai8future/airborne/internal/httpcapture"
 	"github.com/ai8future/airborne/internal/imagegen"
+	"github.com/ai8future/airborne/internal/isolation"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/modelcatalog"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/provider/mock"
+	"github.com/ai8future/airborne/internal/providerhealth"
+	"github.com/ai8future/airborne/internal/qos"
 	"github.com/ai8future/airborne/internal/rag"
 	"github.com/ai8future/airborne/internal/rag/embedder"
 	"github.com/ai8future/airborne/internal/rag/extractor"
@@ -19,10 +34,14 @@ import (
 	"github.com/ai8future/airborne/internal/redis"
 	"github.com/ai8future/airborne/internal/service"
 	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
 
@@ -40,13 +59,56 @@ type ServerComponents struct {
 	TenantMgr   *tenant.Manager
 	RedisClient *redis.Client
 	DBClient    *db.Client
+	Metrics     *metrics.Registry
+	GeoIP       auth.GeoIP // Nil unless auth.geoip_file is set
+
+	stopBackground context.CancelFunc // Stops upload cleanup, rollup and event workers
+	publishers     []events.Publisher // Event stream connections
+	health         *health.Server
+	stopHealth     context.CancelFunc // Stops dependency health probes
+}
+
+// Option customizes the dependencies NewGRPCServer uses.
+type Option func(*serverOptions)
+
+type serverOptions struct {
+	tenants  *tenant.Manager
+	embedder embedder.Embedder
+	store    vectorstore.Store
+	extract  extractor.Extractor
+}
+
+// WithTenants serves mgr's tenants instead of loading tenant configs.
+func WithTenants(mgr *tenant.Manager) Option {
+	return func(o *serverOptions) {
+		o.tenants = mgr
+	}
+}
+
+// WithRAGBackends uses emb, store and ext for RAG instead of the Ollama,
+// Qdrant and Docbox services in the RAG config. RAG must still be enabled.
+func WithRAGBackends(emb embedder.Embedder, store vectorstore.Store, ext extractor.Extractor) Option {
+	return func(o *serverOptions) {
+		o.embedder = emb
+		o.store = store
+		o.extract = ext
+	}
 }
 
 // NewGRPCServer creates a new gRPC server with all services registered
 // Returns the server and components needed by admin HTTP server
-func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *ServerComponents, error) {
+func NewGRPCServer(cfg *config.Config, version VersionInfo, options ...Option) (*grpc.Server, *ServerComponents, error) {
+	var o serverOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+
 	// Load tenant configurations
-	tenantMgr, err := tenant.Load("")
+	tenantMgr := o.tenants
+	var err error
+	if tenantMgr == nil {
+		tenantMgr, err = tenant.Load("")
+	}
 	if err != nil {
 		slog.Warn("tenant config not loaded - running in single-tenant legacy mode", "error", err)
 		// Create an empty manager for legacy mode
@@ -63,24 +125,25 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 	var keyStore *auth.KeyStore
 	var rateLimiter *auth.RateLimiter
 	var tenantInterceptor *auth.TenantInterceptor
+	var redisFallbacks *redis.FallbackTracker
 
 	if cfg.Auth.AuthMode == "redis" {
 		// Redis-based auth (existing behavior)
-		redisClient, err = redis.NewClient(redis.Config{
-			Addr:     cfg.Redis.Addr,
-			Password: cfg.Redis.Password,
-			DB:       cfg.Redis.DB,
-		})
+		redisClient, err = redis.NewClient(cfg.Redis.ClientConfig())
 		if err != nil {
 			return nil, nil, fmt.Errorf("redis required for auth_mode=redis: %w", err)
 		}
+		if inj := chaos.NewInjector(cfg.Chaos, chaos.BoundaryRedis); inj != nil {
+			redisClient.WithFaults(inj.Inject)
+		}
 		keyStore = auth.NewKeyStore(redisClient)
+		redisFallbacks = redis.NewFallbackTracker()
 		rateLimiter = auth.NewRateLimiter(redisClient, auth.RateLimits{
 			RequestsPerMinute: cfg.RateLimits.DefaultRPM,
 			RequestsPerDay:    cfg.RateLimits.DefaultRPD,
 			TokensPerMinute:   cfg.RateLimits.DefaultTPM,
-		}, true)
-		slog.Info("using Redis-based authentication")
+		}, true, auth.WithRateLimitFallback(cfg.Redis.Fallback.RateLimit, redisFallbacks))
+		slog.Info("using Redis-based authentication", "redis_mode", redisClient.Mode())
 	} else {
 		// Static token auth (default)
 		if cfg.Auth.AdminToken == "" {
@@ -95,13 +158,69 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 	}
 
 	// Build interceptor chains
+	metricsRegistry := metrics.NewRegistry()
+	if redisClient != nil {
+		metricsRegistry.SetRedisSource(func() metrics.RedisStats {
+			s := redisClient.PoolStats()
+			return metrics.RedisStats{
+				Mode:       s.Mode,
+				Hits:       s.Hits,
+				Misses:     s.Misses,
+				Timeouts:   s.Timeouts,
+				TotalConns: s.TotalConns,
+				IdleConns:  s.IdleConns,
+				StaleConns: s.StaleConns,
+				Fallbacks:  redisFallbackStats(redisFallbacks),
+			}
+		})
+	}
+
+	// Count blocked cross-tenant accesses into the same registry
+	isolation.SetObserver(func(v isolation.Violation) {
+		metricsRegistry.ObserveIsolationViolation(metrics.IsolationObservation{
+			Layer:        v.Layer,
+			Operation:    v.Operation,
+			TenantID:     v.TenantID,
+			TargetTenant: v.TargetTenant,
+		})
+	})
+
+	// Audit outbound requests into the same registry
+	auditor, err := egress.NewAuditor(cfg.Egress.Audit, metricsRegistry)
+	if err != nil {
+		return nil, nil, fmt.Errorf("egress audit: %w", err)
+	}
+	egress.SetAuditor(auditor)
+	if cfg.Chaos.Enabled {
+		slog.Warn("chaos fault injection enabled: provider, Redis, database and RAG calls may be delayed or fail")
+	}
+	if cfg.Egress.Audit.Enforce {
+		slog.Info("egress enforcement enabled", "allowed_hosts", cfg.Egress.Audit.AllowedHosts)
+	}
+
+	fixtures, err := httpcapture.NewFixtures(cfg.Egress.Fixtures)
+	if err != nil {
+		return nil, nil, fmt.Errorf("egress fixtures: %w", err)
+	}
+	httpcapture.SetFixtures(fixtures)
+	if fixtures != nil {
+		slog.Warn("provider HTTP fixtures enabled", "mode", cfg.Egress.Fixtures.Mode, "dir", cfg.Egress.Fixtures.Dir)
+	}
+
+	accessLogger := accesslog.New(accesslog.Config{
+		SampleRate:    cfg.Logging.AccessLog.SampleRate,
+		SlowThreshold: time.Duration(cfg.Logging.AccessLog.SlowThresholdMs) * time.Millisecond,
+	}, metricsRegistry)
+
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		tracing.UnaryInterceptor(),
 		recoveryInterceptor(),
-		loggingInterceptor(),
+		accessLogger.UnaryInterceptor(),
 	}
 	streamInterceptors := []grpc.StreamServerInterceptor{
+		tracing.StreamInterceptor(),
 		streamRecoveryInterceptor(),
-		streamLoggingInterceptor(),
+		accessLogger.StreamInterceptor(),
 	}
 
 	// Add tenant interceptor first (validates tenant before auth)
@@ -110,9 +229,34 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 		streamInterceptors = append(streamInterceptors, tenantInterceptor.StreamInterceptor())
 	}
 
-	// Add auth interceptors based on mode
+	// Country restrictions on keys, signed requests and the admin server
+	// resolve addresses with the GeoIP database
+	var geo auth.GeoIP
+	if cfg.Auth.GeoIPFile != "" {
+		geoDB, err := auth.LoadGeoIPCSV(cfg.Auth.GeoIPFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load geoip file: %w", err)
+		}
+		geo = geoDB
+	}
+
+	// Verify signed admin dashboard requests; unsigned requests fall through to token auth
+	if signing := cfg.Auth.InternalSigning; signing.Enabled {
+		verifier := auth.NewSignatureVerifier(signing.SigningKeys(), time.Duration(signing.MaxSkewSec)*time.Second)
+		verifier.SetNetwork(signing.Network, geo)
+		unaryInterceptors = append(unaryInterceptors, verifier.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, verifier.StreamInterceptor())
+		slog.Info("internal request signing enabled", "active_key", signing.ActiveKey, "keys", len(signing.Keys))
+	}
+
+	// Add auth interceptors based on mode. Session tokens are minted with
+	// Redis API keys, so they need the Redis mode too.
+	var sessions *auth.SessionStore
 	if cfg.Auth.AuthMode == "redis" && keyStore != nil {
+		sessions = auth.NewSessionStore(redisClient)
 		authenticator := auth.NewAuthenticator(keyStore, rateLimiter)
+		authenticator.SetGeoIP(geo)
+		authenticator.SetSessionStore(sessions)
 		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryInterceptor())
 		streamInterceptors = append(streamInterceptors, authenticator.StreamInterceptor())
 	} else if cfg.Auth.AuthMode != "redis" {
@@ -122,6 +266,10 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 		streamInterceptors = append(streamInterceptors, staticAuth.StreamInterceptor())
 	}
 
+	// Capture tenant and client for access logs once auth has run
+	unaryInterceptors = append(unaryInterceptors, accesslog.UnaryAnnotator())
+	streamInterceptors = append(streamInterceptors, accesslog.StreamAnnotator())
+
 	// Build server options
 	opts := []grpc.ServerOption{
 		// Keepalive settings
@@ -160,33 +308,45 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 
 	// Initialize RAG service if enabled (before ChatService so it can use it)
 	var ragService *rag.Service
+	var emb embedder.Embedder
+	var qdrant *vectorstore.QdrantStore
 	if cfg.RAG.Enabled {
 		// Initialize RAG components
-		emb := embedder.NewOllamaEmbedder(embedder.OllamaConfig{
-			BaseURL: cfg.RAG.OllamaURL,
-			Model:   cfg.RAG.EmbeddingModel,
-		})
-
-		store := vectorstore.NewQdrantStore(vectorstore.QdrantConfig{
-			BaseURL: cfg.RAG.QdrantURL,
-		})
-
-		ext := extractor.NewDocboxExtractor(extractor.DocboxConfig{
-			BaseURL: cfg.RAG.DocboxURL,
-		})
+		emb = o.embedder
+		store := o.store
+		ext := o.extract
+		if store == nil {
+			emb = embedder.NewOllamaEmbedder(embedder.OllamaConfig{
+				BaseURL: cfg.RAG.OllamaURL,
+				Model:   cfg.RAG.EmbeddingModel,
+			})
+
+			qdrant = vectorstore.NewQdrantStore(vectorstore.QdrantConfig{
+				BaseURL: cfg.RAG.QdrantURL,
+			})
+			store = qdrant
+
+			ext = extractor.NewDocboxExtractor(extractor.DocboxConfig{
+				BaseURL: cfg.RAG.DocboxURL,
+			})
+
+			slog.Info("RAG enabled",
+				"ollama_url", cfg.RAG.OllamaURL,
+				"embedding_model", cfg.RAG.EmbeddingModel,
+				"qdrant_url", cfg.RAG.QdrantURL,
+				"docbox_url", cfg.RAG.DocboxURL,
+			)
+		} else {
+			slog.Info("RAG enabled with in-process backends")
+		}
 
-		ragService = rag.NewService(emb, store, ext, rag.ServiceOptions{
+		ragFaults := chaos.NewInjector(cfg.Chaos, chaos.BoundaryRAG)
+		emb = chaos.Embedder(emb, ragFaults)
+		ragService = rag.NewService(emb, chaos.Store(store, ragFaults), ext, rag.ServiceOptions{
 			ChunkSize:     cfg.RAG.ChunkSize,
 			ChunkOverlap:  cfg.RAG.ChunkOverlap,
 			RetrievalTopK: cfg.RAG.RetrievalTopK,
 		})
-
-		slog.Info("RAG enabled",
-			"ollama_url", cfg.RAG.OllamaURL,
-			"embedding_model", cfg.RAG.EmbeddingModel,
-			"qdrant_url", cfg.RAG.QdrantURL,
-			"docbox_url", cfg.RAG.DocboxURL,
-		)
 	}
 
 	// Create image generation client
@@ -197,9 +357,12 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 	if cfg.Database.Enabled {
 		var dbErr error
 		dbClient, dbErr = db.NewClient(context.Background(), db.Config{
+			Driver:         cfg.Database.Driver,
 			URL:            cfg.Database.URL,
+			ReplicaURL:     cfg.Database.ReplicaURL,
 			MaxConnections: cfg.Database.MaxConnections,
 			LogQueries:     cfg.Database.LogQueries,
+			Outbox:         cfg.Events.Enabled,
 			CACert:         cfg.Database.CACert,
 		})
 		if dbErr != nil {
@@ -207,11 +370,71 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 			// Continue without database - it's optional
 		} else {
 			slog.Info("database connection established for message persistence")
+			if inj := chaos.NewInjector(cfg.Chaos, chaos.BoundaryDatabase); inj != nil {
+				dbClient.WithFaults(inj.Inject)
+			}
+			if tenantMgr != nil {
+				restoreTenantOverrides(tenantMgr, dbClient)
+			}
+			auth.SetNetworkViolationSink(networkViolationSink(db.NewRepository(dbClient)))
 		}
 	}
 
 	// Register services
-	chatService := service.NewChatService(rateLimiter, ragService, imageGenClient, dbClient)
+	// Provider health and headroom are tracked for the dashboard even when
+	// requests are not held for headroom
+	providerHealth := providerhealth.NewTracker(0)
+	headroomTracker := headroom.NewTracker()
+	chatOpts := []service.ChatServiceOption{
+		service.WithModelCatalog(modelcatalog.New(cfg.Models.Deny)),
+		service.WithMetrics(metricsRegistry),
+		service.WithProviderHealth(providerHealth),
+		service.WithAnomalyDetection(anomaly.NewDetector()),
+	}
+	if emb != nil {
+		chatOpts = append(chatOpts, service.WithEmbedder(emb))
+	}
+	if cfg.MockProviders.Enabled {
+		slog.Warn("mock providers enabled: OpenAI, Gemini and Anthropic requests will not reach the providers")
+		chatOpts = append(chatOpts, service.WithProviders(
+			mock.NewClient(provider.NameOpenAI, cfg.MockProviders.ProviderConfig(provider.NameOpenAI)),
+			mock.NewClient(provider.NameGemini, cfg.MockProviders.ProviderConfig(provider.NameGemini)),
+			mock.NewClient(provider.NameAnthropic, cfg.MockProviders.ProviderConfig(provider.NameAnthropic)),
+		))
+	}
+	if inj := chaos.NewInjector(cfg.Chaos, chaos.BoundaryProvider); inj != nil {
+		chatOpts = append(chatOpts, service.WithProviderFaults(inj))
+	}
+	if cfg.QoS.MaxConcurrent > 0 {
+		chatOpts = append(chatOpts, service.WithQoS(qos.NewLimiter(qos.Config{
+			MaxConcurrent:  cfg.QoS.MaxConcurrent,
+			MaxQueue:       cfg.QoS.MaxQueue,
+			PressureWindow: time.Duration(cfg.QoS.PressureWindowSec) * time.Second,
+		})))
+	}
+	if cfg.QoS.ProviderHeadroom {
+		chatOpts = append(chatOpts, service.WithHeadroom(headroomTracker, time.Duration(cfg.QoS.HeadroomMaxWaitMs)*time.Millisecond))
+	} else {
+		chatOpts = append(chatOpts, service.WithHeadroomTracking(headroomTracker))
+	}
+	if cfg.QoS.DedupWindowMs > 0 {
+		chatOpts = append(chatOpts, service.WithDedup(time.Duration(cfg.QoS.DedupWindowMs)*time.Millisecond))
+	}
+	if dbClient != nil {
+		chatOpts = append(chatOpts, service.WithUsageLedger(db.NewRepository(dbClient)))
+	}
+	if sessions != nil {
+		chatOpts = append(chatOpts, service.WithSessionStore(sessions))
+	}
+	if redisClient != nil {
+		chatOpts = append(chatOpts,
+			service.WithIdempotency(redisClient, 0),
+			service.WithIdempotencyFallback(cfg.Redis.Fallback.Idempotency, redisFallbacks),
+			service.WithBudgets(redisClient),
+			service.WithToolTurnStore(redisClient),
+		)
+	}
+	chatService := service.NewChatService(rateLimiter, ragService, imageGenClient, dbClient, chatOpts...)
 	pb.RegisterAirborneServiceServer(server, chatService)
 
 	adminService := service.NewAdminService(redisClient, service.AdminServiceConfig{
@@ -219,15 +442,53 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 		GitCommit: version.GitCommit,
 		BuildTime: version.BuildTime,
 		GoVersion: runtime.Version(),
+
+		RedisFallbacks: redisFallbacks,
+		Tenants:        tenantMgr,
+		ProviderHealth: providerHealth,
+		Headroom:       headroomTracker,
 	})
 	pb.RegisterAdminServiceServer(server, adminService)
 
 	// Register FileService if RAG is enabled
+	var fileService *service.FileService
 	if ragService != nil {
-		fileService := service.NewFileService(ragService, rateLimiter)
+		fileOpts := []service.FileServiceOption{
+			service.WithUploadSessions(cfg.RAG.UploadDir, time.Duration(cfg.RAG.UploadTTLMinutes)*time.Minute),
+		}
+		if dbClient != nil {
+			fileOpts = append(fileOpts, service.WithStoreRegistry(db.NewRepository(dbClient)))
+		}
+		fileService = service.NewFileService(ragService, rateLimiter, fileOpts...)
 		pb.RegisterFileServiceServer(server, fileService)
 	}
 
+	// Register MemoryService if message persistence is enabled
+	if dbClient != nil {
+		pb.RegisterMemoryServiceServer(server, service.NewMemoryService(dbClient))
+	}
+
+	// Standard health service with per-subsystem statuses
+	var deps []dependency
+	if dbClient != nil {
+		deps = append(deps, dependency{name: HealthDB, probe: dbClient.Ping})
+	}
+	if redisClient != nil {
+		deps = append(deps, dependency{name: HealthRedis, probe: redisClient.Ping})
+	}
+	if qdrant != nil {
+		deps = append(deps, dependency{name: HealthQdrant, probe: qdrant.Ping})
+	}
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	go newHealthMonitor(healthServer, deps, ragService != nil).Run(healthCtx)
+
+	if cfg.Server.Reflection {
+		reflection.Register(server)
+		slog.Info("gRPC server reflection enabled")
+	}
+
 	tenantCount := 0
 	if tenantMgr != nil {
 		tenantCount = tenantMgr.TenantCount()
@@ -246,6 +507,46 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 		TenantMgr:   tenantMgr,
 		RedisClient: redisClient,
 		DBClient:    dbClient,
+		Metrics:     metricsRegistry,
+		GeoIP:       geo,
+		health:      healthServer,
+		stopHealth:  stopHealth,
+	}
+
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	components.stopBackground = stopBackground
+
+	// Discard abandoned resumable uploads
+	if fileService != nil {
+		go fileService.RunUploadCleanup(bgCtx)
+	}
+
+	if dbClient != nil {
+		// Maintain activity rollups for dashboard stats
+		interval := time.Duration(cfg.Database.RollupIntervalSec) * time.Second
+		go db.NewRollupAggregator(dbClient, interval).Run(bgCtx)
+
+		// Extract intent, entities and topics of stored conversations
+		if cfg.Analytics.Enabled && tenantMgr != nil {
+			go chatService.RunConversationAnalytics(bgCtx, tenantMgr, service.AnalyticsConfig{
+				Interval:  time.Duration(cfg.Analytics.IntervalSec) * time.Second,
+				BatchSize: cfg.Analytics.BatchSize,
+				Provider:  cfg.Analytics.Provider,
+				Model:     cfg.Analytics.Model,
+			})
+		}
+
+		// Deliver outbox events to webhooks and the event stream
+		if cfg.Events.Enabled {
+			sinks, publishers := eventSinks(cfg.Events, tenantMgr, dbClient)
+			components.publishers = publishers
+			go events.NewDispatcher(dbClient, sinks, events.DispatcherConfig{
+				Interval:    time.Duration(cfg.Events.DispatchIntervalMs) * time.Millisecond,
+				BatchSize:   cfg.Events.BatchSize,
+				MaxAttempts: cfg.Events.MaxAttempts,
+				Retention:   time.Duration(cfg.Events.RetentionHours) * time.Hour,
+			}).Run(bgCtx)
+		}
 	}
 
 	return server, components, nil
@@ -253,11 +554,74 @@ func NewGRPCServer(cfg *config.Config, version VersionInfo) (*grpc.Server, *Serv
 
 // Close closes all server components that need cleanup.
 func (c *ServerComponents) Close() {
+	if c.stopHealth != nil {
+		c.stopHealth()
+	}
+	if c.health != nil {
+		c.health.Shutdown()
+	}
+	if c.stopBackground != nil {
+		c.stopBackground()
+	}
+	for _, p := range c.publishers {
+		if err := p.Close(); err != nil {
+			slog.Warn("failed to close event publisher", "error", err)
+		}
+	}
 	if c.DBClient != nil {
 		c.DBClient.Close()
 	}
 }
 
+// networkViolationSink stores network violations in repo's audit table.
+func networkViolationSink(repo *db.Repository) auth.NetworkViolationSink {
+	return func(ctx context.Context, v auth.NetworkViolation) error {
+		return repo.RecordNetworkViolation(ctx, db.NetworkViolation{
+			Surface:    v.Surface,
+			Method:     v.Method,
+			ClientID:   v.ClientID,
+			KeyID:      v.KeyID,
+			RemoteAddr: v.Addr.String(),
+			Country:    v.Country,
+			Reason:     v.Reason.Error(),
+		})
+	}
+}
+
+// eventSinks builds the configured event sinks and the stream publishers
+// they own. A stream that fails to connect is logged and skipped.
+func eventSinks(cfg config.EventsConfig, tenantMgr *tenant.Manager, dbClient *db.Client) ([]events.Sink, []events.Publisher) {
+	var sinks []events.Sink
+	var publishers []events.Publisher
+	for _, wh := range cfg.Webhooks {
+		sinks = append(sinks, events.NewWebhookSink(wh.URL, wh.Secret, wh.Events))
+	}
+
+	policy := events.TenantStreamPolicy(tenantMgr)
+	content := events.RepositoryContent(dbClient)
+	if len(cfg.Kafka.Brokers) > 0 {
+		pub := events.NewKafkaPublisher(cfg.Kafka.Brokers, cfg.Kafka.Topic)
+		publishers = append(publishers, pub)
+		sinks = append(sinks, events.NewStreamSink("kafka:"+cfg.Kafka.Topic, pub, policy, content))
+		slog.Info("kafka event stream enabled", "brokers", cfg.Kafka.Brokers, "topic", cfg.Kafka.Topic)
+	}
+	if cfg.NATS.URL != "" {
+		pub, err := events.NewNATSPublisher(cfg.NATS.URL, cfg.NATS.Subject, cfg.NATS.JetStream)
+		if err != nil {
+			slog.Error("nats event stream disabled", "error", err)
+		} else {
+			publishers = append(publishers, pub)
+			sinks = append(sinks, events.NewStreamSink("nats:"+cfg.NATS.Subject, pub, policy, content))
+			slog.Info("nats event stream enabled", "subject", cfg.NATS.Subject, "jetstream", cfg.NATS.JetStream)
+		}
+	}
+
+	if len(sinks) == 0 {
+		slog.Warn("events enabled without sinks; outbox events will be marked delivered unsent")
+	}
+	return sinks, publishers
+}
+
 // recoveryInterceptor recovers from panics in unary handlers
 func recoveryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
@@ -266,7 +630,7 @@ func recoveryInterceptor() grpc.UnaryServerInterceptor {
 				// Log stack trace
 				buf := make([]byte, 4096)
 				n := runtime.Stack(buf, false)
-				slog.Error("panic recovered",
+				slog.ErrorContext(ctx, "panic recovered",
 					"method", info.FullMethod,
 					"panic", r,
 					"stack", string(buf[:n]),
@@ -285,7 +649,7 @@ func streamRecoveryInterceptor() grpc.StreamServerInterceptor {
 			if r := recover(); r != nil {
 				buf := make([]byte, 4096)
 				n := runtime.Stack(buf, false)
-				slog.Error("panic recovered in stream",
+				slog.ErrorContext(ss.Context(), "panic recovered in stream",
 					"method", info.FullMethod,
 					"panic", r,
 					"stack", string(buf[:n]),
@@ -297,63 +661,6 @@ func streamRecoveryInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
-// loggingInterceptor logs unary requests
-func loggingInterceptor() grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		start := time.Now()
-
-		resp, err := handler(ctx, req)
-
-		duration := time.Since(start)
-		code := codes.OK
-		if err != nil {
-			if st, ok := status.FromError(err); ok {
-				code = st.Code()
-			} else {
-				code = codes.Unknown
-			}
-		}
-
-		// Skip logging for health checks
-		if info.FullMethod != "/airborne.v1.AdminService/Health" {
-			slog.Info("gRPC request",
-				"method", info.FullMethod,
-				"duration_ms", duration.Milliseconds(),
-				"code", code.String(),
-			)
-		}
-
-		return resp, err
-	}
-}
-
-// streamLoggingInterceptor logs stream requests
-func streamLoggingInterceptor() grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		start := time.Now()
-
-		err := handler(srv, ss)
-
-		duration := time.Since(start)
-		code := codes.OK
-		if err != nil {
-			if st, ok := status.FromError(err); ok {
-				code = st.Code()
-			} else {
-				code = codes.Unknown
-			}
-		}
-
-		slog.Info("gRPC stream",
-			"method", info.FullMethod,
-			"duration_ms", duration.Milliseconds(),
-			"code", code.String(),
-		)
-
-		return err
-	}
-}
-
 // developmentAuthInterceptor injects a dev client in non-production mode when Redis is unavailable.
 //
 // WARNING: This function bypasses authentication entirely. It is intended ONLY for
@@ -409,3 +716,45 @@ type devWrappedStream struct {
 func (s *devWrappedStream) Context() context.Context {
 	return s.ctx
 }
+
+// redisFallbackStats converts fallback tracker state for the metrics snapshot.
+func redisFallbackStats(tracker *redis.FallbackTracker) []metrics.RedisFallbackStats {
+	var out []metrics.RedisFallbackStats
+	for _, st := range tracker.Status() {
+		out = append(out, metrics.RedisFallbackStats{
+			Feature:     st.Feature,
+			Policy:      st.Policy,
+			Active:      st.Active,
+			Activations: st.Activations,
+		})
+	}
+	return out
+}
+
+// restoreTenantOverrides reapplies tenant settings changed through the admin
+// API in earlier runs. Failures are logged; the tenant keeps its file config.
+func restoreTenantOverrides(tenantMgr *tenant.Manager, dbClient *db.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stored, err := db.NewRepository(dbClient).GetTenantOverrides(ctx)
+	if err != nil {
+		slog.Warn("failed to load tenant setting overrides", "error", err)
+		return
+	}
+	overrides := make(map[string]tenant.SettingsUpdate, len(stored))
+	for tenantID, settings := range stored {
+		var u tenant.SettingsUpdate
+		if err := json.Unmarshal([]byte(settings), &u); err != nil {
+			slog.Warn("skipping unreadable tenant setting override", "tenant_id", tenantID, "error", err)
+			continue
+		}
+		overrides[tenantID] = u
+	}
+	for _, err := range tenantMgr.RestoreOverrides(overrides) {
+		slog.Warn("dropped tenant setting override", "error", err)
+	}
+	if len(overrides) > 0 {
+		slog.Info("restored tenant setting overrides", "tenants", len(overrides))
+	}
+}
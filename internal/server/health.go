@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Service names reported by the grpc.health.v1 Health service. The empty
+// name is the overall server status, which follows chat.
+const (
+	HealthChat   = "chat"
+	HealthFiles  = "files"
+	HealthAdmin  = "admin"
+	HealthDB     = "db"
+	HealthRedis  = "redis"
+	HealthQdrant = "qdrant"
+)
+
+const (
+	healthCheckInterval = 10 * time.Second
+	healthProbeTimeout  = 3 * time.Second
+)
+
+// dependency is a subsystem probed for the Health service.
+type dependency struct {
+	name  string
+	probe func(ctx context.Context) error
+}
+
+// healthMonitor probes dependencies and publishes per-service statuses. Only
+// configured subsystems are registered; unknown names return NOT_FOUND.
+type healthMonitor struct {
+	server *health.Server
+	deps   []dependency
+	files  bool // FileService is registered; it depends on Qdrant
+}
+
+func newHealthMonitor(server *health.Server, deps []dependency, files bool) *healthMonitor {
+	m := &healthMonitor{server: server, deps: deps, files: files}
+	m.server.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	m.server.SetServingStatus(HealthChat, healthpb.HealthCheckResponse_SERVING)
+	m.server.SetServingStatus(HealthAdmin, healthpb.HealthCheckResponse_SERVING)
+	return m
+}
+
+// check probes every dependency once and updates the statuses.
+func (m *healthMonitor) check(ctx context.Context) {
+	healthy := make(map[string]bool, len(m.deps))
+	for _, dep := range m.deps {
+		probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+		err := dep.probe(probeCtx)
+		cancel()
+		healthy[dep.name] = err == nil
+		if err != nil {
+			slog.Warn("health probe failed", "service", dep.name, "error", err)
+		}
+		m.server.SetServingStatus(dep.name, servingStatus(err == nil))
+	}
+	if m.files {
+		qdrantOK, probed := healthy[HealthQdrant]
+		m.server.SetServingStatus(HealthFiles, servingStatus(qdrantOK || !probed))
+	}
+}
+
+// Run probes dependencies until ctx is cancelled.
+func (m *healthMonitor) Run(ctx context.Context) {
+	m.check(ctx)
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func healthStatus(t *testing.T, s *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) failed: %v", service, err)
+	}
+	return resp.Status
+}
+
+func TestHealthMonitor_PerServiceStatus(t *testing.T) {
+	qdrantErr := errors.New("connection refused")
+	s := health.NewServer()
+	m := newHealthMonitor(s, []dependency{
+		{name: HealthRedis, probe: func(context.Context) error { return nil }},
+		{name: HealthQdrant, probe: func(context.Context) error { return qdrantErr }},
+	}, true)
+	m.check(context.Background())
+
+	want := map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":           healthpb.HealthCheckResponse_SERVING,
+		HealthChat:   healthpb.HealthCheckResponse_SERVING,
+		HealthAdmin:  healthpb.HealthCheckResponse_SERVING,
+		HealthRedis:  healthpb.HealthCheckResponse_SERVING,
+		HealthQdrant: healthpb.HealthCheckResponse_NOT_SERVING,
+		HealthFiles:  healthpb.HealthCheckResponse_NOT_SERVING,
+	}
+	for service, st := range want {
+		if got := healthStatus(t, s, service); got != st {
+			t.Errorf("status(%q) = %v, want %v", service, got, st)
+		}
+	}
+
+	// Unconfigured subsystems are not registered
+	if _, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: HealthDB}); status.Code(err) != codes.NotFound {
+		t.Errorf("Check(db) err = %v, want NotFound", err)
+	}
+
+	// Recovery is picked up on the next check
+	qdrantErr = nil
+	m.check(context.Background())
+	if got := healthStatus(t, s, HealthFiles); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("files status after recovery = %v, want SERVING", got)
+	}
+}
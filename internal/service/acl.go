@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/rag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uploadACL returns the ACL an upload's chunks are stored with. Provider
+// stores have no ACLs, so restricting a provider upload, or its internal
+// mirror, is rejected rather than leaving the provider's copy open.
+func uploadACL(metadata *pb.UploadFileMetadata) (rag.ACL, error) {
+	acl := rag.ACL{Users: metadata.AllowedUsers, Groups: metadata.AllowedGroups}
+	if !acl.IsEmpty() && metadata.Provider != pb.Provider_PROVIDER_UNSPECIFIED {
+		return rag.ACL{}, status.Error(codes.InvalidArgument, "allowed_users and allowed_groups are only supported by internal stores")
+	}
+	return acl, nil
+}
+
+// ragViewer returns the identity internal store retrieval is done for: the
+// authenticated client and its key's groups. Admins see every document.
+func ragViewer(ctx context.Context) *rag.Viewer {
+	client := auth.ClientFromContext(ctx)
+	if client == nil {
+		return &rag.Viewer{}
+	}
+	if client.HasPermission(auth.PermissionAdmin) {
+		return nil
+	}
+	return &rag.Viewer{UserID: client.ClientID, Groups: client.Groups}
+}
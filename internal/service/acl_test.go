@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUploadACL(t *testing.T) {
+	acl, err := uploadACL(&pb.UploadFileMetadata{AllowedUsers: []string{"alice"}, AllowedGroups: []string{"finance"}})
+	if err != nil || acl.IsEmpty() {
+		t.Fatalf("expected internal upload ACL, got %+v (%v)", acl, err)
+	}
+	_, err = uploadACL(&pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_OPENAI, AllowedGroups: []string{"finance"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a provider upload, got %v", err)
+	}
+	if _, err := uploadACL(&pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_GEMINI}); err != nil {
+		t.Errorf("expected unrestricted provider upload, got %v", err)
+	}
+}
+
+func TestRagViewer(t *testing.T) {
+	withClient := func(key *auth.ClientKey) context.Context {
+		return context.WithValue(context.Background(), auth.ClientContextKey, key)
+	}
+
+	viewer := ragViewer(withClient(&auth.ClientKey{ClientID: "alice", Groups: []string{"finance"}, Permissions: []auth.Permission{auth.PermissionFiles}}))
+	if viewer == nil || viewer.UserID != "alice" || len(viewer.Groups) != 1 {
+		t.Errorf("expected alice's viewer, got %+v", viewer)
+	}
+	if viewer := ragViewer(withClient(&auth.ClientKey{ClientID: "ops", Permissions: []auth.Permission{auth.PermissionAdmin}})); viewer != nil {
+		t.Errorf("expected admins to skip ACL checks, got %+v", viewer)
+	}
+	if viewer := ragViewer(context.Background()); viewer == nil || viewer.UserID != "" {
+		t.Errorf("expected an anonymous viewer, got %+v", viewer)
+	}
+}
@@ -2,11 +2,19 @@ package service
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
 	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/headroom"
+	"github.com/ai8future/airborne/internal/providerhealth"
 	"github.com/ai8future/airborne/internal/redis"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // AdminService implements the AdminService gRPC service.
@@ -14,6 +22,10 @@ type AdminService struct {
 	pb.UnimplementedAdminServiceServer
 
 	redis     *redis.Client
+	fallbacks *redis.FallbackTracker
+	tenants   *tenant.Manager
+	health    *providerhealth.Tracker
+	headroom  *headroom.Tracker
 	version   string
 	gitCommit string
 	buildTime string
@@ -27,12 +39,26 @@ type AdminServiceConfig struct {
 	GitCommit string
 	BuildTime string
 	GoVersion string
+
+	// RedisFallbacks reports Redis-dependent features running degraded.
+	RedisFallbacks *redis.FallbackTracker
+
+	// Tenants, ProviderHealth and Headroom back GetProviderHealth. Without
+	// Tenants it returns Unavailable; without a tracker its fields are left
+	// unset.
+	Tenants        *tenant.Manager
+	ProviderHealth *providerhealth.Tracker
+	Headroom       *headroom.Tracker
 }
 
 // NewAdminService creates a new admin service.
 func NewAdminService(redisClient *redis.Client, cfg AdminServiceConfig) *AdminService {
 	return &AdminService{
 		redis:     redisClient,
+		fallbacks: cfg.RedisFallbacks,
+		tenants:   cfg.Tenants,
+		health:    cfg.ProviderHealth,
+		headroom:  cfg.Headroom,
 		version:   cfg.Version,
 		gitCommit: cfg.GitCommit,
 		buildTime: cfg.BuildTime,
@@ -45,11 +71,21 @@ func NewAdminService(redisClient *redis.Client, cfg AdminServiceConfig) *AdminSe
 func (s *AdminService) Health(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
 	uptime := int64(time.Since(s.startTime).Seconds())
 
-	return &pb.HealthResponse{
+	resp := &pb.HealthResponse{
 		Status:        "healthy",
 		Version:       s.version,
 		UptimeSeconds: uptime,
-	}, nil
+	}
+	for _, st := range s.fallbacks.Degradations() {
+		resp.Status = "degraded"
+		resp.Degraded = append(resp.Degraded, &pb.DegradedFeature{
+			Feature:     st.Feature,
+			Policy:      st.Policy,
+			SinceUnix:   st.Since.Unix(),
+			Activations: st.Activations,
+		})
+	}
+	return resp, nil
 }
 
 // Ready returns readiness status with dependency checks.
@@ -104,3 +140,85 @@ func (s *AdminService) Version(ctx context.Context, req *pb.VersionRequest) (*pb
 		GoVersion: s.goVersion,
 	}, nil
 }
+
+// GetProviderHealth reports, per tenant and configured provider, when the
+// provider last answered, its recent error rate, its rate-limit headroom and
+// whether the tenant's API key works.
+func (s *AdminService) GetProviderHealth(ctx context.Context, req *pb.GetProviderHealthRequest) (*pb.GetProviderHealthResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionAdmin); err != nil {
+		return nil, err
+	}
+	if s.tenants == nil {
+		return nil, status.Error(codes.Unavailable, "tenant configuration not available")
+	}
+
+	tenantIDs := s.tenants.TenantCodes()
+	if req.TenantId != "" {
+		tenantID := strings.ToLower(strings.TrimSpace(req.TenantId))
+		if _, ok := s.tenants.Tenant(tenantID); !ok {
+			return nil, status.Error(codes.NotFound, "tenant not found")
+		}
+		tenantIDs = []string{tenantID}
+	}
+	slices.Sort(tenantIDs)
+
+	resp := &pb.GetProviderHealthResponse{}
+	if s.health != nil {
+		resp.WindowSeconds = int64(s.health.Window().Seconds())
+	}
+	for _, tenantID := range tenantIDs {
+		cfg, ok := s.tenants.Tenant(tenantID)
+		if !ok {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(cfg.Providers)) {
+			resp.Providers = append(resp.Providers, s.providerHealth(tenantID, name, cfg.Providers[name]))
+		}
+	}
+	return resp, nil
+}
+
+// providerHealth reports one tenant's provider.
+func (s *AdminService) providerHealth(tenantID, name string, cfg tenant.ProviderConfig) *pb.ProviderHealth {
+	h := &pb.ProviderHealth{
+		TenantId:  tenantID,
+		Provider:  name,
+		Enabled:   cfg.Enabled,
+		KeyStatus: providerhealth.KeyUnknown,
+	}
+	if s.health != nil {
+		st, _ := s.health.Status(tenantID, name)
+		h.KeyStatus = st.KeyState
+		h.LastSuccessUnix = unixOrZero(st.LastSuccess)
+		h.LastFailureUnix = unixOrZero(st.LastFailure)
+		h.LastError = st.LastError
+		h.Calls = int32(st.Calls)
+		h.Errors = int32(st.Errors)
+		h.ErrorRate = st.ErrorRate()
+	}
+	if cfg.APIKey == "" {
+		h.KeyStatus = providerhealth.KeyMissing
+	}
+	if s.headroom != nil {
+		if st, ok := s.headroom.State(tenantID, name); ok {
+			h.Headroom = &pb.RateLimitHeadroom{
+				RemainingRequests: int32(st.RemainingRequests),
+				RemainingTokens:   int32(st.RemainingTokens),
+				RequestsResetUnix: unixOrZero(st.RequestsReset),
+				TokensResetUnix:   unixOrZero(st.TokensReset),
+				RetryAfterUnix:    unixOrZero(st.RetryAfter),
+				WaitMs:            st.Wait(time.Now()).Milliseconds(),
+				UpdatedUnix:       unixOrZero(st.Updated),
+			}
+		}
+	}
+	return h
+}
+
+// unixOrZero returns t as Unix seconds, or 0 for the zero time.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
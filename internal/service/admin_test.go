@@ -3,11 +3,18 @@ package service
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
 	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/headroom"
+	"github.com/ai8future/airborne/internal/providerhealth"
+	"github.com/ai8future/airborne/internal/redis"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ctxWithAdminPermission creates a context with admin permission for testing.
@@ -557,3 +564,92 @@ func TestAdminService_ErrorTypes(t *testing.T) {
 		// This is still acceptable - just verifying the error exists
 	}
 }
+
+func TestAdminService_Health_ReportsRedisDegradation(t *testing.T) {
+	tracker := redis.NewFallbackTracker()
+	tracker.Register(redis.FeatureRateLimit, redis.PolicyMemory)
+	tracker.Register(redis.FeatureIdempotency, redis.PolicyFailClosed)
+	svc := NewAdminService(nil, AdminServiceConfig{Version: "1.0.0", RedisFallbacks: tracker})
+
+	resp, err := svc.Health(context.Background(), &pb.HealthRequest{})
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if resp.Status != "healthy" || len(resp.Degraded) != 0 {
+		t.Fatalf("expected healthy before any failure, got %s %v", resp.Status, resp.Degraded)
+	}
+
+	tracker.Degraded(redis.FeatureRateLimit, errors.New("connection refused"))
+
+	resp, err = svc.Health(context.Background(), &pb.HealthRequest{})
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if resp.Status != "degraded" {
+		t.Errorf("expected degraded status, got %s", resp.Status)
+	}
+	if len(resp.Degraded) != 1 || resp.Degraded[0].Feature != redis.FeatureRateLimit || resp.Degraded[0].Policy != redis.PolicyMemory {
+		t.Errorf("unexpected degraded features: %v", resp.Degraded)
+	}
+	if resp.Degraded[0].Activations != 1 || resp.Degraded[0].SinceUnix == 0 {
+		t.Errorf("expected activation count and start time, got %v", resp.Degraded[0])
+	}
+}
+
+func TestAdminService_GetProviderHealth(t *testing.T) {
+	health := providerhealth.NewTracker(0)
+	rates := headroom.NewTracker()
+	tenants := &tenant.Manager{Tenants: map[string]tenant.TenantConfig{
+		"acme": {TenantID: "acme", Providers: map[string]tenant.ProviderConfig{
+			"openai":    {Enabled: true, APIKey: "sk-acme"},
+			"anthropic": {Enabled: true, APIKey: "sk-ant"},
+			"gemini":    {Enabled: false},
+		}},
+		"globex": {TenantID: "globex", Providers: map[string]tenant.ProviderConfig{
+			"openai": {Enabled: true, APIKey: "sk-globex"},
+		}},
+	}}
+	svc := NewAdminService(nil, AdminServiceConfig{Tenants: tenants, ProviderHealth: health, Headroom: rates})
+
+	health.Observe("acme", "openai", http.StatusOK, nil)
+	health.Observe("acme", "openai", http.StatusInternalServerError, errors.New("500 Internal Server Error"))
+	health.Observe("acme", "anthropic", http.StatusUnauthorized, errors.New("401 invalid x-api-key"))
+	rates.Observe("acme", "openai", http.StatusOK, http.Header{"X-Ratelimit-Remaining-Requests": {"42"}})
+
+	if _, err := svc.GetProviderHealth(ctxWithChatPermission("chat"), &pb.GetProviderHealthRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied without admin permission, got %v", err)
+	}
+
+	resp, err := svc.GetProviderHealth(ctxWithAdminPermission("admin"), &pb.GetProviderHealthRequest{TenantId: "acme"})
+	if err != nil {
+		t.Fatalf("GetProviderHealth failed: %v", err)
+	}
+	if len(resp.Providers) != 3 || resp.WindowSeconds != int64(providerhealth.DefaultWindow.Seconds()) {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	byName := make(map[string]*pb.ProviderHealth)
+	for _, p := range resp.Providers {
+		byName[p.Provider] = p
+	}
+	openai := byName["openai"]
+	if openai.KeyStatus != providerhealth.KeyValid || openai.Calls != 2 || openai.Errors != 1 || openai.ErrorRate != 0.5 || openai.LastSuccessUnix == 0 {
+		t.Errorf("unexpected openai health: %v", openai)
+	}
+	if openai.Headroom.GetRemainingRequests() != 42 || openai.Headroom.GetRemainingTokens() != -1 {
+		t.Errorf("unexpected openai headroom: %v", openai.Headroom)
+	}
+	if byName["anthropic"].KeyStatus != providerhealth.KeyInvalid || byName["anthropic"].Headroom != nil {
+		t.Errorf("unexpected anthropic health: %v", byName["anthropic"])
+	}
+	if gemini := byName["gemini"]; gemini.KeyStatus != providerhealth.KeyMissing || gemini.Enabled {
+		t.Errorf("unexpected gemini health: %v", gemini)
+	}
+
+	resp, err = svc.GetProviderHealth(ctxWithAdminPermission("admin"), &pb.GetProviderHealthRequest{})
+	if err != nil || len(resp.Providers) != 4 || resp.Providers[3].TenantId != "globex" {
+		t.Errorf("all tenants = %v, %v", resp, err)
+	}
+	if _, err := svc.GetProviderHealth(ctxWithAdminPermission("admin"), &pb.GetProviderHealthRequest{TenantId: "initech"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown tenant, got %v", err)
+	}
+}
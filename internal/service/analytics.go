@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	sanitize "github.com/ai8future/airborne/internal/errors"
+	"github.com/ai8future/airborne/internal/isolation"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// Conversation analytics job defaults.
+const (
+	DefaultAnalyticsInterval  = 5 * time.Minute
+	DefaultAnalyticsBatchSize = 50
+)
+
+// analyticsClientID identifies the analytics job in the usage ledger.
+const analyticsClientID = "conversation-analytics"
+
+// AnalyticsConfig configures the conversation analytics job.
+type AnalyticsConfig struct {
+	Interval  time.Duration // Time between runs; non-positive uses DefaultAnalyticsInterval
+	BatchSize int           // Messages analyzed per tenant per run; non-positive uses DefaultAnalyticsBatchSize
+	Provider  string        // Provider to extract with; empty uses the tenant's default
+	Model     string        // Model override; empty uses the provider's model
+}
+
+// RunConversationAnalytics extracts the intent, entities and topics of
+// persisted user messages that have not been analyzed, for every tenant
+// without the conversation_analytics feature disabled, until ctx is done.
+// Any provider can extract them, so conversations with providers that have
+// no native structured output are covered too.
+func (s *ChatService) RunConversationAnalytics(ctx context.Context, tenants *tenant.Manager, cfg AnalyticsConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultAnalyticsInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultAnalyticsBatchSize
+	}
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, tenantID := range tenants.TenantCodes() {
+			tenantCfg, ok := tenants.Tenant(tenantID)
+			if !ok || !tenantCfg.FeatureEnabled(tenant.FeatureConversationAnalytics) || !db.ValidTenantIDs[tenantID] {
+				continue
+			}
+			if _, err := s.analyzeConversations(ctx, &tenantCfg, cfg); err != nil && ctx.Err() == nil {
+				slog.Error("conversation analytics run failed", "tenant_id", tenantID, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// analyzeConversations analyzes up to cfg.BatchSize of a tenant's unanalyzed
+// user messages and returns how many were stored. Messages extraction fails
+// for are stored with the error, so a message the model cannot analyze is
+// not retried every run; a provider that cannot be reached ends the run
+// instead, leaving the rest for the next one.
+func (s *ChatService) analyzeConversations(ctx context.Context, tenantCfg *tenant.TenantConfig, cfg AnalyticsConfig) (int, error) {
+	if s.dbClient == nil {
+		return 0, nil
+	}
+	repo, err := s.dbClient.TenantRepository(tenantCfg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	ctx = context.WithValue(ctx, auth.TenantContextKey, tenantCfg)
+	ctx = isolation.WithTenant(ctx, tenantCfg.TenantID)
+
+	genReq := &pb.GenerateReplyRequest{
+		PreferredProvider: mapProviderToProto(cfg.Provider),
+		ModelOverride:     cfg.Model,
+		Priority:          pb.Priority_PRIORITY_BACKGROUND,
+	}
+	selected, err := s.selectProviderWithTenant(ctx, genReq, nil)
+	if err != nil {
+		return 0, err
+	}
+	schema, err := json.Marshal(analysisRules.Schema)
+	if err != nil {
+		return 0, err
+	}
+
+	messages, err := repo.ListUnanalyzedMessages(ctx, cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	stored := 0
+	for _, msg := range messages {
+		release, err := s.acquireSlot(ctx, genReq, selected.Name())
+		if err != nil {
+			return stored, err
+		}
+		params := provider.GenerateParams{
+			Instructions:  analyzeInstructions + string(schema),
+			UserInput:     msg.Content,
+			OverrideModel: cfg.Model,
+			Config:        s.buildProviderConfig(ctx, genReq, selected.Name()),
+			RequestID:     msg.ID.String(),
+			ClientID:      analyticsClientID,
+		}
+		a, err := s.analyze(ctx, selected, params)
+		release()
+
+		result := &db.MessageAnalysis{
+			MessageID:        msg.ID,
+			ThreadID:         msg.ThreadID,
+			Provider:         selected.Name(),
+			Model:            a.model,
+			MessageCreatedAt: msg.CreatedAt,
+		}
+		switch {
+		case errors.Is(err, errAnalysisMismatch):
+			result.Error = err.Error()
+		case err != nil:
+			return stored, errors.New(sanitize.SanitizeForClient(err))
+		default:
+			result.Intent = a.metadata.Intent
+			result.RequiresUserAction = a.metadata.RequiresUserAction
+			result.Topics = a.metadata.Topics
+			for _, e := range a.metadata.Entities {
+				result.Entities = append(result.Entities, db.AnalysisEntity{Name: e.Name, Type: e.Type})
+			}
+		}
+		if err := repo.SaveMessageAnalysis(ctx, result); err != nil {
+			return stored, err
+		}
+		stored++
+	}
+	if stored > 0 {
+		slog.Info("analyzed conversations", "tenant_id", tenantCfg.TenantID, "messages", stored, "provider", selected.Name())
+	}
+	return stored, nil
+}
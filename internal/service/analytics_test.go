@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestAnalyzeConversations(t *testing.T) {
+	ctx := context.Background()
+	client, err := db.NewClient(ctx, db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	repo, _ := client.TenantRepository("ai8")
+	threadID := uuid.New()
+	for _, input := range []string{"asdf qwer", "Can you move my delivery to Friday?"} {
+		if err := repo.PersistConversationTurn(ctx, threadID, "user-1", input, "OK", "gemini", "gemini-flash", "", 10, 5, 100, 0); err != nil {
+			t.Fatalf("PersistConversationTurn failed: %v", err)
+		}
+	}
+
+	// Newest message first: one valid analysis, then two replies that do
+	// not match the schema for the older message
+	anthropic := &scriptedProvider{mockProvider: newMockProvider("anthropic"), replies: []string{
+		`{"intent": "request", "requires_user_action": true, "entities": [{"name": "Friday", "type": "date"}], "topics": ["Delivery", "scheduling"]}`,
+		`{"intent": "gossip"}`,
+	}}
+	svc := newSummaryService(newMockProvider("openai"))
+	svc.anthropicProvider = anthropic
+	svc.dbClient = client
+	tenantCfg := createTestTenantConfig("anthropic")
+	tenantCfg.TenantID = "ai8"
+
+	stored, err := svc.analyzeConversations(ctx, tenantCfg, AnalyticsConfig{BatchSize: 10, Provider: "anthropic", Model: "claude-haiku"})
+	if err != nil || stored != 2 {
+		t.Fatalf("analyzeConversations = %d, %v, want both messages stored", stored, err)
+	}
+	if len(anthropic.generateCalls) != 1+analyzeAttempts {
+		t.Errorf("provider called %d times, want %d", len(anthropic.generateCalls), 1+analyzeAttempts)
+	}
+	if call := anthropic.generateCalls[0]; call.UserInput != "Can you move my delivery to Friday?" || call.OverrideModel != "claude-haiku" || call.ClientID != analyticsClientID {
+		t.Errorf("unexpected analysis call: input=%q model=%q client=%q", call.UserInput, call.OverrideModel, call.ClientID)
+	}
+
+	now := time.Now()
+	report, err := repo.GetAnalyticsReport(ctx, now.Add(-time.Hour), now.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetAnalyticsReport failed: %v", err)
+	}
+	if report.Analyzed != 1 || report.Failed != 1 || len(report.Intents) != 1 || report.Intents[0].Value != "request" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.Topics) != 2 || report.Topics[0].Value != "delivery" {
+		t.Errorf("Topics = %+v", report.Topics)
+	}
+
+	// Analyzed and failed messages are not analyzed again
+	if stored, err := svc.analyzeConversations(ctx, tenantCfg, AnalyticsConfig{BatchSize: 10, Provider: "anthropic"}); err != nil || stored != 0 {
+		t.Errorf("second run = %d, %v, want nothing left", stored, err)
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAnalyzeText_ExtractsMetadata(t *testing.T) {
+	anthropic := &scriptedProvider{mockProvider: newMockProvider("anthropic"), replies: []string{
+		"Here you go: not json",
+		"```json\n" + `{"intent": "request", "requires_user_action": true,
+			"entities": [{"name": "Acme Corp", "type": "organization"}],
+			"topics": ["renewal", "pricing"],
+			"scheduling_intent": {"detected": true, "datetime_mentioned": "next Tuesday"},
+			"facts": [{"category": "profile", "content": " Head of procurement "}]}` + "\n```",
+	}}
+	svc := newSummaryService(newMockProvider("openai"))
+	svc.anthropicProvider = anthropic
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("anthropic"))
+
+	resp, err := svc.AnalyzeText(ctx, &pb.AnalyzeTextRequest{
+		Text:              "I head procurement at Acme Corp. Can we discuss renewal pricing next Tuesday?",
+		PreferredProvider: pb.Provider_PROVIDER_ANTHROPIC,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeText failed: %v", err)
+	}
+
+	m := resp.Metadata
+	if m.Intent != "request" || !m.RequiresUserAction || len(m.Entities) != 1 || m.Entities[0].Type != "organization" {
+		t.Errorf("unexpected metadata: %+v", m)
+	}
+	if len(m.Topics) != 2 || !m.Scheduling.GetDetected() || len(m.Facts) != 1 || m.Facts[0].Content != "Head of procurement" {
+		t.Errorf("unexpected metadata: %+v", m)
+	}
+	if resp.Provider != pb.Provider_PROVIDER_ANTHROPIC || resp.Usage.TotalTokens != 60 {
+		t.Errorf("provider = %v, usage = %+v; want usage of both calls", resp.Provider, resp.Usage)
+	}
+
+	// The second call corrected the invalid first reply
+	if len(anthropic.generateCalls) != 2 || !strings.Contains(anthropic.generateCalls[1].UserInput, "not valid JSON") {
+		t.Errorf("expected one corrective retry, got %d calls", len(anthropic.generateCalls))
+	}
+	if !strings.Contains(anthropic.generateCalls[0].Instructions, `"email_address"`) {
+		t.Error("instructions should include the metadata schema")
+	}
+}
+
+func TestAnalyzeText_FailsOnSchemaMismatch(t *testing.T) {
+	openai := &scriptedProvider{mockProvider: newMockProvider("openai"), replies: []string{`{"intent": "gossip"}`}}
+	svc := newSummaryService(openai)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	_, err := svc.AnalyzeText(ctx, &pb.AnalyzeTextRequest{Text: "Hello"})
+	if status.Code(err) != codes.Internal || len(openai.generateCalls) != analyzeAttempts {
+		t.Errorf("err = %v after %d calls, want Internal after %d", err, len(openai.generateCalls), analyzeAttempts)
+	}
+
+	_, err = svc.AnalyzeText(ctx, &pb.AnalyzeTextRequest{Text: "  "})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty text: err = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ai8future/airborne/internal/anomaly"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+)
+
+// AnomalyAlertEvent is the event type of anomaly alerts.
+const AnomalyAlertEvent = "usage.anomaly"
+
+// AnomalyAlert is the JSON body posted to a tenant's alert webhook when a
+// client key's usage looks unusual, e.g. because the key leaked or an
+// integration is stuck in a loop.
+type AnomalyAlert struct {
+	Event    string    `json:"event"` // AnomalyAlertEvent
+	TenantID string    `json:"tenant_id"`
+	ClientID string    `json:"client_id"`
+	Kind     string    `json:"kind"` // "token_spike", "error_rate" or "odd_hour"
+	Detail   string    `json:"detail"`
+	Current  float64   `json:"current"`
+	Baseline float64   `json:"baseline"`
+	At       time.Time `json:"at"`
+}
+
+// WithAnomalyDetection checks every provider call made with a client key
+// against the key's usage history, for tenants with anomaly detection
+// enabled.
+func WithAnomalyDetection(detector *anomaly.Detector) ChatServiceOption {
+	return func(s *ChatService) {
+		s.anomalies = detector
+	}
+}
+
+// observeUsage records a provider call in the anomaly detector and alerts
+// on the anomalies it raises. Cancelled calls are left out, since they say
+// nothing about the key's integration.
+func (s *ChatService) observeUsage(ctx context.Context, call db.ProviderCall, err error) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	client := auth.ClientFromContext(ctx)
+	if s.anomalies == nil || tenantCfg == nil || !tenantCfg.Anomaly.Enabled || client == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	found := s.anomalies.Observe(anomaly.Observation{
+		TenantID: tenantCfg.TenantID,
+		ClientID: client.ClientID,
+		Tokens:   call.InputTokens + call.OutputTokens,
+		Failed:   err != nil,
+	}, anomaly.Thresholds{
+		SpikeFactor:  tenantCfg.Anomaly.SpikeFactor,
+		MaxErrorRate: tenantCfg.Anomaly.MaxErrorRate,
+		SkipOddHours: tenantCfg.Anomaly.SkipOddHours,
+	})
+	url := tenantCfg.AnomalyAlertURL()
+	for _, a := range found {
+		slog.WarnContext(ctx, "unusual client key usage",
+			"tenant_id", a.TenantID,
+			"client_id", a.ClientID,
+			"kind", a.Kind,
+			"detail", a.Detail,
+		)
+		if url == "" {
+			continue
+		}
+		alert := AnomalyAlert{
+			Event:    AnomalyAlertEvent,
+			TenantID: a.TenantID,
+			ClientID: a.ClientID,
+			Kind:     a.Kind,
+			Detail:   a.Detail,
+			Current:  a.Current,
+			Baseline: a.Baseline,
+			At:       a.At.UTC(),
+		}
+		// Deliver off the request path, as for SLO alerts
+		go func() {
+			if err := sendAlert(context.Background(), url, alert); err != nil {
+				slog.WarnContext(ctx, "failed to deliver anomaly alert", "tenant_id", alert.TenantID, "kind", alert.Kind, "error", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai8future/airborne/internal/anomaly"
+	"github.com/ai8future/airborne/internal/db"
+)
+
+func TestRecordProviderCall_AnomalyAlert(t *testing.T) {
+	alerts := make(chan AnomalyAlert, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert AnomalyAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("decode alert: %v", err)
+		}
+		alerts <- alert
+	}))
+	defer srv.Close()
+
+	svc := createChatServiceWithMocks(newMockProvider("openai"), nil, nil, nil)
+	WithAnomalyDetection(anomaly.NewDetector())(svc)
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.SLO.AlertWebhook = srv.URL
+	ctx := ctxWithChatPermissionAndTenant("client", tenantCfg)
+	call := db.ProviderCall{Provider: "openai", Model: "gpt-4o"}
+
+	// Not tracked until the tenant enables detection
+	for range anomaly.MinErrorCalls {
+		svc.recordProviderCall(ctx, call, errors.New("500 Internal Server Error"))
+	}
+	tenantCfg.Anomaly.Enabled = true
+	for range anomaly.MinErrorCalls - 1 {
+		svc.recordProviderCall(ctx, call, errors.New("500 Internal Server Error"))
+	}
+	select {
+	case alert := <-alerts:
+		t.Fatalf("alert before %d calls: %+v", anomaly.MinErrorCalls, alert)
+	case <-time.After(50 * time.Millisecond):
+	}
+	svc.recordProviderCall(ctx, call, errors.New("500 Internal Server Error"))
+
+	select {
+	case alert := <-alerts:
+		if alert.Event != AnomalyAlertEvent || alert.TenantID != "test-tenant" || alert.ClientID != "client" ||
+			alert.Kind != anomaly.KindErrorRate || alert.Current != 1 || alert.Baseline != anomaly.DefaultMaxErrorRate {
+			t.Errorf("unexpected alert: %+v", alert)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert delivered")
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Attachment limits. The total stays under the request sizes providers
+// accept once the content is base64 encoded.
+const (
+	maxAttachments     = 10
+	maxAttachmentBytes = 20 << 20
+)
+
+// checkAttachments validates a request's attachments for p and converts
+// them, detecting the type of each from its content. Types p cannot read
+// are rejected here rather than by the provider, so callers get
+// InvalidArgument.
+func checkAttachments(ctx context.Context, req *pb.GenerateReplyRequest, p provider.Provider) ([]provider.Attachment, error) {
+	if len(req.Attachments) == 0 {
+		return nil, nil
+	}
+	if continuesToolTurn(req) {
+		return nil, status.Error(codes.InvalidArgument, "attachments cannot be sent with tool_results")
+	}
+	if len(req.Attachments) > maxAttachments {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d attachments are allowed", maxAttachments)
+	}
+
+	total := 0
+	attachments := make([]provider.Attachment, 0, len(req.Attachments))
+	for i, a := range req.Attachments {
+		if len(a.Content) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "attachments[%d] is empty", i)
+		}
+		total += len(a.Content)
+		if total > maxAttachmentBytes {
+			return nil, status.Errorf(codes.InvalidArgument, "attachments exceed %d MB in total", maxAttachmentBytes>>20)
+		}
+		filename := a.Filename
+		if filename == "" {
+			filename = "attachment"
+		}
+		mimeType, err := checkFileType(ctx, a.Content[:min(len(a.Content), validation.SniffLen)], filename, a.MimeType)
+		if err != nil {
+			return nil, err
+		}
+		if !provider.SupportsAttachment(p, mimeType) {
+			return nil, status.Errorf(codes.InvalidArgument, "attachment %q (%s) is not supported by %s", filename, mimeType, p.Name())
+		}
+		attachments = append(attachments, provider.Attachment{Filename: filename, MIMEType: mimeType, Content: a.Content})
+	}
+	return attachments, nil
+}
+
+// hasFileAttachments reports whether req carries attachments other than
+// text, which not every provider can read.
+func hasFileAttachments(req *pb.GenerateReplyRequest) bool {
+	for _, a := range req.Attachments {
+		if !validation.IsTextType(validation.SniffMIMEType(a.Content[:min(len(a.Content), validation.SniffLen)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxImages is the most images a request can send by reference.
+const maxImages = 10
+
+// checkImages validates a request's images for p and converts them. The type
+// of each is taken from its URL when not declared.
+func checkImages(req *pb.GenerateReplyRequest, p provider.Provider) ([]provider.InlineImage, error) {
+	if len(req.Images) == 0 {
+		return nil, nil
+	}
+	if continuesToolTurn(req) {
+		return nil, status.Error(codes.InvalidArgument, "images cannot be sent with tool_results")
+	}
+	if len(req.Images) > maxImages {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d images are allowed", maxImages)
+	}
+
+	images := make([]provider.InlineImage, 0, len(req.Images))
+	for i, img := range req.Images {
+		if (img.Url == "") == (img.FileId == "") {
+			return nil, status.Errorf(codes.InvalidArgument, "images[%d] must set exactly one of url and file_id", i)
+		}
+		switch img.Detail {
+		case "", "auto", "low", "high":
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "images[%d].detail must be auto, low or high", i)
+		}
+		mimeType := img.MimeType
+		if img.Url != "" {
+			u, err := url.Parse(img.Url)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "data") || (u.Scheme != "data" && u.Host == "") {
+				return nil, status.Errorf(codes.InvalidArgument, "images[%d].url must be an http(s) or data URL", i)
+			}
+			if mimeType == "" {
+				mimeType = imageURLType(u)
+			}
+		}
+		if mimeType != "" && !strings.HasPrefix(mimeType, "image/") {
+			return nil, status.Errorf(codes.InvalidArgument, "images[%d] has type %s, not an image", i, mimeType)
+		}
+		image := provider.InlineImage{URI: img.Url, FileID: img.FileId, MIMEType: mimeType, Detail: img.Detail}
+		if !provider.SupportsImageInput(p, image) {
+			return nil, status.Errorf(codes.InvalidArgument, "images[%d] is not supported by %s", i, p.Name())
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// imageURLType returns the type of the image u points to, from the media
+// type of a data URL or the file extension of other URLs.
+func imageURLType(u *url.URL) string {
+	if u.Scheme == "data" {
+		mediaType, _, _ := strings.Cut(u.Opaque, ";")
+		mediaType, _, _ = strings.Cut(mediaType, ",")
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(mime.TypeByExtension(strings.ToLower(path.Ext(u.Path))), ";")
+	return mediaType
+}
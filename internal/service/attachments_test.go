@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// imageProvider is a mock provider that reads PNG attachments.
+type imageProvider struct {
+	*mockProvider
+}
+
+func (p *imageProvider) SupportsAttachment(mimeType string) bool { return mimeType == "image/png" }
+
+func TestCheckAttachments(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 16)...)
+	text := &pb.Attachment{Filename: "notes.txt", Content: []byte("some notes")}
+	image := &pb.Attachment{Filename: "chart.png", Content: png}
+	tooMany := make([]*pb.Attachment, maxAttachments+1)
+	for i := range tooMany {
+		tooMany[i] = text
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.GenerateReplyRequest
+		p    provider.Provider
+		code codes.Code
+	}{
+		{"none", &pb.GenerateReplyRequest{}, newMockProvider("openai"), codes.OK},
+		{"text anywhere", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{text}}, newMockProvider("mistral"), codes.OK},
+		{"supported image", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{image}}, &imageProvider{newMockProvider("openai")}, codes.OK},
+		{"unsupported image", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{image}}, newMockProvider("mistral"), codes.InvalidArgument},
+		{"mismatched type", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{{Filename: "chart.png", MimeType: "application/pdf", Content: png}}}, &imageProvider{newMockProvider("openai")}, codes.InvalidArgument},
+		{"empty", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{{Filename: "empty.txt"}}}, newMockProvider("openai"), codes.InvalidArgument},
+		{"too many", &pb.GenerateReplyRequest{Attachments: tooMany}, newMockProvider("openai"), codes.InvalidArgument},
+		{"too large", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{{Filename: "big.txt", Content: bytes.Repeat([]byte("a"), maxAttachmentBytes+1)}}}, newMockProvider("openai"), codes.InvalidArgument},
+		{"with tool results", &pb.GenerateReplyRequest{Attachments: []*pb.Attachment{text}, ToolResults: []*pb.ToolResult{{ToolCallId: "call_1"}}}, newMockProvider("openai"), codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachments, err := checkAttachments(ctxWithChatPermissionAndTenant("c", nil), tt.req, tt.p)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if err == nil && len(attachments) != len(tt.req.Attachments) {
+				t.Errorf("expected %d attachments, got %d", len(tt.req.Attachments), len(attachments))
+			}
+		})
+	}
+}
+
+func TestPinnedToProvider_FileAttachments(t *testing.T) {
+	if pinnedToProvider(&pb.GenerateReplyRequest{Attachments: []*pb.Attachment{{Content: []byte("plain text")}}}) {
+		t.Error("text attachments should not pin the request")
+	}
+	if !pinnedToProvider(&pb.GenerateReplyRequest{Attachments: []*pb.Attachment{{Content: []byte("%PDF-1.7\n")}}}) {
+		t.Error("file attachments should pin the request")
+	}
+}
+
+// urlImageProvider is a mock provider that reads images by URL.
+type urlImageProvider struct {
+	*mockProvider
+}
+
+func (p *urlImageProvider) SupportsImageInput(img provider.InlineImage) bool { return img.URI != "" }
+
+func TestCheckImages(t *testing.T) {
+	byURL := &pb.ImageInput{Url: "https://example.com/chart.PNG", Detail: "high"}
+	tooMany := make([]*pb.ImageInput, maxImages+1)
+	for i := range tooMany {
+		tooMany[i] = byURL
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.GenerateReplyRequest
+		code codes.Code
+	}{
+		{"none", &pb.GenerateReplyRequest{}, codes.OK},
+		{"url", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{byURL}}, codes.OK},
+		{"data url", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{Url: "data:image/png;base64,iVBORw0KGgo="}}}, codes.OK},
+		{"unsupported file id", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{FileId: "file-1"}}}, codes.InvalidArgument},
+		{"both", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{Url: byURL.Url, FileId: "file-1"}}}, codes.InvalidArgument},
+		{"neither", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{Detail: "low"}}}, codes.InvalidArgument},
+		{"bad scheme", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{Url: "file:///etc/passwd"}}}, codes.InvalidArgument},
+		{"bad detail", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{Url: byURL.Url, Detail: "max"}}}, codes.InvalidArgument},
+		{"not an image", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{{Url: "https://example.com/report.pdf"}}}, codes.InvalidArgument},
+		{"too many", &pb.GenerateReplyRequest{Images: tooMany}, codes.InvalidArgument},
+		{"with tool results", &pb.GenerateReplyRequest{Images: []*pb.ImageInput{byURL}, ToolResults: []*pb.ToolResult{{ToolCallId: "call_1"}}}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images, err := checkImages(tt.req, &urlImageProvider{newMockProvider("openai")})
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if err == nil && len(images) != len(tt.req.Images) {
+				t.Errorf("expected %d images, got %d", len(tt.req.Images), len(images))
+			}
+		})
+	}
+
+	images, _ := checkImages(&pb.GenerateReplyRequest{Images: []*pb.ImageInput{byURL}}, &urlImageProvider{newMockProvider("openai")})
+	if images[0].MIMEType != "image/png" || images[0].Detail != "high" {
+		t.Errorf("unexpected image %+v", images[0])
+	}
+	if _, err := checkImages(&pb.GenerateReplyRequest{Images: []*pb.ImageInput{byURL}}, newMockProvider("mistral")); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("provider without image input: got %v", err)
+	}
+	if !pinnedToProvider(&pb.GenerateReplyRequest{Images: []*pb.ImageInput{byURL}}) {
+		t.Error("images should pin the request")
+	}
+}
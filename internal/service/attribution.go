@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"google.golang.org/protobuf/proto"
+)
+
+// attributionVersion prefixes the signed attribution fields, so the format
+// can change without old signatures verifying against new fields.
+const attributionVersion = "airborne-attribution-v1"
+
+// attribute returns the attribution of a reply to req generated by model on
+// providerName, or nil when the tenant's attribution policy is disabled.
+func attribute(ctx context.Context, req *pb.GenerateReplyRequest, providerName, model string) *pb.Attribution {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil || !tenantCfg.Attribution.Enabled {
+		return nil
+	}
+	hash, err := attributionHash(req)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to hash request for attribution", "error", err)
+	}
+	a := &pb.Attribution{
+		Model:       model,
+		Provider:    mapProviderToProto(providerName),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		RequestHash: hash,
+	}
+	if key := tenantCfg.Attribution.SigningKey; key != "" {
+		a.Signature = signAttribution(key, providerName, a)
+	}
+	return a
+}
+
+// attributionHash hashes the content of req. Request IDs are left out, so
+// replies shared by deduplication carry the hash of each request they answer.
+func attributionHash(req *pb.GenerateReplyRequest) (string, error) {
+	content := proto.Clone(req).(*pb.GenerateReplyRequest)
+	content.RequestId = ""
+	content.Idempotent = false
+	return requestFingerprint(content)
+}
+
+// attributedReply generates a reply to req with the tenant's attribution,
+// before deduplication shares it.
+func (s *ChatService) attributedReply(ctx context.Context, req *pb.GenerateReplyRequest) (*pb.GenerateReplyResponse, error) {
+	resp, err := s.generateReply(ctx, req)
+	if err != nil || resp == nil || resp.Provider == pb.Provider_PROVIDER_UNSPECIFIED {
+		// Slash command replies are not generated by a provider
+		return resp, err
+	}
+	providerName := providerNameFromProto(resp.Provider)
+	resp.Attribution = attribute(ctx, req, providerName, resp.Model)
+	resp.HtmlContent = attributionSuffix(ctx, resp.HtmlContent, providerName, resp.Attribution)
+	return resp, nil
+}
+
+// signAttribution returns the hex HMAC-SHA256 of a's fields, as documented
+// on the Attribution message.
+func signAttribution(key, providerName string, a *pb.Attribution) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	for _, field := range []string{attributionVersion, a.Model, providerName, a.GeneratedAt, a.RequestHash} {
+		mac.Write([]byte(field))
+		mac.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// attributionSuffix appends a to htmlContent as an HTML comment, which
+// browsers do not render, when the tenant's policy asks for it.
+func attributionSuffix(ctx context.Context, htmlContent, providerName string, a *pb.Attribution) string {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if a == nil || htmlContent == "" || tenantCfg == nil || !tenantCfg.Attribution.HTMLSuffix {
+		return htmlContent
+	}
+	comment := fmt.Sprintf("model=%s provider=%s generated_at=%s request_hash=%s", a.Model, providerName, a.GeneratedAt, a.RequestHash)
+	if a.Signature != "" {
+		comment += " signature=" + a.Signature
+	}
+	// A "--" in a model name would end the comment early
+	for strings.Contains(comment, "--") {
+		comment = strings.ReplaceAll(comment, "--", "-")
+	}
+	return htmlContent + "\n<!-- airborne-attribution: " + comment + " -->"
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+func TestGenerateReply_Attribution(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tenantCfg := createTestTenantConfig("openai")
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+
+	resp, err := svc.GenerateReply(ctx, dedupRequest("req-1"))
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Attribution != nil {
+		t.Errorf("attribution without a policy: %+v", resp.Attribution)
+	}
+
+	tenantCfg.Attribution = tenant.AttributionConfig{Enabled: true, SigningKey: "secret"}
+	resp, err = svc.GenerateReply(ctx, dedupRequest("req-2"))
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	a := resp.Attribution
+	if a == nil || a.Model != "mock-model" || a.Provider != pb.Provider_PROVIDER_OPENAI || a.GeneratedAt == "" {
+		t.Fatalf("unexpected attribution %+v", a)
+	}
+	if want, _ := attributionHash(dedupRequest("req-3")); a.RequestHash != want {
+		t.Errorf("request hash %q, want the hash of the request content %q", a.RequestHash, want)
+	}
+	if want := signAttribution("secret", "openai", a); a.Signature != want {
+		t.Errorf("signature %q, want %q", a.Signature, want)
+	}
+	forged := &pb.Attribution{Model: "other-model", Provider: a.Provider, GeneratedAt: a.GeneratedAt, RequestHash: a.RequestHash}
+	if signAttribution("secret", "openai", forged) == a.Signature {
+		t.Error("signature does not cover the model")
+	}
+}
+
+func TestAttributionSuffix(t *testing.T) {
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.Attribution = tenant.AttributionConfig{Enabled: true}
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+	a := &pb.Attribution{Model: "evil--><script>", GeneratedAt: "2026-10-17T12:00:00Z", RequestHash: "abc", Signature: "def"}
+
+	if got := attributionSuffix(ctx, "<p>Hi</p>", "openai", a); got != "<p>Hi</p>" {
+		t.Errorf("suffix without html_suffix: %q", got)
+	}
+	tenantCfg.Attribution.HTMLSuffix = true
+	got := attributionSuffix(ctx, "<p>Hi</p>", "openai", a)
+	if !strings.HasPrefix(got, "<p>Hi</p>\n<!-- airborne-attribution: ") || !strings.HasSuffix(got, " -->") ||
+		!strings.Contains(got, "provider=openai") || !strings.Contains(got, "signature=def") {
+		t.Errorf("unexpected suffix %q", got)
+	}
+	if strings.Count(got, "-->") != 1 {
+		t.Errorf("model name ended the comment early: %q", got)
+	}
+	if got := attributionSuffix(ctx, "", "openai", a); got != "" {
+		t.Errorf("suffix without html content: %q", got)
+	}
+	if got := attributionSuffix(context.Background(), "<p>Hi</p>", "openai", a); got != "<p>Hi</p>" {
+		t.Errorf("suffix without a tenant: %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/redis"
+)
+
+const (
+	// budgetKeyTTL keeps a month's spend counter until well after the month ends.
+	budgetKeyTTL = 40 * 24 * time.Hour
+
+	// microUSDPerUSD converts dollars to the integer units stored in Redis.
+	microUSDPerUSD = 1_000_000
+)
+
+// WithBudgets enables per-tenant monthly spend tracking in Redis and the
+// downgrade policy configured in each tenant's budget.
+func WithBudgets(client *redis.Client) ChatServiceOption {
+	return func(s *ChatService) {
+		s.budgetStore = client
+	}
+}
+
+// budgetDowngrade is a model swap made by the tenant's budget policy.
+type budgetDowngrade struct {
+	originalModel string
+	model         string
+	reason        string
+}
+
+// budgetKey namespaces spend counters per tenant and calendar month (UTC).
+func budgetKey(tenantID string, now time.Time) string {
+	return fmt.Sprintf("airborne:budget:%s:%s", tenantID, now.UTC().Format("2006-01"))
+}
+
+// tenantSpend returns the tenant's spend for the current month in USD.
+func (s *ChatService) tenantSpend(ctx context.Context, tenantID string) (float64, error) {
+	val, err := s.budgetStore.Get(ctx, budgetKey(tenantID, time.Now()))
+	if redis.IsNil(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	micros, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid spend counter: %w", err)
+	}
+	return float64(micros) / microUSDPerUSD, nil
+}
+
+// checkBudget returns the downgrade to apply when the tenant's spend has
+// reached its downgrade threshold and a cheaper model is configured for the
+// provider. Budget lookups fail open: an unavailable counter never blocks or
+// downgrades a request.
+func (s *ChatService) checkBudget(ctx context.Context, providerName, model string) *budgetDowngrade {
+	if s.budgetStore == nil {
+		return nil
+	}
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil {
+		return nil
+	}
+	budget := tenantCfg.Budget
+	threshold := budget.DowngradeThresholdUSD()
+	cheaper := budget.DowngradeModels[providerName]
+	if threshold == 0 || cheaper == "" || cheaper == model {
+		return nil
+	}
+
+	spend, err := s.tenantSpend(ctx, tenantCfg.TenantID)
+	if err != nil {
+		slog.WarnContext(ctx, "budget check failed, skipping downgrade", "tenant_id", tenantCfg.TenantID, "error", err)
+		return nil
+	}
+	if spend < threshold {
+		return nil
+	}
+
+	return &budgetDowngrade{
+		originalModel: model,
+		model:         cheaper,
+		reason: fmt.Sprintf("monthly spend $%.2f reached %.0f%% of the $%.2f budget",
+			spend, threshold/budget.MonthlyUSD*100, budget.MonthlyUSD),
+	}
+}
+
+// recordSpend adds a generation's estimated cost to the tenant's monthly
+// spend. Only tenants with a budget are tracked.
+func (s *ChatService) recordSpend(ctx context.Context, costUSD float64) {
+	if s.budgetStore == nil || costUSD <= 0 {
+		return
+	}
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil || tenantCfg.Budget.MonthlyUSD <= 0 {
+		return
+	}
+
+	key := budgetKey(tenantCfg.TenantID, time.Now())
+	total, err := s.budgetStore.IncrBy(ctx, key, int64(math.Round(costUSD*microUSDPerUSD)))
+	if err != nil {
+		slog.WarnContext(ctx, "failed to record tenant spend", "tenant_id", tenantCfg.TenantID, "error", err)
+		return
+	}
+	if err := s.budgetStore.Expire(ctx, key, budgetKeyTTL); err != nil {
+		slog.WarnContext(ctx, "failed to set spend counter expiry", "tenant_id", tenantCfg.TenantID, "error", err)
+	}
+	slog.DebugContext(ctx, "recorded tenant spend", "tenant_id", tenantCfg.TenantID, "cost_usd", costUSD, "month_usd", float64(total)/microUSDPerUSD)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/alicebob/miniredis/v2"
+)
+
+func budgetTenant() *tenant.TenantConfig {
+	cfg := createTestTenantConfig("openai", "gemini")
+	cfg.Budget = tenant.BudgetConfig{
+		MonthlyUSD:       100,
+		DowngradePercent: 80,
+		DowngradeModels:  map[string]string{"openai": "gpt-4o-mini"},
+	}
+	return cfg
+}
+
+func newBudgetChatService(t *testing.T, mockOpenAI, mockGemini *mockProvider) (*miniredis.Miniredis, *ChatService) {
+	t.Helper()
+	mr, client := newTestRedis(t)
+	svc := createChatServiceWithMocks(mockOpenAI, mockGemini, newMockProvider("anthropic"), nil)
+	WithBudgets(client)(svc)
+	return mr, svc
+}
+
+func setSpend(t *testing.T, mr *miniredis.Miniredis, tenantID string, usd float64) {
+	t.Helper()
+	if err := mr.Set(budgetKey(tenantID, time.Now()), strconv.FormatInt(int64(usd*microUSDPerUSD), 10)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerateReply_BudgetDowngrade(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mr, svc := newBudgetChatService(t, mockOpenAI, newMockProvider("gemini"))
+	ctx := ctxWithChatPermissionAndTenant("test-client", budgetTenant())
+	setSpend(t, mr, "test-tenant", 85)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		ModelOverride:     "gpt-4o",
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+
+	params := mockOpenAI.generateCalls[0]
+	if params.Config.Model != "gpt-4o-mini" || params.OverrideModel != "" {
+		t.Errorf("model = %q (override %q), want gpt-4o-mini", params.Config.Model, params.OverrideModel)
+	}
+	if resp.OriginalModel != "gpt-4o" {
+		t.Errorf("OriginalModel = %q, want gpt-4o", resp.OriginalModel)
+	}
+	if !strings.Contains(resp.DowngradeReason, "80%") {
+		t.Errorf("DowngradeReason = %q, want threshold mentioned", resp.DowngradeReason)
+	}
+}
+
+func TestGenerateReply_BudgetBelowThreshold(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mr, svc := newBudgetChatService(t, mockOpenAI, newMockProvider("gemini"))
+	ctx := ctxWithChatPermissionAndTenant("test-client", budgetTenant())
+	setSpend(t, mr, "test-tenant", 50)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if got := mockOpenAI.generateCalls[0].Config.Model; got == "gpt-4o-mini" {
+		t.Error("request below threshold should not be downgraded")
+	}
+	if resp.DowngradeReason != "" || resp.OriginalModel != "" {
+		t.Errorf("unexpected downgrade annotation: %q / %q", resp.DowngradeReason, resp.OriginalModel)
+	}
+}
+
+func TestGenerateReply_BudgetNoDowngradeModel(t *testing.T) {
+	mockGemini := newMockProvider("gemini")
+	mr, svc := newBudgetChatService(t, newMockProvider("openai"), mockGemini)
+	ctx := ctxWithChatPermissionAndTenant("test-client", budgetTenant())
+	setSpend(t, mr, "test-tenant", 95)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_GEMINI,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.DowngradeReason != "" {
+		t.Errorf("provider without a downgrade model should not be downgraded: %q", resp.DowngradeReason)
+	}
+}
+
+func TestCheckBudget_FailsOpen(t *testing.T) {
+	mr, svc := newBudgetChatService(t, newMockProvider("openai"), newMockProvider("gemini"))
+	ctx := ctxWithChatPermissionAndTenant("test-client", budgetTenant())
+	mr.Close()
+
+	if d := svc.checkBudget(ctx, "openai", "gpt-4o"); d != nil {
+		t.Errorf("expected no downgrade when Redis is unavailable, got %+v", d)
+	}
+}
+
+func TestRecordSpend(t *testing.T) {
+	mr, svc := newBudgetChatService(t, newMockProvider("openai"), newMockProvider("gemini"))
+	ctx := ctxWithChatPermissionAndTenant("test-client", budgetTenant())
+
+	svc.recordSpend(ctx, 1.25)
+	svc.recordSpend(ctx, 0.000004)
+
+	spend, err := svc.tenantSpend(context.Background(), "test-tenant")
+	if err != nil {
+		t.Fatalf("tenantSpend failed: %v", err)
+	}
+	if spend != 1.250004 {
+		t.Errorf("spend = %v, want 1.250004", spend)
+	}
+	if ttl := mr.TTL(budgetKey("test-tenant", time.Now())); ttl <= 0 {
+		t.Error("spend counter should expire")
+	}
+
+	// Tenants without a budget are not tracked
+	unbudgeted := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+	svc.recordSpend(unbudgeted, 5)
+	if spend, _ := svc.tenantSpend(context.Background(), "test-tenant"); spend != 1.250004 {
+		t.Errorf("spend = %v after unbudgeted request, want unchanged", spend)
+	}
+}
@@ -1,28 +1,44 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"html"
 	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ai8future/airborne/internal/anomaly"
 	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/chaos"
 	"github.com/ai8future/airborne/internal/commands"
 	"github.com/ai8future/airborne/internal/db"
 	sanitize "github.com/ai8future/airborne/internal/errors"
+	"github.com/ai8future/airborne/internal/headroom"
 	"github.com/ai8future/airborne/internal/imagegen"
+	"github.com/ai8future/airborne/internal/isolation"
+	"github.com/ai8future/airborne/internal/logctx"
 	"github.com/ai8future/airborne/internal/markdownsvc"
-	"github.com/ai8future/airborne/internal/pricing"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/modelcatalog"
 	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/providerhealth"
 	"github.com/ai8future/airborne/internal/provider/anthropic"
 	"github.com/ai8future/airborne/internal/provider/gemini"
 	"github.com/ai8future/airborne/internal/provider/openai"
+	"github.com/ai8future/airborne/internal/qos"
 	"github.com/ai8future/airborne/internal/rag"
+	"github.com/ai8future/airborne/internal/rag/embedder"
+	"github.com/ai8future/airborne/internal/redis"
 	"github.com/ai8future/airborne/internal/service/config"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/tracing"
 	"github.com/ai8future/airborne/internal/validation"
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -45,14 +61,64 @@ type ChatService struct {
 	imageGen          *imagegen.Client
 	dbClient          *db.Client // Optional: message persistence
 	configBuilder     *config.Builder
+	modelCatalog      *modelcatalog.Catalog // Optional: server-wide model deny list
+	idempotencyStore  *redis.Client         // Optional: idempotent GenerateReply replay
+	idempotencyTTL    time.Duration
+	idempotencyMemory *redis.MemoryStore     // Non-nil when idempotency falls back to memory
+	redisFallbacks    *redis.FallbackTracker // Optional: reports Redis degradation
+	budgetStore       *redis.Client     // Optional: tenant spend tracking for budget downgrades
+	limiter           *qos.Limiter      // Optional: priority-aware concurrency limit on provider calls
+	headroom          *headroom.Tracker // Optional: provider rate-limit headroom per tenant
+	headroomMaxWait   time.Duration
+	metrics           *metrics.Registry // Optional: hedging metrics
+	localEmbedder     embedder.Embedder // Optional: self-hosted embedder for the Embed RPC
+	toolTurns         toolTurnStore     // Turns awaiting tool results, for providers without native continuity
+	dedup             *dedupGroup       // Optional: collapses identical requests into one provider call
+	ledger            UsageLedger       // Optional: records every provider call for reconciliation
+	health            *providerhealth.Tracker // Optional: provider call outcomes for the health dashboard
+	anomalies         *anomaly.Detector // Optional: flags unusual usage by client keys
+	sessions          *auth.SessionStore // Optional: session tokens for the session token RPCs
+	toolTransport     http.RoundTripper // Optional: overrides the egress transport for remote tool calls and fetches (tests)
+	databaseQuery     databaseQuerier   // Optional: overrides the Postgres query of the query_database tool (tests)
+	databasePools     databasePools     // Connection pools of the query_database tool
+}
+
+// ChatServiceOption configures optional ChatService behavior.
+type ChatServiceOption func(*ChatService)
+
+// WithProviders replaces the built-in provider clients, so the service can
+// be load tested against providers that do not call an API.
+func WithProviders(openai, gemini, anthropic provider.Provider) ChatServiceOption {
+	return func(s *ChatService) {
+		s.openaiProvider = openai
+		s.geminiProvider = gemini
+		s.anthropicProvider = anthropic
+	}
+}
+
+// WithProviderFaults wraps the provider clients so their calls get inj's
+// faults, for chaos testing. It must come after WithProviders.
+func WithProviderFaults(inj *chaos.Injector) ChatServiceOption {
+	return func(s *ChatService) {
+		s.openaiProvider = chaos.Provider(s.openaiProvider, inj)
+		s.geminiProvider = chaos.Provider(s.geminiProvider, inj)
+		s.anthropicProvider = chaos.Provider(s.anthropicProvider, inj)
+	}
+}
+
+// WithModelCatalog sets the server-wide model catalog used to deny models.
+func WithModelCatalog(catalog *modelcatalog.Catalog) ChatServiceOption {
+	return func(s *ChatService) {
+		s.modelCatalog = catalog
+	}
 }
 
 // NewChatService creates a new chat service.
 // The ragService parameter is optional - pass nil to disable self-hosted RAG.
 // The imageGen parameter is optional - pass nil to disable image generation.
 // The dbClient parameter is optional - pass nil to disable message persistence.
-func NewChatService(rateLimiter *auth.RateLimiter, ragService *rag.Service, imageGen *imagegen.Client, dbClient *db.Client) *ChatService {
-	return &ChatService{
+func NewChatService(rateLimiter *auth.RateLimiter, ragService *rag.Service, imageGen *imagegen.Client, dbClient *db.Client, opts ...ChatServiceOption) *ChatService {
+	s := &ChatService{
 		openaiProvider:    openai.NewClient(),
 		geminiProvider:    gemini.NewClient(),
 		anthropicProvider: anthropic.NewClient(),
@@ -61,7 +127,14 @@ func NewChatService(rateLimiter *auth.RateLimiter, ragService *rag.Service, imag
 		imageGen:          imageGen,
 		dbClient:          dbClient,
 		configBuilder:     config.NewBuilder(),
+		toolTurns:         redis.NewMemoryStore(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
 	}
+	return s
 }
 
 // preparedRequest holds the result of request preparation shared by both
@@ -73,6 +146,26 @@ type preparedRequest struct {
 	requestID     string
 	providerCfg   provider.ProviderConfig
 	commandResult *commands.Result // Result of slash command parsing
+	memoryUserID  string           // User that extracted memory facts are stored for (empty if memory disabled)
+	downgrade     *budgetDowngrade // Set when the budget policy swapped in a cheaper model
+	preemptedFrom string           // Provider skipped because its rate limit was exhausted
+	hedged        bool             // A hedge request was sent to a second provider
+	pin           *db.ThreadPin    // New pin of the request's thread; nil if unchanged
+	requestModel  string           // Model the request itself selected via model_override
+	tiered        bool             // Try the tenant's cheap model first (the "auto" model tier)
+}
+
+// paramsFor returns the request's params for providerName with config cfg.
+// A provider other than the selected one drops any model the server chose
+// for the selected provider, such as the thread's pinned model, keeping only
+// the one the request selected.
+func (p *preparedRequest) paramsFor(providerName string, cfg provider.ProviderConfig) provider.GenerateParams {
+	params := p.params
+	params.Config = cfg
+	if providerName != p.provider.Name() {
+		params.OverrideModel = p.requestModel
+	}
+	return params
 }
 
 // prepareRequest validates the request and prepares all data needed for generation.
@@ -89,6 +182,15 @@ func (s *ChatService) prepareRequest(ctx context.Context, req *pb.GenerateReplyR
 		}
 	}
 
+	// Feature flags: tenant settings, overridable per request by admins
+	features, err := resolveFeatures(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := features.checkRequest(req); err != nil {
+		return nil, err
+	}
+
 	// Validate input sizes
 	if err := validation.ValidateGenerateRequest(
 		req.UserInput,
@@ -103,23 +205,42 @@ func (s *ChatService) prepareRequest(ctx context.Context, req *pb.GenerateReplyR
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if !tenant.ValidRetrievalQueryMode(req.RetrievalQueryMode) {
+		return nil, status.Errorf(codes.InvalidArgument, "retrieval_query_mode must be latest, recent or condense, got %q", req.RetrievalQueryMode)
+	}
+	if req.RagContextTokens < 0 {
+		return nil, status.Error(codes.InvalidArgument, "rag_context_tokens must not be negative")
+	}
+	if req.Preset != "" {
+		tenantCfg := auth.TenantFromContext(ctx)
+		if tenantCfg == nil {
+			return nil, status.Error(codes.InvalidArgument, "preset requires a tenant config")
+		}
+		if _, ok := tenantCfg.Preset(req.Preset); !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown preset %q", req.Preset)
+		}
+	}
+
 	// Validate or generate request ID
 	requestID, err := validation.ValidateOrGenerateRequestID(req.RequestId)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Validate request
-	if strings.TrimSpace(req.UserInput) == "" {
+	// Validate request (tool results may be sent without new input)
+	if strings.TrimSpace(req.UserInput) == "" && !continuesToolTurn(req) {
 		return nil, status.Error(codes.InvalidArgument, "user_input is required")
 	}
 
 	// Parse slash commands from user input
 	var commandResult *commands.Result
 	tenantCfg := auth.TenantFromContext(ctx)
-	if tenantCfg != nil {
+	if tenantCfg != nil && strings.TrimSpace(req.UserInput) != "" {
 		// Build image triggers list: configured triggers + /image
-		imageTriggers := append([]string{"/image"}, tenantCfg.ImageGeneration.TriggerPhrases...)
+		var imageTriggers []string
+		if features[tenant.FeatureImageGeneration] {
+			imageTriggers = append([]string{"/image"}, tenantCfg.ImageGeneration.TriggerPhrases...)
+		}
 		parser := commands.NewParser(imageTriggers)
 		parsed := parser.Parse(req.UserInput)
 		commandResult = &parsed
@@ -132,22 +253,101 @@ func (s *ChatService) prepareRequest(ctx context.Context, req *pb.GenerateReplyR
 		}
 	}
 
-	// Select provider (with tenant awareness)
-	selectedProvider, err := s.selectProviderWithTenant(ctx, req)
+	// Select provider (with tenant awareness); turns of a stored thread
+	// default to the provider it is pinned to
+	pin := s.loadThreadPin(ctx, req.RequestId, req.ClientId)
+	selectedProvider, err := s.selectProviderWithTenant(ctx, req, pin)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid provider: %v", err)
 	}
 
+	// Enforce model catalog on client-selected models
+	if err := s.checkRequestedModel(ctx, req, selectedProvider.Name()); err != nil {
+		return nil, err
+	}
+
+	// Computer use is permission-gated and provider-specific
+	computerUse, err := checkComputerUse(ctx, req, selectedProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attachments must be readable by the selected provider
+	attachments, err := checkAttachments(ctx, req, selectedProvider)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) > 0 {
+		accesslog.Annotate(ctx, "attachments", len(attachments))
+	}
+	images, err := checkImages(req, selectedProvider)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) > 0 {
+		accesslog.Annotate(ctx, "images", len(images))
+	}
+
+	// Tool results continue the turn that requested them
+	toolTurn, err := s.loadToolTurn(ctx, req, selectedProvider)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build provider config (from tenant + request overrides)
 	providerCfg := s.buildProviderConfig(ctx, req, selectedProvider.Name())
 
+	// "auto" leaves the model to the tenant's tiering, which tries its cheap model first
+	tiered, err := tieredRequest(tenantCfg, req, selectedProvider.Name())
+	if err != nil {
+		return nil, err
+	}
+	requestModel := req.ModelOverride
+	if isAutoModel(requestModel) {
+		requestModel = ""
+	}
+
+	// Stay on the pinned model, and pin the thread to this turn's choice
+	overrideModel := requestModel
+	if followsPin(tenantCfg, req, pin) {
+		if model := s.pinnedModel(ctx, req, pin); model != "" {
+			overrideModel = model
+		}
+		accesslog.Annotate(ctx, "pinned_provider", pin.Provider)
+	}
+	newPin := &db.ThreadPin{Provider: selectedProvider.Name(), Model: provider.SelectModel(providerCfg.Model, "", overrideModel)}
+	if pin != nil && *pin == *newPin {
+		newPin = nil
+	}
+
+	// Serve from a cheaper model instead of failing when the tenant nears its budget
+	downgrade := s.checkBudget(ctx, selectedProvider.Name(), provider.SelectModel(providerCfg.Model, "", overrideModel))
+	if downgrade != nil {
+		slog.InfoContext(ctx, "budget downgrade applied",
+			"provider", selectedProvider.Name(),
+			"original_model", downgrade.originalModel,
+			"model", downgrade.model,
+			"reason", downgrade.reason,
+		)
+		providerCfg.Model = downgrade.model
+		overrideModel = ""
+		accesslog.Annotate(ctx, "budget_downgrade_from", downgrade.originalModel)
+	}
+	if toolTurn != nil && toolTurn.Model != "" {
+		overrideModel = toolTurn.Model // Replayed state must go back to the same model
+	}
+	if downgrade != nil || toolTurn != nil {
+		tiered = false
+	}
+
 	// Retrieve RAG context for non-OpenAI providers
 	var ragChunks []rag.RetrieveResult
 	instructions := req.Instructions
 	if req.EnableFileSearch && strings.TrimSpace(req.FileStoreId) != "" && selectedProvider.Name() != "openai" {
-		chunks, err := s.retrieveRAGContext(ctx, req.FileStoreId, req.UserInput)
+		query := s.retrievalQuery(ctx, req, selectedProvider, requestID)
+		chunks, err := s.retrieveRAGContext(ctx, req.FileStoreId, query, ragPackOptions(ctx, req))
 		if err != nil {
-			slog.Warn("RAG retrieval failed, continuing without context",
+			slog.WarnContext(ctx, "RAG retrieval failed, continuing without context",
 				"error", err,
 				"store_id", req.FileStoreId,
 			)
@@ -155,13 +355,51 @@ func (s *ChatService) prepareRequest(ctx context.Context, req *pb.GenerateReplyR
 			ragChunks = chunks
 			ragContext := formatRAGContext(chunks)
 			instructions = instructions + ragContext
-			slog.Info("injected RAG context",
-				"store_id", req.FileStoreId,
-				"chunks", len(chunks),
-			)
+			accesslog.Annotate(ctx, "rag_store_id", req.FileStoreId, "rag_chunks", len(chunks))
+		}
+	}
+
+	// Merge the user's personalization profile, scoped like MemoryService calls
+	var profile *db.UserProfile
+	if userID := strings.TrimSpace(req.UserId); userID != "" && s.dbClient != nil {
+		user, err := scopedUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		profile = s.loadUserProfile(ctx, user)
+	}
+	if profile != nil && !profile.IsEmpty() {
+		instructions = instructions + formatProfileContext(profile)
+		accesslog.Annotate(ctx, "user_profile", true)
+	}
+
+	// Inject remembered facts about the user, scoped like MemoryService calls
+	var userForMemory string
+	if req.EnableMemory && s.dbClient != nil {
+		user, err := scopedUserID(ctx, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		userForMemory = user
+		if memories := s.loadMemories(ctx, userForMemory); len(memories) > 0 {
+			instructions = instructions + formatMemoryContext(memories)
+			accesslog.Annotate(ctx, "memory_facts", len(memories))
 		}
 	}
 
+	// Give the current date in the user's time zone; last, as it changes every request
+	if tenantCfg != nil && tenantCfg.DateContext.Inject {
+		loc := dateContextLocation(ctx, tenantCfg, profile)
+		instructions = instructions + formatDateContext(time.Now(), loc)
+		accesslog.Annotate(ctx, "timezone", loc.String())
+	}
+
+	// Memory facts are extracted via the structured output path (Gemini-only)
+	enableStructuredOutput := req.EnableStructuredOutput
+	if userForMemory != "" && selectedProvider.Name() == provider.NameGemini {
+		enableStructuredOutput = true
+	}
+
 	// Use authenticated client ID, falling back to request client_id
 	clientID := req.ClientId
 	if client := auth.ClientFromContext(ctx); client != nil && client.ClientID != "" {
@@ -170,23 +408,39 @@ func (s *ChatService) prepareRequest(ctx context.Context, req *pb.GenerateReplyR
 
 	// Build params
 	params := provider.GenerateParams{
-		Instructions:           instructions, // May include RAG context for non-OpenAI
+		Instructions:           instructions, // May include RAG, memory and date context
 		UserInput:              req.UserInput,
 		ConversationHistory:    convertHistory(req.ConversationHistory),
 		FileStoreID:            req.FileStoreId,
 		PreviousResponseID:     req.PreviousResponseId,
-		OverrideModel:          req.ModelOverride,
+		OverrideModel:          overrideModel,
 		EnableWebSearch:        req.EnableWebSearch,
 		EnableFileSearch:       req.EnableFileSearch,
 		EnableCodeExecution:    req.EnableCodeExecution,
-		EnableStructuredOutput: req.EnableStructuredOutput,
+		EnableStructuredOutput: enableStructuredOutput,
 		FileIDToFilename:       req.FileIdToFilename,
 		Tools:                  convertTools(req.Tools),
 		ToolResults:            convertToolResults(req.ToolResults),
+		ComputerUse:            computerUse,
+		Attachments:            attachments,
+		InlineImages:           images,
 		Config:                 providerCfg,
 		RequestID:              requestID,
 		ClientID:               clientID,
 	}
+	if toolTurn != nil {
+		params.ToolTurn = &provider.ToolTurn{
+			UserInput: toolTurn.UserInput,
+			ToolCalls: toolTurn.ToolCalls,
+			State:     toolTurn.State,
+		}
+	}
+
+	accesslog.Annotate(ctx,
+		"provider", selectedProvider.Name(),
+		"model", providerCfg.Model,
+		"request_id", requestID,
+	)
 
 	return &preparedRequest{
 		provider:      selectedProvider,
@@ -195,6 +449,11 @@ func (s *ChatService) prepareRequest(ctx context.Context, req *pb.GenerateReplyR
 		requestID:     requestID,
 		providerCfg:   providerCfg,
 		commandResult: commandResult,
+		memoryUserID:  userForMemory,
+		downgrade:     downgrade,
+		pin:           newPin,
+		requestModel:  requestModel,
+		tiered:        tiered,
 	}, nil
 }
 
@@ -229,11 +488,32 @@ func (s *ChatService) GenerateReply(ctx context.Context, req *pb.GenerateReplyRe
 		return nil, err
 	}
 
+	// Replay the stored response for an idempotent retry
+	claim, cached, err := s.claimIdempotency(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		accesslog.Annotate(ctx, "cached", true)
+		return cached, nil
+	}
+
+	resp, err := s.dedupReply(ctx, req)
+	claim.finish(ctx, resp, err)
+	return resp, err
+}
+
+// generateReply runs a unary generation once permission and idempotency are settled.
+func (s *ChatService) generateReply(ctx context.Context, req *pb.GenerateReplyRequest) (*pb.GenerateReplyResponse, error) {
+	ctx, cancel := withRequestTimeout(ctx, req)
+	defer cancel()
+
 	// Prepare request (validation, provider selection, RAG retrieval, params building)
 	prepared, err := s.prepareRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	ctx = logctx.With(ctx, logctx.KeyRequestID, prepared.requestID)
 
 	// Handle slash commands
 	if prepared.commandResult != nil {
@@ -256,82 +536,146 @@ func (s *ChatService) GenerateReply(ctx context.Context, req *pb.GenerateReplyRe
 		}
 	}
 
-	slog.Info("generating reply",
-		"provider", prepared.provider.Name(),
-		"model", prepared.providerCfg.Model,
-		"request_id", prepared.requestID,
-		"client_id", prepared.params.ClientID,
-	)
+	// Avoid sending requests a provider has told us it will reject
+	if err := s.awaitHeadroom(ctx, req, prepared); err != nil {
+		return nil, err
+	}
+
+	// Wait for a provider slot according to the request's priority
+	release, err := s.acquireSlot(ctx, req, prepared.provider.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Offer the tenant's remote tools, which are answered server-side below
+	if err := addRemoteTools(ctx, &prepared.params); err != nil {
+		return nil, err
+	}
 
 	// Track processing time
 	startTime := time.Now()
 
-	// Generate reply
-	result, err := prepared.provider.GenerateReply(ctx, prepared.params)
+	// Try the cheap model first for "auto" model tier requests
+	var result provider.GenerateResult
+	tier := modelTierFor(ctx, prepared)
+	if tier != nil {
+		result = s.generateCheap(ctx, req, prepared, tier)
+	}
+
+	// Generate reply, hedging to a second provider if requested
+	if tier.served() {
+		prepared.params.OverrideModel = tier.cheap // Validation retries and judge candidates stay on it
+	} else if hedge := s.hedgeTarget(ctx, req, prepared); hedge != nil {
+		result, err = s.generateHedged(ctx, req, prepared, hedge)
+	} else {
+		// Leave the failover chain part of the request deadline
+		primaryCtx, cancelPrimary := withBudget(ctx, s.primaryBudget(ctx, req, prepared))
+		result, err = prepared.provider.GenerateReply(s.observeHeadroom(primaryCtx, prepared.provider.Name()), prepared.params)
+		cancelPrimary()
+		s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, result, err)
+	}
+	if err != nil && s.ragFallback(ctx, req, prepared, err) {
+		// Native file search failed; retry with the mirrored internal store
+		result, err = prepared.provider.GenerateReply(s.observeHeadroom(ctx, prepared.provider.Name()), prepared.params)
+		s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, result, err)
+	}
+	if err == nil {
+		result, err = s.runRemoteTools(ctx, prepared.provider, prepared.params, result)
+	}
 	if err != nil {
-		// Try failover if enabled
-		if req.EnableFailover {
-			fallbackProvider := s.getFallbackProvider(prepared.provider.Name(), req.FallbackProvider)
-			if fallbackProvider != nil {
-				slog.Warn("primary provider failed, trying fallback",
-					"primary", prepared.provider.Name(),
-					"fallback", fallbackProvider.Name(),
-					"error", err,
-				)
-
-				prepared.params.Config = s.buildProviderConfig(ctx, req, fallbackProvider.Name())
-				fallbackResult, fallbackErr := fallbackProvider.GenerateReply(ctx, prepared.params)
-				if fallbackErr == nil {
-					// Render HTML for fallback result if markdown_svc is enabled
-					var fallbackHTML string
-					if markdownsvc.IsEnabled() {
-						html, renderErr := markdownsvc.RenderHTML(ctx, fallbackResult.Text)
-						if renderErr == nil {
-							fallbackHTML = html
-						} else {
-							slog.Warn("markdown_svc render failed for fallback", "error", renderErr)
-						}
-					}
-					return s.buildResponse(fallbackResult, fallbackProvider.Name(), true, prepared.provider.Name(), sanitize.SanitizeForClient(err), fallbackHTML), nil
-				}
-				// Return original error if fallback also fails
+		// Try failover if enabled (a fired hedge already tried the fallback)
+		var validationAttempts []db.ValidationAttempt
+		if failoverAllowed(req, prepared) {
+			var resp *pb.GenerateReplyResponse
+			if resp, validationAttempts = s.generateWithFailover(ctx, req, prepared, err, time.Since(startTime), nil); resp != nil {
+				s.observeLatency(ctx, metrics.SLOCompletion, providerNameFromProto(resp.Provider), time.Since(startTime))
+				return resp, nil
 			}
+			// Return original error if every fallback also fails
 		}
 		processingTimeMs := int(time.Since(startTime).Milliseconds())
-		slog.Error("provider request failed",
+		slog.ErrorContext(ctx, "provider request failed",
 			"provider", prepared.provider.Name(),
 			"error", err,
 			"request_id", prepared.requestID,
 			"processing_ms", processingTimeMs,
 		)
 		// Persist the failed request for activity tracking
-		s.persistFailedRequest(ctx, req, prepared.provider.Name(), prepared.providerCfg.Model, sanitize.SanitizeForClient(err), processingTimeMs)
+		s.persistFailedRequest(ctx, req, prepared.provider.Name(), prepared.providerCfg.Model, sanitize.SanitizeForClient(err), processingTimeMs, validationAttempts)
 		return nil, status.Error(codes.Internal, sanitize.SanitizeForClient(err))
 	}
 
+	// Regenerate replies that fail the tenant's validation rules
+	result, validationAttempts, err := s.validateReply(ctx, prepared.provider, prepared.params, result)
+	if err != nil {
+		if failoverAllowed(req, prepared) && failoverTriggered(ctx, tenant.FailoverTriggerValidation) {
+			accesslog.Annotate(ctx, "failover_trigger", tenant.FailoverTriggerValidation)
+			var resp *pb.GenerateReplyResponse
+			if resp, validationAttempts = s.generateWithFailover(ctx, req, prepared, err, time.Since(startTime), validationAttempts); resp != nil {
+				s.observeLatency(ctx, metrics.SLOCompletion, providerNameFromProto(resp.Provider), time.Since(startTime))
+				return resp, nil
+			}
+		}
+		processingTimeMs := int(time.Since(startTime).Milliseconds())
+		slog.ErrorContext(ctx, "reply validation failed",
+			"provider", prepared.provider.Name(),
+			"error", err,
+			"request_id", prepared.requestID,
+			"attempts", len(validationAttempts),
+		)
+		s.persistFailedRequest(ctx, req, prepared.provider.Name(), prepared.providerCfg.Model, status.Convert(err).Message(), processingTimeMs, validationAttempts)
+		return nil, err
+	}
+	if result.SafetyBlock != nil {
+		accesslog.Annotate(ctx, "safety_block", result.SafetyBlock.Stage)
+	}
+	if trigger, reason := replyFailoverTrigger(ctx, result); trigger != "" && failoverAllowed(req, prepared) {
+		accesslog.Annotate(ctx, "failover_trigger", trigger)
+		var resp *pb.GenerateReplyResponse
+		if resp, validationAttempts = s.generateWithFailover(ctx, req, prepared, errors.New(reason), time.Since(startTime), validationAttempts); resp != nil {
+			// The rejected reply was still billed
+			s.recordSpend(ctx, estimateCost(prepared.provider.Name(), result.Model, result.Usage, result.GroundingQueries).Total())
+			s.observeLatency(ctx, metrics.SLOCompletion, providerNameFromProto(resp.Provider), time.Since(startTime))
+			return resp, nil
+		}
+		// Keep the original reply if every fallback also fails
+	}
+
+	// Pick the best of several replies if the tenant has a judge policy
+	result, judgement := s.judgeCandidates(ctx, req, prepared, result)
+
+	// Check the reply against the retrieved chunks if the tenant has a grounding policy
+	result, grounding := s.groundReply(ctx, req, prepared, prepared.provider.Name(), result)
+	if result.RequiresToolOutput {
+		result.ResponseID = s.saveToolTurn(ctx, prepared.provider, prepared.params, result.Model, result.ResponseID, provider.PendingCalls(result.ToolCalls, result.ComputerActions), result.ToolTurnState)
+	}
+
 	// Record token usage for rate limiting
 	if s.rateLimiter != nil && result.Usage != nil {
 		client := auth.ClientFromContext(ctx)
 		if client != nil {
 			if err := s.rateLimiter.RecordTokens(ctx, client.ClientID, result.Usage.TotalTokens, client.RateLimits.TokensPerMinute); err != nil {
-				slog.Warn("failed to record token usage for rate limiting", "client_id", client.ClientID, "error", err)
+				slog.WarnContext(ctx, "failed to record token usage for rate limiting", "client_id", client.ClientID, "error", err)
 			}
 		}
 	}
 
-	// Add RAG citations to result if we used self-hosted RAG
-	if len(prepared.ragChunks) > 0 {
+	// Add RAG citations to result if we used self-hosted RAG (a fallback
+	// answer is not drawn from them)
+	if len(prepared.ragChunks) > 0 && grounding.GetAction() != tenant.GroundingActionFallback {
 		result.Citations = append(result.Citations, ragChunksToCitations(prepared.ragChunks)...)
 	}
 
-	// Render HTML if markdown_svc is enabled
+	// Render HTML if markdown_svc is enabled, with citations as footnotes
+	footnoted := replyFootnotes(result.Text, result.Citations, grounding)
 	var htmlContent string
 	if markdownsvc.IsEnabled() {
-		html, err := markdownsvc.RenderHTML(ctx, result.Text)
+		html, err := markdownsvc.RenderHTML(ctx, cmp.Or(footnoted, result.Text))
 		if err == nil {
 			htmlContent = html
 		} else {
-			slog.Warn("markdown_svc render failed", "error", err)
+			slog.WarnContext(ctx, "markdown_svc render failed", "error", err)
 		}
 	}
 
@@ -340,26 +684,59 @@ func (s *ChatService) GenerateReply(ctx context.Context, req *pb.GenerateReplyRe
 
 	// Persist conversation asynchronously (if database client is configured)
 	if s.dbClient != nil && result.Usage != nil {
-		s.persistConversation(ctx, req, result, prepared.provider.Name(), prepared.providerCfg.Model, htmlContent, processingTimeMs)
+		s.persistConversation(ctx, req, result, prepared.provider.Name(), calledModel(prepared.params, ""), htmlContent, processingTimeMs, validationAttempts, prepared.pin)
 	}
 
-	return s.buildResponse(result, prepared.provider.Name(), false, "", "", htmlContent), nil
+	// Remember durable facts extracted from this turn
+	if result.StructuredMetadata != nil {
+		s.persistMemoryFacts(ctx, prepared.memoryUserID, result.StructuredMetadata.Facts)
+	}
+
+	resp := s.buildResponse(result, prepared.provider.Name(), prepared.preemptedFrom != "", prepared.preemptedFrom, preemptedReason(prepared), htmlContent)
+	if prepared.downgrade != nil {
+		resp.DowngradeReason = prepared.downgrade.reason
+		resp.OriginalModel = prepared.downgrade.originalModel
+	}
+	if tier != nil {
+		resp.ModelTier, resp.EscalationReason = tier.name(), tier.escalation
+	}
+	resp.Hedged = prepared.hedged
+	resp.Judge = judgement
+	resp.Grounding = grounding
+	resp.FootnotedMarkdown = footnoted
+	s.recordSpend(ctx, resp.EstimatedCostUsd)
+	s.observeLatency(ctx, metrics.SLOCompletion, prepared.provider.Name(), time.Since(startTime))
+	return resp, nil
 }
 
 // GenerateReplyStream generates a streaming completion.
 func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream pb.AirborneService_GenerateReplyStreamServer) error {
-	ctx := stream.Context()
+	ctx, cancel := withRequestTimeout(stream.Context(), req)
+	defer cancel()
 
 	// Check permission
 	if err := auth.RequirePermission(ctx, auth.PermissionChatStream); err != nil {
 		return err
 	}
 
+	// The cheap reply must be complete before it can be escalated
+	if isAutoModel(req.ModelOverride) {
+		return status.Error(codes.InvalidArgument, `model_override "auto" is not supported by GenerateReplyStream`)
+	}
+
 	// Prepare request (validation, provider selection, RAG retrieval, params building)
 	prepared, err := s.prepareRequest(ctx, req)
 	if err != nil {
 		return err
 	}
+	ctx = logctx.With(ctx, logctx.KeyRequestID, prepared.requestID)
+
+	// Streamed replies use stored memories but extract none: extraction
+	// needs structured output, whose JSON reply cannot be streamed as text
+	if prepared.memoryUserID != "" {
+		prepared.memoryUserID = ""
+		prepared.params.EnableStructuredOutput = req.EnableStructuredOutput
+	}
 
 	// Handle slash commands
 	if prepared.commandResult != nil {
@@ -391,16 +768,47 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 		}
 	}
 
+	// Avoid sending requests a provider has told us it will reject
+	if err := s.awaitHeadroom(ctx, req, prepared); err != nil {
+		return err
+	}
+
+	// Wait for a provider slot; it is held until the stream completes
+	release, err := s.acquireSlot(ctx, req, prepared.provider.Name())
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Track processing time for streaming
 	startTime := time.Now()
 
-	// Generate streaming reply
-	streamChunks, err := prepared.provider.GenerateReplyStream(ctx, prepared.params)
+	// Generate streaming reply, hedging to a second provider if requested
+	var streamChunks <-chan provider.StreamChunk
+	if hedge := s.hedgeTarget(ctx, req, prepared); hedge != nil {
+		streamChunks, err = s.streamHedged(ctx, req, prepared, hedge)
+	} else {
+		streamChunks, err = prepared.provider.GenerateReplyStream(s.observeHeadroom(ctx, prepared.provider.Name()), prepared.params)
+		if err != nil {
+			s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, provider.GenerateResult{}, err)
+		}
+	}
+	if err != nil && s.ragFallback(ctx, req, prepared, err) {
+		// Native file search failed; retry with the mirrored internal store
+		streamChunks, err = prepared.provider.GenerateReplyStream(s.observeHeadroom(ctx, prepared.provider.Name()), prepared.params)
+		if err != nil {
+			s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, provider.GenerateResult{}, err)
+		}
+	}
 	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err // Cancelled while waiting for a hedged stream
+		}
 		return status.Error(codes.Internal, sanitize.SanitizeForClient(err))
 	}
 
 	var accumulatedText strings.Builder
+	var streamCitations []provider.Citation
 
 	// Send RAG citations first if we have them
 	for _, chunk := range prepared.ragChunks {
@@ -427,11 +835,16 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 	}
 
 	// Forward chunks from provider
+	firstToken := true
 	for chunk := range streamChunks {
 		var pbChunk *pb.GenerateReplyChunk
 
 		switch chunk.Type {
 		case provider.ChunkTypeText:
+			if firstToken {
+				firstToken = false
+				s.observeLatency(ctx, metrics.SLOFirstToken, prepared.provider.Name(), time.Since(startTime))
+			}
 			pbChunk = &pb.GenerateReplyChunk{
 				Chunk: &pb.GenerateReplyChunk_TextDelta{
 					TextDelta: &pb.TextDelta{
@@ -442,15 +855,22 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 			}
 			accumulatedText.WriteString(chunk.Text)
 		case provider.ChunkTypeUsage:
+			model := chunk.Model
+			if model == "" {
+				model = prepared.providerCfg.Model
+			}
 			pbChunk = &pb.GenerateReplyChunk{
 				Chunk: &pb.GenerateReplyChunk_UsageUpdate{
 					UsageUpdate: &pb.UsageUpdate{
-						Usage: convertUsage(chunk.Usage),
+						Usage:            convertUsage(chunk.Usage),
+						EstimatedCostUsd: estimateCost(prepared.provider.Name(), model, chunk.Usage, 0).Total(),
+						Estimated:        chunk.UsageEstimated,
 					},
 				},
 			}
 		case provider.ChunkTypeCitation:
 			if chunk.Citation != nil {
+				streamCitations = append(streamCitations, *chunk.Citation)
 				pbChunk = &pb.GenerateReplyChunk{
 					Chunk: &pb.GenerateReplyChunk_CitationUpdate{
 						CitationUpdate: &pb.CitationUpdate{
@@ -469,6 +889,16 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 					},
 				}
 			}
+		case provider.ChunkTypeComputerAction:
+			if chunk.ComputerAction != nil {
+				pbChunk = &pb.GenerateReplyChunk{
+					Chunk: &pb.GenerateReplyChunk_ComputerActionUpdate{
+						ComputerActionUpdate: &pb.ComputerActionUpdate{
+							Action: convertComputerAction(*chunk.ComputerAction),
+						},
+					},
+				}
+			}
 		case provider.ChunkTypeCodeExecution:
 			if chunk.CodeExecution != nil {
 				pbChunk = &pb.GenerateReplyChunk{
@@ -480,24 +910,57 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 				}
 			}
 		case provider.ChunkTypeComplete:
+			s.observeLatency(ctx, metrics.SLOCompletion, prepared.provider.Name(), time.Since(startTime))
+			s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, provider.GenerateResult{Model: chunk.Model, Usage: chunk.Usage, GroundingQueries: chunk.GroundingQueries}, nil)
+			if chunk.RequiresToolOutput {
+				chunk.ResponseID = s.saveToolTurn(ctx, prepared.provider, prepared.params, chunk.Model, chunk.ResponseID, provider.PendingCalls(chunk.ToolCalls, chunk.ComputerActions), chunk.ToolTurnState)
+			}
+
+			// Check the streamed reply against the retrieved chunks. The reply
+			// was already sent, so a disclaimer is sent as its end and a
+			// fallback answer is left to the client to show instead
+			streamed := provider.GenerateResult{
+				Text:               accumulatedText.String(),
+				ToolCalls:          chunk.ToolCalls,
+				ComputerActions:    chunk.ComputerActions,
+				RequiresToolOutput: chunk.RequiresToolOutput,
+				SafetyBlock:        chunk.SafetyBlock,
+			}
+			checked, grounding := s.groundReply(ctx, req, prepared, prepared.provider.Name(), streamed)
+			switch grounding.GetAction() {
+			case tenant.GroundingActionDisclaimer:
+				disclaimer := strings.TrimPrefix(checked.Text, streamed.Text)
+				if err := stream.Send(&pb.GenerateReplyChunk{
+					Chunk: &pb.GenerateReplyChunk_TextDelta{TextDelta: &pb.TextDelta{Text: disclaimer}},
+				}); err != nil {
+					return err
+				}
+				accumulatedText.WriteString(disclaimer)
+			case tenant.GroundingActionFallback:
+				grounding.FallbackText = checked.Text
+				accumulatedText.Reset()
+				accumulatedText.WriteString(checked.Text)
+			}
+
 			// Record token usage for rate limiting on stream completion
 			if s.rateLimiter != nil && chunk.Usage != nil {
 				client := auth.ClientFromContext(ctx)
 				if client != nil {
 					if err := s.rateLimiter.RecordTokens(ctx, client.ClientID, chunk.Usage.TotalTokens, client.RateLimits.TokensPerMinute); err != nil {
-						slog.Warn("failed to record stream token usage for rate limiting", "client_id", client.ClientID, "error", err)
+						slog.WarnContext(ctx, "failed to record stream token usage for rate limiting", "client_id", client.ClientID, "error", err)
 					}
 				}
 			}
 
-			// Render HTML if markdown_svc is enabled
+			// Render HTML if markdown_svc is enabled, with citations as footnotes
+			footnoted := footnoteCitations(accumulatedText.String(), streamCitations)
 			var htmlContent string
 			if markdownsvc.IsEnabled() {
-				html, renderErr := markdownsvc.RenderHTML(ctx, accumulatedText.String())
+				html, renderErr := markdownsvc.RenderHTML(ctx, cmp.Or(footnoted, accumulatedText.String()))
 				if renderErr == nil {
 					htmlContent = html
 				} else {
-					slog.Warn("markdown_svc render failed for stream", "error", renderErr)
+					slog.WarnContext(ctx, "markdown_svc render failed for stream", "error", renderErr)
 				}
 			}
 
@@ -509,11 +972,13 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 					Usage:            chunk.Usage,
 					ToolCalls:        chunk.ToolCalls,
 					GroundingQueries: chunk.GroundingQueries,
+					SystemPrompt:     chunk.SystemPrompt,
+					UserPrompt:       chunk.UserPrompt,
 					RequestJSON:      chunk.RequestJSON,
 					ResponseJSON:     chunk.ResponseJSON,
 				}
 				processingTimeMs := int(time.Since(startTime).Milliseconds())
-				s.persistConversation(ctx, req, streamResult, prepared.provider.Name(), chunk.Model, htmlContent, processingTimeMs)
+				s.persistConversation(ctx, req, streamResult, prepared.provider.Name(), chunk.Model, htmlContent, processingTimeMs, nil, prepared.pin)
 			}
 
 			complete := &pb.StreamComplete{
@@ -523,6 +988,11 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 				FinalUsage:         convertUsage(chunk.Usage),
 				RequiresToolOutput: chunk.RequiresToolOutput,
 				HtmlContent:        htmlContent,
+				EstimatedCostUsd:   estimateCost(prepared.provider.Name(), chunk.Model, chunk.Usage, chunk.GroundingQueries).Total(),
+				SafetyBlock:        convertSafetyBlock(chunk.SafetyBlock),
+			}
+			if chunk.SafetyBlock != nil {
+				accesslog.Annotate(ctx, "safety_block", chunk.SafetyBlock.Stage)
 			}
 			for _, tc := range chunk.ToolCalls {
 				complete.ToolCalls = append(complete.ToolCalls, convertToolCall(tc))
@@ -530,12 +1000,26 @@ func (s *ChatService) GenerateReplyStream(req *pb.GenerateReplyRequest, stream p
 			for _, ce := range chunk.CodeExecutions {
 				complete.CodeExecutions = append(complete.CodeExecutions, convertCodeExecution(ce))
 			}
+			for _, action := range chunk.ComputerActions {
+				complete.ComputerActions = append(complete.ComputerActions, convertComputerAction(action))
+			}
+			if prepared.downgrade != nil {
+				complete.DowngradeReason = prepared.downgrade.reason
+				complete.OriginalModel = prepared.downgrade.originalModel
+			}
+			complete.Hedged = prepared.hedged
+			complete.Grounding = grounding
+			complete.FootnotedMarkdown = footnoted
+			complete.Attribution = attribute(ctx, req, prepared.provider.Name(), chunk.Model)
+			complete.HtmlContent = attributionSuffix(ctx, complete.HtmlContent, prepared.provider.Name(), complete.Attribution)
+			s.recordSpend(ctx, complete.EstimatedCostUsd)
 			pbChunk = &pb.GenerateReplyChunk{
 				Chunk: &pb.GenerateReplyChunk_Complete{
 					Complete: complete,
 				},
 			}
 		case provider.ChunkTypeError:
+			s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, provider.GenerateResult{}, chunk.Error)
 			pbChunk = &pb.GenerateReplyChunk{
 				Chunk: &pb.GenerateReplyChunk_Error{
 					Error: &pb.StreamError{
@@ -575,8 +1059,15 @@ func (s *ChatService) SelectProvider(ctx context.Context, req *pb.SelectProvider
 		}
 	}
 
-	// Check for continuity
-	if req.ExistingProvider != "" {
+	// Continue on the thread's pinned provider and model
+	if pin := s.loadThreadPin(ctx, req.ThreadId, req.ClientId); pin != nil {
+		return &pb.SelectProviderResponse{
+			Provider:      mapProviderToProto(pin.Provider),
+			ModelOverride: pin.Model,
+			Reason:        "continuity",
+		}, nil
+	}
+	if req.ThreadId == "" && req.ExistingProvider != "" {
 		return &pb.SelectProviderResponse{
 			Provider: mapProviderToProto(req.ExistingProvider),
 			Reason:   "continuity",
@@ -590,41 +1081,107 @@ func (s *ChatService) SelectProvider(ctx context.Context, req *pb.SelectProvider
 	}, nil
 }
 
-// getFallbackProvider returns a fallback provider.
-func (s *ChatService) getFallbackProvider(primary string, specified pb.Provider) provider.Provider {
-	if specified != pb.Provider_PROVIDER_UNSPECIFIED {
-		switch specified {
-		case pb.Provider_PROVIDER_OPENAI:
-			return s.openaiProvider
-		case pb.Provider_PROVIDER_GEMINI:
-			return s.geminiProvider
-		case pb.Provider_PROVIDER_ANTHROPIC:
-			return s.anthropicProvider
+// GetCapabilities lists the providers enabled for the tenant and the features each supports.
+func (s *ChatService) GetCapabilities(ctx context.Context, req *pb.GetCapabilitiesRequest) (*pb.GetCapabilitiesResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChat); err != nil {
+		return nil, err
+	}
+
+	tenantCfg := auth.TenantFromContext(ctx)
+
+	resp := &pb.GetCapabilitiesResponse{
+		DefaultProvider: pb.Provider_PROVIDER_OPENAI,
+	}
+	if tenantCfg != nil {
+		if name, _, ok := tenantCfg.DefaultProvider(); ok {
+			resp.DefaultProvider = mapProviderToProto(name)
 		}
 	}
 
-	// Default fallback order
-	switch primary {
-	case provider.NameOpenAI:
-		return s.geminiProvider
-	case provider.NameGemini:
-		return s.openaiProvider
-	case provider.NameAnthropic:
-		return s.openaiProvider
-	default:
-		return s.geminiProvider
+	for _, p := range []provider.Provider{s.openaiProvider, s.geminiProvider, s.anthropicProvider} {
+		var model string
+		if tenantCfg != nil {
+			cfg, ok := tenantCfg.GetProvider(p.Name())
+			if !ok {
+				continue
+			}
+			model = cfg.Model
+		}
+
+		caps := provider.CapabilitiesOf(p, model)
+		resp.Providers = append(resp.Providers, &pb.ProviderCapabilities{
+			Provider:                 mapProviderToProto(p.Name()),
+			Model:                    caps.Model,
+			SupportsFileSearch:       caps.FileSearch,
+			SupportsWebSearch:        caps.WebSearch,
+			SupportsCodeExecution:    caps.CodeExecution,
+			SupportsStreaming:        caps.Streaming,
+			SupportsNativeContinuity: caps.NativeContinuity,
+			SupportsStructuredOutput: caps.StructuredOutput,
+			SupportsComputerUse:      caps.ComputerUse,
+			MaxContextTokens:         int32(caps.MaxContextTokens),
+		})
 	}
+
+	return resp, nil
 }
 
-// buildProviderConfig builds provider config from tenant config and request overrides.
+// checkRequestedModel enforces the model catalog when the request selects a model
+// via model_override or provider_configs[provider].model.
+// The tenant's own configured model is always permitted by its allow list.
+func (s *ChatService) checkRequestedModel(ctx context.Context, req *pb.GenerateReplyRequest, providerName string) error {
+	model := strings.TrimSpace(req.ModelOverride)
+	if isAutoModel(model) {
+		model = "" // The tiering models are the tenant's own
+	}
+	if model == "" {
+		model = strings.TrimSpace(req.ProviderConfigs[providerName].GetModel())
+	}
+	if model == "" {
+		return nil
+	}
+	return s.checkModel(ctx, providerName, model)
+}
+
+// checkModel enforces the model catalog on a model selected for providerName.
+func (s *ChatService) checkModel(ctx context.Context, providerName, model string) error {
+	var allowed []string
+	if tenantCfg := auth.TenantFromContext(ctx); tenantCfg != nil {
+		if pCfg, ok := tenantCfg.GetProvider(providerName); ok && len(pCfg.AllowedModels) > 0 {
+			allowed = append([]string{pCfg.Model}, pCfg.AllowedModels...)
+		}
+	}
+
+	if err := s.modelCatalog.Check(providerName, model, allowed); err != nil {
+		slog.WarnContext(ctx, "requested model rejected by catalog",
+			"provider", providerName,
+			"model", model,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// buildProviderConfig builds provider config from tenant config, the
+// request's generation preset and request overrides.
 func (s *ChatService) buildProviderConfig(ctx context.Context, req *pb.GenerateReplyRequest, providerName string) provider.ProviderConfig {
 	tenantCfg := auth.TenantFromContext(ctx)
 	requestCfg := req.ProviderConfigs[providerName]
-	return s.configBuilder.Build(providerName, tenantCfg, requestCfg)
+	var preset *tenant.GenerationPreset
+	if tenantCfg != nil && req.Preset != "" {
+		if p, ok := tenantCfg.Preset(req.Preset); ok {
+			preset = &p
+		}
+	}
+	cfg := s.configBuilder.BuildWithPreset(providerName, tenantCfg, preset, requestCfg)
+	cfg.Safety = effectiveSafety(tenantCfg, req.Safety)
+	return cfg
 }
 
 // selectProviderWithTenant selects provider using tenant config for validation.
-func (s *ChatService) selectProviderWithTenant(ctx context.Context, req *pb.GenerateReplyRequest) (provider.Provider, error) {
+// A request naming no provider follows its thread's pin, if any.
+func (s *ChatService) selectProviderWithTenant(ctx context.Context, req *pb.GenerateReplyRequest, pin *db.ThreadPin) (provider.Provider, error) {
 	tenantCfg := auth.TenantFromContext(ctx)
 
 	// Determine which provider to use
@@ -637,9 +1194,13 @@ func (s *ChatService) selectProviderWithTenant(ctx context.Context, req *pb.Gene
 	case pb.Provider_PROVIDER_ANTHROPIC:
 		providerName = "anthropic"
 	case pb.Provider_PROVIDER_UNSPECIFIED:
-		// Try to get default from tenant config
-		if tenantCfg != nil {
-			if name, _, ok := tenantCfg.DefaultProvider(); ok {
+		// Try the thread's pin, the preset's provider, then the default from tenant config
+		if followsPin(tenantCfg, req, pin) {
+			providerName = pin.Provider
+		} else if tenantCfg != nil {
+			if preset, ok := tenantCfg.Preset(req.Preset); ok && preset.Provider != "" {
+				providerName = preset.Provider
+			} else if name, _, ok := tenantCfg.DefaultProvider(); ok {
 				providerName = name
 			}
 		}
@@ -671,9 +1232,10 @@ func (s *ChatService) selectProviderWithTenant(ctx context.Context, req *pb.Gene
 }
 
 
-// retrieveRAGContext retrieves relevant document chunks for non-OpenAI providers.
+// retrieveRAGContext retrieves relevant document chunks for non-OpenAI providers,
+// packed into the token budget of pack.
 // Returns nil if RAG is disabled, not configured, or provider is OpenAI.
-func (s *ChatService) retrieveRAGContext(ctx context.Context, storeID, query string) ([]rag.RetrieveResult, error) {
+func (s *ChatService) retrieveRAGContext(ctx context.Context, storeID, query string, pack rag.PackOptions) ([]rag.RetrieveResult, error) {
 	if s.ragService == nil {
 		return nil, nil
 	}
@@ -681,12 +1243,21 @@ func (s *ChatService) retrieveRAGContext(ctx context.Context, storeID, query str
 		return nil, nil
 	}
 
-	return s.ragService.Retrieve(ctx, rag.RetrieveParams{
+	chunks, err := s.ragService.Retrieve(ctx, rag.RetrieveParams{
 		StoreID:  storeID,
 		TenantID: auth.TenantIDFromContext(ctx),
 		Query:    query,
 		TopK:     0, // Use service default (RetrievalTopK from ServiceOptions)
+		Viewer:   ragViewer(ctx),
 	})
+	if err != nil {
+		return nil, err
+	}
+	packed := rag.Pack(chunks, pack)
+	if len(packed) < len(chunks) {
+		accesslog.Annotate(ctx, "rag_chunks_dropped", len(chunks)-len(packed))
+	}
+	return packed, nil
 }
 
 // formatRAGContext formats retrieved chunks for injection into the system prompt.
@@ -734,6 +1305,7 @@ func (s *ChatService) buildResponse(result provider.GenerateResult, providerName
 		Model:              result.Model,
 		Provider:           mapProviderToProto(providerName),
 		RequiresToolOutput: result.RequiresToolOutput,
+		SafetyBlock:        convertSafetyBlock(result.SafetyBlock),
 	}
 
 	for _, c := range result.Citations {
@@ -747,6 +1319,9 @@ func (s *ChatService) buildResponse(result provider.GenerateResult, providerName
 	for _, ce := range result.CodeExecutions {
 		resp.CodeExecutions = append(resp.CodeExecutions, convertCodeExecution(ce))
 	}
+	for _, action := range result.ComputerActions {
+		resp.ComputerActions = append(resp.ComputerActions, convertComputerAction(action))
+	}
 
 	for _, img := range result.Images {
 		resp.Images = append(resp.Images, convertGeneratedImage(img))
@@ -763,24 +1338,12 @@ func (s *ChatService) buildResponse(result provider.GenerateResult, providerName
 		resp.OriginalError = originalError
 	}
 
-	// Add grounding cost tracking
+	// Cost estimate from the pricing table, with grounding broken out
+	cost := estimateCost(providerName, result.Model, result.Usage, result.GroundingQueries)
+	resp.EstimatedCostUsd = cost.Total()
 	if result.GroundingQueries > 0 {
 		resp.GroundingQueries = int32(result.GroundingQueries)
-
-		// For Gemini with structured usage data, use CalculateGeminiCost for accurate grounding cost
-		if providerName == "gemini" && result.Usage != nil {
-			metadata := pricing.GeminiUsageMetadata{
-				PromptTokenCount:        result.Usage.InputTokens,
-				CandidatesTokenCount:    result.Usage.OutputTokens,
-				CachedContentTokenCount: result.Usage.CachedTokens,
-				ToolUsePromptTokenCount: result.Usage.ToolUseTokens,
-				ThoughtsTokenCount:      result.Usage.ThinkingTokens,
-			}
-			costDetails := pricing.CalculateGeminiCost(result.Model, metadata, result.GroundingQueries)
-			resp.GroundingCostUsd = costDetails.GroundingCost
-		} else {
-			resp.GroundingCostUsd = pricing.CalculateGroundingCost(result.Model, result.GroundingQueries)
-		}
+		resp.GroundingCostUsd = cost.GroundingUSD
 	}
 
 	return resp
@@ -789,13 +1352,13 @@ func (s *ChatService) buildResponse(result provider.GenerateResult, providerName
 // generateImageFromCommand generates an image from a slash command prompt.
 func (s *ChatService) generateImageFromCommand(ctx context.Context, prompt string) []provider.GeneratedImage {
 	if s.imageGen == nil {
-		slog.Warn("image generation requested but imageGen client is nil")
+		slog.WarnContext(ctx, "image generation requested but imageGen client is nil")
 		return nil
 	}
 
 	tenantCfg := auth.TenantFromContext(ctx)
 	if tenantCfg == nil {
-		slog.Warn("image generation requested but no tenant config")
+		slog.WarnContext(ctx, "image generation requested but no tenant config")
 		return nil
 	}
 
@@ -809,13 +1372,14 @@ func (s *ChatService) generateImageFromCommand(ctx context.Context, prompt strin
 	}
 
 	if !imgCfg.IsEnabled() {
-		slog.Warn("image generation requested but not enabled for tenant")
+		slog.WarnContext(ctx, "image generation requested but not enabled for tenant")
 		return nil
 	}
 
 	imgReq := &imagegen.ImageRequest{
 		Prompt: prompt,
 		Config: imgCfg,
+		Proxy:  tenantCfg.Proxy,
 	}
 
 	// Get API keys from tenant provider config
@@ -826,14 +1390,14 @@ func (s *ChatService) generateImageFromCommand(ctx context.Context, prompt strin
 		imgReq.OpenAIAPIKey = openaiCfg.APIKey
 	}
 
-	slog.Info("slash command image generation",
+	slog.InfoContext(ctx, "slash command image generation",
 		"provider", imgCfg.Provider,
-		"prompt_preview", truncateString(prompt, 100),
+		logctx.Content("prompt_preview", truncateString(prompt, 100)),
 	)
 
 	img, err := s.imageGen.Generate(ctx, imgReq)
 	if err != nil {
-		slog.Error("slash command image generation failed", "error", err)
+		slog.ErrorContext(ctx, "slash command image generation failed", "error", err)
 		return nil
 	}
 
@@ -946,9 +1510,18 @@ func convertToolResults(results []*pb.ToolResult) []provider.ToolResult {
 	result := make([]provider.ToolResult, len(results))
 	for i, r := range results {
 		result[i] = provider.ToolResult{
-			ToolCallID: r.ToolCallId,
-			Output:     r.Output,
-			IsError:    r.IsError,
+			ToolCallID:         r.ToolCallId,
+			Output:             r.Output,
+			IsError:            r.IsError,
+			Screenshot:         r.Screenshot,
+			ScreenshotMIMEType: r.ScreenshotMimeType,
+		}
+		for _, check := range r.AcknowledgedSafetyChecks {
+			result[i].AcknowledgedSafetyChecks = append(result[i].AcknowledgedSafetyChecks, provider.ComputerSafetyCheck{
+				ID:      check.Id,
+				Code:    check.Code,
+				Message: check.Message,
+			})
 		}
 	}
 	return result
@@ -975,6 +1548,7 @@ func convertCodeExecution(ce provider.CodeExecutionResult) *pb.CodeExecutionResu
 			Name:     f.Name,
 			MimeType: f.MIMEType,
 			Content:  f.Content,
+			Url:      f.URL,
 		})
 	}
 	return result
@@ -1013,35 +1587,49 @@ func convertStructuredMetadata(m *provider.StructuredMetadata) *pb.StructuredMet
 			DatetimeMentioned: m.Scheduling.DatetimeMentioned,
 		}
 	}
+	for _, f := range m.Facts {
+		pm.Facts = append(pm.Facts, &pb.StructuredFact{
+			Category: f.Category,
+			Content:  f.Content,
+		})
+	}
 	return pm
 }
 
-// persistConversation saves the conversation turn to the database asynchronously.
+// conversationUserID returns the user that persisted threads belong to: the
+// authenticated client, else the request's client_id, else "anonymous".
+func conversationUserID(ctx context.Context, clientID string) string {
+	if client := auth.ClientFromContext(ctx); client != nil && client.ClientID != "" {
+		return client.ClientID
+	}
+	if clientID != "" {
+		return clientID
+	}
+	return "anonymous"
+}
+
+// persistConversation saves the conversation turn to the database asynchronously,
+// then sets the thread's pin unless pin is nil.
 // This runs in a goroutine to avoid blocking the response.
-func (s *ChatService) persistConversation(ctx context.Context, req *pb.GenerateReplyRequest, result provider.GenerateResult, providerName, model, renderedHTML string, processingTimeMs int) {
+func (s *ChatService) persistConversation(ctx context.Context, req *pb.GenerateReplyRequest, result provider.GenerateResult, providerName, model, renderedHTML string, processingTimeMs int, validationAttempts []db.ValidationAttempt, pin *db.ThreadPin) {
 	// Extract tenant and user info from context
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
-		slog.Warn("no tenant ID in context, skipping persistence")
+		slog.WarnContext(ctx, "no tenant ID in context, skipping persistence")
 		return
 	}
 
 	// Validate tenant ID is in our allowed list
 	if !db.ValidTenantIDs[tenantID] {
-		slog.Warn("invalid tenant ID, skipping persistence", "tenant_id", tenantID)
+		slog.WarnContext(ctx, "invalid tenant ID, skipping persistence", "tenant_id", tenantID)
 		return
 	}
 
-	userID := ""
+	userID, keyID := conversationUserID(ctx, req.ClientId), ""
 	if client := auth.ClientFromContext(ctx); client != nil {
-		userID = client.ClientID
-	}
-	if userID == "" {
-		userID = req.ClientId
-	}
-	if userID == "" {
-		userID = "anonymous"
+		keyID = client.KeyHash()
 	}
+	placement := turnPlacementFromContext(ctx)
 
 	// Generate or use existing thread ID
 	// Use request ID as thread ID for now (can be extended with proper thread management)
@@ -1049,6 +1637,7 @@ func (s *ChatService) persistConversation(ctx context.Context, req *pb.GenerateR
 	if err != nil {
 		threadID = uuid.New()
 	}
+	ctx = logctx.With(ctx, logctx.KeyThreadID, threadID.String())
 
 	// Calculate cost
 	inputTokens := 0
@@ -1058,69 +1647,44 @@ func (s *ChatService) persistConversation(ctx context.Context, req *pb.GenerateR
 		outputTokens = int(result.Usage.OutputTokens)
 	}
 
-	var costUSD float64
-	var groundingCostUSD float64
 	groundingQueries := result.GroundingQueries
-
-	// For Gemini provider with structured usage data, use CalculateGeminiCost for accurate pricing
-	// This handles cached tokens, thinking tokens, tool use tokens, and grounding queries
-	if providerName == "gemini" && result.Usage != nil {
-		metadata := pricing.GeminiUsageMetadata{
-			PromptTokenCount:        result.Usage.InputTokens,
-			CandidatesTokenCount:    result.Usage.OutputTokens,
-			CachedContentTokenCount: result.Usage.CachedTokens,
-			ToolUsePromptTokenCount: result.Usage.ToolUseTokens,
-			ThoughtsTokenCount:      result.Usage.ThinkingTokens,
-		}
-		costDetails := pricing.CalculateGeminiCost(model, metadata, groundingQueries)
-		costUSD = costDetails.TotalCost - costDetails.GroundingCost // Token costs only
-		groundingCostUSD = costDetails.GroundingCost
-
-		slog.Debug("gemini pricing from CalculateGeminiCost",
-			"total_cost", costDetails.TotalCost,
-			"standard_input_cost", costDetails.StandardInputCost,
-			"cached_input_cost", costDetails.CachedInputCost,
-			"output_cost", costDetails.OutputCost,
-			"thinking_cost", costDetails.ThinkingCost,
-			"grounding_cost", costDetails.GroundingCost,
-			"tier_applied", costDetails.TierApplied,
-			"cached_tokens", result.Usage.CachedTokens,
-			"thinking_tokens", result.Usage.ThinkingTokens,
-			"tool_use_tokens", result.Usage.ToolUseTokens,
-		)
-	} else {
-		// Non-Gemini provider: use basic calculation
-		costUSD = pricing.CalculateCost(model, inputTokens, outputTokens)
-		groundingCostUSD = pricing.CalculateGroundingCost(model, groundingQueries)
-	}
+	cost := estimateCost(providerName, model, result.Usage, groundingQueries)
+	costUSD := cost.TokenUSD
+	groundingCostUSD := cost.GroundingUSD
 
 	// Build debug info from captured JSON and rendered HTML (if available)
 	var debugInfo *db.DebugInfo
-	if len(result.RequestJSON) > 0 || len(result.ResponseJSON) > 0 || renderedHTML != "" {
+	traceID := tracing.IDFromContext(ctx)
+	if len(result.RequestJSON) > 0 || len(result.ResponseJSON) > 0 || renderedHTML != "" || len(validationAttempts) > 0 || keyID != "" || result.SystemPrompt != "" || result.UserPrompt != "" || traceID != "" {
 		debugInfo = &db.DebugInfo{
-			SystemPrompt:    req.Instructions,
-			RawRequestJSON:  string(result.RequestJSON),
-			RawResponseJSON: string(result.ResponseJSON),
-			RenderedHTML:    renderedHTML,
+			SystemPrompt:       req.Instructions,
+			RawRequestJSON:     string(result.RequestJSON),
+			RawResponseJSON:    string(result.ResponseJSON),
+			RenderedHTML:       renderedHTML,
+			FinalSystemPrompt:  result.SystemPrompt,
+			FinalUserPrompt:    result.UserPrompt,
+			ValidationAttempts: validationAttempts,
+			KeyID:              keyID,
+			TraceID:            traceID,
 		}
 	}
 
 	// Check if context is already cancelled to avoid unnecessary work
 	if ctx.Err() != nil {
-		slog.Debug("skipping persistence, context cancelled")
+		slog.DebugContext(ctx, "skipping persistence, context cancelled")
 		return
 	}
 
 	// Run persistence in background goroutine
 	go func() {
 		// Create a new context with timeout for the background operation
-		persistCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		persistCtx, cancel := context.WithTimeout(isolation.Detach(ctx), 10*time.Second)
 		defer cancel()
 
 		// Get tenant-specific repository
 		repo, err := s.dbClient.TenantRepository(tenantID)
 		if err != nil {
-			slog.Error("failed to get tenant repository",
+			slog.ErrorContext(ctx, "failed to get tenant repository",
 				"error", err,
 				"tenant_id", tenantID,
 			)
@@ -1164,29 +1728,32 @@ func (s *ChatService) persistConversation(ctx context.Context, req *pb.GenerateR
 			groundingCostUSD,
 			debugInfo,
 			dbCitations,
+			placement,
 		)
 		if err != nil {
-			slog.Error("failed to persist conversation",
+			slog.ErrorContext(ctx, "failed to persist conversation",
 				"error", err,
 				"thread_id", threadID,
 				"tenant_id", tenantID,
 			)
+			return
 		}
+		pinThread(persistCtx, repo, threadID, pin)
 	}()
 }
 
 // persistFailedRequest stores a failed request in the database for activity tracking.
-func (s *ChatService) persistFailedRequest(ctx context.Context, req *pb.GenerateReplyRequest, providerName, model string, errorMsg string, processingTimeMs int) {
+func (s *ChatService) persistFailedRequest(ctx context.Context, req *pb.GenerateReplyRequest, providerName, model string, errorMsg string, processingTimeMs int, validationAttempts []db.ValidationAttempt) {
 	// Extract tenant and user info from context
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
-		slog.Warn("no tenant ID in context, skipping failed request persistence")
+		slog.WarnContext(ctx, "no tenant ID in context, skipping failed request persistence")
 		return
 	}
 
 	// Validate tenant ID is in our allowed list
 	if !db.ValidTenantIDs[tenantID] {
-		slog.Warn("invalid tenant ID, skipping failed request persistence", "tenant_id", tenantID)
+		slog.WarnContext(ctx, "invalid tenant ID, skipping failed request persistence", "tenant_id", tenantID)
 		return
 	}
 
@@ -1194,15 +1761,9 @@ func (s *ChatService) persistFailedRequest(ctx context.Context, req *pb.Generate
 		return
 	}
 
-	userID := ""
+	userID, keyID := conversationUserID(ctx, req.ClientId), ""
 	if client := auth.ClientFromContext(ctx); client != nil {
-		userID = client.ClientID
-	}
-	if userID == "" {
-		userID = req.ClientId
-	}
-	if userID == "" {
-		userID = "anonymous"
+		keyID = client.KeyHash()
 	}
 
 	// Generate or use existing thread ID
@@ -1210,26 +1771,30 @@ func (s *ChatService) persistFailedRequest(ctx context.Context, req *pb.Generate
 	if err != nil {
 		threadID = uuid.New()
 	}
+	ctx = logctx.With(ctx, logctx.KeyThreadID, threadID.String())
 
 	// Build debug info with error
 	debugInfo := &db.DebugInfo{
-		SystemPrompt: req.Instructions,
+		SystemPrompt:       req.Instructions,
+		ValidationAttempts: validationAttempts,
+		KeyID:              keyID,
+		TraceID:            tracing.IDFromContext(ctx),
 	}
 
 	// Check if context is already cancelled to avoid unnecessary work
 	if ctx.Err() != nil {
-		slog.Debug("skipping persistence, context cancelled")
+		slog.DebugContext(ctx, "skipping persistence, context cancelled")
 		return
 	}
 
 	// Run persistence in background goroutine
 	go func() {
-		persistCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		persistCtx, cancel := context.WithTimeout(isolation.Detach(ctx), 10*time.Second)
 		defer cancel()
 
 		repo, err := s.dbClient.TenantRepository(tenantID)
 		if err != nil {
-			slog.Error("failed to get tenant repository for failed request",
+			slog.ErrorContext(ctx, "failed to get tenant repository for failed request",
 				"error", err,
 				"tenant_id", tenantID,
 			)
@@ -1242,7 +1807,7 @@ func (s *ChatService) persistFailedRequest(ctx context.Context, req *pb.Generate
 			threadID,
 			userID,
 			req.UserInput,
-			"[FAILED] "+errorMsg, // Mark content as failed
+			failedReplyPrefix+errorMsg, // Mark content as failed
 			providerName,
 			model,
 			"",  // No response ID for failed requests
@@ -1254,15 +1819,16 @@ func (s *ChatService) persistFailedRequest(ctx context.Context, req *pb.Generate
 			0,   // No grounding cost
 			debugInfo,
 			nil, // No citations
+			nil, // Failed turns continue the current branch
 		)
 		if err != nil {
-			slog.Error("failed to persist failed request",
+			slog.ErrorContext(ctx, "failed to persist failed request",
 				"error", err,
 				"thread_id", threadID,
 				"tenant_id", tenantID,
 			)
 		} else {
-			slog.Debug("persisted failed request",
+			slog.DebugContext(ctx, "persisted failed request",
 				"thread_id", threadID,
 				"tenant_id", tenantID,
 				"error", errorMsg,
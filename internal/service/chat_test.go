@@ -7,12 +7,15 @@ import (
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
 	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/modelcatalog"
 	"github.com/ai8future/airborne/internal/provider"
 	"github.com/ai8future/airborne/internal/rag"
 	"github.com/ai8future/airborne/internal/rag/testutil"
 	"github.com/ai8future/airborne/internal/rag/vectorstore"
 	"github.com/ai8future/airborne/internal/tenant"
 	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // mockProvider implements provider.Provider for testing.
@@ -480,6 +483,82 @@ func TestPrepareRequest_CustomBaseURLRequiresAdmin(t *testing.T) {
 	}
 }
 
+func TestPrepareRequest_ModelNotInAllowList(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tenantCfg := createTestTenantConfig("openai")
+	p := tenantCfg.Providers["openai"]
+	p.AllowedModels = []string{"gpt-4o-mini*"}
+	tenantCfg.Providers["openai"] = p
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		ModelOverride:     "gpt-4-32k",
+	}
+
+	_, err := svc.prepareRequest(ctx, req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "gpt-4o-mini*") {
+		t.Errorf("expected error to list permitted models, got: %v", err)
+	}
+
+	// Allow-listed and tenant-configured models pass
+	for _, model := range []string{"gpt-4o-mini-2024-07-18", "test-model-openai"} {
+		req.ModelOverride = model
+		if _, err := svc.prepareRequest(ctx, req); err != nil {
+			t.Errorf("expected model %q to be allowed, got: %v", model, err)
+		}
+	}
+}
+
+func TestPrepareRequest_Preset(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tenantCfg := createTestTenantConfig("openai", "anthropic")
+	temp := 0.1
+	tenantCfg.Presets = map[string]tenant.GenerationPreset{
+		"precise": {Provider: "anthropic", Models: map[string]string{"anthropic": "claude-precise"}, Temperature: &temp},
+	}
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+
+	prepared, err := svc.prepareRequest(ctx, &pb.GenerateReplyRequest{UserInput: "Hello", Preset: "precise"})
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+	if prepared.provider.Name() != "anthropic" {
+		t.Errorf("provider = %s, want the preset's anthropic", prepared.provider.Name())
+	}
+	if prepared.providerCfg.Model != "claude-precise" || prepared.providerCfg.Temperature == nil || *prepared.providerCfg.Temperature != 0.1 {
+		t.Errorf("config = %+v, want the preset's model and temperature", prepared.providerCfg)
+	}
+
+	_, err = svc.prepareRequest(ctx, &pb.GenerateReplyRequest{UserInput: "Hello", Preset: "creative"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an unknown preset, got: %v", err)
+	}
+}
+
+func TestPrepareRequest_ModelDenied(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.modelCatalog = modelcatalog.New([]string{"o1-pro*"})
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		ProviderConfigs: map[string]*pb.ProviderConfig{
+			"openai": {Model: "o1-pro-2025-03-19"},
+		},
+	}
+
+	_, err := svc.prepareRequest(ctx, req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got: %v", err)
+	}
+}
+
 func TestPrepareRequest_ProviderSelectionOpenAI(t *testing.T) {
 	mockOpenAI := newMockProvider("openai")
 	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
@@ -988,7 +1067,7 @@ func TestSelectProviderWithTenant_ReturnsOpenAI(t *testing.T) {
 		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
 	}
 
-	p, err := svc.selectProviderWithTenant(ctx, req)
+	p, err := svc.selectProviderWithTenant(ctx, req, nil)
 	if err != nil {
 		t.Fatalf("selectProviderWithTenant failed: %v", err)
 	}
@@ -1007,7 +1086,7 @@ func TestSelectProviderWithTenant_ReturnsGemini(t *testing.T) {
 		PreferredProvider: pb.Provider_PROVIDER_GEMINI,
 	}
 
-	p, err := svc.selectProviderWithTenant(ctx, req)
+	p, err := svc.selectProviderWithTenant(ctx, req, nil)
 	if err != nil {
 		t.Fatalf("selectProviderWithTenant failed: %v", err)
 	}
@@ -1026,7 +1105,7 @@ func TestSelectProviderWithTenant_ReturnsAnthropic(t *testing.T) {
 		PreferredProvider: pb.Provider_PROVIDER_ANTHROPIC,
 	}
 
-	p, err := svc.selectProviderWithTenant(ctx, req)
+	p, err := svc.selectProviderWithTenant(ctx, req, nil)
 	if err != nil {
 		t.Fatalf("selectProviderWithTenant failed: %v", err)
 	}
@@ -1048,7 +1127,7 @@ func TestSelectProviderWithTenant_DefaultsToOpenAI(t *testing.T) {
 		PreferredProvider: pb.Provider_PROVIDER_UNSPECIFIED,
 	}
 
-	p, err := svc.selectProviderWithTenant(ctx, req)
+	p, err := svc.selectProviderWithTenant(ctx, req, nil)
 	if err != nil {
 		t.Fatalf("selectProviderWithTenant failed: %v", err)
 	}
@@ -1068,7 +1147,7 @@ func TestSelectProviderWithTenant_UsesFailoverOrder(t *testing.T) {
 		PreferredProvider: pb.Provider_PROVIDER_UNSPECIFIED,
 	}
 
-	p, err := svc.selectProviderWithTenant(ctx, req)
+	p, err := svc.selectProviderWithTenant(ctx, req, nil)
 	if err != nil {
 		t.Fatalf("selectProviderWithTenant failed: %v", err)
 	}
@@ -1086,7 +1165,7 @@ func TestSelectProviderWithTenant_ProviderNotEnabled(t *testing.T) {
 		PreferredProvider: pb.Provider_PROVIDER_ANTHROPIC, // Not enabled
 	}
 
-	_, err := svc.selectProviderWithTenant(ctx, req)
+	_, err := svc.selectProviderWithTenant(ctx, req, nil)
 	if err == nil {
 		t.Fatal("expected error for disabled provider")
 	}
@@ -1101,7 +1180,7 @@ func TestGetFallbackProvider_SpecifiedFallback(t *testing.T) {
 	mockGemini := newMockProvider("gemini")
 	svc := createChatServiceWithMocks(newMockProvider("openai"), mockGemini, newMockProvider("anthropic"), nil)
 
-	fallback := svc.getFallbackProvider("openai", pb.Provider_PROVIDER_GEMINI)
+	fallback := svc.getFallbackProvider(context.Background(), "openai", pb.Provider_PROVIDER_GEMINI)
 	if fallback == nil {
 		t.Fatal("expected fallback provider")
 	}
@@ -1114,7 +1193,7 @@ func TestGetFallbackProvider_DefaultFallbackFromOpenAI(t *testing.T) {
 	mockGemini := newMockProvider("gemini")
 	svc := createChatServiceWithMocks(newMockProvider("openai"), mockGemini, newMockProvider("anthropic"), nil)
 
-	fallback := svc.getFallbackProvider("openai", pb.Provider_PROVIDER_UNSPECIFIED)
+	fallback := svc.getFallbackProvider(context.Background(), "openai", pb.Provider_PROVIDER_UNSPECIFIED)
 	if fallback == nil {
 		t.Fatal("expected fallback provider")
 	}
@@ -1127,7 +1206,7 @@ func TestGetFallbackProvider_DefaultFallbackFromGemini(t *testing.T) {
 	mockOpenAI := newMockProvider("openai")
 	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
 
-	fallback := svc.getFallbackProvider("gemini", pb.Provider_PROVIDER_UNSPECIFIED)
+	fallback := svc.getFallbackProvider(context.Background(), "gemini", pb.Provider_PROVIDER_UNSPECIFIED)
 	if fallback == nil {
 		t.Fatal("expected fallback provider")
 	}
@@ -1140,7 +1219,7 @@ func TestGetFallbackProvider_DefaultFallbackFromAnthropic(t *testing.T) {
 	mockOpenAI := newMockProvider("openai")
 	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
 
-	fallback := svc.getFallbackProvider("anthropic", pb.Provider_PROVIDER_UNSPECIFIED)
+	fallback := svc.getFallbackProvider(context.Background(), "anthropic", pb.Provider_PROVIDER_UNSPECIFIED)
 	if fallback == nil {
 		t.Fatal("expected fallback provider")
 	}
@@ -1207,3 +1286,47 @@ func TestMapProviderToProto(t *testing.T) {
 		}
 	}
 }
+
+// ==================== GetCapabilities Tests ====================
+
+func TestGetCapabilities_TenantProvidersOnly(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tenantCfg := createTestTenantConfig("gemini", "anthropic")
+	ctx := ctxWithChatPermissionAndTenant("client-1", tenantCfg)
+
+	resp, err := svc.GetCapabilities(ctx, &pb.GetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Providers) != 2 {
+		t.Fatalf("expected 2 enabled providers, got %d", len(resp.Providers))
+	}
+	if resp.Providers[0].Provider != pb.Provider_PROVIDER_GEMINI {
+		t.Errorf("expected gemini first, got %v", resp.Providers[0].Provider)
+	}
+	if resp.Providers[0].Model != "test-model-gemini" {
+		t.Errorf("expected tenant model, got %q", resp.Providers[0].Model)
+	}
+	if !resp.Providers[0].SupportsStreaming || !resp.Providers[0].SupportsFileSearch {
+		t.Error("expected capabilities from provider")
+	}
+	if resp.DefaultProvider != pb.Provider_PROVIDER_ANTHROPIC {
+		t.Errorf("expected first sorted enabled provider as default, got %v", resp.DefaultProvider)
+	}
+}
+
+func TestGetCapabilities_NoTenantListsAll(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ctx := ctxWithChatPermissionAndTenant("client-1", nil)
+
+	resp, err := svc.GetCapabilities(ctx, &pb.GetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Providers) != 3 {
+		t.Fatalf("expected 3 providers, got %d", len(resp.Providers))
+	}
+	if resp.DefaultProvider != pb.Provider_PROVIDER_OPENAI {
+		t.Errorf("expected openai default, got %v", resp.DefaultProvider)
+	}
+}
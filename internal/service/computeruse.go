@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxComputerDisplay bounds the display size a request may declare.
+const maxComputerDisplay = 8192
+
+// checkComputerUse validates a request's computer_use settings for p and
+// converts them. Nil when the request does not enable computer use.
+func checkComputerUse(ctx context.Context, req *pb.GenerateReplyRequest, p provider.Provider) (*provider.ComputerUse, error) {
+	cu := req.GetComputerUse()
+	if cu == nil {
+		return nil, nil
+	}
+	if err := auth.RequirePermission(ctx, auth.PermissionComputerUse); err != nil {
+		return nil, status.Error(status.Code(err), "computer_use requires the computer_use permission")
+	}
+	if supporter, ok := p.(provider.ComputerUseSupporter); !ok || !supporter.SupportsComputerUse() {
+		return nil, status.Errorf(codes.InvalidArgument, "computer_use is not supported by %s", p.Name())
+	}
+	if cu.DisplayWidth <= 0 || cu.DisplayHeight <= 0 || cu.DisplayWidth > maxComputerDisplay || cu.DisplayHeight > maxComputerDisplay {
+		return nil, status.Errorf(codes.InvalidArgument, "computer_use display must be between 1 and %d pixels per side", maxComputerDisplay)
+	}
+	switch cu.Environment {
+	case "", provider.ComputerEnvironmentBrowser, provider.ComputerEnvironmentMac,
+		provider.ComputerEnvironmentWindows, provider.ComputerEnvironmentUbuntu:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown computer_use environment %q", cu.Environment)
+	}
+	for _, tool := range req.Tools {
+		if tool.GetName() == provider.ComputerToolName {
+			return nil, status.Errorf(codes.InvalidArgument, "tool name %q is reserved when computer_use is enabled", provider.ComputerToolName)
+		}
+	}
+
+	return &provider.ComputerUse{
+		DisplayWidth:  int(cu.DisplayWidth),
+		DisplayHeight: int(cu.DisplayHeight),
+		Environment:   cu.Environment,
+	}, nil
+}
+
+// pinnedToProvider reports whether req must stay on its selected provider:
+// tool results can only be answered by the provider that requested them, and
+// computer use, file attachments and images are not available everywhere, so
+// these requests neither fail over nor hedge.
+func pinnedToProvider(req *pb.GenerateReplyRequest) bool {
+	return continuesToolTurn(req) || req.GetComputerUse() != nil || hasFileAttachments(req) || len(req.Images) > 0
+}
+
+func convertComputerAction(action provider.ComputerAction) *pb.ComputerAction {
+	out := &pb.ComputerAction{
+		Id:     action.ID,
+		Action: action.Action,
+		Input:  action.Input,
+	}
+	for _, check := range action.PendingSafetyChecks {
+		out.PendingSafetyChecks = append(out.PendingSafetyChecks, &pb.ComputerSafetyCheck{
+			Id:      check.ID,
+			Code:    check.Code,
+			Message: check.Message,
+		})
+	}
+	return out
+}
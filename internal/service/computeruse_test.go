@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// computerProvider is a mock provider with a computer-use tool.
+type computerProvider struct {
+	*mockProvider
+}
+
+func (p *computerProvider) SupportsComputerUse() bool { return true }
+
+func ctxWithComputerUse() context.Context {
+	ctx := context.WithValue(context.Background(), auth.ClientContextKey, &auth.ClientKey{
+		ClientID:    "agent",
+		Permissions: []auth.Permission{auth.PermissionChat, auth.PermissionChatStream, auth.PermissionComputerUse},
+	})
+	return context.WithValue(ctx, auth.TenantContextKey, createTestTenantConfig("openai", "gemini"))
+}
+
+func TestCheckComputerUse(t *testing.T) {
+	openai := &computerProvider{newMockProvider("openai")}
+	display := &pb.ComputerUse{DisplayWidth: 1280, DisplayHeight: 800}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *pb.GenerateReplyRequest
+		p    provider.Provider
+		code codes.Code
+	}{
+		{"disabled", ctxWithChatPermissionAndTenant("c", nil), &pb.GenerateReplyRequest{}, newMockProvider("gemini"), codes.OK},
+		{"allowed", ctxWithComputerUse(), &pb.GenerateReplyRequest{ComputerUse: display}, openai, codes.OK},
+		{"missing permission", ctxWithChatPermissionAndTenant("c", nil), &pb.GenerateReplyRequest{ComputerUse: display}, openai, codes.PermissionDenied},
+		{"unsupported provider", ctxWithComputerUse(), &pb.GenerateReplyRequest{ComputerUse: display}, newMockProvider("gemini"), codes.InvalidArgument},
+		{"no display", ctxWithComputerUse(), &pb.GenerateReplyRequest{ComputerUse: &pb.ComputerUse{}}, openai, codes.InvalidArgument},
+		{"unknown environment", ctxWithComputerUse(), &pb.GenerateReplyRequest{ComputerUse: &pb.ComputerUse{DisplayWidth: 1, DisplayHeight: 1, Environment: "amiga"}}, openai, codes.InvalidArgument},
+		{"reserved tool name", ctxWithComputerUse(), &pb.GenerateReplyRequest{ComputerUse: display, Tools: []*pb.Tool{{Name: "computer"}}}, openai, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := checkComputerUse(tt.ctx, tt.req, tt.p)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestGenerateReply_ComputerActions(t *testing.T) {
+	openai := &computerProvider{newMockProvider("openai")}
+	openai.generateResult = provider.GenerateResult{
+		ResponseID:         "resp-1",
+		Model:              "computer-use-preview",
+		RequiresToolOutput: true,
+		ComputerActions: []provider.ComputerAction{{
+			ID:                  "call_1",
+			Action:              "click",
+			Input:               `{"type":"click","x":10,"y":20}`,
+			PendingSafetyChecks: []provider.ComputerSafetyCheck{{ID: "sc_1", Code: "malicious_instructions"}},
+		}},
+	}
+	svc := createChatServiceWithMocks(nil, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.openaiProvider = openai
+
+	resp, err := svc.GenerateReply(ctxWithComputerUse(), &pb.GenerateReplyRequest{
+		UserInput:         "Open the pricing page",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFailover:    true,
+		ComputerUse:       &pb.ComputerUse{DisplayWidth: 1280, DisplayHeight: 800},
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if params := openai.generateCalls[0]; params.ComputerUse == nil || params.ComputerUse.DisplayWidth != 1280 {
+		t.Errorf("expected computer use to reach the provider, got %+v", params.ComputerUse)
+	}
+	if !resp.RequiresToolOutput || len(resp.ComputerActions) != 1 {
+		t.Fatalf("expected one computer action, got %+v", resp.ComputerActions)
+	}
+	action := resp.ComputerActions[0]
+	if action.Id != "call_1" || action.Action != "click" || len(action.PendingSafetyChecks) != 1 {
+		t.Errorf("unexpected computer action: %+v", action)
+	}
+
+	req := &pb.GenerateReplyRequest{EnableHedging: true, ComputerUse: &pb.ComputerUse{}}
+	if hedge := svc.hedgeTarget(ctxWithComputerUse(), req, &preparedRequest{provider: openai}); hedge != nil {
+		t.Errorf("expected computer use requests not to hedge, got %s", hedge.Name())
+	}
+}
+
+func TestConvertToolResults_Screenshot(t *testing.T) {
+	results := convertToolResults([]*pb.ToolResult{{
+		ToolCallId:               "call_1",
+		Screenshot:               []byte{0x89, 'P', 'N', 'G'},
+		ScreenshotMimeType:       "image/png",
+		AcknowledgedSafetyChecks: []*pb.ComputerSafetyCheck{{Id: "sc_1", Code: "malicious_instructions"}},
+	}})
+	if len(results[0].Screenshot) != 4 || results[0].ScreenshotMIMEType != "image/png" {
+		t.Errorf("screenshot not converted: %+v", results[0])
+	}
+	if len(results[0].AcknowledgedSafetyChecks) != 1 || results[0].AcknowledgedSafetyChecks[0].ID != "sc_1" {
+		t.Errorf("safety checks not converted: %+v", results[0].AcknowledgedSafetyChecks)
+	}
+}
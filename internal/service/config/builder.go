@@ -1,6 +1,9 @@
 package config
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/ai8future/airborne/internal/provider"
 	"github.com/ai8future/airborne/internal/tenant"
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
@@ -20,11 +23,23 @@ func (b *Builder) Build(
 	providerName string,
 	tenantCfg *tenant.TenantConfig,
 	requestCfg *pb.ProviderConfig,
+) provider.ProviderConfig {
+	return b.BuildWithPreset(providerName, tenantCfg, nil, requestCfg)
+}
+
+// BuildWithPreset is Build with a generation preset applied between the
+// tenant defaults and the request overrides. preset may be nil.
+func (b *Builder) BuildWithPreset(
+	providerName string,
+	tenantCfg *tenant.TenantConfig,
+	preset *tenant.GenerationPreset,
+	requestCfg *pb.ProviderConfig,
 ) provider.ProviderConfig {
 	cfg := provider.ProviderConfig{}
 
 	// Apply tenant defaults
 	if tenantCfg != nil {
+		cfg.Proxy = tenantCfg.Proxy
 		if pCfg, ok := tenantCfg.GetProvider(providerName); ok {
 			cfg.APIKey = pCfg.APIKey
 			cfg.Model = pCfg.Model
@@ -44,6 +59,11 @@ func (b *Builder) Build(
 		}
 	}
 
+	// Apply the preset over the tenant defaults
+	if preset != nil {
+		applyPreset(&cfg, providerName, preset)
+	}
+
 	// Apply request overrides (except API key for security)
 	if requestCfg != nil {
 		// SECURITY: API keys must come from server-side tenant config, not requests
@@ -86,3 +106,37 @@ func (b *Builder) Build(
 
 	return cfg
 }
+
+// applyPreset sets the preset's parameters on cfg. The reasoning effort is
+// translated to each provider's own option.
+func applyPreset(cfg *provider.ProviderConfig, providerName string, preset *tenant.GenerationPreset) {
+	if model := preset.Models[providerName]; model != "" {
+		cfg.Model = model
+	}
+	if preset.Temperature != nil {
+		temperature := *preset.Temperature
+		cfg.Temperature = &temperature
+	}
+	if preset.MaxOutputTokens != nil {
+		maxTokens := *preset.MaxOutputTokens
+		cfg.MaxOutputTokens = &maxTokens
+	}
+	if preset.ReasoningEffort == "" {
+		return
+	}
+	if cfg.ExtraOptions == nil {
+		cfg.ExtraOptions = make(map[string]string)
+	}
+	switch providerName {
+	case provider.NameOpenAI:
+		cfg.ExtraOptions["reasoning_effort"] = preset.ReasoningEffort
+	case provider.NameGemini:
+		level := strings.ToUpper(preset.ReasoningEffort)
+		if preset.ReasoningEffort == tenant.ReasoningEffortNone {
+			level = "MINIMAL"
+		}
+		cfg.ExtraOptions["thinking_level"] = level
+	case provider.NameAnthropic:
+		cfg.ExtraOptions["thinking_enabled"] = strconv.FormatBool(preset.ReasoningEffort != tenant.ReasoningEffortNone)
+	}
+}
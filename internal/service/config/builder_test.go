@@ -3,6 +3,7 @@ package config
 import (
 	"testing"
 
+	"github.com/ai8future/airborne/internal/egress"
 	"github.com/ai8future/airborne/internal/tenant"
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
 )
@@ -35,6 +36,22 @@ func TestBuild_TenantDefaults(t *testing.T) {
 	}
 }
 
+func TestBuild_TenantProxy(t *testing.T) {
+	proxy := &egress.ProxyConfig{URL: "http://proxy.corp.internal:3128"}
+	tenantCfg := &tenant.TenantConfig{
+		Providers: map[string]tenant.ProviderConfig{
+			"gemini": {Enabled: true, APIKey: "tenant-key"},
+		},
+		Proxy: proxy,
+	}
+
+	cfg := NewBuilder().Build("gemini", tenantCfg, nil)
+
+	if cfg.Proxy != proxy {
+		t.Errorf("Proxy = %+v, want the tenant proxy", cfg.Proxy)
+	}
+}
+
 func TestBuild_RequestOverride(t *testing.T) {
 	tenantCfg := &tenant.TenantConfig{
 		Providers: map[string]tenant.ProviderConfig{
@@ -131,6 +148,43 @@ func TestBuild_NoTenantConfig(t *testing.T) {
 	}
 }
 
+func TestBuildWithPreset(t *testing.T) {
+	tenantCfg := &tenant.TenantConfig{
+		Providers: map[string]tenant.ProviderConfig{
+			"openai": {Enabled: true, APIKey: "tenant-key", Model: "gpt-4o", Temperature: floatPtr(0.7)},
+			"gemini": {Enabled: true, APIKey: "tenant-key", Model: "gemini-pro"},
+		},
+	}
+	maxTokens := 500
+	preset := &tenant.GenerationPreset{
+		Models:          map[string]string{"openai": "gpt-4o-mini"},
+		Temperature:     floatPtr(0.2),
+		MaxOutputTokens: &maxTokens,
+		ReasoningEffort: tenant.ReasoningEffortLow,
+	}
+	builder := NewBuilder()
+
+	cfg := builder.BuildWithPreset("openai", tenantCfg, preset, nil)
+	if cfg.Model != "gpt-4o-mini" || *cfg.Temperature != 0.2 || *cfg.MaxOutputTokens != 500 {
+		t.Errorf("config = %+v, want the preset's model, temperature and max tokens", cfg)
+	}
+	if cfg.ExtraOptions["reasoning_effort"] != "low" {
+		t.Errorf("reasoning_effort = %q, want low", cfg.ExtraOptions["reasoning_effort"])
+	}
+
+	// Providers without a preset model keep their own
+	cfg = builder.BuildWithPreset("gemini", tenantCfg, preset, nil)
+	if cfg.Model != "gemini-pro" || cfg.ExtraOptions["thinking_level"] != "LOW" {
+		t.Errorf("config = %+v, want the tenant model and LOW thinking", cfg)
+	}
+
+	// Request overrides win over the preset
+	cfg = builder.BuildWithPreset("openai", tenantCfg, preset, &pb.ProviderConfig{Model: "gpt-4.1", Temperature: floatPtr(0.9)})
+	if cfg.Model != "gpt-4.1" || *cfg.Temperature != 0.9 {
+		t.Errorf("config = %+v, want the request's model and temperature", cfg)
+	}
+}
+
 func floatPtr(f float64) *float64 {
 	return &f
 }
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/ai8future/airborne/internal/pricing"
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+// costEstimate is the priced cost of one generation.
+type costEstimate struct {
+	TokenUSD     float64 // Input, output, cached and thinking tokens
+	GroundingUSD float64 // Web search / grounding queries
+}
+
+// Total returns the combined token and grounding cost.
+func (c costEstimate) Total() float64 {
+	return c.TokenUSD + c.GroundingUSD
+}
+
+// estimateCost prices a generation from the pricing table. Gemini usage is
+// priced in detail (cached input at the reduced rate, thinking tokens at the
+// output rate, tool-use prompt tokens); other providers report reasoning
+// tokens inside output tokens and are priced on input/output. Unknown models
+// cost 0.
+func estimateCost(providerName, model string, usage *provider.Usage, groundingQueries int) costEstimate {
+	if providerName == "gemini" && usage != nil {
+		metadata := pricing.GeminiUsageMetadata{
+			PromptTokenCount:        usage.InputTokens,
+			CandidatesTokenCount:    usage.OutputTokens,
+			CachedContentTokenCount: usage.CachedTokens,
+			ToolUsePromptTokenCount: usage.ToolUseTokens,
+			ThoughtsTokenCount:      usage.ThinkingTokens,
+		}
+		costDetails := pricing.CalculateGeminiCost(model, metadata, groundingQueries)
+
+		slog.Debug("gemini pricing from CalculateGeminiCost",
+			"total_cost", costDetails.TotalCost,
+			"standard_input_cost", costDetails.StandardInputCost,
+			"cached_input_cost", costDetails.CachedInputCost,
+			"output_cost", costDetails.OutputCost,
+			"thinking_cost", costDetails.ThinkingCost,
+			"grounding_cost", costDetails.GroundingCost,
+			"tier_applied", costDetails.TierApplied,
+			"cached_tokens", usage.CachedTokens,
+			"thinking_tokens", usage.ThinkingTokens,
+			"tool_use_tokens", usage.ToolUseTokens,
+		)
+
+		return costEstimate{
+			TokenUSD:     costDetails.TotalCost - costDetails.GroundingCost,
+			GroundingUSD: costDetails.GroundingCost,
+		}
+	}
+
+	var inputTokens, outputTokens int
+	if usage != nil {
+		inputTokens = int(usage.InputTokens)
+		outputTokens = int(usage.OutputTokens)
+	}
+	return costEstimate{
+		TokenUSD:     pricing.CalculateCost(model, inputTokens, outputTokens),
+		GroundingUSD: pricing.CalculateGroundingCost(model, groundingQueries),
+	}
+}
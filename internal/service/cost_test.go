@@ -0,0 +1,63 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai8future/airborne/internal/pricing"
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+func TestEstimateCost_UnknownModel(t *testing.T) {
+	usage := &provider.Usage{InputTokens: 1000, OutputTokens: 500, TotalTokens: 1500}
+
+	for _, providerName := range []string{"openai", "gemini"} {
+		if got := estimateCost(providerName, "no-such-model-xyz", usage, 0).Total(); got != 0 {
+			t.Errorf("%s: estimateCost for unknown model = %v, want 0", providerName, got)
+		}
+	}
+}
+
+func TestEstimateCost_NilUsage(t *testing.T) {
+	if got := estimateCost("gemini", "gemini-2.5-flash", nil, 0).TokenUSD; got != 0 {
+		t.Errorf("token cost with nil usage = %v, want 0", got)
+	}
+}
+
+func TestBuildResponse_EstimatedCost(t *testing.T) {
+	tests := []struct {
+		provider      string
+		model         string
+		queries       int
+		wantTotal     float64
+		wantGrounding float64
+	}{
+		// $2.50 input and $10 output per million tokens
+		{provider: "openai", model: "gpt-4o", wantTotal: 0.0045},
+		// $0.30 input and $2.50 output per million tokens, $35 per 1,000 grounded prompts
+		{provider: "gemini", model: "gemini-2.5-flash", queries: 1, wantTotal: 0.0358, wantGrounding: 0.035},
+	}
+
+	s := &ChatService{}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if _, ok := pricing.GetPricing(tt.model); !ok {
+				t.Skipf("%s not in pricing data, skipping", tt.model)
+			}
+			result := provider.GenerateResult{
+				Text:             "hi",
+				Model:            tt.model,
+				Usage:            &provider.Usage{InputTokens: 1000, OutputTokens: 200},
+				GroundingQueries: tt.queries,
+			}
+
+			resp := s.buildResponse(result, tt.provider, false, "", "", "")
+			if math.Abs(resp.EstimatedCostUsd-tt.wantTotal) > 1e-9 {
+				t.Errorf("EstimatedCostUsd = %v, want %v", resp.EstimatedCostUsd, tt.wantTotal)
+			}
+			if math.Abs(resp.GroundingCostUsd-tt.wantGrounding) > 1e-9 || resp.GroundingQueries != int32(tt.queries) {
+				t.Errorf("grounding = %d queries / $%v, want %d / $%v", resp.GroundingQueries, resp.GroundingCostUsd, tt.queries, tt.wantGrounding)
+			}
+		})
+	}
+}
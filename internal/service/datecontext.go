@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// dateContextLocation returns the time zone the current date is given in:
+// the user's profile time zone, else the tenant's, else UTC.
+func dateContextLocation(ctx context.Context, tenantCfg *tenant.TenantConfig, profile *db.UserProfile) *time.Location {
+	for _, name := range []string{profileTimezone(profile), tenantCfg.DateContext.Timezone} {
+		if name == "" {
+			continue
+		}
+		loc, err := tenant.LoadTimezone(name)
+		if err == nil {
+			return loc
+		}
+		slog.WarnContext(ctx, "unknown time zone, ignoring it", "timezone", name, "error", err)
+	}
+	return time.UTC
+}
+
+func profileTimezone(p *db.UserProfile) string {
+	if p == nil {
+		return ""
+	}
+	return p.Timezone
+}
+
+// formatDateContext formats the current date and time in loc for injection
+// into the system prompt.
+func formatDateContext(now time.Time, loc *time.Location) string {
+	t := now.In(loc)
+	return fmt.Sprintf("\n\n<current_date>\nThe current date and time for the user is %s (%s, UTC%s).\n</current_date>\n\nUse it to resolve relative dates such as \"today\", \"tomorrow\" or \"next Friday\".\n",
+		t.Format("Monday, 2 January 2006, 15:04"), loc, t.Format("-07:00"))
+}
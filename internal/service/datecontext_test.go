@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+func TestFormatDateContext(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// 02:30 UTC is still the previous evening in New York
+	got := formatDateContext(time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC), loc)
+	if !strings.Contains(got, "Friday, 16 October 2026, 22:30 (America/New_York, UTC-04:00)") {
+		t.Errorf("got %q, want the local date and offset", got)
+	}
+	if !strings.Contains(got, "<current_date>") {
+		t.Errorf("got %q, want current_date tags", got)
+	}
+}
+
+func TestDateContextLocation(t *testing.T) {
+	cfg := &tenant.TenantConfig{DateContext: tenant.DateContextConfig{Inject: true, Timezone: "Europe/Berlin"}}
+	tests := []struct {
+		name    string
+		profile *db.UserProfile
+		want    string
+	}{
+		{"no profile", nil, "Europe/Berlin"},
+		{"profile without time zone", &db.UserProfile{Tone: "formal"}, "Europe/Berlin"},
+		{"profile time zone", &db.UserProfile{Timezone: "Asia/Tokyo"}, "Asia/Tokyo"},
+		{"unknown profile time zone", &db.UserProfile{Timezone: "Mars/Olympus"}, "Europe/Berlin"},
+	}
+	for _, tt := range tests {
+		if got := dateContextLocation(context.Background(), cfg, tt.profile).String(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if got := dateContextLocation(context.Background(), &tenant.TenantConfig{}, nil); got != time.UTC {
+		t.Errorf("got %s, want UTC by default", got)
+	}
+}
+
+func TestPrepareRequest_DateContext(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	req := &pb.GenerateReplyRequest{UserInput: "What's on tomorrow?", Instructions: "Be helpful.", PreferredProvider: pb.Provider_PROVIDER_OPENAI}
+
+	cfg := createTestTenantConfig("openai")
+	prepared, err := svc.prepareRequest(ctxWithChatPermissionAndTenant("test-client", cfg), req)
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+	if strings.Contains(prepared.params.Instructions, "<current_date>") {
+		t.Errorf("got %q, want no date context unless enabled", prepared.params.Instructions)
+	}
+
+	cfg.DateContext = tenant.DateContextConfig{Inject: true, Timezone: "UTC"}
+	prepared, err = svc.prepareRequest(ctxWithChatPermissionAndTenant("test-client", cfg), req)
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+	if !strings.HasPrefix(prepared.params.Instructions, "Be helpful.") || !strings.Contains(prepared.params.Instructions, time.Now().UTC().Format("2 January 2006")) {
+		t.Errorf("got %q, want today's date appended", prepared.params.Instructions)
+	}
+}
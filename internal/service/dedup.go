@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// WithDedup collapses identical GenerateReply requests from the same client
+// into one provider call. Requests arriving while an identical one is being
+// generated, or within window after it succeeded, share its reply. Requests
+// are identical when they continue the same thread and differ at most in
+// idempotent. A request_id that is not a UUID names no thread, so client
+// retries that generate a new such request_id are caught.
+func WithDedup(window time.Duration) ChatServiceOption {
+	return func(s *ChatService) {
+		s.dedup = newDedupGroup(window)
+	}
+}
+
+// dedupGroup tracks in-flight and recently completed generations by key.
+type dedupGroup struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is one generation shared by the requests with its key.
+type dedupCall struct {
+	done    chan struct{} // Closed when resp and err are set
+	resp    *pb.GenerateReplyResponse
+	err     error
+	expires time.Time // Set once the call succeeded
+}
+
+func newDedupGroup(window time.Duration) *dedupGroup {
+	return &dedupGroup{window: window, now: time.Now, calls: make(map[string]*dedupCall)}
+}
+
+// dedupKey identifies a request by its tenant, client, thread and content.
+// A request_id that parses as a UUID is the thread the reply is persisted to
+// (see persistConversation), so the same prompt in different threads is not
+// collapsed and every thread gets its own turn.
+func dedupKey(ctx context.Context, req *pb.GenerateReplyRequest) (string, error) {
+	content := proto.Clone(req).(*pb.GenerateReplyRequest)
+	if _, err := uuid.Parse(req.RequestId); err != nil {
+		content.RequestId = ""
+	}
+	content.Idempotent = false
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(auth.TenantIDFromContext(ctx)))
+	h.Write([]byte{0})
+	if client := auth.ClientFromContext(ctx); client != nil {
+		h.Write([]byte(client.ClientID))
+	}
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// do returns the reply of the call with key, running generate if there is
+// none. shared reports a reply generated for another request. Waiters whose
+// leader was cancelled generate their own reply, and failed calls are not
+// kept, so a retry after an error reaches the provider.
+func (g *dedupGroup) do(ctx context.Context, key string, generate func() (*pb.GenerateReplyResponse, error)) (resp *pb.GenerateReplyResponse, shared bool, err error) {
+	g.mu.Lock()
+	now := g.now()
+	for k, c := range g.calls {
+		if !c.expires.IsZero() && now.After(c.expires) {
+			delete(g.calls, k)
+		}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, false, status.FromContextError(ctx.Err()).Err()
+		}
+		if c.err == nil {
+			return proto.Clone(c.resp).(*pb.GenerateReplyResponse), true, nil
+		}
+		if code := status.Code(c.err); code != codes.Canceled && code != codes.DeadlineExceeded {
+			return nil, true, c.err
+		}
+		resp, err := generate()
+		return resp, false, err
+	}
+	c := &dedupCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.resp, c.err = generate()
+	if c.err == nil && c.resp == nil {
+		c.err = status.Error(codes.Internal, "no response generated")
+	}
+
+	g.mu.Lock()
+	if c.err == nil && g.window > 0 {
+		c.expires = g.now().Add(g.window)
+	} else {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(c.done)
+	return c.resp, false, c.err
+}
+
+// dedupReply generates the reply to req, sharing it with identical requests
+// when deduplication is enabled. Shared replies are not charged again.
+func (s *ChatService) dedupReply(ctx context.Context, req *pb.GenerateReplyRequest) (*pb.GenerateReplyResponse, error) {
+	if s.dedup == nil || len(req.ToolResults) > 0 {
+		return s.attributedReply(ctx, req)
+	}
+	key, err := dedupKey(ctx, req)
+	if err != nil {
+		return s.attributedReply(ctx, req)
+	}
+	resp, shared, err := s.dedup.do(ctx, key, func() (*pb.GenerateReplyResponse, error) {
+		return s.attributedReply(ctx, req)
+	})
+	if shared {
+		accesslog.Annotate(ctx, "deduplicated", true)
+		if resp != nil {
+			resp.Deduplicated = true
+		}
+	}
+	return resp, err
+}
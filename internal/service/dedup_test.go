@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/google/uuid"
+)
+
+// heldProvider holds each generation until release is closed.
+type heldProvider struct {
+	*mockProvider
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (p *heldProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	if p.calls.Add(1) == 1 {
+		close(p.started)
+	}
+	<-p.release
+	return p.generateResult, p.generateErr
+}
+
+func dedupRequest(requestID string) *pb.GenerateReplyRequest {
+	return &pb.GenerateReplyRequest{
+		RequestId:         requestID,
+		UserInput:         "Summarize our refund policy",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	}
+}
+
+func TestGenerateReply_DedupConcurrent(t *testing.T) {
+	openai := &heldProvider{mockProvider: newMockProvider("openai"), started: make(chan struct{}), release: make(chan struct{})}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+	WithDedup(time.Second)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	var wg sync.WaitGroup
+	resps := make([]*pb.GenerateReplyResponse, 3)
+	errs := make([]error, 3)
+	run := func(i int, requestID string) {
+		defer wg.Done()
+		resps[i], errs[i] = svc.GenerateReply(ctx, dedupRequest(requestID))
+	}
+	wg.Add(1)
+	go run(0, "req-1")
+	<-openai.started
+	wg.Add(2)
+	go run(1, "req-2")
+	go run(2, "req-3")
+	time.Sleep(20 * time.Millisecond)
+	close(openai.release)
+	wg.Wait()
+
+	if n := openai.calls.Load(); n != 1 {
+		t.Fatalf("expected one provider call, got %d", n)
+	}
+	shared := 0
+	for i, resp := range resps {
+		if errs[i] != nil {
+			t.Fatalf("request %d failed: %v", i, errs[i])
+		}
+		if resp.Text != "Mock response" {
+			t.Errorf("request %d: unexpected text %q", i, resp.Text)
+		}
+		if resp.Deduplicated {
+			shared++
+		}
+	}
+	if shared != 2 || resps[0].Deduplicated {
+		t.Errorf("expected the two retries marked deduplicated, got %d (first: %v)", shared, resps[0].Deduplicated)
+	}
+}
+
+func TestGenerateReply_DedupWindow(t *testing.T) {
+	openai := newMockProvider("openai")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	WithDedup(2 * time.Second)(svc)
+	now := time.Now()
+	svc.dedup.now = func() time.Time { return now }
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	svc.GenerateReply(ctx, dedupRequest("req-1"))
+	resp, err := svc.GenerateReply(ctx, dedupRequest("req-2"))
+	if err != nil || !resp.Deduplicated || len(openai.generateCalls) != 1 {
+		t.Fatalf("expected the retry within the window to share the reply, got %d calls (err %v)", len(openai.generateCalls), err)
+	}
+
+	// Another client's identical request is not collapsed
+	other := ctxWithChatPermissionAndTenant("other-client", createTestTenantConfig("openai"))
+	if resp, _ := svc.GenerateReply(other, dedupRequest("req-3")); resp.Deduplicated {
+		t.Error("expected another client's request to be generated")
+	}
+
+	now = now.Add(3 * time.Second)
+	if resp, _ := svc.GenerateReply(ctx, dedupRequest("req-4")); resp.Deduplicated {
+		t.Error("expected a request after the window to be generated")
+	}
+	if len(openai.generateCalls) != 3 {
+		t.Errorf("expected 3 provider calls, got %d", len(openai.generateCalls))
+	}
+}
+
+func TestGenerateReply_DedupSeparatesThreads(t *testing.T) {
+	openai := newMockProvider("openai")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	WithDedup(time.Minute)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	thread := uuid.NewString()
+	for _, requestID := range []string{thread, uuid.NewString()} {
+		resp, err := svc.GenerateReply(ctx, dedupRequest(requestID))
+		if err != nil || resp.Deduplicated {
+			t.Fatalf("expected the prompt to be generated for thread %s, got %v (err %v)", requestID, resp, err)
+		}
+	}
+	if len(openai.generateCalls) != 2 {
+		t.Fatalf("expected one provider call per thread, got %d", len(openai.generateCalls))
+	}
+
+	// A retry in the same thread shares its reply
+	resp, err := svc.GenerateReply(ctx, dedupRequest(thread))
+	if err != nil || !resp.Deduplicated {
+		t.Errorf("expected the retry in the same thread to share the reply, got %v (err %v)", resp, err)
+	}
+}
+
+func TestGenerateReply_DedupDoesNotKeepErrors(t *testing.T) {
+	openai := newMockProvider("openai")
+	openai.generateErr = errors.New("500 Internal Server Error")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	WithDedup(time.Minute)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	if _, err := svc.GenerateReply(ctx, dedupRequest("req-1")); err == nil {
+		t.Fatal("expected the provider error")
+	}
+	openai.generateErr = nil
+	resp, err := svc.GenerateReply(ctx, dedupRequest("req-2"))
+	if err != nil || resp.Deduplicated {
+		t.Fatalf("expected the retry to be generated, got %v (err %v)", resp, err)
+	}
+	if len(openai.generateCalls) != 2 {
+		t.Errorf("expected 2 provider calls, got %d", len(openai.generateCalls))
+	}
+}
+
+func TestGenerateReply_DedupDisabled(t *testing.T) {
+	openai := newMockProvider("openai")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	svc.GenerateReply(ctx, dedupRequest("req-1"))
+	svc.GenerateReply(ctx, dedupRequest("req-2"))
+	if len(openai.generateCalls) != 2 {
+		t.Errorf("expected 2 provider calls without dedup, got %d", len(openai.generateCalls))
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/egress"
+	sanitize "github.com/ai8future/airborne/internal/errors"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/rag/embedder"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxEmbedTexts bounds the number of texts in one Embed request.
+	maxEmbedTexts = 512
+
+	// maxEmbedTextBytes bounds the size of each text in an Embed request.
+	maxEmbedTextBytes = 32 * 1024
+
+	// embedCharsPerToken estimates token counts for providers that do not
+	// report usage.
+	embedCharsPerToken = 4
+)
+
+// WithEmbedder sets the server's self-hosted embedder, used by the Embed RPC
+// for tenants whose embedding provider is "ollama".
+func WithEmbedder(emb embedder.Embedder) ChatServiceOption {
+	return func(s *ChatService) {
+		s.localEmbedder = emb
+	}
+}
+
+// tenantEmbedder returns the embedder configured for the request's tenant
+// and the name of its provider.
+func (s *ChatService) tenantEmbedder(ctx context.Context) (embedder.Embedder, string, error) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil {
+		return nil, "", status.Error(codes.FailedPrecondition, "tenant configuration not found")
+	}
+
+	cfg := tenantCfg.Embedding
+	switch cfg.Provider {
+	case "openai":
+		return embedder.NewOpenAIEmbedder(embedder.OpenAIConfig{
+			APIKey:     tenantCfg.Providers["openai"].APIKey,
+			BaseURL:    cfg.BaseURL,
+			Model:      cfg.Model,
+			Dimensions: cfg.Dimensions,
+			Proxy:      tenantCfg.Proxy,
+		}), "openai", nil
+	case "gemini":
+		return embedder.NewGeminiEmbedder(embedder.GeminiConfig{
+			APIKey:     tenantCfg.Providers["gemini"].APIKey,
+			BaseURL:    cfg.BaseURL,
+			Model:      cfg.Model,
+			Dimensions: cfg.Dimensions,
+			Proxy:      tenantCfg.Proxy,
+		}), "gemini", nil
+	default:
+		if s.localEmbedder == nil {
+			return nil, "", status.Error(codes.FailedPrecondition, "no embedding provider configured for tenant")
+		}
+		return s.localEmbedder, "ollama", nil
+	}
+}
+
+// estimateEmbedTokens approximates the input tokens of texts.
+func estimateEmbedTokens(texts []string) int64 {
+	var chars int
+	for _, text := range texts {
+		chars += len(text)
+	}
+	return int64((chars + embedCharsPerToken - 1) / embedCharsPerToken)
+}
+
+// Embed returns an embedding for each text from the tenant's embedding
+// provider. Estimated tokens are charged against the client's TPM limit
+// before the provider is called, so bulk embedding cannot bypass it.
+func (s *ChatService) Embed(ctx context.Context, req *pb.EmbedRequest) (*pb.EmbedResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChat); err != nil {
+		return nil, err
+	}
+
+	requestID, err := validation.ValidateOrGenerateRequestID(req.RequestId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(req.Texts) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "texts is required")
+	}
+	if len(req.Texts) > maxEmbedTexts {
+		return nil, status.Errorf(codes.InvalidArgument, "too many texts (%d, maximum %d)", len(req.Texts), maxEmbedTexts)
+	}
+	for i, text := range req.Texts {
+		if len(text) > maxEmbedTextBytes {
+			return nil, status.Errorf(codes.InvalidArgument, "texts[%d] exceeds %d bytes", i, maxEmbedTextBytes)
+		}
+	}
+
+	emb, providerName, err := s.tenantEmbedder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = egress.WithAttribution(ctx, auth.TenantIDFromContext(ctx), providerName)
+
+	accesslog.Annotate(ctx,
+		"provider", providerName,
+		"request_id", requestID,
+		"embed_texts", len(req.Texts),
+	)
+
+	estimated := estimateEmbedTokens(req.Texts)
+	if err := s.recordEmbedTokens(ctx, estimated); err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquireSlot(ctx, &pb.GenerateReplyRequest{}, providerName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var vectors [][]float32
+	tokens := estimated
+	if ue, ok := emb.(embedder.UsageEmbedder); ok {
+		var reported int64
+		vectors, reported, err = ue.EmbedBatchWithUsage(ctx, req.Texts)
+		if reported > 0 {
+			tokens = reported
+		}
+	} else {
+		vectors, err = emb.EmbedBatch(ctx, req.Texts)
+	}
+	s.reportProviderError(providerName, err)
+	if err != nil {
+		s.recordProviderCall(ctx, db.ProviderCall{RequestID: requestID, Provider: providerName, Model: emb.Model()}, err)
+		slog.ErrorContext(ctx, "embedding failed", "provider", providerName, "request_id", requestID, "error", err)
+		return nil, status.Error(codes.Internal, sanitize.SanitizeForClient(err))
+	}
+
+	// Charge any tokens the estimate missed; the request already succeeded
+	if tokens > estimated {
+		if err := s.recordEmbedTokens(ctx, tokens-estimated); err != nil {
+			slog.WarnContext(ctx, "embedding usage exceeded token rate limit", "request_id", requestID)
+		}
+	}
+
+	usage := &provider.Usage{InputTokens: tokens, TotalTokens: tokens}
+	costUSD := 0.0
+	if providerName != "ollama" {
+		costUSD = estimateCost(providerName, emb.Model(), usage, 0).Total()
+	}
+	s.recordSpend(ctx, costUSD)
+	s.recordProviderCall(ctx, db.ProviderCall{
+		RequestID:   requestID,
+		Provider:    providerName,
+		Model:       emb.Model(),
+		InputTokens: tokens,
+		CostUSD:     costUSD,
+	}, nil)
+
+	resp := &pb.EmbedResponse{
+		Embeddings:       make([]*pb.Embedding, len(vectors)),
+		Provider:         providerName,
+		Model:            emb.Model(),
+		Usage:            convertUsage(usage),
+		EstimatedCostUsd: costUSD,
+	}
+	for i, v := range vectors {
+		resp.Embeddings[i] = &pb.Embedding{Values: v}
+	}
+	if len(vectors) > 0 {
+		resp.Dimensions = int32(len(vectors[0]))
+	}
+	return resp, nil
+}
+
+// recordEmbedTokens charges tokens to the client's TPM limit, returning
+// ResourceExhausted once it is exceeded.
+func (s *ChatService) recordEmbedTokens(ctx context.Context, tokens int64) error {
+	client := auth.ClientFromContext(ctx)
+	if s.rateLimiter == nil || client == nil {
+		return nil
+	}
+	err := s.rateLimiter.RecordTokens(ctx, client.ClientID, tokens, client.RateLimits.TokensPerMinute)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, auth.ErrRateLimitExceeded):
+		return status.Error(codes.ResourceExhausted, "token rate limit exceeded")
+	default:
+		slog.WarnContext(ctx, "failed to record token usage for rate limiting", "client_id", client.ClientID, "error", err)
+		return nil
+	}
+}
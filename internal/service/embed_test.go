@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeEmbedder returns a one-value vector holding each text's length.
+type fakeEmbedder struct {
+	calls int
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+func (e *fakeEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i], _ = e.Embed(ctx, text)
+	}
+	return out, nil
+}
+
+func (e *fakeEmbedder) Dimensions() int { return 1 }
+func (e *fakeEmbedder) Model() string   { return "fake-embed" }
+
+func TestEmbed_LocalEmbedder(t *testing.T) {
+	emb := &fakeEmbedder{}
+	svc := NewChatService(nil, nil, nil, nil, WithEmbedder(emb))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	resp, err := svc.Embed(ctx, &pb.EmbedRequest{Texts: []string{"hello", "hi"}})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[0].Values[0] != 5 || resp.Embeddings[1].Values[0] != 2 {
+		t.Errorf("unexpected embeddings: %v", resp.Embeddings)
+	}
+	if resp.Provider != "ollama" || resp.Model != "fake-embed" || resp.Dimensions != 1 || resp.EstimatedCostUsd != 0 {
+		t.Errorf("unexpected response metadata: %+v", resp)
+	}
+	if resp.Usage.InputTokens != 2 { // 7 chars estimated at 4 per token
+		t.Errorf("InputTokens = %d, want 2", resp.Usage.InputTokens)
+	}
+}
+
+func TestEmbed_TenantProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key-openai" {
+			t.Errorf("Authorization = %q, want the tenant's OpenAI key", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"data": [{"index": 0, "embedding": [0.5, 0.25]}], "usage": {"prompt_tokens": 9}}`))
+	}))
+	defer server.Close()
+
+	cfg := createTestTenantConfig("openai")
+	cfg.Embedding = tenant.EmbeddingConfig{Provider: "openai", BaseURL: server.URL}
+	svc := NewChatService(nil, nil, nil, nil)
+
+	resp, err := svc.Embed(ctxWithChatPermissionAndTenant("test-client", cfg), &pb.EmbedRequest{Texts: []string{"hello"}})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if resp.Provider != "openai" || resp.Model != "text-embedding-3-small" || resp.Dimensions != 2 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.InputTokens != 9 {
+		t.Errorf("InputTokens = %d, want the 9 tokens the provider reported", resp.Usage.InputTokens)
+	}
+}
+
+func TestEmbed_Validation(t *testing.T) {
+	svc := NewChatService(nil, nil, nil, nil, WithEmbedder(&fakeEmbedder{}))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	tests := []struct {
+		name  string
+		texts []string
+	}{
+		{"no texts", nil},
+		{"too many texts", make([]string, maxEmbedTexts+1)},
+		{"text too large", []string{strings.Repeat("a", maxEmbedTextBytes+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Embed(ctx, &pb.EmbedRequest{Texts: tt.texts})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("err = %v, want InvalidArgument", err)
+			}
+		})
+	}
+
+	_, err := NewChatService(nil, nil, nil, nil).Embed(ctx, &pb.EmbedRequest{Texts: []string{"hi"}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("without an embedder: err = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestEmbed_TokenRateLimit(t *testing.T) {
+	_, client := newTestRedis(t)
+	limiter := auth.NewRateLimiter(client, auth.RateLimits{TokensPerMinute: 100}, true)
+	emb := &fakeEmbedder{}
+	svc := NewChatService(limiter, nil, nil, nil, WithEmbedder(emb))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	text := strings.Repeat("a", 240) // ~60 tokens
+	if _, err := svc.Embed(ctx, &pb.EmbedRequest{Texts: []string{text}}); err != nil {
+		t.Fatalf("first Embed failed: %v", err)
+	}
+	_, err := svc.Embed(ctx, &pb.EmbedRequest{Texts: []string{text}})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("err = %v, want ResourceExhausted", err)
+	}
+	if emb.calls != 1 {
+		t.Errorf("embedder called %d times, want 1 (rejected request must not reach the provider)", emb.calls)
+	}
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	sanitize "github.com/ai8future/airborne/internal/errors"
+	"github.com/ai8future/airborne/internal/markdownsvc"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// defaultPrimaryBudgetPercent is the share of a request's remaining deadline
+// the primary provider gets when the request can fail over.
+const defaultPrimaryBudgetPercent = 70
+
+// failoverAllowed reports whether req may move to another provider. A fired
+// hedge has already tried the fallback.
+func failoverAllowed(req *pb.GenerateReplyRequest, prepared *preparedRequest) bool {
+	return req.EnableFailover && !prepared.hedged && !pinnedToProvider(req)
+}
+
+// failoverTriggered reports whether the tenant opted into failover on trigger.
+func failoverTriggered(ctx context.Context, trigger string) bool {
+	tenantCfg := auth.TenantFromContext(ctx)
+	return tenantCfg != nil && tenantCfg.Failover.HasTrigger(trigger)
+}
+
+// replyFailoverTrigger returns the tenant's failover trigger a successful
+// reply matches, with a reason to report as its error, or "" to keep it.
+func replyFailoverTrigger(ctx context.Context, result provider.GenerateResult) (trigger, reason string) {
+	switch {
+	case result.SafetyBlock != nil && failoverTriggered(ctx, tenant.FailoverTriggerSafetyBlock):
+		return tenant.FailoverTriggerSafetyBlock, "blocked by safety filters (" + result.SafetyBlock.Stage + ")"
+	case emptyReply(result) && failoverTriggered(ctx, tenant.FailoverTriggerEmpty):
+		return tenant.FailoverTriggerEmpty, "empty response"
+	}
+	return "", ""
+}
+
+// emptyReply reports whether result carries nothing for the client.
+func emptyReply(result provider.GenerateResult) bool {
+	return strings.TrimSpace(result.Text) == "" &&
+		result.SafetyBlock == nil &&
+		!result.RequiresToolOutput &&
+		len(result.ToolCalls) == 0 &&
+		len(result.ComputerActions) == 0 &&
+		len(result.Images) == 0
+}
+
+// failoverChain returns the providers to try, in order, when a request's
+// provider fails. An explicitly requested fallback is tried alone. Otherwise
+// the tenant's failover order supplies every remaining enabled provider, up to
+// its max_attempts; tenants without an order get the default pairing.
+// Providers named in exclude are never returned.
+func (s *ChatService) failoverChain(ctx context.Context, specified pb.Provider, exclude ...string) []provider.Provider {
+	skip := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		if name != "" {
+			skip[name] = true
+		}
+	}
+	var chain []provider.Provider
+	add := func(p provider.Provider) {
+		if p != nil && !skip[p.Name()] {
+			skip[p.Name()] = true
+			chain = append(chain, p)
+		}
+	}
+
+	if specified != pb.Provider_PROVIDER_UNSPECIFIED {
+		add(s.providerByName(providerNameFromProto(specified)))
+		return chain
+	}
+
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil || !tenantCfg.Failover.Enabled || len(tenantCfg.Failover.Order) == 0 {
+		if len(exclude) > 0 {
+			add(s.defaultFallback(exclude[0]))
+		}
+		return chain
+	}
+
+	for _, name := range tenantCfg.Failover.Order {
+		if _, ok := tenantCfg.GetProvider(name); !ok {
+			continue
+		}
+		add(s.providerByName(name))
+		if limit := tenantCfg.Failover.MaxAttempts; limit > 0 && len(chain) == limit {
+			break
+		}
+	}
+	return chain
+}
+
+// getFallbackProvider returns the first provider of primary's failover chain,
+// or nil if there is none.
+func (s *ChatService) getFallbackProvider(ctx context.Context, primary string, specified pb.Provider) provider.Provider {
+	if chain := s.failoverChain(ctx, specified, primary); len(chain) > 0 {
+		return chain[0]
+	}
+	return nil
+}
+
+// defaultFallback returns the fallback for primary when the tenant has no
+// failover order.
+func (s *ChatService) defaultFallback(primary string) provider.Provider {
+	switch primary {
+	case provider.NameOpenAI:
+		return s.geminiProvider
+	case provider.NameGemini:
+		return s.openaiProvider
+	case provider.NameAnthropic:
+		return s.openaiProvider
+	default:
+		return s.geminiProvider
+	}
+}
+
+// providerByName returns the provider registered under name, or nil.
+func (s *ChatService) providerByName(name string) provider.Provider {
+	switch name {
+	case provider.NameOpenAI:
+		return s.openaiProvider
+	case provider.NameGemini:
+		return s.geminiProvider
+	case provider.NameAnthropic:
+		return s.anthropicProvider
+	default:
+		return nil
+	}
+}
+
+// providerNameFromProto maps a proto provider to its provider name.
+func providerNameFromProto(p pb.Provider) string {
+	switch p {
+	case pb.Provider_PROVIDER_OPENAI:
+		return provider.NameOpenAI
+	case pb.Provider_PROVIDER_GEMINI:
+		return provider.NameGemini
+	case pb.Provider_PROVIDER_ANTHROPIC:
+		return provider.NameAnthropic
+	default:
+		return ""
+	}
+}
+
+// withRequestTimeout bounds ctx by the request's timeout_ms, if set.
+func withRequestTimeout(ctx context.Context, req *pb.GenerateReplyRequest) (context.Context, context.CancelFunc) {
+	if req.TimeoutMs <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Duration(req.TimeoutMs)*time.Millisecond)
+}
+
+// withBudget bounds ctx by budget, or returns it unchanged for a zero budget.
+func withBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	if budget <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, budget)
+}
+
+// primaryBudget returns how long the primary provider may take so that the
+// failover chain still has time before the request deadline, or zero when
+// the request has no deadline or cannot fail over.
+func (s *ChatService) primaryBudget(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok || !failoverAllowed(req, prepared) {
+		return 0
+	}
+	if len(s.failoverChain(ctx, req.FallbackProvider, prepared.provider.Name(), prepared.preemptedFrom)) == 0 {
+		return 0
+	}
+	percent := defaultPrimaryBudgetPercent
+	if tenantCfg := auth.TenantFromContext(ctx); tenantCfg != nil && tenantCfg.Failover.PrimaryBudgetPercent > 0 {
+		percent = tenantCfg.Failover.PrimaryBudgetPercent
+	}
+	budget := time.Until(deadline) * time.Duration(percent) / 100
+	accesslog.Annotate(ctx, "primary_budget_ms", budget.Milliseconds())
+	return budget
+}
+
+// fallbackBudget returns how long the next fallback attempt may take: an even
+// share of the time left before the request deadline across the remaining
+// attempts, capped by the tenant's attempt_timeout_ms. Zero means no limit
+// beyond the request's own deadline.
+func fallbackBudget(ctx context.Context, remaining int) time.Duration {
+	var budget time.Duration
+	if tenantCfg := auth.TenantFromContext(ctx); tenantCfg != nil && tenantCfg.Failover.AttemptTimeoutMs > 0 {
+		budget = time.Duration(tenantCfg.Failover.AttemptTimeoutMs) * time.Millisecond
+	}
+	if deadline, ok := ctx.Deadline(); ok && remaining > 0 {
+		if share := time.Until(deadline) / time.Duration(remaining); budget == 0 || share < budget {
+			budget = share
+		}
+	}
+	return budget
+}
+
+// generateWithFailover tries each provider of the failover chain in turn after
+// the request's provider failed with primaryErr. It returns the first
+// successful reply, listing every failed attempt, or nil if all of them failed.
+// The replies the fallbacks regenerated for failing validation are added to
+// the primary's validationAttempts, which are returned and persisted with a
+// successful reply.
+func (s *ChatService) generateWithFailover(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, primaryErr error, primaryElapsed time.Duration, validationAttempts []db.ValidationAttempt) (*pb.GenerateReplyResponse, []db.ValidationAttempt) {
+	primary := prepared.provider.Name()
+	chain := s.failoverChain(ctx, req.FallbackProvider, primary, prepared.preemptedFrom)
+	if len(chain) == 0 {
+		return nil, validationAttempts
+	}
+	chainStart := time.Now()
+
+	attempts := []*pb.FailoverAttempt{{
+		Provider:   mapProviderToProto(primary),
+		Model:      prepared.providerCfg.Model,
+		Error:      sanitize.SanitizeForClient(primaryErr),
+		DurationMs: primaryElapsed.Milliseconds(),
+	}}
+	defer func() {
+		accesslog.Annotate(ctx, "failover_attempts", len(attempts))
+	}()
+
+	lastName, lastErr := primary, primaryErr
+	for i, fallback := range chain {
+		if ctx.Err() != nil {
+			break
+		}
+		slog.WarnContext(ctx, "provider failed, trying next in failover chain",
+			"failed", lastName,
+			"fallback", fallback.Name(),
+			"attempt", len(attempts)+1,
+			"error", lastErr,
+		)
+
+		cfg := s.buildProviderConfig(ctx, req, fallback.Name())
+		prepared.params = prepared.paramsFor(fallback.Name(), cfg)
+		start := time.Now()
+		result, rejected, err := s.attemptFallback(ctx, fallback, prepared, fallbackBudget(ctx, len(chain)-i))
+		validationAttempts = append(validationAttempts, rejected...)
+		if err == nil {
+			if result.RequiresToolOutput {
+				result.ResponseID = s.saveToolTurn(ctx, fallback, prepared.params, result.Model, result.ResponseID, provider.PendingCalls(result.ToolCalls, result.ComputerActions), result.ToolTurnState)
+			}
+			var grounding *pb.GroundingCheck
+			result, grounding = s.groundReply(ctx, req, prepared, fallback.Name(), result)
+			// Render HTML for fallback result if markdown_svc is enabled
+			footnoted := replyFootnotes(result.Text, result.Citations, grounding)
+			var htmlContent string
+			if markdownsvc.IsEnabled() {
+				html, renderErr := markdownsvc.RenderHTML(ctx, cmp.Or(footnoted, result.Text))
+				if renderErr == nil {
+					htmlContent = html
+				} else {
+					slog.WarnContext(ctx, "markdown_svc render failed for fallback", "error", renderErr)
+				}
+			}
+			if s.dbClient != nil && result.Usage != nil {
+				processingTimeMs := int((primaryElapsed + time.Since(chainStart)).Milliseconds())
+				s.persistConversation(ctx, req, result, fallback.Name(), calledModel(prepared.params, ""), htmlContent, processingTimeMs, validationAttempts, nil)
+			}
+			if result.StructuredMetadata != nil {
+				s.persistMemoryFacts(ctx, prepared.memoryUserID, result.StructuredMetadata.Facts)
+			}
+			resp := s.buildResponse(result, fallback.Name(), true, primary, sanitize.SanitizeForClient(primaryErr), htmlContent)
+			resp.FailoverAttempts = attempts
+			resp.Grounding = grounding
+			resp.FootnotedMarkdown = footnoted
+			s.recordSpend(ctx, resp.EstimatedCostUsd)
+			return resp, validationAttempts
+		}
+
+		attempts = append(attempts, &pb.FailoverAttempt{
+			Provider:   mapProviderToProto(fallback.Name()),
+			Model:      cfg.Model,
+			Error:      sanitize.SanitizeForClient(err),
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		lastName, lastErr = fallback.Name(), err
+	}
+	return nil, validationAttempts
+}
+
+// attemptFallback generates and validates a reply from fallback within the
+// per-attempt timeout, returning the replies validation rejected. Replies
+// matching one of the tenant's failover triggers count as failures so the
+// chain moves on.
+func (s *ChatService) attemptFallback(ctx context.Context, fallback provider.Provider, prepared *preparedRequest, timeout time.Duration) (provider.GenerateResult, []db.ValidationAttempt, error) {
+	attemptCtx, cancel := withBudget(ctx, timeout)
+	defer cancel()
+	result, err := fallback.GenerateReply(s.observeHeadroom(attemptCtx, fallback.Name()), prepared.params)
+	s.reportProviderCall(ctx, fallback.Name(), prepared.params, result, err)
+	if err == nil {
+		result, err = s.runRemoteTools(attemptCtx, fallback, prepared.params, result)
+	}
+	if err != nil {
+		return result, nil, err
+	}
+	result, rejected, err := s.validateReply(attemptCtx, fallback, prepared.params, result)
+	if err != nil {
+		return result, rejected, err
+	}
+	if _, reason := replyFailoverTrigger(ctx, result); reason != "" {
+		s.recordSpend(ctx, estimateCost(fallback.Name(), result.Model, result.Usage, result.GroundingQueries).Total())
+		return result, rejected, errors.New(reason)
+	}
+	return result, rejected, nil
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failoverTenant returns a tenant with all providers enabled and the given
+// failover order.
+func failoverTenant(order ...string) *tenant.TenantConfig {
+	cfg := createTestTenantConfig("openai", "gemini", "anthropic")
+	cfg.Failover = tenant.FailoverConfig{Enabled: true, Order: order}
+	return cfg
+}
+
+func chainNames(chain []provider.Provider) []string {
+	var names []string
+	for _, p := range chain {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+func TestFailoverChain(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+
+	disabled := failoverTenant("gemini", "anthropic", "openai")
+	disabled.Providers["anthropic"] = tenant.ProviderConfig{Enabled: false}
+	limited := failoverTenant("gemini", "anthropic", "openai")
+	limited.Failover.MaxAttempts = 1
+
+	tests := []struct {
+		name      string
+		tenantCfg *tenant.TenantConfig
+		primary   string
+		specified pb.Provider
+		want      []string
+	}{
+		{"tenant order", failoverTenant("gemini", "anthropic", "openai"), "gemini", pb.Provider_PROVIDER_UNSPECIFIED, []string{"anthropic", "openai"}},
+		{"disabled provider skipped", disabled, "gemini", pb.Provider_PROVIDER_UNSPECIFIED, []string{"openai"}},
+		{"max attempts", limited, "gemini", pb.Provider_PROVIDER_UNSPECIFIED, []string{"anthropic"}},
+		{"explicit fallback", failoverTenant("gemini", "anthropic", "openai"), "gemini", pb.Provider_PROVIDER_OPENAI, []string{"openai"}},
+		{"no order uses default pairing", createTestTenantConfig("openai", "gemini"), "openai", pb.Provider_PROVIDER_UNSPECIFIED, []string{"gemini"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ctxWithChatPermissionAndTenant("test-client", tt.tenantCfg)
+			got := chainNames(svc.failoverChain(ctx, tt.specified, tt.primary))
+			if len(got) != len(tt.want) {
+				t.Fatalf("chain = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("chain = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateReply_FailoverTriesWholeChain(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mockOpenAI.generateErr = errors.New("openai unavailable")
+	mockGemini := newMockProvider("gemini")
+	mockGemini.generateErr = errors.New("gemini unavailable")
+	mockAnthropic := newMockProvider("anthropic")
+	svc := createChatServiceWithMocks(mockOpenAI, mockGemini, mockAnthropic, nil)
+	ctx := ctxWithChatPermissionAndTenant("test-client", failoverTenant("openai", "gemini", "anthropic"))
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFailover:    true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Provider != pb.Provider_PROVIDER_ANTHROPIC || !resp.FailedOver || resp.OriginalProvider != pb.Provider_PROVIDER_OPENAI {
+		t.Errorf("unexpected failover fields: provider=%v failed_over=%v original=%v", resp.Provider, resp.FailedOver, resp.OriginalProvider)
+	}
+	if len(resp.FailoverAttempts) != 2 {
+		t.Fatalf("expected 2 failed attempts, got %d", len(resp.FailoverAttempts))
+	}
+	if resp.FailoverAttempts[0].Provider != pb.Provider_PROVIDER_OPENAI || resp.FailoverAttempts[1].Provider != pb.Provider_PROVIDER_GEMINI {
+		t.Errorf("unexpected attempt order: %v, %v", resp.FailoverAttempts[0].Provider, resp.FailoverAttempts[1].Provider)
+	}
+	if resp.FailoverAttempts[1].Model != "test-model-gemini" || resp.FailoverAttempts[1].Error == "" {
+		t.Errorf("unexpected gemini attempt: %+v", resp.FailoverAttempts[1])
+	}
+	if len(mockAnthropic.generateCalls) != 1 {
+		t.Errorf("expected anthropic to be called once, got %d", len(mockAnthropic.generateCalls))
+	}
+}
+
+func TestGenerateReply_FailoverChainExhausted(t *testing.T) {
+	mocks := []*mockProvider{newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic")}
+	for _, m := range mocks {
+		m.generateErr = errors.New(m.name + " unavailable")
+	}
+	svc := createChatServiceWithMocks(mocks[0], mocks[1], mocks[2], nil)
+	ctx := ctxWithChatPermissionAndTenant("test-client", failoverTenant("gemini", "openai", "anthropic"))
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_GEMINI,
+		EnableFailover:    true,
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal error, got %v", err)
+	}
+	for _, m := range mocks {
+		if len(m.generateCalls) != 1 {
+			t.Errorf("expected %s to be tried once, got %d", m.name, len(m.generateCalls))
+		}
+	}
+}
+
+func TestGenerateReply_FailoverAttemptTimeout(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mockOpenAI.generateErr = errors.New("openai unavailable")
+	slowGemini := newSlowProvider("gemini", time.Minute)
+	mockAnthropic := newMockProvider("anthropic")
+	svc := &ChatService{
+		openaiProvider:    mockOpenAI,
+		geminiProvider:    slowGemini,
+		anthropicProvider: mockAnthropic,
+	}
+	tenantCfg := failoverTenant("openai", "gemini", "anthropic")
+	tenantCfg.Failover.AttemptTimeoutMs = 50
+	ctx, cancel := context.WithTimeout(ctxWithChatPermissionAndTenant("test-client", tenantCfg), 5*time.Second)
+	defer cancel()
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFailover:    true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if !slowGemini.cancelled.Load() {
+		t.Error("expected the slow gemini attempt to be cancelled")
+	}
+	if resp.Provider != pb.Provider_PROVIDER_ANTHROPIC || len(resp.FailoverAttempts) != 2 {
+		t.Errorf("expected anthropic after 2 failed attempts, got %v after %d", resp.Provider, len(resp.FailoverAttempts))
+	}
+}
+
+func TestGenerateReply_FailoverTriggers(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  provider.GenerateResult
+		triggers []string
+		validate bool
+		wantFail bool
+	}{
+		{"safety block", provider.GenerateResult{SafetyBlock: &provider.SafetyBlock{Stage: "response"}}, []string{tenant.FailoverTriggerSafetyBlock}, false, true},
+		{"safety block without opt-in", provider.GenerateResult{SafetyBlock: &provider.SafetyBlock{Stage: "response"}}, nil, false, false},
+		{"empty response", provider.GenerateResult{Text: "  "}, []string{tenant.FailoverTriggerEmpty}, false, true},
+		{"empty response without opt-in", provider.GenerateResult{Text: "  "}, []string{tenant.FailoverTriggerSafetyBlock}, false, false},
+		{"validation", provider.GenerateResult{Text: "As an AI, I cannot"}, []string{tenant.FailoverTriggerValidation}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOpenAI := newMockProvider("openai")
+			mockOpenAI.generateResult = tt.primary
+			mockGemini := newMockProvider("gemini")
+			svc := createChatServiceWithMocks(mockOpenAI, mockGemini, newMockProvider("anthropic"), nil)
+			tenantCfg := failoverTenant("openai", "gemini")
+			tenantCfg.Failover.Triggers = tt.triggers
+			if tt.validate {
+				tenantCfg.Validation = tenant.ValidationConfig{BannedStrings: []string{"as an ai"}}
+			}
+
+			resp, err := svc.GenerateReply(ctxWithChatPermissionAndTenant("test-client", tenantCfg), &pb.GenerateReplyRequest{
+				UserInput:         "Hello",
+				PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+				EnableFailover:    true,
+			})
+			if err != nil {
+				t.Fatalf("GenerateReply failed: %v", err)
+			}
+			if resp.FailedOver != tt.wantFail {
+				t.Fatalf("failed_over = %v, want %v", resp.FailedOver, tt.wantFail)
+			}
+			if !tt.wantFail {
+				if len(mockGemini.generateCalls) != 0 {
+					t.Errorf("expected gemini not to be called, got %d calls", len(mockGemini.generateCalls))
+				}
+				return
+			}
+			if resp.Provider != pb.Provider_PROVIDER_GEMINI || len(resp.FailoverAttempts) != 1 || resp.OriginalError == "" {
+				t.Errorf("unexpected failover response: provider=%v attempts=%d original_error=%q", resp.Provider, len(resp.FailoverAttempts), resp.OriginalError)
+			}
+		})
+	}
+}
+
+func TestGenerateReply_FailoverTriggerSkipsMatchingFallback(t *testing.T) {
+	blocked := provider.GenerateResult{SafetyBlock: &provider.SafetyBlock{Stage: "prompt"}}
+	mockOpenAI := newMockProvider("openai")
+	mockOpenAI.generateResult = blocked
+	mockGemini := newMockProvider("gemini")
+	mockGemini.generateResult = blocked
+	svc := createChatServiceWithMocks(mockOpenAI, mockGemini, newMockProvider("anthropic"), nil)
+	tenantCfg := failoverTenant("openai", "gemini", "anthropic")
+	tenantCfg.Failover.Triggers = []string{tenant.FailoverTriggerSafetyBlock}
+
+	resp, err := svc.GenerateReply(ctxWithChatPermissionAndTenant("test-client", tenantCfg), &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFailover:    true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Provider != pb.Provider_PROVIDER_ANTHROPIC || len(resp.FailoverAttempts) != 2 {
+		t.Errorf("expected anthropic after 2 blocked attempts, got %v after %d", resp.Provider, len(resp.FailoverAttempts))
+	}
+}
+
+func TestGenerateReply_PrimaryBudgetLeavesTimeForFailover(t *testing.T) {
+	slowOpenAI := newSlowProvider("openai", time.Minute)
+	mockGemini := newMockProvider("gemini")
+	svc := &ChatService{
+		openaiProvider:    slowOpenAI,
+		geminiProvider:    mockGemini,
+		anthropicProvider: newMockProvider("anthropic"),
+	}
+	tenantCfg := failoverTenant("openai", "gemini")
+	tenantCfg.Failover.PrimaryBudgetPercent = 50
+
+	start := time.Now()
+	resp, err := svc.GenerateReply(ctxWithChatPermissionAndTenant("test-client", tenantCfg), &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFailover:    true,
+		TimeoutMs:         400,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if !slowOpenAI.cancelled.Load() || resp.Provider != pb.Provider_PROVIDER_GEMINI {
+		t.Errorf("expected the primary to be cut off and gemini to answer, got %v", resp.Provider)
+	}
+	if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Errorf("request took %v, longer than its 400ms budget", elapsed)
+	}
+}
+
+func TestFallbackBudget(t *testing.T) {
+	tenantCfg := failoverTenant("openai", "gemini", "anthropic")
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+	if got := fallbackBudget(ctx, 2); got != 0 {
+		t.Errorf("expected no budget without a deadline, got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if got := fallbackBudget(ctx, 2); got > 5*time.Second || got < 4*time.Second {
+		t.Errorf("expected about half the deadline for 2 remaining attempts, got %v", got)
+	}
+	tenantCfg.Failover.AttemptTimeoutMs = 1000
+	if got := fallbackBudget(ctx, 2); got != time.Second {
+		t.Errorf("expected attempt_timeout_ms to cap the budget, got %v", got)
+	}
+}
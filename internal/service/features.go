@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// featureFlags are the feature flags in effect for a request.
+type featureFlags map[string]bool
+
+// resolveFeatures returns the tenant's feature flags with the request's
+// overrides applied. Overrides require admin permission, so clients cannot
+// opt themselves into a feature that is still being rolled out.
+func resolveFeatures(ctx context.Context, req *pb.GenerateReplyRequest) (featureFlags, error) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	flags := make(featureFlags, len(tenant.Features))
+	for _, name := range tenant.Features {
+		flags[name] = tenantCfg == nil || tenantCfg.FeatureEnabled(name)
+	}
+
+	overrides := req.GetFeatureOverrides()
+	if len(overrides) == 0 {
+		return flags, nil
+	}
+	if err := auth.RequirePermission(ctx, auth.PermissionAdmin); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "feature_overrides requires admin permission")
+	}
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !tenant.KnownFeature(name) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown feature flag %q", name)
+		}
+		flags[name] = overrides[name]
+	}
+	return flags, nil
+}
+
+// checkRequest rejects a request that asks for a feature the flags disable.
+// Image generation is triggered from the message text, so it is gated where
+// the text is parsed instead.
+func (f featureFlags) checkRequest(req *pb.GenerateReplyRequest) error {
+	requested := []struct {
+		name string
+		on   bool
+	}{
+		{tenant.FeatureStructuredOutput, req.EnableStructuredOutput},
+		{tenant.FeatureCodeExecution, req.EnableCodeExecution},
+		{tenant.FeatureRAG, req.EnableFileSearch},
+	}
+	for _, r := range requested {
+		if r.on && !f[r.name] {
+			return status.Errorf(codes.FailedPrecondition, "%s is not enabled for this tenant", r.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResolveFeatures(t *testing.T) {
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.Features = map[string]bool{tenant.FeatureCodeExecution: false}
+
+	tests := []struct {
+		name     string
+		admin    bool
+		req      *pb.GenerateReplyRequest
+		code     codes.Code
+		wantCode bool // Effective code_execution flag
+	}{
+		{"tenant default", false, &pb.GenerateReplyRequest{}, codes.OK, false},
+		{"tenant disables requested feature", false, &pb.GenerateReplyRequest{EnableCodeExecution: true}, codes.FailedPrecondition, false},
+		{"override needs admin", false, &pb.GenerateReplyRequest{FeatureOverrides: map[string]bool{tenant.FeatureCodeExecution: true}}, codes.PermissionDenied, false},
+		{"admin override", true, &pb.GenerateReplyRequest{EnableCodeExecution: true, FeatureOverrides: map[string]bool{tenant.FeatureCodeExecution: true}}, codes.OK, true},
+		{"unknown override", true, &pb.GenerateReplyRequest{FeatureOverrides: map[string]bool{"teleport": true}}, codes.InvalidArgument, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ctxWithChatPermissionAndTenant("c", tenantCfg)
+			if tt.admin {
+				ctx = ctxWithAdminAndChatPermission("c", tenantCfg)
+			}
+			features, err := resolveFeatures(ctx, tt.req)
+			if err == nil {
+				err = features.checkRequest(tt.req)
+			}
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if err == nil && features[tenant.FeatureCodeExecution] != tt.wantCode {
+				t.Errorf("code_execution = %v, want %v", features[tenant.FeatureCodeExecution], tt.wantCode)
+			}
+			if err == nil && !features[tenant.FeatureRAG] {
+				t.Error("unset flags should be enabled")
+			}
+		})
+	}
+}
+
+func TestGenerateReply_ImageGenerationFlag(t *testing.T) {
+	mock := newMockProvider("openai")
+	svc := createChatServiceWithMocks(mock, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.Features = map[string]bool{tenant.FeatureImageGeneration: false}
+	ctx := ctxWithChatPermissionAndTenant("c", tenantCfg)
+
+	// With image generation flagged off, /image is sent to the model as text
+	if _, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "/image a cat"}); err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if len(mock.generateCalls) != 1 || mock.generateCalls[0].UserInput != "/image a cat" {
+		t.Errorf("expected the prompt to reach the provider, got %+v", mock.generateCalls)
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/email"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+// fetchURLEgress attributes fetch_url requests in the egress audit.
+const fetchURLEgress = "fetch_url"
+
+// maxFetchRedirects bounds the redirects one fetch follows.
+const maxFetchRedirects = 5
+
+// fetchURLTool returns the fetch_url tool as offered to the model, listing
+// the domains it may fetch from.
+func fetchURLTool(cfg tenant.FetchURLConfig) provider.Tool {
+	schema, _ := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url": map[string]any{"type": "string", "description": "The https URL of the page"},
+		},
+		"required": []string{"url"},
+	})
+	return provider.Tool{
+		Name: tenant.FetchURLToolName,
+		Description: "Fetches a web page and returns its text, to answer from live content. " +
+			"Only https URLs on these domains can be fetched: " + strings.Join(cfg.AllowedDomains, ", "),
+		ParametersSchema: string(schema),
+	}
+}
+
+// callFetchURL fetches the page of a fetch_url call. Failures are returned
+// to the model as error results so it can answer without the page.
+func (s *ChatService) callFetchURL(ctx context.Context, cfg tenant.FetchURLConfig, call provider.ToolCall) provider.ToolResult {
+	out, err := s.fetchURL(ctx, cfg, call)
+	if err != nil {
+		slog.WarnContext(ctx, "fetch tool call failed",
+			"call_id", call.ID,
+			"error", err,
+		)
+		return provider.ToolResult{ToolCallID: call.ID, Output: "fetch failed: " + err.Error(), IsError: true}
+	}
+	return provider.ToolResult{ToolCallID: call.ID, Output: out}
+}
+
+// fetchURL GETs the URL of a fetch_url call and returns its text, cut to the
+// remote tool response limit. Redirects are followed only to URLs that
+// could have been fetched directly. Every connection, including those of
+// redirects, is checked against internal addresses when it is dialed.
+func (s *ChatService) fetchURL(ctx context.Context, cfg tenant.FetchURLConfig, call provider.ToolCall) (string, error) {
+	var args struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil || args.URL == "" {
+		return "", errors.New(`arguments must be {"url": ...}`)
+	}
+	allowed, err := validation.ParseURLAllowlist(cfg.AllowedDomains)
+	if err != nil {
+		return "", err
+	}
+	if err := checkFetchURL(allowed, args.URL); err != nil {
+		return "", err
+	}
+
+	transport := s.toolTransport
+	if transport == nil {
+		transport = egress.PublicTransport(tenantProxy(ctx))
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFetchRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxFetchRedirects)
+			}
+			return checkFetchURL(allowed, req.URL.String())
+		},
+	}
+	ctx = egress.WithAttribution(ctx, auth.TenantIDFromContext(ctx), fetchURLEgress)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, args.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html, text/plain;q=0.9, */*;q=0.5")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // The model already has the URL
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("server returned %s", resp.Status)
+	}
+	limit := cfg.BodyLimit()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > limit {
+		return "", fmt.Errorf("page exceeds %d bytes", limit)
+	}
+
+	text, err := pageText(resp.Header.Get("Content-Type"), body)
+	if err != nil {
+		return "", err
+	}
+	return truncateText(text, tenant.DefaultRemoteToolResponseBytes), nil
+}
+
+// checkFetchURL reports why rawURL cannot be fetched: it must be https, on
+// an allowed domain, and not name an internal service. The addresses the
+// domain resolves to are checked when connecting.
+func checkFetchURL(allowed *validation.URLAllowlist, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("url must be an https URL")
+	}
+	if !allowed.Allows(u.Hostname()) {
+		return fmt.Errorf("%s is not an allowed domain", u.Hostname())
+	}
+	// SECURITY: allowed domains must still not name internal services
+	if err := validation.CheckPublicHost(u.Hostname()); err != nil {
+		return fmt.Errorf("url rejected: %w", err)
+	}
+	return nil
+}
+
+// pageText returns the text of a page: the visible text of HTML, or the
+// body of other text types.
+func pageText(contentType string, body []byte) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return email.HTMLToText(string(body)), nil
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml"):
+		if !utf8.Valid(body) {
+			return "", errors.New("page is not valid UTF-8 text")
+		}
+		return string(body), nil
+	}
+	return "", fmt.Errorf("content type %q is not text", mediaType)
+}
+
+// truncateText cuts text to at most limit bytes, at a character boundary,
+// marking that it was cut.
+func truncateText(text string, limit int) string {
+	const marker = "\n[truncated]"
+	if len(text) <= limit {
+		return text
+	}
+	end := limit - len(marker)
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + marker
+}
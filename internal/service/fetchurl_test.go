@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// fetchPagesURL is the allowed domain fetchURLService serves from srv.
+const fetchPagesURL = "https://pages.example.com"
+
+// fetchURLService returns a service whose openai provider returns results
+// and a tenant allowed to fetch from pages.example.com, served by srv.
+func fetchURLService(srv *httptest.Server, results ...provider.GenerateResult) (*ChatService, *resultsProvider, context.Context) {
+	openai := &resultsProvider{mockProvider: newMockProvider("openai"), results: results}
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic"), toolTransport: transport}
+	cfg := createTestTenantConfig("openai")
+	cfg.FetchURL = tenant.FetchURLConfig{AllowedDomains: []string{"pages.example.com"}, MaxBytes: 4096}
+	return svc, openai, ctxWithChatPermissionAndTenant("test-client", cfg)
+}
+
+func TestGenerateReply_FetchURL(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "<html><head><title>Hours</title></head><body><h1>Opening hours</h1><p>Mon-Fri 9-5</p><script>x()</script></body></html>")
+	}))
+	defer srv.Close()
+
+	final := provider.GenerateResult{Text: "We open at 9.", ResponseID: "resp-final", Model: "test-model-openai"}
+	svc, openai, ctx := fetchURLService(srv, toolCallResult("fetch_url", `{"url":"`+fetchPagesURL+`/hours"}`), final)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "When do you open?", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Text != "We open at 9." {
+		t.Errorf("Text = %q, want the final reply", resp.Text)
+	}
+	tools := openai.generateCalls[0].Tools
+	if len(tools) != 1 || tools[0].Name != "fetch_url" || !strings.Contains(tools[0].Description, "pages.example.com") {
+		t.Errorf("Tools = %+v, want fetch_url offered", tools)
+	}
+	results := openai.generateCalls[1].ToolResults
+	if len(results) != 1 || results[0].IsError || results[0].Output != "Opening hours\nMon-Fri 9-5" {
+		t.Errorf("ToolResults = %+v, want the page text", results)
+	}
+}
+
+func TestGenerateReply_FetchURLNotOfferedWithWebSearch(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	svc, openai, ctx := fetchURLService(srv, provider.GenerateResult{Text: "hi"})
+
+	if _, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "News?", PreferredProvider: pb.Provider_PROVIDER_OPENAI, EnableWebSearch: true}); err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if tools := openai.generateCalls[0].Tools; len(tools) != 0 {
+		t.Errorf("Tools = %+v, want none with web search", tools)
+	}
+}
+
+func TestFetchURL_Rejections(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, "https://example.com/", http.StatusFound)
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG"))
+		case "/big":
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, strings.Repeat("a", 5000))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, _, ctx := fetchURLService(srv)
+
+	for args, want := range map[string]string{
+		`{"url":"` + fetchPagesURL + `/away"}`:                                 "example.com is not an allowed domain",
+		`{"url":"` + fetchPagesURL + `/image"}`:                                `content type "image/png" is not text`,
+		`{"url":"` + fetchPagesURL + `/big"}`:                                  "page exceeds 4096 bytes",
+		`{"url":"` + fetchPagesURL + `/missing"}`:                              "server returned 404 Not Found",
+		`{"url":"https://example.com/"}`:                                       "example.com is not an allowed domain",
+		`{"url":"` + strings.Replace(fetchPagesURL, "https", "http", 1) + `"}`: "url must be an https URL",
+		`{}`: `arguments must be {"url": ...}`,
+	} {
+		result := svc.callServerTool(ctx, provider.ToolCall{ID: "call-1", Name: "fetch_url", Arguments: args})
+		if !result.IsError || !strings.Contains(result.Output, want) {
+			t.Errorf("%s: got %+v, want error %q", args, result, want)
+		}
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("short", 100); got != "short" {
+		t.Errorf("got %q, want the text unchanged", got)
+	}
+	got := truncateText(strings.Repeat("é", 20), 20)
+	if len(got) > 20 || !strings.HasSuffix(got, "\n[truncated]") || !strings.HasPrefix(got, "éééé") {
+		t.Errorf("got %q, want a cut at a character boundary", got)
+	}
+}
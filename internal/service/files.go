@@ -1,20 +1,29 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
 	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/isolation"
 	"github.com/ai8future/airborne/internal/provider/gemini"
 	"github.com/ai8future/airborne/internal/provider/openai"
 	"github.com/ai8future/airborne/internal/rag"
+	"github.com/ai8future/airborne/internal/validation"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -40,14 +49,54 @@ type FileService struct {
 
 	ragService  *rag.Service
 	rateLimiter *auth.RateLimiter
+	stores      StoreRegistry // Optional; enables store count and storage limits
+	uploads     *uploadSessions
 }
 
 // NewFileService creates a new file service.
-func NewFileService(ragService *rag.Service, rateLimiter *auth.RateLimiter) *FileService {
-	return &FileService{
+func NewFileService(ragService *rag.Service, rateLimiter *auth.RateLimiter, opts ...FileServiceOption) *FileService {
+	s := &FileService{
 		ragService:  ragService,
 		rateLimiter: rateLimiter,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.uploads == nil {
+		s.uploads = newUploadSessions("", 0)
+	}
+	return s
+}
+
+// tenantProxy returns the tenant's outbound proxy, or nil to use the
+// server-wide proxy.
+func tenantProxy(ctx context.Context) *egress.ProxyConfig {
+	if cfg := auth.TenantFromContext(ctx); cfg != nil {
+		return cfg.Proxy
+	}
+	return nil
+}
+
+// checkFileType checks an uploaded file's type against its content and the
+// tenant's allowed types, and returns the MIME type to use for it.
+func checkFileType(ctx context.Context, head []byte, filename, declared string) (string, error) {
+	mimeType, err := validation.CheckUpload(head, filename, declared, uploadLimits(ctx).AllowedTypes)
+	if err != nil {
+		slog.WarnContext(ctx, "upload rejected",
+			"tenant_id", auth.TenantIDFromContext(ctx),
+			"filename", filename,
+			"declared_type", declared,
+			"error", err,
+		)
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return mimeType, nil
+}
+
+// egressContext attributes outbound requests made with ctx to the caller's
+// tenant and providerName for egress auditing.
+func egressContext(ctx context.Context, providerName string) context.Context {
+	return egress.WithAttribution(ctx, auth.TenantIDFromContext(ctx), providerName)
 }
 
 // ensureRAGEnabled returns an error if RAG is not configured.
@@ -71,9 +120,9 @@ func (s *FileService) CreateFileStore(ctx context.Context, req *pb.CreateFileSto
 	// Route by provider
 	switch req.Provider {
 	case pb.Provider_PROVIDER_OPENAI:
-		return s.createOpenAIVectorStore(ctx, req)
+		return s.createOpenAIVectorStore(egressContext(ctx, "openai"), req)
 	case pb.Provider_PROVIDER_GEMINI:
-		return s.createGeminiFileSearchStore(ctx, req)
+		return s.createGeminiFileSearchStore(egressContext(ctx, "gemini"), req)
 	default:
 		return s.createInternalStore(ctx, req)
 	}
@@ -85,6 +134,7 @@ func (s *FileService) createOpenAIVectorStore(ctx context.Context, req *pb.Creat
 		APIKey:         req.Config.GetApiKey(),
 		BaseURL:        req.Config.GetBaseUrl(),
 		ExpirationDays: int(req.ExpirationDays),
+		Proxy:          tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -93,17 +143,14 @@ func (s *FileService) createOpenAIVectorStore(ctx context.Context, req *pb.Creat
 
 	result, err := openai.CreateVectorStore(ctx, cfg, req.Name)
 	if err != nil {
-		slog.Error("failed to create OpenAI vector store",
+		slog.ErrorContext(ctx, "failed to create OpenAI vector store",
 			"name", req.Name,
 			"error", err,
 		)
 		return nil, fmt.Errorf("create OpenAI vector store: %w", err)
 	}
 
-	slog.Info("OpenAI vector store created",
-		"store_id", result.StoreID,
-		"name", result.Name,
-	)
+	accesslog.Annotate(ctx, "store_id", result.StoreID)
 
 	return &pb.CreateFileStoreResponse{
 		StoreId:   result.StoreID,
@@ -118,6 +165,7 @@ func (s *FileService) createGeminiFileSearchStore(ctx context.Context, req *pb.C
 	cfg := gemini.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -126,17 +174,14 @@ func (s *FileService) createGeminiFileSearchStore(ctx context.Context, req *pb.C
 
 	result, err := gemini.CreateFileSearchStore(ctx, cfg, req.Name)
 	if err != nil {
-		slog.Error("failed to create Gemini file search store",
+		slog.ErrorContext(ctx, "failed to create Gemini file search store",
 			"name", req.Name,
 			"error", err,
 		)
 		return nil, fmt.Errorf("create Gemini file search store: %w", err)
 	}
 
-	slog.Info("Gemini file search store created",
-		"store_id", result.StoreID,
-		"name", result.Name,
-	)
+	accesslog.Annotate(ctx, "store_id", result.StoreID)
 
 	return &pb.CreateFileStoreResponse{
 		StoreId:   result.StoreID,
@@ -163,7 +208,7 @@ func (s *FileService) createInternalStore(ctx context.Context, req *pb.CreateFil
 
 	// Create the Qdrant collection via RAG service
 	if err := s.ragService.CreateStore(ctx, tenantID, storeID); err != nil {
-		slog.Error("failed to create file store",
+		slog.ErrorContext(ctx, "failed to create file store",
 			"tenant_id", tenantID,
 			"store_id", storeID,
 			"error", err,
@@ -171,10 +216,7 @@ func (s *FileService) createInternalStore(ctx context.Context, req *pb.CreateFil
 		return nil, fmt.Errorf("create store: %w", err)
 	}
 
-	slog.Info("file store created",
-		"tenant_id", tenantID,
-		"store_id", storeID,
-	)
+	accesslog.Annotate(ctx, "store_id", storeID)
 
 	return &pb.CreateFileStoreResponse{
 		StoreId:   storeID,
@@ -187,8 +229,16 @@ func (s *FileService) createInternalStore(ctx context.Context, req *pb.CreateFil
 // UploadFile uploads a file to a store using client streaming.
 // Routes to appropriate backend based on provider in metadata.
 func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
-	ctx := stream.Context()
+	resp, err := s.receiveUpload(stream.Context(), stream.Recv, nil)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
 
+// receiveUpload receives a streamed upload from recv and stores it,
+// reporting processing to progress if it is non-nil.
+func (s *FileService) receiveUpload(ctx context.Context, recv func() (*pb.UploadFileRequest, error), progress *uploadProgress) (*pb.UploadFileResponse, error) {
 	// Add upload timeout if context doesn't already have a deadline
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		var cancel context.CancelFunc
@@ -198,7 +248,7 @@ func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
 
 	// Check permission
 	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check rate limit for file uploads
@@ -206,38 +256,45 @@ func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
 		client := auth.ClientFromContext(ctx)
 		if client != nil {
 			if err := s.rateLimiter.Allow(ctx, client); err != nil {
-				return status.Error(codes.ResourceExhausted, "file upload rate limit exceeded")
+				return nil, status.Error(codes.ResourceExhausted, "file upload rate limit exceeded")
 			}
 		}
 	}
 
 	// First message should be metadata
-	firstMsg, err := stream.Recv()
+	firstMsg, err := recv()
 	if err != nil {
-		return fmt.Errorf("receive metadata: %w", err)
+		return nil, fmt.Errorf("receive metadata: %w", err)
 	}
 
 	metadata := firstMsg.GetMetadata()
 	if metadata == nil {
-		return fmt.Errorf("first message must contain metadata")
+		return nil, fmt.Errorf("first message must contain metadata")
 	}
 
 	if metadata.StoreId == "" {
-		return fmt.Errorf("store_id is required")
+		return nil, fmt.Errorf("store_id is required")
 	}
 	if metadata.Filename == "" {
-		return fmt.Errorf("filename is required")
+		return nil, fmt.Errorf("filename is required")
+	}
+	if _, err := uploadACL(metadata); err != nil {
+		return nil, err
 	}
 
-	// Validate declared size if provided
-	if metadata.Size > 0 && metadata.Size > maxUploadBytes {
-		return fmt.Errorf("file size %d exceeds maximum allowed size %d bytes", metadata.Size, maxUploadBytes)
+	// Validate declared size if provided, and reject uploads to a full store
+	// before receiving the file
+	maxBytes := maxFileBytes(uploadLimits(ctx))
+	if metadata.Size > 0 && metadata.Size > maxBytes {
+		return nil, &QuotaError{Limit: QuotaFileBytes, Max: maxBytes, Requested: metadata.Size}
+	}
+	if err := s.checkStoreQuota(ctx, metadata.StoreId, max(metadata.Size, 0)); err != nil {
+		return nil, err
 	}
 
-	slog.Info("starting file upload",
+	accesslog.Annotate(ctx,
 		"store_id", metadata.StoreId,
 		"filename", metadata.Filename,
-		"size", metadata.Size,
 		"provider", metadata.Provider.String(),
 	)
 
@@ -245,7 +302,7 @@ func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
 	// SECURITY: Use a temporary file instead of bytes.Buffer to prevent memory exhaustion (DoS)
 	tmpFile, err := os.CreateTemp("", "airborne-upload-*.tmp")
 	if err != nil {
-		return status.Error(codes.Internal, "failed to create temporary file for upload")
+		return nil, status.Error(codes.Internal, "failed to create temporary file for upload")
 	}
 	defer os.Remove(tmpFile.Name())
 	defer tmpFile.Close()
@@ -255,16 +312,16 @@ func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
 		// Check for context cancellation (timeout)
 		select {
 		case <-ctx.Done():
-			return status.Error(codes.DeadlineExceeded, "upload timeout exceeded")
+			return nil, status.Error(codes.DeadlineExceeded, "upload timeout exceeded")
 		default:
 		}
 
-		msg, err := stream.Recv()
+		msg, err := recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("receive chunk: %w", err)
+			return nil, fmt.Errorf("receive chunk: %w", err)
 		}
 
 		chunk := msg.GetChunk()
@@ -274,115 +331,163 @@ func (s *FileService) UploadFile(stream pb.FileService_UploadFileServer) error {
 
 		// Enforce size limit
 		totalBytes += int64(len(chunk))
-		if totalBytes > maxUploadBytes {
-			return fmt.Errorf("file exceeds maximum allowed size %d bytes", maxUploadBytes)
+		if totalBytes > maxBytes {
+			return nil, &QuotaError{Limit: QuotaFileBytes, Max: maxBytes, Requested: totalBytes}
 		}
 
 		if _, err := tmpFile.Write(chunk); err != nil {
-			return fmt.Errorf("write to temp file: %w", err)
+			return nil, fmt.Errorf("write to temp file: %w", err)
+		}
+	}
+
+	return s.storeUpload(ctx, metadata, tmpFile, totalBytes, progress)
+}
+
+// storeUpload checks a received file's type and the tenant's storage limit,
+// then adds it to metadata's store, reporting progress if it is non-nil.
+func (s *FileService) storeUpload(ctx context.Context, metadata *pb.UploadFileMetadata, file *os.File, size int64, progress *uploadProgress) (*pb.UploadFileResponse, error) {
+	progress.received(size)
+	resp, err := s.routeUpload(ctx, metadata, file, size, progress)
+	if err != nil {
+		progress.fail()
+		return nil, err
+	}
+	progress.finish(resp)
+	return resp, nil
+}
+
+// routeUpload checks and adds a received file to the provider's store.
+func (s *FileService) routeUpload(ctx context.Context, metadata *pb.UploadFileMetadata, file *os.File, size int64, progress *uploadProgress) (*pb.UploadFileResponse, error) {
+	// Check the file type against its content and the tenant's allowlist
+	head := make([]byte, validation.SniffLen)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read temp file: %w", err)
+	}
+	mimeType, err := checkFileType(ctx, head[:n], metadata.Filename, metadata.MimeType)
+	if err != nil {
+		return nil, err
+	}
+	metadata.MimeType = mimeType
+
+	// Recheck storage when the file is larger than declared
+	if size > metadata.Size {
+		if err := s.checkStoreQuota(ctx, metadata.StoreId, size); err != nil {
+			return nil, err
 		}
 	}
 
 	// Reset file pointer to beginning for reading
-	if _, err := tmpFile.Seek(0, 0); err != nil {
-		return fmt.Errorf("seek temp file: %w", err)
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("seek temp file: %w", err)
+	}
+
+	mirror, err := s.mirrorRequested(ctx, metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	// Route by provider
+	var resp *pb.UploadFileResponse
 	switch metadata.Provider {
 	case pb.Provider_PROVIDER_OPENAI:
-		return s.uploadToOpenAI(ctx, stream, metadata, tmpFile)
+		resp, err = s.uploadToOpenAI(egressContext(ctx, "openai"), metadata, file, size, progress)
 	case pb.Provider_PROVIDER_GEMINI:
-		return s.uploadToGemini(ctx, stream, metadata, tmpFile)
+		resp, err = s.uploadToGemini(egressContext(ctx, "gemini"), metadata, file, size, progress)
 	default:
-		return s.uploadToInternal(ctx, stream, metadata, tmpFile)
+		return s.uploadToInternal(ctx, metadata, file, size, progress)
+	}
+	if err != nil || !mirror || resp.Status == UploadStageFailed {
+		return resp, err
 	}
+	resp.MirrorStatus = s.mirrorToInternal(ctx, metadata, file, resp.FileId, progress)
+	return resp, nil
 }
 
 // uploadToOpenAI uploads a file to an OpenAI Vector Store.
-func (s *FileService) uploadToOpenAI(ctx context.Context, stream pb.FileService_UploadFileServer, metadata *pb.UploadFileMetadata, content io.Reader) error {
+func (s *FileService) uploadToOpenAI(ctx context.Context, metadata *pb.UploadFileMetadata, content io.Reader, size int64, progress *uploadProgress) (*pb.UploadFileResponse, error) {
 	cfg := openai.FileStoreConfig{
-		APIKey:  metadata.Config.GetApiKey(),
-		BaseURL: metadata.Config.GetBaseUrl(),
+		APIKey:   metadata.Config.GetApiKey(),
+		BaseURL:  metadata.Config.GetBaseUrl(),
+		Proxy:    tenantProxy(ctx),
+		OnStatus: progress.providerStatus(),
 	}
 
 	if cfg.APIKey == "" {
-		return status.Error(codes.InvalidArgument, "OpenAI API key is required")
+		return nil, status.Error(codes.InvalidArgument, "OpenAI API key is required")
 	}
 
+	progress.report(UploadStageUploading, "")
 	result, err := openai.UploadFileToVectorStore(ctx, cfg, metadata.StoreId, metadata.Filename, content)
 	if err != nil {
-		slog.Error("failed to upload to OpenAI vector store",
+		slog.ErrorContext(ctx, "failed to upload to OpenAI vector store",
 			"store_id", metadata.StoreId,
 			"filename", metadata.Filename,
 			"error", err,
 		)
-		return stream.SendAndClose(&pb.UploadFileResponse{
+		return &pb.UploadFileResponse{
 			FileId:   "",
 			Filename: metadata.Filename,
 			StoreId:  metadata.StoreId,
 			Status:   "failed",
-		})
+		}, nil
 	}
 
-	slog.Info("file uploaded to OpenAI vector store",
-		"store_id", metadata.StoreId,
-		"filename", metadata.Filename,
-		"file_id", result.FileID,
-	)
+	accesslog.Annotate(ctx, "file_id", result.FileID)
+	s.recordUpload(ctx, metadata.StoreId, "openai", size)
 
-	return stream.SendAndClose(&pb.UploadFileResponse{
+	return &pb.UploadFileResponse{
 		FileId:   result.FileID,
 		Filename: result.Filename,
 		StoreId:  result.StoreID,
 		Status:   result.Status,
-	})
+	}, nil
 }
 
 // uploadToGemini uploads a file to a Gemini FileSearchStore.
-func (s *FileService) uploadToGemini(ctx context.Context, stream pb.FileService_UploadFileServer, metadata *pb.UploadFileMetadata, content io.Reader) error {
+func (s *FileService) uploadToGemini(ctx context.Context, metadata *pb.UploadFileMetadata, content io.Reader, size int64, progress *uploadProgress) (*pb.UploadFileResponse, error) {
 	cfg := gemini.FileStoreConfig{
-		APIKey:  metadata.Config.GetApiKey(),
-		BaseURL: metadata.Config.GetBaseUrl(),
+		APIKey:   metadata.Config.GetApiKey(),
+		BaseURL:  metadata.Config.GetBaseUrl(),
+		Proxy:    tenantProxy(ctx),
+		OnStatus: progress.providerStatus(),
 	}
 
 	if cfg.APIKey == "" {
-		return status.Error(codes.InvalidArgument, "Gemini API key is required")
+		return nil, status.Error(codes.InvalidArgument, "Gemini API key is required")
 	}
 
+	progress.report(UploadStageUploading, "")
 	result, err := gemini.UploadFileToFileSearchStore(ctx, cfg, metadata.StoreId, metadata.Filename, metadata.MimeType, content)
 	if err != nil {
-		slog.Error("failed to upload to Gemini file search store",
+		slog.ErrorContext(ctx, "failed to upload to Gemini file search store",
 			"store_id", metadata.StoreId,
 			"filename", metadata.Filename,
 			"error", err,
 		)
-		return stream.SendAndClose(&pb.UploadFileResponse{
+		return &pb.UploadFileResponse{
 			FileId:   "",
 			Filename: metadata.Filename,
 			StoreId:  metadata.StoreId,
 			Status:   "failed",
-		})
+		}, nil
 	}
 
-	slog.Info("file uploaded to Gemini file search store",
-		"store_id", metadata.StoreId,
-		"filename", metadata.Filename,
-		"file_id", result.FileID,
-	)
+	accesslog.Annotate(ctx, "file_id", result.FileID)
+	s.recordUpload(ctx, metadata.StoreId, "gemini", size)
 
-	return stream.SendAndClose(&pb.UploadFileResponse{
+	return &pb.UploadFileResponse{
 		FileId:   result.FileID,
 		Filename: result.Filename,
 		StoreId:  result.StoreID,
 		Status:   result.Status,
-	})
+	}, nil
 }
 
 // uploadToInternal uploads a file to the internal Qdrant store.
-func (s *FileService) uploadToInternal(ctx context.Context, stream pb.FileService_UploadFileServer, metadata *pb.UploadFileMetadata, content io.Reader) error {
+func (s *FileService) uploadToInternal(ctx context.Context, metadata *pb.UploadFileMetadata, content io.Reader, size int64, progress *uploadProgress) (*pb.UploadFileResponse, error) {
 	if err := s.ensureRAGEnabled(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get tenant ID from auth context
@@ -391,10 +496,16 @@ func (s *FileService) uploadToInternal(ctx context.Context, stream pb.FileServic
 	// Generate unique file ID
 	fileID, err := generateFileID()
 	if err != nil {
-		return fmt.Errorf("generate file id: %w", err)
+		return nil, fmt.Errorf("generate file id: %w", err)
+	}
+
+	acl, err := uploadACL(metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	// Ingest the file via RAG service
+	progress.report(UploadStageProcessing, "")
 	result, err := s.ragService.Ingest(ctx, rag.IngestParams{
 		StoreID:  metadata.StoreId,
 		TenantID: tenantID,
@@ -402,34 +513,42 @@ func (s *FileService) uploadToInternal(ctx context.Context, stream pb.FileServic
 		Filename: metadata.Filename,
 		MIMEType: metadata.MimeType,
 		FileID:   fileID,
+		ACL:      acl,
 	})
 	if err != nil {
-		slog.Error("failed to ingest file",
+		slog.ErrorContext(ctx, "failed to ingest file",
 			"store_id", metadata.StoreId,
 			"filename", metadata.Filename,
 			"error", err,
 		)
-		return stream.SendAndClose(&pb.UploadFileResponse{
+		return &pb.UploadFileResponse{
 			FileId:   "",
 			Filename: metadata.Filename,
 			StoreId:  metadata.StoreId,
 			Status:   "failed",
-		})
+		}, nil
 	}
 
-	slog.Info("file uploaded and indexed",
-		"store_id", metadata.StoreId,
-		"filename", metadata.Filename,
-		"file_id", fileID,
-		"chunks", result.ChunkCount,
-	)
+	if result.Duplicate {
+		// A retried or repeated upload; point the caller at the stored copy
+		fileID = result.FileID
+		slog.InfoContext(ctx, "skipped duplicate file upload",
+			"store_id", metadata.StoreId,
+			"filename", metadata.Filename,
+			"file_id", fileID,
+		)
+	} else {
+		s.recordUpload(ctx, metadata.StoreId, "internal", size)
+	}
+	accesslog.Annotate(ctx, "file_id", fileID, "chunks", result.ChunkCount, "duplicate", result.Duplicate)
 
-	return stream.SendAndClose(&pb.UploadFileResponse{
-		FileId:   fileID,
-		Filename: metadata.Filename,
-		StoreId:  metadata.StoreId,
-		Status:   "ready",
-	})
+	return &pb.UploadFileResponse{
+		FileId:    fileID,
+		Filename:  metadata.Filename,
+		StoreId:   metadata.StoreId,
+		Status:    "ready",
+		Duplicate: result.Duplicate,
+	}, nil
 }
 
 // DeleteFileStore deletes a store and all its contents.
@@ -445,14 +564,20 @@ func (s *FileService) DeleteFileStore(ctx context.Context, req *pb.DeleteFileSto
 	}
 
 	// Route by provider
+	var resp *pb.DeleteFileStoreResponse
+	var err error
 	switch req.Provider {
 	case pb.Provider_PROVIDER_OPENAI:
-		return s.deleteOpenAIVectorStore(ctx, req)
+		resp, err = s.deleteOpenAIVectorStore(egressContext(ctx, "openai"), req)
 	case pb.Provider_PROVIDER_GEMINI:
-		return s.deleteGeminiFileSearchStore(ctx, req)
+		resp, err = s.deleteGeminiFileSearchStore(egressContext(ctx, "gemini"), req)
 	default:
-		return s.deleteInternalStore(ctx, req)
+		resp, err = s.deleteInternalStore(ctx, req)
+	}
+	if err == nil && resp.GetSuccess() {
+		s.forgetStore(ctx, req.StoreId)
 	}
+	return resp, err
 }
 
 // deleteOpenAIVectorStore deletes an OpenAI Vector Store.
@@ -460,6 +585,7 @@ func (s *FileService) deleteOpenAIVectorStore(ctx context.Context, req *pb.Delet
 	cfg := openai.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -467,7 +593,7 @@ func (s *FileService) deleteOpenAIVectorStore(ctx context.Context, req *pb.Delet
 	}
 
 	if err := openai.DeleteVectorStore(ctx, cfg, req.StoreId); err != nil {
-		slog.Error("failed to delete OpenAI vector store",
+		slog.ErrorContext(ctx, "failed to delete OpenAI vector store",
 			"store_id", req.StoreId,
 			"error", err,
 		)
@@ -477,7 +603,7 @@ func (s *FileService) deleteOpenAIVectorStore(ctx context.Context, req *pb.Delet
 		}, nil
 	}
 
-	slog.Info("OpenAI vector store deleted", "store_id", req.StoreId)
+	accesslog.Annotate(ctx, "store_id", req.StoreId)
 
 	return &pb.DeleteFileStoreResponse{
 		Success: true,
@@ -490,6 +616,7 @@ func (s *FileService) deleteGeminiFileSearchStore(ctx context.Context, req *pb.D
 	cfg := gemini.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -497,7 +624,7 @@ func (s *FileService) deleteGeminiFileSearchStore(ctx context.Context, req *pb.D
 	}
 
 	if err := gemini.DeleteFileSearchStore(ctx, cfg, req.StoreId, req.Force); err != nil {
-		slog.Error("failed to delete Gemini file search store",
+		slog.ErrorContext(ctx, "failed to delete Gemini file search store",
 			"store_id", req.StoreId,
 			"error", err,
 		)
@@ -507,7 +634,7 @@ func (s *FileService) deleteGeminiFileSearchStore(ctx context.Context, req *pb.D
 		}, nil
 	}
 
-	slog.Info("Gemini file search store deleted", "store_id", req.StoreId)
+	accesslog.Annotate(ctx, "store_id", req.StoreId)
 
 	return &pb.DeleteFileStoreResponse{
 		Success: true,
@@ -525,7 +652,7 @@ func (s *FileService) deleteInternalStore(ctx context.Context, req *pb.DeleteFil
 	tenantID := auth.TenantIDFromContext(ctx)
 
 	if err := s.ragService.DeleteStore(ctx, tenantID, req.StoreId); err != nil {
-		slog.Error("failed to delete file store",
+		slog.ErrorContext(ctx, "failed to delete file store",
 			"store_id", req.StoreId,
 			"error", err,
 		)
@@ -535,7 +662,7 @@ func (s *FileService) deleteInternalStore(ctx context.Context, req *pb.DeleteFil
 		}, nil
 	}
 
-	slog.Info("file store deleted", "store_id", req.StoreId)
+	accesslog.Annotate(ctx, "store_id", req.StoreId)
 
 	return &pb.DeleteFileStoreResponse{
 		Success: true,
@@ -558,9 +685,9 @@ func (s *FileService) GetFileStore(ctx context.Context, req *pb.GetFileStoreRequ
 	// Route by provider
 	switch req.Provider {
 	case pb.Provider_PROVIDER_OPENAI:
-		return s.getOpenAIVectorStore(ctx, req)
+		return s.getOpenAIVectorStore(egressContext(ctx, "openai"), req)
 	case pb.Provider_PROVIDER_GEMINI:
-		return s.getGeminiFileSearchStore(ctx, req)
+		return s.getGeminiFileSearchStore(egressContext(ctx, "gemini"), req)
 	default:
 		return s.getInternalStore(ctx, req)
 	}
@@ -571,6 +698,7 @@ func (s *FileService) getOpenAIVectorStore(ctx context.Context, req *pb.GetFileS
 	cfg := openai.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -597,6 +725,7 @@ func (s *FileService) getGeminiFileSearchStore(ctx context.Context, req *pb.GetF
 	cfg := gemini.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -657,9 +786,9 @@ func (s *FileService) ListFileStores(ctx context.Context, req *pb.ListFileStores
 	// Route by provider
 	switch req.Provider {
 	case pb.Provider_PROVIDER_OPENAI:
-		return s.listOpenAIVectorStores(ctx, req)
+		return s.listOpenAIVectorStores(egressContext(ctx, "openai"), req)
 	case pb.Provider_PROVIDER_GEMINI:
-		return s.listGeminiFileSearchStores(ctx, req)
+		return s.listGeminiFileSearchStores(egressContext(ctx, "gemini"), req)
 	default:
 		return nil, status.Error(codes.Unimplemented, "ListFileStores not yet implemented for internal stores")
 	}
@@ -670,6 +799,7 @@ func (s *FileService) listOpenAIVectorStores(ctx context.Context, req *pb.ListFi
 	cfg := openai.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -703,6 +833,7 @@ func (s *FileService) listGeminiFileSearchStores(ctx context.Context, req *pb.Li
 	cfg := gemini.FileStoreConfig{
 		APIKey:  req.Config.GetApiKey(),
 		BaseURL: req.Config.GetBaseUrl(),
+		Proxy:   tenantProxy(ctx),
 	}
 
 	if cfg.APIKey == "" {
@@ -730,3 +861,368 @@ func (s *FileService) listGeminiFileSearchStores(ctx context.Context, req *pb.Li
 		Stores: stores,
 	}, nil
 }
+
+// maxRetrieveTopK bounds the number of chunks one Retrieve call returns.
+const maxRetrieveTopK = 100
+
+// Retrieve runs a similarity search against an internal store and returns
+// the matching chunks with their scores, so callers can build their own
+// prompts or related-document features without going through chat.
+func (s *FileService) Retrieve(ctx context.Context, req *pb.RetrieveRequest) (*pb.RetrieveResponse, error) {
+	// Check permission
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRAGEnabled(); err != nil {
+		return nil, err
+	}
+
+	if req.StoreId == "" {
+		return nil, status.Error(codes.InvalidArgument, "store_id is required")
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if len(req.Query) > maxEmbedTextBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "query exceeds %d bytes", maxEmbedTextBytes)
+	}
+	if req.TopK < 0 || req.TopK > maxRetrieveTopK {
+		return nil, status.Errorf(codes.InvalidArgument, "top_k must be between 0 and %d", maxRetrieveTopK)
+	}
+
+	tenantID := auth.TenantIDFromContext(ctx)
+	accesslog.Annotate(ctx, "store_id", req.StoreId)
+
+	results, err := s.ragService.Retrieve(ctx, rag.RetrieveParams{
+		StoreID:  req.StoreId,
+		TenantID: tenantID,
+		Query:    req.Query,
+		TopK:     int(req.TopK),
+		ThreadID: req.Filter.GetThreadId(),
+		FileID:   req.Filter.GetFileId(),
+		Filename: req.Filter.GetFilename(),
+		MinScore: req.Filter.GetMinScore(),
+		Viewer:   ragViewer(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, rag.ErrInvalidCollectionName) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, isolation.ErrCrossTenant) {
+			return nil, status.Error(codes.PermissionDenied, "store belongs to another tenant")
+		}
+		slog.ErrorContext(ctx, "retrieve failed", "tenant_id", tenantID, "store_id", req.StoreId, "error", err)
+		return nil, status.Error(codes.Internal, "retrieval failed")
+	}
+
+	chunks := make([]*pb.RetrievedChunk, len(results))
+	for i, r := range results {
+		chunks[i] = &pb.RetrievedChunk{
+			Text:       r.Text,
+			Score:      r.Score,
+			FileId:     r.FileID,
+			Filename:   r.Filename,
+			ThreadId:   r.ThreadID,
+			ChunkIndex: int32(r.ChunkIndex),
+			CharStart:  int32(r.Start),
+			CharEnd:    int32(r.End),
+		}
+	}
+	accesslog.Annotate(ctx, "chunks", len(chunks))
+
+	return &pb.RetrieveResponse{Chunks: chunks}, nil
+}
+
+// ExtractText runs the RAG extractor over a file and returns the text and
+// structure it finds without ingesting anything, so extraction quality can
+// be checked before the file is uploaded to a store.
+func (s *FileService) ExtractText(ctx context.Context, req *pb.ExtractTextRequest) (*pb.ExtractTextResponse, error) {
+	// Check permission
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRAGEnabled(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Filename) == "" {
+		return nil, status.Error(codes.InvalidArgument, "filename is required")
+	}
+	if len(req.Content) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+	if int64(len(req.Content)) > maxUploadBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "file exceeds maximum allowed size %d bytes", maxUploadBytes)
+	}
+	if req.MaxTextChars < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_text_chars must not be negative")
+	}
+
+	mimeType, err := checkFileType(ctx, req.Content[:min(len(req.Content), validation.SniffLen)], req.Filename, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID := auth.TenantIDFromContext(ctx)
+	accesslog.Annotate(ctx, "filename", req.Filename)
+
+	preview, err := s.ragService.PreviewExtraction(ctx, bytes.NewReader(req.Content), req.Filename, mimeType)
+	if err != nil {
+		slog.WarnContext(ctx, "text extraction preview failed", "tenant_id", tenantID, "filename", req.Filename, "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "text extraction failed: %v", err)
+	}
+
+	resp := &pb.ExtractTextResponse{
+		Text:       preview.Text,
+		PageCount:  int32(preview.PageCount),
+		CharCount:  int32(utf8.RuneCountInString(preview.Text)),
+		WordCount:  int32(len(strings.Fields(preview.Text))),
+		ChunkCount: int32(preview.ChunkCount),
+		Structure: &pb.DocumentStructure{
+			Paragraphs: int32(preview.Structure.Paragraphs),
+			ListItems:  int32(preview.Structure.ListItems),
+			Tables:     int32(preview.Structure.Tables),
+		},
+	}
+	resp.Format, _ = preview.Metadata["format"].(string)
+	resp.Fallback, _ = preview.Metadata["fallback"].(bool)
+	for _, h := range preview.Structure.Headings {
+		resp.Structure.Headings = append(resp.Structure.Headings, &pb.DocumentHeading{
+			Level:  int32(h.Level),
+			Text:   h.Text,
+			Offset: int32(h.Offset),
+		})
+	}
+	if req.MaxTextChars > 0 && resp.CharCount > req.MaxTextChars {
+		resp.Text = string([]rune(preview.Text)[:req.MaxTextChars])
+		resp.Truncated = true
+	}
+	accesslog.Annotate(ctx, "chars", resp.CharCount)
+
+	return resp, nil
+}
+
+// StartReindex re-embeds an internal store with the current embedding model.
+// The job runs in the background; poll GetReindexStatus for progress.
+func (s *FileService) StartReindex(ctx context.Context, req *pb.StartReindexRequest) (*pb.ReindexStatus, error) {
+	// Re-indexing is a maintenance operation that blocks uploads to the store
+	if err := auth.RequirePermission(ctx, auth.PermissionAdmin); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRAGEnabled(); err != nil {
+		return nil, err
+	}
+	if req.StoreId == "" {
+		return nil, status.Error(codes.InvalidArgument, "store_id is required")
+	}
+
+	tenantID := auth.TenantIDFromContext(ctx)
+	accesslog.Annotate(ctx, "store_id", req.StoreId)
+
+	job, err := s.ragService.StartReindex(ctx, tenantID, req.StoreId)
+	if err != nil {
+		return nil, reindexError(err, tenantID, req.StoreId)
+	}
+	return reindexStatusToProto(req.StoreId, job), nil
+}
+
+// GetReindexStatus reports a store's re-index progress and whether its
+// vectors match the current embedding model.
+func (s *FileService) GetReindexStatus(ctx context.Context, req *pb.GetReindexStatusRequest) (*pb.ReindexStatus, error) {
+	// Check permission
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRAGEnabled(); err != nil {
+		return nil, err
+	}
+	if req.StoreId == "" {
+		return nil, status.Error(codes.InvalidArgument, "store_id is required")
+	}
+
+	tenantID := auth.TenantIDFromContext(ctx)
+	job, err := s.ragService.ReindexStatus(ctx, tenantID, req.StoreId)
+	if err != nil {
+		return nil, reindexError(err, tenantID, req.StoreId)
+	}
+	return reindexStatusToProto(req.StoreId, job), nil
+}
+
+// reindexError maps re-index errors to gRPC status errors.
+func reindexError(err error, tenantID, storeID string) error {
+	switch {
+	case errors.Is(err, rag.ErrInvalidCollectionName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, rag.ErrStoreNotFound):
+		return status.Error(codes.NotFound, "store not found")
+	case errors.Is(err, rag.ErrReindexInProgress):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, isolation.ErrCrossTenant):
+		return status.Error(codes.PermissionDenied, "store belongs to another tenant")
+	}
+	slog.Error("re-index request failed", "tenant_id", tenantID, "store_id", storeID, "error", err)
+	return status.Error(codes.Internal, "re-index failed")
+}
+
+// reindexStatusToProto converts a re-index job status to its proto form.
+func reindexStatusToProto(storeID string, job *rag.ReindexStatus) *pb.ReindexStatus {
+	resp := &pb.ReindexStatus{
+		StoreId:         storeID,
+		State:           job.State,
+		Model:           job.Model,
+		TotalChunks:     int32(job.TotalChunks),
+		ReindexedChunks: int32(job.ReindexedChunks),
+		Error:           job.Error,
+		StoredModel:     job.StoredModel,
+		NeedsReindex:    job.NeedsReindex,
+	}
+	if !job.StartedAt.IsZero() {
+		resp.StartedAt = job.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if !job.FinishedAt.IsZero() {
+		resp.FinishedAt = job.FinishedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
+
+// exportChunkBytes is the size of the archive pieces ExportStore streams.
+const exportChunkBytes = 64 * 1024
+
+// ExportStore streams a portable snapshot archive of an internal store, so
+// a curated store can be promoted to another environment or tenant without
+// re-uploading its source documents.
+func (s *FileService) ExportStore(req *pb.ExportStoreRequest, stream pb.FileService_ExportStoreServer) error {
+	ctx := stream.Context()
+
+	// Snapshots hold every chunk in the store
+	if err := auth.RequirePermission(ctx, auth.PermissionAdmin); err != nil {
+		return err
+	}
+	if err := s.ensureRAGEnabled(); err != nil {
+		return err
+	}
+	if req.StoreId == "" {
+		return status.Error(codes.InvalidArgument, "store_id is required")
+	}
+
+	tenantID := auth.TenantIDFromContext(ctx)
+	accesslog.Annotate(ctx, "store_id", req.StoreId)
+
+	w := bufio.NewWriterSize(chunkWriterFunc(func(p []byte) error {
+		return stream.Send(&pb.ExportStoreChunk{Data: p})
+	}), exportChunkBytes)
+	manifest, err := s.ragService.ExportStore(ctx, w, rag.ExportParams{
+		TenantID:       tenantID,
+		StoreID:        req.StoreId,
+		IncludeVectors: req.IncludeVectors,
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return snapshotError(err, tenantID, req.StoreId)
+	}
+	accesslog.Annotate(ctx, "chunks", manifest.ChunkCount)
+	return nil
+}
+
+// ImportStore loads a snapshot archive streamed by the client into an
+// internal store of the caller's tenant.
+func (s *FileService) ImportStore(stream pb.FileService_ImportStoreServer) error {
+	ctx := stream.Context()
+
+	// Add upload timeout if context doesn't already have a deadline
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uploadTimeout)
+		defer cancel()
+	}
+
+	// Imports can replace a whole store
+	if err := auth.RequirePermission(ctx, auth.PermissionAdmin); err != nil {
+		return err
+	}
+	if err := s.ensureRAGEnabled(); err != nil {
+		return err
+	}
+
+	// First message should be metadata
+	firstMsg, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("receive metadata: %w", err)
+	}
+	metadata := firstMsg.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "first message must contain metadata")
+	}
+
+	tenantID := auth.TenantIDFromContext(ctx)
+	result, err := s.ragService.ImportStore(ctx, &importStreamReader{stream: stream}, rag.ImportParams{
+		TenantID:  tenantID,
+		StoreID:   metadata.StoreId,
+		Overwrite: metadata.Overwrite,
+	})
+	if err != nil {
+		return snapshotError(err, tenantID, metadata.StoreId)
+	}
+	accesslog.Annotate(ctx, "store_id", result.StoreID, "chunks", result.ChunkCount, "reembedded", result.Reembedded)
+
+	return stream.SendAndClose(&pb.ImportStoreResponse{
+		StoreId:       result.StoreID,
+		ChunkCount:    int32(result.ChunkCount),
+		Reembedded:    result.Reembedded,
+		SourceStoreId: result.Source.StoreID,
+		SourceModel:   result.Source.EmbeddingModel,
+		ExportedAt:    result.Source.ExportedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// snapshotError maps store export and import errors to gRPC status errors.
+func snapshotError(err error, tenantID, storeID string) error {
+	switch {
+	case errors.Is(err, rag.ErrInvalidCollectionName), errors.Is(err, rag.ErrInvalidSnapshot):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, rag.ErrStoreNotFound):
+		return status.Error(codes.NotFound, "store not found")
+	case errors.Is(err, rag.ErrStoreExists):
+		return status.Error(codes.AlreadyExists, "store already exists; set overwrite to replace it")
+	case errors.Is(err, rag.ErrReindexInProgress):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, isolation.ErrCrossTenant):
+		return status.Error(codes.PermissionDenied, "store belongs to another tenant")
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Err()
+	}
+	slog.Error("store snapshot failed", "tenant_id", tenantID, "store_id", storeID, "error", err)
+	return status.Error(codes.Internal, "store snapshot failed")
+}
+
+// chunkWriterFunc adapts a send function to io.Writer.
+type chunkWriterFunc func(p []byte) error
+
+func (f chunkWriterFunc) Write(p []byte) (int, error) {
+	if err := f(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// importStreamReader reads archive data from an ImportStore stream.
+type importStreamReader struct {
+	stream pb.FileService_ImportStoreServer
+	buf    []byte
+}
+
+func (r *importStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = msg.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
 	"github.com/ai8future/airborne/internal/auth"
@@ -364,7 +366,7 @@ func TestFileService_UploadFile_Success(t *testing.T) {
 			},
 			{
 				Data: &pb.UploadFileRequest_Chunk{
-					Chunk: []byte("fake pdf content"),
+					Chunk: []byte("%PDF-1.4 fake pdf content"),
 				},
 			},
 		},
@@ -389,6 +391,38 @@ func TestFileService_UploadFile_Success(t *testing.T) {
 	}
 }
 
+func TestFileService_UploadFile_Duplicate(t *testing.T) {
+	mockStore := testutil.NewMockStore()
+	svc := NewFileService(createRAGServiceWithMocks(mockStore, nil, nil), nil)
+
+	upload := func() *pb.UploadFileResponse {
+		t.Helper()
+		stream := &mockUploadFileServer{
+			ctx: ctxWithFilePermission("tenant1"),
+			messages: []*pb.UploadFileRequest{
+				{Data: &pb.UploadFileRequest_Metadata{Metadata: &pb.UploadFileMetadata{StoreId: "test-store", Filename: "document.pdf"}}},
+				{Data: &pb.UploadFileRequest_Chunk{Chunk: []byte("%PDF-1.4 fake pdf content")}},
+			},
+		}
+		if err := svc.UploadFile(stream); err != nil {
+			t.Fatalf("UploadFile failed: %v", err)
+		}
+		return stream.response
+	}
+
+	first := upload()
+	retry := upload()
+	if first.Duplicate || !retry.Duplicate {
+		t.Errorf("expected only the retry to be a duplicate: first=%v retry=%v", first.Duplicate, retry.Duplicate)
+	}
+	if retry.FileId != first.FileId || retry.Status != "ready" {
+		t.Errorf("expected retry to return file %s, got %s (%s)", first.FileId, retry.FileId, retry.Status)
+	}
+	if len(mockStore.UpsertCalls) != 1 {
+		t.Errorf("expected one upsert, got %d", len(mockStore.UpsertCalls))
+	}
+}
+
 func TestFileService_UploadFile_MissingMetadata(t *testing.T) {
 	mockRAG := createMockRAGService()
 	svc := NewFileService(mockRAG, nil)
@@ -487,7 +521,7 @@ func TestFileService_UploadFile_MultipleChunks(t *testing.T) {
 			},
 			{
 				Data: &pb.UploadFileRequest_Chunk{
-					Chunk: []byte("chunk1-"),
+					Chunk: []byte("%PDF-1.4 chunk1-"),
 				},
 			},
 			{
@@ -540,7 +574,7 @@ func TestFileService_UploadFile_IngestError(t *testing.T) {
 			},
 			{
 				Data: &pb.UploadFileRequest_Chunk{
-					Chunk: []byte("content"),
+					Chunk: []byte("%PDF-1.4 content"),
 				},
 			},
 		},
@@ -717,6 +751,151 @@ func TestFileService_AuthRequired(t *testing.T) {
 	if err == nil {
 		t.Error("ListFileStores: expected auth error")
 	}
+
+	// Test Retrieve without auth
+	_, err = svc.Retrieve(context.Background(), &pb.RetrieveRequest{StoreId: "test-store", Query: "q"})
+	if err == nil {
+		t.Error("Retrieve: expected auth error")
+	}
+}
+
+func TestFileService_Retrieve_Success(t *testing.T) {
+	mockStore := testutil.NewMockStore()
+	mockStore.CreateCollection(context.Background(), "tenant1_test-store", 768)
+	mockStore.Upsert(context.Background(), "tenant1_test-store", []vectorstore.Point{
+		{ID: "1", Vector: make([]float32, 768), Payload: map[string]any{
+			"text": "The quarterly revenue grew 12%.", "filename": "report.pdf", "file_id": "file_1",
+			"thread_id": "thread_9", "chunk_index": 3, "char_start": 1200, "char_end": 1231,
+		}},
+	})
+
+	svc := NewFileService(createRAGServiceWithMocks(mockStore, nil, nil), nil)
+	resp, err := svc.Retrieve(ctxWithFilePermission("tenant1"), &pb.RetrieveRequest{
+		StoreId: "test-store",
+		Query:   "revenue growth",
+		TopK:    5,
+		Filter:  &pb.RetrieveFilter{FileId: "file_1", MinScore: 0.3},
+	})
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(resp.Chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(resp.Chunks))
+	}
+	c := resp.Chunks[0]
+	if c.Text != "The quarterly revenue grew 12%." || c.FileId != "file_1" || c.ThreadId != "thread_9" ||
+		c.ChunkIndex != 3 || c.CharStart != 1200 || c.CharEnd != 1231 || c.Score == 0 {
+		t.Errorf("unexpected chunk: %+v", c)
+	}
+
+	search := mockStore.SearchCalls[0]
+	if search.Limit != 5 || search.ScoreThreshold != 0.3 || search.Filter == nil || search.Filter.Must[0].Match != "file_1" {
+		t.Errorf("unexpected search params: %+v", search)
+	}
+}
+
+func TestFileService_Retrieve_InvalidArguments(t *testing.T) {
+	svc := NewFileService(createMockRAGService(), nil)
+	ctx := ctxWithFilePermission("tenant1")
+
+	tests := []struct {
+		name string
+		req  *pb.RetrieveRequest
+	}{
+		{"missing store", &pb.RetrieveRequest{Query: "q"}},
+		{"missing query", &pb.RetrieveRequest{StoreId: "s", Query: "  "}},
+		{"top_k too large", &pb.RetrieveRequest{StoreId: "s", Query: "q", TopK: maxRetrieveTopK + 1}},
+		{"invalid store id", &pb.RetrieveRequest{StoreId: "../other", Query: "q"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Retrieve(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("err = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestFileService_Retrieve_RequiresRAG(t *testing.T) {
+	svc := NewFileService(nil, nil)
+	_, err := svc.Retrieve(ctxWithFilePermission("tenant1"), &pb.RetrieveRequest{StoreId: "s", Query: "q"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("err = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestFileService_ExtractText_Success(t *testing.T) {
+	mockExt := testutil.NewMockExtractor()
+	mockExt.ExtractFunc = func(ctx context.Context, file io.Reader, filename, mimeType string) (*extractor.ExtractionResult, error) {
+		return &extractor.ExtractionResult{
+			Text:      "# Results\n\nRevenue grew.\n\n- one\n- two",
+			PageCount: 2,
+			Metadata:  map[string]any{"format": "pdf"},
+		}, nil
+	}
+	mockStore := testutil.NewMockStore()
+	svc := NewFileService(createRAGServiceWithMocks(mockStore, nil, mockExt), nil)
+
+	resp, err := svc.ExtractText(ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{
+		Content:      []byte("%PDF-1.7"),
+		Filename:     "report.pdf",
+		MimeType:     "application/pdf",
+		MaxTextChars: 9,
+	})
+	if err != nil {
+		t.Fatalf("ExtractText failed: %v", err)
+	}
+	if resp.Text != "# Results" || !resp.Truncated {
+		t.Errorf("expected truncated text, got %q (truncated=%v)", resp.Text, resp.Truncated)
+	}
+	if resp.Format != "pdf" || resp.Fallback || resp.PageCount != 2 {
+		t.Errorf("unexpected metadata: format=%q fallback=%v pages=%d", resp.Format, resp.Fallback, resp.PageCount)
+	}
+	if resp.CharCount != 37 || resp.WordCount != 8 || resp.ChunkCount != 1 {
+		t.Errorf("unexpected counts: chars=%d words=%d chunks=%d", resp.CharCount, resp.WordCount, resp.ChunkCount)
+	}
+	st := resp.Structure
+	if len(st.Headings) != 1 || st.Headings[0].Text != "Results" || st.Paragraphs != 1 || st.ListItems != 2 {
+		t.Errorf("unexpected structure: %+v", st)
+	}
+
+	// Nothing is ingested
+	if len(mockStore.UpsertCalls) > 0 || len(mockStore.CreateCollectionCalls) > 0 {
+		t.Error("ExtractText should not write to the vector store")
+	}
+}
+
+func TestFileService_ExtractText_Errors(t *testing.T) {
+	failing := testutil.NewMockExtractor()
+	failing.ExtractFunc = func(ctx context.Context, file io.Reader, filename, mimeType string) (*extractor.ExtractionResult, error) {
+		return nil, fmt.Errorf("docbox returned 422")
+	}
+
+	tests := []struct {
+		name string
+		svc  *FileService
+		ctx  context.Context
+		req  *pb.ExtractTextRequest
+		code codes.Code
+	}{
+		{"no auth", NewFileService(createMockRAGService(), nil), context.Background(), &pb.ExtractTextRequest{Content: []byte("x"), Filename: "a.txt"}, codes.Unauthenticated},
+		{"rag disabled", NewFileService(nil, nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Content: []byte("x"), Filename: "a.txt"}, codes.FailedPrecondition},
+		{"missing filename", NewFileService(createMockRAGService(), nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Content: []byte("x")}, codes.InvalidArgument},
+		{"empty content", NewFileService(createMockRAGService(), nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Filename: "a.txt"}, codes.InvalidArgument},
+		{"negative max", NewFileService(createMockRAGService(), nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Content: []byte("x"), Filename: "a.txt", MaxTextChars: -1}, codes.InvalidArgument},
+		{"extraction fails", NewFileService(createRAGServiceWithMocks(nil, nil, failing), nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Content: []byte("x"), Filename: "a.txt"}, codes.InvalidArgument},
+		{"executable", NewFileService(createMockRAGService(), nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Content: []byte("\x7fELF\x02\x01"), Filename: "a.txt"}, codes.InvalidArgument},
+		{"type mismatch", NewFileService(createMockRAGService(), nil), ctxWithFilePermission("tenant1"), &pb.ExtractTextRequest{Content: []byte("x"), Filename: "a.txt", MimeType: "application/pdf"}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.svc.ExtractText(tt.ctx, tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("err = %v, want %v", err, tt.code)
+			}
+		})
+	}
 }
 
 // Helper functions to create mock RAG services
@@ -746,3 +925,203 @@ func createRAGServiceWithMocks(
 
 	return rag.NewService(embedder, store, extractor, rag.DefaultServiceOptions())
 }
+
+func TestFileService_Reindex(t *testing.T) {
+	mockStore := testutil.NewMockStore()
+	mockStore.CreateCollection(context.Background(), "tenant1_test-store", 768)
+	mockStore.Upsert(context.Background(), "tenant1_test-store", []vectorstore.Point{
+		{ID: "1", Vector: make([]float32, 768), Payload: map[string]any{"text": "chunk", "embedding_model": "old-embed"}},
+	})
+	svc := NewFileService(createRAGServiceWithMocks(mockStore, nil, nil), nil)
+
+	before, err := svc.GetReindexStatus(ctxWithFilePermission("tenant1"), &pb.GetReindexStatusRequest{StoreId: "test-store"})
+	if err != nil {
+		t.Fatalf("GetReindexStatus failed: %v", err)
+	}
+	if !before.NeedsReindex || before.StoredModel != "old-embed" || before.State != "" {
+		t.Errorf("unexpected status before re-index: %+v", before)
+	}
+
+	if _, err := svc.StartReindex(ctxWithFilePermission("tenant1"), &pb.StartReindexRequest{StoreId: "test-store"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied without admin, got %v", err)
+	}
+	started, err := svc.StartReindex(ctxWithAdminPermission("tenant1"), &pb.StartReindexRequest{StoreId: "test-store"})
+	if err != nil {
+		t.Fatalf("StartReindex failed: %v", err)
+	}
+	if started.StoreId != "test-store" || started.State != rag.ReindexRunning || started.Model != "mock-embed" ||
+		started.TotalChunks != 1 || started.StartedAt == "" {
+		t.Errorf("unexpected started job: %+v", started)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		st, err := svc.GetReindexStatus(ctxWithFilePermission("tenant1"), &pb.GetReindexStatusRequest{StoreId: "test-store"})
+		if err != nil {
+			t.Fatalf("GetReindexStatus failed: %v", err)
+		}
+		if st.State == rag.ReindexCompleted {
+			if st.ReindexedChunks != 1 || st.NeedsReindex || st.StoredModel != "mock-embed" || st.FinishedAt == "" {
+				t.Errorf("unexpected finished job: %+v", st)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("re-index did not complete: %+v", st)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFileService_Reindex_Errors(t *testing.T) {
+	svc := NewFileService(createMockRAGService(), nil)
+	ctx := ctxWithAdminPermission("tenant1")
+
+	if _, err := svc.StartReindex(ctx, &pb.StartReindexRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing store: err = %v, want InvalidArgument", err)
+	}
+	if _, err := svc.StartReindex(ctx, &pb.StartReindexRequest{StoreId: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown store: err = %v, want NotFound", err)
+	}
+	if _, err := svc.GetReindexStatus(ctx, &pb.GetReindexStatusRequest{StoreId: "../other"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid store: err = %v, want InvalidArgument", err)
+	}
+	if _, err := NewFileService(nil, nil).GetReindexStatus(ctx, &pb.GetReindexStatusRequest{StoreId: "s"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("rag disabled: err = %v, want FailedPrecondition", err)
+	}
+}
+
+// Mock streams for ExportStore/ImportStore testing
+type mockExportStoreServer struct {
+	pb.FileService_ExportStoreServer
+	ctx  context.Context
+	data bytes.Buffer
+}
+
+func (m *mockExportStoreServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockExportStoreServer) Send(chunk *pb.ExportStoreChunk) error {
+	m.data.Write(chunk.Data)
+	return nil
+}
+
+type mockImportStoreServer struct {
+	pb.FileService_ImportStoreServer
+	ctx      context.Context
+	messages []*pb.ImportStoreRequest
+	index    int
+	response *pb.ImportStoreResponse
+}
+
+func (m *mockImportStoreServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockImportStoreServer) Recv() (*pb.ImportStoreRequest, error) {
+	if m.index >= len(m.messages) {
+		return nil, io.EOF
+	}
+	msg := m.messages[m.index]
+	m.index++
+	return msg, nil
+}
+
+func (m *mockImportStoreServer) SendAndClose(resp *pb.ImportStoreResponse) error {
+	m.response = resp
+	return nil
+}
+
+func TestFileService_ExportImportStore(t *testing.T) {
+	mockStore := testutil.NewMockStore()
+	mockStore.CreateCollection(context.Background(), "tenant1_test-store", 768)
+	mockStore.Upsert(context.Background(), "tenant1_test-store", []vectorstore.Point{
+		{ID: "1", Vector: make([]float32, 768), Payload: map[string]any{"text": "chunk one", "embedding_model": "mock-embed"}},
+		{ID: "2", Vector: make([]float32, 768), Payload: map[string]any{"text": "chunk two", "embedding_model": "mock-embed"}},
+	})
+	svc := NewFileService(createRAGServiceWithMocks(mockStore, nil, nil), nil)
+
+	exportStream := &mockExportStoreServer{ctx: ctxWithAdminPermission("tenant1")}
+	if err := svc.ExportStore(&pb.ExportStoreRequest{StoreId: "test-store", IncludeVectors: true}, exportStream); err != nil {
+		t.Fatalf("ExportStore failed: %v", err)
+	}
+	archive := exportStream.data.Bytes()
+	if len(archive) == 0 {
+		t.Fatal("expected archive data")
+	}
+
+	// Split the archive across chunks as a client would
+	messages := []*pb.ImportStoreRequest{{
+		Data: &pb.ImportStoreRequest_Metadata{Metadata: &pb.ImportStoreMetadata{StoreId: "promoted"}},
+	}}
+	for len(archive) > 0 {
+		n := min(len(archive), 100)
+		messages = append(messages, &pb.ImportStoreRequest{Data: &pb.ImportStoreRequest_Chunk{Chunk: archive[:n]}})
+		archive = archive[n:]
+	}
+	importStream := &mockImportStoreServer{ctx: ctxWithAdminPermission("tenant2"), messages: messages}
+	if err := svc.ImportStore(importStream); err != nil {
+		t.Fatalf("ImportStore failed: %v", err)
+	}
+	resp := importStream.response
+	if resp.StoreId != "promoted" || resp.ChunkCount != 2 || resp.Reembedded ||
+		resp.SourceStoreId != "test-store" || resp.SourceModel != "mock-embed" || resp.ExportedAt == "" {
+		t.Errorf("unexpected import response: %+v", resp)
+	}
+	if info, _ := mockStore.CollectionInfo(context.Background(), "tenant2_promoted"); info == nil || info.PointCount != 2 {
+		t.Errorf("expected 2 imported chunks, got %+v", info)
+	}
+
+	// Importing again without overwrite conflicts with the new store
+	importStream = &mockImportStoreServer{ctx: ctxWithAdminPermission("tenant2"), messages: messages}
+	if err := svc.ImportStore(importStream); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists, got %v", err)
+	}
+}
+
+func TestFileService_ExportImportStore_Errors(t *testing.T) {
+	svc := NewFileService(createMockRAGService(), nil)
+
+	tests := []struct {
+		name string
+		run  func() error
+		code codes.Code
+	}{
+		{"export requires admin", func() error {
+			return svc.ExportStore(&pb.ExportStoreRequest{StoreId: "s"}, &mockExportStoreServer{ctx: ctxWithFilePermission("tenant1")})
+		}, codes.PermissionDenied},
+		{"export missing store_id", func() error {
+			return svc.ExportStore(&pb.ExportStoreRequest{}, &mockExportStoreServer{ctx: ctxWithAdminPermission("tenant1")})
+		}, codes.InvalidArgument},
+		{"export unknown store", func() error {
+			return svc.ExportStore(&pb.ExportStoreRequest{StoreId: "missing"}, &mockExportStoreServer{ctx: ctxWithAdminPermission("tenant1")})
+		}, codes.NotFound},
+		{"import requires admin", func() error {
+			return svc.ImportStore(&mockImportStoreServer{ctx: ctxWithFilePermission("tenant1")})
+		}, codes.PermissionDenied},
+		{"import missing metadata", func() error {
+			return svc.ImportStore(&mockImportStoreServer{ctx: ctxWithAdminPermission("tenant1"), messages: []*pb.ImportStoreRequest{
+				{Data: &pb.ImportStoreRequest_Chunk{Chunk: []byte("data")}},
+			}})
+		}, codes.InvalidArgument},
+		{"import invalid archive", func() error {
+			return svc.ImportStore(&mockImportStoreServer{ctx: ctxWithAdminPermission("tenant1"), messages: []*pb.ImportStoreRequest{
+				{Data: &pb.ImportStoreRequest_Metadata{Metadata: &pb.ImportStoreMetadata{}}},
+				{Data: &pb.ImportStoreRequest_Chunk{Chunk: []byte("not a snapshot")}},
+			}})
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.run()); code != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, code)
+			}
+		})
+	}
+
+	disabled := NewFileService(nil, nil)
+	if err := disabled.ExportStore(&pb.ExportStoreRequest{StoreId: "s"}, &mockExportStoreServer{ctx: ctxWithAdminPermission("tenant1")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition without RAG, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// footnote is a citation placed in a reply: the span of text its marker
+// goes after, or replaces.
+type footnote struct {
+	citation   provider.Citation
+	start, end int // Byte offsets; start == end inserts the marker at end
+}
+
+// footnoteCitations returns text as markdown with a numbered marker after
+// each span its citations cover, linking to the source for URL citations,
+// and a numbered list of the sources at the end. Sources are numbered in
+// order of first appearance. Inline links the provider wrote for a URL
+// citation, as OpenAI web search does, are replaced by the marker. Returns
+// "" when no citation has a position in text.
+func footnoteCitations(text string, citations []provider.Citation) string {
+	var notes []footnote
+	for _, c := range citations {
+		start, end, ok := citationSpan(text, c)
+		if !ok {
+			continue
+		}
+		note := footnote{citation: c, start: end, end: end}
+		if c.Type == provider.CitationTypeURL && c.URL != "" && start < end && strings.Contains(text[start:end], c.URL) {
+			note.start = start
+		}
+		notes = append(notes, note)
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	slices.SortStableFunc(notes, func(a, b footnote) int { return a.end - b.end })
+
+	var sb strings.Builder
+	var sources []provider.Citation
+	numbers := make(map[string]int)
+	placed := make(map[[2]int]bool) // Markers placed, by position and number
+	pos := 0
+	for _, note := range notes {
+		key := citationSource(note.citation)
+		n, ok := numbers[key]
+		if !ok {
+			sources = append(sources, note.citation)
+			n = len(sources)
+			numbers[key] = n
+		} else if sources[n-1].Title == "" {
+			sources[n-1].Title = note.citation.Title
+		}
+		// Markers inside a span an earlier marker replaced go after it
+		end := max(note.end, pos)
+		start := note.start
+		if start < pos {
+			start = end
+		}
+		if placed[[2]int{end, n}] {
+			continue
+		}
+		placed[[2]int{end, n}] = true
+
+		sb.WriteString(text[pos:start])
+		if c := note.citation; c.Type == provider.CitationTypeURL && c.URL != "" {
+			fmt.Fprintf(&sb, "[[%d]](<%s>)", n, escapeURL(c.URL))
+		} else {
+			fmt.Fprintf(&sb, `\[%d\]`, n)
+		}
+		pos = end
+	}
+	sb.WriteString(text[pos:])
+
+	sb.WriteString("\n\n---\n\n")
+	for i, c := range sources {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, citationLabel(c))
+	}
+	return sb.String()
+}
+
+// replyFootnotes footnotes the citations of a reply, unless the grounding
+// check replaced its text with the fallback answer.
+func replyFootnotes(text string, citations []provider.Citation, grounding *pb.GroundingCheck) string {
+	if grounding.GetAction() == tenant.GroundingActionFallback {
+		return ""
+	}
+	return footnoteCitations(text, citations)
+}
+
+// citationSpan returns the byte offsets of the text c covers. Gemini counts
+// bytes and OpenAI characters. Citations with only a start index, like
+// OpenAI file citations, are placed at it. Citations without a position, or
+// past the end of text, are not placed.
+func citationSpan(text string, c provider.Citation) (start, end int, ok bool) {
+	start, end = c.StartIndex, c.EndIndex
+	if end <= 0 {
+		end = start
+	}
+	if end <= 0 || start < 0 || start > end {
+		return 0, 0, false
+	}
+	if c.Provider != "gemini" {
+		if start, ok = runeOffset(text, start); !ok {
+			return 0, 0, false
+		}
+		if end, ok = runeOffset(text, end); !ok {
+			return 0, 0, false
+		}
+	}
+	if end > len(text) {
+		return 0, 0, false
+	}
+	// Keep markers out of multi-byte characters
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	for start > 0 && start < len(text) && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	return start, end, true
+}
+
+// runeOffset returns the byte offset of the n-th character of text.
+func runeOffset(text string, n int) (int, bool) {
+	i := 0
+	for offset := range text {
+		if i == n {
+			return offset, true
+		}
+		i++
+	}
+	return len(text), i == n
+}
+
+// citationSource identifies the source c cites, so repeated citations of it
+// share a number.
+func citationSource(c provider.Citation) string {
+	if c.Type == provider.CitationTypeURL {
+		return "url:" + c.URL
+	}
+	return "file:" + c.FileID + ":" + c.Filename
+}
+
+// citationLabel returns the markdown naming c's source in the footnote list.
+func citationLabel(c provider.Citation) string {
+	escape := strings.NewReplacer(`[`, `\[`, `]`, `\]`, "\n", " ").Replace
+	if c.Type == provider.CitationTypeURL && c.URL != "" {
+		title := c.Title
+		if title == "" {
+			title = c.URL
+		}
+		return fmt.Sprintf("[%s](<%s>)", escape(title), escapeURL(c.URL))
+	}
+	if c.Filename != "" {
+		return escape(c.Filename)
+	}
+	return escape(c.FileID)
+}
+
+// escapeURL makes url safe inside a markdown <...> link destination.
+func escapeURL(url string) string {
+	return strings.NewReplacer("<", "%3C", ">", "%3E", " ", "%20", "\n", "").Replace(url)
+}
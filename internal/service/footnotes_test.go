@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+func TestFootnoteCitations(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		citations []provider.Citation
+		want      string
+	}{
+		{
+			name:      "no positions",
+			text:      "Paris is the capital.",
+			citations: []provider.Citation{{Type: provider.CitationTypeFile, Filename: "facts.pdf"}},
+			want:      "",
+		},
+		{
+			name: "gemini byte offsets, shared source",
+			text: "Café opens at 8. Closes at 5.",
+			citations: []provider.Citation{
+				{Type: provider.CitationTypeURL, Provider: "gemini", URL: "https://b.example", Title: "Hours", StartIndex: 18, EndIndex: 30},
+				{Type: provider.CitationTypeURL, Provider: "gemini", URL: "https://a.example", Title: "Café [site]", StartIndex: 0, EndIndex: 17},
+				{Type: provider.CitationTypeURL, Provider: "gemini", URL: "https://b.example", StartIndex: 0, EndIndex: 17},
+			},
+			want: "Café opens at 8.[[1]](<https://a.example>)[[2]](<https://b.example>) Closes at 5.[[2]](<https://b.example>)" +
+				"\n\n---\n\n1. [Café \\[site\\]](<https://a.example>)\n2. [Hours](<https://b.example>)\n",
+		},
+		{
+			name: "openai inline link replaced, file citation by character",
+			text: "Café news ([example.com](https://example.com/a)). See notes.",
+			citations: []provider.Citation{
+				{Type: provider.CitationTypeURL, Provider: "openai", URL: "https://example.com/a", Title: "News", StartIndex: 10, EndIndex: 48},
+				{Type: provider.CitationTypeFile, Provider: "openai", FileID: "file-1", Filename: "notes.md", StartIndex: 60},
+			},
+			want: "Café news [[1]](<https://example.com/a>). See notes.\\[2\\]" +
+				"\n\n---\n\n1. [News](<https://example.com/a>)\n2. notes.md\n",
+		},
+		{
+			name:      "past the end of the text",
+			text:      "Short.",
+			citations: []provider.Citation{{Type: provider.CitationTypeURL, Provider: "gemini", URL: "https://a.example", EndIndex: 40}},
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := footnoteCitations(tt.text, tt.citations); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	fallback := &pb.GroundingCheck{Action: tenant.GroundingActionFallback}
+	cited := []provider.Citation{{Type: provider.CitationTypeURL, Provider: "gemini", URL: "https://a.example", EndIndex: 5}}
+	if got := replyFootnotes("I don't know.", cited, fallback); got != "" {
+		t.Errorf("footnotes on a fallback answer: %q", got)
+	}
+}
+
+func TestGenerateReply_FootnotedMarkdown(t *testing.T) {
+	openai := newMockProvider("openai")
+	openai.generateResult.Text = "Paris is the capital."
+	openai.generateResult.Citations = []provider.Citation{
+		{Type: provider.CitationTypeURL, Provider: "openai", URL: "https://example.com", Title: "France", StartIndex: 0, EndIndex: 20},
+	}
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	resp, err := svc.GenerateReply(ctx, dedupRequest("req-1"))
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	want := "Paris is the capital[[1]](<https://example.com>).\n\n---\n\n1. [France](<https://example.com>)\n"
+	if resp.FootnotedMarkdown != want {
+		t.Errorf("FootnotedMarkdown = %q, want %q", resp.FootnotedMarkdown, want)
+	}
+	if resp.Text != "Paris is the capital." {
+		t.Errorf("Text changed: %q", resp.Text)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+const groundingInstructions = `You are checking whether an answer is supported by source documents.
+Score from 0 to 1 how much of the answer the sources support, where 1 means
+every factual claim is stated in or directly follows from the sources and 0
+means none is. Claims the sources do not cover count as unsupported, even if
+they are true. Greetings and offers of further help are not claims.
+Reply with a JSON object only, in this form:
+{"score": 0.8, "unsupported_claims": ["..."], "reason": "..."}`
+
+// groundingVerdict is the JSON the verifier model returns.
+type groundingVerdict struct {
+	Score             float64  `json:"score"`
+	UnsupportedClaims []string `json:"unsupported_claims"`
+	Reason            string   `json:"reason"`
+}
+
+// groundingPolicy returns the tenant's grounding policy, if it checks replies.
+func groundingPolicy(ctx context.Context) (tenant.GroundingPolicy, bool) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil || !tenantCfg.Grounding.Enabled() {
+		return tenant.GroundingPolicy{}, false
+	}
+	return tenantCfg.Grounding, true
+}
+
+// groundReply applies the tenant's grounding policy to a reply generated by
+// replied with internal RAG context. Replies scored below the threshold get
+// the policy's disclaimer or are replaced with its fallback answer. Replies
+// without RAG context or text are returned unchanged without a check, as are
+// replies the verifier fails to score.
+func (s *ChatService) groundReply(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, replied string, result provider.GenerateResult) (provider.GenerateResult, *pb.GroundingCheck) {
+	policy, ok := groundingPolicy(ctx)
+	if !ok || len(prepared.ragChunks) == 0 || !judgeable(result) {
+		return result, nil
+	}
+	check, err := s.checkGrounding(ctx, req, prepared, policy, replied, result.Text)
+	if err != nil {
+		slog.WarnContext(ctx, "grounding check failed, returning reply unchecked",
+			"error", err,
+			"request_id", prepared.requestID,
+		)
+		return result, nil
+	}
+	switch check.Action {
+	case tenant.GroundingActionDisclaimer:
+		result.Text = withDisclaimer(result.Text, policy)
+	case tenant.GroundingActionFallback:
+		result.Text = policy.FallbackText()
+	}
+	return result, check
+}
+
+// withDisclaimer appends the policy's disclaimer to text.
+func withDisclaimer(text string, policy tenant.GroundingPolicy) string {
+	return strings.TrimRight(text, "\n") + "\n\n" + policy.DisclaimerText()
+}
+
+// checkGrounding asks the policy's verifier model to score how well the
+// request's retrieved chunks support text. The verifier call is charged to
+// the tenant's spend. The check's action is set when text is not grounded.
+func (s *ChatService) checkGrounding(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, policy tenant.GroundingPolicy, replied, text string) (*pb.GroundingCheck, error) {
+	verifierName := policy.Provider
+	if verifierName == "" {
+		verifierName = replied
+	}
+	verifier := s.providerByName(verifierName)
+	if verifier == nil {
+		return nil, fmt.Errorf("grounding verifier provider %q is not available", verifierName)
+	}
+
+	var input strings.Builder
+	input.WriteString("Sources:\n")
+	for i, chunk := range prepared.ragChunks {
+		fmt.Fprintf(&input, "\n[%d] %s\n%s\n", i+1, chunk.Filename, strings.TrimSpace(chunk.Text))
+	}
+	fmt.Fprintf(&input, "\nQuestion:\n%s\n\nAnswer:\n%s\n", prepared.params.UserInput, strings.TrimSpace(text))
+	params := provider.GenerateParams{
+		Instructions:  groundingInstructions,
+		UserInput:     input.String(),
+		OverrideModel: policy.Model,
+		Config:        s.buildProviderConfig(ctx, req, verifierName),
+		RequestID:     prepared.params.RequestID,
+		ClientID:      prepared.params.ClientID,
+	}
+	out, err := verifier.GenerateReply(s.observeHeadroom(ctx, verifierName), params)
+	s.reportProviderCall(ctx, verifierName, params, out, err)
+	if err != nil {
+		return nil, err
+	}
+	s.recordSpend(ctx, estimateCost(verifierName, out.Model, out.Usage, out.GroundingQueries).Total())
+
+	var verdict groundingVerdict
+	if err := json.Unmarshal([]byte(validation.StripCodeFence(out.Text)), &verdict); err != nil {
+		return nil, fmt.Errorf("grounding verifier reply is not valid JSON: %w", err)
+	}
+	check := &pb.GroundingCheck{
+		Score:             min(max(verdict.Score, 0), 1),
+		Threshold:         policy.Threshold,
+		Reason:            verdict.Reason,
+		UnsupportedClaims: verdict.UnsupportedClaims,
+		VerifierProvider:  mapProviderToProto(verifierName),
+		VerifierModel:     out.Model,
+	}
+	check.Grounded = check.Score >= policy.Threshold
+	if !check.Grounded {
+		check.Action = policy.ActionName()
+	}
+	accesslog.Annotate(ctx, "grounding_score", check.Score, "grounded", check.Grounded)
+	return check, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// groundingProvider answers generation calls with reply, streamed or not,
+// and grounding checks with verdict.
+type groundingProvider struct {
+	*mockProvider
+	reply       string
+	verdict     string
+	verifyCalls []provider.GenerateParams
+}
+
+func (p *groundingProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	result := provider.GenerateResult{Text: p.reply, Model: "mock-model", Usage: &provider.Usage{InputTokens: 10, OutputTokens: 20, TotalTokens: 30}}
+	if params.Instructions == groundingInstructions {
+		p.verifyCalls = append(p.verifyCalls, params)
+		result.Model = "verifier-model"
+		result.Text = p.verdict
+	}
+	return result, nil
+}
+
+func (p *groundingProvider) GenerateReplyStream(ctx context.Context, params provider.GenerateParams) (<-chan provider.StreamChunk, error) {
+	ch := make(chan provider.StreamChunk, 2)
+	ch <- provider.StreamChunk{Type: provider.ChunkTypeText, Text: p.reply}
+	ch <- provider.StreamChunk{Type: provider.ChunkTypeComplete, Model: "mock-model"}
+	close(ch)
+	return ch, nil
+}
+
+// mockGenerateReplyStream records the chunks sent to the client.
+type mockGenerateReplyStream struct {
+	pb.AirborneService_GenerateReplyStreamServer
+	ctx    context.Context
+	chunks []*pb.GenerateReplyChunk
+}
+
+func (m *mockGenerateReplyStream) Context() context.Context { return m.ctx }
+
+func (m *mockGenerateReplyStream) Send(chunk *pb.GenerateReplyChunk) error {
+	m.chunks = append(m.chunks, chunk)
+	return nil
+}
+
+func newGroundingService(verdict string) (*ChatService, *groundingProvider) {
+	ragSvc, _ := newQueryRAGService()
+	gemini := &groundingProvider{mockProvider: newMockProvider("gemini"), reply: "The Pro plan costs $25 a month.", verdict: verdict}
+	svc := &ChatService{openaiProvider: newMockProvider("openai"), geminiProvider: gemini, anthropicProvider: newMockProvider("anthropic"), ragService: ragSvc}
+	return svc, gemini
+}
+
+func groundingTenant(policy tenant.GroundingPolicy) context.Context {
+	cfg := createTestTenantConfig("gemini")
+	cfg.Grounding = policy
+	return ctxWithChatPermissionAndTenant("test-client", cfg)
+}
+
+func ragRequest() *pb.GenerateReplyRequest {
+	return &pb.GenerateReplyRequest{
+		UserInput:         "How much is the Pro plan?",
+		PreferredProvider: pb.Provider_PROVIDER_GEMINI,
+		EnableFileSearch:  true,
+		FileStoreId:       "vs_docs",
+	}
+}
+
+const ungroundedVerdict = `{"score": 0.2, "unsupported_claims": ["$25 a month"], "reason": "The sources say $20."}`
+
+func TestGenerateReply_Grounded(t *testing.T) {
+	svc, gemini := newGroundingService("```json\n" + `{"score": 0.9, "reason": "Supported."}` + "\n```")
+	resp, err := svc.GenerateReply(groundingTenant(tenant.GroundingPolicy{Threshold: 0.7}), ragRequest())
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if len(gemini.verifyCalls) != 1 {
+		t.Fatalf("expected one grounding check, got %d", len(gemini.verifyCalls))
+	}
+	input := gemini.verifyCalls[0].UserInput
+	for _, want := range []string{"The Pro plan costs $20 a month.", "How much is the Pro plan?", "The Pro plan costs $25 a month."} {
+		if !strings.Contains(input, want) {
+			t.Errorf("verifier input %q missing %q", input, want)
+		}
+	}
+	g := resp.Grounding
+	if g == nil || !g.Grounded || g.Score != 0.9 || g.Action != "" || g.VerifierModel != "verifier-model" {
+		t.Fatalf("unexpected grounding check: %+v", g)
+	}
+	if resp.Text != gemini.reply {
+		t.Errorf("expected the reply unchanged, got %q", resp.Text)
+	}
+}
+
+func TestGenerateReply_UngroundedDisclaimer(t *testing.T) {
+	svc, gemini := newGroundingService(ungroundedVerdict)
+	resp, err := svc.GenerateReply(groundingTenant(tenant.GroundingPolicy{Threshold: 0.7}), ragRequest())
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if g := resp.Grounding; g == nil || g.Grounded || g.Action != tenant.GroundingActionDisclaimer || len(g.UnsupportedClaims) != 1 {
+		t.Fatalf("unexpected grounding check: %+v", g)
+	}
+	if want := gemini.reply + "\n\n" + tenant.DefaultGroundingDisclaimer; resp.Text != want {
+		t.Errorf("Text = %q, want %q", resp.Text, want)
+	}
+	if len(resp.Citations) == 0 {
+		t.Error("expected the RAG citations to be kept")
+	}
+}
+
+func TestGenerateReply_UngroundedFallback(t *testing.T) {
+	svc, _ := newGroundingService(ungroundedVerdict)
+	policy := tenant.GroundingPolicy{Threshold: 0.7, Action: tenant.GroundingActionFallback, Fallback: "I don't know. Please contact sales."}
+	resp, err := svc.GenerateReply(groundingTenant(policy), ragRequest())
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Text != policy.Fallback {
+		t.Errorf("Text = %q, want the fallback", resp.Text)
+	}
+	if len(resp.Citations) != 0 {
+		t.Errorf("expected no citations with the fallback, got %d", len(resp.Citations))
+	}
+}
+
+func TestGenerateReply_GroundingSkipped(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  tenant.GroundingPolicy
+		req     *pb.GenerateReplyRequest
+		verdict string
+	}{
+		{"no policy", tenant.GroundingPolicy{}, ragRequest(), ungroundedVerdict},
+		{"no rag context", tenant.GroundingPolicy{Threshold: 0.7}, &pb.GenerateReplyRequest{UserInput: "Hi", PreferredProvider: pb.Provider_PROVIDER_GEMINI}, ungroundedVerdict},
+		{"invalid verdict", tenant.GroundingPolicy{Threshold: 0.7}, ragRequest(), "the answer looks fine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, gemini := newGroundingService(tt.verdict)
+			resp, err := svc.GenerateReply(groundingTenant(tt.policy), tt.req)
+			if err != nil {
+				t.Fatalf("GenerateReply failed: %v", err)
+			}
+			if resp.Grounding != nil || resp.Text != gemini.reply {
+				t.Errorf("expected the reply unchecked, got %q with %+v", resp.Text, resp.Grounding)
+			}
+		})
+	}
+}
+
+func TestGenerateReplyStream_Grounding(t *testing.T) {
+	tests := []struct {
+		action       string
+		wantDeltas   []string
+		wantFallback string
+	}{
+		{tenant.GroundingActionDisclaimer, []string{"The Pro plan costs $25 a month.", "\n\n" + tenant.DefaultGroundingDisclaimer}, ""},
+		{tenant.GroundingActionFallback, []string{"The Pro plan costs $25 a month."}, tenant.DefaultGroundingFallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			svc, _ := newGroundingService(ungroundedVerdict)
+			stream := &mockGenerateReplyStream{ctx: groundingTenant(tenant.GroundingPolicy{Threshold: 0.7, Action: tt.action})}
+			if err := svc.GenerateReplyStream(ragRequest(), stream); err != nil {
+				t.Fatalf("GenerateReplyStream failed: %v", err)
+			}
+
+			var deltas []string
+			var complete *pb.StreamComplete
+			for _, c := range stream.chunks {
+				if d := c.GetTextDelta(); d != nil {
+					deltas = append(deltas, d.Text)
+				}
+				if c.GetComplete() != nil {
+					complete = c.GetComplete()
+				}
+			}
+			if strings.Join(deltas, "|") != strings.Join(tt.wantDeltas, "|") {
+				t.Errorf("text deltas = %q, want %q", deltas, tt.wantDeltas)
+			}
+			if complete == nil || complete.Grounding == nil {
+				t.Fatal("expected the grounding check on completion")
+			}
+			if complete.Grounding.Action != tt.action || complete.Grounding.FallbackText != tt.wantFallback {
+				t.Errorf("unexpected grounding check: %+v", complete.Grounding)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/headroom"
+	"github.com/ai8future/airborne/internal/httpcapture"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultHeadroomMaxWait is how long a request waits for provider headroom
+// before failing over or being rejected.
+const defaultHeadroomMaxWait = 2 * time.Second
+
+// WithHeadroom tracks provider rate-limit headers per tenant. Requests to a
+// provider without headroom wait up to maxWait for it to reset; longer waits
+// fail over when the request enables failover and are rejected otherwise.
+// A maxWait of zero uses the default of 2s.
+func WithHeadroom(tracker *headroom.Tracker, maxWait time.Duration) ChatServiceOption {
+	return func(s *ChatService) {
+		if maxWait <= 0 {
+			maxWait = defaultHeadroomMaxWait
+		}
+		s.headroom = tracker
+		s.headroomMaxWait = maxWait
+	}
+}
+
+// WithHeadroomTracking records provider rate-limit headers per tenant in
+// tracker, for reporting, without holding requests as WithHeadroom does.
+func WithHeadroomTracking(tracker *headroom.Tracker) ChatServiceOption {
+	return func(s *ChatService) {
+		s.headroom = tracker
+	}
+}
+
+// observeHeadroom returns a context whose provider responses update the
+// tenant's headroom state for providerName. The context also attributes the
+// provider's outbound requests for egress auditing.
+func (s *ChatService) observeHeadroom(ctx context.Context, providerName string) context.Context {
+	tenantID := auth.TenantIDFromContext(ctx)
+	ctx = egress.WithAttribution(ctx, tenantID, providerName)
+	if s.headroom == nil {
+		return ctx
+	}
+	return httpcapture.WithResponseObserver(ctx, func(statusCode int, header http.Header) {
+		s.headroom.Observe(tenantID, providerName, statusCode, header)
+	})
+}
+
+// awaitHeadroom holds the request until the selected provider has headroom.
+// When the wait would exceed the limit it switches prepared to the first
+// provider in the failover chain that has headroom (if failover is enabled) or returns
+// ResourceExhausted rather than sending a request that will be rejected.
+func (s *ChatService) awaitHeadroom(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest) error {
+	if s.headroom == nil || s.headroomMaxWait == 0 {
+		return nil
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	name := prepared.provider.Name()
+	wait := s.headroom.Wait(tenantID, name)
+	if wait == 0 {
+		return nil
+	}
+
+	if wait <= s.headroomMaxWait {
+		slog.InfoContext(ctx, "provider rate limit exhausted, waiting for reset", "provider", name, "wait", wait)
+		accesslog.Annotate(ctx, "headroom_wait_ms", wait.Milliseconds())
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	if req.EnableFailover && !pinnedToProvider(req) {
+		for _, fallback := range s.failoverChain(ctx, req.FallbackProvider, name) {
+			if s.headroom.Wait(tenantID, fallback.Name()) != 0 {
+				continue
+			}
+			slog.WarnContext(ctx, "provider rate limit exhausted, failing over pre-emptively",
+				"primary", name,
+				"fallback", fallback.Name(),
+				"resets_in", wait,
+			)
+			accesslog.Annotate(ctx, "headroom_failover_from", name)
+			prepared.providerCfg = s.buildProviderConfig(ctx, req, fallback.Name())
+			prepared.params = prepared.paramsFor(fallback.Name(), prepared.providerCfg)
+			prepared.provider = fallback
+			prepared.preemptedFrom = name
+			prepared.downgrade = nil
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.ResourceExhausted, "%s rate limit exhausted; resets in %s", name, wait.Round(time.Second))
+}
+
+// preemptedReason is reported as the original error when a request was moved
+// off a provider whose rate limit was exhausted.
+func preemptedReason(prepared *preparedRequest) string {
+	if prepared.preemptedFrom == "" {
+		return ""
+	}
+	return prepared.preemptedFrom + " rate limit exhausted"
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/headroom"
+	"github.com/ai8future/airborne/internal/httpcapture"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newHeadroomChatService(mockOpenAI, mockGemini *mockProvider) (*ChatService, *headroom.Tracker) {
+	tracker := headroom.NewTracker()
+	svc := createChatServiceWithMocks(mockOpenAI, mockGemini, newMockProvider("anthropic"), nil)
+	WithHeadroom(tracker, 100*time.Millisecond)(svc)
+	return svc, tracker
+}
+
+// exhaust records a 429 from providerName for the test tenant.
+func exhaust(tracker *headroom.Tracker, providerName, retryAfter string) {
+	h := http.Header{}
+	h.Set("Retry-After", retryAfter)
+	tracker.Observe("test-tenant", providerName, http.StatusTooManyRequests, h)
+}
+
+func TestGenerateReply_HeadroomExhaustedFailsOver(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mockGemini := newMockProvider("gemini")
+	svc, tracker := newHeadroomChatService(mockOpenAI, mockGemini)
+	exhaust(tracker, "openai", "60")
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai", "gemini"))
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFailover:    true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if len(mockOpenAI.generateCalls) != 0 || len(mockGemini.generateCalls) != 1 {
+		t.Fatalf("calls: openai=%d gemini=%d; want 0, 1", len(mockOpenAI.generateCalls), len(mockGemini.generateCalls))
+	}
+	if !resp.FailedOver || resp.OriginalProvider != pb.Provider_PROVIDER_OPENAI || resp.Provider != pb.Provider_PROVIDER_GEMINI {
+		t.Errorf("unexpected failover fields: failed_over=%v original=%v provider=%v", resp.FailedOver, resp.OriginalProvider, resp.Provider)
+	}
+}
+
+func TestGenerateReply_HeadroomExhaustedWithoutFailover(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	svc, tracker := newHeadroomChatService(mockOpenAI, newMockProvider("gemini"))
+	exhaust(tracker, "openai", "60")
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	if len(mockOpenAI.generateCalls) != 0 {
+		t.Error("provider should not be called while its rate limit is exhausted")
+	}
+}
+
+func TestGenerateReply_HeadroomShortWait(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	svc, tracker := newHeadroomChatService(mockOpenAI, newMockProvider("gemini"))
+	exhaust(tracker, "openai", "0.02")
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	start := time.Now()
+	if _, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	}); err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if len(mockOpenAI.generateCalls) != 1 {
+		t.Error("expected the request to reach the provider after waiting")
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("request was not delayed (took %v)", elapsed)
+	}
+}
+
+func TestObserveHeadroom_RecordsProviderHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.Header().Set("x-ratelimit-reset-requests", "30s")
+	}))
+	defer server.Close()
+
+	svc, tracker := newHeadroomChatService(newMockProvider("openai"), newMockProvider("gemini"))
+	ctx := svc.observeHeadroom(ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai")), "openai")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := httpcapture.New().Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if wait := tracker.Wait("test-tenant", "openai"); wait <= 0 || wait > 30*time.Second {
+		t.Errorf("Wait = %v, want up to 30s", wait)
+	}
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/provider"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultHedgeDelay is how long the primary provider has to answer before
+	// a hedge request is sent when the request does not set hedge_delay_ms.
+	defaultHedgeDelay = 2 * time.Second
+
+	// maxHedgeDelay caps client-supplied hedge delays.
+	maxHedgeDelay = 60 * time.Second
+)
+
+// WithMetrics records hedging and model tiering outcomes in the metrics registry.
+func WithMetrics(registry *metrics.Registry) ChatServiceOption {
+	return func(s *ChatService) {
+		s.metrics = registry
+	}
+}
+
+// hedgeDelay returns how long to wait for the primary provider before hedging.
+func hedgeDelay(req *pb.GenerateReplyRequest) time.Duration {
+	if req.HedgeDelayMs <= 0 {
+		return defaultHedgeDelay
+	}
+	return min(time.Duration(req.HedgeDelayMs)*time.Millisecond, maxHedgeDelay)
+}
+
+// hedgeTarget returns the provider hedge requests go to, or nil when the
+// request does not hedge or has no usable second provider.
+func (s *ChatService) hedgeTarget(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest) provider.Provider {
+	if !req.EnableHedging || pinnedToProvider(req) {
+		return nil
+	}
+	hedge := s.getFallbackProvider(ctx, prepared.provider.Name(), req.FallbackProvider)
+	if hedge == nil || hedge.Name() == prepared.provider.Name() {
+		return nil
+	}
+	if tenantCfg := auth.TenantFromContext(ctx); tenantCfg != nil {
+		if _, ok := tenantCfg.GetProvider(hedge.Name()); !ok {
+			return nil
+		}
+	}
+	return hedge
+}
+
+// useHedgeWinner points prepared at the hedge provider after it answered first.
+func (s *ChatService) useHedgeWinner(ctx context.Context, prepared *preparedRequest, winner provider.Provider, cfg provider.ProviderConfig) {
+	slog.InfoContext(ctx, "hedge provider answered first",
+		"primary", prepared.provider.Name(),
+		"hedge", winner.Name(),
+	)
+	accesslog.Annotate(ctx, "hedge_winner", winner.Name())
+	prepared.params = prepared.paramsFor(winner.Name(), cfg)
+	prepared.provider = winner
+	prepared.providerCfg = cfg
+	prepared.downgrade = nil // The downgrade applied to the primary provider's model
+}
+
+// hedgeOutcome is one provider's answer to a hedged unary request.
+type hedgeOutcome struct {
+	provider provider.Provider
+	cfg      provider.ProviderConfig
+	params   provider.GenerateParams
+	result   provider.GenerateResult
+	err      error
+}
+
+// generateHedged sends the request to the primary provider and, if it has not
+// answered within the hedge delay, to hedge as well. The hedge takes its own
+// QoS slot and is skipped when none is free. The first successful answer
+// wins and the other call is cancelled; prepared is updated when the hedge
+// wins. If the primary fails before the hedge is sent, its error is returned
+// so regular failover can handle it.
+func (s *ChatService) generateHedged(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, hedge provider.Provider) (provider.GenerateResult, error) {
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outcomes := make(chan hedgeOutcome, 2)
+	call := func(p provider.Provider, cfg provider.ProviderConfig, release func()) {
+		params := prepared.paramsFor(p.Name(), cfg)
+		go func() {
+			defer release()
+			result, err := p.GenerateReply(s.observeHeadroom(callCtx, p.Name()), params)
+			outcomes <- hedgeOutcome{provider: p, cfg: cfg, params: params, result: result, err: err}
+		}()
+	}
+
+	primary := prepared.provider
+	call(primary, prepared.providerCfg, func() {}) // The caller holds the primary's slot
+	pending := 1
+
+	timer := time.NewTimer(hedgeDelay(req))
+	defer timer.Stop()
+
+	obs := metrics.HedgeObservation{Primary: primary.Name(), Hedge: hedge.Name()}
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			release, ok := s.tryAcquireSlot(req, hedge.Name())
+			if !ok {
+				s.skipHedge(ctx, primary, hedge)
+				break
+			}
+			slog.InfoContext(ctx, "primary provider slow, sending hedge request",
+				"primary", primary.Name(),
+				"hedge", hedge.Name(),
+				"delay", hedgeDelay(req),
+			)
+			prepared.hedged = true
+			obs.Fired = true
+			call(hedge, s.buildProviderConfig(ctx, req, hedge.Name()), release)
+			pending++
+
+		case o := <-outcomes:
+			pending--
+			s.reportProviderCall(ctx, o.provider.Name(), o.params, o.result, o.err)
+			if o.err == nil {
+				if o.provider != primary {
+					obs.HedgeWon = true
+					s.useHedgeWinner(ctx, prepared, o.provider, o.cfg)
+				}
+				if pending > 0 {
+					go s.settleHedgeLoser(ctx, outcomes, obs)
+				} else {
+					s.metrics.ObserveHedge(obs)
+				}
+				return o.result, nil
+			}
+			if firstErr == nil {
+				firstErr = o.err
+			}
+			if pending == 0 {
+				s.metrics.ObserveHedge(obs)
+				return provider.GenerateResult{}, firstErr
+			}
+		}
+	}
+}
+
+// skipHedge logs a hedge request to hedge that was not sent because no QoS
+// slot was free.
+func (s *ChatService) skipHedge(ctx context.Context, primary, hedge provider.Provider) {
+	slog.InfoContext(ctx, "primary provider slow, but no slot is free for a hedge request",
+		"primary", primary.Name(),
+		"hedge", hedge.Name(),
+	)
+	accesslog.Annotate(ctx, "hedge_skipped", "no_slot")
+}
+
+// settleHedgeLoser waits for the cancelled losing call of a hedged request.
+// A loser that completed anyway was billed, so its cost is recorded against
+// the tenant's budget and the hedge metrics. Either way the call is recorded
+// in the usage ledger.
+func (s *ChatService) settleHedgeLoser(ctx context.Context, outcomes <-chan hedgeOutcome, obs metrics.HedgeObservation) {
+	o := <-outcomes
+	s.reportProviderCall(ctx, o.provider.Name(), o.params, o.result, o.err)
+	if o.err == nil {
+		obs.LoserCost = estimateCost(o.provider.Name(), o.result.Model, o.result.Usage, o.result.GroundingQueries).Total()
+		s.recordSpend(context.WithoutCancel(ctx), obs.LoserCost)
+		slog.InfoContext(ctx, "hedge loser completed before cancellation",
+			"provider", o.provider.Name(),
+			"cost_usd", obs.LoserCost,
+		)
+	}
+	s.metrics.ObserveHedge(obs)
+}
+
+// hedgeStart is one provider's stream in a hedged streaming request, with
+// its first chunk already read.
+type hedgeStart struct {
+	provider provider.Provider
+	cfg      provider.ProviderConfig
+	params   provider.GenerateParams
+	chunks   <-chan provider.StreamChunk
+	cancel   context.CancelFunc
+	first    provider.StreamChunk
+	gotFirst bool  // False if the stream closed without chunks
+	err      error // GenerateReplyStream failed to start
+}
+
+// abandonFailed abandons a failed stream that is not forwarded, recording
+// its error chunk in the usage ledger. Forwarded error chunks are recorded
+// when they are sent, and streams that failed to start when they fail.
+func (s *ChatService) abandonFailed(ctx context.Context, h *hedgeStart) {
+	if h.err == nil && h.gotFirst {
+		call := db.ProviderCall{RequestID: h.params.RequestID, Provider: h.provider.Name(), Model: calledModel(h.params, "")}
+		s.recordProviderCall(ctx, call, h.first.Error)
+	}
+	h.abandon()
+}
+
+// succeeded reports whether the stream started and produced a non-error chunk.
+func (h hedgeStart) succeeded() bool {
+	return h.err == nil && h.gotFirst && h.first.Type != provider.ChunkTypeError
+}
+
+// abandon cancels the stream and discards its remaining chunks.
+func (h hedgeStart) abandon() {
+	h.cancel()
+	if h.chunks != nil {
+		go func() {
+			for range h.chunks {
+			}
+		}()
+	}
+}
+
+// streamHedged starts the primary stream and, if no chunk arrives within the
+// hedge delay, a hedge stream at the second provider, which holds its own
+// QoS slot until it ends and is skipped when none is free. The stream that
+// produces the first chunk wins and the other is cancelled; prepared is
+// updated when the hedge wins. The returned channel replays the winner's
+// first chunk followed by the rest of its stream.
+func (s *ChatService) streamHedged(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, hedge provider.Provider) (<-chan provider.StreamChunk, error) {
+	starts := make(chan hedgeStart, 2)
+	cancels := make(map[string]context.CancelFunc, 2)
+	launch := func(p provider.Provider, cfg provider.ProviderConfig, release func()) {
+		params := prepared.paramsFor(p.Name(), cfg)
+		callCtx, cancel := context.WithCancel(ctx)
+		cancels[p.Name()] = cancel
+		context.AfterFunc(callCtx, release) // Every stream is cancelled once abandoned or forwarded
+		go func() {
+			chunks, err := p.GenerateReplyStream(s.observeHeadroom(callCtx, p.Name()), params)
+			start := hedgeStart{provider: p, cfg: cfg, params: params, chunks: chunks, cancel: cancel, err: err}
+			if err == nil {
+				start.first, start.gotFirst = <-chunks
+			}
+			starts <- start
+		}()
+	}
+	// discard cancels streams other than keep that are still waiting for
+	// their first chunk, and abandons them once they report back.
+	discard := func(keep string, n int) {
+		for name, cancel := range cancels {
+			if name != keep {
+				cancel()
+			}
+		}
+		go func() {
+			for i := 0; i < n; i++ {
+				(<-starts).abandon()
+			}
+		}()
+	}
+
+	primary := prepared.provider
+	launch(primary, prepared.providerCfg, func() {}) // The caller holds the primary's slot
+	pending := 1
+
+	timer := time.NewTimer(hedgeDelay(req))
+	defer timer.Stop()
+
+	obs := metrics.HedgeObservation{Primary: primary.Name(), Hedge: hedge.Name()}
+	var failed *hedgeStart
+	for {
+		select {
+		case <-timer.C:
+			release, ok := s.tryAcquireSlot(req, hedge.Name())
+			if !ok {
+				s.skipHedge(ctx, primary, hedge)
+				break
+			}
+			slog.InfoContext(ctx, "no first token from primary provider, sending hedge stream",
+				"primary", primary.Name(),
+				"hedge", hedge.Name(),
+				"delay", hedgeDelay(req),
+			)
+			prepared.hedged = true
+			obs.Fired = true
+			launch(hedge, s.buildProviderConfig(ctx, req, hedge.Name()), release)
+			pending++
+
+		case start := <-starts:
+			pending--
+			if start.succeeded() {
+				if start.provider != primary {
+					obs.HedgeWon = true
+					s.useHedgeWinner(ctx, prepared, start.provider, start.cfg)
+				}
+				if failed != nil {
+					s.abandonFailed(ctx, failed)
+				}
+				discard(start.provider.Name(), pending)
+				s.metrics.ObserveHedge(obs)
+				first := start.first
+				return forwardStream(ctx, &first, start.chunks, start.cancel), nil
+			}
+
+			if start.err != nil {
+				s.reportProviderCall(ctx, start.provider.Name(), start.params, provider.GenerateResult{}, start.err)
+			} else if start.gotFirst {
+				s.reportProviderError(start.provider.Name(), start.first.Error)
+			}
+			if failed == nil {
+				failed = &start
+			} else {
+				s.abandonFailed(ctx, &start)
+			}
+			if pending > 0 {
+				continue
+			}
+
+			// Every stream failed: surface the first failure
+			s.metrics.ObserveHedge(obs)
+			if failed.err != nil {
+				failed.cancel()
+				return nil, failed.err
+			}
+			var first *provider.StreamChunk
+			if failed.gotFirst {
+				first = &failed.first
+			}
+			return forwardStream(ctx, first, failed.chunks, failed.cancel), nil
+
+		case <-ctx.Done():
+			if failed != nil {
+				s.abandonFailed(ctx, failed)
+			}
+			discard("", pending)
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// forwardStream replays first (if any) followed by the rest of chunks, and
+// releases the stream's context when the stream ends or ctx is cancelled.
+func forwardStream(ctx context.Context, first *provider.StreamChunk, chunks <-chan provider.StreamChunk, cancel context.CancelFunc) <-chan provider.StreamChunk {
+	out := make(chan provider.StreamChunk)
+	go func() {
+		defer close(out)
+		defer hedgeStart{chunks: chunks, cancel: cancel}.abandon()
+
+		if first != nil {
+			select {
+			case out <- *first:
+			case <-ctx.Done():
+				return
+			}
+		}
+		for chunk := range chunks {
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/qos"
+)
+
+// slowProvider answers after delay and records whether it was cancelled first.
+type slowProvider struct {
+	*mockProvider
+	delay     time.Duration
+	cancelled atomic.Bool
+}
+
+func newSlowProvider(name string, delay time.Duration) *slowProvider {
+	return &slowProvider{mockProvider: newMockProvider(name), delay: delay}
+}
+
+func (p *slowProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	select {
+	case <-time.After(p.delay):
+		return provider.GenerateResult{Text: "from " + p.name, Model: p.name + "-model"}, nil
+	case <-ctx.Done():
+		p.cancelled.Store(true)
+		return provider.GenerateResult{}, ctx.Err()
+	}
+}
+
+func (p *slowProvider) GenerateReplyStream(ctx context.Context, params provider.GenerateParams) (<-chan provider.StreamChunk, error) {
+	ch := make(chan provider.StreamChunk)
+	go func() {
+		defer close(ch)
+		select {
+		case <-time.After(p.delay):
+		case <-ctx.Done():
+			p.cancelled.Store(true)
+			return
+		}
+		for _, chunk := range []provider.StreamChunk{
+			{Type: provider.ChunkTypeText, Text: "from " + p.name},
+			{Type: provider.ChunkTypeComplete, Model: p.name + "-model"},
+		} {
+			select {
+			case ch <- chunk:
+			case <-ctx.Done():
+				p.cancelled.Store(true)
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func newHedgeChatService(openai, gemini provider.Provider) (*ChatService, *metrics.Registry) {
+	registry := metrics.NewRegistry()
+	svc := &ChatService{
+		openaiProvider:    openai,
+		geminiProvider:    gemini,
+		anthropicProvider: newMockProvider("anthropic"),
+	}
+	WithMetrics(registry)(svc)
+	return svc, registry
+}
+
+func hedgedRequest(delayMs int32) *pb.GenerateReplyRequest {
+	return &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableHedging:     true,
+		HedgeDelayMs:      delayMs,
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool, what string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGenerateReply_HedgeWins(t *testing.T) {
+	slowOpenAI := newSlowProvider("openai", time.Minute)
+	fastGemini := newSlowProvider("gemini", 0)
+	svc, registry := newHedgeChatService(slowOpenAI, fastGemini)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai", "gemini"))
+
+	resp, err := svc.GenerateReply(ctx, hedgedRequest(20))
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Provider != pb.Provider_PROVIDER_GEMINI || resp.Text != "from gemini" || !resp.Hedged {
+		t.Errorf("unexpected response: provider=%v text=%q hedged=%v", resp.Provider, resp.Text, resp.Hedged)
+	}
+	waitFor(t, slowOpenAI.cancelled.Load, "primary cancellation")
+
+	waitFor(t, func() bool { return len(registry.Snapshot().Hedges) == 1 }, "hedge metrics")
+	stats := registry.Snapshot().Hedges[0]
+	if stats.Primary != "openai" || stats.Hedge != "gemini" || stats.Fired != 1 || stats.HedgeWins != 1 {
+		t.Errorf("unexpected hedge stats: %+v", stats)
+	}
+}
+
+func TestGenerateReply_PrimaryAnswersBeforeHedge(t *testing.T) {
+	gemini := newSlowProvider("gemini", 0)
+	svc, registry := newHedgeChatService(newSlowProvider("openai", 0), gemini)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai", "gemini"))
+
+	resp, err := svc.GenerateReply(ctx, hedgedRequest(60000))
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Provider != pb.Provider_PROVIDER_OPENAI || resp.Hedged {
+		t.Errorf("unexpected response: provider=%v hedged=%v", resp.Provider, resp.Hedged)
+	}
+	if stats := registry.Snapshot().Hedges[0]; stats.Requests != 1 || stats.Fired != 0 {
+		t.Errorf("unexpected hedge stats: %+v", stats)
+	}
+}
+
+func TestGenerateReply_HedgeTakesQoSSlot(t *testing.T) {
+	tests := []struct {
+		name       string
+		slots      int
+		wantHedged bool
+	}{
+		{"slot free", 2, true},
+		{"no slot free", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gemini := newSlowProvider("gemini", 0)
+			svc, _ := newHedgeChatService(newSlowProvider("openai", 100*time.Millisecond), gemini)
+			WithQoS(qos.NewLimiter(qos.Config{MaxConcurrent: tt.slots}))(svc)
+			ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai", "gemini"))
+
+			resp, err := svc.GenerateReply(ctx, hedgedRequest(20))
+			if err != nil {
+				t.Fatalf("GenerateReply failed: %v", err)
+			}
+			if resp.Hedged != tt.wantHedged {
+				t.Errorf("Hedged = %v, want %v", resp.Hedged, tt.wantHedged)
+			}
+			waitFor(t, func() bool { active, _ := svc.limiter.Stats(); return active == 0 }, "slots released")
+		})
+	}
+}
+
+func TestGenerateReply_HedgeRequiresTenantProvider(t *testing.T) {
+	svc, _ := newHedgeChatService(newSlowProvider("openai", 0), newSlowProvider("gemini", 0))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	prepared, err := svc.prepareRequest(ctx, hedgedRequest(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hedge := svc.hedgeTarget(ctx, hedgedRequest(20), prepared); hedge != nil {
+		t.Errorf("hedge target = %s, want none for a provider the tenant has not enabled", hedge.Name())
+	}
+}
+
+func TestStreamHedged_FirstTokenWins(t *testing.T) {
+	slowOpenAI := newSlowProvider("openai", time.Minute)
+	svc, _ := newHedgeChatService(slowOpenAI, newSlowProvider("gemini", 0))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai", "gemini"))
+	req := hedgedRequest(20)
+
+	prepared, err := svc.prepareRequest(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := svc.streamHedged(ctx, req, prepared, svc.hedgeTarget(ctx, req, prepared))
+	if err != nil {
+		t.Fatalf("streamHedged failed: %v", err)
+	}
+
+	var got []provider.StreamChunk
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	if len(got) != 2 || got[0].Text != "from gemini" || got[1].Type != provider.ChunkTypeComplete {
+		t.Errorf("unexpected chunks: %+v", got)
+	}
+	if prepared.provider.Name() != "gemini" || !prepared.hedged {
+		t.Errorf("prepared provider = %s, hedged = %v; want gemini, true", prepared.provider.Name(), prepared.hedged)
+	}
+	waitFor(t, slowOpenAI.cancelled.Load, "primary stream cancellation")
+}
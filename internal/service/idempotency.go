@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/redis"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultIdempotencyTTL is how long a completed response is replayed for retries.
+	defaultIdempotencyTTL = 24 * time.Hour
+
+	// idempotencyLockTTL is how long an in-flight request holds its key
+	// without extending it, e.g. after the instance running it died.
+	idempotencyLockTTL = 5 * time.Minute
+
+	// idempotencyProcessing marks a request whose first attempt is still running.
+	idempotencyProcessing = "processing"
+)
+
+// WithIdempotency enables idempotent GenerateReply requests backed by Redis.
+// A ttl of zero uses the default 24h replay window.
+func WithIdempotency(client *redis.Client, ttl time.Duration) ChatServiceOption {
+	return func(s *ChatService) {
+		if ttl <= 0 {
+			ttl = defaultIdempotencyTTL
+		}
+		s.idempotencyStore = client
+		s.idempotencyTTL = ttl
+	}
+}
+
+// WithIdempotencyFallback sets what happens when Redis is unavailable: with
+// redis.PolicyMemory request IDs are tracked per instance until Redis
+// recovers, with redis.PolicyFailClosed idempotent requests are rejected
+// with Unavailable. Without this option idempotency fails closed.
+func WithIdempotencyFallback(policy string, tracker *redis.FallbackTracker) ChatServiceOption {
+	return func(s *ChatService) {
+		s.idempotencyMemory = nil
+		if policy == redis.PolicyMemory {
+			s.idempotencyMemory = redis.NewMemoryStore()
+		}
+		s.redisFallbacks = tracker
+		tracker.Register(redis.FeatureIdempotency, policy)
+	}
+}
+
+// idempotencyStore is the subset of Redis used for idempotency, satisfied by
+// both *redis.Client and the in-memory fallback.
+type idempotencyStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+// idempotentRecord is the stored outcome of a completed idempotent request.
+type idempotentRecord struct {
+	Response []byte `json:"response"` // Proto-encoded GenerateReplyResponse
+}
+
+// idempotencyClaim is held by the request that owns an idempotency key.
+type idempotencyClaim struct {
+	store   idempotencyStore
+	key     string
+	ttl     time.Duration
+	lockTTL time.Duration
+
+	stopHold context.CancelFunc // Stops extending the lock; nil until hold
+	held     chan struct{}      // Closed once the lock is no longer extended
+}
+
+// idempotencyKey identifies a request by tenant, request_id and fingerprint.
+// request_id is also the thread ID, so each turn of a thread is keyed by its
+// payload and only exact retries are replayed. Only single-key commands touch
+// it, so it needs no hash tag under Redis Cluster.
+func idempotencyKey(tenantID, requestID, fingerprint string) string {
+	return fmt.Sprintf("airborne:idem:%s:%s:%s", tenantID, requestID, fingerprint)
+}
+
+// requestFingerprint hashes the request, so a request_id reused with a
+// different payload, such as the next turn of a thread, is never answered
+// with an unrelated response.
+func requestFingerprint(req *pb.GenerateReplyRequest) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// claimIdempotency reserves the request for this tenant, or returns the stored
+// response when the same request already completed. It returns a nil claim when
+// idempotency is not requested or not available.
+func (s *ChatService) claimIdempotency(ctx context.Context, req *pb.GenerateReplyRequest) (*idempotencyClaim, *pb.GenerateReplyResponse, error) {
+	if !req.Idempotent {
+		return nil, nil, nil
+	}
+	if req.RequestId == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "request_id is required for idempotent requests")
+	}
+	if _, err := validation.ValidateOrGenerateRequestID(req.RequestId); err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.idempotencyStore == nil {
+		slog.WarnContext(ctx, "idempotency requested but no Redis is configured, proceeding without",
+			"request_id", req.RequestId,
+		)
+		return nil, nil, nil
+	}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "failed to fingerprint request")
+	}
+
+	key := idempotencyKey(auth.TenantIDFromContext(ctx), req.RequestId, fingerprint)
+	var store idempotencyStore = s.idempotencyStore
+	acquired, err := store.SetNX(ctx, key, idempotencyProcessing, idempotencyLockTTL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, status.FromContextError(ctx.Err()).Err()
+		}
+		s.redisFallbacks.Degraded(redis.FeatureIdempotency, err)
+		if s.idempotencyMemory == nil {
+			slog.WarnContext(ctx, "idempotency store unavailable, failing closed", "error", err, "request_id", req.RequestId)
+			return nil, nil, status.Error(codes.Unavailable, "idempotency store unavailable")
+		}
+		store = s.idempotencyMemory
+		acquired, _ = store.SetNX(ctx, key, idempotencyProcessing, idempotencyLockTTL)
+	} else {
+		s.redisFallbacks.Recovered(redis.FeatureIdempotency)
+	}
+	if acquired {
+		claim := &idempotencyClaim{
+			store:   store,
+			key:     key,
+			ttl:     s.idempotencyTTL,
+			lockTTL: idempotencyLockTTL,
+		}
+		claim.hold(ctx)
+		return claim, nil, nil
+	}
+
+	stored, err := store.Get(ctx, key)
+	if err != nil && !redis.IsNil(err) {
+		slog.WarnContext(ctx, "failed to read idempotent response", "error", err, "request_id", req.RequestId)
+		return nil, nil, status.Error(codes.Unavailable, "idempotency store unavailable")
+	}
+	if stored == "" || stored == idempotencyProcessing {
+		return nil, nil, status.Error(codes.Aborted, "an identical request with this request_id is already in progress")
+	}
+
+	var record idempotentRecord
+	if err := json.Unmarshal([]byte(stored), &record); err != nil {
+		slog.WarnContext(ctx, "corrupt idempotent record", "error", err, "request_id", req.RequestId)
+		return nil, nil, status.Error(codes.Internal, "failed to read stored response")
+	}
+	resp := &pb.GenerateReplyResponse{}
+	if err := proto.Unmarshal(record.Response, resp); err != nil {
+		slog.WarnContext(ctx, "corrupt idempotent response", "error", err, "request_id", req.RequestId)
+		return nil, nil, status.Error(codes.Internal, "failed to read stored response")
+	}
+	resp.Cached = true
+
+	slog.DebugContext(ctx, "returning cached idempotent response", "request_id", req.RequestId)
+	return nil, resp, nil
+}
+
+// hold extends the claim's lock every half lockTTL until finish, so a
+// generation that runs longer than lockTTL keeps its key.
+func (c *idempotencyClaim) hold(ctx context.Context) {
+	holdCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	c.stopHold = stop
+	c.held = make(chan struct{})
+	go func() {
+		defer close(c.held)
+		ticker := time.NewTicker(c.lockTTL / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-holdCtx.Done():
+				return
+			case <-ticker.C:
+				if err := c.store.Set(holdCtx, c.key, idempotencyProcessing, c.lockTTL); err != nil && holdCtx.Err() == nil {
+					slog.WarnContext(ctx, "failed to extend idempotency lock", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// finish stores a successful response for replay, or releases the key on
+// failure so the client can retry. A nil claim is a no-op.
+func (c *idempotencyClaim) finish(ctx context.Context, resp *pb.GenerateReplyResponse, err error) {
+	if c == nil {
+		return
+	}
+	if c.stopHold != nil {
+		c.stopHold()
+		<-c.held // An in-flight extension must not overwrite the outcome
+	}
+
+	// Use a fresh context so a cancelled client does not leave the key locked
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err != nil || resp == nil {
+		if delErr := c.store.Del(storeCtx, c.key); delErr != nil {
+			slog.WarnContext(ctx, "failed to release idempotency key", "error", delErr)
+		}
+		return
+	}
+
+	data, marshalErr := proto.Marshal(resp)
+	if marshalErr == nil {
+		var record []byte
+		record, marshalErr = json.Marshal(idempotentRecord{Response: data})
+		if marshalErr == nil {
+			if setErr := c.store.Set(storeCtx, c.key, string(record), c.ttl); setErr != nil {
+				slog.WarnContext(ctx, "failed to store idempotent response", "error", setErr)
+			}
+			return
+		}
+	}
+
+	slog.WarnContext(ctx, "failed to encode idempotent response", "error", marshalErr)
+	if delErr := c.store.Del(storeCtx, c.key); delErr != nil {
+		slog.WarnContext(ctx, "failed to release idempotency key", "error", delErr)
+	}
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/redis"
+	"github.com/alicebob/miniredis/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestRedis starts a miniredis server and returns a connected client.
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client, err := redis.NewClient(redis.Config{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func newIdempotentChatService(t *testing.T, mockOpenAI *mockProvider) *ChatService {
+	t.Helper()
+	_, client := newTestRedis(t)
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	WithIdempotency(client, 0)(svc)
+	return svc
+}
+
+func TestGenerateReply_IdempotentReplay(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	svc := newIdempotentChatService(t, mockOpenAI)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		RequestId:         "req-1",
+		Idempotent:        true,
+	}
+
+	first, err := svc.GenerateReply(ctx, req)
+	if err != nil {
+		t.Fatalf("first GenerateReply failed: %v", err)
+	}
+	if first.Cached {
+		t.Error("first response should not be cached")
+	}
+
+	second, err := svc.GenerateReply(ctx, req)
+	if err != nil {
+		t.Fatalf("second GenerateReply failed: %v", err)
+	}
+	if !second.Cached {
+		t.Error("expected replayed response to be marked cached")
+	}
+	if second.Text != first.Text || second.ResponseId != first.ResponseId {
+		t.Errorf("expected replayed response to match, got %q/%q", second.Text, second.ResponseId)
+	}
+	if len(mockOpenAI.generateCalls) != 1 {
+		t.Errorf("expected provider to be called once, got %d", len(mockOpenAI.generateCalls))
+	}
+}
+
+func TestGenerateReply_IdempotencyScopedToTenant(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	svc := newIdempotentChatService(t, mockOpenAI)
+
+	tenantA := createTestTenantConfig("openai")
+	tenantB := createTestTenantConfig("openai")
+	tenantB.TenantID = "other-tenant"
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		RequestId:         "req-1",
+		Idempotent:        true,
+	}
+
+	if _, err := svc.GenerateReply(ctxWithChatPermissionAndTenant("a", tenantA), req); err != nil {
+		t.Fatalf("tenant A GenerateReply failed: %v", err)
+	}
+	resp, err := svc.GenerateReply(ctxWithChatPermissionAndTenant("b", tenantB), req)
+	if err != nil {
+		t.Fatalf("tenant B GenerateReply failed: %v", err)
+	}
+	if resp.Cached {
+		t.Error("request_id from another tenant must not be replayed")
+	}
+	if len(mockOpenAI.generateCalls) != 2 {
+		t.Errorf("expected provider to be called per tenant, got %d", len(mockOpenAI.generateCalls))
+	}
+}
+
+func TestGenerateReply_IdempotentTurnsInOneThread(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	svc := newIdempotentChatService(t, mockOpenAI)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	turn := func(input string) *pb.GenerateReplyRequest {
+		return &pb.GenerateReplyRequest{
+			UserInput:         input,
+			PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+			RequestId:         "req-1",
+			Idempotent:        true,
+		}
+	}
+	for _, input := range []string{"Hello", "And tomorrow?"} {
+		resp, err := svc.GenerateReply(ctx, turn(input))
+		if err != nil {
+			t.Fatalf("turn %q failed: %v", input, err)
+		}
+		if resp.Cached {
+			t.Errorf("turn %q should not be cached", input)
+		}
+	}
+
+	// A retry of either turn is replayed
+	for _, input := range []string{"Hello", "And tomorrow?"} {
+		resp, err := svc.GenerateReply(ctx, turn(input))
+		if err != nil || !resp.Cached {
+			t.Errorf("expected the retry of %q to be replayed, got %v (err %v)", input, resp, err)
+		}
+	}
+	if len(mockOpenAI.generateCalls) != 2 {
+		t.Errorf("expected one provider call per turn, got %d", len(mockOpenAI.generateCalls))
+	}
+}
+
+func TestGenerateReply_IdempotencyReleasedOnFailure(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mockOpenAI.generateErr = errors.New("provider down")
+	svc := newIdempotentChatService(t, mockOpenAI)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		RequestId:         "req-1",
+		Idempotent:        true,
+	}
+	if _, err := svc.GenerateReply(ctx, req); err == nil {
+		t.Fatal("expected provider error")
+	}
+
+	// Retry after the failure must reach the provider again
+	mockOpenAI.generateErr = nil
+	resp, err := svc.GenerateReply(ctx, req)
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if resp.Cached {
+		t.Error("retry after failure should not be cached")
+	}
+}
+
+func TestGenerateReply_IdempotencyInProgress(t *testing.T) {
+	mr, client := newTestRedis(t)
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	WithIdempotency(client, 0)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:  "Hello",
+		RequestId:  "req-1",
+		Idempotent: true,
+	}
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		t.Fatalf("requestFingerprint failed: %v", err)
+	}
+	if err := mr.Set(idempotencyKey("test-tenant", "req-1", fingerprint), idempotencyProcessing); err != nil {
+		t.Fatalf("failed to seed key: %v", err)
+	}
+
+	_, err = svc.GenerateReply(ctx, req)
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted for in-flight request, got %v", err)
+	}
+}
+
+func TestGenerateReply_IdempotentRequiresRequestID(t *testing.T) {
+	svc := newIdempotentChatService(t, newMockProvider("openai"))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Hello", Idempotent: true})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGenerateReply_IdempotentWithoutRedisProceeds(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		RequestId:         "req-1",
+		Idempotent:        true,
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GenerateReply(ctx, req); err != nil {
+			t.Fatalf("GenerateReply failed: %v", err)
+		}
+	}
+	if len(mockOpenAI.generateCalls) != 2 {
+		t.Errorf("expected provider calls without Redis, got %d", len(mockOpenAI.generateCalls))
+	}
+}
+
+func TestIdempotencyClaim_NilFinish(t *testing.T) {
+	var c *idempotencyClaim
+	c.finish(context.Background(), nil, nil)
+}
+
+func TestIdempotencyClaim_HoldExtendsLock(t *testing.T) {
+	ctx := context.Background()
+	store := redis.NewMemoryStore()
+	claim := &idempotencyClaim{store: store, key: "airborne:idem:ai8:req-1", ttl: time.Hour, lockTTL: 50 * time.Millisecond}
+	if ok, _ := store.SetNX(ctx, claim.key, idempotencyProcessing, claim.lockTTL); !ok {
+		t.Fatal("SetNX failed")
+	}
+
+	claim.hold(ctx)
+	time.Sleep(4 * claim.lockTTL)
+	if got, _ := store.Get(ctx, claim.key); got != idempotencyProcessing {
+		t.Fatalf("lock = %q after outliving its TTL, want it held", got)
+	}
+
+	claim.finish(ctx, nil, errors.New("provider failed"))
+	time.Sleep(claim.lockTTL)
+	if got, _ := store.Get(ctx, claim.key); got != "" {
+		t.Errorf("lock = %q after finish, want it released", got)
+	}
+}
+
+func TestGenerateReply_IdempotencyRedisDownFailsClosed(t *testing.T) {
+	mr, client := newTestRedis(t)
+	mockOpenAI := newMockProvider("openai")
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tracker := redis.NewFallbackTracker()
+	WithIdempotency(client, 0)(svc)
+	WithIdempotencyFallback(redis.PolicyFailClosed, tracker)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:  "Hello",
+		RequestId:  "req-1",
+		Idempotent: true,
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable when Redis is down, got %v", err)
+	}
+	if len(mockOpenAI.generateCalls) != 0 {
+		t.Errorf("expected no provider calls, got %d", len(mockOpenAI.generateCalls))
+	}
+	if d := tracker.Degradations(); len(d) != 1 || d[0].Feature != redis.FeatureIdempotency {
+		t.Errorf("expected idempotency degradation, got %+v", d)
+	}
+}
+
+func TestGenerateReply_IdempotencyRedisDownUsesMemory(t *testing.T) {
+	mr, client := newTestRedis(t)
+	mockOpenAI := newMockProvider("openai")
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tracker := redis.NewFallbackTracker()
+	WithIdempotency(client, 0)(svc)
+	WithIdempotencyFallback(redis.PolicyMemory, tracker)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		RequestId:         "req-1",
+		Idempotent:        true,
+	}
+	if _, err := svc.GenerateReply(ctx, req); err != nil {
+		t.Fatalf("first GenerateReply failed: %v", err)
+	}
+	second, err := svc.GenerateReply(ctx, req)
+	if err != nil {
+		t.Fatalf("second GenerateReply failed: %v", err)
+	}
+	if !second.Cached {
+		t.Error("expected retry to be replayed from memory")
+	}
+	if len(mockOpenAI.generateCalls) != 1 {
+		t.Errorf("expected 1 provider call, got %d", len(mockOpenAI.generateCalls))
+	}
+	if st := tracker.Status(); len(st) != 1 || !st[0].Active || st[0].Activations != 2 {
+		t.Errorf("unexpected fallback status: %+v", st)
+	}
+}
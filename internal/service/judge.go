@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/validation"
+)
+
+const judgeInstructions = `You are judging candidate replies to the same request.
+Score each candidate from 0 to 10 against the criteria below, where 10 fully
+meets them, and explain each score in one sentence.
+Reply with a JSON object only, in this form:
+{"scores": [{"candidate": 1, "score": 7, "reason": "..."}]}
+Include every candidate, numbered as given.
+
+Criteria:
+`
+
+// judgeScores is the JSON the judge model returns.
+type judgeScores struct {
+	Scores []struct {
+		Candidate int     `json:"candidate"`
+		Score     float64 `json:"score"`
+		Reason    string  `json:"reason"`
+	} `json:"scores"`
+}
+
+// judgePolicy returns the tenant's judge policy for the request's use_case
+// metadata and the use case it is configured under.
+func judgePolicy(ctx context.Context, req *pb.GenerateReplyRequest) (string, tenant.JudgePolicy, bool) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil {
+		return "", tenant.JudgePolicy{}, false
+	}
+	useCase := req.Metadata[tenant.JudgeUseCaseKey]
+	policy, ok := tenantCfg.JudgePolicy(useCase)
+	if _, own := tenantCfg.Judge[useCase]; !own {
+		useCase = tenant.JudgeDefaultUseCase
+	}
+	return useCase, policy, ok
+}
+
+// judgeable reports whether a reply can compete as a judged candidate:
+// replies that call tools or were blocked have no text to compare.
+func judgeable(result provider.GenerateResult) bool {
+	return strings.TrimSpace(result.Text) != "" && len(result.ToolCalls) == 0 && len(result.ComputerActions) == 0 &&
+		!result.RequiresToolOutput && result.SafetyBlock == nil
+}
+
+// judgeCandidates applies the tenant's judge policy to a validated reply. The
+// policy's other candidates are generated from the same provider and
+// parameters, a judge model scores them all against the policy's criteria,
+// and the best-scoring reply is returned; ties go to the earliest. Candidates
+// that fail the tenant's validation rules, call tools or were blocked are
+// left out. The other candidates and the judge call are charged to the
+// tenant's spend. If fewer than two candidates remain or judging fails,
+// result is returned unchanged without a JudgeResult.
+func (s *ChatService) judgeCandidates(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, result provider.GenerateResult) (provider.GenerateResult, *pb.JudgeResult) {
+	useCase, policy, ok := judgePolicy(ctx, req)
+	if !ok || !judgeable(result) {
+		return result, nil
+	}
+
+	candidates := []provider.GenerateResult{result}
+	rules, _ := replyRules(ctx)
+	for _, c := range s.generateCandidates(ctx, prepared, policy.CandidateCount()-1) {
+		if judgeable(c) && (!rules.Enabled() || len(rules.Check(c.Text)) == 0) {
+			candidates = append(candidates, c)
+		} else {
+			s.recordSpend(ctx, candidateCost(prepared, c))
+		}
+	}
+	if len(candidates) < 2 {
+		return result, nil
+	}
+
+	judgement, judgeCost, err := s.scoreCandidates(ctx, req, prepared, policy, candidates)
+	s.recordSpend(ctx, judgeCost)
+	winner := 0
+	if err != nil {
+		slog.WarnContext(ctx, "judge failed, keeping the first candidate",
+			"use_case", useCase,
+			"error", err,
+			"request_id", prepared.requestID,
+		)
+		accesslog.Annotate(ctx, "judge_error", err.Error())
+	} else {
+		winner = int(judgement.Winner)
+	}
+	// The returned reply is charged with the response
+	for i, c := range candidates {
+		if i != winner {
+			s.recordSpend(ctx, candidateCost(prepared, c))
+		}
+	}
+	if err != nil {
+		return result, nil
+	}
+
+	judgement.UseCase = useCase
+	accesslog.Annotate(ctx, "judge_use_case", useCase, "judge_candidates", len(candidates), "judge_winner", judgement.Winner)
+	return candidates[winner], judgement
+}
+
+// generateCandidates generates n more replies to the prepared request
+// concurrently. Failed calls are logged and left out.
+func (s *ChatService) generateCandidates(ctx context.Context, prepared *preparedRequest, n int) []provider.GenerateResult {
+	results := make([]provider.GenerateResult, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = prepared.provider.GenerateReply(s.observeHeadroom(ctx, prepared.provider.Name()), prepared.params)
+			s.reportProviderCall(ctx, prepared.provider.Name(), prepared.params, results[i], errs[i])
+		}()
+	}
+	wg.Wait()
+
+	var out []provider.GenerateResult
+	for i, err := range errs {
+		if err != nil {
+			slog.WarnContext(ctx, "judge candidate failed",
+				"provider", prepared.provider.Name(),
+				"error", err,
+				"request_id", prepared.requestID,
+			)
+			continue
+		}
+		out = append(out, results[i])
+	}
+	return out
+}
+
+// scoreCandidates asks the policy's judge model to score the candidates. It
+// returns the scores with the winner set, and the cost of the judge call.
+func (s *ChatService) scoreCandidates(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, policy tenant.JudgePolicy, candidates []provider.GenerateResult) (*pb.JudgeResult, float64, error) {
+	judgeName := policy.Provider
+	if judgeName == "" {
+		judgeName = prepared.provider.Name()
+	}
+	judge := s.providerByName(judgeName)
+	if judge == nil {
+		return nil, 0, fmt.Errorf("judge provider %q is not available", judgeName)
+	}
+
+	var input strings.Builder
+	fmt.Fprintf(&input, "Request:\n%s\n", prepared.params.UserInput)
+	for i, c := range candidates {
+		fmt.Fprintf(&input, "\nCandidate %d:\n%s\n", i+1, strings.TrimSpace(c.Text))
+	}
+	params := provider.GenerateParams{
+		Instructions:  judgeInstructions + policy.Criteria,
+		UserInput:     input.String(),
+		OverrideModel: policy.Model,
+		Config:        s.buildProviderConfig(ctx, req, judgeName),
+		RequestID:     prepared.params.RequestID,
+		ClientID:      prepared.params.ClientID,
+	}
+	out, err := judge.GenerateReply(s.observeHeadroom(ctx, judgeName), params)
+	s.reportProviderCall(ctx, judgeName, params, out, err)
+	if err != nil {
+		return nil, 0, err
+	}
+	cost := estimateCost(judgeName, out.Model, out.Usage, out.GroundingQueries).Total()
+
+	var reply judgeScores
+	if err := json.Unmarshal([]byte(validation.StripCodeFence(out.Text)), &reply); err != nil {
+		return nil, cost, fmt.Errorf("judge reply is not valid JSON: %w", err)
+	}
+	judgement := &pb.JudgeResult{JudgeProvider: mapProviderToProto(judgeName), JudgeModel: out.Model}
+	for _, c := range candidates {
+		judgement.Candidates = append(judgement.Candidates, &pb.JudgeCandidate{Model: c.Model})
+	}
+	scored := 0
+	for _, sc := range reply.Scores {
+		if sc.Candidate < 1 || sc.Candidate > len(candidates) {
+			continue
+		}
+		jc := judgement.Candidates[sc.Candidate-1]
+		jc.Score = min(max(sc.Score, 0), 10)
+		jc.Reason = sc.Reason
+		scored++
+	}
+	if scored == 0 {
+		return nil, cost, errors.New("judge reply scored no candidates")
+	}
+	for i, c := range judgement.Candidates {
+		if c.Score > judgement.Candidates[judgement.Winner].Score {
+			judgement.Winner = int32(i)
+		}
+	}
+	return judgement, cost, nil
+}
+
+// candidateCost estimates the cost of a candidate reply.
+func candidateCost(prepared *preparedRequest, result provider.GenerateResult) float64 {
+	return estimateCost(prepared.provider.Name(), result.Model, result.Usage, result.GroundingQueries).Total()
+}
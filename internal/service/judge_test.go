@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// judgeProvider answers generation calls with numbered candidates and judge
+// calls with judgeReply.
+type judgeProvider struct {
+	*mockProvider
+	judgeReply string
+
+	mu         sync.Mutex
+	candidates int
+	judgeCalls []provider.GenerateParams
+}
+
+func (p *judgeProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := provider.GenerateResult{Model: "mock-model", Usage: &provider.Usage{InputTokens: 10, OutputTokens: 20, TotalTokens: 30}}
+	if strings.HasPrefix(params.Instructions, judgeInstructions) {
+		p.judgeCalls = append(p.judgeCalls, params)
+		result.Model = "judge-model"
+		result.Text = p.judgeReply
+		return result, nil
+	}
+	p.candidates++
+	result.Text = fmt.Sprintf("candidate %d", p.candidates)
+	return result, nil
+}
+
+func judgingTenant(policies map[string]tenant.JudgePolicy) *tenant.TenantConfig {
+	cfg := createTestTenantConfig("openai")
+	cfg.Judge = policies
+	return cfg
+}
+
+func TestGenerateReply_JudgePicksBestCandidate(t *testing.T) {
+	openai := &judgeProvider{
+		mockProvider: newMockProvider("openai"),
+		judgeReply: "```json\n" + `{"scores": [
+			{"candidate": 1, "score": 4, "reason": "vague"},
+			{"candidate": 2, "score": 9, "reason": "cites the clause"},
+			{"candidate": 3, "score": 12, "reason": "out of range"},
+			{"candidate": 7, "score": 10}
+		]}` + "\n```",
+	}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+	ctx := ctxWithChatPermissionAndTenant("test-client", judgingTenant(map[string]tenant.JudgePolicy{
+		"legal_summary": {Candidates: 3, Criteria: "Accuracy and citations"},
+	}))
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Summarize the contract",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		Metadata:          map[string]string{"use_case": "legal_summary"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if openai.candidates != 3 || len(openai.judgeCalls) != 1 {
+		t.Fatalf("got %d candidates and %d judge calls, want 3 and 1", openai.candidates, len(openai.judgeCalls))
+	}
+	judge := resp.Judge
+	if judge == nil || judge.UseCase != "legal_summary" || judge.JudgeModel != "judge-model" || len(judge.Candidates) != 3 {
+		t.Fatalf("unexpected judge result: %+v", judge)
+	}
+	// Out-of-range scores are clamped, and unknown candidates ignored
+	if judge.Winner != 2 || judge.Candidates[2].Score != 10 || judge.Candidates[1].Reason != "cites the clause" {
+		t.Errorf("unexpected scores: winner=%d %+v", judge.Winner, judge.Candidates)
+	}
+
+	call := openai.judgeCalls[0]
+	if !strings.HasSuffix(call.Instructions, "Accuracy and citations") || !strings.Contains(call.UserInput, "Summarize the contract") {
+		t.Errorf("unexpected judge call: %+v", call)
+	}
+	if !strings.Contains(call.UserInput, "Candidate 3:\n"+resp.Text+"\n") {
+		t.Errorf("Text = %q, want the third candidate judged", resp.Text)
+	}
+}
+
+func TestGenerateReply_JudgePolicySelection(t *testing.T) {
+	policies := map[string]tenant.JudgePolicy{
+		"legal_summary": {Candidates: 2, Criteria: "Accuracy"},
+	}
+	tests := []struct {
+		name       string
+		policies   map[string]tenant.JudgePolicy
+		useCase    string
+		judgeReply string
+		wantJudge  bool
+	}{
+		{"other use case", policies, "chat", `{"scores": [{"candidate": 2, "score": 9}]}`, false},
+		{"no use case", policies, "", `{"scores": [{"candidate": 2, "score": 9}]}`, false},
+		{"default policy", map[string]tenant.JudgePolicy{"*": {Candidates: 2, Criteria: "Accuracy"}}, "chat", `{"scores": [{"candidate": 2, "score": 9}]}`, true},
+		{"invalid judge reply", policies, "legal_summary", "Candidate 2 is best", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openai := &judgeProvider{mockProvider: newMockProvider("openai"), judgeReply: tt.judgeReply}
+			svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+			ctx := ctxWithChatPermissionAndTenant("test-client", judgingTenant(tt.policies))
+
+			resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+				UserInput:         "Summarize the contract",
+				PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+				Metadata:          map[string]string{"use_case": tt.useCase},
+			})
+			if err != nil {
+				t.Fatalf("GenerateReply failed: %v", err)
+			}
+			if (resp.Judge != nil) != tt.wantJudge {
+				t.Fatalf("Judge = %+v, want judged=%v", resp.Judge, tt.wantJudge)
+			}
+			if tt.wantJudge && (resp.Judge.UseCase != "*" || resp.Text != "candidate 2") {
+				t.Errorf("unexpected judged reply %q: %+v", resp.Text, resp.Judge)
+			}
+			if !tt.wantJudge && resp.Text != "candidate 1" {
+				t.Errorf("Text = %q, want the first candidate", resp.Text)
+			}
+		})
+	}
+}
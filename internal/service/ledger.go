@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/isolation"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/providerhealth"
+)
+
+// UsageLedger records every provider API call for reconciliation against
+// provider invoices. *db.Repository implements it.
+type UsageLedger interface {
+	RecordProviderCall(ctx context.Context, call db.ProviderCall) error
+}
+
+// WithUsageLedger records every provider call, successful or failed, in
+// ledger. Calls are recorded whether or not their messages are persisted.
+func WithUsageLedger(ledger UsageLedger) ChatServiceOption {
+	return func(s *ChatService) {
+		s.ledger = ledger
+	}
+}
+
+// WithProviderHealth reports the outcome of every provider call to tracker,
+// for the provider health dashboard.
+func WithProviderHealth(tracker *providerhealth.Tracker) ChatServiceOption {
+	return func(s *ChatService) {
+		s.health = tracker
+	}
+}
+
+// httpStatusPattern finds an HTTP error status in a provider error message.
+var httpStatusPattern = regexp.MustCompile(`(?:^|[^\d.])([45]\d\d)(?:[^\d.]|$)`)
+
+// providerHTTPStatus returns the HTTP status of a provider call: 200 when it
+// succeeded, the error status named in err's message, or 0 when the call
+// failed without one (timeouts, connection errors).
+func providerHTTPStatus(err error) int {
+	if err == nil {
+		return 200
+	}
+	m := httpStatusPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(m[1])
+	return code
+}
+
+// reportProviderCall handles the outcome of a generation call to
+// providerName: rate-limit errors are reported to QoS and the call is
+// recorded in the usage ledger.
+func (s *ChatService) reportProviderCall(ctx context.Context, providerName string, params provider.GenerateParams, result provider.GenerateResult, err error) {
+	s.reportProviderError(providerName, err)
+	model := calledModel(params, result.Model)
+	call := db.ProviderCall{RequestID: params.RequestID, Provider: providerName, Model: model}
+	if err == nil {
+		if result.Usage != nil {
+			call.InputTokens = result.Usage.InputTokens
+			call.OutputTokens = result.Usage.OutputTokens
+		}
+		call.CostUSD = estimateCost(providerName, model, result.Usage, result.GroundingQueries).Total()
+	}
+	s.recordProviderCall(ctx, call, err)
+}
+
+// calledModel returns the model a call with params used: the model its
+// result named or, for failed calls, the one requested.
+func calledModel(params provider.GenerateParams, resultModel string) string {
+	if resultModel != "" {
+		return resultModel
+	}
+	return provider.SelectModel(params.Config.Model, "", params.OverrideModel)
+}
+
+// recordProviderCall records call in the provider health tracker and the
+// anomaly detector, and writes it to the usage ledger in the background,
+// attributed to the request's tenant and client key. err is the call's failure, if any. Cancelled calls
+// are left out of provider health, since they say nothing about the provider.
+func (s *ChatService) recordProviderCall(ctx context.Context, call db.ProviderCall, err error) {
+	call.TenantID = auth.TenantIDFromContext(ctx)
+	call.HTTPStatus = providerHTTPStatus(err)
+	if s.health != nil && !errors.Is(err, context.Canceled) {
+		s.health.Observe(call.TenantID, call.Provider, call.HTTPStatus, err)
+	}
+	s.observeUsage(ctx, call, err)
+	if s.ledger == nil {
+		return
+	}
+	if client := auth.ClientFromContext(ctx); client != nil {
+		call.KeyID = client.KeyHash()
+	}
+	if err != nil {
+		call.Error = truncateString(err.Error(), 1000)
+	}
+	call.CreatedAt = time.Now()
+
+	go func() {
+		ledgerCtx, cancel := context.WithTimeout(isolation.Detach(ctx), 10*time.Second)
+		defer cancel()
+		if err := s.ledger.RecordProviderCall(ledgerCtx, call); err != nil {
+			slog.ErrorContext(ctx, "failed to record provider call in usage ledger",
+				"error", err,
+				"provider", call.Provider,
+				"request_id", call.RequestID,
+			)
+		}
+	}()
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/providerhealth"
+)
+
+// chanLedger delivers recorded provider calls on a channel.
+type chanLedger chan db.ProviderCall
+
+func (l chanLedger) RecordProviderCall(ctx context.Context, call db.ProviderCall) error {
+	l <- call
+	return nil
+}
+
+func (l chanLedger) next(t *testing.T) db.ProviderCall {
+	t.Helper()
+	select {
+	case call := <-l:
+		return call
+	case <-time.After(time.Second):
+		t.Fatal("no provider call recorded")
+		return db.ProviderCall{}
+	}
+}
+
+func TestProviderHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 200},
+		{errors.New("POST /v1/responses: 429 Too Many Requests"), 429},
+		{errors.New("Error 503, Message: The model is overloaded."), 503},
+		{errors.New("context deadline exceeded"), 0},
+		{errors.New("request used 4000.5 tokens"), 0},
+	}
+	for _, tt := range tests {
+		if got := providerHTTPStatus(tt.err); got != tt.want {
+			t.Errorf("providerHTTPStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateReply_UsageLedger(t *testing.T) {
+	openai := newMockProvider("openai")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ledger := make(chanLedger, 4)
+	WithUsageLedger(ledger)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		RequestId:         "req-ok",
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	}
+	if _, err := svc.GenerateReply(ctx, req); err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	call := ledger.next(t)
+	if call.TenantID != "test-tenant" || call.RequestID != "req-ok" || call.Provider != "openai" || call.Model != "mock-model" {
+		t.Errorf("unexpected call attribution: %+v", call)
+	}
+	if call.InputTokens != 10 || call.OutputTokens != 20 || call.HTTPStatus != 200 || call.Error != "" {
+		t.Errorf("unexpected successful call: %+v", call)
+	}
+
+	// Failed calls are recorded with the requested model and error status
+	openai.generateResult = provider.GenerateResult{}
+	openai.generateErr = errors.New("POST /v1/responses: 503 Service Unavailable")
+	req.RequestId = "req-failed"
+	if _, err := svc.GenerateReply(ctx, req); err == nil {
+		t.Fatal("expected GenerateReply to fail")
+	}
+	call = ledger.next(t)
+	if call.RequestID != "req-failed" || call.Model != "test-model-openai" || call.HTTPStatus != 503 || call.Error == "" || call.InputTokens != 0 {
+		t.Errorf("unexpected failed call: %+v", call)
+	}
+}
+
+func TestGenerateReplyStream_UsageLedger(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ledger := make(chanLedger, 4)
+	WithUsageLedger(ledger)(svc)
+	stream := &mockGenerateReplyStream{ctx: ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))}
+
+	err := svc.GenerateReplyStream(&pb.GenerateReplyRequest{
+		RequestId:         "req-stream",
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	}, stream)
+	if err != nil {
+		t.Fatalf("GenerateReplyStream failed: %v", err)
+	}
+	call := ledger.next(t)
+	if call.RequestID != "req-stream" || call.Model != "mock-model" || call.HTTPStatus != 200 {
+		t.Errorf("unexpected streamed call: %+v", call)
+	}
+	select {
+	case extra := <-ledger:
+		t.Errorf("stream recorded more than one call: %+v", extra)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestGenerateReply_ProviderHealth(t *testing.T) {
+	openai := newMockProvider("openai")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	health := providerhealth.NewTracker(0)
+	WithProviderHealth(health)(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+	req := &pb.GenerateReplyRequest{UserInput: "Hello", PreferredProvider: pb.Provider_PROVIDER_OPENAI}
+
+	if _, err := svc.GenerateReply(ctx, req); err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	openai.generateErr = errors.New("POST /v1/responses: 401 Unauthorized")
+	if _, err := svc.GenerateReply(ctx, req); err == nil {
+		t.Fatal("expected GenerateReply to fail")
+	}
+
+	st, ok := health.Status("test-tenant", "openai")
+	if !ok || st.Calls != 2 || st.Errors != 1 || st.KeyState != providerhealth.KeyInvalid {
+		t.Errorf("openai health = %+v, %v", st, ok)
+	}
+}
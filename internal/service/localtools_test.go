@@ -0,0 +1,44 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+)
+
+func TestGenerateReply_LocalTools(t *testing.T) {
+	final := provider.GenerateResult{Text: "That comes to 324.48.", ResponseID: "resp-final", Model: "test-model-openai"}
+	openai := &resultsProvider{mockProvider: newMockProvider("openai"), results: []provider.GenerateResult{
+		toolCallResult("calculator", `{"expression":"(1250.40 - 980) * 1.2"}`), final,
+	}}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+	cfg := createTestTenantConfig("openai")
+	cfg.LocalTools = []string{"date_math", "calculator", "calculator"}
+	ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Add 20% to the difference.", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Text != final.Text {
+		t.Errorf("Text = %q, want the final reply", resp.Text)
+	}
+	tools := openai.generateCalls[0].Tools
+	if len(tools) != 2 || tools[0].Name != "calculator" || tools[1].Name != "date_math" || !strings.Contains(tools[0].ParametersSchema, "expression") {
+		t.Errorf("Tools = %+v, want calculator and date_math offered once each", tools)
+	}
+	results := openai.generateCalls[1].ToolResults
+	if len(results) != 1 || results[0].IsError || !strings.Contains(results[0].Output, `"result":"324.48"`) {
+		t.Errorf("ToolResults = %+v, want the calculator result", results)
+	}
+
+	result := svc.callServerTool(ctx, provider.ToolCall{ID: "call-2", Name: "calculator", Arguments: `{"expression":"1/0"}`})
+	if !result.IsError || !strings.HasPrefix(result.Output, "tool call failed: ") {
+		t.Errorf("got %+v, want an error result", result)
+	}
+	if isServerTool(cfg, "generate_uuid") {
+		t.Error("generate_uuid is a server tool, want only the tenant's local tools")
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/rag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mirrorRequested reports whether a provider upload should also be ingested
+// into the internal store with the same ID, as asked by the upload or the
+// tenant's mirror_internal setting. Only an explicit request fails without
+// RAG; the tenant setting is ignored then.
+func (s *FileService) mirrorRequested(ctx context.Context, metadata *pb.UploadFileMetadata) (bool, error) {
+	switch metadata.Provider {
+	case pb.Provider_PROVIDER_OPENAI, pb.Provider_PROVIDER_GEMINI:
+	default:
+		return false, nil // Internal uploads are already in the internal store
+	}
+	if metadata.MirrorInternal {
+		if err := s.ensureRAGEnabled(); err != nil {
+			return false, status.Error(codes.FailedPrecondition, "mirror_internal requires RAG to be enabled")
+		}
+		return true, nil
+	}
+	return s.ragService != nil && uploadLimits(ctx).MirrorInternal, nil
+}
+
+// mirrorToInternal ingests a file already uploaded to a provider store into
+// the internal store with the same ID, under the provider's file ID so
+// citations from either store name the same file. It returns the mirror's
+// status; a failed mirror does not fail the upload.
+func (s *FileService) mirrorToInternal(ctx context.Context, metadata *pb.UploadFileMetadata, file io.ReadSeeker, fileID string, progress *uploadProgress) string {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		slog.ErrorContext(ctx, "failed to rewind file for internal mirror", "store_id", metadata.StoreId, "error", err)
+		return UploadStageFailed
+	}
+
+	progress.report(UploadStageProcessing, "mirroring")
+	result, err := s.ragService.Ingest(ctx, rag.IngestParams{
+		StoreID:  metadata.StoreId,
+		TenantID: auth.TenantIDFromContext(ctx),
+		File:     file,
+		Filename: metadata.Filename,
+		MIMEType: metadata.MimeType,
+		FileID:   fileID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to mirror file to internal store",
+			"store_id", metadata.StoreId,
+			"filename", metadata.Filename,
+			"file_id", fileID,
+			"error", err,
+		)
+		accesslog.Annotate(ctx, "mirror_status", UploadStageFailed)
+		return UploadStageFailed
+	}
+
+	accesslog.Annotate(ctx, "mirror_status", UploadStageReady, "mirror_chunks", result.ChunkCount)
+	return UploadStageReady
+}
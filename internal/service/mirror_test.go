@@ -0,0 +1,73 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMirrorRequested(t *testing.T) {
+	withRAG := NewFileService(createRAGServiceWithMocks(nil, nil, nil), nil)
+	withoutRAG := NewFileService(nil, nil)
+	tenantDefault := ctxWithUploadLimits(tenant.UploadLimits{MirrorInternal: true})
+	noDefault := ctxWithUploadLimits(tenant.UploadLimits{})
+
+	tests := []struct {
+		name     string
+		svc      *FileService
+		metadata *pb.UploadFileMetadata
+		ctxOn    bool
+		want     bool
+		code     codes.Code
+	}{
+		{"off", withRAG, &pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_OPENAI}, false, false, codes.OK},
+		{"requested", withRAG, &pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_OPENAI, MirrorInternal: true}, false, true, codes.OK},
+		{"tenant default", withRAG, &pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_GEMINI}, true, true, codes.OK},
+		{"internal upload", withRAG, &pb.UploadFileMetadata{MirrorInternal: true}, true, false, codes.OK},
+		{"requested without RAG", withoutRAG, &pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_OPENAI, MirrorInternal: true}, false, false, codes.FailedPrecondition},
+		{"tenant default without RAG", withoutRAG, &pb.UploadFileMetadata{Provider: pb.Provider_PROVIDER_OPENAI}, true, false, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := noDefault
+			if tt.ctxOn {
+				ctx = tenantDefault
+			}
+			got, err := tt.svc.mirrorRequested(ctx, tt.metadata)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if got != tt.want {
+				t.Errorf("mirrorRequested = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMirrorToInternal(t *testing.T) {
+	store := testutil.NewMockStore()
+	svc := NewFileService(createRAGServiceWithMocks(store, nil, nil), nil)
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{})
+	metadata := &pb.UploadFileMetadata{StoreId: "vs_docs", Filename: "policy.txt", MimeType: "text/plain", Provider: pb.Provider_PROVIDER_OPENAI}
+
+	file := strings.NewReader("Refunds are issued within 14 days of the request.")
+	file.Seek(10, 0) // Left at the end by the provider upload
+	if got := svc.mirrorToInternal(ctx, metadata, file, "file-abc", nil); got != UploadStageReady {
+		t.Fatalf("expected ready mirror, got %q", got)
+	}
+	if len(store.UpsertCalls) != 1 {
+		t.Fatalf("expected one upsert, got %d", len(store.UpsertCalls))
+	}
+	call := store.UpsertCalls[0]
+	if call.Collection != "tenant1_vs_docs" {
+		t.Errorf("expected the store's internal collection, got %s", call.Collection)
+	}
+	if id := call.Points[0].Payload["file_id"]; id != "file-abc" {
+		t.Errorf("expected the provider's file ID, got %v", id)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+)
+
+// loadThreadPin returns the pin of the thread a request continues, keyed by
+// its request_id like persisted turns, or nil. Errors are logged and treated
+// as "no pin" so chat is never blocked on pins.
+func (s *ChatService) loadThreadPin(ctx context.Context, requestID, clientID string) *db.ThreadPin {
+	if s.dbClient == nil {
+		return nil
+	}
+	threadID, err := uuid.Parse(requestID)
+	if err != nil {
+		return nil
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	if !db.ValidTenantIDs[tenantID] {
+		return nil
+	}
+
+	repo, err := s.dbClient.TenantRepository(tenantID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get tenant repository for thread pin", "error", err, "tenant_id", tenantID)
+		return nil
+	}
+	pin, err := repo.GetThreadPin(ctx, threadID, conversationUserID(ctx, clientID))
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load thread pin, continuing without it", "error", err, "thread_id", threadID)
+		return nil
+	}
+	return pin
+}
+
+// followsPin reports whether a request's provider is taken from its
+// thread's pin: the request and its preset name no provider, and the
+// pinned provider is still enabled for the tenant.
+func followsPin(tenantCfg *tenant.TenantConfig, req *pb.GenerateReplyRequest, pin *db.ThreadPin) bool {
+	if pin == nil || req.PreferredProvider != pb.Provider_PROVIDER_UNSPECIFIED {
+		return false
+	}
+	if tenantCfg == nil {
+		return true
+	}
+	if preset, ok := tenantCfg.Preset(req.Preset); ok && preset.Provider != "" {
+		return false
+	}
+	_, ok := tenantCfg.GetProvider(pin.Provider)
+	return ok
+}
+
+// pinnedModel returns the model a request following its thread's pin is
+// sent to, or "" to use the provider's configured model: the pinned model,
+// unless the request names a model other than "auto", its preset names one
+// or the catalog no longer allows it.
+func (s *ChatService) pinnedModel(ctx context.Context, req *pb.GenerateReplyRequest, pin *db.ThreadPin) string {
+	if pin.Model == "" || (strings.TrimSpace(req.ModelOverride) != "" && !isAutoModel(req.ModelOverride)) || strings.TrimSpace(req.ProviderConfigs[pin.Provider].GetModel()) != "" {
+		return ""
+	}
+	if tenantCfg := auth.TenantFromContext(ctx); tenantCfg != nil {
+		if preset, ok := tenantCfg.Preset(req.Preset); ok && preset.Models[pin.Provider] != "" {
+			return ""
+		}
+	}
+	if err := s.checkModel(ctx, pin.Provider, pin.Model); err != nil {
+		return ""
+	}
+	return pin.Model
+}
+
+// pinThread stores pin as the pin of the thread the request continues,
+// after its turn has been persisted.
+func pinThread(ctx context.Context, repo *db.Repository, threadID uuid.UUID, pin *db.ThreadPin) {
+	if pin == nil {
+		return
+	}
+	if err := repo.PinThread(ctx, threadID, *pin); err != nil {
+		slog.ErrorContext(ctx, "failed to pin thread",
+			"error", err,
+			"thread_id", threadID,
+			"provider", pin.Provider,
+		)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+)
+
+func TestGenerateReply_ThreadPin(t *testing.T) {
+	client, err := db.NewClient(context.Background(), db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	repo, _ := client.TenantRepository("ai8")
+
+	openai, gemini := newMockProvider("openai"), newMockProvider("gemini")
+	svc := createChatServiceWithMocks(openai, gemini, newMockProvider("anthropic"), nil)
+	svc.dbClient = client
+	tenantCfg := createTestTenantConfig("openai", "gemini")
+	tenantCfg.TenantID = "ai8"
+	tenantCfg.Failover.Order = []string{"openai", "gemini"}
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+
+	threadID := uuid.New()
+	reply := func(preferred pb.Provider) {
+		t.Helper()
+		if _, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Hi", RequestId: threadID.String(), PreferredProvider: preferred}); err != nil {
+			t.Fatalf("GenerateReply failed: %v", err)
+		}
+	}
+	waitForPin := func(want db.ThreadPin) {
+		t.Helper()
+		waitFor(t, func() bool {
+			pin, err := repo.GetThreadPin(context.Background(), threadID, "test-client")
+			return err == nil && pin != nil && *pin == want
+		}, "thread pin "+want.Provider)
+	}
+
+	// The first turn pins the thread to its provider and model
+	reply(pb.Provider_PROVIDER_GEMINI)
+	waitForPin(db.ThreadPin{Provider: "gemini", Model: "test-model-gemini"})
+
+	// Later turns stay on them, even after the tenant changes its model
+	gcfg := tenantCfg.Providers["gemini"]
+	gcfg.Model = "test-model-gemini-2"
+	tenantCfg.Providers["gemini"] = gcfg
+	reply(pb.Provider_PROVIDER_UNSPECIFIED)
+	if len(gemini.generateCalls) != 2 || len(openai.generateCalls) != 0 {
+		t.Fatalf("calls = openai %d, gemini %d; want the pinned gemini", len(openai.generateCalls), len(gemini.generateCalls))
+	}
+	if got := gemini.generateCalls[1].OverrideModel; got != "test-model-gemini" {
+		t.Errorf("OverrideModel = %q, want the pinned model", got)
+	}
+
+	// An explicit provider re-pins the thread
+	reply(pb.Provider_PROVIDER_OPENAI)
+	waitForPin(db.ThreadPin{Provider: "openai", Model: "test-model-openai"})
+
+	resp, err := svc.SelectProvider(ctx, &pb.SelectProviderRequest{ThreadId: threadID.String(), ExistingProvider: "gemini"})
+	if err != nil {
+		t.Fatalf("SelectProvider failed: %v", err)
+	}
+	if resp.Provider != pb.Provider_PROVIDER_OPENAI || resp.ModelOverride != "test-model-openai" || resp.Reason != "continuity" {
+		t.Errorf("SelectProvider = %+v, want the pinned openai", resp)
+	}
+	resp, err = svc.SelectProvider(ctx, &pb.SelectProviderRequest{
+		Content:  "draw me a cat",
+		ThreadId: threadID.String(),
+		Triggers: []*pb.ProviderTrigger{{Phrase: "draw", Provider: pb.Provider_PROVIDER_GEMINI}},
+	})
+	if err != nil || resp.Provider != pb.Provider_PROVIDER_GEMINI || resp.Reason != "trigger" {
+		t.Errorf("SelectProvider = %+v, %v, want the trigger to win over the pin", resp, err)
+	}
+
+	// Failing over leaves the pinned model behind
+	openai.generateErr = errors.New("openai unavailable")
+	if _, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Hi", RequestId: threadID.String(), EnableFailover: true}); err != nil {
+		t.Fatalf("GenerateReply with failover failed: %v", err)
+	}
+	if got := openai.generateCalls[len(openai.generateCalls)-1].OverrideModel; got != "test-model-openai" {
+		t.Errorf("openai OverrideModel = %q, want the pinned model", got)
+	}
+	if got := gemini.generateCalls[len(gemini.generateCalls)-1].OverrideModel; got != "" {
+		t.Errorf("gemini OverrideModel = %q, want gemini's own model", got)
+	}
+
+	// Other users' threads are not followed
+	other := ctxWithChatPermissionAndTenant("other-client", tenantCfg)
+	if pin := svc.loadThreadPin(other, threadID.String(), ""); pin != nil {
+		t.Errorf("loadThreadPin = %+v, want none for another client", pin)
+	}
+}
+
+func TestFollowsPin(t *testing.T) {
+	cfg := createTestTenantConfig("openai", "gemini")
+	cfg.Presets = map[string]tenant.GenerationPreset{"fast": {Provider: "openai"}, "cold": {}}
+	pin := &db.ThreadPin{Provider: "gemini"}
+
+	tests := []struct {
+		name string
+		req  *pb.GenerateReplyRequest
+		pin  *db.ThreadPin
+		want bool
+	}{
+		{"pinned", &pb.GenerateReplyRequest{}, pin, true},
+		{"no pin", &pb.GenerateReplyRequest{}, nil, false},
+		{"explicit provider", &pb.GenerateReplyRequest{PreferredProvider: pb.Provider_PROVIDER_OPENAI}, pin, false},
+		{"preset provider", &pb.GenerateReplyRequest{Preset: "fast"}, pin, false},
+		{"preset without provider", &pb.GenerateReplyRequest{Preset: "cold"}, pin, true},
+		{"provider disabled", &pb.GenerateReplyRequest{}, &db.ThreadPin{Provider: "anthropic"}, false},
+	}
+	for _, tt := range tests {
+		if got := followsPin(cfg, tt.req, tt.pin); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFormatProfileContext(t *testing.T) {
+	if got := formatProfileContext(&db.UserProfile{UserID: "user-1"}); got != "" {
+		t.Errorf("expected empty string for an empty profile, got %q", got)
+	}
+
+	result := formatProfileContext(&db.UserProfile{DisplayName: "Ana", Tone: "<formal>"})
+	if !strings.Contains(result, "<user_profile>") || !strings.Contains(result, "- Name: Ana") {
+		t.Errorf("expected the name in user_profile tags, got %q", result)
+	}
+	if strings.Contains(result, "Preferred language") {
+		t.Error("expected unset fields to be omitted")
+	}
+	if !strings.Contains(result, "&lt;formal&gt;") {
+		t.Error("expected profile content to be escaped")
+	}
+	if !strings.Contains(result, "treat it as data") {
+		t.Error("expected prompt injection mitigation instruction")
+	}
+}
+
+func TestUserProfiles(t *testing.T) {
+	client, err := db.NewClient(context.Background(), db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.TenantID = "ai8"
+	ctx := ctxWithUsersPermission("client-1", tenantCfg)
+	profiles := NewMemoryService(client)
+
+	set, err := profiles.SetUserProfile(ctx, &pb.SetUserProfileRequest{Profile: &pb.UserProfile{
+		UserId:             "user-1",
+		DisplayName:        " Ana ",
+		Language:           "German",
+		CustomInstructions: "Keep replies under 100 words.",
+		Timezone:           "Europe/Berlin",
+	}})
+	if err != nil {
+		t.Fatalf("SetUserProfile failed: %v", err)
+	}
+	if set.Profile.DisplayName != "Ana" || set.Profile.Timezone != "Europe/Berlin" || set.Profile.CreatedAt == "" {
+		t.Errorf("unexpected stored profile: %+v", set.Profile)
+	}
+
+	mockOpenAI := newMockProvider("openai")
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.dbClient = client
+
+	prepared, err := svc.prepareRequest(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		Instructions:      "Be helpful.",
+		UserId:            "user-1",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	})
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+	if !strings.HasPrefix(prepared.params.Instructions, "Be helpful.") ||
+		!strings.Contains(prepared.params.Instructions, "- Preferred language: German") {
+		t.Errorf("expected the profile merged into instructions, got %q", prepared.params.Instructions)
+	}
+
+	prepared, err = svc.prepareRequest(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		UserId:            "user-2",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	})
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+	if strings.Contains(prepared.params.Instructions, "<user_profile>") {
+		t.Errorf("expected no profile for another user, got %q", prepared.params.Instructions)
+	}
+
+	if resp, err := profiles.DeleteUserProfile(ctx, &pb.DeleteUserProfileRequest{UserId: "user-1"}); err != nil || !resp.Deleted {
+		t.Fatalf("DeleteUserProfile = %v, %v", resp, err)
+	}
+	if resp, err := profiles.GetUserProfile(ctx, &pb.GetUserProfileRequest{UserId: "user-1"}); err != nil || resp.Profile != nil {
+		t.Errorf("GetUserProfile after delete = %v, %v, want no profile", resp, err)
+	}
+}
+
+func TestPrepareRequest_ProfileScopedToClient(t *testing.T) {
+	client, err := db.NewClient(context.Background(), db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.TenantID = "ai8"
+	profile := &pb.UserProfile{UserId: "user-9", Language: "German"}
+	if _, err := NewMemoryService(client).SetUserProfile(ctxWithUsersPermission("backend", tenantCfg), &pb.SetUserProfileRequest{Profile: profile}); err != nil {
+		t.Fatalf("SetUserProfile failed: %v", err)
+	}
+
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.dbClient = client
+	_, err = svc.prepareRequest(ctxWithChatPermissionAndTenant("client-1", tenantCfg), &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		UserId:            "user-9",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for another user's profile, got %v", err)
+	}
+}
+
+func TestSetUserProfile_Validation(t *testing.T) {
+	svc := NewMemoryService(nil)
+	ctx := ctxWithUsersPermission("client-1", nil)
+
+	tests := []struct {
+		name    string
+		profile *pb.UserProfile
+		code    codes.Code
+	}{
+		{"missing profile", nil, codes.InvalidArgument},
+		{"missing user", &pb.UserProfile{DisplayName: "Ana"}, codes.InvalidArgument},
+		{"long tone", &pb.UserProfile{UserId: "user-1", Tone: strings.Repeat("a", profileShortFieldMaxLen+1)}, codes.InvalidArgument},
+		{"long instructions", &pb.UserProfile{UserId: "user-1", CustomInstructions: strings.Repeat("a", profileInstructionsMaxLen+1)}, codes.InvalidArgument},
+		{"unknown timezone", &pb.UserProfile{UserId: "user-1", Timezone: "Berlin"}, codes.InvalidArgument},
+		{"no database", &pb.UserProfile{UserId: "user-1", Tone: "formal"}, codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SetUserProfile(ctx, &pb.SetUserProfileRequest{Profile: tt.profile})
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+)
+
+// Upload processing stages reported in UploadProgress.
+const (
+	UploadStageReceived   = "received"   // All data is on the server
+	UploadStageUploading  = "uploading"  // Sending the file to the provider
+	UploadStageProcessing = "processing" // The provider or RAG pipeline is indexing the file
+	UploadStageReady      = "ready"
+	UploadStageFailed     = "failed"
+)
+
+// uploadProgress passes an upload's processing progress to notify. Repeats
+// of the last report are dropped, so providers can report after every poll.
+// A nil *uploadProgress reports nothing.
+type uploadProgress struct {
+	notify func(*pb.UploadProgress)
+	bytes  int64
+	last   *pb.UploadProgress
+}
+
+// received reports that all size bytes of the file are on the server.
+func (p *uploadProgress) received(size int64) {
+	if p == nil {
+		return
+	}
+	p.bytes = size
+	p.report(UploadStageReceived, "")
+}
+
+// report sends stage and the provider's status unless unchanged.
+func (p *uploadProgress) report(stage, providerStatus string) {
+	if p == nil {
+		return
+	}
+	if p.last != nil && p.last.Stage == stage && p.last.ProviderStatus == providerStatus {
+		return
+	}
+	p.send(&pb.UploadProgress{Stage: stage, ProviderStatus: providerStatus})
+}
+
+// providerStatus returns a callback reporting provider statuses as
+// processing, or nil for a nil *uploadProgress.
+func (p *uploadProgress) providerStatus() func(string) {
+	if p == nil {
+		return nil
+	}
+	return func(status string) {
+		p.report(UploadStageProcessing, status)
+	}
+}
+
+// finish sends the upload's result.
+func (p *uploadProgress) finish(resp *pb.UploadFileResponse) {
+	if p == nil {
+		return
+	}
+	stage := UploadStageReady
+	if resp.Status == "failed" {
+		stage = UploadStageFailed
+	}
+	p.send(&pb.UploadProgress{Stage: stage, ProviderStatus: resp.Status, Result: resp})
+}
+
+// fail reports that the upload was rejected or errored. The error itself is
+// returned to the caller.
+func (p *uploadProgress) fail() {
+	if p == nil {
+		return
+	}
+	p.report(UploadStageFailed, "")
+}
+
+func (p *uploadProgress) send(msg *pb.UploadProgress) {
+	msg.BytesReceived = p.bytes
+	msg.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	p.last = msg
+	p.notify(msg)
+}
+
+// UploadFileWithProgress uploads a file like UploadFile, streaming an
+// UploadProgress for each processing stage. Errors end the stream with a
+// status, as for UploadFile.
+func (s *FileService) UploadFileWithProgress(stream pb.FileService_UploadFileWithProgressServer) error {
+	var sendErr error
+	progress := &uploadProgress{notify: func(msg *pb.UploadProgress) {
+		// Keep processing if the client stops listening; the upload
+		// still completes
+		if sendErr == nil {
+			sendErr = stream.Send(msg)
+		}
+	}}
+	if _, err := s.receiveUpload(stream.Context(), stream.Recv, progress); err != nil {
+		return err
+	}
+	return sendErr
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockUploadProgressServer struct {
+	pb.FileService_UploadFileWithProgressServer
+	ctx      context.Context
+	messages []*pb.UploadFileRequest
+	index    int
+	sent     []*pb.UploadProgress
+}
+
+func (m *mockUploadProgressServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockUploadProgressServer) Recv() (*pb.UploadFileRequest, error) {
+	if m.index >= len(m.messages) {
+		return nil, io.EOF
+	}
+	msg := m.messages[m.index]
+	m.index++
+	return msg, nil
+}
+
+func (m *mockUploadProgressServer) Send(msg *pb.UploadProgress) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func stages(msgs []*pb.UploadProgress) []string {
+	var out []string
+	for _, msg := range msgs {
+		out = append(out, msg.Stage)
+	}
+	return out
+}
+
+func TestUploadFileWithProgress(t *testing.T) {
+	svc := NewFileService(createRAGServiceWithMocks(testutil.NewMockStore(), nil, nil), nil)
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{})
+	stream := &mockUploadProgressServer{ctx: ctx, messages: uploadStream(ctx, "docs", 0, "hello").messages}
+
+	if err := svc.UploadFileWithProgress(stream); err != nil {
+		t.Fatalf("UploadFileWithProgress failed: %v", err)
+	}
+	got := stages(stream.sent)
+	want := []string{UploadStageReceived, UploadStageProcessing, UploadStageReady}
+	if len(got) != len(want) {
+		t.Fatalf("stages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("stages = %v, want %v", got, want)
+		}
+	}
+	last := stream.sent[len(stream.sent)-1]
+	if last.Result == nil || last.Result.FileId == "" || last.BytesReceived != 5 || last.UpdatedAt == "" {
+		t.Errorf("unexpected final progress: %+v", last)
+	}
+}
+
+func TestUploadFileWithProgress_Rejected(t *testing.T) {
+	svc := NewFileService(createRAGServiceWithMocks(testutil.NewMockStore(), nil, nil), nil)
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{AllowedTypes: []string{"application/pdf"}})
+	stream := &mockUploadProgressServer{ctx: ctx, messages: uploadStream(ctx, "docs", 0, "hello").messages}
+
+	err := svc.UploadFileWithProgress(stream)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	if got := stages(stream.sent); len(got) != 2 || got[1] != UploadStageFailed {
+		t.Errorf("stages = %v, want received then failed", got)
+	}
+}
+
+func TestUploadProgress_DropsRepeats(t *testing.T) {
+	var sent []*pb.UploadProgress
+	p := &uploadProgress{notify: func(msg *pb.UploadProgress) { sent = append(sent, msg) }}
+
+	p.received(10)
+	onStatus := p.providerStatus()
+	onStatus("in_progress")
+	onStatus("in_progress")
+	onStatus("completed")
+	p.finish(&pb.UploadFileResponse{FileId: "f1", Status: "completed"})
+
+	if len(sent) != 4 {
+		t.Fatalf("expected 4 reports, got %d: %v", len(sent), sent)
+	}
+	if sent[1].Stage != UploadStageProcessing || sent[1].ProviderStatus != "in_progress" || sent[2].ProviderStatus != "completed" {
+		t.Errorf("unexpected provider reports: %v", sent[1:3])
+	}
+	if sent[3].Stage != UploadStageReady || sent[3].Result.GetFileId() != "f1" {
+		t.Errorf("unexpected final report: %v", sent[3])
+	}
+
+	// A nil progress reports nothing
+	var none *uploadProgress
+	none.received(1)
+	none.finish(&pb.UploadFileResponse{})
+	if none.providerStatus() != nil {
+		t.Error("expected no status callback")
+	}
+}
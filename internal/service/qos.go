@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/qos"
+	"github.com/ai8future/airborne/internal/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithQoS schedules provider calls through a priority-aware concurrency limiter.
+func WithQoS(limiter *qos.Limiter) ChatServiceOption {
+	return func(s *ChatService) {
+		s.limiter = limiter
+	}
+}
+
+// priorityClass maps a request priority to its QoS class. Unspecified
+// requests are interactive so existing clients keep their latency.
+func priorityClass(p pb.Priority) qos.Class {
+	switch p {
+	case pb.Priority_PRIORITY_BATCH:
+		return qos.Batch
+	case pb.Priority_PRIORITY_BACKGROUND:
+		return qos.Background
+	default:
+		return qos.Interactive
+	}
+}
+
+// acquireSlot waits for a slot to call providerName. The returned release
+// func is non-nil whenever err is nil.
+func (s *ChatService) acquireSlot(ctx context.Context, req *pb.GenerateReplyRequest, providerName string) (func(), error) {
+	class := priorityClass(req.Priority)
+	accesslog.Annotate(ctx, "priority", class.String())
+	if s.limiter == nil {
+		return func() {}, nil
+	}
+
+	release, err := s.limiter.Acquire(ctx, providerName, class)
+	switch {
+	case err == nil:
+		return release, nil
+	case errors.Is(err, qos.ErrShed):
+		slog.WarnContext(ctx, "request shed by qos", "provider", providerName, "priority", class.String())
+		return nil, status.Errorf(codes.ResourceExhausted, "%s request shed to protect interactive traffic on %s; retry later", class, providerName)
+	case errors.Is(err, qos.ErrQueueFull):
+		slog.WarnContext(ctx, "qos queue full, rejecting request", "provider", providerName, "priority", class.String())
+		return nil, status.Error(codes.ResourceExhausted, "server is at capacity; retry later")
+	default:
+		return nil, status.FromContextError(err).Err()
+	}
+}
+
+// tryAcquireSlot takes a provider slot for an extra call, such as a hedge,
+// only if one is free now: waiting for one would defeat its purpose.
+func (s *ChatService) tryAcquireSlot(req *pb.GenerateReplyRequest, providerName string) (func(), bool) {
+	if s.limiter == nil {
+		return func() {}, true
+	}
+	return s.limiter.TryAcquire(providerName, priorityClass(req.Priority))
+}
+
+// reportProviderError puts a provider under rate-limit pressure when it
+// rejects a call for rate limits, so queued batch work is shed first.
+func (s *ChatService) reportProviderError(providerName string, err error) {
+	if s.limiter != nil && retry.IsRateLimited(err) {
+		slog.Warn("provider rate limited, shedding batch traffic", "provider", providerName)
+		s.limiter.ReportRateLimited(providerName)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/qos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPriorityClass(t *testing.T) {
+	tests := map[pb.Priority]qos.Class{
+		pb.Priority_PRIORITY_UNSPECIFIED: qos.Interactive,
+		pb.Priority_PRIORITY_INTERACTIVE: qos.Interactive,
+		pb.Priority_PRIORITY_BATCH:       qos.Batch,
+		pb.Priority_PRIORITY_BACKGROUND:  qos.Background,
+	}
+	for p, want := range tests {
+		if got := priorityClass(p); got != want {
+			t.Errorf("priorityClass(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestGenerateReply_RateLimitShedsBatch(t *testing.T) {
+	mockOpenAI := newMockProvider("openai")
+	mockOpenAI.generateErr = errors.New("POST /v1/responses: 429 Too Many Requests")
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	WithQoS(qos.NewLimiter(qos.Config{MaxConcurrent: 4}))(svc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	req := &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+	}
+	if _, err := svc.GenerateReply(ctx, req); status.Code(err) != codes.Internal {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+
+	// Batch traffic is now shed without reaching the provider
+	req.Priority = pb.Priority_PRIORITY_BATCH
+	if _, err := svc.GenerateReply(ctx, req); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted for batch request, got %v", err)
+	}
+	if len(mockOpenAI.generateCalls) != 1 {
+		t.Errorf("provider calls = %d, want 1", len(mockOpenAI.generateCalls))
+	}
+
+	// Interactive traffic still reaches the provider
+	mockOpenAI.generateErr = nil
+	req.Priority = pb.Priority_PRIORITY_INTERACTIVE
+	if _, err := svc.GenerateReply(ctx, req); err != nil {
+		t.Fatalf("interactive request failed: %v", err)
+	}
+	if active, _ := svc.limiter.Stats(); active != 0 {
+		t.Errorf("active slots = %d after requests completed, want 0", active)
+	}
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Connection limits of the pool kept for each query_database database.
+const (
+	databasePoolMaxConns    = 4
+	databasePoolMaxIdleTime = 5 * time.Minute
+)
+
+// qualifiedCall matches the schema of a function, operator or type an
+// EXPLAIN VERBOSE expression qualifies, as in `billing.total(id)`,
+// `OPERATOR(billing.=)` or `'x'::billing.money`. Postgres only qualifies
+// those its search_path does not find.
+var qualifiedCall = regexp.MustCompile(`(` + sqlIdent + `)\.` + sqlIdent + `\(|(?:OPERATOR\(|::)(` + sqlIdent + `)\.`)
+
+// sqlIdent matches an identifier as Postgres prints it, quoted if needed.
+const sqlIdent = `(?:"(?:[^"]|"")+"|[A-Za-z_][A-Za-z0-9_$]*)`
+
+// errDatabaseUnavailable is returned to the model when a database cannot be
+// reached, keeping connection details out of its tool result.
+var errDatabaseUnavailable = errors.New("database unavailable")
+
+// queryDatabaseArgs are the arguments of a query_database call.
+type queryDatabaseArgs struct {
+	Database string `json:"database"`
+	SQL      string `json:"sql"`
+}
+
+// queryResult is a query's result, returned to the model as JSON.
+type queryResult struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Truncated bool     `json:"truncated,omitempty"` // More rows matched than were returned
+}
+
+// databaseQuerier runs sql against db, returning at most maxRows rows.
+type databaseQuerier func(ctx context.Context, db tenant.QueryDatabase, sql string, maxRows int) (queryResult, error)
+
+// queryDatabaseTool returns the query_database tool as offered to the model,
+// listing the tenant's databases.
+func queryDatabaseTool(cfg tenant.QueryDatabaseConfig) provider.Tool {
+	names := make([]string, 0, len(cfg.Databases))
+	for name := range cfg.Databases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var desc strings.Builder
+	fmt.Fprintf(&desc, "Runs one read-only PostgreSQL SELECT statement and returns up to %d rows as JSON. "+
+		"Tables must be in the database's schemas. Databases:", cfg.Rows())
+	for _, name := range names {
+		db := cfg.Databases[name]
+		fmt.Fprintf(&desc, "\n- %s (schemas: %s)", name, strings.Join(db.Schemas, ", "))
+		if db.Description != "" {
+			desc.WriteString(": " + db.Description)
+		}
+	}
+
+	schema, _ := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"database": map[string]any{"type": "string", "enum": names},
+			"sql":      map[string]any{"type": "string", "description": "A single SELECT statement"},
+		},
+		"required": []string{"database", "sql"},
+	})
+	return provider.Tool{Name: tenant.QueryDatabaseToolName, Description: desc.String(), ParametersSchema: string(schema)}
+}
+
+// callQueryDatabase runs the query of a query_database call within the
+// tenant's limits. Failures, including SQL errors, are returned to the model
+// as error results so it can correct the query or answer without it.
+func (s *ChatService) callQueryDatabase(ctx context.Context, cfg tenant.QueryDatabaseConfig, call provider.ToolCall) provider.ToolResult {
+	out, err := s.queryDatabase(ctx, cfg, call)
+	if err != nil {
+		slog.WarnContext(ctx, "database tool call failed",
+			"call_id", call.ID,
+			"error", err,
+		)
+		return provider.ToolResult{ToolCallID: call.ID, Output: "query failed: " + err.Error(), IsError: true}
+	}
+	return provider.ToolResult{ToolCallID: call.ID, Output: out}
+}
+
+// queryDatabase returns the JSON result of a query_database call, dropping
+// rows past the remote tool response limit.
+func (s *ChatService) queryDatabase(ctx context.Context, cfg tenant.QueryDatabaseConfig, call provider.ToolCall) (string, error) {
+	var args queryDatabaseArgs
+	if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil || strings.TrimSpace(args.SQL) == "" {
+		return "", errors.New(`arguments must be {"database": ..., "sql": ...}`)
+	}
+	db, ok := cfg.Databases[args.Database]
+	if !ok {
+		return "", fmt.Errorf("unknown database %q", args.Database)
+	}
+
+	query := s.databaseQuery
+	if query == nil {
+		query = s.databasePools.query
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout())
+	defer cancel()
+	result, err := query(ctx, db, args.SQL, cfg.Rows())
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		out, err := json.Marshal(result)
+		if err != nil {
+			return "", err
+		}
+		if len(out) <= tenant.DefaultRemoteToolResponseBytes || len(result.Rows) == 0 {
+			return string(out), nil
+		}
+		result.Rows = result.Rows[:len(result.Rows)/2]
+		result.Truncated = true
+	}
+}
+
+// databasePools holds a connection pool for each database DSN the
+// query_database tool has queried. Idle connections are closed after
+// databasePoolMaxIdleTime, so pools of databases dropped from tenant configs
+// hold none.
+type databasePools struct {
+	mu    sync.Mutex
+	pools map[string]*pgxpool.Pool
+}
+
+// pool returns the connection pool of dsn, creating it on first use.
+// Connections are established when acquired.
+func (p *databasePools) pool(dsn string) (*pgxpool.Pool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pool, ok := p.pools[dsn]; ok {
+		return pool, nil
+	}
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.ConnConfig.RuntimeParams["application_name"] = "airborne"
+	poolCfg.MaxConns = databasePoolMaxConns
+	poolCfg.MaxConnIdleTime = databasePoolMaxIdleTime
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
+	if err != nil {
+		return nil, err
+	}
+	if p.pools == nil {
+		p.pools = make(map[string]*pgxpool.Pool)
+	}
+	p.pools[dsn] = pool
+	return pool, nil
+}
+
+// query runs sql in a read-only transaction on db, within ctx's deadline,
+// after checking its plan only reads tables and calls functions in db's
+// schemas. Functions may also be built-ins from pg_catalog.
+func (p *databasePools) query(ctx context.Context, db tenant.QueryDatabase, sql string, maxRows int) (queryResult, error) {
+	pool, err := p.pool(db.DSN)
+	if err != nil {
+		return queryResult{}, errDatabaseUnavailable
+	}
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "database tool connection failed", "error", err)
+		return queryResult{}, errDatabaseUnavailable
+	}
+	defer conn.Release()
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return queryResult{}, queryError(err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	// The server stops the statement too, should the client's deadline fail
+	timeout := "0"
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10)
+	}
+	schemas := make([]string, len(db.Schemas))
+	for i, schema := range db.Schemas {
+		schemas[i] = pgx.Identifier{schema}.Sanitize()
+	}
+	// Both settings are local to the transaction, leaving the pooled connection as it was
+	if _, err := tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true), set_config('search_path', $2, true)",
+		timeout, strings.Join(schemas, ", ")); err != nil {
+		return queryResult{}, queryError(err)
+	}
+
+	// Statements that cannot be explained, and multiple statements, fail here
+	var plan string
+	if err := tx.QueryRow(ctx, "EXPLAIN (VERBOSE, FORMAT JSON) "+sql).Scan(&plan); err != nil {
+		return queryResult{}, queryError(err)
+	}
+	read, err := planSchemas([]byte(plan))
+	if err != nil {
+		return queryResult{}, err
+	}
+	for _, schema := range read {
+		if !slices.Contains(db.Schemas, schema) {
+			return queryResult{}, fmt.Errorf("schema %q is not allowed; queries may read %s", schema, strings.Join(db.Schemas, ", "))
+		}
+	}
+
+	rows, err := tx.Query(ctx, sql)
+	if err != nil {
+		return queryResult{}, queryError(err)
+	}
+	defer rows.Close()
+	result := queryResult{Rows: [][]any{}}
+	for _, f := range rows.FieldDescriptions() {
+		result.Columns = append(result.Columns, f.Name)
+	}
+	for rows.Next() {
+		if len(result.Rows) == maxRows {
+			result.Truncated = true
+			break
+		}
+		values, err := rows.Values()
+		if err != nil {
+			return queryResult{}, queryError(err)
+		}
+		for i, v := range values {
+			values[i] = jsonValue(v)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return queryResult{}, queryError(err)
+	}
+	return result, nil
+}
+
+// planSchemas returns the schemas of the tables an EXPLAIN (VERBOSE, FORMAT
+// JSON) plan reads and of the functions and operators it calls, including in
+// subqueries and CTEs. Built-in functions from pg_catalog are left out. The
+// plan must be explained with search_path set to the allowed schemas, so
+// that expressions qualify every function outside them.
+func planSchemas(plan []byte) ([]string, error) {
+	var root any
+	if err := json.Unmarshal(plan, &root); err != nil {
+		return nil, fmt.Errorf("reading query plan: %w", err)
+	}
+	var schemas []string
+	add := func(schema string) {
+		if !slices.Contains(schemas, schema) {
+			schemas = append(schemas, schema)
+		}
+	}
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		case map[string]any:
+			schema, _ := v["Schema"].(string)
+			if _, ok := v["Relation Name"]; ok && schema != "" {
+				add(schema)
+			}
+			if _, ok := v["Function Name"]; ok && schema != "" && schema != "pg_catalog" {
+				add(schema)
+			}
+			for _, e := range v {
+				walk(e)
+			}
+		case string:
+			// Expressions: outputs, filters, conditions, function calls and casts
+			for _, m := range qualifiedCall.FindAllStringSubmatch(v, -1) {
+				schema := cmp.Or(m[1], m[2])
+				if unquoted, ok := strings.CutPrefix(schema, `"`); ok {
+					schema = strings.ReplaceAll(strings.TrimSuffix(unquoted, `"`), `""`, `"`)
+				}
+				if schema != "pg_catalog" {
+					add(schema)
+				}
+			}
+		}
+	}
+	walk(root)
+	slices.Sort(schemas)
+	return schemas, nil
+}
+
+// queryError returns the message of a Postgres error, which helps the model
+// correct its query, in place of the driver's wrapping.
+func queryError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return errors.New(pgErr.Message)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.New("query timed out")
+	}
+	return err
+}
+
+// jsonValue converts column values JSON would encode unreadably: UUIDs and
+// text held as bytes.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case [16]byte:
+		return uuid.UUID(v).String()
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+	}
+	return v
+}
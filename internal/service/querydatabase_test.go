@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// queryDatabaseService returns a service whose openai provider returns
+// results and a tenant with an analytics database answered by query.
+func queryDatabaseService(query databaseQuerier, results ...provider.GenerateResult) (*ChatService, *resultsProvider, context.Context) {
+	openai := &resultsProvider{mockProvider: newMockProvider("openai"), results: results}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic"), databaseQuery: query}
+	cfg := createTestTenantConfig("openai")
+	cfg.QueryDatabase = tenant.QueryDatabaseConfig{
+		Databases: map[string]tenant.QueryDatabase{"analytics": {DSN: "postgres://reader@db.example.com/analytics", Schemas: []string{"reporting"}, Description: "Daily sales"}},
+		MaxRows:   2,
+	}
+	return svc, openai, ctxWithChatPermissionAndTenant("test-client", cfg)
+}
+
+func TestGenerateReply_QueryDatabase(t *testing.T) {
+	var gotSQL string
+	var gotRows int
+	query := func(ctx context.Context, db tenant.QueryDatabase, sql string, maxRows int) (queryResult, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("query has no deadline")
+		}
+		gotSQL, gotRows = sql, maxRows
+		return queryResult{Columns: []string{"day", "total"}, Rows: [][]any{{"2026-10-16", 120}}}, nil
+	}
+	final := provider.GenerateResult{Text: "Sales were 120.", ResponseID: "resp-final", Model: "test-model-openai"}
+	svc, openai, ctx := queryDatabaseService(query, toolCallResult("query_database", `{"database":"analytics","sql":"SELECT day, total FROM sales"}`), final)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Sales yesterday?", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Text != "Sales were 120." {
+		t.Errorf("Text = %q, want the final reply", resp.Text)
+	}
+	if gotSQL != "SELECT day, total FROM sales" || gotRows != 2 {
+		t.Errorf("query(%q, %d), want the call's SQL and the tenant's row limit", gotSQL, gotRows)
+	}
+
+	tools := openai.generateCalls[0].Tools
+	if len(tools) != 1 || tools[0].Name != "query_database" || !strings.Contains(tools[0].Description, "analytics (schemas: reporting): Daily sales") {
+		t.Errorf("Tools = %+v, want query_database offered", tools)
+	}
+	results := openai.generateCalls[1].ToolResults
+	if len(results) != 1 || results[0].IsError || results[0].Output != `{"columns":["day","total"],"rows":[["2026-10-16",120]]}` {
+		t.Errorf("ToolResults = %+v, want the query result", results)
+	}
+}
+
+func TestQueryDatabase_Errors(t *testing.T) {
+	query := func(ctx context.Context, db tenant.QueryDatabase, sql string, maxRows int) (queryResult, error) {
+		return queryResult{}, errors.New(`relation "sales" does not exist`)
+	}
+	svc, _, ctx := queryDatabaseService(query)
+
+	for args, want := range map[string]string{
+		`{"database":"billing","sql":"SELECT 1"}`:              `query failed: unknown database "billing"`,
+		`{"database":"analytics"}`:                             `query failed: arguments must be {"database": ..., "sql": ...}`,
+		`{"database":"analytics","sql":"SELECT * FROM sales"}`: `query failed: relation "sales" does not exist`,
+	} {
+		result := svc.callServerTool(ctx, provider.ToolCall{ID: "call-1", Name: "query_database", Arguments: args})
+		if !result.IsError || result.Output != want {
+			t.Errorf("%s: got %+v, want error %q", args, result, want)
+		}
+	}
+}
+
+func TestQueryDatabase_ResponseLimit(t *testing.T) {
+	row := []any{strings.Repeat("x", 1000)}
+	query := func(ctx context.Context, db tenant.QueryDatabase, sql string, maxRows int) (queryResult, error) {
+		return queryResult{Columns: []string{"note"}, Rows: slices.Repeat([][]any{row}, 200)}, nil
+	}
+	svc, _, ctx := queryDatabaseService(query)
+
+	result := svc.callServerTool(ctx, provider.ToolCall{ID: "call-1", Name: "query_database", Arguments: `{"database":"analytics","sql":"SELECT note FROM notes"}`})
+	var got queryResult
+	if err := json.Unmarshal([]byte(result.Output), &got); err != nil {
+		t.Fatalf("output is not a query result: %v", err)
+	}
+	if len(result.Output) > tenant.DefaultRemoteToolResponseBytes || !got.Truncated || len(got.Rows) == 0 {
+		t.Errorf("got %d bytes, %d rows, truncated %v, want rows dropped to fit", len(result.Output), len(got.Rows), got.Truncated)
+	}
+}
+
+func TestGenerateReply_QueryDatabaseNameCollision(t *testing.T) {
+	svc, _, ctx := queryDatabaseService(nil, provider.GenerateResult{Text: "hi"})
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		Tools:             []*pb.Tool{{Name: "query_database", Description: "Mine"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestPlanSchemas(t *testing.T) {
+	plan := `[{"Plan": {"Node Type": "Hash Join", "Output": ["s.day", "upper(s.region)"], "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "sales", "Schema": "reporting", "Alias": "s"},
+		{"Node Type": "Hash", "Plans": [
+			{"Node Type": "Index Scan", "Relation Name": "invoices", "Schema": "billing"},
+			{"Node Type": "Function Scan", "Function Name": "generate_series", "Schema": "pg_catalog"}
+		]},
+		{"Node Type": "Seq Scan", "Relation Name": "regions", "Schema": "reporting", "Filter": "(pg_catalog.lower(r.name) = 'north'::text)"}
+	]}}]`
+	schemas, err := planSchemas([]byte(plan))
+	if err != nil {
+		t.Fatalf("planSchemas failed: %v", err)
+	}
+	if !slices.Equal(schemas, []string{"billing", "reporting"}) {
+		t.Errorf("schemas = %v, want the schemas of the scanned tables", schemas)
+	}
+	if _, err := planSchemas([]byte("not json")); err == nil {
+		t.Error("expected an error for an unreadable plan")
+	}
+}
+
+func TestPlanSchemas_Functions(t *testing.T) {
+	tests := []struct {
+		name string
+		node string
+		want []string
+	}{
+		{"built-in function scan", `{"Node Type": "Function Scan", "Function Name": "generate_series", "Schema": "pg_catalog"}`, nil},
+		{"function scan", `{"Node Type": "Function Scan", "Function Name": "leak", "Schema": "admin"}`, []string{"admin"}},
+		{"multiple function scan", `{"Node Type": "Function Scan", "Function Call": "generate_series(1, 3), admin.leak()"}`, []string{"admin"}},
+		{"function in output", `{"Node Type": "Result", "Output": ["admin.leak(s.id)"]}`, []string{"admin"}},
+		{"quoted schema", `{"Node Type": "Result", "Filter": "(\"Admin Tools\".leak() > 0)"}`, []string{"Admin Tools"}},
+		{"operator", `{"Node Type": "Result", "Filter": "(s.id OPERATOR(admin.===) 1)"}`, []string{"admin"}},
+		{"cast", `{"Node Type": "Result", "Output": ["'x'::admin.secret"]}`, []string{"admin"}},
+		{"column reference", `{"Node Type": "Result", "Output": ["s.total", "(s.total)::numeric(10,2)"]}`, nil},
+	}
+	for _, tt := range tests {
+		schemas, err := planSchemas([]byte(`[{"Plan": ` + tt.node + `}]`))
+		if err != nil {
+			t.Fatalf("%s: planSchemas failed: %v", tt.name, err)
+		}
+		if !slices.Equal(schemas, tt.want) {
+			t.Errorf("%s: schemas = %v, want %v", tt.name, schemas, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StoreRegistry records the files and bytes uploaded to each file store,
+// for enforcing tenant upload limits. *db.Repository implements it.
+type StoreRegistry interface {
+	GetStoreUsage(ctx context.Context, tenantID, storeID string) (db.StoreUsage, error)
+	GetTenantStorageBytes(ctx context.Context, tenantID string) (int64, error)
+	RecordStoreUpload(ctx context.Context, tenantID, storeID, provider string, bytes int64) error
+	DeleteStoreUsage(ctx context.Context, tenantID, storeID string) error
+}
+
+// FileServiceOption configures optional FileService features.
+type FileServiceOption func(*FileService)
+
+// WithStoreRegistry tracks store usage in registry and enforces the
+// per-store file count and total storage limits in each tenant's config.
+// Without a registry only the per-file size limit is enforced.
+func WithStoreRegistry(registry StoreRegistry) FileServiceOption {
+	return func(s *FileService) {
+		s.stores = registry
+	}
+}
+
+// Upload limits named in QuotaError.
+const (
+	QuotaFileBytes     = "max_file_bytes"
+	QuotaFilesPerStore = "max_files_per_store"
+	QuotaStorageBytes  = "max_storage_bytes"
+)
+
+// QuotaError is returned when an upload would exceed one of the tenant's
+// upload limits.
+type QuotaError struct {
+	Limit     string // QuotaFileBytes, QuotaFilesPerStore or QuotaStorageBytes
+	Max       int64
+	Used      int64 // Usage before this upload; 0 for QuotaFileBytes
+	Requested int64 // Bytes, or 1 file for QuotaFilesPerStore
+}
+
+func (e *QuotaError) Error() string {
+	switch e.Limit {
+	case QuotaFileBytes:
+		return fmt.Sprintf("file size %d exceeds maximum allowed size %d bytes", e.Requested, e.Max)
+	case QuotaFilesPerStore:
+		return fmt.Sprintf("store already has %d of %d allowed files", e.Used, e.Max)
+	default:
+		return fmt.Sprintf("upload of %d bytes exceeds storage limit: %d of %d bytes used", e.Requested, e.Used, e.Max)
+	}
+}
+
+// GRPCStatus converts the error to a ResourceExhausted status carrying an
+// ErrorInfo detail, so clients can tell which limit was hit.
+func (e *QuotaError) GRPCStatus() *status.Status {
+	st := status.New(codes.ResourceExhausted, e.Error())
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "UPLOAD_QUOTA_EXCEEDED",
+		Domain: "airborne",
+		Metadata: map[string]string{
+			"limit":     e.Limit,
+			"max":       strconv.FormatInt(e.Max, 10),
+			"used":      strconv.FormatInt(e.Used, 10),
+			"requested": strconv.FormatInt(e.Requested, 10),
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// uploadLimits returns the caller's tenant upload limits.
+func uploadLimits(ctx context.Context) tenant.UploadLimits {
+	if cfg := auth.TenantFromContext(ctx); cfg != nil {
+		return cfg.Uploads
+	}
+	return tenant.UploadLimits{}
+}
+
+// maxFileBytes returns the largest file the tenant may upload.
+func maxFileBytes(limits tenant.UploadLimits) int64 {
+	if limits.MaxFileBytes > 0 && limits.MaxFileBytes < maxUploadBytes {
+		return limits.MaxFileBytes
+	}
+	return maxUploadBytes
+}
+
+// checkStoreQuota checks that a file of size bytes fits the store's file
+// count and the tenant's storage limits. Registry errors are logged and the
+// upload allowed, so a database outage does not block uploads. Concurrent
+// uploads are not serialized, so each may overshoot a limit by one file.
+func (s *FileService) checkStoreQuota(ctx context.Context, storeID string, size int64) error {
+	limits := uploadLimits(ctx)
+	if s.stores == nil || (limits.MaxFilesPerStore == 0 && limits.MaxStorageBytes == 0) {
+		return nil
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+
+	if limits.MaxFilesPerStore > 0 {
+		usage, err := s.stores.GetStoreUsage(ctx, tenantID, storeID)
+		if err != nil {
+			slog.WarnContext(ctx, "store registry unavailable, skipping file count limit", "tenant_id", tenantID, "store_id", storeID, "error", err)
+		} else if usage.FileCount >= limits.MaxFilesPerStore {
+			return &QuotaError{Limit: QuotaFilesPerStore, Max: int64(limits.MaxFilesPerStore), Used: int64(usage.FileCount), Requested: 1}
+		}
+	}
+
+	if limits.MaxStorageBytes > 0 {
+		used, err := s.stores.GetTenantStorageBytes(ctx, tenantID)
+		if err != nil {
+			slog.WarnContext(ctx, "store registry unavailable, skipping storage limit", "tenant_id", tenantID, "error", err)
+		} else if used+size > limits.MaxStorageBytes {
+			return &QuotaError{Limit: QuotaStorageBytes, Max: limits.MaxStorageBytes, Used: used, Requested: size}
+		}
+	}
+	return nil
+}
+
+// recordUpload adds a stored file to the store registry.
+func (s *FileService) recordUpload(ctx context.Context, storeID, providerName string, size int64) {
+	if s.stores == nil {
+		return
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	if err := s.stores.RecordStoreUpload(ctx, tenantID, storeID, providerName, size); err != nil {
+		slog.WarnContext(ctx, "failed to record upload in store registry", "tenant_id", tenantID, "store_id", storeID, "error", err)
+	}
+}
+
+// forgetStore removes a deleted store from the store registry.
+func (s *FileService) forgetStore(ctx context.Context, storeID string) {
+	if s.stores == nil {
+		return
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	if err := s.stores.DeleteStoreUsage(ctx, tenantID, storeID); err != nil {
+		slog.WarnContext(ctx, "failed to remove store from store registry", "tenant_id", tenantID, "store_id", storeID, "error", err)
+	}
+}
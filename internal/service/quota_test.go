@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStoreRegistry is an in-memory StoreRegistry.
+type fakeStoreRegistry struct {
+	usage map[string]db.StoreUsage // tenant/store -> usage
+	err   error
+}
+
+func newFakeStoreRegistry() *fakeStoreRegistry {
+	return &fakeStoreRegistry{usage: make(map[string]db.StoreUsage)}
+}
+
+func (f *fakeStoreRegistry) GetStoreUsage(ctx context.Context, tenantID, storeID string) (db.StoreUsage, error) {
+	return f.usage[tenantID+"/"+storeID], f.err
+}
+
+func (f *fakeStoreRegistry) GetTenantStorageBytes(ctx context.Context, tenantID string) (int64, error) {
+	var total int64
+	for key, u := range f.usage {
+		if strings.HasPrefix(key, tenantID+"/") {
+			total += u.TotalBytes
+		}
+	}
+	return total, f.err
+}
+
+func (f *fakeStoreRegistry) RecordStoreUpload(ctx context.Context, tenantID, storeID, provider string, bytes int64) error {
+	u := f.usage[tenantID+"/"+storeID]
+	u.FileCount++
+	u.TotalBytes += bytes
+	f.usage[tenantID+"/"+storeID] = u
+	return f.err
+}
+
+func (f *fakeStoreRegistry) DeleteStoreUsage(ctx context.Context, tenantID, storeID string) error {
+	delete(f.usage, tenantID+"/"+storeID)
+	return f.err
+}
+
+func ctxWithUploadLimits(limits tenant.UploadLimits) context.Context {
+	ctx := ctxWithFilePermission("client")
+	return context.WithValue(ctx, auth.TenantContextKey, &tenant.TenantConfig{TenantID: "tenant1", Uploads: limits})
+}
+
+func uploadStream(ctx context.Context, storeID string, declared int64, content string) *mockUploadFileServer {
+	return &mockUploadFileServer{
+		ctx: ctx,
+		messages: []*pb.UploadFileRequest{
+			{Data: &pb.UploadFileRequest_Metadata{Metadata: &pb.UploadFileMetadata{StoreId: storeID, Filename: "doc.txt", Size: declared}}},
+			{Data: &pb.UploadFileRequest_Chunk{Chunk: []byte(content)}},
+		},
+	}
+}
+
+func TestUploadFile_Quotas(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    tenant.UploadLimits
+		usage     map[string]db.StoreUsage
+		declared  int64
+		content   string
+		wantLimit string // Empty for success
+	}{
+		{"within limits", tenant.UploadLimits{MaxFileBytes: 100, MaxFilesPerStore: 2, MaxStorageBytes: 100}, nil, 0, "hello", ""},
+		{"declared size too large", tenant.UploadLimits{MaxFileBytes: 10}, nil, 50, "hello", QuotaFileBytes},
+		{"streamed size too large", tenant.UploadLimits{MaxFileBytes: 3}, nil, 0, "hello", QuotaFileBytes},
+		{"store full", tenant.UploadLimits{MaxFilesPerStore: 1}, map[string]db.StoreUsage{"tenant1/docs": {FileCount: 1, TotalBytes: 5}}, 0, "hello", QuotaFilesPerStore},
+		{"other store not counted", tenant.UploadLimits{MaxFilesPerStore: 1}, map[string]db.StoreUsage{"tenant1/other": {FileCount: 1}}, 0, "hello", ""},
+		{"storage full", tenant.UploadLimits{MaxStorageBytes: 8}, map[string]db.StoreUsage{"tenant1/other": {FileCount: 1, TotalBytes: 5}}, 0, "hello", QuotaStorageBytes},
+		{"other tenant not counted", tenant.UploadLimits{MaxStorageBytes: 8}, map[string]db.StoreUsage{"tenant2/docs": {FileCount: 1, TotalBytes: 500}}, 0, "hello", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newFakeStoreRegistry()
+			for k, v := range tt.usage {
+				registry.usage[k] = v
+			}
+			svc := NewFileService(createRAGServiceWithMocks(testutil.NewMockStore(), nil, nil), nil, WithStoreRegistry(registry))
+
+			err := svc.UploadFile(uploadStream(ctxWithUploadLimits(tt.limits), "docs", tt.declared, tt.content))
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("UploadFile failed: %v", err)
+				}
+				if u := registry.usage["tenant1/docs"]; u.FileCount != 1 || u.TotalBytes != int64(len(tt.content)) {
+					t.Errorf("upload not recorded: %+v", u)
+				}
+				return
+			}
+			var quotaErr *QuotaError
+			if !errors.As(err, &quotaErr) || quotaErr.Limit != tt.wantLimit {
+				t.Fatalf("expected %s quota error, got %v", tt.wantLimit, err)
+			}
+			if status.Code(err) != codes.ResourceExhausted {
+				t.Errorf("expected ResourceExhausted, got %v", status.Code(err))
+			}
+		})
+	}
+}
+
+func TestUploadFile_RegistryErrorAllowsUpload(t *testing.T) {
+	registry := newFakeStoreRegistry()
+	registry.err = errors.New("database down")
+	svc := NewFileService(createRAGServiceWithMocks(testutil.NewMockStore(), nil, nil), nil, WithStoreRegistry(registry))
+
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{MaxFilesPerStore: 1, MaxStorageBytes: 1})
+	if err := svc.UploadFile(uploadStream(ctx, "docs", 0, "hello")); err != nil {
+		t.Fatalf("expected upload to proceed without the registry, got %v", err)
+	}
+}
+
+func TestDeleteFileStore_ForgetsStore(t *testing.T) {
+	registry := newFakeStoreRegistry()
+	registry.usage["tenant1/docs"] = db.StoreUsage{FileCount: 3, TotalBytes: 300}
+	mockStore := testutil.NewMockStore()
+	mockStore.CreateCollection(context.Background(), "tenant1_docs", 768)
+	svc := NewFileService(createRAGServiceWithMocks(mockStore, nil, nil), nil, WithStoreRegistry(registry))
+
+	resp, err := svc.DeleteFileStore(ctxWithUploadLimits(tenant.UploadLimits{}), &pb.DeleteFileStoreRequest{StoreId: "docs"})
+	if err != nil || !resp.Success {
+		t.Fatalf("DeleteFileStore failed: %v, %+v", err, resp)
+	}
+	if _, ok := registry.usage["tenant1/docs"]; ok {
+		t.Error("expected the store to be removed from the registry")
+	}
+}
+
+func TestUploadFile_FileType(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		file    string
+		content string
+		code    codes.Code
+	}{
+		{"allowed", []string{"text/*"}, "doc.txt", "hello", codes.OK},
+		{"not in allowlist", []string{"application/pdf"}, "doc.txt", "hello", codes.InvalidArgument},
+		{"content mismatch", nil, "doc.pdf", "hello", codes.InvalidArgument},
+		{"executable", nil, "doc.txt", "#!/bin/sh\necho hi\n", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newFakeStoreRegistry()
+			svc := NewFileService(createRAGServiceWithMocks(testutil.NewMockStore(), nil, nil), nil, WithStoreRegistry(registry))
+
+			stream := uploadStream(ctxWithUploadLimits(tenant.UploadLimits{AllowedTypes: tt.allowed}), "docs", 0, tt.content)
+			stream.messages[0].GetMetadata().Filename = tt.file
+			err := svc.UploadFile(stream)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if tt.code != codes.OK && len(registry.usage) != 0 {
+				t.Error("rejected upload was recorded")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/retry"
+	"google.golang.org/grpc/status"
+)
+
+// fileSearchErrorPatterns identify provider errors caused by native file
+// search, such as a deleted vector store.
+var fileSearchErrorPatterns = []string{"vector store", "vector_store", "file_search", "file search"}
+
+// usesNativeFileSearch reports whether prepared searches the provider's own
+// file store rather than injecting internal RAG context (see prepareRequest).
+func usesNativeFileSearch(prepared *preparedRequest) bool {
+	return prepared.params.EnableFileSearch &&
+		strings.TrimSpace(prepared.params.FileStoreID) != "" &&
+		prepared.provider.Name() == provider.NameOpenAI
+}
+
+// fileSearchFailure reports whether err may come from native file search: an
+// error naming the vector store or file_search tool, or a server error, which
+// file search can cause as well. Rate limits and cancellations are not.
+func fileSearchFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if _, ok := status.FromError(err); ok {
+		return false // Raised by the service, not the provider
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range fileSearchErrorPatterns {
+		if strings.Contains(msg, p) {
+			return true
+		}
+	}
+	return retry.IsRetryable(err) && !retry.IsRateLimited(err)
+}
+
+// ragFallback switches a request whose native file search failed with err to
+// the internal RAG path, so it can be retried. Context is retrieved from the
+// internal store mirroring the provider's: the tenant's store with the same
+// ID, filled by uploading the same files without a provider. It reports false,
+// leaving prepared unchanged, when there is no such store or it has nothing
+// relevant to the question.
+func (s *ChatService) ragFallback(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, err error) bool {
+	if !usesNativeFileSearch(prepared) || !fileSearchFailure(err) {
+		return false
+	}
+	storeID := prepared.params.FileStoreID
+	query := s.retrievalQuery(ctx, req, prepared.provider, prepared.requestID)
+	chunks, ragErr := s.retrieveRAGContext(ctx, storeID, query, ragPackOptions(ctx, req))
+	if ragErr != nil {
+		slog.WarnContext(ctx, "RAG fallback retrieval failed", "error", ragErr, "store_id", storeID)
+		return false
+	}
+	if len(chunks) == 0 {
+		return false
+	}
+
+	slog.WarnContext(ctx, "native file search failed, retrying with internal RAG",
+		"provider", prepared.provider.Name(),
+		"store_id", storeID,
+		"chunks", len(chunks),
+		"error", err,
+	)
+	prepared.params.EnableFileSearch = false
+	prepared.params.Instructions += formatRAGContext(chunks)
+	prepared.ragChunks = chunks
+	accesslog.Annotate(ctx, "rag_fallback", true, "rag_store_id", storeID, "rag_chunks", len(chunks))
+	return true
+}
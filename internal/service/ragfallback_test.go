@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/rag"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+)
+
+func TestFileSearchFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New(`404 Not Found: Vector store with id 'vs_abc' not found`), true},
+		{errors.New("file_search tool call failed"), true},
+		{errors.New("500 Internal Server Error: server_error"), true},
+		{errors.New("429 Too Many Requests: rate limit reached"), false},
+		{errors.New("401 Unauthorized: invalid_api_key"), false},
+		{context.DeadlineExceeded, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := fileSearchFailure(tt.err); got != tt.want {
+			t.Errorf("fileSearchFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// newMirroredRAGService returns a RAG service whose test-tenant store
+// vs_docs mirrors an OpenAI vector store of the same ID.
+func newMirroredRAGService() *rag.Service {
+	store := testutil.NewMockStore()
+	store.CreateCollection(context.Background(), "test-tenant_vs_docs", 768)
+	store.Upsert(context.Background(), "test-tenant_vs_docs", []vectorstore.Point{{
+		ID:      "chunk1",
+		Vector:  make([]float32, 768),
+		Payload: map[string]any{"text": "Refunds are issued within 14 days.", "filename": "policy.pdf"},
+	}})
+	return rag.NewService(testutil.NewMockEmbedder(768), store, testutil.NewMockExtractor(), rag.DefaultServiceOptions())
+}
+
+func TestGenerateReply_RAGFallback(t *testing.T) {
+	openai := newMockProvider("openai")
+	openai.generateErr = errors.New(`404 Not Found: Vector store with id 'vs_docs' not found`)
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), newMirroredRAGService())
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	_, _ = svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "How long do refunds take?",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFileSearch:  true,
+		FileStoreId:       "vs_docs",
+	})
+
+	if len(openai.generateCalls) != 2 {
+		t.Fatalf("expected a retry after the file search failure, got %d calls", len(openai.generateCalls))
+	}
+	if !openai.generateCalls[0].EnableFileSearch {
+		t.Error("expected the first attempt to use native file search")
+	}
+	retried := openai.generateCalls[1]
+	if retried.EnableFileSearch {
+		t.Error("expected the retry to disable native file search")
+	}
+	if !strings.Contains(retried.Instructions, "Refunds are issued within 14 days.") {
+		t.Errorf("expected the retry to carry mirrored store context, got %q", retried.Instructions)
+	}
+}
+
+func TestGenerateReply_NoRAGFallbackWithoutMirror(t *testing.T) {
+	openai := newMockProvider("openai")
+	openai.generateErr = errors.New(`404 Not Found: Vector store with id 'vs_other' not found`)
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), newMirroredRAGService())
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "How long do refunds take?",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		EnableFileSearch:  true,
+		FileStoreId:       "vs_other",
+	})
+	if err == nil {
+		t.Fatal("expected the original error")
+	}
+	if len(openai.generateCalls) != 1 {
+		t.Errorf("expected no retry without a mirrored store, got %d calls", len(openai.generateCalls))
+	}
+}
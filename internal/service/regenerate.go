@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/logctx"
+	"github.com/ai8future/airborne/internal/validation"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// failedReplyPrefix marks the assistant message persisted for a failed request.
+const failedReplyPrefix = "[FAILED] "
+
+// RegenerateMessage truncates a persisted thread at a message and generates a
+// new reply from the thread's earlier messages. The new turn is persisted like
+// any GenerateReply turn, as a branch alongside the replaced messages, which
+// are marked superseded only once it succeeds. Without new content the user
+// message is kept and the new reply is an alternative to its earlier
+// replies. A failed regeneration keeps the old branch and, like any failed
+// request, appends a failed turn, which later history leaves out.
+func (s *ChatService) RegenerateMessage(ctx context.Context, req *pb.RegenerateMessageRequest) (*pb.RegenerateMessageResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChat); err != nil {
+		return nil, err
+	}
+
+	options := &pb.GenerateReplyRequest{}
+	if req.Options != nil {
+		options = proto.Clone(req.Options).(*pb.GenerateReplyRequest)
+	}
+	repo, msg, err := s.threadMessage(ctx, req.MessageId, options.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	ctx = logctx.With(ctx, logctx.KeyThreadID, msg.ThreadID.String())
+
+	messages, err := repo.GetActiveMessages(ctx, msg.ThreadID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get thread messages", "error", err, "thread_id", msg.ThreadID)
+		return nil, status.Error(codes.Internal, "failed to load thread")
+	}
+	start, err := regenerationStart(messages, msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	userMsg := messages[start]
+	placement := &db.TurnPlacement{ReplyID: uuid.New()}
+	replaced := messages[start:]
+
+	options.TenantId = req.TenantId
+	options.RequestId = msg.ThreadID.String() // Persist the new turn to the same thread
+	options.UserInput = userMsg.Content
+	if strings.TrimSpace(req.Content) != "" {
+		options.UserInput = req.Content
+		if userMsg.ParentMessageID != nil {
+			placement.ParentID = *userMsg.ParentMessageID
+		}
+	} else {
+		placement.UserMessageID = userMsg.ID
+		replaced = messages[start+1:]
+	}
+	options.ConversationHistory = conversationHistory(messages[:start])
+	options.PreviousResponseId = ""
+	options.ToolResults = nil
+	options.Idempotent = false
+	if strings.TrimSpace(options.Instructions) == "" {
+		options.Instructions = replacedInstructions(replaced)
+	}
+
+	reply, err := s.generateReply(withTurnPlacement(ctx, placement), options)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(replaced))
+	resp := &pb.RegenerateMessageResponse{Reply: reply, ThreadId: msg.ThreadID.String(), MessageId: placement.ReplyID.String()}
+	for i, m := range replaced {
+		ids[i] = m.ID
+		resp.SupersededMessageIds = append(resp.SupersededMessageIds, m.ID.String())
+	}
+	superseded, err := repo.SupersedeMessages(ctx, msg.ThreadID, ids)
+	if err != nil {
+		// The reply is already persisted; report it rather than have the
+		// client retry and add a third branch
+		slog.ErrorContext(ctx, "failed to supersede regenerated messages", "error", err, "thread_id", msg.ThreadID)
+		resp.SupersededMessageIds = nil
+	}
+
+	accesslog.Annotate(ctx, "thread_id", msg.ThreadID.String(), "message_id", req.MessageId, "superseded_count", superseded)
+	return resp, nil
+}
+
+// ListBranches lists the alternatives at a message of a persisted thread:
+// the edits of a user message, or the replies regenerated for the user
+// message of an assistant reply.
+func (s *ChatService) ListBranches(ctx context.Context, req *pb.ListBranchesRequest) (*pb.ListBranchesResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChat); err != nil {
+		return nil, err
+	}
+	repo, msg, err := s.threadMessage(ctx, req.MessageId, req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	ctx = logctx.With(ctx, logctx.KeyThreadID, msg.ThreadID.String())
+
+	branches, err := repo.GetBranches(ctx, msg.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get branches", "error", err, "message_id", msg.ID)
+		return nil, status.Error(codes.Internal, "failed to load branches")
+	}
+	resp := &pb.ListBranchesResponse{ThreadId: msg.ThreadID.String()}
+	if msg.ParentMessageID != nil {
+		resp.ParentMessageId = msg.ParentMessageID.String()
+	}
+	for _, m := range branches {
+		resp.Branches = append(resp.Branches, &pb.Branch{
+			MessageId: m.ID.String(),
+			Role:      m.Role,
+			Content:   m.Content,
+			Provider:  derefString(m.Provider),
+			Model:     derefString(m.Model),
+			CreatedAt: m.CreatedAt.UTC().Format(time.RFC3339),
+			Active:    m.SupersededAt == nil,
+		})
+	}
+	return resp, nil
+}
+
+// SelectBranch makes the branch through a message the thread's current one,
+// so later turns and regenerations continue from it.
+func (s *ChatService) SelectBranch(ctx context.Context, req *pb.SelectBranchRequest) (*pb.SelectBranchResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChat); err != nil {
+		return nil, err
+	}
+	repo, msg, err := s.threadMessage(ctx, req.MessageId, req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	ctx = logctx.With(ctx, logctx.KeyThreadID, msg.ThreadID.String())
+
+	path, err := repo.SelectBranch(ctx, msg.ThreadID, msg.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to select branch", "error", err, "message_id", msg.ID)
+		return nil, status.Error(codes.Internal, "failed to select branch")
+	}
+	if path == nil {
+		return nil, status.Error(codes.NotFound, "message not found")
+	}
+	resp := &pb.SelectBranchResponse{ThreadId: msg.ThreadID.String()}
+	for _, id := range path {
+		resp.MessageIds = append(resp.MessageIds, id.String())
+	}
+
+	accesslog.Annotate(ctx, "thread_id", msg.ThreadID.String(), "message_id", req.MessageId)
+	return resp, nil
+}
+
+// threadMessage loads a message of a persisted thread for the branching
+// RPCs. Messages in threads of other users are reported as missing.
+func (s *ChatService) threadMessage(ctx context.Context, id, clientID string) (*db.Repository, *db.Message, error) {
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, "message_id must be a valid UUID")
+	}
+	if s.dbClient == nil {
+		return nil, nil, status.Error(codes.FailedPrecondition, "threads require a database to be configured")
+	}
+	repo, err := s.dbClient.TenantRepository(auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, nil, status.Error(codes.FailedPrecondition, "threads are not available for this tenant")
+	}
+
+	msg, err := repo.GetMessage(ctx, messageID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get message", "error", err, "message_id", messageID)
+		return nil, nil, status.Error(codes.Internal, "failed to load thread")
+	}
+	var thread *db.Thread
+	if msg != nil {
+		thread, err = repo.GetThread(ctx, msg.ThreadID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get thread", "error", err, "thread_id", msg.ThreadID)
+			return nil, nil, status.Error(codes.Internal, "failed to load thread")
+		}
+	}
+	if msg == nil || thread == nil || thread.UserID != conversationUserID(ctx, clientID) {
+		return nil, nil, status.Error(codes.NotFound, "message not found")
+	}
+	return repo, msg, nil
+}
+
+// turnPlacementKey carries the db.TurnPlacement of a regenerated turn from
+// RegenerateMessage to persistConversation.
+type turnPlacementKey struct{}
+
+func withTurnPlacement(ctx context.Context, placement *db.TurnPlacement) context.Context {
+	return context.WithValue(ctx, turnPlacementKey{}, placement)
+}
+
+// turnPlacementFromContext returns the placement set by withTurnPlacement,
+// or nil to continue the thread's current branch.
+func turnPlacementFromContext(ctx context.Context) *db.TurnPlacement {
+	placement, _ := ctx.Value(turnPlacementKey{}).(*db.TurnPlacement)
+	return placement
+}
+
+// regenerationStart returns the index in messages of the user message a
+// regeneration from messageID starts at: the message itself, or for an
+// assistant message the user message before it.
+func regenerationStart(messages []db.Message, messageID uuid.UUID) (int, error) {
+	i := -1
+	for j, m := range messages {
+		if m.ID == messageID {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return 0, status.Error(codes.FailedPrecondition, "message has been superseded")
+	}
+	switch messages[i].Role {
+	case db.RoleUser:
+		return i, nil
+	case db.RoleAssistant:
+		for j := i - 1; j >= 0; j-- {
+			if messages[j].Role == db.RoleUser {
+				return j, nil
+			}
+		}
+		return 0, status.Error(codes.FailedPrecondition, "no user message precedes this reply")
+	}
+	return 0, status.Errorf(codes.InvalidArgument, "cannot regenerate from a %s message", messages[i].Role)
+}
+
+// conversationHistory converts the messages before a regenerated turn to
+// request history, keeping the most recent validation.MaxHistoryCount.
+// Failed turns are left out along with the user message that caused them.
+func conversationHistory(messages []db.Message) []*pb.Message {
+	var history []*pb.Message
+	for _, m := range messages {
+		if m.Role == db.RoleAssistant && strings.HasPrefix(m.Content, failedReplyPrefix) {
+			if n := len(history); n > 0 && history[n-1].Role == db.RoleUser {
+				history = history[:n-1]
+			}
+			continue
+		}
+		history = append(history, &pb.Message{Role: m.Role, Content: m.Content, Timestamp: m.CreatedAt.Unix()})
+	}
+	if len(history) > validation.MaxHistoryCount {
+		history = history[len(history)-validation.MaxHistoryCount:]
+	}
+	return history
+}
+
+// replacedInstructions returns the system prompt of the first reply being
+// replaced, or "" when none was recorded.
+func replacedInstructions(replaced []db.Message) string {
+	for _, m := range replaced {
+		if m.Role == db.RoleAssistant {
+			if m.SystemPrompt != nil {
+				return *m.SystemPrompt
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newThreadRepo returns a SQLite-backed client and the "ai8" tenant's
+// repository, holding a thread of two turns owned by test-client.
+func newThreadRepo(t *testing.T) (*db.Client, *db.Repository, uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	client, err := db.NewClient(ctx, db.Config{Driver: db.DriverSQLite, URL: filepath.Join(t.TempDir(), "airborne.db")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+	repo, _ := client.TenantRepository("ai8")
+
+	threadID := uuid.New()
+	for _, turn := range []string{"first", "second"} {
+		err := repo.PersistConversationTurnWithDebug(ctx, threadID, "test-client", turn, "re: "+turn, "openai", "gpt-4o", "",
+			1, 2, 3, 0.01, 0, 0, &db.DebugInfo{SystemPrompt: "be brief"}, nil, nil)
+		if err != nil {
+			t.Fatalf("PersistConversationTurnWithDebug failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond) // NOW() has millisecond resolution in SQLite
+	}
+	return client, repo, threadID
+}
+
+// waitForMessages polls until the thread's current branch has n messages.
+func waitForMessages(t *testing.T, repo *db.Repository, threadID uuid.UUID, n int) []db.Message {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		messages, err := repo.GetActiveMessages(context.Background(), threadID)
+		if err != nil {
+			t.Fatalf("GetActiveMessages failed: %v", err)
+		}
+		if len(messages) == n || time.Now().After(deadline) {
+			return messages
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRegenerateMessage(t *testing.T) {
+	client, repo, threadID := newThreadRepo(t)
+	mockOpenAI := newMockProvider("openai")
+	svc := createChatServiceWithMocks(mockOpenAI, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.dbClient = client
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.TenantID = "ai8"
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+
+	before := waitForMessages(t, repo, threadID, 4)
+	resp, err := svc.RegenerateMessage(ctx, &pb.RegenerateMessageRequest{
+		MessageId: before[3].ID.String(), // Reply to "second"
+		Content:   "second, edited",
+		Options:   &pb.GenerateReplyRequest{PreferredProvider: pb.Provider_PROVIDER_OPENAI},
+	})
+	if err != nil {
+		t.Fatalf("RegenerateMessage failed: %v", err)
+	}
+	if resp.ThreadId != threadID.String() || resp.Reply.GetText() != "Mock response" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(resp.SupersededMessageIds) != 2 || resp.SupersededMessageIds[0] != before[2].ID.String() {
+		t.Errorf("SupersededMessageIds = %v, want the second turn", resp.SupersededMessageIds)
+	}
+
+	call := mockOpenAI.generateCalls[0]
+	if call.UserInput != "second, edited" || call.Instructions != "be brief" {
+		t.Errorf("unexpected generation: input=%q instructions=%q", call.UserInput, call.Instructions)
+	}
+	if len(call.ConversationHistory) != 2 || call.ConversationHistory[0].Content != "first" || call.ConversationHistory[1].Content != "re: first" {
+		t.Errorf("unexpected history: %+v", call.ConversationHistory)
+	}
+
+	after := waitForMessages(t, repo, threadID, 4)
+	if len(after) != 4 || after[2].Content != "second, edited" || after[3].Content != "Mock response" {
+		t.Fatalf("expected the new branch to replace the second turn, got %d messages", len(after))
+	}
+
+	// The old branch cannot be regenerated again
+	_, err = svc.RegenerateMessage(ctx, &pb.RegenerateMessageRequest{MessageId: before[2].ID.String()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("superseded message: expected FailedPrecondition, got %v", err)
+	}
+}
+
+func TestRegenerateMessage_Errors(t *testing.T) {
+	client, repo, threadID := newThreadRepo(t)
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.TenantID = "ai8"
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+	messages := waitForMessages(t, repo, threadID, 4)
+
+	if _, err := svc.RegenerateMessage(ctx, &pb.RegenerateMessageRequest{MessageId: messages[0].ID.String()}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("no database: expected FailedPrecondition, got %v", err)
+	}
+	svc.dbClient = client
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		messageID string
+		code      codes.Code
+	}{
+		{"invalid id", ctx, "not-a-uuid", codes.InvalidArgument},
+		{"unknown message", ctx, uuid.NewString(), codes.NotFound},
+		{"other user's thread", ctxWithChatPermissionAndTenant("other-client", tenantCfg), messages[0].ID.String(), codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.RegenerateMessage(tt.ctx, &pb.RegenerateMessageRequest{MessageId: tt.messageID})
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestConversationHistory_SkipsFailedTurns(t *testing.T) {
+	messages := []db.Message{
+		{Role: db.RoleUser, Content: "hello"},
+		{Role: db.RoleAssistant, Content: "hi"},
+		{Role: db.RoleUser, Content: "broken"},
+		{Role: db.RoleAssistant, Content: failedReplyPrefix + "timeout"},
+	}
+	history := conversationHistory(messages)
+	if len(history) != 2 || history[1].Content != "hi" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestBranches(t *testing.T) {
+	client, repo, threadID := newThreadRepo(t)
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.dbClient = client
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.TenantID = "ai8"
+	ctx := ctxWithChatPermissionAndTenant("test-client", tenantCfg)
+	before := waitForMessages(t, repo, threadID, 4)
+
+	// Regenerating without new content keeps the user message
+	resp, err := svc.RegenerateMessage(ctx, &pb.RegenerateMessageRequest{
+		MessageId: before[3].ID.String(),
+		Options:   &pb.GenerateReplyRequest{PreferredProvider: pb.Provider_PROVIDER_OPENAI},
+	})
+	if err != nil {
+		t.Fatalf("RegenerateMessage failed: %v", err)
+	}
+	if len(resp.SupersededMessageIds) != 1 || resp.SupersededMessageIds[0] != before[3].ID.String() {
+		t.Errorf("SupersededMessageIds = %v, want the old reply", resp.SupersededMessageIds)
+	}
+	after := waitForMessages(t, repo, threadID, 4)
+	if after[2].ID != before[2].ID || after[3].ID.String() != resp.MessageId {
+		t.Fatalf("expected the new reply to answer the original message, got %+v", after)
+	}
+
+	branches, err := svc.ListBranches(ctx, &pb.ListBranchesRequest{MessageId: resp.MessageId})
+	if err != nil {
+		t.Fatalf("ListBranches failed: %v", err)
+	}
+	if branches.ParentMessageId != before[2].ID.String() || len(branches.Branches) != 2 {
+		t.Fatalf("unexpected branches: %+v", branches)
+	}
+	if old := branches.Branches[0]; old.MessageId != before[3].ID.String() || old.Active || old.Content != "re: second" || old.Model != "gpt-4o" {
+		t.Errorf("unexpected original branch: %+v", old)
+	}
+	if !branches.Branches[1].Active {
+		t.Errorf("expected the new reply to be active")
+	}
+
+	selected, err := svc.SelectBranch(ctx, &pb.SelectBranchRequest{MessageId: before[3].ID.String()})
+	if err != nil {
+		t.Fatalf("SelectBranch failed: %v", err)
+	}
+	if len(selected.MessageIds) != 4 || selected.MessageIds[3] != before[3].ID.String() {
+		t.Errorf("unexpected selected branch: %v", selected.MessageIds)
+	}
+	if active := waitForMessages(t, repo, threadID, 4); active[3].Content != "re: second" {
+		t.Errorf("expected the original reply to be current, got %q", active[3].Content)
+	}
+
+	other := ctxWithChatPermissionAndTenant("other-client", tenantCfg)
+	if _, err := svc.ListBranches(other, &pb.ListBranchesRequest{MessageId: resp.MessageId}); status.Code(err) != codes.NotFound {
+		t.Errorf("other user's thread: expected NotFound, got %v", err)
+	}
+	if _, err := svc.SelectBranch(ctx, &pb.SelectBranchRequest{MessageId: "not-a-uuid"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid id: expected InvalidArgument, got %v", err)
+	}
+}
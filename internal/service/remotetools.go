@@ -0,0 +1,322 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/localtools"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/retry"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxRemoteToolRounds bounds how many times one request answers the model's
+// remote tool calls, against models that call tools in a loop.
+const maxRemoteToolRounds = 5
+
+// remoteToolEgress attributes remote tool calls in the egress audit.
+const remoteToolEgress = "remote_tool"
+
+// RemoteToolCall is the JSON body POSTed to a remote tool's endpoint.
+type RemoteToolCall struct {
+	Tool      string          `json:"tool"`
+	CallID    string          `json:"call_id"`
+	TenantID  string          `json:"tenant_id"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// addRemoteTools offers the tenant's remote tools and enabled built-in
+// tools to the model alongside the request's own tools. fetch_url is left
+// out when the request uses provider web search. A request tool may not
+// share a server tool's name, since its calls would be ambiguous.
+func addRemoteTools(ctx context.Context, params *provider.GenerateParams) error {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil || (len(tenantCfg.RemoteTools) == 0 && len(tenantCfg.LocalTools) == 0 && !tenantCfg.QueryDatabase.Enabled() && !tenantCfg.FetchURL.Enabled()) {
+		return nil
+	}
+	for _, t := range params.Tools {
+		if isServerTool(tenantCfg, t.Name) {
+			return status.Errorf(codes.InvalidArgument, "tool %q is already provided by the tenant", t.Name)
+		}
+	}
+
+	names := make([]string, 0, len(tenantCfg.RemoteTools))
+	for name := range tenantCfg.RemoteTools {
+		names = append(names, name)
+	}
+	slices.Sort(names) // Stable tool order keeps provider prompt caches warm
+	tools := slices.Clone(params.Tools)
+	for _, name := range names {
+		rt := tenantCfg.RemoteTools[name]
+		var schema []byte
+		if rt.Parameters != nil {
+			schema, _ = json.Marshal(rt.Parameters) // Validated at config load
+		}
+		tools = append(tools, provider.Tool{Name: name, Description: rt.Description, ParametersSchema: string(schema)})
+	}
+	for _, name := range localToolNames(tenantCfg) {
+		lt, _ := localtools.Lookup(name)
+		schema, _ := json.Marshal(lt.Parameters)
+		tools = append(tools, provider.Tool{Name: name, Description: lt.Description, ParametersSchema: string(schema)})
+	}
+	if tenantCfg.QueryDatabase.Enabled() {
+		tools = append(tools, queryDatabaseTool(tenantCfg.QueryDatabase))
+	}
+	if tenantCfg.FetchURL.Enabled() && !params.EnableWebSearch {
+		tools = append(tools, fetchURLTool(tenantCfg.FetchURL))
+	}
+	params.Tools = tools
+	return nil
+}
+
+// isServerTool reports whether the server answers calls to the named tool
+// for the tenant.
+func isServerTool(tenantCfg *tenant.TenantConfig, name string) bool {
+	switch {
+	case name == tenant.QueryDatabaseToolName && tenantCfg.QueryDatabase.Enabled():
+		return true
+	case name == tenant.FetchURLToolName && tenantCfg.FetchURL.Enabled():
+		return true
+	case slices.Contains(tenantCfg.LocalTools, name):
+		return true
+	}
+	_, ok := tenantCfg.RemoteTools[name]
+	return ok
+}
+
+// serverAnswers reports whether the server answers every tool call of
+// result. Mixed batches go back to the client unanswered, as calls are
+// answered together.
+func serverAnswers(ctx context.Context, result provider.GenerateResult) bool {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil || !result.RequiresToolOutput || len(result.ToolCalls) == 0 || len(result.ComputerActions) > 0 {
+		return false
+	}
+	for _, call := range result.ToolCalls {
+		if !isServerTool(tenantCfg, call.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// runRemoteTools answers the model's calls to the tenant's server tools and
+// continues the turn from p until the model replies without them, for up to
+// maxRemoteToolRounds rounds. The returned result's usage covers every round.
+func (s *ChatService) runRemoteTools(ctx context.Context, p provider.Provider, params provider.GenerateParams, result provider.GenerateResult) (provider.GenerateResult, error) {
+	userInput := params.UserInput
+	if params.ToolTurn != nil {
+		userInput = params.ToolTurn.UserInput // Chained calls keep the original message
+	}
+
+	calls := 0
+	defer func() {
+		if calls > 0 {
+			accesslog.Annotate(ctx, "remote_tool_calls", calls)
+		}
+	}()
+	for round := 0; round < maxRemoteToolRounds; round++ {
+		if !serverAnswers(ctx, result) {
+			return result, nil
+		}
+		results := make([]provider.ToolResult, len(result.ToolCalls))
+		for i, call := range result.ToolCalls {
+			results[i] = s.callServerTool(ctx, call)
+			calls++
+		}
+
+		next := params
+		next.UserInput = ""
+		next.Attachments = nil
+		next.InlineImages = nil
+		next.ToolResults = results
+		next.PreviousResponseID = result.ResponseID
+		next.ToolTurn = &provider.ToolTurn{UserInput: userInput, ToolCalls: result.ToolCalls, State: result.ToolTurnState}
+		params = next
+
+		usage := result.Usage
+		var err error
+		result, err = p.GenerateReply(s.observeHeadroom(ctx, p.Name()), params)
+		s.reportProviderCall(ctx, p.Name(), params, result, err)
+		if err != nil {
+			return result, err
+		}
+		result.Usage = addUsage(usage, result.Usage)
+	}
+	if serverAnswers(ctx, result) {
+		return result, fmt.Errorf("model still calling remote tools after %d rounds", maxRemoteToolRounds)
+	}
+	return result, nil
+}
+
+// localToolNames returns the tenant's local tools, sorted and without
+// duplicates or tools that do not exist.
+func localToolNames(tenantCfg *tenant.TenantConfig) []string {
+	var names []string
+	for _, name := range tenantCfg.LocalTools {
+		if _, ok := localtools.Lookup(name); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// callLocalTool answers call with the local tool of its name, in-process.
+// Invalid arguments are returned to the model as error results so it can
+// correct them.
+func callLocalTool(ctx context.Context, call provider.ToolCall) provider.ToolResult {
+	lt, ok := localtools.Lookup(call.Name)
+	if !ok {
+		return provider.ToolResult{ToolCallID: call.ID, Output: "tool call failed: unknown tool", IsError: true}
+	}
+	out, err := lt.Run(call.Arguments)
+	if err != nil {
+		slog.DebugContext(ctx, "local tool call failed",
+			"tool", call.Name,
+			"call_id", call.ID,
+			"error", err,
+		)
+		return provider.ToolResult{ToolCallID: call.ID, Output: "tool call failed: " + err.Error(), IsError: true}
+	}
+	return provider.ToolResult{ToolCallID: call.ID, Output: out}
+}
+
+// callServerTool answers call with the tenant's tool of that name.
+func (s *ChatService) callServerTool(ctx context.Context, call provider.ToolCall) provider.ToolResult {
+	tenantCfg := auth.TenantFromContext(ctx)
+	switch {
+	case call.Name == tenant.QueryDatabaseToolName && tenantCfg.QueryDatabase.Enabled():
+		return s.callQueryDatabase(ctx, tenantCfg.QueryDatabase, call)
+	case call.Name == tenant.FetchURLToolName && tenantCfg.FetchURL.Enabled():
+		return s.callFetchURL(ctx, tenantCfg.FetchURL, call)
+	case slices.Contains(tenantCfg.LocalTools, call.Name):
+		return callLocalTool(ctx, call)
+	}
+	return s.callRemoteTool(ctx, tenantCfg.RemoteTools[call.Name], call)
+}
+
+// addUsage returns the sum of two calls' token usage.
+func addUsage(a, b *provider.Usage) *provider.Usage {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &provider.Usage{
+		InputTokens:    a.InputTokens + b.InputTokens,
+		OutputTokens:   a.OutputTokens + b.OutputTokens,
+		TotalTokens:    a.TotalTokens + b.TotalTokens,
+		CachedTokens:   a.CachedTokens + b.CachedTokens,
+		ThinkingTokens: a.ThinkingTokens + b.ThinkingTokens,
+		ToolUseTokens:  a.ToolUseTokens + b.ToolUseTokens,
+	}
+}
+
+// callRemoteTool POSTs call to the tool's endpoint, retrying network errors,
+// 429s and 5xx responses. Failures are returned to the model as error
+// results so it can answer without the tool.
+func (s *ChatService) callRemoteTool(ctx context.Context, rt tenant.RemoteTool, call provider.ToolCall) provider.ToolResult {
+	out, err := s.postRemoteTool(ctx, rt, call)
+	if err != nil {
+		slog.WarnContext(ctx, "remote tool call failed",
+			"tool", call.Name,
+			"call_id", call.ID,
+			"error", err,
+		)
+		return provider.ToolResult{ToolCallID: call.ID, Output: "tool call failed: " + err.Error(), IsError: true}
+	}
+	return provider.ToolResult{ToolCallID: call.ID, Output: out}
+}
+
+// postRemoteTool returns the response body of a remote tool call, making up
+// to the tool's retry limit of further attempts.
+func (s *ChatService) postRemoteTool(ctx context.Context, rt tenant.RemoteTool, call provider.ToolCall) (string, error) {
+	// SECURITY: the endpoint must not reach internal services
+	if err := validation.ValidateExternalURL(rt.URL); err != nil {
+		return "", fmt.Errorf("endpoint rejected: %w", err)
+	}
+	args := json.RawMessage(call.Arguments)
+	if !json.Valid(args) {
+		args = json.RawMessage("{}")
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	body, err := json.Marshal(RemoteToolCall{Tool: call.Name, CallID: call.ID, TenantID: tenantID, Arguments: args})
+	if err != nil {
+		return "", err
+	}
+
+	transport := s.toolTransport
+	if transport == nil {
+		transport = egress.Transport(tenantProxy(ctx))
+	}
+	client := &http.Client{Transport: transport, Timeout: rt.Timeout()}
+	ctx = egress.WithAttribution(ctx, tenantID, remoteToolEgress)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			retry.SleepWithBackoff(ctx, attempt)
+		}
+		out, retryable, err := postRemoteToolOnce(ctx, client, rt, body)
+		if err == nil || !retryable || attempt >= rt.Retries() || ctx.Err() != nil {
+			return out, err
+		}
+	}
+}
+
+// postRemoteToolOnce makes one attempt at a remote tool call and reports
+// whether a failure is worth retrying.
+func postRemoteToolOnce(ctx context.Context, client *http.Client, rt tenant.RemoteTool, body []byte) (string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rt.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if rt.AuthToken != "" {
+		if rt.AuthHeader == "" || http.CanonicalHeaderKey(rt.AuthHeader) == "Authorization" {
+			req.Header.Set("Authorization", "Bearer "+rt.AuthToken)
+		} else {
+			req.Header.Set(rt.AuthHeader, rt.AuthToken)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // Keep the endpoint out of the model's tool result
+		}
+		return "", true, err
+	}
+	defer resp.Body.Close()
+	limit := rt.ResponseLimit()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return "", true, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return "", true, fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", false, fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	if len(data) > limit {
+		return "", false, fmt.Errorf("response exceeds %d bytes", limit)
+	}
+	return string(data), false, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resultsProvider returns results in order, repeating the last one.
+type resultsProvider struct {
+	*mockProvider
+	results []provider.GenerateResult
+}
+
+func (p *resultsProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	p.mockProvider.GenerateReply(ctx, params)
+	return p.results[min(len(p.generateCalls), len(p.results))-1], nil
+}
+
+// toolCallResult is a reply calling the named tool.
+func toolCallResult(name, args string) provider.GenerateResult {
+	return provider.GenerateResult{
+		ResponseID:         "resp-tool",
+		Model:              "test-model-openai",
+		ToolCalls:          []provider.ToolCall{{ID: "call-1", Name: name, Arguments: args}},
+		RequiresToolOutput: true,
+		Usage:              &provider.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+	}
+}
+
+// remoteToolService returns a service whose openai provider returns results
+// and a tenant offering lookup_order at srv.
+func remoteToolService(srv *httptest.Server, results ...provider.GenerateResult) (*ChatService, *resultsProvider, context.Context) {
+	openai := &resultsProvider{mockProvider: newMockProvider("openai"), results: results}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic"), toolTransport: srv.Client().Transport}
+	cfg := createTestTenantConfig("openai")
+	cfg.RemoteTools = map[string]tenant.RemoteTool{"lookup_order": {
+		Description: "Looks up an order",
+		Parameters:  map[string]any{"type": "object"},
+		URL:         srv.URL + "/order",
+		AuthToken:   "tool-secret",
+	}}
+	return svc, openai, ctxWithChatPermissionAndTenant("test-client", cfg)
+}
+
+func TestGenerateReply_RunsRemoteTools(t *testing.T) {
+	var got RemoteToolCall
+	var authHeader string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"status":"shipped"}`)
+	}))
+	defer srv.Close()
+
+	final := provider.GenerateResult{Text: "Your order has shipped.", ResponseID: "resp-final", Model: "test-model-openai",
+		Usage: &provider.Usage{InputTokens: 20, OutputTokens: 7, TotalTokens: 27}}
+	svc, openai, ctx := remoteToolService(srv, toolCallResult("lookup_order", `{"order_id":"A1"}`), final)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Where is order A1?", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Text != "Your order has shipped." || resp.RequiresToolOutput {
+		t.Errorf("Text = %q, RequiresToolOutput = %v, want the final reply", resp.Text, resp.RequiresToolOutput)
+	}
+	if resp.Usage.GetInputTokens() != 30 || resp.Usage.GetOutputTokens() != 12 {
+		t.Errorf("Usage = %+v, want both rounds counted", resp.Usage)
+	}
+
+	if authHeader != "Bearer tool-secret" {
+		t.Errorf("Authorization = %q", authHeader)
+	}
+	if got.Tool != "lookup_order" || got.CallID != "call-1" || got.TenantID != "test-tenant" || string(got.Arguments) != `{"order_id":"A1"}` {
+		t.Errorf("unexpected tool call body: %+v", got)
+	}
+
+	if len(openai.generateCalls) != 2 {
+		t.Fatalf("provider called %d times, want 2", len(openai.generateCalls))
+	}
+	if tools := openai.generateCalls[0].Tools; len(tools) != 1 || tools[0].Name != "lookup_order" || tools[0].ParametersSchema != `{"type":"object"}` {
+		t.Errorf("Tools = %+v, want the remote tool offered", tools)
+	}
+	cont := openai.generateCalls[1]
+	if len(cont.ToolResults) != 1 || cont.ToolResults[0].Output != `{"status":"shipped"}` || cont.ToolResults[0].IsError {
+		t.Errorf("ToolResults = %+v, want the endpoint's response", cont.ToolResults)
+	}
+	if cont.PreviousResponseID != "resp-tool" || cont.ToolTurn == nil || cont.ToolTurn.UserInput != "Where is order A1?" || cont.UserInput != "" {
+		t.Errorf("continuation does not resume the tool turn: %+v", cont)
+	}
+}
+
+func TestCallRemoteTool_RetriesAndLimits(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			io.WriteString(w, "ok")
+		case "/large":
+			io.WriteString(w, strings.Repeat("x", 2048))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, _, ctx := remoteToolService(srv)
+	call := provider.ToolCall{ID: "call-1", Name: "lookup_order", Arguments: "{}"}
+
+	if out := svc.callRemoteTool(ctx, tenant.RemoteTool{URL: srv.URL + "/flaky"}, call); out.IsError || out.Output != "ok" {
+		t.Errorf("flaky endpoint: %+v, want the retried response", out)
+	}
+	if out := svc.callRemoteTool(ctx, tenant.RemoteTool{URL: srv.URL + "/large", MaxResponseBytes: 1024}, call); !out.IsError {
+		t.Errorf("oversized response: %+v, want an error result", out)
+	}
+	if out := svc.callRemoteTool(ctx, tenant.RemoteTool{URL: srv.URL + "/missing"}, call); !out.IsError || !strings.Contains(out.Output, "404") {
+		t.Errorf("missing endpoint: %+v, want a 404 error result", out)
+	}
+	if out := svc.callRemoteTool(ctx, tenant.RemoteTool{URL: "https://169.254.169.254/latest"}, call); !out.IsError {
+		t.Errorf("metadata endpoint: %+v, want the call rejected", out)
+	}
+}
+
+func TestGenerateReply_RemoteToolNameCollision(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	svc, _, ctx := remoteToolService(srv, provider.GenerateResult{Text: "hi"})
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Hello",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		Tools:             []*pb.Tool{{Name: "lookup_order", Description: "Mine"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGenerateReply_ClientToolsReturnedToClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	svc, openai, ctx := remoteToolService(srv, toolCallResult("get_weather", `{}`))
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "Weather?",
+		PreferredProvider: pb.Provider_PROVIDER_OPENAI,
+		Tools:             []*pb.Tool{{Name: "get_weather", Description: "Weather"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if !resp.RequiresToolOutput || len(resp.ToolCalls) != 1 || len(openai.generateCalls) != 1 {
+		t.Errorf("want the client's tool call returned unanswered, got %+v after %d calls", resp.ToolCalls, len(openai.generateCalls))
+	}
+}
@@ -0,0 +1,506 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxResumableUploadBytes is the maximum size of a resumable upload
+	// (512MB), larger than a single stream can reliably carry.
+	maxResumableUploadBytes int64 = 512 * 1024 * 1024
+
+	// defaultUploadTTL is how long an idle resumable upload is kept.
+	defaultUploadTTL = 24 * time.Hour
+
+	// maxUploadSessionsPerTenant bounds the disk one tenant's unfinished
+	// uploads can use.
+	maxUploadSessionsPerTenant = 8
+
+	// uploadCleanupInterval is how often expired uploads are removed.
+	uploadCleanupInterval = time.Minute
+
+	// uploadPartPattern names the temp files holding upload data.
+	uploadPartPattern = "upload-*.part"
+)
+
+// WithUploadSessions stores resumable uploads in dir and discards them
+// after ttl without a chunk. Empty dir uses a directory under the system
+// temp dir; zero ttl uses 24h.
+func WithUploadSessions(dir string, ttl time.Duration) FileServiceOption {
+	return func(s *FileService) {
+		s.uploads = newUploadSessions(dir, ttl)
+	}
+}
+
+// uploadSession is a resumable upload in progress.
+type uploadSession struct {
+	mu       sync.Mutex // Serializes chunks and completion
+	id       string
+	tenantID string
+	clientID string
+	metadata *pb.UploadFileMetadata
+	path     string // Temp file holding the data received so far
+
+	// state guards the fields below, so status checks need not wait for a
+	// chunk or completion. Writers also hold mu.
+	state     sync.Mutex
+	received  int64
+	expires   time.Time
+	progress  *pb.UploadProgress // Set once completion starts
+	completed bool               // Stored; kept until expiry so the result can be fetched
+	done      bool               // Removed from uploadSessions
+}
+
+// isCompleted reports whether the upload has been stored.
+func (sess *uploadSession) isCompleted() bool {
+	sess.state.Lock()
+	defer sess.state.Unlock()
+	return sess.completed
+}
+
+// isDone reports whether the session has been removed.
+func (sess *uploadSession) isDone() bool {
+	sess.state.Lock()
+	defer sess.state.Unlock()
+	return sess.done
+}
+
+// setProgress records the session's processing progress.
+func (sess *uploadSession) setProgress(progress *pb.UploadProgress) {
+	sess.state.Lock()
+	sess.progress = progress
+	sess.state.Unlock()
+}
+
+// proto converts the session to its API form.
+func (sess *uploadSession) proto() *pb.UploadSession {
+	sess.state.Lock()
+	defer sess.state.Unlock()
+	return &pb.UploadSession{
+		UploadId:      sess.id,
+		Size:          sess.metadata.Size,
+		ReceivedBytes: sess.received,
+		ExpiresAt:     sess.expires.UTC().Format(time.RFC3339),
+		Progress:      sess.progress,
+	}
+}
+
+// uploadSessions tracks resumable uploads. Sessions live in memory with
+// their data in temp files, so they do not survive a restart; files left
+// by an earlier process are removed once they are older than the TTL.
+type uploadSessions struct {
+	dir string
+	ttl time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*uploadSession
+}
+
+func newUploadSessions(dir string, ttl time.Duration) *uploadSessions {
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "airborne-uploads")
+	}
+	if ttl <= 0 {
+		ttl = defaultUploadTTL
+	}
+	return &uploadSessions{dir: dir, ttl: ttl, sessions: make(map[string]*uploadSession)}
+}
+
+// create starts a session for the caller and creates its temp file.
+func (u *uploadSessions) create(ctx context.Context, metadata *pb.UploadFileMetadata) (*uploadSession, error) {
+	tenantID := auth.TenantIDFromContext(ctx)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	active := 0
+	for _, sess := range u.sessions {
+		if sess.tenantID == tenantID && !sess.isCompleted() {
+			active++
+		}
+	}
+	if active >= maxUploadSessionsPerTenant {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many unfinished uploads (max %d); complete or let some expire first", maxUploadSessionsPerTenant)
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("generate upload id: %w", err)
+	}
+	if err := os.MkdirAll(u.dir, 0o700); err != nil {
+		return nil, status.Error(codes.Internal, "failed to create upload directory")
+	}
+	f, err := os.CreateTemp(u.dir, uploadPartPattern)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create temporary file for upload")
+	}
+	f.Close()
+
+	sess := &uploadSession{
+		id:       "upload_" + hex.EncodeToString(buf),
+		tenantID: tenantID,
+		metadata: metadata,
+		path:     f.Name(),
+		expires:  time.Now().Add(u.ttl),
+	}
+	if client := auth.ClientFromContext(ctx); client != nil {
+		sess.clientID = client.ClientID
+	}
+	u.sessions[sess.id] = sess
+	return sess, nil
+}
+
+// lookup returns the caller's session with the given ID. Sessions of other
+// tenants or clients are reported as not found.
+func (u *uploadSessions) lookup(ctx context.Context, id string) (*uploadSession, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "upload_id is required")
+	}
+	u.mu.Lock()
+	sess, ok := u.sessions[id]
+	u.mu.Unlock()
+
+	clientID := ""
+	if client := auth.ClientFromContext(ctx); client != nil {
+		clientID = client.ClientID
+	}
+	if !ok || sess.tenantID != auth.TenantIDFromContext(ctx) || sess.clientID != clientID || sess.isDone() {
+		return nil, status.Errorf(codes.NotFound, "upload not found: %s", id)
+	}
+	return sess, nil
+}
+
+// acquire returns the caller's session with the given ID, locked for a
+// chunk or completion.
+func (u *uploadSessions) acquire(ctx context.Context, id string) (*uploadSession, error) {
+	sess, err := u.lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	if sess.isDone() {
+		sess.mu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "upload not found: %s", id)
+	}
+	return sess, nil
+}
+
+// remove deletes a locked session and its temp file.
+func (u *uploadSessions) remove(sess *uploadSession) {
+	sess.state.Lock()
+	sess.done = true
+	sess.state.Unlock()
+	if err := os.Remove(sess.path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to remove upload temp file", "upload_id", sess.id, "error", err)
+	}
+	u.mu.Lock()
+	delete(u.sessions, sess.id)
+	u.mu.Unlock()
+}
+
+// complete marks a locked session as stored and deletes its temp file. The
+// session is kept until it expires so its result can still be fetched.
+func (u *uploadSessions) complete(sess *uploadSession) {
+	if err := os.Remove(sess.path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to remove upload temp file", "upload_id", sess.id, "error", err)
+	}
+	sess.state.Lock()
+	sess.completed = true
+	sess.expires = time.Now().Add(u.ttl)
+	sess.state.Unlock()
+}
+
+// touch extends a locked session's expiry.
+func (u *uploadSessions) touch(sess *uploadSession) {
+	sess.state.Lock()
+	sess.expires = time.Now().Add(u.ttl)
+	sess.state.Unlock()
+}
+
+// cleanup removes expired sessions, and temp files no session owns that are
+// older than the TTL. Sessions busy with a chunk or completion are skipped.
+func (u *uploadSessions) cleanup(now time.Time) {
+	u.mu.Lock()
+	var expired []*uploadSession
+	owned := make(map[string]bool, len(u.sessions))
+	for _, sess := range u.sessions {
+		owned[sess.path] = true
+		if !sess.mu.TryLock() {
+			continue
+		}
+		sess.state.Lock()
+		expires := sess.expires
+		sess.state.Unlock()
+		if now.After(expires) {
+			expired = append(expired, sess)
+		} else {
+			sess.mu.Unlock()
+		}
+	}
+	u.mu.Unlock()
+
+	for _, sess := range expired {
+		slog.Info("discarding abandoned upload",
+			"upload_id", sess.id,
+			"tenant_id", sess.tenantID,
+			"filename", sess.metadata.Filename,
+			"received_bytes", sess.received,
+		)
+		u.remove(sess)
+		sess.mu.Unlock()
+	}
+
+	// Files from an earlier process
+	paths, _ := filepath.Glob(filepath.Join(u.dir, uploadPartPattern))
+	for _, path := range paths {
+		if owned[path] {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && now.Sub(info.ModTime()) > u.ttl {
+			os.Remove(path)
+		}
+	}
+}
+
+// RunUploadCleanup removes abandoned resumable uploads until ctx is done.
+func (s *FileService) RunUploadCleanup(ctx context.Context) {
+	ticker := time.NewTicker(uploadCleanupInterval)
+	defer ticker.Stop()
+
+	s.uploads.cleanup(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.uploads.cleanup(now)
+		}
+	}
+}
+
+// maxResumableFileBytes returns the largest resumable upload the tenant
+// may make.
+func maxResumableFileBytes(ctx context.Context) int64 {
+	limits := uploadLimits(ctx)
+	if limits.MaxFileBytes > 0 && limits.MaxFileBytes < maxResumableUploadBytes {
+		return limits.MaxFileBytes
+	}
+	return maxResumableUploadBytes
+}
+
+// InitUpload starts a resumable upload. The declared size is required so
+// size and storage limits are checked before any data is sent.
+func (s *FileService) InitUpload(ctx context.Context, req *pb.InitUploadRequest) (*pb.UploadSession, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+
+	if s.rateLimiter != nil {
+		if client := auth.ClientFromContext(ctx); client != nil {
+			if err := s.rateLimiter.Allow(ctx, client); err != nil {
+				return nil, status.Error(codes.ResourceExhausted, "file upload rate limit exceeded")
+			}
+		}
+	}
+
+	metadata := req.GetMetadata()
+	switch {
+	case metadata == nil:
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	case metadata.StoreId == "":
+		return nil, status.Error(codes.InvalidArgument, "store_id is required")
+	case metadata.Filename == "":
+		return nil, status.Error(codes.InvalidArgument, "filename is required")
+	case metadata.Size <= 0:
+		return nil, status.Error(codes.InvalidArgument, "size is required for resumable uploads")
+	}
+	if _, err := uploadACL(metadata); err != nil {
+		return nil, err
+	}
+
+	if maxBytes := maxResumableFileBytes(ctx); metadata.Size > maxBytes {
+		return nil, &QuotaError{Limit: QuotaFileBytes, Max: maxBytes, Requested: metadata.Size}
+	}
+	if err := s.checkStoreQuota(ctx, metadata.StoreId, metadata.Size); err != nil {
+		return nil, err
+	}
+
+	sess, err := s.uploads.create(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+	accesslog.Annotate(ctx,
+		"upload_id", sess.id,
+		"store_id", metadata.StoreId,
+		"filename", metadata.Filename,
+		"provider", metadata.Provider.String(),
+	)
+	slog.InfoContext(ctx, "resumable upload started",
+		"upload_id", sess.id,
+		"tenant_id", sess.tenantID,
+		"store_id", metadata.StoreId,
+		"filename", metadata.Filename,
+		"size", metadata.Size,
+	)
+	return sess.proto(), nil
+}
+
+// UploadChunk writes data at offset. Offsets up to the bytes already
+// received are accepted, so a chunk whose response was lost can be resent;
+// data the server already has is skipped.
+func (s *FileService) UploadChunk(ctx context.Context, req *pb.UploadChunkRequest) (*pb.UploadSession, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+
+	sess, err := s.uploads.acquire(ctx, req.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.mu.Unlock()
+	if sess.isCompleted() {
+		return nil, status.Error(codes.FailedPrecondition, "upload already completed")
+	}
+
+	end := req.Offset + int64(len(req.Data))
+	switch {
+	case req.Offset < 0:
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative")
+	case req.Offset > sess.received:
+		return nil, status.Errorf(codes.FailedPrecondition, "offset %d is past the %d bytes received; resume from received_bytes", req.Offset, sess.received)
+	case end > sess.metadata.Size:
+		return nil, status.Errorf(codes.InvalidArgument, "chunk ends at %d, past the declared size %d", end, sess.metadata.Size)
+	}
+
+	if end > sess.received {
+		f, err := os.OpenFile(sess.path, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to open upload temp file")
+		}
+		_, err = f.WriteAt(req.Data[sess.received-req.Offset:], sess.received)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("write to temp file: %w", err)
+		}
+		sess.state.Lock()
+		sess.received = end
+		sess.state.Unlock()
+	}
+	s.uploads.touch(sess)
+	return sess.proto(), nil
+}
+
+// GetUploadStatus reports a resumable upload's progress, including its
+// processing progress once CompleteUpload has started.
+func (s *FileService) GetUploadStatus(ctx context.Context, req *pb.GetUploadStatusRequest) (*pb.UploadSession, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+
+	// Not locked, so the status is available while CompleteUpload runs
+	sess, err := s.uploads.lookup(ctx, req.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	return sess.proto(), nil
+}
+
+// CompleteUpload adds a fully received upload to its store. Once stored,
+// the upload's data is deleted but its result is kept until the session
+// expires, so the call can be repeated; if the provider rejects the file,
+// the data is kept so completion can be retried.
+func (s *FileService) CompleteUpload(ctx context.Context, req *pb.CompleteUploadRequest) (*pb.UploadFileResponse, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uploadTimeout)
+		defer cancel()
+	}
+
+	if err := auth.RequirePermission(ctx, auth.PermissionFiles); err != nil {
+		return nil, err
+	}
+
+	sess, err := s.uploads.acquire(ctx, req.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.mu.Unlock()
+
+	// A retry after a lost response gets the stored result
+	if sess.isCompleted() {
+		return sess.proto().Progress.GetResult(), nil
+	}
+	if sess.received != sess.metadata.Size {
+		return nil, status.Errorf(codes.FailedPrecondition, "upload incomplete: %d of %d bytes received", sess.received, sess.metadata.Size)
+	}
+
+	accesslog.Annotate(ctx,
+		"upload_id", sess.id,
+		"store_id", sess.metadata.StoreId,
+		"filename", sess.metadata.Filename,
+		"provider", sess.metadata.Provider.String(),
+	)
+
+	f, err := os.Open(sess.path)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to open upload temp file")
+	}
+	defer f.Close()
+
+	if req.Sha256 != "" {
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return nil, fmt.Errorf("read temp file: %w", err)
+		}
+		if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, req.Sha256) {
+			// The data is corrupt; the client must start over
+			s.uploads.remove(sess)
+			return nil, status.Errorf(codes.DataLoss, "sha256 mismatch: received data hashes to %s; upload discarded", sum)
+		}
+	}
+
+	// Work on a copy so a failed attempt leaves the session's metadata as sent
+	metadata := proto.Clone(sess.metadata).(*pb.UploadFileMetadata)
+	progress := &uploadProgress{notify: sess.setProgress}
+	resp, err := s.storeUpload(ctx, metadata, f, sess.received, progress)
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			// The file itself was rejected; retrying cannot succeed
+			s.uploads.remove(sess)
+		}
+		return nil, err
+	}
+	if resp.Status == "failed" {
+		s.uploads.touch(sess)
+		return resp, nil
+	}
+
+	s.uploads.complete(sess)
+	slog.InfoContext(ctx, "resumable upload completed",
+		"upload_id", sess.id,
+		"tenant_id", sess.tenantID,
+		"store_id", resp.StoreId,
+		"file_id", resp.FileId,
+	)
+	return resp, nil
+}
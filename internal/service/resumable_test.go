@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newResumableFileService(t *testing.T, opts ...FileServiceOption) (*FileService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	opts = append([]FileServiceOption{WithUploadSessions(dir, time.Hour)}, opts...)
+	return NewFileService(createRAGServiceWithMocks(testutil.NewMockStore(), nil, nil), nil, opts...), dir
+}
+
+func initUpload(t *testing.T, svc *FileService, ctx context.Context, size int64) *pb.UploadSession {
+	t.Helper()
+	sess, err := svc.InitUpload(ctx, &pb.InitUploadRequest{
+		Metadata: &pb.UploadFileMetadata{StoreId: "docs", Filename: "doc.txt", Size: size},
+	})
+	if err != nil {
+		t.Fatalf("InitUpload failed: %v", err)
+	}
+	return sess
+}
+
+func TestResumableUpload(t *testing.T) {
+	registry := newFakeStoreRegistry()
+	svc, dir := newResumableFileService(t, WithStoreRegistry(registry))
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{})
+	content := []byte("hello resumable world")
+
+	sess := initUpload(t, svc, ctx, int64(len(content)))
+	if sess.UploadId == "" || sess.ReceivedBytes != 0 || sess.ExpiresAt == "" {
+		t.Fatalf("unexpected session: %+v", sess)
+	}
+
+	chunks := []struct {
+		offset int64
+		end    int64
+		want   int64
+	}{
+		{0, 8, 8},
+		{0, 8, 8},   // Retried chunk
+		{5, 14, 14}, // Overlaps received data
+		{14, 21, 21},
+	}
+	for _, c := range chunks {
+		got, err := svc.UploadChunk(ctx, &pb.UploadChunkRequest{UploadId: sess.UploadId, Offset: c.offset, Data: content[c.offset:c.end]})
+		if err != nil {
+			t.Fatalf("UploadChunk(%d) failed: %v", c.offset, err)
+		}
+		if got.ReceivedBytes != c.want {
+			t.Fatalf("UploadChunk(%d): received %d, want %d", c.offset, got.ReceivedBytes, c.want)
+		}
+	}
+
+	progress, err := svc.GetUploadStatus(ctx, &pb.GetUploadStatusRequest{UploadId: sess.UploadId})
+	if err != nil || progress.ReceivedBytes != int64(len(content)) {
+		t.Fatalf("GetUploadStatus = %+v, %v", progress, err)
+	}
+
+	sum := sha256.Sum256(content)
+	resp, err := svc.CompleteUpload(ctx, &pb.CompleteUploadRequest{UploadId: sess.UploadId, Sha256: hex.EncodeToString(sum[:])})
+	if err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+	if resp.Status != "ready" || resp.StoreId != "docs" || resp.FileId == "" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if u := registry.usage["tenant1/docs"]; u.FileCount != 1 || u.TotalBytes != int64(len(content)) {
+		t.Errorf("upload not recorded: %+v", u)
+	}
+
+	// The result stays available, and completion can be repeated
+	progress, err = svc.GetUploadStatus(ctx, &pb.GetUploadStatusRequest{UploadId: sess.UploadId})
+	if err != nil || progress.Progress.GetStage() != UploadStageReady || progress.Progress.GetResult().GetFileId() != resp.FileId {
+		t.Errorf("GetUploadStatus after completion = %+v, %v", progress, err)
+	}
+	again, err := svc.CompleteUpload(ctx, &pb.CompleteUploadRequest{UploadId: sess.UploadId})
+	if err != nil || again.FileId != resp.FileId {
+		t.Errorf("repeated CompleteUpload = %+v, %v", again, err)
+	}
+	if u := registry.usage["tenant1/docs"]; u.FileCount != 1 {
+		t.Errorf("repeated completion stored the file again: %+v", u)
+	}
+	if _, err := svc.UploadChunk(ctx, &pb.UploadChunkRequest{UploadId: sess.UploadId, Data: []byte("x")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("chunk after completion: expected FailedPrecondition, got %v", err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("temp files left behind: %v", files)
+	}
+}
+
+func TestResumableUpload_Errors(t *testing.T) {
+	svc, _ := newResumableFileService(t)
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{})
+	sess := initUpload(t, svc, ctx, 10)
+
+	if _, err := svc.UploadChunk(ctx, &pb.UploadChunkRequest{UploadId: sess.UploadId, Offset: 4, Data: []byte("abc")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("gap: expected FailedPrecondition, got %v", err)
+	}
+	if _, err := svc.UploadChunk(ctx, &pb.UploadChunkRequest{UploadId: sess.UploadId, Data: []byte("12345678901")}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("past size: expected InvalidArgument, got %v", err)
+	}
+	if _, err := svc.CompleteUpload(ctx, &pb.CompleteUploadRequest{UploadId: sess.UploadId}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("incomplete: expected FailedPrecondition, got %v", err)
+	}
+
+	other := context.WithValue(ctxWithFilePermission("other-client"), auth.TenantContextKey, &tenant.TenantConfig{TenantID: "tenant1"})
+	if _, err := svc.GetUploadStatus(other, &pb.GetUploadStatusRequest{UploadId: sess.UploadId}); status.Code(err) != codes.NotFound {
+		t.Errorf("other client: expected NotFound, got %v", err)
+	}
+	if _, err := svc.GetUploadStatus(ctx, &pb.GetUploadStatusRequest{UploadId: "upload_missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown id: expected NotFound, got %v", err)
+	}
+
+	// A corrupt upload is discarded
+	if _, err := svc.UploadChunk(ctx, &pb.UploadChunkRequest{UploadId: sess.UploadId, Data: []byte("0123456789")}); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+	if _, err := svc.CompleteUpload(ctx, &pb.CompleteUploadRequest{UploadId: sess.UploadId, Sha256: "00"}); status.Code(err) != codes.DataLoss {
+		t.Errorf("checksum: expected DataLoss, got %v", err)
+	}
+	if _, err := svc.GetUploadStatus(ctx, &pb.GetUploadStatusRequest{UploadId: sess.UploadId}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected corrupt upload to be discarded, got %v", err)
+	}
+}
+
+func TestInitUpload_Limits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits tenant.UploadLimits
+		usage  map[string]db.StoreUsage
+		size   int64
+		code   codes.Code
+	}{
+		{"size required", tenant.UploadLimits{}, nil, 0, codes.InvalidArgument},
+		{"larger than stream limit", tenant.UploadLimits{}, nil, maxUploadBytes + 1, codes.OK},
+		{"over server maximum", tenant.UploadLimits{}, nil, maxResumableUploadBytes + 1, codes.ResourceExhausted},
+		{"over tenant maximum", tenant.UploadLimits{MaxFileBytes: 100}, nil, 101, codes.ResourceExhausted},
+		{"store full", tenant.UploadLimits{MaxFilesPerStore: 1}, map[string]db.StoreUsage{"tenant1/docs": {FileCount: 1}}, 10, codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newFakeStoreRegistry()
+			for k, v := range tt.usage {
+				registry.usage[k] = v
+			}
+			svc, _ := newResumableFileService(t, WithStoreRegistry(registry))
+			_, err := svc.InitUpload(ctxWithUploadLimits(tt.limits), &pb.InitUploadRequest{
+				Metadata: &pb.UploadFileMetadata{StoreId: "docs", Filename: "doc.txt", Size: tt.size},
+			})
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestInitUpload_SessionLimit(t *testing.T) {
+	svc, _ := newResumableFileService(t)
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{})
+	for range maxUploadSessionsPerTenant {
+		initUpload(t, svc, ctx, 10)
+	}
+	_, err := svc.InitUpload(ctx, &pb.InitUploadRequest{
+		Metadata: &pb.UploadFileMetadata{StoreId: "docs", Filename: "doc.txt", Size: 10},
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+}
+
+func TestUploadSessions_Cleanup(t *testing.T) {
+	svc, dir := newResumableFileService(t)
+	ctx := ctxWithUploadLimits(tenant.UploadLimits{})
+	sess := initUpload(t, svc, ctx, 10)
+
+	// A file left by an earlier process
+	orphan := filepath.Join(dir, "upload-orphan.part")
+	if err := os.WriteFile(orphan, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.uploads.cleanup(time.Now())
+	if _, err := svc.GetUploadStatus(ctx, &pb.GetUploadStatusRequest{UploadId: sess.UploadId}); err != nil {
+		t.Fatalf("live upload removed: %v", err)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Fatalf("recent orphan removed: %v", err)
+	}
+
+	svc.uploads.cleanup(time.Now().Add(2 * time.Hour))
+	if _, err := svc.GetUploadStatus(ctx, &pb.GetUploadStatusRequest{UploadId: sess.UploadId}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected expired upload to be removed, got %v", err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("temp files left behind: %v", files)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/rag"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+const condenseInstructions = `Rewrite the user's latest message as a standalone search query for a
+document search. Resolve pronouns and references to earlier messages so the
+query can be understood without the conversation.
+Reply with the query only, without quotes or explanation.`
+
+// maxCondenseMessageChars bounds each earlier message sent to the condense
+// model, since long replies add cost without helping the rewrite.
+const maxCondenseMessageChars = 2000
+
+// retrievalConfig returns the tenant's retrieval settings with the request's
+// query mode applied.
+func retrievalConfig(ctx context.Context, req *pb.GenerateReplyRequest) tenant.RetrievalConfig {
+	var cfg tenant.RetrievalConfig
+	if tenantCfg := auth.TenantFromContext(ctx); tenantCfg != nil {
+		cfg = tenantCfg.Retrieval
+	}
+	if req.RetrievalQueryMode != "" {
+		cfg.QueryMode = req.RetrievalQueryMode
+	}
+	if cfg.QueryMode == "" {
+		cfg.QueryMode = tenant.RetrievalQueryLatest
+	}
+	return cfg
+}
+
+// ragPackOptions returns the token budget for the request's RAG context: the
+// tenant's, lowered by the request's rag_context_tokens.
+func ragPackOptions(ctx context.Context, req *pb.GenerateReplyRequest) rag.PackOptions {
+	cfg := retrievalConfig(ctx, req)
+	opts := rag.PackOptions{MaxTokens: cfg.ContextBudget(), MaxSourceTokens: cfg.SourceTokens}
+	if req.RagContextTokens > 0 {
+		opts.MaxTokens = min(opts.MaxTokens, int(req.RagContextTokens))
+	}
+	return opts
+}
+
+// retrievalQuery returns the query internal RAG retrieval embeds for req.
+// Without history, or in the latest mode, it is the user input. The recent
+// mode prefixes the user's recent messages; the condense mode has a model
+// rewrite the recent conversation into a standalone question, falling back
+// to the recent query if that fails. The condense call is charged to the
+// tenant's spend.
+func (s *ChatService) retrievalQuery(ctx context.Context, req *pb.GenerateReplyRequest, selected provider.Provider, requestID string) string {
+	cfg := retrievalConfig(ctx, req)
+	history := req.ConversationHistory
+	if n := len(history) - cfg.Turns(); n > 0 {
+		history = history[n:]
+	}
+	if cfg.QueryMode == tenant.RetrievalQueryLatest || len(history) == 0 {
+		return req.UserInput
+	}
+	accesslog.Annotate(ctx, "rag_query_mode", cfg.QueryMode)
+
+	if cfg.QueryMode == tenant.RetrievalQueryCondense {
+		query, err := s.condenseQuery(ctx, req, selected, cfg, history, requestID)
+		if err == nil {
+			return query
+		}
+		slog.WarnContext(ctx, "condensing retrieval query failed, using recent messages",
+			"error", err,
+			"request_id", requestID,
+		)
+	}
+	return recentQuery(req.UserInput, history)
+}
+
+// recentQuery joins the user messages in history and userInput, one per line.
+func recentQuery(userInput string, history []*pb.Message) string {
+	var lines []string
+	for _, m := range history {
+		if m.Role == "user" && strings.TrimSpace(m.Content) != "" {
+			lines = append(lines, strings.TrimSpace(m.Content))
+		}
+	}
+	return strings.Join(append(lines, userInput), "\n")
+}
+
+// condenseQuery asks the configured model, or the selected provider's, to
+// rewrite the conversation's latest message as a standalone query.
+func (s *ChatService) condenseQuery(ctx context.Context, req *pb.GenerateReplyRequest, selected provider.Provider, cfg tenant.RetrievalConfig, history []*pb.Message, requestID string) (string, error) {
+	p := selected
+	if cfg.Provider != "" {
+		p = s.providerByName(cfg.Provider)
+		if p == nil {
+			return "", fmt.Errorf("condense provider %q is not available", cfg.Provider)
+		}
+	}
+	name := p.Name()
+
+	var input strings.Builder
+	input.WriteString("Conversation:\n")
+	for _, m := range history {
+		fmt.Fprintf(&input, "%s: %s\n", m.Role, truncateString(strings.TrimSpace(m.Content), maxCondenseMessageChars))
+	}
+	fmt.Fprintf(&input, "\nLatest message:\n%s\n", req.UserInput)
+
+	params := provider.GenerateParams{
+		Instructions:  condenseInstructions,
+		UserInput:     input.String(),
+		OverrideModel: cfg.Model,
+		Config:        s.buildProviderConfig(ctx, req, name),
+		RequestID:     requestID,
+		ClientID:      req.ClientId,
+	}
+	out, err := p.GenerateReply(s.observeHeadroom(ctx, name), params)
+	s.reportProviderCall(ctx, name, params, out, err)
+	if err != nil {
+		return "", err
+	}
+	s.recordSpend(ctx, estimateCost(name, out.Model, out.Usage, out.GroundingQueries).Total())
+
+	query := strings.TrimSpace(out.Text)
+	if query == "" {
+		return "", errors.New("condense model returned an empty query")
+	}
+	return query, nil
+}
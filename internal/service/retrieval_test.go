@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/rag"
+	"github.com/ai8future/airborne/internal/rag/testutil"
+	"github.com/ai8future/airborne/internal/rag/vectorstore"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newQueryRAGService returns a RAG service with a test-tenant store vs_docs
+// and the embedder that records its queries.
+func newQueryRAGService() (*rag.Service, *testutil.MockEmbedder) {
+	store := testutil.NewMockStore()
+	store.CreateCollection(context.Background(), "test-tenant_vs_docs", 768)
+	store.Upsert(context.Background(), "test-tenant_vs_docs", []vectorstore.Point{{
+		ID:      "chunk1",
+		Vector:  make([]float32, 768),
+		Payload: map[string]any{"text": "The Pro plan costs $20 a month.", "filename": "pricing.pdf"},
+	}})
+	emb := testutil.NewMockEmbedder(768)
+	return rag.NewService(emb, store, testutil.NewMockExtractor(), rag.DefaultServiceOptions()), emb
+}
+
+func followUpRequest(mode string) *pb.GenerateReplyRequest {
+	return &pb.GenerateReplyRequest{
+		UserInput:         "How much does it cost?",
+		PreferredProvider: pb.Provider_PROVIDER_GEMINI,
+		EnableFileSearch:  true,
+		FileStoreId:       "vs_docs",
+		ConversationHistory: []*pb.Message{
+			{Role: "user", Content: "Tell me about the Pro plan."},
+			{Role: "assistant", Content: "The Pro plan adds team workspaces."},
+		},
+		RetrievalQueryMode: mode,
+	}
+}
+
+func TestRetrievalQuery_Modes(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"", "How much does it cost?"},
+		{tenant.RetrievalQueryLatest, "How much does it cost?"},
+		{tenant.RetrievalQueryRecent, "Tell me about the Pro plan.\nHow much does it cost?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			ragSvc, emb := newQueryRAGService()
+			gemini := newMockProvider("gemini")
+			svc := createChatServiceWithMocks(newMockProvider("openai"), gemini, newMockProvider("anthropic"), ragSvc)
+			ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("gemini"))
+
+			if _, err := svc.GenerateReply(ctx, followUpRequest(tt.mode)); err != nil {
+				t.Fatalf("GenerateReply: %v", err)
+			}
+			if len(emb.EmbedCalls) != 1 || emb.EmbedCalls[0] != tt.want {
+				t.Errorf("embedded queries = %q, want [%q]", emb.EmbedCalls, tt.want)
+			}
+			if len(gemini.generateCalls) != 1 {
+				t.Errorf("expected only the reply call, got %d calls", len(gemini.generateCalls))
+			}
+		})
+	}
+}
+
+func TestRetrievalQuery_Condense(t *testing.T) {
+	ragSvc, emb := newQueryRAGService()
+	gemini := newMockProvider("gemini")
+	gemini.generateResult.Text = "  Pro plan price  "
+	svc := createChatServiceWithMocks(newMockProvider("openai"), gemini, newMockProvider("anthropic"), ragSvc)
+	cfg := createTestTenantConfig("gemini")
+	cfg.Retrieval = tenant.RetrievalConfig{QueryMode: tenant.RetrievalQueryCondense}
+	ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+
+	if _, err := svc.GenerateReply(ctx, followUpRequest("")); err != nil {
+		t.Fatalf("GenerateReply: %v", err)
+	}
+	if len(gemini.generateCalls) != 2 {
+		t.Fatalf("expected a condense call and the reply call, got %d calls", len(gemini.generateCalls))
+	}
+	condense := gemini.generateCalls[0]
+	if condense.Instructions != condenseInstructions {
+		t.Errorf("expected the condense instructions, got %q", condense.Instructions)
+	}
+	for _, want := range []string{"user: Tell me about the Pro plan.", "assistant: The Pro plan adds team workspaces.", "How much does it cost?"} {
+		if !strings.Contains(condense.UserInput, want) {
+			t.Errorf("condense input %q missing %q", condense.UserInput, want)
+		}
+	}
+	if len(emb.EmbedCalls) != 1 || emb.EmbedCalls[0] != "Pro plan price" {
+		t.Errorf("embedded queries = %q, want the condensed query", emb.EmbedCalls)
+	}
+	if !strings.Contains(gemini.generateCalls[1].Instructions, "The Pro plan costs $20 a month.") {
+		t.Error("expected the reply to carry the retrieved context")
+	}
+}
+
+func TestRetrievalQuery_CondenseFailureUsesRecent(t *testing.T) {
+	ragSvc, emb := newQueryRAGService()
+	gemini := newMockProvider("gemini")
+	gemini.generateErr = errors.New("503 Service Unavailable")
+	svc := createChatServiceWithMocks(newMockProvider("openai"), gemini, newMockProvider("anthropic"), ragSvc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("gemini"))
+
+	_, _ = svc.GenerateReply(ctx, followUpRequest(tenant.RetrievalQueryCondense))
+
+	want := "Tell me about the Pro plan.\nHow much does it cost?"
+	if len(emb.EmbedCalls) == 0 || emb.EmbedCalls[0] != want {
+		t.Errorf("embedded queries = %q, want the recent query %q", emb.EmbedCalls, want)
+	}
+}
+
+func TestRetrievalQuery_HistoryTurns(t *testing.T) {
+	history := []*pb.Message{
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: "reply"},
+		{Role: "user", Content: "second"},
+	}
+	req := &pb.GenerateReplyRequest{UserInput: "third", ConversationHistory: history}
+	cfg := createTestTenantConfig("gemini")
+	cfg.Retrieval = tenant.RetrievalConfig{QueryMode: tenant.RetrievalQueryRecent, HistoryTurns: 2}
+	ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+
+	if got := svc.retrievalQuery(ctx, req, svc.geminiProvider, "req-1"); got != "second\nthird" {
+		t.Errorf("retrievalQuery = %q, want %q", got, "second\nthird")
+	}
+}
+
+func TestGenerateReply_InvalidRetrievalQueryMode(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("gemini"))
+
+	_, err := svc.GenerateReply(ctx, followUpRequest("everything"))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestRAGPackOptions(t *testing.T) {
+	cfg := createTestTenantConfig("gemini")
+	cfg.Retrieval = tenant.RetrievalConfig{ContextTokens: 2000, SourceTokens: 800}
+	ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+
+	tests := []struct {
+		requested int32
+		want      int
+	}{
+		{0, 2000},
+		{500, 500},
+		{5000, 2000}, // Requests cannot raise the tenant's budget
+	}
+	for _, tt := range tests {
+		opts := ragPackOptions(ctx, &pb.GenerateReplyRequest{RagContextTokens: tt.requested})
+		if opts.MaxTokens != tt.want || opts.MaxSourceTokens != 800 {
+			t.Errorf("rag_context_tokens %d: got %+v, want MaxTokens %d", tt.requested, opts, tt.want)
+		}
+	}
+
+	opts := ragPackOptions(ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("gemini")), &pb.GenerateReplyRequest{})
+	if opts.MaxTokens != tenant.DefaultRetrievalContextTokens {
+		t.Errorf("expected the default budget, got %d", opts.MaxTokens)
+	}
+}
+
+func TestGenerateReply_RAGContextBudget(t *testing.T) {
+	ragSvc, _ := newQueryRAGService()
+	gemini := newMockProvider("gemini")
+	svc := createChatServiceWithMocks(newMockProvider("openai"), gemini, newMockProvider("anthropic"), ragSvc)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("gemini"))
+
+	req := followUpRequest("")
+	req.RagContextTokens = 2 // Too small for the stored chunk
+	if _, err := svc.GenerateReply(ctx, req); err != nil {
+		t.Fatalf("GenerateReply: %v", err)
+	}
+	if strings.Contains(gemini.generateCalls[0].Instructions, "<document_context>") {
+		t.Error("expected no RAG context within the budget")
+	}
+
+	req.RagContextTokens = -1
+	if _, err := svc.GenerateReply(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a negative budget, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// effectiveSafety combines the tenant's safety thresholds with the ones on
+// the request. The request can only tighten a category; it never loosens a
+// threshold the tenant configured.
+func effectiveSafety(tenantCfg *tenant.TenantConfig, requested *pb.SafetySettings) *provider.SafetySettings {
+	var settings provider.SafetySettings
+	if tenantCfg != nil {
+		// The loader rejects invalid thresholds, so the error is unreachable here.
+		settings, _ = tenantCfg.Safety.Settings()
+	}
+	settings = settings.Tighten(safetySettingsFromProto(requested))
+	if settings.IsZero() {
+		return nil
+	}
+	return &settings
+}
+
+func safetySettingsFromProto(s *pb.SafetySettings) provider.SafetySettings {
+	if s == nil {
+		return provider.SafetySettings{}
+	}
+	return provider.SafetySettings{
+		Harassment:       safetyThresholdFromProto(s.Harassment),
+		HateSpeech:       safetyThresholdFromProto(s.HateSpeech),
+		SexuallyExplicit: safetyThresholdFromProto(s.SexuallyExplicit),
+		DangerousContent: safetyThresholdFromProto(s.DangerousContent),
+	}
+}
+
+func safetyThresholdFromProto(t pb.SafetyThreshold) provider.SafetyThreshold {
+	switch t {
+	case pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_NONE:
+		return provider.SafetyBlockNone
+	case pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_ONLY_HIGH:
+		return provider.SafetyBlockOnlyHigh
+	case pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_MEDIUM_AND_ABOVE:
+		return provider.SafetyBlockMediumAndAbove
+	case pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_LOW_AND_ABOVE:
+		return provider.SafetyBlockLowAndAbove
+	default:
+		return provider.SafetyUnspecified
+	}
+}
+
+func convertSafetyBlock(b *provider.SafetyBlock) *pb.SafetyBlock {
+	if b == nil {
+		return nil
+	}
+	return &pb.SafetyBlock{
+		Stage:    b.Stage,
+		Category: b.Category,
+		Reason:   b.Reason,
+	}
+}
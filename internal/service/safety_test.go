@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// blockingProvider reports every reply as blocked by safety filters.
+type blockingProvider struct {
+	*mockProvider
+}
+
+func (p *blockingProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	result, err := p.mockProvider.GenerateReply(ctx, params)
+	result.Text = ""
+	result.SafetyBlock = &provider.SafetyBlock{Stage: "prompt", Category: provider.HarmHarassment, Reason: "SAFETY"}
+	return result, err
+}
+
+func TestEffectiveSafety_RequestOnlyTightens(t *testing.T) {
+	cfg := createTestTenantConfig("openai")
+	cfg.Safety = tenant.SafetyConfig{Harassment: "block_medium_and_above", HateSpeech: "block_only_high"}
+
+	got := effectiveSafety(cfg, &pb.SafetySettings{
+		Harassment:       pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_NONE,
+		HateSpeech:       pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_LOW_AND_ABOVE,
+		DangerousContent: pb.SafetyThreshold_SAFETY_THRESHOLD_BLOCK_ONLY_HIGH,
+	})
+	want := provider.SafetySettings{
+		Harassment:       provider.SafetyBlockMediumAndAbove,
+		HateSpeech:       provider.SafetyBlockLowAndAbove,
+		DangerousContent: provider.SafetyBlockOnlyHigh,
+	}
+	if got == nil || *got != want {
+		t.Errorf("effectiveSafety = %+v, want %+v", got, want)
+	}
+
+	if got := effectiveSafety(createTestTenantConfig("openai"), nil); got != nil {
+		t.Errorf("effectiveSafety with nothing configured = %+v, want nil", got)
+	}
+}
+
+func TestGenerateReply_ReportsSafetyBlock(t *testing.T) {
+	openai := &blockingProvider{mockProvider: newMockProvider("openai")}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+	cfg := validatingTenant(tenant.ValidationConfig{NonEmpty: true})
+	cfg.Safety = tenant.SafetyConfig{Harassment: "block_low_and_above"}
+	ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Hello", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.SafetyBlock == nil || resp.SafetyBlock.Stage != "prompt" || resp.SafetyBlock.Category != "harassment" {
+		t.Errorf("SafetyBlock = %+v, want prompt block on harassment", resp.SafetyBlock)
+	}
+	// A blocked reply is not regenerated by validation
+	if len(openai.generateCalls) != 1 {
+		t.Fatalf("provider called %d times, want 1", len(openai.generateCalls))
+	}
+	if safety := openai.generateCalls[0].Config.Safety; safety == nil || safety.Harassment != provider.SafetyBlockLowAndAbove {
+		t.Errorf("provider config safety = %+v, want tenant thresholds", safety)
+	}
+}
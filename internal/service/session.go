@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithSessionStore enables the session token RPCs.
+func WithSessionStore(sessions *auth.SessionStore) ChatServiceOption {
+	return func(s *ChatService) {
+		s.sessions = sessions
+	}
+}
+
+// CreateSessionToken mints a session token with the caller's API key, for a
+// front-end app to stream replies without holding the key.
+func (s *ChatService) CreateSessionToken(ctx context.Context, req *pb.CreateSessionTokenRequest) (*pb.CreateSessionTokenResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChatStream); err != nil {
+		return nil, err
+	}
+	if s.sessions == nil {
+		return nil, status.Error(codes.FailedPrecondition, "session tokens require auth_mode redis")
+	}
+	client := auth.ClientFromContext(ctx)
+	if client.KeyID == "" || client.Session != nil {
+		return nil, status.Error(codes.PermissionDenied, "session tokens must be minted with an API key")
+	}
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	if ttl < 0 || ttl > auth.MaxSessionTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl_seconds must be between 0 and %d", int(auth.MaxSessionTTL.Seconds()))
+	}
+	if req.RequestsPerMinute < 0 || req.RequestsPerDay < 0 {
+		return nil, status.Error(codes.InvalidArgument, "rate limits must not be negative")
+	}
+
+	token, session, err := s.sessions.Create(ctx, client, auth.CreateSessionParams{
+		TTL:               ttl,
+		RequestsPerMinute: int(req.RequestsPerMinute),
+		RequestsPerDay:    int(req.RequestsPerDay),
+	})
+	if errors.Is(err, auth.ErrKeyExpired) {
+		return nil, status.Error(codes.Unauthenticated, "API key expired")
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create session token", "error", err, "client_id", client.ClientID)
+		return nil, status.Error(codes.Internal, "failed to create session token")
+	}
+	slog.InfoContext(ctx, "session token created",
+		"client_id", client.ClientID,
+		"session_id", session.ID,
+		"expires_at", session.ExpiresAt,
+	)
+	return &pb.CreateSessionTokenResponse{
+		Token:     token,
+		SessionId: session.ID,
+		ExpiresAt: session.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// RevokeSessionToken revokes a session token minted by the caller's client,
+// or by any client for admins.
+func (s *ChatService) RevokeSessionToken(ctx context.Context, req *pb.RevokeSessionTokenRequest) (*pb.RevokeSessionTokenResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChatStream); err != nil {
+		return nil, err
+	}
+	if s.sessions == nil {
+		return nil, status.Error(codes.FailedPrecondition, "session tokens require auth_mode redis")
+	}
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	client := auth.ClientFromContext(ctx)
+	session, err := s.sessions.Get(ctx, req.SessionId)
+	if errors.Is(err, auth.ErrSessionNotFound) {
+		return &pb.RevokeSessionTokenResponse{}, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get session token", "error", err, "session_id", req.SessionId)
+		return nil, status.Error(codes.Internal, "failed to revoke session token")
+	}
+	// Sessions of other clients are reported as missing
+	if session.ClientID != client.ClientID && !client.HasPermission(auth.PermissionAdmin) {
+		return &pb.RevokeSessionTokenResponse{}, nil
+	}
+	if err := s.sessions.Revoke(ctx, session.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke session token", "error", err, "session_id", session.ID)
+		return nil, status.Error(codes.Internal, "failed to revoke session token")
+	}
+	slog.InfoContext(ctx, "session token revoked", "client_id", session.ClientID, "session_id", session.ID, "revoked_by", client.ClientID)
+	return &pb.RevokeSessionTokenResponse{Revoked: true}, nil
+}
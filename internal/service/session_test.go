@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func ctxWithStreamingKey(keyID, clientID string) context.Context {
+	return context.WithValue(context.Background(), auth.ClientContextKey, &auth.ClientKey{
+		KeyID:       keyID,
+		ClientID:    clientID,
+		Permissions: []auth.Permission{auth.PermissionChat, auth.PermissionChatStream},
+	})
+}
+
+func TestCreateSessionToken(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), nil, nil, nil)
+	ctx := ctxWithStreamingKey("key1", "web")
+
+	_, err := svc.CreateSessionToken(ctx, &pb.CreateSessionTokenRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("without store: got %v, want FailedPrecondition", err)
+	}
+
+	_, client := newTestRedis(t)
+	sessions := auth.NewSessionStore(client)
+	WithSessionStore(sessions)(svc)
+
+	resp, err := svc.CreateSessionToken(ctx, &pb.CreateSessionTokenRequest{TtlSeconds: 60, RequestsPerMinute: 10})
+	if err != nil {
+		t.Fatalf("CreateSessionToken failed: %v", err)
+	}
+	session, err := sessions.Validate(context.Background(), resp.Token)
+	if err != nil || session.ID != resp.SessionId || session.ClientID != "web" || session.RateLimits.RequestsPerMinute != 10 {
+		t.Errorf("Validate = %+v, %v", session, err)
+	}
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		req  *pb.CreateSessionTokenRequest
+		code codes.Code
+	}{
+		"ttl too long":      {ctx, &pb.CreateSessionTokenRequest{TtlSeconds: 7200}, codes.InvalidArgument},
+		"negative limit":    {ctx, &pb.CreateSessionTokenRequest{RequestsPerDay: -1}, codes.InvalidArgument},
+		"static auth":       {ctxWithStreamingKey("", "static"), &pb.CreateSessionTokenRequest{}, codes.PermissionDenied},
+		"without streaming": {ctxWithChatPermission("web"), &pb.CreateSessionTokenRequest{}, codes.PermissionDenied},
+	} {
+		if _, err := svc.CreateSessionToken(tc.ctx, tc.req); status.Code(err) != tc.code {
+			t.Errorf("%s: got %v, want %v", name, err, tc.code)
+		}
+	}
+}
+
+func TestRevokeSessionToken(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), nil, nil, nil)
+	_, client := newTestRedis(t)
+	sessions := auth.NewSessionStore(client)
+	WithSessionStore(sessions)(svc)
+	ctx := ctxWithStreamingKey("key1", "web")
+
+	created, err := svc.CreateSessionToken(ctx, &pb.CreateSessionTokenRequest{})
+	if err != nil {
+		t.Fatalf("CreateSessionToken failed: %v", err)
+	}
+
+	// Other clients cannot revoke it
+	resp, err := svc.RevokeSessionToken(ctxWithStreamingKey("key2", "other"), &pb.RevokeSessionTokenRequest{SessionId: created.SessionId})
+	if err != nil || resp.Revoked {
+		t.Errorf("other client: got %v, %v, want not revoked", resp, err)
+	}
+	resp, err = svc.RevokeSessionToken(ctx, &pb.RevokeSessionTokenRequest{SessionId: created.SessionId})
+	if err != nil || !resp.Revoked {
+		t.Fatalf("got %v, %v, want revoked", resp, err)
+	}
+	if _, err := sessions.Validate(context.Background(), created.Token); err == nil {
+		t.Error("revoked token still valid")
+	}
+	resp, err = svc.RevokeSessionToken(ctx, &pb.RevokeSessionTokenRequest{SessionId: created.SessionId})
+	if err != nil || resp.Revoked {
+		t.Errorf("second revoke: got %v, %v, want not revoked", resp, err)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+// SLO alert event types.
+const (
+	SLOAlertBreached  = "slo.breached"
+	SLOAlertRecovered = "slo.recovered"
+)
+
+// alertTimeout bounds a single alert delivery.
+const alertTimeout = 10 * time.Second
+
+var alertClient = &http.Client{Timeout: alertTimeout, Transport: egress.Audited(http.DefaultTransport)}
+
+// SLOAlert is the JSON body posted to a tenant's SLO alert webhook when an
+// objective goes into or out of breach.
+type SLOAlert struct {
+	Event       string    `json:"event"` // SLOAlertBreached or SLOAlertRecovered
+	TenantID    string    `json:"tenant_id"`
+	Objective   string    `json:"objective"` // "first_token" or "completion"
+	Provider    string    `json:"provider"`  // Provider of the request that changed the state
+	Percentile  float64   `json:"percentile"`
+	ThresholdMs int64     `json:"threshold_ms"`
+	CurrentMs   int64     `json:"current_ms"`
+	At          time.Time `json:"at"`
+}
+
+// observeLatency records a generation latency against the tenant's latency
+// objective, alerting when the objective goes into or out of breach.
+// Latencies are only tracked when metrics are enabled.
+func (s *ChatService) observeLatency(ctx context.Context, objective, providerName string, latency time.Duration) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if s.metrics == nil || tenantCfg == nil {
+		return
+	}
+	var target tenant.LatencyObjective
+	switch objective {
+	case metrics.SLOFirstToken:
+		target = tenantCfg.SLO.FirstToken
+	case metrics.SLOCompletion:
+		target = tenantCfg.SLO.Completion
+	}
+	if !target.Enabled() {
+		return
+	}
+
+	transition := s.metrics.ObserveSLO(metrics.SLOObservation{
+		TenantID:   tenantCfg.TenantID,
+		Objective:  objective,
+		Provider:   providerName,
+		Latency:    latency,
+		Percentile: target.EffectivePercentile(),
+		Threshold:  target.Threshold(),
+	})
+	if transition == nil {
+		return
+	}
+
+	alert := SLOAlert{
+		Event:       SLOAlertRecovered,
+		TenantID:    transition.TenantID,
+		Objective:   transition.Objective,
+		Provider:    transition.Provider,
+		Percentile:  transition.Percentile,
+		ThresholdMs: transition.ThresholdMs,
+		CurrentMs:   transition.CurrentMs,
+		At:          transition.At.UTC(),
+	}
+	logArgs := []any{"tenant_id", alert.TenantID, "objective", alert.Objective, "provider", alert.Provider,
+		"percentile", alert.Percentile, "threshold_ms", alert.ThresholdMs, "current_ms", alert.CurrentMs}
+	if transition.Breached {
+		alert.Event = SLOAlertBreached
+		slog.WarnContext(ctx, "latency objective breached", logArgs...)
+	} else {
+		slog.InfoContext(ctx, "latency objective recovered", logArgs...)
+	}
+
+	if url := tenantCfg.SLO.AlertWebhook; url != "" {
+		// Deliver off the request path; a slow alert endpoint must not add
+		// to the latency being alerted on
+		go func() {
+			if err := sendAlert(context.Background(), url, alert); err != nil {
+				slog.WarnContext(ctx, "failed to deliver slo alert", "tenant_id", alert.TenantID, "objective", alert.Objective, "error", err)
+			}
+		}()
+	}
+}
+
+// sendAlert POSTs alert as JSON to a tenant's alert webhook.
+func sendAlert(ctx context.Context, url string, alert any) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("encode alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := alertClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/tenant"
+)
+
+func TestObserveLatency_Alerts(t *testing.T) {
+	alerts := make(chan SLOAlert, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert SLOAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("decode alert: %v", err)
+		}
+		alerts <- alert
+	}))
+	defer srv.Close()
+
+	svc := createChatServiceWithMocks(newMockProvider("openai"), nil, nil, nil)
+	registry := metrics.NewRegistry()
+	WithMetrics(registry)(svc)
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.SLO = tenant.SLOConfig{
+		FirstToken:   tenant.LatencyObjective{ThresholdMs: 2000},
+		AlertWebhook: srv.URL,
+	}
+	ctx := ctxWithChatPermissionAndTenant("client", tenantCfg)
+
+	// Completion has no objective, so is not tracked
+	svc.observeLatency(ctx, metrics.SLOCompletion, "openai", time.Minute)
+	for range metrics.SLOMinSamples {
+		svc.observeLatency(ctx, metrics.SLOFirstToken, "openai", 3*time.Second)
+	}
+
+	select {
+	case alert := <-alerts:
+		if alert.Event != SLOAlertBreached || alert.TenantID != "test-tenant" || alert.Objective != metrics.SLOFirstToken ||
+			alert.Provider != "openai" || alert.Percentile != 95 || alert.ThresholdMs != 2000 || alert.CurrentMs != 3000 {
+			t.Errorf("unexpected alert: %+v", alert)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert delivered")
+	}
+
+	snap := registry.Snapshot()
+	if len(snap.SLOs) != 1 || !snap.SLOs[0].Breached || snap.SLOs[0].Count != int64(metrics.SLOMinSamples) {
+		t.Errorf("unexpected slo stats: %+v", snap.SLOs)
+	}
+}
+
+func TestGenerateReply_ObservesCompletion(t *testing.T) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), nil, nil, nil)
+	registry := metrics.NewRegistry()
+	WithMetrics(registry)(svc)
+	tenantCfg := createTestTenantConfig("openai")
+	tenantCfg.SLO.Completion = tenant.LatencyObjective{Percentile: 99, ThresholdMs: 20000}
+
+	_, err := svc.GenerateReply(ctxWithChatPermissionAndTenant("client", tenantCfg), &pb.GenerateReplyRequest{UserInput: "hi"})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+
+	snap := registry.Snapshot()
+	if len(snap.SLOs) != 1 {
+		t.Fatalf("expected 1 objective, got %+v", snap.SLOs)
+	}
+	if st := snap.SLOs[0]; st.Objective != metrics.SLOCompletion || st.Count != 1 || st.Percentile != 99 || st.Slow != 0 || st.Breached {
+		t.Errorf("unexpected slo stats: %+v", st)
+	}
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	sanitize "github.com/ai8future/airborne/internal/errors"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/rag"
+	"github.com/ai8future/airborne/internal/rag/chunker"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// summaryPartChars is the target size of each document part summarized
+	// in the map step.
+	summaryPartChars = 12000
+
+	// maxSummaryParts bounds the number of map calls for one document.
+	maxSummaryParts = 200
+
+	// summaryConcurrency is how many map calls run at once.
+	summaryConcurrency = 4
+
+	// summaryReduceBatch is how many part notes are combined per reduce call.
+	summaryReduceBatch = 12
+
+	// defaultSummarySections is used when the request does not set max_sections.
+	defaultSummarySections = 8
+
+	// summarySnippetChars is the length of the snippet returned for a cited part.
+	summarySnippetChars = 160
+)
+
+const summaryMapInstructions = `You are summarizing one part of a longer document.
+Write concise notes covering the key facts, arguments, figures and conclusions
+in this part. Do not add information that is not in the text.`
+
+const summaryCombineInstructions = `You are combining notes from consecutive parts of
+a long document. Merge them into shorter notes, keeping the most important
+points. Every point must keep the [Part N] markers of the parts it came from.`
+
+const summaryReduceInstructions = `You are writing the final summary of a long
+document from notes on its parts. Each note is labelled [Part N].
+Reply with a JSON object only, in this form:
+{"title": "...", "summary": "...", "sections": [{"heading": "...", "summary": "...", "parts": [1, 2]}]}
+"summary" is an overview of the whole document. "sections" follow the
+document's order, and "parts" lists the part numbers each section is based on.`
+
+// documentPart is a contiguous range of the document summarized in one map call.
+type documentPart struct {
+	text  string
+	start int // Character offsets in the extracted text
+	end   int
+}
+
+// summaryReply is the JSON the final reduce call returns.
+type summaryReply struct {
+	Title    string `json:"title"`
+	Summary  string `json:"summary"`
+	Sections []struct {
+		Heading string `json:"heading"`
+		Summary string `json:"summary"`
+		Parts   []int  `json:"parts"`
+	} `json:"sections"`
+}
+
+// summarizer runs the map and reduce calls of one summarization and totals
+// their usage and cost.
+type summarizer struct {
+	svc      *ChatService
+	provider provider.Provider
+	params   provider.GenerateParams
+
+	mu      sync.Mutex
+	usage   provider.Usage
+	costUSD float64
+	model   string
+}
+
+// generate runs one call with the given instructions and input.
+func (z *summarizer) generate(ctx context.Context, instructions, input string) (string, error) {
+	params := z.params
+	params.Instructions = instructions
+	params.UserInput = input
+
+	result, err := z.provider.GenerateReply(z.svc.observeHeadroom(ctx, z.provider.Name()), params)
+	z.svc.reportProviderCall(ctx, z.provider.Name(), params, result, err)
+	if err != nil {
+		return "", err
+	}
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if result.Usage != nil {
+		z.usage.InputTokens += result.Usage.InputTokens
+		z.usage.OutputTokens += result.Usage.OutputTokens
+		z.usage.TotalTokens += result.Usage.TotalTokens
+	}
+	z.costUSD += estimateCost(z.provider.Name(), result.Model, result.Usage, result.GroundingQueries).Total()
+	if result.Model != "" {
+		z.model = result.Model
+	}
+	return result.Text, nil
+}
+
+// mapParts summarizes each part, returning notes labelled with part numbers.
+func (z *summarizer) mapParts(ctx context.Context, parts []documentPart, focus string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	notes := make([]string, len(parts))
+	errs := make([]error, len(parts))
+	sem := make(chan struct{}, summaryConcurrency)
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				errs[i] = ctx.Err()
+				return
+			}
+
+			input := fmt.Sprintf("Part %d of %d:\n\n%s", i+1, len(parts), part.text)
+			text, err := z.generate(ctx, summaryMapInstructions+focus, input)
+			if err != nil {
+				errs[i] = err
+				cancel() // One failed part fails the summary
+				return
+			}
+			notes[i] = fmt.Sprintf("[Part %d]\n%s", i+1, strings.TrimSpace(text))
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return notes, nil
+}
+
+// combine merges notes in batches until they fit in one reduce call.
+func (z *summarizer) combine(ctx context.Context, notes []string, focus string) ([]string, error) {
+	for len(notes) > summaryReduceBatch {
+		var combined []string
+		for start := 0; start < len(notes); start += summaryReduceBatch {
+			batch := notes[start:min(start+summaryReduceBatch, len(notes))]
+			text, err := z.generate(ctx, summaryCombineInstructions+focus, strings.Join(batch, "\n\n"))
+			if err != nil {
+				return nil, err
+			}
+			combined = append(combined, strings.TrimSpace(text))
+		}
+		notes = combined
+	}
+	return notes, nil
+}
+
+// SummarizeDocument summarizes a long document. The document is split into
+// parts that are summarized independently (map), the notes are combined in
+// batches until they fit one call, and a final call (reduce) writes the
+// structured summary. Sections cite the document parts they are based on.
+func (s *ChatService) SummarizeDocument(ctx context.Context, req *pb.SummarizeDocumentRequest) (*pb.SummarizeDocumentResponse, error) {
+	if err := auth.RequirePermission(ctx, auth.PermissionChat); err != nil {
+		return nil, err
+	}
+
+	requestID, err := validation.ValidateOrGenerateRequestID(req.RequestId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(req.Instructions) > validation.MaxInstructionsBytes {
+		return nil, status.Error(codes.InvalidArgument, validation.ErrInstructionsTooLarge.Error())
+	}
+
+	parts, err := s.documentParts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "document contains no text")
+	}
+	if len(parts) > maxSummaryParts {
+		return nil, status.Errorf(codes.InvalidArgument, "document too large to summarize (%d parts, maximum %d)", len(parts), maxSummaryParts)
+	}
+
+	// Select the provider and model the same way GenerateReply does
+	genReq := &pb.GenerateReplyRequest{
+		PreferredProvider: req.PreferredProvider,
+		ModelOverride:     req.ModelOverride,
+	}
+	selected, err := s.selectProviderWithTenant(ctx, genReq, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid provider: %v", err)
+	}
+	if err := s.checkRequestedModel(ctx, genReq, selected.Name()); err != nil {
+		return nil, err
+	}
+
+	clientID := ""
+	if client := auth.ClientFromContext(ctx); client != nil {
+		clientID = client.ClientID
+	}
+	z := &summarizer{
+		svc:      s,
+		provider: selected,
+		params: provider.GenerateParams{
+			OverrideModel: req.ModelOverride,
+			Config:        s.buildProviderConfig(ctx, genReq, selected.Name()),
+			RequestID:     requestID,
+			ClientID:      clientID,
+		},
+	}
+
+	accesslog.Annotate(ctx,
+		"provider", selected.Name(),
+		"request_id", requestID,
+		"summary_parts", len(parts),
+	)
+
+	var focus string
+	if strings.TrimSpace(req.Instructions) != "" {
+		focus = "\n\nThe reader's focus: " + strings.TrimSpace(req.Instructions)
+	}
+	maxSections := int(req.MaxSections)
+	if maxSections <= 0 {
+		maxSections = defaultSummarySections
+	}
+
+	reply, err := s.summarizeParts(ctx, z, parts, focus, maxSections)
+	s.recordSpend(ctx, z.costUSD)
+	if err != nil {
+		slog.ErrorContext(ctx, "document summarization failed",
+			"provider", selected.Name(),
+			"parts", len(parts),
+			"error", err,
+			"request_id", requestID,
+		)
+		return nil, status.Error(codes.Internal, sanitize.SanitizeForClient(err))
+	}
+
+	resp := &pb.SummarizeDocumentResponse{
+		Title:            reply.Title,
+		Summary:          reply.Summary,
+		Provider:         mapProviderToProto(selected.Name()),
+		Model:            z.model,
+		Usage:            convertUsage(&z.usage),
+		EstimatedCostUsd: z.costUSD,
+		PartCount:        int32(len(parts)),
+	}
+	for _, sec := range reply.Sections {
+		section := &pb.SummarySection{Heading: sec.Heading, Summary: sec.Summary}
+		for _, n := range sec.Parts {
+			if n < 1 || n > len(parts) {
+				continue // Model cited a part that does not exist
+			}
+			part := parts[n-1]
+			section.Citations = append(section.Citations, &pb.DocumentSpan{
+				Part:      int32(n),
+				CharStart: int32(part.start),
+				CharEnd:   int32(part.end),
+				Snippet:   truncateString(strings.TrimSpace(part.text), summarySnippetChars),
+			})
+		}
+		resp.Sections = append(resp.Sections, section)
+	}
+	return resp, nil
+}
+
+// summarizeParts runs the map, combine and reduce steps.
+func (s *ChatService) summarizeParts(ctx context.Context, z *summarizer, parts []documentPart, focus string, maxSections int) (*summaryReply, error) {
+	notes, err := z.mapParts(ctx, parts, focus)
+	if err != nil {
+		return nil, fmt.Errorf("map: %w", err)
+	}
+	notes, err = z.combine(ctx, notes, focus)
+	if err != nil {
+		return nil, fmt.Errorf("combine: %w", err)
+	}
+
+	instructions := fmt.Sprintf("%s\nUse at most %d sections.%s", summaryReduceInstructions, maxSections, focus)
+	text, err := z.generate(ctx, instructions, strings.Join(notes, "\n\n"))
+	if err != nil {
+		return nil, fmt.Errorf("reduce: %w", err)
+	}
+
+	var reply summaryReply
+	if err := json.Unmarshal([]byte(validation.StripCodeFence(text)), &reply); err != nil {
+		// Keep the model's summary rather than failing the whole request
+		slog.WarnContext(ctx, "summary was not valid JSON, returning it without sections", "error", err)
+		return &summaryReply{Summary: strings.TrimSpace(text)}, nil
+	}
+	if len(reply.Sections) > maxSections {
+		reply.Sections = reply.Sections[:maxSections]
+	}
+	return &reply, nil
+}
+
+// documentParts loads the request's document and splits it into parts.
+func (s *ChatService) documentParts(ctx context.Context, req *pb.SummarizeDocumentRequest) ([]documentPart, error) {
+	switch src := req.Source.(type) {
+	case *pb.SummarizeDocumentRequest_Content:
+		text, err := s.documentText(ctx, src.Content, req.Filename, req.MimeType)
+		if err != nil {
+			return nil, err
+		}
+		chunks := chunker.ChunkText(text, chunker.Options{ChunkSize: summaryPartChars, Overlap: 200})
+		parts := make([]documentPart, len(chunks))
+		for i, c := range chunks {
+			parts[i] = documentPart{text: c.Text, start: c.Start, end: c.End}
+		}
+		return parts, nil
+
+	case *pb.SummarizeDocumentRequest_StoredFile:
+		if s.ragService == nil {
+			return nil, status.Error(codes.FailedPrecondition, "stored files require RAG to be enabled")
+		}
+		chunks, err := s.ragService.FileChunks(ctx, auth.TenantIDFromContext(ctx), src.StoredFile.GetStoreId(), src.StoredFile.GetFileId(), ragViewer(ctx))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if len(chunks) == 0 {
+			return nil, status.Error(codes.NotFound, "file not found in store")
+		}
+		return groupFileChunks(chunks), nil
+
+	default:
+		return nil, status.Error(codes.InvalidArgument, "content or stored_file is required")
+	}
+}
+
+// documentText extracts text from uploaded content. Without RAG only UTF-8
+// text documents can be summarized.
+func (s *ChatService) documentText(ctx context.Context, content []byte, filename, mimeType string) (string, error) {
+	if s.ragService != nil {
+		text, err := s.ragService.ExtractText(ctx, bytes.NewReader(content), filename, mimeType)
+		if err != nil {
+			slog.WarnContext(ctx, "document text extraction failed", "filename", filename, "error", err)
+			return "", status.Error(codes.InvalidArgument, "could not extract text from document")
+		}
+		return text, nil
+	}
+	if !utf8.Valid(content) {
+		return "", status.Error(codes.FailedPrecondition, "summarizing non-text documents requires RAG to be enabled")
+	}
+	return string(content), nil
+}
+
+// groupFileChunks merges consecutive stored chunks into parts of up to
+// summaryPartChars.
+func groupFileChunks(chunks []rag.FileChunk) []documentPart {
+	var parts []documentPart
+	var cur *documentPart
+	for _, c := range chunks {
+		if cur != nil && len(cur.text)+len(c.Text) > summaryPartChars {
+			parts = append(parts, *cur)
+			cur = nil
+		}
+		if cur == nil {
+			cur = &documentPart{text: c.Text, start: c.Start, end: c.End}
+			continue
+		}
+		cur.text += "\n" + c.Text
+		cur.end = c.End
+	}
+	if cur != nil {
+		parts = append(parts, *cur)
+	}
+	return parts
+}
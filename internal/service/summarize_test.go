@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// summaryProvider answers map and combine calls with notes and reduce calls
+// with reduceReply.
+type summaryProvider struct {
+	*mockProvider
+	reduceReply string
+
+	mu       sync.Mutex
+	maps     int
+	combines int
+	reduces  int
+}
+
+func (p *summaryProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := provider.GenerateResult{Model: "mock-model", Usage: &provider.Usage{InputTokens: 100, OutputTokens: 10, TotalTokens: 110}}
+	switch {
+	case strings.HasPrefix(params.Instructions, summaryMapInstructions):
+		p.maps++
+		result.Text = "notes on " + strings.SplitN(params.UserInput, ":", 2)[0]
+	case strings.HasPrefix(params.Instructions, summaryCombineInstructions):
+		p.combines++
+		result.Text = "[Part 1] combined notes"
+	default:
+		p.reduces++
+		result.Text = p.reduceReply
+	}
+	return result, nil
+}
+
+func newSummaryService(p provider.Provider) *ChatService {
+	return &ChatService{openaiProvider: p, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+}
+
+func TestSummarizeDocument_MapReduce(t *testing.T) {
+	p := &summaryProvider{
+		mockProvider: newMockProvider("openai"),
+		reduceReply: "```json\n" + `{"title": "Report", "summary": "Overall", "sections": [
+			{"heading": "Intro", "summary": "First part", "parts": [1]},
+			{"heading": "Rest", "summary": "Later parts", "parts": [2, 3, 99]}
+		]}` + "\n```",
+	}
+	svc := newSummaryService(p)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	doc := strings.Repeat(strings.Repeat("word ", 199)+"end.\n\n", 30) // ~30KB
+	resp, err := svc.SummarizeDocument(ctx, &pb.SummarizeDocumentRequest{
+		Source:   &pb.SummarizeDocumentRequest_Content{Content: []byte(doc)},
+		Filename: "report.txt",
+	})
+	if err != nil {
+		t.Fatalf("SummarizeDocument failed: %v", err)
+	}
+
+	if resp.PartCount < 3 || p.maps != int(resp.PartCount) || p.combines != 0 || p.reduces != 1 {
+		t.Errorf("parts=%d maps=%d combines=%d reduces=%d; want one map per part and one reduce", resp.PartCount, p.maps, p.combines, p.reduces)
+	}
+	if resp.Title != "Report" || resp.Summary != "Overall" || len(resp.Sections) != 2 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	cites := resp.Sections[1].Citations
+	if len(cites) != 2 || cites[0].Part != 2 || cites[1].Part != 3 || cites[0].CharStart >= cites[0].CharEnd {
+		t.Errorf("unexpected citations (part 99 should be dropped): %+v", cites)
+	}
+	if resp.Usage.TotalTokens != int64(p.maps+1)*110 || resp.Provider != pb.Provider_PROVIDER_OPENAI {
+		t.Errorf("usage = %+v, provider = %v", resp.Usage, resp.Provider)
+	}
+}
+
+func TestSummarizeDocument_CombinesManyParts(t *testing.T) {
+	p := &summaryProvider{mockProvider: newMockProvider("openai"), reduceReply: "Not JSON, just a summary."}
+	svc := newSummaryService(p)
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	doc := strings.Repeat(strings.Repeat("word ", 199)+"end.\n\n", 200) // ~200KB, more parts than one reduce batch
+	resp, err := svc.SummarizeDocument(ctx, &pb.SummarizeDocumentRequest{
+		Source: &pb.SummarizeDocumentRequest_Content{Content: []byte(doc)},
+	})
+	if err != nil {
+		t.Fatalf("SummarizeDocument failed: %v", err)
+	}
+	if want := (int(resp.PartCount) + summaryReduceBatch - 1) / summaryReduceBatch; p.combines != want {
+		t.Errorf("combines = %d, want %d batches for %d parts", p.combines, want, resp.PartCount)
+	}
+	if resp.Summary != "Not JSON, just a summary." || len(resp.Sections) != 0 {
+		t.Errorf("unexpected fallback summary: %+v", resp)
+	}
+}
+
+func TestSummarizeDocument_RequiresSource(t *testing.T) {
+	svc := newSummaryService(newMockProvider("openai"))
+	ctx := ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai"))
+
+	_, err := svc.SummarizeDocument(ctx, &pb.SummarizeDocumentRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("err = %v, want InvalidArgument", err)
+	}
+
+	_, err = svc.SummarizeDocument(ctx, &pb.SummarizeDocumentRequest{
+		Source: &pb.SummarizeDocumentRequest_StoredFile{StoredFile: &pb.StoredFileRef{StoreId: "docs", FileId: "file_1"}},
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("stored file without RAG: err = %v, want FailedPrecondition", err)
+	}
+}
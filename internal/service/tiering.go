@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// escalatedOnError is the escalation reason of a cheap model call that failed.
+const escalatedOnError = "error"
+
+const tierConfidenceInstructions = `You are checking an assistant's answer to a user's message.
+Score from 0 to 1 how confident you are that the answer is correct, complete
+and directly addresses the message, where 1 means a careful expert would give
+the same answer and 0 means it is wrong, evasive or off-topic.
+Reply with a JSON object only, in this form:
+{"score": 0.8, "reason": "..."}`
+
+// confidenceVerdict is the JSON the confidence verifier model returns.
+type confidenceVerdict struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// modelTier is an "auto" model tier request's cheap model and the outcome
+// of trying it.
+type modelTier struct {
+	policy     tenant.TieringConfig
+	cheap      string
+	escalation string // Criterion the cheap reply failed; empty if it was served
+}
+
+// served reports whether the cheap model's reply is the request's reply.
+func (t *modelTier) served() bool {
+	return t != nil && t.escalation == ""
+}
+
+// name returns the tier that served the request.
+func (t *modelTier) name() string {
+	if t.served() {
+		return tenant.TierCheap
+	}
+	return tenant.TierPremium
+}
+
+// isAutoModel reports whether model asks for the "auto" model tier.
+func isAutoModel(model string) bool {
+	return strings.EqualFold(strings.TrimSpace(model), tenant.AutoModel)
+}
+
+// tieredRequest reports whether a request to providerName is served from
+// the "auto" model tier: it selects "auto" as its model, or the tenant tiers
+// requests that select no model. Selecting "auto" when the tenant has no
+// cheap model for the provider is an error.
+func tieredRequest(tenantCfg *tenant.TenantConfig, req *pb.GenerateReplyRequest, providerName string) (bool, error) {
+	if isAutoModel(req.ModelOverride) {
+		if tenantCfg == nil {
+			return false, status.Error(codes.InvalidArgument, `model_override "auto" requires a tenant config`)
+		}
+		if _, ok := tenantCfg.Tiering.CheapModel(providerName); !ok {
+			return false, status.Errorf(codes.InvalidArgument, `model_override "auto" requires a cheap model for %s in the tenant's tiering config`, providerName)
+		}
+		return true, nil
+	}
+	if tenantCfg == nil || !tenantCfg.Tiering.Default {
+		return false, nil
+	}
+	if strings.TrimSpace(req.ModelOverride) != "" || strings.TrimSpace(req.ProviderConfigs[providerName].GetModel()) != "" {
+		return false, nil
+	}
+	if preset, ok := tenantCfg.Preset(req.Preset); ok && preset.Models[providerName] != "" {
+		return false, nil
+	}
+	_, ok := tenantCfg.Tiering.CheapModel(providerName)
+	return ok, nil
+}
+
+// modelTierFor returns the model tier of a request prepared for the "auto"
+// tier, or nil when it is served as usual: the provider it ended up on has
+// no cheap model, or would use the cheap model anyway.
+func modelTierFor(ctx context.Context, prepared *preparedRequest) *modelTier {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if !prepared.tiered || tenantCfg == nil {
+		return nil
+	}
+	cheap, ok := tenantCfg.Tiering.CheapModel(prepared.provider.Name())
+	if !ok || cheap == provider.SelectModel(prepared.providerCfg.Model, "", prepared.params.OverrideModel) {
+		return nil
+	}
+	return &modelTier{policy: tenantCfg.Tiering, cheap: cheap}
+}
+
+// generateCheap generates the reply of an "auto" model tier request from
+// its cheap model and checks it against the tenant's escalation criteria.
+// When it fails one, tier.escalation is set, the reply's cost is charged to
+// the tenant's spend and the request goes on to the premium model.
+func (s *ChatService) generateCheap(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, tier *modelTier) provider.GenerateResult {
+	name := prepared.provider.Name()
+	params := prepared.params
+	params.OverrideModel = tier.cheap
+
+	// Leave the premium model and failover chain part of the request deadline
+	cheapCtx, cancel := withBudget(ctx, s.primaryBudget(ctx, req, prepared))
+	result, err := prepared.provider.GenerateReply(s.observeHeadroom(cheapCtx, name), params)
+	cancel()
+	s.reportProviderCall(ctx, name, params, result, err)
+	if err == nil {
+		result, err = s.runRemoteTools(ctx, prepared.provider, params, result)
+	}
+
+	var cheapCost float64
+	if err != nil {
+		tier.escalation = escalatedOnError
+		slog.WarnContext(ctx, "cheap model failed, escalating to premium model",
+			"provider", name,
+			"model", tier.cheap,
+			"error", err,
+			"request_id", prepared.requestID,
+		)
+	} else if tier.escalation = s.escalationCriterion(ctx, req, prepared, tier.policy, result); tier.escalation != "" {
+		cheapCost = estimateCost(name, result.Model, result.Usage, result.GroundingQueries).Total()
+		s.recordSpend(ctx, cheapCost)
+		slog.InfoContext(ctx, "cheap model reply escalated to premium model",
+			"provider", name,
+			"model", tier.cheap,
+			"criterion", tier.escalation,
+			"request_id", prepared.requestID,
+		)
+	}
+
+	accesslog.Annotate(ctx, "model_tier", tier.name())
+	if tier.escalation != "" {
+		accesslog.Annotate(ctx, "tier_escalation", tier.escalation)
+	}
+	s.metrics.ObserveTier(metrics.TierObservation{
+		TenantID:  auth.TenantIDFromContext(ctx),
+		Provider:  name,
+		Escalated: tier.escalation != "",
+		CheapCost: cheapCost,
+	})
+	return result
+}
+
+// escalationCriterion returns the first of the policy's escalation criteria
+// a cheap reply fails, or "" to serve it. Replies that request tool calls or
+// were blocked by safety filters are served; failover handles the latter.
+// The confidence check runs last, as it calls a verifier model; replies the
+// verifier fails to score are served.
+func (s *ChatService) escalationCriterion(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, policy tenant.TieringConfig, result provider.GenerateResult) string {
+	if policy.EscalatesOn(tenant.EscalateOnEmpty) && emptyReply(result) {
+		return tenant.EscalateOnEmpty
+	}
+	if len(result.ToolCalls) > 0 || result.SafetyBlock != nil {
+		return ""
+	}
+	if rules, _ := replyRules(ctx); policy.EscalatesOn(tenant.EscalateOnValidation) && rules.Enabled() && len(rules.Check(result.Text)) > 0 {
+		return tenant.EscalateOnValidation
+	}
+	if !judgeable(result) {
+		return ""
+	}
+	if policy.EscalatesOn(tenant.EscalateOnUncertain) && policy.Uncertain(result.Text) != "" {
+		return tenant.EscalateOnUncertain
+	}
+	if policy.EscalatesOn(tenant.EscalateOnConfidence) {
+		score, err := s.scoreConfidence(ctx, req, prepared, policy, result.Text)
+		if err != nil {
+			slog.WarnContext(ctx, "confidence check failed, serving cheap reply",
+				"error", err,
+				"request_id", prepared.requestID,
+			)
+			return ""
+		}
+		accesslog.Annotate(ctx, "tier_confidence", score)
+		if score < policy.MinConfidence {
+			return tenant.EscalateOnConfidence
+		}
+	}
+	return ""
+}
+
+// scoreConfidence asks the policy's verifier model to score, from 0 to 1,
+// how likely text correctly answers the request. The verifier call is
+// charged to the tenant's spend.
+func (s *ChatService) scoreConfidence(ctx context.Context, req *pb.GenerateReplyRequest, prepared *preparedRequest, policy tenant.TieringConfig, text string) (float64, error) {
+	verifierName := policy.Provider
+	if verifierName == "" {
+		verifierName = prepared.provider.Name()
+	}
+	verifier := s.providerByName(verifierName)
+	if verifier == nil {
+		return 0, fmt.Errorf("confidence verifier provider %q is not available", verifierName)
+	}
+
+	params := provider.GenerateParams{
+		Instructions:  tierConfidenceInstructions,
+		UserInput:     fmt.Sprintf("Message:\n%s\n\nAnswer:\n%s\n", prepared.params.UserInput, strings.TrimSpace(text)),
+		OverrideModel: policy.Model,
+		Config:        s.buildProviderConfig(ctx, req, verifierName),
+		RequestID:     prepared.params.RequestID,
+		ClientID:      prepared.params.ClientID,
+	}
+	out, err := verifier.GenerateReply(s.observeHeadroom(ctx, verifierName), params)
+	s.reportProviderCall(ctx, verifierName, params, out, err)
+	if err != nil {
+		return 0, err
+	}
+	s.recordSpend(ctx, estimateCost(verifierName, out.Model, out.Usage, out.GroundingQueries).Total())
+
+	var verdict confidenceVerdict
+	if err := json.Unmarshal([]byte(validation.StripCodeFence(out.Text)), &verdict); err != nil {
+		return 0, fmt.Errorf("confidence verifier reply is not valid JSON: %w", err)
+	}
+	return min(max(verdict.Score, 0), 1), nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/metrics"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGenerateReply_ModelTier(t *testing.T) {
+	answer := provider.GenerateResult{Text: "Our store opens at 9am.", Model: "cheap-model", Usage: &provider.Usage{TotalTokens: 10}}
+	unsure := provider.GenerateResult{Text: "I'm not sure when the store opens.", Model: "cheap-model", Usage: &provider.Usage{TotalTokens: 10}}
+	premium := provider.GenerateResult{Text: "The store opens at 9am on weekdays.", Model: "test-model-openai", Usage: &provider.Usage{TotalTokens: 20}}
+	tiering := tenant.TieringConfig{CheapModels: map[string]string{"openai": "cheap-model"}}
+
+	tests := []struct {
+		name       string
+		tiering    tenant.TieringConfig
+		model      string
+		results    []provider.GenerateResult
+		confidence string // Verifier reply
+		wantModels []string
+		wantTier   string
+		wantReason string
+		wantCode   codes.Code
+	}{
+		{name: "cheap reply served", tiering: tiering, model: "auto", results: []provider.GenerateResult{answer},
+			wantModels: []string{"cheap-model"}, wantTier: tenant.TierCheap},
+		{name: "uncertain reply escalated", tiering: tiering, model: "auto", results: []provider.GenerateResult{unsure, premium},
+			wantModels: []string{"cheap-model", ""}, wantTier: tenant.TierPremium, wantReason: tenant.EscalateOnUncertain},
+		{name: "empty reply escalated", tiering: tiering, model: "auto", results: []provider.GenerateResult{{Model: "cheap-model"}, premium},
+			wantModels: []string{"cheap-model", ""}, wantTier: tenant.TierPremium, wantReason: tenant.EscalateOnEmpty},
+		{name: "criterion not configured", tiering: tenant.TieringConfig{CheapModels: tiering.CheapModels, EscalateOn: []string{"empty_response"}}, model: "auto", results: []provider.GenerateResult{unsure},
+			wantModels: []string{"cheap-model"}, wantTier: tenant.TierCheap},
+		{name: "low confidence escalated", tiering: tenant.TieringConfig{CheapModels: tiering.CheapModels, EscalateOn: []string{"confidence"}, MinConfidence: 0.6, Provider: "gemini"}, model: "auto",
+			results: []provider.GenerateResult{answer, premium}, confidence: `{"score": 0.3, "reason": "guessed"}`,
+			wantModels: []string{"cheap-model", ""}, wantTier: tenant.TierPremium, wantReason: tenant.EscalateOnConfidence},
+		{name: "high confidence served", tiering: tenant.TieringConfig{CheapModels: tiering.CheapModels, EscalateOn: []string{"confidence"}, MinConfidence: 0.6, Provider: "gemini"}, model: "auto",
+			results: []provider.GenerateResult{answer}, confidence: `{"score": 0.9, "reason": "matches"}`,
+			wantModels: []string{"cheap-model"}, wantTier: tenant.TierCheap},
+		{name: "tenant default", tiering: tenant.TieringConfig{CheapModels: tiering.CheapModels, Default: true}, results: []provider.GenerateResult{answer},
+			wantModels: []string{"cheap-model"}, wantTier: tenant.TierCheap},
+		{name: "tenant default with a requested model", tiering: tenant.TieringConfig{CheapModels: tiering.CheapModels, Default: true}, model: "test-model-openai", results: []provider.GenerateResult{premium},
+			wantModels: []string{"test-model-openai"}},
+		{name: "auto without a cheap model", model: "auto", results: []provider.GenerateResult{premium}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openai := &resultsProvider{mockProvider: newMockProvider("openai"), results: tt.results}
+			gemini := newMockProvider("gemini")
+			gemini.generateResult = provider.GenerateResult{Text: tt.confidence, Model: "test-model-gemini"}
+			registry := metrics.NewRegistry()
+			svc := &ChatService{openaiProvider: openai, geminiProvider: gemini, anthropicProvider: newMockProvider("anthropic"), metrics: registry}
+			cfg := createTestTenantConfig("openai", "gemini")
+			cfg.Tiering = tt.tiering
+			ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+
+			resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "When do you open?", PreferredProvider: pb.Provider_PROVIDER_OPENAI, ModelOverride: tt.model})
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateReply failed: %v", err)
+			}
+
+			if len(openai.generateCalls) != len(tt.wantModels) {
+				t.Fatalf("openai calls = %d, want %d", len(openai.generateCalls), len(tt.wantModels))
+			}
+			for i, want := range tt.wantModels {
+				if got := openai.generateCalls[i].OverrideModel; got != want {
+					t.Errorf("call %d OverrideModel = %q, want %q", i, got, want)
+				}
+			}
+			if want := tt.results[len(tt.wantModels)-1].Text; resp.Text != want {
+				t.Errorf("Text = %q, want %q", resp.Text, want)
+			}
+			if resp.ModelTier != tt.wantTier || resp.EscalationReason != tt.wantReason {
+				t.Errorf("tier = %q (%q), want %q (%q)", resp.ModelTier, resp.EscalationReason, tt.wantTier, tt.wantReason)
+			}
+
+			tiers := registry.Snapshot().Tiers
+			if tt.wantTier == "" {
+				if len(tiers) != 0 {
+					t.Errorf("tier stats = %+v, want none for an untiered request", tiers)
+				}
+				return
+			}
+			if len(tiers) != 1 || tiers[0].Requests != 1 || (tiers[0].Escalations == 1) != (tt.wantTier == tenant.TierPremium) {
+				t.Errorf("tier stats = %+v, want one %s request", tiers, tt.wantTier)
+			}
+		})
+	}
+}
+
+func TestGenerateReplyStream_AutoModelRejected(t *testing.T) {
+	openai := newMockProvider("openai")
+	svc := createChatServiceWithMocks(openai, newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	cfg := createTestTenantConfig("openai")
+	cfg.Tiering = tenant.TieringConfig{CheapModels: map[string]string{"openai": "cheap-model"}}
+	stream := &mockGenerateReplyStream{ctx: ctxWithChatPermissionAndTenant("test-client", cfg)}
+
+	err := svc.GenerateReplyStream(&pb.GenerateReplyRequest{UserInput: "When do you open?", ModelOverride: "auto"}, stream)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("err = %v, want InvalidArgument", err)
+	}
+	if len(openai.streamCalls) != 0 || len(stream.chunks) != 0 {
+		t.Errorf("streamCalls = %d, chunks = %d, want none", len(openai.streamCalls), len(stream.chunks))
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/redis"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toolTurnTTL is how long a turn that requested tools can be continued.
+const toolTurnTTL = time.Hour
+
+// WithToolTurnStore keeps turns that requested tools in Redis so any server
+// instance can continue them. Without it they are kept in process memory.
+func WithToolTurnStore(client *redis.Client) ChatServiceOption {
+	return func(s *ChatService) {
+		if client != nil {
+			s.toolTurns = client
+		}
+	}
+}
+
+// toolTurnStore is the subset of Redis used for tool turns, satisfied by
+// both *redis.Client and *redis.MemoryStore.
+type toolTurnStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// toolTurnRecord is a stored turn that ended by requesting tools, for
+// providers that cannot continue from a response ID on their own.
+type toolTurnRecord struct {
+	Provider  string              `json:"provider"`
+	Model     string              `json:"model"`
+	UserInput string              `json:"user_input"` // The user message that started the tool exchange
+	ToolCalls []provider.ToolCall `json:"tool_calls"`
+	State     []byte              `json:"state"` // Provider replay state
+}
+
+// toolTurnKey namespaces turn IDs per tenant.
+func toolTurnKey(tenantID, responseID string) string {
+	return fmt.Sprintf("airborne:toolturn:%s:%s", tenantID, responseID)
+}
+
+// continuesToolTurn reports whether req sends tool results.
+func continuesToolTurn(req *pb.GenerateReplyRequest) bool {
+	return len(req.ToolResults) > 0
+}
+
+// loadToolTurn validates a request carrying tool results and returns the turn
+// to replay. Providers with native continuity resume from the response ID
+// themselves, so nothing is loaded for them.
+func (s *ChatService) loadToolTurn(ctx context.Context, req *pb.GenerateReplyRequest, p provider.Provider) (*toolTurnRecord, error) {
+	if !continuesToolTurn(req) {
+		return nil, nil
+	}
+	if req.PreviousResponseId == "" {
+		return nil, status.Error(codes.InvalidArgument, "previous_response_id is required with tool_results")
+	}
+	results := convertToolResults(req.ToolResults)
+	if p.SupportsNativeContinuity() {
+		// The provider knows its own calls; only the batch itself can be checked
+		if err := provider.ValidateToolResultIDs(results); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, nil
+	}
+
+	data, err := s.toolTurns.Get(ctx, toolTurnKey(auth.TenantIDFromContext(ctx), req.PreviousResponseId))
+	if redis.IsNil(err) {
+		return nil, status.Errorf(codes.FailedPrecondition, "tool call turn %q not found or expired", req.PreviousResponseId)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load tool turn", "response_id", req.PreviousResponseId, "error", err)
+		return nil, status.Error(codes.Unavailable, "tool call turn store unavailable")
+	}
+	var rec toolTurnRecord
+	if err := json.Unmarshal([]byte(data), &rec); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "tool call turn %q is corrupt", req.PreviousResponseId)
+	}
+
+	if rec.Provider != p.Name() {
+		return nil, status.Errorf(codes.InvalidArgument, "tool results for %q must be sent to %s", req.PreviousResponseId, rec.Provider)
+	}
+	// Parallel calls are answered as one batch, in any order
+	if _, err := provider.OrderToolResults(rec.ToolCalls, results); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &rec, nil
+}
+
+// saveToolTurn stores a turn that ended by requesting tools and returns the
+// response ID the client continues from. Providers with native continuity
+// keep their own state, so their response ID is returned unchanged.
+func (s *ChatService) saveToolTurn(ctx context.Context, p provider.Provider, params provider.GenerateParams, model, responseID string, toolCalls []provider.ToolCall, state []byte) string {
+	if len(toolCalls) == 0 || len(state) == 0 || p.SupportsNativeContinuity() {
+		return responseID
+	}
+	if responseID == "" {
+		responseID = "turn_" + uuid.NewString()
+	}
+
+	rec := toolTurnRecord{
+		Provider:  p.Name(),
+		Model:     model,
+		UserInput: params.UserInput,
+		ToolCalls: toolCalls,
+		State:     state,
+	}
+	if params.ToolTurn != nil {
+		rec.UserInput = params.ToolTurn.UserInput // Chained calls keep the original message
+	}
+	data, err := json.Marshal(rec)
+	if err == nil {
+		err = s.toolTurns.Set(ctx, toolTurnKey(auth.TenantIDFromContext(ctx), responseID), data, toolTurnTTL)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to store tool turn, it cannot be continued",
+			"provider", p.Name(),
+			"response_id", responseID,
+			"error", err,
+		)
+	}
+	return responseID
+}
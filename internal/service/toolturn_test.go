@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/redis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newToolTurnService returns a service whose Gemini provider lacks native
+// continuity, so tool turns go through the store.
+func newToolTurnService() (*ChatService, context.Context) {
+	svc := createChatServiceWithMocks(newMockProvider("openai"), newMockProvider("gemini"), newMockProvider("anthropic"), nil)
+	svc.toolTurns = redis.NewMemoryStore()
+	return svc, ctxWithChatPermissionAndTenant("test-client", createTestTenantConfig("openai", "gemini"))
+}
+
+// saveGeminiToolTurn stores a Gemini turn that requested the "lookup" tool.
+func saveGeminiToolTurn(t *testing.T, svc *ChatService, ctx context.Context) string {
+	t.Helper()
+	params := provider.GenerateParams{UserInput: "What is the weather?"}
+	calls := []provider.ToolCall{{ID: "call_0", Name: "lookup", Arguments: `{"city":"Paris"}`}}
+	id := svc.saveToolTurn(ctx, svc.geminiProvider, params, "gemini-test", "", calls, []byte(`[]`))
+	if !strings.HasPrefix(id, "turn_") {
+		t.Fatalf("expected generated turn ID, got %q", id)
+	}
+	return id
+}
+
+func TestToolTurn_ContinuesStoredTurn(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	id := saveGeminiToolTurn(t, svc, ctx)
+
+	prepared, err := svc.prepareRequest(ctx, &pb.GenerateReplyRequest{
+		PreferredProvider:  pb.Provider_PROVIDER_GEMINI,
+		PreviousResponseId: id,
+		ToolResults:        []*pb.ToolResult{{ToolCallId: "call_0", Output: "sunny"}},
+	})
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+
+	turn := prepared.params.ToolTurn
+	if turn == nil {
+		t.Fatal("expected tool turn to be loaded")
+	}
+	if turn.UserInput != "What is the weather?" {
+		t.Errorf("expected original user input, got %q", turn.UserInput)
+	}
+	if len(turn.ToolCalls) != 1 || turn.ToolCalls[0].ID != "call_0" {
+		t.Errorf("unexpected tool calls: %+v", turn.ToolCalls)
+	}
+	if string(turn.State) != "[]" {
+		t.Errorf("expected stored state, got %q", turn.State)
+	}
+	if prepared.params.OverrideModel != "gemini-test" {
+		t.Errorf("expected continuation to pin model, got %q", prepared.params.OverrideModel)
+	}
+}
+
+func TestToolTurn_ChainedCallsKeepOriginalInput(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	params := provider.GenerateParams{
+		ToolTurn: &provider.ToolTurn{UserInput: "What is the weather?"},
+	}
+	calls := []provider.ToolCall{{ID: "call_1", Name: "forecast"}}
+	id := svc.saveToolTurn(ctx, svc.geminiProvider, params, "gemini-test", "", calls, []byte(`[]`))
+
+	rec, err := svc.loadToolTurn(ctx, &pb.GenerateReplyRequest{
+		PreviousResponseId: id,
+		ToolResults:        []*pb.ToolResult{{ToolCallId: "call_1", Output: "rain"}},
+	}, svc.geminiProvider)
+	if err != nil {
+		t.Fatalf("loadToolTurn failed: %v", err)
+	}
+	if rec.UserInput != "What is the weather?" {
+		t.Errorf("expected original user input, got %q", rec.UserInput)
+	}
+}
+
+func TestToolTurn_Errors(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	id := saveGeminiToolTurn(t, svc, ctx)
+
+	tests := []struct {
+		name     string
+		req      *pb.GenerateReplyRequest
+		provider provider.Provider
+		code     codes.Code
+	}{
+		{
+			name:     "missing previous_response_id",
+			req:      &pb.GenerateReplyRequest{ToolResults: []*pb.ToolResult{{ToolCallId: "call_0"}}},
+			provider: svc.geminiProvider,
+			code:     codes.InvalidArgument,
+		},
+		{
+			name: "unknown tool_call_id",
+			req: &pb.GenerateReplyRequest{
+				PreviousResponseId: id,
+				ToolResults:        []*pb.ToolResult{{ToolCallId: "call_9"}},
+			},
+			provider: svc.geminiProvider,
+			code:     codes.InvalidArgument,
+		},
+		{
+			name: "different provider",
+			req: &pb.GenerateReplyRequest{
+				PreviousResponseId: id,
+				ToolResults:        []*pb.ToolResult{{ToolCallId: "call_0"}},
+			},
+			provider: svc.anthropicProvider,
+			code:     codes.InvalidArgument,
+		},
+		{
+			name: "expired turn",
+			req: &pb.GenerateReplyRequest{
+				PreviousResponseId: "turn_missing",
+				ToolResults:        []*pb.ToolResult{{ToolCallId: "call_0"}},
+			},
+			provider: svc.geminiProvider,
+			code:     codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.loadToolTurn(ctx, tt.req, tt.provider)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestToolTurn_NativeContinuity(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	openai := svc.openaiProvider.(*mockProvider)
+	openai.supportsNative = true
+
+	calls := []provider.ToolCall{{ID: "call_abc", Name: "lookup"}}
+	if id := svc.saveToolTurn(ctx, openai, provider.GenerateParams{}, "gpt-test", "resp-1", calls, nil); id != "resp-1" {
+		t.Errorf("expected provider response ID, got %q", id)
+	}
+
+	prepared, err := svc.prepareRequest(ctx, &pb.GenerateReplyRequest{
+		PreferredProvider:  pb.Provider_PROVIDER_OPENAI,
+		PreviousResponseId: "resp-1",
+		ToolResults:        []*pb.ToolResult{{ToolCallId: "call_abc", Output: "ok"}},
+	})
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+	if prepared.params.ToolTurn != nil {
+		t.Error("expected no stored turn for native continuity")
+	}
+	if len(prepared.params.ToolResults) != 1 {
+		t.Errorf("expected tool results to be passed through, got %d", len(prepared.params.ToolResults))
+	}
+}
+
+func TestToolTurn_NoFailoverOrHedging(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	req := &pb.GenerateReplyRequest{
+		EnableHedging:      true,
+		EnableFailover:     true,
+		PreviousResponseId: "resp-1",
+		ToolResults:        []*pb.ToolResult{{ToolCallId: "call_0"}},
+	}
+	prepared := &preparedRequest{provider: svc.geminiProvider}
+	if hedge := svc.hedgeTarget(ctx, req, prepared); hedge != nil {
+		t.Errorf("expected no hedge for tool continuation, got %s", hedge.Name())
+	}
+}
+
+func TestGenerateReply_StoresToolTurn(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	gemini := svc.geminiProvider.(*mockProvider)
+	gemini.generateResult = provider.GenerateResult{
+		Model:              "gemini-test",
+		ToolCalls:          []provider.ToolCall{{ID: "call_0", Name: "lookup"}},
+		RequiresToolOutput: true,
+		ToolTurnState:      []byte(`[]`),
+	}
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{
+		UserInput:         "What is the weather?",
+		PreferredProvider: pb.Provider_PROVIDER_GEMINI,
+	})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if !resp.RequiresToolOutput || !strings.HasPrefix(resp.ResponseId, "turn_") {
+		t.Fatalf("expected stored tool turn, got requires=%v id=%q", resp.RequiresToolOutput, resp.ResponseId)
+	}
+
+	rec, err := svc.loadToolTurn(ctx, &pb.GenerateReplyRequest{
+		PreviousResponseId: resp.ResponseId,
+		ToolResults:        []*pb.ToolResult{{ToolCallId: "call_0", Output: "sunny"}},
+	}, gemini)
+	if err != nil {
+		t.Fatalf("loadToolTurn failed: %v", err)
+	}
+	if rec.UserInput != "What is the weather?" || rec.Model != "gemini-test" {
+		t.Errorf("unexpected stored turn: %+v", rec)
+	}
+}
+
+func TestToolTurn_ParallelResultBatch(t *testing.T) {
+	svc, ctx := newToolTurnService()
+	calls := []provider.ToolCall{{ID: "call_0", Name: "lookup"}, {ID: "call_1", Name: "lookup"}}
+	id := svc.saveToolTurn(ctx, svc.geminiProvider, provider.GenerateParams{UserInput: "Paris and Rome?"}, "gemini-test", "", calls, []byte(`[]`))
+
+	tests := []struct {
+		name    string
+		results []*pb.ToolResult
+		code    codes.Code
+	}{
+		{"any order", []*pb.ToolResult{{ToolCallId: "call_1"}, {ToolCallId: "call_0"}}, codes.OK},
+		{"missing result", []*pb.ToolResult{{ToolCallId: "call_0"}}, codes.InvalidArgument},
+		{"duplicate result", []*pb.ToolResult{{ToolCallId: "call_0"}, {ToolCallId: "call_0"}, {ToolCallId: "call_1"}}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.loadToolTurn(ctx, &pb.GenerateReplyRequest{PreviousResponseId: id, ToolResults: tt.results}, svc.geminiProvider)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+
+	// Native continuity providers still reject a malformed batch
+	openai := svc.openaiProvider.(*mockProvider)
+	openai.supportsNative = true
+	_, err := svc.loadToolTurn(ctx, &pb.GenerateReplyRequest{
+		PreviousResponseId: "resp-1",
+		ToolResults:        []*pb.ToolResult{{ToolCallId: "call_a"}, {ToolCallId: "call_a"}},
+	}, openai)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for duplicate native results, got %v", err)
+	}
+}
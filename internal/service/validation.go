@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/ai8future/airborne/internal/accesslog"
+	"github.com/ai8future/airborne/internal/auth"
+	"github.com/ai8future/airborne/internal/db"
+	sanitize "github.com/ai8future/airborne/internal/errors"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxRejectedReplyChars bounds the rejected reply text kept in debug data.
+const maxRejectedReplyChars = 2000
+
+// replyRules returns the tenant's reply validation rules and how many times
+// a failing reply is regenerated.
+func replyRules(ctx context.Context) (validation.ReplyRules, int) {
+	tenantCfg := auth.TenantFromContext(ctx)
+	if tenantCfg == nil {
+		return validation.ReplyRules{}, 0
+	}
+	v := tenantCfg.Validation
+	rules := validation.ReplyRules{
+		NonEmpty:      v.NonEmpty,
+		RequireJSON:   v.RequireJSON,
+		Schema:        v.JSONSchema,
+		BannedStrings: v.BannedStrings,
+	}
+	return rules, v.Retries()
+}
+
+// validateReply checks result against the tenant's validation rules. While
+// it fails, the reply is regenerated from p with a corrective instruction,
+// up to the tenant's retry limit. Rejected replies are returned for the
+// debug record and their cost is charged to the tenant's spend. Replies that
+// request tool calls are not validated.
+func (s *ChatService) validateReply(ctx context.Context, p provider.Provider, params provider.GenerateParams, result provider.GenerateResult) (provider.GenerateResult, []db.ValidationAttempt, error) {
+	rules, retries := replyRules(ctx)
+	if !rules.Enabled() {
+		return result, nil, nil
+	}
+
+	var attempts []db.ValidationAttempt
+	for attempt := 1; ; attempt++ {
+		// Tool calls and safety blocks carry no reply text to validate
+		if len(result.ToolCalls) > 0 || result.SafetyBlock != nil {
+			return result, attempts, nil
+		}
+		problems := rules.Check(result.Text)
+		if len(problems) == 0 {
+			if len(attempts) > 0 {
+				accesslog.Annotate(ctx, "validation_retries", len(attempts))
+			}
+			return result, attempts, nil
+		}
+
+		attempts = append(attempts, db.ValidationAttempt{
+			Attempt:  attempt,
+			Provider: p.Name(),
+			Model:    result.Model,
+			Problems: problems,
+			Response: truncateString(result.Text, maxRejectedReplyChars),
+		})
+		s.recordSpend(ctx, estimateCost(p.Name(), result.Model, result.Usage, result.GroundingQueries).Total())
+
+		if attempt > retries {
+			accesslog.Annotate(ctx, "validation_retries", retries)
+			return result, attempts, status.Errorf(codes.Internal, "response failed validation after %d attempts: %s",
+				attempt, strings.Join(problems, "; "))
+		}
+
+		slog.InfoContext(ctx, "reply failed validation, regenerating",
+			"provider", p.Name(),
+			"attempt", attempt,
+			"problems", problems,
+			"request_id", params.RequestID,
+		)
+
+		// Show the model its rejected reply and ask for a corrected one
+		params.ConversationHistory = append(slices.Clone(params.ConversationHistory),
+			provider.Message{Role: "user", Content: params.UserInput},
+			provider.Message{Role: "assistant", Content: result.Text},
+		)
+		params.UserInput = validation.CorrectiveInstruction(rules, problems)
+
+		var err error
+		result, err = p.GenerateReply(s.observeHeadroom(ctx, p.Name()), params)
+		s.reportProviderCall(ctx, p.Name(), params, result, err)
+		if err != nil {
+			return result, attempts, status.Error(codes.Internal, sanitize.SanitizeForClient(err))
+		}
+	}
+}
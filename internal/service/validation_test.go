@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/ai8future/airborne/gen/go/airborne/v1"
+	"github.com/ai8future/airborne/internal/db"
+	"github.com/ai8future/airborne/internal/provider"
+	"github.com/ai8future/airborne/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scriptedProvider returns replies in order, repeating the last one.
+type scriptedProvider struct {
+	*mockProvider
+	replies []string
+}
+
+func (p *scriptedProvider) GenerateReply(ctx context.Context, params provider.GenerateParams) (provider.GenerateResult, error) {
+	result, err := p.mockProvider.GenerateReply(ctx, params)
+	result.Text = p.replies[min(len(p.generateCalls), len(p.replies))-1]
+	return result, err
+}
+
+func validatingTenant(rules tenant.ValidationConfig) *tenant.TenantConfig {
+	cfg := createTestTenantConfig("openai")
+	cfg.Validation = rules
+	return cfg
+}
+
+func TestGenerateReply_RegeneratesInvalidReply(t *testing.T) {
+	openai := &scriptedProvider{mockProvider: newMockProvider("openai"), replies: []string{"", "Sure: {\"answer\": 42}", `{"answer": 42}`}}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+	ctx := ctxWithChatPermissionAndTenant("test-client", validatingTenant(tenant.ValidationConfig{
+		NonEmpty:   true,
+		JSONSchema: map[string]any{"type": "object", "required": []any{"answer"}},
+	}))
+
+	resp, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "What is the answer?", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if err != nil {
+		t.Fatalf("GenerateReply failed: %v", err)
+	}
+	if resp.Text != `{"answer": 42}` {
+		t.Errorf("Text = %q, want the third (valid) reply", resp.Text)
+	}
+	if len(openai.generateCalls) != 3 {
+		t.Fatalf("provider called %d times, want 3", len(openai.generateCalls))
+	}
+
+	// Retries carry the rejected reply and a corrective instruction
+	retry := openai.generateCalls[2]
+	if len(retry.ConversationHistory) != 4 || retry.ConversationHistory[0].Content != "What is the answer?" {
+		t.Errorf("unexpected retry history: %+v", retry.ConversationHistory)
+	}
+	if !strings.Contains(retry.UserInput, "not valid JSON") {
+		t.Errorf("retry instruction %q does not explain the failure", retry.UserInput)
+	}
+}
+
+func TestGenerateReply_FailsAfterValidationRetries(t *testing.T) {
+	openai := &scriptedProvider{mockProvider: newMockProvider("openai"), replies: []string{"As an AI model, I cannot"}}
+	svc := &ChatService{openaiProvider: openai, geminiProvider: newMockProvider("gemini"), anthropicProvider: newMockProvider("anthropic")}
+	ctx := ctxWithChatPermissionAndTenant("test-client", validatingTenant(tenant.ValidationConfig{
+		BannedStrings: []string{"as an ai"},
+		MaxRetries:    1,
+	}))
+
+	_, err := svc.GenerateReply(ctx, &pb.GenerateReplyRequest{UserInput: "Hello", PreferredProvider: pb.Provider_PROVIDER_OPENAI})
+	if status.Code(err) != codes.Internal || !strings.Contains(err.Error(), "failed validation after 2 attempts") {
+		t.Errorf("err = %v, want validation failure after 2 attempts", err)
+	}
+	if len(openai.generateCalls) != 2 {
+		t.Errorf("provider called %d times, want 2", len(openai.generateCalls))
+	}
+}
+
+func TestGenerateWithFailover_KeepsValidationAttempts(t *testing.T) {
+	gemini := &scriptedProvider{mockProvider: newMockProvider("gemini"), replies: []string{"As an AI, I cannot", "Hello!"}}
+	svc := &ChatService{openaiProvider: newMockProvider("openai"), geminiProvider: gemini, anthropicProvider: newMockProvider("anthropic")}
+	cfg := failoverTenant("openai", "gemini")
+	cfg.Validation = tenant.ValidationConfig{BannedStrings: []string{"as an ai"}}
+	ctx := ctxWithChatPermissionAndTenant("test-client", cfg)
+	req := &pb.GenerateReplyRequest{UserInput: "Hello", PreferredProvider: pb.Provider_PROVIDER_OPENAI, EnableFailover: true}
+	prepared, err := svc.prepareRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("prepareRequest failed: %v", err)
+	}
+
+	primary := []db.ValidationAttempt{{Attempt: 1, Provider: "openai", Problems: []string{"contains banned string"}}}
+	resp, attempts := svc.generateWithFailover(ctx, req, prepared, errors.New("validation failed"), 0, primary)
+	if resp == nil || resp.Text != "Hello!" {
+		t.Fatalf("resp = %+v, want gemini's regenerated reply", resp)
+	}
+	if len(attempts) != 2 || attempts[0].Provider != "openai" || attempts[1].Provider != "gemini" || attempts[1].Response != "As an AI, I cannot" {
+		t.Errorf("attempts = %+v, want the primary's and gemini's rejected replies", attempts)
+	}
+}
+
+func TestValidateReply_SkipsToolCalls(t *testing.T) {
+	svc := &ChatService{}
+	ctx := ctxWithChatPermissionAndTenant("test-client", validatingTenant(tenant.ValidationConfig{NonEmpty: true}))
+	result := provider.GenerateResult{ToolCalls: []provider.ToolCall{{ID: "call-1", Name: "lookup"}}}
+
+	got, attempts, err := svc.validateReply(ctx, newMockProvider("openai"), provider.GenerateParams{}, result)
+	if err != nil || len(attempts) != 0 || len(got.ToolCalls) != 1 {
+		t.Errorf("validateReply = %+v, %v, %v; want tool call reply unchanged", got, attempts, err)
+	}
+}
@@ -0,0 +1,22 @@
+package tenant
+
+// AnomalyConfig enables detection of unusual usage by the tenant's client
+// keys: hourly token usage SpikeFactor times the key's average over the last
+// day, an hourly error rate of MaxErrorRate or more, and use at an hour of the
+// day the key was not used at in the last week. Anomalies are logged and,
+// with an alert webhook, posted there. Zero values use the defaults.
+type AnomalyConfig struct {
+	Enabled      bool    `json:"enabled" yaml:"enabled"`
+	SpikeFactor  float64 `json:"spike_factor,omitempty" yaml:"spike_factor,omitempty"`     // Defaults to 10
+	MaxErrorRate float64 `json:"max_error_rate,omitempty" yaml:"max_error_rate,omitempty"` // 0-1; defaults to 0.5
+	SkipOddHours bool    `json:"skip_odd_hours,omitempty" yaml:"skip_odd_hours,omitempty"` // For keys used around the clock
+	AlertWebhook string  `json:"alert_webhook,omitempty" yaml:"alert_webhook,omitempty"`   // http(s) URL; defaults to slo.alert_webhook
+}
+
+// AnomalyAlertURL returns the webhook anomaly alerts are posted to, if any.
+func (c TenantConfig) AnomalyAlertURL() string {
+	if c.Anomaly.AlertWebhook != "" {
+		return c.Anomaly.AlertWebhook
+	}
+	return c.SLO.AlertWebhook
+}
@@ -0,0 +1,12 @@
+package tenant
+
+// AttributionConfig adds attribution metadata to the tenant's replies: the
+// model and provider that generated them, when, and a hash of the request,
+// so downstream systems can show which content was generated and by which
+// configuration. With a signing key the metadata is signed with HMAC-SHA256,
+// so it cannot be forged without the key.
+type AttributionConfig struct {
+	Enabled    bool   `json:"enabled" yaml:"enabled"`
+	HTMLSuffix bool   `json:"html_suffix,omitempty" yaml:"html_suffix,omitempty"` // Also append it to html_content as an HTML comment
+	SigningKey string `json:"signing_key,omitempty" yaml:"signing_key,omitempty"` // ENV=, FILE= or inline; unsigned when empty
+}
@@ -1,16 +1,45 @@
 package tenant
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ai8future/airborne/internal/egress"
+	"github.com/ai8future/airborne/internal/provider"
+)
 
 // TenantConfig defines per-tenant overrides loaded from JSON/YAML files.
 type TenantConfig struct {
-	TenantID        string                    `json:"tenant_id" yaml:"tenant_id"`
-	DisplayName     string                    `json:"display_name" yaml:"display_name"`
-	Providers       map[string]ProviderConfig `json:"providers" yaml:"providers"`
-	RateLimits      RateLimitConfig           `json:"rate_limits" yaml:"rate_limits"`
-	Failover        FailoverConfig            `json:"failover" yaml:"failover"`
-	ImageGeneration ImageGenerationConfig     `json:"image_generation" yaml:"image_generation"`
-	Metadata        map[string]string         `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	TenantID        string                      `json:"tenant_id" yaml:"tenant_id"`
+	DisplayName     string                      `json:"display_name" yaml:"display_name"`
+	Providers       map[string]ProviderConfig   `json:"providers" yaml:"providers"`
+	RateLimits      RateLimitConfig             `json:"rate_limits" yaml:"rate_limits"`
+	Failover        FailoverConfig              `json:"failover" yaml:"failover"`
+	ImageGeneration ImageGenerationConfig       `json:"image_generation" yaml:"image_generation"`
+	EventStream     EventStreamConfig           `json:"event_stream" yaml:"event_stream"`
+	Budget          BudgetConfig                `json:"budget" yaml:"budget"`
+	Validation      ValidationConfig            `json:"validation" yaml:"validation"`
+	Embedding       EmbeddingConfig             `json:"embedding" yaml:"embedding"`
+	Safety          SafetyConfig                `json:"safety" yaml:"safety"`
+	Uploads         UploadLimits                `json:"uploads" yaml:"uploads"`
+	Redaction       RedactionPolicy             `json:"redaction" yaml:"redaction"`
+	SLO             SLOConfig                   `json:"slo" yaml:"slo"`
+	Anomaly         AnomalyConfig               `json:"anomaly" yaml:"anomaly"`
+	Privacy         PrivacyConfig               `json:"privacy" yaml:"privacy"`
+	Retrieval       RetrievalConfig             `json:"retrieval" yaml:"retrieval"`
+	Grounding       GroundingPolicy             `json:"grounding" yaml:"grounding"`
+	Attribution     AttributionConfig           `json:"attribution" yaml:"attribution"`
+	QueryDatabase   QueryDatabaseConfig         `json:"query_database" yaml:"query_database"`
+	FetchURL        FetchURLConfig              `json:"fetch_url" yaml:"fetch_url"`
+	DateContext     DateContextConfig           `json:"date_context" yaml:"date_context"`
+	Tiering         TieringConfig               `json:"tiering" yaml:"tiering"`
+	Judge           map[string]JudgePolicy      `json:"judge,omitempty" yaml:"judge,omitempty"`               // Use case -> judge policy; "*" applies to other use cases
+	Presets         map[string]GenerationPreset `json:"presets,omitempty" yaml:"presets,omitempty"`           // Name -> preset requests select, e.g. "precise"
+	RemoteTools     map[string]RemoteTool       `json:"remote_tools,omitempty" yaml:"remote_tools,omitempty"` // Tool name -> tool the server executes itself
+	LocalTools      []string                    `json:"local_tools,omitempty" yaml:"local_tools,omitempty"`   // Local tools offered, e.g. "calculator"
+	Features        map[string]bool             `json:"features,omitempty" yaml:"features,omitempty"`         // Feature flag -> enabled; unset flags are enabled
+	Proxy           *egress.ProxyConfig         `json:"proxy,omitempty" yaml:"proxy,omitempty"`               // Outbound proxy; overrides the server-wide proxy
+	Metadata        map[string]string           `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 // ImageGenerationConfig holds settings for AI image generation.
@@ -23,6 +52,119 @@ type ImageGenerationConfig struct {
 	MaxImages       int      `json:"max_images,omitempty" yaml:"max_images,omitempty"`
 }
 
+// EventStreamConfig controls publishing of completed generations to the
+// Kafka/NATS event stream. Events carry usage metadata only unless
+// IncludeContent is set.
+type EventStreamConfig struct {
+	Enabled        bool `json:"enabled" yaml:"enabled"`
+	IncludeContent bool `json:"include_content,omitempty" yaml:"include_content,omitempty"` // Add user input and response text
+}
+
+// PrivacyConfig controls how the tenant's user content, such as prompts,
+// appears in server logs. Stored conversations are not affected.
+type PrivacyConfig struct {
+	LogContent string `json:"log_content,omitempty" yaml:"log_content,omitempty"` // "full" (default), "truncate" or "hash"
+}
+
+// UploadLimits caps a tenant's file uploads so one tenant cannot starve
+// others of storage. Zero leaves a limit at the server default: the server's
+// per-file maximum, and no file count or storage limit. An empty
+// AllowedTypes accepts any non-executable file whose content matches its type.
+// MirrorInternal mirrors every OpenAI and Gemini upload into the internal
+// store with the same ID, as if each upload set mirror_internal.
+type UploadLimits struct {
+	MaxFileBytes     int64    `json:"max_file_bytes,omitempty" yaml:"max_file_bytes,omitempty"`           // Per file; capped at the server maximum
+	MaxFilesPerStore int      `json:"max_files_per_store,omitempty" yaml:"max_files_per_store,omitempty"` // Files uploaded to one store
+	MaxStorageBytes  int64    `json:"max_storage_bytes,omitempty" yaml:"max_storage_bytes,omitempty"`     // Across all of the tenant's stores
+	AllowedTypes     []string `json:"allowed_types,omitempty" yaml:"allowed_types,omitempty"`             // MIME types or "type/*", e.g. [application/pdf, text/*]
+	MirrorInternal   bool     `json:"mirror_internal,omitempty" yaml:"mirror_internal,omitempty"`
+}
+
+// BudgetConfig sets a monthly spend budget. Once spend crosses
+// DowngradePercent of MonthlyUSD, requests are served by the cheaper model
+// configured for their provider instead of being rejected.
+type BudgetConfig struct {
+	MonthlyUSD       float64           `json:"monthly_usd,omitempty" yaml:"monthly_usd,omitempty"`             // 0 disables budget tracking
+	DowngradePercent float64           `json:"downgrade_percent,omitempty" yaml:"downgrade_percent,omitempty"` // e.g. 80; defaults to 100
+	DowngradeModels  map[string]string `json:"downgrade_models,omitempty" yaml:"downgrade_models,omitempty"`   // provider -> cheaper model, e.g. openai: gpt-4o-mini
+}
+
+// DowngradeThresholdUSD returns the spend at which requests are downgraded,
+// or 0 when no budget is configured.
+func (b BudgetConfig) DowngradeThresholdUSD() float64 {
+	if b.MonthlyUSD <= 0 {
+		return 0
+	}
+	pct := b.DowngradePercent
+	if pct <= 0 {
+		pct = 100
+	}
+	return b.MonthlyUSD * pct / 100
+}
+
+// ValidationConfig sets checks every reply must pass. A reply that fails is
+// regenerated with a corrective instruction up to MaxRetries times before the
+// request fails.
+type ValidationConfig struct {
+	NonEmpty      bool           `json:"non_empty,omitempty" yaml:"non_empty,omitempty"`
+	RequireJSON   bool           `json:"require_json,omitempty" yaml:"require_json,omitempty"`
+	JSONSchema    map[string]any `json:"json_schema,omitempty" yaml:"json_schema,omitempty"`       // Subset of JSON Schema; implies require_json
+	BannedStrings []string       `json:"banned_strings,omitempty" yaml:"banned_strings,omitempty"` // Case-insensitive
+	MaxRetries    int            `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`       // Defaults to 2
+}
+
+// Retries returns how many times a failing reply is regenerated.
+func (v ValidationConfig) Retries() int {
+	if v.MaxRetries <= 0 {
+		return 2
+	}
+	return v.MaxRetries
+}
+
+// EmbeddingConfig selects the provider behind the Embed RPC. OpenAI and
+// Gemini use the API key of the tenant's provider of the same name; "ollama"
+// uses the server's self-hosted RAG embedder.
+type EmbeddingConfig struct {
+	Provider   string `json:"provider,omitempty" yaml:"provider,omitempty"`     // "openai", "gemini" or "ollama"; defaults to "ollama"
+	Model      string `json:"model,omitempty" yaml:"model,omitempty"`           // e.g., "text-embedding-3-small"; provider default if empty
+	Dimensions int    `json:"dimensions,omitempty" yaml:"dimensions,omitempty"` // Shortened vector size (OpenAI/Gemini only)
+	BaseURL    string `json:"base_url,omitempty" yaml:"base_url,omitempty"`     // Override the provider's embeddings API URL
+}
+
+// SafetyConfig sets a safety threshold per harm category: "block_none",
+// "block_only_high", "block_medium_and_above" or "block_low_and_above".
+// Empty leaves the provider default. Requests may tighten but never loosen
+// these thresholds.
+type SafetyConfig struct {
+	Harassment       string `json:"harassment,omitempty" yaml:"harassment,omitempty"`
+	HateSpeech       string `json:"hate_speech,omitempty" yaml:"hate_speech,omitempty"`
+	SexuallyExplicit string `json:"sexually_explicit,omitempty" yaml:"sexually_explicit,omitempty"`
+	DangerousContent string `json:"dangerous_content,omitempty" yaml:"dangerous_content,omitempty"`
+}
+
+// Settings parses the configured thresholds.
+func (s SafetyConfig) Settings() (provider.SafetySettings, error) {
+	var out provider.SafetySettings
+	fields := []struct {
+		name  string
+		value string
+		dst   *provider.SafetyThreshold
+	}{
+		{provider.HarmHarassment, s.Harassment, &out.Harassment},
+		{provider.HarmHateSpeech, s.HateSpeech, &out.HateSpeech},
+		{provider.HarmSexuallyExplicit, s.SexuallyExplicit, &out.SexuallyExplicit},
+		{provider.HarmDangerousContent, s.DangerousContent, &out.DangerousContent},
+	}
+	for _, f := range fields {
+		t, err := provider.ParseSafetyThreshold(f.value)
+		if err != nil {
+			return provider.SafetySettings{}, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = t
+	}
+	return out, nil
+}
+
 // ProviderConfig holds per-tenant provider settings.
 type ProviderConfig struct {
 	Enabled         bool              `json:"enabled" yaml:"enabled"`
@@ -33,6 +175,7 @@ type ProviderConfig struct {
 	MaxOutputTokens *int              `json:"max_output_tokens,omitempty" yaml:"max_output_tokens,omitempty"`
 	BaseURL         string            `json:"base_url,omitempty" yaml:"base_url,omitempty"`
 	ExtraOptions    map[string]string `json:"extra_options,omitempty" yaml:"extra_options,omitempty"`
+	AllowedModels   []string          `json:"allowed_models,omitempty" yaml:"allowed_models,omitempty"` // Models requests may override to (empty = any); trailing "*" is a wildcard
 }
 
 // RateLimitConfig holds per-tenant rate limits.
@@ -44,8 +187,34 @@ type RateLimitConfig struct {
 
 // FailoverConfig holds per-tenant failover settings.
 type FailoverConfig struct {
-	Enabled bool     `json:"enabled" yaml:"enabled"`
-	Order   []string `json:"order" yaml:"order"`
+	Enabled          bool     `json:"enabled" yaml:"enabled"`
+	Order            []string `json:"order" yaml:"order"`
+	MaxAttempts      int      `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`             // Fallback providers to try (0 = every remaining one in order)
+	AttemptTimeoutMs int      `json:"attempt_timeout_ms,omitempty" yaml:"attempt_timeout_ms,omitempty"` // Budget for each fallback attempt (0 = the request's own deadline)
+	Triggers         []string `json:"triggers,omitempty" yaml:"triggers,omitempty"`                     // Replies that fail over besides provider errors (see FailoverTrigger*)
+
+	// PrimaryBudgetPercent is the share of a request's deadline the primary
+	// provider may use before it is abandoned for the failover chain (0 = 70).
+	PrimaryBudgetPercent int `json:"primary_budget_percent,omitempty" yaml:"primary_budget_percent,omitempty"`
+}
+
+// Failover triggers a tenant can opt into. Each one re-sends the request to
+// another vendor, so tenants that must keep content with a single vendor
+// leave them unset.
+const (
+	FailoverTriggerSafetyBlock = "safety_block"   // Safety filters blocked the prompt or response
+	FailoverTriggerEmpty       = "empty_response" // The reply has no text, tool calls or images
+	FailoverTriggerValidation  = "validation"     // The reply still fails validation after its retries
+)
+
+// HasTrigger reports whether the tenant opted into failover on trigger.
+func (f FailoverConfig) HasTrigger(trigger string) bool {
+	for _, t := range f.Triggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
 }
 
 // GetProvider returns the provider config for a given provider name.
@@ -105,3 +105,19 @@ func TestTenantConfigDefaultProvider_NoEnabledProviders(t *testing.T) {
 		t.Fatal("expected no default provider when all disabled")
 	}
 }
+
+func TestBudgetConfigDowngradeThreshold(t *testing.T) {
+	tests := []struct {
+		budget BudgetConfig
+		want   float64
+	}{
+		{BudgetConfig{}, 0},
+		{BudgetConfig{MonthlyUSD: 200}, 200},
+		{BudgetConfig{MonthlyUSD: 200, DowngradePercent: 80}, 160},
+	}
+	for _, tt := range tests {
+		if got := tt.budget.DowngradeThresholdUSD(); got != tt.want {
+			t.Errorf("%+v: DowngradeThresholdUSD() = %v, want %v", tt.budget, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package tenant
+
+import (
+	"errors"
+	"time"
+)
+
+// DateContextConfig adds the current date and time to the instructions of
+// the tenant's requests, so models resolve relative dates such as "next
+// Friday" correctly. The time is given in the time zone of the user's
+// profile, or Timezone for users without one.
+type DateContextConfig struct {
+	Inject   bool   `json:"inject,omitempty" yaml:"inject,omitempty"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"` // IANA name, e.g. "Europe/Berlin"; defaults to UTC
+}
+
+// LoadTimezone returns the location of an IANA time zone name, or UTC for
+// an empty name. "Local" is rejected, as it depends on the server.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, errors.New("must be an IANA time zone name")
+	}
+	return time.LoadLocation(name)
+}
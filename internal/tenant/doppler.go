@@ -2,6 +2,7 @@ package tenant
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ai8future/airborne/internal/config/schema"
 )
 
 // dopplerClient fetches secrets from Doppler API.
@@ -222,7 +225,11 @@ func LoadTenantsFromDoppler() (map[string]TenantConfig, error) {
 		// Parse the JSON config
 		var cfg TenantConfig
 		if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
-			return nil, fmt.Errorf("parse AIRBORNE_TENANT_CONFIG from %s: %w", brandProject, err)
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, fmt.Errorf("parse AIRBORNE_TENANT_CONFIG from %s: %w", brandProject, err)
+			}
+			// Type mismatches are reported by path below
 		}
 
 		// Normalize tenant ID
@@ -233,9 +240,13 @@ func LoadTenantsFromDoppler() (map[string]TenantConfig, error) {
 			return nil, fmt.Errorf("tenant_id missing in AIRBORNE_TENANT_CONFIG from %s", brandProject)
 		}
 
+		if err := schema.CheckJSON([]byte(configJSON), &cfg); err != nil {
+			return nil, fmt.Errorf("invalid AIRBORNE_TENANT_CONFIG from %s:\n%w", brandProject, schema.Prefix(tenantPath(cfg.TenantID), err))
+		}
+
 		// Validate tenant config
 		if err := validateTenantConfig(&cfg); err != nil {
-			return nil, fmt.Errorf("validating tenant from %s: %w", brandProject, err)
+			return nil, fmt.Errorf("invalid AIRBORNE_TENANT_CONFIG from %s:\n%w", brandProject, schema.Prefix(tenantPath(cfg.TenantID), err))
 		}
 
 		result[cfg.TenantID] = cfg
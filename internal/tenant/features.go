@@ -0,0 +1,39 @@
+package tenant
+
+import "slices"
+
+// Feature flags gate optional request features per tenant, so a feature can
+// be rolled out tenant by tenant. Flags a tenant does not set are enabled.
+const (
+	FeatureStructuredOutput = "structured_output"
+	FeatureImageGeneration  = "image_generation"
+	FeatureCodeExecution    = "code_execution"
+	FeatureRAG              = "rag"
+
+	// FeatureConversationAnalytics gates the background analysis of the
+	// tenant's stored conversations rather than a request feature.
+	FeatureConversationAnalytics = "conversation_analytics"
+)
+
+// Features lists the known feature flags.
+var Features = []string{
+	FeatureStructuredOutput,
+	FeatureImageGeneration,
+	FeatureCodeExecution,
+	FeatureRAG,
+	FeatureConversationAnalytics,
+}
+
+// KnownFeature reports whether name is a known feature flag.
+func KnownFeature(name string) bool {
+	return slices.Contains(Features, name)
+}
+
+// FeatureEnabled reports whether the tenant has the feature flag enabled.
+// Image generation additionally needs image_generation to be configured.
+func (c TenantConfig) FeatureEnabled(name string) bool {
+	if enabled, ok := c.Features[name]; ok {
+		return enabled
+	}
+	return true
+}
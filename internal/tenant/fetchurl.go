@@ -0,0 +1,45 @@
+package tenant
+
+import "time"
+
+// FetchURLToolName is the name the built-in fetch tool is offered to the
+// model under.
+const FetchURLToolName = "fetch_url"
+
+// Fetch tool limits and defaults.
+const (
+	DefaultFetchBytes   = 1 << 20
+	MaxFetchBytes       = 5 << 20
+	DefaultFetchTimeout = 10 * time.Second
+	MaxFetchTimeout     = 30 * time.Second
+)
+
+// FetchURLConfig enables the built-in fetch_url tool, which GETs a page on
+// an allowed domain and returns its text to the model. It is offered when
+// any domains are allowed and the request does not use provider web search.
+type FetchURLConfig struct {
+	AllowedDomains []string `json:"allowed_domains,omitempty" yaml:"allowed_domains,omitempty"` // Hostnames, or "*.example.com" for subdomains
+	MaxBytes       int      `json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"`             // Largest page fetched; defaults to 1 MB, at most 5 MB
+	TimeoutMs      int      `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`           // Per fetch, including redirects; defaults to 10s, at most 30s
+}
+
+// Enabled reports whether the tool is offered.
+func (c FetchURLConfig) Enabled() bool {
+	return len(c.AllowedDomains) > 0
+}
+
+// BodyLimit returns the largest response body fetched.
+func (c FetchURLConfig) BodyLimit() int {
+	if c.MaxBytes <= 0 {
+		return DefaultFetchBytes
+	}
+	return min(c.MaxBytes, MaxFetchBytes)
+}
+
+// Timeout returns the time allowed for each fetch.
+func (c FetchURLConfig) Timeout() time.Duration {
+	if c.TimeoutMs <= 0 {
+		return DefaultFetchTimeout
+	}
+	return min(time.Duration(c.TimeoutMs)*time.Millisecond, MaxFetchTimeout)
+}
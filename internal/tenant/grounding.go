@@ -0,0 +1,58 @@
+package tenant
+
+// Grounding actions: what happens to a reply scored below the threshold.
+const (
+	// GroundingActionDisclaimer appends the policy's disclaimer (the default).
+	GroundingActionDisclaimer = "disclaimer"
+
+	// GroundingActionFallback replaces the reply with the policy's fallback.
+	GroundingActionFallback = "fallback"
+)
+
+// Default texts for replies that fail the grounding check.
+const (
+	DefaultGroundingDisclaimer = "Note: parts of this answer may not be supported by the available documents. Please verify it against the sources."
+	DefaultGroundingFallback   = "I don't know. The available documents do not answer this question."
+)
+
+// GroundingPolicy checks replies generated with RAG context against the
+// retrieved chunks. A verifier model scores how well the chunks support the
+// reply, from 0 (unsupported) to 1 (fully supported); replies scored below
+// Threshold get a disclaimer or are replaced with a fallback answer.
+type GroundingPolicy struct {
+	Threshold  float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`   // Minimum support score, 0-1; 0 disables the check
+	Action     string  `json:"action,omitempty" yaml:"action,omitempty"`         // "disclaimer" (default) or "fallback"
+	Disclaimer string  `json:"disclaimer,omitempty" yaml:"disclaimer,omitempty"` // Appended to ungrounded replies
+	Fallback   string  `json:"fallback,omitempty" yaml:"fallback,omitempty"`     // Replaces ungrounded replies
+	Provider   string  `json:"provider,omitempty" yaml:"provider,omitempty"`     // Verifier provider; defaults to the one that replied
+	Model      string  `json:"model,omitempty" yaml:"model,omitempty"`           // Verifier model; defaults to the provider's configured model
+}
+
+// Enabled reports whether replies are checked.
+func (p GroundingPolicy) Enabled() bool {
+	return p.Threshold > 0
+}
+
+// ActionName returns the action taken on ungrounded replies.
+func (p GroundingPolicy) ActionName() string {
+	if p.Action == "" {
+		return GroundingActionDisclaimer
+	}
+	return p.Action
+}
+
+// DisclaimerText returns the disclaimer appended to ungrounded replies.
+func (p GroundingPolicy) DisclaimerText() string {
+	if p.Disclaimer == "" {
+		return DefaultGroundingDisclaimer
+	}
+	return p.Disclaimer
+}
+
+// FallbackText returns the answer that replaces ungrounded replies.
+func (p GroundingPolicy) FallbackText() string {
+	if p.Fallback == "" {
+		return DefaultGroundingFallback
+	}
+	return p.Fallback
+}
@@ -0,0 +1,49 @@
+package tenant
+
+// Judge policy limits and defaults.
+const (
+	// JudgeUseCaseKey is the request metadata key naming the use case whose
+	// judge policy applies.
+	JudgeUseCaseKey = "use_case"
+
+	// JudgeDefaultUseCase keys the policy for requests whose use case has no
+	// policy of its own.
+	JudgeDefaultUseCase = "*"
+
+	// DefaultJudgeCandidates is how many replies are generated when a policy
+	// does not set candidates.
+	DefaultJudgeCandidates = 3
+
+	// MaxJudgeCandidates bounds the replies generated for one request.
+	MaxJudgeCandidates = 5
+)
+
+// JudgePolicy enables self-consistency scoring for a use case: several
+// candidate replies are generated, a judge model scores each against
+// Criteria, and the best-scoring reply is returned.
+type JudgePolicy struct {
+	Candidates int    `json:"candidates,omitempty" yaml:"candidates,omitempty"` // Replies generated per request, 2-5; defaults to 3
+	Criteria   string `json:"criteria" yaml:"criteria"`                         // What the judge scores replies against
+	Provider   string `json:"provider,omitempty" yaml:"provider,omitempty"`     // Judge provider; defaults to the one that generated the replies
+	Model      string `json:"model,omitempty" yaml:"model,omitempty"`           // Judge model; defaults to the provider's configured model
+}
+
+// CandidateCount returns how many replies are generated per request.
+func (p JudgePolicy) CandidateCount() int {
+	if p.Candidates <= 0 {
+		return DefaultJudgeCandidates
+	}
+	return min(p.Candidates, MaxJudgeCandidates)
+}
+
+// JudgePolicy returns the judge policy for a use case: its own, else the
+// tenant's default policy. ok is false when neither is configured.
+func (c TenantConfig) JudgePolicy(useCase string) (policy JudgePolicy, ok bool) {
+	if useCase != "" {
+		if policy, ok = c.Judge[useCase]; ok {
+			return policy, true
+		}
+	}
+	policy, ok = c.Judge[JudgeDefaultUseCase]
+	return policy, ok
+}
@@ -4,13 +4,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/ai8future/airborne/internal/config/schema"
+	"github.com/ai8future/airborne/internal/localtools"
+	"github.com/ai8future/airborne/internal/logctx"
+	"github.com/ai8future/airborne/internal/validation"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrNoTenantConfigs is returned when the configs directory holds no tenant
+// configs; the server then runs in single-tenant legacy mode.
+var ErrNoTenantConfigs = errors.New("no tenant configs found")
+
 // loadTenants loads all tenant configurations from the given directory.
 // Supports both JSON (.json) and YAML (.yaml, .yml) files.
 func loadTenants(dir string) (map[string]TenantConfig, error) {
@@ -44,16 +56,21 @@ func loadTenantsInternal(dir string, resolveSecretsFlag bool) (map[string]Tenant
 			return nil, fmt.Errorf("reading %s: %w", path, err)
 		}
 
+		// Type mismatches are tolerated here and reported by path below
 		var cfg TenantConfig
+		var typeErr bool
 		switch ext {
 		case ".json":
-			if err := json.Unmarshal(raw, &cfg); err != nil {
-				return nil, fmt.Errorf("decoding %s: %w", path, err)
-			}
+			err = json.Unmarshal(raw, &cfg)
+			var jsonTypeErr *json.UnmarshalTypeError
+			typeErr = errors.As(err, &jsonTypeErr)
 		case ".yaml", ".yml":
-			if err := yaml.Unmarshal(raw, &cfg); err != nil {
-				return nil, fmt.Errorf("decoding %s: %w", path, err)
-			}
+			err = yaml.Unmarshal(raw, &cfg)
+			var yamlTypeErr *yaml.TypeError
+			typeErr = errors.As(err, &yamlTypeErr)
+		}
+		if err != nil && !typeErr {
+			return nil, fmt.Errorf("decoding %s: %w", path, err)
 		}
 
 		// Normalize tenant ID to lowercase
@@ -64,6 +81,15 @@ func loadTenantsInternal(dir string, resolveSecretsFlag bool) (map[string]Tenant
 			continue
 		}
 
+		// Report unknown keys and type mismatches by path
+		check := schema.CheckYAML
+		if ext == ".json" {
+			check = schema.CheckJSON
+		}
+		if err := check(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid tenant config %s:\n%w", path, schema.Prefix(tenantPath(cfg.TenantID), err))
+		}
+
 		// Resolve secrets (ENV=, FILE= patterns) if requested
 		if resolveSecretsFlag {
 			if err := resolveSecrets(&cfg); err != nil {
@@ -73,7 +99,7 @@ func loadTenantsInternal(dir string, resolveSecretsFlag bool) (map[string]Tenant
 
 		// Validate (skip secret validation if not resolving)
 		if err := validateTenantConfig(&cfg); err != nil {
-			return nil, fmt.Errorf("validating %s: %w", path, err)
+			return nil, fmt.Errorf("invalid tenant config %s:\n%w", path, schema.Prefix(tenantPath(cfg.TenantID), err))
 		}
 
 		// Check for duplicates
@@ -85,74 +111,407 @@ func loadTenantsInternal(dir string, resolveSecretsFlag bool) (map[string]Tenant
 	}
 
 	if len(result) == 0 {
-		return nil, errors.New("no tenant configs found")
+		return nil, ErrNoTenantConfigs
 	}
 
 	return result, nil
 }
 
-// validateTenantConfig validates a tenant configuration.
+// tenantPath is the error path prefix for a tenant's settings.
+func tenantPath(tenantID string) string {
+	return "tenants." + tenantID
+}
+
+// validateTenantConfig validates a tenant configuration. Errors carry paths
+// relative to the tenant (e.g. "providers.gemini.temperature").
 func validateTenantConfig(cfg *TenantConfig) error {
+	var errs schema.Errors
+
 	// Validate tenant ID
 	if cfg.TenantID == "" {
-		return errors.New("tenant_id is required")
+		errs.Add("tenant_id", "is required")
 	}
 	if len(cfg.TenantID) > 64 {
-		return errors.New("tenant_id must be <= 64 characters")
+		errs.Add("tenant_id", "must be <= 64 characters")
 	}
 
 	// Validate at least one provider is configured and enabled
 	hasProvider := false
-	for name, pCfg := range cfg.Providers {
+	for _, name := range sortedKeys(cfg.Providers) {
+		pCfg := cfg.Providers[name]
 		if !pCfg.Enabled {
 			continue
 		}
 		hasProvider = true
+		path := "providers." + name
 
 		// Validate API key is set
 		if pCfg.APIKey == "" {
-			return fmt.Errorf("%s.api_key is required when provider is enabled", name)
+			errs.Add(path+".api_key", "is required when provider is enabled")
 		}
 
 		// Validate model is set
 		if pCfg.Model == "" {
-			return fmt.Errorf("%s.model is required when provider is enabled", name)
+			errs.Add(path+".model", "is required when provider is enabled")
 		}
 
 		// Validate temperature if set
 		if pCfg.Temperature != nil {
 			if *pCfg.Temperature < 0 || *pCfg.Temperature > 2 {
-				return fmt.Errorf("%s.temperature must be between 0 and 2", name)
+				errs.Add(path+".temperature", "must be between 0 and 2")
 			}
 		}
 
 		// Validate top_p if set
 		if pCfg.TopP != nil {
 			if *pCfg.TopP < 0 || *pCfg.TopP > 1 {
-				return fmt.Errorf("%s.top_p must be between 0 and 1", name)
+				errs.Add(path+".top_p", "must be between 0 and 1")
+			}
+		}
+
+		// Validate allowed_models entries
+		for i, m := range pCfg.AllowedModels {
+			if strings.TrimSpace(m) == "" {
+				errs.Add(fmt.Sprintf("%s.allowed_models[%d]", path, i), "must not be empty")
 			}
 		}
 
 		// Validate max_output_tokens if set
 		if pCfg.MaxOutputTokens != nil {
 			if *pCfg.MaxOutputTokens < 1 || *pCfg.MaxOutputTokens > 128000 {
-				return fmt.Errorf("%s.max_output_tokens must be between 1 and 128000", name)
+				errs.Add(path+".max_output_tokens", "must be between 1 and 128000")
 			}
 		}
 	}
 
 	if !hasProvider {
-		return errors.New("at least one provider must be enabled")
+		errs.Add("providers", "at least one provider must be enabled")
+	}
+
+	// Validate rate limits
+	if cfg.RateLimits.RequestsPerMinute < 0 {
+		errs.Add("rate_limits.rpm", "must be >= 0")
+	}
+	if cfg.RateLimits.RequestsPerDay < 0 {
+		errs.Add("rate_limits.rpd", "must be >= 0")
+	}
+	if cfg.RateLimits.TokensPerMinute < 0 {
+		errs.Add("rate_limits.tpm", "must be >= 0")
+	}
+
+	// Validate budget downgrade policy
+	if cfg.Budget.MonthlyUSD < 0 {
+		errs.Add("budget.monthly_usd", "must be >= 0")
+	}
+	if cfg.Budget.DowngradePercent < 0 || cfg.Budget.DowngradePercent > 100 {
+		errs.Add("budget.downgrade_percent", "must be between 0 and 100")
+	}
+	for _, name := range sortedKeys(cfg.Budget.DowngradeModels) {
+		path := "budget.downgrade_models." + name
+		if _, ok := cfg.Providers[name]; !ok {
+			errs.Add(path, "references unknown provider %q", name)
+		}
+		if strings.TrimSpace(cfg.Budget.DowngradeModels[name]) == "" {
+			errs.Add(path, "must not be empty")
+		}
+	}
+
+	// Validate the "auto" model tier
+	for _, name := range sortedKeys(cfg.Tiering.CheapModels) {
+		path := "tiering.cheap_models." + name
+		if _, ok := cfg.Providers[name]; !ok {
+			errs.Add(path, "references unknown provider %q", name)
+		}
+		if _, ok := cfg.Tiering.CheapModel(name); !ok {
+			errs.Add(path, "must not be empty")
+		}
+	}
+	if cfg.Tiering.Default && len(cfg.Tiering.CheapModels) == 0 {
+		errs.Add("tiering.default", "requires cheap_models")
+	}
+	for i, criterion := range cfg.Tiering.EscalateOn {
+		if !ValidEscalationCriterion(criterion) {
+			errs.Add(fmt.Sprintf("tiering.escalate_on[%d]", i), "unknown criterion %q", criterion)
+		}
+	}
+	if cfg.Tiering.MinConfidence < 0 || cfg.Tiering.MinConfidence > 1 {
+		errs.Add("tiering.min_confidence", "must be between 0 and 1")
+	} else if cfg.Tiering.MinConfidence == 0 && cfg.Tiering.EscalatesOn(EscalateOnConfidence) {
+		errs.Add("tiering.min_confidence", "is required by the confidence criterion")
+	}
+	if cfg.Tiering.Provider != "" && !cfg.Providers[cfg.Tiering.Provider].Enabled {
+		errs.Add("tiering.provider", "%q must be an enabled provider", cfg.Tiering.Provider)
+	}
+
+	// Validate reply validation rules
+	if cfg.Validation.MaxRetries < 0 || cfg.Validation.MaxRetries > 5 {
+		errs.Add("validation.max_retries", "must be between 0 and 5")
+	}
+	errs.Wrap("validation.json_schema", validation.CheckSchema(cfg.Validation.JSONSchema))
+
+	// Validate embedding provider
+	switch cfg.Embedding.Provider {
+	case "", "ollama":
+		if cfg.Embedding.Dimensions != 0 {
+			errs.Add("embedding.dimensions", "is not supported for ollama")
+		}
+	case "openai", "gemini":
+		if !cfg.Providers[cfg.Embedding.Provider].Enabled {
+			errs.Add("embedding.provider", "%q must be an enabled provider", cfg.Embedding.Provider)
+		}
+	default:
+		errs.Add("embedding.provider", "must be openai, gemini or ollama, got %q", cfg.Embedding.Provider)
+	}
+	if cfg.Embedding.Dimensions < 0 {
+		errs.Add("embedding.dimensions", "must be >= 0")
+	}
+	if _, err := cfg.Safety.Settings(); err != nil {
+		// Settings errors name the harm category, e.g. "harassment: unknown threshold"
+		category, msg, ok := strings.Cut(err.Error(), ": ")
+		if ok {
+			errs.Add("safety."+category, "%s", msg)
+		} else {
+			errs.Wrap("safety", err)
+		}
+	}
+	if cfg.Proxy != nil {
+		errs.Wrap("proxy", cfg.Proxy.Validate())
+	}
+
+	// Validate upload limits
+	if cfg.Uploads.MaxFileBytes < 0 {
+		errs.Add("uploads.max_file_bytes", "must be >= 0")
+	}
+	if cfg.Uploads.MaxFilesPerStore < 0 {
+		errs.Add("uploads.max_files_per_store", "must be >= 0")
+	}
+	if cfg.Uploads.MaxStorageBytes < 0 {
+		errs.Add("uploads.max_storage_bytes", "must be >= 0")
+	}
+	errs.Wrap("uploads.allowed_types", validation.ValidateAllowedTypes(cfg.Uploads.AllowedTypes))
+	if _, err := cfg.Redaction.Compile(); err != nil {
+		errs.Wrap("redaction", err)
+	}
+	for _, useCase := range sortedKeys(cfg.Judge) {
+		policy, path := cfg.Judge[useCase], "judge."+useCase
+		if strings.TrimSpace(policy.Criteria) == "" {
+			errs.Add(path+".criteria", "is required")
+		}
+		if policy.Candidates != 0 && (policy.Candidates < 2 || policy.Candidates > MaxJudgeCandidates) {
+			errs.Add(path+".candidates", "must be between 2 and %d", MaxJudgeCandidates)
+		}
+		if policy.Provider != "" && !cfg.Providers[policy.Provider].Enabled {
+			errs.Add(path+".provider", "%q must be an enabled provider", policy.Provider)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Presets) {
+		preset, path := cfg.Presets[name], "presets."+name
+		if preset.Provider != "" && !cfg.Providers[preset.Provider].Enabled {
+			errs.Add(path+".provider", "%q must be an enabled provider", preset.Provider)
+		}
+		for _, providerName := range sortedKeys(preset.Models) {
+			if !cfg.Providers[providerName].Enabled {
+				errs.Add(path+".models."+providerName, "%q must be an enabled provider", providerName)
+			}
+			if strings.TrimSpace(preset.Models[providerName]) == "" {
+				errs.Add(path+".models."+providerName, "must not be empty")
+			}
+		}
+		if preset.Temperature != nil && (*preset.Temperature < 0 || *preset.Temperature > 2) {
+			errs.Add(path+".temperature", "must be between 0 and 2")
+		}
+		if preset.MaxOutputTokens != nil && (*preset.MaxOutputTokens < 1 || *preset.MaxOutputTokens > 128000) {
+			errs.Add(path+".max_output_tokens", "must be between 1 and 128000")
+		}
+		if !ValidReasoningEffort(preset.ReasoningEffort) {
+			errs.Add(path+".reasoning_effort", "must be none, low, medium or high, got %q", preset.ReasoningEffort)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.RemoteTools) {
+		tool, path := cfg.RemoteTools[name], "remote_tools."+name
+		if !ValidRemoteToolName(name) {
+			errs.Add(path, "name must be 1-64 letters, digits, underscores or hyphens")
+		}
+		if strings.TrimSpace(tool.Description) == "" {
+			errs.Add(path+".description", "is required")
+		}
+		if u, err := url.Parse(tool.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			errs.Add(path+".url", "must be an https URL")
+		}
+		if _, err := json.Marshal(tool.Parameters); err != nil {
+			errs.Add(path+".parameters", "must be a JSON Schema object: %v", err)
+		}
+		if tool.TimeoutMs < 0 || time.Duration(tool.TimeoutMs)*time.Millisecond > MaxRemoteToolTimeout {
+			errs.Add(path+".timeout_ms", "must be between 0 and %d", MaxRemoteToolTimeout.Milliseconds())
+		}
+		if tool.MaxRetries != nil && (*tool.MaxRetries < 0 || *tool.MaxRetries > MaxRemoteToolRetries) {
+			errs.Add(path+".max_retries", "must be between 0 and %d", MaxRemoteToolRetries)
+		}
+		if tool.MaxResponseBytes < 0 || tool.MaxResponseBytes > MaxRemoteToolResponseBytes {
+			errs.Add(path+".max_response_bytes", "must be between 0 and %d", MaxRemoteToolResponseBytes)
+		}
+	}
+
+	for i, name := range cfg.LocalTools {
+		if _, ok := localtools.Lookup(name); !ok {
+			errs.Add(fmt.Sprintf("local_tools[%d]", i), "must be one of %s, got %q", strings.Join(localtools.Names(), ", "), name)
+		} else if _, ok := cfg.RemoteTools[name]; ok {
+			errs.Add("remote_tools."+name, "name is used by a local tool")
+		}
+	}
+	if _, ok := cfg.RemoteTools[QueryDatabaseToolName]; ok && cfg.QueryDatabase.Enabled() {
+		errs.Add("remote_tools."+QueryDatabaseToolName, "name is used by the query_database tool")
+	}
+	for _, name := range sortedKeys(cfg.QueryDatabase.Databases) {
+		db, path := cfg.QueryDatabase.Databases[name], "query_database.databases."+name
+		if !ValidRemoteToolName(name) {
+			errs.Add(path, "name must be 1-64 letters, digits, underscores or hyphens")
+		}
+		if db.DSN == "" {
+			errs.Add(path+".dsn", "is required")
+		} else if !isSecretReference(db.DSN) {
+			if u, err := url.Parse(db.DSN); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+				errs.Add(path+".dsn", "must be a postgres:// URL")
+			}
+		}
+		if len(db.Schemas) == 0 {
+			errs.Add(path+".schemas", "is required")
+		}
+		for _, schema := range db.Schemas {
+			if !ValidSchemaName(schema) {
+				errs.Add(path+".schemas", "%q is not a lowercase schema name", schema)
+			}
+		}
+	}
+	if cfg.QueryDatabase.MaxRows < 0 || cfg.QueryDatabase.MaxRows > MaxQueryRows {
+		errs.Add("query_database.max_rows", "must be between 0 and %d", MaxQueryRows)
+	}
+	if cfg.QueryDatabase.TimeoutMs < 0 || time.Duration(cfg.QueryDatabase.TimeoutMs)*time.Millisecond > MaxQueryTimeout {
+		errs.Add("query_database.timeout_ms", "must be between 0 and %d", MaxQueryTimeout.Milliseconds())
+	}
+
+	if _, ok := cfg.RemoteTools[FetchURLToolName]; ok && cfg.FetchURL.Enabled() {
+		errs.Add("remote_tools."+FetchURLToolName, "name is used by the fetch_url tool")
+	}
+	for _, domain := range cfg.FetchURL.AllowedDomains {
+		host := strings.TrimPrefix(domain, "*.")
+		if host == "" || strings.ContainsAny(host, "/:@* ") || net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+			errs.Add("fetch_url.allowed_domains", "%q is not a domain name", domain)
+		}
+	}
+	if cfg.FetchURL.MaxBytes < 0 || cfg.FetchURL.MaxBytes > MaxFetchBytes {
+		errs.Add("fetch_url.max_bytes", "must be between 0 and %d", MaxFetchBytes)
+	}
+	if cfg.FetchURL.TimeoutMs < 0 || time.Duration(cfg.FetchURL.TimeoutMs)*time.Millisecond > MaxFetchTimeout {
+		errs.Add("fetch_url.timeout_ms", "must be between 0 and %d", MaxFetchTimeout.Milliseconds())
+	}
+
+	if !ValidRetrievalQueryMode(cfg.Retrieval.QueryMode) {
+		errs.Add("retrieval.query_mode", "must be latest, recent or condense, got %q", cfg.Retrieval.QueryMode)
+	}
+	if cfg.Retrieval.HistoryTurns < 0 || cfg.Retrieval.HistoryTurns > MaxRetrievalHistoryTurns {
+		errs.Add("retrieval.history_turns", "must be between 0 and %d", MaxRetrievalHistoryTurns)
+	}
+	if cfg.Retrieval.ContextTokens < 0 {
+		errs.Add("retrieval.context_tokens", "must not be negative")
+	}
+	if cfg.Retrieval.SourceTokens < 0 {
+		errs.Add("retrieval.source_tokens", "must not be negative")
+	}
+	if cfg.Retrieval.Provider != "" && !cfg.Providers[cfg.Retrieval.Provider].Enabled {
+		errs.Add("retrieval.provider", "%q must be an enabled provider", cfg.Retrieval.Provider)
+	}
+
+	if cfg.Grounding.Threshold < 0 || cfg.Grounding.Threshold > 1 {
+		errs.Add("grounding.threshold", "must be between 0 and 1")
+	}
+	switch cfg.Grounding.Action {
+	case "", GroundingActionDisclaimer, GroundingActionFallback:
+	default:
+		errs.Add("grounding.action", "must be disclaimer or fallback, got %q", cfg.Grounding.Action)
+	}
+	if cfg.Grounding.Provider != "" && !cfg.Providers[cfg.Grounding.Provider].Enabled {
+		errs.Add("grounding.provider", "%q must be an enabled provider", cfg.Grounding.Provider)
+	}
+
+	switch cfg.Privacy.LogContent {
+	case "", logctx.ContentFull, logctx.ContentTruncate, logctx.ContentHash:
+	default:
+		errs.Add("privacy.log_content", "must be full, truncate or hash, got %q", cfg.Privacy.LogContent)
+	}
+
+	if _, err := LoadTimezone(cfg.DateContext.Timezone); err != nil {
+		errs.Add("date_context.timezone", "must be an IANA time zone name, got %q", cfg.DateContext.Timezone)
+	}
+
+	// Validate latency objectives
+	for _, slo := range []struct {
+		path      string
+		objective LatencyObjective
+	}{{"slo.first_token", cfg.SLO.FirstToken}, {"slo.completion", cfg.SLO.Completion}} {
+		if slo.objective.Percentile < 0 || slo.objective.Percentile >= 100 {
+			errs.Add(slo.path+".percentile", "must be between 0 and 100")
+		}
+		if slo.objective.ThresholdMs < 0 {
+			errs.Add(slo.path+".threshold_ms", "must be >= 0")
+		}
+	}
+	if url := cfg.SLO.AlertWebhook; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		errs.Add("slo.alert_webhook", "must be an http or https URL")
+	}
+	if cfg.Anomaly.SpikeFactor < 0 || (cfg.Anomaly.SpikeFactor > 0 && cfg.Anomaly.SpikeFactor <= 1) {
+		errs.Add("anomaly.spike_factor", "must be greater than 1")
+	}
+	if cfg.Anomaly.MaxErrorRate < 0 || cfg.Anomaly.MaxErrorRate > 1 {
+		errs.Add("anomaly.max_error_rate", "must be between 0 and 1")
+	}
+	if url := cfg.Anomaly.AlertWebhook; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		errs.Add("anomaly.alert_webhook", "must be an http or https URL")
+	}
+
+	// Validate feature flags
+	for _, name := range sortedKeys(cfg.Features) {
+		if !KnownFeature(name) {
+			errs.Add("features."+name, "unknown feature flag, must be one of %s", strings.Join(Features, ", "))
+		}
 	}
 
 	// Validate failover order references valid providers
 	if cfg.Failover.Enabled {
-		for _, name := range cfg.Failover.Order {
+		for i, name := range cfg.Failover.Order {
 			if _, ok := cfg.Providers[name]; !ok {
-				return fmt.Errorf("failover.order references unknown provider %q", name)
+				errs.Add(fmt.Sprintf("failover.order[%d]", i), "references unknown provider %q", name)
 			}
 		}
 	}
+	if cfg.Failover.MaxAttempts < 0 {
+		errs.Add("failover.max_attempts", "must not be negative")
+	}
+	if cfg.Failover.AttemptTimeoutMs < 0 {
+		errs.Add("failover.attempt_timeout_ms", "must not be negative")
+	}
+	if p := cfg.Failover.PrimaryBudgetPercent; p < 0 || p >= 100 {
+		errs.Add("failover.primary_budget_percent", "must be between 0 and 99")
+	}
+	for i, trigger := range cfg.Failover.Triggers {
+		switch trigger {
+		case FailoverTriggerSafetyBlock, FailoverTriggerEmpty, FailoverTriggerValidation:
+		default:
+			errs.Add(fmt.Sprintf("failover.triggers[%d]", i), "unknown trigger %q", trigger)
+		}
+	}
 
-	return nil
+	return errs.Err()
+}
+
+// sortedKeys returns map keys in order so errors are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
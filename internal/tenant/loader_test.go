@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/ai8future/airborne/internal/egress"
 )
 
 func floatPtr(v float64) *float64 {
@@ -73,6 +75,57 @@ func TestValidateTenantConfig(t *testing.T) {
 		{"invalid failover provider", func(c *TenantConfig) {
 			c.Failover = FailoverConfig{Enabled: true, Order: []string{"missing"}}
 		}, true},
+		{"unknown failover trigger", func(c *TenantConfig) {
+			c.Failover = FailoverConfig{Triggers: []string{"slow_response"}}
+		}, true},
+		{"failover primary budget leaves no time for fallbacks", func(c *TenantConfig) {
+			c.Failover = FailoverConfig{Enabled: true, Order: []string{"openai"}, PrimaryBudgetPercent: 100}
+		}, true},
+		{"negative failover attempt timeout", func(c *TenantConfig) {
+			c.Failover = FailoverConfig{Enabled: true, Order: []string{"openai"}, AttemptTimeoutMs: -1}
+		}, true},
+		{"negative budget", func(c *TenantConfig) {
+			c.Budget = BudgetConfig{MonthlyUSD: -1}
+		}, true},
+		{"downgrade percent too high", func(c *TenantConfig) {
+			c.Budget = BudgetConfig{MonthlyUSD: 100, DowngradePercent: 120}
+		}, true},
+		{"downgrade model for unknown provider", func(c *TenantConfig) {
+			c.Budget = BudgetConfig{MonthlyUSD: 100, DowngradeModels: map[string]string{"gemini": "gemini-2.5-flash"}}
+		}, true},
+		{"valid budget", func(c *TenantConfig) {
+			c.Budget = BudgetConfig{MonthlyUSD: 100, DowngradePercent: 80, DowngradeModels: map[string]string{"openai": "gpt-4o-mini"}}
+		}, false},
+		{"unsupported validation schema keyword", func(c *TenantConfig) {
+			c.Validation = ValidationConfig{JSONSchema: map[string]any{"type": "object", "pattern": "^a"}}
+		}, true},
+		{"too many validation retries", func(c *TenantConfig) {
+			c.Validation = ValidationConfig{NonEmpty: true, MaxRetries: 10}
+		}, true},
+		{"valid validation rules", func(c *TenantConfig) {
+			c.Validation = ValidationConfig{NonEmpty: true, BannedStrings: []string{"lorem ipsum"}, JSONSchema: map[string]any{"type": "object", "required": []any{"answer"}}}
+		}, false},
+		{"embedding provider not enabled", func(c *TenantConfig) {
+			c.Embedding = EmbeddingConfig{Provider: "gemini"}
+		}, true},
+		{"unknown embedding provider", func(c *TenantConfig) {
+			c.Embedding = EmbeddingConfig{Provider: "cohere"}
+		}, true},
+		{"valid embedding provider", func(c *TenantConfig) {
+			c.Embedding = EmbeddingConfig{Provider: "openai", Model: "text-embedding-3-small", Dimensions: 512}
+		}, false},
+		{"unknown safety threshold", func(c *TenantConfig) {
+			c.Safety = SafetyConfig{HateSpeech: "block_everything"}
+		}, true},
+		{"valid safety thresholds", func(c *TenantConfig) {
+			c.Safety = SafetyConfig{Harassment: "block_only_high", DangerousContent: "low_and_above"}
+		}, false},
+		{"unsupported proxy scheme", func(c *TenantConfig) {
+			c.Proxy = &egress.ProxyConfig{URL: "ftp://proxy.corp.internal"}
+		}, true},
+		{"valid socks5 proxy", func(c *TenantConfig) {
+			c.Proxy = &egress.ProxyConfig{URL: "socks5://proxy.corp.internal:1080", NoProxy: []string{".corp.internal"}}
+		}, false},
 		{"valid temperature", func(c *TenantConfig) {
 			p := c.Providers["openai"]
 			p.Temperature = floatPtr(0.7)
@@ -89,7 +142,212 @@ func TestValidateTenantConfig(t *testing.T) {
 			c.Providers["openai"] = p
 		}, false},
 		{"valid failover", func(c *TenantConfig) {
-			c.Failover = FailoverConfig{Enabled: true, Order: []string{"openai"}}
+			c.Failover = FailoverConfig{Enabled: true, Order: []string{"openai"}, MaxAttempts: 2, AttemptTimeoutMs: 15000, Triggers: []string{"safety_block", "validation"}, PrimaryBudgetPercent: 60}
+		}, false},
+		{"empty allowed model", func(c *TenantConfig) {
+			p := c.Providers["openai"]
+			p.AllowedModels = []string{"gpt-4o", " "}
+			c.Providers["openai"] = p
+		}, true},
+		{"valid allowed models", func(c *TenantConfig) {
+			p := c.Providers["openai"]
+			p.AllowedModels = []string{"gpt-4o", "gpt-4o-mini*"}
+			c.Providers["openai"] = p
+		}, false},
+		{"unknown redaction pii kind", func(c *TenantConfig) {
+			c.Redaction = RedactionPolicy{PII: []string{"passport"}}
+		}, true},
+		{"invalid redaction pattern", func(c *TenantConfig) {
+			c.Redaction = RedactionPolicy{Patterns: []string{"(unclosed"}}
+		}, true},
+		{"valid redaction policy", func(c *TenantConfig) {
+			c.Redaction = RedactionPolicy{PII: []string{"email", "phone"}, Patterns: []string{`ACCT-\d+`}, HideUserID: true}
+		}, false},
+		{"judge policy without criteria", func(c *TenantConfig) {
+			c.Judge = map[string]JudgePolicy{"legal_summary": {Candidates: 3}}
+		}, true},
+		{"judge policy with too many candidates", func(c *TenantConfig) {
+			c.Judge = map[string]JudgePolicy{"legal_summary": {Candidates: 9, Criteria: "accuracy"}}
+		}, true},
+		{"judge policy with disabled provider", func(c *TenantConfig) {
+			c.Judge = map[string]JudgePolicy{"*": {Criteria: "accuracy", Provider: "anthropic"}}
+		}, true},
+		{"valid judge policy", func(c *TenantConfig) {
+			c.Judge = map[string]JudgePolicy{"legal_summary": {Candidates: 3, Criteria: "accuracy", Provider: "openai", Model: "gpt-4o"}}
+		}, false},
+		{"preset with disabled provider", func(c *TenantConfig) {
+			c.Presets = map[string]GenerationPreset{"cheap": {Provider: "anthropic"}}
+		}, true},
+		{"preset model for disabled provider", func(c *TenantConfig) {
+			c.Presets = map[string]GenerationPreset{"cheap": {Models: map[string]string{"gemini": "gemini-flash"}}}
+		}, true},
+		{"preset with unknown reasoning effort", func(c *TenantConfig) {
+			c.Presets = map[string]GenerationPreset{"precise": {ReasoningEffort: "extreme"}}
+		}, true},
+		{"valid preset", func(c *TenantConfig) {
+			temp := 0.2
+			c.Presets = map[string]GenerationPreset{"precise": {Provider: "openai", Models: map[string]string{"openai": "gpt-4o"}, Temperature: &temp, ReasoningEffort: ReasoningEffortHigh}}
+		}, false},
+		{"remote tool with invalid name", func(c *TenantConfig) {
+			c.RemoteTools = map[string]RemoteTool{"lookup order": {Description: "Looks up an order", URL: "https://tools.example.com/order"}}
+		}, true},
+		{"remote tool without description", func(c *TenantConfig) {
+			c.RemoteTools = map[string]RemoteTool{"lookup_order": {URL: "https://tools.example.com/order"}}
+		}, true},
+		{"remote tool over plain http", func(c *TenantConfig) {
+			c.RemoteTools = map[string]RemoteTool{"lookup_order": {Description: "Looks up an order", URL: "http://tools.example.com/order"}}
+		}, true},
+		{"remote tool with too many retries", func(c *TenantConfig) {
+			c.RemoteTools = map[string]RemoteTool{"lookup_order": {Description: "Looks up an order", URL: "https://tools.example.com/order", MaxRetries: intPtr(5)}}
+		}, true},
+		{"remote tool timeout too long", func(c *TenantConfig) {
+			c.RemoteTools = map[string]RemoteTool{"lookup_order": {Description: "Looks up an order", URL: "https://tools.example.com/order", TimeoutMs: 120000}}
+		}, true},
+		{"valid remote tool", func(c *TenantConfig) {
+			c.RemoteTools = map[string]RemoteTool{"lookup_order": {
+				Description: "Looks up an order",
+				Parameters:  map[string]any{"type": "object", "properties": map[string]any{"order_id": map[string]any{"type": "string"}}},
+				URL:         "https://tools.example.com/order",
+				AuthToken:   "secret",
+				TimeoutMs:   5000,
+				MaxRetries:  intPtr(0),
+			}}
+		}, false},
+		{"unknown local tool", func(c *TenantConfig) {
+			c.LocalTools = []string{"calculator", "weather"}
+		}, true},
+		{"remote tool named like a local tool", func(c *TenantConfig) {
+			c.LocalTools = []string{"calculator"}
+			c.RemoteTools = map[string]RemoteTool{"calculator": {Description: "Calculates", URL: "https://tools.example.com/calc"}}
+		}, true},
+		{"valid local tools", func(c *TenantConfig) {
+			c.LocalTools = []string{"calculator", "date_math", "convert_units", "generate_uuid"}
+		}, false},
+		{"query database without schemas", func(c *TenantConfig) {
+			c.QueryDatabase.Databases = map[string]QueryDatabase{"analytics": {DSN: "postgres://reader@db.example.com/analytics"}}
+		}, true},
+		{"query database with mysql dsn", func(c *TenantConfig) {
+			c.QueryDatabase.Databases = map[string]QueryDatabase{"analytics": {DSN: "mysql://reader@db.example.com/analytics", Schemas: []string{"public"}}}
+		}, true},
+		{"query database with quoted schema", func(c *TenantConfig) {
+			c.QueryDatabase.Databases = map[string]QueryDatabase{"analytics": {DSN: "ENV=ANALYTICS_DSN", Schemas: []string{`"Reporting"`}}}
+		}, true},
+		{"query database row limit too high", func(c *TenantConfig) {
+			c.QueryDatabase = QueryDatabaseConfig{Databases: map[string]QueryDatabase{"analytics": {DSN: "ENV=ANALYTICS_DSN", Schemas: []string{"public"}}}, MaxRows: 5000}
+		}, true},
+		{"remote tool named query_database", func(c *TenantConfig) {
+			c.QueryDatabase.Databases = map[string]QueryDatabase{"analytics": {DSN: "ENV=ANALYTICS_DSN", Schemas: []string{"public"}}}
+			c.RemoteTools = map[string]RemoteTool{"query_database": {Description: "Queries", URL: "https://tools.example.com/query"}}
+		}, true},
+		{"valid query database", func(c *TenantConfig) {
+			c.QueryDatabase = QueryDatabaseConfig{
+				Databases: map[string]QueryDatabase{"analytics": {DSN: "postgres://reader@db.example.com/analytics", Schemas: []string{"public", "reporting"}, Description: "Daily sales"}},
+				MaxRows:   500,
+				TimeoutMs: 10000,
+			}
+		}, false},
+		{"fetch url allowing an ip", func(c *TenantConfig) {
+			c.FetchURL.AllowedDomains = []string{"10.0.0.5"}
+		}, true},
+		{"fetch url allowing localhost", func(c *TenantConfig) {
+			c.FetchURL.AllowedDomains = []string{"localhost"}
+		}, true},
+		{"fetch url allowing a url", func(c *TenantConfig) {
+			c.FetchURL.AllowedDomains = []string{"https://docs.example.com"}
+		}, true},
+		{"fetch url timeout too long", func(c *TenantConfig) {
+			c.FetchURL = FetchURLConfig{AllowedDomains: []string{"docs.example.com"}, TimeoutMs: 60000}
+		}, true},
+		{"valid fetch url", func(c *TenantConfig) {
+			c.FetchURL = FetchURLConfig{AllowedDomains: []string{"docs.example.com", "*.example.org"}, MaxBytes: 2 << 20, TimeoutMs: 5000}
+		}, false},
+		{"slo percentile out of range", func(c *TenantConfig) {
+			c.SLO.FirstToken = LatencyObjective{Percentile: 100, ThresholdMs: 2000}
+		}, true},
+		{"negative slo threshold", func(c *TenantConfig) {
+			c.SLO.Completion = LatencyObjective{ThresholdMs: -1}
+		}, true},
+		{"slo alert webhook not http", func(c *TenantConfig) {
+			c.SLO.AlertWebhook = "ftp://alerts.example.com"
+		}, true},
+		{"unknown retrieval query mode", func(c *TenantConfig) {
+			c.Retrieval.QueryMode = "everything"
+		}, true},
+		{"too many retrieval history turns", func(c *TenantConfig) {
+			c.Retrieval = RetrievalConfig{QueryMode: RetrievalQueryRecent, HistoryTurns: 50}
+		}, true},
+		{"retrieval with disabled provider", func(c *TenantConfig) {
+			c.Retrieval = RetrievalConfig{QueryMode: RetrievalQueryCondense, Provider: "gemini"}
+		}, true},
+		{"negative retrieval context budget", func(c *TenantConfig) {
+			c.Retrieval.ContextTokens = -1
+		}, true},
+		{"valid retrieval config", func(c *TenantConfig) {
+			c.Retrieval = RetrievalConfig{QueryMode: RetrievalQueryCondense, HistoryTurns: 6, Provider: "openai", Model: "gpt-4o-mini", ContextTokens: 4000, SourceTokens: 1500}
+		}, false},
+		{"grounding threshold above 1", func(c *TenantConfig) {
+			c.Grounding = GroundingPolicy{Threshold: 1.5}
+		}, true},
+		{"unknown grounding action", func(c *TenantConfig) {
+			c.Grounding = GroundingPolicy{Threshold: 0.7, Action: "refuse"}
+		}, true},
+		{"grounding with disabled provider", func(c *TenantConfig) {
+			c.Grounding = GroundingPolicy{Threshold: 0.7, Provider: "anthropic"}
+		}, true},
+		{"valid grounding policy", func(c *TenantConfig) {
+			c.Grounding = GroundingPolicy{Threshold: 0.7, Action: "fallback", Fallback: "Please contact support.", Provider: "openai"}
+		}, false},
+		{"tiering cheap model for unknown provider", func(c *TenantConfig) {
+			c.Tiering.CheapModels = map[string]string{"mistral": "mistral-small"}
+		}, true},
+		{"tiering default without cheap models", func(c *TenantConfig) {
+			c.Tiering.Default = true
+		}, true},
+		{"unknown tiering criterion", func(c *TenantConfig) {
+			c.Tiering = TieringConfig{CheapModels: map[string]string{"openai": "gpt-4o-mini"}, EscalateOn: []string{"length"}}
+		}, true},
+		{"tiering confidence without min_confidence", func(c *TenantConfig) {
+			c.Tiering = TieringConfig{CheapModels: map[string]string{"openai": "gpt-4o-mini"}, EscalateOn: []string{"confidence"}}
+		}, true},
+		{"valid tiering", func(c *TenantConfig) {
+			c.Tiering = TieringConfig{
+				CheapModels:   map[string]string{"openai": "gpt-4o-mini"},
+				Default:       true,
+				EscalateOn:    []string{"empty_response", "confidence"},
+				MinConfidence: 0.6,
+				Provider:      "openai",
+			}
+		}, false},
+		{"unknown date context timezone", func(c *TenantConfig) {
+			c.DateContext = DateContextConfig{Inject: true, Timezone: "Mars/Olympus"}
+		}, true},
+		{"server local date context timezone", func(c *TenantConfig) {
+			c.DateContext.Timezone = "Local"
+		}, true},
+		{"valid date context", func(c *TenantConfig) {
+			c.DateContext = DateContextConfig{Inject: true, Timezone: "America/New_York"}
+		}, false},
+		{"unknown log content mode", func(c *TenantConfig) {
+			c.Privacy.LogContent = "encrypt"
+		}, true},
+		{"hashed log content", func(c *TenantConfig) {
+			c.Privacy.LogContent = "hash"
+		}, false},
+		{"valid slo", func(c *TenantConfig) {
+			c.SLO = SLOConfig{
+				FirstToken:   LatencyObjective{ThresholdMs: 2000},
+				Completion:   LatencyObjective{Percentile: 99, ThresholdMs: 20000},
+				AlertWebhook: "https://alerts.example.com/airborne",
+			}
+		}, false},
+		{"anomaly spike factor of 1", func(c *TenantConfig) {
+			c.Anomaly = AnomalyConfig{Enabled: true, SpikeFactor: 1}
+		}, true},
+		{"anomaly error rate above 1", func(c *TenantConfig) {
+			c.Anomaly = AnomalyConfig{Enabled: true, MaxErrorRate: 50}
+		}, true},
+		{"valid anomaly detection", func(c *TenantConfig) {
+			c.Anomaly = AnomalyConfig{Enabled: true, SpikeFactor: 5, MaxErrorRate: 0.3, AlertWebhook: "https://alerts.example.com/airborne"}
 		}, false},
 	}
 
@@ -248,3 +506,48 @@ func TestLoadTenants_ValidationError(t *testing.T) {
 		t.Fatal("expected validation error")
 	}
 }
+
+func TestLoadTenants_ErrorPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		body string
+		want string
+	}{
+		{
+			name: "range check",
+			file: "ai8.yaml",
+			body: "tenant_id: ai8\nproviders:\n  gemini:\n    enabled: true\n    api_key: key\n    model: gemini-2.5-pro\n    temperature: 3\n",
+			want: "tenants.ai8.providers.gemini.temperature: must be between 0 and 2",
+		},
+		{
+			name: "unknown yaml key",
+			file: "ai8.yaml",
+			body: "tenant_id: ai8\nproviders:\n  gemini:\n    enabled: true\n    api_key: key\n    model: gemini-2.5-pro\n    temprature: 0.5\n",
+			want: `tenants.ai8.providers.gemini.temprature: unknown field (did you mean "temperature"?) (line 7)`,
+		},
+		{
+			name: "json type mismatch",
+			file: "t1.json",
+			body: `{"tenant_id":"t1","providers":{"openai":{"enabled":"yes","api_key":"key","model":"gpt-4o"}}}`,
+			want: `tenants.t1.providers.openai.enabled: expected true or false, got "yes"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.body), 0o600); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+
+			_, err := loadTenants(dir)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
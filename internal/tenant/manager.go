@@ -12,7 +12,8 @@ import (
 type Manager struct {
 	Env       EnvConfig
 	Tenants   map[string]TenantConfig
-	configDir string // effective config directory (may differ from Env.ConfigsDir if overridden)
+	configDir string                    // effective config directory (may differ from Env.ConfigsDir if overridden)
+	overrides map[string]SettingsUpdate // Runtime settings changes, reapplied on Reload
 	mu        sync.RWMutex
 }
 
@@ -27,6 +28,7 @@ type ReloadDiff struct {
 	Added     []string // Tenant IDs that were added
 	Removed   []string // Tenant IDs that were removed
 	Unchanged []string // Tenant IDs that remained (may have updated)
+	Dropped   []error  // Runtime overrides that no longer apply and were discarded
 }
 
 // Load builds a Manager by loading environment config plus all tenant config files.
@@ -216,8 +218,9 @@ func (m *Manager) Reload() (ReloadDiff, error) {
 	sort.Strings(diff.Removed)
 	sort.Strings(diff.Unchanged)
 
-	// Apply new configs
+	// Apply new configs, keeping runtime overrides on top
 	m.Tenants = newTenants
+	diff.Dropped = m.applyOverridesLocked()
 
 	return diff, nil
 }
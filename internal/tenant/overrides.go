@@ -0,0 +1,284 @@
+package tenant
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// SettingsUpdate is a runtime change to a tenant's routing settings, applied
+// on top of the loaded config. Nil fields are left unchanged. Credentials,
+// base URLs and proxies cannot be changed this way; they still require a
+// config change.
+type SettingsUpdate struct {
+	Providers map[string]ProviderUpdate `json:"providers,omitempty"`
+	Failover  *FailoverUpdate           `json:"failover,omitempty"`
+	Features  *FeatureUpdate            `json:"features,omitempty"`
+}
+
+// ProviderUpdate changes one provider's settings.
+type ProviderUpdate struct {
+	Enabled         *bool     `json:"enabled,omitempty"`
+	Model           *string   `json:"model,omitempty"`
+	Temperature     *float64  `json:"temperature,omitempty"`
+	TopP            *float64  `json:"top_p,omitempty"`
+	MaxOutputTokens *int      `json:"max_output_tokens,omitempty"`
+	AllowedModels   *[]string `json:"allowed_models,omitempty"`
+}
+
+// FailoverUpdate changes the failover settings.
+type FailoverUpdate struct {
+	Enabled          *bool     `json:"enabled,omitempty"`
+	Order            *[]string `json:"order,omitempty"`
+	MaxAttempts      *int      `json:"max_attempts,omitempty"`
+	AttemptTimeoutMs *int      `json:"attempt_timeout_ms,omitempty"`
+	Triggers         *[]string `json:"triggers,omitempty"`
+}
+
+// FeatureUpdate toggles optional tenant features. Flags are set
+// individually; flags not named keep their current value.
+type FeatureUpdate struct {
+	ImageGeneration *bool           `json:"image_generation,omitempty"`
+	EventStream     *bool           `json:"event_stream,omitempty"`
+	Flags           map[string]bool `json:"flags,omitempty"`
+}
+
+// IsEmpty reports whether u changes nothing.
+func (u SettingsUpdate) IsEmpty() bool {
+	return len(u.Providers) == 0 && u.Failover == nil && u.Features == nil
+}
+
+// apply returns cfg with u applied. cfg's maps and slices are not modified.
+func (u SettingsUpdate) apply(cfg TenantConfig) (TenantConfig, error) {
+	if len(u.Providers) > 0 {
+		cfg.Providers = maps.Clone(cfg.Providers)
+		for _, name := range sortedKeys(u.Providers) {
+			p, ok := cfg.Providers[name]
+			if !ok {
+				return cfg, fmt.Errorf("providers.%s: provider is not configured for this tenant", name)
+			}
+			pu := u.Providers[name]
+			setIf(&p.Enabled, pu.Enabled)
+			setIf(&p.Model, pu.Model)
+			if pu.Temperature != nil {
+				p.Temperature = ptr(*pu.Temperature)
+			}
+			if pu.TopP != nil {
+				p.TopP = ptr(*pu.TopP)
+			}
+			if pu.MaxOutputTokens != nil {
+				p.MaxOutputTokens = ptr(*pu.MaxOutputTokens)
+			}
+			if pu.AllowedModels != nil {
+				p.AllowedModels = slices.Clone(*pu.AllowedModels)
+			}
+			cfg.Providers[name] = p
+		}
+	}
+
+	if f := u.Failover; f != nil {
+		setIf(&cfg.Failover.Enabled, f.Enabled)
+		setIf(&cfg.Failover.MaxAttempts, f.MaxAttempts)
+		setIf(&cfg.Failover.AttemptTimeoutMs, f.AttemptTimeoutMs)
+		if f.Order != nil {
+			cfg.Failover.Order = slices.Clone(*f.Order)
+		}
+		if f.Triggers != nil {
+			cfg.Failover.Triggers = slices.Clone(*f.Triggers)
+		}
+	}
+
+	if f := u.Features; f != nil {
+		setIf(&cfg.ImageGeneration.Enabled, f.ImageGeneration)
+		setIf(&cfg.EventStream.Enabled, f.EventStream)
+		if len(f.Flags) > 0 {
+			cfg.Features = maps.Clone(cfg.Features)
+			if cfg.Features == nil {
+				cfg.Features = make(map[string]bool, len(f.Flags))
+			}
+			maps.Copy(cfg.Features, f.Flags)
+		}
+	}
+	return cfg, nil
+}
+
+// merge returns u with next applied on top, so the result replays both.
+func (u SettingsUpdate) merge(next SettingsUpdate) SettingsUpdate {
+	out := SettingsUpdate{Failover: u.Failover, Features: u.Features}
+	if len(u.Providers) > 0 || len(next.Providers) > 0 {
+		out.Providers = maps.Clone(u.Providers)
+		if out.Providers == nil {
+			out.Providers = make(map[string]ProviderUpdate)
+		}
+		for name, pn := range next.Providers {
+			p := out.Providers[name]
+			mergeIf(&p.Enabled, pn.Enabled)
+			mergeIf(&p.Model, pn.Model)
+			mergeIf(&p.Temperature, pn.Temperature)
+			mergeIf(&p.TopP, pn.TopP)
+			mergeIf(&p.MaxOutputTokens, pn.MaxOutputTokens)
+			mergeIf(&p.AllowedModels, pn.AllowedModels)
+			out.Providers[name] = p
+		}
+	}
+	if next.Failover != nil {
+		f := FailoverUpdate{}
+		if u.Failover != nil {
+			f = *u.Failover
+		}
+		mergeIf(&f.Enabled, next.Failover.Enabled)
+		mergeIf(&f.Order, next.Failover.Order)
+		mergeIf(&f.MaxAttempts, next.Failover.MaxAttempts)
+		mergeIf(&f.AttemptTimeoutMs, next.Failover.AttemptTimeoutMs)
+		mergeIf(&f.Triggers, next.Failover.Triggers)
+		out.Failover = &f
+	}
+	if next.Features != nil {
+		f := FeatureUpdate{}
+		if u.Features != nil {
+			f = *u.Features
+		}
+		mergeIf(&f.ImageGeneration, next.Features.ImageGeneration)
+		mergeIf(&f.EventStream, next.Features.EventStream)
+		if len(next.Features.Flags) > 0 {
+			f.Flags = maps.Clone(f.Flags)
+			if f.Flags == nil {
+				f.Flags = make(map[string]bool, len(next.Features.Flags))
+			}
+			maps.Copy(f.Flags, next.Features.Flags)
+		}
+		out.Features = &f
+	}
+	return out
+}
+
+func setIf[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func mergeIf[T any](dst **T, v *T) {
+	if v != nil {
+		*dst = v
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// PreviewSettings returns the config and merged override a tenant would have
+// after UpdateSettings(tenantID, u), without changing anything.
+func (m *Manager) PreviewSettings(tenantID string, u SettingsUpdate) (TenantConfig, SettingsUpdate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.previewLocked(tenantID, u)
+}
+
+// UpdateSettings validates and applies u to a tenant's live config. The
+// change is kept as an override that survives Reload, and the merged override
+// for the tenant is returned so callers can persist it.
+func (m *Manager) UpdateSettings(tenantID string, u SettingsUpdate) (TenantConfig, SettingsUpdate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updated, merged, err := m.previewLocked(tenantID, u)
+	if err != nil {
+		return TenantConfig{}, SettingsUpdate{}, err
+	}
+	if m.overrides == nil {
+		m.overrides = make(map[string]SettingsUpdate)
+	}
+	m.overrides[tenantID] = merged
+	m.Tenants[tenantID] = updated
+	return updated, merged, nil
+}
+
+// RevertSettings restores a tenant's config and override from before an
+// UpdateSettings call, e.g. when the change could not be persisted.
+// hadOverride reports whether the tenant had an override then.
+func (m *Manager) RevertSettings(tenantID string, cfg TenantConfig, override SettingsUpdate, hadOverride bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.Tenants[tenantID]; !ok {
+		return // Removed by a reload since
+	}
+	m.Tenants[tenantID] = cfg
+	if hadOverride {
+		m.overrides[tenantID] = override
+	} else {
+		delete(m.overrides, tenantID)
+	}
+}
+
+// previewLocked applies u to a tenant's current config. m.mu must be held.
+func (m *Manager) previewLocked(tenantID string, u SettingsUpdate) (TenantConfig, SettingsUpdate, error) {
+	cfg, ok := m.Tenants[tenantID]
+	if !ok {
+		return TenantConfig{}, SettingsUpdate{}, fmt.Errorf("tenant not found: %s", tenantID)
+	}
+	updated, err := u.apply(cfg)
+	if err != nil {
+		return TenantConfig{}, SettingsUpdate{}, err
+	}
+	if err := validateTenantConfig(&updated); err != nil {
+		return TenantConfig{}, SettingsUpdate{}, err
+	}
+	return updated, m.overrides[tenantID].merge(u), nil
+}
+
+// Override returns the runtime override applied to a tenant, if any.
+func (m *Manager) Override(tenantID string) (SettingsUpdate, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, ok := m.overrides[tenantID]
+	return u, ok
+}
+
+// RestoreOverrides applies overrides persisted by an earlier process, e.g. at
+// startup. Overrides for unknown tenants, or that no longer validate against
+// the loaded config, are skipped and returned as errors.
+func (m *Manager) RestoreOverrides(overrides map[string]SettingsUpdate) []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.overrides == nil {
+		m.overrides = make(map[string]SettingsUpdate)
+	}
+	maps.Copy(m.overrides, overrides)
+	return m.applyOverridesLocked()
+}
+
+// applyOverridesLocked applies the overrides to m.Tenants, dropping any that
+// no longer apply. m.mu must be held.
+func (m *Manager) applyOverridesLocked() []error {
+	var errs []error
+	ids := make([]string, 0, len(m.overrides))
+	for id := range m.overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		cfg, ok := m.Tenants[id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("override for %s: tenant not found", id))
+			delete(m.overrides, id)
+			continue
+		}
+		updated, err := m.overrides[id].apply(cfg)
+		if err == nil {
+			err = validateTenantConfig(&updated)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("override for %s: %w", id, err))
+			delete(m.overrides, id)
+			continue
+		}
+		m.Tenants[id] = updated
+	}
+	return errs
+}
@@ -0,0 +1,169 @@
+package tenant
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testOverrideManager() *Manager {
+	return &Manager{Tenants: map[string]TenantConfig{
+		"acme": {
+			TenantID: "acme",
+			Providers: map[string]ProviderConfig{
+				"openai":    {Enabled: true, APIKey: "sk-openai", Model: "gpt-4o"},
+				"anthropic": {Enabled: true, APIKey: "sk-ant", Model: "claude"},
+			},
+		},
+	}}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	mgr := testOverrideManager()
+
+	var u SettingsUpdate
+	if err := json.Unmarshal([]byte(`{
+		"providers": {"openai": {"model": "gpt-4o-mini", "temperature": 0.2}},
+		"failover": {"enabled": true, "order": ["anthropic"]},
+		"features": {"event_stream": true}
+	}`), &u); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	cfg, merged, err := mgr.UpdateSettings("acme", u)
+	if err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	openai := cfg.Providers["openai"]
+	if openai.Model != "gpt-4o-mini" || openai.Temperature == nil || *openai.Temperature != 0.2 {
+		t.Errorf("openai not updated: %+v", openai)
+	}
+	if openai.APIKey != "sk-openai" {
+		t.Errorf("API key changed: %q", openai.APIKey)
+	}
+	if !cfg.Failover.Enabled || !reflect.DeepEqual(cfg.Failover.Order, []string{"anthropic"}) {
+		t.Errorf("failover not updated: %+v", cfg.Failover)
+	}
+	if !cfg.EventStream.Enabled {
+		t.Error("event stream not enabled")
+	}
+	if live, _ := mgr.Tenant("acme"); live.Providers["openai"].Model != "gpt-4o-mini" {
+		t.Errorf("live config not updated: %+v", live.Providers["openai"])
+	}
+
+	// A second update merges into the override
+	model := "gpt-4.1"
+	_, merged, err = mgr.UpdateSettings("acme", SettingsUpdate{Providers: map[string]ProviderUpdate{"openai": {Model: &model}}})
+	if err != nil {
+		t.Fatalf("second UpdateSettings failed: %v", err)
+	}
+	p := merged.Providers["openai"]
+	if *p.Model != "gpt-4.1" || p.Temperature == nil || *p.Temperature != 0.2 || merged.Failover == nil || merged.Features == nil {
+		t.Errorf("override not merged: %+v", merged)
+	}
+	if override, ok := mgr.Override("acme"); !ok || !reflect.DeepEqual(override, merged) {
+		t.Errorf("Override = %+v, %v, want %+v", override, ok, merged)
+	}
+}
+
+func TestUpdateSettings_Invalid(t *testing.T) {
+	temp := 3.0
+	unknown := []string{"mistral"}
+	enabled := true
+	tests := []struct {
+		name    string
+		tenant  string
+		update  SettingsUpdate
+		wantErr string
+	}{
+		{"unknown tenant", "nope", SettingsUpdate{}, "tenant not found"},
+		{"unconfigured provider", "acme", SettingsUpdate{Providers: map[string]ProviderUpdate{"gemini": {Enabled: &enabled}}}, "providers.gemini"},
+		{"temperature out of range", "acme", SettingsUpdate{Providers: map[string]ProviderUpdate{"openai": {Temperature: &temp}}}, "providers.openai.temperature"},
+		{"unknown failover provider", "acme", SettingsUpdate{Failover: &FailoverUpdate{Enabled: &enabled, Order: &unknown}}, "failover.order[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := testOverrideManager()
+			_, _, err := mgr.UpdateSettings(tt.tenant, tt.update)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if cfg, _ := mgr.Tenant("acme"); cfg.Providers["openai"].Temperature != nil || cfg.Failover.Enabled {
+				t.Errorf("config changed despite error: %+v", cfg)
+			}
+			if _, ok := mgr.Override(tt.tenant); ok {
+				t.Error("override recorded despite error")
+			}
+		})
+	}
+}
+
+func TestRevertSettings(t *testing.T) {
+	mgr := testOverrideManager()
+	model := "gpt-4o-mini"
+	if _, _, err := mgr.UpdateSettings("acme", SettingsUpdate{Providers: map[string]ProviderUpdate{"openai": {Model: &model}}}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	before, _ := mgr.Tenant("acme")
+	previous, hadOverride := mgr.Override("acme")
+
+	temp := 0.2
+	if _, _, err := mgr.UpdateSettings("acme", SettingsUpdate{Providers: map[string]ProviderUpdate{"openai": {Temperature: &temp}}}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	mgr.RevertSettings("acme", before, previous, hadOverride)
+	if cfg, _ := mgr.Tenant("acme"); cfg.Providers["openai"].Model != "gpt-4o-mini" || cfg.Providers["openai"].Temperature != nil {
+		t.Errorf("config not reverted: %+v", cfg.Providers["openai"])
+	}
+	if override, ok := mgr.Override("acme"); !ok || !reflect.DeepEqual(override, previous) {
+		t.Errorf("Override = %+v, %v, want %+v", override, ok, previous)
+	}
+
+	mgr.RevertSettings("acme", before, SettingsUpdate{}, false)
+	if _, ok := mgr.Override("acme"); ok {
+		t.Error("override kept after reverting to none")
+	}
+}
+
+func TestManagerReload_KeepsOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeTenantJSON(t, dir, "t1.json", "t1")
+	writeTenantJSON(t, dir, "t2.json", "t2")
+
+	initial, err := loadTenants(dir)
+	if err != nil {
+		t.Fatalf("loadTenants failed: %v", err)
+	}
+	mgr := &Manager{Tenants: initial, configDir: dir}
+
+	model := "gpt-4o-mini"
+	errs := mgr.RestoreOverrides(map[string]SettingsUpdate{
+		"t1":   {Providers: map[string]ProviderUpdate{"openai": {Model: &model}}},
+		"t2":   {Providers: map[string]ProviderUpdate{"gemini": {Model: &model}}},
+		"gone": {Providers: map[string]ProviderUpdate{"openai": {Model: &model}}},
+	})
+	if len(errs) != 2 {
+		t.Fatalf("expected overrides for t2 and gone to be dropped, got %v", errs)
+	}
+	if cfg, _ := mgr.Tenant("t1"); cfg.Providers["openai"].Model != model {
+		t.Fatalf("restored override not applied: %+v", cfg.Providers["openai"])
+	}
+
+	diff, err := mgr.Reload()
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if len(diff.Dropped) != 0 {
+		t.Errorf("unexpected dropped overrides: %v", diff.Dropped)
+	}
+	if cfg, _ := mgr.Tenant("t1"); cfg.Providers["openai"].Model != model {
+		t.Errorf("override lost on reload: %+v", cfg.Providers["openai"])
+	}
+	if cfg, _ := mgr.Tenant("t2"); cfg.Providers["openai"].Model != "model" {
+		t.Errorf("t2 changed: %+v", cfg.Providers["openai"])
+	}
+	if _, ok := mgr.Override("t2"); ok {
+		t.Error("dropped override still recorded")
+	}
+}
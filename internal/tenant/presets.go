@@ -0,0 +1,37 @@
+package tenant
+
+// Reasoning efforts a generation preset can set.
+const (
+	ReasoningEffortNone   = "none"
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
+)
+
+// GenerationPreset bundles generation parameters a request selects by name
+// (e.g. "precise") instead of setting each one. Set fields replace the
+// provider's tenant defaults; the request's provider_configs still override
+// the preset.
+type GenerationPreset struct {
+	Provider        string            `json:"provider,omitempty" yaml:"provider,omitempty"` // Used when the request names no provider
+	Models          map[string]string `json:"models,omitempty" yaml:"models,omitempty"`     // provider -> model, e.g. openai: gpt-4o-mini
+	Temperature     *float64          `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	MaxOutputTokens *int              `json:"max_output_tokens,omitempty" yaml:"max_output_tokens,omitempty"`
+	ReasoningEffort string            `json:"reasoning_effort,omitempty" yaml:"reasoning_effort,omitempty"` // none, low, medium or high
+}
+
+// ValidReasoningEffort reports whether effort is a known reasoning effort
+// or empty.
+func ValidReasoningEffort(effort string) bool {
+	switch effort {
+	case "", ReasoningEffortNone, ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh:
+		return true
+	}
+	return false
+}
+
+// Preset returns the named generation preset and whether it exists.
+func (tc *TenantConfig) Preset(name string) (GenerationPreset, bool) {
+	p, ok := tc.Presets[name]
+	return p, ok
+}
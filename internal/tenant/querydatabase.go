@@ -0,0 +1,67 @@
+package tenant
+
+import (
+	"regexp"
+	"time"
+)
+
+// QueryDatabaseToolName is the name the built-in database tool is offered
+// to the model under.
+const QueryDatabaseToolName = "query_database"
+
+// Database tool limits and defaults.
+const (
+	DefaultQueryRows    = 100
+	MaxQueryRows        = 1000
+	DefaultQueryTimeout = 5 * time.Second
+	MaxQueryTimeout     = 30 * time.Second
+)
+
+// schemaName matches the unquoted Postgres identifiers allowed schemas can
+// be named by.
+var schemaName = regexp.MustCompile(`^[a-z_][a-z0-9_$]{0,62}$`)
+
+// QueryDatabaseConfig registers the tenant's databases for the built-in
+// query_database tool, which the server offers the model when any are set.
+// Queries run in read-only transactions within the row and time limits, and
+// may only read tables in each database's schemas.
+type QueryDatabaseConfig struct {
+	Databases map[string]QueryDatabase `json:"databases,omitempty" yaml:"databases,omitempty"`   // Name -> database, as the model refers to it
+	MaxRows   int                      `json:"max_rows,omitempty" yaml:"max_rows,omitempty"`     // Rows returned per query; defaults to 100, at most 1000
+	TimeoutMs int                      `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"` // Per query; defaults to 5s, at most 30s
+}
+
+// QueryDatabase is a PostgreSQL database the model can query. The DSN should
+// log in as a read-only role; read-only transactions and the schema check are
+// a second line of defence.
+type QueryDatabase struct {
+	DSN         string   `json:"dsn" yaml:"dsn"`                                     // postgres:// URL; ENV=, FILE= or inline
+	Schemas     []string `json:"schemas" yaml:"schemas"`                             // Schemas queries may read
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"` // What the data is, shown to the model
+}
+
+// Enabled reports whether the tool is offered.
+func (c QueryDatabaseConfig) Enabled() bool {
+	return len(c.Databases) > 0
+}
+
+// Rows returns the most rows a query returns.
+func (c QueryDatabaseConfig) Rows() int {
+	if c.MaxRows <= 0 {
+		return DefaultQueryRows
+	}
+	return min(c.MaxRows, MaxQueryRows)
+}
+
+// Timeout returns the time allowed for each query.
+func (c QueryDatabaseConfig) Timeout() time.Duration {
+	if c.TimeoutMs <= 0 {
+		return DefaultQueryTimeout
+	}
+	return min(time.Duration(c.TimeoutMs)*time.Millisecond, MaxQueryTimeout)
+}
+
+// ValidSchemaName reports whether name is a lowercase, unquoted schema name.
+func ValidSchemaName(name string) bool {
+	return schemaName.MatchString(name)
+}
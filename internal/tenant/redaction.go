@@ -0,0 +1,107 @@
+package tenant
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Built-in kinds of personal data a RedactionPolicy can remove.
+const (
+	RedactEmail      = "email"
+	RedactPhone      = "phone"
+	RedactCreditCard = "credit_card"
+	RedactSSN        = "ssn"
+	RedactIPAddress  = "ip_address"
+)
+
+// defaultRedactionReplacement replaces redacted text when the policy sets none.
+const defaultRedactionReplacement = "[REDACTED]"
+
+// piiPatterns match each built-in kind of personal data.
+var piiPatterns = map[string]*regexp.Regexp{
+	RedactEmail:      regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	RedactPhone:      regexp.MustCompile(`\+?\d{1,3}[ .-]?\(?\d{3}\)?[ .-]?\d{3}[ .-]?\d{4}\b|\(\d{3}\) ?\d{3}[ .-]?\d{4}\b`),
+	RedactCreditCard: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	RedactSSN:        regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	RedactIPAddress:  regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+}
+
+// piiOrder applies the built-in patterns longest-match first, so a card
+// number is not partly consumed as a phone number.
+var piiOrder = []string{RedactEmail, RedactCreditCard, RedactSSN, RedactPhone, RedactIPAddress}
+
+// RedactionPolicy removes sensitive text from conversations a tenant shares
+// outside the service, such as thread exports. The zero value redacts
+// nothing.
+type RedactionPolicy struct {
+	PII         []string `json:"pii,omitempty" yaml:"pii,omitempty"`                 // Built-in kinds, see Redact*
+	Patterns    []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`       // Extra regular expressions (RE2 syntax)
+	Replacement string   `json:"replacement,omitempty" yaml:"replacement,omitempty"` // Defaults to "[REDACTED]"
+	HideUserID  bool     `json:"hide_user_id,omitempty" yaml:"hide_user_id,omitempty"`
+}
+
+// IsEmpty reports whether the policy redacts nothing.
+func (p RedactionPolicy) IsEmpty() bool {
+	return len(p.PII) == 0 && len(p.Patterns) == 0 && !p.HideUserID
+}
+
+// Redactor applies a compiled RedactionPolicy. A nil *Redactor redacts
+// nothing.
+type Redactor struct {
+	patterns    []*regexp.Regexp
+	replacement string
+	hideUserID  bool
+}
+
+// Compile checks the policy and returns its Redactor, or nil when the
+// policy redacts nothing.
+func (p RedactionPolicy) Compile() (*Redactor, error) {
+	if p.IsEmpty() {
+		return nil, nil
+	}
+	r := &Redactor{replacement: p.Replacement, hideUserID: p.HideUserID}
+	if r.replacement == "" {
+		r.replacement = defaultRedactionReplacement
+	}
+	for _, kind := range p.PII {
+		if _, ok := piiPatterns[kind]; !ok {
+			return nil, fmt.Errorf("unknown pii kind %q, must be one of %s", kind, strings.Join(piiOrder, ", "))
+		}
+	}
+	for _, kind := range piiOrder {
+		for _, want := range p.PII {
+			if want == kind {
+				r.patterns = append(r.patterns, piiPatterns[kind])
+				break
+			}
+		}
+	}
+	for i, pattern := range p.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("patterns[%d]: %w", i, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact replaces each match of the policy's patterns in text.
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, re := range r.patterns {
+		text = re.ReplaceAllLiteralString(text, r.replacement)
+	}
+	return text
+}
+
+// UserID returns userID, or the replacement when the policy hides user IDs.
+func (r *Redactor) UserID(userID string) string {
+	if r == nil || !r.hideUserID || userID == "" {
+		return userID
+	}
+	return r.replacement
+}
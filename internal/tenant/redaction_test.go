@@ -0,0 +1,54 @@
+package tenant
+
+import "testing"
+
+func TestRedactionPolicy(t *testing.T) {
+	r, err := RedactionPolicy{
+		PII:         []string{RedactEmail, RedactPhone, RedactCreditCard, RedactSSN, RedactIPAddress},
+		Patterns:    []string{`ACCT-\d+`},
+		Replacement: "***",
+		HideUserID:  true,
+	}.Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"mail jane.doe@example.com today", "mail *** today"},
+		{"call (555) 123-4567 or +1 555-123-4567", "call *** or ***"},
+		{"card 4111 1111 1111 1111 expires", "card *** expires"},
+		{"ssn 123-45-6789", "ssn ***"},
+		{"from 10.0.0.12", "from ***"},
+		{"account ACCT-99812 is open", "account *** is open"},
+		{"nothing to hide in 2026", "nothing to hide in 2026"},
+	}
+	for _, tt := range tests {
+		if got := r.Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := r.UserID("user-1"); got != "***" {
+		t.Errorf("UserID = %q, want it hidden", got)
+	}
+}
+
+func TestRedactionPolicy_Empty(t *testing.T) {
+	r, err := RedactionPolicy{}.Compile()
+	if err != nil || r != nil {
+		t.Fatalf("Compile() = %v, %v; want nil, nil", r, err)
+	}
+	if got := r.Redact("jane@example.com"); got != "jane@example.com" {
+		t.Errorf("nil Redactor changed text: %q", got)
+	}
+	if got := r.UserID("user-1"); got != "user-1" {
+		t.Errorf("nil Redactor changed user ID: %q", got)
+	}
+
+	r, _ = RedactionPolicy{PII: []string{RedactEmail}}.Compile()
+	if got := r.Redact("jane@example.com"); got != defaultRedactionReplacement {
+		t.Errorf("Redact = %q, want default replacement", got)
+	}
+}
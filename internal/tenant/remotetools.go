@@ -0,0 +1,64 @@
+package tenant
+
+import (
+	"regexp"
+	"time"
+)
+
+// Remote tool limits and defaults.
+const (
+	DefaultRemoteToolTimeout       = 10 * time.Second
+	MaxRemoteToolTimeout           = 60 * time.Second
+	DefaultRemoteToolRetries       = 1
+	MaxRemoteToolRetries           = 3
+	DefaultRemoteToolResponseBytes = 64 << 10
+	MaxRemoteToolResponseBytes     = 1 << 20
+)
+
+// remoteToolName matches the function names every provider accepts.
+var remoteToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// RemoteTool is a tool the server offers the model on the tenant's behalf
+// and executes itself: each call's arguments are POSTed to URL and the
+// response body is returned to the model, so the tool loop completes
+// without the client.
+type RemoteTool struct {
+	Description      string         `json:"description" yaml:"description"`
+	Parameters       map[string]any `json:"parameters,omitempty" yaml:"parameters,omitempty"`                 // JSON Schema of the arguments
+	URL              string         `json:"url" yaml:"url"`                                                   // HTTPS endpoint
+	AuthHeader       string         `json:"auth_header,omitempty" yaml:"auth_header,omitempty"`               // Defaults to Authorization, sent as "Bearer <token>"
+	AuthToken        string         `json:"auth_token,omitempty" yaml:"auth_token,omitempty"`                 // ENV=, FILE= or inline
+	TimeoutMs        int            `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`                 // Per attempt; defaults to 10s, at most 60s
+	MaxRetries       *int           `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`               // Retries of failed attempts, 0-3; defaults to 1
+	MaxResponseBytes int            `json:"max_response_bytes,omitempty" yaml:"max_response_bytes,omitempty"` // Defaults to 64 KB, at most 1 MB
+}
+
+// Timeout returns the time allowed for each attempt.
+func (t RemoteTool) Timeout() time.Duration {
+	if t.TimeoutMs <= 0 {
+		return DefaultRemoteToolTimeout
+	}
+	return min(time.Duration(t.TimeoutMs)*time.Millisecond, MaxRemoteToolTimeout)
+}
+
+// Retries returns how many times a failed attempt is retried.
+func (t RemoteTool) Retries() int {
+	if t.MaxRetries == nil {
+		return DefaultRemoteToolRetries
+	}
+	return min(max(*t.MaxRetries, 0), MaxRemoteToolRetries)
+}
+
+// ResponseLimit returns the largest response body accepted.
+func (t RemoteTool) ResponseLimit() int {
+	if t.MaxResponseBytes <= 0 {
+		return DefaultRemoteToolResponseBytes
+	}
+	return min(t.MaxResponseBytes, MaxRemoteToolResponseBytes)
+}
+
+// ValidRemoteToolName reports whether name can be offered to every provider
+// as a function name.
+func ValidRemoteToolName(name string) bool {
+	return remoteToolName.MatchString(name)
+}
@@ -0,0 +1,64 @@
+package tenant
+
+// Retrieval query modes: what internal RAG retrieval embeds as its query.
+const (
+	// RetrievalQueryLatest embeds the latest user input (the default).
+	RetrievalQueryLatest = "latest"
+
+	// RetrievalQueryRecent embeds the user's recent messages followed by
+	// the latest input.
+	RetrievalQueryRecent = "recent"
+
+	// RetrievalQueryCondense has a model rewrite the recent conversation
+	// and the latest input into a standalone question.
+	RetrievalQueryCondense = "condense"
+)
+
+// DefaultRetrievalHistoryTurns is how many earlier messages the recent and
+// condense modes consider when history_turns is not set.
+const DefaultRetrievalHistoryTurns = 4
+
+// MaxRetrievalHistoryTurns bounds the earlier messages a query is built from.
+const MaxRetrievalHistoryTurns = 20
+
+// DefaultRetrievalContextTokens is the token budget for injected RAG context
+// when context_tokens is not set.
+const DefaultRetrievalContextTokens = 8000
+
+// RetrievalConfig controls how RAG queries are built, so follow-up questions
+// such as "what about the second one?" retrieve the chunks they refer to,
+// and how much retrieved context is injected into the prompt.
+type RetrievalConfig struct {
+	QueryMode     string `json:"query_mode,omitempty" yaml:"query_mode,omitempty"`         // "latest" (default), "recent" or "condense"
+	HistoryTurns  int    `json:"history_turns,omitempty" yaml:"history_turns,omitempty"`   // Earlier messages considered; defaults to 4
+	Provider      string `json:"provider,omitempty" yaml:"provider,omitempty"`             // Condense provider; defaults to the request's
+	Model         string `json:"model,omitempty" yaml:"model,omitempty"`                   // Condense model; defaults to the provider's configured model
+	ContextTokens int    `json:"context_tokens,omitempty" yaml:"context_tokens,omitempty"` // Budget for injected chunks; defaults to 8000
+	SourceTokens  int    `json:"source_tokens,omitempty" yaml:"source_tokens,omitempty"`   // Cap per source file; 0 means no cap
+}
+
+// ContextBudget returns the token budget for injected RAG context.
+func (c RetrievalConfig) ContextBudget() int {
+	if c.ContextTokens <= 0 {
+		return DefaultRetrievalContextTokens
+	}
+	return c.ContextTokens
+}
+
+// Turns returns how many earlier messages a query is built from.
+func (c RetrievalConfig) Turns() int {
+	if c.HistoryTurns <= 0 {
+		return DefaultRetrievalHistoryTurns
+	}
+	return min(c.HistoryTurns, MaxRetrievalHistoryTurns)
+}
+
+// ValidRetrievalQueryMode reports whether mode names a query mode; empty
+// selects the default.
+func ValidRetrievalQueryMode(mode string) bool {
+	switch mode {
+	case "", RetrievalQueryLatest, RetrievalQueryRecent, RetrievalQueryCondense:
+		return true
+	}
+	return false
+}
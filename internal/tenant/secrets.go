@@ -66,6 +66,27 @@ func resolveSecrets(cfg *TenantConfig) error {
 		pCfg.APIKey = resolved
 		cfg.Providers[name] = pCfg
 	}
+	for name, tool := range cfg.RemoteTools {
+		resolved, err := loadSecret(tool.AuthToken)
+		if err != nil {
+			return fmt.Errorf("remote_tools.%s auth_token: %w", name, err)
+		}
+		tool.AuthToken = resolved
+		cfg.RemoteTools[name] = tool
+	}
+	for name, db := range cfg.QueryDatabase.Databases {
+		resolved, err := loadSecret(db.DSN)
+		if err != nil {
+			return fmt.Errorf("query_database.databases.%s dsn: %w", name, err)
+		}
+		db.DSN = resolved
+		cfg.QueryDatabase.Databases[name] = db
+	}
+	resolved, err := loadSecret(cfg.Attribution.SigningKey)
+	if err != nil {
+		return fmt.Errorf("attribution signing_key: %w", err)
+	}
+	cfg.Attribution.SigningKey = resolved
 	return nil
 }
 
@@ -84,6 +105,39 @@ func ReplaceSecretsWithReferences(cfg *TenantConfig) {
 		}
 		// If it already has ENV=/FILE=/${} pattern, keep it as-is
 	}
+	for name, tool := range cfg.RemoteTools {
+		if tool.AuthToken != "" && !isSecretReference(tool.AuthToken) {
+			tool.AuthToken = "ENV=" + remoteToolTokenEnv(name)
+			cfg.RemoteTools[name] = tool
+		}
+	}
+	for name, db := range cfg.QueryDatabase.Databases {
+		if db.DSN != "" && !isSecretReference(db.DSN) {
+			db.DSN = "ENV=" + queryDatabaseDSNEnv(name)
+			cfg.QueryDatabase.Databases[name] = db
+		}
+	}
+	if cfg.Attribution.SigningKey != "" && !isSecretReference(cfg.Attribution.SigningKey) {
+		cfg.Attribution.SigningKey = "ENV=ATTRIBUTION_SIGNING_KEY"
+	}
+}
+
+// isSecretReference reports whether value refers to a secret rather than
+// holding it.
+func isSecretReference(value string) bool {
+	return strings.HasPrefix(value, "ENV=") || strings.HasPrefix(value, "FILE=") || strings.HasPrefix(value, "${")
+}
+
+// remoteToolTokenEnv names the environment variable a frozen config reads a
+// remote tool's auth token from, e.g. TOOL_LOOKUP_ORDER_AUTH_TOKEN.
+func remoteToolTokenEnv(name string) string {
+	return "TOOL_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_AUTH_TOKEN"
+}
+
+// queryDatabaseDSNEnv names the environment variable a frozen config reads a
+// query_database DSN from, e.g. QUERY_DATABASE_ANALYTICS_DSN.
+func queryDatabaseDSNEnv(name string) string {
+	return "QUERY_DATABASE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_DSN"
 }
 
 // loadSecret resolves a secret value from ENV=, FILE=, or inline.
@@ -103,6 +103,44 @@ func TestResolveSecrets_MultipleProviders(t *testing.T) {
 	}
 }
 
+func TestResolveSecrets_RemoteToolToken(t *testing.T) {
+	t.Setenv("ORDERS_TOKEN", "orders-token")
+
+	cfg := TenantConfig{
+		RemoteTools: map[string]RemoteTool{
+			"lookup_order": {Description: "Looks up an order", URL: "https://tools.example.com/order", AuthToken: "ENV=ORDERS_TOKEN"},
+		},
+	}
+	if err := resolveSecrets(&cfg); err != nil {
+		t.Fatalf("resolveSecrets failed: %v", err)
+	}
+	if got := cfg.RemoteTools["lookup_order"].AuthToken; got != "orders-token" {
+		t.Fatalf("AuthToken = %q, want orders-token", got)
+	}
+
+	ReplaceSecretsWithReferences(&cfg)
+	if got := cfg.RemoteTools["lookup_order"].AuthToken; got != "ENV=TOOL_LOOKUP_ORDER_AUTH_TOKEN" {
+		t.Fatalf("frozen AuthToken = %q, want an ENV= reference", got)
+	}
+}
+
+func TestResolveSecrets_AttributionSigningKey(t *testing.T) {
+	t.Setenv("ACME_ATTRIBUTION_KEY", "attribution-key")
+
+	cfg := TenantConfig{Attribution: AttributionConfig{Enabled: true, SigningKey: "ENV=ACME_ATTRIBUTION_KEY"}}
+	if err := resolveSecrets(&cfg); err != nil {
+		t.Fatalf("resolveSecrets failed: %v", err)
+	}
+	if got := cfg.Attribution.SigningKey; got != "attribution-key" {
+		t.Fatalf("SigningKey = %q, want attribution-key", got)
+	}
+
+	ReplaceSecretsWithReferences(&cfg)
+	if got := cfg.Attribution.SigningKey; got != "ENV=ATTRIBUTION_SIGNING_KEY" {
+		t.Fatalf("frozen SigningKey = %q, want an ENV= reference", got)
+	}
+}
+
 func TestValidateSecretPath_TraversalBlocked(t *testing.T) {
 	tests := []string{
 		"/etc/airborne/secrets/../../../etc/passwd",
@@ -215,3 +253,22 @@ func TestValidateSecretPath_SymlinkToAllowed(t *testing.T) {
 		t.Errorf("expected symlink within allowed dir to pass: %v", err)
 	}
 }
+
+func TestResolveSecrets_QueryDatabaseDSN(t *testing.T) {
+	t.Setenv("ACME_ANALYTICS_DSN", "postgres://reader:pw@db.example.com/analytics")
+
+	cfg := TenantConfig{QueryDatabase: QueryDatabaseConfig{Databases: map[string]QueryDatabase{
+		"analytics": {DSN: "ENV=ACME_ANALYTICS_DSN", Schemas: []string{"public"}},
+	}}}
+	if err := resolveSecrets(&cfg); err != nil {
+		t.Fatalf("resolveSecrets failed: %v", err)
+	}
+	if got := cfg.QueryDatabase.Databases["analytics"].DSN; got != "postgres://reader:pw@db.example.com/analytics" {
+		t.Fatalf("DSN = %q, want the resolved DSN", got)
+	}
+
+	ReplaceSecretsWithReferences(&cfg)
+	if got := cfg.QueryDatabase.Databases["analytics"].DSN; got != "ENV=QUERY_DATABASE_ANALYTICS_DSN" {
+		t.Fatalf("frozen DSN = %q, want an ENV= reference", got)
+	}
+}
@@ -0,0 +1,43 @@
+package tenant
+
+import "time"
+
+// DefaultSLOPercentile is the percentile a LatencyObjective tracks when it
+// sets none.
+const DefaultSLOPercentile = 95
+
+// SLOConfig sets latency objectives for the tenant's generations, measured
+// from when a request is sent to the provider. An objective is breached
+// while its percentile over recent requests is above its threshold. Breaches
+// are counted in the admin metrics and, with AlertWebhook set, posted there
+// along with recoveries.
+type SLOConfig struct {
+	FirstToken   LatencyObjective `json:"first_token" yaml:"first_token"` // Streaming requests only
+	Completion   LatencyObjective `json:"completion" yaml:"completion"`
+	AlertWebhook string           `json:"alert_webhook,omitempty" yaml:"alert_webhook,omitempty"` // http(s) URL
+}
+
+// LatencyObjective is a latency target for a percentile of requests, e.g.
+// p95 under 2000ms. A zero ThresholdMs disables the objective.
+type LatencyObjective struct {
+	Percentile  float64 `json:"percentile,omitempty" yaml:"percentile,omitempty"` // Defaults to 95
+	ThresholdMs int     `json:"threshold_ms,omitempty" yaml:"threshold_ms,omitempty"`
+}
+
+// Enabled reports whether the objective is tracked.
+func (o LatencyObjective) Enabled() bool {
+	return o.ThresholdMs > 0
+}
+
+// EffectivePercentile returns the percentile tracked, applying the default.
+func (o LatencyObjective) EffectivePercentile() float64 {
+	if o.Percentile <= 0 {
+		return DefaultSLOPercentile
+	}
+	return o.Percentile
+}
+
+// Threshold returns the objective's latency limit.
+func (o LatencyObjective) Threshold() time.Duration {
+	return time.Duration(o.ThresholdMs) * time.Millisecond
+}
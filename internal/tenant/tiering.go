@@ -0,0 +1,95 @@
+package tenant
+
+import (
+	"slices"
+	"strings"
+)
+
+// AutoModel is the model_override that asks for the "auto" model tier.
+const AutoModel = "auto"
+
+// Tiers reported for "auto" model tier requests.
+const (
+	TierCheap   = "cheap"
+	TierPremium = "premium"
+)
+
+// Escalation criteria: checks that send an "auto" request to the premium
+// model when the cheap model's reply fails them.
+const (
+	EscalateOnValidation = "validation"     // The reply fails the tenant's validation rules
+	EscalateOnEmpty      = "empty_response" // The reply has no text, tool calls or images
+	EscalateOnUncertain  = "uncertain"      // The reply contains one of the uncertain phrases
+	EscalateOnConfidence = "confidence"     // A verifier model scores the reply below min_confidence
+)
+
+// DefaultEscalateOn is used when a tiering config names no criteria.
+var DefaultEscalateOn = []string{EscalateOnValidation, EscalateOnEmpty, EscalateOnUncertain}
+
+// DefaultUncertainPhrases mark a reply as uncertain, matched case-insensitively.
+var DefaultUncertainPhrases = []string{
+	"i'm not sure",
+	"i am not sure",
+	"i don't know",
+	"i do not know",
+	"i'm unable to",
+	"i am unable to",
+	"i cannot answer",
+	"i can't answer",
+}
+
+// TieringConfig sets up the "auto" model tier: requests are first sent to
+// the provider's cheap model, and only sent again to its configured
+// (premium) model when the cheap reply fails one of the escalation criteria.
+type TieringConfig struct {
+	CheapModels      map[string]string `json:"cheap_models,omitempty" yaml:"cheap_models,omitempty"`           // Provider -> cheap model, e.g. "openai": "gpt-4o-mini"
+	Default          bool              `json:"default,omitempty" yaml:"default,omitempty"`                     // Tier requests that select no model
+	EscalateOn       []string          `json:"escalate_on,omitempty" yaml:"escalate_on,omitempty"`             // Escalation criteria; defaults to validation, empty_response and uncertain
+	UncertainPhrases []string          `json:"uncertain_phrases,omitempty" yaml:"uncertain_phrases,omitempty"` // Defaults to DefaultUncertainPhrases
+	MinConfidence    float64           `json:"min_confidence,omitempty" yaml:"min_confidence,omitempty"`       // Verifier score, 0-1, the "confidence" criterion requires
+	Provider         string            `json:"provider,omitempty" yaml:"provider,omitempty"`                   // Verifier provider; defaults to the one that replied
+	Model            string            `json:"model,omitempty" yaml:"model,omitempty"`                         // Verifier model; defaults to the provider's configured model
+}
+
+// CheapModel returns the cheap model of a provider, if it has one.
+func (t TieringConfig) CheapModel(providerName string) (string, bool) {
+	model := strings.TrimSpace(t.CheapModels[providerName])
+	return model, model != ""
+}
+
+// Criteria returns the escalation criteria in effect.
+func (t TieringConfig) Criteria() []string {
+	if len(t.EscalateOn) == 0 {
+		return DefaultEscalateOn
+	}
+	return t.EscalateOn
+}
+
+// EscalatesOn reports whether criterion is in effect.
+func (t TieringConfig) EscalatesOn(criterion string) bool {
+	return slices.Contains(t.Criteria(), criterion)
+}
+
+// Uncertain returns the first uncertain phrase text contains, or "".
+func (t TieringConfig) Uncertain(text string) string {
+	phrases := t.UncertainPhrases
+	if len(phrases) == 0 {
+		phrases = DefaultUncertainPhrases
+	}
+	lower := strings.ToLower(text)
+	for _, phrase := range phrases {
+		if phrase != "" && strings.Contains(lower, strings.ToLower(phrase)) {
+			return phrase
+		}
+	}
+	return ""
+}
+
+// ValidEscalationCriterion reports whether criterion is a known escalation criterion.
+func ValidEscalationCriterion(criterion string) bool {
+	switch criterion {
+	case EscalateOnValidation, EscalateOnEmpty, EscalateOnUncertain, EscalateOnConfidence:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Package tracing assigns every request a server-side trace ID.
+//
+// The ID is generated when a gRPC call or admin HTTP request arrives,
+// returned to the caller (gRPC header and trailer, HTTP response header),
+// and carried on the request context, where the logctx handler adds it as
+// trace_id to every log line written with that context. Support can then go from the ID a user reports
+// straight to the request's logs and persisted debug data.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the gRPC header and trailer carrying the trace ID.
+const MetadataKey = "x-trace-id"
+
+// HeaderName is the HTTP response header carrying the trace ID.
+const HeaderName = "X-Trace-Id"
+
+// LogKey is the log attribute holding the trace ID.
+const LogKey = "trace_id"
+
+type contextKey struct{}
+
+// NewID returns a random 128-bit trace ID as 32 hex characters.
+func NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithID records a trace ID on ctx.
+func WithID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// IDFromContext returns the trace ID recorded on ctx, or "".
+func IDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// UnaryInterceptor assigns each unary RPC a trace ID and returns it in the
+// response header and trailer. It should be the outermost interceptor so
+// that rejected and panicking requests are traced too.
+func UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := NewID()
+		md := metadata.Pairs(MetadataKey, id)
+		// Errors only mean ctx is not a server RPC context, as in tests
+		_ = grpc.SetHeader(ctx, md)
+		_ = grpc.SetTrailer(ctx, md)
+		return handler(WithID(ctx, id), req)
+	}
+}
+
+// StreamInterceptor assigns each streaming RPC a trace ID and returns it in
+// the response header and trailer.
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := NewID()
+		md := metadata.Pairs(MetadataKey, id)
+		_ = ss.SetHeader(md)
+		ss.SetTrailer(md)
+		return handler(srv, &tracedStream{ServerStream: ss, ctx: WithID(ss.Context(), id)})
+	}
+}
+
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+// Middleware assigns each HTTP request a trace ID and returns it in the
+// X-Trace-Id response header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := NewID()
+		w.Header().Set(HeaderName, id)
+		next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+	})
+}
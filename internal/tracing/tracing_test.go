@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if len(a) != 32 || a == b {
+		t.Errorf("expected distinct 32-character IDs, got %q and %q", a, b)
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	var got string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = IDFromContext(ctx)
+		return nil, nil
+	}
+	if _, err := UnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 32 {
+		t.Errorf("expected trace ID on handler context, got %q", got)
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	header, trailer metadata.MD
+}
+
+func (f *fakeStream) Context() context.Context       { return context.Background() }
+func (f *fakeStream) SetHeader(md metadata.MD) error { f.header = md; return nil }
+func (f *fakeStream) SetTrailer(md metadata.MD)      { f.trailer = md }
+
+func TestStreamInterceptor(t *testing.T) {
+	ss := &fakeStream{}
+	var got string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		got = IDFromContext(stream.Context())
+		return nil
+	}
+	if err := StreamInterceptor()(nil, ss, &grpc.StreamServerInfo{}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == "" {
+		t.Fatal("expected trace ID on stream context")
+	}
+	if h := ss.header.Get(MetadataKey); len(h) != 1 || h[0] != got {
+		t.Errorf("expected trace ID header %q, got %v", got, h)
+	}
+	if tr := ss.trailer.Get(MetadataKey); len(tr) != 1 || tr[0] != got {
+		t.Errorf("expected trace ID trailer %q, got %v", got, tr)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = IDFromContext(r.Context())
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/health", nil))
+	if got == "" || rec.Header().Get(HeaderName) != got {
+		t.Errorf("expected header %q to match context ID %q", rec.Header().Get(HeaderName), got)
+	}
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+)
+
+// URLAllowlist lists internal destinations that custom base URLs may target
+// despite resolving to private IP ranges, such as a corporate gateway that
+// proxies provider traffic. Cloud metadata endpoints stay blocked regardless.
+type URLAllowlist struct {
+	hosts []string // Exact hostnames, or "*.suffix" wildcards
+	nets  []*net.IPNet
+}
+
+// ParseURLAllowlist parses allowlist entries. Each entry is a hostname
+// ("gateway.corp.internal"), a wildcard ("*.corp.internal"), an IP address,
+// or a CIDR ("10.20.0.0/16").
+func ParseURLAllowlist(entries []string) (*URLAllowlist, error) {
+	a := &URLAllowlist{}
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowlist CIDR %q: %w", entry, err)
+			}
+			a.nets = append(a.nets, ipNet)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			a.nets = append(a.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		if strings.ContainsAny(entry, ":@ ") || entry == "*." || strings.Contains(strings.TrimPrefix(entry, "*."), "*") {
+			return nil, fmt.Errorf("invalid allowlist hostname %q", entry)
+		}
+		a.hosts = append(a.hosts, entry)
+	}
+	return a, nil
+}
+
+// allowsHost reports whether the hostname matches a hostname entry.
+func (a *URLAllowlist) allowsHost(hostname string) bool {
+	if a == nil {
+		return false
+	}
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	for _, h := range a.hosts {
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			if strings.HasSuffix(hostname, suffix) {
+				return true
+			}
+		} else if hostname == h {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsIP reports whether the IP falls inside a CIDR entry.
+func (a *URLAllowlist) allowsIP(ip net.IP) bool {
+	if a == nil {
+		return false
+	}
+	for _, n := range a.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether a hostname or IP address matches an entry.
+func (a *URLAllowlist) Allows(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return a.allowsIP(ip)
+	}
+	return a.allowsHost(host)
+}
+
+var urlAllowlist atomic.Pointer[URLAllowlist]
+
+// SetURLAllowlist installs the allowlist ValidateProviderURL honors for
+// provider and file store base URLs. A nil allowlist restores the default
+// rules.
+func SetURLAllowlist(a *URLAllowlist) {
+	urlAllowlist.Store(a)
+}
@@ -0,0 +1,263 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Upload rejection reasons. CheckUpload wraps them with details.
+var (
+	ErrExecutableUpload   = errors.New("executable files are not allowed")
+	ErrFileTypeMismatch   = errors.New("file content does not match its declared type")
+	ErrFileTypeNotAllowed = errors.New("file type is not allowed")
+)
+
+// SniffLen is how many leading bytes of a file CheckUpload inspects.
+const SniffLen = 512
+
+// octetStream is the type of unrecognized binary content, and the type
+// clients send when they do not know a file's type.
+const octetStream = "application/octet-stream"
+
+// oleStorage is the sniffed type of legacy Office (OLE2) documents, which
+// http.DetectContentType does not recognize.
+const oleStorage = "application/x-ole-storage"
+
+// extensionTypes maps file extensions to MIME types.
+var extensionTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".txt":  "text/plain",
+	".md":   "text/markdown",
+	".csv":  "text/csv",
+	".json": "application/json",
+	".xml":  "application/xml",
+	".html": "text/html",
+	".htm":  "text/html",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".rtf":  "application/rtf",
+	".epub": "application/epub+zip",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+// executableExtensions are rejected whatever their content.
+var executableExtensions = []string{
+	".apk", ".app", ".bat", ".cmd", ".com", ".dll", ".dylib", ".exe", ".jar",
+	".msi", ".ps1", ".scr", ".sh", ".so", ".vbs",
+}
+
+// executableTypes are rejected when declared.
+var executableTypes = []string{
+	"application/java-archive",
+	"application/vnd.android.package-archive",
+	"application/vnd.microsoft.portable-executable",
+	"application/x-bat",
+	"application/x-dosexec",
+	"application/x-elf",
+	"application/x-executable",
+	"application/x-mach-binary",
+	"application/x-msdownload",
+	"application/x-msi",
+	"application/x-sh",
+	"application/x-shellscript",
+}
+
+// executableMagic are the leading bytes of native executables and class files.
+var executableMagic = [][]byte{
+	[]byte("\x7fELF"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf}, // Mach-O
+	{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe}, // Mach-O universal, Java class
+	[]byte("#!"),             // Script with an interpreter line
+}
+
+// typeAliases normalizes alternative names for the same type to the name
+// http.DetectContentType reports.
+var typeAliases = map[string]string{
+	"image/jpg":                    "image/jpeg",
+	"audio/wav":                    "audio/wave",
+	"audio/x-wav":                  "audio/wave",
+	"audio/mp3":                    "audio/mpeg",
+	"application/gzip":             "application/x-gzip",
+	"application/x-zip-compressed": "application/zip",
+}
+
+// MIMETypeForFilename returns the MIME type for filename's extension, or ""
+// when the extension is unknown.
+func MIMETypeForFilename(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if t, ok := extensionTypes[ext]; ok {
+		return t
+	}
+	return normalizeType(mime.TypeByExtension(ext))
+}
+
+// SniffMIMEType returns the MIME type detected from a file's first bytes.
+func SniffMIMEType(head []byte) string {
+	if bytes.HasPrefix(head, []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}) {
+		return oleStorage
+	}
+	return normalizeType(http.DetectContentType(head))
+}
+
+// CheckUpload checks an uploaded file's type against its content and
+// returns the MIME type to store it as. head is the start of the file, at
+// least SniffLen bytes unless the file is shorter. Executables are rejected,
+// as are files whose content does not match their declared type or
+// extension. When allowed is non-empty the file's type must match one of its
+// entries, either a MIME type or a "type/*" wildcard.
+func CheckUpload(head []byte, filename, declared string, allowed []string) (string, error) {
+	declared = normalizeType(declared)
+	if declared == octetStream {
+		declared = ""
+	}
+	byExtension := MIMETypeForFilename(filename)
+	sniffed := SniffMIMEType(head)
+
+	if isExecutable(head, sniffed) ||
+		slices.Contains(executableExtensions, strings.ToLower(filepath.Ext(filename))) ||
+		slices.Contains(executableTypes, declared) {
+		return "", fmt.Errorf("%w: %s", ErrExecutableUpload, filename)
+	}
+
+	if declared != "" && !contentMatches(declared, sniffed) {
+		return "", fmt.Errorf("%w: declared %s, content is %s", ErrFileTypeMismatch, declared, sniffed)
+	}
+	if byExtension != "" && !contentMatches(byExtension, sniffed) {
+		return "", fmt.Errorf("%w: %s has extension for %s, content is %s", ErrFileTypeMismatch, filename, byExtension, sniffed)
+	}
+
+	fileType := sniffed
+	switch {
+	case declared != "":
+		fileType = declared
+	case byExtension != "":
+		fileType = byExtension
+	}
+
+	if len(allowed) > 0 && !typeAllowed(fileType, allowed) {
+		return "", fmt.Errorf("%w: %s", ErrFileTypeNotAllowed, fileType)
+	}
+	return fileType, nil
+}
+
+// ValidateAllowedTypes checks allowlist entries for CheckUpload.
+func ValidateAllowedTypes(allowed []string) error {
+	for _, entry := range allowed {
+		major, minor, ok := strings.Cut(entry, "/")
+		if !ok || major == "" || minor == "" || major == "*" {
+			return fmt.Errorf("invalid file type %q, want type/subtype or type/*", entry)
+		}
+	}
+	return nil
+}
+
+// normalizeType lowercases t and drops its parameters.
+func normalizeType(t string) string {
+	t, _, _ = strings.Cut(t, ";")
+	t = strings.ToLower(strings.TrimSpace(t))
+	if alias, ok := typeAliases[t]; ok {
+		return alias
+	}
+	return t
+}
+
+// isExecutable reports whether content is a native executable or script.
+func isExecutable(head []byte, sniffed string) bool {
+	for _, magic := range executableMagic {
+		if bytes.HasPrefix(head, magic) {
+			return true
+		}
+	}
+	// Windows PE; text that happens to start with "MZ" sniffs as text
+	return bytes.HasPrefix(head, []byte("MZ")) && sniffed == octetStream
+}
+
+// contentMatches reports whether content sniffed as sniffed can be a file of
+// type claimed.
+func contentMatches(claimed, sniffed string) bool {
+	if claimed == sniffed {
+		return true
+	}
+	switch {
+	case strings.HasPrefix(sniffed, "text/"):
+		return IsTextType(claimed)
+	case sniffed == "application/zip":
+		return isZipType(claimed)
+	case sniffed == oleStorage:
+		return isOLEType(claimed)
+	case sniffed == octetStream:
+		// Content http.DetectContentType does not recognize can only be a
+		// type it would have recognized if the claim were true
+		return !IsTextType(claimed) && !isZipType(claimed) && !isOLEType(claimed) && !sniffableTypes[claimed]
+	}
+	return false
+}
+
+// sniffableTypes are binary types SniffMIMEType always recognizes.
+var sniffableTypes = map[string]bool{
+	"application/pdf":    true,
+	"application/x-gzip": true,
+	"image/png":          true,
+	"image/jpeg":         true,
+	"image/gif":          true,
+	"image/webp":         true,
+	"image/bmp":          true,
+}
+
+// IsTextType reports whether files of MIME type t are text.
+func IsTextType(t string) bool {
+	switch t {
+	case "application/json", "application/xml", "application/javascript", "application/rtf",
+		"application/x-yaml", "application/yaml", "application/x-ndjson":
+		return true
+	}
+	return strings.HasPrefix(t, "text/") || strings.HasSuffix(t, "+xml") || strings.HasSuffix(t, "+json")
+}
+
+func isZipType(t string) bool {
+	return t == "application/zip" || t == "application/epub+zip" ||
+		strings.HasPrefix(t, "application/vnd.openxmlformats-officedocument.") ||
+		strings.HasPrefix(t, "application/vnd.oasis.opendocument.")
+}
+
+func isOLEType(t string) bool {
+	switch t {
+	case "application/msword", "application/vnd.ms-excel", "application/vnd.ms-powerpoint", "application/vnd.ms-outlook":
+		return true
+	}
+	return false
+}
+
+// typeAllowed reports whether t matches an allowlist entry.
+func typeAllowed(t string, allowed []string) bool {
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == t {
+			return true
+		}
+		if major, ok := strings.CutSuffix(entry, "/*"); ok && strings.HasPrefix(t, major+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckUpload(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj")
+	zip := []byte("PK\x03\x04\x14\x00\x06\x00\x08\x00\x00\x00!\x00")
+	ole := []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1\x00\x00\x00\x00")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	text := []byte("Quarterly results were strong.\n")
+	elf := []byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00")
+	pe := append([]byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00"), make([]byte, 48)...)
+
+	tests := []struct {
+		name     string
+		head     []byte
+		filename string
+		declared string
+		allowed  []string
+		wantType string
+		wantErr  error
+	}{
+		{"pdf", pdf, "report.pdf", "application/pdf", nil, "application/pdf", nil},
+		{"type from extension", pdf, "report.pdf", "", nil, "application/pdf", nil},
+		{"octet-stream declared", pdf, "report.pdf", "application/octet-stream", nil, "application/pdf", nil},
+		{"type from content", pdf, "report", "", nil, "application/pdf", nil},
+		{"docx is a zip", zip, "memo.docx", "", nil, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", nil},
+		{"legacy doc", ole, "memo.doc", "application/msword", nil, "application/msword", nil},
+		{"markdown", text, "notes.md", "text/markdown; charset=utf-8", nil, "text/markdown", nil},
+		{"json", []byte(`{"a": 1}`), "data.json", "", nil, "application/json", nil},
+		{"unsniffable binary", []byte("\x00\x01\x02\x03"), "model.onnx", "application/x-onnx", nil, "application/x-onnx", nil},
+		{"jpg alias", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "photo.jpg", "image/jpg", nil, "image/jpeg", nil},
+
+		{"pdf declared as text", pdf, "notes.txt", "text/plain", nil, "", ErrFileTypeMismatch},
+		{"text named pdf", text, "report.pdf", "", nil, "", ErrFileTypeMismatch},
+		{"png declared as pdf", png, "report.pdf", "application/pdf", nil, "", ErrFileTypeMismatch},
+		{"binary claimed as pdf", []byte("\x00\x01\x02\x03"), "report.pdf", "", nil, "", ErrFileTypeMismatch},
+
+		{"elf", elf, "report.pdf", "", nil, "", ErrExecutableUpload},
+		{"pe", pe, "setup", "", nil, "", ErrExecutableUpload},
+		{"shebang", []byte("#!/bin/sh\nrm -rf /\n"), "notes.txt", "", nil, "", ErrExecutableUpload},
+		{"exe extension", text, "notes.exe", "", nil, "", ErrExecutableUpload},
+		{"executable type", text, "notes", "application/x-msdownload", nil, "", ErrExecutableUpload},
+		{"text starting with MZ", []byte("MZ is a postcode prefix\n"), "notes.txt", "", nil, "text/plain", nil},
+
+		{"allowed exact", pdf, "report.pdf", "", []string{"application/pdf"}, "application/pdf", nil},
+		{"allowed wildcard", text, "notes.md", "", []string{"text/*"}, "text/markdown", nil},
+		{"not allowed", png, "photo.png", "", []string{"application/pdf", "text/*"}, "", ErrFileTypeNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckUpload(tt.head, tt.filename, tt.declared, tt.allowed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.wantType {
+				t.Errorf("type = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestValidateAllowedTypes(t *testing.T) {
+	if err := ValidateAllowedTypes([]string{"application/pdf", "text/*"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, bad := range []string{"pdf", "*/*", "text/", ""} {
+		if err := ValidateAllowedTypes([]string{bad}); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
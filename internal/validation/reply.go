@@ -0,0 +1,280 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ReplyRules are the checks a provider reply must pass before it is returned
+// to the client.
+type ReplyRules struct {
+	NonEmpty      bool           // Reply must contain non-whitespace text
+	RequireJSON   bool           // Reply must be a JSON document (code fences allowed)
+	Schema        map[string]any // JSON Schema subset the document must match; implies RequireJSON
+	BannedStrings []string       // Case-insensitive substrings the reply must not contain
+}
+
+// Enabled reports whether any rule is set.
+func (r ReplyRules) Enabled() bool {
+	return r.NonEmpty || r.RequireJSON || len(r.Schema) > 0 || len(r.BannedStrings) > 0
+}
+
+// Check returns a description of each rule text violates, or nil if it passes.
+func (r ReplyRules) Check(text string) []string {
+	var problems []string
+	if r.NonEmpty && strings.TrimSpace(text) == "" {
+		problems = append(problems, "the response is empty")
+	}
+
+	lower := strings.ToLower(text)
+	for _, banned := range r.BannedStrings {
+		if banned != "" && strings.Contains(lower, strings.ToLower(banned)) {
+			problems = append(problems, fmt.Sprintf("the response contains the banned phrase %q", banned))
+		}
+	}
+
+	if r.RequireJSON || len(r.Schema) > 0 {
+		var doc any
+		if err := json.Unmarshal([]byte(StripCodeFence(text)), &doc); err != nil {
+			problems = append(problems, "the response is not valid JSON: "+err.Error())
+		} else if len(r.Schema) > 0 {
+			problems = append(problems, matchSchema(r.Schema, doc, "$")...)
+		}
+	}
+	return problems
+}
+
+// CorrectiveInstruction builds the follow-up message asking the model to
+// replace a reply that failed validation.
+func CorrectiveInstruction(rules ReplyRules, problems []string) string {
+	var b strings.Builder
+	b.WriteString("Your previous response was rejected because:\n")
+	for _, p := range problems {
+		b.WriteString("- ")
+		b.WriteString(p)
+		b.WriteString("\n")
+	}
+	b.WriteString("\nRespond again to the original request, fixing these problems.")
+	if rules.RequireJSON || len(rules.Schema) > 0 {
+		b.WriteString(" Reply with the JSON document only, without commentary.")
+	}
+	if len(rules.Schema) > 0 {
+		if schema, err := json.Marshal(rules.Schema); err == nil {
+			b.WriteString(" It must match this JSON Schema: ")
+			b.Write(schema)
+		}
+	}
+	return b.String()
+}
+
+// StripCodeFence removes a Markdown code fence wrapping the whole text, as
+// models often add one around JSON.
+func StripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") || len(text) < 6 {
+		return text
+	}
+	text = strings.TrimSuffix(text[3:], "```")
+	if nl := strings.IndexByte(text, '\n'); nl >= 0 {
+		text = text[nl+1:] // Drop the language tag line
+	}
+	return strings.TrimSpace(text)
+}
+
+// schemaKeywords are the JSON Schema keywords matchSchema understands.
+var schemaKeywords = map[string]bool{
+	"type": true, "properties": true, "required": true, "items": true,
+	"enum": true, "additionalProperties": true,
+	"$schema": true, "title": true, "description": true, // Annotations, ignored
+}
+
+// CheckSchema returns an error if schema uses keywords or values reply
+// validation does not support, so misconfigurations surface at load time
+// instead of silently passing every reply.
+func CheckSchema(schema map[string]any) error {
+	return checkSchema(schema, "$")
+}
+
+func checkSchema(schema map[string]any, path string) error {
+	for key, value := range schema {
+		if !schemaKeywords[key] {
+			return fmt.Errorf("%s: unsupported schema keyword %q", path, key)
+		}
+		switch key {
+		case "type":
+			for _, t := range schemaTypes(value) {
+				if !validSchemaType(t) {
+					return fmt.Errorf("%s: unknown type %q", path, t)
+				}
+			}
+		case "properties":
+			props, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s.properties must be an object", path)
+			}
+			for name, sub := range props {
+				subSchema, ok := sub.(map[string]any)
+				if !ok {
+					return fmt.Errorf("%s.properties.%s must be an object", path, name)
+				}
+				if err := checkSchema(subSchema, path+"."+name); err != nil {
+					return err
+				}
+			}
+		case "items":
+			subSchema, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s.items must be an object", path)
+			}
+			if err := checkSchema(subSchema, path+"[]"); err != nil {
+				return err
+			}
+		case "required":
+			if _, ok := stringList(value); !ok {
+				return fmt.Errorf("%s.required must be a list of strings", path)
+			}
+		case "enum":
+			if _, ok := value.([]any); !ok {
+				return fmt.Errorf("%s.enum must be a list", path)
+			}
+		case "additionalProperties":
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%s.additionalProperties must be a boolean", path)
+			}
+		}
+	}
+	return nil
+}
+
+// matchSchema returns the ways doc fails to match schema.
+func matchSchema(schema map[string]any, doc any, path string) []string {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(doc, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return []string{fmt.Sprintf("%s must be of type %s", path, strings.Join(types, " or "))}
+		}
+	}
+
+	var problems []string
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(enum, doc) {
+		problems = append(problems, fmt.Sprintf("%s must be one of %v", path, enum))
+	}
+
+	switch v := doc.(type) {
+	case map[string]any:
+		required, _ := stringList(schema["required"])
+		for _, name := range required {
+			if _, ok := v[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s is missing required field %q", path, name))
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sub, ok := props[name].(map[string]any)
+			if !ok {
+				if extra, set := schema["additionalProperties"].(bool); set && !extra {
+					problems = append(problems, fmt.Sprintf("%s has unexpected field %q", path, name))
+				}
+				continue
+			}
+			problems = append(problems, matchSchema(sub, v[name], path+"."+name)...)
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				problems = append(problems, matchSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// schemaTypes returns the type or list of types a schema allows.
+func schemaTypes(value any) []string {
+	if t, ok := value.(string); ok {
+		return []string{t}
+	}
+	types, _ := stringList(value)
+	return types
+}
+
+func validSchemaType(t string) bool {
+	switch t {
+	case "object", "array", "string", "number", "integer", "boolean", "null":
+		return true
+	}
+	return false
+}
+
+func hasType(doc any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := doc.(map[string]any)
+		return ok
+	case "array":
+		_, ok := doc.([]any)
+		return ok
+	case "string":
+		_, ok := doc.(string)
+		return ok
+	case "number":
+		_, ok := doc.(float64)
+		return ok
+	case "integer":
+		n, ok := doc.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := doc.(bool)
+		return ok
+	case "null":
+		return doc == nil
+	}
+	return false
+}
+
+func inEnum(enum []any, doc any) bool {
+	for _, v := range enum {
+		// YAML decodes integers as int, JSON documents as float64
+		if n, ok := v.(int); ok {
+			v = float64(n)
+		}
+		if reflect.DeepEqual(v, doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList converts a decoded list of strings.
+func stringList(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	}
+	return nil, false
+}
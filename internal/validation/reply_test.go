@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+var personSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"name", "age"},
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string"},
+		"age":  map[string]any{"type": "integer"},
+		"role": map[string]any{"enum": []any{"admin", "user"}},
+		"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	},
+	"additionalProperties": false,
+}
+
+func TestReplyRules_Check(t *testing.T) {
+	tests := []struct {
+		name         string
+		rules        ReplyRules
+		text         string
+		wantProblems int
+	}{
+		{"empty reply", ReplyRules{NonEmpty: true}, "  \n", 1},
+		{"non-empty reply", ReplyRules{NonEmpty: true}, "hello", 0},
+		{"banned phrase, any case", ReplyRules{BannedStrings: []string{"As an AI"}}, "as an ai language model...", 1},
+		{"invalid JSON", ReplyRules{RequireJSON: true}, "{name: bob}", 1},
+		{"fenced JSON", ReplyRules{RequireJSON: true}, "```json\n{\"name\": \"bob\"}\n```", 0},
+		{"matches schema", ReplyRules{Schema: personSchema}, `{"name": "bob", "age": 42, "role": "admin", "tags": ["a"]}`, 0},
+		{"wrong root type", ReplyRules{Schema: personSchema}, `["bob"]`, 1},
+		{"missing required field", ReplyRules{Schema: personSchema}, `{"name": "bob"}`, 1},
+		{"non-integer", ReplyRules{Schema: personSchema}, `{"name": "bob", "age": 4.5}`, 1},
+		{"enum, item type and extra field", ReplyRules{Schema: personSchema}, `{"name": "bob", "age": 1, "role": "root", "tags": [1], "x": 1}`, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Check(tt.text); len(got) != tt.wantProblems {
+				t.Errorf("Check() = %q, want %d problems", got, tt.wantProblems)
+			}
+		})
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	if err := CheckSchema(personSchema); err != nil {
+		t.Errorf("CheckSchema(personSchema) = %v", err)
+	}
+	bad := []map[string]any{
+		{"type": "text"},
+		{"pattern": "^a"},
+		{"properties": map[string]any{"a": map[string]any{"minLength": 1}}},
+		{"required": "name"},
+	}
+	for _, schema := range bad {
+		if err := CheckSchema(schema); err == nil {
+			t.Errorf("CheckSchema(%v) = nil, want error", schema)
+		}
+	}
+}
+
+func TestCorrectiveInstruction(t *testing.T) {
+	got := CorrectiveInstruction(ReplyRules{Schema: personSchema}, []string{`$ is missing required field "age"`})
+	for _, want := range []string{`missing required field "age"`, "JSON document only", `"required":["name","age"]`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("instruction %q does not contain %q", got, want)
+		}
+	}
+}
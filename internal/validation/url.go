@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"syscall"
 )
 
 var (
@@ -44,8 +45,11 @@ var dangerousProtocols = map[string]bool{
 // lookupIP allows tests to stub DNS resolution.
 var lookupIP = net.LookupIP
 
-// validateHostnameResolvesPublic checks that a hostname doesn't resolve to private/metadata IPs.
-func validateHostnameResolvesPublic(hostname string) error {
+// validateHostnameResolvesPublic checks that a hostname doesn't resolve to
+// private/metadata IPs. Private IPs are accepted for allowlisted hostnames or
+// inside an allowlisted CIDR.
+func validateHostnameResolvesPublic(hostname string, allow *URLAllowlist) error {
+	hostAllowed := allow.allowsHost(hostname)
 	ips, err := lookupIP(hostname)
 	if err != nil {
 		return fmt.Errorf("%w: DNS lookup failed for %s: %v", ErrInvalidURL, hostname, err)
@@ -61,7 +65,7 @@ func validateHostnameResolvesPublic(hostname string) error {
 		if isMetadataEndpoint(ip.String()) {
 			return fmt.Errorf("%w: %s resolves to metadata IP %s", ErrMetadataEndpoint, hostname, ip.String())
 		}
-		if ip.IsLoopback() || isPrivateIP(ip) {
+		if (ip.IsLoopback() || isPrivateIP(ip)) && !hostAllowed && !allow.allowsIP(ip) {
 			return fmt.Errorf("%w: %s resolves to private IP %s", ErrPrivateIP, hostname, ip.String())
 		}
 	}
@@ -76,7 +80,25 @@ func validateHostnameResolvesPublic(hostname string) error {
 // - Blocking dangerous protocols (file://, gopher://, javascript:, data:, etc.)
 // - Blocking private/internal IP ranges (10.x, 172.16.x, 192.168.x)
 // - Blocking cloud metadata endpoints (169.254.169.254)
+//
+// Hostnames and CIDRs in the URL allowlist (see SetURLAllowlist) are exempt
+// from the private IP checks; protocol and metadata checks still apply. The
+// allowlist is meant for self-hosted providers and file stores only; URLs
+// that tenants or models supply are checked with ValidateExternalURL.
 func ValidateProviderURL(rawURL string) error {
+	return validateURL(rawURL, urlAllowlist.Load())
+}
+
+// ValidateExternalURL applies the checks of ValidateProviderURL to a URL a
+// tenant or model supplies, such as a remote tool endpoint, without the
+// operator's URL allowlist: internal ranges allowed for providers stay
+// unreachable.
+func ValidateExternalURL(rawURL string) error {
+	return validateURL(rawURL, nil)
+}
+
+// validateURL checks rawURL, exempting allow's entries from the private IP checks.
+func validateURL(rawURL string, allow *URLAllowlist) error {
 	rawURL = strings.TrimSpace(rawURL)
 	if rawURL == "" {
 		return ErrEmptyURL
@@ -129,14 +151,14 @@ func ValidateProviderURL(rawURL string) error {
 		}
 
 		// Block private IPs
-		if isPrivateIP(ip) {
+		if isPrivateIP(ip) && !allow.allowsIP(ip) {
 			return fmt.Errorf("%w: %s is in a private IP range", ErrPrivateIP, hostname)
 		}
 	}
 
 	// For non-IP hostnames (not localhost), verify they don't resolve to private IPs
 	if ip == nil && !isLocalhost {
-		if err := validateHostnameResolvesPublic(hostname); err != nil {
+		if err := validateHostnameResolvesPublic(hostname, allow); err != nil {
 			return err
 		}
 	}
@@ -226,3 +248,37 @@ func isPrivateIP(ip net.IP) bool {
 
 	return false
 }
+
+// CheckPublicHost reports why host, a hostname or IP address, must not be
+// connected to for content a model chose: it is a cloud metadata endpoint,
+// localhost, or a private, loopback or link-local address. Hostnames are not
+// resolved here; connections dialed with DialControl check the addresses
+// they resolve to, so a hostname cannot rebind to an internal address after
+// it was checked. The URL allowlist does not apply.
+func CheckPublicHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if isMetadataEndpoint(host) {
+		return fmt.Errorf("%w: %s is blocked", ErrMetadataEndpoint, host)
+	}
+	if isLocalhostHost(host) {
+		return fmt.Errorf("%w: %s is a loopback address", ErrPrivateIP, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return fmt.Errorf("%w: %s is in a private IP range", ErrPrivateIP, host)
+	}
+	return nil
+}
+
+// DialControl is a net.Dialer Control function that refuses connections to
+// the addresses CheckPublicHost rejects. It runs on every address a dial
+// tries, after DNS resolution.
+func DialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%w: dialed %s, not an IP address", ErrInvalidURL, host)
+	}
+	return CheckPublicHost(host)
+}
@@ -356,3 +356,85 @@ func TestValidateProviderURL_ResolvesMetadataIP(t *testing.T) {
 		t.Fatalf("expected ErrMetadataEndpoint, got %v", err)
 	}
 }
+
+func TestValidateProviderURL_Allowlist(t *testing.T) {
+	originalLookup := lookupIP
+	lookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "gateway.corp.internal", "llm.corp.internal":
+			return []net.IP{net.ParseIP("10.20.0.5")}, nil
+		case "sneaky.corp.internal":
+			return []net.IP{net.ParseIP("169.254.169.254")}, nil
+		default:
+			return []net.IP{net.ParseIP("192.168.1.10")}, nil
+		}
+	}
+	allow, err := ParseURLAllowlist([]string{"*.corp.internal", "172.16.0.0/12", "192.168.1.10"})
+	if err != nil {
+		t.Fatalf("ParseURLAllowlist failed: %v", err)
+	}
+	SetURLAllowlist(allow)
+	t.Cleanup(func() {
+		lookupIP = originalLookup
+		SetURLAllowlist(nil)
+	})
+
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"https://gateway.corp.internal/v1", nil},
+		{"https://172.20.1.1:8443/v1", nil},
+		{"https://other.example.test", nil}, // Resolves to the allowlisted 192.168.1.10
+		{"https://10.0.0.1/v1", ErrPrivateIP},
+		{"https://sneaky.corp.internal", ErrMetadataEndpoint},
+		{"https://169.254.169.254", ErrMetadataEndpoint},
+		{"http://gateway.corp.internal", ErrHTTPNotAllowed},
+	}
+	for _, tt := range tests {
+		if err := ValidateProviderURL(tt.url); !errors.Is(err, tt.wantErr) {
+			t.Errorf("ValidateProviderURL(%q) = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+
+	// URLs tenants and models supply never use the allowlist
+	for _, url := range []string{"https://gateway.corp.internal/v1", "https://172.20.1.1:8443/v1"} {
+		if err := ValidateExternalURL(url); !errors.Is(err, ErrPrivateIP) {
+			t.Errorf("ValidateExternalURL(%q) = %v, want ErrPrivateIP", url, err)
+		}
+	}
+
+	SetURLAllowlist(nil)
+	if err := ValidateProviderURL("https://gateway.corp.internal/v1"); !errors.Is(err, ErrPrivateIP) {
+		t.Errorf("without allowlist got %v, want ErrPrivateIP", err)
+	}
+}
+
+func TestParseURLAllowlist_Invalid(t *testing.T) {
+	for _, entry := range []string{"10.0.0.0/33", "gateway:8443", "*.", "a.*.internal"} {
+		if _, err := ParseURLAllowlist([]string{entry}); err == nil {
+			t.Errorf("ParseURLAllowlist(%q) succeeded, want error", entry)
+		}
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr error
+	}{
+		{"93.184.216.34:443", nil},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", nil},
+		{"10.1.2.3:443", ErrPrivateIP},
+		{"127.0.0.1:443", ErrPrivateIP},
+		{"[::1]:443", ErrPrivateIP},
+		{"[::ffff:192.168.1.1]:443", ErrPrivateIP},
+		{"169.254.169.254:80", ErrMetadataEndpoint},
+		{"pages.example.com:443", ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		if err := DialControl("tcp", tt.address, nil); !errors.Is(err, tt.wantErr) {
+			t.Errorf("DialControl(%q) = %v, want %v", tt.address, err, tt.wantErr)
+		}
+	}
+}
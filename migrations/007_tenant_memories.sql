@@ -0,0 +1,67 @@
+-- ============================================================================
+-- AIRBORNE TENANT-PREFIXED MEMORIES TABLES MIGRATION
+-- ============================================================================
+-- Purpose: Store durable facts about users (names, preferences) that are
+--          extracted after each turn and injected into future prompts
+-- Tables: memories
+-- Run: psql -d airborne -f migrations/007_tenant_memories.sql
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- AI8 MEMORIES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS ai8_airborne_memories (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         TEXT NOT NULL,
+    category        TEXT NOT NULL DEFAULT 'other',  -- name, preference, profile, relationship, other
+    content         TEXT NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),      -- Last time the fact was observed
+    UNIQUE (user_id, content)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai8_memories_user_updated ON ai8_airborne_memories(user_id, updated_at DESC);
+
+COMMENT ON TABLE ai8_airborne_memories IS 'AI8 tenant per-user conversation memory facts';
+
+-- ----------------------------------------------------------------------------
+-- EMAIL4AI MEMORIES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS email4ai_airborne_memories (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         TEXT NOT NULL,
+    category        TEXT NOT NULL DEFAULT 'other',
+    content         TEXT NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (user_id, content)
+);
+
+CREATE INDEX IF NOT EXISTS idx_email4ai_memories_user_updated ON email4ai_airborne_memories(user_id, updated_at DESC);
+
+COMMENT ON TABLE email4ai_airborne_memories IS 'Email4AI tenant per-user conversation memory facts';
+
+-- ----------------------------------------------------------------------------
+-- ZZTEST MEMORIES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS zztest_airborne_memories (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         TEXT NOT NULL,
+    category        TEXT NOT NULL DEFAULT 'other',
+    content         TEXT NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (user_id, content)
+);
+
+CREATE INDEX IF NOT EXISTS idx_zztest_memories_user_updated ON zztest_airborne_memories(user_id, updated_at DESC);
+
+COMMENT ON TABLE zztest_airborne_memories IS 'Test tenant per-user conversation memory facts';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS ai8_airborne_memories;
+-- DROP TABLE IF EXISTS email4ai_airborne_memories;
+-- DROP TABLE IF EXISTS zztest_airborne_memories;
@@ -0,0 +1,42 @@
+-- ============================================================================
+-- AIRBORNE ACTIVITY ROLLUPS MIGRATION
+-- ============================================================================
+-- Purpose: Pre-aggregated usage per tenant/provider/model so dashboard stats
+--          do not scan raw message tables. Maintained by the background
+--          rollup aggregator (internal/db/aggregator.go); safe to truncate,
+--          the aggregator rebuilds it from the message tables.
+-- Tables: airborne_activity_rollups (shared, keyed by tenant_id)
+-- Run: psql -d airborne -f migrations/008_activity_rollups.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS airborne_activity_rollups (
+    granularity         TEXT NOT NULL,              -- hour, day
+    bucket_start        TIMESTAMPTZ NOT NULL,       -- UTC start of the hour/day
+    tenant_id           TEXT NOT NULL,
+    provider            TEXT NOT NULL,
+    model               TEXT NOT NULL,
+
+    request_count       BIGINT NOT NULL DEFAULT 0,  -- Assistant messages in the bucket
+    failed_count        BIGINT NOT NULL DEFAULT 0,  -- Of which "[FAILED] " responses
+    input_tokens        BIGINT NOT NULL DEFAULT 0,
+    output_tokens       BIGINT NOT NULL DEFAULT 0,
+    cost_usd            DOUBLE PRECISION NOT NULL DEFAULT 0,
+    grounding_queries   BIGINT NOT NULL DEFAULT 0,
+    grounding_cost_usd  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    processing_time_ms  BIGINT NOT NULL DEFAULT 0,  -- Sum; divide by request_count for average
+
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (granularity, bucket_start, tenant_id, provider, model),
+    CONSTRAINT valid_rollup_granularity CHECK (granularity IN ('hour', 'day'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_rollups_bucket ON airborne_activity_rollups(granularity, bucket_start DESC);
+
+COMMENT ON TABLE airborne_activity_rollups IS 'Hourly and daily usage rollups per tenant/provider/model';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS airborne_activity_rollups;
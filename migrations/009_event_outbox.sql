@@ -0,0 +1,38 @@
+-- ============================================================================
+-- AIRBORNE EVENT OUTBOX MIGRATION
+-- ============================================================================
+-- Purpose: Transactional outbox for webhooks and analytics events. Events are
+--          written in the same transaction as the conversation turn they
+--          describe, then delivered at-least-once by the event dispatcher
+--          (internal/events/dispatcher.go), so a crash never loses an event.
+-- Tables: airborne_event_outbox (shared, keyed by tenant_id)
+-- Run: psql -d airborne -f migrations/009_event_outbox.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS airborne_event_outbox (
+    id               UUID PRIMARY KEY,
+    tenant_id        TEXT NOT NULL,
+    event_type       TEXT NOT NULL,                   -- e.g. generation.completed
+    payload          JSONB NOT NULL,
+
+    status           TEXT NOT NULL DEFAULT 'pending', -- pending, delivered, dead
+    attempts         INTEGER NOT NULL DEFAULT 0,      -- Delivery attempts claimed so far
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error       TEXT,
+
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ,
+
+    CONSTRAINT valid_outbox_status CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+
+-- Dispatcher polls for due pending events
+CREATE INDEX IF NOT EXISTS idx_event_outbox_due ON airborne_event_outbox(status, next_attempt_at);
+
+COMMENT ON TABLE airborne_event_outbox IS 'Transactional outbox for at-least-once event delivery';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS airborne_event_outbox;
@@ -0,0 +1,38 @@
+-- ============================================================================
+-- AIRBORNE KEY USAGE ATTRIBUTION MIGRATION
+-- ============================================================================
+-- Purpose: Attribute usage to the client API key that made each request, so
+--          tenants handing out several keys can see which team spends what.
+--          key_id holds a hash of the key ID (see ClientKey.KeyHash), never
+--          the key ID itself, which is part of the API key.
+-- Tables: {tenant}_airborne_messages, airborne_activity_rollups
+-- Run: psql -d airborne -f migrations/010_key_usage_attribution.sql
+-- ============================================================================
+
+ALTER TABLE ai8_airborne_messages ADD COLUMN IF NOT EXISTS key_id TEXT;
+ALTER TABLE email4ai_airborne_messages ADD COLUMN IF NOT EXISTS key_id TEXT;
+ALTER TABLE zztest_airborne_messages ADD COLUMN IF NOT EXISTS key_id TEXT;
+
+COMMENT ON COLUMN ai8_airborne_messages.key_id IS 'Hashed client API key ID (assistant messages)';
+COMMENT ON COLUMN email4ai_airborne_messages.key_id IS 'Hashed client API key ID (assistant messages)';
+COMMENT ON COLUMN zztest_airborne_messages.key_id IS 'Hashed client API key ID (assistant messages)';
+
+-- Rollups gain key_id as part of their identity. Empty string is unattributed
+-- usage (static-token auth and messages recorded before this migration).
+ALTER TABLE airborne_activity_rollups ADD COLUMN IF NOT EXISTS key_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE airborne_activity_rollups DROP CONSTRAINT IF EXISTS airborne_activity_rollups_pkey;
+ALTER TABLE airborne_activity_rollups ADD PRIMARY KEY (granularity, bucket_start, tenant_id, provider, model, key_id);
+
+COMMENT ON COLUMN airborne_activity_rollups.key_id IS 'Hashed client API key ID; empty for unattributed usage';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- TRUNCATE airborne_activity_rollups;  -- rebuilt by the rollup aggregator
+-- ALTER TABLE airborne_activity_rollups DROP CONSTRAINT airborne_activity_rollups_pkey;
+-- ALTER TABLE airborne_activity_rollups DROP COLUMN key_id;
+-- ALTER TABLE airborne_activity_rollups ADD PRIMARY KEY (granularity, bucket_start, tenant_id, provider, model);
+-- ALTER TABLE ai8_airborne_messages DROP COLUMN key_id;
+-- ALTER TABLE email4ai_airborne_messages DROP COLUMN key_id;
+-- ALTER TABLE zztest_airborne_messages DROP COLUMN key_id;
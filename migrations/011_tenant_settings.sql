@@ -0,0 +1,42 @@
+-- ============================================================================
+-- AIRBORNE TENANT SETTINGS MIGRATION
+-- ============================================================================
+-- Purpose: Runtime tenant settings changed through the admin API (provider
+--          models and sampling, failover order, feature flags), kept as
+--          overrides on top of the tenant config files so they survive
+--          restarts, plus an audit trail of every change.
+-- Tables: airborne_tenant_overrides, airborne_tenant_settings_audit (shared,
+--         keyed by tenant_id)
+-- Run: psql -d airborne -f migrations/011_tenant_settings.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS airborne_tenant_overrides (
+    tenant_id   TEXT PRIMARY KEY,
+    settings    JSONB NOT NULL,                 -- Merged tenant.SettingsUpdate
+    updated_by  TEXT NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS airborne_tenant_settings_audit (
+    id          UUID PRIMARY KEY,
+    tenant_id   TEXT NOT NULL,
+    actor       TEXT NOT NULL,                  -- Operator named in the request
+    remote_addr TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL DEFAULT '',
+    change      JSONB NOT NULL,                 -- The update as submitted
+    before      JSONB NOT NULL,                 -- Masked settings before the change
+    after       JSONB NOT NULL,                 -- Masked settings after the change
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_settings_audit_tenant ON airborne_tenant_settings_audit(tenant_id, created_at DESC);
+
+COMMENT ON TABLE airborne_tenant_overrides IS 'Runtime tenant settings applied on top of config files';
+COMMENT ON TABLE airborne_tenant_settings_audit IS 'Audit trail of runtime tenant settings changes';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS airborne_tenant_settings_audit;
+-- DROP TABLE IF EXISTS airborne_tenant_overrides;
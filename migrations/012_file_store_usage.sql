@@ -0,0 +1,27 @@
+-- ============================================================================
+-- AIRBORNE FILE STORE USAGE MIGRATION
+-- ============================================================================
+-- Purpose: Store registry of file counts and bytes uploaded to each file
+--          store, used to enforce per-tenant upload limits (files per store,
+--          total storage).
+-- Tables: airborne_file_store_usage (shared, keyed by tenant_id and store_id)
+-- Run: psql -d airborne -f migrations/012_file_store_usage.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS airborne_file_store_usage (
+    tenant_id   TEXT NOT NULL,
+    store_id    TEXT NOT NULL,
+    provider    TEXT NOT NULL,                  -- openai, gemini or internal
+    file_count  INTEGER NOT NULL DEFAULT 0,
+    total_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, store_id)
+);
+
+COMMENT ON TABLE airborne_file_store_usage IS 'Files and bytes uploaded per file store, for tenant upload limits';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS airborne_file_store_usage;
@@ -0,0 +1,26 @@
+-- ============================================================================
+-- AIRBORNE SUPERSEDED MESSAGES MIGRATION
+-- ============================================================================
+-- Purpose: Keep the messages replaced when a turn is edited and regenerated
+--          (RegenerateMessage). superseded_at is set on the old branch;
+--          conversation reads skip those messages, while debug views and
+--          usage accounting still see them.
+-- Tables: {tenant}_airborne_messages
+-- Run: psql -d airborne -f migrations/013_superseded_messages.sql
+-- ============================================================================
+
+ALTER TABLE ai8_airborne_messages ADD COLUMN IF NOT EXISTS superseded_at TIMESTAMPTZ;
+ALTER TABLE email4ai_airborne_messages ADD COLUMN IF NOT EXISTS superseded_at TIMESTAMPTZ;
+ALTER TABLE zztest_airborne_messages ADD COLUMN IF NOT EXISTS superseded_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN ai8_airborne_messages.superseded_at IS 'When the message was replaced by a regenerated turn; NULL for the current branch';
+COMMENT ON COLUMN email4ai_airborne_messages.superseded_at IS 'When the message was replaced by a regenerated turn; NULL for the current branch';
+COMMENT ON COLUMN zztest_airborne_messages.superseded_at IS 'When the message was replaced by a regenerated turn; NULL for the current branch';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration (superseded messages reappear in threads):
+-- ALTER TABLE ai8_airborne_messages DROP COLUMN superseded_at;
+-- ALTER TABLE email4ai_airborne_messages DROP COLUMN superseded_at;
+-- ALTER TABLE zztest_airborne_messages DROP COLUMN superseded_at;
@@ -0,0 +1,58 @@
+-- ============================================================================
+-- AIRBORNE MESSAGE BRANCHES MIGRATION
+-- ============================================================================
+-- Purpose: Turn each thread into a tree of messages so one user turn can have
+--          several assistant alternatives (RegenerateMessage, ListBranches,
+--          SelectBranch). parent_message_id is the previous message on the
+--          message's branch; NULL for a thread's first message. Messages off
+--          the active branch are marked with superseded_at (migration 013).
+-- Tables: {tenant}_airborne_messages
+-- Run: psql -d airborne -f migrations/014_message_branches.sql
+-- ============================================================================
+
+ALTER TABLE ai8_airborne_messages ADD COLUMN IF NOT EXISTS parent_message_id UUID;
+ALTER TABLE email4ai_airborne_messages ADD COLUMN IF NOT EXISTS parent_message_id UUID;
+ALTER TABLE zztest_airborne_messages ADD COLUMN IF NOT EXISTS parent_message_id UUID;
+
+COMMENT ON COLUMN ai8_airborne_messages.parent_message_id IS 'Previous message on this branch of the thread; NULL for the first message';
+COMMENT ON COLUMN email4ai_airborne_messages.parent_message_id IS 'Previous message on this branch of the thread; NULL for the first message';
+COMMENT ON COLUMN zztest_airborne_messages.parent_message_id IS 'Previous message on this branch of the thread; NULL for the first message';
+
+CREATE INDEX IF NOT EXISTS idx_ai8_messages_parent ON ai8_airborne_messages(thread_id, parent_message_id);
+CREATE INDEX IF NOT EXISTS idx_email4ai_messages_parent ON email4ai_airborne_messages(thread_id, parent_message_id);
+CREATE INDEX IF NOT EXISTS idx_zztest_messages_parent ON zztest_airborne_messages(thread_id, parent_message_id);
+
+-- Existing threads are linear: each message's parent is the one before it.
+-- A turn's user and assistant messages share created_at, so the user message
+-- sorts first.
+UPDATE ai8_airborne_messages m SET parent_message_id = prev.parent_id
+FROM (
+    SELECT id, LAG(id) OVER (PARTITION BY thread_id ORDER BY created_at, CASE WHEN role = 'user' THEN 0 ELSE 1 END, id) AS parent_id
+    FROM ai8_airborne_messages
+) prev
+WHERE m.id = prev.id AND m.parent_message_id IS NULL AND prev.parent_id IS NOT NULL;
+
+UPDATE email4ai_airborne_messages m SET parent_message_id = prev.parent_id
+FROM (
+    SELECT id, LAG(id) OVER (PARTITION BY thread_id ORDER BY created_at, CASE WHEN role = 'user' THEN 0 ELSE 1 END, id) AS parent_id
+    FROM email4ai_airborne_messages
+) prev
+WHERE m.id = prev.id AND m.parent_message_id IS NULL AND prev.parent_id IS NOT NULL;
+
+UPDATE zztest_airborne_messages m SET parent_message_id = prev.parent_id
+FROM (
+    SELECT id, LAG(id) OVER (PARTITION BY thread_id ORDER BY created_at, CASE WHEN role = 'user' THEN 0 ELSE 1 END, id) AS parent_id
+    FROM zztest_airborne_messages
+) prev
+WHERE m.id = prev.id AND m.parent_message_id IS NULL AND prev.parent_id IS NOT NULL;
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP INDEX IF EXISTS idx_ai8_messages_parent;
+-- DROP INDEX IF EXISTS idx_email4ai_messages_parent;
+-- DROP INDEX IF EXISTS idx_zztest_messages_parent;
+-- ALTER TABLE ai8_airborne_messages DROP COLUMN parent_message_id;
+-- ALTER TABLE email4ai_airborne_messages DROP COLUMN parent_message_id;
+-- ALTER TABLE zztest_airborne_messages DROP COLUMN parent_message_id;
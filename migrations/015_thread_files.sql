@@ -0,0 +1,79 @@
+-- ============================================================================
+-- AIRBORNE THREAD FILES MIGRATION
+-- ============================================================================
+-- Purpose: Register files attached to dashboard chat threads through the
+--          Gemini Files API. Gemini deletes uploads after 48 hours, so each
+--          file records its expiry and keeps its content (up to 20 MB) for
+--          re-upload when an expired URI is used again. original_uri is the
+--          URI the file was first uploaded under, which clients keep using.
+-- Tables: {tenant}_airborne_thread_files
+-- Run: psql -d airborne -f migrations/015_thread_files.sql
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- AI8 THREAD FILES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS ai8_airborne_thread_files (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    thread_id       UUID NOT NULL REFERENCES ai8_airborne_threads(id) ON DELETE CASCADE,
+    original_uri    TEXT NOT NULL,
+    file_uri        TEXT NOT NULL,                  -- Current Gemini file URI
+    filename        TEXT NOT NULL,
+    mime_type       TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    content         BYTEA,                          -- NULL when too large to keep
+    expires_at      TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (thread_id, original_uri)
+);
+
+COMMENT ON TABLE ai8_airborne_thread_files IS 'AI8 tenant Gemini files attached to chat threads';
+
+-- ----------------------------------------------------------------------------
+-- EMAIL4AI THREAD FILES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS email4ai_airborne_thread_files (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    thread_id       UUID NOT NULL REFERENCES email4ai_airborne_threads(id) ON DELETE CASCADE,
+    original_uri    TEXT NOT NULL,
+    file_uri        TEXT NOT NULL,
+    filename        TEXT NOT NULL,
+    mime_type       TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    content         BYTEA,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (thread_id, original_uri)
+);
+
+COMMENT ON TABLE email4ai_airborne_thread_files IS 'Email4AI tenant Gemini files attached to chat threads';
+
+-- ----------------------------------------------------------------------------
+-- ZZTEST THREAD FILES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS zztest_airborne_thread_files (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    thread_id       UUID NOT NULL REFERENCES zztest_airborne_threads(id) ON DELETE CASCADE,
+    original_uri    TEXT NOT NULL,
+    file_uri        TEXT NOT NULL,
+    filename        TEXT NOT NULL,
+    mime_type       TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    content         BYTEA,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (thread_id, original_uri)
+);
+
+COMMENT ON TABLE zztest_airborne_thread_files IS 'Test tenant Gemini files attached to chat threads';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS ai8_airborne_thread_files;
+-- DROP TABLE IF EXISTS email4ai_airborne_thread_files;
+-- DROP TABLE IF EXISTS zztest_airborne_thread_files;
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- AIRBORNE PROVIDER CALL LEDGER MIGRATION
+-- ============================================================================
+-- Purpose: One row per provider API call, successful or failed, with the
+--          tokens, estimated cost and HTTP status, recorded independently of
+--          message persistence so usage can be reconciled against provider
+--          invoices.
+-- Tables: airborne_provider_calls (shared, keyed by id)
+-- Run: psql -d airborne -f migrations/016_provider_call_ledger.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS airborne_provider_calls (
+    id            UUID PRIMARY KEY,
+    tenant_id     TEXT NOT NULL,
+    request_id    TEXT NOT NULL DEFAULT '',
+    provider      TEXT NOT NULL,                -- openai, gemini, anthropic, ...
+    model         TEXT NOT NULL DEFAULT '',
+    key_id        TEXT NOT NULL DEFAULT '',     -- Hashed client API key ID
+    input_tokens  INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    cost_usd      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    http_status   INTEGER NOT NULL DEFAULT 0,   -- 0 when the call failed without a response
+    error         TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_provider_calls_created ON airborne_provider_calls(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_provider_calls_tenant ON airborne_provider_calls(tenant_id, created_at DESC);
+
+COMMENT ON TABLE airborne_provider_calls IS 'Every provider API call, for reconciliation against provider invoices';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS airborne_provider_calls;
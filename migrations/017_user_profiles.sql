@@ -0,0 +1,62 @@
+-- ============================================================================
+-- AIRBORNE USER PROFILES MIGRATION
+-- ============================================================================
+-- Purpose: Store per-user personalization profiles (display name, preferred
+--          language, tone, custom instructions) that are merged into the
+--          instructions of every request carrying the user's ID
+-- Tables: {tenant}_airborne_user_profiles
+-- Run: psql -d airborne -f migrations/017_user_profiles.sql
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- AI8 USER PROFILES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS ai8_airborne_user_profiles (
+    user_id             TEXT PRIMARY KEY,
+    display_name        TEXT NOT NULL DEFAULT '',
+    language            TEXT NOT NULL DEFAULT '',   -- Preferred reply language, e.g. "German" or "de"
+    tone                TEXT NOT NULL DEFAULT '',   -- e.g. "formal", "friendly"
+    custom_instructions TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ DEFAULT NOW()
+);
+
+COMMENT ON TABLE ai8_airborne_user_profiles IS 'AI8 tenant per-user personalization profiles';
+
+-- ----------------------------------------------------------------------------
+-- EMAIL4AI USER PROFILES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS email4ai_airborne_user_profiles (
+    user_id             TEXT PRIMARY KEY,
+    display_name        TEXT NOT NULL DEFAULT '',
+    language            TEXT NOT NULL DEFAULT '',
+    tone                TEXT NOT NULL DEFAULT '',
+    custom_instructions TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ DEFAULT NOW()
+);
+
+COMMENT ON TABLE email4ai_airborne_user_profiles IS 'Email4AI tenant per-user personalization profiles';
+
+-- ----------------------------------------------------------------------------
+-- ZZTEST USER PROFILES
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS zztest_airborne_user_profiles (
+    user_id             TEXT PRIMARY KEY,
+    display_name        TEXT NOT NULL DEFAULT '',
+    language            TEXT NOT NULL DEFAULT '',
+    tone                TEXT NOT NULL DEFAULT '',
+    custom_instructions TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ DEFAULT NOW()
+);
+
+COMMENT ON TABLE zztest_airborne_user_profiles IS 'Test tenant per-user personalization profiles';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS ai8_airborne_user_profiles;
+-- DROP TABLE IF EXISTS email4ai_airborne_user_profiles;
+-- DROP TABLE IF EXISTS zztest_airborne_user_profiles;
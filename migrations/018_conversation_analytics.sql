@@ -0,0 +1,101 @@
+-- ============================================================================
+-- AIRBORNE CONVERSATION ANALYTICS MIGRATION
+-- ============================================================================
+-- Purpose: Store the intent, entities and topics extracted from user messages
+--          by the background analytics job, for aggregate intent and topic
+--          reports
+-- Tables: {tenant}_airborne_message_analytics, {tenant}_airborne_message_topics
+-- Run: psql -d airborne -f migrations/018_conversation_analytics.sql
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- AI8 CONVERSATION ANALYTICS
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS ai8_airborne_message_analytics (
+    message_id           UUID PRIMARY KEY REFERENCES ai8_airborne_messages(id) ON DELETE CASCADE,
+    thread_id            UUID NOT NULL,
+    intent               TEXT NOT NULL DEFAULT '',
+    requires_user_action BOOLEAN NOT NULL DEFAULT FALSE,
+    entities             JSONB NOT NULL DEFAULT '[]',
+    provider             TEXT NOT NULL DEFAULT '',
+    model                TEXT NOT NULL DEFAULT '',
+    error                TEXT NOT NULL DEFAULT '',   -- Set when extraction failed; the message is not retried
+    message_created_at   TIMESTAMPTZ NOT NULL,
+    analyzed_at          TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai8_message_analytics_created ON ai8_airborne_message_analytics(message_created_at);
+
+CREATE TABLE IF NOT EXISTS ai8_airborne_message_topics (
+    message_id          UUID NOT NULL REFERENCES ai8_airborne_message_analytics(message_id) ON DELETE CASCADE,
+    topic               TEXT NOT NULL,              -- Lowercased
+    PRIMARY KEY (message_id, topic)
+);
+
+COMMENT ON TABLE ai8_airborne_message_analytics IS 'AI8 tenant structured metadata extracted from user messages';
+COMMENT ON TABLE ai8_airborne_message_topics IS 'AI8 tenant topics of analyzed user messages';
+
+-- ----------------------------------------------------------------------------
+-- EMAIL4AI CONVERSATION ANALYTICS
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS email4ai_airborne_message_analytics (
+    message_id           UUID PRIMARY KEY REFERENCES email4ai_airborne_messages(id) ON DELETE CASCADE,
+    thread_id            UUID NOT NULL,
+    intent               TEXT NOT NULL DEFAULT '',
+    requires_user_action BOOLEAN NOT NULL DEFAULT FALSE,
+    entities             JSONB NOT NULL DEFAULT '[]',
+    provider             TEXT NOT NULL DEFAULT '',
+    model                TEXT NOT NULL DEFAULT '',
+    error                TEXT NOT NULL DEFAULT '',
+    message_created_at   TIMESTAMPTZ NOT NULL,
+    analyzed_at          TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email4ai_message_analytics_created ON email4ai_airborne_message_analytics(message_created_at);
+
+CREATE TABLE IF NOT EXISTS email4ai_airborne_message_topics (
+    message_id          UUID NOT NULL REFERENCES email4ai_airborne_message_analytics(message_id) ON DELETE CASCADE,
+    topic               TEXT NOT NULL,
+    PRIMARY KEY (message_id, topic)
+);
+
+COMMENT ON TABLE email4ai_airborne_message_analytics IS 'Email4AI tenant structured metadata extracted from user messages';
+COMMENT ON TABLE email4ai_airborne_message_topics IS 'Email4AI tenant topics of analyzed user messages';
+
+-- ----------------------------------------------------------------------------
+-- ZZTEST CONVERSATION ANALYTICS
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS zztest_airborne_message_analytics (
+    message_id           UUID PRIMARY KEY REFERENCES zztest_airborne_messages(id) ON DELETE CASCADE,
+    thread_id            UUID NOT NULL,
+    intent               TEXT NOT NULL DEFAULT '',
+    requires_user_action BOOLEAN NOT NULL DEFAULT FALSE,
+    entities             JSONB NOT NULL DEFAULT '[]',
+    provider             TEXT NOT NULL DEFAULT '',
+    model                TEXT NOT NULL DEFAULT '',
+    error                TEXT NOT NULL DEFAULT '',
+    message_created_at   TIMESTAMPTZ NOT NULL,
+    analyzed_at          TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_zztest_message_analytics_created ON zztest_airborne_message_analytics(message_created_at);
+
+CREATE TABLE IF NOT EXISTS zztest_airborne_message_topics (
+    message_id          UUID NOT NULL REFERENCES zztest_airborne_message_analytics(message_id) ON DELETE CASCADE,
+    topic               TEXT NOT NULL,
+    PRIMARY KEY (message_id, topic)
+);
+
+COMMENT ON TABLE zztest_airborne_message_analytics IS 'Test tenant structured metadata extracted from user messages';
+COMMENT ON TABLE zztest_airborne_message_topics IS 'Test tenant topics of analyzed user messages';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS ai8_airborne_message_topics;
+-- DROP TABLE IF EXISTS ai8_airborne_message_analytics;
+-- DROP TABLE IF EXISTS email4ai_airborne_message_topics;
+-- DROP TABLE IF EXISTS email4ai_airborne_message_analytics;
+-- DROP TABLE IF EXISTS zztest_airborne_message_topics;
+-- DROP TABLE IF EXISTS zztest_airborne_message_analytics;
@@ -0,0 +1,24 @@
+-- ============================================================================
+-- AIRBORNE USER PROFILE TIME ZONE MIGRATION
+-- ============================================================================
+-- Purpose: Store each user's time zone, used to give the current date and
+--          time in the user's time zone when the tenant enables date context
+-- Tables: {tenant}_airborne_user_profiles
+-- Run: psql -d airborne -f migrations/019_user_profile_timezone.sql
+-- ============================================================================
+
+ALTER TABLE ai8_airborne_user_profiles ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE email4ai_airborne_user_profiles ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE zztest_airborne_user_profiles ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN ai8_airborne_user_profiles.timezone IS 'IANA time zone name, e.g. Europe/Berlin; empty for the tenant default';
+COMMENT ON COLUMN email4ai_airborne_user_profiles.timezone IS 'IANA time zone name, e.g. Europe/Berlin; empty for the tenant default';
+COMMENT ON COLUMN zztest_airborne_user_profiles.timezone IS 'IANA time zone name, e.g. Europe/Berlin; empty for the tenant default';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- ALTER TABLE ai8_airborne_user_profiles DROP COLUMN timezone;
+-- ALTER TABLE email4ai_airborne_user_profiles DROP COLUMN timezone;
+-- ALTER TABLE zztest_airborne_user_profiles DROP COLUMN timezone;
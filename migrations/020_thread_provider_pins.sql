@@ -0,0 +1,34 @@
+-- ============================================================================
+-- AIRBORNE THREAD PROVIDER PINS MIGRATION
+-- ============================================================================
+-- Purpose: Pin each thread to the provider and model its conversation started
+--          on, so later turns that do not choose a provider stay on them.
+--          Unlike provider and model, the last used, the pin does not move
+--          on failover; an explicit provider or model override re-pins it.
+-- Tables: {tenant}_airborne_threads
+-- Run: psql -d airborne -f migrations/020_thread_provider_pins.sql
+-- ============================================================================
+
+ALTER TABLE ai8_airborne_threads ADD COLUMN IF NOT EXISTS pinned_provider TEXT;
+ALTER TABLE ai8_airborne_threads ADD COLUMN IF NOT EXISTS pinned_model TEXT;
+ALTER TABLE email4ai_airborne_threads ADD COLUMN IF NOT EXISTS pinned_provider TEXT;
+ALTER TABLE email4ai_airborne_threads ADD COLUMN IF NOT EXISTS pinned_model TEXT;
+ALTER TABLE zztest_airborne_threads ADD COLUMN IF NOT EXISTS pinned_provider TEXT;
+ALTER TABLE zztest_airborne_threads ADD COLUMN IF NOT EXISTS pinned_model TEXT;
+
+COMMENT ON COLUMN ai8_airborne_threads.pinned_provider IS 'Provider later turns default to; NULL until the first reply';
+COMMENT ON COLUMN email4ai_airborne_threads.pinned_provider IS 'Provider later turns default to; NULL until the first reply';
+COMMENT ON COLUMN zztest_airborne_threads.pinned_provider IS 'Provider later turns default to; NULL until the first reply';
+
+-- Existing threads are pinned to their last-used provider and model
+UPDATE ai8_airborne_threads SET pinned_provider = provider, pinned_model = model WHERE pinned_provider IS NULL;
+UPDATE email4ai_airborne_threads SET pinned_provider = provider, pinned_model = model WHERE pinned_provider IS NULL;
+UPDATE zztest_airborne_threads SET pinned_provider = provider, pinned_model = model WHERE pinned_provider IS NULL;
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- ALTER TABLE ai8_airborne_threads DROP COLUMN pinned_provider, DROP COLUMN pinned_model;
+-- ALTER TABLE email4ai_airborne_threads DROP COLUMN pinned_provider, DROP COLUMN pinned_model;
+-- ALTER TABLE zztest_airborne_threads DROP COLUMN pinned_provider, DROP COLUMN pinned_model;
@@ -0,0 +1,32 @@
+-- ============================================================================
+-- AIRBORNE NETWORK VIOLATIONS MIGRATION
+-- ============================================================================
+-- Purpose: Audit trail of requests rejected by network restrictions on API
+--          keys, signed requests and the admin HTTP server, served by
+--          GET /admin/network/violations.
+-- Tables: airborne_network_violations (shared; violations are recorded
+--         before a request's tenant is trusted)
+-- Run: psql -d airborne -f migrations/021_network_violations.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS airborne_network_violations (
+    id          UUID PRIMARY KEY,
+    surface     TEXT NOT NULL,                  -- grpc or admin_http
+    method      TEXT NOT NULL,                  -- RPC method or HTTP method and path
+    client_id   TEXT NOT NULL DEFAULT '',
+    key_id      TEXT NOT NULL DEFAULT '',       -- Hashed key ID
+    remote_addr TEXT NOT NULL,
+    country     TEXT NOT NULL DEFAULT '',       -- Empty when not resolved
+    reason      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_network_violations_created ON airborne_network_violations(created_at DESC);
+
+COMMENT ON TABLE airborne_network_violations IS 'Audit trail of requests rejected by network restrictions';
+
+-- ============================================================================
+-- ROLLBACK INSTRUCTIONS
+-- ============================================================================
+-- To rollback this migration:
+-- DROP TABLE IF EXISTS airborne_network_violations;
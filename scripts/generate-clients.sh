@@ -0,0 +1,41 @@
+#!/bin/bash
+set -euo pipefail
+
+# Generate TypeScript and/or Python client code from proto files using buf
+# Usage: generate-clients.sh [ts|python|all]
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+PROJECT_ROOT="$(dirname "$SCRIPT_DIR")"
+
+cd "$PROJECT_ROOT"
+
+TARGET="${1:-all}"
+
+generate_ts() {
+    echo "Generating TypeScript client code..."
+    rm -rf clients/typescript/src/gen
+    buf generate --template buf.gen.ts.yaml
+}
+
+generate_python() {
+    echo "Generating Python client code..."
+    rm -rf clients/python/src/airborne
+    buf generate --template buf.gen.python.yaml
+    # buf does not emit package markers
+    find clients/python/src/airborne -type d -exec touch {}/__init__.py \;
+}
+
+case "$TARGET" in
+    ts) generate_ts ;;
+    python) generate_python ;;
+    all)
+        generate_ts
+        generate_python
+        ;;
+    *)
+        echo "Unknown target: $TARGET (expected ts, python or all)" >&2
+        exit 1
+        ;;
+esac
+
+echo "Client code generation complete!"
@@ -0,0 +1,56 @@
+#!/bin/bash
+set -euo pipefail
+
+# Build publishable TypeScript and/or Python client packages into dist/clients,
+# versioned from the repo's VERSION file
+# Usage: package-clients.sh [ts|python|all]
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+PROJECT_ROOT="$(dirname "$SCRIPT_DIR")"
+
+cd "$PROJECT_ROOT"
+
+TARGET="${1:-all}"
+VERSION="$(cat VERSION)"
+OUT_DIR="$PROJECT_ROOT/dist/clients"
+mkdir -p "$OUT_DIR"
+
+package_ts() {
+    "$SCRIPT_DIR/generate-clients.sh" ts
+    echo "Packaging TypeScript client $VERSION..."
+    (
+        cd clients/typescript
+        # Stamp the version for the pack only; package.json stays at 0.0.0-dev
+        cp package.json package.json.orig
+        trap 'mv package.json.orig package.json' EXIT
+        npm pkg set version="$VERSION"
+        npm install --no-audit --no-fund
+        npm run build
+        npm pack --pack-destination "$OUT_DIR"
+    )
+}
+
+package_python() {
+    "$SCRIPT_DIR/generate-clients.sh" python
+    echo "Packaging Python client $VERSION..."
+    (
+        cd clients/python
+        echo "__version__ = \"$VERSION\"" > src/airborne_client/_version.py
+        python3 -m build --outdir "$OUT_DIR"
+    )
+}
+
+case "$TARGET" in
+    ts) package_ts ;;
+    python) package_python ;;
+    all)
+        package_ts
+        package_python
+        ;;
+    *)
+        echo "Unknown target: $TARGET (expected ts, python or all)" >&2
+        exit 1
+        ;;
+esac
+
+echo "Client packages written to $OUT_DIR"